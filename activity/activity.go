@@ -0,0 +1,290 @@
+// Package activity records the activity log of each deposit: the log lines about it and the attempts of its claim
+// tx, stored next to its changes of status, so the support tools get the whole story of a deposit from one query
+// instead of grepping the logs of the service.
+package activity
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// KindLog is a log line about the deposit
+	KindLog = "log"
+	// KindClaimAttempt is a claim tx sent for the deposit, or the claim tx manager giving up on it
+	KindClaimAttempt = "claim_attempt"
+	// KindStatus is a change of status of the deposit, read from its status history
+	KindStatus = "status"
+
+	// LevelDebug is the level of the debug log lines
+	LevelDebug = "debug"
+	// LevelInfo is the level of the info log lines and the claim txs sent
+	LevelInfo = "info"
+	// LevelWarn is the level of the warning log lines
+	LevelWarn = "warn"
+	// LevelError is the level of the error log lines and the failed claim txs
+	LevelError = "error"
+)
+
+// pruneInterval is the time between two deletions of the entries older than the retention
+const pruneInterval = time.Hour
+
+type storageInterface interface {
+	AddDepositActivities(ctx context.Context, entries []etherman.DepositActivity, dbTx pgx.Tx) error
+	DeleteDepositActivities(ctx context.Context, before time.Time, dbTx pgx.Tx) (int64, error)
+}
+
+// Recorder stores the entries of the activity log of the deposits. It implements hooks.Hook, recording the claim
+// attempts of the claim tx manager.
+type Recorder struct {
+	hooks.NopHook
+	cfg     Config
+	storage storageInterface
+	queue   *queue.Queue[etherman.DepositActivity]
+	level   int
+	now     func() time.Time
+}
+
+// recorder is the recorder of the Loggers, nil while the activity log is disabled.
+var recorder atomic.Pointer[Recorder]
+
+// NewRecorder creates the recorder of the activity log.
+func NewRecorder(cfg Config, storage interface{}) (*Recorder, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	level, _ := parseLevel(cfg.Level)
+	q, err := queue.New[etherman.DepositActivity]("activity", cfg.QueueConfig(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		cfg:     cfg,
+		storage: storage.(storageInterface),
+		queue:   q,
+		level:   level,
+		now:     time.Now,
+	}, nil
+}
+
+// SetRecorder makes the Loggers record their lines with the recorder, nil stops recording them.
+func SetRecorder(r *Recorder) {
+	recorder.Store(r)
+}
+
+// Start stores the queued entries every FlushInterval, and prunes the entries older than the retention, until the
+// context is done. The entries queued then are stored before returning.
+func (r *Recorder) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.FlushInterval.Duration)
+	defer ticker.Stop()
+	var lastPrune time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			// The context of the service is done, the last entries are stored without it
+			r.flush(context.Background())
+			return
+		case <-ticker.C:
+			r.flush(ctx)
+			if r.cfg.Retention.Duration > 0 && r.now().Sub(lastPrune) >= pruneInterval {
+				r.prune(ctx)
+				lastPrune = r.now()
+			}
+		}
+	}
+}
+
+// flush stores the queued entries in batches. A batch that fails is dropped, so the size of the log is bounded
+// while the database is down.
+func (r *Recorder) flush(ctx context.Context) {
+	for {
+		batch := make([]etherman.DepositActivity, 0, r.cfg.BatchSize)
+	read:
+		for len(batch) < r.cfg.BatchSize {
+			select {
+			case a := <-r.queue.C():
+				batch = append(batch, a)
+			default:
+				break read
+			}
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := r.storage.AddDepositActivities(ctx, batch, nil); err != nil {
+			log.Warnf("activity: error storing %d entries of the activity log, dropping them: %v", len(batch), err)
+			return
+		}
+		if len(batch) < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+func (r *Recorder) prune(ctx context.Context) {
+	deleted, err := r.storage.DeleteDepositActivities(ctx, r.now().Add(-r.cfg.Retention.Duration), nil)
+	if err != nil {
+		log.Warnf("activity: error deleting the entries older than the retention: %v", err)
+	} else if deleted > 0 {
+		log.Debugf("activity: %d entries older than the retention deleted", deleted)
+	}
+}
+
+// record queues an entry, dropping it if the queue is full.
+func (r *Recorder) record(ctx context.Context, a etherman.DepositActivity) {
+	a.Time = r.now()
+	r.queue.Push(ctx, a)
+}
+
+// OnClaimSent implements hooks.Hook. The claim txs are only sent for the L1 deposits.
+func (r *Recorder) OnClaimSent(ctx context.Context, mTx *ctmtypes.MonitoredTx, tx *types.Transaction) {
+	r.record(ctx, etherman.DepositActivity{
+		DepositCnt: mTx.DepositID,
+		Kind:       KindClaimAttempt,
+		Level:      LevelInfo,
+		Message:    "claim tx sent",
+		Fields: map[string]string{
+			"tx_hash":   tx.Hash().String(),
+			"nonce":     strconv.FormatUint(tx.Nonce(), 10),
+			"gas_price": tx.GasPrice().String(),
+			"from":      mTx.From.String(),
+		},
+	})
+}
+
+// OnClaimFailed implements hooks.Hook.
+func (r *Recorder) OnClaimFailed(ctx context.Context, mTx *ctmtypes.MonitoredTx) {
+	r.record(ctx, etherman.DepositActivity{
+		DepositCnt: mTx.DepositID,
+		Kind:       KindClaimAttempt,
+		Level:      LevelError,
+		Message:    "claim tx failed, the claim tx manager gives up on it",
+		Fields:     map[string]string{"status": string(mTx.Status), "txs": strconv.Itoa(len(mTx.History))},
+	})
+}
+
+// Logger logs the lines about a deposit, recording them in its activity log.
+type Logger struct {
+	networkID  uint
+	depositCnt uint
+	log        *log.Logger
+	fields     map[string]string
+}
+
+// For returns the logger of a deposit.
+func For(networkID, depositCnt uint) *Logger {
+	return &Logger{networkID: networkID, depositCnt: depositCnt, log: log.WithFields()}
+}
+
+// WithFields returns a logger adding the key value pairs to the lines, and to the fields of their entries.
+func (l *Logger) WithFields(keyValuePairs ...interface{}) *Logger {
+	fields := make(map[string]string, len(l.fields)+len(keyValuePairs)/2) //nolint:gomnd
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	for i := 0; i+1 < len(keyValuePairs); i += 2 {
+		fields[fmt.Sprint(keyValuePairs[i])] = fmt.Sprint(keyValuePairs[i+1])
+	}
+	return &Logger{networkID: l.networkID, depositCnt: l.depositCnt, log: l.log.WithFields(keyValuePairs...), fields: fields}
+}
+
+// Debug logs a debug line.
+func (l *Logger) Debug(args ...interface{}) {
+	l.log.Debug(args...)
+	l.record(LevelDebug, fmt.Sprint(args...))
+}
+
+// Info logs an info line.
+func (l *Logger) Info(args ...interface{}) {
+	l.log.Info(args...)
+	l.record(LevelInfo, fmt.Sprint(args...))
+}
+
+// Warn logs a warning line.
+func (l *Logger) Warn(args ...interface{}) {
+	l.log.Warn(args...)
+	l.record(LevelWarn, fmt.Sprint(args...))
+}
+
+// Error logs an error line.
+func (l *Logger) Error(args ...interface{}) {
+	l.log.Error(args...)
+	l.record(LevelError, fmt.Sprint(args...))
+}
+
+// Debugf logs a formatted debug line.
+func (l *Logger) Debugf(template string, args ...interface{}) {
+	l.log.Debugf(template, args...)
+	l.record(LevelDebug, fmt.Sprintf(template, args...))
+}
+
+// Infof logs a formatted info line.
+func (l *Logger) Infof(template string, args ...interface{}) {
+	l.log.Infof(template, args...)
+	l.record(LevelInfo, fmt.Sprintf(template, args...))
+}
+
+// Warnf logs a formatted warning line.
+func (l *Logger) Warnf(template string, args ...interface{}) {
+	l.log.Warnf(template, args...)
+	l.record(LevelWarn, fmt.Sprintf(template, args...))
+}
+
+// Errorf logs a formatted error line.
+func (l *Logger) Errorf(template string, args ...interface{}) {
+	l.log.Errorf(template, args...)
+	l.record(LevelError, fmt.Sprintf(template, args...))
+}
+
+// caller returns the package and the file of the caller of the Logger, with its line. The caller of the log lines
+// is this package, so the recorded entries get it in their fields.
+func caller() string {
+	_, file, line, ok := runtime.Caller(3) //nolint:gomnd
+	if !ok {
+		return ""
+	}
+	if i := strings.LastIndexByte(file, '/'); i > 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+	return file + ":" + strconv.Itoa(line)
+}
+
+func (l *Logger) record(level, message string) {
+	r := recorder.Load()
+	if r == nil {
+		return
+	}
+	if i, _ := parseLevel(level); i < r.level {
+		return
+	}
+	fields := make(map[string]string, len(l.fields)+1)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	if c := caller(); c != "" {
+		fields["caller"] = c
+	}
+	r.record(context.Background(), etherman.DepositActivity{
+		NetworkID:  l.networkID,
+		DepositCnt: l.depositCnt,
+		Kind:       KindLog,
+		Level:      level,
+		Message:    message,
+		Fields:     fields,
+	})
+}
@@ -0,0 +1,123 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// memStorage keeps the stored entries in memory.
+type memStorage struct {
+	mu      sync.Mutex
+	entries []etherman.DepositActivity
+	batches []int
+	err     error
+}
+
+func (s *memStorage) AddDepositActivities(ctx context.Context, entries []etherman.DepositActivity, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.entries = append(s.entries, entries...)
+	s.batches = append(s.batches, len(entries))
+	return nil
+}
+
+func (s *memStorage) DeleteDepositActivities(ctx context.Context, before time.Time, dbTx pgx.Tx) (int64, error) {
+	return 0, nil
+}
+
+func testConfig() Config {
+	return Config{Level: LevelInfo, QueueSize: 10, FlushInterval: types.NewDuration(time.Hour), BatchSize: 2}
+}
+
+func TestLogger(t *testing.T) {
+	storage := &memStorage{}
+	r, err := NewRecorder(testConfig(), storage)
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	r.now = func() time.Time { return now }
+
+	// Nothing is recorded without a recorder
+	For(0, 5).Errorf("error getting the claim proof of the deposit %d", 5)
+	SetRecorder(r)
+	defer SetRecorder(nil)
+
+	logger := For(0, 5)
+	logger.Debugf("below the level")
+	logger.Infof("create the claim tx for the deposit %d", 5)
+	logger.WithFields("monitoredTx", 5).Error("maximum retries and the tx is still missing in the pool. TxHash: ", "0x01")
+	For(1, 7).Warnf("other deposit")
+	r.flush(context.Background())
+
+	require.Len(t, storage.entries, 3)
+	// The entries are stored in batches of BatchSize
+	require.Equal(t, []int{2, 1}, storage.batches)
+	// The entries have the caller of the line
+	require.Regexp(t, `^activity/activity_test\.go:\d+$`, storage.entries[0].Fields["caller"])
+	require.Equal(t, etherman.DepositActivity{NetworkID: 0, DepositCnt: 5, Time: now, Kind: KindLog, Level: LevelInfo, Message: "create the claim tx for the deposit 5", Fields: map[string]string{"caller": storage.entries[0].Fields["caller"]}}, storage.entries[0])
+	require.Equal(t, LevelError, storage.entries[1].Level)
+	require.Equal(t, "maximum retries and the tx is still missing in the pool. TxHash: 0x01", storage.entries[1].Message)
+	require.Equal(t, map[string]string{"monitoredTx": "5", "caller": storage.entries[1].Fields["caller"]}, storage.entries[1].Fields)
+	require.NotEqual(t, storage.entries[0].Fields["caller"], storage.entries[1].Fields["caller"])
+	require.Equal(t, uint(1), storage.entries[2].NetworkID)
+
+	// A batch that can't be stored is dropped
+	storage.err = errors.New("db down")
+	logger.Info("dropped")
+	r.flush(context.Background())
+	storage.err = nil
+	r.flush(context.Background())
+	require.Len(t, storage.entries, 3)
+}
+
+func TestClaimAttempts(t *testing.T) {
+	storage := &memStorage{}
+	r, err := NewRecorder(testConfig(), storage)
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x02")
+	mTx := &ctmtypes.MonitoredTx{DepositID: 5, From: common.HexToAddress("0x01"), To: &to, Nonce: 3, Value: big.NewInt(0), GasPrice: big.NewInt(100)}
+	tx := ethtypes.NewTx(&ethtypes.LegacyTx{To: &to, Nonce: 3, Value: big.NewInt(0), GasPrice: big.NewInt(100)})
+	r.OnClaimSent(context.Background(), mTx, tx)
+	mTx.Status = ctmtypes.MonitoredTxStatusFailed
+	r.OnClaimFailed(context.Background(), mTx)
+	r.flush(context.Background())
+
+	require.Len(t, storage.entries, 2)
+	sent := storage.entries[0]
+	require.Equal(t, KindClaimAttempt, sent.Kind)
+	require.Equal(t, uint(0), sent.NetworkID)
+	require.Equal(t, uint(5), sent.DepositCnt)
+	require.Equal(t, tx.Hash().String(), sent.Fields["tx_hash"])
+	require.Equal(t, "3", sent.Fields["nonce"])
+	require.Equal(t, "100", sent.Fields["gas_price"])
+	require.Equal(t, LevelError, storage.entries[1].Level)
+	require.Equal(t, string(ctmtypes.MonitoredTxStatusFailed), storage.entries[1].Fields["status"])
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, testConfig().Validate())
+	cfg := testConfig()
+	cfg.Level = "trace"
+	require.Error(t, cfg.Validate())
+	cfg = testConfig()
+	cfg.BatchSize = 0
+	require.Error(t, cfg.Validate())
+	cfg = testConfig()
+	cfg.QueueSize = 0
+	require.Error(t, cfg.Validate())
+}
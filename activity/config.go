@@ -0,0 +1,67 @@
+package activity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the activity log of the deposits
+type Config struct {
+	// Enabled records the log lines and the claim attempts of each deposit
+	Enabled bool `mapstructure:"Enabled"`
+	// Level is the min level of the log lines recorded: debug, info, warn or error. Empty is debug
+	Level string `mapstructure:"Level"`
+	// QueueSize is the max number of entries waiting to be stored, the next ones are dropped so the logging
+	// never blocks the sync and claim loops
+	QueueSize int `mapstructure:"QueueSize"`
+	// FlushInterval is the time between two writes of the queued entries
+	FlushInterval types.Duration `mapstructure:"FlushInterval"`
+	// BatchSize is the max number of entries stored in a db tx
+	BatchSize int `mapstructure:"BatchSize"`
+	// Retention is the time the entries are kept, 0 keeps them forever
+	Retention types.Duration `mapstructure:"Retention"`
+}
+
+// QueueConfig returns the config of the queue of the entries.
+func (c Config) QueueConfig() queue.Config {
+	return queue.Config{Size: c.QueueSize, Policy: queue.PolicyDropNewest}
+}
+
+// Validate checks the level, the queue and the intervals of the activity log.
+func (c Config) Validate() error {
+	if _, err := parseLevel(c.Level); err != nil {
+		return err
+	}
+	// The entries are read from the queue by the flushes, so it must hold them meanwhile
+	if c.QueueSize <= 0 {
+		return errors.New("the queue size of the activity log must be positive")
+	}
+	if c.FlushInterval.Duration <= 0 {
+		return errors.New("the flush interval of the activity log must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("the batch size of the activity log must be positive")
+	}
+	if c.Retention.Duration < 0 {
+		return errors.New("the retention of the activity log is negative")
+	}
+	return nil
+}
+
+// levels are the levels of the log lines, from the lowest.
+var levels = []string{LevelDebug, LevelInfo, LevelWarn, LevelError}
+
+func parseLevel(level string) (int, error) {
+	if level == "" {
+		return 0, nil
+	}
+	for i, l := range levels {
+		if l == level {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown activity log level %s", level)
+}
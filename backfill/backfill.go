@@ -0,0 +1,84 @@
+// Package backfill fills the data of the synced deposits recorded after they were synced: the traces of the deposit
+// txs synced before the tracing was enabled, or while the provider couldn't trace them, and the history of the
+// addresses watched by the wallets, backfilled on demand.
+package backfill
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// Storage is the storage of the traces of the deposit txs.
+type Storage interface {
+	GetUntracedDeposits(ctx context.Context, networkID, fromDepositCnt, limit uint, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	AddDepositTrace(ctx context.Context, trace *etherman.DepositTrace, dbTx pgx.Tx) error
+}
+
+// Tracer traces the deposit txs of a network.
+type Tracer interface {
+	TraceDeposit(ctx context.Context, txHash common.Hash) (*etherman.DepositTrace, error)
+}
+
+// Result is the result of the backfill of the traces of a network.
+type Result struct {
+	NetworkID uint `json:"network_id"`
+	// Traced is the number of deposit txs traced
+	Traced int `json:"traced"`
+	// Failed is the number of deposit txs that couldn't be traced, they are tried again in the next backfill
+	Failed int `json:"failed"`
+}
+
+type traceAdder interface {
+	AddDepositTrace(ctx context.Context, trace *etherman.DepositTrace, dbTx pgx.Tx) error
+}
+
+// Traces traces the untraced deposits of a network in batches, in order. The deposits of a tx with several deposits
+// are traced once.
+func Traces(ctx context.Context, storage Storage, tracer Tracer, networkID, batch uint) (Result, error) {
+	return traceDeposits(ctx, storage, tracer, networkID, func(from uint) ([]*etherman.Deposit, error) {
+		return storage.GetUntracedDeposits(ctx, networkID, from, batch, nil)
+	})
+}
+
+// traceDeposits traces the batches of untraced deposits of a network returned from a deposit count, until a batch
+// is empty.
+func traceDeposits(ctx context.Context, storage traceAdder, tracer Tracer, networkID uint, untraced func(from uint) ([]*etherman.Deposit, error)) (Result, error) {
+	result := Result{NetworkID: networkID}
+	var from uint
+	for {
+		deposits, err := untraced(from)
+		if err != nil {
+			return result, err
+		}
+		if len(deposits) == 0 {
+			return result, nil
+		}
+		seen := make(map[common.Hash]bool)
+		for _, deposit := range deposits {
+			from = deposit.DepositCount + 1
+			if seen[deposit.TxHash] {
+				continue
+			}
+			seen[deposit.TxHash] = true
+			trace, err := tracer.TraceDeposit(ctx, deposit.TxHash)
+			if errors.Is(err, etherman.ErrTracingNotSupported) {
+				return result, err
+			} else if err != nil {
+				log.Warnf("networkID: %d, error tracing the deposit tx %s: %v", networkID, deposit.TxHash.String(), err)
+				result.Failed++
+				continue
+			}
+			trace.NetworkID = networkID
+			trace.BlockID = deposit.BlockID
+			if err := storage.AddDepositTrace(ctx, trace, nil); err != nil {
+				return result, err
+			}
+			result.Traced++
+		}
+	}
+}
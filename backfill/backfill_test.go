@@ -0,0 +1,88 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// traceStorage returns the deposits whose tx has no trace stored.
+type traceStorage struct {
+	deposits []*etherman.Deposit
+	traces   map[common.Hash]*etherman.DepositTrace
+}
+
+func (s *traceStorage) GetUntracedDeposits(_ context.Context, networkID, fromDepositCnt, limit uint, _ pgx.Tx) ([]*etherman.Deposit, error) {
+	var deposits []*etherman.Deposit
+	for _, d := range s.deposits {
+		if _, traced := s.traces[d.TxHash]; !traced && d.NetworkID == networkID && d.DepositCount >= fromDepositCnt && uint(len(deposits)) < limit {
+			deposits = append(deposits, d)
+		}
+	}
+	return deposits, nil
+}
+
+func (s *traceStorage) AddDepositTrace(_ context.Context, trace *etherman.DepositTrace, _ pgx.Tx) error {
+	s.traces[trace.TxHash] = trace
+	return nil
+}
+
+// tracer traces the txs, failing for the failed ones.
+type tracer struct {
+	failed map[common.Hash]bool
+	calls  int
+}
+
+func (t *tracer) TraceDeposit(_ context.Context, txHash common.Hash) (*etherman.DepositTrace, error) {
+	t.calls++
+	if t.failed[txHash] {
+		return nil, errors.New("trace timeout")
+	}
+	return &etherman.DepositTrace{TxHash: txHash, Originator: common.HexToAddress("0x1")}, nil
+}
+
+func TestTraces(t *testing.T) {
+	ctx := context.Background()
+	tx := func(i int64) common.Hash { return common.BigToHash(big.NewInt(i)) }
+	st := &traceStorage{traces: make(map[common.Hash]*etherman.DepositTrace)}
+	for i := uint(0); i < 5; i++ {
+		st.deposits = append(st.deposits, &etherman.Deposit{NetworkID: 1, DepositCount: i, BlockID: uint64(10 + i), TxHash: tx(int64(i))})
+	}
+	// A tx with two deposits is traced once
+	st.deposits = append(st.deposits, &etherman.Deposit{NetworkID: 1, DepositCount: 5, BlockID: 14, TxHash: tx(4)})
+	st.deposits = append(st.deposits, &etherman.Deposit{NetworkID: 0, DepositCount: 0, TxHash: tx(100)})
+	st.traces[tx(1)] = &etherman.DepositTrace{TxHash: tx(1)}
+
+	tr := &tracer{failed: map[common.Hash]bool{tx(2): true}}
+	result, err := Traces(ctx, st, tr, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, Result{NetworkID: 1, Traced: 3, Failed: 1}, result)
+	require.Equal(t, 4, tr.calls)
+	require.Equal(t, uint(1), st.traces[tx(4)].NetworkID)
+	require.Equal(t, uint64(14), st.traces[tx(4)].BlockID)
+	_, found := st.traces[tx(100)]
+	require.False(t, found)
+
+	// The failed txs are traced again by the next backfill
+	tr.failed = nil
+	result, err = Traces(ctx, st, tr, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, Result{NetworkID: 1, Traced: 1}, result)
+
+	// The backfill stops if the provider can't trace
+	st.deposits = append(st.deposits, &etherman.Deposit{NetworkID: 1, DepositCount: 6, TxHash: tx(6)})
+	_, err = Traces(ctx, st, &unsupportedTracer{}, 1, 2)
+	require.ErrorIs(t, err, etherman.ErrTracingNotSupported)
+}
+
+type unsupportedTracer struct{}
+
+func (unsupportedTracer) TraceDeposit(context.Context, common.Hash) (*etherman.DepositTrace, error) {
+	return nil, etherman.ErrTracingNotSupported
+}
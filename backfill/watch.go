@@ -0,0 +1,101 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+type watchStorage interface {
+	GetPendingWatchBackfills(ctx context.Context, limit uint, dbTx pgx.Tx) ([]etherman.WatchedAddress, error)
+	GetUntracedDepositsTo(ctx context.Context, networkID uint, destAddr common.Address, fromDepositCnt, limit uint, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	AddDepositTrace(ctx context.Context, trace *etherman.DepositTrace, dbTx pgx.Tx) error
+	SetWatchBackfilled(ctx context.Context, owner, address common.Address, requestedAt, backfilledAt time.Time, dbTx pgx.Tx) error
+}
+
+// Watches backfills the history of the addresses watched by the wallets when they request it, tracing the deposits
+// to the address ahead of the backfill of every deposit of the networks.
+type Watches struct {
+	storage  watchStorage
+	tracers  map[uint]Tracer
+	networks []uint
+	interval time.Duration
+	batch    uint
+	now      func() time.Time
+}
+
+// NewWatches creates the backfiller of the watched addresses, checking the requested backfills every interval and
+// reading the deposits in batches.
+func NewWatches(storage interface{}, tracers map[uint]Tracer, interval time.Duration, batch uint) (*Watches, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("the interval of the backfills of the watched addresses must be positive")
+	}
+	if batch == 0 {
+		return nil, fmt.Errorf("the batch of the backfills of the watched addresses must be positive")
+	}
+	networks := make([]uint, 0, len(tracers))
+	for networkID := range tracers {
+		networks = append(networks, networkID)
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i] < networks[j] })
+	return &Watches{
+		storage:  storage.(watchStorage),
+		tracers:  tracers,
+		networks: networks,
+		interval: interval,
+		batch:    batch,
+		now:      time.Now,
+	}, nil
+}
+
+// Start runs the requested backfills every interval until the context is done.
+func (w *Watches) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if err := w.run(ctx); err != nil {
+			log.Warnf("error backfilling the watched addresses: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run backfills the pending backfills, the oldest requests first. A backfill is done once the deposits to the
+// address of every network are traced, the failed traces are tried again by the next request.
+func (w *Watches) run(ctx context.Context) error {
+	watches, err := w.storage.GetPendingWatchBackfills(ctx, w.batch, nil)
+	if err != nil {
+		return err
+	}
+	for _, watch := range watches {
+		for _, networkID := range w.networks {
+			addr := watch.Address
+			result, err := traceDeposits(ctx, w.storage, w.tracers[networkID], networkID, func(from uint) ([]*etherman.Deposit, error) {
+				return w.storage.GetUntracedDepositsTo(ctx, networkID, addr, from, w.batch, nil)
+			})
+			if errors.Is(err, etherman.ErrTracingNotSupported) {
+				log.Warnf("networkID: %d, the deposits to the watched address %s can't be traced: %v", networkID, addr.Hex(), err)
+				continue
+			} else if err != nil {
+				return err
+			}
+			log.Infof("networkID: %d, history of the watched address %s backfilled: %d deposit txs traced, %d failed",
+				networkID, addr.Hex(), result.Traced, result.Failed)
+		}
+		if err := w.storage.SetWatchBackfilled(ctx, watch.Owner, watch.Address, *watch.BackfillRequestedAt, w.now(), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
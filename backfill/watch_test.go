@@ -0,0 +1,90 @@
+package backfill
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// watchTraceStorage is the trace storage with the requested backfills of the watched addresses.
+type watchTraceStorage struct {
+	*traceStorage
+	watches []etherman.WatchedAddress
+}
+
+func (s *watchTraceStorage) GetPendingWatchBackfills(_ context.Context, limit uint, _ pgx.Tx) ([]etherman.WatchedAddress, error) {
+	var pending []etherman.WatchedAddress
+	for _, w := range s.watches {
+		if w.BackfillRequestedAt != nil && w.BackfilledAt == nil && uint(len(pending)) < limit {
+			pending = append(pending, w)
+		}
+	}
+	return pending, nil
+}
+
+func (s *watchTraceStorage) GetUntracedDepositsTo(ctx context.Context, networkID uint, destAddr common.Address, fromDepositCnt, limit uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	deposits, err := s.GetUntracedDeposits(ctx, networkID, fromDepositCnt, uint(len(s.deposits)), dbTx)
+	var to []*etherman.Deposit
+	for _, d := range deposits {
+		if d.DestinationAddress == destAddr && uint(len(to)) < limit {
+			to = append(to, d)
+		}
+	}
+	return to, err
+}
+
+func (s *watchTraceStorage) SetWatchBackfilled(_ context.Context, owner, address common.Address, requestedAt, backfilledAt time.Time, _ pgx.Tx) error {
+	for i, w := range s.watches {
+		if w.Owner == owner && w.Address == address && w.BackfillRequestedAt.Equal(requestedAt) {
+			s.watches[i].BackfilledAt = &backfilledAt
+		}
+	}
+	return nil
+}
+
+func TestWatches(t *testing.T) {
+	ctx := context.Background()
+	tx := func(i int64) common.Hash { return common.BigToHash(big.NewInt(i)) }
+	watched, other := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	st := &watchTraceStorage{traceStorage: &traceStorage{traces: make(map[common.Hash]*etherman.DepositTrace)}}
+	for i := uint(0); i < 4; i++ {
+		to := watched
+		if i%2 == 1 {
+			to = other
+		}
+		st.deposits = append(st.deposits, &etherman.Deposit{NetworkID: 0, DepositCount: i, DestinationAddress: to, TxHash: tx(int64(i))})
+		st.deposits = append(st.deposits, &etherman.Deposit{NetworkID: 1, DepositCount: i, DestinationAddress: to, TxHash: tx(int64(10 + i))})
+	}
+	requestedAt := time.Unix(1700000000, 0)
+	st.watches = []etherman.WatchedAddress{
+		{Owner: other, Address: watched, BackfillRequestedAt: &requestedAt},
+		{Owner: watched, Address: other},
+	}
+	l1, l2 := &tracer{}, &unsupportedTracer{}
+	w, err := NewWatches(st, map[uint]Tracer{0: l1, 1: l2}, time.Minute, 1)
+	require.NoError(t, err)
+
+	// Only the deposits to the requested address are traced, the networks that can't trace are skipped
+	require.NoError(t, w.run(ctx))
+	require.Equal(t, 2, l1.calls)
+	require.Len(t, st.traces, 2)
+	require.Contains(t, st.traces, tx(0))
+	require.Contains(t, st.traces, tx(2))
+	require.NotNil(t, st.watches[0].BackfilledAt)
+	require.Nil(t, st.watches[1].BackfilledAt)
+
+	// Nothing is traced until the next request
+	require.NoError(t, w.run(ctx))
+	require.Equal(t, 2, l1.calls)
+
+	_, err = NewWatches(st, nil, 0, 1)
+	require.Error(t, err)
+	_, err = NewWatches(st, nil, time.Minute, 0)
+	require.Error(t, err)
+}
@@ -0,0 +1,429 @@
+// Package backup writes consistent logical backups of the bridge database with pg_dump, restores them with
+// pg_restore, and verifies them by restoring them in a scratch database and checking the roots of its exit trees.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// filePrefix and fileExt name the scheduled backups, with their time in between so they sort by it
+	filePrefix     = "bridge-"
+	fileExt        = ".dump"
+	fileTimeFormat = "20060102T150405Z"
+	// partialExt is the extension of a backup being written, renamed once pg_dump ends
+	partialExt = ".partial"
+	// invalidExt is the extension of a scheduled backup that failed its verification, never pruned
+	invalidExt = ".invalid"
+	// lockRetryInterval is the time between the attempts of the instances not running the scheduled backups to take
+	// their lock
+	lockRetryInterval = time.Minute
+)
+
+var (
+	lastBackupDesc = prometheus.NewDesc("bridge_backup_last_success_timestamp_seconds",
+		"Unix time of the last scheduled backup written", nil, nil)
+	lastVerifiedDesc = prometheus.NewDesc("bridge_backup_last_verified_timestamp_seconds",
+		"Unix time of the last scheduled backup verified", nil, nil)
+)
+
+type storageInterface interface {
+	GetNetworkPins(ctx context.Context, dbTx pgx.Tx) ([]*etherman.NetworkPin, error)
+	GetNetworkDepositsUntilBlock(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	GetRoot(ctx context.Context, depositCnt uint, network uint, dbTx pgx.Tx) ([]byte, error)
+}
+
+// runner runs a binary with the extra environment variables, writing its output to stdout if it's set.
+type runner func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error
+
+// Backup is a backup file of the bridge database.
+type Backup struct {
+	File      string    `json:"file"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TreeCheck is the check of the local exit tree of a network in a restored backup.
+type TreeCheck struct {
+	NetworkID uint `json:"network_id"`
+	Deposits  int  `json:"deposits"`
+	// Root is the stored root of the last deposit, the zero hash if it's missing
+	Root common.Hash `json:"root"`
+	// Computed is the root calculated from the deposits
+	Computed common.Hash `json:"computed"`
+	// Contiguous is false if a deposit count is missing
+	Contiguous bool `json:"contiguous"`
+	Consistent bool `json:"consistent"`
+}
+
+// Verification is the result of the verification of a backup.
+type Verification struct {
+	File       string      `json:"file"`
+	Trees      []TreeCheck `json:"trees"`
+	Consistent bool        `json:"consistent"`
+}
+
+// Backuper writes, restores and verifies the backups of the bridge database. It implements prometheus.Collector.
+type Backuper struct {
+	cfg    Config
+	db     db.Config
+	height uint8
+	run    runner
+	now    func() time.Time
+	lock   locker
+
+	mu            sync.Mutex
+	verifyStorage interface{}
+	lastBackup    time.Time
+	lastVerified  time.Time
+}
+
+// NewBackuper creates a new backuper of the database, with the height of its exit trees.
+func NewBackuper(cfg Config, syncDB db.Config, height uint8) (*Backuper, error) {
+	if cfg.VerifyDB.Name != "" && sameDatabase(cfg.VerifyDB, syncDB) {
+		return nil, fmt.Errorf("the scratch database of the backups can't be the synchronizer database")
+	}
+	return &Backuper{
+		cfg:    cfg,
+		db:     syncDB,
+		height: height,
+		run:    runCommand,
+		now:    time.Now,
+		lock:   &pgLock{db: syncDB},
+	}, nil
+}
+
+func sameDatabase(a, b db.Config) bool {
+	return a.Host == b.Host && a.Port == b.Port && a.Name == b.Name
+}
+
+// connEnv returns the libpq environment variables of the connection to a database, so the password isn't in the
+// arguments of the processes.
+func connEnv(cfg db.Config) []string {
+	return []string{
+		"PGHOST=" + cfg.Host,
+		"PGPORT=" + cfg.Port,
+		"PGUSER=" + cfg.User,
+		"PGPASSWORD=" + cfg.Password,
+		"PGDATABASE=" + cfg.Name,
+	}
+}
+
+func runCommand(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running %s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Create writes a backup of the database in the file. The dump is a consistent snapshot of the database, taken once
+// no serializable transaction can make it inconsistent, so the deposits, the exit trees and the claims agree. The
+// output of pg_dump is streamed to the file, which only exists once the dump is complete.
+func (b *Backuper) Create(ctx context.Context, file string) (*Backup, error) {
+	createdAt := b.now()
+	partial := file + partialExt
+	if err := b.dump(ctx, partial); err != nil {
+		if removeErr := os.Remove(partial); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			log.Errorf("error removing the partial backup %s: %v", partial, removeErr)
+		}
+		return nil, err
+	}
+	if err := os.Rename(partial, file); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Backup{File: file, Size: info.Size(), CreatedAt: createdAt}, nil
+}
+
+// dump pipes the output of pg_dump to the file, synced to the disk before it's renamed.
+func (b *Backuper) dump(ctx context.Context, file string) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) //nolint:gomnd
+	if err != nil {
+		return err
+	}
+	args := []string{"--format=custom", "--serializable-deferrable"}
+	if err := b.run(ctx, connEnv(b.db), f, b.cfg.PgDump, args...); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Restore replaces the tables of the target database with the ones of the backup, in a single transaction so a
+// failed restore leaves the database as it was.
+func (b *Backuper) Restore(ctx context.Context, file string, target db.Config) error {
+	if _, err := os.Stat(file); err != nil {
+		return err
+	}
+	args := []string{"--clean", "--if-exists", "--no-owner", "--single-transaction", "--exit-on-error", "--dbname=" + target.Name, file}
+	return b.run(ctx, connEnv(target), nil, b.cfg.PgRestore, args...)
+}
+
+// Verify restores the backup in the scratch database and checks its exit trees.
+func (b *Backuper) Verify(ctx context.Context, file string) (*Verification, error) {
+	if b.cfg.VerifyDB.Name == "" {
+		return nil, fmt.Errorf("the verification of the backups requires the scratch database Backup.VerifyDB")
+	}
+	if err := b.Restore(ctx, file, b.cfg.VerifyDB); err != nil {
+		return nil, fmt.Errorf("error restoring %s in the scratch database: %w", file, err)
+	}
+	storage, err := b.scratchStorage()
+	if err != nil {
+		return nil, err
+	}
+	trees, err := CheckTrees(ctx, storage, b.height)
+	if err != nil {
+		return nil, fmt.Errorf("error checking the exit trees of %s: %w", file, err)
+	}
+	v := &Verification{File: file, Trees: trees, Consistent: true}
+	for _, tree := range trees {
+		v.Consistent = v.Consistent && tree.Consistent
+	}
+	return v, nil
+}
+
+// scratchStorage returns the storage of the scratch database, kept between the verifications since the restores
+// replace its tables and not the database.
+func (b *Backuper) scratchStorage() (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.verifyStorage == nil {
+		storage, err := db.NewStorage(b.cfg.VerifyDB)
+		if err != nil {
+			return nil, err
+		}
+		b.verifyStorage = storage
+	}
+	return b.verifyStorage, nil
+}
+
+// CheckTrees calculates the local exit tree of each network of the database from its deposits and compares its
+// root with the stored root of the last deposit.
+func CheckTrees(ctx context.Context, storage interface{}, height uint8) ([]TreeCheck, error) {
+	s := storage.(storageInterface)
+	pins, err := s.GetNetworkPins(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	trees := make([]TreeCheck, 0, len(pins))
+	for _, pin := range pins {
+		deposits, err := s.GetNetworkDepositsUntilBlock(ctx, pin.NetworkID, math.MaxInt64, nil)
+		if err != nil {
+			return nil, err
+		}
+		tree := TreeCheck{NetworkID: pin.NetworkID, Deposits: len(deposits), Contiguous: true}
+		if len(deposits) == 0 {
+			tree.Consistent = true
+			trees = append(trees, tree)
+			continue
+		}
+		leaves := make([][bridgectrl.KeyLen]byte, 0, len(deposits))
+		for i, deposit := range deposits {
+			tree.Contiguous = tree.Contiguous && deposit.DepositCount == uint(i)
+			leaves = append(leaves, bridgectrl.HashDeposit(deposit))
+		}
+		tree.Computed = common.Hash(bridgectrl.ComputeRoot(leaves, height))
+		root, err := s.GetRoot(ctx, deposits[len(deposits)-1].DepositCount, pin.NetworkID, nil)
+		if err != nil && !errors.Is(err, gerror.ErrStorageNotFound) {
+			return nil, err
+		}
+		tree.Root = common.BytesToHash(root)
+		tree.Consistent = tree.Contiguous && tree.Root == tree.Computed
+		trees = append(trees, tree)
+	}
+	return trees, nil
+}
+
+// List returns the scheduled backups of the directory, from the oldest.
+func (b *Backuper) List() ([]Backup, error) {
+	entries, err := os.ReadDir(b.cfg.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var backups []Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileExt) {
+			continue
+		}
+		createdAt, err := time.Parse(fileTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileExt))
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, Backup{File: filepath.Join(b.cfg.Dir, name), Size: info.Size(), CreatedAt: createdAt})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.Before(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// NewFile returns the file of a new scheduled backup in the directory, named by the current time.
+func (b *Backuper) NewFile() string {
+	return filepath.Join(b.cfg.Dir, filePrefix+b.now().UTC().Format(fileTimeFormat)+fileExt)
+}
+
+// prune removes the scheduled backups beyond the number kept, from the oldest.
+func (b *Backuper) prune() error {
+	backups, err := b.List()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(backups)-b.cfg.Keep; i++ {
+		if err := os.Remove(backups[i].File); err != nil {
+			return err
+		}
+		log.Infof("backup %s removed", backups[i].File)
+	}
+	return nil
+}
+
+// Start writes a backup every interval until the context is done, in the instance holding the lock of the backups
+// of the database. The other instances try to take it every lockRetryInterval, and run the backups once the instance
+// holding it stops. The first backup is written an interval after the last backup of the directory, so the restarts
+// don't delay nor repeat the backups.
+func (b *Backuper) Start(ctx context.Context) {
+	defer b.lock.release()
+	leader := false
+	var wait time.Duration
+	for {
+		if !leader {
+			acquired, err := b.lock.acquire(ctx)
+			if err != nil {
+				log.Warnf("error taking the lock of the backups: %v", err)
+			}
+			if !acquired {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(lockRetryInterval):
+				}
+				continue
+			}
+			leader = true
+			log.Info("lock of the backups taken, running the scheduled backups")
+			wait = b.firstWait()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		// The lock is lost with its connection, and another instance may have taken it
+		if err := b.lock.held(ctx); err != nil {
+			log.Warnf("lock of the backups lost: %v", err)
+			leader = false
+			continue
+		}
+		if err := b.backup(ctx); err != nil {
+			log.Errorf("backup error: %v", err)
+		}
+		wait = b.cfg.Interval.Duration
+	}
+}
+
+// firstWait returns the time until an interval after the last backup of the directory.
+func (b *Backuper) firstWait() time.Duration {
+	backups, err := b.List()
+	if err != nil {
+		log.Warnf("error listing the backups: %v", err)
+		return 0
+	}
+	if len(backups) == 0 {
+		return 0
+	}
+	last := backups[len(backups)-1].CreatedAt
+	b.mu.Lock()
+	b.lastBackup = last
+	b.mu.Unlock()
+	return last.Add(b.cfg.Interval.Duration).Sub(b.now())
+}
+
+// backup writes a scheduled backup, verifies it if it's configured and prunes the older ones. A backup failing its
+// verification is renamed so it's neither pruned nor counted as one of the kept backups.
+func (b *Backuper) backup(ctx context.Context) error {
+	if err := os.MkdirAll(b.cfg.Dir, 0700); err != nil { //nolint:gomnd
+		return err
+	}
+	file := b.NewFile()
+	backup, err := b.Create(ctx, file)
+	if err != nil {
+		return err
+	}
+	log.Infof("backup %s written, %d bytes", backup.File, backup.Size)
+	b.mu.Lock()
+	b.lastBackup = backup.CreatedAt
+	b.mu.Unlock()
+	if b.cfg.Verify {
+		v, err := b.Verify(ctx, file)
+		if err != nil {
+			return err
+		}
+		if !v.Consistent {
+			if err := os.Rename(file, file+invalidExt); err != nil {
+				return err
+			}
+			return fmt.Errorf("the exit trees of the backup %s are inconsistent, renamed to %s", file, file+invalidExt)
+		}
+		log.Infof("backup %s verified", file)
+		b.mu.Lock()
+		b.lastVerified = backup.CreatedAt
+		b.mu.Unlock()
+	}
+	return b.prune()
+}
+
+// Describe implements prometheus.Collector.
+func (b *Backuper) Describe(ch chan<- *prometheus.Desc) {
+	ch <- lastBackupDesc
+	ch <- lastVerifiedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (b *Backuper) Collect(ch chan<- prometheus.Metric) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.lastBackup.IsZero() {
+		ch <- prometheus.MustNewConstMetric(lastBackupDesc, prometheus.GaugeValue, float64(b.lastBackup.Unix()))
+	}
+	if !b.lastVerified.IsZero() {
+		ch <- prometheus.MustNewConstMetric(lastVerifiedDesc, prometheus.GaugeValue, float64(b.lastVerified.Unix()))
+	}
+}
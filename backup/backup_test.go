@@ -0,0 +1,274 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// treeStorage returns the deposits and the stored roots of the networks.
+type treeStorage struct {
+	deposits map[uint][]*etherman.Deposit
+	roots    map[uint][]byte
+}
+
+func (s *treeStorage) GetNetworkPins(ctx context.Context, dbTx pgx.Tx) ([]*etherman.NetworkPin, error) {
+	return []*etherman.NetworkPin{{NetworkID: 0}, {NetworkID: 1}, {NetworkID: 2}}, nil
+}
+
+func (s *treeStorage) GetNetworkDepositsUntilBlock(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	return s.deposits[networkID], nil
+}
+
+func (s *treeStorage) GetRoot(ctx context.Context, depositCnt uint, network uint, dbTx pgx.Tx) ([]byte, error) {
+	root, found := s.roots[network]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return root, nil
+}
+
+func TestCheckTrees(t *testing.T) {
+	const height = 32
+	deposits := func(networkID uint, counts ...uint) []*etherman.Deposit {
+		var res []*etherman.Deposit
+		for _, count := range counts {
+			res = append(res, &etherman.Deposit{
+				NetworkID:          networkID,
+				DepositCount:       count,
+				Amount:             big.NewInt(int64(count + 1)),
+				DestinationAddress: common.HexToAddress("0xc949254d682d8c9ad5682521675b8f43b102aec4"),
+			})
+		}
+		return res
+	}
+	root := func(deposits []*etherman.Deposit) []byte {
+		var leaves [][bridgectrl.KeyLen]byte
+		for _, deposit := range deposits {
+			leaves = append(leaves, bridgectrl.HashDeposit(deposit))
+		}
+		computed := bridgectrl.ComputeRoot(leaves, height)
+		return computed[:]
+	}
+	storage := &treeStorage{
+		deposits: map[uint][]*etherman.Deposit{0: deposits(0, 0, 1, 2), 1: deposits(1, 0, 2)},
+		roots:    map[uint][]byte{},
+	}
+	storage.roots[0] = root(storage.deposits[0])
+	storage.roots[1] = root(storage.deposits[1])
+
+	trees, err := CheckTrees(context.Background(), storage, height)
+	require.NoError(t, err)
+	require.Len(t, trees, 3)
+	require.Equal(t, 3, trees[0].Deposits)
+	require.True(t, trees[0].Consistent)
+	require.Equal(t, common.BytesToHash(storage.roots[0]), trees[0].Computed)
+	// The roots agree, but a deposit is missing
+	require.False(t, trees[1].Contiguous)
+	require.False(t, trees[1].Consistent)
+	// A network without deposits has an empty tree
+	require.Equal(t, 0, trees[2].Deposits)
+	require.True(t, trees[2].Consistent)
+
+	// A stored root of other deposits, or a missing one, is inconsistent
+	storage.roots[0] = root(deposits(0, 0, 1))
+	delete(storage.roots, 1)
+	storage.deposits[1] = deposits(1, 0, 1)
+	trees, err = CheckTrees(context.Background(), storage, height)
+	require.NoError(t, err)
+	require.False(t, trees[0].Consistent)
+	require.True(t, trees[1].Contiguous)
+	require.False(t, trees[1].Consistent)
+	require.Equal(t, common.Hash{}, trees[1].Root)
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	syncDB := db.Config{Name: "bridge", User: "user", Password: "secret", Host: "db", Port: "5432"}
+	b, err := NewBackuper(Config{PgDump: "pg_dump", PgRestore: "pg_restore"}, syncDB, 32)
+	require.NoError(t, err)
+	var (
+		gotEnv  []string
+		gotArgs []string
+	)
+	b.run = func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
+		gotEnv, gotArgs = env, args
+		if stdout == nil {
+			return errors.New("no output")
+		}
+		_, err := stdout.Write([]byte("dump"))
+		return err
+	}
+
+	file := filepath.Join(dir, "bridge.dump")
+	backup, err := b.Create(context.Background(), file)
+	require.NoError(t, err)
+	require.Equal(t, file, backup.File)
+	require.Equal(t, int64(4), backup.Size)
+	require.Contains(t, gotArgs, "--serializable-deferrable")
+	// The dump is streamed to the file, not written by pg_dump
+	for _, arg := range gotArgs {
+		require.False(t, strings.HasPrefix(arg, "--file="))
+	}
+	require.Contains(t, gotEnv, "PGPASSWORD=secret")
+	// The password is only in the environment
+	for _, arg := range gotArgs {
+		require.NotContains(t, arg, "secret")
+	}
+	_, err = os.Stat(file + partialExt)
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	// A failed dump leaves no file
+	b.run = func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
+		_, err := stdout.Write([]byte("du"))
+		require.NoError(t, err)
+		return errors.New("connection refused")
+	}
+	_, err = b.Create(context.Background(), filepath.Join(dir, "failed.dump"))
+	require.Error(t, err)
+	_, err = os.Stat(filepath.Join(dir, "failed.dump"+partialExt))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+	_, err = os.Stat(filepath.Join(dir, "failed.dump"))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	// The scratch database can't be the synchronizer one
+	_, err = NewBackuper(Config{VerifyDB: syncDB}, syncDB, 32)
+	require.Error(t, err)
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	b, err := NewBackuper(Config{Dir: dir, Keep: 2, Interval: types.NewDuration(time.Hour)}, db.Config{}, 32)
+	require.NoError(t, err)
+	b.now = func() time.Time { return now }
+	b.run = func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
+		_, err := stdout.Write([]byte("dump"))
+		return err
+	}
+	// The invalid backups and the other files are never pruned
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bridge-20240101T000000Z.dump"+invalidExt), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0600))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.backup(context.Background()))
+		now = now.Add(time.Hour)
+	}
+
+	backups, err := b.List()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	require.Equal(t, filepath.Join(dir, "bridge-20240301T130000Z.dump"), backups[0].File)
+	require.Equal(t, time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC), backups[1].CreatedAt)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+}
+
+// sharedLock is a lock of the backups shared by the instances, held by one of them until it's released or lost.
+type sharedLock struct {
+	mu     sync.Mutex
+	holder *instanceLock
+}
+
+// instanceLock is the lock of an instance, lost once lost is closed.
+type instanceLock struct {
+	shared *sharedLock
+	lost   chan struct{}
+}
+
+func (l *instanceLock) acquire(ctx context.Context) (bool, error) {
+	l.shared.mu.Lock()
+	defer l.shared.mu.Unlock()
+	if l.shared.holder != nil && l.shared.holder != l {
+		return false, nil
+	}
+	l.shared.holder = l
+	return true, nil
+}
+
+func (l *instanceLock) held(ctx context.Context) error {
+	select {
+	case <-l.lost:
+		return errors.New("connection lost")
+	default:
+		return nil
+	}
+}
+
+func (l *instanceLock) release() {
+	l.shared.mu.Lock()
+	defer l.shared.mu.Unlock()
+	if l.shared.holder == l {
+		l.shared.holder = nil
+	}
+}
+
+func TestStartLock(t *testing.T) {
+	shared := &sharedLock{}
+	// The instances write their backups in directories of their own
+	start := func(ctx context.Context) (*instanceLock, chan struct{}) {
+		b, err := NewBackuper(Config{Dir: t.TempDir(), Keep: 10, Interval: types.NewDuration(20 * time.Millisecond)}, db.Config{}, 32)
+		require.NoError(t, err)
+		lock := &instanceLock{shared: shared, lost: make(chan struct{})}
+		b.lock = lock
+		dumps := make(chan struct{}, 100)
+		b.run = func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
+			dumps <- struct{}{}
+			_, err := stdout.Write([]byte("dump"))
+			return err
+		}
+		go b.Start(ctx)
+		return lock, dumps
+	}
+	waitDumps := func(dumps chan struct{}, n int) {
+		for i := 0; i < n; i++ {
+			select {
+			case <-dumps:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the instance holding the lock didn't write the backups")
+			}
+		}
+	}
+	noDumps := func(dumps chan struct{}) {
+		select {
+		case <-dumps:
+			t.Fatal("the instance without the lock wrote a backup")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Only the instance holding the lock runs the backups
+	leaderLock, leaderDumps := start(ctx)
+	waitDumps(leaderDumps, 2)
+	standbyLock, standbyDumps := start(ctx)
+	noDumps(standbyDumps)
+
+	// The instance that lost the lock, taken by another one, stops running the backups
+	shared.mu.Lock()
+	shared.holder = standbyLock
+	shared.mu.Unlock()
+	close(leaderLock.lost)
+	time.Sleep(50 * time.Millisecond)
+	for len(leaderDumps) > 0 {
+		<-leaderDumps
+	}
+	noDumps(leaderDumps)
+}
@@ -0,0 +1,48 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the backups of the bridge database
+type Config struct {
+	// Enabled writes a backup every interval in the run command
+	Enabled bool `mapstructure:"Enabled"`
+	// Interval is the time between two scheduled backups
+	Interval types.Duration `mapstructure:"Interval"`
+	// Dir is the directory of the scheduled backups
+	Dir string `mapstructure:"Dir"`
+	// Keep is the number of scheduled backups kept in the directory, the older ones are removed
+	Keep int `mapstructure:"Keep"`
+	// PgDump is the pg_dump binary, of the major version of the database server or a later one
+	PgDump string `mapstructure:"PgDump"`
+	// PgRestore is the pg_restore binary, of the version of PgDump
+	PgRestore string `mapstructure:"PgRestore"`
+	// Verify restores each scheduled backup in the VerifyDB and checks its exit trees
+	Verify bool `mapstructure:"Verify"`
+	// VerifyDB is the scratch database the backups are restored in to verify them. Its tables are replaced
+	VerifyDB db.Config `mapstructure:"VerifyDB"`
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if c.Interval.Duration <= 0 {
+		return fmt.Errorf("the interval of the backups must be positive")
+	}
+	if c.Dir == "" {
+		return fmt.Errorf("the directory of the backups is required")
+	}
+	if c.Keep <= 0 {
+		return fmt.Errorf("the number of backups kept must be positive")
+	}
+	if c.PgDump == "" || c.PgRestore == "" {
+		return fmt.Errorf("the pg_dump and pg_restore binaries are required")
+	}
+	if c.Verify && c.VerifyDB.Name == "" {
+		return fmt.Errorf("the verification of the backups requires a scratch database")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+)
+
+// lockKey is the key of the advisory lock held by the instance running the scheduled backups
+const lockKey int64 = 0x6261636b7570
+
+// locker is the lock of the instance running the scheduled backups.
+type locker interface {
+	// acquire tries to take the lock, false if another instance holds it
+	acquire(ctx context.Context) (bool, error)
+	// held returns an error if the lock was lost
+	held(ctx context.Context) error
+	release()
+}
+
+// pgLock is a session advisory lock of the synchronizer database, held by a connection of its own while the
+// instance runs the backups. The lock is released by the server when the connection is lost.
+type pgLock struct {
+	db   db.Config
+	conn *pgx.Conn
+}
+
+func (l *pgLock) acquire(ctx context.Context) (bool, error) {
+	conn, err := pgx.Connect(ctx, fmt.Sprintf("postgres://%s:%s@%s:%s/%s", l.db.User, l.db.Password, l.db.Host, l.db.Port, l.db.Name))
+	if err != nil {
+		return false, err
+	}
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", lockKey).Scan(&acquired); err != nil || !acquired {
+		l.close(conn)
+		return false, err
+	}
+	l.conn = conn
+	return true, nil
+}
+
+func (l *pgLock) held(ctx context.Context) error {
+	if l.conn == nil {
+		return fmt.Errorf("the lock is not taken")
+	}
+	if err := l.conn.Ping(ctx); err != nil {
+		l.close(l.conn)
+		l.conn = nil
+		return err
+	}
+	return nil
+}
+
+// release closes the connection holding the lock, which releases it.
+func (l *pgLock) release() {
+	if l.conn != nil {
+		l.close(l.conn)
+		l.conn = nil
+	}
+}
+
+func (l *pgLock) close(conn *pgx.Conn) {
+	if err := conn.Close(context.Background()); err != nil {
+		log.Warnf("error closing the connection of the lock of the backups: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+// Package blocktime measures the block time of each network from the latest blocks seen by its synchronizer, so
+// the polling of the network follows its chain: fast on the L2s with blocks every few seconds and slower on L1.
+package blocktime
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// averageWeight is the weight of the last sample in the moving average
+const averageWeight = 0.2
+
+var blockTimeDesc = prometheus.NewDesc("bridge_block_time_seconds",
+	"Moving average of the observed block time of the network", []string{"network_id"}, nil)
+
+// Config is the configuration of an interval adapted to the block time of the network.
+type Config struct {
+	// Enabled makes the interval follow the observed block time of the network, instead of the fixed one
+	Enabled bool `mapstructure:"Enabled"`
+	// MinInterval is the shortest interval, so the networks with very fast blocks are not polled in a loop
+	MinInterval types.Duration `mapstructure:"MinInterval"`
+	// MaxInterval is the longest interval, so the networks with slow blocks or stalled are still polled. 0 doesn't limit it
+	MaxInterval types.Duration `mapstructure:"MaxInterval"`
+}
+
+// Validate checks that the min interval is not over the max interval.
+func (c Config) Validate() error {
+	if c.MaxInterval.Duration > 0 && c.MinInterval.Duration > c.MaxInterval.Duration {
+		return fmt.Errorf("the min interval %s is over the max interval %s", c.MinInterval.Duration, c.MaxInterval.Duration)
+	}
+	return nil
+}
+
+// Stats is the block time observed in a network.
+type Stats struct {
+	NetworkID uint
+	// BlockTime is the moving average of the time between blocks
+	BlockTime time.Duration
+	// Samples is the number of block ranges measured
+	Samples uint64
+}
+
+type network struct {
+	stats       Stats
+	lastNumber  uint64
+	lastTime    uint64
+	initialized bool
+}
+
+// Tracker measures the block time of the networks from the numbers and the timestamps of their latest blocks.
+type Tracker struct {
+	mu       sync.Mutex
+	networks map[uint]*network
+}
+
+// NewTracker creates an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{networks: make(map[uint]*network)}
+}
+
+// Observe records the latest block of the network with its timestamp in unix seconds. The block time is measured
+// over the blocks since the previous observation, so the second precision of the timestamps is averaged out on
+// the networks with several blocks per second.
+func (t *Tracker) Observe(networkID uint, number, timestamp uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n, found := t.networks[networkID]
+	if !found {
+		n = &network{stats: Stats{NetworkID: networkID}}
+		t.networks[networkID] = n
+	}
+	// The first block, the same block or a reorged one only move the reference
+	if n.initialized && number > n.lastNumber && timestamp >= n.lastTime {
+		sample := time.Duration(timestamp-n.lastTime) * time.Second / time.Duration(number-n.lastNumber)
+		if n.stats.Samples == 0 {
+			n.stats.BlockTime = sample
+		} else {
+			n.stats.BlockTime = time.Duration(averageWeight*float64(sample) + (1-averageWeight)*float64(n.stats.BlockTime))
+		}
+		n.stats.Samples++
+	}
+	if number != n.lastNumber || !n.initialized {
+		n.lastNumber, n.lastTime, n.initialized = number, timestamp, true
+	}
+}
+
+// BlockTime returns the observed block time of the network, false until it's measured.
+func (t *Tracker) BlockTime(networkID uint) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n, found := t.networks[networkID]
+	if !found || n.stats.Samples == 0 {
+		return 0, false
+	}
+	return n.stats.BlockTime, true
+}
+
+// Interval returns the block time of the network between the min and the max interval of the config. The fallback
+// is returned when the adaptive interval is disabled or the block time is not measured yet.
+func (t *Tracker) Interval(cfg Config, networkID uint, fallback time.Duration) time.Duration {
+	if !cfg.Enabled {
+		return fallback
+	}
+	interval, found := t.BlockTime(networkID)
+	if !found {
+		return fallback
+	}
+	if interval < cfg.MinInterval.Duration {
+		interval = cfg.MinInterval.Duration
+	}
+	if cfg.MaxInterval.Duration > 0 && interval > cfg.MaxInterval.Duration {
+		interval = cfg.MaxInterval.Duration
+	}
+	return interval
+}
+
+// Stats returns the stats of the observed networks sorted by network id.
+func (t *Tracker) Stats() []Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := make([]Stats, 0, len(t.networks))
+	for _, n := range t.networks {
+		stats = append(stats, n.stats)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].NetworkID < stats[j].NetworkID })
+	return stats
+}
+
+// Describe implements prometheus.Collector.
+func (t *Tracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- blockTimeDesc
+}
+
+// Collect implements prometheus.Collector.
+func (t *Tracker) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range t.Stats() {
+		if s.Samples == 0 {
+			continue
+		}
+		networkID := strconv.FormatUint(uint64(s.NetworkID), 10) //nolint:gomnd
+		ch <- prometheus.MustNewConstMetric(blockTimeDesc, prometheus.GaugeValue, s.BlockTime.Seconds(), networkID)
+	}
+}
+
+// Default is the tracker fed by the synchronizers and read by the synchronizers and the claim tx managers.
+var Default = NewTracker()
+
+// Observe records the latest block of a network in the default tracker.
+func Observe(networkID uint, number, timestamp uint64) {
+	Default.Observe(networkID, number, timestamp)
+}
+
+// Interval returns the interval of a network from the default tracker.
+func Interval(cfg Config, networkID uint, fallback time.Duration) time.Duration {
+	return Default.Interval(cfg, networkID, fallback)
+}
@@ -0,0 +1,61 @@
+package blocktime
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	cfg := Config{Enabled: true, MinInterval: types.NewDuration(time.Second), MaxInterval: types.NewDuration(10 * time.Second)}
+
+	// The first block and the same block again don't measure the block time
+	tracker.Observe(0, 100, 1700000000)
+	tracker.Observe(0, 100, 1700000000)
+	_, found := tracker.BlockTime(0)
+	require.False(t, found)
+	require.Equal(t, 2*time.Second, tracker.Interval(cfg, 0, 2*time.Second))
+
+	tracker.Observe(0, 101, 1700000012)
+	blockTime, found := tracker.BlockTime(0)
+	require.True(t, found)
+	require.Equal(t, 12*time.Second, blockTime)
+	tracker.Observe(0, 103, 1700000032)
+	blockTime, _ = tracker.BlockTime(0)
+	require.Equal(t, time.Duration(0.2*float64(10*time.Second)+0.8*float64(12*time.Second)), blockTime)
+	// The interval is limited by the max interval, and it's the fixed one when disabled
+	require.Equal(t, 10*time.Second, tracker.Interval(cfg, 0, 2*time.Second))
+	require.Equal(t, 2*time.Second, tracker.Interval(Config{}, 0, 2*time.Second))
+
+	// Several blocks per second are averaged over the blocks since the last observation
+	tracker.Observe(1, 1000, 1700000000)
+	tracker.Observe(1, 1004, 1700000001)
+	blockTime, _ = tracker.BlockTime(1)
+	require.Equal(t, 250*time.Millisecond, blockTime)
+	require.Equal(t, time.Second, tracker.Interval(cfg, 1, 2*time.Second))
+
+	// A reorg to a previous block only moves the reference
+	tracker.Observe(1, 1002, 1700000001)
+	tracker.Observe(1, 1006, 1700000003)
+	blockTime, _ = tracker.BlockTime(1)
+	require.Equal(t, time.Duration(0.2*float64(500*time.Millisecond)+0.8*float64(250*time.Millisecond)), blockTime)
+
+	expected := `
+# HELP bridge_block_time_seconds Moving average of the observed block time of the network
+# TYPE bridge_block_time_seconds gauge
+bridge_block_time_seconds{network_id="0"} 11.6
+bridge_block_time_seconds{network_id="1"} 0.3
+`
+	require.NoError(t, testutil.CollectAndCompare(tracker, strings.NewReader(expected)))
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, Config{}.Validate())
+	require.NoError(t, Config{MinInterval: types.NewDuration(time.Second)}.Validate())
+	require.Error(t, Config{MinInterval: types.NewDuration(time.Minute), MaxInterval: types.NewDuration(time.Second)}.Validate())
+}
@@ -13,14 +13,17 @@ const (
 	KeyLen = 32
 )
 
-// BridgeController struct
+// BridgeController manages the trees of the bridge: the local exit tree of each network, the rollup exit
+// tree of the local exit roots of the rollups and the L1 info tree of the global exit roots.
 type BridgeController struct {
-	exitTrees  []*MerkleTree
-	networkIDs map[uint]uint8
+	exitTrees      []*MerkleTree
+	rollupExitTree *RollupExitTree
+	l1InfoTree     *L1InfoTree
+	networkIDs     map[uint]uint8
 }
 
 // NewBridgeController creates new BridgeController.
-func NewBridgeController(cfg Config, networks []uint, mtStore interface{}) (*BridgeController, error) {
+func NewBridgeController(ctx context.Context, cfg Config, networks []uint, mtStore interface{}) (*BridgeController, error) {
 	var (
 		networkIDs = make(map[uint]uint8)
 		exitTrees  []*MerkleTree
@@ -28,16 +31,22 @@ func NewBridgeController(cfg Config, networks []uint, mtStore interface{}) (*Bri
 
 	for i, network := range networks {
 		networkIDs[network] = uint8(i)
-		mt, err := NewMerkleTree(context.TODO(), mtStore.(merkleTreeStore), cfg.Height, network)
+		mt, err := NewMerkleTree(ctx, mtStore.(merkleTreeStore), cfg.Height, network)
 		if err != nil {
 			return nil, err
 		}
 		exitTrees = append(exitTrees, mt)
 	}
+	l1InfoTree, err := NewL1InfoTree(ctx, mtStore.(l1InfoTreeStore), cfg.Height)
+	if err != nil {
+		return nil, err
+	}
 
 	return &BridgeController{
-		exitTrees:  exitTrees,
-		networkIDs: networkIDs,
+		exitTrees:      exitTrees,
+		rollupExitTree: NewRollupExitTree(mtStore.(rollupExitTreeStore), cfg.Height),
+		l1InfoTree:     l1InfoTree,
+		networkIDs:     networkIDs,
 	}, nil
 }
 
@@ -51,7 +60,7 @@ func (bt *BridgeController) getNetworkID(networkID uint) (uint8, error) {
 
 // AddDeposit adds deposit information to the bridge tree.
 func (bt *BridgeController) AddDeposit(deposit *etherman.Deposit, depositID uint64, dbTx pgx.Tx) error {
-	leaf := hashDeposit(deposit)
+	leaf := HashDeposit(deposit)
 	tID, err := bt.getNetworkID(deposit.NetworkID)
 	if err != nil {
 		return err
@@ -68,6 +77,28 @@ func (bt *BridgeController) ReorgMT(depositCount uint, networkID uint, dbTx pgx.
 	return bt.exitTrees[tID].resetLeaf(context.TODO(), depositCount, dbTx)
 }
 
+// AddRollupExitLeaf updates the leaf of a rollup in the rollup exit tree.
+func (bt *BridgeController) AddRollupExitLeaf(ctx context.Context, leaf *etherman.RollupExitLeaf, dbTx pgx.Tx) error {
+	return bt.rollupExitTree.updateLeaf(ctx, leaf, dbTx)
+}
+
+// AddL1InfoTreeLeaf appends a global exit root update to the L1 info tree.
+func (bt *BridgeController) AddL1InfoTreeLeaf(ctx context.Context, leaf *etherman.L1InfoTreeLeaf, dbTx pgx.Tx) error {
+	return bt.l1InfoTree.addLeaf(ctx, leaf, dbTx)
+}
+
+// ReorgL1InfoTree reloads the L1 info tree after a reorg of L1. The rollup exit tree doesn't need it since
+// it's calculated from the stored leaves.
+func (bt *BridgeController) ReorgL1InfoTree(ctx context.Context, dbTx pgx.Tx) error {
+	return bt.l1InfoTree.reset(ctx, dbTx)
+}
+
+// GetL1InfoTreeProof returns the merkle proof of the leaf of the L1 info tree at the given index, in the tree of
+// the first count leaves.
+func (bt *BridgeController) GetL1InfoTreeProof(index, count uint) (*L1InfoTreeProof, error) {
+	return bt.l1InfoTree.proof(index, count)
+}
+
 // GetExitRoot returns the dedicated merkle tree's root.
 // only use for the test purpose
 func (bt *BridgeController) GetExitRoot(networkID int, dbTx pgx.Tx) ([]byte, error) {
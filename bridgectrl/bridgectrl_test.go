@@ -50,10 +50,9 @@ func TestBridgeTree(t *testing.T) {
 	store, err := pgstorage.NewPostgresStorage(dbCfg)
 	require.NoError(t, err)
 
-	bt, err := NewBridgeController(cfg, []uint{0, 1000}, store)
-	require.NoError(t, err)
-
 	ctx := context.TODO()
+	bt, err := NewBridgeController(ctx, cfg, []uint{0, 1000}, store)
+	require.NoError(t, err)
 	t.Run("Test adding deposit for the bridge tree", func(t *testing.T) {
 		for i, testVector := range testVectors {
 			block := &etherman.Block{
@@ -75,7 +74,7 @@ func TestBridgeTree(t *testing.T) {
 				DepositCount:       uint(i),
 				Metadata:           common.FromHex(testVector.Metadata),
 			}
-			leafHash := hashDeposit(deposit)
+			leafHash := HashDeposit(deposit)
 			assert.Equal(t, testVector.ExpectedHash, hex.EncodeToString(leafHash[:]))
 			depositID, err := store.AddDeposit(ctx, deposit, nil)
 			require.NoError(t, err)
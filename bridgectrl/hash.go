@@ -34,7 +34,8 @@ func generateZeroHashes(height uint8) [][KeyLen]byte {
 	return zeroHashes
 }
 
-func hashDeposit(deposit *etherman.Deposit) [KeyLen]byte {
+// HashDeposit calculates the leaf hash of a deposit in the exit merkle tree.
+func HashDeposit(deposit *etherman.Deposit) [KeyLen]byte {
 	var res [KeyLen]byte
 	origNet := make([]byte, 4) //nolint:gomnd
 	binary.BigEndian.PutUint32(origNet, uint32(deposit.OriginalNetwork))
@@ -45,3 +46,12 @@ func hashDeposit(deposit *etherman.Deposit) [KeyLen]byte {
 	copy(res[:], keccak256.Hash([]byte{deposit.LeafType}, origNet, deposit.OriginalAddress[:], destNet, deposit.DestinationAddress[:], deposit.Amount.FillBytes(buf[:]), metaHash))
 	return res
 }
+
+// HashL1InfoLeaf calculates the leaf hash of a global exit root update in the L1 info tree.
+func HashL1InfoLeaf(ger, parentHash [KeyLen]byte, timestamp uint64) [KeyLen]byte {
+	var res [KeyLen]byte
+	ts := make([]byte, 8) //nolint:gomnd
+	binary.BigEndian.PutUint64(ts, timestamp)
+	copy(res[:], keccak256.Hash(ger[:], parentHash[:], ts))
+	return res
+}
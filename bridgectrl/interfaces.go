@@ -3,6 +3,7 @@ package bridgectrl
 import (
 	"context"
 
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -14,3 +15,17 @@ type merkleTreeStore interface {
 	SetRoot(ctx context.Context, root []byte, depositID uint64, network uint, dbTx pgx.Tx) error
 	GetLastDepositCount(ctx context.Context, network uint, dbTx pgx.Tx) (uint, error)
 }
+
+// rollupExitTreeStore interface for the Rollup Exit Tree
+type rollupExitTreeStore interface {
+	AddRollupExitLeaf(ctx context.Context, leaf *etherman.RollupExitLeaf, dbTx pgx.Tx) error
+	GetLatestRollupExitLeaves(ctx context.Context, dbTx pgx.Tx) ([]etherman.RollupExitLeaf, error)
+}
+
+// l1InfoTreeStore interface for the L1 Info Tree
+type l1InfoTreeStore interface {
+	AddL1InfoTreeLeaf(ctx context.Context, leaf *etherman.L1InfoTreeLeaf, dbTx pgx.Tx) error
+	GetL1InfoTreeLeaves(ctx context.Context, dbTx pgx.Tx) ([]etherman.L1InfoTreeLeaf, error)
+	GetL1InfoTreeLeafCount(ctx context.Context, dbTx pgx.Tx) (uint, error)
+	GetL1GlobalExitRootUpdates(ctx context.Context, dbTx pgx.Tx) ([]etherman.L1InfoTreeLeaf, error)
+}
@@ -0,0 +1,178 @@
+package bridgectrl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// L1InfoTree is the append only tree of the global exit root updates in L1. Only its leaves are stored, the
+// nodes are kept in memory to calculate the new roots and the proofs of the leaves.
+type L1InfoTree struct {
+	store  l1InfoTreeStore
+	height uint8
+	mu     sync.RWMutex
+	// nodes are the non empty nodes of each level, from the leaves to the children of the root
+	nodes [][][KeyLen]byte
+	root  [KeyLen]byte
+}
+
+// NewL1InfoTree creates new L1InfoTree.
+func NewL1InfoTree(ctx context.Context, store l1InfoTreeStore, height uint8) (*L1InfoTree, error) {
+	lt := &L1InfoTree{
+		store:  store,
+		height: height,
+	}
+	if err := lt.load(ctx, nil); err != nil {
+		return nil, err
+	}
+	// The global exit roots synced before the tree existed are appended to it
+	updates, err := store.GetL1GlobalExitRootUpdates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := lt.count(); i < uint(len(updates)); i++ {
+		if err := lt.addLeaf(ctx, &updates[i], nil); err != nil {
+			return nil, err
+		}
+	}
+	return lt, nil
+}
+
+// count returns the number of leaves.
+func (lt *L1InfoTree) count() uint {
+	return uint(len(lt.nodes[0]))
+}
+
+// load replays the stored leaves to calculate the nodes of the tree.
+func (lt *L1InfoTree) load(ctx context.Context, dbTx pgx.Tx) error {
+	leaves, err := lt.store.GetL1InfoTreeLeaves(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	lt.nodes = make([][][KeyLen]byte, lt.height)
+	lt.root = zeroHashes[lt.height]
+	for _, leaf := range leaves {
+		lt.append(leaf.Leaf)
+	}
+	return nil
+}
+
+// append adds the leaf to the tree, returning the new root.
+func (lt *L1InfoTree) append(leaf [KeyLen]byte) [KeyLen]byte {
+	lt.nodes[0] = append(lt.nodes[0], leaf)
+	lt.updatePath(lt.count() - 1)
+	return lt.root
+}
+
+// truncate keeps the first count leaves of the tree.
+func (lt *L1InfoTree) truncate(count uint) {
+	for h := range lt.nodes {
+		// the number of nodes of the level is the number of leaves rounded up to its sub trees
+		lt.nodes[h] = lt.nodes[h][:(count+1<<h-1)>>h]
+	}
+	if count == 0 {
+		lt.root = zeroHashes[lt.height]
+		return
+	}
+	// the path of the new last leaf had the removed ones in its right sub trees
+	lt.updatePath(count - 1)
+}
+
+// updatePath calculates the nodes from the last leaf, at the given index, to the root.
+func (lt *L1InfoTree) updatePath(index uint) {
+	cur := lt.nodes[0][index]
+	for h := uint8(0); h < lt.height; h++ {
+		i := index >> h
+		if i%2 == 1 {
+			cur = Hash(lt.nodes[h][i-1], cur)
+		} else {
+			cur = Hash(cur, zeroHashes[h])
+		}
+		if h+1 == lt.height {
+			break
+		}
+		if parent := i >> 1; parent < uint(len(lt.nodes[h+1])) {
+			lt.nodes[h+1][parent] = cur
+		} else {
+			lt.nodes[h+1] = append(lt.nodes[h+1], cur)
+		}
+	}
+	lt.root = cur
+}
+
+// addLeaf appends the global exit root update to the tree, storing it with the new L1 info root.
+func (lt *L1InfoTree) addLeaf(ctx context.Context, leaf *etherman.L1InfoTreeLeaf, dbTx pgx.Tx) error {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	// The leaves added in a db tx that was rolled back are still in the tree, so it's reloaded when the stored
+	// leaves don't match it
+	count, err := lt.store.GetL1InfoTreeLeafCount(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	if count != lt.count() {
+		if err := lt.load(ctx, dbTx); err != nil {
+			return err
+		}
+	}
+	leaf.Index = lt.count()
+	leaf.Leaf = common.Hash(HashL1InfoLeaf(leaf.GlobalExitRoot, leaf.ParentHash, uint64(leaf.Timestamp.Unix())))
+	leaf.Root = common.Hash(lt.append(leaf.Leaf))
+	if err := lt.store.AddL1InfoTreeLeaf(ctx, leaf, dbTx); err != nil {
+		// The leaf is not added if it's not stored
+		lt.truncate(leaf.Index)
+		return err
+	}
+	return nil
+}
+
+// reset reloads the tree from the stored leaves, after a reorg removed some of them.
+func (lt *L1InfoTree) reset(ctx context.Context, dbTx pgx.Tx) error {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	return lt.load(ctx, dbTx)
+}
+
+// L1InfoTreeProof is the merkle proof of a leaf of the L1 info tree.
+type L1InfoTreeProof struct {
+	Leaf     [KeyLen]byte
+	Siblings [][KeyLen]byte
+	Root     [KeyLen]byte
+}
+
+// proof returns the merkle proof of the leaf at the given index in the tree of the first count leaves. The last
+// leaves may be from a db tx not committed yet, so the proofs are calculated for the stored ones.
+func (lt *L1InfoTree) proof(index, count uint) (*L1InfoTreeProof, error) {
+	lt.mu.RLock()
+	defer lt.mu.RUnlock()
+	if index >= count || count > lt.count() {
+		return nil, fmt.Errorf("index %d out of the %d leaves of the tree with %d leaves", index, count, lt.count())
+	}
+	siblings := make([][KeyLen]byte, 0, lt.height)
+	for h := uint8(0); h < lt.height; h++ {
+		// The sibling of the node in this level is its pair
+		siblings = append(siblings, lt.node(h, (index>>h)^1, count))
+	}
+	return &L1InfoTreeProof{
+		Leaf:     lt.nodes[0][index],
+		Siblings: siblings,
+		Root:     lt.node(lt.height, 0, count),
+	}, nil
+}
+
+// node returns the node of the level at the given index in the tree of the first count leaves. Only the node of
+// the sub tree with the last leaf differs from the nodes of the whole tree, so it's calculated from its children.
+func (lt *L1InfoTree) node(h uint8, i, count uint) [KeyLen]byte {
+	if i<<h >= count {
+		return zeroHashes[h]
+	}
+	if h < lt.height && (i+1)<<h <= count {
+		return lt.nodes[h][i]
+	}
+	return Hash(lt.node(h-1, 2*i, count), lt.node(h-1, 2*i+1, count)) //nolint:gomnd
+}
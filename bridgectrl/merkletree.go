@@ -2,6 +2,7 @@ package bridgectrl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
@@ -38,7 +39,7 @@ func init() {
 func NewMerkleTree(ctx context.Context, store merkleTreeStore, height uint8, network uint) (*MerkleTree, error) {
 	depositCnt, err := store.GetLastDepositCount(ctx, network, nil)
 	if err != nil {
-		if err != gerror.ErrStorageNotFound {
+		if !errors.Is(err, gerror.ErrStorageNotFound) {
 			return nil, err
 		}
 		depositCnt = 0
@@ -108,8 +109,11 @@ func (mt *MerkleTree) initSiblings(ctx context.Context, dbTx pgx.Tx) ([][KeyLen]
 }
 
 func (mt *MerkleTree) addLeaf(ctx context.Context, depositID uint64, leaf [KeyLen]byte, index uint, dbTx pgx.Tx) error {
+	if index < mt.count {
+		return gerror.Wrap(gerror.ErrAlreadyProcessed, fmt.Errorf("deposit count %d already added, expected: %d", index, mt.count))
+	}
 	if index != mt.count {
-		return fmt.Errorf("mismatched deposit count: %d, expected: %d", index, mt.count)
+		return gerror.Wrap(gerror.ErrDepositGap, fmt.Errorf("mismatched deposit count: %d, expected: %d", index, mt.count))
 	}
 	cur := leaf
 	isFilledSubTree := true
@@ -14,7 +14,9 @@ import (
 	"github.com/0xPolygonHermez/zkevm-bridge-service/db/pgstorage"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/test/vectors"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -67,7 +69,7 @@ func TestLeafHash(t *testing.T) {
 				DepositCount:       uint(ti + 1),
 				Metadata:           common.FromHex(testVector.Metadata),
 			}
-			leafHash := hashDeposit(deposit)
+			leafHash := HashDeposit(deposit)
 			assert.Equal(t, testVector.ExpectedHash[2:], hex.EncodeToString(leafHash[:]))
 		})
 	}
@@ -128,7 +130,7 @@ func TestMTAddLeaf(t *testing.T) {
 			require.NoError(t, err)
 			assert.Equal(t, hex.EncodeToString(curRoot), testVector.CurrentRoot[2:])
 
-			leafHash := hashDeposit(deposit)
+			leafHash := HashDeposit(deposit)
 			err = mt.addLeaf(ctx, depositIDs[len(depositIDs)-1], leafHash, uint(len(testVector.ExistingLeaves)), nil)
 			require.NoError(t, err)
 			newRoot, err := mt.getRoot(ctx, nil)
@@ -182,7 +184,7 @@ func TestMTGetProof(t *testing.T) {
 				}
 				depositID, err := store.AddDeposit(ctx, deposit, nil)
 				require.NoError(t, err)
-				leafHash := hashDeposit(deposit)
+				leafHash := HashDeposit(deposit)
 				if li == int(testVector.Index) {
 					cur = leafHash
 				}
@@ -205,3 +207,94 @@ func TestMTGetProof(t *testing.T) {
 		})
 	}
 }
+
+// memoryStore is an in memory merkleTreeStore, so the benchmarks measure the tree and not the database.
+type memoryStore struct {
+	nodes map[string][][]byte
+	roots map[uint][]byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{nodes: make(map[string][][]byte), roots: make(map[uint][]byte)}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key []byte, dbTx pgx.Tx) ([][]byte, error) {
+	value, found := s.nodes[string(key)]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return value, nil
+}
+
+func (s *memoryStore) BulkSet(ctx context.Context, rows [][]interface{}, dbTx pgx.Tx) error {
+	for _, row := range rows {
+		s.nodes[string(row[0].([]byte))] = row[1].([][]byte)
+	}
+	return nil
+}
+
+func (s *memoryStore) GetRoot(ctx context.Context, depositCount uint, network uint, dbTx pgx.Tx) ([]byte, error) {
+	root, found := s.roots[depositCount]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return root, nil
+}
+
+func (s *memoryStore) SetRoot(ctx context.Context, root []byte, depositID uint64, network uint, dbTx pgx.Tx) error {
+	s.roots[uint(len(s.roots))] = root
+	return nil
+}
+
+func (s *memoryStore) GetLastDepositCount(ctx context.Context, network uint, dbTx pgx.Tx) (uint, error) {
+	if len(s.roots) == 0 {
+		return 0, gerror.ErrStorageNotFound
+	}
+	return uint(len(s.roots) - 1), nil
+}
+
+func benchDeposits(b *testing.B) []*etherman.Deposit {
+	data, err := os.ReadFile("test/vectors/src/mt-bridge/leaf-vectors.json")
+	require.NoError(b, err)
+	var leafVectors []vectors.DepositVectorRaw
+	require.NoError(b, json.Unmarshal(data, &leafVectors))
+
+	deposits := make([]*etherman.Deposit, 0, len(leafVectors))
+	for i, testVector := range leafVectors {
+		amount, _ := new(big.Int).SetString(testVector.Amount, 0)
+		deposits = append(deposits, &etherman.Deposit{
+			OriginalNetwork:    testVector.OriginalNetwork,
+			OriginalAddress:    common.HexToAddress(testVector.TokenAddress),
+			Amount:             amount,
+			DestinationNetwork: testVector.DestinationNetwork,
+			DestinationAddress: common.HexToAddress(testVector.DestinationAddress),
+			DepositCount:       uint(i + 1),
+			Metadata:           common.FromHex(testVector.Metadata),
+		})
+	}
+	return deposits
+}
+
+func BenchmarkHashDeposit(b *testing.B) {
+	deposits := benchDeposits(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		HashDeposit(deposits[i%len(deposits)])
+	}
+}
+
+func BenchmarkMTAddLeaf(b *testing.B) {
+	ctx := context.Background()
+	deposits := benchDeposits(b)
+	mt, err := NewMerkleTree(ctx, newMemoryStore(), uint8(32), 0)
+	require.NoError(b, err)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		leaf := HashDeposit(deposits[i%len(deposits)])
+		if err := mt.addLeaf(ctx, uint64(i+1), leaf, uint(i), nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
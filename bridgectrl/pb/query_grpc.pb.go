@@ -33,8 +33,136 @@ type BridgeServiceClient interface {
 	GetBridge(ctx context.Context, in *GetBridgeRequest, opts ...grpc.CallOption) (*GetBridgeResponse, error)
 	/// Get claims for the specific smart contract address both in L1 and L2
 	GetClaims(ctx context.Context, in *GetClaimsRequest, opts ...grpc.CallOption) (*GetClaimsResponse, error)
+	/// Stream the new deposits, the deposits ready for claim and the claims of an address as they are synced
+	SubscribeBridgeEvents(ctx context.Context, in *SubscribeBridgeEventsRequest, opts ...grpc.CallOption) (BridgeService_SubscribeBridgeEventsClient, error)
+	/// Stream the changes of status of a deposit, the past ones first, and the batch states of the L2 deposits, until it's claimed
+	StreamDepositStatus(ctx context.Context, in *StreamDepositStatusRequest, opts ...grpc.CallOption) (BridgeService_StreamDepositStatusClient, error)
 	/// Get token wrapped for the specific smart contract address both in L1 and L2
 	GetTokenWrapped(ctx context.Context, in *GetTokenWrappedRequest, opts ...grpc.CallOption) (*GetTokenWrappedResponse, error)
+	/// Get all the mappings of the wrapped tokens of an original token, with their validity ranges
+	GetTokenWrappedHistory(ctx context.Context, in *GetTokenWrappedHistoryRequest, opts ...grpc.CallOption) (*GetTokenWrappedHistoryResponse, error)
+	/// Simulate a claim in the destination network to check if it would succeed
+	ValidateClaim(ctx context.Context, in *ValidateClaimRequest, opts ...grpc.CallOption) (*ValidateClaimResponse, error)
+	/// Resolve an EIP-3668 (CCIP-Read) offchain lookup of a claim proof
+	GetCCIPProof(ctx context.Context, in *GetCCIPProofRequest, opts ...grpc.CallOption) (*GetCCIPProofResponse, error)
+	/// Get the deposits and claims counts and volumes of a network aggregated by hour or day
+	GetActivity(ctx context.Context, in *GetActivityRequest, opts ...grpc.CallOption) (*GetActivityResponse, error)
+	/// Get the deposits counts and volumes of a network by the integration they come from
+	GetIntegrationStats(ctx context.Context, in *GetIntegrationStatsRequest, opts ...grpc.CallOption) (*GetIntegrationStatsResponse, error)
+	/// Get the unsigned claim transaction of a deposit, to be signed and sent by the claimer
+	GetClaimTx(ctx context.Context, in *GetClaimTxRequest, opts ...grpc.CallOption) (*GetClaimTxResponse, error)
+	/// Get the outcome of the mined claim of a deposit sent by the claim tx manager, decoded from its receipt
+	GetClaimOutcome(ctx context.Context, in *GetClaimOutcomeRequest, opts ...grpc.CallOption) (*GetClaimOutcomeResponse, error)
+	/// Get the activity log of a deposit: its changes of status, the attempts of its claim tx and the log lines
+	/// about it, in order
+	GetDepositActivity(ctx context.Context, in *GetDepositActivityRequest, opts ...grpc.CallOption) (*GetDepositActivityResponse, error)
+	/// Get the maintenance in progress, with the notice for the frontends. The writes are rejected during it
+	GetMaintenance(ctx context.Context, in *GetMaintenanceRequest, opts ...grpc.CallOption) (*GetMaintenanceResponse, error)
+	/// Simulate the claims of the deposits ready for claim, with the estimated gas of each claim and their total cost
+	SimulateClaims(ctx context.Context, in *SimulateClaimsRequest, opts ...grpc.CallOption) (*SimulateClaimsResponse, error)
+	/// Get the observed delays between the global exit root updates in L1 and their injection in the destination networks
+	GetGERInjectionLatency(ctx context.Context, in *GetGERInjectionLatencyRequest, opts ...grpc.CallOption) (*GetGERInjectionLatencyResponse, error)
+	/// Get the deposits of a tx, with the originator and the call path of the tx when the deposits are traced
+	GetBridgesByTx(ctx context.Context, in *GetBridgesByTxRequest, opts ...grpc.CallOption) (*GetBridgesByTxResponse, error)
+	/// Search the deposits and the claims whose destination address or tx hash start with a hex prefix
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	/// Get the merkle proof of a global exit root in the L1 info tree, against the latest L1 info root
+	GetL1InfoTreeProof(ctx context.Context, in *GetL1InfoTreeProofRequest, opts ...grpc.CallOption) (*GetL1InfoTreeProofResponse, error)
+	/// Get the inputs of the claim of a deposit, formatted for the claim interface of a release of the bridge contract
+	GetClaimWitness(ctx context.Context, in *GetClaimWitnessRequest, opts ...grpc.CallOption) (*GetClaimWitnessResponse, error)
+	/// Decode a global index of the v2 claims into its mainnet flag, rollup index and local index
+	DecodeGlobalIndex(ctx context.Context, in *DecodeGlobalIndexRequest, opts ...grpc.CallOption) (*DecodeGlobalIndexResponse, error)
+	/// Get the contracts the service interacts with in each network, with their code hash and whether they are
+	/// official deployments
+	GetContracts(ctx context.Context, in *GetContractsRequest, opts ...grpc.CallOption) (*GetContractsResponse, error)
+	/// Get the replication lag and divergence of the read replicas of the database. The request of a replica fails
+	/// as unavailable if it's stale or diverged, so the load balancers route the traffic away from its region
+	GetReplication(ctx context.Context, in *GetReplicationRequest, opts ...grpc.CallOption) (*GetReplicationResponse, error)
+	// Admin
+	/// Get the claims parked until they are approved because their amount is above the approval threshold, they are uneconomical
+	/// or their message reverts
+	GetPendingClaimApprovals(ctx context.Context, in *GetPendingClaimApprovalsRequest, opts ...grpc.CallOption) (*GetPendingClaimApprovalsResponse, error)
+	/// Approve a parked claim so the claim tx manager sends it
+	ApproveClaim(ctx context.Context, in *ApproveClaimRequest, opts ...grpc.CallOption) (*ApproveClaimResponse, error)
+	/// Get the predefined read only queries the operators can run
+	GetAdminQueries(ctx context.Context, in *GetAdminQueriesRequest, opts ...grpc.CallOption) (*GetAdminQueriesResponse, error)
+	/// Run a predefined read only query over the deposits and claims, optionally exported as csv
+	RunAdminQuery(ctx context.Context, in *RunAdminQueryRequest, opts ...grpc.CallOption) (*RunAdminQueryResponse, error)
+	/// Get the indexes missing for the most expensive queries recorded by pg_stat_statements
+	GetIndexSuggestions(ctx context.Context, in *GetIndexSuggestionsRequest, opts ...grpc.CallOption) (*GetIndexSuggestionsResponse, error)
+	/// Submit the claim transactions signed offline, so the claim tx manager sends them
+	SubmitSignedClaims(ctx context.Context, in *SubmitSignedClaimsRequest, opts ...grpc.CallOption) (*SubmitSignedClaimsResponse, error)
+	/// Get the proof of reserve attestation signed by the operator: the locked and minted amounts of each token at a block of each network, with their deposits and claims
+	GetReserveAttestation(ctx context.Context, in *GetReserveAttestationRequest, opts ...grpc.CallOption) (*GetReserveAttestationResponse, error)
+	/// Submit a predefined read only query to run asynchronously, for the exports and the scans too long for a request
+	SubmitQueryJob(ctx context.Context, in *SubmitQueryJobRequest, opts ...grpc.CallOption) (*SubmitQueryJobResponse, error)
+	/// Get the status of a query job
+	GetQueryJob(ctx context.Context, in *GetQueryJobRequest, opts ...grpc.CallOption) (*GetQueryJobResponse, error)
+	/// Get the result of a done query job, optionally exported as csv
+	GetQueryJobResult(ctx context.Context, in *GetQueryJobResultRequest, opts ...grpc.CallOption) (*GetQueryJobResultResponse, error)
+	/// Get the state of the circuit breakers of the claim txs of the networks
+	GetClaimBreakers(ctx context.Context, in *GetClaimBreakersRequest, opts ...grpc.CallOption) (*GetClaimBreakersResponse, error)
+	/// Get if the proofs are restricted to the allowed consumers, and the allowed consumers
+	GetProofAccess(ctx context.Context, in *GetProofAccessRequest, opts ...grpc.CallOption) (*GetProofAccessResponse, error)
+	/// Restrict the proofs to the allowed consumers, or make them public again
+	SetProofAccess(ctx context.Context, in *SetProofAccessRequest, opts ...grpc.CallOption) (*SetProofAccessResponse, error)
+	/// Start a maintenance until its eta, pausing the writers, or end it
+	SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*SetMaintenanceResponse, error)
+	/// Allow a consumer to get the restricted proofs, returning its new key. The key of an existing consumer is rotated
+	AddProofConsumer(ctx context.Context, in *AddProofConsumerRequest, opts ...grpc.CallOption) (*AddProofConsumerResponse, error)
+	/// Remove a consumer of the restricted proofs
+	DeleteProofConsumer(ctx context.Context, in *DeleteProofConsumerRequest, opts ...grpc.CallOption) (*DeleteProofConsumerResponse, error)
+	/// Get the tokens in the allow and deny lists of the operators
+	GetTokenList(ctx context.Context, in *GetTokenListRequest, opts ...grpc.CallOption) (*GetTokenListResponse, error)
+	/// Add a token to the allow or the deny list, moving it from the other list
+	SetTokenListEntry(ctx context.Context, in *SetTokenListEntryRequest, opts ...grpc.CallOption) (*SetTokenListEntryResponse, error)
+	/// Remove a token from its list
+	DeleteTokenListEntry(ctx context.Context, in *DeleteTokenListEntryRequest, opts ...grpc.CallOption) (*DeleteTokenListEntryResponse, error)
+	/// Get the changes of the token lists, the last first
+	GetTokenListAudit(ctx context.Context, in *GetTokenListAuditRequest, opts ...grpc.CallOption) (*GetTokenListAuditResponse, error)
+	/// Get the operator keys signing the responses, with the times they signed them
+	GetSigningKeys(ctx context.Context, in *GetSigningKeysRequest, opts ...grpc.CallOption) (*GetSigningKeysResponse, error)
+	/// Retire the key signing the responses and activate another configured key
+	RotateSigningKey(ctx context.Context, in *RotateSigningKeyRequest, opts ...grpc.CallOption) (*RotateSigningKeyResponse, error)
+	/// Propose a change of the runtime settings, applied when another admin approves it
+	ProposeConfigChange(ctx context.Context, in *ProposeConfigChangeRequest, opts ...grpc.CallOption) (*ProposeConfigChangeResponse, error)
+	/// Approve a change proposed by another admin, applying it
+	ApproveConfigChange(ctx context.Context, in *ApproveConfigChangeRequest, opts ...grpc.CallOption) (*ApproveConfigChangeResponse, error)
+	/// Reject a proposed change, or withdraw it by its proposer
+	RejectConfigChange(ctx context.Context, in *RejectConfigChangeRequest, opts ...grpc.CallOption) (*RejectConfigChangeResponse, error)
+	/// Get the proposed changes of the runtime settings, the last first
+	GetConfigChanges(ctx context.Context, in *GetConfigChangesRequest, opts ...grpc.CallOption) (*GetConfigChangesResponse, error)
+	// Wallet sessions
+	/// Get the EIP-712 typed data signed by a wallet to create a session proving the ownership of its address
+	GetSessionChallenge(ctx context.Context, in *GetSessionChallengeRequest, opts ...grpc.CallOption) (*GetSessionChallengeResponse, error)
+	/// Create a session from the signature of the challenge, returning the token of the wallet endpoints
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error)
+	/// Get the deposits to the address of the session, with their claims, status history and annotations
+	GetWalletHistory(ctx context.Context, in *GetWalletHistoryRequest, opts ...grpc.CallOption) (*GetWalletHistoryResponse, error)
+	/// Set the note of the wallet of the session on one of its deposits, an empty note removes it
+	AnnotateDeposit(ctx context.Context, in *AnnotateDepositRequest, opts ...grpc.CallOption) (*AnnotateDepositResponse, error)
+	/// Set the external reference id of one of the deposits to the address of the session, an empty id removes it
+	SetDepositRef(ctx context.Context, in *SetDepositRefRequest, opts ...grpc.CallOption) (*SetDepositRefResponse, error)
+	/// Get the webhooks of the wallet of the session
+	GetWalletWebhooks(ctx context.Context, in *GetWalletWebhooksRequest, opts ...grpc.CallOption) (*GetWalletWebhooksResponse, error)
+	/// Add a webhook receiving the events of the deposits to the address of the session
+	AddWalletWebhook(ctx context.Context, in *AddWalletWebhookRequest, opts ...grpc.CallOption) (*AddWalletWebhookResponse, error)
+	/// Delete a webhook of the wallet of the session
+	DeleteWalletWebhook(ctx context.Context, in *DeleteWalletWebhookRequest, opts ...grpc.CallOption) (*DeleteWalletWebhookResponse, error)
+	/// Get the push notification devices of the wallet of the session
+	GetPushDevices(ctx context.Context, in *GetPushDevicesRequest, opts ...grpc.CallOption) (*GetPushDevicesResponse, error)
+	/// Register a device receiving the push notifications of the deposits to the address of the session
+	AddPushDevice(ctx context.Context, in *AddPushDeviceRequest, opts ...grpc.CallOption) (*AddPushDeviceResponse, error)
+	/// Delete a push notification device of the wallet of the session
+	DeletePushDevice(ctx context.Context, in *DeletePushDeviceRequest, opts ...grpc.CallOption) (*DeletePushDeviceResponse, error)
+	/// Get the addresses watched by the wallet of the session
+	GetWatchedAddresses(ctx context.Context, in *GetWatchedAddressesRequest, opts ...grpc.CallOption) (*GetWatchedAddressesResponse, error)
+	/// Watch an address, keeping its responses hot in the cache
+	AddWatchedAddress(ctx context.Context, in *AddWatchedAddressRequest, opts ...grpc.CallOption) (*AddWatchedAddressResponse, error)
+	/// Stop watching an address
+	DeleteWatchedAddress(ctx context.Context, in *DeleteWatchedAddressRequest, opts ...grpc.CallOption) (*DeleteWatchedAddressResponse, error)
+	/// Request the backfill of the history of a watched address
+	BackfillWatchedAddress(ctx context.Context, in *BackfillWatchedAddressRequest, opts ...grpc.CallOption) (*BackfillWatchedAddressResponse, error)
 }
 
 type bridgeServiceClient struct {
@@ -90,174 +218,2184 @@ func (c *bridgeServiceClient) GetClaims(ctx context.Context, in *GetClaimsReques
 	return out, nil
 }
 
+func (c *bridgeServiceClient) SubscribeBridgeEvents(ctx context.Context, in *SubscribeBridgeEventsRequest, opts ...grpc.CallOption) (BridgeService_SubscribeBridgeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BridgeService_ServiceDesc.Streams[0], "/bridge.v1.BridgeService/SubscribeBridgeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bridgeServiceSubscribeBridgeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BridgeService_SubscribeBridgeEventsClient interface {
+	Recv() (*BridgeEvent, error)
+	grpc.ClientStream
+}
+
+type bridgeServiceSubscribeBridgeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *bridgeServiceSubscribeBridgeEventsClient) Recv() (*BridgeEvent, error) {
+	m := new(BridgeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bridgeServiceClient) StreamDepositStatus(ctx context.Context, in *StreamDepositStatusRequest, opts ...grpc.CallOption) (BridgeService_StreamDepositStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BridgeService_ServiceDesc.Streams[1], "/bridge.v1.BridgeService/StreamDepositStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bridgeServiceStreamDepositStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BridgeService_StreamDepositStatusClient interface {
+	Recv() (*DepositStatusChange, error)
+	grpc.ClientStream
+}
+
+type bridgeServiceStreamDepositStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *bridgeServiceStreamDepositStatusClient) Recv() (*DepositStatusChange, error) {
+	m := new(DepositStatusChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *bridgeServiceClient) GetTokenWrapped(ctx context.Context, in *GetTokenWrappedRequest, opts ...grpc.CallOption) (*GetTokenWrappedResponse, error) {
 	out := new(GetTokenWrappedResponse)
 	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetTokenWrapped", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetTokenWrappedHistory(ctx context.Context, in *GetTokenWrappedHistoryRequest, opts ...grpc.CallOption) (*GetTokenWrappedHistoryResponse, error) {
+	out := new(GetTokenWrappedHistoryResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetTokenWrappedHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) ValidateClaim(ctx context.Context, in *ValidateClaimRequest, opts ...grpc.CallOption) (*ValidateClaimResponse, error) {
+	out := new(ValidateClaimResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/ValidateClaim", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetCCIPProof(ctx context.Context, in *GetCCIPProofRequest, opts ...grpc.CallOption) (*GetCCIPProofResponse, error) {
+	out := new(GetCCIPProofResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetCCIPProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetActivity(ctx context.Context, in *GetActivityRequest, opts ...grpc.CallOption) (*GetActivityResponse, error) {
+	out := new(GetActivityResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetActivity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetIntegrationStats(ctx context.Context, in *GetIntegrationStatsRequest, opts ...grpc.CallOption) (*GetIntegrationStatsResponse, error) {
+	out := new(GetIntegrationStatsResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetIntegrationStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetClaimTx(ctx context.Context, in *GetClaimTxRequest, opts ...grpc.CallOption) (*GetClaimTxResponse, error) {
+	out := new(GetClaimTxResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetClaimTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetClaimOutcome(ctx context.Context, in *GetClaimOutcomeRequest, opts ...grpc.CallOption) (*GetClaimOutcomeResponse, error) {
+	out := new(GetClaimOutcomeResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetClaimOutcome", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetDepositActivity(ctx context.Context, in *GetDepositActivityRequest, opts ...grpc.CallOption) (*GetDepositActivityResponse, error) {
+	out := new(GetDepositActivityResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetDepositActivity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetMaintenance(ctx context.Context, in *GetMaintenanceRequest, opts ...grpc.CallOption) (*GetMaintenanceResponse, error) {
+	out := new(GetMaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) SimulateClaims(ctx context.Context, in *SimulateClaimsRequest, opts ...grpc.CallOption) (*SimulateClaimsResponse, error) {
+	out := new(SimulateClaimsResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/SimulateClaims", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetGERInjectionLatency(ctx context.Context, in *GetGERInjectionLatencyRequest, opts ...grpc.CallOption) (*GetGERInjectionLatencyResponse, error) {
+	out := new(GetGERInjectionLatencyResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetGERInjectionLatency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetBridgesByTx(ctx context.Context, in *GetBridgesByTxRequest, opts ...grpc.CallOption) (*GetBridgesByTxResponse, error) {
+	out := new(GetBridgesByTxResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetBridgesByTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetL1InfoTreeProof(ctx context.Context, in *GetL1InfoTreeProofRequest, opts ...grpc.CallOption) (*GetL1InfoTreeProofResponse, error) {
+	out := new(GetL1InfoTreeProofResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetL1InfoTreeProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetClaimWitness(ctx context.Context, in *GetClaimWitnessRequest, opts ...grpc.CallOption) (*GetClaimWitnessResponse, error) {
+	out := new(GetClaimWitnessResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetClaimWitness", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) DecodeGlobalIndex(ctx context.Context, in *DecodeGlobalIndexRequest, opts ...grpc.CallOption) (*DecodeGlobalIndexResponse, error) {
+	out := new(DecodeGlobalIndexResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/DecodeGlobalIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetContracts(ctx context.Context, in *GetContractsRequest, opts ...grpc.CallOption) (*GetContractsResponse, error) {
+	out := new(GetContractsResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetContracts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetReplication(ctx context.Context, in *GetReplicationRequest, opts ...grpc.CallOption) (*GetReplicationResponse, error) {
+	out := new(GetReplicationResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetReplication", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetPendingClaimApprovals(ctx context.Context, in *GetPendingClaimApprovalsRequest, opts ...grpc.CallOption) (*GetPendingClaimApprovalsResponse, error) {
+	out := new(GetPendingClaimApprovalsResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetPendingClaimApprovals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) ApproveClaim(ctx context.Context, in *ApproveClaimRequest, opts ...grpc.CallOption) (*ApproveClaimResponse, error) {
+	out := new(ApproveClaimResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/ApproveClaim", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetAdminQueries(ctx context.Context, in *GetAdminQueriesRequest, opts ...grpc.CallOption) (*GetAdminQueriesResponse, error) {
+	out := new(GetAdminQueriesResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetAdminQueries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) RunAdminQuery(ctx context.Context, in *RunAdminQueryRequest, opts ...grpc.CallOption) (*RunAdminQueryResponse, error) {
+	out := new(RunAdminQueryResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/RunAdminQuery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetIndexSuggestions(ctx context.Context, in *GetIndexSuggestionsRequest, opts ...grpc.CallOption) (*GetIndexSuggestionsResponse, error) {
+	out := new(GetIndexSuggestionsResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetIndexSuggestions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) SubmitSignedClaims(ctx context.Context, in *SubmitSignedClaimsRequest, opts ...grpc.CallOption) (*SubmitSignedClaimsResponse, error) {
+	out := new(SubmitSignedClaimsResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/SubmitSignedClaims", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetReserveAttestation(ctx context.Context, in *GetReserveAttestationRequest, opts ...grpc.CallOption) (*GetReserveAttestationResponse, error) {
+	out := new(GetReserveAttestationResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetReserveAttestation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) SubmitQueryJob(ctx context.Context, in *SubmitQueryJobRequest, opts ...grpc.CallOption) (*SubmitQueryJobResponse, error) {
+	out := new(SubmitQueryJobResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/SubmitQueryJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetQueryJob(ctx context.Context, in *GetQueryJobRequest, opts ...grpc.CallOption) (*GetQueryJobResponse, error) {
+	out := new(GetQueryJobResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetQueryJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetQueryJobResult(ctx context.Context, in *GetQueryJobResultRequest, opts ...grpc.CallOption) (*GetQueryJobResultResponse, error) {
+	out := new(GetQueryJobResultResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetQueryJobResult", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetClaimBreakers(ctx context.Context, in *GetClaimBreakersRequest, opts ...grpc.CallOption) (*GetClaimBreakersResponse, error) {
+	out := new(GetClaimBreakersResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetClaimBreakers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetProofAccess(ctx context.Context, in *GetProofAccessRequest, opts ...grpc.CallOption) (*GetProofAccessResponse, error) {
+	out := new(GetProofAccessResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetProofAccess", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) SetProofAccess(ctx context.Context, in *SetProofAccessRequest, opts ...grpc.CallOption) (*SetProofAccessResponse, error) {
+	out := new(SetProofAccessResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/SetProofAccess", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*SetMaintenanceResponse, error) {
+	out := new(SetMaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/SetMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) AddProofConsumer(ctx context.Context, in *AddProofConsumerRequest, opts ...grpc.CallOption) (*AddProofConsumerResponse, error) {
+	out := new(AddProofConsumerResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/AddProofConsumer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) DeleteProofConsumer(ctx context.Context, in *DeleteProofConsumerRequest, opts ...grpc.CallOption) (*DeleteProofConsumerResponse, error) {
+	out := new(DeleteProofConsumerResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/DeleteProofConsumer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetTokenList(ctx context.Context, in *GetTokenListRequest, opts ...grpc.CallOption) (*GetTokenListResponse, error) {
+	out := new(GetTokenListResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetTokenList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) SetTokenListEntry(ctx context.Context, in *SetTokenListEntryRequest, opts ...grpc.CallOption) (*SetTokenListEntryResponse, error) {
+	out := new(SetTokenListEntryResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/SetTokenListEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) DeleteTokenListEntry(ctx context.Context, in *DeleteTokenListEntryRequest, opts ...grpc.CallOption) (*DeleteTokenListEntryResponse, error) {
+	out := new(DeleteTokenListEntryResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/DeleteTokenListEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetTokenListAudit(ctx context.Context, in *GetTokenListAuditRequest, opts ...grpc.CallOption) (*GetTokenListAuditResponse, error) {
+	out := new(GetTokenListAuditResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetTokenListAudit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetSigningKeys(ctx context.Context, in *GetSigningKeysRequest, opts ...grpc.CallOption) (*GetSigningKeysResponse, error) {
+	out := new(GetSigningKeysResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetSigningKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) RotateSigningKey(ctx context.Context, in *RotateSigningKeyRequest, opts ...grpc.CallOption) (*RotateSigningKeyResponse, error) {
+	out := new(RotateSigningKeyResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/RotateSigningKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) ProposeConfigChange(ctx context.Context, in *ProposeConfigChangeRequest, opts ...grpc.CallOption) (*ProposeConfigChangeResponse, error) {
+	out := new(ProposeConfigChangeResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/ProposeConfigChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) ApproveConfigChange(ctx context.Context, in *ApproveConfigChangeRequest, opts ...grpc.CallOption) (*ApproveConfigChangeResponse, error) {
+	out := new(ApproveConfigChangeResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/ApproveConfigChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) RejectConfigChange(ctx context.Context, in *RejectConfigChangeRequest, opts ...grpc.CallOption) (*RejectConfigChangeResponse, error) {
+	out := new(RejectConfigChangeResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/RejectConfigChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetConfigChanges(ctx context.Context, in *GetConfigChangesRequest, opts ...grpc.CallOption) (*GetConfigChangesResponse, error) {
+	out := new(GetConfigChangesResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetConfigChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetSessionChallenge(ctx context.Context, in *GetSessionChallengeRequest, opts ...grpc.CallOption) (*GetSessionChallengeResponse, error) {
+	out := new(GetSessionChallengeResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetSessionChallenge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error) {
+	out := new(CreateSessionResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/CreateSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetWalletHistory(ctx context.Context, in *GetWalletHistoryRequest, opts ...grpc.CallOption) (*GetWalletHistoryResponse, error) {
+	out := new(GetWalletHistoryResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetWalletHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) AnnotateDeposit(ctx context.Context, in *AnnotateDepositRequest, opts ...grpc.CallOption) (*AnnotateDepositResponse, error) {
+	out := new(AnnotateDepositResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/AnnotateDeposit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) SetDepositRef(ctx context.Context, in *SetDepositRefRequest, opts ...grpc.CallOption) (*SetDepositRefResponse, error) {
+	out := new(SetDepositRefResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/SetDepositRef", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetWalletWebhooks(ctx context.Context, in *GetWalletWebhooksRequest, opts ...grpc.CallOption) (*GetWalletWebhooksResponse, error) {
+	out := new(GetWalletWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetWalletWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) AddWalletWebhook(ctx context.Context, in *AddWalletWebhookRequest, opts ...grpc.CallOption) (*AddWalletWebhookResponse, error) {
+	out := new(AddWalletWebhookResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/AddWalletWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) DeleteWalletWebhook(ctx context.Context, in *DeleteWalletWebhookRequest, opts ...grpc.CallOption) (*DeleteWalletWebhookResponse, error) {
+	out := new(DeleteWalletWebhookResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/DeleteWalletWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetPushDevices(ctx context.Context, in *GetPushDevicesRequest, opts ...grpc.CallOption) (*GetPushDevicesResponse, error) {
+	out := new(GetPushDevicesResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetPushDevices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) AddPushDevice(ctx context.Context, in *AddPushDeviceRequest, opts ...grpc.CallOption) (*AddPushDeviceResponse, error) {
+	out := new(AddPushDeviceResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/AddPushDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) DeletePushDevice(ctx context.Context, in *DeletePushDeviceRequest, opts ...grpc.CallOption) (*DeletePushDeviceResponse, error) {
+	out := new(DeletePushDeviceResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/DeletePushDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) GetWatchedAddresses(ctx context.Context, in *GetWatchedAddressesRequest, opts ...grpc.CallOption) (*GetWatchedAddressesResponse, error) {
+	out := new(GetWatchedAddressesResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/GetWatchedAddresses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) AddWatchedAddress(ctx context.Context, in *AddWatchedAddressRequest, opts ...grpc.CallOption) (*AddWatchedAddressResponse, error) {
+	out := new(AddWatchedAddressResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/AddWatchedAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) DeleteWatchedAddress(ctx context.Context, in *DeleteWatchedAddressRequest, opts ...grpc.CallOption) (*DeleteWatchedAddressResponse, error) {
+	out := new(DeleteWatchedAddressResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/DeleteWatchedAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bridgeServiceClient) BackfillWatchedAddress(ctx context.Context, in *BackfillWatchedAddressRequest, opts ...grpc.CallOption) (*BackfillWatchedAddressResponse, error) {
+	out := new(BackfillWatchedAddressResponse)
+	err := c.cc.Invoke(ctx, "/bridge.v1.BridgeService/BackfillWatchedAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BridgeServiceServer is the server API for BridgeService service.
+// All implementations must embed UnimplementedBridgeServiceServer
+// for forward compatibility
+type BridgeServiceServer interface {
+	// Getters
+	/// Get api version
+	CheckAPI(context.Context, *CheckAPIRequest) (*CheckAPIResponse, error)
+	/// Get bridges for the destination address both in L1 and L2
+	GetBridges(context.Context, *GetBridgesRequest) (*GetBridgesResponse, error)
+	/// Get the merkle proof for the specific deposit
+	GetProof(context.Context, *GetProofRequest) (*GetProofResponse, error)
+	/// Get the specific deposit
+	GetBridge(context.Context, *GetBridgeRequest) (*GetBridgeResponse, error)
+	/// Get claims for the specific smart contract address both in L1 and L2
+	GetClaims(context.Context, *GetClaimsRequest) (*GetClaimsResponse, error)
+	/// Stream the new deposits, the deposits ready for claim and the claims of an address as they are synced
+	SubscribeBridgeEvents(*SubscribeBridgeEventsRequest, BridgeService_SubscribeBridgeEventsServer) error
+	/// Stream the changes of status of a deposit, the past ones first, and the batch states of the L2 deposits, until it's claimed
+	StreamDepositStatus(*StreamDepositStatusRequest, BridgeService_StreamDepositStatusServer) error
+	/// Get token wrapped for the specific smart contract address both in L1 and L2
+	GetTokenWrapped(context.Context, *GetTokenWrappedRequest) (*GetTokenWrappedResponse, error)
+	/// Get all the mappings of the wrapped tokens of an original token, with their validity ranges
+	GetTokenWrappedHistory(context.Context, *GetTokenWrappedHistoryRequest) (*GetTokenWrappedHistoryResponse, error)
+	/// Simulate a claim in the destination network to check if it would succeed
+	ValidateClaim(context.Context, *ValidateClaimRequest) (*ValidateClaimResponse, error)
+	/// Resolve an EIP-3668 (CCIP-Read) offchain lookup of a claim proof
+	GetCCIPProof(context.Context, *GetCCIPProofRequest) (*GetCCIPProofResponse, error)
+	/// Get the deposits and claims counts and volumes of a network aggregated by hour or day
+	GetActivity(context.Context, *GetActivityRequest) (*GetActivityResponse, error)
+	/// Get the deposits counts and volumes of a network by the integration they come from
+	GetIntegrationStats(context.Context, *GetIntegrationStatsRequest) (*GetIntegrationStatsResponse, error)
+	/// Get the unsigned claim transaction of a deposit, to be signed and sent by the claimer
+	GetClaimTx(context.Context, *GetClaimTxRequest) (*GetClaimTxResponse, error)
+	/// Get the outcome of the mined claim of a deposit sent by the claim tx manager, decoded from its receipt
+	GetClaimOutcome(context.Context, *GetClaimOutcomeRequest) (*GetClaimOutcomeResponse, error)
+	/// Get the activity log of a deposit: its changes of status, the attempts of its claim tx and the log lines
+	/// about it, in order
+	GetDepositActivity(context.Context, *GetDepositActivityRequest) (*GetDepositActivityResponse, error)
+	/// Get the maintenance in progress, with the notice for the frontends. The writes are rejected during it
+	GetMaintenance(context.Context, *GetMaintenanceRequest) (*GetMaintenanceResponse, error)
+	/// Simulate the claims of the deposits ready for claim, with the estimated gas of each claim and their total cost
+	SimulateClaims(context.Context, *SimulateClaimsRequest) (*SimulateClaimsResponse, error)
+	/// Get the observed delays between the global exit root updates in L1 and their injection in the destination networks
+	GetGERInjectionLatency(context.Context, *GetGERInjectionLatencyRequest) (*GetGERInjectionLatencyResponse, error)
+	/// Get the deposits of a tx, with the originator and the call path of the tx when the deposits are traced
+	GetBridgesByTx(context.Context, *GetBridgesByTxRequest) (*GetBridgesByTxResponse, error)
+	/// Search the deposits and the claims whose destination address or tx hash start with a hex prefix
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	/// Get the merkle proof of a global exit root in the L1 info tree, against the latest L1 info root
+	GetL1InfoTreeProof(context.Context, *GetL1InfoTreeProofRequest) (*GetL1InfoTreeProofResponse, error)
+	/// Get the inputs of the claim of a deposit, formatted for the claim interface of a release of the bridge contract
+	GetClaimWitness(context.Context, *GetClaimWitnessRequest) (*GetClaimWitnessResponse, error)
+	/// Decode a global index of the v2 claims into its mainnet flag, rollup index and local index
+	DecodeGlobalIndex(context.Context, *DecodeGlobalIndexRequest) (*DecodeGlobalIndexResponse, error)
+	/// Get the contracts the service interacts with in each network, with their code hash and whether they are
+	/// official deployments
+	GetContracts(context.Context, *GetContractsRequest) (*GetContractsResponse, error)
+	/// Get the replication lag and divergence of the read replicas of the database. The request of a replica fails
+	/// as unavailable if it's stale or diverged, so the load balancers route the traffic away from its region
+	GetReplication(context.Context, *GetReplicationRequest) (*GetReplicationResponse, error)
+	// Admin
+	/// Get the claims parked until they are approved because their amount is above the approval threshold, they are uneconomical
+	/// or their message reverts
+	GetPendingClaimApprovals(context.Context, *GetPendingClaimApprovalsRequest) (*GetPendingClaimApprovalsResponse, error)
+	/// Approve a parked claim so the claim tx manager sends it
+	ApproveClaim(context.Context, *ApproveClaimRequest) (*ApproveClaimResponse, error)
+	/// Get the predefined read only queries the operators can run
+	GetAdminQueries(context.Context, *GetAdminQueriesRequest) (*GetAdminQueriesResponse, error)
+	/// Run a predefined read only query over the deposits and claims, optionally exported as csv
+	RunAdminQuery(context.Context, *RunAdminQueryRequest) (*RunAdminQueryResponse, error)
+	/// Get the indexes missing for the most expensive queries recorded by pg_stat_statements
+	GetIndexSuggestions(context.Context, *GetIndexSuggestionsRequest) (*GetIndexSuggestionsResponse, error)
+	/// Submit the claim transactions signed offline, so the claim tx manager sends them
+	SubmitSignedClaims(context.Context, *SubmitSignedClaimsRequest) (*SubmitSignedClaimsResponse, error)
+	/// Get the proof of reserve attestation signed by the operator: the locked and minted amounts of each token at a block of each network, with their deposits and claims
+	GetReserveAttestation(context.Context, *GetReserveAttestationRequest) (*GetReserveAttestationResponse, error)
+	/// Submit a predefined read only query to run asynchronously, for the exports and the scans too long for a request
+	SubmitQueryJob(context.Context, *SubmitQueryJobRequest) (*SubmitQueryJobResponse, error)
+	/// Get the status of a query job
+	GetQueryJob(context.Context, *GetQueryJobRequest) (*GetQueryJobResponse, error)
+	/// Get the result of a done query job, optionally exported as csv
+	GetQueryJobResult(context.Context, *GetQueryJobResultRequest) (*GetQueryJobResultResponse, error)
+	/// Get the state of the circuit breakers of the claim txs of the networks
+	GetClaimBreakers(context.Context, *GetClaimBreakersRequest) (*GetClaimBreakersResponse, error)
+	/// Get if the proofs are restricted to the allowed consumers, and the allowed consumers
+	GetProofAccess(context.Context, *GetProofAccessRequest) (*GetProofAccessResponse, error)
+	/// Restrict the proofs to the allowed consumers, or make them public again
+	SetProofAccess(context.Context, *SetProofAccessRequest) (*SetProofAccessResponse, error)
+	/// Start a maintenance until its eta, pausing the writers, or end it
+	SetMaintenance(context.Context, *SetMaintenanceRequest) (*SetMaintenanceResponse, error)
+	/// Allow a consumer to get the restricted proofs, returning its new key. The key of an existing consumer is rotated
+	AddProofConsumer(context.Context, *AddProofConsumerRequest) (*AddProofConsumerResponse, error)
+	/// Remove a consumer of the restricted proofs
+	DeleteProofConsumer(context.Context, *DeleteProofConsumerRequest) (*DeleteProofConsumerResponse, error)
+	/// Get the tokens in the allow and deny lists of the operators
+	GetTokenList(context.Context, *GetTokenListRequest) (*GetTokenListResponse, error)
+	/// Add a token to the allow or the deny list, moving it from the other list
+	SetTokenListEntry(context.Context, *SetTokenListEntryRequest) (*SetTokenListEntryResponse, error)
+	/// Remove a token from its list
+	DeleteTokenListEntry(context.Context, *DeleteTokenListEntryRequest) (*DeleteTokenListEntryResponse, error)
+	/// Get the changes of the token lists, the last first
+	GetTokenListAudit(context.Context, *GetTokenListAuditRequest) (*GetTokenListAuditResponse, error)
+	/// Get the operator keys signing the responses, with the times they signed them
+	GetSigningKeys(context.Context, *GetSigningKeysRequest) (*GetSigningKeysResponse, error)
+	/// Retire the key signing the responses and activate another configured key
+	RotateSigningKey(context.Context, *RotateSigningKeyRequest) (*RotateSigningKeyResponse, error)
+	/// Propose a change of the runtime settings, applied when another admin approves it
+	ProposeConfigChange(context.Context, *ProposeConfigChangeRequest) (*ProposeConfigChangeResponse, error)
+	/// Approve a change proposed by another admin, applying it
+	ApproveConfigChange(context.Context, *ApproveConfigChangeRequest) (*ApproveConfigChangeResponse, error)
+	/// Reject a proposed change, or withdraw it by its proposer
+	RejectConfigChange(context.Context, *RejectConfigChangeRequest) (*RejectConfigChangeResponse, error)
+	/// Get the proposed changes of the runtime settings, the last first
+	GetConfigChanges(context.Context, *GetConfigChangesRequest) (*GetConfigChangesResponse, error)
+	// Wallet sessions
+	/// Get the EIP-712 typed data signed by a wallet to create a session proving the ownership of its address
+	GetSessionChallenge(context.Context, *GetSessionChallengeRequest) (*GetSessionChallengeResponse, error)
+	/// Create a session from the signature of the challenge, returning the token of the wallet endpoints
+	CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error)
+	/// Get the deposits to the address of the session, with their claims, status history and annotations
+	GetWalletHistory(context.Context, *GetWalletHistoryRequest) (*GetWalletHistoryResponse, error)
+	/// Set the note of the wallet of the session on one of its deposits, an empty note removes it
+	AnnotateDeposit(context.Context, *AnnotateDepositRequest) (*AnnotateDepositResponse, error)
+	/// Set the external reference id of one of the deposits to the address of the session, an empty id removes it
+	SetDepositRef(context.Context, *SetDepositRefRequest) (*SetDepositRefResponse, error)
+	/// Get the webhooks of the wallet of the session
+	GetWalletWebhooks(context.Context, *GetWalletWebhooksRequest) (*GetWalletWebhooksResponse, error)
+	/// Add a webhook receiving the events of the deposits to the address of the session
+	AddWalletWebhook(context.Context, *AddWalletWebhookRequest) (*AddWalletWebhookResponse, error)
+	/// Delete a webhook of the wallet of the session
+	DeleteWalletWebhook(context.Context, *DeleteWalletWebhookRequest) (*DeleteWalletWebhookResponse, error)
+	/// Get the push notification devices of the wallet of the session
+	GetPushDevices(context.Context, *GetPushDevicesRequest) (*GetPushDevicesResponse, error)
+	/// Register a device receiving the push notifications of the deposits to the address of the session
+	AddPushDevice(context.Context, *AddPushDeviceRequest) (*AddPushDeviceResponse, error)
+	/// Delete a push notification device of the wallet of the session
+	DeletePushDevice(context.Context, *DeletePushDeviceRequest) (*DeletePushDeviceResponse, error)
+	/// Get the addresses watched by the wallet of the session
+	GetWatchedAddresses(context.Context, *GetWatchedAddressesRequest) (*GetWatchedAddressesResponse, error)
+	/// Watch an address, keeping its responses hot in the cache
+	AddWatchedAddress(context.Context, *AddWatchedAddressRequest) (*AddWatchedAddressResponse, error)
+	/// Stop watching an address
+	DeleteWatchedAddress(context.Context, *DeleteWatchedAddressRequest) (*DeleteWatchedAddressResponse, error)
+	/// Request the backfill of the history of a watched address
+	BackfillWatchedAddress(context.Context, *BackfillWatchedAddressRequest) (*BackfillWatchedAddressResponse, error)
+	mustEmbedUnimplementedBridgeServiceServer()
+}
+
+// UnimplementedBridgeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBridgeServiceServer struct {
+}
+
+func (UnimplementedBridgeServiceServer) CheckAPI(context.Context, *CheckAPIRequest) (*CheckAPIResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckAPI not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetBridges(context.Context, *GetBridgesRequest) (*GetBridgesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBridges not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetProof(context.Context, *GetProofRequest) (*GetProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProof not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetBridge(context.Context, *GetBridgeRequest) (*GetBridgeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBridge not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetClaims(context.Context, *GetClaimsRequest) (*GetClaimsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClaims not implemented")
+}
+func (UnimplementedBridgeServiceServer) SubscribeBridgeEvents(*SubscribeBridgeEventsRequest, BridgeService_SubscribeBridgeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBridgeEvents not implemented")
+}
+func (UnimplementedBridgeServiceServer) StreamDepositStatus(*StreamDepositStatusRequest, BridgeService_StreamDepositStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDepositStatus not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetTokenWrapped(context.Context, *GetTokenWrappedRequest) (*GetTokenWrappedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTokenWrapped not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetTokenWrappedHistory(context.Context, *GetTokenWrappedHistoryRequest) (*GetTokenWrappedHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTokenWrappedHistory not implemented")
+}
+func (UnimplementedBridgeServiceServer) ValidateClaim(context.Context, *ValidateClaimRequest) (*ValidateClaimResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateClaim not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetCCIPProof(context.Context, *GetCCIPProofRequest) (*GetCCIPProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCCIPProof not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetActivity(context.Context, *GetActivityRequest) (*GetActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActivity not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetIntegrationStats(context.Context, *GetIntegrationStatsRequest) (*GetIntegrationStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIntegrationStats not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetClaimTx(context.Context, *GetClaimTxRequest) (*GetClaimTxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClaimTx not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetClaimOutcome(context.Context, *GetClaimOutcomeRequest) (*GetClaimOutcomeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClaimOutcome not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetDepositActivity(context.Context, *GetDepositActivityRequest) (*GetDepositActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDepositActivity not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetMaintenance(context.Context, *GetMaintenanceRequest) (*GetMaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMaintenance not implemented")
+}
+func (UnimplementedBridgeServiceServer) SimulateClaims(context.Context, *SimulateClaimsRequest) (*SimulateClaimsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateClaims not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetGERInjectionLatency(context.Context, *GetGERInjectionLatencyRequest) (*GetGERInjectionLatencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGERInjectionLatency not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetBridgesByTx(context.Context, *GetBridgesByTxRequest) (*GetBridgesByTxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBridgesByTx not implemented")
+}
+func (UnimplementedBridgeServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetL1InfoTreeProof(context.Context, *GetL1InfoTreeProofRequest) (*GetL1InfoTreeProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetL1InfoTreeProof not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetClaimWitness(context.Context, *GetClaimWitnessRequest) (*GetClaimWitnessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClaimWitness not implemented")
+}
+func (UnimplementedBridgeServiceServer) DecodeGlobalIndex(context.Context, *DecodeGlobalIndexRequest) (*DecodeGlobalIndexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecodeGlobalIndex not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetContracts(context.Context, *GetContractsRequest) (*GetContractsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetContracts not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetReplication(context.Context, *GetReplicationRequest) (*GetReplicationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReplication not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetPendingClaimApprovals(context.Context, *GetPendingClaimApprovalsRequest) (*GetPendingClaimApprovalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPendingClaimApprovals not implemented")
+}
+func (UnimplementedBridgeServiceServer) ApproveClaim(context.Context, *ApproveClaimRequest) (*ApproveClaimResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveClaim not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetAdminQueries(context.Context, *GetAdminQueriesRequest) (*GetAdminQueriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAdminQueries not implemented")
+}
+func (UnimplementedBridgeServiceServer) RunAdminQuery(context.Context, *RunAdminQueryRequest) (*RunAdminQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunAdminQuery not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetIndexSuggestions(context.Context, *GetIndexSuggestionsRequest) (*GetIndexSuggestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIndexSuggestions not implemented")
+}
+func (UnimplementedBridgeServiceServer) SubmitSignedClaims(context.Context, *SubmitSignedClaimsRequest) (*SubmitSignedClaimsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitSignedClaims not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetReserveAttestation(context.Context, *GetReserveAttestationRequest) (*GetReserveAttestationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReserveAttestation not implemented")
+}
+func (UnimplementedBridgeServiceServer) SubmitQueryJob(context.Context, *SubmitQueryJobRequest) (*SubmitQueryJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitQueryJob not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetQueryJob(context.Context, *GetQueryJobRequest) (*GetQueryJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQueryJob not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetQueryJobResult(context.Context, *GetQueryJobResultRequest) (*GetQueryJobResultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQueryJobResult not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetClaimBreakers(context.Context, *GetClaimBreakersRequest) (*GetClaimBreakersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClaimBreakers not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetProofAccess(context.Context, *GetProofAccessRequest) (*GetProofAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProofAccess not implemented")
+}
+func (UnimplementedBridgeServiceServer) SetProofAccess(context.Context, *SetProofAccessRequest) (*SetProofAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProofAccess not implemented")
+}
+func (UnimplementedBridgeServiceServer) SetMaintenance(context.Context, *SetMaintenanceRequest) (*SetMaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaintenance not implemented")
+}
+func (UnimplementedBridgeServiceServer) AddProofConsumer(context.Context, *AddProofConsumerRequest) (*AddProofConsumerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProofConsumer not implemented")
+}
+func (UnimplementedBridgeServiceServer) DeleteProofConsumer(context.Context, *DeleteProofConsumerRequest) (*DeleteProofConsumerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProofConsumer not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetTokenList(context.Context, *GetTokenListRequest) (*GetTokenListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTokenList not implemented")
+}
+func (UnimplementedBridgeServiceServer) SetTokenListEntry(context.Context, *SetTokenListEntryRequest) (*SetTokenListEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTokenListEntry not implemented")
+}
+func (UnimplementedBridgeServiceServer) DeleteTokenListEntry(context.Context, *DeleteTokenListEntryRequest) (*DeleteTokenListEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTokenListEntry not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetTokenListAudit(context.Context, *GetTokenListAuditRequest) (*GetTokenListAuditResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTokenListAudit not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetSigningKeys(context.Context, *GetSigningKeysRequest) (*GetSigningKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSigningKeys not implemented")
+}
+func (UnimplementedBridgeServiceServer) RotateSigningKey(context.Context, *RotateSigningKeyRequest) (*RotateSigningKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateSigningKey not implemented")
+}
+func (UnimplementedBridgeServiceServer) ProposeConfigChange(context.Context, *ProposeConfigChangeRequest) (*ProposeConfigChangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProposeConfigChange not implemented")
+}
+func (UnimplementedBridgeServiceServer) ApproveConfigChange(context.Context, *ApproveConfigChangeRequest) (*ApproveConfigChangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveConfigChange not implemented")
+}
+func (UnimplementedBridgeServiceServer) RejectConfigChange(context.Context, *RejectConfigChangeRequest) (*RejectConfigChangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectConfigChange not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetConfigChanges(context.Context, *GetConfigChangesRequest) (*GetConfigChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigChanges not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetSessionChallenge(context.Context, *GetSessionChallengeRequest) (*GetSessionChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessionChallenge not implemented")
+}
+func (UnimplementedBridgeServiceServer) CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetWalletHistory(context.Context, *GetWalletHistoryRequest) (*GetWalletHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWalletHistory not implemented")
+}
+func (UnimplementedBridgeServiceServer) AnnotateDeposit(context.Context, *AnnotateDepositRequest) (*AnnotateDepositResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnnotateDeposit not implemented")
+}
+func (UnimplementedBridgeServiceServer) SetDepositRef(context.Context, *SetDepositRefRequest) (*SetDepositRefResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDepositRef not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetWalletWebhooks(context.Context, *GetWalletWebhooksRequest) (*GetWalletWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWalletWebhooks not implemented")
+}
+func (UnimplementedBridgeServiceServer) AddWalletWebhook(context.Context, *AddWalletWebhookRequest) (*AddWalletWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWalletWebhook not implemented")
+}
+func (UnimplementedBridgeServiceServer) DeleteWalletWebhook(context.Context, *DeleteWalletWebhookRequest) (*DeleteWalletWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWalletWebhook not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetPushDevices(context.Context, *GetPushDevicesRequest) (*GetPushDevicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPushDevices not implemented")
+}
+func (UnimplementedBridgeServiceServer) AddPushDevice(context.Context, *AddPushDeviceRequest) (*AddPushDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPushDevice not implemented")
+}
+func (UnimplementedBridgeServiceServer) DeletePushDevice(context.Context, *DeletePushDeviceRequest) (*DeletePushDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePushDevice not implemented")
+}
+func (UnimplementedBridgeServiceServer) GetWatchedAddresses(context.Context, *GetWatchedAddressesRequest) (*GetWatchedAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWatchedAddresses not implemented")
+}
+func (UnimplementedBridgeServiceServer) AddWatchedAddress(context.Context, *AddWatchedAddressRequest) (*AddWatchedAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWatchedAddress not implemented")
+}
+func (UnimplementedBridgeServiceServer) DeleteWatchedAddress(context.Context, *DeleteWatchedAddressRequest) (*DeleteWatchedAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWatchedAddress not implemented")
+}
+func (UnimplementedBridgeServiceServer) BackfillWatchedAddress(context.Context, *BackfillWatchedAddressRequest) (*BackfillWatchedAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackfillWatchedAddress not implemented")
+}
+func (UnimplementedBridgeServiceServer) mustEmbedUnimplementedBridgeServiceServer() {}
+
+// UnsafeBridgeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BridgeServiceServer will
+// result in compilation errors.
+type UnsafeBridgeServiceServer interface {
+	mustEmbedUnimplementedBridgeServiceServer()
+}
+
+func RegisterBridgeServiceServer(s grpc.ServiceRegistrar, srv BridgeServiceServer) {
+	s.RegisterService(&BridgeService_ServiceDesc, srv)
+}
+
+func _BridgeService_CheckAPI_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckAPIRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).CheckAPI(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/CheckAPI",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).CheckAPI(ctx, req.(*CheckAPIRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetBridges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBridgesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetBridges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetBridges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetBridges(ctx, req.(*GetBridgesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetProof(ctx, req.(*GetProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetBridge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBridgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetBridge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetBridge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetBridge(ctx, req.(*GetBridgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetClaims_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClaimsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetClaims(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetClaims",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetClaims(ctx, req.(*GetClaimsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_SubscribeBridgeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBridgeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BridgeServiceServer).SubscribeBridgeEvents(m, &bridgeServiceSubscribeBridgeEventsServer{stream})
+}
+
+type BridgeService_SubscribeBridgeEventsServer interface {
+	Send(*BridgeEvent) error
+	grpc.ServerStream
+}
+
+type bridgeServiceSubscribeBridgeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *bridgeServiceSubscribeBridgeEventsServer) Send(m *BridgeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BridgeService_StreamDepositStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamDepositStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BridgeServiceServer).StreamDepositStatus(m, &bridgeServiceStreamDepositStatusServer{stream})
+}
+
+type BridgeService_StreamDepositStatusServer interface {
+	Send(*DepositStatusChange) error
+	grpc.ServerStream
+}
+
+type bridgeServiceStreamDepositStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *bridgeServiceStreamDepositStatusServer) Send(m *DepositStatusChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BridgeService_GetTokenWrapped_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenWrappedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetTokenWrapped(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetTokenWrapped",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetTokenWrapped(ctx, req.(*GetTokenWrappedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetTokenWrappedHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenWrappedHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetTokenWrappedHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetTokenWrappedHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetTokenWrappedHistory(ctx, req.(*GetTokenWrappedHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_ValidateClaim_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateClaimRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).ValidateClaim(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/ValidateClaim",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).ValidateClaim(ctx, req.(*ValidateClaimRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetCCIPProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCCIPProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetCCIPProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetCCIPProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetCCIPProof(ctx, req.(*GetCCIPProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetActivity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetActivity(ctx, req.(*GetActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetIntegrationStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIntegrationStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetIntegrationStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetIntegrationStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetIntegrationStats(ctx, req.(*GetIntegrationStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetClaimTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClaimTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetClaimTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetClaimTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetClaimTx(ctx, req.(*GetClaimTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetClaimOutcome_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClaimOutcomeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetClaimOutcome(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetClaimOutcome",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetClaimOutcome(ctx, req.(*GetClaimOutcomeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetDepositActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDepositActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetDepositActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetDepositActivity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetDepositActivity(ctx, req.(*GetDepositActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetMaintenance(ctx, req.(*GetMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_SimulateClaims_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateClaimsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).SimulateClaims(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/SimulateClaims",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).SimulateClaims(ctx, req.(*SimulateClaimsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetGERInjectionLatency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGERInjectionLatencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetGERInjectionLatency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetGERInjectionLatency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetGERInjectionLatency(ctx, req.(*GetGERInjectionLatencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetBridgesByTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBridgesByTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetBridgesByTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetBridgesByTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetBridgesByTx(ctx, req.(*GetBridgesByTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetL1InfoTreeProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetL1InfoTreeProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetL1InfoTreeProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetL1InfoTreeProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetL1InfoTreeProof(ctx, req.(*GetL1InfoTreeProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetClaimWitness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClaimWitnessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetClaimWitness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetClaimWitness",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetClaimWitness(ctx, req.(*GetClaimWitnessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_DecodeGlobalIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeGlobalIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).DecodeGlobalIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/DecodeGlobalIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).DecodeGlobalIndex(ctx, req.(*DecodeGlobalIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetContracts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetContractsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetContracts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetContracts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetContracts(ctx, req.(*GetContractsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetReplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetReplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetReplication",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetReplication(ctx, req.(*GetReplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetPendingClaimApprovals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPendingClaimApprovalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetPendingClaimApprovals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetPendingClaimApprovals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetPendingClaimApprovals(ctx, req.(*GetPendingClaimApprovalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_ApproveClaim_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveClaimRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).ApproveClaim(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/ApproveClaim",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).ApproveClaim(ctx, req.(*ApproveClaimRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetAdminQueries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAdminQueriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetAdminQueries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetAdminQueries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetAdminQueries(ctx, req.(*GetAdminQueriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_RunAdminQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunAdminQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).RunAdminQuery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/RunAdminQuery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).RunAdminQuery(ctx, req.(*RunAdminQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetIndexSuggestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIndexSuggestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetIndexSuggestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetIndexSuggestions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetIndexSuggestions(ctx, req.(*GetIndexSuggestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_SubmitSignedClaims_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitSignedClaimsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).SubmitSignedClaims(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/SubmitSignedClaims",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).SubmitSignedClaims(ctx, req.(*SubmitSignedClaimsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetReserveAttestation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReserveAttestationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetReserveAttestation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetReserveAttestation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetReserveAttestation(ctx, req.(*GetReserveAttestationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_SubmitQueryJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitQueryJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).SubmitQueryJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/SubmitQueryJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).SubmitQueryJob(ctx, req.(*SubmitQueryJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetQueryJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueryJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetQueryJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetQueryJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetQueryJob(ctx, req.(*GetQueryJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetQueryJobResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueryJobResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetQueryJobResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetQueryJobResult",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetQueryJobResult(ctx, req.(*GetQueryJobResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetClaimBreakers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClaimBreakersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetClaimBreakers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetClaimBreakers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetClaimBreakers(ctx, req.(*GetClaimBreakersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetProofAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProofAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetProofAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetProofAccess",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetProofAccess(ctx, req.(*GetProofAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_SetProofAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProofAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).SetProofAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/SetProofAccess",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).SetProofAccess(ctx, req.(*SetProofAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_SetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).SetMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/SetMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).SetMaintenance(ctx, req.(*SetMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_AddProofConsumer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProofConsumerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).AddProofConsumer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/AddProofConsumer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).AddProofConsumer(ctx, req.(*AddProofConsumerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_DeleteProofConsumer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProofConsumerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).DeleteProofConsumer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/DeleteProofConsumer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).DeleteProofConsumer(ctx, req.(*DeleteProofConsumerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetTokenList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetTokenList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetTokenList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetTokenList(ctx, req.(*GetTokenListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_SetTokenListEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTokenListEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).SetTokenListEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/SetTokenListEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).SetTokenListEntry(ctx, req.(*SetTokenListEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_DeleteTokenListEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTokenListEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).DeleteTokenListEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/DeleteTokenListEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).DeleteTokenListEntry(ctx, req.(*DeleteTokenListEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetTokenListAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenListAuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetTokenListAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetTokenListAudit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetTokenListAudit(ctx, req.(*GetTokenListAuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetSigningKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSigningKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetSigningKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetSigningKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetSigningKeys(ctx, req.(*GetSigningKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_RotateSigningKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateSigningKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).RotateSigningKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/RotateSigningKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).RotateSigningKey(ctx, req.(*RotateSigningKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_ProposeConfigChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProposeConfigChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).ProposeConfigChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/ProposeConfigChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).ProposeConfigChange(ctx, req.(*ProposeConfigChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_ApproveConfigChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveConfigChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).ApproveConfigChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/ApproveConfigChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).ApproveConfigChange(ctx, req.(*ApproveConfigChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_RejectConfigChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectConfigChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).RejectConfigChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/RejectConfigChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).RejectConfigChange(ctx, req.(*RejectConfigChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetConfigChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetConfigChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetConfigChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetConfigChanges(ctx, req.(*GetConfigChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetSessionChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetSessionChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetSessionChallenge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetSessionChallenge(ctx, req.(*GetSessionChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/CreateSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).CreateSession(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_GetWalletHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWalletHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetWalletHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetWalletHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetWalletHistory(ctx, req.(*GetWalletHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BridgeService_AnnotateDeposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnotateDepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).AnnotateDeposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/AnnotateDeposit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).AnnotateDeposit(ctx, req.(*AnnotateDepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// BridgeServiceServer is the server API for BridgeService service.
-// All implementations must embed UnimplementedBridgeServiceServer
-// for forward compatibility
-type BridgeServiceServer interface {
-	// Getters
-	/// Get api version
-	CheckAPI(context.Context, *CheckAPIRequest) (*CheckAPIResponse, error)
-	/// Get bridges for the destination address both in L1 and L2
-	GetBridges(context.Context, *GetBridgesRequest) (*GetBridgesResponse, error)
-	/// Get the merkle proof for the specific deposit
-	GetProof(context.Context, *GetProofRequest) (*GetProofResponse, error)
-	/// Get the specific deposit
-	GetBridge(context.Context, *GetBridgeRequest) (*GetBridgeResponse, error)
-	/// Get claims for the specific smart contract address both in L1 and L2
-	GetClaims(context.Context, *GetClaimsRequest) (*GetClaimsResponse, error)
-	/// Get token wrapped for the specific smart contract address both in L1 and L2
-	GetTokenWrapped(context.Context, *GetTokenWrappedRequest) (*GetTokenWrappedResponse, error)
-	mustEmbedUnimplementedBridgeServiceServer()
+func _BridgeService_SetDepositRef_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDepositRefRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).SetDepositRef(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/SetDepositRef",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).SetDepositRef(ctx, req.(*SetDepositRefRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedBridgeServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedBridgeServiceServer struct {
+func _BridgeService_GetWalletWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWalletWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetWalletWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetWalletWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetWalletWebhooks(ctx, req.(*GetWalletWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedBridgeServiceServer) CheckAPI(context.Context, *CheckAPIRequest) (*CheckAPIResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CheckAPI not implemented")
-}
-func (UnimplementedBridgeServiceServer) GetBridges(context.Context, *GetBridgesRequest) (*GetBridgesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetBridges not implemented")
-}
-func (UnimplementedBridgeServiceServer) GetProof(context.Context, *GetProofRequest) (*GetProofResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetProof not implemented")
-}
-func (UnimplementedBridgeServiceServer) GetBridge(context.Context, *GetBridgeRequest) (*GetBridgeResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetBridge not implemented")
-}
-func (UnimplementedBridgeServiceServer) GetClaims(context.Context, *GetClaimsRequest) (*GetClaimsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetClaims not implemented")
-}
-func (UnimplementedBridgeServiceServer) GetTokenWrapped(context.Context, *GetTokenWrappedRequest) (*GetTokenWrappedResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetTokenWrapped not implemented")
+func _BridgeService_AddWalletWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWalletWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).AddWalletWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/AddWalletWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).AddWalletWebhook(ctx, req.(*AddWalletWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedBridgeServiceServer) mustEmbedUnimplementedBridgeServiceServer() {}
 
-// UnsafeBridgeServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to BridgeServiceServer will
-// result in compilation errors.
-type UnsafeBridgeServiceServer interface {
-	mustEmbedUnimplementedBridgeServiceServer()
+func _BridgeService_DeleteWalletWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWalletWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).DeleteWalletWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/DeleteWalletWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).DeleteWalletWebhook(ctx, req.(*DeleteWalletWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterBridgeServiceServer(s grpc.ServiceRegistrar, srv BridgeServiceServer) {
-	s.RegisterService(&BridgeService_ServiceDesc, srv)
+func _BridgeService_GetPushDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPushDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BridgeServiceServer).GetPushDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bridge.v1.BridgeService/GetPushDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BridgeServiceServer).GetPushDevices(ctx, req.(*GetPushDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _BridgeService_CheckAPI_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CheckAPIRequest)
+func _BridgeService_AddPushDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPushDeviceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BridgeServiceServer).CheckAPI(ctx, in)
+		return srv.(BridgeServiceServer).AddPushDevice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bridge.v1.BridgeService/CheckAPI",
+		FullMethod: "/bridge.v1.BridgeService/AddPushDevice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BridgeServiceServer).CheckAPI(ctx, req.(*CheckAPIRequest))
+		return srv.(BridgeServiceServer).AddPushDevice(ctx, req.(*AddPushDeviceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BridgeService_GetBridges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetBridgesRequest)
+func _BridgeService_DeletePushDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePushDeviceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BridgeServiceServer).GetBridges(ctx, in)
+		return srv.(BridgeServiceServer).DeletePushDevice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bridge.v1.BridgeService/GetBridges",
+		FullMethod: "/bridge.v1.BridgeService/DeletePushDevice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BridgeServiceServer).GetBridges(ctx, req.(*GetBridgesRequest))
+		return srv.(BridgeServiceServer).DeletePushDevice(ctx, req.(*DeletePushDeviceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BridgeService_GetProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetProofRequest)
+func _BridgeService_GetWatchedAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWatchedAddressesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BridgeServiceServer).GetProof(ctx, in)
+		return srv.(BridgeServiceServer).GetWatchedAddresses(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bridge.v1.BridgeService/GetProof",
+		FullMethod: "/bridge.v1.BridgeService/GetWatchedAddresses",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BridgeServiceServer).GetProof(ctx, req.(*GetProofRequest))
+		return srv.(BridgeServiceServer).GetWatchedAddresses(ctx, req.(*GetWatchedAddressesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BridgeService_GetBridge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetBridgeRequest)
+func _BridgeService_AddWatchedAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWatchedAddressRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BridgeServiceServer).GetBridge(ctx, in)
+		return srv.(BridgeServiceServer).AddWatchedAddress(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bridge.v1.BridgeService/GetBridge",
+		FullMethod: "/bridge.v1.BridgeService/AddWatchedAddress",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BridgeServiceServer).GetBridge(ctx, req.(*GetBridgeRequest))
+		return srv.(BridgeServiceServer).AddWatchedAddress(ctx, req.(*AddWatchedAddressRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BridgeService_GetClaims_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetClaimsRequest)
+func _BridgeService_DeleteWatchedAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWatchedAddressRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BridgeServiceServer).GetClaims(ctx, in)
+		return srv.(BridgeServiceServer).DeleteWatchedAddress(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bridge.v1.BridgeService/GetClaims",
+		FullMethod: "/bridge.v1.BridgeService/DeleteWatchedAddress",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BridgeServiceServer).GetClaims(ctx, req.(*GetClaimsRequest))
+		return srv.(BridgeServiceServer).DeleteWatchedAddress(ctx, req.(*DeleteWatchedAddressRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BridgeService_GetTokenWrapped_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTokenWrappedRequest)
+func _BridgeService_BackfillWatchedAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackfillWatchedAddressRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BridgeServiceServer).GetTokenWrapped(ctx, in)
+		return srv.(BridgeServiceServer).BackfillWatchedAddress(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bridge.v1.BridgeService/GetTokenWrapped",
+		FullMethod: "/bridge.v1.BridgeService/BackfillWatchedAddress",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BridgeServiceServer).GetTokenWrapped(ctx, req.(*GetTokenWrappedRequest))
+		return srv.(BridgeServiceServer).BackfillWatchedAddress(ctx, req.(*BackfillWatchedAddressRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -293,7 +2431,254 @@ var BridgeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTokenWrapped",
 			Handler:    _BridgeService_GetTokenWrapped_Handler,
 		},
+		{
+			MethodName: "GetTokenWrappedHistory",
+			Handler:    _BridgeService_GetTokenWrappedHistory_Handler,
+		},
+		{
+			MethodName: "ValidateClaim",
+			Handler:    _BridgeService_ValidateClaim_Handler,
+		},
+		{
+			MethodName: "GetCCIPProof",
+			Handler:    _BridgeService_GetCCIPProof_Handler,
+		},
+		{
+			MethodName: "GetActivity",
+			Handler:    _BridgeService_GetActivity_Handler,
+		},
+		{
+			MethodName: "GetIntegrationStats",
+			Handler:    _BridgeService_GetIntegrationStats_Handler,
+		},
+		{
+			MethodName: "GetClaimTx",
+			Handler:    _BridgeService_GetClaimTx_Handler,
+		},
+		{
+			MethodName: "GetClaimOutcome",
+			Handler:    _BridgeService_GetClaimOutcome_Handler,
+		},
+		{
+			MethodName: "GetDepositActivity",
+			Handler:    _BridgeService_GetDepositActivity_Handler,
+		},
+		{
+			MethodName: "GetMaintenance",
+			Handler:    _BridgeService_GetMaintenance_Handler,
+		},
+		{
+			MethodName: "SimulateClaims",
+			Handler:    _BridgeService_SimulateClaims_Handler,
+		},
+		{
+			MethodName: "GetGERInjectionLatency",
+			Handler:    _BridgeService_GetGERInjectionLatency_Handler,
+		},
+		{
+			MethodName: "GetBridgesByTx",
+			Handler:    _BridgeService_GetBridgesByTx_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _BridgeService_Search_Handler,
+		},
+		{
+			MethodName: "GetL1InfoTreeProof",
+			Handler:    _BridgeService_GetL1InfoTreeProof_Handler,
+		},
+		{
+			MethodName: "GetClaimWitness",
+			Handler:    _BridgeService_GetClaimWitness_Handler,
+		},
+		{
+			MethodName: "DecodeGlobalIndex",
+			Handler:    _BridgeService_DecodeGlobalIndex_Handler,
+		},
+		{
+			MethodName: "GetContracts",
+			Handler:    _BridgeService_GetContracts_Handler,
+		},
+		{
+			MethodName: "GetReplication",
+			Handler:    _BridgeService_GetReplication_Handler,
+		},
+		{
+			MethodName: "GetPendingClaimApprovals",
+			Handler:    _BridgeService_GetPendingClaimApprovals_Handler,
+		},
+		{
+			MethodName: "ApproveClaim",
+			Handler:    _BridgeService_ApproveClaim_Handler,
+		},
+		{
+			MethodName: "GetAdminQueries",
+			Handler:    _BridgeService_GetAdminQueries_Handler,
+		},
+		{
+			MethodName: "RunAdminQuery",
+			Handler:    _BridgeService_RunAdminQuery_Handler,
+		},
+		{
+			MethodName: "GetIndexSuggestions",
+			Handler:    _BridgeService_GetIndexSuggestions_Handler,
+		},
+		{
+			MethodName: "SubmitSignedClaims",
+			Handler:    _BridgeService_SubmitSignedClaims_Handler,
+		},
+		{
+			MethodName: "GetReserveAttestation",
+			Handler:    _BridgeService_GetReserveAttestation_Handler,
+		},
+		{
+			MethodName: "SubmitQueryJob",
+			Handler:    _BridgeService_SubmitQueryJob_Handler,
+		},
+		{
+			MethodName: "GetQueryJob",
+			Handler:    _BridgeService_GetQueryJob_Handler,
+		},
+		{
+			MethodName: "GetQueryJobResult",
+			Handler:    _BridgeService_GetQueryJobResult_Handler,
+		},
+		{
+			MethodName: "GetClaimBreakers",
+			Handler:    _BridgeService_GetClaimBreakers_Handler,
+		},
+		{
+			MethodName: "GetProofAccess",
+			Handler:    _BridgeService_GetProofAccess_Handler,
+		},
+		{
+			MethodName: "SetProofAccess",
+			Handler:    _BridgeService_SetProofAccess_Handler,
+		},
+		{
+			MethodName: "SetMaintenance",
+			Handler:    _BridgeService_SetMaintenance_Handler,
+		},
+		{
+			MethodName: "AddProofConsumer",
+			Handler:    _BridgeService_AddProofConsumer_Handler,
+		},
+		{
+			MethodName: "DeleteProofConsumer",
+			Handler:    _BridgeService_DeleteProofConsumer_Handler,
+		},
+		{
+			MethodName: "GetTokenList",
+			Handler:    _BridgeService_GetTokenList_Handler,
+		},
+		{
+			MethodName: "SetTokenListEntry",
+			Handler:    _BridgeService_SetTokenListEntry_Handler,
+		},
+		{
+			MethodName: "DeleteTokenListEntry",
+			Handler:    _BridgeService_DeleteTokenListEntry_Handler,
+		},
+		{
+			MethodName: "GetTokenListAudit",
+			Handler:    _BridgeService_GetTokenListAudit_Handler,
+		},
+		{
+			MethodName: "GetSigningKeys",
+			Handler:    _BridgeService_GetSigningKeys_Handler,
+		},
+		{
+			MethodName: "RotateSigningKey",
+			Handler:    _BridgeService_RotateSigningKey_Handler,
+		},
+		{
+			MethodName: "ProposeConfigChange",
+			Handler:    _BridgeService_ProposeConfigChange_Handler,
+		},
+		{
+			MethodName: "ApproveConfigChange",
+			Handler:    _BridgeService_ApproveConfigChange_Handler,
+		},
+		{
+			MethodName: "RejectConfigChange",
+			Handler:    _BridgeService_RejectConfigChange_Handler,
+		},
+		{
+			MethodName: "GetConfigChanges",
+			Handler:    _BridgeService_GetConfigChanges_Handler,
+		},
+		{
+			MethodName: "GetSessionChallenge",
+			Handler:    _BridgeService_GetSessionChallenge_Handler,
+		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _BridgeService_CreateSession_Handler,
+		},
+		{
+			MethodName: "GetWalletHistory",
+			Handler:    _BridgeService_GetWalletHistory_Handler,
+		},
+		{
+			MethodName: "AnnotateDeposit",
+			Handler:    _BridgeService_AnnotateDeposit_Handler,
+		},
+		{
+			MethodName: "SetDepositRef",
+			Handler:    _BridgeService_SetDepositRef_Handler,
+		},
+		{
+			MethodName: "GetWalletWebhooks",
+			Handler:    _BridgeService_GetWalletWebhooks_Handler,
+		},
+		{
+			MethodName: "AddWalletWebhook",
+			Handler:    _BridgeService_AddWalletWebhook_Handler,
+		},
+		{
+			MethodName: "DeleteWalletWebhook",
+			Handler:    _BridgeService_DeleteWalletWebhook_Handler,
+		},
+		{
+			MethodName: "GetPushDevices",
+			Handler:    _BridgeService_GetPushDevices_Handler,
+		},
+		{
+			MethodName: "AddPushDevice",
+			Handler:    _BridgeService_AddPushDevice_Handler,
+		},
+		{
+			MethodName: "DeletePushDevice",
+			Handler:    _BridgeService_DeletePushDevice_Handler,
+		},
+		{
+			MethodName: "GetWatchedAddresses",
+			Handler:    _BridgeService_GetWatchedAddresses_Handler,
+		},
+		{
+			MethodName: "AddWatchedAddress",
+			Handler:    _BridgeService_AddWatchedAddress_Handler,
+		},
+		{
+			MethodName: "DeleteWatchedAddress",
+			Handler:    _BridgeService_DeleteWatchedAddress_Handler,
+		},
+		{
+			MethodName: "BackfillWatchedAddress",
+			Handler:    _BridgeService_BackfillWatchedAddress_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBridgeEvents",
+			Handler:       _BridgeService_SubscribeBridgeEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamDepositStatus",
+			Handler:       _BridgeService_StreamDepositStatus_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "query.proto",
 }
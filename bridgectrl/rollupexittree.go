@@ -0,0 +1,56 @@
+package bridgectrl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// RollupExitTree is the tree of the local exit roots of the rollups, whose root is the rollup exit root of
+// the global exit root. The leaf of a rollup is updated every time a batch of the rollup is verified in L1.
+// It only has a leaf per rollup, so it's calculated from the latest leaves instead of storing its nodes.
+type RollupExitTree struct {
+	store  rollupExitTreeStore
+	height uint8
+}
+
+// NewRollupExitTree creates new RollupExitTree.
+func NewRollupExitTree(store rollupExitTreeStore, height uint8) *RollupExitTree {
+	return &RollupExitTree{
+		store:  store,
+		height: height,
+	}
+}
+
+// RollupExitLeaves returns the leaves of the rollup exit tree, the position rollupID - 1 is the leaf of the rollup.
+// The rollups without a leaf have the zero hash.
+func RollupExitLeaves(leaves []etherman.RollupExitLeaf) ([][KeyLen]byte, error) {
+	var res [][KeyLen]byte
+	for _, leaf := range leaves {
+		if leaf.RollupID == 0 {
+			return nil, fmt.Errorf("invalid rollup id 0 of the rollup exit leaf %s", leaf.Leaf.String())
+		}
+		for uint(len(res)) < leaf.RollupID {
+			res = append(res, HashZero)
+		}
+		res[leaf.RollupID-1] = leaf.Leaf
+	}
+	return res, nil
+}
+
+// updateLeaf updates the leaf of a rollup, storing it with the new rollup exit root.
+func (rt *RollupExitTree) updateLeaf(ctx context.Context, leaf *etherman.RollupExitLeaf, dbTx pgx.Tx) error {
+	latest, err := rt.store.GetLatestRollupExitLeaves(ctx, dbTx)
+	if err != nil {
+		return err
+	}
+	leaves, err := RollupExitLeaves(append(latest, *leaf))
+	if err != nil {
+		return err
+	}
+	leaf.Root = common.Hash(ComputeRoot(leaves, rt.height))
+	return rt.store.AddRollupExitLeaf(ctx, leaf, dbTx)
+}
@@ -0,0 +1,65 @@
+package bridgectrl
+
+import (
+	"fmt"
+)
+
+// ComputeRoot calculates the root of the sparse merkle tree of the given height with the given leaves
+// in its first positions.
+func ComputeRoot(leaves [][KeyLen]byte, height uint8) [KeyLen]byte {
+	nodes := leaves
+	for h := uint8(0); h < height; h++ {
+		if len(nodes) == 0 {
+			return zeroHashes[height]
+		}
+		if len(nodes)%2 == 1 {
+			nodes = append(nodes, zeroHashes[h])
+		}
+		parents := make([][KeyLen]byte, 0, len(nodes)/2) //nolint:gomnd
+		for i := 0; i < len(nodes); i += 2 {
+			parents = append(parents, Hash(nodes[i], nodes[i+1]))
+		}
+		nodes = parents
+	}
+	if len(nodes) == 0 {
+		return zeroHashes[height]
+	}
+	return nodes[0]
+}
+
+// ComputeSiblings calculates the merkle proof of the leaf at the given index, from the leafs to the top, in the
+// sparse merkle tree of the given height with the given leaves in its first positions.
+func ComputeSiblings(index uint, leaves [][KeyLen]byte, height uint8) ([][KeyLen]byte, error) {
+	if index >= uint(len(leaves)) {
+		return nil, fmt.Errorf("index %d out of the %d leaves of the tree", index, len(leaves))
+	}
+	var siblings [][KeyLen]byte
+	nodes := append([][KeyLen]byte{}, leaves...)
+	for h := uint8(0); h < height; h++ {
+		if len(nodes)%2 == 1 {
+			nodes = append(nodes, zeroHashes[h])
+		}
+		// The sibling of the node in this level is its pair
+		siblings = append(siblings, nodes[index^1])
+		parents := make([][KeyLen]byte, 0, len(nodes)/2) //nolint:gomnd
+		for i := 0; i < len(nodes); i += 2 {
+			parents = append(parents, Hash(nodes[i], nodes[i+1]))
+		}
+		nodes = parents
+		index /= 2
+	}
+	return siblings, nil
+}
+
+// CheckProof checks the merkle proof of a leaf against the root.
+func CheckProof(leaf [KeyLen]byte, index uint, proof [][KeyLen]byte, root [KeyLen]byte) bool {
+	cur := leaf
+	for h, sibling := range proof {
+		if index&(1<<h) > 0 {
+			cur = Hash(sibling, cur)
+		} else {
+			cur = Hash(cur, sibling)
+		}
+	}
+	return cur == root
+}
@@ -0,0 +1,212 @@
+package bridgectrl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/test/vectors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeRoot(t *testing.T) {
+	data, err := os.ReadFile("test/vectors/src/mt-bridge/root-vectors.json")
+	require.NoError(t, err)
+	var testVectors []vectors.MTRootVectorRaw
+	require.NoError(t, json.Unmarshal(data, &testVectors))
+
+	for _, testVector := range testVectors {
+		var leaves [][KeyLen]byte
+		for _, leaf := range testVector.ExistingLeaves {
+			leaves = append(leaves, common.HexToHash(leaf))
+		}
+		require.Equal(t, common.HexToHash(testVector.CurrentRoot), common.Hash(ComputeRoot(leaves, 32)))
+		leaves = append(leaves, common.HexToHash(testVector.NewLeaf.CurrentHash))
+		require.Equal(t, common.HexToHash(testVector.NewRoot), common.Hash(ComputeRoot(leaves, 32)))
+	}
+}
+
+func TestComputeSiblings(t *testing.T) {
+	data, err := os.ReadFile("test/vectors/src/mt-bridge/claim-vectors.json")
+	require.NoError(t, err)
+	var testVectors []vectors.MTClaimVectorRaw
+	require.NoError(t, json.Unmarshal(data, &testVectors))
+
+	for _, testVector := range testVectors {
+		var leaves [][KeyLen]byte
+		for _, deposit := range testVector.Deposits {
+			leaves = append(leaves, common.HexToHash(deposit.ExpectedHash))
+		}
+		proof, err := ComputeSiblings(testVector.Index, leaves, 32)
+		require.NoError(t, err)
+		require.Len(t, proof, len(testVector.MerkleProof))
+		for i, sibling := range testVector.MerkleProof {
+			require.Equal(t, common.HexToHash(sibling), common.Hash(proof[i]))
+		}
+		root := common.HexToHash(testVector.ExpectedRoot)
+		require.True(t, CheckProof(leaves[testVector.Index], testVector.Index, proof, root))
+		require.False(t, CheckProof(leaves[testVector.Index], testVector.Index+1, proof, root))
+	}
+	_, err = ComputeSiblings(1, [][KeyLen]byte{HashZero}, 32)
+	require.Error(t, err)
+}
+
+// treeStore keeps the leaves of the rollup exit tree and the L1 info tree in memory.
+type treeStore struct {
+	rollupExitLeaves []etherman.RollupExitLeaf
+	l1InfoLeaves     []etherman.L1InfoTreeLeaf
+	gerUpdates       []etherman.L1InfoTreeLeaf
+	addErr           error
+}
+
+func (s *treeStore) AddRollupExitLeaf(ctx context.Context, leaf *etherman.RollupExitLeaf, dbTx pgx.Tx) error {
+	s.rollupExitLeaves = append(s.rollupExitLeaves, *leaf)
+	return nil
+}
+
+func (s *treeStore) GetLatestRollupExitLeaves(ctx context.Context, dbTx pgx.Tx) ([]etherman.RollupExitLeaf, error) {
+	latest := make(map[uint]etherman.RollupExitLeaf)
+	for _, leaf := range s.rollupExitLeaves {
+		latest[leaf.RollupID] = leaf
+	}
+	var leaves []etherman.RollupExitLeaf
+	for _, leaf := range latest {
+		leaves = append(leaves, leaf)
+	}
+	return leaves, nil
+}
+
+func (s *treeStore) AddL1InfoTreeLeaf(ctx context.Context, leaf *etherman.L1InfoTreeLeaf, dbTx pgx.Tx) error {
+	if s.addErr != nil {
+		return s.addErr
+	}
+	s.l1InfoLeaves = append(s.l1InfoLeaves, *leaf)
+	return nil
+}
+
+func (s *treeStore) GetL1InfoTreeLeaves(ctx context.Context, dbTx pgx.Tx) ([]etherman.L1InfoTreeLeaf, error) {
+	return s.l1InfoLeaves, nil
+}
+
+func (s *treeStore) GetL1InfoTreeLeafCount(ctx context.Context, dbTx pgx.Tx) (uint, error) {
+	return uint(len(s.l1InfoLeaves)), nil
+}
+
+func (s *treeStore) GetL1GlobalExitRootUpdates(ctx context.Context, dbTx pgx.Tx) ([]etherman.L1InfoTreeLeaf, error) {
+	return s.gerUpdates, nil
+}
+
+func TestRollupExitTree(t *testing.T) {
+	ctx := context.Background()
+	store := &treeStore{}
+	rt := NewRollupExitTree(store, 32)
+
+	ler1, ler2, ler3 := common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+	for _, tc := range []struct {
+		leaf     etherman.RollupExitLeaf
+		expected [][KeyLen]byte
+	}{
+		{etherman.RollupExitLeaf{RollupID: 2, Leaf: ler1}, [][KeyLen]byte{HashZero, ler1}},
+		{etherman.RollupExitLeaf{RollupID: 1, Leaf: ler2}, [][KeyLen]byte{ler2, ler1}},
+		{etherman.RollupExitLeaf{RollupID: 2, Leaf: ler3}, [][KeyLen]byte{ler2, ler3}},
+	} {
+		leaf := tc.leaf
+		require.NoError(t, rt.updateLeaf(ctx, &leaf, nil))
+		require.Equal(t, common.Hash(ComputeRoot(tc.expected, 32)), leaf.Root)
+	}
+
+	// The proof of a rollup is calculated from the leaves
+	leaves, err := RollupExitLeaves(store.rollupExitLeaves)
+	require.NoError(t, err)
+	proof, err := ComputeSiblings(1, leaves, 32)
+	require.NoError(t, err)
+	require.True(t, CheckProof(ler3, 1, proof, store.rollupExitLeaves[2].Root))
+	require.Error(t, rt.updateLeaf(ctx, &etherman.RollupExitLeaf{RollupID: 0, Leaf: ler1}, nil))
+}
+
+func TestL1InfoTree(t *testing.T) {
+	ctx := context.Background()
+	store := &treeStore{}
+	for i := 0; i < 5; i++ {
+		store.gerUpdates = append(store.gerUpdates, etherman.L1InfoTreeLeaf{
+			GlobalExitRoot: common.BigToHash(big.NewInt(int64(i + 1))),
+			ParentHash:     common.HexToHash("0xff"),
+			Timestamp:      time.Unix(int64(1000+i), 0),
+		})
+	}
+
+	// The synced global exit roots are appended to the new tree
+	lt, err := NewL1InfoTree(ctx, store, 32)
+	require.NoError(t, err)
+	require.Len(t, store.l1InfoLeaves, 5)
+	var leaves [][KeyLen]byte
+	for i, leaf := range store.l1InfoLeaves {
+		require.Equal(t, uint(i), leaf.Index)
+		require.Equal(t, common.Hash(HashL1InfoLeaf(leaf.GlobalExitRoot, leaf.ParentHash, uint64(leaf.Timestamp.Unix()))), leaf.Leaf)
+		leaves = append(leaves, leaf.Leaf)
+		require.Equal(t, common.Hash(ComputeRoot(leaves, 32)), leaf.Root)
+	}
+
+	// The tree is reloaded after a reorg
+	store.l1InfoLeaves = store.l1InfoLeaves[:2]
+	require.NoError(t, lt.reset(ctx, nil))
+	leaf := &etherman.L1InfoTreeLeaf{GlobalExitRoot: common.HexToHash("0x0a"), Timestamp: time.Unix(2000, 0)}
+	require.NoError(t, lt.addLeaf(ctx, leaf, nil))
+	require.Equal(t, uint(2), leaf.Index)
+	require.Equal(t, common.Hash(ComputeRoot([][KeyLen]byte{leaves[0], leaves[1], leaf.Leaf}, 32)), leaf.Root)
+
+	// The leaves of a rolled back db tx are dropped from the tree
+	require.NoError(t, lt.addLeaf(ctx, &etherman.L1InfoTreeLeaf{GlobalExitRoot: common.HexToHash("0x0b"), Timestamp: time.Unix(2001, 0)}, nil))
+	store.l1InfoLeaves = store.l1InfoLeaves[:3]
+	leaf = &etherman.L1InfoTreeLeaf{GlobalExitRoot: common.HexToHash("0x0c"), Timestamp: time.Unix(2002, 0)}
+	require.NoError(t, lt.addLeaf(ctx, leaf, nil))
+	require.Equal(t, uint(3), leaf.Index)
+	require.Equal(t, common.Hash(ComputeRoot([][KeyLen]byte{leaves[0], leaves[1], store.l1InfoLeaves[2].Leaf, leaf.Leaf}, 32)), leaf.Root)
+	store.l1InfoLeaves = store.l1InfoLeaves[:3]
+
+	// The restarted tree only appends the missing global exit roots
+	store.gerUpdates = store.gerUpdates[:3]
+	_, err = NewL1InfoTree(ctx, store, 32)
+	require.NoError(t, err)
+	require.Len(t, store.l1InfoLeaves, 3)
+
+	// The proofs are of the tree of the first leaves, the last ones may not be committed yet
+	for i := 3; i < 7; i++ {
+		require.NoError(t, lt.addLeaf(ctx, &etherman.L1InfoTreeLeaf{GlobalExitRoot: common.BigToHash(big.NewInt(int64(i + 10)))}, nil))
+	}
+	leaves = nil
+	for _, leaf := range store.l1InfoLeaves {
+		leaves = append(leaves, leaf.Leaf)
+	}
+	for count := 1; count <= len(leaves); count++ {
+		for index := 0; index < count; index++ {
+			proof, err := lt.proof(uint(index), uint(count))
+			require.NoError(t, err)
+			siblings, err := ComputeSiblings(uint(index), leaves[:count], 32)
+			require.NoError(t, err)
+			require.Equal(t, leaves[index], proof.Leaf)
+			require.Equal(t, siblings, proof.Siblings)
+			require.Equal(t, [KeyLen]byte(store.l1InfoLeaves[count-1].Root), proof.Root)
+		}
+	}
+	_, err = lt.proof(7, 7)
+	require.Error(t, err)
+	_, err = lt.proof(0, 8)
+	require.Error(t, err)
+
+	// The leaf that is not stored is removed from the tree
+	store.addErr = errors.New("db error")
+	require.Error(t, lt.addLeaf(ctx, &etherman.L1InfoTreeLeaf{GlobalExitRoot: common.HexToHash("0x0d")}, nil))
+	store.addErr = nil
+	leaf = &etherman.L1InfoTreeLeaf{GlobalExitRoot: common.HexToHash("0x0e")}
+	require.NoError(t, lt.addLeaf(ctx, leaf, nil))
+	require.Equal(t, uint(7), leaf.Index)
+	require.Equal(t, common.Hash(ComputeRoot(append(leaves, leaf.Leaf), 32)), leaf.Root)
+}
@@ -0,0 +1,389 @@
+// Package cache is the cache of the API responses, in two tiers: a local cache in each replica and an optional
+// redis shared by all the replicas, so a scaled deployment warms the cache once.
+//
+// The entries are invalidated by tags. Each tag has a generation, part of the keys of its entries, and the
+// invalidation increments it, so the entries of the previous generation aren't read anymore and expire. The
+// generations are counters in redis, and the replicas learn about the increments with the messages published
+// on the invalidation channel. The invalidations are triggered by the hooks of the sync and claim loops.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// AllTag invalidates all the tagged entries, on reorgs
+	AllTag = "*"
+
+	invalidationChannel = "invalidate"
+	resubscribeInterval = time.Second
+)
+
+// AccountTag invalidates the queries of an account.
+func AccountTag(addr common.Address) string {
+	return "account:" + addr.Hex()
+}
+
+// TokenTag invalidates the metadata of a token.
+func TokenTag(originalNetwork uint, originalAddress common.Address) string {
+	return fmt.Sprintf("token:%d:%s", originalNetwork, originalAddress.Hex())
+}
+
+// subscription receives the invalidation messages, until it's closed.
+type subscription interface {
+	Receive(ctx context.Context) ([]byte, error)
+	Close() error
+}
+
+// remote is the tier shared by the replicas. Get returns redis.Nil for the missing keys.
+type remote interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	MGet(ctx context.Context, keys ...string) ([][]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Incr(ctx context.Context, key string) (int64, error)
+	Publish(ctx context.Context, channel string, message []byte) error
+	Subscribe(ctx context.Context, channel string) (subscription, error)
+}
+
+type redisRemote struct {
+	client *redis.Client
+}
+
+func (r redisRemote) Get(ctx context.Context, key string) ([]byte, error) {
+	return r.client.Get(ctx, key).Bytes()
+}
+
+func (r redisRemote) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([][]byte, len(values))
+	for i, value := range values {
+		// The missing keys are nil
+		if value, ok := value.(string); ok {
+			res[i] = []byte(value)
+		}
+	}
+	return res, nil
+}
+
+func (r redisRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (r redisRemote) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
+func (r redisRemote) Publish(ctx context.Context, channel string, message []byte) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe subscribes to the channel, returning once redis confirms the subscription.
+func (r redisRemote) Subscribe(ctx context.Context, channel string) (subscription, error) {
+	pubSub := r.client.Subscribe(ctx, channel)
+	if _, err := pubSub.Receive(ctx); err != nil {
+		_ = pubSub.Close()
+		return nil, err
+	}
+	return redisSubscription{pubSub}, nil
+}
+
+type redisSubscription struct {
+	pubSub *redis.PubSub
+}
+
+func (s redisSubscription) Receive(ctx context.Context) ([]byte, error) {
+	message, err := s.pubSub.ReceiveMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(message.Payload), nil
+}
+
+func (s redisSubscription) Close() error {
+	return s.pubSub.Close()
+}
+
+// Cache is the two tier cache. A nil cache loads every value.
+type Cache struct {
+	local   *lru.Cache[string, []byte]
+	remote  remote
+	prefix  string
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu sync.Mutex
+	// gens are the generations of the tags. With redis they are the known values of its counters, and they are
+	// nil while not subscribed to the invalidations, so the counters are read on each load
+	gens map[string]int64
+	// epoch changes with each invalidation received, so the generations read from redis meanwhile aren't kept
+	epoch uint64
+
+	pendingMu sync.Mutex
+	pending   map[string]struct{}
+	notify    chan struct{}
+}
+
+// New creates the cache of the configuration.
+func New(cfg Config) (*Cache, error) {
+	var r remote
+	if cfg.RedisURL != "" {
+		opts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = cfg.Timeout.Duration, cfg.Timeout.Duration, cfg.Timeout.Duration
+		r = redisRemote{redis.NewClient(opts)}
+	}
+	return newCache(cfg, r)
+}
+
+func newCache(cfg Config, r remote) (*Cache, error) {
+	local, err := lru.New[string, []byte](cfg.LocalSize)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cache{
+		local:   local,
+		remote:  r,
+		prefix:  cfg.Prefix,
+		ttl:     cfg.TTL.Duration,
+		timeout: cfg.Timeout.Duration,
+		pending: make(map[string]struct{}),
+		notify:  make(chan struct{}, 1),
+	}
+	if r == nil {
+		c.gens = make(map[string]int64)
+	}
+	return c, nil
+}
+
+// Load returns the value of the key, calling load on a miss and caching its value. A tagged entry is
+// invalidated by any of its tags or by AllTag, while the entries without tags are never invalidated, for the
+// values that can't change. The errors of the shared tier are misses, so the values are still loaded.
+func (c *Cache) Load(ctx context.Context, key string, tags []string, load func() ([]byte, error)) ([]byte, error) {
+	if c == nil {
+		return load()
+	}
+	// The key is resolved before the load, so a value loaded while its tags are invalidated is cached in the
+	// previous generation
+	versionedKey, err := c.versionedKey(ctx, key, tags)
+	if err != nil {
+		log.Debugf("error getting the generations of the cache key %s: %v", key, err)
+		return load()
+	}
+	if value, ok := c.local.Get(versionedKey); ok {
+		return value, nil
+	}
+	if c.remote != nil {
+		remoteCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		value, err := c.remote.Get(remoteCtx, c.prefix+versionedKey)
+		cancel()
+		if err == nil {
+			c.local.Add(versionedKey, value)
+			return value, nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			log.Debugf("error getting the cache key %s: %v", key, err)
+		}
+	}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.local.Add(versionedKey, value)
+	if c.remote != nil {
+		remoteCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		if err := c.remote.Set(remoteCtx, c.prefix+versionedKey, value, c.ttl); err != nil {
+			log.Debugf("error setting the cache key %s: %v", key, err)
+		}
+		cancel()
+	}
+	return value, nil
+}
+
+// versionedKey returns the key with the generations of its tags.
+func (c *Cache) versionedKey(ctx context.Context, key string, tags []string) (string, error) {
+	if len(tags) == 0 {
+		return key, nil
+	}
+	tags = append([]string{AllTag}, tags...)
+	gens, err := c.generations(ctx, tags)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(key)
+	for i, tag := range tags {
+		b.WriteString("|" + tag + "=" + strconv.FormatInt(gens[i], 10)) //nolint:gomnd
+	}
+	return b.String(), nil
+}
+
+func (c *Cache) generations(ctx context.Context, tags []string) ([]int64, error) {
+	gens := make([]int64, len(tags))
+	var missing []int
+	c.mu.Lock()
+	epoch := c.epoch
+	for i, tag := range tags {
+		gen, found := c.gens[tag]
+		if !found && c.remote != nil {
+			missing = append(missing, i)
+		}
+		gens[i] = gen
+	}
+	c.mu.Unlock()
+	if len(missing) == 0 {
+		return gens, nil
+	}
+	keys := make([]string, 0, len(missing))
+	for _, i := range missing {
+		keys = append(keys, c.genKey(tags[i]))
+	}
+	remoteCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	values, err := c.remote.MGet(remoteCtx, keys...)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for j, i := range missing {
+		if values[j] != nil {
+			if gens[i], err = strconv.ParseInt(string(values[j]), 10, 64); err != nil { //nolint:gomnd
+				return nil, err
+			}
+		}
+		if c.gens != nil && c.epoch == epoch {
+			c.gens[tags[i]] = gens[i]
+		}
+	}
+	return gens, nil
+}
+
+func (c *Cache) genKey(tag string) string {
+	return c.prefix + "gen:" + tag
+}
+
+// Invalidate invalidates the entries of the tags in all the replicas.
+func (c *Cache) Invalidate(ctx context.Context, tags ...string) error {
+	if c == nil || len(tags) == 0 {
+		return nil
+	}
+	if c.remote == nil {
+		c.mu.Lock()
+		for _, tag := range tags {
+			c.gens[tag]++
+		}
+		c.mu.Unlock()
+		return nil
+	}
+	for _, tag := range tags {
+		gen, err := c.remote.Incr(ctx, c.genKey(tag))
+		if err != nil {
+			return err
+		}
+		c.mu.Lock()
+		if c.gens != nil && gen > c.gens[tag] {
+			c.gens[tag] = gen
+		}
+		c.mu.Unlock()
+	}
+	return c.remote.Publish(ctx, c.prefix+invalidationChannel, []byte(strings.Join(tags, "\n")))
+}
+
+// invalidateLater queues the invalidation of the tags, for the hooks that can't wait for redis. The queued
+// tags are invalidated together by Run.
+func (c *Cache) invalidateLater(tags ...string) {
+	c.pendingMu.Lock()
+	for _, tag := range tags {
+		c.pending[tag] = struct{}{}
+	}
+	c.pendingMu.Unlock()
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run invalidates the queued tags and, with redis, receives the invalidations of the other replicas, until the
+// context is done.
+func (c *Cache) Run(ctx context.Context) {
+	if c.remote != nil {
+		go c.receiveInvalidations(ctx)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.notify:
+		}
+		c.pendingMu.Lock()
+		tags := make([]string, 0, len(c.pending))
+		for tag := range c.pending {
+			tags = append(tags, tag)
+		}
+		c.pending = make(map[string]struct{})
+		c.pendingMu.Unlock()
+		sort.Strings(tags)
+		if err := c.Invalidate(ctx, tags...); err != nil {
+			log.Errorf("error invalidating the cache tags %v, they are invalidated when their entries expire: %v", tags, err)
+		}
+	}
+}
+
+func (c *Cache) receiveInvalidations(ctx context.Context) {
+	for {
+		sub, err := c.remote.Subscribe(ctx, c.prefix+invalidationChannel)
+		if err != nil {
+			log.Warnf("error subscribing to the cache invalidations: %v", err)
+		} else {
+			// The invalidations sent while not subscribed are lost, so the known generations are read again
+			c.setGenerations(make(map[string]int64))
+			for {
+				message, err := sub.Receive(ctx)
+				if err != nil {
+					break
+				}
+				c.mu.Lock()
+				for _, tag := range strings.Split(string(message), "\n") {
+					delete(c.gens, tag)
+				}
+				c.epoch++
+				c.mu.Unlock()
+			}
+			c.setGenerations(nil)
+			if err := sub.Close(); err != nil {
+				log.Debugf("error closing the cache invalidations subscription: %v", err)
+			}
+			if ctx.Err() == nil {
+				log.Warn("cache invalidations subscription lost, reading the generations from redis until subscribed again")
+			}
+		}
+		if wait.Sleep(ctx, wait.RealClock, resubscribeInterval) != nil {
+			return
+		}
+	}
+}
+
+func (c *Cache) setGenerations(gens map[string]int64) {
+	c.mu.Lock()
+	c.gens = gens
+	c.epoch++
+	c.mu.Unlock()
+}
@@ -0,0 +1,276 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// The logger is initialized before the goroutines of the caches log
+	log.Init(log.Config{
+		Level:   "debug",
+		Outputs: []string{"stdout"},
+	})
+}
+
+// memRemote is an in-memory redis shared by the caches of the test.
+type memRemote struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	subs   []chan []byte
+	closed int
+}
+
+func newMemRemote() *memRemote {
+	return &memRemote{values: make(map[string][]byte)}
+}
+
+func (r *memRemote) Get(_ context.Context, key string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, found := r.values[key]
+	if !found {
+		return nil, redis.Nil
+	}
+	return value, nil
+}
+
+func (r *memRemote) MGet(_ context.Context, keys ...string) ([][]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = r.values[key]
+	}
+	return values, nil
+}
+
+func (r *memRemote) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[key] = value
+	return nil
+}
+
+func (r *memRemote) Incr(_ context.Context, key string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, _ := strconv.ParseInt(string(r.values[key]), 10, 64)
+	r.values[key] = []byte(strconv.FormatInt(n+1, 10))
+	return n + 1, nil
+}
+
+func (r *memRemote) Publish(_ context.Context, _ string, message []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sub := range r.subs {
+		sub <- message
+	}
+	return nil
+}
+
+func (r *memRemote) Subscribe(_ context.Context, _ string) (subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub := &memSubscription{remote: r, messages: make(chan []byte, 100)}
+	r.subs = append(r.subs, sub.messages)
+	return sub, nil
+}
+
+// drop drops the subscriptions, like a lost connection.
+func (r *memRemote) drop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sub := range r.subs {
+		close(sub)
+	}
+	r.subs = nil
+}
+
+func (r *memRemote) subscriptions() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.subs), r.closed
+}
+
+type memSubscription struct {
+	remote   *memRemote
+	messages chan []byte
+}
+
+func (s *memSubscription) Receive(ctx context.Context) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case message, ok := <-s.messages:
+		if !ok {
+			return nil, errors.New("connection lost")
+		}
+		return message, nil
+	}
+}
+
+func (s *memSubscription) Close() error {
+	s.remote.mu.Lock()
+	defer s.remote.mu.Unlock()
+	s.remote.closed++
+	return nil
+}
+
+var testConfig = Config{Prefix: "test:", TTL: types.NewDuration(time.Minute), LocalSize: 100, Timeout: types.NewDuration(time.Second)}
+
+// loader counts the loads of a value.
+type loader struct {
+	mu    sync.Mutex
+	loads int
+	value string
+}
+
+func (l *loader) load() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.loads++
+	return []byte(l.value + strconv.Itoa(l.loads)), nil
+}
+
+func (l *loader) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.loads
+}
+
+func TestLocalCache(t *testing.T) {
+	ctx := context.Background()
+	c, err := newCache(testConfig, nil)
+	require.NoError(t, err)
+	addr := common.HexToAddress("0x1")
+	tags := []string{AccountTag(addr), TokenTag(0, common.Address{})}
+
+	l := &loader{value: "bridges"}
+	value, err := c.Load(ctx, "bridges", tags, l.load)
+	require.NoError(t, err)
+	require.Equal(t, "bridges1", string(value))
+	value, err = c.Load(ctx, "bridges", tags, l.load)
+	require.NoError(t, err)
+	require.Equal(t, "bridges1", string(value))
+
+	// Any of the tags invalidates the entry
+	for i, tag := range []string{AccountTag(addr), TokenTag(0, common.Address{}), AllTag} {
+		require.NoError(t, c.Invalidate(ctx, tag))
+		value, err = c.Load(ctx, "bridges", tags, l.load)
+		require.NoError(t, err)
+		require.Equal(t, "bridges"+strconv.Itoa(i+2), string(value))
+	}
+	require.NoError(t, c.Invalidate(ctx, AccountTag(common.HexToAddress("0x2"))))
+	_, err = c.Load(ctx, "bridges", tags, l.load)
+	require.NoError(t, err)
+	require.Equal(t, 4, l.count())
+
+	// The entries without tags are never invalidated
+	node := &loader{value: "node"}
+	_, err = c.Load(ctx, "node", nil, node.load)
+	require.NoError(t, err)
+	require.NoError(t, c.Invalidate(ctx, AllTag))
+	value, err = c.Load(ctx, "node", nil, node.load)
+	require.NoError(t, err)
+	require.Equal(t, "node1", string(value))
+
+	// The errors aren't cached
+	loadErr := errors.New("not found")
+	_, err = c.Load(ctx, "token", []string{TokenTag(0, addr)}, func() ([]byte, error) { return nil, loadErr })
+	require.ErrorIs(t, err, loadErr)
+	token := &loader{value: "token"}
+	_, err = c.Load(ctx, "token", []string{TokenTag(0, addr)}, token.load)
+	require.NoError(t, err)
+	require.Equal(t, 1, token.count())
+
+	// A value loaded while its tags are invalidated is cached in the previous generation
+	racy := &loader{value: "racy"}
+	_, err = c.Load(ctx, "racy", tags, func() ([]byte, error) {
+		require.NoError(t, c.Invalidate(ctx, AccountTag(addr)))
+		return racy.load()
+	})
+	require.NoError(t, err)
+	value, err = c.Load(ctx, "racy", tags, racy.load)
+	require.NoError(t, err)
+	require.Equal(t, "racy2", string(value))
+
+	// A nil cache loads every value
+	var disabled *Cache
+	_, err = disabled.Load(ctx, "bridges", tags, l.load)
+	require.NoError(t, err)
+	require.Equal(t, 5, l.count())
+	require.NoError(t, disabled.Invalidate(ctx, AllTag))
+}
+
+func TestSharedCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := newMemRemote()
+	syncer, err := newCache(testConfig, r)
+	require.NoError(t, err)
+	replica, err := newCache(testConfig, r)
+	require.NoError(t, err)
+	go syncer.Run(ctx)
+	go replica.Run(ctx)
+	subscribed := func(c *Cache) func() bool {
+		return func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.gens != nil
+		}
+	}
+	require.Eventually(t, subscribed(syncer), 5*time.Second, time.Millisecond)
+	require.Eventually(t, subscribed(replica), 5*time.Second, time.Millisecond)
+
+	addr := common.HexToAddress("0x1")
+	tags := []string{AccountTag(addr), TokenTag(0, common.Address{})}
+	l := &loader{value: "bridges"}
+	_, err = syncer.Load(ctx, "bridges", tags, l.load)
+	require.NoError(t, err)
+	// The replica reads the value cached by the other one
+	value, err := replica.Load(ctx, "bridges", tags, l.load)
+	require.NoError(t, err)
+	require.Equal(t, "bridges1", string(value))
+	require.Equal(t, 1, l.count())
+
+	// The hook of the syncer invalidates the entries of the replica
+	syncer.Hook().OnDepositIndexed(ctx, &etherman.Deposit{DestinationAddress: addr})
+	require.Eventually(t, func() bool {
+		value, err := replica.Load(ctx, "bridges", tags, l.load)
+		return err == nil && string(value) == "bridges2"
+	}, 5*time.Second, time.Millisecond)
+
+	// The reorgs invalidate all the tagged entries
+	syncer.Hook().OnReorg(ctx, 0, 10)
+	require.Eventually(t, func() bool {
+		value, err := replica.Load(ctx, "bridges", tags, l.load)
+		return err == nil && string(value) == "bridges3"
+	}, 5*time.Second, time.Millisecond)
+	value, err = syncer.Load(ctx, "bridges", tags, l.load)
+	require.NoError(t, err)
+	require.Equal(t, "bridges3", string(value))
+
+	// The lost subscriptions are closed before subscribing again
+	r.drop()
+	require.Eventually(t, func() bool {
+		subs, closed := r.subscriptions()
+		return subs == 2 && closed == 2
+	}, 5*time.Second, time.Millisecond)
+	syncer.Hook().OnDepositIndexed(ctx, &etherman.Deposit{DestinationAddress: addr})
+	require.Eventually(t, func() bool {
+		value, err := replica.Load(ctx, "bridges", tags, l.load)
+		return err == nil && string(value) == "bridges4"
+	}, 5*time.Second, time.Millisecond)
+}
@@ -0,0 +1,20 @@
+package cache
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+// Config is the configuration of the cache of the API responses
+type Config struct {
+	// Enabled caches the token metadata, the proof nodes and the account queries of the API
+	Enabled bool `mapstructure:"Enabled"`
+	// RedisURL is the redis shared by the API replicas, like redis://:password@localhost:6379/0. Empty keeps
+	// only the local cache of each replica
+	RedisURL string `mapstructure:"RedisURL"`
+	// Prefix is prepended to the redis keys and to the invalidation channel, so deployments can share a redis
+	Prefix string `mapstructure:"Prefix"`
+	// TTL is the expiration of the entries in redis
+	TTL types.Duration `mapstructure:"TTL"`
+	// LocalSize is the number of entries of the local cache of each replica
+	LocalSize int `mapstructure:"LocalSize"`
+	// Timeout bounds the redis commands, a slow redis is a cache miss
+	Timeout types.Duration `mapstructure:"Timeout"`
+}
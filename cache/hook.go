@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// invalidator invalidates the entries changed by the bridge events. The invalidations are queued, since the
+// hooks must return quickly, and they run after the data is committed.
+type invalidator struct {
+	hooks.NopHook
+	cache *Cache
+}
+
+// Hook returns the hook invalidating the entries on the bridge events, to be registered in the process running
+// the sync and claim loops.
+func (c *Cache) Hook() hooks.Hook {
+	return invalidator{cache: c}
+}
+
+// OnDepositIndexed implements hooks.Hook.
+func (i invalidator) OnDepositIndexed(_ context.Context, deposit *etherman.Deposit) {
+	i.cache.invalidateLater(depositTags(deposit)...)
+}
+
+// OnDepositReady implements hooks.Hook.
+func (i invalidator) OnDepositReady(_ context.Context, deposit *etherman.Deposit) {
+	i.cache.invalidateLater(depositTags(deposit)...)
+}
+
+// OnClaimIndexed implements hooks.Hook.
+func (i invalidator) OnClaimIndexed(_ context.Context, claim *etherman.Claim) {
+	i.cache.invalidateLater(AccountTag(claim.DestinationAddress))
+}
+
+// OnTokenWrapped implements hooks.Hook.
+func (i invalidator) OnTokenWrapped(_ context.Context, token *etherman.TokenWrapped) {
+	i.cache.invalidateLater(TokenTag(token.OriginalNetwork, token.OriginalTokenAddress))
+}
+
+// OnL2DepositReady implements hooks.Hook.
+func (i invalidator) OnL2DepositReady(_ context.Context, deposit *etherman.Deposit) {
+	i.cache.invalidateLater(depositTags(deposit)...)
+}
+
+// depositTags returns the tags of the queries of the destination address and the senders of a deposit.
+func depositTags(deposit *etherman.Deposit) []string {
+	tags := []string{AccountTag(deposit.DestinationAddress)}
+	for _, addr := range []common.Address{deposit.Sender, deposit.Depositor} {
+		if addr != (common.Address{}) {
+			tags = append(tags, AccountTag(addr))
+		}
+	}
+	return tags
+}
+
+// OnReorg implements hooks.Hook.
+func (i invalidator) OnReorg(context.Context, uint, uint64) {
+	i.cache.invalidateLater(AllTag)
+}
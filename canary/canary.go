@@ -0,0 +1,233 @@
+//go:build !readonly
+// +build !readonly
+
+// Package canary sends tiny real deposits periodically in both directions between L1 and L2 and claims them,
+// measuring the latency of the whole pipeline. It's the only end to end health signal of the bridge: the deposit
+// must be mined, synced, included in a global exit root, claimed and the claim synced.
+package canary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Directions of the canary deposits
+const (
+	DirectionL1ToL2 = "l1_to_l2"
+	DirectionL2ToL1 = "l2_to_l1"
+)
+
+// Stages of a canary round, measured from the time the deposit is sent
+const (
+	StageMined   = "mined"
+	StageIndexed = "indexed"
+	StageReady   = "ready"
+	StageClaimed = "claimed"
+)
+
+// Bridge sends the canary txs to the bridge of a network.
+type Bridge interface {
+	// Deposit sends a deposit of ether to the canary account in the destination network,
+	// returning its tx hash once it's mined
+	Deposit(ctx context.Context, destNetwork uint, amount *big.Int) (common.Hash, error)
+	// Claim sends the claim of a deposit, returning once it's mined
+	Claim(ctx context.Context, deposit *etherman.Deposit, proof [][bridgectrl.KeyLen]byte, ger *etherman.GlobalExitRoot) error
+}
+
+type storageInterface interface {
+	GetDepositsByTxHash(ctx context.Context, txHash common.Hash, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error)
+	GetClaim(ctx context.Context, depositCount, networkID uint, dbTx pgx.Tx) (*etherman.Claim, error)
+}
+
+type bridgeServiceInterface interface {
+	GetClaimProof(depositCnt, networkID uint, dbTx pgx.Tx) (*etherman.GlobalExitRoot, [][bridgectrl.KeyLen]byte, error)
+}
+
+// route is a direction of the canary deposits.
+type route struct {
+	direction   string
+	from, to    Bridge
+	fromNetwork uint
+	toNetwork   uint
+	timeout     time.Duration
+	// autoClaimed is set when the claim tx manager claims the deposits, so the canary only waits for the claim
+	autoClaimed bool
+}
+
+// Canary runs the canary rounds and exports their metrics. It implements prometheus.Collector.
+type Canary struct {
+	cfg           Config
+	storage       storageInterface
+	bridgeService bridgeServiceInterface
+	routes        []route
+	poll          wait.Waiter
+	now           func() time.Time
+
+	rounds      *prometheus.CounterVec
+	latency     *prometheus.HistogramVec
+	stages      *prometheus.GaugeVec
+	lastSuccess *prometheus.GaugeVec
+}
+
+// NewCanary creates the canary between L1 and a L2 network. autoClaimed is set when the claim tx manager
+// of the L2 network is enabled, so the L1 deposits are claimed by it instead of by the canary.
+func NewCanary(cfg Config, l1, l2 Bridge, l1NetworkID, l2NetworkID uint, autoClaimed bool, bridgeService bridgeServiceInterface, storage interface{}) *Canary {
+	labels := []string{"direction"}
+	return &Canary{
+		cfg:           cfg,
+		storage:       storage.(storageInterface),
+		bridgeService: bridgeService,
+		routes: []route{
+			{direction: DirectionL1ToL2, from: l1, to: l2, fromNetwork: l1NetworkID, toNetwork: l2NetworkID, timeout: cfg.L1ToL2Timeout.Duration, autoClaimed: autoClaimed},
+			{direction: DirectionL2ToL1, from: l2, to: l1, fromNetwork: l2NetworkID, toNetwork: l1NetworkID, timeout: cfg.L2ToL1Timeout.Duration},
+		},
+		poll: wait.Waiter{Interval: cfg.PollInterval.Duration},
+		now:  time.Now,
+		rounds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bridge_canary_rounds_total",
+			Help: "Number of canary rounds by direction and result",
+		}, []string{"direction", "result"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "bridge_canary_latency_seconds",
+			Help:    "Time from sending a canary deposit until its claim is synced",
+			Buckets: prometheus.ExponentialBuckets(30, 2, 10), //nolint:gomnd
+		}, labels),
+		stages: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "bridge_canary_stage_seconds",
+			Help: "Time from sending the last successful canary deposit until it reached each stage",
+		}, []string{"direction", "stage"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "bridge_canary_last_success_timestamp_seconds",
+			Help: "Unix time of the end of the last successful canary round",
+		}, labels),
+	}
+}
+
+// Start runs the canary rounds of both directions until the context is done.
+func (c *Canary) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, r := range c.routes {
+		wg.Add(1)
+		go func(r route) {
+			defer wg.Done()
+			for {
+				c.runRound(ctx, r)
+				if err := wait.Sleep(ctx, nil, c.cfg.Interval.Duration); err != nil {
+					return
+				}
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+// runRound runs a round and records its metrics.
+func (c *Canary) runRound(ctx context.Context, r route) {
+	stages, err := c.round(ctx, r)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		c.rounds.WithLabelValues(r.direction, "failure").Inc()
+		log.Warnf("canary %s round failed: %v", r.direction, err)
+		return
+	}
+	c.rounds.WithLabelValues(r.direction, "success").Inc()
+	for stage, d := range stages {
+		c.stages.WithLabelValues(r.direction, stage).Set(d.Seconds())
+	}
+	c.latency.WithLabelValues(r.direction).Observe(stages[StageClaimed].Seconds())
+	c.lastSuccess.WithLabelValues(r.direction).Set(float64(c.now().Unix()))
+	log.Infof("canary %s round succeeded in %s", r.direction, stages[StageClaimed])
+}
+
+// round sends a canary deposit and waits until its claim is synced, returning the time to reach each stage.
+func (c *Canary) round(ctx context.Context, r route) (map[string]time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	start := c.now()
+	stages := make(map[string]time.Duration)
+	txHash, err := r.from.Deposit(ctx, r.toNetwork, c.cfg.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("error sending the deposit: %w", err)
+	}
+	stages[StageMined] = c.now().Sub(start)
+
+	deposit, err := wait.Until(ctx, c.poll, func(ctx context.Context) (*etherman.Deposit, bool, error) {
+		deposits, err := c.storage.GetDepositsByTxHash(ctx, txHash, nil)
+		if errors.Is(err, gerror.ErrStorageNotFound) {
+			return nil, false, nil
+		} else if err != nil {
+			return nil, false, err
+		}
+		return deposits[0], true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for the deposit %s to be synced: %w", txHash.String(), err)
+	}
+	stages[StageIndexed] = c.now().Sub(start)
+
+	_, err = wait.Until(ctx, c.poll, func(ctx context.Context) (struct{}, bool, error) {
+		d, err := c.storage.GetDeposit(ctx, deposit.DepositCount, r.fromNetwork, nil)
+		if err != nil {
+			return struct{}{}, false, err
+		}
+		return struct{}{}, d.ReadyForClaim, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for the deposit %d to be ready for claim: %w", deposit.DepositCount, err)
+	}
+	stages[StageReady] = c.now().Sub(start)
+
+	if !r.autoClaimed {
+		ger, proof, err := c.bridgeService.GetClaimProof(deposit.DepositCount, r.fromNetwork, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the proof of the deposit %d: %w", deposit.DepositCount, err)
+		}
+		if err := r.to.Claim(ctx, deposit, proof, ger); err != nil {
+			return nil, fmt.Errorf("error claiming the deposit %d: %w", deposit.DepositCount, err)
+		}
+	}
+	_, err = wait.Until(ctx, c.poll, func(ctx context.Context) (struct{}, bool, error) {
+		_, err := c.storage.GetClaim(ctx, deposit.DepositCount, r.toNetwork, nil)
+		if errors.Is(err, gerror.ErrStorageNotFound) {
+			return struct{}{}, false, nil
+		}
+		return struct{}{}, err == nil, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for the claim of the deposit %d to be synced: %w", deposit.DepositCount, err)
+	}
+	stages[StageClaimed] = c.now().Sub(start)
+	return stages, nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *Canary) Describe(ch chan<- *prometheus.Desc) {
+	c.rounds.Describe(ch)
+	c.latency.Describe(ch)
+	c.stages.Describe(ch)
+	c.lastSuccess.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Canary) Collect(ch chan<- prometheus.Metric) {
+	c.rounds.Collect(ch)
+	c.latency.Collect(ch)
+	c.stages.Collect(ch)
+	c.lastSuccess.Collect(ch)
+}
@@ -0,0 +1,171 @@
+//go:build !readonly
+// +build !readonly
+
+package canary
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// network simulates the bridge of a network and the data synced from it.
+type network struct {
+	id      uint
+	chain   *chain
+	err     error
+	claimed []uint
+}
+
+// chain is the state shared by the networks: the synced deposits and claims.
+type chain struct {
+	mu       sync.Mutex
+	deposits map[common.Hash]*etherman.Deposit
+	claims   map[[2]uint]bool
+	// polls is the number of status checks before a deposit is ready for claim
+	polls       int
+	autoClaimed bool
+}
+
+func (n *network) Deposit(ctx context.Context, destNetwork uint, amount *big.Int) (common.Hash, error) {
+	if n.err != nil {
+		return common.Hash{}, n.err
+	}
+	n.chain.mu.Lock()
+	defer n.chain.mu.Unlock()
+	txHash := common.BigToHash(big.NewInt(int64(len(n.chain.deposits) + 1)))
+	n.chain.deposits[txHash] = &etherman.Deposit{
+		NetworkID:          n.id,
+		DestinationNetwork: destNetwork,
+		Amount:             amount,
+		DepositCount:       uint(len(n.chain.deposits)),
+		TxHash:             txHash,
+	}
+	return txHash, nil
+}
+
+func (n *network) Claim(ctx context.Context, deposit *etherman.Deposit, proof [][bridgectrl.KeyLen]byte, ger *etherman.GlobalExitRoot) error {
+	n.chain.mu.Lock()
+	defer n.chain.mu.Unlock()
+	n.claimed = append(n.claimed, deposit.DepositCount)
+	n.chain.claims[[2]uint{deposit.DepositCount, n.id}] = true
+	return nil
+}
+
+func (c *chain) GetDepositsByTxHash(ctx context.Context, txHash common.Hash, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deposit, found := c.deposits[txHash]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return []*etherman.Deposit{deposit}, nil
+}
+
+func (c *chain) GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range c.deposits {
+		if d.DepositCount != depositCnt || d.NetworkID != networkID {
+			continue
+		}
+		c.polls--
+		if c.polls <= 0 {
+			d.ReadyForClaim = true
+			// The claim tx manager claims the deposit once it's ready
+			if c.autoClaimed {
+				c.claims[[2]uint{d.DepositCount, d.DestinationNetwork}] = true
+			}
+		}
+		deposit := *d
+		return &deposit, nil
+	}
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (c *chain) GetClaim(ctx context.Context, depositCount, networkID uint, dbTx pgx.Tx) (*etherman.Claim, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.claims[[2]uint{depositCount, networkID}] {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &etherman.Claim{Index: depositCount, NetworkID: networkID}, nil
+}
+
+func (c *chain) GetClaimProof(depositCnt, networkID uint, dbTx pgx.Tx) (*etherman.GlobalExitRoot, [][bridgectrl.KeyLen]byte, error) {
+	return &etherman.GlobalExitRoot{}, make([][bridgectrl.KeyLen]byte, 32), nil //nolint:gomnd
+}
+
+func newTestCanary(autoClaimed bool) (*Canary, *chain, *network, *network) {
+	c := &chain{deposits: make(map[common.Hash]*etherman.Deposit), claims: make(map[[2]uint]bool), polls: 3, autoClaimed: autoClaimed}
+	l1, l2 := &network{id: 0, chain: c}, &network{id: 1, chain: c}
+	cfg := Config{
+		Amount:        big.NewInt(1000),
+		PollInterval:  types.NewDuration(time.Millisecond),
+		L1ToL2Timeout: types.NewDuration(time.Second),
+		L2ToL1Timeout: types.NewDuration(time.Second),
+	}
+	return NewCanary(cfg, l1, l2, 0, 1, autoClaimed, c, c), c, l1, l2
+}
+
+func TestRound(t *testing.T) {
+	ctx := context.Background()
+	canary, _, l1, l2 := newTestCanary(false)
+
+	// The canary claims the deposits of both directions
+	canary.runRound(ctx, canary.routes[0])
+	canary.runRound(ctx, canary.routes[1])
+	require.Equal(t, []uint{0}, l2.claimed)
+	require.Equal(t, []uint{1}, l1.claimed)
+	require.Equal(t, float64(1), testutil.ToFloat64(canary.rounds.WithLabelValues(DirectionL1ToL2, "success")))
+	require.Equal(t, float64(1), testutil.ToFloat64(canary.rounds.WithLabelValues(DirectionL2ToL1, "success")))
+	require.NotZero(t, testutil.ToFloat64(canary.lastSuccess.WithLabelValues(DirectionL1ToL2)))
+	for _, stage := range []string{StageMined, StageIndexed, StageReady, StageClaimed} {
+		require.Equal(t, 1, testutil.CollectAndCount(canary.stages.WithLabelValues(DirectionL1ToL2, stage)))
+	}
+
+	// A failed deposit fails the round
+	l1.err = errors.New("insufficient funds")
+	canary.runRound(ctx, canary.routes[0])
+	require.Equal(t, float64(1), testutil.ToFloat64(canary.rounds.WithLabelValues(DirectionL1ToL2, "failure")))
+}
+
+func TestRoundAutoClaimed(t *testing.T) {
+	ctx := context.Background()
+	canary, _, _, l2 := newTestCanary(true)
+
+	// The L1 deposit is claimed by the claim tx manager
+	canary.runRound(ctx, canary.routes[0])
+	require.Empty(t, l2.claimed)
+	require.Equal(t, float64(1), testutil.ToFloat64(canary.rounds.WithLabelValues(DirectionL1ToL2, "success")))
+}
+
+func TestRoundTimeout(t *testing.T) {
+	ctx := context.Background()
+	canary, c, _, _ := newTestCanary(false)
+	c.polls = 1 << 30
+	canary.routes[0].timeout = 20 * time.Millisecond
+
+	// The deposit is never ready for claim
+	canary.runRound(ctx, canary.routes[0])
+	require.Equal(t, float64(1), testutil.ToFloat64(canary.rounds.WithLabelValues(DirectionL1ToL2, "failure")))
+	expected := `
+# HELP bridge_canary_rounds_total Number of canary rounds by direction and result
+# TYPE bridge_canary_rounds_total counter
+bridge_canary_rounds_total{direction="l1_to_l2",result="failure"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(canary, strings.NewReader(expected), "bridge_canary_rounds_total"))
+}
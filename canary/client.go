@@ -0,0 +1,72 @@
+//go:build !readonly
+// +build !readonly
+
+package canary
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// client sends the canary txs through the node of a network.
+type client struct {
+	node *utils.Client
+	auth *bind.TransactOpts
+	// mu serializes the txs, the deposits and the claims of both directions share the account
+	mu sync.Mutex
+}
+
+// NewBridge creates the Bridge of a network, sending the txs from the account of the keystore.
+func NewBridge(ctx context.Context, nodeURL string, bridgeAddr common.Address, ks types.KeystoreFileConfig) (Bridge, error) {
+	node, err := utils.NewClient(ctx, nodeURL, bridgeAddr)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := node.GetSignerFromKeystore(ctx, ks)
+	if err != nil {
+		return nil, err
+	}
+	return &client{node: node, auth: auth}, nil
+}
+
+// Deposit implements Bridge.
+func (c *client) Deposit(ctx context.Context, destNetwork uint, amount *big.Int) (common.Hash, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	auth := *c.auth
+	auth.Context = ctx
+	tx, err := c.node.BridgeAsset(ctx, common.Address{}, amount, uint32(destNetwork), nil, nil, &auth)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// Claim implements Bridge.
+func (c *client) Claim(ctx context.Context, deposit *etherman.Deposit, proof [][bridgectrl.KeyLen]byte, ger *etherman.GlobalExitRoot) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var smtProof [32][bridgectrl.KeyLen]byte
+	copy(smtProof[:], proof)
+	auth := *c.auth
+	auth.Context = ctx
+	return c.node.SendClaim(ctx, &pb.Deposit{
+		LeafType:   uint32(deposit.LeafType),
+		OrigNet:    uint32(deposit.OriginalNetwork),
+		OrigAddr:   deposit.OriginalAddress.Hex(),
+		Amount:     deposit.Amount.String(),
+		DestNet:    uint32(deposit.DestinationNetwork),
+		DestAddr:   deposit.DestinationAddress.Hex(),
+		DepositCnt: uint64(deposit.DepositCount),
+		Metadata:   "0x" + common.Bytes2Hex(deposit.Metadata),
+	}, smtProof, ger, &auth)
+}
@@ -0,0 +1,27 @@
+package canary
+
+import (
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the canary deposits
+type Config struct {
+	// Enabled sends the canary deposits periodically in both directions between L1 and the first L2
+	Enabled bool `mapstructure:"Enabled"`
+	// Interval is the time between the end of a canary round and the next one
+	Interval types.Duration `mapstructure:"Interval"`
+	// PrivateKey defines the key store file of the dedicated account sending the canary deposits and
+	// claims. It must be funded in L1 and L2, and not used by anything else so its nonces are not shared
+	PrivateKey types.KeystoreFileConfig `mapstructure:"PrivateKey"`
+	// Amount is the amount of wei deposited in each canary deposit
+	Amount *big.Int `mapstructure:"Amount"`
+	// PollInterval is the time between each check of the status of a canary deposit
+	PollInterval types.Duration `mapstructure:"PollInterval"`
+	// L1ToL2Timeout is the max time for a L1 deposit to be claimed in L2 before the round fails
+	L1ToL2Timeout types.Duration `mapstructure:"L1ToL2Timeout"`
+	// L2ToL1Timeout is the max time for a L2 deposit to be claimed in L1 before the round fails. It includes
+	// the verification of the batch in L1, so it's much longer than the L1 to L2 one
+	L2ToL1Timeout types.Duration `mapstructure:"L2ToL1Timeout"`
+}
@@ -0,0 +1,255 @@
+// Package changefeed receives the changes of the deposits, claims and wrapped tokens notified by the synchronizer
+// database with LISTEN/NOTIFY, so the API replicas invalidate their cache and wake their subscribers as soon as the
+// changes are readable from their database, without polling the tables.
+package changefeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/cache"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// Tables of the changes
+const (
+	TableDeposit      = "deposit"
+	TableClaim        = "claim"
+	TableTokenWrapped = "token_wrapped"
+)
+
+// replayPollInterval is the time between two checks of the WAL position replayed by the read replica
+const replayPollInterval = 10 * time.Millisecond
+
+// Change is a change of a row notified by the database. A change without table is sent after the feed
+// reconnects, since the changes notified meanwhile are lost: any row may have changed.
+type Change struct {
+	Table string `json:"table"`
+	// Operation is INSERT, UPDATE or DELETE
+	Operation string `json:"op"`
+	NetworkID uint   `json:"network_id"`
+	// DepositCount is the deposit count of the deposit, or of the claimed deposit for the claims
+	DepositCount       uint           `json:"deposit_cnt"`
+	DestinationNetwork uint           `json:"dest_net"`
+	DestinationAddress common.Address `json:"dest_addr"`
+	// Sender and Depositor are only set for the deposits with their senders recorded
+	Sender    common.Address `json:"sender"`
+	Depositor common.Address `json:"depositor"`
+	// Ready is only set for the changes of the deposits that made them ready for claim
+	Ready bool `json:"ready"`
+	// OriginalNetwork and OriginalAddress are only set for the wrapped tokens
+	OriginalNetwork uint           `json:"orig_net"`
+	OriginalAddress common.Address `json:"orig_addr"`
+}
+
+// Tags returns the cache tags invalidated by the change.
+func (c Change) Tags() []string {
+	switch c.Table {
+	case TableDeposit, TableClaim:
+		tags := []string{cache.AccountTag(c.DestinationAddress)}
+		for _, addr := range []common.Address{c.Sender, c.Depositor} {
+			if addr != (common.Address{}) {
+				tags = append(tags, cache.AccountTag(addr))
+			}
+		}
+		return tags
+	case TableTokenWrapped:
+		return []string{cache.TokenTag(c.OriginalNetwork, c.OriginalAddress)}
+	default:
+		return []string{cache.AllTag}
+	}
+}
+
+type sourceStorage interface {
+	ListenChanges(ctx context.Context, handle func(payload string)) error
+	GetCurrentLSN(ctx context.Context, dbTx pgx.Tx) (string, error)
+}
+
+type replicaStorage interface {
+	IsLSNReplayed(ctx context.Context, lsn string, dbTx pgx.Tx) (bool, error)
+}
+
+// Feed listens to the changes of the synchronizer database and dispatches them once they are replayed by the
+// database of the API.
+type Feed struct {
+	cfg     Config
+	source  sourceStorage
+	replica replicaStorage
+	cache   *cache.Cache
+
+	mu      sync.Mutex
+	pending []Change
+	notify  chan struct{}
+
+	subsMu sync.Mutex
+	subs   map[*Subscription]struct{}
+}
+
+// NewFeed creates the change feed of the changes notified by the source storage, the synchronizer database, read
+// from the replica storage, the database of the API.
+func NewFeed(cfg Config, source, replica interface{}) *Feed {
+	return &Feed{
+		cfg:     cfg,
+		source:  source.(sourceStorage),
+		replica: replica.(replicaStorage),
+		notify:  make(chan struct{}, 1),
+		subs:    make(map[*Subscription]struct{}),
+	}
+}
+
+// SetCache sets the cache invalidated by the changes.
+func (f *Feed) SetCache(c *cache.Cache) {
+	f.cache = c
+}
+
+// Start listens to the changes until the context is done, listening again when the connection is lost.
+func (f *Feed) Start(ctx context.Context) {
+	go f.dispatch(ctx)
+	for connected := false; ; connected = true {
+		if connected {
+			f.receive(Change{})
+		}
+		err := f.source.ListenChanges(ctx, func(payload string) {
+			var change Change
+			if err := json.Unmarshal([]byte(payload), &change); err != nil {
+				log.Warnf("invalid change notified %s: %v", payload, err)
+				return
+			}
+			f.receive(change)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warnf("change feed connection lost, listening again in %s: %v", f.cfg.ReconnectInterval.Duration, err)
+		if wait.Sleep(ctx, wait.RealClock, f.cfg.ReconnectInterval.Duration) != nil {
+			return
+		}
+	}
+}
+
+// receive queues a change, the queued changes are dispatched together.
+func (f *Feed) receive(change Change) {
+	f.mu.Lock()
+	f.pending = append(f.pending, change)
+	f.mu.Unlock()
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (f *Feed) dispatch(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.notify:
+		}
+		f.mu.Lock()
+		changes := f.pending
+		f.pending = nil
+		f.mu.Unlock()
+		// The changes were committed before they were notified, so they are replayed once the replica reaches the
+		// current position of the source
+		if err := f.waitReplayed(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnf("error waiting for the API database to replay %d changes, dispatching them: %v", len(changes), err)
+		}
+		if err := f.cache.Invalidate(ctx, tags(changes)...); err != nil {
+			log.Errorf("error invalidating the cache on %d changes, they are invalidated when their entries expire: %v", len(changes), err)
+		}
+		f.publish(changes)
+	}
+}
+
+func (f *Feed) waitReplayed(ctx context.Context) error {
+	lsn, err := f.source.GetCurrentLSN(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = wait.Poll(ctx, replayPollInterval, f.cfg.ReplicaWait.Duration, func() (bool, error) {
+		return f.replica.IsLSNReplayed(ctx, lsn, nil)
+	})
+	if errors.Is(err, wait.ErrTimeout) {
+		log.Debugf("the API database didn't replay the position %s in %s", lsn, f.cfg.ReplicaWait.Duration)
+		return nil
+	}
+	return err
+}
+
+// tags returns the cache tags of the changes, without duplicates.
+func tags(changes []Change) []string {
+	set := make(map[string]struct{})
+	for _, change := range changes {
+		for _, tag := range change.Tags() {
+			set[tag] = struct{}{}
+		}
+	}
+	tags := make([]string, 0, len(set))
+	for tag := range set {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func (f *Feed) publish(changes []Change) {
+	f.subsMu.Lock()
+	defer f.subsMu.Unlock()
+	for sub := range f.subs {
+		for _, change := range changes {
+			select {
+			case sub.ch <- change:
+			default:
+				sub.dropped++
+			}
+		}
+	}
+}
+
+// Subscription receives the changes dispatched by the feed until it's closed.
+type Subscription struct {
+	feed    *Feed
+	ch      chan Change
+	dropped uint64
+}
+
+// Subscribe subscribes to the changes dispatched from now on.
+func (f *Feed) Subscribe() *Subscription {
+	sub := &Subscription{feed: f, ch: make(chan Change, f.cfg.SubscriberBuffer)}
+	f.subsMu.Lock()
+	f.subs[sub] = struct{}{}
+	f.subsMu.Unlock()
+	return sub
+}
+
+// Changes returns the channel of the changes.
+func (s *Subscription) Changes() <-chan Change {
+	return s.ch
+}
+
+// Dropped returns the number of changes dropped while the buffer of the subscription was full.
+func (s *Subscription) Dropped() uint64 {
+	s.feed.subsMu.Lock()
+	defer s.feed.subsMu.Unlock()
+	return s.dropped
+}
+
+// Close stops receiving the changes.
+func (s *Subscription) Close() {
+	s.feed.subsMu.Lock()
+	delete(s.feed.subs, s)
+	s.feed.subsMu.Unlock()
+	if s.dropped > 0 {
+		log.Debugf("%d changes dropped for a subscriber with the buffer full", s.dropped)
+	}
+}
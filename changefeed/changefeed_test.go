@@ -0,0 +1,154 @@
+package changefeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/cache"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// memStorage notifies the payloads sent by the test, and is replayed by the replica once the test allows it.
+type memStorage struct {
+	payloads chan string
+	// lost makes the current listen return, as a lost connection
+	lost chan struct{}
+
+	mu       sync.Mutex
+	replayed bool
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{payloads: make(chan string), lost: make(chan struct{}), replayed: true}
+}
+
+func (s *memStorage) ListenChanges(ctx context.Context, handle func(payload string)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.lost:
+			return errors.New("connection lost")
+		case payload := <-s.payloads:
+			handle(payload)
+		}
+	}
+}
+
+func (s *memStorage) GetCurrentLSN(ctx context.Context, dbTx pgx.Tx) (string, error) {
+	return "0/1", nil
+}
+
+func (s *memStorage) IsLSNReplayed(ctx context.Context, lsn string, dbTx pgx.Tx) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.replayed, nil
+}
+
+func (s *memStorage) setReplayed(replayed bool) {
+	s.mu.Lock()
+	s.replayed = replayed
+	s.mu.Unlock()
+}
+
+func receive(t *testing.T, sub *Subscription) Change {
+	select {
+	case change := <-sub.Changes():
+		return change
+	case <-time.After(5 * time.Second):
+		t.Fatal("change not received")
+		return Change{}
+	}
+}
+
+func TestFeed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	storage := newMemStorage()
+	cfg := Config{ReplicaWait: types.NewDuration(time.Minute), ReconnectInterval: types.NewDuration(time.Millisecond), SubscriberBuffer: 10}
+	c, err := cache.New(cache.Config{LocalSize: 10})
+	require.NoError(t, err)
+	feed := NewFeed(cfg, storage, storage)
+	feed.SetCache(c)
+	sub := feed.Subscribe()
+	defer sub.Close()
+	go feed.Start(ctx)
+
+	addr := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	loads := 0
+	load := func() {
+		_, err := c.Load(ctx, "bridges", []string{cache.AccountTag(addr)}, func() ([]byte, error) {
+			loads++
+			return []byte("deposits"), nil
+		})
+		require.NoError(t, err)
+	}
+	load()
+
+	// The change is dispatched once the replica replays it, invalidating the account
+	storage.setReplayed(false)
+	storage.payloads <- `{"table": "deposit", "op": "INSERT", "network_id": 0, "deposit_cnt": 1, "dest_net": 1,
+		"dest_addr": "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"}`
+	select {
+	case <-sub.Changes():
+		t.Fatal("change dispatched before it's replayed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	load()
+	require.Equal(t, 1, loads)
+	storage.setReplayed(true)
+	change := receive(t, sub)
+	require.Equal(t, TableDeposit, change.Table)
+	require.Equal(t, uint(1), change.DepositCount)
+	require.Equal(t, addr, change.DestinationAddress)
+	load()
+	require.Equal(t, 2, loads)
+
+	// The invalid payloads are ignored
+	storage.payloads <- "invalid"
+	storage.payloads <- `{"table": "token_wrapped", "op": "INSERT", "network_id": 1, "orig_net": 0, "orig_addr": "0x0000000000000000000000000000000000000001"}`
+	change = receive(t, sub)
+	require.Equal(t, TableTokenWrapped, change.Table)
+	require.Equal(t, []string{cache.TokenTag(0, common.HexToAddress("0x01"))}, change.Tags())
+
+	// A lost connection invalidates everything, since the changes meanwhile are lost
+	storage.lost <- struct{}{}
+	change = receive(t, sub)
+	require.Equal(t, "", change.Table)
+	require.Equal(t, []string{cache.AllTag}, change.Tags())
+	load()
+	require.Equal(t, 3, loads)
+
+	// The closed subscriptions don't receive the changes
+	sub.Close()
+	other := feed.Subscribe()
+	defer other.Close()
+	storage.payloads <- `{"table": "claim", "op": "INSERT", "network_id": 1, "deposit_cnt": 1, "dest_addr": "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"}`
+	require.Equal(t, TableClaim, receive(t, other).Table)
+	require.Empty(t, sub.Changes())
+}
+
+func TestTags(t *testing.T) {
+	addr := common.HexToAddress("0x01")
+	changes := []Change{
+		{Table: TableDeposit, DestinationAddress: addr},
+		{Table: TableClaim, DestinationAddress: addr},
+		{Table: TableTokenWrapped, OriginalNetwork: 1, OriginalAddress: addr},
+	}
+	require.Equal(t, []string{cache.AccountTag(addr), cache.TokenTag(1, addr)}, tags(changes))
+
+	// The queries of the recorded senders of a deposit are invalidated too
+	sender := common.HexToAddress("0x02")
+	require.Equal(t, []string{cache.AccountTag(addr), cache.AccountTag(sender)}, Change{Table: TableDeposit, DestinationAddress: addr, Sender: sender}.Tags())
+	var change Change
+	require.NoError(t, json.Unmarshal([]byte(`{"table": "deposit", "dest_addr": "0x0000000000000000000000000000000000000001",
+		"sender": "0x0000000000000000000000000000000000000000", "depositor": "0x0000000000000000000000000000000000000002"}`), &change))
+	require.Equal(t, []string{cache.AccountTag(addr), cache.AccountTag(sender)}, change.Tags())
+}
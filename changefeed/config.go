@@ -0,0 +1,17 @@
+package changefeed
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+// Config is the configuration of the change feed of the API
+type Config struct {
+	// Enabled listens to the changes notified by the synchronizer database, that must have ChangeNotify enabled
+	Enabled bool `mapstructure:"Enabled"`
+	// ReplicaWait is the max time waiting for the database of the API to replay the changes, when it's a read
+	// replica, before invalidating the cache and waking the subscribers
+	ReplicaWait types.Duration `mapstructure:"ReplicaWait"`
+	// ReconnectInterval is the time between two attempts to listen again after the connection is lost
+	ReconnectInterval types.Duration `mapstructure:"ReconnectInterval"`
+	// SubscriberBuffer is the number of changes buffered for each subscriber, the changes are dropped for the
+	// subscribers whose buffer is full
+	SubscriberBuffer int `mapstructure:"SubscriberBuffer"`
+}
@@ -0,0 +1,162 @@
+//go:build !readonly
+// +build !readonly
+
+package claimtxman
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	breakerOpenDesc = prometheus.NewDesc("bridge_claim_breaker_open",
+		"1 if the circuit breaker of the claim txs of the network is open or half open, 0 if it's closed", []string{"network_id"}, nil)
+	breakerFailuresDesc = prometheus.NewDesc("bridge_claim_breaker_failures",
+		"Number of consecutive failed cycles of the claim txs of the network", []string{"network_id"}, nil)
+)
+
+// breaker is the circuit breaker of the claim txs of a network. It's opened after threshold consecutive failed
+// cycles, so a network that keeps failing is not called again until the cooldown expires. A breaker with a
+// threshold of 0 is never opened.
+type breaker struct {
+	mu        sync.Mutex
+	networkID uint
+	threshold int
+	cooldown  time.Duration
+	state     ctmtypes.BreakerState
+}
+
+func newBreaker(networkID uint, threshold int, cooldown time.Duration) *breaker {
+	return &breaker{
+		networkID: networkID,
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     ctmtypes.BreakerState{NetworkID: networkID, State: ctmtypes.BreakerStateClosed},
+	}
+}
+
+// allow checks if the network can be called at now, half opening the breaker once its cooldown expired.
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state.State != ctmtypes.BreakerStateOpen {
+		return true
+	}
+	if now.Before(b.state.RetryAt) {
+		return false
+	}
+	log.Infof("claim breaker of the network %d half opened, probing the network", b.networkID)
+	b.state.State = ctmtypes.BreakerStateHalfOpen
+	return true
+}
+
+// record records the result of a cycle. The cycles without calls to the network don't change the breaker.
+func (b *breaker) record(c breakerCycle, now time.Time) {
+	if c.succeeded > 0 {
+		b.success()
+	} else if c.failed > 0 {
+		b.failure(c.err, now)
+	}
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state.State != ctmtypes.BreakerStateClosed {
+		log.Infof("claim breaker of the network %d closed", b.networkID)
+	}
+	b.state = ctmtypes.BreakerState{NetworkID: b.networkID, State: ctmtypes.BreakerStateClosed}
+}
+
+func (b *breaker) failure(err error, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state.Failures++
+	if err != nil {
+		b.state.LastError = err.Error()
+	}
+	if b.threshold <= 0 {
+		return
+	}
+	if b.state.State == ctmtypes.BreakerStateHalfOpen || b.state.State == ctmtypes.BreakerStateClosed && b.state.Failures >= b.threshold {
+		log.Warnf("claim breaker of the network %d opened for %s after %d failed cycles, last error: %s", b.networkID, b.cooldown, b.state.Failures, b.state.LastError)
+		b.state.State = ctmtypes.BreakerStateOpen
+		b.state.OpenedAt = now
+		b.state.RetryAt = now.Add(b.cooldown)
+	}
+}
+
+func (b *breaker) snapshot() ctmtypes.BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// breakerCycle counts the calls to the network that succeeded and failed in a cycle. A cycle fails when some
+// calls failed and none succeeded.
+type breakerCycle struct {
+	succeeded int
+	failed    int
+	err       error
+}
+
+// breakerCycleOf is the cycle of a single call to the network that returned err.
+func breakerCycleOf(err error) breakerCycle {
+	var c breakerCycle
+	if err != nil {
+		c.fail(err)
+	} else {
+		c.succeed()
+	}
+	return c
+}
+
+func (c *breakerCycle) succeed() {
+	c.succeeded++
+}
+
+func (c *breakerCycle) fail(err error) {
+	c.failed++
+	c.err = err
+}
+
+// BreakerState returns the state of the circuit breaker of the claim txs of the network.
+func (tm *ClaimTxManager) BreakerState() ctmtypes.BreakerState {
+	return tm.breaker.snapshot()
+}
+
+// BreakerCollector exports the circuit breakers of the claim txs of all the networks, labelled by network id, so
+// the shared descriptors are registered once.
+type BreakerCollector struct {
+	managers []*ClaimTxManager
+}
+
+// NewBreakerCollector creates the collector of the breakers of the claim tx managers.
+func NewBreakerCollector(managers []*ClaimTxManager) *BreakerCollector {
+	return &BreakerCollector{managers: managers}
+}
+
+// Describe implements prometheus.Collector.
+func (c *BreakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- breakerOpenDesc
+	ch <- breakerFailuresDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *BreakerCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, tm := range c.managers {
+		state := tm.breaker.snapshot()
+		networkID := strconv.FormatUint(uint64(state.NetworkID), 10)
+		var open float64
+		if state.State != ctmtypes.BreakerStateClosed {
+			open = 1
+		}
+		ch <- prometheus.MustNewConstMetric(breakerOpenDesc, prometheus.GaugeValue, open, networkID)
+		ch <- prometheus.MustNewConstMetric(breakerFailuresDesc, prometheus.GaugeValue, float64(state.Failures), networkID)
+	}
+}
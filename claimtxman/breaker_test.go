@@ -0,0 +1,73 @@
+//go:build !readonly
+// +build !readonly
+
+package claimtxman
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	b := newBreaker(1, 2, time.Minute)
+	errRPC := errors.New("connection refused")
+
+	// The cycles without calls and the failures of the cycles with successes don't open the breaker
+	b.record(breakerCycle{}, now)
+	b.record(breakerCycle{succeeded: 1, failed: 3, err: errRPC}, now)
+	require.Equal(t, ctmtypes.BreakerState{NetworkID: 1, State: ctmtypes.BreakerStateClosed}, b.snapshot())
+	b.record(breakerCycleOf(errRPC), now)
+	require.True(t, b.allow(now))
+	b.record(breakerCycleOf(errRPC), now)
+	state := b.snapshot()
+	require.Equal(t, ctmtypes.BreakerStateOpen, state.State)
+	require.Equal(t, 2, state.Failures)
+	require.Equal(t, errRPC.Error(), state.LastError)
+	require.Equal(t, now, state.OpenedAt)
+	require.Equal(t, now.Add(time.Minute), state.RetryAt)
+	require.False(t, b.allow(now.Add(time.Second)))
+
+	// A failed probe opens it again, and a successful one closes it
+	require.True(t, b.allow(now.Add(time.Minute)))
+	require.Equal(t, ctmtypes.BreakerStateHalfOpen, b.snapshot().State)
+	b.record(breakerCycleOf(errRPC), now.Add(time.Minute))
+	require.Equal(t, ctmtypes.BreakerStateOpen, b.snapshot().State)
+	require.False(t, b.allow(now.Add(time.Minute+time.Second)))
+	require.True(t, b.allow(now.Add(2*time.Minute)))
+	b.record(breakerCycleOf(nil), now.Add(2*time.Minute))
+	require.Equal(t, ctmtypes.BreakerState{NetworkID: 1, State: ctmtypes.BreakerStateClosed}, b.snapshot())
+
+	// A disabled breaker counts the failures but is never opened
+	b = newBreaker(2, 0, time.Minute)
+	for i := 0; i < 10; i++ {
+		b.record(breakerCycleOf(errRPC), now)
+	}
+	require.Equal(t, ctmtypes.BreakerStateClosed, b.snapshot().State)
+	require.Equal(t, 10, b.snapshot().Failures)
+	require.True(t, b.allow(now))
+}
+
+func TestBreakerCollector(t *testing.T) {
+	first := &ClaimTxManager{breaker: newBreaker(1, 1, time.Minute)}
+	second := &ClaimTxManager{breaker: newBreaker(2, 1, time.Minute)}
+	second.breaker.record(breakerCycleOf(errors.New("connection refused")), time.Unix(1700000000, 0))
+
+	// The breakers of all the networks are registered once
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(NewBreakerCollector([]*ClaimTxManager{first, second})))
+	expected := `
+# HELP bridge_claim_breaker_open 1 if the circuit breaker of the claim txs of the network is open or half open, 0 if it's closed
+# TYPE bridge_claim_breaker_open gauge
+bridge_claim_breaker_open{network_id="1"} 0
+bridge_claim_breaker_open{network_id="2"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "bridge_claim_breaker_open"))
+}
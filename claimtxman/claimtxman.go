@@ -1,16 +1,28 @@
+//go:build !readonly
+// +build !readonly
+
 package claimtxman
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-bridge-service/activity"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/blocktime"
 	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/gerlatency"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/maintenance"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/utils"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/ethereum/go-ethereum"
@@ -27,28 +39,59 @@ const (
 	mtHeight        = 32
 	cacheSize       = 1000
 	LeafTypeMessage = uint8(1)
+	// gasPriceMultiplier multiplies the suggested gas price to increase the efficiency of the tx in the sequence
+	gasPriceMultiplier = 10
+	// claimBitMapWordBits is the number of deposits of each word of the claim bitmap of the bridge
+	claimBitMapWordBits = 256
 )
 
+// unsentStatuses are the statuses of the claim txs that can still be sent, denied if their token is denied.
+var unsentStatuses = []ctmtypes.MonitoredTxStatus{
+	ctmtypes.MonitoredTxStatusCreated, ctmtypes.MonitoredTxStatusPendingApproval, ctmtypes.MonitoredTxStatusApproved,
+	ctmtypes.MonitoredTxStatusPendingSignature, ctmtypes.MonitoredTxStatusSigned, ctmtypes.MonitoredTxStatusUneconomical,
+}
+
 // ClaimTxManager is the claim transaction manager for L2.
 type ClaimTxManager struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	// client is the ethereum client
-	l2Node          *utils.Client
-	l2NetworkID     uint
-	bridgeService   bridgeServiceInterface
-	cfg             Config
-	chExitRootEvent chan *etherman.GlobalExitRoot
-	chSynced        chan uint
-	storage         storageInterface
-	auth            *bind.TransactOpts
-	nonceCache      *lru.Cache[string, uint64]
-	synced          bool
+	// l2Node is the client of the L2 network
+	l2Node         l2NodeInterface
+	l2NetworkID    uint
+	bridgeService  bridgeServiceInterface
+	cfg            Config
+	exitRootEvents *queue.Queue[*etherman.GlobalExitRoot]
+	chSynced       chan uint
+	storage        storageInterface
+	auth           *bind.TransactOpts
+	nonceCache     *lru.Cache[string, uint64]
+	relay          *privateRelay
+	// updates are the exit roots waiting for the workers updating the status of their deposits
+	updates *queue.Queue[*etherman.GlobalExitRoot]
+	// offline exports the claim txs to be signed offline, nil when they are signed with the PrivateKey
+	offline  *offlineSigner
+	gerCache *lru.Cache[common.Hash, bool]
+	// clock is the source of time of the monitoring, the retries and the breaker, a fake one in the tests
+	clock wait.Clock
+	// retry is the waiter of the retried L2 calls, on the clock
+	retry wait.Waiter
+	// breaker stops the claim txs of the network while they keep failing
+	breaker *breaker
+	// maintenance pauses the claim txs during the maintenances, nil when it's not available
+	maintenance *maintenance.Monitor
+	// prices values the deposits to park the uneconomical claims, nil when their value is not checked
+	prices *priceFeed
+	// approvalThresholds are the ApprovalThresholds of the tokens
+	approvalThresholds map[priceKey]*big.Int
+	synced             bool
 }
 
 // NewClaimTxManager creates a new claim transaction manager.
-func NewClaimTxManager(cfg Config, chExitRootEvent chan *etherman.GlobalExitRoot, chSynced chan uint, l2NodeURL string, l2NetworkID uint, l2BridgeAddr common.Address, bridgeService bridgeServiceInterface, storage interface{}) (*ClaimTxManager, error) {
+func NewClaimTxManager(cfg Config, exitRootEvents *queue.Queue[*etherman.GlobalExitRoot], chSynced chan uint, l2NodeURL string, l2NetworkID uint, l2BridgeAddr common.Address, bridgeService bridgeServiceInterface, storage interface{}) (*ClaimTxManager, error) {
+	if err := cfg.AdaptiveMonitorInterval.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid adaptive monitor interval: %w", err)
+	}
 	ctx := context.Background()
 	client, err := utils.NewClient(ctx, l2NodeURL, l2BridgeAddr)
 	if err != nil {
@@ -58,20 +101,67 @@ func NewClaimTxManager(cfg Config, chExitRootEvent chan *etherman.GlobalExitRoot
 	if err != nil {
 		return nil, err
 	}
+	gerCache, err := lru.New[common.Hash, bool](int(cacheSize))
+	if err != nil {
+		return nil, err
+	}
+	relay, err := newPrivateRelay(cfg.PrivateRelayURL, cfg.PrivateRelayTimeout.Duration)
+	if err != nil {
+		return nil, err
+	}
+	attempts := cfg.RetryNumber
+	if attempts < 1 {
+		attempts = 1
+	}
+	offline, err := newOfflineSigner(cfg.OfflineSigningDir, cfg.OfflineSignerAddress)
+	if err != nil {
+		return nil, err
+	}
+	updates, err := queue.New[*etherman.GlobalExitRoot](fmt.Sprintf("claim_updates_%d", l2NetworkID), cfg.UpdateQueue, nil)
+	if err != nil {
+		return nil, err
+	}
+	var prices *priceFeed
+	if cfg.MinClaimValue.Enabled {
+		if prices, err = newPriceFeed(cfg.MinClaimValue); err != nil {
+			return nil, fmt.Errorf("invalid min claim value: %w", err)
+		}
+	}
+	approvalThresholds, err := parseApprovalThresholds(cfg.ApprovalThresholds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval thresholds: %w", err)
+	}
+	if err := cfg.DynamicFee.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid dynamic fee: %w", err)
+	}
 	ctx, cancel := context.WithCancel(ctx)
-	auth, err := client.GetSignerFromKeystore(ctx, cfg.PrivateKey)
+	var auth *bind.TransactOpts
+	if offline != nil {
+		auth = offline.transactOpts()
+	} else {
+		auth, err = client.GetSignerFromKeystore(ctx, cfg.PrivateKey)
+	}
 	return &ClaimTxManager{
-		ctx:             ctx,
-		cancel:          cancel,
-		l2Node:          client,
-		l2NetworkID:     l2NetworkID,
-		bridgeService:   bridgeService,
-		cfg:             cfg,
-		chExitRootEvent: chExitRootEvent,
-		chSynced:        chSynced,
-		storage:         storage.(storageInterface),
-		auth:            auth,
-		nonceCache:      cache,
+		ctx:                ctx,
+		cancel:             cancel,
+		l2Node:             client,
+		l2NetworkID:        l2NetworkID,
+		bridgeService:      bridgeService,
+		cfg:                cfg,
+		exitRootEvents:     exitRootEvents,
+		chSynced:           chSynced,
+		updates:            updates,
+		storage:            storage.(storageInterface),
+		auth:               auth,
+		nonceCache:         cache,
+		relay:              relay,
+		offline:            offline,
+		gerCache:           gerCache,
+		clock:              wait.RealClock,
+		retry:              wait.Waiter{Interval: cfg.RetryInterval.Duration, Attempts: attempts, Clock: wait.RealClock},
+		breaker:            newBreaker(l2NetworkID, cfg.BreakerThreshold, cfg.BreakerCooldown.Duration),
+		prices:             prices,
+		approvalThresholds: approvalThresholds,
 	}, err
 }
 
@@ -79,7 +169,15 @@ func NewClaimTxManager(cfg Config, chExitRootEvent chan *etherman.GlobalExitRoot
 // send then to the blockchain and keep monitoring them until they
 // get mined
 func (tm *ClaimTxManager) Start() {
-	ticker := time.NewTicker(tm.cfg.FrequencyToMonitorTxs.Duration)
+	workers := tm.cfg.UpdateWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go tm.updateWorker()
+	}
+	// The monitoring is scheduled on the clock instead of a ticker, so the loop runs on the fake clocks of the tests
+	monitor := tm.clock.After(tm.monitorInterval())
 	for {
 		select {
 		case <-tm.ctx.Done():
@@ -89,19 +187,31 @@ func (tm *ClaimTxManager) Start() {
 				log.Info("NetworkID synced: ", netID)
 				tm.synced = true
 			}
-		case ger := <-tm.chExitRootEvent:
-			if tm.synced {
+		case ger := <-tm.exitRootEvents.C():
+			// The L1 deposits of the exit roots skipped while the breaker is open are updated with the next one
+			if tm.synced && ger.BlockID == 0 && !tm.breaker.allow(tm.clock.Now()) {
+				log.Warnf("claim breaker of the network %d is open, skipping the L1 exit root %s", tm.l2NetworkID, ger.GlobalExitRoot.String())
+			} else if tm.synced && ger.BlockID == 0 && tm.maintenance.Active(tm.ctx) != nil {
+				log.Infof("maintenance in progress, skipping the L1 exit root %s of the network %d", ger.GlobalExitRoot.String(), tm.l2NetworkID)
+			} else if tm.synced {
 				log.Debug("UpdateDepositsStatus for ger: ", ger.GlobalExitRoot)
-				go func() {
-					err := tm.updateDepositsStatus(ger)
-					if err != nil {
-						log.Errorf("failed to update deposits status: %v", err)
-					}
-				}()
+				if !tm.updates.Push(tm.ctx, ger) {
+					log.Warnf("claim updates queue of the network %d full, dropping the exit root %s", tm.l2NetworkID, ger.GlobalExitRoot.String())
+				}
 			} else {
 				log.Infof("Waiting for networkID %d to be synced before processing deposits", tm.l2NetworkID)
 			}
-		case <-ticker.C:
+		case <-monitor:
+			// The block time of the network is measured by its synchronizer, it may change while running
+			monitor = tm.clock.After(tm.monitorInterval())
+			if !tm.breaker.allow(tm.clock.Now()) {
+				log.Debugf("claim breaker of the network %d is open, skipping the monitored txs", tm.l2NetworkID)
+				continue
+			}
+			if tm.maintenance.Active(tm.ctx) != nil {
+				log.Debugf("maintenance in progress, skipping the monitored txs of the network %d", tm.l2NetworkID)
+				continue
+			}
 			err := tm.monitorTxs(tm.ctx)
 			if err != nil {
 				log.Errorf("failed to monitor txs: %v", err)
@@ -110,12 +220,55 @@ func (tm *ClaimTxManager) Start() {
 	}
 }
 
+// SetMaintenanceMonitor pauses the claim txs during the maintenances. The L1 deposits of the exit roots skipped
+// meanwhile are updated with the next one.
+func (tm *ClaimTxManager) SetMaintenanceMonitor(monitor *maintenance.Monitor) {
+	tm.maintenance = monitor
+}
+
+// updateWorker updates the status of the deposits of the queued exit roots until the manager is stopped.
+func (tm *ClaimTxManager) updateWorker() {
+	for {
+		select {
+		case <-tm.ctx.Done():
+			return
+		case ger := <-tm.updates.C():
+			err := tm.updateDepositsStatus(ger)
+			if err != nil {
+				log.Errorf("failed to update deposits status: %v", err)
+			}
+			// Only the L1 exit roots call the network, to create the claim txs
+			if ger.BlockID == 0 {
+				tm.breaker.record(breakerCycleOf(err), tm.clock.Now())
+			}
+		}
+	}
+}
+
+// monitorInterval returns the time between the monitoring of the txs, the observed block time of the L2 network
+// if the adaptive interval is enabled.
+func (tm *ClaimTxManager) monitorInterval() time.Duration {
+	return blocktime.Interval(tm.cfg.AdaptiveMonitorInterval, tm.l2NetworkID, tm.cfg.FrequencyToMonitorTxs.Duration)
+}
+
 func (tm *ClaimTxManager) updateDepositsStatus(ger *etherman.GlobalExitRoot) error {
+	// The L1 deposits are not ready until the L2 can verify the claims against the exit root.
+	// The L2 exit roots come from the L1 events, so they are already available in L1.
+	if ger.BlockID == 0 {
+		if err := tm.waitGlobalExitRoot(ger.GlobalExitRoot); err != nil {
+			return err
+		}
+	}
+	// The claim bitmap is read before the db tx, so its retries don't hold the tx open
+	claimed, err := tm.claimedDeposits(ger)
+	if err != nil {
+		return err
+	}
 	dbTx, err := tm.storage.BeginDBTransaction(tm.ctx)
 	if err != nil {
 		return err
 	}
-	err = tm.processDepositStatus(ger, dbTx)
+	readyDeposits, err := tm.processDepositStatus(ger, claimed, dbTx)
 	if err != nil {
 		log.Errorf("error processing ger. Error: %v", err)
 		rollbackErr := tm.storage.Rollback(tm.ctx, dbTx)
@@ -134,61 +287,195 @@ func (tm *ClaimTxManager) updateDepositsStatus(ger *etherman.GlobalExitRoot) err
 		}
 		log.Fatalf("AddClaimTx committing dbTx, err: %s", err.Error())
 	}
+	for _, deposit := range readyDeposits {
+		if ger.BlockID != 0 {
+			hooks.L2DepositReady(tm.ctx, deposit)
+		} else {
+			hooks.DepositReady(tm.ctx, deposit)
+		}
+	}
+	return nil
+}
+
+// waitGlobalExitRoot waits until the global exit root is available in the L2 global exit root manager.
+func (tm *ClaimTxManager) waitGlobalExitRoot(globalExitRoot common.Hash) error {
+	if tm.gerCache.Contains(globalExitRoot) {
+		return nil
+	}
+	_, err := wait.Until(tm.ctx, tm.retry, func(ctx context.Context) (struct{}, bool, error) {
+		available, err := tm.l2Node.IsGlobalExitRootAvailable(ctx, globalExitRoot)
+		if err != nil {
+			log.Warnf("error checking if the global exit root %s is available in L2. Error: %v", globalExitRoot.String(), err)
+		}
+		return struct{}{}, available, nil
+	})
+	if err != nil {
+		return fmt.Errorf("global exit root %s not available in L2 network %d: %w", globalExitRoot.String(), tm.l2NetworkID, err)
+	}
+	gerlatency.Injected(tm.l2NetworkID, globalExitRoot)
+	tm.gerCache.Add(globalExitRoot, true)
 	return nil
 }
 
-func (tm *ClaimTxManager) processDepositStatus(ger *etherman.GlobalExitRoot, dbTx pgx.Tx) error {
+// claimedDeposits returns the L1 deposits the global exit root makes ready for claim that are already claimed in the
+// bridge. The L2 deposits are claimed in L1, so they are not checked. The deposits that get no claim tx anyway are
+// not checked either, and the bitmap is read once for the deposits of each of its words.
+func (tm *ClaimTxManager) claimedDeposits(ger *etherman.GlobalExitRoot) (map[uint]bool, error) {
+	if ger.BlockID != 0 {
+		return nil, nil
+	}
+	deposits, err := tm.storage.GetReadyL1Deposits(tm.ctx, ger.ExitRoots[0][:], nil)
+	if err != nil {
+		log.Errorf("error getting the L1 deposits ready for claim. Error: %v", err)
+		return nil, err
+	}
+	claimed := make(map[uint]bool)
+	words := make(map[uint]*big.Int)
+	for _, deposit := range deposits {
+		if deposit.LeafType == LeafTypeMessage && !tm.isDepositMessageAllowed(deposit) {
+			continue
+		}
+		claimHash, err := tm.bridgeService.GetDepositStatus(tm.ctx, deposit.DepositCount, deposit.DestinationNetwork)
+		if err != nil {
+			log.Errorf("error getting deposit status for deposit %d. Error: %v", deposit.DepositCount, err)
+			return nil, err
+		}
+		if len(claimHash) > 0 {
+			continue
+		}
+		list, err := tm.tokenList(deposit, nil)
+		if err != nil {
+			log.Errorf("error getting the token list of the deposit %d. Error: %v", deposit.DepositCount, err)
+			return nil, err
+		}
+		if list == etherman.TokenListDeny {
+			continue
+		}
+		wordPos := deposit.DepositCount / claimBitMapWordBits
+		word, ok := words[wordPos]
+		if !ok {
+			word = tm.claimedBitMap(wordPos)
+			words[wordPos] = word
+		}
+		if word != nil && word.Bit(int(deposit.DepositCount%claimBitMapWordBits)) == 1 {
+			claimed[deposit.DepositCount] = true
+		}
+	}
+	return claimed, nil
+}
+
+// processDepositStatus updates the deposits status and returns the deposits that became ready for claim. The
+// deposits in claimed are already claimed in the bridge, they get no claim tx.
+func (tm *ClaimTxManager) processDepositStatus(ger *etherman.GlobalExitRoot, claimed map[uint]bool, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
 	if ger.BlockID != 0 { // L2 exit root is updated
 		log.Infof("Rollup exitroot %v is updated", ger.ExitRoots[1])
-		if err := tm.storage.UpdateL2DepositsStatus(tm.ctx, ger.ExitRoots[1][:], tm.l2NetworkID, dbTx); err != nil {
+		deposits, err := tm.storage.UpdateL2DepositsStatus(tm.ctx, ger.ExitRoots[1][:], tm.l2NetworkID, dbTx)
+		if err != nil {
 			log.Errorf("error updating L2DepositsStatus. Error: %v", err)
-			return err
+			return nil, err
 		}
-	} else { // L1 exit root is updated in the trusted state
-		log.Infof("Mainnet exitroot %v is updated", ger.ExitRoots[0])
-		deposits, err := tm.storage.UpdateL1DepositsStatus(tm.ctx, ger.ExitRoots[0][:], dbTx)
+		return deposits, nil
+	}
+	// L1 exit root is updated in the trusted state
+	log.Infof("Mainnet exitroot %v is updated", ger.ExitRoots[0])
+	deposits, err := tm.storage.UpdateL1DepositsStatus(tm.ctx, ger.ExitRoots[0][:], dbTx)
+	if err != nil {
+		log.Errorf("error getting and updating L1DepositsStatus. Error: %v", err)
+		return nil, err
+	}
+	for _, deposit := range deposits {
+		depositLog := activity.For(deposit.NetworkID, deposit.DepositCount)
+		claimHash, err := tm.bridgeService.GetDepositStatus(tm.ctx, deposit.DepositCount, deposit.DestinationNetwork)
 		if err != nil {
-			log.Errorf("error getting and updating L1DepositsStatus. Error: %v", err)
-			return err
+			depositLog.Errorf("error getting deposit status for deposit %d. Error: %v", deposit.DepositCount, err)
+			return nil, err
 		}
-		for _, deposit := range deposits {
-			claimHash, err := tm.bridgeService.GetDepositStatus(tm.ctx, deposit.DepositCount, deposit.DestinationNetwork)
-			if err != nil {
-				log.Errorf("error getting deposit status for deposit %d. Error: %v", deposit.DepositCount, err)
-				return err
-			}
-			if len(claimHash) > 0 || deposit.LeafType == LeafTypeMessage && !tm.isDepositMessageAllowed(deposit) {
-				log.Infof("Ignoring deposit: %d, leafType: %d, claimHash: %s, deposit.OriginalAddress: %s", deposit.DepositCount, deposit.LeafType, claimHash, deposit.OriginalAddress.String())
-				continue
-			}
-			log.Infof("create the claim tx for the deposit %d", deposit.DepositCount)
-			ger, proves, err := tm.bridgeService.GetClaimProof(deposit.DepositCount, deposit.NetworkID, dbTx)
-			if err != nil {
-				log.Errorf("error getting Claim Proof for deposit %d. Error: %v", deposit.DepositCount, err)
-				return err
-			}
-			var mtProves [mtHeight][keyLen]byte
-			for i := 0; i < mtHeight; i++ {
-				mtProves[i] = proves[i]
-			}
-			tx, err := tm.l2Node.BuildSendClaim(tm.ctx, deposit, mtProves,
-				&etherman.GlobalExitRoot{
-					ExitRoots: []common.Hash{
-						ger.ExitRoots[0],
-						ger.ExitRoots[1],
-					}}, 1, 1, 1,
-				tm.auth)
-			if err != nil {
-				log.Errorf("error BuildSendClaim tx for deposit %d. Error: %v", deposit.DepositCount, err)
-				return err
-			}
-			if err = tm.addClaimTx(deposit.DepositCount, tm.auth.From, tx.To(), nil, tx.Data(), dbTx); err != nil {
-				log.Errorf("error adding claim tx for deposit %d. Error: %v", deposit.DepositCount, err)
-				return err
+		if len(claimHash) > 0 || deposit.LeafType == LeafTypeMessage && !tm.isDepositMessageAllowed(deposit) {
+			depositLog.Infof("Ignoring deposit: %d, leafType: %d, claimHash: %s, deposit.OriginalAddress: %s", deposit.DepositCount, deposit.LeafType, claimHash, deposit.OriginalAddress.String())
+			continue
+		}
+		list, err := tm.tokenList(deposit, dbTx)
+		if err != nil {
+			depositLog.Errorf("error getting the token list of the deposit %d. Error: %v", deposit.DepositCount, err)
+			return nil, err
+		}
+		if list != etherman.TokenListDeny && claimed[deposit.DepositCount] {
+			depositLog.Infof("the deposit %d is already claimed in the bridge, skipping its claim tx", deposit.DepositCount)
+			mTx := ctmtypes.MonitoredTx{DepositID: deposit.DepositCount, From: tm.auth.From, Status: ctmtypes.MonitoredTxStatusAlreadyClaimed}
+			if err := tm.storage.AddClaimTx(tm.ctx, mTx, dbTx); err != nil {
+				depositLog.Errorf("error adding the claim tx of the claimed deposit %d. Error: %v", deposit.DepositCount, err)
+				return nil, err
 			}
+			continue
+		}
+		depositLog.Infof("create the claim tx for the deposit %d", deposit.DepositCount)
+		ger, proves, err := tm.bridgeService.GetClaimProof(deposit.DepositCount, deposit.NetworkID, dbTx)
+		if err != nil {
+			depositLog.Errorf("error getting Claim Proof for deposit %d. Error: %v", deposit.DepositCount, err)
+			return nil, err
+		}
+		var mtProves [mtHeight][keyLen]byte
+		for i := 0; i < mtHeight; i++ {
+			mtProves[i] = proves[i]
+		}
+		tx, err := tm.l2Node.BuildSendClaim(tm.ctx, deposit, mtProves,
+			&etherman.GlobalExitRoot{
+				ExitRoots: []common.Hash{
+					ger.ExitRoots[0],
+					ger.ExitRoots[1],
+				}}, 1, 1, 1,
+			tm.auth)
+		if err != nil {
+			depositLog.Errorf("error BuildSendClaim tx for deposit %d. Error: %v", deposit.DepositCount, err)
+			return nil, err
+		}
+		status := ctmtypes.MonitoredTxStatusCreated
+		if tm.requiresApproval(deposit) {
+			depositLog.Warnf("the amount %s of the deposit %d is above the approval threshold of its token, parking the claim tx until it's approved", deposit.Amount.String(), deposit.DepositCount)
+			status = ctmtypes.MonitoredTxStatusPendingApproval
+		}
+		if tm.cfg.AllowedTokensOnly && deposit.LeafType != LeafTypeMessage && list != etherman.TokenListAllow {
+			depositLog.Warnf("the token %d:%s of the deposit %d is not in the allow list, parking the claim tx until it's approved", deposit.OriginalNetwork, deposit.OriginalAddress.String(), deposit.DepositCount)
+			status = ctmtypes.MonitoredTxStatusPendingApproval
+		}
+		// The denied claim tx is stored with its data, so it can be approved once the token leaves the deny list
+		if list == etherman.TokenListDeny {
+			depositLog.Warnf("the token %d:%s of the deposit %d is in the deny list, denying its claim tx", deposit.OriginalNetwork, deposit.OriginalAddress.String(), deposit.DepositCount)
+			status = ctmtypes.MonitoredTxStatusDenied
+		}
+		if err = tm.addClaimTx(deposit, tm.auth.From, tx.To(), nil, tx.Data(), status, dbTx); err != nil {
+			depositLog.Errorf("error adding claim tx for deposit %d. Error: %v", deposit.DepositCount, err)
+			return nil, err
 		}
 	}
-	return nil
+	return deposits, nil
+}
+
+// tokenList returns the token list of the asset of the deposit, empty if it's not in a list or it's a message.
+func (tm *ClaimTxManager) tokenList(deposit *etherman.Deposit, dbTx pgx.Tx) (string, error) {
+	if deposit.LeafType == LeafTypeMessage {
+		return "", nil
+	}
+	entry, err := tm.storage.GetTokenListEntry(tm.ctx, deposit.OriginalNetwork, deposit.OriginalAddress, dbTx)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return entry.List, nil
+}
+
+// claimedBitMap reads the word of the claim bitmap of the bridge at the position, nil if the bridge can't be read.
+// Its deposits are considered not claimed then, their claim txs revert at worst.
+func (tm *ClaimTxManager) claimedBitMap(wordPos uint) *big.Int {
+	word, err := wait.Retry(tm.ctx, tm.retry, func(ctx context.Context) (*big.Int, error) {
+		return tm.l2Node.ClaimedBitMap(ctx, wordPos)
+	}, nil)
+	if err != nil {
+		log.Warnf("error reading the word %d of the claim bitmap of the bridge. Error: %v", wordPos, err)
+		return nil
+	}
+	return word
 }
 
 func (tm *ClaimTxManager) isDepositMessageAllowed(deposit *etherman.Deposit) bool {
@@ -198,10 +485,46 @@ func (tm *ClaimTxManager) isDepositMessageAllowed(deposit *etherman.Deposit) boo
 			return true
 		}
 	}
+	for _, addr := range tm.cfg.AuthorizedClaimMessageDestinations {
+		if deposit.DestinationAddress == addr {
+			log.Infof("MessageBridge to authorized destination detected: %+v, destination: %s", deposit, addr.String())
+			return true
+		}
+	}
 	log.Infof("MessageBridge Not authorized. DepositCount: %d", deposit.DepositCount)
 	return false
 }
 
+// parseApprovalThresholds parses the approval thresholds of the tokens, by the keys of the prices.
+func parseApprovalThresholds(thresholds map[string]*big.Int) (map[priceKey]*big.Int, error) {
+	parsed := make(map[priceKey]*big.Int, len(thresholds))
+	for key, threshold := range thresholds {
+		k, err := parsePriceKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if threshold != nil && threshold.Sign() < 0 {
+			return nil, fmt.Errorf("negative approval threshold of %s", key)
+		}
+		parsed[k] = threshold
+	}
+	return parsed, nil
+}
+
+// requiresApproval checks if the deposit amount is above the approval threshold of its token. The amounts of the
+// tokens have their own decimals, so the ether and the messages are compared with the ApprovalThreshold and each
+// token with its own threshold.
+func (tm *ClaimTxManager) requiresApproval(deposit *etherman.Deposit) bool {
+	threshold := tm.cfg.ApprovalThreshold
+	if deposit.LeafType != LeafTypeMessage && deposit.OriginalAddress != (common.Address{}) {
+		threshold = tm.approvalThresholds[priceKey{network: deposit.OriginalNetwork, address: deposit.OriginalAddress}]
+	}
+	if threshold == nil || threshold.Sign() == 0 {
+		return false
+	}
+	return deposit.Amount != nil && deposit.Amount.Cmp(threshold) > 0
+}
+
 func (tm *ClaimTxManager) getNextNonce(from common.Address) (uint64, error) {
 	nonce, err := tm.l2Node.NonceAt(tm.ctx, from, nil)
 	if err != nil {
@@ -216,7 +539,8 @@ func (tm *ClaimTxManager) getNextNonce(from common.Address) (uint64, error) {
 	return nonce, nil
 }
 
-func (tm *ClaimTxManager) addClaimTx(depositCount uint, from common.Address, to *common.Address, value *big.Int, data []byte, dbTx pgx.Tx) error {
+func (tm *ClaimTxManager) addClaimTx(deposit *etherman.Deposit, from common.Address, to *common.Address, value *big.Int, data []byte, status ctmtypes.MonitoredTxStatus, dbTx pgx.Tx) error {
+	depositLog := activity.For(deposit.NetworkID, deposit.DepositCount)
 	// get gas
 	tx := ethereum.CallMsg{
 		From:  from,
@@ -224,36 +548,57 @@ func (tm *ClaimTxManager) addClaimTx(depositCount uint, from common.Address, to
 		Value: value,
 		Data:  data,
 	}
-	gas, err := tm.l2Node.EstimateGas(tm.ctx, tx)
-	for i := 1; err != nil && err.Error() != runtime.ErrExecutionReverted.Error() && i < tm.cfg.RetryNumber; i++ {
-		log.Warnf("error while doing gas estimation. Retrying... Error: %v, Data: %s", err, common.Bytes2Hex(data))
-		time.Sleep(tm.cfg.RetryInterval.Duration)
-		gas, err = tm.l2Node.EstimateGas(tm.ctx, tx)
-	}
-	if err != nil {
-		log.Errorf("failed to estimate gas. Ignoring tx... Error: %v, data: %s", err, common.Bytes2Hex(data))
+	gas, err := wait.Retry(tm.ctx, tm.retry, func(ctx context.Context) (uint64, error) {
+		return tm.l2Node.EstimateGas(ctx, tx)
+	}, func(err error) bool {
+		if isReverted(err) {
+			return false
+		}
+		depositLog.Warnf("error while doing gas estimation. Retrying... Error: %v, Data: %s", err, common.Bytes2Hex(data))
+		return true
+	})
+	if err != nil && deposit.LeafType == LeafTypeMessage && isReverted(err) {
+		// The bridge reverts the claims of the messages whose call to the destination reverts. The claim tx is
+		// parked, its gas is estimated once it's approved
+		depositLog.Warnf("the claim of the message %d reverts, its destination %s rejects it, parking the claim tx until it's approved. Error: %v", deposit.DepositCount, deposit.DestinationAddress.String(), err)
+		status, gas = ctmtypes.MonitoredTxStatusMessageReverted, 0
+	} else if err != nil {
+		depositLog.Errorf("failed to estimate gas. Ignoring tx... Error: %v, data: %s", err, common.Bytes2Hex(data))
 		return nil
 	}
-	// get next nonce
-	nonce, err := tm.getNextNonce(from)
-	if err != nil {
-		err := fmt.Errorf("failed to get current nonce: %v", err)
-		log.Errorf("error getting next nonce. Error: %s", err.Error())
-		return err
+	if status == ctmtypes.MonitoredTxStatusCreated {
+		uneconomical, err := tm.isClaimUneconomical(tm.ctx, deposit, gas)
+		if err != nil {
+			depositLog.Errorf("error checking the value of the deposit %d. Error: %v", deposit.DepositCount, err)
+			return err
+		}
+		if uneconomical {
+			status = ctmtypes.MonitoredTxStatusUneconomical
+		}
+	}
+	// get next nonce. The parked txs get it once they are approved, so they don't block the next ones
+	var nonce uint64
+	if status == ctmtypes.MonitoredTxStatusCreated {
+		nonce, err = tm.getNextNonce(from)
+		if err != nil {
+			err := fmt.Errorf("failed to get current nonce: %v", err)
+			depositLog.Errorf("error getting next nonce. Error: %s", err.Error())
+			return err
+		}
 	}
 
 	// create monitored tx
 	mTx := ctmtypes.MonitoredTx{
-		DepositID: depositCount, From: from, To: to,
+		DepositID: deposit.DepositCount, From: from, To: to,
 		Nonce: nonce, Value: value, Data: data,
-		Gas: gas, Status: ctmtypes.MonitoredTxStatusCreated,
+		Gas: gas, Status: status,
 	}
 
 	// add to storage
 	err = tm.storage.AddClaimTx(tm.ctx, mTx, dbTx)
 	if err != nil {
-		err := fmt.Errorf("failed to add tx to get monitored: %v", err)
-		log.Errorf("error adding claim tx to db. Error: %s", err.Error())
+		err := fmt.Errorf("failed to add tx to get monitored: %w", err)
+		depositLog.Errorf("error adding claim tx to db. Error: %s", err.Error())
 		return err
 	}
 
@@ -267,7 +612,24 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 		return err
 	}
 
-	statusesFilter := []ctmtypes.MonitoredTxStatus{ctmtypes.MonitoredTxStatusCreated}
+	// The tokens denied after the claim txs were created are checked again before the txs are sent
+	denied, err := tm.storage.DenyClaimTxs(ctx, unsentStatuses, dbTx)
+	if err != nil {
+		log.Errorf("failed to deny the monitored txs of the denied tokens: %v", err)
+		rollbackErr := tm.storage.Rollback(tm.ctx, dbTx)
+		if rollbackErr != nil {
+			log.Errorf("claimtxman error rolling back state. RollbackErr: %s, err: %v", rollbackErr.Error(), err)
+			return rollbackErr
+		}
+		return fmt.Errorf("failed to deny the monitored txs of the denied tokens: %v", err)
+	}
+	if len(denied) > 0 {
+		log.Warnf("the tokens of the deposits %v are in the deny list, their claim txs are denied", denied)
+		// The nonces of the denied txs are not used, the next txs take them
+		tm.nonceCache.Remove(tm.auth.From.Hex())
+	}
+
+	statusesFilter := []ctmtypes.MonitoredTxStatus{ctmtypes.MonitoredTxStatusCreated, ctmtypes.MonitoredTxStatusApproved, ctmtypes.MonitoredTxStatusSigned}
 	mTxs, err := tm.storage.GetClaimTxsByStatus(ctx, statusesFilter, dbTx)
 	if err != nil {
 		log.Errorf("failed to get created monitored txs: %v", err)
@@ -280,10 +642,45 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 	}
 
 	isResetNonce := false // it will reset the nonce in one cycle
+	var cycle breakerCycle
+	var unsignedTxs []ctmtypes.MonitoredTx
 	log.Infof("found %v monitored tx to process", len(mTxs))
 	for _, mTx := range mTxs {
 		mTx := mTx // force variable shadowing to avoid pointer conflicts
-		mTxLog := log.WithFields("monitoredTx", mTx.DepositID)
+		mTxLog := activity.For(0, mTx.DepositID).WithFields("monitoredTx", mTx.DepositID)
+		if mTx.Status == ctmtypes.MonitoredTxStatusApproved && mTx.Gas == 0 {
+			// The claim tx of a reverting message is approved without gas
+			skip, err := tm.reviewApprovedMessage(ctx, &mTx, dbTx, mTxLog)
+			if err != nil {
+				mTxLog.Error(err)
+				rollbackErr := tm.storage.Rollback(tm.ctx, dbTx)
+				if rollbackErr != nil {
+					log.Errorf("claimtxman error rolling back state. RollbackErr: %s, err: %v", rollbackErr.Error(), err)
+					return rollbackErr
+				}
+				return err
+			}
+			if skip {
+				continue
+			}
+		}
+		if mTx.Status == ctmtypes.MonitoredTxStatusApproved {
+			nonce, err := tm.getNextNonce(mTx.From)
+			if err != nil {
+				mTxLog.Errorf("failed to get the nonce of the approved tx: %v", err)
+				continue
+			}
+			mTxLog.Infof("tx approved, using nonce %d", nonce)
+			mTx.Nonce = nonce
+			mTx.Status = ctmtypes.MonitoredTxStatusCreated
+		}
+		if mTx.Status == ctmtypes.MonitoredTxStatusSigned {
+			tm.sendSignedTx(ctx, &mTx, mTxLog)
+			if err := tm.storage.UpdateClaimTx(ctx, mTx, dbTx); err != nil {
+				mTxLog.Errorf("failed to update monitored tx signed offline: %v", err)
+			}
+			continue
+		}
 		mTxLog.Infof("processing tx with nonce %d", mTx.Nonce)
 
 		// check if any of the txs in the history was mined
@@ -298,29 +695,39 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 			mined, receipt, err = tm.l2Node.CheckTxWasMined(ctx, txHash)
 			if err != nil {
 				mTxLog.Errorf("failed to check if tx %s was mined: %v", txHash.String(), err)
+				cycle.fail(err)
 				continue
 			}
 
 			// if the tx is not mined yet, check that not all the tx were mined and go to the next
 			if !mined {
-				// check if the tx is in the pending pool
-				_, _, err = tm.l2Node.TransactionByHash(ctx, txHash)
-				if err != nil {
-					mTxLog.Errorf("error getting txByHash %s. Error: %v", txHash.String(), err)
-					// Retry if the tx has not appeared in the pool yet.
-					for i := 0; i < tm.cfg.RetryNumber && err != nil; i++ {
-						mTxLog.Warn("waiting and retrying to find the tx in the pool. TxHash: %s. Error: %v", txHash.String(), err)
-						time.Sleep(tm.cfg.RetryInterval.Duration)
-						_, _, err = tm.l2Node.TransactionByHash(ctx, txHash)
+				// the relayed txs are not in the public pool until the relay timeout expires
+				if pending, expiredTx := tm.relay.pending(txHash, tm.clock.Now()); pending {
+					mTxLog.Infof("tx %s sent to the private relay, not mined yet", txHash.String())
+					allHistoryTxMined = false
+					continue
+				} else if expiredTx != nil {
+					mTxLog.Warnf("tx %s not mined by the private relay before the timeout, sending it to the public mempool", txHash.String())
+					if err := tm.l2Node.SendTransaction(ctx, expiredTx); err != nil {
+						mTxLog.Errorf("failed to send tx %s to the public mempool: %v", txHash.String(), err)
 					}
-					if errors.Is(err, ethereum.NotFound) {
-						mTxLog.Error("maximum retries and the tx is still missing in the pool. TxHash: ", txHash.String())
-						hasFailedReceipts = true
-						continue
-					} else if err != nil {
-						mTxLog.Errorf("failed to retry to get tx %s: %v", txHash.String(), err)
-						continue
+				}
+				// check if the tx is in the pending pool, retrying if the tx has not appeared in the pool yet
+				_, err = wait.Retry(ctx, tm.retry, func(ctx context.Context) (*types.Transaction, error) {
+					tx, _, err := tm.l2Node.TransactionByHash(ctx, txHash)
+					if err != nil {
+						mTxLog.Warnf("waiting and retrying to find the tx in the pool. TxHash: %s. Error: %v", txHash.String(), err)
 					}
+					return tx, err
+				}, nil)
+				if errors.Is(err, ethereum.NotFound) {
+					mTxLog.Error("maximum retries and the tx is still missing in the pool. TxHash: ", txHash.String())
+					hasFailedReceipts = true
+					continue
+				} else if err != nil {
+					mTxLog.Errorf("failed to retry to get tx %s: %v", txHash.String(), err)
+					cycle.fail(err)
+					continue
 				}
 				log.Infof("tx: %s not mined yet", txHash.String())
 
@@ -329,18 +736,30 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 			}
 
 			// if the tx was mined successfully we can break the loop and proceed
+			tm.relay.remove(txHash)
 			if receipt.Status == types.ReceiptStatusSuccessful {
 				mTxLog.Infof("tx %s was mined successfully", txHash.String())
 				receiptSuccessful = true
+				cycle.succeed()
 				mTx.Status = ctmtypes.MonitoredTxStatusConfirmed
 				// update monitored tx changes into storage
 				err = tm.storage.UpdateClaimTx(ctx, mTx, dbTx)
 				if err != nil {
 					mTxLog.Errorf("failed to update monitored tx when confirmed: %v", err)
+				} else if err := tm.addClaimOutcome(ctx, mTx, receipt, dbTx, mTxLog); err != nil {
+					mTxLog.Error(err)
+					rollbackErr := tm.storage.Rollback(tm.ctx, dbTx)
+					if rollbackErr != nil {
+						log.Errorf("claimtxman error rolling back state. RollbackErr: %s, err: %v", rollbackErr.Error(), err)
+						return rollbackErr
+					}
+					return err
 				}
 				break
 			}
 
+			// a reverted claim is the failure of the claim, not of the network, so it doesn't open the breaker
+			tm.logRevertReason(ctx, txHash, receipt, mTxLog)
 			// if the tx was mined but failed, we continue to consider it was not mined
 			// and store the failed receipt to be used to check if nonce needs to be reviewed
 			mined = false
@@ -363,6 +782,7 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 			if err != nil {
 				mTxLog.Errorf("failed to update monitored tx when max history size limit reached: %v", err)
 			}
+			hooks.ClaimFailed(ctx, &mTx)
 			continue
 		}
 
@@ -371,6 +791,24 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 		// tx that were not mined yet, if so, we just need to wait, because maybe one of them
 		// will get mined successfully
 		if allHistoryTxMined {
+			// none of the txs of the history claimed the deposit, it's not sent again if another tx claimed it,
+			// e.g. a user or the previous run of the service before a restart
+			// the claim bitmap is read once, the db tx is open for the whole cycle
+			claimed, err := tm.l2Node.IsClaimed(ctx, mTx.DepositID)
+			if err != nil {
+				mTxLog.Errorf("failed to check if the deposit is claimed in the bridge: %v", err)
+				cycle.fail(err)
+				continue
+			}
+			if claimed {
+				mTxLog.Infof("deposit already claimed in the bridge by another tx, the tx is not sent")
+				mTx.Status = ctmtypes.MonitoredTxStatusAlreadyClaimed
+				if err := tm.storage.UpdateClaimTx(ctx, mTx, dbTx); err != nil {
+					mTxLog.Errorf("failed to update monitored tx of the claimed deposit: %v", err)
+				}
+				continue
+			}
+
 			// in case of all tx were mined and none of them were mined successfully, we need to
 			// review the tx information
 			if hasFailedReceipts {
@@ -378,24 +816,40 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 				err := tm.ReviewMonitoredTx(ctx, &mTx, true)
 				if err != nil {
 					mTxLog.Errorf("failed to review monitored tx: %v", err)
+					cycle.fail(err)
 					continue
 				}
 			}
 
-			// GasPrice is set here to use always the proper and most accurate value right before sending it to L2
-			gasPrice, err := tm.l2Node.SuggestGasPrice(ctx)
+			// The fees are set here to use always the proper and most accurate values right before sending it to L2
+			fees, err := tm.fees(ctx)
 			if err != nil {
-				mTxLog.Errorf("failed to get suggested gasPrice. Error: %v", err)
+				mTxLog.Errorf("failed to get the fees of the tx. Error: %v", err)
+				cycle.fail(err)
 				continue
 			}
-			//Multiply gasPrice by 10 to increase the efficiency of the tx in the sequence
-			mTx.GasPrice = big.NewInt(0).Mul(gasPrice, big.NewInt(10)) //nolint:gomnd
-			log.Infof("Using gasPrice: %s. The gasPrice suggested by the network is %s", mTx.GasPrice.String(), gasPrice.String())
+			mTx.GasPrice, mTx.GasTipCap, mTx.GasFeeCap = fees.gasPrice, fees.gasTipCap, fees.gasFeeCap
+			if fees.gasFeeCap != nil {
+				log.Infof("Using maxFeePerGas: %s and maxPriorityFeePerGas: %s. The base fee of the network is %s", fees.gasFeeCap.String(), fees.gasTipCap.String(), fees.baseFee.String())
+			} else {
+				log.Infof("Using gasPrice: %s", mTx.GasPrice.String())
+			}
 
 			// rebuild transaction
 			tx := mTx.Tx()
 			mTxLog.Debugf("unsigned tx created for monitored tx")
 
+			if tm.offline != nil {
+				// the tx is sent once it's signed offline
+				mTx.Status = ctmtypes.MonitoredTxStatusPendingSignature
+				if err := tm.storage.UpdateClaimTx(ctx, mTx, dbTx); err != nil {
+					mTxLog.Errorf("failed to update monitored tx pending of signature: %v", err)
+					continue
+				}
+				unsignedTxs = append(unsignedTxs, mTx)
+				continue
+			}
+
 			var signedTx *types.Transaction
 			// sign tx
 			signedTx, err = tm.auth.Signer(mTx.From, tx)
@@ -417,9 +871,10 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 			// check if the tx is already in the network, if not, send it
 			_, _, err = tm.l2Node.TransactionByHash(ctx, signedTx.Hash())
 			if errors.Is(err, ethereum.NotFound) {
-				err := tm.l2Node.SendTransaction(ctx, signedTx)
+				err := tm.sendTx(ctx, signedTx)
 				if err != nil {
 					mTxLog.Errorf("failed to send tx %s to network: %v", signedTx.Hash().String(), err)
+					cycle.fail(err)
 					var reviewNonce bool
 					if strings.Contains(err.Error(), "nonce") {
 						mTxLog.Infof("nonce error detected, Nonce used: %d", signedTx.Nonce())
@@ -436,9 +891,13 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 					if err != nil {
 						mTxLog.Errorf("failed to review monitored tx: %v", err)
 					}
+				} else {
+					cycle.succeed()
+					hooks.ClaimSent(ctx, &mTx, signedTx)
 				}
 			} else if err != nil && !errors.Is(err, ethereum.NotFound) {
 				mTxLog.Error("unexpected error getting TransactionByHash. Error: ", err)
+				cycle.fail(err)
 			} else {
 				mTxLog.Infof("signed tx %v already found in the network for the monitored tx.", signedTx.Hash().String())
 			}
@@ -453,6 +912,23 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 		}
 	}
 
+	tm.breaker.record(cycle, tm.clock.Now())
+
+	if len(unsignedTxs) > 0 {
+		// the txs are not committed as pending of signature if they can't be exported
+		path, err := tm.exportUnsignedTxs(ctx, unsignedTxs)
+		if err != nil {
+			log.Errorf("failed to export the claim txs to be signed offline: %v", err)
+			rollbackErr := tm.storage.Rollback(tm.ctx, dbTx)
+			if rollbackErr != nil {
+				log.Errorf("claimtxman error rolling back state. RollbackErr: %s, err: %v", rollbackErr.Error(), err)
+				return rollbackErr
+			}
+			return err
+		}
+		log.Infof("%d claim txs exported to be signed offline to %s", len(unsignedTxs), path)
+	}
+
 	err = tm.storage.Commit(tm.ctx, dbTx)
 	if err != nil {
 		log.Errorf("UpdateClaimTx committing dbTx, err: %v", err)
@@ -466,11 +942,126 @@ func (tm *ClaimTxManager) monitorTxs(ctx context.Context) error {
 	return nil
 }
 
+// exportUnsignedTxs exports the monitored txs to a file to be signed offline.
+func (tm *ClaimTxManager) exportUnsignedTxs(ctx context.Context, mTxs []ctmtypes.MonitoredTx) (string, error) {
+	chainID, err := tm.l2Node.ChainID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return tm.offline.export(tm.l2NetworkID, chainID, mTxs, tm.clock.Now())
+}
+
+// sendSignedTx sends the tx signed offline, that is monitored from now on as the txs signed online.
+// If it can't be sent, the monitored tx is reviewed to be exported again.
+func (tm *ClaimTxManager) sendSignedTx(ctx context.Context, mTx *ctmtypes.MonitoredTx, mTxLog *activity.Logger) {
+	signedTx := new(types.Transaction)
+	err := signedTx.UnmarshalBinary(mTx.SignedTx)
+	mTx.SignedTx = nil
+	mTx.Status = ctmtypes.MonitoredTxStatusCreated
+	if err != nil {
+		mTxLog.Errorf("failed to decode the tx signed offline, it will be exported again: %v", err)
+		return
+	}
+	if err := mTx.AddHistory(signedTx); errors.Is(err, ctmtypes.ErrAlreadyExists) {
+		mTxLog.Infof("signed tx already existed in the history")
+	}
+	if err := tm.sendTx(ctx, signedTx); err != nil {
+		mTxLog.Errorf("failed to send tx %s signed offline to network, it will be exported again: %v", signedTx.Hash().String(), err)
+		mTx.RemoveHistory(signedTx)
+		if err := tm.ReviewMonitoredTx(ctx, mTx, strings.Contains(err.Error(), "nonce")); err != nil {
+			mTxLog.Errorf("failed to review monitored tx: %v", err)
+		}
+		return
+	}
+	mTxLog.Infof("tx %s signed offline sent to the network", signedTx.Hash().String())
+	hooks.ClaimSent(ctx, mTx, signedTx)
+}
+
+// reviewApprovedMessage estimates the gas of the approved claim tx of a reverting message, before it takes a nonce.
+// The claim tx is parked again while the message still reverts, and estimated again in the next cycle when the
+// estimation fails for another reason. It returns if the claim tx is skipped in this cycle.
+func (tm *ClaimTxManager) reviewApprovedMessage(ctx context.Context, mTx *ctmtypes.MonitoredTx, dbTx pgx.Tx, mTxLog *activity.Logger) (bool, error) {
+	err := tm.ReviewMonitoredTx(ctx, mTx, false)
+	if err == nil {
+		return false, nil
+	}
+	if !isReverted(err) {
+		mTxLog.Warnf("failed to estimate the gas of the approved message, retrying in the next cycle: %v", err)
+		return true, nil
+	}
+	mTxLog.Warnf("the claim of the approved message still reverts, parking it again: %v", err)
+	mTx.Status = ctmtypes.MonitoredTxStatusMessageReverted
+	if err := tm.storage.UpdateClaimTx(ctx, *mTx, dbTx); err != nil {
+		return true, fmt.Errorf("failed to update the monitored tx of the reverting message: %w", err)
+	}
+	return true, nil
+}
+
+// isReverted checks if the gas estimation failed because the tx reverts, with or without reason.
+func isReverted(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if strings.HasPrefix(err.Error(), runtime.ErrExecutionReverted.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// logRevertReason logs the decoded revert reason of a failed claim tx, replaying it at its block.
+func (tm *ClaimTxManager) logRevertReason(ctx context.Context, txHash common.Hash, receipt *types.Receipt, mTxLog *activity.Logger) {
+	tx, _, err := tm.l2Node.TransactionByHash(ctx, txHash)
+	if err != nil {
+		mTxLog.Warnf("tx %s was mined but failed, error getting it to decode the revert reason: %v", txHash.String(), err)
+		return
+	}
+	reason, err := tm.l2Node.RevertReason(ctx, tx, receipt.BlockNumber)
+	if err != nil {
+		mTxLog.Warnf("tx %s was mined but failed, error getting the revert reason: %v", txHash.String(), err)
+		return
+	}
+	mTxLog.Warnf("tx %s was mined but failed at block %d, reason: %s", txHash.String(), receipt.BlockNumber.Uint64(), reason)
+}
+
+// addClaimOutcome stores the outcome of a confirmed claim tx decoded from its receipt, warning if the recipient
+// received another amount than the claimed one. The outcomes that can't be decoded are skipped, it only fails if the
+// outcome can't be stored.
+func (tm *ClaimTxManager) addClaimOutcome(ctx context.Context, mTx ctmtypes.MonitoredTx, receipt *types.Receipt, dbTx pgx.Tx, mTxLog *activity.Logger) error {
+	if mTx.To == nil {
+		return nil
+	}
+	outcome, err := etherman.DecodeClaimOutcome(mTx.Data, receipt, *mTx.To)
+	if err != nil {
+		mTxLog.Warnf("failed to decode the outcome of the tx %s: %v", receipt.TxHash.String(), err)
+		return nil
+	}
+	outcome.DepositID, outcome.DecodedAt = mTx.DepositID, tm.clock.Now().UTC()
+	if outcome.Discrepancy() {
+		mTxLog.Warnf("tx %s claimed %s of the token %s but the recipient %s received %s", receipt.TxHash.String(), outcome.Amount.String(), outcome.Token.String(), outcome.Recipient.String(), outcome.Delivered.String())
+	}
+	if err := tm.storage.AddClaimOutcome(ctx, outcome, dbTx); err != nil {
+		return fmt.Errorf("failed to store the outcome of the tx %s: %w", receipt.TxHash.String(), err)
+	}
+	return nil
+}
+
+// sendTx sends the signed tx through the private relay if it's configured, falling back to the public mempool.
+func (tm *ClaimTxManager) sendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if tm.relay != nil {
+		err := tm.relay.send(ctx, signedTx, tm.clock.Now())
+		if err == nil {
+			log.Infof("tx %s sent to the private relay", signedTx.Hash().String())
+			return nil
+		}
+		log.Warnf("failed to send tx %s to the private relay, sending it to the public mempool: %v", signedTx.Hash().String(), err)
+	}
+	return tm.l2Node.SendTransaction(ctx, signedTx)
+}
+
 // ReviewMonitoredTx checks if tx needs to be updated
 // accordingly to the current information stored and the current
 // state of the blockchain
 func (tm *ClaimTxManager) ReviewMonitoredTx(ctx context.Context, mTx *ctmtypes.MonitoredTx, reviewNonce bool) error {
-	mTxLog := log.WithFields("monitoredTx", mTx.DepositID)
+	mTxLog := activity.For(0, mTx.DepositID).WithFields("monitoredTx", mTx.DepositID)
 	mTxLog.Debug("reviewing")
 	// get gas
 	tx := ethereum.CallMsg{
@@ -479,14 +1070,17 @@ func (tm *ClaimTxManager) ReviewMonitoredTx(ctx context.Context, mTx *ctmtypes.M
 		Value: mTx.Value,
 		Data:  mTx.Data,
 	}
-	gas, err := tm.l2Node.EstimateGas(ctx, tx)
-	for i := 1; err != nil && err.Error() != runtime.ErrExecutionReverted.Error() && i < tm.cfg.RetryNumber; i++ {
+	gas, err := wait.Retry(ctx, tm.retry, func(ctx context.Context) (uint64, error) {
+		return tm.l2Node.EstimateGas(ctx, tx)
+	}, func(err error) bool {
+		if isReverted(err) {
+			return false
+		}
 		mTxLog.Warnf("error during gas estimation. Retrying... Error: %v, Data: %s", err, common.Bytes2Hex(tx.Data))
-		time.Sleep(tm.cfg.RetryInterval.Duration)
-		gas, err = tm.l2Node.EstimateGas(tm.ctx, tx)
-	}
+		return true
+	})
 	if err != nil {
-		err := fmt.Errorf("failed to estimate gas. Error: %v, Data: %s", err, common.Bytes2Hex(tx.Data))
+		err := fmt.Errorf("failed to estimate gas. Data: %s, Error: %w", common.Bytes2Hex(tx.Data), err)
 		mTxLog.Errorf("error: %s", err.Error())
 		return err
 	}
@@ -513,3 +1107,32 @@ func (tm *ClaimTxManager) ReviewMonitoredTx(ctx context.Context, mTx *ctmtypes.M
 
 	return nil
 }
+
+// StateKey identifies the claim tx manager in the state file.
+func (tm *ClaimTxManager) StateKey() string {
+	return fmt.Sprintf("claimtxman-%d", tm.l2NetworkID)
+}
+
+// ExportState returns the last nonces used by each sender, so the pending txs are not
+// overlapped by new ones after a restart.
+func (tm *ClaimTxManager) ExportState() (json.RawMessage, error) {
+	nonces := make(map[string]uint64, tm.nonceCache.Len())
+	for _, from := range tm.nonceCache.Keys() {
+		if nonce, ok := tm.nonceCache.Peek(from); ok {
+			nonces[from] = nonce
+		}
+	}
+	return json.Marshal(nonces)
+}
+
+// ImportState restores the last nonces used by each sender.
+func (tm *ClaimTxManager) ImportState(state json.RawMessage) error {
+	var nonces map[string]uint64
+	if err := json.Unmarshal(state, &nonces); err != nil {
+		return err
+	}
+	for from, nonce := range nonces {
+		tm.nonceCache.Add(from, nonce)
+	}
+	return nil
+}
@@ -1,16 +1,29 @@
+//go:build !readonly
+// +build !readonly
+
 package claimtxman
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-bridge-service/activity"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
 	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/db/pgstorage"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/require"
 )
 
@@ -88,9 +101,231 @@ func TestMonitoredTxStorage(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, mTxs, 2)
 
+	// A parked tx can only be approved once
+	mTx = ctmtypes.MonitoredTx{
+		DepositID: 3,
+		From:      common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"),
+		To:        &toAdr,
+		Value:     big.NewInt(1000000),
+		Data:      common.FromHex("0x0"),
+		Gas:       1000000,
+		Status:    ctmtypes.MonitoredTxStatusPendingApproval,
+		History:   make(map[common.Hash]bool),
+	}
+	err = pg.AddClaimTx(ctx, mTx, tx)
+	require.NoError(t, err)
+	require.NoError(t, pg.ApproveClaimTx(ctx, 3, tx))
+	require.ErrorIs(t, pg.ApproveClaimTx(ctx, 3, tx), gerror.ErrStorageNotFound)
+	require.ErrorIs(t, pg.ApproveClaimTx(ctx, 1, tx), gerror.ErrStorageNotFound)
+	mTxs, err = pg.GetClaimTxsByStatus(ctx, []ctmtypes.MonitoredTxStatus{ctmtypes.MonitoredTxStatusApproved}, tx)
+	require.NoError(t, err)
+	require.Len(t, mTxs, 1)
+	require.Equal(t, uint(3), mTxs[0].DepositID)
+
+	// The claim txs not sent yet are denied once their token is in the deny list, and can't be approved
+	deposit.DepositCount = 4
+	_, err = pg.AddDeposit(ctx, deposit, tx)
+	require.NoError(t, err)
+	mTx.DepositID, mTx.Status = 4, ctmtypes.MonitoredTxStatusPendingApproval
+	err = pg.AddClaimTx(ctx, mTx, tx)
+	require.NoError(t, err)
+	require.NoError(t, pg.SetTokenListEntry(ctx, &etherman.TokenListEntry{OriginalAddress: deposit.OriginalAddress, List: etherman.TokenListDeny, Reason: "exploit", Operator: "alice", UpdatedAt: time.Now()}, tx))
+	require.ErrorIs(t, pg.ApproveClaimTx(ctx, 4, tx), gerror.ErrStorageNotFound)
+	denied, err := pg.DenyClaimTxs(ctx, unsentStatuses, tx)
+	require.NoError(t, err)
+	// The tx of the deposit 1 was already sent
+	require.Equal(t, []uint{4}, denied)
+	require.ErrorIs(t, pg.ApproveClaimTx(ctx, 4, tx), gerror.ErrStorageNotFound)
+	// The denied tx is approved once the token leaves the deny list
+	require.NoError(t, pg.DeleteTokenListEntry(ctx, &etherman.TokenListChange{OriginalAddress: deposit.OriginalAddress, Reason: "fixed", Operator: "alice", CreatedAt: time.Now()}, tx))
+	require.NoError(t, pg.ApproveClaimTx(ctx, 4, tx))
+
 	require.NoError(t, tx.Commit(ctx))
 }
 
+func TestRequiresApproval(t *testing.T) {
+	var err error
+	tm := &ClaimTxManager{}
+	deposit := &etherman.Deposit{Amount: big.NewInt(1000)}
+	require.False(t, tm.requiresApproval(deposit))
+	tm.cfg.ApprovalThreshold = big.NewInt(0)
+	require.False(t, tm.requiresApproval(deposit))
+	tm.cfg.ApprovalThreshold = big.NewInt(1000)
+	require.False(t, tm.requiresApproval(deposit))
+	tm.cfg.ApprovalThreshold = big.NewInt(999)
+	require.True(t, tm.requiresApproval(deposit))
+	message := &etherman.Deposit{LeafType: LeafTypeMessage, OriginalAddress: common.HexToAddress("0x1"), Amount: big.NewInt(1000)}
+	require.True(t, tm.requiresApproval(message))
+
+	// The tokens are compared with their own thresholds, in their own decimals
+	tm.approvalThresholds, err = parseApprovalThresholds(map[string]*big.Int{"0:0x0000000000000000000000000000000000000001": big.NewInt(2000000)})
+	require.NoError(t, err)
+	token := &etherman.Deposit{OriginalNetwork: 0, OriginalAddress: common.HexToAddress("0x1"), Amount: big.NewInt(1000000)}
+	require.False(t, tm.requiresApproval(token))
+	token.Amount = big.NewInt(2000001)
+	require.True(t, tm.requiresApproval(token))
+	token.OriginalNetwork = 1
+	require.False(t, tm.requiresApproval(token))
+	_, err = parseApprovalThresholds(map[string]*big.Int{"0:0x0000000000000000000000000000000000000001": big.NewInt(-1)})
+	require.Error(t, err)
+	_, err = parseApprovalThresholds(map[string]*big.Int{"0x0000000000000000000000000000000000000001": big.NewInt(1)})
+	require.Error(t, err)
+}
+
+func TestIsDepositMessageAllowed(t *testing.T) {
+	sender, destination := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	tm := &ClaimTxManager{}
+	require.False(t, tm.isDepositMessageAllowed(&etherman.Deposit{OriginalAddress: sender, DestinationAddress: destination}))
+	tm.cfg.AuthorizedClaimMessageAddresses = []common.Address{sender}
+	require.True(t, tm.isDepositMessageAllowed(&etherman.Deposit{OriginalAddress: sender}))
+	// The messages to an authorized destination are claimed whatever their sender
+	tm.cfg.AuthorizedClaimMessageDestinations = []common.Address{destination}
+	require.True(t, tm.isDepositMessageAllowed(&etherman.Deposit{OriginalAddress: common.HexToAddress("0x3"), DestinationAddress: destination}))
+	require.False(t, tm.isDepositMessageAllowed(&etherman.Deposit{OriginalAddress: destination, DestinationAddress: sender}))
+}
+
+// claimStorage records the claim txs added.
+type claimStorage struct {
+	storageInterface
+	added      []ctmtypes.MonitoredTx
+	updated    []ctmtypes.MonitoredTx
+	updateErr  error
+	outcomes   []*etherman.ClaimOutcome
+	outcomeErr error
+}
+
+func (s *claimStorage) AddClaimTx(ctx context.Context, mTx ctmtypes.MonitoredTx, dbTx pgx.Tx) error {
+	s.added = append(s.added, mTx)
+	return nil
+}
+
+func TestAddClaimTxRevertingMessage(t *testing.T) {
+	clock := wait.NewFakeClock(time.Unix(1700000000, 0))
+	node := &l2Node{estimateErr: errors.New("execution reverted: MessageFailed")}
+	storage := &claimStorage{}
+	tm := &ClaimTxManager{
+		ctx:     context.Background(),
+		l2Node:  node,
+		storage: storage,
+		clock:   clock,
+		retry:   wait.Waiter{Interval: time.Second, Attempts: 5, Clock: clock},
+	}
+	to := common.HexToAddress("0x4")
+
+	// The claim of a reverting message is parked without gas, and without retrying the estimation
+	message := &etherman.Deposit{LeafType: LeafTypeMessage, DepositCount: 1, DestinationAddress: common.HexToAddress("0x2")}
+	require.NoError(t, tm.addClaimTx(message, common.HexToAddress("0x1"), &to, nil, []byte{1}, ctmtypes.MonitoredTxStatusCreated, nil))
+	require.Len(t, storage.added, 1)
+	require.Equal(t, ctmtypes.MonitoredTxStatusMessageReverted, storage.added[0].Status)
+	require.Zero(t, storage.added[0].Gas)
+	require.Zero(t, clock.Slept())
+
+	// The reverting claims of the assets are still ignored
+	asset := &etherman.Deposit{LeafType: 0, DepositCount: 2}
+	require.NoError(t, tm.addClaimTx(asset, common.HexToAddress("0x1"), &to, nil, []byte{1}, ctmtypes.MonitoredTxStatusCreated, nil))
+	require.Len(t, storage.added, 1)
+}
+
+func (s *claimStorage) UpdateClaimTx(ctx context.Context, mTx ctmtypes.MonitoredTx, dbTx pgx.Tx) error {
+	if s.updateErr != nil {
+		return s.updateErr
+	}
+	s.updated = append(s.updated, mTx)
+	return nil
+}
+
+func TestReviewApprovedMessage(t *testing.T) {
+	clock := wait.NewFakeClock(time.Unix(1700000000, 0))
+	node := &l2Node{estimateErr: errors.New("execution reverted: MessageFailed")}
+	storage := &claimStorage{}
+	tm := &ClaimTxManager{
+		l2Node:  node,
+		storage: storage,
+		clock:   clock,
+		retry:   wait.Waiter{Interval: time.Second, Attempts: 2, Clock: clock},
+	}
+	ctx := context.Background()
+	to := common.HexToAddress("0x4")
+	mTxLog := activity.For(0, 1)
+	approved := func() *ctmtypes.MonitoredTx {
+		return &ctmtypes.MonitoredTx{DepositID: 1, To: &to, Value: big.NewInt(0), Status: ctmtypes.MonitoredTxStatusApproved}
+	}
+
+	// The message that still reverts is parked again
+	mTx := approved()
+	skip, err := tm.reviewApprovedMessage(ctx, mTx, nil, mTxLog)
+	require.NoError(t, err)
+	require.True(t, skip)
+	require.Len(t, storage.updated, 1)
+	require.Equal(t, ctmtypes.MonitoredTxStatusMessageReverted, storage.updated[0].Status)
+
+	// The error updating it is returned, so the db tx is rolled back
+	storage.updateErr = errors.New("db down")
+	_, err = tm.reviewApprovedMessage(ctx, approved(), nil, mTxLog)
+	require.ErrorIs(t, err, storage.updateErr)
+	storage.updateErr = nil
+
+	// The other estimation errors are retried in the next cycle, without parking it
+	node.estimateErr = errors.New("connection refused")
+	mTx = approved()
+	skip, err = tm.reviewApprovedMessage(ctx, mTx, nil, mTxLog)
+	require.NoError(t, err)
+	require.True(t, skip)
+	require.Equal(t, ctmtypes.MonitoredTxStatusApproved, mTx.Status)
+	require.Len(t, storage.updated, 1)
+
+	// The message that doesn't revert anymore gets its gas
+	node.estimateErr, node.gas = nil, 50000
+	mTx = approved()
+	skip, err = tm.reviewApprovedMessage(ctx, mTx, nil, mTxLog)
+	require.NoError(t, err)
+	require.False(t, skip)
+	require.Equal(t, uint64(50000), mTx.Gas)
+	require.Len(t, storage.updated, 1)
+}
+
+func (s *claimStorage) AddClaimOutcome(ctx context.Context, outcome *etherman.ClaimOutcome, dbTx pgx.Tx) error {
+	if s.outcomeErr != nil {
+		return s.outcomeErr
+	}
+	s.outcomes = append(s.outcomes, outcome)
+	return nil
+}
+
+func TestAddClaimOutcome(t *testing.T) {
+	storage := &claimStorage{}
+	tm := &ClaimTxManager{storage: storage, clock: wait.NewFakeClock(time.Unix(1700000000, 0))}
+	ctx := context.Background()
+	mTxLog := activity.For(0, 1)
+	bridge, recipient := common.HexToAddress("0x4"), common.HexToAddress("0x5")
+	data, err := etherman.PackClaim(etherman.ClaimVersionV1, &etherman.Deposit{Amount: big.NewInt(1)}, [32][32]byte{}, [32][32]byte{}, &etherman.GlobalExitRoot{ExitRoots: []common.Hash{{}, {}}})
+	require.NoError(t, err)
+	mTx := ctmtypes.MonitoredTx{DepositID: 3, To: &bridge, Data: data}
+	// The ClaimEvent of the ether, which has no transfers
+	var eventData []byte
+	for _, word := range []common.Hash{{}, {}, {}, common.BytesToHash(recipient.Bytes()), common.BigToHash(big.NewInt(1))} {
+		eventData = append(eventData, word.Bytes()...)
+	}
+	receipt := &types.Receipt{BlockNumber: big.NewInt(10), Logs: []*types.Log{{
+		Address: bridge,
+		Topics:  []common.Hash{crypto.Keccak256Hash([]byte("ClaimEvent(uint32,uint32,address,address,uint256)"))},
+		Data:    eventData,
+	}}}
+
+	require.NoError(t, tm.addClaimOutcome(ctx, mTx, receipt, nil, mTxLog))
+	require.Len(t, storage.outcomes, 1)
+	require.Equal(t, uint(3), storage.outcomes[0].DepositID)
+	require.Equal(t, recipient, storage.outcomes[0].Recipient)
+
+	// The outcomes that can't be decoded are skipped
+	require.NoError(t, tm.addClaimOutcome(ctx, mTx, &types.Receipt{}, nil, mTxLog))
+	require.Len(t, storage.outcomes, 1)
+
+	// The error storing it is returned, so the db tx is rolled back
+	storage.outcomeErr = errors.New("db down")
+	require.ErrorIs(t, tm.addClaimOutcome(ctx, mTx, receipt, nil, mTxLog), storage.outcomeErr)
+}
+
 // Test the update deposit status logic
 func TestUpdateDepositStatus(t *testing.T) {
 	ctx := context.Background()
@@ -172,6 +407,10 @@ func TestUpdateDepositStatus(t *testing.T) {
 	l2Root1 := common.FromHex("0xda7bce9f4e8618b6bd2f4132ce798cdc7a60e7e1460a7299e3c6342a579626d2")
 	require.NoError(t, pg.SetRoot(ctx, l2Root1, depositID, deposit.NetworkID, nil))
 
+	ready, err := pg.GetReadyL1Deposits(ctx, l1Root, nil)
+	require.NoError(t, err)
+	require.Len(t, ready, 1)
+	require.Equal(t, uint(1), ready[0].DepositCount)
 	deposits, err := pg.UpdateL1DepositsStatus(ctx, l1Root, nil)
 	require.NoError(t, err)
 	require.Len(t, deposits, 1)
@@ -179,8 +418,11 @@ func TestUpdateDepositStatus(t *testing.T) {
 	require.Equal(t, uint(1), deposits[0].DepositCount)
 	require.Equal(t, uint(0), deposits[0].NetworkID)
 
-	require.NoError(t, pg.UpdateL2DepositsStatus(ctx, l2Root, 1, nil))
-	deposits, err = pg.GetDeposits(ctx, destAdr, 10, 0, nil)
+	updated, err := pg.UpdateL2DepositsStatus(ctx, l2Root, 1, nil)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	require.Equal(t, uint(1), updated[0].NetworkID)
+	deposits, err = pg.GetDeposits(ctx, destAdr, "", "", 10, 0, nil)
 	require.NoError(t, err)
 	require.Len(t, deposits, 2)
 	require.True(t, deposits[1].ReadyForClaim)
@@ -252,32 +494,365 @@ func TestUpdateL2DepositStatusMultipleRollups(t *testing.T) {
 	require.NoError(t, pg.SetRoot(ctx, l2Root2, depositID2, deposit2.NetworkID, nil))
 
 	// This root is for network 1, this won't upgrade anything
-	require.NoError(t, pg.UpdateL2DepositsStatus(ctx, l2Root1, 2, nil))
-	deposits, err := pg.GetDeposits(ctx, destAdr, 10, 0, nil)
+	updated, err := pg.UpdateL2DepositsStatus(ctx, l2Root1, 2, nil)
+	require.NoError(t, err)
+	require.Empty(t, updated)
+	deposits, err := pg.GetDeposits(ctx, destAdr, "", "", 10, 0, nil)
 	require.NoError(t, err)
 	require.Len(t, deposits, 2)
 	require.False(t, deposits[1].ReadyForClaim)
 	require.False(t, deposits[0].ReadyForClaim)
 
 	// This root is for network 2, this won't upgrade anything
-	require.NoError(t, pg.UpdateL2DepositsStatus(ctx, l2Root2, 1, nil))
-	deposits, err = pg.GetDeposits(ctx, destAdr, 10, 0, nil)
+	updated, err = pg.UpdateL2DepositsStatus(ctx, l2Root2, 1, nil)
+	require.NoError(t, err)
+	require.Empty(t, updated)
+	deposits, err = pg.GetDeposits(ctx, destAdr, "", "", 10, 0, nil)
 	require.NoError(t, err)
 	require.Len(t, deposits, 2)
 	require.False(t, deposits[1].ReadyForClaim)
 	require.False(t, deposits[0].ReadyForClaim)
 
-	require.NoError(t, pg.UpdateL2DepositsStatus(ctx, l2Root1, 1, nil))
-	deposits, err = pg.GetDeposits(ctx, destAdr, 10, 0, nil)
+	updated, err = pg.UpdateL2DepositsStatus(ctx, l2Root1, 1, nil)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	deposits, err = pg.GetDeposits(ctx, destAdr, "", "", 10, 0, nil)
 	require.NoError(t, err)
 	require.Len(t, deposits, 2)
 	require.True(t, deposits[1].ReadyForClaim)
 	require.False(t, deposits[0].ReadyForClaim)
 
-	require.NoError(t, pg.UpdateL2DepositsStatus(ctx, l2Root2, 2, nil))
-	deposits, err = pg.GetDeposits(ctx, destAdr, 10, 0, nil)
+	updated, err = pg.UpdateL2DepositsStatus(ctx, l2Root2, 2, nil)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	deposits, err = pg.GetDeposits(ctx, destAdr, "", "", 10, 0, nil)
 	require.NoError(t, err)
 	require.Len(t, deposits, 2)
 	require.True(t, deposits[1].ReadyForClaim)
 	require.True(t, deposits[0].ReadyForClaim)
 }
+
+func TestPrivateRelayPending(t *testing.T) {
+	relay := &privateRelay{
+		timeout: time.Minute,
+		txs:     make(map[common.Hash]relayedTx),
+	}
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1})
+	now := time.Now()
+	relay.txs[tx.Hash()] = relayedTx{tx: tx, sentAt: now}
+
+	pending, expiredTx := relay.pending(tx.Hash(), now.Add(30*time.Second))
+	require.True(t, pending)
+	require.Nil(t, expiredTx)
+	// After the timeout the tx is returned once to be sent to the public mempool
+	pending, expiredTx = relay.pending(tx.Hash(), now.Add(time.Minute))
+	require.False(t, pending)
+	require.Equal(t, tx.Hash(), expiredTx.Hash())
+	pending, expiredTx = relay.pending(tx.Hash(), now.Add(time.Minute))
+	require.False(t, pending)
+	require.Nil(t, expiredTx)
+
+	// A disabled relay has no pending txs
+	var disabled *privateRelay
+	pending, expiredTx = disabled.pending(tx.Hash(), now)
+	require.False(t, pending)
+	require.Nil(t, expiredTx)
+}
+
+func TestWaitGlobalExitRootCached(t *testing.T) {
+	gerCache, err := lru.New[common.Hash, bool](10)
+	require.NoError(t, err)
+	tm := &ClaimTxManager{gerCache: gerCache}
+	ger := common.HexToHash("0x1")
+	// A verified exit root is not requested again to the L2 network
+	gerCache.Add(ger, true)
+	require.NoError(t, tm.waitGlobalExitRoot(ger))
+}
+
+func TestWaitGlobalExitRootNotCached(t *testing.T) {
+	gerCache, err := lru.New[common.Hash, bool](10)
+	require.NoError(t, err)
+	clock := wait.NewFakeClock(time.Unix(1700000000, 0))
+	node := &l2Node{gerFailures: 2}
+	tm := &ClaimTxManager{
+		ctx:      context.Background(),
+		l2Node:   node,
+		gerCache: gerCache,
+		clock:    clock,
+		retry:    wait.Waiter{Interval: time.Second, Attempts: 3, Clock: clock},
+	}
+
+	// An exit root not cached is checked in the L2 network, retried while the checks fail, and cached once available
+	ger := common.HexToHash("0x1")
+	require.NoError(t, tm.waitGlobalExitRoot(ger))
+	require.Equal(t, 3, node.gerChecks)
+	require.True(t, gerCache.Contains(ger))
+
+	// The exit root is not cached if the checks keep failing
+	node.gerChecks, node.gerFailures = 0, 5
+	err = tm.waitGlobalExitRoot(common.HexToHash("0x2"))
+	require.ErrorIs(t, err, wait.ErrTimeout)
+	require.Equal(t, 3, node.gerChecks)
+	require.False(t, gerCache.Contains(common.HexToHash("0x2")))
+}
+
+// l2Node is a L2 network whose exit roots are available after some checks, and whose claim bitmap fails until
+// some calls.
+type l2Node struct {
+	l2NodeInterface
+	availableAfter int
+	gerChecks      int
+	gerFailures    int
+	claimed        map[uint]bool
+	claimFailures  int
+	claimChecked   func()
+	bitMapReads    int
+	gas            uint64
+	estimateErr    error
+}
+
+func (n *l2Node) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return n.gas, n.estimateErr
+}
+
+func (n *l2Node) BuildSendClaim(ctx context.Context, deposit *etherman.Deposit, smtProof [mtHeight][keyLen]byte, globalExitRoot *etherman.GlobalExitRoot, nonce, gasPrice int64, gasLimit uint64, auth *bind.TransactOpts) (*types.Transaction, error) {
+	to := common.HexToAddress("0x4")
+	return types.NewTx(&types.LegacyTx{To: &to, Data: []byte{byte(deposit.DepositCount)}}), nil
+}
+
+func (n *l2Node) IsGlobalExitRootAvailable(ctx context.Context, globalExitRoot common.Hash) (bool, error) {
+	n.gerChecks++
+	if n.gerFailures > 0 {
+		n.gerFailures--
+		return false, errors.New("connection refused")
+	}
+	return n.gerChecks >= n.availableAfter, nil
+}
+
+func (n *l2Node) IsClaimed(ctx context.Context, depositCount uint) (bool, error) {
+	if n.claimChecked != nil {
+		n.claimChecked()
+	}
+	if n.claimFailures > 0 {
+		n.claimFailures--
+		return false, errors.New("connection refused")
+	}
+	return n.claimed[depositCount], nil
+}
+
+func (n *l2Node) ClaimedBitMap(ctx context.Context, wordPos uint) (*big.Int, error) {
+	n.bitMapReads++
+	if n.claimChecked != nil {
+		n.claimChecked()
+	}
+	if n.claimFailures > 0 {
+		n.claimFailures--
+		return nil, errors.New("connection refused")
+	}
+	word := new(big.Int)
+	for depositCount, claimed := range n.claimed {
+		if claimed && depositCount/claimBitMapWordBits == wordPos {
+			word.SetBit(word, int(depositCount%claimBitMapWordBits), 1)
+		}
+	}
+	return word, nil
+}
+
+func TestL2Retries(t *testing.T) {
+	gerCache, err := lru.New[common.Hash, bool](10)
+	require.NoError(t, err)
+	clock := wait.NewFakeClock(time.Unix(1700000000, 0))
+	node := &l2Node{availableAfter: 3, claimed: map[uint]bool{7: true}}
+	tm := &ClaimTxManager{
+		ctx:      context.Background(),
+		l2Node:   node,
+		gerCache: gerCache,
+		clock:    clock,
+		retry:    wait.Waiter{Interval: time.Second, Attempts: 5, Clock: clock},
+	}
+
+	// The exit root is checked every retry interval until it's injected in the L2 network
+	require.NoError(t, tm.waitGlobalExitRoot(common.HexToHash("0x1")))
+	require.Equal(t, 3, node.gerChecks)
+	require.Equal(t, 2*time.Second, clock.Slept())
+	require.NoError(t, tm.waitGlobalExitRoot(common.HexToHash("0x1")))
+	require.Equal(t, 3, node.gerChecks)
+
+	// It fails after the attempts
+	node.gerChecks, node.availableAfter = 0, 10
+	err = tm.waitGlobalExitRoot(common.HexToHash("0x2"))
+	require.ErrorIs(t, err, wait.ErrTimeout)
+	require.Equal(t, 5, node.gerChecks)
+	require.Equal(t, 6*time.Second, clock.Slept())
+
+	// The claim bitmap is read again while the L2 network fails, and the deposit is not claimed if it keeps failing
+	node.claimFailures = 2
+	require.Equal(t, uint(1), tm.claimedBitMap(0).Bit(7))
+	require.Equal(t, 8*time.Second, clock.Slept())
+	node.claimFailures = 5
+	require.Nil(t, tm.claimedBitMap(0))
+	require.Equal(t, 12*time.Second, clock.Slept())
+}
+
+// readyStorage makes L1 deposits ready for claim, recording if a db tx is open. Their tokens are in the deny list
+// if denied.
+type readyStorage struct {
+	claimStorage
+	ready  []*etherman.Deposit
+	denied bool
+	txOpen bool
+	commit bool
+}
+
+func (s *readyStorage) GetReadyL1Deposits(ctx context.Context, exitRoot []byte, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	return s.ready, nil
+}
+
+func (s *readyStorage) BeginDBTransaction(ctx context.Context) (pgx.Tx, error) {
+	s.txOpen = true
+	return nil, nil
+}
+
+func (s *readyStorage) UpdateL1DepositsStatus(ctx context.Context, exitRoot []byte, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	return s.ready, nil
+}
+
+func (s *readyStorage) GetTokenListEntry(ctx context.Context, originalNetwork uint, originalAddress common.Address, dbTx pgx.Tx) (*etherman.TokenListEntry, error) {
+	if s.denied {
+		return &etherman.TokenListEntry{OriginalNetwork: originalNetwork, OriginalAddress: originalAddress, List: etherman.TokenListDeny}, nil
+	}
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (s *readyStorage) Commit(ctx context.Context, dbTx pgx.Tx) error {
+	s.txOpen, s.commit = false, true
+	return nil
+}
+
+func (s *readyStorage) Rollback(ctx context.Context, dbTx pgx.Tx) error {
+	s.txOpen = false
+	return nil
+}
+
+// proofService has no claims of the deposits, and fails to build their proofs unless it has the exit root of the
+// proofs.
+type proofService struct {
+	proofs int
+	ger    *etherman.GlobalExitRoot
+}
+
+func (s *proofService) GetClaimProof(depositCnt, networkID uint, dbTx pgx.Tx) (*etherman.GlobalExitRoot, [][bridgectrl.KeyLen]byte, error) {
+	s.proofs++
+	if s.ger == nil {
+		return nil, nil, errors.New("no proof")
+	}
+	return s.ger, make([][bridgectrl.KeyLen]byte, mtHeight), nil
+}
+
+func (s *proofService) GetDepositStatus(ctx context.Context, depositCount uint, destNetworkID uint) (string, error) {
+	return "", nil
+}
+
+func TestUpdateDepositsStatusClaimedOnChain(t *testing.T) {
+	ger := &etherman.GlobalExitRoot{GlobalExitRoot: common.HexToHash("0x1"), ExitRoots: []common.Hash{common.HexToHash("0x2"), common.HexToHash("0x3")}}
+	for _, tc := range []struct {
+		name          string
+		claimed       bool
+		denied        bool
+		claimFailures int
+		slept         time.Duration
+	}{
+		{name: "claimed", claimed: true},
+		{name: "denied", denied: true},
+		{name: "not claimed"},
+		{name: "rpc error", claimFailures: 5, slept: 4 * time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gerCache, err := lru.New[common.Hash, bool](10)
+			require.NoError(t, err)
+			gerCache.Add(ger.GlobalExitRoot, true)
+			clock := wait.NewFakeClock(time.Unix(1700000000, 0))
+			storage := &readyStorage{ready: []*etherman.Deposit{{NetworkID: 0, DestinationNetwork: 1, DepositCount: 7}}, denied: tc.denied}
+			node := &l2Node{claimed: map[uint]bool{7: tc.claimed}, claimFailures: tc.claimFailures}
+			// The claim bitmap is read, with its retries, before the db tx is open
+			node.claimChecked = func() { require.False(t, storage.txOpen) }
+			bridge := &proofService{}
+			if tc.denied {
+				bridge.ger = ger
+			}
+			tm := &ClaimTxManager{
+				ctx:           context.Background(),
+				l2Node:        node,
+				storage:       storage,
+				bridgeService: bridge,
+				gerCache:      gerCache,
+				clock:         clock,
+				retry:         wait.Waiter{Interval: time.Second, Attempts: 5, Clock: clock},
+				auth:          &bind.TransactOpts{From: common.HexToAddress("0x1")},
+			}
+
+			err = tm.updateDepositsStatus(ger)
+			require.Equal(t, tc.slept, clock.Slept())
+			require.False(t, storage.txOpen)
+			if tc.claimed {
+				// The claimed deposit gets no claim tx
+				require.NoError(t, err)
+				require.True(t, storage.commit)
+				require.Zero(t, bridge.proofs)
+				require.Len(t, storage.added, 1)
+				require.Equal(t, ctmtypes.MonitoredTxStatusAlreadyClaimed, storage.added[0].Status)
+				return
+			}
+			if tc.denied {
+				// The denial is stored with the claim tx, so the deposit is not checked again and can be approved. The
+				// bitmap is not read for it
+				require.NoError(t, err)
+				require.Zero(t, node.bitMapReads)
+				require.True(t, storage.commit)
+				require.Equal(t, 1, bridge.proofs)
+				require.Len(t, storage.added, 1)
+				require.Equal(t, ctmtypes.MonitoredTxStatusDenied, storage.added[0].Status)
+				require.Equal(t, []byte{7}, storage.added[0].Data)
+				require.Zero(t, storage.added[0].Nonce)
+				return
+			}
+			// The deposits not claimed, or that can't be checked, get their claim tx built
+			require.Error(t, err)
+			require.False(t, storage.commit)
+			require.Equal(t, 1, bridge.proofs)
+			require.Empty(t, storage.added)
+		})
+	}
+}
+
+func TestClaimedDeposits(t *testing.T) {
+	ger := &etherman.GlobalExitRoot{GlobalExitRoot: common.HexToHash("0x1"), ExitRoots: []common.Hash{common.HexToHash("0x2"), common.HexToHash("0x3")}}
+	clock := wait.NewFakeClock(time.Unix(1700000000, 0))
+	storage := &readyStorage{ready: []*etherman.Deposit{
+		{DestinationNetwork: 1, DepositCount: 1},
+		{DestinationNetwork: 1, DepositCount: 2},
+		{DestinationNetwork: 1, DepositCount: 3, LeafType: LeafTypeMessage},
+		{DestinationNetwork: 1, DepositCount: 300},
+	}}
+	node := &l2Node{claimed: map[uint]bool{2: true, 3: true, 300: true}}
+	tm := &ClaimTxManager{
+		ctx:           context.Background(),
+		l2Node:        node,
+		storage:       storage,
+		bridgeService: &proofService{},
+		clock:         clock,
+		retry:         wait.Waiter{Interval: time.Second, Attempts: 5, Clock: clock},
+	}
+
+	// The bitmap is read once for each word, and not for the messages that get no claim tx
+	claimed, err := tm.claimedDeposits(ger)
+	require.NoError(t, err)
+	require.Equal(t, map[uint]bool{2: true, 300: true}, claimed)
+	require.Equal(t, 2, node.bitMapReads)
+
+	// The deposits of the L2 exit roots are not checked
+	claimed, err = tm.claimedDeposits(&etherman.GlobalExitRoot{BlockID: 1})
+	require.NoError(t, err)
+	require.Nil(t, claimed)
+	require.Equal(t, 2, node.bitMapReads)
+}
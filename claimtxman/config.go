@@ -1,6 +1,10 @@
 package claimtxman
 
 import (
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/blocktime"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -11,6 +15,9 @@ type Config struct {
 	Enabled bool `mapstructure:"Enabled"`
 	// FrequencyToMonitorTxs frequency of the resending failed txs
 	FrequencyToMonitorTxs types.Duration `mapstructure:"FrequencyToMonitorTxs"`
+	// AdaptiveMonitorInterval makes the frequency of the monitoring of the txs follow the observed block time of
+	// the L2 network, instead of the FrequencyToMonitorTxs
+	AdaptiveMonitorInterval blocktime.Config `mapstructure:"AdaptiveMonitorInterval"`
 	// PrivateKey defines the key store file that is going
 	// to be read in order to provide the private key to sign the claim txs
 	PrivateKey types.KeystoreFileConfig `mapstructure:"PrivateKey"`
@@ -20,4 +27,86 @@ type Config struct {
 	RetryNumber int `mapstructure:"RetryNumber"`
 	// AuthorizedClaimMessageAddresses are the allowed address to bridge message with autoClaim
 	AuthorizedClaimMessageAddresses []common.Address `mapstructure:"AuthorizedClaimMessageAddresses"`
+	// AuthorizedClaimMessageDestinations are the destinations whose messages are claimed with autoClaim, whatever
+	// the address that bridged them
+	AuthorizedClaimMessageDestinations []common.Address `mapstructure:"AuthorizedClaimMessageDestinations"`
+	// ApprovalThreshold is the amount in wei of the ether deposits and the messages above which the claim tx is
+	// parked until it's approved through the admin API. 0 disables their approvals
+	ApprovalThreshold *big.Int `mapstructure:"ApprovalThreshold"`
+	// ApprovalThresholds are the amounts of the token deposits above which the claim tx is parked until it's
+	// approved, in the base units of the token by "<original network>:<original token address>". The deposits of
+	// the tokens without a threshold are not parked by their amount
+	ApprovalThresholds map[string]*big.Int `mapstructure:"ApprovalThresholds"`
+	// AllowedTokensOnly parks the claim txs of the assets whose token is not in the allow list of the admin API
+	// until they are approved. The tokens in the deny list are never claimed
+	AllowedTokensOnly bool `mapstructure:"AllowedTokensOnly"`
+	// PrivateRelayURL is the url of a private tx relay (e.g. Flashbots Protect) used to send the claim txs
+	// without exposing them in the public mempool. Empty sends them to the public mempool
+	PrivateRelayURL string `mapstructure:"PrivateRelayURL"`
+	// PrivateRelayTimeout is the time to wait for a relayed claim tx to be mined before sending it to the public mempool
+	PrivateRelayTimeout types.Duration `mapstructure:"PrivateRelayTimeout"`
+	// OfflineSigningDir is the directory where the batches of unsigned claim txs are exported to be signed
+	// offline, the signed txs are sent once they are submitted through the admin API. Empty signs the claim
+	// txs with the PrivateKey
+	OfflineSigningDir string `mapstructure:"OfflineSigningDir"`
+	// OfflineSignerAddress is the address of the offline key, the sender of the claim txs when they are signed offline
+	OfflineSignerAddress common.Address `mapstructure:"OfflineSignerAddress"`
+	// BreakerThreshold is the number of consecutive failed cycles (e.g. the L2 node is down or the bridge is paused)
+	// that open the circuit breaker of the network, stopping its claim txs until the cooldown expires. 0 disables it
+	BreakerThreshold int `mapstructure:"BreakerThreshold"`
+	// BreakerCooldown is the time the circuit breaker stays open before probing the network again
+	BreakerCooldown types.Duration `mapstructure:"BreakerCooldown"`
+	// MinClaimValue parks the claim txs of the deposits worth less than the gas to claim them
+	MinClaimValue MinClaimValueConfig `mapstructure:"MinClaimValue"`
+	// UpdateWorkers is the number of exit roots whose deposits are updated and claimed concurrently
+	UpdateWorkers int `mapstructure:"UpdateWorkers"`
+	// UpdateQueue is the queue of the exit roots waiting for the UpdateWorkers. A newer exit root covers the
+	// deposits of the older ones, so they can be dropped
+	UpdateQueue queue.Config `mapstructure:"UpdateQueue"`
+	// DynamicFee sends the claim txs as EIP-1559 txs instead of legacy txs
+	DynamicFee DynamicFeeConfig `mapstructure:"DynamicFee"`
+}
+
+// Strategies of the max priority fee per gas of the EIP-1559 claim txs
+const (
+	// TipStrategySuggested uses the tip suggested by the L2 node, at least the configured Tip
+	TipStrategySuggested = "suggested"
+	// TipStrategyFixed always uses the configured Tip
+	TipStrategyFixed = "fixed"
+)
+
+// DynamicFeeConfig is the choice of the fees of the EIP-1559 claim txs.
+type DynamicFeeConfig struct {
+	// Enabled sends the claim txs as EIP-1559 txs when the L2 network has a base fee. The networks without
+	// EIP-1559 still get legacy txs
+	Enabled bool `mapstructure:"Enabled"`
+	// TipStrategy chooses the max priority fee per gas: "suggested" by the L2 node or "fixed" to the Tip
+	TipStrategy string `mapstructure:"TipStrategy"`
+	// Tip is the max priority fee per gas in wei of the fixed strategy, and the min one of the suggested strategy
+	Tip *big.Int `mapstructure:"Tip"`
+	// BaseFeeMultiplier is the multiple of the base fee of the latest block in the max fee per gas, added to the
+	// tip, so the claim tx stays valid while the base fee rises
+	BaseFeeMultiplier uint64 `mapstructure:"BaseFeeMultiplier"`
+	// MaxFeeCap is the highest max fee per gas in wei of the claim txs. 0 doesn't cap it
+	MaxFeeCap *big.Int `mapstructure:"MaxFeeCap"`
+}
+
+// MinClaimValueConfig is the comparison of the value of the deposits with the cost of their claim txs.
+type MinClaimValueConfig struct {
+	// Enabled parks the claim txs of the deposits whose value is below the cost of the claim tx, they are only
+	// sent once they are approved through the admin API
+	Enabled bool `mapstructure:"Enabled"`
+	// MinValueRatio is the min value of a deposit as a multiple of the cost of its claim tx, e.g. 1 claims the
+	// deposits worth at least the gas they spend
+	MinValueRatio float64 `mapstructure:"MinValueRatio"`
+	// Prices are the prices in ether of a whole token by "<original network>:<original token address>". The ether
+	// is always worth 1, the deposits of the tokens without a price are claimed
+	Prices map[string]float64 `mapstructure:"Prices"`
+	// PriceFeedURL returns the prices as a JSON object with the keys of the Prices, which take precedence over the
+	// configured ones. Empty only uses the configured prices
+	PriceFeedURL string `mapstructure:"PriceFeedURL"`
+	// PriceFeedInterval is the time the prices of the feed are used before loading them again
+	PriceFeedInterval types.Duration `mapstructure:"PriceFeedInterval"`
+	// PriceFeedTimeout is the max time of a request to the price feed
+	PriceFeedTimeout types.Duration `mapstructure:"PriceFeedTimeout"`
 }
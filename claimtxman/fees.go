@@ -0,0 +1,101 @@
+//go:build !readonly
+// +build !readonly
+
+package claimtxman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// claimFees are the fees of a claim tx: the gas price of a legacy tx, or the max fees of an EIP-1559 tx with the
+// base fee they were chosen for.
+type claimFees struct {
+	gasPrice  *big.Int
+	gasTipCap *big.Int
+	gasFeeCap *big.Int
+	baseFee   *big.Int
+}
+
+// effectiveGasPrice returns the gas price paid by the claim tx if it's mined at the base fee of the fees.
+func (f claimFees) effectiveGasPrice() *big.Int {
+	if f.gasFeeCap == nil {
+		return f.gasPrice
+	}
+	price := new(big.Int).Add(f.baseFee, f.gasTipCap)
+	if price.Cmp(f.gasFeeCap) > 0 {
+		return new(big.Int).Set(f.gasFeeCap)
+	}
+	return price
+}
+
+// fees returns the fees of the next claim tx: the max fees of an EIP-1559 tx when they are enabled and the latest
+// block of the L2 network has a base fee, the multiplied suggested gas price of a legacy tx otherwise.
+func (tm *ClaimTxManager) fees(ctx context.Context) (claimFees, error) {
+	if tm.cfg.DynamicFee.Enabled {
+		header, err := tm.l2Node.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return claimFees{}, err
+		}
+		// The networks without EIP-1559 have no base fee
+		if header.BaseFee != nil {
+			return tm.dynamicFees(ctx, header.BaseFee)
+		}
+	}
+	gasPrice, err := tm.l2Node.SuggestGasPrice(ctx)
+	if err != nil {
+		return claimFees{}, err
+	}
+	return claimFees{gasPrice: new(big.Int).Mul(gasPrice, big.NewInt(gasPriceMultiplier))}, nil
+}
+
+// dynamicFees returns the max priority fee per gas of the tip strategy, and the max fee per gas for the base fee,
+// capped by the MaxFeeCap.
+func (tm *ClaimTxManager) dynamicFees(ctx context.Context, baseFee *big.Int) (claimFees, error) {
+	cfg := tm.cfg.DynamicFee
+	tip := new(big.Int)
+	if cfg.Tip != nil {
+		tip.Set(cfg.Tip)
+	}
+	if cfg.TipStrategy == TipStrategySuggested {
+		suggested, err := tm.l2Node.SuggestGasTipCap(ctx)
+		if err != nil {
+			return claimFees{}, err
+		}
+		if suggested.Cmp(tip) > 0 {
+			tip = suggested
+		}
+	}
+	feeCap := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(cfg.BaseFeeMultiplier))
+	feeCap.Add(feeCap, tip)
+	if cfg.MaxFeeCap != nil && cfg.MaxFeeCap.Sign() > 0 && feeCap.Cmp(cfg.MaxFeeCap) > 0 {
+		feeCap.Set(cfg.MaxFeeCap)
+		// The tip can't be higher than the max fee
+		if tip.Cmp(feeCap) > 0 {
+			tip = new(big.Int).Set(feeCap)
+		}
+	}
+	return claimFees{gasTipCap: tip, gasFeeCap: feeCap, baseFee: baseFee}, nil
+}
+
+// Validate returns an error if the enabled fees can't be chosen.
+func (c DynamicFeeConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.TipStrategy != TipStrategySuggested && c.TipStrategy != TipStrategyFixed {
+		return fmt.Errorf("unknown tip strategy %q", c.TipStrategy)
+	}
+	if c.BaseFeeMultiplier == 0 {
+		return errors.New("the base fee multiplier must be at least 1")
+	}
+	if c.Tip != nil && c.Tip.Sign() < 0 {
+		return errors.New("negative tip")
+	}
+	if c.MaxFeeCap != nil && c.MaxFeeCap.Sign() < 0 {
+		return errors.New("negative max fee cap")
+	}
+	return nil
+}
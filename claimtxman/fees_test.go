@@ -0,0 +1,93 @@
+//go:build !readonly
+// +build !readonly
+
+package claimtxman
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// feeNode is a L2 network with the base fee and the suggested fees of the test.
+type feeNode struct {
+	l2NodeInterface
+	baseFee  *big.Int
+	gasPrice *big.Int
+	tip      *big.Int
+}
+
+func (n *feeNode) HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error) {
+	return &ethtypes.Header{Number: big.NewInt(1), BaseFee: n.baseFee}, nil
+}
+
+func (n *feeNode) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(n.gasPrice), nil
+}
+
+func (n *feeNode) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(n.tip), nil
+}
+
+func TestFees(t *testing.T) {
+	ctx := context.Background()
+	node := &feeNode{gasPrice: big.NewInt(5), tip: big.NewInt(3)}
+	tm := &ClaimTxManager{l2Node: node}
+
+	// The legacy txs use the multiplied gas price
+	fees, err := tm.fees(ctx)
+	require.NoError(t, err)
+	require.Nil(t, fees.gasFeeCap)
+	require.Equal(t, big.NewInt(50), fees.gasPrice)
+	require.Equal(t, big.NewInt(50), fees.effectiveGasPrice())
+
+	// The networks without a base fee still get legacy txs
+	tm.cfg.DynamicFee = DynamicFeeConfig{Enabled: true, TipStrategy: TipStrategySuggested, Tip: big.NewInt(1), BaseFeeMultiplier: 2}
+	fees, err = tm.fees(ctx)
+	require.NoError(t, err)
+	require.Nil(t, fees.gasFeeCap)
+	require.Equal(t, big.NewInt(50), fees.gasPrice)
+
+	// The suggested tip is used when it's above the configured one
+	node.baseFee = big.NewInt(100)
+	fees, err = tm.fees(ctx)
+	require.NoError(t, err)
+	require.Nil(t, fees.gasPrice)
+	require.Equal(t, big.NewInt(3), fees.gasTipCap)
+	require.Equal(t, big.NewInt(203), fees.gasFeeCap)
+	require.Equal(t, big.NewInt(103), fees.effectiveGasPrice())
+
+	tm.cfg.DynamicFee.Tip = big.NewInt(10)
+	fees, err = tm.fees(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10), fees.gasTipCap)
+	require.Equal(t, big.NewInt(210), fees.gasFeeCap)
+
+	// The fixed tip ignores the suggested one, and the max fee is capped
+	tm.cfg.DynamicFee = DynamicFeeConfig{Enabled: true, TipStrategy: TipStrategyFixed, Tip: big.NewInt(50), BaseFeeMultiplier: 2, MaxFeeCap: big.NewInt(120)}
+	fees, err = tm.fees(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(50), fees.gasTipCap)
+	require.Equal(t, big.NewInt(120), fees.gasFeeCap)
+	require.Equal(t, big.NewInt(120), fees.effectiveGasPrice())
+
+	tm.cfg.DynamicFee.MaxFeeCap = big.NewInt(40)
+	fees, err = tm.fees(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(40), fees.gasTipCap)
+	require.Equal(t, big.NewInt(40), fees.gasFeeCap)
+}
+
+func TestValidateDynamicFee(t *testing.T) {
+	require.NoError(t, DynamicFeeConfig{TipStrategy: "unknown"}.Validate())
+	cfg := DynamicFeeConfig{Enabled: true, TipStrategy: TipStrategyFixed, Tip: big.NewInt(1), BaseFeeMultiplier: 2}
+	require.NoError(t, cfg.Validate())
+	cfg.TipStrategy = "unknown"
+	require.ErrorContains(t, cfg.Validate(), "unknown tip strategy")
+	cfg.TipStrategy = TipStrategySuggested
+	cfg.BaseFeeMultiplier = 0
+	require.ErrorContains(t, cfg.Validate(), "base fee multiplier")
+}
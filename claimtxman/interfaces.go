@@ -1,21 +1,34 @@
+//go:build !readonly
+// +build !readonly
+
 package claimtxman
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 )
 
 type storageInterface interface {
 	AddBlock(ctx context.Context, block *etherman.Block, dbTx pgx.Tx) (uint64, error)
+	GetReadyL1Deposits(ctx context.Context, exitRoot []byte, dbTx pgx.Tx) ([]*etherman.Deposit, error)
 	UpdateL1DepositsStatus(ctx context.Context, exitRoot []byte, dbTx pgx.Tx) ([]*etherman.Deposit, error)
-	UpdateL2DepositsStatus(ctx context.Context, exitRoot []byte, networkID uint, dbTx pgx.Tx) error
+	UpdateL2DepositsStatus(ctx context.Context, exitRoot []byte, networkID uint, dbTx pgx.Tx) ([]*etherman.Deposit, error)
 	AddClaimTx(ctx context.Context, mTx types.MonitoredTx, dbTx pgx.Tx) error
 	UpdateClaimTx(ctx context.Context, mTx types.MonitoredTx, dbTx pgx.Tx) error
 	GetClaimTxsByStatus(ctx context.Context, statuses []types.MonitoredTxStatus, dbTx pgx.Tx) ([]types.MonitoredTx, error)
+	DenyClaimTxs(ctx context.Context, statuses []types.MonitoredTxStatus, dbTx pgx.Tx) ([]uint, error)
+	AddClaimOutcome(ctx context.Context, outcome *etherman.ClaimOutcome, dbTx pgx.Tx) error
+	GetTokenWrapped(ctx context.Context, originalNetwork uint, originalTokenAddress common.Address, dbTx pgx.Tx) (*etherman.TokenWrapped, error)
+	GetTokenListEntry(ctx context.Context, originalNetwork uint, originalAddress common.Address, dbTx pgx.Tx) (*etherman.TokenListEntry, error)
 	// atomic
 	Rollback(ctx context.Context, dbTx pgx.Tx) error
 	BeginDBTransaction(ctx context.Context) (pgx.Tx, error)
@@ -26,3 +39,21 @@ type bridgeServiceInterface interface {
 	GetClaimProof(depositCnt, networkID uint, dbTx pgx.Tx) (*etherman.GlobalExitRoot, [][bridgectrl.KeyLen]byte, error)
 	GetDepositStatus(ctx context.Context, depositCount uint, destNetworkID uint) (string, error)
 }
+
+// l2NodeInterface is the client of the L2 network the claim txs are sent to.
+type l2NodeInterface interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *ethtypes.Transaction, isPending bool, err error)
+	CheckTxWasMined(ctx context.Context, txHash common.Hash) (bool, *ethtypes.Receipt, error)
+	RevertReason(ctx context.Context, tx *ethtypes.Transaction, blockNumber *big.Int) (string, error)
+	IsClaimed(ctx context.Context, depositCount uint) (bool, error)
+	ClaimedBitMap(ctx context.Context, wordPos uint) (*big.Int, error)
+	IsGlobalExitRootAvailable(ctx context.Context, globalExitRoot common.Hash) (bool, error)
+	BuildSendClaim(ctx context.Context, deposit *etherman.Deposit, smtProof [mtHeight][keyLen]byte, globalExitRoot *etherman.GlobalExitRoot, nonce, gasPrice int64, gasLimit uint64, auth *bind.TransactOpts) (*ethtypes.Transaction, error)
+}
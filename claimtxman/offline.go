@@ -0,0 +1,95 @@
+//go:build !readonly
+// +build !readonly
+
+package claimtxman
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// offlineSigner exports the claim txs to files to be signed offline, for the operators whose policies
+// don't allow online signing keys. The signed txs are submitted back through the admin API.
+type offlineSigner struct {
+	dir  string
+	from common.Address
+}
+
+// unsignedClaimBatch is the content of an exported file.
+type unsignedClaimBatch struct {
+	NetworkID uint              `json:"networkId"`
+	ChainID   uint64            `json:"chainId"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Txs       []unsignedClaimTx `json:"txs"`
+}
+
+type unsignedClaimTx struct {
+	DepositID uint           `json:"depositId"`
+	From      common.Address `json:"from"`
+	// SigningHash is the EIP-155 hash of the tx to be signed
+	SigningHash common.Hash        `json:"signingHash"`
+	Tx          *types.Transaction `json:"tx"`
+}
+
+func newOfflineSigner(dir string, from common.Address) (*offlineSigner, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if from == (common.Address{}) {
+		return nil, fmt.Errorf("the offline signer address is required to sign the claim txs offline")
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil { //nolint:gomnd
+		return nil, err
+	}
+	return &offlineSigner{dir: dir, from: from}, nil
+}
+
+// transactOpts returns the options to build the claim txs without the key, the txs are left unsigned.
+func (s *offlineSigner) transactOpts() *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: s.from,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}
+}
+
+// export writes the unsigned txs of the monitored txs to a new file, returning its path. The file is
+// written to a temporary path first, so a partial file is never picked up by the signing workflow.
+func (s *offlineSigner) export(networkID uint, chainID *big.Int, mTxs []ctmtypes.MonitoredTx, now time.Time) (string, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	batch := unsignedClaimBatch{
+		NetworkID: networkID,
+		ChainID:   chainID.Uint64(),
+		CreatedAt: now.UTC(),
+		Txs:       make([]unsignedClaimTx, 0, len(mTxs)),
+	}
+	for _, mTx := range mTxs {
+		tx := mTx.Tx()
+		batch.Txs = append(batch.Txs, unsignedClaimTx{
+			DepositID:   mTx.DepositID,
+			From:        mTx.From,
+			SigningHash: signer.Hash(tx),
+			Tx:          tx,
+		})
+	}
+	data, err := json.MarshalIndent(batch, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.dir, fmt.Sprintf("unsigned-claims-%d-%d.json", networkID, now.UnixNano()))
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil { //nolint:gomnd
+		return "", err
+	}
+	return path, os.Rename(tmpPath, path)
+}
@@ -0,0 +1,73 @@
+//go:build !readonly
+// +build !readonly
+
+package claimtxman
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineSignerExport(t *testing.T) {
+	_, err := newOfflineSigner(t.TempDir(), common.Address{})
+	require.Error(t, err)
+	signer, err := newOfflineSigner("", common.Address{})
+	require.NoError(t, err)
+	require.Nil(t, signer)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	dir := filepath.Join(t.TempDir(), "claims")
+	signer, err = newOfflineSigner(dir, from)
+	require.NoError(t, err)
+
+	// The claim txs are built without signing them
+	opts := signer.transactOpts()
+	require.Equal(t, from, opts.From)
+	unsignedTx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	tx, err := opts.Signer(from, unsignedTx)
+	require.NoError(t, err)
+	require.Equal(t, unsignedTx.Hash(), tx.Hash())
+
+	to := common.HexToAddress("0x2a3DD3EB832aF982ec71669E178424b10Dca2EDe")
+	mTx := ctmtypes.MonitoredTx{
+		DepositID: 7, From: from, To: &to, Nonce: 2,
+		Value: big.NewInt(0), Data: []byte{1, 2}, Gas: 200000, GasPrice: big.NewInt(1000000000),
+	}
+	chainID := big.NewInt(1001)
+	now := time.Unix(1700000000, 0)
+	path, err := signer.export(1, chainID, []ctmtypes.MonitoredTx{mTx}, now)
+	require.NoError(t, err)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	require.Equal(t, filepath.Join(dir, files[0].Name()), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var batch unsignedClaimBatch
+	require.NoError(t, json.Unmarshal(data, &batch))
+	require.Equal(t, uint(1), batch.NetworkID)
+	require.Equal(t, uint64(1001), batch.ChainID)
+	require.Equal(t, 1, len(batch.Txs))
+	require.Equal(t, uint(7), batch.Txs[0].DepositID)
+	require.Equal(t, mTx.Tx().Hash(), batch.Txs[0].Tx.Hash())
+
+	// Signing the exported hash returns a tx accepted for the monitored tx
+	sig, err := crypto.Sign(batch.Txs[0].SigningHash[:], key)
+	require.NoError(t, err)
+	signedTx, err := batch.Txs[0].Tx.WithSignature(types.NewEIP155Signer(chainID), sig)
+	require.NoError(t, err)
+	require.NoError(t, mTx.CheckSignedTx(signedTx, chainID))
+}
@@ -0,0 +1,85 @@
+//go:build !readonly
+// +build !readonly
+
+package claimtxman
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// privateRelay sends the claim txs through a private tx relay, so they are not exposed in the
+// public mempool until they are mined or the relay timeout expires.
+type privateRelay struct {
+	client  *ethclient.Client
+	timeout time.Duration
+
+	mu sync.Mutex
+	// txs are the txs sent to the relay that are not in the public mempool yet
+	txs map[common.Hash]relayedTx
+}
+
+type relayedTx struct {
+	tx     *types.Transaction
+	sentAt time.Time
+}
+
+func newPrivateRelay(url string, timeout time.Duration) (*privateRelay, error) {
+	if url == "" {
+		return nil, nil
+	}
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &privateRelay{
+		client:  client,
+		timeout: timeout,
+		txs:     make(map[common.Hash]relayedTx),
+	}, nil
+}
+
+// send sends the tx to the relay and keeps it to fall back to the public mempool.
+func (r *privateRelay) send(ctx context.Context, tx *types.Transaction, now time.Time) error {
+	if err := r.client.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.txs[tx.Hash()] = relayedTx{tx: tx, sentAt: now}
+	return nil
+}
+
+// pending returns whether the tx was sent to the relay and it may still be mined by it.
+// The txs whose timeout expired are returned to be sent to the public mempool.
+func (r *privateRelay) pending(txHash common.Hash, now time.Time) (bool, *types.Transaction) {
+	if r == nil {
+		return false, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	relayed, found := r.txs[txHash]
+	if !found {
+		return false, nil
+	}
+	if now.Sub(relayed.sentAt) < r.timeout {
+		return true, nil
+	}
+	delete(r.txs, txHash)
+	return false, relayed.tx
+}
+
+// remove forgets a relayed tx once it's mined.
+func (r *privateRelay) remove(txHash common.Hash) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.txs, txHash)
+}
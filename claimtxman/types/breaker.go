@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+const (
+	// BreakerStateClosed means the claim txs of the network are processed
+	BreakerStateClosed = "closed"
+	// BreakerStateOpen means the claim txs of the network keep failing and they are not processed until the
+	// cooldown expires
+	BreakerStateOpen = "open"
+	// BreakerStateHalfOpen means the cooldown expired and the next cycle probes the network, closing the breaker
+	// if it succeeds and opening it again if it fails
+	BreakerStateHalfOpen = "half_open"
+)
+
+// BreakerState is the state of the circuit breaker of the claim txs of a network.
+type BreakerState struct {
+	NetworkID uint
+	State     string
+	// Failures is the number of consecutive failed cycles
+	Failures  int
+	LastError string
+	// OpenedAt is the time the breaker was opened, zero if it's closed
+	OpenedAt time.Time
+	// RetryAt is the time the breaker is half opened, zero if it's not open
+	RetryAt time.Time
+}
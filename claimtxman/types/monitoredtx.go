@@ -1,7 +1,9 @@
 package types
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -21,6 +23,36 @@ const (
 	// MonitoredTxStatusConfirmed means the tx was already mined and the receipt
 	// status is Successful
 	MonitoredTxStatusConfirmed = MonitoredTxStatus("confirmed")
+
+	// MonitoredTxStatusPendingApproval means the claim value is above the approval threshold
+	// and the tx is parked until an admin approves it
+	MonitoredTxStatusPendingApproval = MonitoredTxStatus("pending_approval")
+
+	// MonitoredTxStatusApproved means the tx was approved by an admin and it's waiting
+	// for the nonce to be assigned before being sent
+	MonitoredTxStatusApproved = MonitoredTxStatus("approved")
+
+	// MonitoredTxStatusPendingSignature means the unsigned tx was exported to be signed offline
+	MonitoredTxStatusPendingSignature = MonitoredTxStatus("pending_signature")
+
+	// MonitoredTxStatusSigned means the tx signed offline was received and it's waiting to be sent
+	MonitoredTxStatusSigned = MonitoredTxStatus("signed")
+
+	// MonitoredTxStatusUneconomical means the deposit is worth less than the gas to claim it
+	// and the tx is parked until an admin approves it
+	MonitoredTxStatusUneconomical = MonitoredTxStatus("uneconomical")
+
+	// MonitoredTxStatusMessageReverted means the claim of the message reverts, its destination rejects it,
+	// and the tx is parked until an admin approves it
+	MonitoredTxStatusMessageReverted = MonitoredTxStatus("message_reverted")
+
+	// MonitoredTxStatusAlreadyClaimed means the deposit was found claimed in the bridge by another tx,
+	// so the tx is not sent
+	MonitoredTxStatusAlreadyClaimed = MonitoredTxStatus("already_claimed")
+
+	// MonitoredTxStatusDenied means the token of the deposit is in the deny list of the operators,
+	// so the tx is not sent
+	MonitoredTxStatusDenied = MonitoredTxStatus("denied")
 )
 
 var (
@@ -63,6 +95,12 @@ type MonitoredTx struct {
 	// GasPrice is the tx gas price
 	GasPrice *big.Int
 
+	// GasTipCap is the max priority fee per gas of the EIP-1559 tx
+	GasTipCap *big.Int
+
+	// GasFeeCap is the max fee per gas of the EIP-1559 tx, nil for a legacy tx
+	GasFeeCap *big.Int
+
 	// Status of this monitoring
 	Status MonitoredTxStatus
 
@@ -76,10 +114,24 @@ type MonitoredTx struct {
 
 	// UpdatedAt last date time it was updated
 	UpdatedAt time.Time
+
+	// SignedTx is the RLP encoded tx signed offline, waiting to be sent
+	SignedTx []byte
 }
 
-// Tx uses the current information to build a tx
+// Tx uses the current information to build a tx, an EIP-1559 tx when the GasFeeCap is set
 func (mTx MonitoredTx) Tx() *types.Transaction {
+	if mTx.GasFeeCap != nil {
+		return types.NewTx(&types.DynamicFeeTx{
+			To:        mTx.To,
+			Nonce:     mTx.Nonce,
+			Value:     mTx.Value,
+			Data:      mTx.Data,
+			Gas:       mTx.Gas,
+			GasTipCap: mTx.GasTipCap,
+			GasFeeCap: mTx.GasFeeCap,
+		})
+	}
 	tx := types.NewTx(&types.LegacyTx{
 		To:       mTx.To,
 		Nonce:    mTx.Nonce,
@@ -92,6 +144,38 @@ func (mTx MonitoredTx) Tx() *types.Transaction {
 	return tx
 }
 
+// CheckSignedTx checks that the tx signed offline was built from the monitored tx, for the chain id of the network
+// the claim tx is sent to. The gas price is chosen when the tx is exported, so it's not checked.
+func (mTx MonitoredTx) CheckSignedTx(tx *types.Transaction, chainID *big.Int) error {
+	// The txs without replay protection have a chain id 0
+	if tx.ChainId().Cmp(chainID) != 0 {
+		return fmt.Errorf("chain id %s instead of %s", tx.ChainId().String(), chainID.String())
+	}
+	sender, err := types.LatestSignerForChainID(tx.ChainId()).Sender(tx)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	value := mTx.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	switch {
+	case sender != mTx.From:
+		return fmt.Errorf("signed by %s instead of %s", sender.Hex(), mTx.From.Hex())
+	case tx.Nonce() != mTx.Nonce:
+		return fmt.Errorf("nonce %d instead of %d", tx.Nonce(), mTx.Nonce)
+	case tx.To() == nil || mTx.To == nil || *tx.To() != *mTx.To:
+		return fmt.Errorf("unexpected receiver %v", tx.To())
+	case tx.Value().Cmp(value) != 0:
+		return fmt.Errorf("value %s instead of %s", tx.Value().String(), value.String())
+	case tx.Gas() != mTx.Gas:
+		return fmt.Errorf("gas %d instead of %d", tx.Gas(), mTx.Gas)
+	case !bytes.Equal(tx.Data(), mTx.Data):
+		return fmt.Errorf("unexpected data")
+	}
+	return nil
+}
+
 // AddHistory adds a transaction to the monitoring history
 func (mTx MonitoredTx) AddHistory(tx *types.Transaction) error {
 	if _, found := mTx.History[tx.Hash()]; found {
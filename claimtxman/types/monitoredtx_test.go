@@ -6,6 +6,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -45,3 +46,55 @@ func TestHistoryHashSlice(t *testing.T) {
 	assert.Equal(t, txs[1].Hash(), common.BytesToHash(history[0]))
 	t.Log("TEST3: ", txs[1].Hash(), common.BytesToHash(history[0]))
 }
+
+func TestCheckSignedTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266")
+	mTx := MonitoredTx{
+		From:  crypto.PubkeyToAddress(key.PublicKey),
+		To:    &to,
+		Nonce: 3,
+		Data:  []byte{1, 2, 3},
+		Gas:   100000,
+	}
+	signer := types.NewEIP155Signer(big.NewInt(1001))
+	unsigned := mTx
+	unsigned.GasPrice = big.NewInt(1000000000)
+	signedTx, err := types.SignTx(unsigned.Tx(), signer, key)
+	require.NoError(t, err)
+	require.NoError(t, mTx.CheckSignedTx(signedTx, big.NewInt(1001)))
+
+	// The tx must be signed by the sender of the monitored tx
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherTx, err := types.SignTx(unsigned.Tx(), signer, otherKey)
+	require.NoError(t, err)
+	assert.ErrorContains(t, mTx.CheckSignedTx(otherTx, big.NewInt(1001)), "signed by")
+
+	// The tx must be signed for the chain of the network
+	assert.ErrorContains(t, mTx.CheckSignedTx(signedTx, big.NewInt(1002)), "chain id")
+	unprotectedTx, err := types.SignTx(unsigned.Tx(), types.HomesteadSigner{}, key)
+	require.NoError(t, err)
+	assert.ErrorContains(t, mTx.CheckSignedTx(unprotectedTx, big.NewInt(1001)), "chain id")
+
+	// The EIP-1559 txs are checked as the legacy ones
+	dynamic := mTx
+	dynamic.GasTipCap, dynamic.GasFeeCap = big.NewInt(1000000000), big.NewInt(3000000000)
+	require.Equal(t, uint8(types.DynamicFeeTxType), dynamic.Tx().Type())
+	dynamicTx, err := types.SignTx(dynamic.Tx(), types.LatestSignerForChainID(big.NewInt(1001)), key)
+	require.NoError(t, err)
+	require.NoError(t, mTx.CheckSignedTx(dynamicTx, big.NewInt(1001)))
+
+	// The tx fields can't be changed by the signer
+	changed := unsigned
+	changed.Data = []byte{4}
+	changedTx, err := types.SignTx(changed.Tx(), signer, key)
+	require.NoError(t, err)
+	assert.ErrorContains(t, mTx.CheckSignedTx(changedTx, big.NewInt(1001)), "unexpected data")
+	changed = unsigned
+	changed.Nonce = 4
+	changedTx, err = types.SignTx(changed.Tx(), signer, key)
+	require.NoError(t, err)
+	assert.ErrorContains(t, mTx.CheckSignedTx(changedTx, big.NewInt(1001)), "nonce")
+}
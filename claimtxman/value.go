@@ -0,0 +1,217 @@
+//go:build !readonly
+// +build !readonly
+
+package claimtxman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/activity"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// priceKey is an original token, the key of its price.
+type priceKey struct {
+	network uint
+	address common.Address
+}
+
+// parsePriceKey parses a key of the prices, "<original network>:<original token address>".
+func parsePriceKey(key string) (priceKey, error) {
+	network, address, found := strings.Cut(key, ":")
+	if !found || !common.IsHexAddress(address) {
+		return priceKey{}, fmt.Errorf("invalid price key %s, it must be <original network>:<original token address>", key)
+	}
+	n, err := strconv.ParseUint(network, 10, 32) //nolint:gomnd
+	if err != nil {
+		return priceKey{}, fmt.Errorf("invalid network of the price key %s: %w", key, err)
+	}
+	return priceKey{network: uint(n), address: common.HexToAddress(address)}, nil
+}
+
+func parsePrices(prices map[string]float64) (map[priceKey]float64, error) {
+	parsed := make(map[priceKey]float64, len(prices))
+	for key, price := range prices {
+		k, err := parsePriceKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if price < 0 {
+			return nil, fmt.Errorf("negative price of %s", key)
+		}
+		parsed[k] = price
+	}
+	return parsed, nil
+}
+
+// priceFeed returns the prices in ether of the tokens, the configured ones and the ones of the feed url, reloaded
+// every interval. A feed that can't be loaded keeps its previous prices.
+type priceFeed struct {
+	configured map[priceKey]float64
+	url        string
+	interval   time.Duration
+	client     *http.Client
+	now        func() time.Time
+
+	mu       sync.Mutex
+	loading  bool
+	loadedAt time.Time
+	feed     map[priceKey]float64
+}
+
+func newPriceFeed(cfg MinClaimValueConfig) (*priceFeed, error) {
+	configured, err := parsePrices(cfg.Prices)
+	if err != nil {
+		return nil, err
+	}
+	return &priceFeed{
+		configured: configured,
+		url:        cfg.PriceFeedURL,
+		interval:   cfg.PriceFeedInterval.Duration,
+		client:     &http.Client{Timeout: cfg.PriceFeedTimeout.Duration},
+		now:        time.Now,
+	}, nil
+}
+
+// price returns the price in ether of a whole original token, false if it's not known.
+func (f *priceFeed) price(ctx context.Context, network uint, address common.Address) (float64, bool) {
+	if address == (common.Address{}) {
+		return 1, true
+	}
+	key := priceKey{network: network, address: address}
+	if f.url != "" {
+		f.reload(ctx)
+		f.mu.Lock()
+		price, found := f.feed[key]
+		f.mu.Unlock()
+		if found {
+			return price, true
+		}
+	}
+	price, found := f.configured[key]
+	return price, found
+}
+
+// reload loads the prices of the feed url when the interval elapsed. The url is requested without holding the lock,
+// the other callers use the previous prices meanwhile instead of waiting for it.
+func (f *priceFeed) reload(ctx context.Context) {
+	f.mu.Lock()
+	if f.loading || (f.feed != nil && f.now().Sub(f.loadedAt) < f.interval) {
+		f.mu.Unlock()
+		return
+	}
+	f.loading = true
+	f.mu.Unlock()
+
+	feed, err := f.load(ctx)
+	if err != nil {
+		log.Warnf("error loading the prices of the claim values, using the previous ones: %v", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		f.feed = feed
+	}
+	// The feed is not requested again until the next interval even if it failed
+	f.loadedAt = f.now()
+	f.loading = false
+}
+
+func (f *priceFeed) load(ctx context.Context) (map[priceKey]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var prices map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return nil, err
+	}
+	return parsePrices(prices)
+}
+
+// claimValue returns the value in wei of the amount of a token with the decimals and the price in ether of a
+// whole token. The value is calculated exactly and rounded down, so the amounts of the tokens with huge supplies are
+// not rounded by a float precision.
+func claimValue(amount *big.Int, decimals uint8, price float64) *big.Int {
+	ratPrice := new(big.Rat).SetFloat64(price)
+	if ratPrice == nil {
+		return big.NewInt(0)
+	}
+	value := new(big.Rat).Mul(new(big.Rat).SetInt(amount), ratPrice)
+	value.Mul(value, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(etherman.EtherDecimals), nil))) //nolint:gomnd
+	value.Quo(value, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))        //nolint:gomnd
+	return new(big.Int).Quo(value.Num(), value.Denom())
+}
+
+// isUneconomical returns if the value is below the cost of the claim tx times the ratio.
+func isUneconomical(value *big.Int, gas uint64, gasPrice *big.Int, ratio float64) bool {
+	ratRatio := new(big.Rat).SetFloat64(ratio)
+	if ratRatio == nil {
+		return false
+	}
+	cost := new(big.Rat).SetInt(new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice))
+	cost.Mul(cost, ratRatio)
+	return new(big.Rat).SetInt(value).Cmp(cost) < 0
+}
+
+// tokenDecimals resolves the decimals of the original token of a deposit from its metadata or from the wrapped
+// token, false if they are not known.
+func (tm *ClaimTxManager) tokenDecimals(deposit *etherman.Deposit) (uint8, bool) {
+	decimals, found, err := etherman.TokenDecimals(deposit.OriginalAddress, deposit.Metadata, func() (*etherman.TokenWrapped, error) {
+		return tm.storage.GetTokenWrapped(tm.ctx, deposit.OriginalNetwork, deposit.OriginalAddress, nil)
+	})
+	if err != nil {
+		activity.For(deposit.NetworkID, deposit.DepositCount).Warnf("error getting the wrapped token of the deposit %d. Error: %v", deposit.DepositCount, err)
+	}
+	return decimals, found
+}
+
+// isClaimUneconomical checks if the deposit is worth less than the gas of its claim tx. The messages, and the
+// deposits of the tokens without a price or decimals, are always claimed.
+func (tm *ClaimTxManager) isClaimUneconomical(ctx context.Context, deposit *etherman.Deposit, gas uint64) (bool, error) {
+	depositLog := activity.For(deposit.NetworkID, deposit.DepositCount)
+	if tm.prices == nil || deposit.LeafType == LeafTypeMessage || deposit.Amount == nil {
+		return false, nil
+	}
+	price, found := tm.prices.price(ctx, deposit.OriginalNetwork, deposit.OriginalAddress)
+	if !found {
+		depositLog.Debugf("no price of the token %d:%s of the deposit %d, it's claimed", deposit.OriginalNetwork, deposit.OriginalAddress.String(), deposit.DepositCount)
+		return false, nil
+	}
+	decimals, found := tm.tokenDecimals(deposit)
+	if !found {
+		depositLog.Debugf("unknown decimals of the token %d:%s of the deposit %d, it's claimed", deposit.OriginalNetwork, deposit.OriginalAddress.String(), deposit.DepositCount)
+		return false, nil
+	}
+	fees, err := tm.fees(ctx)
+	if err != nil {
+		return false, err
+	}
+	// The claim txs are sent with the same fees
+	gasPrice := fees.effectiveGasPrice()
+	value := claimValue(deposit.Amount, decimals, price)
+	if !isUneconomical(value, gas, gasPrice, tm.cfg.MinClaimValue.MinValueRatio) {
+		return false, nil
+	}
+	depositLog.Warnf("the deposit %d is worth %s wei, less than %v times the %d gas at %s wei of its claim tx, parking it until it's approved",
+		deposit.DepositCount, value.String(), tm.cfg.MinClaimValue.MinValueRatio, gas, gasPrice.String())
+	return true, nil
+}
@@ -0,0 +1,133 @@
+//go:build !readonly
+// +build !readonly
+
+package claimtxman
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimValue(t *testing.T) {
+	// 1 unit of a 6 decimals token worth 0.0005 ether
+	require.Equal(t, big.NewInt(500000000000000), claimValue(big.NewInt(1000000), 6, 0.0005))
+	// 2 ether
+	require.Equal(t, big.NewInt(2000000000000000000), claimValue(big.NewInt(2000000000000000000), etherman.EtherDecimals, 1))
+	require.Equal(t, big.NewInt(0), claimValue(big.NewInt(1000), 18, 0))
+	// The amounts of the tokens with huge supplies are not rounded, the fractions of wei are rounded down
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+	require.Equal(t, huge, claimValue(huge, 18, 1))
+	require.Equal(t, big.NewInt(3), claimValue(big.NewInt(7), 18, 0.5))
+
+	// 21000 gas at 10 gwei cost 2.1e14 wei
+	require.True(t, isUneconomical(big.NewInt(200000000000000), 21000, big.NewInt(10000000000), 1))
+	require.False(t, isUneconomical(big.NewInt(210000000000000), 21000, big.NewInt(10000000000), 1))
+	require.True(t, isUneconomical(big.NewInt(300000000000000), 21000, big.NewInt(10000000000), 2))
+}
+
+func TestPriceFeed(t *testing.T) {
+	ctx := context.Background()
+	token := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	other := common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984")
+
+	_, err := parsePriceKey("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	require.Error(t, err)
+	_, err = parsePriceKey("a:0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	require.Error(t, err)
+	key, err := parsePriceKey("1:0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	require.NoError(t, err)
+	require.Equal(t, priceKey{network: 1, address: token}, key)
+
+	feed := map[string]float64{"0:" + token.String(): 0.002}
+	failing := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(feed))
+	}))
+	defer srv.Close()
+	prices, err := newPriceFeed(MinClaimValueConfig{
+		Prices:            map[string]float64{"0:" + token.String(): 0.001, "0:" + other.String(): 0.003},
+		PriceFeedURL:      srv.URL,
+		PriceFeedInterval: types.NewDuration(time.Minute),
+		PriceFeedTimeout:  types.NewDuration(time.Second),
+	})
+	require.NoError(t, err)
+	now := time.Now()
+	prices.now = func() time.Time { return now }
+
+	// The ether is always worth 1 and the feed takes precedence over the configured prices
+	price, found := prices.price(ctx, 0, common.Address{})
+	require.True(t, found)
+	require.Equal(t, float64(1), price)
+	price, found = prices.price(ctx, 0, token)
+	require.True(t, found)
+	require.Equal(t, 0.002, price)
+	price, found = prices.price(ctx, 0, other)
+	require.True(t, found)
+	require.Equal(t, 0.003, price)
+	_, found = prices.price(ctx, 1, token)
+	require.False(t, found)
+
+	// The feed is reloaded every interval, keeping the previous prices when it fails
+	feed["0:"+token.String()] = 0.004
+	price, _ = prices.price(ctx, 0, token)
+	require.Equal(t, 0.002, price)
+	now = now.Add(time.Minute)
+	price, _ = prices.price(ctx, 0, token)
+	require.Equal(t, 0.004, price)
+	failing = true
+	now = now.Add(time.Minute)
+	price, _ = prices.price(ctx, 0, token)
+	require.Equal(t, 0.004, price)
+
+	_, err = newPriceFeed(MinClaimValueConfig{Prices: map[string]float64{"0:" + token.String(): -1}})
+	require.Error(t, err)
+}
+
+func TestPriceFeedLoadWithoutLock(t *testing.T) {
+	ctx := context.Background()
+	token := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	requested, release := make(chan struct{}), make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-release
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]float64{"0:" + token.String(): 0.002}))
+	}))
+	defer srv.Close()
+	prices, err := newPriceFeed(MinClaimValueConfig{
+		Prices:            map[string]float64{"0:" + token.String(): 0.001},
+		PriceFeedURL:      srv.URL,
+		PriceFeedInterval: types.NewDuration(time.Minute),
+		PriceFeedTimeout:  types.NewDuration(time.Second),
+	})
+	require.NoError(t, err)
+
+	loaded := make(chan float64)
+	go func() {
+		price, _ := prices.price(ctx, 0, token)
+		loaded <- price
+	}()
+	<-requested
+	// The other callers don't wait for the feed being loaded, they use the configured price meanwhile
+	price, found := prices.price(ctx, 0, token)
+	require.True(t, found)
+	require.Equal(t, 0.001, price)
+	close(release)
+	require.Equal(t, 0.002, <-loaded)
+	price, _ = prices.price(ctx, 0, token)
+	require.Equal(t, 0.002, price)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/backfill"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagNetworkID = "network-id"
+	flagBatch     = "batch"
+)
+
+// backfillTracesCmd traces the deposit txs synced without their trace, e.g. before the tracing was enabled or
+// while the provider couldn't trace them.
+func backfillTracesCmd(ctx *cli.Context) error {
+	batch := ctx.Uint(flagBatch)
+	if batch == 0 {
+		return fmt.Errorf("the batch must be positive")
+	}
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	l1Etherman, l2Ethermans, err := newEthermans(c)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	var results []backfill.Result
+	for _, client := range append([]*etherman.Client{l1Etherman}, l2Ethermans...) {
+		networkID, err := client.GetNetworkID(ctx.Context)
+		if err != nil {
+			return err
+		}
+		if ctx.IsSet(flagNetworkID) && ctx.Uint(flagNetworkID) != networkID {
+			continue
+		}
+		result, err := backfill.Traces(ctx.Context, storage.(backfill.Storage), client, networkID, batch)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+	}
+	return printResult(ctx, results, func(w io.Writer) error {
+		for _, r := range results {
+			if _, err := fmt.Fprintf(w, "network %d: %d deposit txs traced, %d failed\n", r.NetworkID, r.Traced, r.Failed); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/backup"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/urfave/cli/v2"
+)
+
+// newBackuper returns the backuper of the synchronizer database of the config.
+func newBackuper(ctx *cli.Context) (*backup.Backuper, error) {
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backup.NewBackuper(c.Backup, c.SyncDB, c.BridgeController.Height)
+}
+
+// backupCreateCmd writes a backup of the database, in the directory of the scheduled backups if no file is set.
+func backupCreateCmd(ctx *cli.Context) error {
+	b, err := newBackuper(ctx)
+	if err != nil {
+		return err
+	}
+	file := ctx.String(flagFile)
+	if file == "" {
+		file = b.NewFile()
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil { //nolint:gomnd
+			return err
+		}
+	}
+	result, err := b.Create(ctx.Context, file)
+	if err != nil {
+		return err
+	}
+	return printResult(ctx, result, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "backup %s written, %d bytes\n", result.File, result.Size)
+		return err
+	})
+}
+
+// backupListCmd writes the scheduled backups of the directory.
+func backupListCmd(ctx *cli.Context) error {
+	b, err := newBackuper(ctx)
+	if err != nil {
+		return err
+	}
+	backups, err := b.List()
+	if err != nil {
+		return err
+	}
+	if backups == nil {
+		backups = []backup.Backup{}
+	}
+	return printResult(ctx, backups, func(w io.Writer) error {
+		for _, b := range backups {
+			if _, err := fmt.Fprintf(w, "%s  %d bytes\n", b.File, b.Size); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// backupVerifyCmd restores a backup in the scratch database and checks its exit trees, failing if they are
+// inconsistent.
+func backupVerifyCmd(ctx *cli.Context) error {
+	b, err := newBackuper(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := b.Verify(ctx.Context, ctx.String(flagFile))
+	if err != nil {
+		return err
+	}
+	err = printResult(ctx, result, func(w io.Writer) error {
+		for _, tree := range result.Trees {
+			status := "consistent"
+			if !tree.Contiguous {
+				status = "missing deposits"
+			} else if !tree.Consistent {
+				status = "root mismatch"
+			}
+			if _, err := fmt.Fprintf(w, "network %d: %d deposits, root %s, computed %s: %s\n", tree.NetworkID, tree.Deposits,
+				tree.Root.String(), tree.Computed.String(), status); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !result.Consistent {
+		// The trees are in the result already
+		return cli.Exit(fmt.Sprintf("the exit trees of the backup %s are inconsistent", result.File), 1)
+	}
+	return nil
+}
+
+// backupRestoreCmd replaces the tables of the synchronizer database with the ones of a backup. The service must be
+// stopped, it syncs the blocks after the ones of the backup in the next start.
+func backupRestoreCmd(ctx *cli.Context) error {
+	file := ctx.String(flagFile)
+	if !ctx.Bool(flagYes) {
+		return fmt.Errorf("the tables of the database are replaced with the ones of %s, confirm it with the --%s flag", file, flagYes)
+	}
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	b, err := backup.NewBackuper(c.Backup, c.SyncDB, c.BridgeController.Height)
+	if err != nil {
+		return err
+	}
+	if err := b.Restore(ctx.Context, file, c.SyncDB); err != nil {
+		return err
+	}
+	log.Warnf("database %s restored from %s", c.SyncDB.Name, file)
+	result := struct {
+		File     string `json:"file"`
+		Database string `json:"database"`
+	}{File: file, Database: c.SyncDB.Name}
+	return printResult(ctx, result, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "database %s restored from %s\n", result.Database, result.File)
+		return err
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/urfave/cli/v2"
+)
+
+const flagStatus = "status"
+
+type claimStorage interface {
+	GetClaimTxsByStatus(ctx context.Context, statuses []ctmtypes.MonitoredTxStatus, dbTx pgx.Tx) ([]ctmtypes.MonitoredTx, error)
+	ApproveClaimTx(ctx context.Context, depositID uint, dbTx pgx.Tx) error
+}
+
+// claimTx is a claim tx of the claim tx manager, as listed by the claim list command.
+type claimTx struct {
+	DepositID uint            `json:"deposit_id"`
+	Status    string          `json:"status"`
+	From      common.Address  `json:"from"`
+	To        *common.Address `json:"to"`
+	Nonce     uint64          `json:"nonce"`
+	Gas       uint64          `json:"gas"`
+	// Txs are the hashes of the txs sent for the claim
+	Txs       []common.Hash `json:"txs"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// claimListCmd writes the claim txs of the claim tx manager with the statuses of the flag, the oldest first.
+func claimListCmd(ctx *cli.Context) error {
+	var statuses []ctmtypes.MonitoredTxStatus
+	for _, status := range ctx.StringSlice(flagStatus) {
+		statuses = append(statuses, ctmtypes.MonitoredTxStatus(status))
+	}
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	mTxs, err := storage.(claimStorage).GetClaimTxsByStatus(ctx.Context, statuses, nil)
+	if err != nil {
+		return err
+	}
+	txs := make([]claimTx, 0, len(mTxs))
+	for _, mTx := range mTxs {
+		tx := claimTx{
+			DepositID: mTx.DepositID,
+			Status:    mTx.Status.String(),
+			From:      mTx.From,
+			To:        mTx.To,
+			Nonce:     mTx.Nonce,
+			Gas:       mTx.Gas,
+			Txs:       make([]common.Hash, 0, len(mTx.History)),
+			CreatedAt: mTx.CreatedAt,
+			UpdatedAt: mTx.UpdatedAt,
+		}
+		for hash := range mTx.History {
+			tx.Txs = append(tx.Txs, hash)
+		}
+		txs = append(txs, tx)
+	}
+	return printResult(ctx, txs, func(w io.Writer) error {
+		for _, tx := range txs {
+			if _, err := fmt.Fprintf(w, "deposit %d  %s  nonce %d  gas %d  %d txs  created at %s\n", tx.DepositID, tx.Status, tx.Nonce,
+				tx.Gas, len(tx.Txs), tx.CreatedAt.UTC().Format(time.RFC3339)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// claimApproveCmd approves the parked claim tx of a deposit, as the ApproveClaim endpoint.
+func claimApproveCmd(ctx *cli.Context) error {
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	depositCnt := ctx.Uint(flagDepositCnt)
+	err = storage.(claimStorage).ApproveClaimTx(ctx.Context, depositCnt, nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return fmt.Errorf("the claim tx of the deposit %d is not parked, or its token is in the deny list", depositCnt)
+	} else if err != nil {
+		return err
+	}
+	log.Infof("claim tx of the deposit %d approved", depositCnt)
+	result := struct {
+		DepositID uint   `json:"deposit_id"`
+		Status    string `json:"status"`
+	}{DepositID: depositCnt, Status: ctmtypes.MonitoredTxStatusApproved.String()}
+	return printResult(ctx, result, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "claim tx of the deposit %d approved\n", depositCnt)
+		return err
+	})
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/server"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// networkContracts returns the contracts the service interacts with in the configured networks, with the readers
+// of their code by network id.
+func networkContracts(c *config.Config, l1Etherman *etherman.Client, l2Ethermans []*etherman.Client, networkIDs []uint) ([]server.Contract, map[uint]server.CodeReader) {
+	contracts := []server.Contract{
+		{NetworkID: networkIDs[0], Name: server.ContractBridge, Address: c.NetworkConfig.PolygonBridgeAddress},
+		{NetworkID: networkIDs[0], Name: server.ContractGlobalExitRootManager, Address: c.NetworkConfig.PolygonZkEVMGlobalExitRootAddress},
+	}
+	if c.NetworkConfig.PolygonRollupManagerAddress != (common.Address{}) {
+		contracts = append(contracts, server.Contract{NetworkID: networkIDs[0], Name: server.ContractRollupManager, Address: c.NetworkConfig.PolygonRollupManagerAddress})
+	}
+	readers := map[uint]server.CodeReader{networkIDs[0]: l1Etherman}
+	for i, client := range l2Ethermans {
+		contracts = append(contracts, server.Contract{NetworkID: networkIDs[i+1], Name: server.ContractBridge, Address: c.NetworkConfig.L2PolygonBridgeAddresses[i]})
+		readers[networkIDs[i+1]] = client
+	}
+	return contracts, readers
+}
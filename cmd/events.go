@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/eventbus"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/gerlatency"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/synchronizer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagConsumer = "consumer"
+	flagFrom     = "from"
+)
+
+// Consumers of the event bus subscribed by the service, besides the hooks
+const (
+	claimTxManagerConsumer = "claimtxman"
+	gerLatencyConsumer     = "gerlatency"
+)
+
+type eventsStorage interface {
+	GetBusOffsets(ctx context.Context, dbTx pgx.Tx) ([]etherman.BusOffset, error)
+	GetLastBusEventID(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	SetBusOffset(ctx context.Context, consumer string, eventID uint64, dbTx pgx.Tx) error
+}
+
+// eventsOffsetsResult is the result of the events offsets command.
+type eventsOffsetsResult struct {
+	// LastEventID is the id of the last event published
+	LastEventID uint64           `json:"last_event_id"`
+	Consumers   []eventsConsumer `json:"consumers"`
+}
+
+type eventsConsumer struct {
+	Consumer string `json:"consumer"`
+	// EventID is the id of the last event handled by the consumer
+	EventID   uint64    `json:"event_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// newEventBus creates the event bus the synchronizers publish to, with the claim tx managers and the exit root
+// latency stats subscribed. The hooks are subscribed once they are all registered.
+func newEventBus(c *config.Config, storage interface{}, synchronizers []synchronizer.Synchronizer, exitRootEvents *queue.Queue[*etherman.GlobalExitRoot], l1NetworkID uint) (*eventbus.Bus, error) {
+	bus, err := eventbus.NewBus(c.EventBus, storage)
+	if err != nil {
+		return nil, err
+	}
+	for _, sy := range synchronizers {
+		sy.(*synchronizer.ClientSynchronizer).SetEventBus(bus)
+	}
+	if err := bus.Subscribe(claimTxManagerConsumer, exitRootConsumer(exitRootEvents, l1NetworkID), eventbus.GERUpdated); err != nil {
+		return nil, err
+	}
+	err = bus.Subscribe(gerLatencyConsumer, eventbus.ConsumerFunc(func(_ context.Context, event *eventbus.Event) error {
+		// The injections in the L2 networks are observed by their claim tx managers
+		if event.NetworkID == l1NetworkID {
+			gerlatency.L1Updated(event.GlobalExitRoot.GlobalExitRoot, event.BlockTime)
+		}
+		return nil
+	}), eventbus.GERUpdated)
+	if err != nil {
+		return nil, err
+	}
+	return bus, nil
+}
+
+// exitRootConsumer queues the exit roots for the claim tx managers. The L1 exit roots that don't update the rollup
+// exit root are skipped, as the L1 synchronizer does without the event bus.
+func exitRootConsumer(exitRootEvents *queue.Queue[*etherman.GlobalExitRoot], l1NetworkID uint) eventbus.Consumer {
+	var rollupExitRoot common.Hash
+	return eventbus.ConsumerFunc(func(ctx context.Context, event *eventbus.Event) error {
+		ger := event.GlobalExitRoot
+		if event.NetworkID == l1NetworkID && len(ger.ExitRoots) > 1 {
+			if ger.ExitRoots[1] == rollupExitRoot {
+				return nil
+			}
+			rollupExitRoot = ger.ExitRoots[1]
+		}
+		exitRootEvents.Push(ctx, ger)
+		return nil
+	})
+}
+
+// eventsOffsetsCmd writes the position of each consumer of the event bus.
+func eventsOffsetsCmd(ctx *cli.Context) error {
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	s := storage.(eventsStorage)
+	var result eventsOffsetsResult
+	if result.LastEventID, err = s.GetLastBusEventID(ctx.Context, nil); err != nil {
+		return err
+	}
+	offsets, err := s.GetBusOffsets(ctx.Context, nil)
+	if err != nil {
+		return err
+	}
+	result.Consumers = make([]eventsConsumer, 0, len(offsets))
+	for _, o := range offsets {
+		result.Consumers = append(result.Consumers, eventsConsumer{Consumer: o.Consumer, EventID: o.EventID, UpdatedAt: o.UpdatedAt})
+	}
+	return printResult(ctx, result, func(w io.Writer) error {
+		if _, err := fmt.Fprintf(w, "last event: %d\n", result.LastEventID); err != nil {
+			return err
+		}
+		for _, consumer := range result.Consumers {
+			if _, err := fmt.Fprintf(w, "%s: event %d, updated at %s\n", consumer.Consumer, consumer.EventID, consumer.UpdatedAt.Format(time.RFC3339)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// eventsReplayCmd moves a consumer of the event bus back, so it handles again the events from an id. The running
+// service applies it to the next batch of the consumer.
+func eventsReplayCmd(ctx *cli.Context) error {
+	consumer, from := ctx.String(flagConsumer), ctx.Uint64(flagFrom)
+	if from == 0 {
+		return fmt.Errorf("the ids of the events start at 1")
+	}
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	if err := storage.(eventsStorage).SetBusOffset(ctx.Context, consumer, from-1, nil); err != nil {
+		return err
+	}
+	result := eventsConsumer{Consumer: consumer, EventID: from - 1, UpdatedAt: time.Now()}
+	return printResult(ctx, result, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%s replays the events from %d\n", consumer, from)
+		return err
+	})
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/eventbus"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitRootConsumer(t *testing.T) {
+	ctx := context.Background()
+	exitRootEvents, err := queue.New[*etherman.GlobalExitRoot]("test_exit_roots", queue.Config{Size: 10, Policy: queue.PolicyDropNewest}, nil)
+	require.NoError(t, err)
+	consumer := exitRootConsumer(exitRootEvents, 0)
+	handle := func(networkID uint, mainnet, rollup int64) {
+		ger := &etherman.GlobalExitRoot{ExitRoots: []common.Hash{common.BigToHash(big.NewInt(mainnet)), common.BigToHash(big.NewInt(rollup))}}
+		require.NoError(t, consumer.HandleEvent(ctx, &eventbus.Event{Type: eventbus.GERUpdated, NetworkID: networkID, GlobalExitRoot: ger}))
+	}
+	// The L1 exit roots only updating the mainnet exit root are skipped, the trusted ones are always queued
+	handle(0, 1, 1)
+	handle(0, 2, 1)
+	handle(0, 2, 2)
+	handle(1, 2, 2)
+	require.Len(t, exitRootEvents.C(), 3)
+}
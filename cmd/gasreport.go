@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/gasreport"
+	"github.com/jackc/pgx/v4"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagSince         = "since"
+	flagWindows       = "windows"
+	flagSizes         = "sizes"
+	flagCallOverhead  = "call-overhead"
+	flagBatchOverhead = "batch-overhead"
+)
+
+type gasReportStorage interface {
+	GetClaimGasStats(ctx context.Context, since time.Time, dbTx pgx.Tx) (*etherman.ClaimGasStats, error)
+	GetClaimsGas(ctx context.Context, since time.Time, dbTx pgx.Tx) ([]etherman.ClaimGas, error)
+}
+
+// gasReportCmd replays the confirmed claim txs of the claim tx manager with the batching windows and the multicall
+// sizes of the flags, writing the gas they would have spent.
+func gasReportCmd(ctx *cli.Context) error {
+	// The formats of the report are the outputs of the commands
+	format, err := outputFormat(ctx)
+	if err != nil {
+		return err
+	}
+	params := gasreport.Params{
+		Sizes:         ctx.IntSlice(flagSizes),
+		CallOverhead:  ctx.Uint64(flagCallOverhead),
+		BatchOverhead: ctx.Uint64(flagBatchOverhead),
+	}
+	for _, w := range ctx.StringSlice(flagWindows) {
+		window, err := time.ParseDuration(w)
+		if err != nil || window < 0 {
+			return fmt.Errorf("invalid window %s", w)
+		}
+		params.Windows = append(params.Windows, window)
+	}
+	for _, size := range params.Sizes {
+		if size <= 0 {
+			return fmt.Errorf("invalid size %d, it must be positive", size)
+		}
+	}
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	since := time.Now().Add(-ctx.Duration(flagSince))
+	stats, err := storage.(gasReportStorage).GetClaimGasStats(ctx.Context, since, nil)
+	if err != nil {
+		return err
+	}
+	claimsGas, err := storage.(gasReportStorage).GetClaimsGas(ctx.Context, since, nil)
+	if err != nil {
+		return err
+	}
+	claims := make([]gasreport.Claim, 0, len(claimsGas))
+	for _, c := range claimsGas {
+		claims = append(claims, gasreport.Claim{CreatedAt: c.CreatedAt, GasUsed: c.GasUsed, GasPrice: c.EffectiveGasPrice})
+	}
+	totals := gasreport.Totals{Claims: stats.Claims, From: stats.From, To: stats.To, Gas: stats.GasUsed, Fee: stats.Fee}
+	w, closeFile, err := resultFile(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	return gasreport.Write(w, format, gasreport.NewReport(totals, claims, params))
+}
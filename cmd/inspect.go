@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/urfave/cli/v2"
+)
+
+const flagDepositCnt = "deposit-cnt"
+
+type inspectStorage interface {
+	GetDeposit(ctx context.Context, depositCounterUser uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error)
+	GetClaim(ctx context.Context, depositCount, networkID uint, dbTx pgx.Tx) (*etherman.Claim, error)
+	GetDepositStatusHistory(ctx context.Context, depositCnt, networkID uint, dbTx pgx.Tx) ([]etherman.DepositStatusChange, error)
+	GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error)
+}
+
+// inspectDeposit is the result of the inspect deposit command.
+type inspectDeposit struct {
+	NetworkID     uint           `json:"network_id"`
+	DepositCount  uint           `json:"deposit_cnt"`
+	OrigNet       uint           `json:"orig_net"`
+	OrigAddr      common.Address `json:"orig_addr"`
+	Amount        string         `json:"amount"`
+	DestNet       uint           `json:"dest_net"`
+	DestAddr      common.Address `json:"dest_addr"`
+	BlockNumber   uint64         `json:"block_num"`
+	TxHash        common.Hash    `json:"tx_hash"`
+	ReadyForClaim bool           `json:"ready_for_claim"`
+	// ClaimTxHash is the tx of the claim of the deposit, empty if it's not claimed
+	ClaimTxHash string          `json:"claim_tx_hash,omitempty"`
+	History     []inspectStatus `json:"history"`
+}
+
+type inspectStatus struct {
+	Status      string    `json:"status"`
+	BlockNumber uint64    `json:"block_num,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// inspectSyncNetwork is a network of the result of the inspect sync command.
+type inspectSyncNetwork struct {
+	NetworkID   uint        `json:"network_id"`
+	ChainID     uint64      `json:"chain_id"`
+	BlockNumber uint64      `json:"block_num"`
+	BlockHash   common.Hash `json:"block_hash"`
+	ReceivedAt  time.Time   `json:"received_at"`
+}
+
+// inspectDepositCmd writes a deposit with its claim and the history of its status, read from the database.
+func inspectDepositCmd(ctx *cli.Context) error {
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	s := storage.(inspectStorage)
+	networkID, depositCnt := ctx.Uint(flagNetworkID), ctx.Uint(flagDepositCnt)
+	deposit, err := s.GetDeposit(ctx.Context, depositCnt, networkID, nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return fmt.Errorf("deposit %d of the network %d not found", depositCnt, networkID)
+	} else if err != nil {
+		return err
+	}
+	result := inspectDeposit{
+		NetworkID:     deposit.NetworkID,
+		DepositCount:  deposit.DepositCount,
+		OrigNet:       deposit.OriginalNetwork,
+		OrigAddr:      deposit.OriginalAddress,
+		Amount:        deposit.Amount.String(),
+		DestNet:       deposit.DestinationNetwork,
+		DestAddr:      deposit.DestinationAddress,
+		BlockNumber:   deposit.BlockNumber,
+		TxHash:        deposit.TxHash,
+		ReadyForClaim: deposit.ReadyForClaim,
+		History:       []inspectStatus{},
+	}
+	claim, err := s.GetClaim(ctx.Context, depositCnt, deposit.DestinationNetwork, nil)
+	if err == nil {
+		result.ClaimTxHash = claim.TxHash.String()
+	} else if !errors.Is(err, gerror.ErrStorageNotFound) {
+		return err
+	}
+	history, err := s.GetDepositStatusHistory(ctx.Context, depositCnt, networkID, nil)
+	if err != nil {
+		return err
+	}
+	for _, h := range history {
+		result.History = append(result.History, inspectStatus{Status: h.Status, BlockNumber: h.BlockNumber, Time: h.Time})
+	}
+	return printResult(ctx, result, func(w io.Writer) error {
+		claimed := "not claimed"
+		if result.ClaimTxHash != "" {
+			claimed = "claimed in " + result.ClaimTxHash
+		}
+		_, err := fmt.Fprintf(w, "deposit %d of the network %d to the network %d, %s of %s (%d) to %s\n"+
+			"tx %s in the block %d, ready for claim: %t, %s\n",
+			result.DepositCount, result.NetworkID, result.DestNet, result.Amount, result.OrigAddr.String(), result.OrigNet,
+			result.DestAddr.String(), result.TxHash.String(), result.BlockNumber, result.ReadyForClaim, claimed)
+		if err != nil {
+			return err
+		}
+		for _, h := range result.History {
+			if _, err := fmt.Fprintf(w, "  %s  %s  block %d\n", h.Time.UTC().Format(time.RFC3339), h.Status, h.BlockNumber); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// inspectSyncCmd writes the last block synced of each network.
+func inspectSyncCmd(ctx *cli.Context) error {
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	// The networks are the ones the database was built against
+	pins, err := storage.(networkPinStorage).GetNetworkPins(ctx.Context, nil)
+	if err != nil {
+		return err
+	}
+	networks := make([]inspectSyncNetwork, 0, len(pins))
+	for _, pin := range pins {
+		block, err := storage.(inspectStorage).GetLastBlock(ctx.Context, pin.NetworkID, nil)
+		if errors.Is(err, gerror.ErrStorageNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		networks = append(networks, inspectSyncNetwork{
+			NetworkID:   pin.NetworkID,
+			ChainID:     pin.ChainID,
+			BlockNumber: block.BlockNumber,
+			BlockHash:   block.BlockHash,
+			ReceivedAt:  block.ReceivedAt,
+		})
+	}
+	return printResult(ctx, networks, func(w io.Writer) error {
+		for _, n := range networks {
+			if _, err := fmt.Fprintf(w, "network %d (chain %d): block %d %s received at %s\n", n.NetworkID, n.ChainID, n.BlockNumber, n.BlockHash.String(),
+				n.ReceivedAt.UTC().Format(time.RFC3339)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -3,14 +3,23 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	zkevmbridgeservice "github.com/0xPolygonHermez/zkevm-bridge-service"
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/gasreport"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/proofformat"
 	"github.com/urfave/cli/v2"
 )
 
 const (
 	flagCfg     = "cfg"
 	flagNetwork = "network"
+	flagOutput  = "output"
+	// flagOverrideNetworkPins allows to start with other chains or contracts than the ones the database was built against
+	flagOverrideNetworkPins = "override-network-pins"
+	flagFormat              = "format"
+	flagFile                = "file"
 )
 
 const (
@@ -18,23 +27,29 @@ const (
 	appName = "zkevm-bridge"
 )
 
+// withGlobalFlags appends the global flags to the flags of a command.
+func withGlobalFlags(flags ...cli.Flag) []cli.Flag {
+	return append(flags, globalFlags()...)
+}
+
 func main() {
 	app := cli.NewApp()
 	app.Name = appName
 	app.Version = zkevmbridgeservice.Version
-	flags := []cli.Flag{
-		&cli.StringFlag{
-			Name:     flagCfg,
-			Aliases:  []string{"c"},
-			Usage:    "Configuration `FILE`",
-			Required: false,
-		},
-		&cli.StringFlag{
-			Name:     flagNetwork,
-			Aliases:  []string{"n"},
-			Usage:    "Network: mainnet, testnet, internaltestnet, local. By default it uses mainnet",
-			Required: false,
-		},
+	app.Usage = "Bridge service of the zkEVM networks"
+	app.Flags = globalFlags()
+
+	networkIDFlag := func(usage string) *cli.UintFlag {
+		return &cli.UintFlag{
+			Name:     flagNetworkID,
+			Usage:    usage,
+			Required: true,
+		}
+	}
+	depositCntFlag := &cli.UintFlag{
+		Name:     flagDepositCnt,
+		Usage:    "Deposit count of the deposit",
+		Required: true,
 	}
 
 	app.Commands = []*cli.Command{
@@ -42,14 +57,283 @@ func main() {
 			Name:    "version",
 			Aliases: []string{},
 			Usage:   "Application version and build",
-			Action:  versionCmd,
+			Action:  action(versionCmd),
+			Flags:   withGlobalFlags(),
 		},
 		{
 			Name:    "run",
 			Aliases: []string{},
 			Usage:   "Run the zkevm bridge",
 			Action:  start,
-			Flags:   flags,
+			Flags: withGlobalFlags(
+				&cli.BoolFlag{
+					Name:     flagOverrideNetworkPins,
+					Usage:    "Replace the chain ids and contract addresses the database was built against with the configured ones",
+					Required: false,
+				},
+			),
+		},
+		{
+			Name:    "migrate",
+			Aliases: []string{},
+			Usage:   "Apply the pending migrations to the database without starting the service",
+			Action:  action(migrateCmd),
+			Flags:   withGlobalFlags(),
+		},
+		{
+			Name:  "backfill",
+			Usage: "Fill the data of the synced events recorded after they were synced",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "traces",
+					Usage:  "Trace the deposit txs synced without their originator and call path",
+					Action: action(backfillTracesCmd),
+					Flags: withGlobalFlags(
+						&cli.UintFlag{
+							Name:  flagNetworkID,
+							Usage: "Network of the deposits, all the networks if it's not set",
+						},
+						&cli.UintFlag{
+							Name:  flagBatch,
+							Usage: "Number of deposits read at once",
+							Value: 100, //nolint:gomnd
+						},
+					),
+				},
+			},
+		},
+		{
+			Name:  "events",
+			Usage: "Read and move the positions of the consumers of the event bus",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "offsets",
+					Usage:  "Write the last event handled by each consumer",
+					Action: action(eventsOffsetsCmd),
+					Flags:  withGlobalFlags(),
+				},
+				{
+					Name:   "replay",
+					Usage:  "Make a consumer handle again the events from an id",
+					Action: action(eventsReplayCmd),
+					Flags: withGlobalFlags(
+						&cli.StringFlag{
+							Name:     flagConsumer,
+							Usage:    "Name of the consumer, hook:<name> for the hooks",
+							Required: true,
+						},
+						&cli.Uint64Flag{
+							Name:     flagFrom,
+							Usage:    "Id of the first event handled again",
+							Required: true,
+						},
+					),
+				},
+			},
+		},
+		{
+			Name:  "inspect",
+			Usage: "Read the synced state from the database",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "deposit",
+					Usage:  "Write a deposit with its claim and the history of its status",
+					Action: action(inspectDepositCmd),
+					Flags:  withGlobalFlags(networkIDFlag("Network of the deposit"), depositCntFlag),
+				},
+				{
+					Name:   "sync",
+					Usage:  "Write the last block synced of each network",
+					Action: action(inspectSyncCmd),
+					Flags:  withGlobalFlags(),
+				},
+			},
+		},
+		{
+			Name:  "claim",
+			Usage: "Operate the claim txs of the claim tx manager",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List the claim txs with a status, the oldest first",
+					Action: action(claimListCmd),
+					Flags: withGlobalFlags(
+						&cli.StringSliceFlag{
+							Name:  flagStatus,
+							Usage: "Statuses of the claim txs",
+							Value: cli.NewStringSlice(ctmtypes.MonitoredTxStatusCreated.String(), ctmtypes.MonitoredTxStatusFailed.String(),
+								ctmtypes.MonitoredTxStatusPendingApproval.String(), ctmtypes.MonitoredTxStatusUneconomical.String(), ctmtypes.MonitoredTxStatusMessageReverted.String(), ctmtypes.MonitoredTxStatusApproved.String(),
+								ctmtypes.MonitoredTxStatusPendingSignature.String(), ctmtypes.MonitoredTxStatusSigned.String()),
+						},
+					),
+				},
+				{
+					Name:   "approve",
+					Usage:  "Approve the parked claim tx of a deposit, pending of approval, uneconomical or of a reverting message",
+					Action: action(claimApproveCmd),
+					Flags:  withGlobalFlags(depositCntFlag),
+				},
+			},
+		},
+		{
+			Name:  "proof",
+			Usage: "Convert the proofs of the claims for the external tooling",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "format",
+					Usage:  "Encode the inputs of the claim of a claim witness, the response of GET /claim-witness",
+					Action: action(proofFormatCmd),
+					Flags: withGlobalFlags(
+						&cli.StringFlag{
+							Name:  flagFormat,
+							Usage: "Format of the claim inputs: hex, json, tuple or cast",
+							Value: proofformat.FormatCast,
+						},
+						&cli.StringFlag{
+							Name:    flagInput,
+							Aliases: []string{"i"},
+							Usage:   "Claim witness `FILE`, the stdin if it's not set",
+						},
+					),
+				},
+			},
+		},
+		{
+			Name:   "rollback",
+			Usage:  "Remove the blocks of a network after a block, the service must be stopped",
+			Action: action(rollbackCmd),
+			Flags: withGlobalFlags(
+				networkIDFlag("Network of the blocks"),
+				&cli.Uint64Flag{
+					Name:     flagBlock,
+					Usage:    "Last block kept",
+					Required: true,
+				},
+				&cli.BoolFlag{
+					Name:  flagYes,
+					Usage: "Confirm the removal of the blocks",
+				},
+			),
+		},
+		{
+			Name:  "backup",
+			Usage: "Write, verify and restore the backups of the database",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "create",
+					Usage:  "Write a consistent backup of the database with pg_dump",
+					Action: action(backupCreateCmd),
+					Flags: withGlobalFlags(
+						&cli.StringFlag{
+							Name:    flagFile,
+							Aliases: []string{"f"},
+							Usage:   "Backup `FILE`, a new file of the backups directory if it's not set",
+						},
+					),
+				},
+				{
+					Name:   "list",
+					Usage:  "List the backups of the backups directory",
+					Action: action(backupListCmd),
+					Flags:  withGlobalFlags(),
+				},
+				{
+					Name:   "verify",
+					Usage:  "Restore a backup in the scratch database and check the roots of its exit trees",
+					Action: action(backupVerifyCmd),
+					Flags: withGlobalFlags(
+						&cli.StringFlag{
+							Name:     flagFile,
+							Aliases:  []string{"f"},
+							Usage:    "Backup `FILE`",
+							Required: true,
+						},
+					),
+				},
+				{
+					Name:   "restore",
+					Usage:  "Replace the tables of the database with the ones of a backup, the service must be stopped",
+					Action: action(backupRestoreCmd),
+					Flags: withGlobalFlags(
+						&cli.StringFlag{
+							Name:     flagFile,
+							Aliases:  []string{"f"},
+							Usage:    "Backup `FILE`",
+							Required: true,
+						},
+						&cli.BoolFlag{
+							Name:  flagYes,
+							Usage: "Confirm the replacement of the tables",
+						},
+					),
+				},
+			},
+		},
+		{
+			Name:  "report",
+			Usage: "Write the reports of the operation of the service",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "gas",
+					Usage:  "Estimate the gas the past claim txs would have spent with other batching windows and multicall sizes",
+					Action: action(gasReportCmd),
+					Flags: withGlobalFlags(
+						&cli.DurationFlag{
+							Name:  flagSince,
+							Usage: "Replay the claim txs created in the last `DURATION`",
+							Value: 30 * 24 * time.Hour, //nolint:gomnd
+						},
+						&cli.StringSliceFlag{
+							Name:  flagWindows,
+							Usage: "Max times a batch waits for more claims",
+							Value: cli.NewStringSlice("0s", "30s", "1m", "5m", "15m"),
+						},
+						&cli.IntSliceFlag{
+							Name:  flagSizes,
+							Usage: "Max numbers of claims of a multicall",
+							Value: cli.NewIntSlice(1, 5, 10, 20), //nolint:gomnd
+						},
+						&cli.Uint64Flag{
+							Name:  flagCallOverhead,
+							Usage: "Gas a multicall spends on each of its calls",
+							Value: gasreport.DefaultCallOverhead,
+						},
+						&cli.Uint64Flag{
+							Name:  flagBatchOverhead,
+							Usage: "Gas a multicall spends on top of its calls",
+							Value: gasreport.DefaultBatchOverhead,
+						},
+						fileFlag,
+					),
+				},
+				{
+					Name:   "stuck",
+					Usage:  "Write the deposits stuck beyond the SLAs of the watchdog, grouped by their likely cause",
+					Action: action(reportStuckCmd),
+					Flags:  withGlobalFlags(),
+				},
+			},
+		},
+		{
+			Name:    "validate-config",
+			Aliases: []string{},
+			Usage:   "Check the configuration without connecting to the providers or the database",
+			Action:  action(validateConfigCmd),
+			Flags:   withGlobalFlags(),
+		},
+		{
+			Name:    "schema",
+			Aliases: []string{},
+			Usage:   "Migrate the database and write its schema as documented SQL or as an entity relationship diagram",
+			Action:  action(schemaCmd),
+			Flags: withGlobalFlags(
+				&cli.StringFlag{
+					Name:  flagFormat,
+					Usage: "Format of the schema: sql, mermaid or dot",
+					Value: "sql",
+				},
+				fileFlag,
+			),
 		},
 	}
 
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/urfave/cli/v2"
+)
+
+// migrateResult is the result of the migrate command.
+type migrateResult struct {
+	Database string `json:"database"`
+	// Migrations are the ids of the migrations applied to the database, in order
+	Migrations []string `json:"migrations"`
+}
+
+// migrateCmd applies the pending migrations to the database of the config, without starting the service.
+func migrateCmd(ctx *cli.Context) error {
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if err = db.RunMigrations(c.SyncDB); err != nil {
+		return err
+	}
+	migrations, err := db.GetMigrations(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	result := migrateResult{Database: c.SyncDB.Name, Migrations: migrations}
+	return printResult(ctx, result, func(w io.Writer) error {
+		last := "none"
+		if len(migrations) > 0 {
+			last = migrations[len(migrations)-1]
+		}
+		_, err := fmt.Fprintf(w, "database %s migrated, %d migrations applied, the last one %s\n", result.Database, len(migrations), last)
+		return err
+	})
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/tuning"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Output formats of the commands
+const (
+	// outputText is the output for the operators
+	outputText = "text"
+	// outputJSON is the output for the scripts, a JSON document on the stdout, with the logs on the stderr
+	outputJSON = "json"
+)
+
+// globalFlags returns the flags accepted before the command and after it, e.g. `--cfg FILE run` and `run --cfg FILE`.
+// They are new instances on each call, so the value set in one place doesn't hide the other.
+func globalFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     flagCfg,
+			Aliases:  []string{"c"},
+			Usage:    "Configuration `FILE`",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     flagNetwork,
+			Aliases:  []string{"n"},
+			Usage:    "Network: mainnet, testnet, internaltestnet, local. By default it uses mainnet",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     flagOutput,
+			Aliases:  []string{"o"},
+			Usage:    "Output of the command: text or json",
+			Value:    outputText,
+			Required: false,
+		},
+	}
+}
+
+// fileFlag is the flag of the file a command writes its result in.
+var fileFlag = &cli.StringFlag{
+	Name:    flagFile,
+	Aliases: []string{"f"},
+	Usage:   "Output `FILE`, the stdout if it's not set",
+}
+
+// globalString returns the value of a global flag, set after the command or before it.
+func globalString(ctx *cli.Context, name string) string {
+	for _, c := range ctx.Lineage() {
+		if c.IsSet(name) {
+			return c.String(name)
+		}
+	}
+	return ctx.String(name)
+}
+
+// outputFormat returns the output of the command, checking it's known.
+func outputFormat(ctx *cli.Context) (string, error) {
+	output := globalString(ctx, flagOutput)
+	if output != outputText && output != outputJSON {
+		return "", fmt.Errorf("invalid output %s, it must be %s or %s", output, outputText, outputJSON)
+	}
+	return output, nil
+}
+
+// loadConfig loads the config of the global flags. The logs of the commands other than run are written in the
+// stderr, so the stdout only has the result.
+func loadConfig(ctx *cli.Context) (*config.Config, error) {
+	c, err := config.Load(globalString(ctx, flagCfg), globalString(ctx, flagNetwork))
+	if err != nil {
+		return nil, err
+	}
+	c.Log.Outputs = []string{"stderr"}
+	setupLog(c.Log)
+	tune(c)
+	return c, nil
+}
+
+// tune sizes the runtime, the database pools and the workers of the config for the limits of the container, in the
+// service and in the commands, so the commands connect to the database with the same pools.
+func tune(c *config.Config) {
+	tuner := tuning.NewTuner(c.Tuning)
+	tuner.Apply()
+	c.SyncDB.MaxConns = tuner.DBConns(c.SyncDB.MaxConns)
+	c.BridgeServer.DB.MaxConns = tuner.DBConns(c.BridgeServer.DB.MaxConns)
+	c.Webhook.Workers = tuner.Workers(c.Webhook.Workers)
+	c.Push.Workers = tuner.Workers(c.Push.Workers)
+}
+
+// printResult writes the result of a command: as JSON for the json output, otherwise with the text function.
+func printResult(ctx *cli.Context, result interface{}, text func(w io.Writer) error) error {
+	output, err := outputFormat(ctx)
+	if err != nil {
+		return err
+	}
+	return writeResult(os.Stdout, output, result, text)
+}
+
+func writeResult(w io.Writer, output string, result interface{}, text func(w io.Writer) error) error {
+	if output == outputJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	return text(w)
+}
+
+// resultFile returns the writer of the result of a command, the file of the file flag or the stdout. The file is
+// closed by the returned function.
+func resultFile(ctx *cli.Context) (io.Writer, func(), error) {
+	file := ctx.String(flagFile)
+	if file == "" {
+		return os.Stdout, func() {}, nil
+	}
+	f, err := os.OpenFile(filepath.Clean(file), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) //nolint:gomnd
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() {
+		if err := f.Close(); err != nil {
+			log.Errorf("error closing %s: %v", file, err)
+		}
+	}, nil
+}
+
+// action wraps the action of a command so its error is written as a JSON document for the json output. The exit
+// errors are returned as they are, their result is already written.
+func action(run cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		err := run(ctx)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(cli.ExitCoder); ok {
+			return err
+		}
+		if output, _ := outputFormat(ctx); output != outputJSON {
+			return err
+		}
+		if writeErr := writeResult(os.Stdout, outputJSON, struct {
+			Error string `json:"error"`
+		}{Error: err.Error()}, nil); writeErr != nil {
+			return err
+		}
+		return cli.Exit("", 1)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+)
+
+type networkPinStorage interface {
+	GetNetworkPins(ctx context.Context, dbTx pgx.Tx) ([]*etherman.NetworkPin, error)
+	SetNetworkPin(ctx context.Context, pin *etherman.NetworkPin, dbTx pgx.Tx) error
+}
+
+// networkPins returns the chain ids and contract addresses of the configured networks.
+func networkPins(ctx context.Context, c *config.Config, l1Etherman *etherman.Client, l2Ethermans []*etherman.Client, networkIDs []uint) ([]*etherman.NetworkPin, error) {
+	chainID, err := l1Etherman.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pins := []*etherman.NetworkPin{{
+		NetworkID:             networkIDs[0],
+		ChainID:               chainID,
+		BridgeAddress:         c.NetworkConfig.PolygonBridgeAddress,
+		GlobalExitRootAddress: c.NetworkConfig.PolygonZkEVMGlobalExitRootAddress,
+	}}
+	for i, client := range l2Ethermans {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pins = append(pins, &etherman.NetworkPin{
+			NetworkID:     networkIDs[i+1],
+			ChainID:       chainID,
+			BridgeAddress: c.NetworkConfig.L2PolygonBridgeAddresses[i],
+		})
+	}
+	return pins, nil
+}
+
+// checkNetworkPins refuses to use a database built against other chains or contracts than the configured ones,
+// unless the override is set. The networks not pinned yet are pinned to the configured ones.
+func checkNetworkPins(ctx context.Context, storage interface{}, pins []*etherman.NetworkPin, override bool) error {
+	st := storage.(networkPinStorage)
+	stored, err := st.GetNetworkPins(ctx, nil)
+	if err != nil {
+		return err
+	}
+	mismatches := pinMismatches(stored, pins)
+	if len(mismatches) > 0 {
+		if !override {
+			return fmt.Errorf("the config doesn't match the networks the database was built against: %s. Use the --%s flag to replace them",
+				strings.Join(mismatches, "; "), flagOverrideNetworkPins)
+		}
+		log.Warnf("replacing the networks the database was built against: %s", strings.Join(mismatches, "; "))
+	}
+	pinned := make(map[uint]bool, len(stored))
+	for _, pin := range stored {
+		pinned[pin.NetworkID] = true
+	}
+	for _, pin := range pins {
+		if pinned[pin.NetworkID] && !override {
+			continue
+		}
+		if err := st.SetNetworkPin(ctx, pin, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pinMismatches(stored, pins []*etherman.NetworkPin) []string {
+	storedByNetwork := make(map[uint]*etherman.NetworkPin, len(stored))
+	for _, pin := range stored {
+		storedByNetwork[pin.NetworkID] = pin
+	}
+	var mismatches []string
+	for _, pin := range pins {
+		prev, found := storedByNetwork[pin.NetworkID]
+		if !found {
+			continue
+		}
+		if prev.ChainID != pin.ChainID {
+			mismatches = append(mismatches, fmt.Sprintf("network %d chain id %d instead of %d", pin.NetworkID, pin.ChainID, prev.ChainID))
+		}
+		if prev.BridgeAddress != pin.BridgeAddress {
+			mismatches = append(mismatches, fmt.Sprintf("network %d bridge address %s instead of %s", pin.NetworkID, pin.BridgeAddress.String(), prev.BridgeAddress.String()))
+		}
+		if prev.GlobalExitRootAddress != pin.GlobalExitRootAddress {
+			mismatches = append(mismatches, fmt.Sprintf("network %d global exit root address %s instead of %s", pin.NetworkID, pin.GlobalExitRootAddress.String(), prev.GlobalExitRootAddress.String()))
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type pinStorage struct {
+	pins map[uint]*etherman.NetworkPin
+}
+
+func (s *pinStorage) GetNetworkPins(context.Context, pgx.Tx) ([]*etherman.NetworkPin, error) {
+	var pins []*etherman.NetworkPin
+	for _, pin := range s.pins {
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+func (s *pinStorage) SetNetworkPin(_ context.Context, pin *etherman.NetworkPin, _ pgx.Tx) error {
+	s.pins[pin.NetworkID] = pin
+	return nil
+}
+
+func TestCheckNetworkPins(t *testing.T) {
+	ctx := context.Background()
+	mainnet := []*etherman.NetworkPin{
+		{NetworkID: 0, ChainID: 1, BridgeAddress: common.HexToAddress("0x2a3DD3EB832aF982ec71669E178424b10Dca2EDe"), GlobalExitRootAddress: common.HexToAddress("0x580bda1e7A0CFAe92Fa7F6c20A3794F169CE3CFb")},
+		{NetworkID: 1, ChainID: 1101, BridgeAddress: common.HexToAddress("0x2a3DD3EB832aF982ec71669E178424b10Dca2EDe")},
+	}
+	testnet := []*etherman.NetworkPin{
+		{NetworkID: 0, ChainID: 5, BridgeAddress: common.HexToAddress("0xF6BEEeBB578e214CA9E23B0e9683454Ff88Ed2A7"), GlobalExitRootAddress: common.HexToAddress("0x4d9427DCA0406358445bC0a8F88C26b704004f74")},
+		{NetworkID: 1, ChainID: 1442, BridgeAddress: common.HexToAddress("0xF6BEEeBB578e214CA9E23B0e9683454Ff88Ed2A7")},
+	}
+	st := &pinStorage{pins: make(map[uint]*etherman.NetworkPin)}
+
+	// The first start pins the networks
+	require.NoError(t, checkNetworkPins(ctx, st, mainnet, false))
+	require.Len(t, st.pins, 2)
+	require.NoError(t, checkNetworkPins(ctx, st, mainnet, false))
+
+	// Other networks are refused without the override
+	err := checkNetworkPins(ctx, st, testnet, false)
+	require.ErrorContains(t, err, "network 0 chain id 5 instead of 1")
+	require.ErrorContains(t, err, "network 1 bridge address")
+	require.Equal(t, uint64(1), st.pins[0].ChainID)
+	require.Len(t, pinMismatches(mainnet, testnet), 5)
+
+	// The override replaces the pins
+	require.NoError(t, checkNetworkPins(ctx, st, testnet, true))
+	require.Equal(t, uint64(5), st.pins[0].ChainID)
+	require.Equal(t, uint64(1442), st.pins[1].ChainID)
+	require.NoError(t, checkNetworkPins(ctx, st, testnet, false))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/proofformat"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const flagInput = "input"
+
+// proofFormatResult is the result of the proof format command.
+type proofFormatResult struct {
+	Format  string `json:"format"`
+	Encoded string `json:"encoded"`
+}
+
+// proofFormatCmd encodes the inputs of the claim of a claim witness, the response of GET /claim-witness read from
+// the input file or the stdin, in the format of the flag.
+func proofFormatCmd(ctx *cli.Context) error {
+	format := ctx.String(flagFormat)
+	if !proofformat.IsFormat(format) {
+		return fmt.Errorf("invalid format %s, it must be %s, %s, %s or %s", format, proofformat.FormatHex, proofformat.FormatJSON,
+			proofformat.FormatTuple, proofformat.FormatCast)
+	}
+	r := io.Reader(os.Stdin)
+	if input := ctx.String(flagInput); input != "" {
+		f, err := os.Open(filepath.Clean(input))
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck
+		r = f
+	}
+	witness, err := readClaimWitness(r)
+	if err != nil {
+		return err
+	}
+	encoded, err := proofformat.Encode(format, witness)
+	if err != nil {
+		return err
+	}
+	result := proofFormatResult{Format: format, Encoded: encoded}
+	return printResult(ctx, result, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, result.Encoded)
+		return err
+	})
+}
+
+// readClaimWitness reads a claim witness as the JSON of the GET /claim-witness response.
+func readClaimWitness(r io.Reader) (*pb.ClaimWitness, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var res pb.GetClaimWitnessResponse
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error reading the claim witness: %w", err)
+	}
+	if res.Witness == nil {
+		return nil, fmt.Errorf("error reading the claim witness: no witness in the input")
+	}
+	return res.Witness, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/watchdog"
+	"github.com/urfave/cli/v2"
+)
+
+// reportStuckCmd writes the deposits stuck beyond the SLAs of the watchdog config, grouped by their likely cause,
+// without alerting on them.
+func reportStuckCmd(ctx *cli.Context) error {
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	w, err := watchdog.NewWatchdog(c.Watchdog, storage)
+	if err != nil {
+		return err
+	}
+	alerts, err := w.Check(ctx.Context)
+	if err != nil {
+		return err
+	}
+	if alerts == nil {
+		alerts = []watchdog.Alert{}
+	}
+	return printResult(ctx, alerts, func(w io.Writer) error {
+		if len(alerts) == 0 {
+			_, err := fmt.Fprintln(w, "no stuck deposits")
+			return err
+		}
+		for _, a := range alerts {
+			if _, err := fmt.Fprintf(w, "%s/%s: %d deposits beyond %s, the oldest since %s\n", a.Status, a.Cause, a.Count, a.SLA,
+				a.OldestSince.UTC().Format(time.RFC3339)); err != nil {
+				return err
+			}
+			for _, d := range a.Deposits {
+				if _, err := fmt.Fprintf(w, "  %d/%d to %d  %s\n", d.NetworkID, d.DepositCount, d.DestinationNetwork, d.TxHash.String()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagBlock = "block"
+	flagYes   = "yes"
+)
+
+type rollbackStorage interface {
+	BeginDBTransaction(ctx context.Context) (pgx.Tx, error)
+	Commit(ctx context.Context, dbTx pgx.Tx) error
+	Rollback(ctx context.Context, dbTx pgx.Tx) error
+	Reset(ctx context.Context, blockNumber uint64, networkID uint, dbTx pgx.Tx) error
+	GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error)
+	GetNumberDeposits(ctx context.Context, networkID uint, blockNumber uint64, dbTx pgx.Tx) (uint64, error)
+}
+
+// rollbackResult is the result of the rollback command.
+type rollbackResult struct {
+	NetworkID uint `json:"network_id"`
+	// Block is the last block kept, the next start syncs the network again from the next one
+	Block uint64 `json:"block"`
+	// Deposits is the number of deposits of the network kept
+	Deposits uint64 `json:"deposits"`
+}
+
+// rollbackCmd removes the blocks of a network after a block, with their events, as the synchronizer does on a
+// reorg. The service must be stopped, its exit trees are rebuilt from the kept deposits in the next start.
+func rollbackCmd(ctx *cli.Context) error {
+	networkID, block := ctx.Uint(flagNetworkID), ctx.Uint64(flagBlock)
+	if !ctx.Bool(flagYes) {
+		return fmt.Errorf("the blocks of the network %d after the block %d are removed, confirm it with the --%s flag", networkID, block, flagYes)
+	}
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	s := storage.(rollbackStorage)
+	last, err := s.GetLastBlock(ctx.Context, networkID, nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return fmt.Errorf("the network %d has no synced blocks", networkID)
+	} else if err != nil {
+		return err
+	}
+	if block >= last.BlockNumber {
+		return fmt.Errorf("the block %d is not before the last block %d of the network %d", block, last.BlockNumber, networkID)
+	}
+	dbTx, err := s.BeginDBTransaction(ctx.Context)
+	if err != nil {
+		return err
+	}
+	if err := s.Reset(ctx.Context, block, networkID, dbTx); err != nil {
+		if rollbackErr := s.Rollback(ctx.Context, dbTx); rollbackErr != nil {
+			log.Errorf("error rolling back the reset of the network %d: %v", networkID, rollbackErr)
+		}
+		return err
+	}
+	if err := s.Commit(ctx.Context, dbTx); err != nil {
+		return err
+	}
+	deposits, err := s.GetNumberDeposits(ctx.Context, networkID, math.MaxInt64, nil)
+	if err != nil {
+		return err
+	}
+	log.Warnf("network %d rolled back from the block %d to the block %d", networkID, last.BlockNumber, block)
+	result := rollbackResult{NetworkID: networkID, Block: block, Deposits: deposits}
+	return printResult(ctx, result, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "network %d rolled back to the block %d, %d deposits kept\n", result.NetworkID, result.Block, result.Deposits)
+		return err
+	})
+}
@@ -1,31 +1,89 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/signal"
 
+	zkevmbridgeservice "github.com/0xPolygonHermez/zkevm-bridge-service"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/activity"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/backfill"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/backup"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/blocktime"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
-	"github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/cache"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/changefeed"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/eventbus"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/gerlatency"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/indexadvisor"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/maintenance"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/push"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/reconciliation"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/replication"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/rosetta"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/server"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/statusrules"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/synchronizer"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
-	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/statefile"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/watchdog"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/webhook"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/urfave/cli/v2"
 )
 
-func start(ctx *cli.Context) error {
-	configFilePath := ctx.String(flagCfg)
-	network := ctx.String(flagNetwork)
+// claimProver is the bridge service used by the claim tx managers and the canary deposits to build the claims.
+type claimProver interface {
+	GetClaimProof(depositCnt, networkID uint, dbTx pgx.Tx) (*etherman.GlobalExitRoot, [][bridgectrl.KeyLen]byte, error)
+	GetDepositStatus(ctx context.Context, depositCount uint, destNetworkID uint) (string, error)
+}
+
+// claimTxManager sends the claim txs of a L2 network, it's not built in the read-only builds.
+type claimTxManager interface {
+	statefile.Exporter
+	server.ClaimBreaker
+	SetMaintenanceMonitor(monitor *maintenance.Monitor)
+	Start()
+}
+
+// canaryDeposits sends the canary deposits, it's not built in the read-only builds.
+type canaryDeposits interface {
+	prometheus.Collector
+	Start(ctx context.Context)
+}
 
-	c, err := config.Load(configFilePath, network)
+func start(ctx *cli.Context) error {
+	output, err := outputFormat(ctx)
 	if err != nil {
 		return err
 	}
+	c, err := config.Load(globalString(ctx, flagCfg), globalString(ctx, flagNetwork))
+	if err != nil {
+		return err
+	}
+	if output == outputJSON {
+		// The service has no result, its logs are written as JSON
+		c.Log.Environment = log.EnvironmentProduction
+	}
 	setupLog(c.Log)
+	tune(c)
+	if c.Synchronizer.Retry.Profile == "" {
+		c.Synchronizer.Retry = c.Etherman.Retry
+	}
+	if registered := hooks.Registered(); len(registered) > 0 {
+		log.Infof("registered hooks: %v", registered)
+	}
+	if zkevmbridgeservice.ReadOnly {
+		log.Info("read-only build, the claim txs, the canary deposits and the reserve attestations are not available")
+	}
 	err = db.RunMigrations(c.SyncDB)
 	if err != nil {
 		log.Error(err)
@@ -61,11 +119,20 @@ func start(ctx *cli.Context) error {
 		log.Error(err)
 		return err
 	}
+	pins, err := networkPins(ctx.Context, c, l1Etherman, l2Ethermans, networkIDs)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if err = checkNetworkPins(ctx.Context, storage, pins, ctx.Bool(flagOverrideNetworkPins)); err != nil {
+		log.Error(err)
+		return err
+	}
 
 	var bridgeController *bridgectrl.BridgeController
 
 	if c.BridgeController.Store == "postgres" {
-		bridgeController, err = bridgectrl.NewBridgeController(c.BridgeController, networkIDs, storage)
+		bridgeController, err = bridgectrl.NewBridgeController(ctx.Context, c.BridgeController, networkIDs, storage)
 		if err != nil {
 			log.Error(err)
 			return err
@@ -80,30 +147,280 @@ func start(ctx *cli.Context) error {
 		log.Error(err)
 		return err
 	}
-	bridgeService := server.NewBridgeService(c.BridgeServer, c.BridgeController.Height, networkIDs, apiStorage)
-	err = server.RunServer(c.BridgeServer, bridgeService)
+	log.Debug("trusted sequencer URL ", c.Etherman.L2URLs[0])
+	zkEVMClient, err := synchronizer.NewQuorumZkEVMClient(c.Etherman.L2URLs[0], c.Etherman.L2Quorum)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	exitRootEvents, err := queue.New[*etherman.GlobalExitRoot]("exit_roots", c.Synchronizer.ExitRootQueue, nil)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
-
-	log.Debug("trusted sequencer URL ", c.Etherman.L2URLs[0])
-	zkEVMClient := client.NewClient(c.Etherman.L2URLs[0])
-	chExitRootEvent := make(chan *etherman.GlobalExitRoot)
 	chSynced := make(chan uint)
-	go runSynchronizer(c.NetworkConfig.GenBlockNumber, bridgeController, l1Etherman, c.Synchronizer, storage, zkEVMClient, chExitRootEvent, chSynced)
+	synchronizers := []synchronizer.Synchronizer{newSynchronizer(c.NetworkConfig.GenBlockNumber, c.Synchronizer.L1Confirmations, bridgeController, l1Etherman, c.Synchronizer, storage, zkEVMClient, exitRootEvents, chSynced)}
+	for i, client := range l2Ethermans {
+		var confirmations uint64
+		if i < len(c.Synchronizer.L2Confirmations) {
+			confirmations = c.Synchronizer.L2Confirmations[i]
+		}
+		synchronizers = append(synchronizers, newSynchronizer(0, confirmations, bridgeController, client, c.Synchronizer, storage, zkEVMClient, exitRootEvents, chSynced))
+	}
+
+	var bus *eventbus.Bus
+	if c.EventBus.Enabled {
+		bus, err = newEventBus(c, storage, synchronizers, exitRootEvents, networkIDs[0])
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	claimSimulators := map[uint]server.ClaimSimulator{networkIDs[0]: l1Etherman}
+	batchReaders := make(map[uint]server.BatchReader, len(l2Ethermans))
+	for i, client := range l2Ethermans {
+		claimSimulators[networkIDs[i+1]] = client
+		batchReaders[networkIDs[i+1]] = client
+	}
+	bridgeService := server.NewBridgeService(c.BridgeServer, c.BridgeController.Height, networkIDs, apiStorage, claimSimulators)
+	bridgeService.SetBatchReaders(batchReaders)
+	bridgeService.SetL1InfoTree(bridgeController)
+	statusRules, err := statusrules.Compile(c.BridgeServer.StatusRules)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	bridgeService.SetStatusRules(statusRules)
+	contracts, codeReaders := networkContracts(c, l1Etherman, l2Ethermans, networkIDs)
+	bridgeService.SetContracts(contracts, config.OfficialDeployments(), codeReaders)
+	// The injections in the L2 networks are observed by their claim tx managers
+	gerlatency.Default.Track(networkIDs[1:]...)
+	prometheus.MustRegister(gerlatency.Default)
+	prometheus.MustRegister(blocktime.Default)
+	prometheus.MustRegister(synchronizer.DepositContinuityErrors)
+	prometheus.MustRegister(watchdog.StageViolations)
+	prometheus.MustRegister(queue.Default)
+	stateModules := []statefile.Exporter{bridgeService, l1Etherman}
 	for _, client := range l2Ethermans {
-		go runSynchronizer(0, bridgeController, client, c.Synchronizer, storage, zkEVMClient, chExitRootEvent, chSynced)
+		stateModules = append(stateModules, client)
 	}
 
-	if c.ClaimTxManager.Enabled {
-		for i := 0; i < len(c.Etherman.L2URLs); i++ {
-			// we should match the orders of L2URLs between etherman and claimtxman
-			// since we are using the networkIDs in the same order
-			claimTxManager, err := claimtxman.NewClaimTxManager(c.ClaimTxManager, chExitRootEvent, chSynced, c.Etherman.L2URLs[i], networkIDs[i+1], c.NetworkConfig.L2PolygonBridgeAddresses[i], bridgeService, storage)
-			if err != nil {
-				log.Fatalf("error creating claim tx manager for L2 %s. Error: %v", c.Etherman.L2URLs[i], err)
+	claimTxManagers, err := newClaimTxManagers(c, networkIDs, exitRootEvents, chSynced, bridgeService, storage)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	claimBreakers := make([]server.ClaimBreaker, 0, len(claimTxManagers))
+	for _, claimTxManager := range claimTxManagers {
+		stateModules = append(stateModules, claimTxManager)
+		claimBreakers = append(claimBreakers, claimTxManager)
+	}
+	bridgeService.SetClaimBreakers(claimBreakers)
+
+	// The maintenance is set in the synchronizer database, which all the writers read
+	maintenanceMonitor := maintenance.NewMonitor(c.Maintenance, storage)
+	bridgeService.SetMaintenanceMonitor(maintenanceMonitor)
+	for _, sy := range synchronizers {
+		sy.(*synchronizer.ClientSynchronizer).SetMaintenanceMonitor(maintenanceMonitor)
+	}
+	for _, claimTxManager := range claimTxManagers {
+		claimTxManager.SetMaintenanceMonitor(maintenanceMonitor)
+	}
+
+	// Restore the in-memory state saved in the previous shutdown
+	err = statefile.Load(c.StateFile, stateModules)
+	if err != nil {
+		log.Warn("error loading the state file, starting with empty in-memory state. Error: ", err)
+	}
+
+	syncStatusReporters := make([]server.SyncStatusReporter, 0, len(synchronizers))
+	for _, sy := range synchronizers {
+		syncStatusReporters = append(syncStatusReporters, sy)
+	}
+	var responseCache *cache.Cache
+	if c.Cache.Enabled {
+		responseCache, err = cache.New(c.Cache)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		// The synced data invalidates the cached responses of all the replicas
+		hooks.Register("cache", responseCache.Hook())
+		go responseCache.Run(ctx.Context)
+		bridgeService.SetCache(responseCache)
+		if c.BridgeServer.Watch.WarmInterval.Duration > 0 {
+			go bridgeService.StartWatchWarmer(ctx.Context)
+		}
+	}
+	if c.ChangeFeed.Enabled {
+		// The changes are notified by the synchronizer database and read from the database of the API
+		feed := changefeed.NewFeed(c.ChangeFeed, storage, apiStorage)
+		feed.SetCache(responseCache)
+		bridgeService.SetChangeFeed(feed)
+		go feed.Start(ctx.Context)
+	}
+	if c.Reserve.Enabled {
+		attester, err := newReserveAttester(c.Reserve, storage)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		bridgeService.SetReserveAttester(attester)
+	}
+	if c.ResponseSigning.Enabled {
+		signer, err := newResponseSigner(c.ResponseSigning, storage)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		bridgeService.SetResponseSigner(signer)
+	}
+	if c.Replication.Enabled {
+		monitor, err := newReplicationMonitor(c, storage, apiStorage, networkIDs)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		prometheus.MustRegister(monitor)
+		bridgeService.SetReplication(monitor)
+		go monitor.Start(ctx.Context)
+	}
+	readiness := server.NewReadinessChecker(c.BridgeServer, apiStorage, syncStatusReporters)
+	err = server.RunServer(c.BridgeServer, bridgeService, readiness)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if c.Rosetta.Enabled {
+		// The Rosetta API reads the synced blocks from the database of the API, as the bridge service
+		rosettaServer, err := rosetta.NewServer(c.Rosetta, apiStorage, networkIDs, zkevmbridgeservice.Version)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		go func() {
+			if err := rosettaServer.Start(ctx.Context); err != nil {
+				log.Error("rosetta api error: ", err)
 			}
+		}()
+	}
+
+	if c.Activity.Enabled {
+		recorder, err := activity.NewRecorder(c.Activity, storage)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		activity.SetRecorder(recorder)
+		hooks.Register("activity", recorder)
+		go recorder.Start(ctx.Context)
+	}
+
+	if c.Webhook.Enabled {
+		dispatcher, err := webhook.NewDispatcher(c.Webhook, storage)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		hooks.Register("webhook", dispatcher)
+		prometheus.MustRegister(dispatcher)
+		go dispatcher.Start(ctx.Context)
+	}
+
+	if c.Push.Enabled {
+		notifier, err := push.NewNotifier(c.Push, storage)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		log.Infof("push notifications sent to the %v devices", notifier.Platforms())
+		hooks.Register("push", notifier)
+		go notifier.Start(ctx.Context)
+	}
+
+	if c.Watchdog.Enabled {
+		stuckDeposits, err := watchdog.NewWatchdog(c.Watchdog, storage)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		prometheus.MustRegister(stuckDeposits)
+		if c.Watchdog.Stages.Enabled() {
+			// The stages are measured from the events of the sync and claim loops
+			hooks.Register("watchdog", stuckDeposits)
+		}
+		go stuckDeposits.Start(ctx.Context)
+	}
+
+	if c.Reconciliation.Enabled {
+		reconciler, err := reconciliation.NewReconciler(c.Reconciliation, storage, networkIDs)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		prometheus.MustRegister(reconciler)
+		go reconciler.Start(ctx.Context)
+	}
+
+	if c.Backup.Enabled {
+		backuper, err := backup.NewBackuper(c.Backup, c.SyncDB, c.BridgeController.Height)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		prometheus.MustRegister(backuper)
+		go backuper.Start(ctx.Context)
+	}
+
+	if c.BridgeServer.Watch.BackfillInterval.Duration > 0 {
+		// The watched addresses are backfilled with the clients of the synced networks
+		tracers := map[uint]backfill.Tracer{networkIDs[0]: l1Etherman}
+		for i, client := range l2Ethermans {
+			tracers[networkIDs[i+1]] = client
+		}
+		watches, err := backfill.NewWatches(storage, tracers, c.BridgeServer.Watch.BackfillInterval.Duration, c.BridgeServer.Watch.BackfillBatch)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		go watches.Start(ctx.Context)
+	}
+
+	if bus != nil {
+		// The hooks consume the synced events from the bus once they are all registered
+		if err := bus.SubscribeHooks(); err != nil {
+			log.Error(err)
+			return err
+		}
+		go bus.Start(ctx.Context)
+	}
+
+	for _, sy := range synchronizers {
+		go runSynchronizer(sy)
+	}
+
+	if c.IndexAdvisor.Enabled {
+		if err := c.IndexAdvisor.Validate(); err != nil {
+			log.Error(err)
+			return err
+		}
+		go indexadvisor.NewAdvisor(c.IndexAdvisor, storage).Start(ctx.Context)
+	}
+
+	if c.Canary.Enabled {
+		canaryDeposits, err := newCanary(ctx.Context, c, networkIDs, bridgeService, storage)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		prometheus.MustRegister(canaryDeposits)
+		go canaryDeposits.Start(ctx.Context)
+	}
+
+	if c.ClaimTxManager.Enabled {
+		for _, claimTxManager := range claimTxManagers {
 			go claimTxManager.Start()
 		}
 	} else {
@@ -111,7 +428,7 @@ func start(ctx *cli.Context) error {
 		go func() {
 			for {
 				select {
-				case <-chExitRootEvent:
+				case <-exitRootEvents.C():
 					log.Debug("New GER received")
 				case netID := <-chSynced:
 					log.Debug("NetworkID synced: ", netID)
@@ -128,6 +445,11 @@ func start(ctx *cli.Context) error {
 	signal.Notify(ch, os.Interrupt)
 	<-ch
 
+	// Dump the in-memory state to be restored in the next start
+	if err := statefile.Save(c.StateFile, stateModules); err != nil {
+		log.Error("error saving the state file. Error: ", err)
+	}
+
 	return nil
 }
 
@@ -135,8 +457,25 @@ func setupLog(c log.Config) {
 	log.Init(c)
 }
 
+// newReplicationMonitor creates the monitor of the configured read replicas, and of the database of the API when
+// it's not the synchronizer one.
+func newReplicationMonitor(c *config.Config, storage, apiStorage interface{}, networkIDs []uint) (*replication.Monitor, error) {
+	var replicas []replication.Replica
+	if c.BridgeServer.DB != c.SyncDB {
+		replicas = append(replicas, replication.Replica{Name: replication.APIReplica, Storage: apiStorage})
+	}
+	for _, replica := range c.Replication.Replicas {
+		replicaStorage, err := db.NewStorage(replica.DB)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to the replica %s: %w", replica.Name, err)
+		}
+		replicas = append(replicas, replication.Replica{Name: replica.Name, Region: replica.Region, Storage: replicaStorage})
+	}
+	return replication.NewMonitor(c.Replication, storage, replicas, networkIDs), nil
+}
+
 func newEthermans(c *config.Config) (*etherman.Client, []*etherman.Client, error) {
-	l1Etherman, err := etherman.NewClient(c.Etherman, c.NetworkConfig.PolygonBridgeAddress, c.NetworkConfig.PolygonZkEVMGlobalExitRootAddress)
+	l1Etherman, err := etherman.NewClient(c.Etherman, c.NetworkConfig.PolygonBridgeAddress, c.NetworkConfig.PolygonZkEVMGlobalExitRootAddress, c.NetworkConfig.PolygonRollupManagerAddress)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -145,7 +484,7 @@ func newEthermans(c *config.Config) (*etherman.Client, []*etherman.Client, error
 	}
 	var l2Ethermans []*etherman.Client
 	for i, addr := range c.L2PolygonBridgeAddresses {
-		l2Etherman, err := etherman.NewL2Client(c.Etherman.L2URLs[i], addr)
+		l2Etherman, err := etherman.NewL2Client(c.Etherman, c.Etherman.L2URLs[i], addr)
 		if err != nil {
 			return l1Etherman, nil, err
 		}
@@ -154,11 +493,16 @@ func newEthermans(c *config.Config) (*etherman.Client, []*etherman.Client, error
 	return l1Etherman, l2Ethermans, nil
 }
 
-func runSynchronizer(genBlockNumber uint64, brdigeCtrl *bridgectrl.BridgeController, etherman *etherman.Client, cfg synchronizer.Config, storage db.Storage, zkEVMClient *client.Client, chExitRootEvent chan *etherman.GlobalExitRoot, chSynced chan uint) {
-	sy, err := synchronizer.NewSynchronizer(storage, brdigeCtrl, etherman, zkEVMClient, genBlockNumber, chExitRootEvent, chSynced, cfg)
+func newSynchronizer(genBlockNumber, confirmations uint64, brdigeCtrl *bridgectrl.BridgeController, etherman *etherman.Client, cfg synchronizer.Config, storage db.Storage, zkEVMClient *synchronizer.QuorumZkEVMClient, exitRootEvents *queue.Queue[*etherman.GlobalExitRoot], chSynced chan uint) synchronizer.Synchronizer {
+	sy, err := synchronizer.NewSynchronizer(storage, brdigeCtrl, etherman, zkEVMClient, genBlockNumber, exitRootEvents, chSynced, cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
+	sy.(*synchronizer.ClientSynchronizer).SetConfirmations(confirmations)
+	return sy
+}
+
+func runSynchronizer(sy synchronizer.Synchronizer) {
 	if err := sy.Sync(); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/schemadoc"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/urfave/cli/v2"
+)
+
+type schemaStorage interface {
+	GetSchema(ctx context.Context) (*schemadoc.Schema, error)
+}
+
+// schemaCmd migrates the database of the config and writes its schema, so the docs are the ones of the
+// migrations of this version. The json output writes the tables, columns and constraints read from the database.
+func schemaCmd(ctx *cli.Context) error {
+	output, err := outputFormat(ctx)
+	if err != nil {
+		return err
+	}
+	format := ctx.String(flagFormat)
+	if !schemadoc.IsFormat(format) {
+		return fmt.Errorf("invalid format %s, it must be %s, %s or %s", format, schemadoc.FormatSQL, schemadoc.FormatMermaid, schemadoc.FormatDot)
+	}
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if err = db.RunMigrations(c.SyncDB); err != nil {
+		return err
+	}
+	storage, err := db.NewStorage(c.SyncDB)
+	if err != nil {
+		return err
+	}
+	schema, err := storage.(schemaStorage).GetSchema(ctx.Context)
+	if err != nil {
+		return err
+	}
+	w, closeFile, err := resultFile(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	if file := ctx.String(flagFile); file != "" {
+		defer log.Infof("schema of %d tables written in %s", len(schema.Tables), file)
+	}
+	return writeResult(w, output, schema, func(w io.Writer) error {
+		return schemadoc.Write(w, format, schema)
+	})
+}
@@ -0,0 +1,87 @@
+//go:build !readonly
+// +build !readonly
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/canary"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/reserve"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/server"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/signing"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// validateClaimTxManager returns an error if the enabled claim tx managers can't be created with the config.
+func validateClaimTxManager(cfg claimtxman.Config) error {
+	if err := cfg.DynamicFee.Validate(); err != nil {
+		return fmt.Errorf("DynamicFee: %w", err)
+	}
+	return nil
+}
+
+// newClaimTxManagers creates the claim tx managers of the L2 networks if they are enabled.
+func newClaimTxManagers(c *config.Config, networkIDs []uint, exitRootEvents *queue.Queue[*etherman.GlobalExitRoot], chSynced chan uint, bridgeService claimProver, storage db.Storage) ([]claimTxManager, error) {
+	var (
+		claimTxManagers []claimTxManager
+		managers        []*claimtxman.ClaimTxManager
+	)
+	if !c.ClaimTxManager.Enabled {
+		return nil, nil
+	}
+	for i := 0; i < len(c.Etherman.L2URLs); i++ {
+		// we should match the orders of L2URLs between etherman and claimtxman
+		// since we are using the networkIDs in the same order
+		claimTxManager, err := claimtxman.NewClaimTxManager(c.ClaimTxManager, exitRootEvents, chSynced, c.Etherman.L2URLs[i], networkIDs[i+1], c.NetworkConfig.L2PolygonBridgeAddresses[i], bridgeService, storage)
+		if err != nil {
+			log.Fatalf("error creating claim tx manager for L2 %s. Error: %v", c.Etherman.L2URLs[i], err)
+		}
+		claimTxManagers = append(claimTxManagers, claimTxManager)
+		managers = append(managers, claimTxManager)
+	}
+	// The breakers of all the networks share their metrics
+	prometheus.MustRegister(claimtxman.NewBreakerCollector(managers))
+	return claimTxManagers, nil
+}
+
+// newCanary creates the canary deposits between L1 and the first L2.
+func newCanary(ctx context.Context, c *config.Config, networkIDs []uint, bridgeService claimProver, storage db.Storage) (canaryDeposits, error) {
+	l1Bridge, err := canary.NewBridge(ctx, c.Etherman.L1URL, c.NetworkConfig.PolygonBridgeAddress, c.Canary.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	l2Bridge, err := canary.NewBridge(ctx, c.Etherman.L2URLs[0], c.NetworkConfig.L2PolygonBridgeAddresses[0], c.Canary.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	// The L1 deposits are claimed by the claim tx manager when it's enabled
+	return canary.NewCanary(c.Canary, l1Bridge, l2Bridge, networkIDs[0], networkIDs[1], c.ClaimTxManager.Enabled, bridgeService, storage), nil
+}
+
+// newReserveAttester creates the signer of the proof of reserve attestations.
+func newReserveAttester(cfg reserve.Config, storage db.Storage) (server.ReserveAttester, error) {
+	attester, err := reserve.NewAttester(cfg, storage)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("proof of reserve attestations signed by %s", attester.Signer().String())
+	return attester, nil
+}
+
+// newResponseSigner creates the signer of the responses of the REST API.
+func newResponseSigner(cfg signing.Config, storage db.Storage) (server.ResponseSigner, error) {
+	signer, err := signing.NewSigner(cfg, storage)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("the responses are signed by one of %d operator keys", len(cfg.Keys))
+	return signer, nil
+}
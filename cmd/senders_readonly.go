@@ -0,0 +1,49 @@
+//go:build readonly
+// +build readonly
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/reserve"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/server"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/signing"
+)
+
+// The read-only builds don't link the code sending txs or loading keys, so the modules using it must be disabled.
+var (
+	errReadOnlyClaimTxManager = errors.New("the claim tx manager is not available in the read-only build, disable it in the [ClaimTxManager] section")
+	errReadOnlyCanary         = errors.New("the canary deposits are not available in the read-only build, disable them in the [Canary] section")
+	errReadOnlyReserve        = errors.New("the reserve attestations are not available in the read-only build, disable them in the [Reserve] section")
+	errReadOnlySigning        = errors.New("the signed responses are not available in the read-only build, disable them in the [ResponseSigning] section")
+)
+
+func validateClaimTxManager(cfg claimtxman.Config) error {
+	return nil
+}
+
+func newClaimTxManagers(c *config.Config, networkIDs []uint, exitRootEvents *queue.Queue[*etherman.GlobalExitRoot], chSynced chan uint, bridgeService claimProver, storage db.Storage) ([]claimTxManager, error) {
+	if c.ClaimTxManager.Enabled {
+		return nil, errReadOnlyClaimTxManager
+	}
+	return nil, nil
+}
+
+func newCanary(ctx context.Context, c *config.Config, networkIDs []uint, bridgeService claimProver, storage db.Storage) (canaryDeposits, error) {
+	return nil, errReadOnlyCanary
+}
+
+func newReserveAttester(cfg reserve.Config, storage db.Storage) (server.ReserveAttester, error) {
+	return nil, errReadOnlyReserve
+}
+
+func newResponseSigner(cfg signing.Config, storage db.Storage) (server.ResponseSigner, error) {
+	return nil, errReadOnlySigning
+}
@@ -0,0 +1,32 @@
+//go:build readonly
+// +build readonly
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/reserve"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/signing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlySenders(t *testing.T) {
+	var c config.Config
+	claimTxManagers, err := newClaimTxManagers(&c, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, claimTxManagers)
+
+	// The modules sending txs or loading keys can't be enabled
+	c.ClaimTxManager.Enabled = true
+	_, err = newClaimTxManagers(&c, nil, nil, nil, nil, nil)
+	require.ErrorIs(t, err, errReadOnlyClaimTxManager)
+	_, err = newCanary(context.Background(), &c, nil, nil, nil)
+	require.ErrorIs(t, err, errReadOnlyCanary)
+	_, err = newReserveAttester(reserve.Config{Enabled: true}, nil)
+	require.ErrorIs(t, err, errReadOnlyReserve)
+	_, err = newResponseSigner(signing.Config{Enabled: true}, nil)
+	require.ErrorIs(t, err, errReadOnlySigning)
+}
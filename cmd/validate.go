@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/statusrules"
+	"github.com/urfave/cli/v2"
+)
+
+// validateResult is the result of the validate-config command.
+type validateResult struct {
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors"`
+}
+
+// validateConfig returns the errors of the config found without connecting to the providers or the database.
+func validateConfig(c *config.Config) []error {
+	var errs []error
+	check := func(section string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", section, err))
+		}
+	}
+	if len(c.L2PolygonBridgeAddresses) != len(c.Etherman.L2URLs) {
+		errs = append(errs, fmt.Errorf("NetworkConfig: %d L2 bridge addresses for %d L2 urls", len(c.L2PolygonBridgeAddresses), len(c.Etherman.L2URLs)))
+	}
+	if len(c.Synchronizer.L2Confirmations) > len(c.Etherman.L2URLs) {
+		errs = append(errs, fmt.Errorf("Synchronizer: %d L2 confirmations for %d L2 urls", len(c.Synchronizer.L2Confirmations), len(c.Etherman.L2URLs)))
+	}
+	check("Etherman.L1Quorum", c.Etherman.L1Quorum.Validate())
+	check("Etherman.L2Quorum", c.Etherman.L2Quorum.Validate())
+	check("Etherman.Archive", c.Etherman.Archive.Validate(c.Etherman.L2URLs))
+	check("Etherman.Capture", c.Etherman.Capture.Validate())
+	check("Etherman.Retry", c.Etherman.Retry.Validate())
+	check("Synchronizer.AdaptiveInterval", c.Synchronizer.AdaptiveInterval.Validate())
+	check("Synchronizer.ExitRootQueue", c.Synchronizer.ExitRootQueue.Validate())
+	check("Synchronizer.Retry", c.Synchronizer.Retry.Validate())
+	if c.ClaimTxManager.Enabled {
+		check("ClaimTxManager.UpdateQueue", c.ClaimTxManager.UpdateQueue.Validate())
+		check("ClaimTxManager", validateClaimTxManager(c.ClaimTxManager))
+	}
+	if c.Webhook.Enabled {
+		check("Webhook", c.Webhook.QueueConfig().Validate())
+	}
+	if c.Push.Enabled {
+		check("Push", c.Push.QueueConfig().Validate())
+	}
+	if c.Watchdog.Enabled {
+		check("Watchdog", c.Watchdog.Validate())
+	}
+	if c.Reconciliation.Enabled {
+		check("Reconciliation", c.Reconciliation.Validate())
+	}
+	if c.Replication.Enabled {
+		check("Replication", c.Replication.Validate())
+	}
+	if c.ResponseSigning.Enabled {
+		check("ResponseSigning", c.ResponseSigning.Validate())
+	}
+	if c.EventBus.Enabled {
+		check("EventBus", c.EventBus.Validate())
+	}
+	if c.BridgeServer.ConfigApprovals.Enabled {
+		check("BridgeServer.ConfigApprovals", c.BridgeServer.ConfigApprovals.Validate())
+	}
+	check("Maintenance", c.Maintenance.Validate())
+	if c.Backup.Enabled {
+		check("Backup", c.Backup.Validate())
+	}
+	if c.Rosetta.Enabled {
+		check("Rosetta", c.Rosetta.Validate())
+	}
+	if c.Activity.Enabled {
+		check("Activity", c.Activity.Validate())
+	}
+	if c.IndexAdvisor.Enabled {
+		check("IndexAdvisor", c.IndexAdvisor.Validate())
+	}
+	check("BridgeServer.GRPC", c.BridgeServer.GRPC.Validate())
+	check("BridgeServer.Gateway", c.BridgeServer.Gateway.Validate())
+	check("BridgeServer.Watch", c.BridgeServer.Watch.Validate())
+	_, err := statusrules.Compile(c.BridgeServer.StatusRules)
+	check("BridgeServer.StatusRules", err)
+	if !etherman.IsClaimVersion(c.BridgeServer.ClaimVersion) {
+		errs = append(errs, fmt.Errorf("BridgeServer: unknown claim version %s", c.BridgeServer.ClaimVersion))
+	}
+	if c.BridgeServer.MaxEventSubscribers < 0 {
+		errs = append(errs, fmt.Errorf("BridgeServer: the max number of event subscribers %d is negative", c.BridgeServer.MaxEventSubscribers))
+	}
+	if c.Tuning.MemoryLimitRatio < 0 || c.Tuning.MemoryLimitRatio > 1 {
+		errs = append(errs, fmt.Errorf("Tuning: the memory limit ratio %v is not between 0 and 1", c.Tuning.MemoryLimitRatio))
+	}
+	return errs
+}
+
+// validateConfigCmd loads the config and checks it, failing if it's not valid, so it can be checked before a deploy.
+func validateConfigCmd(ctx *cli.Context) error {
+	c, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	errs := validateConfig(c)
+	result := validateResult{Valid: len(errs) == 0, Errors: make([]string, 0, len(errs))}
+	for _, err := range errs {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	err = printResult(ctx, result, func(w io.Writer) error {
+		if result.Valid {
+			_, err := fmt.Fprintln(w, "the config is valid")
+			return err
+		}
+		for _, e := range result.Errors {
+			if _, err := fmt.Fprintln(w, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !result.Valid {
+		// The errors are in the result already
+		return cli.Exit(fmt.Sprintf("the config has %d errors", len(errs)), 1)
+	}
+	return nil
+}
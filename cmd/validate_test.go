@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	c, err := config.Load("../config/config.local.toml", "")
+	require.NoError(t, err)
+	require.Empty(t, validateConfig(c))
+
+	c.Etherman.L2URLs = append(c.Etherman.L2URLs, "http://localhost:8124")
+	c.BridgeServer.ClaimVersion = "v3"
+	c.Tuning.MemoryLimitRatio = 1.5
+	c.IndexAdvisor.Enabled = true
+	c.IndexAdvisor.Interval.Duration = 0
+	errs := validateConfig(c)
+	require.Equal(t, 4, len(errs))
+	require.Contains(t, errs[0].Error(), "NetworkConfig")
+	require.Contains(t, errs[1].Error(), "IndexAdvisor")
+	require.Contains(t, errs[2].Error(), "claim version v3")
+
+	c.L2PolygonBridgeAddresses = append(c.L2PolygonBridgeAddresses, common.HexToAddress("0x1"))
+	c.BridgeServer.ClaimVersion = "v2"
+	c.Tuning.MemoryLimitRatio = 0.9
+	c.IndexAdvisor.Interval.Duration = time.Hour
+	require.Empty(t, validateConfig(c))
+}
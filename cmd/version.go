@@ -1,13 +1,33 @@
 package main
 
 import (
-	"os"
+	"io"
+	"runtime"
 
 	zkevmbridgeservice "github.com/0xPolygonHermez/zkevm-bridge-service"
 	"github.com/urfave/cli/v2"
 )
 
-func versionCmd(*cli.Context) error {
-	zkevmbridgeservice.PrintVersion(os.Stdout)
-	return nil
+func versionCmd(ctx *cli.Context) error {
+	result := struct {
+		Version   string `json:"version"`
+		GitRev    string `json:"git_rev"`
+		GitBranch string `json:"git_branch"`
+		GoVersion string `json:"go_version"`
+		BuildDate string `json:"build_date"`
+		OSArch    string `json:"os_arch"`
+		ReadOnly  bool   `json:"read_only"`
+	}{
+		Version:   zkevmbridgeservice.Version,
+		GitRev:    zkevmbridgeservice.GitRev,
+		GitBranch: zkevmbridgeservice.GitBranch,
+		GoVersion: runtime.Version(),
+		BuildDate: zkevmbridgeservice.BuildDate,
+		OSArch:    runtime.GOOS + "/" + runtime.GOARCH,
+		ReadOnly:  zkevmbridgeservice.ReadOnly,
+	}
+	return printResult(ctx, result, func(w io.Writer) error {
+		zkevmbridgeservice.PrintVersion(w)
+		return nil
+	})
 }
@@ -6,12 +6,30 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/0xPolygonHermez/zkevm-bridge-service/activity"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/backup"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/cache"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/canary"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/changefeed"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/eventbus"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/indexadvisor"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/maintenance"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/push"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/reconciliation"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/replication"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/reserve"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/rosetta"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/server"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/signing"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/synchronizer"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/tuning"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/statefile"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/watchdog"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/webhook"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
@@ -26,6 +44,24 @@ type Config struct {
 	Synchronizer     synchronizer.Config
 	BridgeController bridgectrl.Config
 	BridgeServer     server.Config
+	StateFile        statefile.Config
+	IndexAdvisor     indexadvisor.Config
+	Watchdog         watchdog.Config
+	Reconciliation   reconciliation.Config
+	Webhook          webhook.Config
+	Canary           canary.Config
+	Reserve          reserve.Config
+	ResponseSigning  signing.Config
+	Cache            cache.Config
+	ChangeFeed       changefeed.Config
+	EventBus         eventbus.Config
+	Replication      replication.Config
+	Push             push.Config
+	Tuning           tuning.Config
+	Maintenance      maintenance.Config
+	Backup           backup.Config
+	Rosetta          rosetta.Config
+	Activity         activity.Config
 	NetworkConfig
 }
 
@@ -13,7 +13,9 @@ Password = "test_password"
 Name = "test_db"
 Host = "zkevm-bridge-db"
 Port = "5432"
-MaxConns = 20
+MaxConns = 0
+ChangeOutbox = false
+ChangeNotify = false
 
 [ClaimTxManager]
 Enabled = false
@@ -22,14 +24,94 @@ PrivateKey = {Path = "./test/test.keystore", Password = "testonly"}
 RetryInterval = "1s"
 RetryNumber = 10
 AuthorizedClaimMessageAddresses = []
+AuthorizedClaimMessageDestinations = []
+ApprovalThreshold = "0"
+AllowedTokensOnly = false
+PrivateRelayURL = ""
+PrivateRelayTimeout = "2m"
+OfflineSigningDir = ""
+OfflineSignerAddress = "0x0000000000000000000000000000000000000000"
+BreakerThreshold = 5
+BreakerCooldown = "5m"
+UpdateWorkers = 4
+    [ClaimTxManager.AdaptiveMonitorInterval]
+    Enabled = false
+    MinInterval = "1s"
+    MaxInterval = "15s"
+    [ClaimTxManager.MinClaimValue]
+    Enabled = false
+    MinValueRatio = 1.0
+    PriceFeedURL = ""
+    PriceFeedInterval = "5m"
+    PriceFeedTimeout = "10s"
+    [ClaimTxManager.UpdateQueue]
+    Size = 100
+    Policy = "drop_oldest"
+    [ClaimTxManager.DynamicFee]
+    Enabled = false
+    TipStrategy = "suggested"
+    Tip = "0"
+    BaseFeeMultiplier = 2
+    MaxFeeCap = "0"
 
 [Etherman]
 L1URL = "http://localhost:8545"
 L2URLs = [""]
+    [Etherman.Cache]
+    Size = 1000
+    FinalizedBlockDepth = 64
+    [Etherman.L1Throttle]
+    RequestsPerSecond = 0
+    ComputeUnitsPerSecond = 0
+    BurstSeconds = 1
+    DefaultComputeUnits = 20
+    [Etherman.L2Throttle]
+    RequestsPerSecond = 0
+    ComputeUnitsPerSecond = 0
+    BurstSeconds = 1
+    DefaultComputeUnits = 20
+    [Etherman.L1Quorum]
+    URLs = []
+    Threshold = 0
+    [Etherman.L2Quorum]
+    URLs = []
+    Threshold = 0
+    [Etherman.Capture]
+    SampleRate = 0
+    Dir = "/tmp/rpc-capture"
+    MaxFiles = 10000
+    MaxBodySize = 1048576
+    [Etherman.Retry]
+    Profile = "mainnet-conservative"
+    Attempts = 0
+    InitialBackoff = "0s"
+    MaxBackoff = "0s"
+    [Etherman.Archive]
+    L1URL = ""
+    L2URLs = []
+    Depth = 128
 
 [Synchronizer]
 SyncInterval = "2s"
 SyncChunkSize = 100
+L1Confirmations = 0
+L2Confirmations = []
+PersistRawLogs = false
+TraceDeposits = false
+AttributeDeposits = false
+RecordDepositSenders = false
+TraceMessageClaims = false
+RepairDepositGaps = true
+Integrations = []
+    [Synchronizer.AdaptiveInterval]
+    Enabled = false
+    MinInterval = "1s"
+    MaxInterval = "15s"
+    [Synchronizer.ExitRootQueue]
+    Size = 100
+    Policy = "block"
+    [Synchronizer.Retry]
+    Profile = ""
 
 [BridgeController]
 Store = "postgres"
@@ -40,8 +122,66 @@ GRPCPort = "9090"
 HTTPPort = "8080"
 DefaultPageLimit = 25
 CacheSize = 100000
+TopLevels = 20
+TopLevelsSize = 50000
 MaxPageLimit = 100
+MaxPageResults = 10000
+AdminMaxPageLimit = 1000
+MaxSimulatedClaims = 20
 BridgeVersion = "v1"
+MaxSyncLag = 100
+AdminToken = ""
+InProcessGateway = false
+ClaimVersion = "v1"
+IdempotencyKeyTTL = "24h"
+MaxEventSubscribers = 1000
+BatchPollInterval = "10s"
+    [BridgeServer.GRPC]
+    MaxRecvMsgSize = 0
+    MaxSendMsgSize = 0
+    MaxConcurrentStreams = 0
+    MaxConnectionIdle = "0s"
+    MaxConnectionAge = "0s"
+    MaxConnectionAgeGrace = "0s"
+    KeepaliveTime = "2h"
+    KeepaliveTimeout = "20s"
+    KeepaliveMinTime = "5m"
+    KeepalivePermitWithoutStream = false
+    XDS = false
+    [BridgeServer.Gateway]
+    Target = ""
+    Addresses = []
+    ServiceConfig = ""
+    Authority = ""
+    [BridgeServer.Session]
+    Secret = ""
+    ChainID = 1
+    ChallengeTTL = "5m"
+    SessionTTL = "24h"
+    MaxWebhooks = 5
+    MaxDevices = 5
+    [BridgeServer.Watch]
+    MaxAddresses = 10
+    WarmInterval = "30s"
+    BatchPollInterval = "2s"
+    BackfillInterval = "1m"
+    BackfillBatch = 100
+    [BridgeServer.Jobs]
+    MaxConcurrent = 2
+    MaxRows = 1000000
+    Timeout = "10m"
+    TTL = "24h"
+    [BridgeServer.ProofAccess]
+    Restricted = false
+    RefreshInterval = "10s"
+    [BridgeServer.TokenList]
+    RefreshInterval = "10s"
+    [BridgeServer.ConfigApprovals]
+    Enabled = false
+    TTL = "24h"
+    [BridgeServer.Contracts]
+    CheckInterval = "10m"
+    CheckTimeout = "30s"
     [BridgeServer.DB]
     Database = "postgres"
     User = "test_user"
@@ -49,5 +189,158 @@ BridgeVersion = "v1"
     Name = "test_db"
     Host = "zkevm-bridge-db"
     Port = "5432"
-    MaxConns = 20
+    MaxConns = 0
+
+[StateFile]
+Path = ""
+MaxAge = "1h"
+
+[IndexAdvisor]
+Enabled = false
+Interval = "1h"
+MinCalls = 100
+CreateIndexes = false
+
+[Watchdog]
+Enabled = false
+Interval = "5m"
+L1IndexedSLA = "1h"
+L2IndexedSLA = "2h"
+ReadyForClaimSLA = "30m"
+MaxDeposits = 50
+AlertURL = ""
+Timeout = "10s"
+    [Watchdog.Stages]
+    Indexed = "5m"
+    Ready = "2h"
+    Claimed = "1h"
+
+[Reconciliation]
+Enabled = false
+Interval = "10m"
+AlertURL = ""
+Timeout = "10s"
+
+[Webhook]
+Enabled = false
+Workers = 0
+MaxWorkers = 32
+ScaleInterval = "1s"
+MaxEndpointConcurrency = 4
+EndpointBacklog = 100
+SlowThreshold = "5s"
+QuarantineDuration = "5m"
+QueueSize = 1000
+QueuePolicy = "drop_newest"
+RefillInterval = "1s"
+Timeout = "10s"
+RetryInterval = "5s"
+RetryNumber = 3
+Subscriptions = []
+WalletRefreshInterval = "1m"
+
+[Canary]
+Enabled = false
+Interval = "10m"
+PrivateKey = {Path = "./test/test.keystore", Password = "testonly"}
+Amount = "1000000000"
+PollInterval = "10s"
+L1ToL2Timeout = "30m"
+L2ToL1Timeout = "3h"
+
+[Reserve]
+Enabled = false
+PrivateKey = {Path = "./test/test.keystore", Password = "testonly"}
+
+[ResponseSigning]
+Enabled = false
+Keys = [{Path = "./test/test.keystore", Password = "testonly"}]
+RefreshInterval = "10s"
+
+[Cache]
+Enabled = false
+RedisURL = ""
+Prefix = "bridge:"
+TTL = "10m"
+LocalSize = 10000
+Timeout = "100ms"
+
+[ChangeFeed]
+Enabled = false
+ReplicaWait = "5s"
+ReconnectInterval = "1s"
+SubscriberBuffer = 100
+
+[EventBus]
+Enabled = false
+PollInterval = "5s"
+RetryInterval = "5s"
+BatchSize = 100
+Retention = "168h"
+
+[Replication]
+Enabled = false
+Interval = "10s"
+MaxLagBytes = 16777216
+MaxReplayDelay = "30s"
+
+[Push]
+Enabled = false
+Workers = 0
+MaxWorkers = 32
+ScaleInterval = "1s"
+QueueSize = 1000
+QueuePolicy = "drop_newest"
+Timeout = "10s"
+    [Push.FCM]
+    CredentialsFile = ""
+    [Push.APNs]
+    KeyFile = ""
+    KeyID = ""
+    TeamID = ""
+    Topic = ""
+    Sandbox = false
+
+[Tuning]
+GOMAXPROCS = 0
+MemoryLimitRatio = 0.9
+DBConnsPerCPU = 4
+DBMinConns = 4
+DBMaxConns = 64
+WorkersPerCPU = 2
+
+[Maintenance]
+RefreshInterval = "10s"
+MaxDuration = "24h"
+
+[Backup]
+Enabled = false
+Interval = "24h"
+Dir = "/backups"
+Keep = 7
+PgDump = "pg_dump"
+PgRestore = "pg_restore"
+Verify = false
+    [Backup.VerifyDB]
+    Database = "postgres"
+    User = "test_user"
+    Password = "test_password"
+    Name = ""
+    Host = "zkevm-bridge-db"
+    Port = "5432"
+    MaxConns = 0
+
+[Rosetta]
+Enabled = false
+Port = "8090"
+Blockchain = "zkevm-bridge"
+Network = "mainnet"
+
+[Activity]
+Enabled = false
+Level = "info"
+QueueSize = 1000
+FlushInterval = "1s"
+BatchSize = 100
+Retention = "720h"
 `
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/server"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -10,7 +13,9 @@ type NetworkConfig struct {
 	GenBlockNumber                    uint64
 	PolygonBridgeAddress              common.Address
 	PolygonZkEVMGlobalExitRootAddress common.Address
-	L2PolygonBridgeAddresses          []common.Address
+	// PolygonRollupManagerAddress is the rollup manager of the multi rollup contracts, zero for a single rollup
+	PolygonRollupManagerAddress common.Address
+	L2PolygonBridgeAddresses    []common.Address
 }
 
 const (
@@ -58,3 +63,33 @@ func (cfg *Config) loadNetworkConfig(network string) {
 		cfg.NetworkConfig = networkConfigs[defaultNetwork]
 	}
 }
+
+// OfficialDeployments returns the contracts of the known public networks, sorted by network name. The L2 bridges
+// deployed at another address than the L1 one are named after the network with an L2 suffix.
+func OfficialDeployments() []server.Deployment {
+	names := make([]string, 0, len(networkConfigs))
+	for name := range networkConfigs {
+		if name != "local" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var deployments []server.Deployment
+	add := func(name, contract string, addr common.Address) {
+		if addr != (common.Address{}) {
+			deployments = append(deployments, server.Deployment{Name: name, Contract: contract, Address: addr})
+		}
+	}
+	for _, name := range names {
+		network := networkConfigs[name]
+		add(name, server.ContractBridge, network.PolygonBridgeAddress)
+		add(name, server.ContractGlobalExitRootManager, network.PolygonZkEVMGlobalExitRootAddress)
+		add(name, server.ContractRollupManager, network.PolygonRollupManagerAddress)
+		for _, addr := range network.L2PolygonBridgeAddresses {
+			if addr != network.PolygonBridgeAddress {
+				add(name+" L2", server.ContractBridge, addr)
+			}
+		}
+	}
+	return deployments
+}
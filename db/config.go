@@ -20,6 +20,15 @@ type Config struct {
 	// Port Number
 	Port string `mapstructure:"Port"`
 
-	// MaxConns is the maximum number of connections in the pool.
+	// MaxConns is the maximum number of connections in the pool, 0 to size it for the CPUs of the container
+	// with the Tuning config.
 	MaxConns int `mapstructure:"MaxConns"`
+
+	// ChangeOutbox writes the inserts, updates and deletes of the deposits and claims in the
+	// sync.outbox table for the CDC consumers. Only used by the synchronizer database
+	ChangeOutbox bool `mapstructure:"ChangeOutbox"`
+
+	// ChangeNotify notifies the changes of the deposits, claims and wrapped tokens on the bridge_changes
+	// channel for the change feed of the API replicas. Only used by the synchronizer database
+	ChangeNotify bool `mapstructure:"ChangeNotify"`
 }
@@ -0,0 +1,70 @@
+package pgstorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/jackc/pgx/v4"
+)
+
+// ChangesChannel is the channel the triggers notify the changes of the bridge tables on.
+const ChangesChannel = "bridge_changes"
+
+// ListenChanges calls handle with the payload of each change notified, until the context is done or the connection
+// is lost. It holds a connection of the pool, closed when it returns.
+func (p *PostgresStorage) ListenChanges(ctx context.Context, handle func(payload string)) error {
+	pooled, err := p.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The listening connection is not returned to the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background()) //nolint:errcheck
+	if _, err := conn.Exec(ctx, "LISTEN "+ChangesChannel); err != nil {
+		return err
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handle(notification.Payload)
+	}
+}
+
+// GetCurrentLSN gets the current WAL position of the database.
+func (p *PostgresStorage) GetCurrentLSN(ctx context.Context, dbTx pgx.Tx) (string, error) {
+	var lsn string
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, "SELECT pg_current_wal_lsn()::TEXT").Scan(&lsn)
+	return lsn, err
+}
+
+// IsLSNReplayed returns if the database has replayed the WAL until the position, always true for a primary.
+func (p *PostgresStorage) IsLSNReplayed(ctx context.Context, lsn string, dbTx pgx.Tx) (bool, error) {
+	const isLSNReplayedSQL = "SELECT NOT pg_is_in_recovery() OR COALESCE(pg_last_wal_replay_lsn() >= $1::pg_lsn, false)"
+	var replayed bool
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, isLSNReplayedSQL, lsn).Scan(&replayed)
+	return replayed, err
+}
+
+// GetReplayStatus gets the replay of the WAL until the position of the primary by the database, a read replica.
+func (p *PostgresStorage) GetReplayStatus(ctx context.Context, lsn string, dbTx pgx.Tx) (*etherman.ReplayStatus, error) {
+	const getReplayStatusSQL = `SELECT pg_is_in_recovery(),
+		COALESCE(GREATEST(pg_wal_lsn_diff($1::pg_lsn, pg_last_wal_replay_lsn()), 0), 0)::BIGINT, pg_last_xact_replay_timestamp()`
+	var (
+		status   etherman.ReplayStatus
+		replayAt *time.Time
+	)
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getReplayStatusSQL, lsn).Scan(&status.InRecovery, &status.LagBytes, &replayAt)
+	if err != nil {
+		return nil, err
+	}
+	if !status.InRecovery {
+		// A primary has no lag, its position is not the one of the primary it was compared with
+		status.LagBytes = 0
+	} else if replayAt != nil {
+		status.LastReplayAt = *replayAt
+	}
+	return &status, nil
+}
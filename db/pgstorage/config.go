@@ -17,6 +17,6 @@ type Config struct {
 	// Port Number
 	Port string `mapstructure:"Port"`
 
-	// MaxConns is the maximum number of connections in the pool.
+	// MaxConns is the maximum number of connections in the pool, 0 for the default of pgx.
 	MaxConns int `mapstructure:"MaxConns"`
 }
@@ -0,0 +1,102 @@
+package pgstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/indexadvisor"
+	"github.com/jackc/pgx/v4"
+)
+
+// maxQueryStats is the max number of statements analyzed by the index advisor
+const maxQueryStats = 200
+
+// GetQueryStats returns the profile of the bridge statements with at least minCalls calls, recorded by
+// the pg_stat_statements extension, the most expensive first.
+func (p *PostgresStorage) GetQueryStats(ctx context.Context, minCalls uint64) ([]indexadvisor.QueryStat, error) {
+	const getQueryStatsSQL = `
+		SELECT query, calls, total_exec_time FROM pg_stat_statements
+		WHERE calls >= $1 AND (query ILIKE '%sync.%' OR query ILIKE '%mt.%')
+		ORDER BY total_exec_time DESC LIMIT $2`
+	rows, err := p.Query(ctx, getQueryStatsSQL, minCalls, maxQueryStats)
+	if err != nil {
+		return nil, fmt.Errorf("error reading pg_stat_statements, the extension must be installed: %w", err)
+	}
+	defer rows.Close()
+	var stats []indexadvisor.QueryStat
+	for rows.Next() {
+		var (
+			stat      indexadvisor.QueryStat
+			totalTime float64
+		)
+		if err := rows.Scan(&stat.Query, &stat.Calls, &totalTime); err != nil {
+			return nil, err
+		}
+		// total_exec_time is in milliseconds
+		stat.TotalTime = time.Duration(totalTime * float64(time.Millisecond))
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+// GetIndexes returns the indexes of the bridge tables with their columns in order.
+func (p *PostgresStorage) GetIndexes(ctx context.Context) ([]indexadvisor.Index, error) {
+	const getIndexesSQL = `
+		SELECT n.nspname || '.' || t.relname, i.relname, array_agg(a.attname ORDER BY k.ord), x.indisvalid
+		FROM pg_index x
+		INNER JOIN pg_class t ON t.oid = x.indrelid
+		INNER JOIN pg_class i ON i.oid = x.indexrelid
+		INNER JOIN pg_namespace n ON n.oid = t.relnamespace
+		INNER JOIN unnest(x.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+		INNER JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE n.nspname IN ('sync', 'mt')
+		GROUP BY n.nspname, t.relname, i.relname, x.indisvalid`
+	rows, err := p.Query(ctx, getIndexesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var indexes []indexadvisor.Index
+	for rows.Next() {
+		var index indexadvisor.Index
+		if err := rows.Scan(&index.Table, &index.Name, &index.Columns, &index.Valid); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
+
+// CreateIndex creates an index concurrently, so the table is not locked. It can't run inside a db transaction. A
+// failed concurrent creation leaves an invalid index, which IF NOT EXISTS would keep, so it's dropped before
+// creating the index again.
+func (p *PostgresStorage) CreateIndex(ctx context.Context, name, table string, columns []string) error {
+	const getIndexValidSQL = `SELECT x.indisvalid FROM pg_index x
+		INNER JOIN pg_class i ON i.oid = x.indexrelid
+		INNER JOIN pg_namespace n ON n.oid = i.relnamespace
+		WHERE n.nspname = $1 AND i.relname = $2`
+	schema := strings.Split(table, ".")[0]
+	index := pgx.Identifier{schema, name}.Sanitize()
+	var valid bool
+	err := p.QueryRow(ctx, getIndexValidSQL, schema, name).Scan(&valid)
+	if err == nil && valid {
+		return nil
+	} else if err == nil {
+		if _, err := p.Exec(ctx, "DROP INDEX CONCURRENTLY IF EXISTS "+index); err != nil {
+			return fmt.Errorf("error dropping the invalid index %s: %w", name, err)
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pgx.Identifier{column}.Sanitize()
+	}
+	createIndexSQL := fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s (%s)",
+		pgx.Identifier{name}.Sanitize(), pgx.Identifier(strings.Split(table, ".")).Sanitize(), strings.Join(quoted, ", "))
+	_, err = p.Exec(ctx, createIndexSQL)
+	return err
+}
@@ -0,0 +1,19 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.raw_log;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sync.raw_log
+(
+    id          SERIAL PRIMARY KEY,
+    block_id    BIGINT NOT NULL REFERENCES sync.block (id) ON DELETE CASCADE,
+    network_id  INTEGER NOT NULL,
+    tx_hash     BYTEA NOT NULL,
+    tx_index    INTEGER NOT NULL,
+    log_index   INTEGER NOT NULL,
+    address     BYTEA NOT NULL,
+    topics      BYTEA[] NOT NULL,
+    data        BYTEA NOT NULL,
+    CONSTRAINT raw_log_uc UNIQUE (block_id, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS raw_log_network_id_idx ON sync.raw_log(network_id);
@@ -0,0 +1,47 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the table to store the raw logs of the bridge events.
+
+type migrationTest0008 struct{}
+
+func (m migrationTest0008) InsertData(db *sql.DB) error {
+	block := "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(2, 2803824, decode('27474F16174BBE50C294FE13C190B92E42B2368A6D4AEB8A4A015F52816296C3','hex'), decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), 0, '0001-01-01 01:00:00.000');"
+	if _, err := db.Exec(block); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m migrationTest0008) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	insertRawLog := "INSERT INTO sync.raw_log (block_id, network_id, tx_hash, tx_index, log_index, address, topics, data) VALUES(2, 0, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B3143DB50E4995CB2422E','hex'), 0, 3, decode('F6BEEEBB578E214CA9E23B0E9683454FF88ED2A7','hex'), '{}', decode('','hex'));"
+	_, err := db.Exec(insertRawLog)
+	assert.NoError(t, err)
+	_, err = db.Exec(insertRawLog)
+	assert.Error(t, err)
+
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.raw_log;").Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// Check the raw logs are removed with the block
+	_, err = db.Exec("DELETE FROM sync.block WHERE id = 2;")
+	assert.NoError(t, err)
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.raw_log;").Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func (m migrationTest0008) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.raw_log;").Scan(&count))
+}
+
+func TestMigration0008(t *testing.T) {
+	runMigrationTest(t, 8, migrationTest0008{})
+}
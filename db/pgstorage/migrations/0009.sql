@@ -0,0 +1,21 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.token_wrapped_history;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sync.token_wrapped_history
+(
+    id                 SERIAL PRIMARY KEY,
+    network_id         INTEGER NOT NULL,
+    orig_net           INTEGER NOT NULL,
+    orig_token_addr    BYTEA NOT NULL,
+    wrapped_token_addr BYTEA NOT NULL,
+    block_id           BIGINT NOT NULL REFERENCES sync.block (id) ON DELETE CASCADE,
+    block_num          BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS token_wrapped_history_orig_token_idx ON sync.token_wrapped_history(orig_net, orig_token_addr);
+
+-- The current mappings are the first entries of the history
+INSERT INTO sync.token_wrapped_history (network_id, orig_net, orig_token_addr, wrapped_token_addr, block_id, block_num)
+SELECT t.network_id, t.orig_net, t.orig_token_addr, t.wrapped_token_addr, t.block_id, b.block_num
+FROM sync.token_wrapped AS t INNER JOIN sync.block AS b ON t.block_id = b.id;
@@ -0,0 +1,52 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the table to keep the history of the wrapped tokens mappings.
+
+type migrationTest0009 struct{}
+
+func (m migrationTest0009) InsertData(db *sql.DB) error {
+	block := "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(2, 2803824, decode('27474F16174BBE50C294FE13C190B92E42B2368A6D4AEB8A4A015F52816296C3','hex'), decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), 1, '0001-01-01 01:00:00.000');"
+	if _, err := db.Exec(block); err != nil {
+		return err
+	}
+	tokenWrapped := "INSERT INTO sync.token_wrapped (network_id, orig_net, orig_token_addr, wrapped_token_addr, block_id, name, symbol, decimals) VALUES(1, 0, decode('6B175474E89094C44DA98B954EEDEAC495271D0F','hex'), decode('187BD40226A7073B49163B1F6C2B73D8F2AA8478','hex'), 2, 'CoinA', 'COA', 18);"
+	if _, err := db.Exec(tokenWrapped); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m migrationTest0009) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	// Check the current mapping is moved to the history
+	var (
+		blockNum    uint64
+		wrappedAddr []byte
+	)
+	row := db.QueryRow("SELECT block_num, wrapped_token_addr FROM sync.token_wrapped_history WHERE network_id = 1 AND orig_net = 0;")
+	assert.NoError(t, row.Scan(&blockNum, &wrappedAddr))
+	assert.Equal(t, uint64(2803824), blockNum)
+	assert.Equal(t, 20, len(wrappedAddr))
+
+	// Check the history is removed with the block
+	_, err := db.Exec("DELETE FROM sync.block WHERE id = 2;")
+	assert.NoError(t, err)
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.token_wrapped_history;").Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func (m migrationTest0009) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.token_wrapped_history;").Scan(&count))
+}
+
+func TestMigration0009(t *testing.T) {
+	runMigrationTest(t, 9, migrationTest0009{})
+}
@@ -0,0 +1,28 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.activity_bucket;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sync.activity_bucket
+(
+    network_id INTEGER NOT NULL,
+    kind       VARCHAR NOT NULL,
+    orig_net   INTEGER NOT NULL,
+    orig_addr  BYTEA NOT NULL,
+    bucket     TIMESTAMP NOT NULL, -- UTC hour
+    count      BIGINT NOT NULL,
+    volume     NUMERIC(78, 0) NOT NULL,
+    PRIMARY KEY (network_id, kind, orig_net, orig_addr, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS activity_bucket_network_bucket_idx ON sync.activity_bucket(network_id, bucket);
+
+-- Aggregate the deposits and claims already synced
+INSERT INTO sync.activity_bucket (network_id, kind, orig_net, orig_addr, bucket, count, volume)
+SELECT b.network_id, 'deposit', d.orig_net, d.orig_addr, date_trunc('hour', b.received_at AT TIME ZONE 'UTC'), count(*), sum(d.amount::NUMERIC)
+FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.block_id = b.id
+GROUP BY b.network_id, d.orig_net, d.orig_addr, date_trunc('hour', b.received_at AT TIME ZONE 'UTC');
+
+INSERT INTO sync.activity_bucket (network_id, kind, orig_net, orig_addr, bucket, count, volume)
+SELECT b.network_id, 'claim', c.orig_net, c.orig_addr, date_trunc('hour', b.received_at AT TIME ZONE 'UTC'), count(*), sum(c.amount::NUMERIC)
+FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id
+GROUP BY b.network_id, c.orig_net, c.orig_addr, date_trunc('hour', b.received_at AT TIME ZONE 'UTC');
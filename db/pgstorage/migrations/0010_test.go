@@ -0,0 +1,52 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the table with the deposits and claims aggregated by hour.
+
+type migrationTest0010 struct{}
+
+func (m migrationTest0010) InsertData(db *sql.DB) error {
+	block := "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(2, 2803824, decode('27474F16174BBE50C294FE13C190B92E42B2368A6D4AEB8A4A015F52816296C3','hex'), decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), 0, '2023-01-01 10:30:00.000+00');"
+	if _, err := db.Exec(block); err != nil {
+		return err
+	}
+	const addDepositSQL = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata) VALUES (0, 0, 0, decode('0000000000000000000000000000000000000000','hex'), $1, 1, decode('F39FD6E51AAD88F6F4CE6AB8827279CFFFB92266','hex'), 2, $2, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B9C5A1E2FE9E3A428A7DC','hex'), decode('','hex'))"
+	if _, err := db.Exec(addDepositSQL, "1000000000000000000", 1); err != nil {
+		return err
+	}
+	if _, err := db.Exec(addDepositSQL, "2000000000000000000", 2); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m migrationTest0010) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	// Check the synced deposits are aggregated
+	var (
+		count  uint64
+		volume string
+		bucket string
+	)
+	row := db.QueryRow("SELECT count, volume::VARCHAR, to_char(bucket, 'YYYY-MM-DD HH24:MI') FROM sync.activity_bucket WHERE network_id = 0 AND kind = 'deposit';")
+	assert.NoError(t, row.Scan(&count, &volume, &bucket))
+	assert.Equal(t, uint64(2), count)
+	assert.Equal(t, "3000000000000000000", volume)
+	assert.Equal(t, "2023-01-01 10:00", bucket)
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.activity_bucket WHERE kind = 'claim';").Scan(&count))
+	assert.Equal(t, uint64(0), count)
+}
+
+func (m migrationTest0010) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.activity_bucket;").Scan(&count))
+}
+
+func TestMigration0010(t *testing.T) {
+	runMigrationTest(t, 10, migrationTest0010{})
+}
@@ -0,0 +1,37 @@
+-- +migrate Down
+DROP TRIGGER IF EXISTS deposit_outbox ON sync.deposit;
+DROP TRIGGER IF EXISTS claim_outbox ON sync.claim;
+DROP FUNCTION IF EXISTS sync.capture_row_change();
+DROP TABLE IF EXISTS sync.outbox;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sync.outbox
+(
+    id         BIGSERIAL PRIMARY KEY,
+    table_name VARCHAR NOT NULL,
+    operation  VARCHAR NOT NULL, -- INSERT, UPDATE or DELETE
+    row_data   JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync.capture_row_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO sync.outbox (table_name, operation, row_data) VALUES (TG_TABLE_NAME, TG_OP, to_jsonb(OLD));
+        RETURN OLD;
+    END IF;
+    INSERT INTO sync.outbox (table_name, operation, row_data) VALUES (TG_TABLE_NAME, TG_OP, to_jsonb(NEW));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER deposit_outbox AFTER INSERT OR UPDATE OR DELETE ON sync.deposit
+    FOR EACH ROW EXECUTE FUNCTION sync.capture_row_change();
+CREATE TRIGGER claim_outbox AFTER INSERT OR UPDATE OR DELETE ON sync.claim
+    FOR EACH ROW EXECUTE FUNCTION sync.capture_row_change();
+
+-- The changes are only captured when the outbox is enabled in the config
+ALTER TABLE sync.deposit DISABLE TRIGGER deposit_outbox;
+ALTER TABLE sync.claim DISABLE TRIGGER claim_outbox;
@@ -0,0 +1,56 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the outbox table with the changes of the deposits and claims.
+
+type migrationTest0011 struct{}
+
+func (m migrationTest0011) InsertData(db *sql.DB) error {
+	block := "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(2, 2803824, decode('27474F16174BBE50C294FE13C190B92E42B2368A6D4AEB8A4A015F52816296C3','hex'), decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), 0, '2023-01-01 10:30:00.000+00');"
+	_, err := db.Exec(block)
+	return err
+}
+
+const addOutboxDepositSQL = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata) VALUES (0, 0, 0, decode('0000000000000000000000000000000000000000','hex'), '1000000000000000000', 1, decode('F39FD6E51AAD88F6F4CE6AB8827279CFFFB92266','hex'), 2, $1, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B9C5A1E2FE9E3A428A7DC','hex'), decode('','hex'))"
+
+func (m migrationTest0011) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	// The changes are not captured while the triggers are disabled
+	_, err := db.Exec(addOutboxDepositSQL, 1)
+	assert.NoError(t, err)
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.outbox;").Scan(&count))
+	assert.Equal(t, 0, count)
+
+	_, err = db.Exec("ALTER TABLE sync.deposit ENABLE TRIGGER deposit_outbox;")
+	assert.NoError(t, err)
+	_, err = db.Exec(addOutboxDepositSQL, 2)
+	assert.NoError(t, err)
+	_, err = db.Exec("UPDATE sync.deposit SET ready_for_claim = true WHERE deposit_cnt = 2;")
+	assert.NoError(t, err)
+	var (
+		tableName, operation string
+		depositCnt           int
+	)
+	row := db.QueryRow("SELECT table_name, operation, (row_data->>'deposit_cnt')::INTEGER FROM sync.outbox ORDER BY id LIMIT 1;")
+	assert.NoError(t, row.Scan(&tableName, &operation, &depositCnt))
+	assert.Equal(t, "deposit", tableName)
+	assert.Equal(t, "INSERT", operation)
+	assert.Equal(t, 2, depositCnt)
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.outbox WHERE operation = 'UPDATE';").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func (m migrationTest0011) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.outbox;").Scan(&count))
+}
+
+func TestMigration0011(t *testing.T) {
+	runMigrationTest(t, 11, migrationTest0011{})
+}
@@ -0,0 +1,11 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.network_pin;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sync.network_pin
+(
+    network_id  INTEGER PRIMARY KEY,
+    chain_id    BIGINT NOT NULL,
+    bridge_addr BYTEA NOT NULL,
+    ger_addr    BYTEA NOT NULL
+);
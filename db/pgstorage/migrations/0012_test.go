@@ -0,0 +1,34 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the table with the chain ids and contract addresses the database was built against.
+
+type migrationTest0012 struct{}
+
+func (m migrationTest0012) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0012) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const addPinSQL = "INSERT INTO sync.network_pin (network_id, chain_id, bridge_addr, ger_addr) VALUES (0, 1, decode('2a3DD3EB832aF982ec71669E178424b10Dca2EDe','hex'), decode('580bda1e7A0CFAe92Fa7F6c20A3794F169CE3CFb','hex'))"
+	_, err := db.Exec(addPinSQL)
+	assert.NoError(t, err)
+	// Only one pin per network
+	_, err = db.Exec(addPinSQL)
+	assert.Error(t, err)
+}
+
+func (m migrationTest0012) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.network_pin;").Scan(&count))
+}
+
+func TestMigration0012(t *testing.T) {
+	runMigrationTest(t, 12, migrationTest0012{})
+}
@@ -0,0 +1,6 @@
+-- +migrate Down
+ALTER TABLE sync.monitored_txs DROP COLUMN IF EXISTS signed_tx;
+
+-- +migrate Up
+-- The claim txs signed offline are stored until the claim tx manager sends them
+ALTER TABLE sync.monitored_txs ADD COLUMN IF NOT EXISTS signed_tx BYTEA;
@@ -0,0 +1,35 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the column with the claim txs signed offline.
+
+type migrationTest0013 struct{}
+
+func (m migrationTest0013) InsertData(db *sql.DB) error {
+	const addMonitoredTxSQL = "INSERT INTO sync.monitored_txs (deposit_id, from_addr, nonce, gas, status, created_at, updated_at) VALUES (1, decode('2a3DD3EB832aF982ec71669E178424b10Dca2EDe','hex'), 0, 100000, 'pending_signature', NOW(), NOW())"
+	_, err := db.Exec(addMonitoredTxSQL)
+	return err
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	_, err := db.Exec("UPDATE sync.monitored_txs SET signed_tx = decode('f86c','hex'), status = 'signed' WHERE deposit_id = 1")
+	assert.NoError(t, err)
+	var signedTx []byte
+	assert.NoError(t, db.QueryRow("SELECT signed_tx FROM sync.monitored_txs WHERE deposit_id = 1").Scan(&signedTx))
+	assert.Equal(t, []byte{0xf8, 0x6c}, signedTx)
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var signedTx []byte
+	assert.Error(t, db.QueryRow("SELECT signed_tx FROM sync.monitored_txs WHERE deposit_id = 1").Scan(&signedTx))
+}
+
+func TestMigration0013(t *testing.T) {
+	runMigrationTest(t, 13, migrationTest0013{})
+}
@@ -0,0 +1,17 @@
+-- +migrate Down
+DROP INDEX IF EXISTS sync.deposit_tx_hash_idx;
+DROP TABLE IF EXISTS sync.deposit_trace;
+
+-- +migrate Up
+-- The origin of the deposit txs, traced from their internal calls
+CREATE TABLE IF NOT EXISTS sync.deposit_trace
+(
+    network_id INTEGER NOT NULL,
+    tx_hash    BYTEA NOT NULL,
+    block_id   BIGINT NOT NULL REFERENCES sync.block (id) ON DELETE CASCADE,
+    originator BYTEA NOT NULL,
+    call_path  BYTEA[] NOT NULL,
+    PRIMARY KEY (network_id, tx_hash)
+);
+
+CREATE INDEX IF NOT EXISTS deposit_tx_hash_idx ON sync.deposit(tx_hash);
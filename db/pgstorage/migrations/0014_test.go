@@ -0,0 +1,42 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the table with the traced origin of the deposit txs.
+
+type migrationTest0014 struct{}
+
+func (m migrationTest0014) InsertData(db *sql.DB) error {
+	block := "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(14, 2803824, decode('37474F16174BBE50C294FE13C190B92E42B2368A6D4AEB8A4A015F52816296C3','hex'), decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), 0, '0001-01-01 01:00:00.000');"
+	_, err := db.Exec(block)
+	return err
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	insertTrace := "INSERT INTO sync.deposit_trace (network_id, tx_hash, block_id, originator, call_path) VALUES(0, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B3143DB50E4995CB2422E','hex'), 14, decode('F6BEEEBB578E214CA9E23B0E9683454FF88ED2A7','hex'), '{}');"
+	_, err := db.Exec(insertTrace)
+	assert.NoError(t, err)
+	_, err = db.Exec(insertTrace)
+	assert.Error(t, err)
+
+	// Check the traces are removed with the block
+	_, err = db.Exec("DELETE FROM sync.block WHERE id = 14;")
+	assert.NoError(t, err)
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.deposit_trace;").Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.deposit_trace;").Scan(&count))
+}
+
+func TestMigration0014(t *testing.T) {
+	runMigrationTest(t, 14, migrationTest0014{})
+}
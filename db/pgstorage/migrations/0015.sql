@@ -0,0 +1,29 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.deposit_status_history;
+
+-- +migrate Up
+-- The changes of status of the deposits, with the block of the event that changed it. The block is NULL
+-- when the change was not triggered by a synced block, e.g. the trusted global exit roots
+CREATE TABLE IF NOT EXISTS sync.deposit_status_history
+(
+    deposit_id BIGINT NOT NULL REFERENCES sync.deposit (id) ON DELETE CASCADE,
+    status     VARCHAR NOT NULL,
+    block_id   BIGINT REFERENCES sync.block (id) ON DELETE CASCADE,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (deposit_id, status)
+);
+
+-- Record the history of the deposits already synced. The time the deposits became ready for claim is unknown,
+-- so they are recorded as ready when the migration runs
+INSERT INTO sync.deposit_status_history (deposit_id, status, block_id, changed_at)
+SELECT d.id, 'indexed', b.id, b.received_at FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.block_id = b.id;
+
+INSERT INTO sync.deposit_status_history (deposit_id, status, block_id, changed_at)
+SELECT d.id, 'ready_for_claim', NULL, NOW() FROM sync.deposit AS d WHERE d.ready_for_claim;
+
+INSERT INTO sync.deposit_status_history (deposit_id, status, block_id, changed_at)
+SELECT d.id, 'claimed', b.id, b.received_at FROM sync.claim AS c
+    INNER JOIN sync.deposit AS d ON d.deposit_cnt = c.index AND d.dest_net = c.network_id AND d.orig_net = c.orig_net
+        AND d.orig_addr = c.orig_addr
+    INNER JOIN sync.block AS b ON c.block_id = b.id
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,69 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the table with the changes of status of the deposits.
+
+type migrationTest0015 struct{}
+
+func (m migrationTest0015) InsertData(db *sql.DB) error {
+	const block = "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES($1, $2, $3, decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), $4, '2023-01-01 10:30:00.000+00');"
+	if _, err := db.Exec(block, 15, 2803824, []byte{0x15}, 0); err != nil {
+		return err
+	}
+	if _, err := db.Exec(block, 16, 120, []byte{0x16}, 1); err != nil {
+		return err
+	}
+	const addDepositSQL = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata, ready_for_claim) VALUES (0, 0, 0, decode('0000000000000000000000000000000000000000','hex'), '1000', 1, decode('F39FD6E51AAD88F6F4CE6AB8827279CFFFB92266','hex'), 15, $1, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B9C5A1E2FE9E3A428A7DC','hex'), decode('','hex'), $2)"
+	if _, err := db.Exec(addDepositSQL, 1, true); err != nil {
+		return err
+	}
+	if _, err := db.Exec(addDepositSQL, 2, false); err != nil {
+		return err
+	}
+	const addClaimSQL = "INSERT INTO sync.claim (network_id, index, orig_net, orig_addr, amount, dest_addr, block_id, tx_hash) VALUES (1, $1, $2, decode('0000000000000000000000000000000000000000','hex'), '1000', decode('F39FD6E51AAD88F6F4CE6AB8827279CFFFB92266','hex'), 16, decode('D2D6575EA98EB55E36B5AC6E11196800362594458A4B9C5A1E2FE9E3A428A7DC','hex'))"
+	if _, err := db.Exec(addClaimSQL, 1, 0); err != nil {
+		return err
+	}
+	// The claim of a deposit with the same count from another origin network doesn't claim the deposit 2
+	_, err := db.Exec(addClaimSQL, 2, 1)
+	return err
+}
+
+func (m migrationTest0015) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	// Check the history of the synced deposits is recorded
+	countSQL := "SELECT count(*) FROM sync.deposit_status_history AS h INNER JOIN sync.deposit AS d ON d.id = h.deposit_id WHERE d.deposit_cnt = $1 AND h.status = $2"
+	for _, tc := range []struct {
+		depositCnt int
+		status     string
+		count      int
+	}{
+		{1, "indexed", 1}, {1, "ready_for_claim", 1}, {1, "claimed", 1},
+		{2, "indexed", 1}, {2, "ready_for_claim", 0}, {2, "claimed", 0},
+	} {
+		var count int
+		assert.NoError(t, db.QueryRow(countSQL, tc.depositCnt, tc.status).Scan(&count))
+		assert.Equal(t, tc.count, count, "deposit %d, status %s", tc.depositCnt, tc.status)
+	}
+
+	// Check the claimed status is removed with the block of the claim
+	_, err := db.Exec("DELETE FROM sync.block WHERE id = 16;")
+	assert.NoError(t, err)
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.deposit_status_history WHERE status = 'claimed';").Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func (m migrationTest0015) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.deposit_status_history;").Scan(&count))
+}
+
+func TestMigration0015(t *testing.T) {
+	runMigrationTest(t, 15, migrationTest0015{})
+}
@@ -0,0 +1,31 @@
+-- +migrate Down
+DROP TABLE IF EXISTS mt.rollup_exit;
+DROP TABLE IF EXISTS mt.l1_info_tree;
+
+-- +migrate Up
+-- The leaves of the rollup exit tree: the local exit root of a rollup every time its batches are verified,
+-- with the rollup exit root after the update
+CREATE TABLE IF NOT EXISTS mt.rollup_exit
+(
+    id        BIGSERIAL PRIMARY KEY,
+    leaf      BYTEA NOT NULL,
+    rollup_id BIGINT NOT NULL,
+    root      BYTEA NOT NULL,
+    block_id  BIGINT NOT NULL REFERENCES sync.block (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS rollup_exit_root_idx ON mt.rollup_exit (root);
+
+-- The leaves of the L1 info tree: the global exit root updates in L1, with the L1 info root after appending them
+CREATE TABLE IF NOT EXISTS mt.l1_info_tree
+(
+    leaf_index       BIGINT PRIMARY KEY,
+    leaf             BYTEA NOT NULL,
+    global_exit_root BYTEA NOT NULL,
+    parent_hash      BYTEA NOT NULL,
+    timestamp        TIMESTAMP WITH TIME ZONE NOT NULL,
+    root             BYTEA NOT NULL,
+    block_id         BIGINT NOT NULL REFERENCES sync.block (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS l1_info_tree_ger_idx ON mt.l1_info_tree (global_exit_root);
@@ -0,0 +1,49 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the tables of the leaves of the rollup exit tree and the L1 info tree.
+
+type migrationTest0016 struct{}
+
+func (m migrationTest0016) InsertData(db *sql.DB) error {
+	const block = "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(16, 2803825, decode('16','hex'), decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), 0, '2023-01-01 10:30:00.000+00');"
+	_, err := db.Exec(block)
+	return err
+}
+
+func (m migrationTest0016) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const addRollupExitSQL = "INSERT INTO mt.rollup_exit (leaf, rollup_id, root, block_id) VALUES (decode('01','hex'), 1, decode('02','hex'), 16)"
+	_, err := db.Exec(addRollupExitSQL)
+	assert.NoError(t, err)
+	const addL1InfoSQL = "INSERT INTO mt.l1_info_tree (leaf_index, leaf, global_exit_root, parent_hash, timestamp, root, block_id) VALUES ($1, decode('03','hex'), decode('04','hex'), decode('05','hex'), '2023-01-01 10:30:00.000+00', decode('06','hex'), 16)"
+	_, err = db.Exec(addL1InfoSQL, 0)
+	assert.NoError(t, err)
+	// The leaf index is unique
+	_, err = db.Exec(addL1InfoSQL, 0)
+	assert.Error(t, err)
+
+	// Check the leaves are removed with their block
+	_, err = db.Exec("DELETE FROM sync.block WHERE id = 16;")
+	assert.NoError(t, err)
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM mt.rollup_exit;").Scan(&count))
+	assert.Equal(t, 0, count)
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM mt.l1_info_tree;").Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func (m migrationTest0016) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM mt.rollup_exit;").Scan(&count))
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM mt.l1_info_tree;").Scan(&count))
+}
+
+func TestMigration0016(t *testing.T) {
+	runMigrationTest(t, 16, migrationTest0016{})
+}
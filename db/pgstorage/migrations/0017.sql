@@ -0,0 +1,29 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.deposit_annotation;
+DROP TABLE IF EXISTS sync.wallet_webhook;
+
+-- +migrate Up
+-- The notes of the wallets on their deposits. They are not removed with the deposits in a reorg,
+-- so they are kept when the deposit is synced again
+CREATE TABLE IF NOT EXISTS sync.deposit_annotation
+(
+    network_id  INTEGER NOT NULL,
+    deposit_cnt BIGINT NOT NULL,
+    address     BYTEA NOT NULL,
+    note        VARCHAR NOT NULL,
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (address, network_id, deposit_cnt)
+);
+
+-- The webhooks registered by the wallets, receiving the events of the deposits to their address
+CREATE TABLE IF NOT EXISTS sync.wallet_webhook
+(
+    id          BIGSERIAL PRIMARY KEY,
+    address     BYTEA NOT NULL,
+    url         VARCHAR NOT NULL,
+    secret      VARCHAR NOT NULL DEFAULT '',
+    event_types VARCHAR[] NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS wallet_webhook_address_idx ON sync.wallet_webhook (address);
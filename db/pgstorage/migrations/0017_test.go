@@ -0,0 +1,42 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the tables of the deposit annotations and the webhooks of the wallets.
+
+type migrationTest0017 struct{}
+
+func (m migrationTest0017) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const addAnnotationSQL = "INSERT INTO sync.deposit_annotation (network_id, deposit_cnt, address, note) VALUES (0, 1, decode('01','hex'), $1)"
+	_, err := db.Exec(addAnnotationSQL, "rent")
+	assert.NoError(t, err)
+	// A wallet has a single note per deposit
+	_, err = db.Exec(addAnnotationSQL, "salary")
+	assert.Error(t, err)
+
+	const addWebhookSQL = "INSERT INTO sync.wallet_webhook (address, url, event_types) VALUES (decode('01','hex'), 'https://example.com', '{claim_sent}') RETURNING id"
+	var id int64
+	assert.NoError(t, db.QueryRow(addWebhookSQL).Scan(&id))
+	var secret string
+	assert.NoError(t, db.QueryRow("SELECT secret FROM sync.wallet_webhook WHERE id = $1", id).Scan(&secret))
+	assert.Equal(t, "", secret)
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.deposit_annotation;").Scan(&count))
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.wallet_webhook;").Scan(&count))
+}
+
+func TestMigration0017(t *testing.T) {
+	runMigrationTest(t, 17, migrationTest0017{})
+}
@@ -0,0 +1,16 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.push_device;
+
+-- +migrate Up
+-- The devices registered by the wallets, receiving the push notifications of the deposits to their address.
+-- A device token belongs to a single wallet, the last one registering it
+CREATE TABLE IF NOT EXISTS sync.push_device
+(
+    platform   VARCHAR NOT NULL,
+    token      VARCHAR NOT NULL,
+    address    BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (platform, token)
+);
+
+CREATE INDEX IF NOT EXISTS push_device_address_idx ON sync.push_device (address);
@@ -0,0 +1,36 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the table of the push notification devices of the wallets.
+
+type migrationTest0018 struct{}
+
+func (m migrationTest0018) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0018) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const addDeviceSQL = "INSERT INTO sync.push_device (platform, token, address) VALUES ('fcm', 'token', decode($1,'hex'))"
+	_, err := db.Exec(addDeviceSQL, "01")
+	assert.NoError(t, err)
+	// A device token is registered once
+	_, err = db.Exec(addDeviceSQL, "02")
+	assert.Error(t, err)
+	_, err = db.Exec("INSERT INTO sync.push_device (platform, token, address) VALUES ('apns', 'token', decode('01','hex'))")
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0018) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.push_device;").Scan(&count))
+}
+
+func TestMigration0018(t *testing.T) {
+	runMigrationTest(t, 18, migrationTest0018{})
+}
@@ -0,0 +1,15 @@
+-- +migrate Down
+ALTER TABLE sync.block DROP COLUMN IF EXISTS synced_at;
+ALTER TABLE sync.deposit DROP COLUMN IF EXISTS log_index;
+ALTER TABLE sync.claim DROP COLUMN IF EXISTS log_index;
+
+-- +migrate Up
+-- The time each block was synced by the service, next to the timestamp of the block in received_at. The blocks
+-- synced before the migration have no sync time
+ALTER TABLE sync.block ADD COLUMN IF NOT EXISTS synced_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE sync.block ALTER COLUMN synced_at SET DEFAULT NOW();
+
+-- The index of the log of the deposits and claims in their block, ordering the events of a block. The events
+-- synced before the migration have no log index
+ALTER TABLE sync.deposit ADD COLUMN IF NOT EXISTS log_index INTEGER;
+ALTER TABLE sync.claim ADD COLUMN IF NOT EXISTS log_index INTEGER;
@@ -0,0 +1,49 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the sync time of the blocks and the log index of the deposits and claims.
+
+type migrationTest0019 struct{}
+
+const migrationTest0019Block = "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES($1, $2, $3, decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), 0, '2023-01-01 10:30:00.000+00');"
+
+func (m migrationTest0019) InsertData(db *sql.DB) error {
+	_, err := db.Exec(migrationTest0019Block, 19, 2803819, []byte{0x19})
+	return err
+}
+
+func (m migrationTest0019) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	// The blocks synced before the migration have no sync time, the new ones are set by the database
+	const getSyncedAtSQL = "SELECT synced_at IS NOT NULL FROM sync.block WHERE id = $1"
+	var synced bool
+	assert.NoError(t, db.QueryRow(getSyncedAtSQL, 19).Scan(&synced))
+	assert.False(t, synced)
+	_, err := db.Exec(migrationTest0019Block, 20, 2803820, []byte{0x20})
+	assert.NoError(t, err)
+	assert.NoError(t, db.QueryRow(getSyncedAtSQL, 20).Scan(&synced))
+	assert.True(t, synced)
+
+	const addDepositSQL = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata, log_index) VALUES (0, 0, 0, decode('00','hex'), '1000', 1, decode('01','hex'), 20, 1, decode('02','hex'), decode('','hex'), 7)"
+	_, err = db.Exec(addDepositSQL)
+	assert.NoError(t, err)
+	const addClaimSQL = "INSERT INTO sync.claim (network_id, index, orig_net, orig_addr, amount, dest_addr, block_id, tx_hash, log_index) VALUES (0, 1, 1, decode('00','hex'), '1000', decode('01','hex'), 20, decode('03','hex'), 8)"
+	_, err = db.Exec(addClaimSQL)
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0019) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(synced_at) FROM sync.block;").Scan(&count))
+	assert.Error(t, db.QueryRow("SELECT count(log_index) FROM sync.deposit;").Scan(&count))
+	assert.Error(t, db.QueryRow("SELECT count(log_index) FROM sync.claim;").Scan(&count))
+}
+
+func TestMigration0019(t *testing.T) {
+	runMigrationTest(t, 19, migrationTest0019{})
+}
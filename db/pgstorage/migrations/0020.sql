@@ -0,0 +1,19 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.query_job;
+
+-- +migrate Up
+-- The admin queries run asynchronously, with their result once they finish. The jobs are deleted after their TTL
+CREATE TABLE IF NOT EXISTS sync.query_job
+(
+    id          VARCHAR PRIMARY KEY,
+    query       VARCHAR NOT NULL,
+    params      JSONB NOT NULL,
+    status      VARCHAR NOT NULL,
+    error       VARCHAR NOT NULL DEFAULT '',
+    row_cnt     BIGINT NOT NULL DEFAULT 0,
+    result      BYTEA,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS query_job_created_at_idx ON sync.query_job (created_at);
@@ -0,0 +1,38 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration creates the table of the asynchronous admin query jobs.
+
+type migrationTest0020 struct{}
+
+func (m migrationTest0020) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0020) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const addJobSQL = "INSERT INTO sync.query_job (id, query, params, status) VALUES ('1', 'address_history', '{\"address\": \"0x01\"}', 'running')"
+	_, err := db.Exec(addJobSQL)
+	assert.NoError(t, err)
+	_, err = db.Exec(addJobSQL)
+	assert.Error(t, err)
+	_, err = db.Exec("UPDATE sync.query_job SET status = 'done', row_cnt = 1, result = decode('7b7d','hex'), finished_at = NOW() WHERE id = '1'")
+	assert.NoError(t, err)
+	var rows int
+	assert.NoError(t, db.QueryRow("SELECT row_cnt FROM sync.query_job WHERE id = '1'").Scan(&rows))
+	assert.Equal(t, 1, rows)
+}
+
+func (m migrationTest0020) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(*) FROM sync.query_job;").Scan(&count))
+}
+
+func TestMigration0020(t *testing.T) {
+	runMigrationTest(t, 20, migrationTest0020{})
+}
@@ -0,0 +1,10 @@
+-- +migrate Down
+DROP INDEX IF EXISTS sync.deposit_integration_idx;
+ALTER TABLE sync.deposit DROP COLUMN IF EXISTS integration;
+
+-- +migrate Up
+-- The integration each deposit comes from: a known integration, direct or contract. The deposits synced before the
+-- migration or without the attribution enabled are unattributed, with an empty integration
+ALTER TABLE sync.deposit ADD COLUMN IF NOT EXISTS integration VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS deposit_integration_idx ON sync.deposit(network_id, integration);
@@ -0,0 +1,46 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the integration the deposits come from.
+
+type migrationTest0021 struct{}
+
+const migrationTest0021Deposit = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata) VALUES (0, 0, 0, decode('00','hex'), '1000', 1, decode('01','hex'), 21, $1, decode('02','hex'), decode('','hex'))"
+
+func (m migrationTest0021) InsertData(db *sql.DB) error {
+	const block = "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(21, 2803821, decode('21','hex'), decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), 0, '2023-01-01 10:30:00.000+00');"
+	if _, err := db.Exec(block); err != nil {
+		return err
+	}
+	_, err := db.Exec(migrationTest0021Deposit, 1)
+	return err
+}
+
+func (m migrationTest0021) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	// The deposits synced before the migration are unattributed
+	const getIntegrationSQL = "SELECT integration FROM sync.deposit WHERE deposit_cnt = $1"
+	var integration string
+	assert.NoError(t, db.QueryRow(getIntegrationSQL, 1).Scan(&integration))
+	assert.Equal(t, "", integration)
+	_, err := db.Exec(migrationTest0021Deposit, 2)
+	assert.NoError(t, err)
+	_, err = db.Exec("UPDATE sync.deposit SET integration = 'direct' WHERE deposit_cnt = 2")
+	assert.NoError(t, err)
+	assert.NoError(t, db.QueryRow(getIntegrationSQL, 2).Scan(&integration))
+	assert.Equal(t, "direct", integration)
+}
+
+func (m migrationTest0021) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.Error(t, db.QueryRow("SELECT count(integration) FROM sync.deposit;").Scan(&count))
+}
+
+func TestMigration0021(t *testing.T) {
+	runMigrationTest(t, 21, migrationTest0021{})
+}
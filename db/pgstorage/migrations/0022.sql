@@ -0,0 +1,125 @@
+-- +migrate Down
+COMMENT ON TABLE sync.block IS NULL;
+COMMENT ON TABLE sync.exit_root IS NULL;
+COMMENT ON TABLE sync.deposit IS NULL;
+COMMENT ON TABLE sync.claim IS NULL;
+COMMENT ON TABLE sync.token_wrapped IS NULL;
+COMMENT ON TABLE sync.token_wrapped_history IS NULL;
+COMMENT ON TABLE sync.monitored_txs IS NULL;
+COMMENT ON TABLE sync.raw_log IS NULL;
+COMMENT ON TABLE sync.activity_bucket IS NULL;
+COMMENT ON TABLE sync.outbox IS NULL;
+COMMENT ON TABLE sync.network_pin IS NULL;
+COMMENT ON TABLE sync.deposit_trace IS NULL;
+COMMENT ON TABLE sync.deposit_status_history IS NULL;
+COMMENT ON TABLE sync.deposit_annotation IS NULL;
+COMMENT ON TABLE sync.wallet_webhook IS NULL;
+COMMENT ON TABLE sync.push_device IS NULL;
+COMMENT ON TABLE sync.query_job IS NULL;
+COMMENT ON TABLE mt.rht IS NULL;
+COMMENT ON TABLE mt.root IS NULL;
+COMMENT ON TABLE mt.rollup_exit IS NULL;
+COMMENT ON TABLE mt.l1_info_tree IS NULL;
+
+COMMENT ON COLUMN sync.block.id IS NULL;
+COMMENT ON COLUMN sync.block.network_id IS NULL;
+COMMENT ON COLUMN sync.block.received_at IS NULL;
+COMMENT ON COLUMN sync.block.synced_at IS NULL;
+COMMENT ON COLUMN sync.exit_root.block_id IS NULL;
+COMMENT ON COLUMN sync.exit_root.exit_roots IS NULL;
+COMMENT ON COLUMN sync.deposit.id IS NULL;
+COMMENT ON COLUMN sync.deposit.leaf_type IS NULL;
+COMMENT ON COLUMN sync.deposit.network_id IS NULL;
+COMMENT ON COLUMN sync.deposit.orig_net IS NULL;
+COMMENT ON COLUMN sync.deposit.orig_addr IS NULL;
+COMMENT ON COLUMN sync.deposit.amount IS NULL;
+COMMENT ON COLUMN sync.deposit.deposit_cnt IS NULL;
+COMMENT ON COLUMN sync.deposit.metadata IS NULL;
+COMMENT ON COLUMN sync.deposit.ready_for_claim IS NULL;
+COMMENT ON COLUMN sync.deposit.log_index IS NULL;
+COMMENT ON COLUMN sync.deposit.integration IS NULL;
+COMMENT ON COLUMN sync.claim.network_id IS NULL;
+COMMENT ON COLUMN sync.claim.index IS NULL;
+COMMENT ON COLUMN sync.claim.log_index IS NULL;
+COMMENT ON COLUMN sync.token_wrapped.network_id IS NULL;
+COMMENT ON COLUMN sync.monitored_txs.deposit_id IS NULL;
+COMMENT ON COLUMN sync.monitored_txs.status IS NULL;
+COMMENT ON COLUMN sync.monitored_txs.history IS NULL;
+COMMENT ON COLUMN sync.monitored_txs.signed_tx IS NULL;
+COMMENT ON COLUMN sync.activity_bucket.kind IS NULL;
+COMMENT ON COLUMN sync.activity_bucket.bucket IS NULL;
+COMMENT ON COLUMN sync.outbox.operation IS NULL;
+COMMENT ON COLUMN sync.deposit_trace.originator IS NULL;
+COMMENT ON COLUMN sync.deposit_trace.call_path IS NULL;
+COMMENT ON COLUMN sync.deposit_status_history.block_id IS NULL;
+COMMENT ON COLUMN sync.query_job.result IS NULL;
+COMMENT ON COLUMN mt.rht.key IS NULL;
+COMMENT ON COLUMN mt.rht.value IS NULL;
+COMMENT ON COLUMN mt.rht.deposit_id IS NULL;
+COMMENT ON COLUMN mt.root.deposit_id IS NULL;
+COMMENT ON COLUMN mt.rollup_exit.root IS NULL;
+COMMENT ON COLUMN mt.l1_info_tree.root IS NULL;
+
+-- +migrate Up
+-- The docs of the tables, read by the schema command. The columns whose meaning is clear from their name and
+-- the table are not commented
+COMMENT ON TABLE sync.block IS 'The synced blocks of every network with bridge events. The block 0 holds the trusted global exit roots';
+COMMENT ON TABLE sync.exit_root IS 'The global exit roots of L1, synced from the global exit root manager, and the trusted one of the L2';
+COMMENT ON TABLE sync.deposit IS 'The deposits of the bridges, the leaves of the local exit tree of their network';
+COMMENT ON TABLE sync.claim IS 'The claims of the deposits in their destination network';
+COMMENT ON TABLE sync.token_wrapped IS 'The current wrapped token of each original token in the networks';
+COMMENT ON TABLE sync.token_wrapped_history IS 'Every wrapped token created for an original token, with the block it was created in';
+COMMENT ON TABLE sync.monitored_txs IS 'The claim txs sent by the claim tx manager until they are mined';
+COMMENT ON TABLE sync.raw_log IS 'The raw deposit and claim logs, stored to decode them again, only if PersistRawLogs is enabled';
+COMMENT ON TABLE sync.activity_bucket IS 'The deposits and claims aggregated by hour, network and token';
+COMMENT ON TABLE sync.outbox IS 'The changes of the deposits and claims for the CDC pipelines, only written if the change outbox is enabled';
+COMMENT ON TABLE sync.network_pin IS 'The chain ids and contract addresses the database was built against';
+COMMENT ON TABLE sync.deposit_trace IS 'The originator and the contracts calling the bridge of the traced deposit txs';
+COMMENT ON TABLE sync.deposit_status_history IS 'The changes of status of the deposits';
+COMMENT ON TABLE sync.deposit_annotation IS 'The notes of the wallets on their deposits, kept in the reorgs';
+COMMENT ON TABLE sync.wallet_webhook IS 'The webhooks of the wallets, receiving the events of the deposits to their address';
+COMMENT ON TABLE sync.push_device IS 'The devices of the wallets, receiving the push notifications of the deposits to their address';
+COMMENT ON TABLE sync.query_job IS 'The admin queries run asynchronously, with their result once they finish';
+COMMENT ON TABLE mt.rht IS 'The nodes of the local exit trees, the children of the node by its hash';
+COMMENT ON TABLE mt.root IS 'The roots of the local exit trees after each deposit';
+COMMENT ON TABLE mt.rollup_exit IS 'The leaves of the rollup exit tree, the local exit root of a rollup each time its batches are verified';
+COMMENT ON TABLE mt.l1_info_tree IS 'The leaves of the L1 info tree, the global exit root updates of L1';
+
+COMMENT ON COLUMN sync.block.id IS 'Referenced by the events of the block, that are deleted with it in a reorg';
+COMMENT ON COLUMN sync.block.network_id IS 'Network of the block, NULL for the block 0';
+COMMENT ON COLUMN sync.block.received_at IS 'Timestamp of the block';
+COMMENT ON COLUMN sync.block.synced_at IS 'Time the block was synced by the service, NULL for the blocks synced before it was recorded';
+COMMENT ON COLUMN sync.exit_root.block_id IS 'Block of the update, 0 for the trusted global exit root';
+COMMENT ON COLUMN sync.exit_root.exit_roots IS 'The mainnet and the rollup exit roots of the global exit root';
+COMMENT ON COLUMN sync.deposit.id IS 'Internal id of the deposit, referenced by the trees and the claim txs';
+COMMENT ON COLUMN sync.deposit.leaf_type IS '0 for the assets and 1 for the messages';
+COMMENT ON COLUMN sync.deposit.network_id IS 'Network of the bridge the deposit was made in';
+COMMENT ON COLUMN sync.deposit.orig_net IS 'Network of the original token';
+COMMENT ON COLUMN sync.deposit.orig_addr IS 'Address of the original token, zero for the ether or the message sender for the messages';
+COMMENT ON COLUMN sync.deposit.amount IS 'Amount in the smallest unit of the token, as a decimal string';
+COMMENT ON COLUMN sync.deposit.deposit_cnt IS 'Index of the leaf of the deposit in the local exit tree of its network';
+COMMENT ON COLUMN sync.deposit.metadata IS 'ABI encoded name, symbol and decimals of the token, or the data of the message';
+COMMENT ON COLUMN sync.deposit.ready_for_claim IS 'The exit root of the deposit is in a global exit root of the destination network';
+COMMENT ON COLUMN sync.deposit.log_index IS 'Index of the log in the block, NULL for the deposits synced before it was recorded';
+COMMENT ON COLUMN sync.deposit.integration IS 'Integration the deposit comes from, empty if it was not attributed';
+COMMENT ON COLUMN sync.claim.network_id IS 'Destination network of the claimed deposit, where the claim was made';
+COMMENT ON COLUMN sync.claim.index IS 'Deposit count of the claimed deposit';
+COMMENT ON COLUMN sync.claim.log_index IS 'Index of the log in the block, NULL for the claims synced before it was recorded';
+COMMENT ON COLUMN sync.token_wrapped.network_id IS 'Network of the wrapped token';
+COMMENT ON COLUMN sync.monitored_txs.deposit_id IS 'Id of the claimed deposit';
+COMMENT ON COLUMN sync.monitored_txs.status IS 'created, failed, confirmed, pending_approval, approved, pending_signature or signed';
+COMMENT ON COLUMN sync.monitored_txs.history IS 'Hashes of every tx sent for the claim';
+COMMENT ON COLUMN sync.monitored_txs.signed_tx IS 'Claim tx signed offline, until the claim tx manager sends it';
+COMMENT ON COLUMN sync.activity_bucket.kind IS 'deposit or claim';
+COMMENT ON COLUMN sync.activity_bucket.bucket IS 'UTC hour of the blocks of the events';
+COMMENT ON COLUMN sync.outbox.operation IS 'INSERT, UPDATE or DELETE';
+COMMENT ON COLUMN sync.deposit_trace.originator IS 'Sender of the deposit tx';
+COMMENT ON COLUMN sync.deposit_trace.call_path IS 'Contracts called from the originator until the bridge, empty if the tx was sent to the bridge';
+COMMENT ON COLUMN sync.deposit_status_history.block_id IS 'Block of the event that changed the status, NULL if it was not a synced block';
+COMMENT ON COLUMN sync.query_job.result IS 'Result of the finished query, NULL until then or if it failed';
+COMMENT ON COLUMN mt.rht.key IS 'Hash of the node';
+COMMENT ON COLUMN mt.rht.value IS 'The left and the right children of the node';
+COMMENT ON COLUMN mt.rht.deposit_id IS 'Deposit that added the node, that is removed with it in a reorg';
+COMMENT ON COLUMN mt.root.deposit_id IS 'Last deposit of the tree of the root';
+COMMENT ON COLUMN mt.rollup_exit.root IS 'Rollup exit root after the update';
+COMMENT ON COLUMN mt.l1_info_tree.root IS 'L1 info root after appending the leaf';
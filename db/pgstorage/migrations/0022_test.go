@@ -0,0 +1,37 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the docs of the tables and columns as comments.
+
+type migrationTest0022 struct{}
+
+const migrationTest0022Comment = "SELECT COALESCE(obj_description('sync.deposit'::regclass, 'pg_class'), '')"
+
+func (m migrationTest0022) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0022) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var comment string
+	assert.NoError(t, db.QueryRow(migrationTest0022Comment).Scan(&comment))
+	assert.NotEmpty(t, comment)
+	const columnCommentSQL = "SELECT COALESCE(col_description('sync.deposit'::regclass, (SELECT attnum FROM pg_attribute WHERE attrelid = 'sync.deposit'::regclass AND attname = 'deposit_cnt')), '')"
+	assert.NoError(t, db.QueryRow(columnCommentSQL).Scan(&comment))
+	assert.NotEmpty(t, comment)
+}
+
+func (m migrationTest0022) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var comment string
+	assert.NoError(t, db.QueryRow(migrationTest0022Comment).Scan(&comment))
+	assert.Empty(t, comment)
+}
+
+func TestMigration0022(t *testing.T) {
+	runMigrationTest(t, 22, migrationTest0022{})
+}
@@ -0,0 +1,11 @@
+-- +migrate Down
+DROP INDEX IF EXISTS sync.deposit_dest_addr_idx;
+DROP INDEX IF EXISTS sync.claim_dest_addr_idx;
+DROP INDEX IF EXISTS sync.claim_tx_hash_idx;
+
+-- +migrate Up
+-- The prefix searches of the addresses and the tx hashes are range scans of these indexes, the bytea values are
+-- ordered byte by byte. The tx hashes of the deposits are already indexed by deposit_tx_hash_idx
+CREATE INDEX IF NOT EXISTS deposit_dest_addr_idx ON sync.deposit(dest_addr);
+CREATE INDEX IF NOT EXISTS claim_dest_addr_idx ON sync.claim(dest_addr);
+CREATE INDEX IF NOT EXISTS claim_tx_hash_idx ON sync.claim(tx_hash);
@@ -0,0 +1,34 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the indexes of the prefix searches.
+
+type migrationTest0023 struct{}
+
+const migrationTest0023Indexes = "SELECT count(*) FROM pg_indexes WHERE schemaname = 'sync' AND indexname IN ('deposit_dest_addr_idx', 'claim_dest_addr_idx', 'claim_tx_hash_idx')"
+
+func (m migrationTest0023) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0023) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0023Indexes).Scan(&count))
+	assert.Equal(t, 3, count)
+}
+
+func (m migrationTest0023) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0023Indexes).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0023(t *testing.T) {
+	runMigrationTest(t, 23, migrationTest0023{})
+}
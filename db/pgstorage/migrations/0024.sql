@@ -0,0 +1,25 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.proof_consumer;
+DROP TABLE IF EXISTS sync.proof_access;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sync.proof_consumer
+(
+    name       VARCHAR PRIMARY KEY,
+    key_hash   BYTEA NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- A single row, so every instance of the service restricts the proofs at the same time
+CREATE TABLE IF NOT EXISTS sync.proof_access
+(
+    id         BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    restricted BOOLEAN NOT NULL,
+    reason     VARCHAR NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE sync.proof_consumer IS 'The consumers allowed to get the proofs when they are restricted, e.g. the relayers of the operator';
+COMMENT ON TABLE sync.proof_access IS 'The mode of the proofs set by the operators, the one of the config is used without it';
+COMMENT ON COLUMN sync.proof_consumer.key_hash IS 'SHA-256 of the key of the consumer, the key is not stored';
+COMMENT ON COLUMN sync.proof_access.reason IS 'Note of the operator on the change, e.g. the incident';
@@ -0,0 +1,41 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the consumers and the mode of the restricted proofs.
+
+type migrationTest0024 struct{}
+
+const migrationTest0024Tables = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename IN ('proof_consumer', 'proof_access')"
+
+func (m migrationTest0024) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0024) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0024Tables).Scan(&count))
+	assert.Equal(t, 2, count)
+
+	// The mode has a single row
+	const setAccessSQL = "INSERT INTO sync.proof_access (id, restricted, reason, updated_at) VALUES ($1, true, '', NOW())"
+	_, err := db.Exec(setAccessSQL, true)
+	assert.NoError(t, err)
+	_, err = db.Exec(setAccessSQL, false)
+	assert.Error(t, err)
+}
+
+func (m migrationTest0024) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0024Tables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0024(t *testing.T) {
+	runMigrationTest(t, 24, migrationTest0024{})
+}
@@ -0,0 +1,47 @@
+-- +migrate Down
+DROP TRIGGER IF EXISTS deposit_notify ON sync.deposit;
+DROP TRIGGER IF EXISTS claim_notify ON sync.claim;
+DROP TRIGGER IF EXISTS token_wrapped_notify ON sync.token_wrapped;
+DROP FUNCTION IF EXISTS sync.notify_row_change();
+
+-- +migrate Up
+-- Notifies the changes of the rows on the bridge_changes channel, with the keys the API replicas invalidate. The
+-- notifications are sent on commit, and the identical ones of a transaction are sent once
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync.notify_row_change() RETURNS TRIGGER AS $$
+DECLARE
+    r RECORD;
+    payload JSON;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        r := OLD;
+    ELSE
+        r := NEW;
+    END IF;
+    IF TG_TABLE_NAME = 'deposit' THEN
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.deposit_cnt, 'dest_net', r.dest_net, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'));
+    ELSIF TG_TABLE_NAME = 'claim' THEN
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.index, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'));
+    ELSE
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'orig_net', r.orig_net, 'orig_addr', '0x' || encode(r.orig_token_addr, 'hex'));
+    END IF;
+    PERFORM pg_notify('bridge_changes', payload::TEXT);
+    RETURN r;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER deposit_notify AFTER INSERT OR UPDATE OR DELETE ON sync.deposit
+    FOR EACH ROW EXECUTE FUNCTION sync.notify_row_change();
+CREATE TRIGGER claim_notify AFTER INSERT OR UPDATE OR DELETE ON sync.claim
+    FOR EACH ROW EXECUTE FUNCTION sync.notify_row_change();
+CREATE TRIGGER token_wrapped_notify AFTER INSERT OR UPDATE OR DELETE ON sync.token_wrapped
+    FOR EACH ROW EXECUTE FUNCTION sync.notify_row_change();
+
+-- The changes are only notified when the notifications are enabled in the config
+ALTER TABLE sync.deposit DISABLE TRIGGER deposit_notify;
+ALTER TABLE sync.claim DISABLE TRIGGER claim_notify;
+ALTER TABLE sync.token_wrapped DISABLE TRIGGER token_wrapped_notify;
@@ -0,0 +1,64 @@
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/stdlib"
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the triggers notifying the changes of the deposits, claims and wrapped tokens.
+
+type migrationTest0025 struct{}
+
+const migrationTest0025Triggers = "SELECT count(*) FROM pg_trigger WHERE tgname IN ('deposit_notify', 'claim_notify', 'token_wrapped_notify')"
+
+func (m migrationTest0025) InsertData(db *sql.DB) error {
+	block := "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(2, 2803824, decode('27474F16174BBE50C294FE13C190B92E42B2368A6D4AEB8A4A015F52816296C3','hex'), decode('C9B5033799ADF3739383A0489EFBE8A0D4D5E4478778A4F4304562FD51AE4C07','hex'), 0, '2023-01-01 10:30:00.000+00');"
+	_, err := db.Exec(block)
+	return err
+}
+
+func (m migrationTest0025) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0025Triggers).Scan(&count))
+	assert.Equal(t, 3, count)
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	assert.NoError(t, err)
+	defer conn.Close() //nolint:errcheck
+	_, err = conn.ExecContext(ctx, "LISTEN bridge_changes")
+	assert.NoError(t, err)
+	_, err = db.Exec("ALTER TABLE sync.deposit ENABLE TRIGGER deposit_notify;")
+	assert.NoError(t, err)
+	_, err = db.Exec(addOutboxDepositSQL, 1)
+	assert.NoError(t, err)
+
+	// The payload has the keys of the deposit
+	err = conn.Raw(func(driverConn interface{}) error {
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		n, err := driverConn.(*stdlib.Conn).Conn().WaitForNotification(waitCtx)
+		if err != nil {
+			return err
+		}
+		assert.JSONEq(t, `{"table": "deposit", "op": "INSERT", "network_id": 0, "deposit_cnt": 1, "dest_net": 1,
+			"dest_addr": "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"}`, n.Payload)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0025) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0025Triggers).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0025(t *testing.T) {
+	runMigrationTest(t, 25, migrationTest0025{})
+}
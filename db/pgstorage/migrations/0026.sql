@@ -0,0 +1,19 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.idempotency_key;
+
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS sync.idempotency_key
+(
+    method       VARCHAR NOT NULL,
+    key          VARCHAR NOT NULL,
+    request_hash BYTEA NOT NULL,
+    response     BYTEA,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (method, key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_key_created_at ON sync.idempotency_key USING btree (created_at);
+
+COMMENT ON TABLE sync.idempotency_key IS 'The Idempotency-Key of the admin mutations, so their retries get the first response without running again';
+COMMENT ON COLUMN sync.idempotency_key.request_hash IS 'SHA-256 of the request, a key reused with another request is rejected';
+COMMENT ON COLUMN sync.idempotency_key.response IS 'The encoded response, NULL while the request is running';
@@ -0,0 +1,43 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the idempotency keys of the admin mutations.
+
+type migrationTest0026 struct{}
+
+const migrationTest0026Tables = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename = 'idempotency_key'"
+
+func (m migrationTest0026) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0026) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0026Tables).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// A key is unique for each method
+	const addKeySQL = "INSERT INTO sync.idempotency_key (method, key, request_hash, created_at) VALUES ($1, 'retry-1', '\\x01', NOW())"
+	_, err := db.Exec(addKeySQL, "ApproveClaim")
+	assert.NoError(t, err)
+	_, err = db.Exec(addKeySQL, "SetProofAccess")
+	assert.NoError(t, err)
+	_, err = db.Exec(addKeySQL, "ApproveClaim")
+	assert.Error(t, err)
+}
+
+func (m migrationTest0026) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0026Tables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0026(t *testing.T) {
+	runMigrationTest(t, 26, migrationTest0026{})
+}
@@ -0,0 +1,34 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration documents the uneconomical status of the claim txs.
+
+type migrationTest0027 struct{}
+
+const migrationTest0027Comment = "SELECT col_description('sync.monitored_txs'::regclass, (SELECT attnum FROM pg_attribute WHERE attrelid = 'sync.monitored_txs'::regclass AND attname = 'status'))"
+
+func (m migrationTest0027) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0027) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var comment string
+	assert.NoError(t, db.QueryRow(migrationTest0027Comment).Scan(&comment))
+	assert.Contains(t, comment, "uneconomical")
+}
+
+func (m migrationTest0027) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var comment string
+	assert.NoError(t, db.QueryRow(migrationTest0027Comment).Scan(&comment))
+	assert.NotContains(t, comment, "uneconomical")
+}
+
+func TestMigration0027(t *testing.T) {
+	runMigrationTest(t, 27, migrationTest0027{})
+}
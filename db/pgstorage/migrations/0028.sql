@@ -0,0 +1,20 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.deposit_ref;
+
+-- +migrate Up
+-- The external reference ids of the deposits set by their destination address. They are not removed with the
+-- deposits in a reorg, so they are kept when the deposit is synced again
+CREATE TABLE IF NOT EXISTS sync.deposit_ref
+(
+    network_id  INTEGER NOT NULL,
+    deposit_cnt BIGINT NOT NULL,
+    address     BYTEA NOT NULL,
+    ref_id      VARCHAR NOT NULL,
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (network_id, deposit_cnt)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS deposit_ref_address_ref_id_idx ON sync.deposit_ref (address, ref_id);
+
+COMMENT ON TABLE sync.deposit_ref IS 'The external reference ids of the deposits set by their destination address, kept in the reorgs';
+COMMENT ON COLUMN sync.deposit_ref.ref_id IS 'The id of the deposit in the ledger of the integrator, unique for each address';
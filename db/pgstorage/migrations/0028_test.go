@@ -0,0 +1,43 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the external reference ids of the deposits.
+
+type migrationTest0028 struct{}
+
+const migrationTest0028Tables = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename = 'deposit_ref'"
+
+func (m migrationTest0028) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0028) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0028Tables).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// A reference id is unique for each address
+	const addRefSQL = "INSERT INTO sync.deposit_ref (network_id, deposit_cnt, address, ref_id) VALUES (0, $1, $2, 'order-1')"
+	_, err := db.Exec(addRefSQL, 1, []byte{0x01})
+	assert.NoError(t, err)
+	_, err = db.Exec(addRefSQL, 2, []byte{0x02})
+	assert.NoError(t, err)
+	_, err = db.Exec(addRefSQL, 3, []byte{0x01})
+	assert.Error(t, err)
+}
+
+func (m migrationTest0028) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0028Tables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0028(t *testing.T) {
+	runMigrationTest(t, 28, migrationTest0028{})
+}
@@ -0,0 +1,18 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.webhook_spill;
+
+-- +migrate Up
+-- The webhook deliveries stored while the queue of the dispatcher is full, they are queued back in order when the
+-- workers free room
+CREATE TABLE IF NOT EXISTS sync.webhook_spill
+(
+    id           BIGSERIAL PRIMARY KEY,
+    subscription VARCHAR NOT NULL,
+    url          VARCHAR NOT NULL,
+    event_type   VARCHAR NOT NULL,
+    body         BYTEA NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE sync.webhook_spill IS 'The webhook deliveries spilled while the queue of the dispatcher is full';
+COMMENT ON COLUMN sync.webhook_spill.subscription IS 'The name of the subscription receiving the event, with the url';
@@ -0,0 +1,39 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the webhook deliveries spilled while the queue of the dispatcher is full.
+
+type migrationTest0029 struct{}
+
+const migrationTest0029Tables = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename = 'webhook_spill'"
+
+func (m migrationTest0029) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0029) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0029Tables).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	const addDeliverySQL = "INSERT INTO sync.webhook_spill (subscription, url, event_type, body) VALUES ('exchange', 'http://localhost', 'deposit_ready', $1) RETURNING id"
+	var id uint64
+	assert.NoError(t, db.QueryRow(addDeliverySQL, []byte("{}")).Scan(&id))
+	assert.Equal(t, uint64(1), id)
+}
+
+func (m migrationTest0029) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0029Tables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0029(t *testing.T) {
+	runMigrationTest(t, 29, migrationTest0029{})
+}
@@ -0,0 +1,34 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.token_list_audit;
+DROP TABLE IF EXISTS sync.token_list;
+
+-- +migrate Up
+-- The tokens in the allow and deny lists of the operators, managed with the admin API
+CREATE TABLE IF NOT EXISTS sync.token_list
+(
+    orig_net   INTEGER NOT NULL,
+    orig_addr  BYTEA NOT NULL,
+    list       VARCHAR NOT NULL,
+    reason     VARCHAR NOT NULL,
+    operator   VARCHAR NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (orig_net, orig_addr)
+);
+
+-- The changes of the token lists, never updated nor deleted
+CREATE TABLE IF NOT EXISTS sync.token_list_audit
+(
+    id         BIGSERIAL PRIMARY KEY,
+    action     VARCHAR NOT NULL,
+    orig_net   INTEGER NOT NULL,
+    orig_addr  BYTEA NOT NULL,
+    list       VARCHAR NOT NULL,
+    reason     VARCHAR NOT NULL,
+    operator   VARCHAR NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE sync.token_list IS 'The tokens in the allow and deny lists of the operators';
+COMMENT ON COLUMN sync.token_list.list IS 'allow or deny';
+COMMENT ON TABLE sync.token_list_audit IS 'The audit log of the changes of the token lists';
+COMMENT ON COLUMN sync.token_list_audit.action IS 'set or delete';
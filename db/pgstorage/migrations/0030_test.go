@@ -0,0 +1,40 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the token lists of the operators and their audit log.
+
+type migrationTest0030 struct{}
+
+const migrationTest0030Tables = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename IN ('token_list', 'token_list_audit')"
+
+func (m migrationTest0030) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0030) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0030Tables).Scan(&count))
+	assert.Equal(t, 2, count)
+
+	const addEntrySQL = "INSERT INTO sync.token_list (orig_net, orig_addr, list, reason, operator, updated_at) VALUES (0, $1, 'deny', 'scam', 'alice', NOW())"
+	_, err := db.Exec(addEntrySQL, []byte{1})
+	assert.NoError(t, err)
+	_, err = db.Exec(addEntrySQL, []byte{1})
+	assert.Error(t, err)
+}
+
+func (m migrationTest0030) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0030Tables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0030(t *testing.T) {
+	runMigrationTest(t, 30, migrationTest0030{})
+}
@@ -0,0 +1,26 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.config_change;
+
+-- +migrate Up
+-- The changes of the runtime settings proposed by an admin, applied when another admin approves them
+CREATE TABLE IF NOT EXISTS sync.config_change
+(
+    id          BIGSERIAL PRIMARY KEY,
+    method      VARCHAR NOT NULL,
+    request     VARCHAR NOT NULL,
+    reason      VARCHAR NOT NULL,
+    proposer    VARCHAR NOT NULL,
+    status      VARCHAR NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    reviewer    VARCHAR NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    error       VARCHAR NOT NULL DEFAULT '',
+    CHECK (reviewer = '' OR status = 'rejected' OR reviewer <> proposer)
+);
+
+CREATE INDEX IF NOT EXISTS config_change_status_idx ON sync.config_change (status);
+
+COMMENT ON TABLE sync.config_change IS 'The changes of the runtime settings of the two-person rule of the admins';
+COMMENT ON COLUMN sync.config_change.request IS 'The json request of the admin endpoint applying the change';
+COMMENT ON COLUMN sync.config_change.status IS 'pending, approved, applied, failed or rejected';
@@ -0,0 +1,44 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the changes of the runtime settings approved by a second admin.
+
+type migrationTest0031 struct{}
+
+const migrationTest0031Tables = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename = 'config_change'"
+
+func (m migrationTest0031) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0031) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0031Tables).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	const addChangeSQL = `INSERT INTO sync.config_change (method, request, reason, proposer, status, created_at, expires_at, reviewer)
+		VALUES ('SetProofAccess', '{}', '', 'alice', $1, NOW(), NOW(), $2)`
+	_, err := db.Exec(addChangeSQL, "pending", "")
+	assert.NoError(t, err)
+	_, err = db.Exec(addChangeSQL, "approved", "bob")
+	assert.NoError(t, err)
+	// The proposer can't approve its own change
+	_, err = db.Exec(addChangeSQL, "approved", "alice")
+	assert.Error(t, err)
+}
+
+func (m migrationTest0031) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0031Tables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0031(t *testing.T) {
+	runMigrationTest(t, 31, migrationTest0031{})
+}
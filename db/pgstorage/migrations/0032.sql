@@ -0,0 +1,18 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.signing_key;
+
+-- +migrate Up
+-- The rotations of the operator keys signing the responses of the API
+CREATE TABLE IF NOT EXISTS sync.signing_key
+(
+    id           BIGSERIAL PRIMARY KEY,
+    address      BYTEA NOT NULL,
+    activated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    retired_at   TIMESTAMP WITH TIME ZONE
+);
+
+-- Only one key signs the responses at a time
+CREATE UNIQUE INDEX IF NOT EXISTS signing_key_active_idx ON sync.signing_key ((retired_at IS NULL)) WHERE retired_at IS NULL;
+
+COMMENT ON TABLE sync.signing_key IS 'The activations of the operator keys that signed the responses of the API';
+COMMENT ON COLUMN sync.signing_key.retired_at IS 'NULL for the key signing the responses';
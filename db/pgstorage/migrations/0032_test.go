@@ -0,0 +1,43 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the rotations of the operator keys signing the responses of the API.
+
+type migrationTest0032 struct{}
+
+const migrationTest0032Tables = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename = 'signing_key'"
+
+func (m migrationTest0032) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0032) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0032Tables).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	const addKeySQL = "INSERT INTO sync.signing_key (address, activated_at, retired_at) VALUES ($1, NOW(), $2)"
+	_, err := db.Exec(addKeySQL, []byte{1}, "2024-01-01T00:00:00Z")
+	assert.NoError(t, err)
+	_, err = db.Exec(addKeySQL, []byte{2}, nil)
+	assert.NoError(t, err)
+	// A key is activated again after it's retired, but only one key is active
+	_, err = db.Exec(addKeySQL, []byte{1}, nil)
+	assert.Error(t, err)
+}
+
+func (m migrationTest0032) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0032Tables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0032(t *testing.T) {
+	runMigrationTest(t, 32, migrationTest0032{})
+}
@@ -0,0 +1,23 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.watched_address;
+
+-- +migrate Up
+-- The addresses watched by the wallets, whose responses are kept hot in the cache and whose history is backfilled
+-- on demand
+CREATE TABLE IF NOT EXISTS sync.watched_address
+(
+    owner                 BYTEA NOT NULL,
+    address               BYTEA NOT NULL,
+    created_at            TIMESTAMP WITH TIME ZONE NOT NULL,
+    backfill_requested_at TIMESTAMP WITH TIME ZONE,
+    backfilled_at         TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (owner, address)
+);
+
+-- The backfills not done yet, read by the backfiller
+CREATE INDEX IF NOT EXISTS watched_address_backfill_idx ON sync.watched_address (backfill_requested_at) WHERE backfilled_at IS NULL;
+
+COMMENT ON TABLE sync.watched_address IS 'The addresses watched by the wallets, kept hot in the cache and backfilled on demand';
+COMMENT ON COLUMN sync.watched_address.owner IS 'Address of the wallet watching the address';
+COMMENT ON COLUMN sync.watched_address.backfill_requested_at IS 'Time of the last backfill requested, NULL if it was never requested';
+COMMENT ON COLUMN sync.watched_address.backfilled_at IS 'Time the last requested backfill was done, NULL while it is pending';
@@ -0,0 +1,43 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the addresses watched by the wallets.
+
+type migrationTest0033 struct{}
+
+const migrationTest0033Tables = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename = 'watched_address'"
+
+func (m migrationTest0033) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0033) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0033Tables).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	const addWatchSQL = "INSERT INTO sync.watched_address (owner, address, created_at) VALUES ($1, $2, NOW())"
+	_, err := db.Exec(addWatchSQL, []byte{1}, []byte{2})
+	assert.NoError(t, err)
+	_, err = db.Exec(addWatchSQL, []byte{3}, []byte{2})
+	assert.NoError(t, err)
+	// A wallet watches an address once
+	_, err = db.Exec(addWatchSQL, []byte{1}, []byte{2})
+	assert.Error(t, err)
+}
+
+func (m migrationTest0033) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0033Tables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0033(t *testing.T) {
+	runMigrationTest(t, 33, migrationTest0033{})
+}
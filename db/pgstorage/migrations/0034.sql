@@ -0,0 +1,31 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.bus_offset;
+DROP TABLE IF EXISTS sync.bus_event;
+
+-- +migrate Up
+-- The events of the synced data published on the event bus, stored with the data so the consumers replay them
+CREATE TABLE IF NOT EXISTS sync.bus_event
+(
+    id         BIGSERIAL PRIMARY KEY,
+    type       VARCHAR NOT NULL,
+    network_id INTEGER NOT NULL,
+    payload    JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- The events older than the retention are pruned by their creation time
+CREATE INDEX IF NOT EXISTS bus_event_created_at_idx ON sync.bus_event (created_at);
+
+-- The position of each consumer of the event bus
+CREATE TABLE IF NOT EXISTS sync.bus_offset
+(
+    consumer   VARCHAR PRIMARY KEY,
+    event_id   BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE sync.bus_event IS 'The events of the synced data published on the event bus';
+COMMENT ON COLUMN sync.bus_event.type IS 'deposit_indexed, ger_updated, claim_confirmed or reorg';
+COMMENT ON COLUMN sync.bus_event.payload IS 'The synced data of the event as JSON';
+COMMENT ON TABLE sync.bus_offset IS 'The position of each consumer of the event bus';
+COMMENT ON COLUMN sync.bus_offset.event_id IS 'Id of the last event handled by the consumer';
@@ -0,0 +1,44 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the events and the offsets of the consumers of the event bus.
+
+type migrationTest0034 struct{}
+
+const migrationTest0034Tables = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename IN ('bus_event', 'bus_offset')"
+
+func (m migrationTest0034) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0034) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0034Tables).Scan(&count))
+	assert.Equal(t, 2, count)
+
+	const addEventSQL = "INSERT INTO sync.bus_event (type, network_id, payload, created_at) VALUES ('deposit_indexed', 0, '{}', NOW()) RETURNING id"
+	var id uint64
+	assert.NoError(t, db.QueryRow(addEventSQL).Scan(&id))
+	const setOffsetSQL = "INSERT INTO sync.bus_offset (consumer, event_id, updated_at) VALUES ('webhook', $1, NOW())"
+	_, err := db.Exec(setOffsetSQL, id)
+	assert.NoError(t, err)
+	// A consumer has one offset
+	_, err = db.Exec(setOffsetSQL, id)
+	assert.Error(t, err)
+}
+
+func (m migrationTest0034) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0034Tables).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0034(t *testing.T) {
+	runMigrationTest(t, 34, migrationTest0034{})
+}
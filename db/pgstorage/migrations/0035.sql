@@ -0,0 +1,18 @@
+-- +migrate Down
+ALTER TABLE sync.deposit DROP CONSTRAINT IF EXISTS deposit_amount_uint256;
+ALTER TABLE sync.claim DROP CONSTRAINT IF EXISTS claim_amount_uint256;
+COMMENT ON COLUMN sync.activity_bucket.volume IS NULL;
+-- The type of the volume created by 0010
+ALTER TABLE sync.activity_bucket ALTER COLUMN volume TYPE NUMERIC(78, 0);
+
+-- +migrate Up
+-- The sum of the amounts of a token in an hour can overflow the 78 digits of a uint256
+ALTER TABLE sync.activity_bucket ALTER COLUMN volume TYPE NUMERIC;
+
+-- The amounts are the decimal strings of uint256 values. The rows already synced are not checked
+ALTER TABLE sync.deposit ADD CONSTRAINT deposit_amount_uint256 CHECK (CASE WHEN amount ~ '^[0-9]{1,78}$'
+    THEN amount::NUMERIC <= 115792089237316195423570985008687907853269984665640564039457584007913129639935 ELSE FALSE END) NOT VALID;
+ALTER TABLE sync.claim ADD CONSTRAINT claim_amount_uint256 CHECK (CASE WHEN amount ~ '^[0-9]{1,78}$'
+    THEN amount::NUMERIC <= 115792089237316195423570985008687907853269984665640564039457584007913129639935 ELSE FALSE END) NOT VALID;
+
+COMMENT ON COLUMN sync.activity_bucket.volume IS 'Sum of the amounts of the deposits or claims of the bucket, in the smallest unit of the token';
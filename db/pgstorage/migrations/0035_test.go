@@ -0,0 +1,63 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration checks that the amounts of the deposits and the claims are uint256 values, and lets the volumes
+// of the activity buckets overflow them.
+
+type migrationTest0035 struct{}
+
+const (
+	migrationTest0035Block   = "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(35, 35, decode('0000000000000000000000000000000000000000000000000000000000000035','hex'), decode('0000000000000000000000000000000000000000000000000000000000000034','hex'), 0, '2023-01-01 10:30:00.000+00')"
+	migrationTest0035Deposit = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata) VALUES (0, 0, 0, decode('0000000000000000000000000000000000000000','hex'), $1, 1, decode('F39FD6E51AAD88F6F4CE6AB8827279CFFFB92266','hex'), 35, $2, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B9C5A1E2FE9E3A428A7DC','hex'), decode('','hex'))"
+	migrationTest0035Max     = "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+)
+
+func (m migrationTest0035) InsertData(db *sql.DB) error {
+	if _, err := db.Exec(migrationTest0035Block); err != nil {
+		return err
+	}
+	// The amounts synced before the migration are not checked
+	_, err := db.Exec(migrationTest0035Deposit, "<nil>", 3500)
+	return err
+}
+
+func (m migrationTest0035) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(migrationTest0035Deposit, migrationTest0035Max, 3501)
+	assert.NoError(t, err)
+	for i, amount := range []string{"115792089237316195423570985008687907853269984665640564039457584007913129639936", "-1", "1.5", "<nil>"} {
+		_, err = db.Exec(migrationTest0035Deposit, amount, 3502+i)
+		assert.Error(t, err, amount)
+	}
+
+	const addVolumeSQL = "INSERT INTO sync.activity_bucket (network_id, kind, orig_net, orig_addr, bucket, count, volume) VALUES (0, 'deposit', 0, decode('00','hex'), '2023-01-01 10:00', 1, $1::NUMERIC * 10)"
+	_, err = db.Exec(addVolumeSQL, migrationTest0035Max)
+	assert.NoError(t, err)
+	var volume string
+	assert.NoError(t, db.QueryRow("SELECT volume::VARCHAR FROM sync.activity_bucket WHERE orig_addr = decode('00','hex')").Scan(&volume))
+	assert.Equal(t, migrationTest0035Max+"0", volume)
+	_, err = db.Exec("DELETE FROM sync.activity_bucket WHERE orig_addr = decode('00','hex')")
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0035) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(migrationTest0035Deposit, "1.5", 3510)
+	assert.NoError(t, err)
+	// The volume has the type created by 0010 again
+	var (
+		columnType string
+		notNull    bool
+	)
+	assert.NoError(t, db.QueryRow("SELECT format_type(atttypid, atttypmod), attnotnull FROM pg_attribute WHERE attrelid = 'sync.activity_bucket'::regclass AND attname = 'volume'").Scan(&columnType, &notNull))
+	assert.Equal(t, "numeric(78,0)", columnType)
+	assert.True(t, notNull)
+}
+
+func TestMigration0035(t *testing.T) {
+	runMigrationTest(t, 35, migrationTest0035{})
+}
@@ -0,0 +1,24 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.claim_outcome;
+
+-- +migrate Up
+-- The outcome of the mined claims, decoded from the logs of their receipts
+CREATE TABLE IF NOT EXISTS sync.claim_outcome
+(
+    deposit_id BIGINT PRIMARY KEY REFERENCES sync.monitored_txs (deposit_id) ON DELETE CASCADE,
+    tx_hash    BYTEA NOT NULL,
+    block_num  BIGINT NOT NULL,
+    orig_net   INTEGER NOT NULL,
+    orig_addr  BYTEA NOT NULL,
+    recipient  BYTEA NOT NULL,
+    amount     NUMERIC NOT NULL,
+    token      BYTEA NOT NULL,
+    minted     BOOLEAN NOT NULL,
+    delivered  NUMERIC,
+    decoded_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE sync.claim_outcome IS 'The outcome of the mined claims, decoded from the logs of their receipts';
+COMMENT ON COLUMN sync.claim_outcome.recipient IS 'Destination address of the ClaimEvent';
+COMMENT ON COLUMN sync.claim_outcome.token IS 'Token transferred or minted to the recipient, zero for the ether and the messages';
+COMMENT ON COLUMN sync.claim_outcome.delivered IS 'Sum of the transfers of the token to the recipient, NULL for the ether and the messages';
@@ -0,0 +1,47 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the outcome of the mined claims, decoded from their receipts.
+
+type migrationTest0036 struct{}
+
+const (
+	migrationTest0036ClaimTx = "INSERT INTO sync.monitored_txs (deposit_id, from_addr, to_addr, nonce, value, data, gas, status, history, created_at, updated_at) VALUES (3600, decode('00','hex'), decode('00','hex'), 1, '0', decode('00','hex'), 1, 'confirmed', NULL, NOW(), NOW())"
+	migrationTest0036Outcome = "INSERT INTO sync.claim_outcome (deposit_id, tx_hash, block_num, orig_net, orig_addr, recipient, amount, token, minted, delivered, decoded_at) VALUES ($1, decode('00','hex'), 1, 0, decode('00','hex'), decode('00','hex'), 100, decode('00','hex'), false, 98, NOW())"
+	migrationTest0036Table   = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename = 'claim_outcome'"
+)
+
+func (m migrationTest0036) InsertData(db *sql.DB) error {
+	_, err := db.Exec(migrationTest0036ClaimTx)
+	return err
+}
+
+func (m migrationTest0036) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(migrationTest0036Outcome, 3600)
+	assert.NoError(t, err)
+	// An outcome is stored for a monitored claim tx only
+	_, err = db.Exec(migrationTest0036Outcome, 3601)
+	assert.Error(t, err)
+
+	_, err = db.Exec("DELETE FROM sync.monitored_txs WHERE deposit_id = 3600")
+	assert.NoError(t, err)
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.claim_outcome WHERE deposit_id = 3600").Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func (m migrationTest0036) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0036Table).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0036(t *testing.T) {
+	runMigrationTest(t, 36, migrationTest0036{})
+}
@@ -0,0 +1,75 @@
+-- +migrate Down
+DROP INDEX IF EXISTS sync.deposit_sender_idx;
+DROP INDEX IF EXISTS sync.deposit_depositor_idx;
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync.notify_row_change() RETURNS TRIGGER AS $$
+DECLARE
+    r RECORD;
+    payload JSON;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        r := OLD;
+    ELSE
+        r := NEW;
+    END IF;
+    IF TG_TABLE_NAME = 'deposit' THEN
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.deposit_cnt, 'dest_net', r.dest_net, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'));
+    ELSIF TG_TABLE_NAME = 'claim' THEN
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.index, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'));
+    ELSE
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'orig_net', r.orig_net, 'orig_addr', '0x' || encode(r.orig_token_addr, 'hex'));
+    END IF;
+    PERFORM pg_notify('bridge_changes', payload::TEXT);
+    RETURN r;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+ALTER TABLE sync.deposit DROP COLUMN IF EXISTS sender;
+ALTER TABLE sync.deposit DROP COLUMN IF EXISTS depositor;
+
+-- +migrate Up
+-- The msg.sender of the bridge call and the sender of the tx of each deposit, so the deposits made by contracts on
+-- behalf of the users can be queried by either. The deposits synced before the migration or without the senders
+-- recorded have them NULL
+ALTER TABLE sync.deposit ADD COLUMN IF NOT EXISTS sender BYTEA;
+ALTER TABLE sync.deposit ADD COLUMN IF NOT EXISTS depositor BYTEA;
+CREATE INDEX IF NOT EXISTS deposit_sender_idx ON sync.deposit(sender);
+CREATE INDEX IF NOT EXISTS deposit_depositor_idx ON sync.deposit(depositor);
+
+-- The changes of the deposits are notified with their senders, so the API replicas invalidate their queries too
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync.notify_row_change() RETURNS TRIGGER AS $$
+DECLARE
+    r RECORD;
+    payload JSON;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        r := OLD;
+    ELSE
+        r := NEW;
+    END IF;
+    IF TG_TABLE_NAME = 'deposit' THEN
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.deposit_cnt, 'dest_net', r.dest_net, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'),
+            'sender', '0x' || encode(COALESCE(r.sender, decode(repeat('00', 20), 'hex')), 'hex'),
+            'depositor', '0x' || encode(COALESCE(r.depositor, decode(repeat('00', 20), 'hex')), 'hex'));
+    ELSIF TG_TABLE_NAME = 'claim' THEN
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.index, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'));
+    ELSE
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'orig_net', r.orig_net, 'orig_addr', '0x' || encode(r.orig_token_addr, 'hex'));
+    END IF;
+    PERFORM pg_notify('bridge_changes', payload::TEXT);
+    RETURN r;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+COMMENT ON COLUMN sync.deposit.sender IS 'msg.sender of the bridge call, NULL if it was not recorded';
+COMMENT ON COLUMN sync.deposit.depositor IS 'Sender of the tx of the deposit, NULL if it was not recorded';
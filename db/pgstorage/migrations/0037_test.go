@@ -0,0 +1,51 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the msg.sender of the bridge call and the sender of the tx of the deposits, and notifies the
+// changes of the deposits with them.
+
+type migrationTest0037 struct{}
+
+const (
+	migrationTest0037Block   = "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(37, 37, decode('0000000000000000000000000000000000000000000000000000000000000037','hex'), decode('0000000000000000000000000000000000000000000000000000000000000036','hex'), 0, '2023-01-01 10:30:00.000+00')"
+	migrationTest0037Deposit = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata) VALUES (0, 0, 0, decode('0000000000000000000000000000000000000000','hex'), '1', 1, decode('F39FD6E51AAD88F6F4CE6AB8827279CFFFB92266','hex'), 37, 3700, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B9C5A1E2FE9E3A428A7DC','hex'), decode('','hex'))"
+	migrationTest0037Columns = "SELECT count(*) FROM information_schema.columns WHERE table_schema = 'sync' AND table_name = 'deposit' AND column_name IN ('sender', 'depositor')"
+)
+
+func (m migrationTest0037) InsertData(db *sql.DB) error {
+	if _, err := db.Exec(migrationTest0037Block); err != nil {
+		return err
+	}
+	_, err := db.Exec(migrationTest0037Deposit)
+	return err
+}
+
+func (m migrationTest0037) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0037Columns).Scan(&count))
+	assert.Equal(t, 2, count)
+	// The deposits synced before the migration have no senders
+	var sender []byte
+	assert.NoError(t, db.QueryRow("SELECT sender FROM sync.deposit WHERE deposit_cnt = 3700").Scan(&sender))
+	assert.Nil(t, sender)
+	_, err := db.Exec("UPDATE sync.deposit SET sender = decode('2222222222222222222222222222222222222222','hex'), depositor = decode('1111111111111111111111111111111111111111','hex') WHERE deposit_cnt = 3700")
+	assert.NoError(t, err)
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.deposit WHERE depositor = decode('1111111111111111111111111111111111111111','hex')").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func (m migrationTest0037) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0037Columns).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0037(t *testing.T) {
+	runMigrationTest(t, 37, migrationTest0037{})
+}
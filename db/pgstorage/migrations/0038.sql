@@ -0,0 +1,17 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.maintenance;
+
+-- +migrate Up
+-- A single row, so every instance of the service enters and leaves the maintenance at the same time
+CREATE TABLE IF NOT EXISTS sync.maintenance
+(
+    id         BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    enabled    BOOLEAN NOT NULL,
+    message    VARCHAR NOT NULL,
+    eta        TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE sync.maintenance IS 'The maintenance set by the operators, pausing the writers until its ETA';
+COMMENT ON COLUMN sync.maintenance.message IS 'Notice shown by the frontends during the maintenance';
+COMMENT ON COLUMN sync.maintenance.eta IS 'Expected end of the maintenance, it ends automatically then';
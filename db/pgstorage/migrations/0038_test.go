@@ -0,0 +1,41 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the maintenance set by the operators.
+
+type migrationTest0038 struct{}
+
+const migrationTest0038Table = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename = 'maintenance'"
+
+func (m migrationTest0038) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0038) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0038Table).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// The maintenance has a single row
+	const setMaintenanceSQL = "INSERT INTO sync.maintenance (id, enabled, message, eta, updated_at) VALUES ($1, true, 'upgrade', NOW() + INTERVAL '1 hour', NOW())"
+	_, err := db.Exec(setMaintenanceSQL, true)
+	assert.NoError(t, err)
+	_, err = db.Exec(setMaintenanceSQL, false)
+	assert.Error(t, err)
+}
+
+func (m migrationTest0038) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0038Table).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0038(t *testing.T) {
+	runMigrationTest(t, 38, migrationTest0038{})
+}
@@ -0,0 +1,7 @@
+-- +migrate Down
+ALTER TABLE sync.claim DROP COLUMN IF EXISTS message_call;
+
+-- +migrate Up
+ALTER TABLE sync.claim ADD COLUMN IF NOT EXISTS message_call VARCHAR;
+
+COMMENT ON COLUMN sync.claim.message_call IS 'Result of the call to the destination of a claimed message: succeeded, reverted or no_code. NULL for the assets and the claims not traced';
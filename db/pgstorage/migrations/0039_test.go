@@ -0,0 +1,48 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the result of the call to the destination of the claimed messages.
+
+type migrationTest0039 struct{}
+
+const (
+	migrationTest0039Block  = "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(39, 39, decode('0000000000000000000000000000000000000000000000000000000000000039','hex'), decode('0000000000000000000000000000000000000000000000000000000000000038','hex'), 1, '2023-01-01 10:30:00.000+00')"
+	migrationTest0039Claim  = "INSERT INTO sync.claim (network_id, index, orig_net, orig_addr, amount, dest_addr, block_id, tx_hash) VALUES (1, 3900, 0, decode('0000000000000000000000000000000000000000','hex'), '1', decode('F39FD6E51AAD88F6F4CE6AB8827279CFFFB92266','hex'), 39, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B9C5A1E2FE9E3A428A7DC','hex'))"
+	migrationTest0039Column = "SELECT count(*) FROM information_schema.columns WHERE table_schema = 'sync' AND table_name = 'claim' AND column_name = 'message_call'"
+)
+
+func (m migrationTest0039) InsertData(db *sql.DB) error {
+	if _, err := db.Exec(migrationTest0039Block); err != nil {
+		return err
+	}
+	_, err := db.Exec(migrationTest0039Claim)
+	return err
+}
+
+func (m migrationTest0039) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0039Column).Scan(&count))
+	assert.Equal(t, 1, count)
+	// The claims synced before the migration were not traced
+	var messageCall sql.NullString
+	assert.NoError(t, db.QueryRow("SELECT message_call FROM sync.claim WHERE index = 3900").Scan(&messageCall))
+	assert.False(t, messageCall.Valid)
+	_, err := db.Exec("UPDATE sync.claim SET message_call = 'reverted' WHERE index = 3900")
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0039) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0039Column).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0039(t *testing.T) {
+	runMigrationTest(t, 39, migrationTest0039{})
+}
@@ -0,0 +1,25 @@
+-- +migrate Down
+DROP TABLE IF EXISTS sync.deposit_activity;
+
+-- +migrate Up
+-- The log lines and the claim attempts of each deposit, the changes of status are in sync.deposit_status_history
+CREATE TABLE IF NOT EXISTS sync.deposit_activity
+(
+    id          BIGSERIAL PRIMARY KEY,
+    network_id  INTEGER NOT NULL,
+    deposit_cnt BIGINT NOT NULL,
+    kind        VARCHAR NOT NULL,
+    level       VARCHAR NOT NULL,
+    message     VARCHAR NOT NULL,
+    fields      JSONB NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS deposit_activity_deposit_idx ON sync.deposit_activity (network_id, deposit_cnt, id);
+-- The entries older than the retention are pruned by their creation time
+CREATE INDEX IF NOT EXISTS deposit_activity_created_at_idx ON sync.deposit_activity (created_at);
+
+COMMENT ON TABLE sync.deposit_activity IS 'The log lines and the claim attempts of each deposit, for the support tools';
+COMMENT ON COLUMN sync.deposit_activity.kind IS 'log or claim_attempt';
+COMMENT ON COLUMN sync.deposit_activity.level IS 'debug, info, warn or error';
+COMMENT ON COLUMN sync.deposit_activity.fields IS 'The structured fields of the entry, e.g. the hash of the claim tx';
@@ -0,0 +1,42 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the activity log of the deposits.
+
+type migrationTest0040 struct{}
+
+const migrationTest0040Table = "SELECT count(*) FROM pg_tables WHERE schemaname = 'sync' AND tablename = 'deposit_activity'"
+
+func (m migrationTest0040) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0040) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0040Table).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	const addActivitySQL = `INSERT INTO sync.deposit_activity (network_id, deposit_cnt, kind, level, message, fields, created_at)
+		VALUES (0, 40, 'log', 'warn', 'error getting the claim proof', '{"error": "not found"}', NOW())`
+	_, err := db.Exec(addActivitySQL)
+	assert.NoError(t, err)
+	var message string
+	assert.NoError(t, db.QueryRow("SELECT message FROM sync.deposit_activity WHERE network_id = 0 AND deposit_cnt = 40").Scan(&message))
+	assert.Equal(t, "error getting the claim proof", message)
+}
+
+func (m migrationTest0040) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0040Table).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0040(t *testing.T) {
+	runMigrationTest(t, 40, migrationTest0040{})
+}
@@ -0,0 +1,5 @@
+-- +migrate Down
+COMMENT ON COLUMN sync.monitored_txs.status IS 'created, failed, confirmed, pending_approval, uneconomical, approved, pending_signature or signed';
+
+-- +migrate Up
+COMMENT ON COLUMN sync.monitored_txs.status IS 'created, failed, confirmed, pending_approval, uneconomical, message_reverted, approved, pending_signature, signed or already_claimed';
@@ -0,0 +1,34 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration documents the message_reverted and already_claimed statuses of the claim txs.
+
+type migrationTest0041 struct{}
+
+const migrationTest0041Comment = "SELECT col_description('sync.monitored_txs'::regclass, (SELECT attnum FROM pg_attribute WHERE attrelid = 'sync.monitored_txs'::regclass AND attname = 'status'))"
+
+func (m migrationTest0041) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0041) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var comment string
+	assert.NoError(t, db.QueryRow(migrationTest0041Comment).Scan(&comment))
+	assert.Contains(t, comment, "message_reverted")
+}
+
+func (m migrationTest0041) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var comment string
+	assert.NoError(t, db.QueryRow(migrationTest0041Comment).Scan(&comment))
+	assert.NotContains(t, comment, "message_reverted")
+}
+
+func TestMigration0041(t *testing.T) {
+	runMigrationTest(t, 41, migrationTest0041{})
+}
@@ -0,0 +1,14 @@
+-- +migrate Down
+ALTER TABLE sync.token_wrapped_history DROP COLUMN IF EXISTS name;
+ALTER TABLE sync.token_wrapped_history DROP COLUMN IF EXISTS symbol;
+ALTER TABLE sync.token_wrapped_history DROP COLUMN IF EXISTS decimals;
+
+-- +migrate Up
+ALTER TABLE sync.token_wrapped_history ADD COLUMN IF NOT EXISTS name VARCHAR;
+ALTER TABLE sync.token_wrapped_history ADD COLUMN IF NOT EXISTS symbol VARCHAR;
+ALTER TABLE sync.token_wrapped_history ADD COLUMN IF NOT EXISTS decimals INTEGER;
+
+-- The metadata of the current mappings is kept in the history
+UPDATE sync.token_wrapped_history AS h SET name = t.name, symbol = t.symbol, decimals = t.decimals
+FROM sync.token_wrapped AS t
+WHERE h.network_id = t.network_id AND h.orig_net = t.orig_net AND h.orig_token_addr = t.orig_token_addr AND h.wrapped_token_addr = t.wrapped_token_addr;
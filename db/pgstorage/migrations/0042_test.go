@@ -0,0 +1,34 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration keeps the metadata of the wrapped tokens in their mapping history.
+
+type migrationTest0042 struct{}
+
+const migrationTest0042Columns = "SELECT count(*) FROM information_schema.columns WHERE table_schema = 'sync' AND table_name = 'token_wrapped_history' AND column_name IN ('name', 'symbol', 'decimals')"
+
+func (m migrationTest0042) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0042) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0042Columns).Scan(&count))
+	assert.Equal(t, 3, count)
+}
+
+func (m migrationTest0042) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0042Columns).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0042(t *testing.T) {
+	runMigrationTest(t, 42, migrationTest0042{})
+}
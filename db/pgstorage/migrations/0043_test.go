@@ -0,0 +1,33 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration documents the denied status of the claim txs.
+
+type migrationTest0043 struct{}
+
+func (m migrationTest0043) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0043) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var comment string
+	assert.NoError(t, db.QueryRow(migrationTest0041Comment).Scan(&comment))
+	assert.Contains(t, comment, "denied")
+}
+
+func (m migrationTest0043) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var comment string
+	assert.NoError(t, db.QueryRow(migrationTest0041Comment).Scan(&comment))
+	assert.NotContains(t, comment, "denied")
+	assert.Contains(t, comment, "already_claimed")
+}
+
+func TestMigration0043(t *testing.T) {
+	runMigrationTest(t, 43, migrationTest0043{})
+}
@@ -0,0 +1,11 @@
+-- +migrate Down
+DROP INDEX IF EXISTS sync.deposit_dest_net_token_idx;
+DROP INDEX IF EXISTS sync.claim_network_token_idx;
+DROP INDEX IF EXISTS sync.block_received_at_idx;
+
+-- +migrate Up
+-- The amounts deposited and claimed of each token are summed in the order of their group, from the indexes
+CREATE INDEX IF NOT EXISTS deposit_dest_net_token_idx ON sync.deposit (dest_net, orig_net, orig_addr) INCLUDE (amount);
+CREATE INDEX IF NOT EXISTS claim_network_token_idx ON sync.claim (network_id, orig_net, orig_addr) INCLUDE (amount, block_id);
+-- The claims are reconciled until the time their blocks were received
+CREATE INDEX IF NOT EXISTS block_received_at_idx ON sync.block (received_at);
@@ -0,0 +1,40 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the indexes of the reconciliation of the token amounts.
+
+type migrationTest0044 struct{}
+
+var migrationTest0044Indexes = []string{"deposit_dest_net_token_idx", "claim_network_token_idx", "block_received_at_idx"}
+
+func (m migrationTest0044) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0044) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	for _, idx := range migrationTest0044Indexes {
+		const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+		var result int
+		assert.NoError(t, db.QueryRow(getIndex, idx).Scan(&result))
+		assert.Equal(t, 1, result)
+	}
+}
+
+func (m migrationTest0044) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	for _, idx := range migrationTest0044Indexes {
+		const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+		var result int
+		assert.NoError(t, db.QueryRow(getIndex, idx).Scan(&result))
+		assert.Equal(t, 0, result)
+	}
+}
+
+func TestMigration0044(t *testing.T) {
+	runMigrationTest(t, 44, migrationTest0044{})
+}
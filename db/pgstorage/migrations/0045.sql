@@ -0,0 +1,68 @@
+-- +migrate Down
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync.notify_row_change() RETURNS TRIGGER AS $$
+DECLARE
+    r RECORD;
+    payload JSON;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        r := OLD;
+    ELSE
+        r := NEW;
+    END IF;
+    IF TG_TABLE_NAME = 'deposit' THEN
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.deposit_cnt, 'dest_net', r.dest_net, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'),
+            'sender', '0x' || encode(COALESCE(r.sender, decode(repeat('00', 20), 'hex')), 'hex'),
+            'depositor', '0x' || encode(COALESCE(r.depositor, decode(repeat('00', 20), 'hex')), 'hex'));
+    ELSIF TG_TABLE_NAME = 'claim' THEN
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.index, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'));
+    ELSE
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'orig_net', r.orig_net, 'orig_addr', '0x' || encode(r.orig_token_addr, 'hex'));
+    END IF;
+    PERFORM pg_notify('bridge_changes', payload::TEXT);
+    RETURN r;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate Up
+-- The changes of the deposits are notified with the transition of their ready_for_claim, so the other updates of a
+-- deposit ready for claim don't notify it ready again
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync.notify_row_change() RETURNS TRIGGER AS $$
+DECLARE
+    r RECORD;
+    payload JSON;
+    ready BOOLEAN := FALSE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        r := OLD;
+    ELSE
+        r := NEW;
+    END IF;
+    IF TG_TABLE_NAME = 'deposit' THEN
+        IF TG_OP = 'INSERT' THEN
+            ready := NEW.ready_for_claim;
+        ELSIF TG_OP = 'UPDATE' THEN
+            ready := NEW.ready_for_claim AND NOT OLD.ready_for_claim;
+        END IF;
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.deposit_cnt, 'dest_net', r.dest_net, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'),
+            'sender', '0x' || encode(COALESCE(r.sender, decode(repeat('00', 20), 'hex')), 'hex'),
+            'depositor', '0x' || encode(COALESCE(r.depositor, decode(repeat('00', 20), 'hex')), 'hex'),
+            'ready', ready);
+    ELSIF TG_TABLE_NAME = 'claim' THEN
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'deposit_cnt', r.index, 'dest_addr', '0x' || encode(r.dest_addr, 'hex'));
+    ELSE
+        payload := json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'network_id', r.network_id,
+            'orig_net', r.orig_net, 'orig_addr', '0x' || encode(r.orig_token_addr, 'hex'));
+    END IF;
+    PERFORM pg_notify('bridge_changes', payload::TEXT);
+    RETURN r;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
@@ -0,0 +1,76 @@
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/stdlib"
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration notifies the changes of the deposits with the transition of their ready_for_claim.
+
+type migrationTest0045 struct{}
+
+const (
+	migrationTest0045Block   = "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(45, 45, decode('0000000000000000000000000000000000000000000000000000000000000045','hex'), decode('0000000000000000000000000000000000000000000000000000000000000044','hex'), 0, '2023-01-01 10:30:00.000+00')"
+	migrationTest0045Deposit = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata) VALUES (0, 0, 0, decode('0000000000000000000000000000000000000000','hex'), '1', 1, decode('F39FD6E51AAD88F6F4CE6AB8827279CFFFB92266','hex'), 45, 4500, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B9C5A1E2FE9E3A428A7DC','hex'), decode('','hex'))"
+)
+
+func (m migrationTest0045) InsertData(db *sql.DB) error {
+	_, err := db.Exec(migrationTest0045Block)
+	return err
+}
+
+func (m migrationTest0045) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	assert.NoError(t, err)
+	defer conn.Close() //nolint:errcheck
+	_, err = conn.ExecContext(ctx, "LISTEN bridge_changes")
+	assert.NoError(t, err)
+	_, err = db.Exec("ALTER TABLE sync.deposit ENABLE TRIGGER deposit_notify;")
+	assert.NoError(t, err)
+	ready := func() bool {
+		var payload struct {
+			Ready bool `json:"ready"`
+		}
+		err := conn.Raw(func(driverConn interface{}) error {
+			waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			n, err := driverConn.(*stdlib.Conn).Conn().WaitForNotification(waitCtx)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal([]byte(n.Payload), &payload)
+		})
+		assert.NoError(t, err)
+		return payload.Ready
+	}
+
+	// Only the update making the deposit ready for claim notifies it ready
+	_, err = db.Exec(migrationTest0045Deposit)
+	assert.NoError(t, err)
+	assert.False(t, ready())
+	_, err = db.Exec("UPDATE sync.deposit SET ready_for_claim = true WHERE deposit_cnt = 4500")
+	assert.NoError(t, err)
+	assert.True(t, ready())
+	_, err = db.Exec("UPDATE sync.deposit SET sender = decode('2222222222222222222222222222222222222222','hex') WHERE deposit_cnt = 4500")
+	assert.NoError(t, err)
+	assert.False(t, ready())
+	_, err = db.Exec("ALTER TABLE sync.deposit DISABLE TRIGGER deposit_notify;")
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0045) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var definition string
+	assert.NoError(t, db.QueryRow("SELECT prosrc FROM pg_proc WHERE proname = 'notify_row_change'").Scan(&definition))
+	assert.NotContains(t, definition, "ready")
+}
+
+func TestMigration0045(t *testing.T) {
+	runMigrationTest(t, 45, migrationTest0045{})
+}
@@ -0,0 +1,31 @@
+-- +migrate Down
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync.capture_row_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO sync.outbox (table_name, operation, row_data) VALUES (TG_TABLE_NAME, TG_OP, to_jsonb(OLD));
+        RETURN OLD;
+    END IF;
+    INSERT INTO sync.outbox (table_name, operation, row_data) VALUES (TG_TABLE_NAME, TG_OP, to_jsonb(NEW));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate Up
+-- The ids are handed out when the changes are written and the consumers move past the last id they read, so the tx
+-- holds the lock of the outbox until it ends: the changes are committed in the order of their ids, and a consumer
+-- never skips a change committed after a higher id. The key is 'outbox' in hex
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION sync.capture_row_change() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(x'6f7574626f78'::BIGINT);
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO sync.outbox (table_name, operation, row_data) VALUES (TG_TABLE_NAME, TG_OP, to_jsonb(OLD));
+        RETURN OLD;
+    END IF;
+    INSERT INTO sync.outbox (table_name, operation, row_data) VALUES (TG_TABLE_NAME, TG_OP, to_jsonb(NEW));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
@@ -0,0 +1,56 @@
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration serializes the txs writing in the outbox, so its changes are committed in the order of their ids.
+
+type migrationTest0046 struct{}
+
+const (
+	migrationTest0046Block   = "INSERT INTO sync.block (id, block_num, block_hash, parent_hash, network_id, received_at) VALUES(46, 46, decode('0000000000000000000000000000000000000000000000000000000000000046','hex'), decode('0000000000000000000000000000000000000000000000000000000000000045','hex'), 0, '2023-01-01 10:30:00.000+00')"
+	migrationTest0046Deposit = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata) VALUES (0, 0, 0, decode('0000000000000000000000000000000000000000','hex'), '1', 1, decode('F39FD6E51AAD88F6F4CE6AB8827279CFFFB92266','hex'), 46, 4600, decode('C2D6575EA98EB55E36B5AC6E11196800362594458A4B9C5A1E2FE9E3A428A7DC','hex'), decode('','hex'))"
+)
+
+func (m migrationTest0046) InsertData(db *sql.DB) error {
+	_, err := db.Exec(migrationTest0046Block)
+	return err
+}
+
+func (m migrationTest0046) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	ctx := context.Background()
+	_, err := db.Exec("ALTER TABLE sync.deposit ENABLE TRIGGER deposit_outbox;")
+	assert.NoError(t, err)
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	_, err = tx.Exec(migrationTest0046Deposit)
+	assert.NoError(t, err)
+
+	// The lock of the outbox is held by the tx until it ends
+	var acquired bool
+	assert.NoError(t, db.QueryRow("SELECT pg_try_advisory_xact_lock(x'6f7574626f78'::BIGINT)").Scan(&acquired))
+	assert.False(t, acquired)
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, db.QueryRow("SELECT pg_try_advisory_xact_lock(x'6f7574626f78'::BIGINT)").Scan(&acquired))
+	assert.True(t, acquired)
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM sync.outbox WHERE (row_data->>'deposit_cnt')::INTEGER = 4600;").Scan(&count))
+	assert.Equal(t, 1, count)
+	_, err = db.Exec("ALTER TABLE sync.deposit DISABLE TRIGGER deposit_outbox;")
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0046) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var definition string
+	assert.NoError(t, db.QueryRow("SELECT prosrc FROM pg_proc WHERE proname = 'capture_row_change'").Scan(&definition))
+	assert.NotContains(t, definition, "pg_advisory_xact_lock")
+}
+
+func TestMigration0046(t *testing.T) {
+	runMigrationTest(t, 46, migrationTest0046{})
+}
@@ -0,0 +1,11 @@
+-- +migrate Down
+ALTER TABLE sync.claim_outcome DROP COLUMN IF EXISTS gas_used;
+ALTER TABLE sync.claim_outcome DROP COLUMN IF EXISTS effective_gas_price;
+
+-- +migrate Up
+-- The gas the mined claims spent, from their receipts. It's unknown for the claims mined before this migration
+ALTER TABLE sync.claim_outcome ADD COLUMN IF NOT EXISTS gas_used BIGINT;
+ALTER TABLE sync.claim_outcome ADD COLUMN IF NOT EXISTS effective_gas_price NUMERIC;
+
+COMMENT ON COLUMN sync.claim_outcome.gas_used IS 'Gas used by the claim tx, from its receipt';
+COMMENT ON COLUMN sync.claim_outcome.effective_gas_price IS 'Price paid for each unit of gas of the claim tx, from its receipt';
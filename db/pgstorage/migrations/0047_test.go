@@ -0,0 +1,48 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// This migration adds the gas the mined claims spent to their outcome.
+
+type migrationTest0047 struct{}
+
+const (
+	migrationTest0047ClaimTx = "INSERT INTO sync.monitored_txs (deposit_id, from_addr, to_addr, nonce, value, data, gas, status, history, created_at, updated_at) VALUES (4700, decode('00','hex'), decode('00','hex'), 1, '0', decode('00','hex'), 1, 'confirmed', NULL, NOW(), NOW())"
+	migrationTest0047Outcome = "INSERT INTO sync.claim_outcome (deposit_id, tx_hash, block_num, orig_net, orig_addr, recipient, amount, token, minted, delivered, decoded_at) VALUES (4700, decode('00','hex'), 1, 0, decode('00','hex'), decode('00','hex'), 100, decode('00','hex'), false, 100, NOW())"
+	migrationTest0047Columns = "SELECT count(*) FROM information_schema.columns WHERE table_schema = 'sync' AND table_name = 'claim_outcome' AND column_name IN ('gas_used', 'effective_gas_price')"
+)
+
+func (m migrationTest0047) InsertData(db *sql.DB) error {
+	if _, err := db.Exec(migrationTest0047ClaimTx); err != nil {
+		return err
+	}
+	_, err := db.Exec(migrationTest0047Outcome)
+	return err
+}
+
+func (m migrationTest0047) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0047Columns).Scan(&count))
+	assert.Equal(t, 2, count)
+	// The gas of the outcomes decoded before the migration is unknown
+	var gasUsed sql.NullInt64
+	assert.NoError(t, db.QueryRow("SELECT gas_used FROM sync.claim_outcome WHERE deposit_id = 4700").Scan(&gasUsed))
+	assert.False(t, gasUsed.Valid)
+	_, err := db.Exec("UPDATE sync.claim_outcome SET gas_used = 90000, effective_gas_price = 1000000000 WHERE deposit_id = 4700")
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0047) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	var count int
+	assert.NoError(t, db.QueryRow(migrationTest0047Columns).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigration0047(t *testing.T) {
+	runMigrationTest(t, 47, migrationTest0047{})
+}
@@ -9,14 +9,24 @@ import (
 
 	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/uint256"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/lib/pq"
 )
 
+const (
+	activityKindDeposit = "deposit"
+	activityKindClaim   = "claim"
+)
+
+// busEventLockKey is the key of the advisory lock serializing the txs that store events of the event bus
+const busEventLockKey int64 = 0x6275735f6576
+
 // PostgresStorage implements the Storage interface.
 type PostgresStorage struct {
 	*pgxpool.Pool
@@ -32,7 +42,12 @@ func (p *PostgresStorage) getExecQuerier(dbTx pgx.Tx) execQuerier {
 
 // NewPostgresStorage creates a new Storage DB
 func NewPostgresStorage(cfg Config) (*PostgresStorage, error) {
-	config, err := pgxpool.ParseConfig(fmt.Sprintf("postgres://%s:%s@%s:%s/%s?pool_max_conns=%d", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name, cfg.MaxConns))
+	connString := fmt.Sprintf("postgres://%s:%s@%s:%s/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
+	// Without max conns the pool has the default size of pgx
+	if cfg.MaxConns > 0 {
+		connString += fmt.Sprintf("?pool_max_conns=%d", cfg.MaxConns)
+	}
+	config, err := pgxpool.ParseConfig(connString)
 	if err != nil {
 		log.Errorf("Unable to parse DB config: %v\n", err)
 		return nil, err
@@ -67,6 +82,12 @@ func (p *PostgresStorage) BeginDBTransaction(ctx context.Context) (pgx.Tx, error
 	return p.Begin(ctx)
 }
 
+// BeginSnapshotTransaction starts a read only transaction whose queries read the same snapshot of the database,
+// the one of its first query, whatever is committed meanwhile.
+func (p *PostgresStorage) BeginSnapshotTransaction(ctx context.Context) (pgx.Tx, error) {
+	return p.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+}
+
 // GetLastBlock gets the last block.
 func (p *PostgresStorage) GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
 	var block etherman.Block
@@ -94,7 +115,7 @@ func (p *PostgresStorage) AddBlock(ctx context.Context, block *etherman.Block, d
 	e := p.getExecQuerier(dbTx)
 	err := e.QueryRow(ctx, addBlockSQL, block.BlockNumber, block.BlockHash, block.ParentHash, block.NetworkID, block.ReceivedAt).Scan(&blockID)
 
-	if err == pgx.ErrNoRows {
+	if errors.Is(err, pgx.ErrNoRows) {
 		err = nil
 	}
 
@@ -106,23 +127,106 @@ func (p *PostgresStorage) AddGlobalExitRoot(ctx context.Context, exitRoot *ether
 	const addExitRootSQL = "INSERT INTO sync.exit_root (block_id, global_exit_root, exit_roots) VALUES ($1, $2, $3)"
 	e := p.getExecQuerier(dbTx)
 	_, err := e.Exec(ctx, addExitRootSQL, exitRoot.BlockID, exitRoot.GlobalExitRoot, pq.Array([][]byte{exitRoot.ExitRoots[0][:], exitRoot.ExitRoots[1][:]}))
-	return err
+	return wrapInsertError(err)
 }
 
 // AddDeposit adds new deposit to the storage.
 func (p *PostgresStorage) AddDeposit(ctx context.Context, deposit *etherman.Deposit, dbTx pgx.Tx) (uint64, error) {
-	const addDepositSQL = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id"
+	const addDepositSQL = "INSERT INTO sync.deposit (leaf_type, network_id, orig_net, orig_addr, amount, dest_net, dest_addr, block_id, deposit_cnt, tx_hash, metadata, log_index, integration, sender, depositor) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id"
+	amount, err := amountValue(deposit.Amount)
+	if err != nil {
+		return 0, fmt.Errorf("error adding the deposit %d of the network %d: %w", deposit.DepositCount, deposit.NetworkID, err)
+	}
 	e := p.getExecQuerier(dbTx)
 	var depositID uint64
-	err := e.QueryRow(ctx, addDepositSQL, deposit.LeafType, deposit.NetworkID, deposit.OriginalNetwork, deposit.OriginalAddress, deposit.Amount.String(), deposit.DestinationNetwork, deposit.DestinationAddress, deposit.BlockID, deposit.DepositCount, deposit.TxHash, deposit.Metadata).Scan(&depositID)
+	err = e.QueryRow(ctx, addDepositSQL, deposit.LeafType, deposit.NetworkID, deposit.OriginalNetwork, deposit.OriginalAddress, amount, deposit.DestinationNetwork, deposit.DestinationAddress, deposit.BlockID, deposit.DepositCount, deposit.TxHash, deposit.Metadata, deposit.LogIndex, deposit.Integration, addressValue(deposit.Sender), addressValue(deposit.Depositor)).Scan(&depositID)
+	if err != nil {
+		return depositID, wrapInsertError(err)
+	}
+	err = p.addActivity(ctx, activityKindDeposit, deposit.OriginalNetwork, deposit.OriginalAddress, deposit.Amount, deposit.BlockID, dbTx)
+	if err != nil {
+		return depositID, err
+	}
+	// The claim can be synced before the deposit when the destination network is synced faster
+	const addDepositHistorySQL = `INSERT INTO sync.deposit_status_history (deposit_id, status, block_id, changed_at)
+		SELECT $1, 'indexed', id, received_at FROM sync.block WHERE id = $2
+		UNION ALL
+		SELECT $1, 'claimed', b.id, b.received_at FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id
+		WHERE c.index = $3 AND c.network_id = $4 AND c.orig_net = $5 AND c.orig_addr = $6`
+	_, err = e.Exec(ctx, addDepositHistorySQL, depositID, deposit.BlockID, deposit.DepositCount, deposit.DestinationNetwork, deposit.OriginalNetwork, deposit.OriginalAddress)
 	return depositID, err
 }
 
 // AddClaim adds new claim to the storage.
 func (p *PostgresStorage) AddClaim(ctx context.Context, claim *etherman.Claim, dbTx pgx.Tx) error {
-	const addClaimSQL = "INSERT INTO sync.claim (network_id, index, orig_net, orig_addr, amount, dest_addr, block_id, tx_hash) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	const addClaimSQL = "INSERT INTO sync.claim (network_id, index, orig_net, orig_addr, amount, dest_addr, block_id, tx_hash, log_index, message_call) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''))"
+	amount, err := amountValue(claim.Amount)
+	if err != nil {
+		return fmt.Errorf("error adding the claim %d of the network %d: %w", claim.Index, claim.NetworkID, err)
+	}
 	e := p.getExecQuerier(dbTx)
-	_, err := e.Exec(ctx, addClaimSQL, claim.NetworkID, claim.Index, claim.OriginalNetwork, claim.OriginalAddress, claim.Amount.String(), claim.DestinationAddress, claim.BlockID, claim.TxHash)
+	_, err = e.Exec(ctx, addClaimSQL, claim.NetworkID, claim.Index, claim.OriginalNetwork, claim.OriginalAddress, amount, claim.DestinationAddress, claim.BlockID, claim.TxHash, claim.LogIndex, claim.MessageCall)
+	if err != nil {
+		return wrapInsertError(err)
+	}
+	err = p.addActivity(ctx, activityKindClaim, claim.OriginalNetwork, claim.OriginalAddress, claim.Amount, claim.BlockID, dbTx)
+	if err != nil {
+		return err
+	}
+	const addClaimHistorySQL = `INSERT INTO sync.deposit_status_history (deposit_id, status, block_id, changed_at)
+		SELECT d.id, 'claimed', b.id, b.received_at FROM sync.deposit AS d, sync.block AS b
+		WHERE d.deposit_cnt = $1 AND d.dest_net = $2 AND d.orig_net = $3 AND d.orig_addr = $4 AND b.id = $5
+		ON CONFLICT DO NOTHING`
+	_, err = e.Exec(ctx, addClaimHistorySQL, claim.Index, claim.NetworkID, claim.OriginalNetwork, claim.OriginalAddress, claim.BlockID)
+	return err
+}
+
+// amountValue returns the decimal string of an amount written to the database, checking it's a uint256 so it's
+// never truncated when it's aggregated as a NUMERIC. A missing amount is 0.
+func amountValue(amount *big.Int) (string, error) {
+	if amount == nil {
+		return "0", nil
+	}
+	if err := uint256.Check(amount); err != nil {
+		return "", err
+	}
+	return amount.String(), nil
+}
+
+// addressValue returns the value of an address that is not always set, NULL when it's zero.
+func addressValue(addr common.Address) []byte {
+	if addr == (common.Address{}) {
+		return nil
+	}
+	return addr.Bytes()
+}
+
+// depositAddressColumn returns the column of the deposits queried by the role of the address, the destination
+// address when it's empty.
+func depositAddressColumn(role string) (string, error) {
+	switch role {
+	case "", etherman.DepositAddressDestination:
+		return "dest_addr", nil
+	case etherman.DepositAddressSender:
+		return "sender", nil
+	case etherman.DepositAddressDepositor:
+		return "depositor", nil
+	default:
+		return "", fmt.Errorf("invalid address role %q", role)
+	}
+}
+
+// addActivity adds a deposit or claim to the hourly activity bucket of its block.
+func (p *PostgresStorage) addActivity(ctx context.Context, kind string, originalNetwork uint, originalAddress common.Address, amount *big.Int, blockID uint64, dbTx pgx.Tx) error {
+	const addActivitySQL = `INSERT INTO sync.activity_bucket (network_id, kind, orig_net, orig_addr, bucket, count, volume)
+		SELECT network_id, $1, $2, $3, date_trunc('hour', received_at AT TIME ZONE 'UTC'), 1, $4::NUMERIC FROM sync.block WHERE id = $5
+		ON CONFLICT (network_id, kind, orig_net, orig_addr, bucket) DO UPDATE
+		SET count = sync.activity_bucket.count + 1, volume = sync.activity_bucket.volume + EXCLUDED.volume`
+	volume := "0"
+	if amount != nil {
+		volume = amount.String()
+	}
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addActivitySQL, kind, originalNetwork, originalAddress, volume, blockID)
 	return err
 }
 
@@ -140,7 +244,7 @@ func (p *PostgresStorage) AddTokenWrapped(ctx context.Context, tokenWrapped *eth
 	metadata, err := p.GetTokenMetadata(ctx, tokenWrapped.OriginalNetwork, tokenWrapped.NetworkID, tokenWrapped.OriginalTokenAddress, dbTx)
 	var tokenMetadata *etherman.TokenMetadata
 	if err != nil {
-		if err != pgx.ErrNoRows {
+		if !errors.Is(err, pgx.ErrNoRows) {
 			return err
 		}
 		// if err == pgx.ErrNoRows, this is due to missing the related deposit in the opposite network in fast sync mode.
@@ -153,17 +257,51 @@ func (p *PostgresStorage) AddTokenWrapped(ctx context.Context, tokenWrapped *eth
 		}
 	}
 
-	const addTokenWrappedSQL = "INSERT INTO sync.token_wrapped (network_id, orig_net, orig_token_addr, wrapped_token_addr, block_id, name, symbol, decimals) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	// If the token is remapped, the current mapping is replaced and the previous one is kept in the history
+	const addTokenWrappedSQL = `INSERT INTO sync.token_wrapped (network_id, orig_net, orig_token_addr, wrapped_token_addr, block_id, name, symbol, decimals) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (network_id, orig_net, orig_token_addr) DO UPDATE SET wrapped_token_addr = EXCLUDED.wrapped_token_addr, block_id = EXCLUDED.block_id, name = EXCLUDED.name, symbol = EXCLUDED.symbol, decimals = EXCLUDED.decimals`
 	e := p.getExecQuerier(dbTx)
 	_, err = e.Exec(ctx, addTokenWrappedSQL, tokenWrapped.NetworkID, tokenWrapped.OriginalNetwork, tokenWrapped.OriginalTokenAddress, tokenWrapped.WrappedTokenAddress, tokenWrapped.BlockID, tokenMetadata.Name, tokenMetadata.Symbol, tokenMetadata.Decimals)
+	if err != nil {
+		return err
+	}
+	const addTokenWrappedHistorySQL = "INSERT INTO sync.token_wrapped_history (network_id, orig_net, orig_token_addr, wrapped_token_addr, block_id, block_num, name, symbol, decimals) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
+	_, err = e.Exec(ctx, addTokenWrappedHistorySQL, tokenWrapped.NetworkID, tokenWrapped.OriginalNetwork, tokenWrapped.OriginalTokenAddress, tokenWrapped.WrappedTokenAddress, tokenWrapped.BlockID, tokenWrapped.BlockNumber, tokenMetadata.Name, tokenMetadata.Symbol, tokenMetadata.Decimals)
 	return err
 }
 
 // Reset resets the state to a block for the given DB tx.
 func (p *PostgresStorage) Reset(ctx context.Context, blockNumber uint64, networkID uint, dbTx pgx.Tx) error {
+	// The deposits and claims of the removed blocks are subtracted from the activity buckets
+	const resetActivitySQL = `UPDATE sync.activity_bucket AS a SET count = a.count - r.count, volume = a.volume - r.volume
+		FROM (
+			SELECT $3::VARCHAR AS kind, d.orig_net, d.orig_addr, date_trunc('hour', b.received_at AT TIME ZONE 'UTC') AS bucket, count(*) AS count, sum(d.amount::NUMERIC) AS volume
+			FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.block_id = b.id WHERE b.block_num > $1 AND b.network_id = $2
+			GROUP BY d.orig_net, d.orig_addr, bucket
+			UNION ALL
+			SELECT $4::VARCHAR AS kind, c.orig_net, c.orig_addr, date_trunc('hour', b.received_at AT TIME ZONE 'UTC') AS bucket, count(*) AS count, sum(c.amount::NUMERIC) AS volume
+			FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id WHERE b.block_num > $1 AND b.network_id = $2
+			GROUP BY c.orig_net, c.orig_addr, bucket
+		) AS r
+		WHERE a.network_id = $2 AND a.kind = r.kind AND a.orig_net = r.orig_net AND a.orig_addr = r.orig_addr AND a.bucket = r.bucket`
 	const resetSQL = "DELETE FROM sync.block WHERE block_num > $1 AND network_id = $2"
 	e := p.getExecQuerier(dbTx)
-	_, err := e.Exec(ctx, resetSQL, blockNumber, networkID)
+	_, err := e.Exec(ctx, resetActivitySQL, blockNumber, networkID, activityKindDeposit, activityKindClaim)
+	if err != nil {
+		return err
+	}
+	_, err = e.Exec(ctx, resetSQL, blockNumber, networkID)
+	if err != nil {
+		return err
+	}
+	// The remapped tokens whose latest mapping has been removed get back the previous mapping.
+	// The mappings stored in the history before their metadata have an empty one, filled again by GetTokenWrapped.
+	const restoreTokenWrappedSQL = `INSERT INTO sync.token_wrapped (network_id, orig_net, orig_token_addr, wrapped_token_addr, block_id, name, symbol, decimals)
+		SELECT DISTINCT ON (network_id, orig_net, orig_token_addr) network_id, orig_net, orig_token_addr, wrapped_token_addr, block_id,
+		COALESCE(name, ''), COALESCE(symbol, ''), COALESCE(decimals, 0)
+		FROM sync.token_wrapped_history WHERE network_id = $1 ORDER BY network_id, orig_net, orig_token_addr, block_num DESC, id DESC
+		ON CONFLICT (network_id, orig_net, orig_token_addr) DO NOTHING`
+	_, err = e.Exec(ctx, restoreTokenWrappedSQL, networkID)
 	return err
 }
 
@@ -203,13 +341,40 @@ func (p *PostgresStorage) GetClaim(ctx context.Context, depositCount, networkID
 		claim  etherman.Claim
 		amount string
 	)
-	const getClaimSQL = "SELECT index, orig_net, orig_addr, amount, dest_addr, block_id, network_id, tx_hash FROM sync.claim WHERE index = $1 AND network_id = $2"
-	err := p.getExecQuerier(dbTx).QueryRow(ctx, getClaimSQL, depositCount, networkID).Scan(&claim.Index, &claim.OriginalNetwork, &claim.OriginalAddress, &amount, &claim.DestinationAddress, &claim.BlockID, &claim.NetworkID, &claim.TxHash)
+	const getClaimSQL = `SELECT ` + claimColumns + ` FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id
+		WHERE c.index = $1 AND c.network_id = $2`
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getClaimSQL, depositCount, networkID).Scan(claimScanDest(&claim, &amount)...)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, gerror.ErrStorageNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if claim.Amount, err = uint256.Parse(amount); err != nil {
+		return nil, err
 	}
-	claim.Amount, _ = new(big.Int).SetString(amount, 10) //nolint:gomnd
-	return &claim, err
+	return &claim, nil
+}
+
+// The columns of the deposits and claims read by the bridge service, with their block. The events synced before
+// the log index and the sync time were recorded have a zero log index and sync time, the deposits without senders
+// have zero senders and the claims not traced have an empty message call.
+const (
+	zeroAddressSQL = `decode(repeat('00', 20), 'hex')`
+	depositColumns = `d.leaf_type, d.orig_net, d.orig_addr, d.amount, d.dest_net, d.dest_addr, d.deposit_cnt, d.block_id, b.block_num, d.network_id, d.tx_hash, d.metadata, d.ready_for_claim,
+		COALESCE(d.log_index, 0), b.received_at, COALESCE(b.synced_at, to_timestamp(0)), d.integration,
+		COALESCE(d.sender, ` + zeroAddressSQL + `), COALESCE(d.depositor, ` + zeroAddressSQL + `)`
+	claimColumns = `c.index, c.orig_net, c.orig_addr, c.amount, c.dest_addr, c.block_id, b.block_num, c.network_id, c.tx_hash,
+		COALESCE(c.log_index, 0), b.received_at, COALESCE(b.synced_at, to_timestamp(0)), COALESCE(c.message_call, '')`
+)
+
+func depositScanDest(deposit *etherman.Deposit, amount *string) []interface{} {
+	return []interface{}{&deposit.LeafType, &deposit.OriginalNetwork, &deposit.OriginalAddress, amount, &deposit.DestinationNetwork, &deposit.DestinationAddress, &deposit.DepositCount, &deposit.BlockID, &deposit.BlockNumber, &deposit.NetworkID, &deposit.TxHash, &deposit.Metadata, &deposit.ReadyForClaim,
+		&deposit.LogIndex, &deposit.BlockTime, &deposit.SyncedAt, &deposit.Integration, &deposit.Sender, &deposit.Depositor}
+}
+
+func claimScanDest(claim *etherman.Claim, amount *string) []interface{} {
+	return []interface{}{&claim.Index, &claim.OriginalNetwork, &claim.OriginalAddress, amount, &claim.DestinationAddress, &claim.BlockID, &claim.BlockNumber, &claim.NetworkID, &claim.TxHash,
+		&claim.LogIndex, &claim.BlockTime, &claim.SyncedAt, &claim.MessageCall}
 }
 
 // GetDeposit gets a specific deposit from the storage.
@@ -218,14 +383,39 @@ func (p *PostgresStorage) GetDeposit(ctx context.Context, depositCounterUser uin
 		deposit etherman.Deposit
 		amount  string
 	)
-	const getDepositSQL = "SELECT leaf_type, orig_net, orig_addr, amount, dest_net, dest_addr, deposit_cnt, block_id, b.block_num, d.network_id, tx_hash, metadata, ready_for_claim FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id WHERE d.network_id = $1 AND deposit_cnt = $2"
-	err := p.getExecQuerier(dbTx).QueryRow(ctx, getDepositSQL, networkID, depositCounterUser).Scan(&deposit.LeafType, &deposit.OriginalNetwork, &deposit.OriginalAddress, &amount, &deposit.DestinationNetwork, &deposit.DestinationAddress, &deposit.DepositCount, &deposit.BlockID, &deposit.BlockNumber, &deposit.NetworkID, &deposit.TxHash, &deposit.Metadata, &deposit.ReadyForClaim)
+	const getDepositSQL = `SELECT ` + depositColumns + ` FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE d.network_id = $1 AND deposit_cnt = $2`
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getDepositSQL, networkID, depositCounterUser).Scan(depositScanDest(&deposit, &amount)...)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, gerror.ErrStorageNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if deposit.Amount, err = uint256.Parse(amount); err != nil {
+		return nil, err
 	}
-	deposit.Amount, _ = new(big.Int).SetString(amount, 10) //nolint:gomnd
+	return &deposit, nil
+}
 
-	return &deposit, err
+// GetClaimedDeposit gets the deposit of a claim: the deposit with its index to the network of the claim, sent from its
+// origin network and address.
+func (p *PostgresStorage) GetClaimedDeposit(ctx context.Context, depositCnt, destNetworkID, origNet uint, origAddr common.Address, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	var (
+		deposit etherman.Deposit
+		amount  string
+	)
+	const getClaimedDepositSQL = `SELECT ` + depositColumns + ` FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE d.deposit_cnt = $1 AND d.dest_net = $2 AND d.orig_net = $3 AND d.orig_addr = $4 ORDER BY d.id LIMIT 1`
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getClaimedDepositSQL, depositCnt, destNetworkID, origNet, origAddr).Scan(depositScanDest(&deposit, &amount)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if deposit.Amount, err = uint256.Parse(amount); err != nil {
+		return nil, err
+	}
+	return &deposit, nil
 }
 
 // GetLatestExitRoot gets the latest global exit root.
@@ -275,7 +465,8 @@ func (p *PostgresStorage) GetLatestTrustedExitRoot(ctx context.Context, dbTx pgx
 
 // GetTokenWrapped gets a specific wrapped token.
 func (p *PostgresStorage) GetTokenWrapped(ctx context.Context, originalNetwork uint, originalTokenAddress common.Address, dbTx pgx.Tx) (*etherman.TokenWrapped, error) {
-	const getWrappedTokenSQL = "SELECT network_id, orig_net, orig_token_addr, wrapped_token_addr, block_id, name, symbol, decimals FROM sync.token_wrapped WHERE orig_net = $1 AND orig_token_addr = $2"
+	const getWrappedTokenSQL = `SELECT network_id, orig_net, orig_token_addr, wrapped_token_addr, block_id, COALESCE(name, ''), COALESCE(symbol, ''), COALESCE(decimals, 0)
+		FROM sync.token_wrapped WHERE orig_net = $1 AND orig_token_addr = $2`
 
 	var token etherman.TokenWrapped
 	err := p.getExecQuerier(dbTx).QueryRow(ctx, getWrappedTokenSQL, originalNetwork, originalTokenAddress).Scan(&token.NetworkID, &token.OriginalNetwork, &token.OriginalTokenAddress, &token.WrappedTokenAddress, &token.BlockID, &token.Name, &token.Symbol, &token.Decimals)
@@ -289,7 +480,7 @@ func (p *PostgresStorage) GetTokenWrapped(ctx context.Context, originalNetwork u
 		metadata, err := p.GetTokenMetadata(ctx, token.OriginalNetwork, token.NetworkID, token.OriginalTokenAddress, dbTx)
 		var tokenMetadata *etherman.TokenMetadata
 		if err != nil {
-			if err != pgx.ErrNoRows {
+			if !errors.Is(err, pgx.ErrNoRows) {
 				return nil, err
 			}
 		} else {
@@ -308,6 +499,102 @@ func (p *PostgresStorage) GetTokenWrapped(ctx context.Context, originalNetwork u
 	return &token, err
 }
 
+// GetTokenWrappedHistory gets all the mappings of an original token, sorted from the oldest to the newest.
+func (p *PostgresStorage) GetTokenWrappedHistory(ctx context.Context, originalNetwork uint, originalTokenAddress common.Address, dbTx pgx.Tx) ([]*etherman.TokenWrappedMapping, error) {
+	const getTokenWrappedHistorySQL = `SELECT network_id, orig_net, orig_token_addr, wrapped_token_addr, block_num,
+		COALESCE(LEAD(block_num) OVER (PARTITION BY network_id ORDER BY block_num, id), 0)
+		FROM sync.token_wrapped_history WHERE orig_net = $1 AND orig_token_addr = $2 ORDER BY network_id, block_num, id`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getTokenWrappedHistorySQL, originalNetwork, originalTokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := make([]*etherman.TokenWrappedMapping, 0, len(rows.RawValues()))
+	for rows.Next() {
+		var mapping etherman.TokenWrappedMapping
+		err = rows.Scan(&mapping.NetworkID, &mapping.OriginalNetwork, &mapping.OriginalTokenAddress, &mapping.WrappedTokenAddress, &mapping.ValidFromBlock, &mapping.ValidToBlock)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, &mapping)
+	}
+	return mappings, nil
+}
+
+// GetActivity gets the deposits and claims of a network aggregated by token and by hour or day, from the oldest
+// bucket to the newest. The token filter is optional, without it there is a bucket per token, since the volumes of
+// the tokens have different decimals. The limit applies to the returned rows, a bucket of each token.
+func (p *PostgresStorage) GetActivity(ctx context.Context, networkID uint, originalNetwork uint, originalTokenAddress *common.Address, interval string, from, to time.Time, limit uint, dbTx pgx.Tx) ([]*etherman.ActivityBucket, error) {
+	const getActivitySQL = `SELECT date_trunc($2, bucket) AS bucket_time, orig_net, orig_addr,
+			COALESCE(sum(count) FILTER (WHERE kind = $6), 0)::BIGINT, COALESCE(sum(volume) FILTER (WHERE kind = $6), 0)::VARCHAR,
+			COALESCE(sum(count) FILTER (WHERE kind = $7), 0)::BIGINT, COALESCE(sum(volume) FILTER (WHERE kind = $7), 0)::VARCHAR
+		FROM sync.activity_bucket
+		WHERE network_id = $1 AND bucket >= date_trunc($2, $3::TIMESTAMP) AND bucket < $4 AND ($5::BOOLEAN IS FALSE OR (orig_net = $8 AND orig_addr = $9))
+		GROUP BY bucket_time, orig_net, orig_addr HAVING sum(count) > 0 ORDER BY bucket_time ASC, orig_net ASC, orig_addr ASC LIMIT $10`
+	var tokenAddr common.Address
+	if originalTokenAddress != nil {
+		tokenAddr = *originalTokenAddress
+	}
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getActivitySQL, networkID, interval, from.UTC(), to.UTC(), originalTokenAddress != nil, activityKindDeposit, activityKindClaim, originalNetwork, tokenAddr, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	buckets := make([]*etherman.ActivityBucket, 0, len(rows.RawValues()))
+	for rows.Next() {
+		var (
+			bucket                     etherman.ActivityBucket
+			depositVolume, claimVolume string
+		)
+		err = rows.Scan(&bucket.Time, &bucket.OriginalNetwork, &bucket.OriginalAddress, &bucket.DepositCount, &depositVolume, &bucket.ClaimCount, &claimVolume)
+		if err != nil {
+			return nil, err
+		}
+		if bucket.DepositVolume, err = uint256.ParseSum(depositVolume); err != nil {
+			return nil, err
+		}
+		if bucket.ClaimVolume, err = uint256.ParseSum(claimVolume); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, &bucket)
+	}
+	return buckets, rows.Err()
+}
+
+// GetIntegrationStats gets the number and the volume of the deposits of a network by the integration they come
+// from, in the range [from, to) of the times of their blocks. The token filter is optional.
+func (p *PostgresStorage) GetIntegrationStats(ctx context.Context, networkID uint, originalNetwork uint, originalTokenAddress *common.Address, from, to time.Time, dbTx pgx.Tx) ([]*etherman.IntegrationStats, error) {
+	const getIntegrationStatsSQL = `SELECT d.integration, count(*), COALESCE(sum(d.amount::NUMERIC), 0)::VARCHAR
+		FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.block_id = b.id
+		WHERE d.network_id = $1 AND b.received_at >= $2 AND b.received_at < $3 AND ($4::BOOLEAN IS FALSE OR (d.orig_net = $5 AND d.orig_addr = $6))
+		GROUP BY d.integration ORDER BY count(*) DESC, d.integration`
+	var tokenAddr common.Address
+	if originalTokenAddress != nil {
+		tokenAddr = *originalTokenAddress
+	}
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getIntegrationStatsSQL, networkID, from.UTC(), to.UTC(), originalTokenAddress != nil, originalNetwork, tokenAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var stats []*etherman.IntegrationStats
+	for rows.Next() {
+		var (
+			integration etherman.IntegrationStats
+			volume      string
+		)
+		if err = rows.Scan(&integration.Integration, &integration.DepositCount, &volume); err != nil {
+			return nil, err
+		}
+		if integration.DepositVolume, err = uint256.ParseSum(volume); err != nil {
+			return nil, err
+		}
+		stats = append(stats, &integration)
+	}
+	return stats, rows.Err()
+}
+
 // GetDepositCountByRoot gets the deposit count by the root.
 func (p *PostgresStorage) GetDepositCountByRoot(ctx context.Context, root []byte, network uint8, dbTx pgx.Tx) (uint, error) {
 	var depositCount uint
@@ -377,6 +664,138 @@ func (p *PostgresStorage) GetLastDepositCount(ctx context.Context, network uint,
 	return uint(depositCnt), nil
 }
 
+// AddRollupExitLeaf adds a leaf of the rollup exit tree with the rollup exit root after updating it.
+func (p *PostgresStorage) AddRollupExitLeaf(ctx context.Context, leaf *etherman.RollupExitLeaf, dbTx pgx.Tx) error {
+	const addRollupExitLeafSQL = "INSERT INTO mt.rollup_exit (leaf, rollup_id, root, block_id) VALUES ($1, $2, $3, $4)"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addRollupExitLeafSQL, leaf.Leaf, leaf.RollupID, leaf.Root, leaf.BlockID)
+	return err
+}
+
+// GetLatestRollupExitLeaves gets the latest leaf of each rollup in the rollup exit tree.
+func (p *PostgresStorage) GetLatestRollupExitLeaves(ctx context.Context, dbTx pgx.Tx) ([]etherman.RollupExitLeaf, error) {
+	const getLatestRollupExitLeavesSQL = `SELECT DISTINCT ON (r.rollup_id) r.leaf, r.rollup_id, r.root, r.block_id, b.block_num
+		FROM mt.rollup_exit AS r INNER JOIN sync.block AS b ON b.id = r.block_id
+		ORDER BY r.rollup_id, r.id DESC`
+	return p.getRollupExitLeaves(ctx, getLatestRollupExitLeavesSQL, dbTx)
+}
+
+// GetRollupExitLeavesByRoot gets the leaves of the rollup exit tree with the given root.
+func (p *PostgresStorage) GetRollupExitLeavesByRoot(ctx context.Context, root common.Hash, dbTx pgx.Tx) ([]etherman.RollupExitLeaf, error) {
+	const getRollupExitLeavesByRootSQL = `SELECT DISTINCT ON (r.rollup_id) r.leaf, r.rollup_id, r.root, r.block_id, b.block_num
+		FROM mt.rollup_exit AS r INNER JOIN sync.block AS b ON b.id = r.block_id
+		WHERE r.id <= (SELECT MAX(id) FROM mt.rollup_exit WHERE root = $1)
+		ORDER BY r.rollup_id, r.id DESC`
+	leaves, err := p.getRollupExitLeaves(ctx, getRollupExitLeavesByRootSQL, dbTx, root)
+	if err != nil {
+		return nil, err
+	}
+	if len(leaves) == 0 {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return leaves, nil
+}
+
+func (p *PostgresStorage) getRollupExitLeaves(ctx context.Context, sql string, dbTx pgx.Tx, args ...interface{}) ([]etherman.RollupExitLeaf, error) {
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	leaves := make([]etherman.RollupExitLeaf, 0)
+	for rows.Next() {
+		var leaf etherman.RollupExitLeaf
+		if err := rows.Scan(&leaf.Leaf, &leaf.RollupID, &leaf.Root, &leaf.BlockID, &leaf.BlockNumber); err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	return leaves, rows.Err()
+}
+
+// AddL1InfoTreeLeaf adds a leaf of the L1 info tree with the L1 info root after appending it.
+func (p *PostgresStorage) AddL1InfoTreeLeaf(ctx context.Context, leaf *etherman.L1InfoTreeLeaf, dbTx pgx.Tx) error {
+	const addL1InfoTreeLeafSQL = "INSERT INTO mt.l1_info_tree (leaf_index, leaf, global_exit_root, parent_hash, timestamp, root, block_id) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addL1InfoTreeLeafSQL, leaf.Index, leaf.Leaf, leaf.GlobalExitRoot, leaf.ParentHash, leaf.Timestamp, leaf.Root, leaf.BlockID)
+	return wrapInsertError(err)
+}
+
+// GetL1InfoTreeLeaves gets the leaves of the L1 info tree in order.
+func (p *PostgresStorage) GetL1InfoTreeLeaves(ctx context.Context, dbTx pgx.Tx) ([]etherman.L1InfoTreeLeaf, error) {
+	const getL1InfoTreeLeavesSQL = `SELECT l.leaf_index, l.leaf, l.global_exit_root, l.parent_hash, l.timestamp, l.root, l.block_id, b.block_num
+		FROM mt.l1_info_tree AS l INNER JOIN sync.block AS b ON b.id = l.block_id ORDER BY l.leaf_index`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getL1InfoTreeLeavesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	leaves := make([]etherman.L1InfoTreeLeaf, 0)
+	for rows.Next() {
+		var leaf etherman.L1InfoTreeLeaf
+		if err := rows.Scan(&leaf.Index, &leaf.Leaf, &leaf.GlobalExitRoot, &leaf.ParentHash, &leaf.Timestamp, &leaf.Root, &leaf.BlockID, &leaf.BlockNumber); err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	return leaves, rows.Err()
+}
+
+// GetL1InfoTreeLeaf gets the first leaf of the L1 info tree with the global exit root.
+func (p *PostgresStorage) GetL1InfoTreeLeaf(ctx context.Context, ger common.Hash, dbTx pgx.Tx) (*etherman.L1InfoTreeLeaf, error) {
+	const getL1InfoTreeLeafSQL = `SELECT l.leaf_index, l.leaf, l.global_exit_root, l.parent_hash, l.timestamp, l.root, l.block_id, b.block_num
+		FROM mt.l1_info_tree AS l INNER JOIN sync.block AS b ON b.id = l.block_id
+		WHERE l.global_exit_root = $1 ORDER BY l.leaf_index LIMIT 1`
+	return p.getL1InfoTreeLeaf(ctx, getL1InfoTreeLeafSQL, dbTx, ger)
+}
+
+// GetLatestL1InfoTreeLeaf gets the last leaf of the L1 info tree, with the current L1 info root.
+func (p *PostgresStorage) GetLatestL1InfoTreeLeaf(ctx context.Context, dbTx pgx.Tx) (*etherman.L1InfoTreeLeaf, error) {
+	const getLatestL1InfoTreeLeafSQL = `SELECT l.leaf_index, l.leaf, l.global_exit_root, l.parent_hash, l.timestamp, l.root, l.block_id, b.block_num
+		FROM mt.l1_info_tree AS l INNER JOIN sync.block AS b ON b.id = l.block_id
+		ORDER BY l.leaf_index DESC LIMIT 1`
+	return p.getL1InfoTreeLeaf(ctx, getLatestL1InfoTreeLeafSQL, dbTx)
+}
+
+func (p *PostgresStorage) getL1InfoTreeLeaf(ctx context.Context, query string, dbTx pgx.Tx, args ...interface{}) (*etherman.L1InfoTreeLeaf, error) {
+	var leaf etherman.L1InfoTreeLeaf
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, query, args...).Scan(&leaf.Index, &leaf.Leaf, &leaf.GlobalExitRoot, &leaf.ParentHash, &leaf.Timestamp, &leaf.Root, &leaf.BlockID, &leaf.BlockNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &leaf, nil
+}
+
+// GetL1InfoTreeLeafCount gets the number of leaves of the L1 info tree.
+func (p *PostgresStorage) GetL1InfoTreeLeafCount(ctx context.Context, dbTx pgx.Tx) (uint, error) {
+	var count uint
+	const getL1InfoTreeLeafCountSQL = "SELECT coalesce(MAX(leaf_index) + 1, 0) FROM mt.l1_info_tree"
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getL1InfoTreeLeafCountSQL).Scan(&count)
+	return count, err
+}
+
+// GetL1GlobalExitRootUpdates gets the global exit roots synced from L1 in order, with the block of their update.
+func (p *PostgresStorage) GetL1GlobalExitRootUpdates(ctx context.Context, dbTx pgx.Tx) ([]etherman.L1InfoTreeLeaf, error) {
+	const getL1GlobalExitRootUpdatesSQL = `SELECT e.global_exit_root, b.parent_hash, b.received_at, b.id, b.block_num
+		FROM sync.exit_root AS e INNER JOIN sync.block AS b ON b.id = e.block_id
+		WHERE e.block_id > 0 ORDER BY e.id`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getL1GlobalExitRootUpdatesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	updates := make([]etherman.L1InfoTreeLeaf, 0)
+	for rows.Next() {
+		var update etherman.L1InfoTreeLeaf
+		if err := rows.Scan(&update.GlobalExitRoot, &update.ParentHash, &update.Timestamp, &update.BlockID, &update.BlockNumber); err != nil {
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+	return updates, rows.Err()
+}
+
 // GetClaimCount gets the claim count for the destination address.
 func (p *PostgresStorage) GetClaimCount(ctx context.Context, destAddr string, dbTx pgx.Tx) (uint64, error) {
 	const getClaimCountSQL = "SELECT COUNT(*) FROM sync.claim WHERE dest_addr = $1"
@@ -388,9 +807,10 @@ func (p *PostgresStorage) GetClaimCount(ctx context.Context, destAddr string, db
 	return claimCount, err
 }
 
-// GetClaims gets the claim list which be smaller than index.
+// GetClaims gets the claims of an address, from the last one in the ordering of the events.
 func (p *PostgresStorage) GetClaims(ctx context.Context, destAddr string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Claim, error) {
-	const getClaimsSQL = "SELECT index, orig_net, orig_addr, amount, dest_addr, block_id, network_id, tx_hash FROM sync.claim WHERE dest_addr = $1 ORDER BY block_id DESC LIMIT $2 OFFSET $3"
+	const getClaimsSQL = `SELECT ` + claimColumns + ` FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id
+		WHERE c.dest_addr = $1 ORDER BY b.id DESC, c.log_index DESC NULLS LAST, c.index DESC LIMIT $2 OFFSET $3`
 	rows, err := p.getExecQuerier(dbTx).Query(ctx, getClaimsSQL, common.FromHex(destAddr), limit, offset)
 	if err != nil {
 		return nil, err
@@ -402,20 +822,29 @@ func (p *PostgresStorage) GetClaims(ctx context.Context, destAddr string, limit
 			claim  etherman.Claim
 			amount string
 		)
-		err = rows.Scan(&claim.Index, &claim.OriginalNetwork, &claim.OriginalAddress, &amount, &claim.DestinationAddress, &claim.BlockID, &claim.NetworkID, &claim.TxHash)
+		err = rows.Scan(claimScanDest(&claim, &amount)...)
 		if err != nil {
 			return nil, err
 		}
-		claim.Amount, _ = new(big.Int).SetString(amount, 10) //nolint:gomnd
+		if claim.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
 		claims = append(claims, &claim)
 	}
 	return claims, nil
 }
 
-// GetDeposits gets the deposit list which be smaller than depositCount.
-func (p *PostgresStorage) GetDeposits(ctx context.Context, destAddr string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
-	const getDepositsSQL = "SELECT leaf_type, orig_net, orig_addr, amount, dest_net, dest_addr, deposit_cnt, block_id, b.block_num, d.network_id, tx_hash, metadata, ready_for_claim FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id WHERE dest_addr = $1 ORDER BY d.block_id DESC, d.deposit_cnt DESC LIMIT $2 OFFSET $3"
-	rows, err := p.getExecQuerier(dbTx).Query(ctx, getDepositsSQL, common.FromHex(destAddr), limit, offset)
+// GetDeposits gets the deposits of an address in its role, the destination address, the msg.sender of the bridge
+// call or the sender of the tx, from the last one in the ordering of the events. The deposits of all the
+// integrations are returned when integration is empty.
+func (p *PostgresStorage) GetDeposits(ctx context.Context, addr string, role string, integration string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	column, err := depositAddressColumn(role)
+	if err != nil {
+		return nil, err
+	}
+	getDepositsSQL := `SELECT ` + depositColumns + ` FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE d.` + column + ` = $1 AND ($4 = '' OR d.integration = $4) ORDER BY b.id DESC, d.log_index DESC NULLS LAST, d.deposit_cnt DESC LIMIT $2 OFFSET $3`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getDepositsSQL, common.FromHex(addr), limit, offset, integration)
 	if err != nil {
 		return nil, err
 	}
@@ -427,131 +856,1668 @@ func (p *PostgresStorage) GetDeposits(ctx context.Context, destAddr string, limi
 			deposit etherman.Deposit
 			amount  string
 		)
-		err = rows.Scan(&deposit.LeafType, &deposit.OriginalNetwork, &deposit.OriginalAddress, &amount, &deposit.DestinationNetwork, &deposit.DestinationAddress, &deposit.DepositCount, &deposit.BlockID, &deposit.BlockNumber, &deposit.NetworkID, &deposit.TxHash, &deposit.Metadata, &deposit.ReadyForClaim)
+		err = rows.Scan(depositScanDest(&deposit, &amount)...)
 		if err != nil {
 			return nil, err
 		}
-		deposit.Amount, _ = new(big.Int).SetString(amount, 10) //nolint:gomnd
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
 		deposits = append(deposits, &deposit)
 	}
 
 	return deposits, nil
 }
 
-// GetDepositCount gets the deposit count for the destination address.
-func (p *PostgresStorage) GetDepositCount(ctx context.Context, destAddr string, dbTx pgx.Tx) (uint64, error) {
-	const getDepositCountSQL = "SELECT COUNT(*) FROM sync.deposit WHERE dest_addr = $1"
-	var depositCount uint64
-	err := p.getExecQuerier(dbTx).QueryRow(ctx, getDepositCountSQL, common.FromHex(destAddr)).Scan(&depositCount)
-	return depositCount, err
-}
-
-// UpdateBlocksForTesting updates the hash of blocks.
-func (p *PostgresStorage) UpdateBlocksForTesting(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) error {
-	const updateBlocksSQL = "UPDATE sync.block SET block_hash = SUBSTRING(block_hash FROM 1 FOR LENGTH(block_hash)-1) || '\x61' WHERE network_id = $1 AND block_num >= $2"
-	_, err := p.getExecQuerier(dbTx).Exec(ctx, updateBlocksSQL, networkID, blockNum)
-	return err
-}
-
-// UpdateL1DepositsStatus updates the ready_for_claim status of L1 deposits.
-func (p *PostgresStorage) UpdateL1DepositsStatus(ctx context.Context, exitRoot []byte, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
-	const updateDepositsStatusSQL = `UPDATE sync.deposit SET ready_for_claim = true 
-		WHERE deposit_cnt <=
-			(SELECT sync.deposit.deposit_cnt FROM mt.root INNER JOIN sync.deposit ON sync.deposit.id = mt.root.deposit_id WHERE mt.root.root = $1 AND mt.root.network = 0) 
-			AND network_id = 0 AND ready_for_claim = false
-			RETURNING leaf_type, orig_net, orig_addr, amount, dest_net, dest_addr, deposit_cnt, block_id, network_id, tx_hash, metadata, ready_for_claim;`
-	rows, err := p.getExecQuerier(dbTx).Query(ctx, updateDepositsStatusSQL, exitRoot)
+// GetNetworkDepositsUntilBlock gets the deposits of a network synced until the block number, in order.
+func (p *PostgresStorage) GetNetworkDepositsUntilBlock(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	const getDepositsSQL = `SELECT leaf_type, orig_net, orig_addr, amount, dest_net, dest_addr, deposit_cnt, block_id, b.block_num, d.network_id, tx_hash, metadata, ready_for_claim
+		FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE d.network_id = $1 AND b.block_num <= $2 ORDER BY d.deposit_cnt`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getDepositsSQL, networkID, blockNum)
 	if err != nil {
 		return nil, err
 	}
-
-	deposits := make([]*etherman.Deposit, 0, len(rows.RawValues()))
+	defer rows.Close()
+	deposits := make([]*etherman.Deposit, 0)
 	for rows.Next() {
 		var (
 			deposit etherman.Deposit
 			amount  string
 		)
-		err = rows.Scan(&deposit.LeafType, &deposit.OriginalNetwork, &deposit.OriginalAddress, &amount, &deposit.DestinationNetwork, &deposit.DestinationAddress, &deposit.DepositCount, &deposit.BlockID, &deposit.NetworkID, &deposit.TxHash, &deposit.Metadata, &deposit.ReadyForClaim)
+		err = rows.Scan(&deposit.LeafType, &deposit.OriginalNetwork, &deposit.OriginalAddress, &amount, &deposit.DestinationNetwork, &deposit.DestinationAddress, &deposit.DepositCount, &deposit.BlockID, &deposit.BlockNumber, &deposit.NetworkID, &deposit.TxHash, &deposit.Metadata, &deposit.ReadyForClaim)
 		if err != nil {
 			return nil, err
 		}
-		deposit.Amount, _ = new(big.Int).SetString(amount, 10) //nolint:gomnd
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
 		deposits = append(deposits, &deposit)
 	}
-	return deposits, nil
-}
-
-// UpdateL2DepositsStatus updates the ready_for_claim status of L2 deposits.
-func (p *PostgresStorage) UpdateL2DepositsStatus(ctx context.Context, exitRoot []byte, networkID uint, dbTx pgx.Tx) error {
-	const updateDepositsStatusSQL = `UPDATE sync.deposit SET ready_for_claim = true
-		WHERE deposit_cnt <=
-			(SELECT sync.deposit.deposit_cnt FROM mt.root INNER JOIN sync.deposit ON sync.deposit.id = mt.root.deposit_id WHERE mt.root.root = $1 AND mt.root.network = $2)
-			AND network_id = $2 AND ready_for_claim = false;`
-	_, err := p.getExecQuerier(dbTx).Exec(ctx, updateDepositsStatusSQL, exitRoot, networkID)
-	return err
-}
-
-// AddClaimTx adds a claim monitored transaction to the storage.
-func (p *PostgresStorage) AddClaimTx(ctx context.Context, mTx ctmtypes.MonitoredTx, dbTx pgx.Tx) error {
-	const addMonitoredTxSQL = `INSERT INTO sync.monitored_txs 
-		(deposit_id, from_addr, to_addr, nonce, value, data, gas, status, history, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-	_, err := p.getExecQuerier(dbTx).Exec(ctx, addMonitoredTxSQL, mTx.DepositID, mTx.From, mTx.To, mTx.Nonce, mTx.Value.String(), mTx.Data, mTx.Gas, mTx.Status, pq.Array(mTx.HistoryHashSlice()), time.Now().UTC(), time.Now().UTC())
-	return err
-}
-
-// UpdateClaimTx updates a claim monitored transaction in the storage.
-func (p *PostgresStorage) UpdateClaimTx(ctx context.Context, mTx ctmtypes.MonitoredTx, dbTx pgx.Tx) error {
-	const updateMonitoredTxSQL = `UPDATE sync.monitored_txs 
-		SET from_addr = $2
-		, to_addr = $3
-		, nonce = $4
-		, value = $5
-		, data = $6
-		, gas = $7
-		, status = $8
-		, history = $9
-		, updated_at = $10
-		WHERE deposit_id = $1`
-	_, err := p.getExecQuerier(dbTx).Exec(ctx, updateMonitoredTxSQL, mTx.DepositID, mTx.From, mTx.To, mTx.Nonce, mTx.Value.String(), mTx.Data, mTx.Gas, mTx.Status, pq.Array(mTx.HistoryHashSlice()), time.Now().UTC())
-	return err
+	return deposits, rows.Err()
 }
 
-// GetClaimTxsByStatus gets the monitored transactions by status.
-func (p *PostgresStorage) GetClaimTxsByStatus(ctx context.Context, statuses []ctmtypes.MonitoredTxStatus, dbTx pgx.Tx) ([]ctmtypes.MonitoredTx, error) {
-	const getMonitoredTxsSQL = "SELECT deposit_id, from_addr, to_addr, nonce, value, data, gas, status, history, created_at, updated_at FROM sync.monitored_txs WHERE status = ANY($1) ORDER BY created_at ASC"
-	rows, err := p.getExecQuerier(dbTx).Query(ctx, getMonitoredTxsSQL, pq.Array(statuses))
-	if errors.Is(err, pgx.ErrNoRows) {
-		return []ctmtypes.MonitoredTx{}, nil
-	} else if err != nil {
+// GetNetworkClaimsUntilBlock gets the claims of a network synced until the block number, in order.
+func (p *PostgresStorage) GetNetworkClaimsUntilBlock(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) ([]*etherman.Claim, error) {
+	const getClaimsSQL = `SELECT c.index, c.orig_net, c.orig_addr, c.amount, c.dest_addr, c.block_id, b.block_num, c.network_id, c.tx_hash
+		FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id
+		WHERE c.network_id = $1 AND b.block_num <= $2 ORDER BY b.block_num, c.index`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getClaimsSQL, networkID, blockNum)
+	if err != nil {
 		return nil, err
 	}
-
-	mTxs := make([]ctmtypes.MonitoredTx, 0, len(rows.RawValues()))
+	defer rows.Close()
+	claims := make([]*etherman.Claim, 0)
 	for rows.Next() {
 		var (
-			value   string
-			history [][]byte
+			claim  etherman.Claim
+			amount string
 		)
-		mTx := ctmtypes.MonitoredTx{}
-		err = rows.Scan(&mTx.DepositID, &mTx.From, &mTx.To, &mTx.Nonce, &value, &mTx.Data, &mTx.Gas, &mTx.Status, pq.Array(&history), &mTx.CreatedAt, &mTx.UpdatedAt)
+		err = rows.Scan(&claim.Index, &claim.OriginalNetwork, &claim.OriginalAddress, &amount, &claim.DestinationAddress, &claim.BlockID, &claim.BlockNumber, &claim.NetworkID, &claim.TxHash)
 		if err != nil {
-			return mTxs, err
+			return nil, err
 		}
-		mTx.Value, _ = new(big.Int).SetString(value, 10) //nolint:gomnd
-		mTx.History = make(map[common.Hash]bool)
-		for _, h := range history {
-			mTx.History[common.BytesToHash(h)] = true
+		if claim.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
 		}
-		mTxs = append(mTxs, mTx)
+		claims = append(claims, &claim)
 	}
+	return claims, rows.Err()
+}
 
-	return mTxs, nil
+// GetBlockUntil gets the last synced block of a network until the block number.
+func (p *PostgresStorage) GetBlockUntil(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.Block, error) {
+	var block etherman.Block
+	const getBlockUntilSQL = "SELECT id, block_num, block_hash, parent_hash, network_id, received_at FROM sync.block WHERE network_id = $1 AND block_num <= $2 ORDER BY block_num DESC LIMIT 1"
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getBlockUntilSQL, networkID, blockNum).Scan(&block.ID, &block.BlockNumber, &block.BlockHash, &block.ParentHash, &block.NetworkID, &block.ReceivedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &block, err
 }
 
-// UpdateDepositsStatusForTesting updates the ready_for_claim status of all deposits for testing.
-func (p *PostgresStorage) UpdateDepositsStatusForTesting(ctx context.Context, dbTx pgx.Tx) error {
-	const updateDepositsStatusSQL = "UPDATE sync.deposit SET ready_for_claim = true;"
+// GetFirstBlock gets the first synced block of a network.
+func (p *PostgresStorage) GetFirstBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
+	var block etherman.Block
+	const getFirstBlockSQL = "SELECT id, block_num, block_hash, parent_hash, network_id, received_at FROM sync.block WHERE network_id = $1 ORDER BY block_num LIMIT 1"
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getFirstBlockSQL, networkID).Scan(&block.ID, &block.BlockNumber, &block.BlockHash, &block.ParentHash, &block.NetworkID, &block.ReceivedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &block, err
+}
+
+// GetBlockByHash gets a synced block of a network by its hash.
+func (p *PostgresStorage) GetBlockByHash(ctx context.Context, networkID uint, blockHash common.Hash, dbTx pgx.Tx) (*etherman.Block, error) {
+	var block etherman.Block
+	const getBlockByHashSQL = "SELECT id, block_num, block_hash, parent_hash, network_id, received_at FROM sync.block WHERE network_id = $1 AND block_hash = $2"
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getBlockByHashSQL, networkID, blockHash).Scan(&block.ID, &block.BlockNumber, &block.BlockHash, &block.ParentHash, &block.NetworkID, &block.ReceivedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &block, err
+}
+
+// GetBlockDeposits gets the deposits of a block, in the order of their logs.
+func (p *PostgresStorage) GetBlockDeposits(ctx context.Context, blockID uint64, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	const getBlockDepositsSQL = `SELECT ` + depositColumns + ` FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE d.block_id = $1 ORDER BY d.log_index NULLS FIRST, d.deposit_cnt`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getBlockDepositsSQL, blockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	deposits := make([]*etherman.Deposit, 0)
+	for rows.Next() {
+		var (
+			deposit etherman.Deposit
+			amount  string
+		)
+		if err = rows.Scan(depositScanDest(&deposit, &amount)...); err != nil {
+			return nil, err
+		}
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, &deposit)
+	}
+	return deposits, rows.Err()
+}
+
+// GetBlockClaims gets the claims of a block, in the order of their logs.
+func (p *PostgresStorage) GetBlockClaims(ctx context.Context, blockID uint64, dbTx pgx.Tx) ([]*etherman.Claim, error) {
+	const getBlockClaimsSQL = `SELECT ` + claimColumns + ` FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id
+		WHERE c.block_id = $1 ORDER BY c.log_index NULLS FIRST, c.index`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getBlockClaimsSQL, blockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	claims := make([]*etherman.Claim, 0)
+	for rows.Next() {
+		var (
+			claim  etherman.Claim
+			amount string
+		)
+		if err = rows.Scan(claimScanDest(&claim, &amount)...); err != nil {
+			return nil, err
+		}
+		if claim.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		claims = append(claims, &claim)
+	}
+	return claims, rows.Err()
+}
+
+// GetDepositsByTxHash gets the deposits of a tx.
+func (p *PostgresStorage) GetDepositsByTxHash(ctx context.Context, txHash common.Hash, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	const getDepositsSQL = `SELECT ` + depositColumns + ` FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE tx_hash = $1 ORDER BY d.network_id, b.block_num, d.log_index NULLS FIRST, d.deposit_cnt`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getDepositsSQL, txHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deposits []*etherman.Deposit
+	for rows.Next() {
+		var (
+			deposit etherman.Deposit
+			amount  string
+		)
+		err = rows.Scan(depositScanDest(&deposit, &amount)...)
+		if err != nil {
+			return nil, err
+		}
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, &deposit)
+	}
+	if len(deposits) == 0 {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return deposits, rows.Err()
+}
+
+// SearchDeposits gets the deposits whose destination address or tx hash start with the hex prefix, from the last
+// one in the ordering of the events.
+func (p *PostgresStorage) SearchDeposits(ctx context.Context, prefix string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	from, to, err := hexPrefixRange(prefix)
+	if err != nil {
+		return nil, err
+	}
+	const searchDepositsSQL = `SELECT ` + depositColumns + ` FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE (d.dest_addr >= $1 AND d.dest_addr < $2) OR (d.tx_hash >= $1 AND d.tx_hash < $2)
+		ORDER BY b.id DESC, d.log_index DESC NULLS LAST, d.deposit_cnt DESC LIMIT $3 OFFSET $4`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, searchDepositsSQL, from, to, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deposits []*etherman.Deposit
+	for rows.Next() {
+		var (
+			deposit etherman.Deposit
+			amount  string
+		)
+		err = rows.Scan(depositScanDest(&deposit, &amount)...)
+		if err != nil {
+			return nil, err
+		}
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, &deposit)
+	}
+	return deposits, rows.Err()
+}
+
+// SearchClaims gets the claims whose destination address or tx hash start with the hex prefix, from the last
+// one in the ordering of the events.
+func (p *PostgresStorage) SearchClaims(ctx context.Context, prefix string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Claim, error) {
+	from, to, err := hexPrefixRange(prefix)
+	if err != nil {
+		return nil, err
+	}
+	const searchClaimsSQL = `SELECT ` + claimColumns + ` FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id
+		WHERE (c.dest_addr >= $1 AND c.dest_addr < $2) OR (c.tx_hash >= $1 AND c.tx_hash < $2)
+		ORDER BY b.id DESC, c.log_index DESC NULLS LAST, c.index DESC LIMIT $3 OFFSET $4`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, searchClaimsSQL, from, to, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claims []*etherman.Claim
+	for rows.Next() {
+		var (
+			claim  etherman.Claim
+			amount string
+		)
+		err = rows.Scan(claimScanDest(&claim, &amount)...)
+		if err != nil {
+			return nil, err
+		}
+		if claim.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		claims = append(claims, &claim)
+	}
+	return claims, rows.Err()
+}
+
+// AddDepositTrace stores the traced origin of a deposit tx. The trace of a tx with several deposits is only stored once.
+func (p *PostgresStorage) AddDepositTrace(ctx context.Context, trace *etherman.DepositTrace, dbTx pgx.Tx) error {
+	const addDepositTraceSQL = "INSERT INTO sync.deposit_trace (network_id, tx_hash, block_id, originator, call_path) VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING"
+	callPath := make([][]byte, 0, len(trace.CallPath))
+	for _, addr := range trace.CallPath {
+		callPath = append(callPath, addr.Bytes())
+	}
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addDepositTraceSQL, trace.NetworkID, trace.TxHash, trace.BlockID, trace.Originator, pq.Array(callPath))
+	return err
+}
+
+// GetDepositTrace gets the traced origin of a deposit tx.
+func (p *PostgresStorage) GetDepositTrace(ctx context.Context, networkID uint, txHash common.Hash, dbTx pgx.Tx) (*etherman.DepositTrace, error) {
+	const getDepositTraceSQL = "SELECT network_id, tx_hash, block_id, originator, call_path FROM sync.deposit_trace WHERE network_id = $1 AND tx_hash = $2"
+	var (
+		trace    etherman.DepositTrace
+		callPath [][]byte
+	)
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getDepositTraceSQL, networkID, txHash).Scan(&trace.NetworkID, &trace.TxHash, &trace.BlockID, &trace.Originator, pq.Array(&callPath))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	trace.CallPath = make([]common.Address, 0, len(callPath))
+	for _, addr := range callPath {
+		trace.CallPath = append(trace.CallPath, common.BytesToAddress(addr))
+	}
+	return &trace, nil
+}
+
+// GetUntracedDeposits gets the deposits of a network from a deposit count whose tx has no trace, in order.
+func (p *PostgresStorage) GetUntracedDeposits(ctx context.Context, networkID, fromDepositCnt, limit uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	const getUntracedDepositsSQL = `SELECT ` + depositColumns + ` FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE d.network_id = $1 AND d.deposit_cnt >= $2
+		AND NOT EXISTS (SELECT 1 FROM sync.deposit_trace as t WHERE t.network_id = d.network_id AND t.tx_hash = d.tx_hash)
+		ORDER BY d.deposit_cnt LIMIT $3`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getUntracedDepositsSQL, networkID, fromDepositCnt, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deposits []*etherman.Deposit
+	for rows.Next() {
+		var (
+			deposit etherman.Deposit
+			amount  string
+		)
+		if err := rows.Scan(depositScanDest(&deposit, &amount)...); err != nil {
+			return nil, err
+		}
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, &deposit)
+	}
+	return deposits, rows.Err()
+}
+
+// GetUntracedDepositsTo gets the deposits of a network to an address whose tx has no trace stored, from a deposit
+// count, in order.
+func (p *PostgresStorage) GetUntracedDepositsTo(ctx context.Context, networkID uint, destAddr common.Address, fromDepositCnt, limit uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	const getUntracedDepositsToSQL = `SELECT ` + depositColumns + ` FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE d.network_id = $1 AND d.dest_addr = $2 AND d.deposit_cnt >= $3
+		AND NOT EXISTS (SELECT 1 FROM sync.deposit_trace as t WHERE t.network_id = d.network_id AND t.tx_hash = d.tx_hash)
+		ORDER BY d.deposit_cnt LIMIT $4`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getUntracedDepositsToSQL, networkID, destAddr, fromDepositCnt, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deposits []*etherman.Deposit
+	for rows.Next() {
+		var (
+			deposit etherman.Deposit
+			amount  string
+		)
+		if err := rows.Scan(depositScanDest(&deposit, &amount)...); err != nil {
+			return nil, err
+		}
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, &deposit)
+	}
+	return deposits, rows.Err()
+}
+
+// GetDepositStatusHistory gets the changes of status of a deposit, in order.
+func (p *PostgresStorage) GetDepositStatusHistory(ctx context.Context, depositCnt, networkID uint, dbTx pgx.Tx) ([]etherman.DepositStatusChange, error) {
+	const getDepositStatusHistorySQL = `SELECT h.status, COALESCE(b.id, 0), COALESCE(b.network_id, 0), COALESCE(b.block_num, 0), h.changed_at
+		FROM sync.deposit_status_history AS h INNER JOIN sync.deposit AS d ON d.id = h.deposit_id LEFT JOIN sync.block AS b ON b.id = h.block_id
+		WHERE d.network_id = $1 AND d.deposit_cnt = $2
+		ORDER BY h.changed_at, array_position(ARRAY['indexed', 'ready_for_claim', 'claimed']::VARCHAR[], h.status)`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getDepositStatusHistorySQL, networkID, depositCnt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var history []etherman.DepositStatusChange
+	for rows.Next() {
+		var c etherman.DepositStatusChange
+		if err := rows.Scan(&c.Status, &c.BlockID, &c.NetworkID, &c.BlockNumber, &c.Time); err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
+
+// AddDepositActivities stores entries of the activity log of the deposits, copying them in a single statement.
+func (p *PostgresStorage) AddDepositActivities(ctx context.Context, entries []etherman.DepositActivity, dbTx pgx.Tx) error {
+	rows := make([][]interface{}, 0, len(entries))
+	for _, a := range entries {
+		fields := a.Fields
+		if fields == nil {
+			fields = map[string]string{}
+		}
+		rows = append(rows, []interface{}{a.NetworkID, a.DepositCnt, a.Kind, a.Level, a.Message, fields, a.Time})
+	}
+	columns := []string{"network_id", "deposit_cnt", "kind", "level", "message", "fields", "created_at"}
+	_, err := p.getExecQuerier(dbTx).CopyFrom(ctx, pgx.Identifier{"sync", "deposit_activity"}, columns, pgx.CopyFromRows(rows))
+	return err
+}
+
+// GetDepositActivities gets the last recorded entries of the activity log of a deposit, up to the limit, returned in
+// order. A non empty q only gets the entries whose message or a field, as key=value, contains it, ignoring the case.
+func (p *PostgresStorage) GetDepositActivities(ctx context.Context, networkID, depositCnt uint, q string, limit uint, dbTx pgx.Tx) ([]etherman.DepositActivity, error) {
+	const getDepositActivitiesSQL = `SELECT network_id, deposit_cnt, kind, level, message, fields, created_at
+		FROM sync.deposit_activity WHERE network_id = $1 AND deposit_cnt = $2
+			AND ($3 = '' OR strpos(lower(message), lower($3)) > 0
+				OR EXISTS (SELECT 1 FROM jsonb_each_text(fields) AS f WHERE strpos(lower(f.key || '=' || f.value), lower($3)) > 0))
+		ORDER BY id DESC LIMIT $4`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getDepositActivitiesSQL, networkID, depositCnt, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []etherman.DepositActivity
+	for rows.Next() {
+		var a etherman.DepositActivity
+		if err := rows.Scan(&a.NetworkID, &a.DepositCnt, &a.Kind, &a.Level, &a.Message, &a.Fields, &a.Time); err != nil {
+			return nil, err
+		}
+		entries = append(entries, a)
+	}
+	// The entries are read from the last one
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, rows.Err()
+}
+
+// DeleteDepositActivities deletes the entries of the activity log of the deposits created before a time, returning
+// the number deleted.
+func (p *PostgresStorage) DeleteDepositActivities(ctx context.Context, before time.Time, dbTx pgx.Tx) (int64, error) {
+	const deleteDepositActivitiesSQL = "DELETE FROM sync.deposit_activity WHERE created_at < $1"
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, deleteDepositActivitiesSQL, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+// GetStuckDeposits gets the unclaimed deposits, ready for claim or not, that have had that status since before
+// l1Before for the L1 deposits and l2Before for the L2 ones, the oldest first. A zero time skips the network. The
+// deposits ready for claim are only the L1 ones, the L2 deposits are claimed by their users, not by the claim tx
+// manager. The deposits whose claim tx is denied are not stuck, they are never claimed.
+func (p *PostgresStorage) GetStuckDeposits(ctx context.Context, readyForClaim bool, l1Before, l2Before time.Time, limit uint, dbTx pgx.Tx) ([]*etherman.StuckDeposit, error) {
+	const getStuckDepositsSQL = `SELECT ` + depositColumns + `, COALESCE(h.changed_at, b.received_at) AS since, COALESCE(m.status, '')
+		FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.network_id = b.network_id AND d.block_id = b.id
+		LEFT JOIN sync.deposit_status_history AS h ON h.deposit_id = d.id AND h.status = 'ready_for_claim' AND d.ready_for_claim
+		LEFT JOIN sync.monitored_txs AS m ON d.network_id = 0 AND m.deposit_id = d.deposit_cnt
+		WHERE d.ready_for_claim = $1 AND (NOT d.ready_for_claim OR d.network_id = 0)
+			AND COALESCE(h.changed_at, b.received_at) < CASE WHEN d.network_id = 0 THEN $2 ELSE $3 END
+			AND NOT EXISTS (SELECT 1 FROM sync.claim AS c WHERE c.index = d.deposit_cnt AND c.network_id = d.dest_net)
+			AND COALESCE(m.status, '') <> $5
+		ORDER BY since LIMIT $4`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getStuckDepositsSQL, readyForClaim, l1Before, l2Before, limit, ctmtypes.MonitoredTxStatusDenied)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var deposits []*etherman.StuckDeposit
+	for rows.Next() {
+		var (
+			deposit etherman.StuckDeposit
+			amount  string
+		)
+		dest := append(depositScanDest(&deposit.Deposit, &amount), &deposit.Since, &deposit.ClaimTxStatus)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, &deposit)
+	}
+	return deposits, rows.Err()
+}
+
+// GetTokenFlows gets, for each token claimed in a network, the amount deposited to the network and the amount
+// claimed in blocks until claimedUntil, ordered by network and token.
+func (p *PostgresStorage) GetTokenFlows(ctx context.Context, claimedUntil time.Time, dbTx pgx.Tx) ([]*etherman.TokenFlow, error) {
+	const getTokenFlowsSQL = `WITH deposited AS (
+			SELECT dest_net AS network_id, orig_net, orig_addr, SUM(amount::NUMERIC) AS amount
+			FROM sync.deposit GROUP BY dest_net, orig_net, orig_addr
+		), claimed AS (
+			SELECT c.network_id, c.orig_net, c.orig_addr, SUM(c.amount::NUMERIC) AS amount
+			FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id
+			WHERE b.received_at <= $1 GROUP BY c.network_id, c.orig_net, c.orig_addr
+		)
+		SELECT c.network_id, c.orig_net, c.orig_addr, COALESCE(d.amount, 0)::TEXT, c.amount::TEXT
+		FROM claimed AS c LEFT JOIN deposited AS d
+			ON d.network_id = c.network_id AND d.orig_net = c.orig_net AND d.orig_addr = c.orig_addr
+		ORDER BY c.network_id, c.orig_net, c.orig_addr`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getTokenFlowsSQL, claimedUntil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var flows []*etherman.TokenFlow
+	for rows.Next() {
+		var (
+			flow               etherman.TokenFlow
+			deposited, claimed string
+		)
+		if err := rows.Scan(&flow.NetworkID, &flow.OriginalNetwork, &flow.OriginalAddress, &deposited, &claimed); err != nil {
+			return nil, err
+		}
+		if flow.Deposited, err = uint256.ParseSum(deposited); err != nil {
+			return nil, err
+		}
+		if flow.Claimed, err = uint256.ParseSum(claimed); err != nil {
+			return nil, err
+		}
+		flows = append(flows, &flow)
+	}
+	return flows, rows.Err()
+}
+
+// GetClaimableDeposits gets the deposits to a network that are ready for claim and not claimed, the oldest first.
+// An empty destAddr matches all the addresses, and an empty origAddr all the tokens.
+func (p *PostgresStorage) GetClaimableDeposits(ctx context.Context, destNet uint, destAddr string, origNet uint, origAddr string, limit, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	const getClaimableDepositsSQL = `SELECT ` + depositColumns + ` FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.network_id = b.network_id AND d.block_id = b.id
+		WHERE d.dest_net = $1 AND d.ready_for_claim
+			AND (octet_length($2::BYTEA) = 0 OR d.dest_addr = $2)
+			AND (octet_length($4::BYTEA) = 0 OR (d.orig_net = $3 AND d.orig_addr = $4))
+			AND NOT EXISTS (SELECT 1 FROM sync.claim AS c WHERE c.index = d.deposit_cnt AND c.network_id = d.dest_net)
+		ORDER BY d.network_id, d.deposit_cnt LIMIT $5 OFFSET $6`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getClaimableDepositsSQL, destNet, common.FromHex(destAddr), origNet, common.FromHex(origAddr), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var deposits []*etherman.Deposit
+	for rows.Next() {
+		var (
+			deposit etherman.Deposit
+			amount  string
+		)
+		if err := rows.Scan(depositScanDest(&deposit, &amount)...); err != nil {
+			return nil, err
+		}
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, &deposit)
+	}
+	return deposits, rows.Err()
+}
+
+// SetDepositAnnotation sets the note of a wallet on a deposit. An empty note removes it.
+func (p *PostgresStorage) SetDepositAnnotation(ctx context.Context, annotation *etherman.DepositAnnotation, dbTx pgx.Tx) error {
+	e := p.getExecQuerier(dbTx)
+	if annotation.Note == "" {
+		const deleteDepositAnnotationSQL = "DELETE FROM sync.deposit_annotation WHERE address = $1 AND network_id = $2 AND deposit_cnt = $3"
+		_, err := e.Exec(ctx, deleteDepositAnnotationSQL, annotation.Address, annotation.NetworkID, annotation.DepositCnt)
+		return err
+	}
+	const setDepositAnnotationSQL = `INSERT INTO sync.deposit_annotation (network_id, deposit_cnt, address, note, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (address, network_id, deposit_cnt) DO UPDATE SET note = EXCLUDED.note, updated_at = EXCLUDED.updated_at`
+	_, err := e.Exec(ctx, setDepositAnnotationSQL, annotation.NetworkID, annotation.DepositCnt, annotation.Address, annotation.Note, annotation.UpdatedAt)
+	return err
+}
+
+// GetDepositAnnotations gets the notes of a wallet on its deposits.
+func (p *PostgresStorage) GetDepositAnnotations(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.DepositAnnotation, error) {
+	const getDepositAnnotationsSQL = "SELECT network_id, deposit_cnt, address, note, updated_at FROM sync.deposit_annotation WHERE address = $1"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getDepositAnnotationsSQL, address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var annotations []etherman.DepositAnnotation
+	for rows.Next() {
+		var a etherman.DepositAnnotation
+		if err := rows.Scan(&a.NetworkID, &a.DepositCnt, &a.Address, &a.Note, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, rows.Err()
+}
+
+// SetDepositRef sets the external reference id of a deposit. An empty id removes it. It returns
+// ErrAlreadyProcessed if the address set the id on another deposit.
+func (p *PostgresStorage) SetDepositRef(ctx context.Context, ref *etherman.DepositRef, dbTx pgx.Tx) error {
+	e := p.getExecQuerier(dbTx)
+	if ref.RefID == "" {
+		const deleteDepositRefSQL = "DELETE FROM sync.deposit_ref WHERE network_id = $1 AND deposit_cnt = $2"
+		_, err := e.Exec(ctx, deleteDepositRefSQL, ref.NetworkID, ref.DepositCnt)
+		return err
+	}
+	const setDepositRefSQL = `INSERT INTO sync.deposit_ref (network_id, deposit_cnt, address, ref_id, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (network_id, deposit_cnt) DO UPDATE SET address = EXCLUDED.address, ref_id = EXCLUDED.ref_id, updated_at = EXCLUDED.updated_at`
+	_, err := e.Exec(ctx, setDepositRefSQL, ref.NetworkID, ref.DepositCnt, ref.Address, ref.RefID, ref.UpdatedAt)
+	return wrapInsertError(err)
+}
+
+// GetDepositRef gets the external reference id of a deposit.
+func (p *PostgresStorage) GetDepositRef(ctx context.Context, networkID, depositCnt uint, dbTx pgx.Tx) (*etherman.DepositRef, error) {
+	const getDepositRefSQL = "SELECT network_id, deposit_cnt, address, ref_id, updated_at FROM sync.deposit_ref WHERE network_id = $1 AND deposit_cnt = $2"
+	var ref etherman.DepositRef
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getDepositRefSQL, networkID, depositCnt).Scan(&ref.NetworkID, &ref.DepositCnt, &ref.Address, &ref.RefID, &ref.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ref, nil
+}
+
+// GetDepositRefs gets the external reference ids set by an address on its deposits.
+func (p *PostgresStorage) GetDepositRefs(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.DepositRef, error) {
+	const getDepositRefsSQL = "SELECT network_id, deposit_cnt, address, ref_id, updated_at FROM sync.deposit_ref WHERE address = $1"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getDepositRefsSQL, address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var refs []etherman.DepositRef
+	for rows.Next() {
+		var ref etherman.DepositRef
+		if err := rows.Scan(&ref.NetworkID, &ref.DepositCnt, &ref.Address, &ref.RefID, &ref.UpdatedAt); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
+// AddWalletWebhook adds a webhook of a wallet, setting its id.
+func (p *PostgresStorage) AddWalletWebhook(ctx context.Context, webhook *etherman.WalletWebhook, dbTx pgx.Tx) error {
+	const addWalletWebhookSQL = "INSERT INTO sync.wallet_webhook (address, url, secret, event_types, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	return p.getExecQuerier(dbTx).QueryRow(ctx, addWalletWebhookSQL, webhook.Address, webhook.URL, webhook.Secret, pq.Array(webhook.EventTypes), webhook.CreatedAt).Scan(&webhook.ID)
+}
+
+// GetWalletWebhooks gets the webhooks of a wallet.
+func (p *PostgresStorage) GetWalletWebhooks(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.WalletWebhook, error) {
+	const getWalletWebhooksSQL = "SELECT id, address, url, secret, event_types, created_at FROM sync.wallet_webhook WHERE address = $1 ORDER BY id"
+	return p.getWalletWebhooks(ctx, getWalletWebhooksSQL, dbTx, address)
+}
+
+// GetAllWalletWebhooks gets the webhooks of all the wallets.
+func (p *PostgresStorage) GetAllWalletWebhooks(ctx context.Context, dbTx pgx.Tx) ([]etherman.WalletWebhook, error) {
+	const getAllWalletWebhooksSQL = "SELECT id, address, url, secret, event_types, created_at FROM sync.wallet_webhook ORDER BY id"
+	return p.getWalletWebhooks(ctx, getAllWalletWebhooksSQL, dbTx)
+}
+
+func (p *PostgresStorage) getWalletWebhooks(ctx context.Context, sql string, dbTx pgx.Tx, args ...interface{}) ([]etherman.WalletWebhook, error) {
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	webhooks := make([]etherman.WalletWebhook, 0)
+	for rows.Next() {
+		var w etherman.WalletWebhook
+		if err := rows.Scan(&w.ID, &w.Address, &w.URL, &w.Secret, pq.Array(&w.EventTypes), &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// DeleteWalletWebhook deletes a webhook of a wallet.
+func (p *PostgresStorage) DeleteWalletWebhook(ctx context.Context, id uint64, address common.Address, dbTx pgx.Tx) error {
+	const deleteWalletWebhookSQL = "DELETE FROM sync.wallet_webhook WHERE id = $1 AND address = $2"
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, deleteWalletWebhookSQL, id, address)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrStorageNotFound
+	}
+	return nil
+}
+
+// SpillWebhookDelivery stores a delivery while the queue of the webhook dispatcher is full, setting its id.
+func (p *PostgresStorage) SpillWebhookDelivery(ctx context.Context, delivery *etherman.WebhookDelivery, dbTx pgx.Tx) error {
+	const spillWebhookDeliverySQL = "INSERT INTO sync.webhook_spill (subscription, url, event_type, body, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	return p.getExecQuerier(dbTx).QueryRow(ctx, spillWebhookDeliverySQL, delivery.Subscription, delivery.URL, delivery.EventType, delivery.Body, delivery.CreatedAt).Scan(&delivery.ID)
+}
+
+// UnspillWebhookDeliveries removes and returns the oldest stored webhook deliveries, at most limit.
+func (p *PostgresStorage) UnspillWebhookDeliveries(ctx context.Context, limit uint, dbTx pgx.Tx) ([]etherman.WebhookDelivery, error) {
+	const unspillWebhookDeliveriesSQL = `WITH unspilled AS (
+		DELETE FROM sync.webhook_spill WHERE id IN (SELECT id FROM sync.webhook_spill ORDER BY id LIMIT $1)
+		RETURNING id, subscription, url, event_type, body, created_at
+	) SELECT id, subscription, url, event_type, body, created_at FROM unspilled ORDER BY id`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, unspillWebhookDeliveriesSQL, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var deliveries []etherman.WebhookDelivery
+	for rows.Next() {
+		var d etherman.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.Subscription, &d.URL, &d.EventType, &d.Body, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// AddProofConsumer adds a consumer of the restricted proofs, replacing the key of the consumer with the same name.
+func (p *PostgresStorage) AddProofConsumer(ctx context.Context, consumer *etherman.ProofConsumer, dbTx pgx.Tx) error {
+	const addProofConsumerSQL = `INSERT INTO sync.proof_consumer (name, key_hash, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET key_hash = EXCLUDED.key_hash, created_at = EXCLUDED.created_at`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addProofConsumerSQL, consumer.Name, consumer.KeyHash, consumer.CreatedAt)
+	return err
+}
+
+// GetProofConsumers gets the consumers of the restricted proofs.
+func (p *PostgresStorage) GetProofConsumers(ctx context.Context, dbTx pgx.Tx) ([]etherman.ProofConsumer, error) {
+	const getProofConsumersSQL = "SELECT name, key_hash, created_at FROM sync.proof_consumer ORDER BY name"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getProofConsumersSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	consumers := make([]etherman.ProofConsumer, 0)
+	for rows.Next() {
+		var c etherman.ProofConsumer
+		if err := rows.Scan(&c.Name, &c.KeyHash, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		consumers = append(consumers, c)
+	}
+	return consumers, rows.Err()
+}
+
+// DeleteProofConsumer deletes a consumer of the restricted proofs.
+func (p *PostgresStorage) DeleteProofConsumer(ctx context.Context, name string, dbTx pgx.Tx) error {
+	const deleteProofConsumerSQL = "DELETE FROM sync.proof_consumer WHERE name = $1"
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, deleteProofConsumerSQL, name)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrStorageNotFound
+	}
+	return nil
+}
+
+// GetProofAccess gets the mode of the proofs set by the operators.
+func (p *PostgresStorage) GetProofAccess(ctx context.Context, dbTx pgx.Tx) (*etherman.ProofAccess, error) {
+	const getProofAccessSQL = "SELECT restricted, reason, updated_at FROM sync.proof_access"
+	var access etherman.ProofAccess
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getProofAccessSQL).Scan(&access.Restricted, &access.Reason, &access.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &access, err
+}
+
+// SetProofAccess sets the mode of the proofs.
+func (p *PostgresStorage) SetProofAccess(ctx context.Context, access *etherman.ProofAccess, dbTx pgx.Tx) error {
+	const setProofAccessSQL = `INSERT INTO sync.proof_access (restricted, reason, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET restricted = EXCLUDED.restricted, reason = EXCLUDED.reason, updated_at = EXCLUDED.updated_at`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, setProofAccessSQL, access.Restricted, access.Reason, access.UpdatedAt)
+	return err
+}
+
+// GetMaintenance gets the maintenance set by the operators.
+func (p *PostgresStorage) GetMaintenance(ctx context.Context, dbTx pgx.Tx) (*etherman.Maintenance, error) {
+	const getMaintenanceSQL = "SELECT enabled, message, eta, updated_at FROM sync.maintenance"
+	var maintenance etherman.Maintenance
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getMaintenanceSQL).Scan(&maintenance.Enabled, &maintenance.Message, &maintenance.ETA, &maintenance.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &maintenance, err
+}
+
+// SetMaintenance sets the maintenance.
+func (p *PostgresStorage) SetMaintenance(ctx context.Context, maintenance *etherman.Maintenance, dbTx pgx.Tx) error {
+	const setMaintenanceSQL = `INSERT INTO sync.maintenance (enabled, message, eta, updated_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET enabled = EXCLUDED.enabled, message = EXCLUDED.message, eta = EXCLUDED.eta, updated_at = EXCLUDED.updated_at`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, setMaintenanceSQL, maintenance.Enabled, maintenance.Message, maintenance.ETA, maintenance.UpdatedAt)
+	return err
+}
+
+// GetTokenList gets the tokens in the allow and deny lists.
+func (p *PostgresStorage) GetTokenList(ctx context.Context, dbTx pgx.Tx) ([]etherman.TokenListEntry, error) {
+	const getTokenListSQL = "SELECT orig_net, orig_addr, list, reason, operator, updated_at FROM sync.token_list ORDER BY orig_net, orig_addr"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getTokenListSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := make([]etherman.TokenListEntry, 0)
+	for rows.Next() {
+		var e etherman.TokenListEntry
+		if err := rows.Scan(&e.OriginalNetwork, &e.OriginalAddress, &e.List, &e.Reason, &e.Operator, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetTokenListEntry gets the list of a token.
+func (p *PostgresStorage) GetTokenListEntry(ctx context.Context, originalNetwork uint, originalAddress common.Address, dbTx pgx.Tx) (*etherman.TokenListEntry, error) {
+	const getTokenListEntrySQL = "SELECT orig_net, orig_addr, list, reason, operator, updated_at FROM sync.token_list WHERE orig_net = $1 AND orig_addr = $2"
+	var e etherman.TokenListEntry
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getTokenListEntrySQL, originalNetwork, originalAddress).
+		Scan(&e.OriginalNetwork, &e.OriginalAddress, &e.List, &e.Reason, &e.Operator, &e.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &e, err
+}
+
+// SetTokenListEntry adds a token to its list, replacing its previous list, and the change to the audit log.
+func (p *PostgresStorage) SetTokenListEntry(ctx context.Context, entry *etherman.TokenListEntry, dbTx pgx.Tx) error {
+	const setTokenListEntrySQL = `WITH audit AS (
+			INSERT INTO sync.token_list_audit (action, orig_net, orig_addr, list, reason, operator, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		)
+		INSERT INTO sync.token_list (orig_net, orig_addr, list, reason, operator, updated_at) VALUES ($2, $3, $4, $5, $6, $7)
+		ON CONFLICT (orig_net, orig_addr) DO UPDATE SET list = EXCLUDED.list, reason = EXCLUDED.reason, operator = EXCLUDED.operator, updated_at = EXCLUDED.updated_at`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, setTokenListEntrySQL, etherman.TokenListActionSet, entry.OriginalNetwork, entry.OriginalAddress,
+		entry.List, entry.Reason, entry.Operator, entry.UpdatedAt)
+	return err
+}
+
+// DeleteTokenListEntry removes a token from its list, adding the change to the audit log.
+func (p *PostgresStorage) DeleteTokenListEntry(ctx context.Context, change *etherman.TokenListChange, dbTx pgx.Tx) error {
+	const deleteTokenListEntrySQL = `WITH deleted AS (
+			DELETE FROM sync.token_list WHERE orig_net = $2 AND orig_addr = $3 RETURNING list
+		)
+		INSERT INTO sync.token_list_audit (action, orig_net, orig_addr, list, reason, operator, created_at)
+		SELECT $1, $2, $3, list, $4, $5, $6 FROM deleted`
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, deleteTokenListEntrySQL, etherman.TokenListActionDelete, change.OriginalNetwork, change.OriginalAddress,
+		change.Reason, change.Operator, change.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrStorageNotFound
+	}
+	return nil
+}
+
+// GetTokenListChanges gets the changes of the token lists, the last first.
+func (p *PostgresStorage) GetTokenListChanges(ctx context.Context, limit, offset uint, dbTx pgx.Tx) ([]etherman.TokenListChange, error) {
+	const getTokenListChangesSQL = `SELECT id, action, orig_net, orig_addr, list, reason, operator, created_at FROM sync.token_list_audit
+		ORDER BY id DESC LIMIT $1 OFFSET $2`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getTokenListChangesSQL, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	changes := make([]etherman.TokenListChange, 0)
+	for rows.Next() {
+		var c etherman.TokenListChange
+		if err := rows.Scan(&c.ID, &c.Action, &c.OriginalNetwork, &c.OriginalAddress, &c.List, &c.Reason, &c.Operator, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// AddConfigChange adds a proposed change of the runtime settings, setting its id.
+func (p *PostgresStorage) AddConfigChange(ctx context.Context, change *etherman.ConfigChange, dbTx pgx.Tx) error {
+	const addConfigChangeSQL = `INSERT INTO sync.config_change (method, request, reason, proposer, status, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	return p.getExecQuerier(dbTx).QueryRow(ctx, addConfigChangeSQL, change.Method, change.Request, change.Reason, change.Proposer,
+		change.Status, change.CreatedAt, change.ExpiresAt).Scan(&change.ID)
+}
+
+const configChangeColumns = "id, method, request, reason, proposer, status, created_at, expires_at, reviewer, reviewed_at, error"
+
+func scanConfigChange(row pgx.Row) (*etherman.ConfigChange, error) {
+	var c etherman.ConfigChange
+	err := row.Scan(&c.ID, &c.Method, &c.Request, &c.Reason, &c.Proposer, &c.Status, &c.CreatedAt, &c.ExpiresAt,
+		&c.Reviewer, &c.ReviewedAt, &c.Error)
+	return &c, err
+}
+
+// GetConfigChange gets a proposed change of the runtime settings.
+func (p *PostgresStorage) GetConfigChange(ctx context.Context, id uint64, dbTx pgx.Tx) (*etherman.ConfigChange, error) {
+	getConfigChangeSQL := "SELECT " + configChangeColumns + " FROM sync.config_change WHERE id = $1"
+	change, err := scanConfigChange(p.getExecQuerier(dbTx).QueryRow(ctx, getConfigChangeSQL, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return change, err
+}
+
+// GetConfigChanges gets the proposed changes of the runtime settings with a status, or all of them if it's empty,
+// the last first. The pending changes not reviewed before now are expired.
+func (p *PostgresStorage) GetConfigChanges(ctx context.Context, status string, now time.Time, limit, offset uint, dbTx pgx.Tx) ([]etherman.ConfigChange, error) {
+	getConfigChangesSQL := `SELECT ` + configChangeColumns + ` FROM (
+			SELECT id, method, request, reason, proposer,
+				CASE WHEN status = $1 AND expires_at <= $2 THEN $3 ELSE status END AS status,
+				created_at, expires_at, reviewer, reviewed_at, error
+			FROM sync.config_change
+		) c WHERE $4 = '' OR status = $4 ORDER BY id DESC LIMIT $5 OFFSET $6`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getConfigChangesSQL, etherman.ConfigChangePending, now, etherman.ConfigChangeExpired,
+		status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	changes := make([]etherman.ConfigChange, 0)
+	for rows.Next() {
+		change, err := scanConfigChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+	return changes, rows.Err()
+}
+
+// ReviewConfigChange approves or rejects a pending change of the runtime settings that has not expired. The
+// changes are only approved by another admin than their proposer. It returns ErrAlreadyProcessed if the change is
+// not pending anymore, e.g. when it's reviewed at the same time through another instance.
+func (p *PostgresStorage) ReviewConfigChange(ctx context.Context, id uint64, status, reviewer string, reviewedAt time.Time, dbTx pgx.Tx) error {
+	const reviewConfigChangeSQL = `UPDATE sync.config_change SET status = $2, reviewer = $3, reviewed_at = $4
+		WHERE id = $1 AND status = $5 AND expires_at > $4 AND ($2 = $6 OR proposer <> $3)`
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, reviewConfigChangeSQL, id, status, reviewer, reviewedAt, etherman.ConfigChangePending,
+		etherman.ConfigChangeRejected)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrAlreadyProcessed
+	}
+	return nil
+}
+
+// SetConfigChangeResult sets the status of an approved change of the runtime settings once it's applied, with the
+// error if it failed.
+func (p *PostgresStorage) SetConfigChangeResult(ctx context.Context, id uint64, status, errMsg string, dbTx pgx.Tx) error {
+	const setConfigChangeResultSQL = "UPDATE sync.config_change SET status = $2, error = $3 WHERE id = $1"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, setConfigChangeResultSQL, id, status, errMsg)
+	return err
+}
+
+// GetSigningKeys gets the activations of the operator keys that signed the responses of the API, in order.
+func (p *PostgresStorage) GetSigningKeys(ctx context.Context, dbTx pgx.Tx) ([]etherman.SigningKey, error) {
+	const getSigningKeysSQL = "SELECT address, activated_at, retired_at FROM sync.signing_key ORDER BY id"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getSigningKeysSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make([]etherman.SigningKey, 0)
+	for rows.Next() {
+		var k etherman.SigningKey
+		if err := rows.Scan(&k.Address, &k.ActivatedAt, &k.RetiredAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// RetireSigningKey retires the key signing the responses of the API.
+func (p *PostgresStorage) RetireSigningKey(ctx context.Context, retiredAt time.Time, dbTx pgx.Tx) error {
+	const retireSigningKeySQL = "UPDATE sync.signing_key SET retired_at = $1 WHERE retired_at IS NULL"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, retireSigningKeySQL, retiredAt)
+	return err
+}
+
+// AddSigningKey activates a key signing the responses of the API. The active key must be retired first.
+func (p *PostgresStorage) AddSigningKey(ctx context.Context, address common.Address, activatedAt time.Time, dbTx pgx.Tx) error {
+	const addSigningKeySQL = "INSERT INTO sync.signing_key (address, activated_at) VALUES ($1, $2)"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addSigningKeySQL, address, activatedAt)
+	return err
+}
+
+// AddIdempotencyKey adds the idempotency key of a request. It returns ErrAlreadyProcessed if the key of the method
+// was already added.
+func (p *PostgresStorage) AddIdempotencyKey(ctx context.Context, key *etherman.IdempotencyKey, dbTx pgx.Tx) error {
+	const addIdempotencyKeySQL = `INSERT INTO sync.idempotency_key (method, key, request_hash, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (method, key) DO NOTHING`
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, addIdempotencyKeySQL, key.Method, key.Key, key.RequestHash, key.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrAlreadyProcessed
+	}
+	return nil
+}
+
+// GetIdempotencyKey gets the idempotency key of a method, with the response of its request if it's done.
+func (p *PostgresStorage) GetIdempotencyKey(ctx context.Context, method, key string, dbTx pgx.Tx) (*etherman.IdempotencyKey, error) {
+	const getIdempotencyKeySQL = "SELECT method, key, request_hash, response, created_at FROM sync.idempotency_key WHERE method = $1 AND key = $2"
+	var k etherman.IdempotencyKey
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getIdempotencyKeySQL, method, key).Scan(&k.Method, &k.Key, &k.RequestHash, &k.Response, &k.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &k, err
+}
+
+// SetIdempotencyResponse stores the response of the request of an idempotency key.
+func (p *PostgresStorage) SetIdempotencyResponse(ctx context.Context, method, key string, response []byte, dbTx pgx.Tx) error {
+	const setIdempotencyResponseSQL = "UPDATE sync.idempotency_key SET response = $3 WHERE method = $1 AND key = $2"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, setIdempotencyResponseSQL, method, key, response)
+	return err
+}
+
+// DeleteIdempotencyKey deletes the idempotency key of a method, so the request can be retried with it.
+func (p *PostgresStorage) DeleteIdempotencyKey(ctx context.Context, method, key string, dbTx pgx.Tx) error {
+	const deleteIdempotencyKeySQL = "DELETE FROM sync.idempotency_key WHERE method = $1 AND key = $2"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, deleteIdempotencyKeySQL, method, key)
+	return err
+}
+
+// DeleteIdempotencyKeys deletes the idempotency keys added before the time.
+func (p *PostgresStorage) DeleteIdempotencyKeys(ctx context.Context, before time.Time, dbTx pgx.Tx) error {
+	const deleteIdempotencyKeysSQL = "DELETE FROM sync.idempotency_key WHERE created_at < $1"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, deleteIdempotencyKeysSQL, before)
+	return err
+}
+
+// AddPushDevice registers a device of a wallet. A device token registered by another wallet moves to this one.
+func (p *PostgresStorage) AddPushDevice(ctx context.Context, device *etherman.PushDevice, dbTx pgx.Tx) error {
+	const addPushDeviceSQL = `INSERT INTO sync.push_device (platform, token, address, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (platform, token) DO UPDATE SET address = EXCLUDED.address, created_at = EXCLUDED.created_at`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addPushDeviceSQL, device.Platform, device.Token, device.Address, device.CreatedAt)
+	return err
+}
+
+// GetPushDevices gets the devices of a wallet.
+func (p *PostgresStorage) GetPushDevices(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.PushDevice, error) {
+	const getPushDevicesSQL = "SELECT address, platform, token, created_at FROM sync.push_device WHERE address = $1 ORDER BY created_at, platform, token"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getPushDevicesSQL, address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	devices := make([]etherman.PushDevice, 0)
+	for rows.Next() {
+		var d etherman.PushDevice
+		if err := rows.Scan(&d.Address, &d.Platform, &d.Token, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+// DeletePushDevice deletes a device of a wallet.
+func (p *PostgresStorage) DeletePushDevice(ctx context.Context, address common.Address, platform, token string, dbTx pgx.Tx) error {
+	const deletePushDeviceSQL = "DELETE FROM sync.push_device WHERE platform = $1 AND token = $2 AND address = $3"
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, deletePushDeviceSQL, platform, token, address)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrStorageNotFound
+	}
+	return nil
+}
+
+// AddWatchedAddress adds an address watched by a wallet, nothing is done if the wallet already watches it.
+func (p *PostgresStorage) AddWatchedAddress(ctx context.Context, watch *etherman.WatchedAddress, dbTx pgx.Tx) error {
+	const addWatchedAddressSQL = `INSERT INTO sync.watched_address (owner, address, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (owner, address) DO NOTHING`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addWatchedAddressSQL, watch.Owner, watch.Address, watch.CreatedAt)
+	return err
+}
+
+// GetWatchedAddresses gets the addresses watched by a wallet.
+func (p *PostgresStorage) GetWatchedAddresses(ctx context.Context, owner common.Address, dbTx pgx.Tx) ([]etherman.WatchedAddress, error) {
+	const getWatchedAddressesSQL = `SELECT owner, address, created_at, backfill_requested_at, backfilled_at FROM sync.watched_address
+		WHERE owner = $1 ORDER BY created_at, address`
+	return p.getWatchedAddresses(ctx, getWatchedAddressesSQL, dbTx, owner)
+}
+
+// GetPendingWatchBackfills gets the backfills of the watched addresses requested and not done yet, the oldest first.
+func (p *PostgresStorage) GetPendingWatchBackfills(ctx context.Context, limit uint, dbTx pgx.Tx) ([]etherman.WatchedAddress, error) {
+	const getPendingWatchBackfillsSQL = `SELECT owner, address, created_at, backfill_requested_at, backfilled_at FROM sync.watched_address
+		WHERE backfill_requested_at IS NOT NULL AND backfilled_at IS NULL ORDER BY backfill_requested_at LIMIT $1`
+	return p.getWatchedAddresses(ctx, getPendingWatchBackfillsSQL, dbTx, limit)
+}
+
+func (p *PostgresStorage) getWatchedAddresses(ctx context.Context, sql string, dbTx pgx.Tx, args ...interface{}) ([]etherman.WatchedAddress, error) {
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	watches := make([]etherman.WatchedAddress, 0)
+	for rows.Next() {
+		var w etherman.WatchedAddress
+		if err := rows.Scan(&w.Owner, &w.Address, &w.CreatedAt, &w.BackfillRequestedAt, &w.BackfilledAt); err != nil {
+			return nil, err
+		}
+		watches = append(watches, w)
+	}
+	return watches, rows.Err()
+}
+
+// GetAllWatchedAddresses gets the addresses watched by any wallet, once each.
+func (p *PostgresStorage) GetAllWatchedAddresses(ctx context.Context, dbTx pgx.Tx) ([]common.Address, error) {
+	const getAllWatchedAddressesSQL = "SELECT DISTINCT address FROM sync.watched_address ORDER BY address"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getAllWatchedAddressesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	addresses := make([]common.Address, 0)
+	for rows.Next() {
+		var addr common.Address
+		if err := rows.Scan(&addr); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, addr)
+	}
+	return addresses, rows.Err()
+}
+
+// IsWatchedAddress checks if an address is watched by any wallet.
+func (p *PostgresStorage) IsWatchedAddress(ctx context.Context, address common.Address, dbTx pgx.Tx) (bool, error) {
+	const isWatchedAddressSQL = "SELECT EXISTS (SELECT 1 FROM sync.watched_address WHERE address = $1)"
+	var watched bool
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, isWatchedAddressSQL, address).Scan(&watched)
+	return watched, err
+}
+
+// DeleteWatchedAddress deletes an address watched by a wallet.
+func (p *PostgresStorage) DeleteWatchedAddress(ctx context.Context, owner, address common.Address, dbTx pgx.Tx) error {
+	const deleteWatchedAddressSQL = "DELETE FROM sync.watched_address WHERE owner = $1 AND address = $2"
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, deleteWatchedAddressSQL, owner, address)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrStorageNotFound
+	}
+	return nil
+}
+
+// RequestWatchBackfill requests the backfill of an address watched by a wallet, pending again if it was done.
+func (p *PostgresStorage) RequestWatchBackfill(ctx context.Context, owner, address common.Address, requestedAt time.Time, dbTx pgx.Tx) error {
+	const requestWatchBackfillSQL = `UPDATE sync.watched_address SET backfill_requested_at = $3, backfilled_at = NULL
+		WHERE owner = $1 AND address = $2`
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, requestWatchBackfillSQL, owner, address, requestedAt)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrStorageNotFound
+	}
+	return nil
+}
+
+// SetWatchBackfilled records the backfill of a watched address as done. Nothing is done if the backfill was requested
+// again after requestedAt, so the new request stays pending.
+func (p *PostgresStorage) SetWatchBackfilled(ctx context.Context, owner, address common.Address, requestedAt, backfilledAt time.Time, dbTx pgx.Tx) error {
+	const setWatchBackfilledSQL = `UPDATE sync.watched_address SET backfilled_at = $4
+		WHERE owner = $1 AND address = $2 AND backfill_requested_at = $3`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, setWatchBackfilledSQL, owner, address, requestedAt, backfilledAt)
+	return err
+}
+
+// AddBusEvent stores an event of the event bus in the db tx of its data, setting its id. The ids are handed out
+// when the events are stored and the consumers move past the last id they read, so the tx holds the lock of the
+// events until it ends: the events are committed in the order of their ids, and a consumer never skips an event
+// committed after a higher id.
+func (p *PostgresStorage) AddBusEvent(ctx context.Context, event *etherman.BusEvent, dbTx pgx.Tx) error {
+	if dbTx == nil {
+		return errors.New("the events of the event bus are stored in a db tx")
+	}
+	const lockBusEventsSQL = "SELECT pg_advisory_xact_lock($1)"
+	if _, err := dbTx.Exec(ctx, lockBusEventsSQL, busEventLockKey); err != nil {
+		return err
+	}
+	const addBusEventSQL = `INSERT INTO sync.bus_event (type, network_id, payload, created_at) VALUES ($1, $2, $3, $4)
+		RETURNING id`
+	return dbTx.QueryRow(ctx, addBusEventSQL, event.Type, event.NetworkID, event.Payload, event.CreatedAt).Scan(&event.ID)
+}
+
+// GetBusEvents gets the events of the event bus after an id with one of the types, in order.
+func (p *PostgresStorage) GetBusEvents(ctx context.Context, afterID uint64, types []string, limit uint, dbTx pgx.Tx) ([]etherman.BusEvent, error) {
+	const getBusEventsSQL = `SELECT id, type, network_id, payload, created_at FROM sync.bus_event
+		WHERE id > $1 AND type = ANY($2) ORDER BY id LIMIT $3`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getBusEventsSQL, afterID, types, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := make([]etherman.BusEvent, 0)
+	for rows.Next() {
+		var e etherman.BusEvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.NetworkID, &e.Payload, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// GetLastBusEventID gets the id of the last event of the event bus, 0 if there isn't any.
+func (p *PostgresStorage) GetLastBusEventID(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	const getLastBusEventIDSQL = "SELECT COALESCE(MAX(id), 0) FROM sync.bus_event"
+	var id uint64
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getLastBusEventIDSQL).Scan(&id)
+	return id, err
+}
+
+// DeleteBusEvents deletes the events of the event bus created before a time, returning the number deleted.
+func (p *PostgresStorage) DeleteBusEvents(ctx context.Context, before time.Time, dbTx pgx.Tx) (int64, error) {
+	const deleteBusEventsSQL = "DELETE FROM sync.bus_event WHERE created_at < $1"
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, deleteBusEventsSQL, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+// GetBusOffset gets the id of the last event handled by a consumer of the event bus.
+func (p *PostgresStorage) GetBusOffset(ctx context.Context, consumer string, dbTx pgx.Tx) (uint64, error) {
+	const getBusOffsetSQL = "SELECT event_id FROM sync.bus_offset WHERE consumer = $1"
+	var id uint64
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getBusOffsetSQL, consumer).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, gerror.ErrStorageNotFound
+	}
+	return id, err
+}
+
+// GetBusOffsets gets the positions of all the consumers of the event bus.
+func (p *PostgresStorage) GetBusOffsets(ctx context.Context, dbTx pgx.Tx) ([]etherman.BusOffset, error) {
+	const getBusOffsetsSQL = "SELECT consumer, event_id, updated_at FROM sync.bus_offset ORDER BY consumer"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getBusOffsetsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	offsets := make([]etherman.BusOffset, 0)
+	for rows.Next() {
+		var o etherman.BusOffset
+		if err := rows.Scan(&o.Consumer, &o.EventID, &o.UpdatedAt); err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, o)
+	}
+	return offsets, rows.Err()
+}
+
+// SetBusOffset sets the id of the last event handled by a consumer of the event bus.
+func (p *PostgresStorage) SetBusOffset(ctx context.Context, consumer string, eventID uint64, dbTx pgx.Tx) error {
+	const setBusOffsetSQL = `INSERT INTO sync.bus_offset (consumer, event_id, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (consumer) DO UPDATE SET event_id = EXCLUDED.event_id, updated_at = EXCLUDED.updated_at`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, setBusOffsetSQL, consumer, eventID, time.Now())
+	return err
+}
+
+// AdvanceBusOffset moves a consumer of the event bus from an event to a later one. The offset is not moved if it
+// was set meanwhile, e.g. by a replay, so the replays are not overwritten by the batch being handled.
+func (p *PostgresStorage) AdvanceBusOffset(ctx context.Context, consumer string, fromEventID, toEventID uint64, dbTx pgx.Tx) error {
+	const advanceBusOffsetSQL = "UPDATE sync.bus_offset SET event_id = $3, updated_at = $4 WHERE consumer = $1 AND event_id = $2"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, advanceBusOffsetSQL, consumer, fromEventID, toEventID, time.Now())
+	return err
+}
+
+// GetHistoryPoint gets the point of the history at a block of a network. The time of the point is the time
+// of the last synced block of the network until the block.
+func (p *PostgresStorage) GetHistoryPoint(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.HistoryPoint, error) {
+	const getHistoryPointSQL = "SELECT received_at FROM sync.block WHERE network_id = $1 AND block_num <= $2 ORDER BY block_num DESC LIMIT 1"
+	point := etherman.HistoryPoint{NetworkID: networkID, BlockNumber: blockNum}
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getHistoryPointSQL, networkID, blockNum).Scan(&point.Time)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &point, nil
+}
+
+// GetLocalExitRootAt gets the root of the exit tree of a network at a point of the history.
+func (p *PostgresStorage) GetLocalExitRootAt(ctx context.Context, networkID uint, point etherman.HistoryPoint, dbTx pgx.Tx) (common.Hash, error) {
+	const getLocalExitRootAtSQL = `SELECT r.root FROM mt.root AS r
+		INNER JOIN sync.deposit AS d ON d.id = r.deposit_id INNER JOIN sync.block AS b ON b.id = d.block_id
+		WHERE r.network = $1 AND CASE WHEN b.network_id = $2 THEN b.block_num <= $3 ELSE b.received_at <= $4 END
+		ORDER BY r.deposit_cnt DESC LIMIT 1`
+	var root common.Hash
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getLocalExitRootAtSQL, networkID, point.NetworkID, point.BlockNumber, point.Time).Scan(&root)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return common.Hash{}, gerror.ErrStorageNotFound
+	}
+	return root, err
+}
+
+// GetDepositCount gets the deposit count for the address in its role, of all the integrations when it's empty.
+func (p *PostgresStorage) GetDepositCount(ctx context.Context, addr string, role string, integration string, dbTx pgx.Tx) (uint64, error) {
+	column, err := depositAddressColumn(role)
+	if err != nil {
+		return 0, err
+	}
+	getDepositCountSQL := "SELECT COUNT(*) FROM sync.deposit WHERE " + column + " = $1 AND ($2 = '' OR integration = $2)"
+	var depositCount uint64
+	err = p.getExecQuerier(dbTx).QueryRow(ctx, getDepositCountSQL, common.FromHex(addr), integration).Scan(&depositCount)
+	return depositCount, err
+}
+
+// UpdateBlocksForTesting updates the hash of blocks.
+func (p *PostgresStorage) UpdateBlocksForTesting(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) error {
+	const updateBlocksSQL = "UPDATE sync.block SET block_hash = SUBSTRING(block_hash FROM 1 FOR LENGTH(block_hash)-1) || '\x61' WHERE network_id = $1 AND block_num >= $2"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, updateBlocksSQL, networkID, blockNum)
+	return err
+}
+
+// GetReadyL1Deposits gets the L1 deposits that the exit root makes ready for claim, the ones UpdateL1DepositsStatus
+// updates.
+func (p *PostgresStorage) GetReadyL1Deposits(ctx context.Context, exitRoot []byte, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	const getReadyL1DepositsSQL = `SELECT leaf_type, orig_net, orig_addr, amount, dest_net, dest_addr, deposit_cnt, block_id, network_id, tx_hash, metadata, ready_for_claim
+		FROM sync.deposit
+		WHERE deposit_cnt <=
+			(SELECT sync.deposit.deposit_cnt FROM mt.root INNER JOIN sync.deposit ON sync.deposit.id = mt.root.deposit_id WHERE mt.root.root = $1 AND mt.root.network = 0)
+			AND network_id = 0 AND ready_for_claim = false ORDER BY deposit_cnt`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getReadyL1DepositsSQL, exitRoot)
+	if err != nil {
+		return nil, err
+	}
+	return scanUpdatedDeposits(rows)
+}
+
+// UpdateL1DepositsStatus updates the ready_for_claim status of L1 deposits.
+func (p *PostgresStorage) UpdateL1DepositsStatus(ctx context.Context, exitRoot []byte, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	// The L1 deposits are ready when the trusted global exit root is synced, which has no block
+	const updateDepositsStatusSQL = `WITH updated AS (UPDATE sync.deposit SET ready_for_claim = true 
+		WHERE deposit_cnt <=
+			(SELECT sync.deposit.deposit_cnt FROM mt.root INNER JOIN sync.deposit ON sync.deposit.id = mt.root.deposit_id WHERE mt.root.root = $1 AND mt.root.network = 0) 
+			AND network_id = 0 AND ready_for_claim = false
+			RETURNING id, leaf_type, orig_net, orig_addr, amount, dest_net, dest_addr, deposit_cnt, block_id, network_id, tx_hash, metadata, ready_for_claim),
+		history AS (INSERT INTO sync.deposit_status_history (deposit_id, status, changed_at) SELECT id, 'ready_for_claim', NOW() FROM updated ON CONFLICT DO NOTHING)
+		SELECT leaf_type, orig_net, orig_addr, amount, dest_net, dest_addr, deposit_cnt, block_id, network_id, tx_hash, metadata, ready_for_claim FROM updated;`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, updateDepositsStatusSQL, exitRoot)
+	if err != nil {
+		return nil, err
+	}
+	return scanUpdatedDeposits(rows)
+}
+
+// scanUpdatedDeposits scans the deposits whose ready_for_claim status was updated.
+func scanUpdatedDeposits(rows pgx.Rows) ([]*etherman.Deposit, error) {
+	defer rows.Close()
+	deposits := make([]*etherman.Deposit, 0, len(rows.RawValues()))
+	for rows.Next() {
+		var (
+			deposit etherman.Deposit
+			amount  string
+		)
+		err := rows.Scan(&deposit.LeafType, &deposit.OriginalNetwork, &deposit.OriginalAddress, &amount, &deposit.DestinationNetwork, &deposit.DestinationAddress, &deposit.DepositCount, &deposit.BlockID, &deposit.NetworkID, &deposit.TxHash, &deposit.Metadata, &deposit.ReadyForClaim)
+		if err != nil {
+			return nil, err
+		}
+		if deposit.Amount, err = uint256.Parse(amount); err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, &deposit)
+	}
+	return deposits, rows.Err()
+}
+
+// UpdateL2DepositsStatus updates the ready_for_claim status of L2 deposits and returns the updated deposits.
+func (p *PostgresStorage) UpdateL2DepositsStatus(ctx context.Context, exitRoot []byte, networkID uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	// The L2 deposits are ready when the L1 global exit root with their exit root is synced. With the multi rollup
+	// contracts, the exit root of the rollup is its leaf in the rollup exit tree of the rollup exit root
+	const updateDepositsStatusSQL = `WITH local_exit_root AS (SELECT COALESCE(
+			(SELECT leaf FROM mt.rollup_exit WHERE rollup_id = $2 AND id <= (SELECT MAX(id) FROM mt.rollup_exit WHERE root = $1) ORDER BY id DESC LIMIT 1),
+			$1) AS root),
+		updated AS (UPDATE sync.deposit SET ready_for_claim = true
+		WHERE deposit_cnt <=
+			(SELECT sync.deposit.deposit_cnt FROM mt.root INNER JOIN sync.deposit ON sync.deposit.id = mt.root.deposit_id WHERE mt.root.root = (SELECT root FROM local_exit_root) AND mt.root.network = $2)
+			AND network_id = $2 AND ready_for_claim = false
+			RETURNING id, leaf_type, orig_net, orig_addr, amount, dest_net, dest_addr, deposit_cnt, block_id, network_id, tx_hash, metadata, ready_for_claim),
+		ger_block AS (SELECT b.id, b.received_at FROM sync.exit_root AS e INNER JOIN sync.block AS b ON b.id = e.block_id
+			WHERE e.exit_roots[2] = $1 AND e.block_id > 0 ORDER BY e.id LIMIT 1),
+		history AS (INSERT INTO sync.deposit_status_history (deposit_id, status, block_id, changed_at)
+		SELECT u.id, 'ready_for_claim', g.id, COALESCE(g.received_at, NOW()) FROM updated AS u LEFT JOIN ger_block AS g ON true
+		ON CONFLICT DO NOTHING)
+		SELECT leaf_type, orig_net, orig_addr, amount, dest_net, dest_addr, deposit_cnt, block_id, network_id, tx_hash, metadata, ready_for_claim FROM updated;`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, updateDepositsStatusSQL, exitRoot, networkID)
+	if err != nil {
+		return nil, err
+	}
+	return scanUpdatedDeposits(rows)
+}
+
+// AddClaimTx adds a claim monitored transaction to the storage.
+func (p *PostgresStorage) AddClaimTx(ctx context.Context, mTx ctmtypes.MonitoredTx, dbTx pgx.Tx) error {
+	const addMonitoredTxSQL = `INSERT INTO sync.monitored_txs 
+		(deposit_id, from_addr, to_addr, nonce, value, data, gas, status, history, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addMonitoredTxSQL, mTx.DepositID, mTx.From, mTx.To, mTx.Nonce, mTx.Value.String(), mTx.Data, mTx.Gas, mTx.Status, pq.Array(mTx.HistoryHashSlice()), time.Now().UTC(), time.Now().UTC())
+	return wrapInsertError(err)
+}
+
+// UpdateClaimTx updates a claim monitored transaction in the storage.
+func (p *PostgresStorage) UpdateClaimTx(ctx context.Context, mTx ctmtypes.MonitoredTx, dbTx pgx.Tx) error {
+	const updateMonitoredTxSQL = `UPDATE sync.monitored_txs 
+		SET from_addr = $2
+		, to_addr = $3
+		, nonce = $4
+		, value = $5
+		, data = $6
+		, gas = $7
+		, status = $8
+		, history = $9
+		, updated_at = $10
+		, signed_tx = $11
+		WHERE deposit_id = $1`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, updateMonitoredTxSQL, mTx.DepositID, mTx.From, mTx.To, mTx.Nonce, mTx.Value.String(), mTx.Data, mTx.Gas, mTx.Status, pq.Array(mTx.HistoryHashSlice()), time.Now().UTC(), mTx.SignedTx)
+	return err
+}
+
+// GetClaimTxsByStatus gets the monitored transactions by status.
+func (p *PostgresStorage) GetClaimTxsByStatus(ctx context.Context, statuses []ctmtypes.MonitoredTxStatus, dbTx pgx.Tx) ([]ctmtypes.MonitoredTx, error) {
+	const getMonitoredTxsSQL = "SELECT deposit_id, from_addr, to_addr, nonce, value, data, gas, status, history, created_at, updated_at, signed_tx FROM sync.monitored_txs WHERE status = ANY($1) ORDER BY created_at ASC"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getMonitoredTxsSQL, pq.Array(statuses))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return []ctmtypes.MonitoredTx{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	mTxs := make([]ctmtypes.MonitoredTx, 0, len(rows.RawValues()))
+	for rows.Next() {
+		var (
+			value   string
+			history [][]byte
+		)
+		mTx := ctmtypes.MonitoredTx{}
+		err = rows.Scan(&mTx.DepositID, &mTx.From, &mTx.To, &mTx.Nonce, &value, &mTx.Data, &mTx.Gas, &mTx.Status, pq.Array(&history), &mTx.CreatedAt, &mTx.UpdatedAt, &mTx.SignedTx)
+		if err != nil {
+			return mTxs, err
+		}
+		mTx.Value, _ = new(big.Int).SetString(value, 10) //nolint:gomnd
+		mTx.History = make(map[common.Hash]bool)
+		for _, h := range history {
+			mTx.History[common.BytesToHash(h)] = true
+		}
+		mTxs = append(mTxs, mTx)
+	}
+
+	return mTxs, nil
+}
+
+// AddClaimOutcome stores the outcome of a mined claim, replacing the previous one of its monitored tx.
+func (p *PostgresStorage) AddClaimOutcome(ctx context.Context, outcome *etherman.ClaimOutcome, dbTx pgx.Tx) error {
+	const addClaimOutcomeSQL = `INSERT INTO sync.claim_outcome
+		(deposit_id, tx_hash, block_num, orig_net, orig_addr, recipient, amount, token, minted, delivered, decoded_at, gas_used, effective_gas_price)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (deposit_id) DO UPDATE SET tx_hash = $2, block_num = $3, orig_net = $4, orig_addr = $5, recipient = $6,
+		amount = $7, token = $8, minted = $9, delivered = $10, decoded_at = $11, gas_used = $12, effective_gas_price = $13`
+	amount, err := amountValue(outcome.Amount)
+	if err != nil {
+		return fmt.Errorf("error adding the outcome of the claim %d: %w", outcome.DepositID, err)
+	}
+	var delivered, gasPrice *string
+	if outcome.Delivered != nil {
+		value := outcome.Delivered.String()
+		delivered = &value
+	}
+	if outcome.EffectiveGasPrice != nil {
+		value := outcome.EffectiveGasPrice.String()
+		gasPrice = &value
+	}
+	_, err = p.getExecQuerier(dbTx).Exec(ctx, addClaimOutcomeSQL, outcome.DepositID, outcome.TxHash, outcome.BlockNumber, outcome.OriginalNetwork, outcome.OriginalAddress, outcome.Recipient, amount, outcome.Token, outcome.Minted, delivered, outcome.DecodedAt, outcome.GasUsed, gasPrice)
+	return err
+}
+
+// GetClaimOutcome gets the outcome of the mined claim of a monitored tx.
+func (p *PostgresStorage) GetClaimOutcome(ctx context.Context, depositID uint, dbTx pgx.Tx) (*etherman.ClaimOutcome, error) {
+	const getClaimOutcomeSQL = `SELECT deposit_id, tx_hash, block_num, orig_net, orig_addr, recipient, amount, token, minted, delivered, decoded_at,
+		COALESCE(gas_used, 0), effective_gas_price::VARCHAR FROM sync.claim_outcome WHERE deposit_id = $1`
+	var (
+		outcome             etherman.ClaimOutcome
+		amount              string
+		delivered, gasPrice *string
+	)
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getClaimOutcomeSQL, depositID).Scan(&outcome.DepositID, &outcome.TxHash, &outcome.BlockNumber, &outcome.OriginalNetwork, &outcome.OriginalAddress, &outcome.Recipient, &amount, &outcome.Token, &outcome.Minted, &delivered, &outcome.DecodedAt, &outcome.GasUsed, &gasPrice)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if outcome.Amount, err = uint256.Parse(amount); err != nil {
+		return nil, err
+	}
+	if delivered != nil {
+		if outcome.Delivered, err = uint256.ParseSum(*delivered); err != nil {
+			return nil, err
+		}
+	}
+	if gasPrice != nil {
+		if outcome.EffectiveGasPrice, err = uint256.Parse(*gasPrice); err != nil {
+			return nil, err
+		}
+	}
+	return &outcome, nil
+}
+
+// GetClaimGasStats gets the gas spent by the claim txs of the claim tx manager created since a time and confirmed,
+// whose receipts have it.
+func (p *PostgresStorage) GetClaimGasStats(ctx context.Context, since time.Time, dbTx pgx.Tx) (*etherman.ClaimGasStats, error) {
+	const getClaimGasStatsSQL = `SELECT count(*), COALESCE(sum(o.gas_used), 0)::BIGINT, COALESCE(sum(o.gas_used * o.effective_gas_price), 0)::VARCHAR,
+		COALESCE(min(m.created_at), to_timestamp(0)), COALESCE(max(m.created_at), to_timestamp(0))
+		FROM sync.claim_outcome AS o INNER JOIN sync.monitored_txs AS m ON m.deposit_id = o.deposit_id
+		WHERE m.status = $1 AND m.created_at >= $2 AND o.gas_used IS NOT NULL AND o.effective_gas_price IS NOT NULL`
+	var (
+		stats etherman.ClaimGasStats
+		fee   string
+	)
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getClaimGasStatsSQL, ctmtypes.MonitoredTxStatusConfirmed, since.UTC()).Scan(&stats.Claims, &stats.GasUsed, &fee, &stats.From, &stats.To)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Fee, err = uint256.ParseSum(fee); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetClaimsGas gets the gas spent by each claim tx of the claim tx manager created since a time and confirmed, whose
+// receipt has it, from the oldest to the newest.
+func (p *PostgresStorage) GetClaimsGas(ctx context.Context, since time.Time, dbTx pgx.Tx) ([]etherman.ClaimGas, error) {
+	const getClaimsGasSQL = `SELECT m.created_at, o.gas_used, o.effective_gas_price::VARCHAR
+		FROM sync.claim_outcome AS o INNER JOIN sync.monitored_txs AS m ON m.deposit_id = o.deposit_id
+		WHERE m.status = $1 AND m.created_at >= $2 AND o.gas_used IS NOT NULL AND o.effective_gas_price IS NOT NULL
+		ORDER BY m.created_at ASC`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getClaimsGasSQL, ctmtypes.MonitoredTxStatusConfirmed, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var claims []etherman.ClaimGas
+	for rows.Next() {
+		var (
+			claim    etherman.ClaimGas
+			gasPrice string
+		)
+		if err = rows.Scan(&claim.CreatedAt, &claim.GasUsed, &gasPrice); err != nil {
+			return nil, err
+		}
+		if claim.EffectiveGasPrice, err = uint256.Parse(gasPrice); err != nil {
+			return nil, err
+		}
+		claims = append(claims, claim)
+	}
+	return claims, rows.Err()
+}
+
+// deniedClaimTxSQL is the condition of the claim monitored transactions m of the deposits whose token is in the
+// deny list, $1 the name of the list.
+const deniedClaimTxSQL = `EXISTS (SELECT 1 FROM sync.deposit AS d
+	INNER JOIN sync.token_list AS t ON t.orig_net = d.orig_net AND t.orig_addr = d.orig_addr AND t.list = $1
+	WHERE d.network_id = 0 AND d.deposit_cnt = m.deposit_id AND d.leaf_type = 0)`
+
+// ApproveClaimTx approves a claim monitored transaction that is pending of approval, parked as uneconomical or as
+// a reverting message, or denied while its token was in the deny list. The claim txs of the tokens in the deny list
+// can't be approved.
+func (p *PostgresStorage) ApproveClaimTx(ctx context.Context, depositID uint, dbTx pgx.Tx) error {
+	const approveMonitoredTxSQL = "UPDATE sync.monitored_txs AS m SET status = $4, updated_at = $5 WHERE m.deposit_id = $2 AND m.status = ANY($3) AND NOT " + deniedClaimTxSQL
+	parked := []ctmtypes.MonitoredTxStatus{ctmtypes.MonitoredTxStatusPendingApproval, ctmtypes.MonitoredTxStatusUneconomical, ctmtypes.MonitoredTxStatusMessageReverted, ctmtypes.MonitoredTxStatusDenied}
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, approveMonitoredTxSQL, etherman.TokenListDeny, depositID, pq.Array(parked), ctmtypes.MonitoredTxStatusApproved, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrStorageNotFound
+	}
+	return nil
+}
+
+// DenyClaimTxs sets as denied the claim monitored transactions in the statuses, never sent, of the deposits whose
+// token is in the deny list, and returns their deposit ids.
+func (p *PostgresStorage) DenyClaimTxs(ctx context.Context, statuses []ctmtypes.MonitoredTxStatus, dbTx pgx.Tx) ([]uint, error) {
+	const denyClaimTxsSQL = `UPDATE sync.monitored_txs AS m SET status = $3, updated_at = $4
+		WHERE m.status = ANY($2) AND COALESCE(cardinality(m.history), 0) = 0 AND ` + deniedClaimTxSQL + ` RETURNING m.deposit_id`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, denyClaimTxsSQL, etherman.TokenListDeny, pq.Array(statuses), ctmtypes.MonitoredTxStatusDenied, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var depositIDs []uint
+	for rows.Next() {
+		var depositID uint
+		if err := rows.Scan(&depositID); err != nil {
+			return nil, err
+		}
+		depositIDs = append(depositIDs, depositID)
+	}
+	return depositIDs, rows.Err()
+}
+
+// AddSignedClaimTx stores the tx signed offline of a claim monitored transaction that is pending of signature.
+func (p *PostgresStorage) AddSignedClaimTx(ctx context.Context, depositID uint, signedTx []byte, dbTx pgx.Tx) error {
+	const addSignedTxSQL = "UPDATE sync.monitored_txs SET signed_tx = $2, status = $4, updated_at = $5 WHERE deposit_id = $1 AND status = $3"
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, addSignedTxSQL, depositID, signedTx, ctmtypes.MonitoredTxStatusPendingSignature, ctmtypes.MonitoredTxStatusSigned, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrStorageNotFound
+	}
+	return nil
+}
+
+// UpdateDepositsStatusForTesting updates the ready_for_claim status of all deposits for testing.
+func (p *PostgresStorage) UpdateDepositsStatusForTesting(ctx context.Context, dbTx pgx.Tx) error {
+	const updateDepositsStatusSQL = "UPDATE sync.deposit SET ready_for_claim = true WHERE ready_for_claim = false;"
 	_, err := p.getExecQuerier(dbTx).Exec(ctx, updateDepositsStatusSQL)
 	return err
 }
+
+// AddRawLog stores the raw log of a bridge event.
+func (p *PostgresStorage) AddRawLog(ctx context.Context, vLog *types.Log, blockID uint64, networkID uint, dbTx pgx.Tx) error {
+	const addRawLogSQL = "INSERT INTO sync.raw_log (block_id, network_id, tx_hash, tx_index, log_index, address, topics, data) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	topics := make([][]byte, 0, len(vLog.Topics))
+	for _, topic := range vLog.Topics {
+		topics = append(topics, topic.Bytes())
+	}
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addRawLogSQL, blockID, networkID, vLog.TxHash, vLog.TxIndex, vLog.Index, vLog.Address, pq.Array(topics), vLog.Data)
+	return wrapInsertError(err)
+}
+
+// GetRawLogs gets the stored raw logs of a network within the block range, ordered as they were emitted.
+func (p *PostgresStorage) GetRawLogs(ctx context.Context, networkID uint, fromBlock, toBlock uint64, dbTx pgx.Tx) ([]types.Log, error) {
+	const getRawLogsSQL = `SELECT b.block_num, b.block_hash, l.tx_hash, l.tx_index, l.log_index, l.address, l.topics, l.data
+		FROM sync.raw_log as l INNER JOIN sync.block as b ON l.block_id = b.id
+		WHERE l.network_id = $1 AND b.block_num >= $2 AND b.block_num <= $3
+		ORDER BY b.block_num ASC, l.log_index ASC`
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getRawLogsSQL, networkID, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := make([]types.Log, 0, len(rows.RawValues()))
+	for rows.Next() {
+		var (
+			vLog   types.Log
+			topics [][]byte
+		)
+		err = rows.Scan(&vLog.BlockNumber, &vLog.BlockHash, &vLog.TxHash, &vLog.TxIndex, &vLog.Index, &vLog.Address, pq.Array(&topics), &vLog.Data)
+		if err != nil {
+			return nil, err
+		}
+		for _, topic := range topics {
+			vLog.Topics = append(vLog.Topics, common.BytesToHash(topic))
+		}
+		logs = append(logs, vLog)
+	}
+	return logs, nil
+}
+
+// GetNetworkPins gets the chain ids and contract addresses the database was built against.
+func (p *PostgresStorage) GetNetworkPins(ctx context.Context, dbTx pgx.Tx) ([]*etherman.NetworkPin, error) {
+	const getNetworkPinsSQL = "SELECT network_id, chain_id, bridge_addr, ger_addr FROM sync.network_pin ORDER BY network_id"
+	rows, err := p.getExecQuerier(dbTx).Query(ctx, getNetworkPinsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pins := make([]*etherman.NetworkPin, 0, len(rows.RawValues()))
+	for rows.Next() {
+		var pin etherman.NetworkPin
+		err = rows.Scan(&pin.NetworkID, &pin.ChainID, &pin.BridgeAddress, &pin.GlobalExitRootAddress)
+		if err != nil {
+			return nil, err
+		}
+		pins = append(pins, &pin)
+	}
+	return pins, nil
+}
+
+// SetNetworkPin stores the chain id and contract addresses of a network, replacing the previous ones.
+func (p *PostgresStorage) SetNetworkPin(ctx context.Context, pin *etherman.NetworkPin, dbTx pgx.Tx) error {
+	const setNetworkPinSQL = `INSERT INTO sync.network_pin (network_id, chain_id, bridge_addr, ger_addr) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (network_id) DO UPDATE SET chain_id = EXCLUDED.chain_id, bridge_addr = EXCLUDED.bridge_addr, ger_addr = EXCLUDED.ger_addr`
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, setNetworkPinSQL, pin.NetworkID, pin.ChainID, pin.BridgeAddress, pin.GlobalExitRootAddress)
+	return err
+}
@@ -0,0 +1,282 @@
+package pgstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/jackc/pgx/v4"
+)
+
+const adminQueryTimeout = 30 * time.Second
+
+type adminQueryParamType string
+
+const (
+	adminQueryParamUint    adminQueryParamType = "uint"
+	adminQueryParamTime    adminQueryParamType = "time"
+	adminQueryParamString  adminQueryParamType = "string"
+	adminQueryParamAddress adminQueryParamType = "address"
+)
+
+type adminQueryParam struct {
+	name      string
+	paramType adminQueryParamType
+}
+
+// adminQuery is a predefined query the operators can run through the admin API. The params
+// are bound in order to the $1..$n placeholders of the sql, followed by the rows limit.
+type adminQuery struct {
+	description string
+	params      []adminQueryParam
+	sql         string
+}
+
+// adminQueries are the queries the operators can run, by name.
+var adminQueries = map[string]adminQuery{
+	"deposits_by_token": {
+		description: "Deposits count and volume of a network by token in a time range",
+		params:      []adminQueryParam{{"network_id", adminQueryParamUint}, {"from", adminQueryParamTime}, {"to", adminQueryParamTime}},
+		sql: `SELECT d.orig_net, d.orig_addr, count(*) AS deposit_cnt, sum(d.amount::NUMERIC)::VARCHAR AS volume
+			FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.block_id = b.id
+			WHERE d.network_id = $1 AND b.received_at >= $2 AND b.received_at < $3
+			GROUP BY d.orig_net, d.orig_addr ORDER BY deposit_cnt DESC LIMIT $4`,
+	},
+	"claims_by_day": {
+		description: "Claims count and volume of a network by day in a time range",
+		params:      []adminQueryParam{{"network_id", adminQueryParamUint}, {"from", adminQueryParamTime}, {"to", adminQueryParamTime}},
+		sql: `SELECT date_trunc('day', b.received_at) AS day, count(*) AS claim_cnt, sum(c.amount::NUMERIC)::VARCHAR AS volume
+			FROM sync.claim AS c INNER JOIN sync.block AS b ON c.block_id = b.id
+			WHERE c.network_id = $1 AND b.received_at >= $2 AND b.received_at < $3
+			GROUP BY day ORDER BY day LIMIT $4`,
+	},
+	"pending_deposits": {
+		description: "Deposits of a network not ready for claim yet, synced before a time",
+		params:      []adminQueryParam{{"network_id", adminQueryParamUint}, {"before", adminQueryParamTime}},
+		sql: `SELECT d.deposit_cnt, d.dest_net, d.dest_addr, d.amount, d.tx_hash, b.received_at
+			FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.block_id = b.id
+			WHERE d.network_id = $1 AND d.ready_for_claim = false AND b.received_at < $2
+			ORDER BY d.deposit_cnt LIMIT $3`,
+	},
+	"unclaimed_deposits": {
+		description: "Deposits of a network not claimed, ready for claim or not, synced before a time",
+		params:      []adminQueryParam{{"network_id", adminQueryParamUint}, {"before", adminQueryParamTime}},
+		sql: `SELECT d.deposit_cnt, d.dest_net, d.dest_addr, d.amount, d.tx_hash, b.received_at, d.ready_for_claim
+			FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.block_id = b.id
+			WHERE d.network_id = $1 AND b.received_at < $2
+			AND NOT EXISTS (SELECT 1 FROM sync.claim AS c WHERE c.index = d.deposit_cnt AND c.network_id = d.dest_net)
+			ORDER BY d.deposit_cnt LIMIT $3`,
+	},
+	"address_history": {
+		description: "Deposits to an address in all the networks with their claim tx, the full history for the exports",
+		params:      []adminQueryParam{{"address", adminQueryParamAddress}},
+		sql: `SELECT d.network_id, d.deposit_cnt, b.block_num, d.orig_net, d.orig_addr, d.amount, d.dest_net, d.tx_hash,
+			b.received_at, d.ready_for_claim, c.tx_hash AS claim_tx_hash
+			FROM sync.deposit AS d INNER JOIN sync.block AS b ON d.block_id = b.id
+			LEFT JOIN sync.claim AS c ON c.index = d.deposit_cnt AND c.network_id = d.dest_net
+			WHERE d.dest_addr = $1 ORDER BY d.network_id, b.block_num, d.log_index NULLS FIRST, d.deposit_cnt LIMIT $2`,
+	},
+	"claim_txs_by_status": {
+		description: "Claim txs of the claim tx manager with a status",
+		params:      []adminQueryParam{{"status", adminQueryParamString}},
+		sql: `SELECT deposit_id, from_addr, nonce, gas, status, created_at, updated_at
+			FROM sync.monitored_txs WHERE status = $1 ORDER BY created_at LIMIT $2`,
+	},
+}
+
+// GetAdminQueries returns the predefined admin queries sorted by name.
+func (p *PostgresStorage) GetAdminQueries() []etherman.AdminQuery {
+	queries := make([]etherman.AdminQuery, 0, len(adminQueries))
+	for name, query := range adminQueries {
+		params := make([]string, 0, len(query.params))
+		for _, param := range query.params {
+			params = append(params, param.name+":"+string(param.paramType))
+		}
+		queries = append(queries, etherman.AdminQuery{Name: name, Description: query.description, Params: params})
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+	return queries
+}
+
+// RunAdminQuery runs a predefined admin query with at most limit rows. The query runs in its own
+// read only transaction with a statement timeout, so it can't modify the data or hold the database.
+func (p *PostgresStorage) RunAdminQuery(ctx context.Context, name string, params map[string]string, limit uint) (*etherman.AdminQueryResult, error) {
+	return p.RunAdminQueryWithTimeout(ctx, name, params, limit, adminQueryTimeout)
+}
+
+// RunAdminQueryWithTimeout runs a predefined admin query like RunAdminQuery, with the given statement timeout.
+func (p *PostgresStorage) RunAdminQueryWithTimeout(ctx context.Context, name string, params map[string]string, limit uint, timeout time.Duration) (*etherman.AdminQueryResult, error) {
+	query, found := adminQueries[name]
+	if !found {
+		return nil, fmt.Errorf("%w: unknown query %s", gerror.ErrInvalidAdminQuery, name)
+	}
+	args, err := adminQueryArgs(query, params)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, limit)
+
+	dbTx, err := p.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer dbTx.Rollback(ctx) //nolint:errcheck
+	if _, err = dbTx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())); err != nil {
+		return nil, err
+	}
+	rows, err := dbTx.Query(ctx, query.sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result etherman.AdminQueryResult
+	for _, field := range rows.FieldDescriptions() {
+		result.Columns = append(result.Columns, string(field.Name))
+	}
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, err
+		}
+		row := make([]string, len(values))
+		for i, value := range values {
+			row[i] = formatQueryValue(value)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return &result, rows.Err()
+}
+
+func adminQueryArgs(query adminQuery, params map[string]string) ([]interface{}, error) {
+	if len(params) > len(query.params) {
+		return nil, fmt.Errorf("%w: unexpected params", gerror.ErrInvalidAdminQuery)
+	}
+	args := make([]interface{}, 0, len(query.params)+1)
+	for _, param := range query.params {
+		value, found := params[param.name]
+		if !found {
+			return nil, fmt.Errorf("%w: missing param %s", gerror.ErrInvalidAdminQuery, param.name)
+		}
+		var (
+			arg interface{}
+			err error
+		)
+		switch param.paramType {
+		case adminQueryParamUint:
+			arg, err = strconv.ParseUint(value, 10, 64) //nolint:gomnd
+		case adminQueryParamTime:
+			arg, err = time.Parse(time.RFC3339, value)
+		case adminQueryParamAddress:
+			if !common.IsHexAddress(value) {
+				err = fmt.Errorf("not a hex address")
+			}
+			arg = common.HexToAddress(value)
+		default:
+			arg = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s param %s: %v", gerror.ErrInvalidAdminQuery, param.paramType, param.name, err)
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func formatQueryValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		if len(v) == common.AddressLength {
+			return common.BytesToAddress(v).String()
+		}
+		return hexutil.Encode(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// AddQueryJob stores a new admin query job.
+func (p *PostgresStorage) AddQueryJob(ctx context.Context, job *etherman.QueryJob, dbTx pgx.Tx) error {
+	const addQueryJobSQL = "INSERT INTO sync.query_job (id, query, params, status, created_at) VALUES ($1, $2, $3, $4, $5)"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, addQueryJobSQL, job.ID, job.Query, job.Params, job.Status, job.CreatedAt)
+	return wrapInsertError(err)
+}
+
+// FinishQueryJob stores the status of a finished admin query job, with its result when it's done.
+func (p *PostgresStorage) FinishQueryJob(ctx context.Context, id string, status string, jobErr string, result *etherman.AdminQueryResult, dbTx pgx.Tx) error {
+	var (
+		encoded []byte
+		rows    int
+	)
+	if result != nil {
+		var err error
+		if encoded, err = json.Marshal(result); err != nil {
+			return err
+		}
+		rows = len(result.Rows)
+	}
+	const finishQueryJobSQL = "UPDATE sync.query_job SET status = $2, error = $3, row_cnt = $4, result = $5, finished_at = NOW() WHERE id = $1"
+	res, err := p.getExecQuerier(dbTx).Exec(ctx, finishQueryJobSQL, id, status, jobErr, rows, encoded)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return gerror.ErrStorageNotFound
+	}
+	return nil
+}
+
+// GetQueryJob gets an admin query job, without its result.
+func (p *PostgresStorage) GetQueryJob(ctx context.Context, id string, dbTx pgx.Tx) (*etherman.QueryJob, error) {
+	const getQueryJobSQL = "SELECT id, query, params, status, error, row_cnt, created_at, COALESCE(finished_at, to_timestamp(0)) FROM sync.query_job WHERE id = $1"
+	var job etherman.QueryJob
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getQueryJobSQL, id).Scan(&job.ID, &job.Query, &job.Params, &job.Status, &job.Error, &job.Rows, &job.CreatedAt, &job.FinishedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &job, err
+}
+
+// GetQueryJobResult gets the columns and a page of the rows of the result of a done admin query job. The rows are
+// sliced in the database, so only the page is read.
+func (p *PostgresStorage) GetQueryJobResult(ctx context.Context, id string, offset, limit uint, dbTx pgx.Tx) (*etherman.AdminQueryResult, error) {
+	const getQueryJobResultSQL = `WITH job AS (SELECT convert_from(result, 'UTF8')::JSONB AS result FROM sync.query_job WHERE id = $1 AND result IS NOT NULL)
+		SELECT job.result -> 'Columns', COALESCE((SELECT jsonb_agg(r ORDER BY n)
+			FROM jsonb_array_elements(CASE WHEN jsonb_typeof(job.result -> 'Rows') = 'array' THEN job.result -> 'Rows' ELSE '[]'::JSONB END) WITH ORDINALITY AS t(r, n)
+			WHERE n > $2 AND n <= $2 + $3), '[]'::JSONB)
+		FROM job`
+	var columns, rows []byte
+	err := p.getExecQuerier(dbTx).QueryRow(ctx, getQueryJobResultSQL, id, int64(offset), int64(limit)).Scan(&columns, &rows)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, gerror.ErrStorageNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var result etherman.AdminQueryResult
+	if err := json.Unmarshal(columns, &result.Columns); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rows, &result.Rows); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteQueryJobs deletes the admin query jobs created before the time.
+func (p *PostgresStorage) DeleteQueryJobs(ctx context.Context, before time.Time, dbTx pgx.Tx) error {
+	const deleteQueryJobsSQL = "DELETE FROM sync.query_job WHERE created_at < $1"
+	_, err := p.getExecQuerier(dbTx).Exec(ctx, deleteQueryJobsSQL, before)
+	return err
+}
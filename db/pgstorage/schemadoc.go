@@ -0,0 +1,158 @@
+package pgstorage
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/schemadoc"
+)
+
+// GetSchema returns the tables of the bridge schemas with their columns, constraints, indexes, triggers and
+// comments, as they are in the database after the migrations.
+func (p *PostgresStorage) GetSchema(ctx context.Context) (*schemadoc.Schema, error) {
+	const getTablesSQL = `
+		SELECT n.nspname, c.relname, COALESCE(obj_description(c.oid, 'pg_class'), '')
+		FROM pg_class c INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind = 'r' AND n.nspname IN ('sync', 'mt')
+		ORDER BY n.nspname DESC, c.relname`
+	rows, err := p.Query(ctx, getTablesSQL)
+	if err != nil {
+		return nil, err
+	}
+	var schema schemadoc.Schema
+	tables := make(map[string]*schemadoc.Table)
+	for rows.Next() {
+		var table schemadoc.Table
+		if err := rows.Scan(&table.Schema, &table.Name, &table.Comment); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		schema.Tables = append(schema.Tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range schema.Tables {
+		tables[schema.Tables[i].FullName()] = &schema.Tables[i]
+	}
+
+	const getColumnsSQL = `
+		SELECT n.nspname || '.' || c.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+			COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), COALESCE(col_description(c.oid, a.attnum), '')
+		FROM pg_attribute a
+		INNER JOIN pg_class c ON c.oid = a.attrelid
+		INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE c.relkind = 'r' AND n.nspname IN ('sync', 'mt') AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attrelid, a.attnum`
+	err = p.scanSchema(ctx, getColumnsSQL, tables, func(scan scanTableFunc) error {
+		var column schemadoc.Column
+		table, err := scan(&column.Name, &column.Type, &column.NotNull, &column.Default, &column.Comment)
+		if err != nil {
+			return err
+		}
+		table.Columns = append(table.Columns, column)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	const getConstraintsSQL = `
+		SELECT n.nspname || '.' || c.relname, con.conname, con.contype, pg_get_constraintdef(con.oid),
+			ARRAY(SELECT a.attname FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+				INNER JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum ORDER BY k.ord),
+			COALESCE(rn.nspname || '.' || rc.relname, ''),
+			ARRAY(SELECT a.attname FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+				INNER JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum ORDER BY k.ord)
+		FROM pg_constraint con
+		INNER JOIN pg_class c ON c.oid = con.conrelid
+		INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_class rc ON rc.oid = con.confrelid
+		LEFT JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+		WHERE n.nspname IN ('sync', 'mt') AND con.contype IN ('p', 'f', 'u')
+		ORDER BY con.contype, con.conname`
+	err = p.scanSchema(ctx, getConstraintsSQL, tables, func(scan scanTableFunc) error {
+		var constraint schemadoc.Constraint
+		table, err := scan(&constraint.Name, &constraint.Type, &constraint.Definition, &constraint.Columns, &constraint.RefTable, &constraint.RefColumns)
+		if err != nil {
+			return err
+		}
+		table.Constraints = append(table.Constraints, constraint)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	const getIndexesSQL = `
+		SELECT n.nspname || '.' || t.relname, pg_get_indexdef(i.oid)
+		FROM pg_index x
+		INNER JOIN pg_class t ON t.oid = x.indrelid
+		INNER JOIN pg_class i ON i.oid = x.indexrelid
+		INNER JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE n.nspname IN ('sync', 'mt') AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = x.indexrelid)
+		ORDER BY i.relname`
+	err = p.scanSchema(ctx, getIndexesSQL, tables, func(scan scanTableFunc) error {
+		var index string
+		table, err := scan(&index)
+		if err != nil {
+			return err
+		}
+		table.Indexes = append(table.Indexes, index)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	const getTriggersSQL = `
+		SELECT n.nspname || '.' || c.relname, pg_get_triggerdef(tg.oid), tg.tgenabled <> 'D'
+		FROM pg_trigger tg
+		INNER JOIN pg_class c ON c.oid = tg.tgrelid
+		INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname IN ('sync', 'mt') AND NOT tg.tgisinternal
+		ORDER BY tg.tgname`
+	err = p.scanSchema(ctx, getTriggersSQL, tables, func(scan scanTableFunc) error {
+		var trigger schemadoc.Trigger
+		table, err := scan(&trigger.Definition, &trigger.Enabled)
+		if err != nil {
+			return err
+		}
+		table.Triggers = append(table.Triggers, trigger)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// scanTableFunc scans a row into dest, returning the table of the row.
+type scanTableFunc func(dest ...interface{}) (*schemadoc.Table, error)
+
+// scanSchema runs a query whose first column is the table of the row, scanning each row with scanRow.
+func (p *PostgresStorage) scanSchema(ctx context.Context, sql string, tables map[string]*schemadoc.Table, scanRow func(scan scanTableFunc) error) error {
+	rows, err := p.Query(ctx, sql)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = scanRow(func(dest ...interface{}) (*schemadoc.Table, error) {
+			var name string
+			if err := rows.Scan(append([]interface{}{&name}, dest...)...); err != nil {
+				return nil, err
+			}
+			if table, found := tables[name]; found {
+				return table, nil
+			}
+			// The rows of other relations are discarded
+			return &schemadoc.Table{}, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -1,18 +1,80 @@
 package pgstorage
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/gobuffalo/packr/v2"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/stdlib"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
+// uniqueViolationCode is the postgres error code of the unique constraint violations
+const uniqueViolationCode = "23505"
+
+// wrapInsertError tags the unique constraint violations as gerror.ErrAlreadyProcessed,
+// so the callers can tell an already stored object from a database failure.
+func wrapInsertError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return gerror.Wrap(gerror.ErrAlreadyProcessed, err)
+	}
+	return err
+}
+
+// maxSearchValueLen is the length of the longest searched value, the tx hashes
+const maxSearchValueLen = 32
+
+// hexPrefixRange returns the range [from, to) of the byte values starting with the hex prefix, so the prefix
+// searches are range scans of the btree indexes of the bytea columns. The last char of an odd prefix is the
+// high half of its last byte.
+func hexPrefixRange(prefix string) ([]byte, []byte, error) {
+	prefix = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(prefix, "0x"), "0X"))
+	if prefix == "" {
+		return nil, nil, fmt.Errorf("empty hex prefix")
+	}
+	next := []byte(prefix)
+	i := len(next) - 1
+	for ; i >= 0 && next[i] == 'f'; i-- {
+		next[i] = '0'
+	}
+	if i >= 0 {
+		if next[i] == '9' {
+			next[i] = 'a'
+		} else {
+			next[i]++
+		}
+	}
+	if len(prefix)%2 == 1 {
+		prefix += "0"
+		next = append(next, '0')
+	}
+	from, err := hex.DecodeString(prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid hex prefix: %w", err)
+	}
+	if i < 0 {
+		// The prefix is all f, every longer value starting with it is before a longer value of 0xff bytes
+		return from, bytes.Repeat([]byte{0xff}, maxSearchValueLen+1), nil
+	}
+	to, err := hex.DecodeString(string(next))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid hex prefix: %w", err)
+	}
+	return from, to, nil
+}
+
 // RunMigrationsUp migrate up.
 func RunMigrationsUp(cfg Config) error {
 	return runMigrations(cfg, migrate.Up)
@@ -42,6 +104,61 @@ func runMigrations(cfg Config, direction migrate.MigrationDirection) error {
 	return nil
 }
 
+// GetMigrations returns the ids of the migrations applied to the database, in order.
+func GetMigrations(cfg Config) ([]string, error) {
+	c, err := pgx.ParseConfig("postgres://" + cfg.User + ":" + cfg.Password + "@" + cfg.Host + ":" + cfg.Port + "/" + cfg.Name)
+	if err != nil {
+		return nil, err
+	}
+	db := stdlib.OpenDB(*c)
+	defer db.Close() //nolint:errcheck
+
+	records, err := migrate.GetMigrationRecords(db, "postgres")
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.Id)
+	}
+	return ids, nil
+}
+
+// SetChangeOutbox enables or disables the triggers that write the changes of the deposits and claims
+// in the sync.outbox table, so they can be consumed by CDC pipelines.
+func SetChangeOutbox(cfg Config, enabled bool) error {
+	return setTriggers(cfg, "change outbox", enabled, map[string]string{"sync.deposit": "deposit_outbox", "sync.claim": "claim_outbox"})
+}
+
+// SetChangeNotify enables or disables the triggers that notify the changes of the deposits, claims and wrapped
+// tokens on the bridge_changes channel, so the API replicas listening to it learn about them without polling.
+func SetChangeNotify(cfg Config, enabled bool) error {
+	return setTriggers(cfg, "change notifications", enabled, map[string]string{
+		"sync.deposit": "deposit_notify", "sync.claim": "claim_notify", "sync.token_wrapped": "token_wrapped_notify",
+	})
+}
+
+// setTriggers enables or disables the triggers of the tables.
+func setTriggers(cfg Config, name string, enabled bool, triggers map[string]string) error {
+	c, err := pgx.ParseConfig("postgres://" + cfg.User + ":" + cfg.Password + "@" + cfg.Host + ":" + cfg.Port + "/" + cfg.Name)
+	if err != nil {
+		return err
+	}
+	db := stdlib.OpenDB(*c)
+	defer db.Close() //nolint:errcheck
+	action := "DISABLE"
+	if enabled {
+		action = "ENABLE"
+	}
+	for table, trigger := range triggers {
+		if _, err := db.Exec("ALTER TABLE " + table + " " + action + " TRIGGER " + trigger); err != nil {
+			return err
+		}
+	}
+	log.Infof("%s %sd", name, strings.ToLower(action))
+	return nil
+}
+
 // InitOrReset will initializes the db running the migrations or
 // will reset all the known data and rerun the migrations
 func InitOrReset(cfg Config) error {
@@ -0,0 +1,37 @@
+package pgstorage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHexPrefixRange(t *testing.T) {
+	for _, tc := range []struct {
+		prefix   string
+		from, to string
+	}{
+		{"0xabcd", "0xabcd", "0xabce"},
+		{"ABC", "0xabc0", "0xabd0"},
+		{"a9", "0xa9", "0xaa"},
+		{"0x1ff", "0x1ff0", "0x2000"},
+	} {
+		from, to, err := hexPrefixRange(tc.prefix)
+		require.NoError(t, err)
+		require.Equal(t, common.FromHex(tc.from), from, tc.prefix)
+		require.Equal(t, common.FromHex(tc.to), to, tc.prefix)
+	}
+
+	// Every value starting with a prefix of f is in the range
+	from, to, err := hexPrefixRange("ff")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xff}, from)
+	require.Equal(t, 1, bytes.Compare(to, bytes.Repeat([]byte{0xff}, maxSearchValueLen)))
+
+	for _, prefix := range []string{"", "0x", "0xzz"} {
+		_, _, err = hexPrefixRange(prefix)
+		require.Error(t, err, prefix)
+	}
+}
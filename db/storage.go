@@ -34,5 +34,22 @@ func RunMigrations(cfg Config) error {
 		Host:     cfg.Host,
 		Port:     cfg.Port,
 	}
-	return pgstorage.RunMigrationsUp(config)
+	if err := pgstorage.RunMigrationsUp(config); err != nil {
+		return err
+	}
+	if err := pgstorage.SetChangeOutbox(config, cfg.ChangeOutbox); err != nil {
+		return err
+	}
+	return pgstorage.SetChangeNotify(config, cfg.ChangeNotify)
+}
+
+// GetMigrations returns the ids of the migrations applied to the database, in order
+func GetMigrations(cfg Config) ([]string, error) {
+	return pgstorage.GetMigrations(pgstorage.Config{
+		Name:     cfg.Name,
+		User:     cfg.User,
+		Password: cfg.Password,
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+	})
 }
@@ -6,9 +6,13 @@ import (
 	"testing"
 	"time"
 
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/db/pgstorage"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/uint256"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -292,6 +296,23 @@ func TestBSStorage(t *testing.T) {
 	_, err = pg.AddDeposit(ctx, deposit, tx)
 	require.NoError(t, err)
 
+	rawLog := &types.Log{
+		Address:     common.HexToAddress("0x2279B7A0a67DB372996a5FaB50D91eAA73d2eBe6"),
+		Topics:      []common.Hash{common.HexToHash("0x501781209a1f8899323b96b4ef08b168df93e0a90c673d1e4cce39366cb62f9b")},
+		Data:        common.FromHex("0x0001"),
+		BlockNumber: 1,
+		TxHash:      common.HexToHash("0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f3"),
+		TxIndex:     1,
+		BlockHash:   block.BlockHash,
+		Index:       2,
+	}
+	err = pg.AddRawLog(ctx, rawLog, 1, 0, tx)
+	require.NoError(t, err)
+	rawLogs, err := pg.GetRawLogs(ctx, 0, 1, 1, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rawLogs))
+	require.Equal(t, *rawLog, rawLogs[0])
+
 	claim := &etherman.Claim{
 		Index:              1,
 		OriginalNetwork:    0,
@@ -306,7 +327,7 @@ func TestBSStorage(t *testing.T) {
 	err = pg.AddClaim(ctx, claim, tx)
 	require.NoError(t, err)
 
-	count, err := pg.GetDepositCount(ctx, deposit.DestinationAddress.String(), tx)
+	count, err := pg.GetDepositCount(ctx, deposit.DestinationAddress.String(), "", "", tx)
 	require.NoError(t, err)
 	require.Equal(t, count, uint64(1))
 
@@ -315,7 +336,7 @@ func TestBSStorage(t *testing.T) {
 	require.Equal(t, rDeposit.DestinationAddress, deposit.DestinationAddress)
 	require.Equal(t, rDeposit.DepositCount, deposit.DepositCount)
 
-	rDeposits, err := pg.GetDeposits(ctx, deposit.DestinationAddress.String(), 10, 0, tx)
+	rDeposits, err := pg.GetDeposits(ctx, deposit.DestinationAddress.String(), "", "", 10, 0, tx)
 	require.NoError(t, err)
 	require.Equal(t, len(rDeposits), 1)
 
@@ -340,6 +361,107 @@ func TestBSStorage(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, len(rClaims), 1)
 
+	// The prefixes of the destination address and the tx hash, odd or not, match the events
+	rDeposits, err = pg.SearchDeposits(ctx, "f39fd6e", 10, 0, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rDeposits))
+	rClaims, err = pg.SearchClaims(ctx, "29e885edaf", 10, 0, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rClaims))
+	rClaims, err = pg.SearchClaims(ctx, "29e886", 10, 0, tx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(rClaims))
+
+	// The deposit is not claimed in its destination network, so it's stuck after its block
+	stuck, err := pg.GetStuckDeposits(ctx, false, block.ReceivedAt.Add(time.Minute), time.Time{}, 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(stuck))
+	require.Equal(t, deposit.DepositCount, stuck[0].DepositCount)
+	require.Equal(t, "", stuck[0].ClaimTxStatus)
+	stuck, err = pg.GetStuckDeposits(ctx, false, block.ReceivedAt.Add(-time.Minute), time.Time{}, 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(stuck))
+	stuck, err = pg.GetStuckDeposits(ctx, true, block.ReceivedAt.Add(time.Minute), time.Time{}, 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(stuck))
+
+	// The claim in the network 0 has no deposit to the network 0, the claims after the time are not counted
+	flows, err := pg.GetTokenFlows(ctx, block.ReceivedAt.Add(time.Minute), tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(flows))
+	require.Equal(t, uint(0), flows[0].NetworkID)
+	require.Equal(t, claim.OriginalAddress, flows[0].OriginalAddress)
+	require.Equal(t, "0", flows[0].Deposited.String())
+	require.Equal(t, claim.Amount.String(), flows[0].Claimed.String())
+	flows, err = pg.GetTokenFlows(ctx, block.ReceivedAt.Add(-time.Minute), tx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(flows))
+
+	// The mode of the proofs is the one of the config until it's set
+	_, err = pg.GetProofAccess(ctx, tx)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	require.NoError(t, pg.SetProofAccess(ctx, &etherman.ProofAccess{Restricted: true, Reason: "incident", UpdatedAt: block.ReceivedAt}, tx))
+	require.NoError(t, pg.SetProofAccess(ctx, &etherman.ProofAccess{Restricted: false, UpdatedAt: block.ReceivedAt}, tx))
+	access, err := pg.GetProofAccess(ctx, tx)
+	require.NoError(t, err)
+	require.False(t, access.Restricted)
+	require.Equal(t, "", access.Reason)
+
+	// A consumer added again rotates its key
+	consumer := &etherman.ProofConsumer{Name: "relayer", KeyHash: common.HexToHash("0x01"), CreatedAt: block.ReceivedAt}
+	require.NoError(t, pg.AddProofConsumer(ctx, consumer, tx))
+	consumer.KeyHash = common.HexToHash("0x02")
+	require.NoError(t, pg.AddProofConsumer(ctx, consumer, tx))
+	consumers, err := pg.GetProofConsumers(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(consumers))
+	require.Equal(t, consumer.KeyHash, consumers[0].KeyHash)
+	require.NoError(t, pg.DeleteProofConsumer(ctx, "relayer", tx))
+	require.ErrorIs(t, pg.DeleteProofConsumer(ctx, "relayer", tx), gerror.ErrStorageNotFound)
+
+	// The key of a method is only added once, the retries get its response
+	key := &etherman.IdempotencyKey{Method: "ApproveClaim", Key: "retry-1", RequestHash: common.HexToHash("0x01"), CreatedAt: block.ReceivedAt}
+	require.NoError(t, pg.AddIdempotencyKey(ctx, key, tx))
+	require.ErrorIs(t, pg.AddIdempotencyKey(ctx, key, tx), gerror.ErrAlreadyProcessed)
+	stored, err := pg.GetIdempotencyKey(ctx, key.Method, key.Key, tx)
+	require.NoError(t, err)
+	require.Equal(t, key.RequestHash, stored.RequestHash)
+	require.Nil(t, stored.Response)
+	require.NoError(t, pg.SetIdempotencyResponse(ctx, key.Method, key.Key, []byte{1}, tx))
+	stored, err = pg.GetIdempotencyKey(ctx, key.Method, key.Key, tx)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, stored.Response)
+	require.NoError(t, pg.DeleteIdempotencyKeys(ctx, block.ReceivedAt, tx))
+	require.NoError(t, pg.DeleteIdempotencyKey(ctx, key.Method, key.Key, tx))
+	_, err = pg.GetIdempotencyKey(ctx, key.Method, key.Key, tx)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	require.NoError(t, pg.AddIdempotencyKey(ctx, key, tx))
+	require.NoError(t, pg.DeleteIdempotencyKeys(ctx, block.ReceivedAt.Add(time.Second), tx))
+	_, err = pg.GetIdempotencyKey(ctx, key.Method, key.Key, tx)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+
+	activity, err := pg.GetActivity(ctx, 0, 0, &deposit.OriginalAddress, "hour", block.ReceivedAt.Add(-time.Hour), block.ReceivedAt.Add(time.Hour), 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(activity))
+	require.Equal(t, uint64(1), activity[0].DepositCount)
+	require.Equal(t, deposit.Amount, activity[0].DepositVolume)
+	require.Equal(t, uint64(1), activity[0].ClaimCount)
+	require.Equal(t, claim.Amount, activity[0].ClaimVolume)
+	require.Equal(t, deposit.OriginalAddress, activity[0].OriginalAddress)
+	activity, err = pg.GetActivity(ctx, 1, 0, nil, "day", block.ReceivedAt.Add(-time.Hour), block.ReceivedAt.Add(time.Hour), 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(activity))
+
+	integrations, err := pg.GetIntegrationStats(ctx, 0, 0, &deposit.OriginalAddress, block.ReceivedAt.Add(-time.Hour), block.ReceivedAt.Add(time.Hour), tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(integrations))
+	require.Equal(t, "", integrations[0].Integration)
+	require.Equal(t, uint64(1), integrations[0].DepositCount)
+	require.Equal(t, deposit.Amount, integrations[0].DepositVolume)
+	count, err = pg.GetDepositCount(ctx, deposit.DestinationAddress.String(), "", etherman.DepositIntegrationDirect, tx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), count)
+
 	wrappedToken := &etherman.TokenWrapped{
 		OriginalNetwork:      0,
 		OriginalTokenAddress: deposit.OriginalAddress,
@@ -362,5 +484,367 @@ func TestBSStorage(t *testing.T) {
 	require.Equal(t, wt.TokenMetadata.Symbol, "COA")
 	require.Equal(t, wt.TokenMetadata.Decimals, uint8(12))
 
+	// Remap the token in a later block
+	remapBlock := &etherman.Block{
+		BlockNumber: 5,
+		BlockHash:   common.HexToHash("0x39e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f1"),
+		ParentHash:  common.HexToHash("0x39e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f2"),
+		NetworkID:   1,
+		ReceivedAt:  time.Now(),
+	}
+	remapBlockID, err := pg.AddBlock(ctx, remapBlock, tx)
+	require.NoError(t, err)
+	remappedToken := *wrappedToken
+	remappedToken.WrappedTokenAddress = common.HexToAddress("0x287Bd40226A7073b49163b1f6c2b73d8F2aa8478")
+	remappedToken.BlockID = remapBlockID
+	remappedToken.BlockNumber = remapBlock.BlockNumber
+	err = pg.AddTokenWrapped(ctx, &remappedToken, tx)
+	require.NoError(t, err)
+
+	wt, err = pg.GetTokenWrapped(ctx, wrappedToken.OriginalNetwork, wrappedToken.OriginalTokenAddress, tx)
+	require.NoError(t, err)
+	require.Equal(t, remappedToken.WrappedTokenAddress, wt.WrappedTokenAddress)
+
+	history, err := pg.GetTokenWrappedHistory(ctx, wrappedToken.OriginalNetwork, wrappedToken.OriginalTokenAddress, tx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(history))
+	require.Equal(t, wrappedToken.WrappedTokenAddress, history[0].WrappedTokenAddress)
+	require.Equal(t, wrappedToken.BlockNumber, history[0].ValidFromBlock)
+	require.Equal(t, remapBlock.BlockNumber, history[0].ValidToBlock)
+	require.Equal(t, remappedToken.WrappedTokenAddress, history[1].WrappedTokenAddress)
+	require.Equal(t, uint64(0), history[1].ValidToBlock)
+
+	// A reorg of the remap restores the previous mapping
+	err = pg.Reset(ctx, 1, 1, tx)
+	require.NoError(t, err)
+	wt, err = pg.GetTokenWrapped(ctx, wrappedToken.OriginalNetwork, wrappedToken.OriginalTokenAddress, tx)
+	require.NoError(t, err)
+	require.Equal(t, wrappedToken.WrappedTokenAddress, wt.WrappedTokenAddress)
+	require.Equal(t, "COA", wt.TokenMetadata.Symbol)
+	require.Equal(t, uint8(12), wt.TokenMetadata.Decimals)
+	history, err = pg.GetTokenWrappedHistory(ctx, wrappedToken.OriginalNetwork, wrappedToken.OriginalTokenAddress, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(history))
+
+	// The watched addresses and their backfills
+	owner := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	watch := &etherman.WatchedAddress{Owner: owner, Address: deposit.DestinationAddress, CreatedAt: time.Now()}
+	require.NoError(t, pg.AddWatchedAddress(ctx, watch, tx))
+	require.NoError(t, pg.AddWatchedAddress(ctx, watch, tx))
+	require.NoError(t, pg.AddWatchedAddress(ctx, &etherman.WatchedAddress{Owner: deposit.DestinationAddress, Address: deposit.DestinationAddress, CreatedAt: time.Now()}, tx))
+	watches, err := pg.GetWatchedAddresses(ctx, owner, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(watches))
+	require.Nil(t, watches[0].BackfillRequestedAt)
+	addresses, err := pg.GetAllWatchedAddresses(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{deposit.DestinationAddress}, addresses)
+	watched, err := pg.IsWatchedAddress(ctx, deposit.DestinationAddress, tx)
+	require.NoError(t, err)
+	require.True(t, watched)
+	watched, err = pg.IsWatchedAddress(ctx, owner, tx)
+	require.NoError(t, err)
+	require.False(t, watched)
+	requestedAt := time.Now().Truncate(time.Microsecond)
+	require.NoError(t, pg.RequestWatchBackfill(ctx, owner, deposit.DestinationAddress, requestedAt, tx))
+	require.ErrorIs(t, pg.RequestWatchBackfill(ctx, owner, owner, requestedAt, tx), gerror.ErrStorageNotFound)
+	watches, err = pg.GetPendingWatchBackfills(ctx, 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(watches))
+	untraced, err := pg.GetUntracedDepositsTo(ctx, 0, deposit.DestinationAddress, 0, 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(untraced))
+	// A backfill requested again meanwhile stays pending
+	require.NoError(t, pg.SetWatchBackfilled(ctx, owner, deposit.DestinationAddress, requestedAt.Add(-time.Second), time.Now(), tx))
+	watches, err = pg.GetPendingWatchBackfills(ctx, 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(watches))
+	require.NoError(t, pg.SetWatchBackfilled(ctx, owner, deposit.DestinationAddress, *watches[0].BackfillRequestedAt, time.Now(), tx))
+	watches, err = pg.GetPendingWatchBackfills(ctx, 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(watches))
+	require.NoError(t, pg.DeleteWatchedAddress(ctx, owner, deposit.DestinationAddress, tx))
+	require.ErrorIs(t, pg.DeleteWatchedAddress(ctx, owner, deposit.DestinationAddress, tx), gerror.ErrStorageNotFound)
+
+	// Event bus
+	busEvent := &etherman.BusEvent{Type: "deposit_indexed", NetworkID: 0, Payload: []byte(`{"DepositCount":1}`), CreatedAt: time.Now()}
+	require.NoError(t, pg.AddBusEvent(ctx, busEvent, tx))
+	require.NoError(t, pg.AddBusEvent(ctx, &etherman.BusEvent{Type: "ger_updated", NetworkID: 0, Payload: []byte(`{}`), CreatedAt: time.Now()}, tx))
+	lastEventID, err := pg.GetLastBusEventID(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, busEvent.ID+1, lastEventID)
+	busEvents, err := pg.GetBusEvents(ctx, busEvent.ID-1, []string{"deposit_indexed"}, 10, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(busEvents))
+	require.JSONEq(t, `{"DepositCount":1}`, string(busEvents[0].Payload))
+	_, err = pg.GetBusOffset(ctx, "webhook", tx)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	require.NoError(t, pg.SetBusOffset(ctx, "webhook", busEvent.ID, tx))
+	require.NoError(t, pg.AdvanceBusOffset(ctx, "webhook", busEvent.ID, lastEventID, tx))
+	offset, err := pg.GetBusOffset(ctx, "webhook", tx)
+	require.NoError(t, err)
+	require.Equal(t, lastEventID, offset)
+	// The offset set meanwhile is not moved
+	require.NoError(t, pg.AdvanceBusOffset(ctx, "webhook", busEvent.ID, lastEventID+1, tx))
+	offset, err = pg.GetBusOffset(ctx, "webhook", tx)
+	require.NoError(t, err)
+	require.Equal(t, lastEventID, offset)
+	offsets, err := pg.GetBusOffsets(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(offsets))
+	deleted, err := pg.DeleteBusEvents(ctx, time.Now().Add(time.Minute), tx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+
+	// The amounts are uint256 values, read back without truncation
+	maxDeposit := *deposit
+	maxDeposit.DepositCount, maxDeposit.Amount = 100, uint256.Max
+	_, err = pg.AddDeposit(ctx, &maxDeposit, tx)
+	require.NoError(t, err)
+	readDeposit, err := pg.GetDeposit(ctx, 100, 0, tx)
+	require.NoError(t, err)
+	require.Equal(t, uint256.Max.String(), readDeposit.Amount.String())
+	maxDeposit.DepositCount, maxDeposit.Amount = 101, new(big.Int).Add(uint256.Max, big.NewInt(1))
+	_, err = pg.AddDeposit(ctx, &maxDeposit, tx)
+	require.ErrorIs(t, err, uint256.ErrInvalid)
+
+	// The outcome of a mined claim is stored for its monitored tx, with the delivered amount unknown for the ether
+	to := common.HexToAddress("0x2a3dd3eb832af982ec71669e178424b10dca2ede")
+	require.NoError(t, pg.AddClaimTx(ctx, ctmtypes.MonitoredTx{DepositID: 110, To: &to, Value: big.NewInt(0), Status: ctmtypes.MonitoredTxStatusConfirmed}, tx))
+	_, err = pg.GetClaimOutcome(ctx, 110, tx)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	outcome := &etherman.ClaimOutcome{DepositID: 110, TxHash: common.HexToHash("0x01"), BlockNumber: 5, Recipient: to, Amount: big.NewInt(100), DecodedAt: time.Now()}
+	require.NoError(t, pg.AddClaimOutcome(ctx, outcome, tx))
+	readOutcome, err := pg.GetClaimOutcome(ctx, 110, tx)
+	require.NoError(t, err)
+	require.Nil(t, readOutcome.Delivered)
+	outcome.Token, outcome.Delivered = common.HexToAddress("0x03"), big.NewInt(98)
+	require.NoError(t, pg.AddClaimOutcome(ctx, outcome, tx))
+	readOutcome, err = pg.GetClaimOutcome(ctx, 110, tx)
+	require.NoError(t, err)
+	require.Equal(t, outcome.Token, readOutcome.Token)
+	require.Equal(t, "98", readOutcome.Delivered.String())
+	require.True(t, readOutcome.Discrepancy())
+	require.Nil(t, readOutcome.EffectiveGasPrice)
+
+	// The gas of the confirmed claims is aggregated from the outcomes with the gas of their receipts
+	stats, err := pg.GetClaimGasStats(ctx, time.Time{}, tx)
+	require.NoError(t, err)
+	require.Equal(t, 0, stats.Claims)
+	require.Equal(t, "0", stats.Fee.String())
+	outcome.GasUsed, outcome.EffectiveGasPrice = 90000, big.NewInt(2e9)
+	require.NoError(t, pg.AddClaimOutcome(ctx, outcome, tx))
+	readOutcome, err = pg.GetClaimOutcome(ctx, 110, tx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(90000), readOutcome.GasUsed)
+	require.Equal(t, outcome.EffectiveGasPrice, readOutcome.EffectiveGasPrice)
+	stats, err = pg.GetClaimGasStats(ctx, time.Time{}, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Claims)
+	require.Equal(t, uint64(90000), stats.GasUsed)
+	require.Equal(t, "180000000000000", stats.Fee.String())
+	claimsGas, err := pg.GetClaimsGas(ctx, time.Time{}, tx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(claimsGas))
+	require.Equal(t, stats.From, claimsGas[0].CreatedAt)
+	claimsGas, err = pg.GetClaimsGas(ctx, time.Now().Add(time.Hour), tx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(claimsGas))
+
+	// The deposits made by a router on behalf of a user are queried by the router and by the user
+	user, router := common.HexToAddress("0x11"), common.HexToAddress("0x22")
+	routedDeposit := *deposit
+	routedDeposit.DepositCount, routedDeposit.Amount, routedDeposit.Sender, routedDeposit.Depositor = 120, big.NewInt(1), router, user
+	_, err = pg.AddDeposit(ctx, &routedDeposit, tx)
+	require.NoError(t, err)
+	for role, addr := range map[string]common.Address{etherman.DepositAddressSender: router, etherman.DepositAddressDepositor: user} {
+		count, err := pg.GetDepositCount(ctx, addr.String(), role, "", tx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), count)
+		rDeposits, err := pg.GetDeposits(ctx, addr.String(), role, "", 10, 0, tx)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(rDeposits))
+		require.Equal(t, router, rDeposits[0].Sender)
+		require.Equal(t, user, rDeposits[0].Depositor)
+	}
+	readDeposit, err = pg.GetDeposit(ctx, 100, 0, tx)
+	require.NoError(t, err)
+	require.Equal(t, common.Address{}, readDeposit.Sender)
+	_, err = pg.GetDeposits(ctx, user.String(), "origin", "", 10, 0, tx)
+	require.Error(t, err)
+
+	// The maintenance is a single row, replaced when it's set again
+	_, err = pg.GetMaintenance(ctx, tx)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	eta := time.Now().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, pg.SetMaintenance(ctx, &etherman.Maintenance{Enabled: true, Message: "upgrade", ETA: eta, UpdatedAt: time.Now()}, tx))
+	require.NoError(t, pg.SetMaintenance(ctx, &etherman.Maintenance{Enabled: true, Message: "upgrade v2", ETA: eta, UpdatedAt: time.Now()}, tx))
+	maintenance, err := pg.GetMaintenance(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, "upgrade v2", maintenance.Message)
+	require.True(t, maintenance.ETA.Equal(eta))
+	require.True(t, maintenance.Active(time.Now()))
+
+	// The claims of the messages keep the result of the call to their destination, the other claims have none
+	messageClaim := *claim
+	messageClaim.Index, messageClaim.NetworkID, messageClaim.MessageCall = 130, 1, etherman.MessageCallReverted
+	require.NoError(t, pg.AddClaim(ctx, &messageClaim, tx))
+	rClaim, err = pg.GetClaim(ctx, 130, 1, tx)
+	require.NoError(t, err)
+	require.Equal(t, etherman.MessageCallReverted, rClaim.MessageCall)
+	rClaim, err = pg.GetClaim(ctx, 1, 0, tx)
+	require.NoError(t, err)
+	require.Empty(t, rClaim.MessageCall)
+
+	// The blocks are read by their hash with their deposits and claims
+	firstBlock, err := pg.GetFirstBlock(ctx, 0, tx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), firstBlock.BlockNumber)
+	rBlock, err := pg.GetBlockByHash(ctx, 0, block.BlockHash, tx)
+	require.NoError(t, err)
+	require.Equal(t, firstBlock.ID, rBlock.ID)
+	_, err = pg.GetBlockByHash(ctx, 1, block.BlockHash, tx)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	blockDeposits, err := pg.GetBlockDeposits(ctx, rBlock.ID, tx)
+	require.NoError(t, err)
+	require.NotEmpty(t, blockDeposits)
+	for _, d := range blockDeposits {
+		require.Equal(t, rBlock.ID, d.BlockID)
+	}
+	blockClaims, err := pg.GetBlockClaims(ctx, rBlock.ID, tx)
+	require.NoError(t, err)
+	require.NotEmpty(t, blockClaims)
+	require.Equal(t, rBlock.ID, blockClaims[0].BlockID)
+	claimedDeposit, err := pg.GetClaimedDeposit(ctx, 1, 1, 0, common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"), tx)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), claimedDeposit.DepositCount)
+	require.Equal(t, uint(1), claimedDeposit.DestinationNetwork)
+	_, err = pg.GetClaimedDeposit(ctx, 1, 1, 0, common.HexToAddress("0x01"), tx)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+
+	// The activity log of a deposit is read in order, with its fields
+	activities := []etherman.DepositActivity{
+		{NetworkID: 0, DepositCnt: 7, Kind: "log", Level: "warn", Message: "error getting the claim proof", Time: time.Now()},
+		{NetworkID: 0, DepositCnt: 7, Kind: "claim_attempt", Level: "info", Message: "claim tx sent", Fields: map[string]string{"tx_hash": "0x01"}, Time: time.Now()},
+		{NetworkID: 1, DepositCnt: 7, Kind: "log", Level: "info", Message: "other network", Time: time.Now()},
+	}
+	require.NoError(t, pg.AddDepositActivities(ctx, activities, tx))
+	rActivities, err := pg.GetDepositActivities(ctx, 0, 7, "", 10, tx)
+	require.NoError(t, err)
+	require.Len(t, rActivities, 2)
+	require.Equal(t, "error getting the claim proof", rActivities[0].Message)
+	require.Empty(t, rActivities[0].Fields)
+	require.Equal(t, "0x01", rActivities[1].Fields["tx_hash"])
+	// The limit keeps the last entries
+	rActivities, err = pg.GetDepositActivities(ctx, 0, 7, "", 1, tx)
+	require.NoError(t, err)
+	require.Len(t, rActivities, 1)
+	require.Equal(t, "claim tx sent", rActivities[0].Message)
+	// The text filter matches the messages and the fields, ignoring the case
+	rActivities, err = pg.GetDepositActivities(ctx, 0, 7, "PROOF", 10, tx)
+	require.NoError(t, err)
+	require.Len(t, rActivities, 1)
+	require.Equal(t, "error getting the claim proof", rActivities[0].Message)
+	rActivities, err = pg.GetDepositActivities(ctx, 0, 7, "tx_hash=0x01", 10, tx)
+	require.NoError(t, err)
+	require.Len(t, rActivities, 1)
+	require.Equal(t, "claim tx sent", rActivities[0].Message)
+	deleted, err = pg.DeleteDepositActivities(ctx, time.Now().Add(time.Minute), tx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+
 	require.NoError(t, tx.Commit(ctx))
 }
+
+func TestAdminQueries(t *testing.T) {
+	// Init database instance
+	cfg := pgstorage.NewConfigFromEnv()
+	err := pgstorage.InitOrReset(cfg)
+	require.NoError(t, err)
+	ctx := context.Background()
+	pg, err := pgstorage.NewPostgresStorage(cfg)
+	require.NoError(t, err)
+	tx, err := pg.BeginDBTransaction(ctx)
+	require.NoError(t, err)
+
+	block := &etherman.Block{
+		BlockNumber: 1,
+		BlockHash:   common.HexToHash("0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f1"),
+		ParentHash:  common.HexToHash("0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f2"),
+		NetworkID:   0,
+		ReceivedAt:  time.Now(),
+	}
+	blockID, err := pg.AddBlock(ctx, block, tx)
+	require.NoError(t, err)
+	deposit := &etherman.Deposit{
+		NetworkID:          0,
+		OriginalAddress:    common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"),
+		Amount:             big.NewInt(1000000),
+		DestinationNetwork: 1,
+		DestinationAddress: common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		BlockNumber:        1,
+		BlockID:            blockID,
+		DepositCount:       1,
+	}
+	_, err = pg.AddDeposit(ctx, deposit, tx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit(ctx))
+
+	require.NotEmpty(t, pg.GetAdminQueries())
+	from, to := block.ReceivedAt.Add(-time.Hour).Format(time.RFC3339), block.ReceivedAt.Add(time.Hour).Format(time.RFC3339)
+	result, err := pg.RunAdminQuery(ctx, "deposits_by_token", map[string]string{"network_id": "0", "from": from, "to": to}, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"orig_net", "orig_addr", "deposit_cnt", "volume"}, result.Columns)
+	require.Equal(t, [][]string{{"0", deposit.OriginalAddress.String(), "1", "1000000"}}, result.Rows)
+
+	result, err = pg.RunAdminQuery(ctx, "pending_deposits", map[string]string{"network_id": "0", "before": to}, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Rows))
+	// The unclaimed deposits are the ones not claimed yet, ready for claim or not
+	result, err = pg.RunAdminQuery(ctx, "unclaimed_deposits", map[string]string{"network_id": "0", "before": to}, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Rows))
+	require.Equal(t, "false", result.Rows[0][len(result.Columns)-1])
+
+	_, err = pg.RunAdminQuery(ctx, "unknown", nil, 10)
+	require.ErrorIs(t, err, gerror.ErrInvalidAdminQuery)
+	_, err = pg.RunAdminQuery(ctx, "pending_deposits", map[string]string{"network_id": "a", "before": to}, 10)
+	require.ErrorIs(t, err, gerror.ErrInvalidAdminQuery)
+	_, err = pg.RunAdminQuery(ctx, "pending_deposits", map[string]string{"network_id": "0"}, 10)
+	require.ErrorIs(t, err, gerror.ErrInvalidAdminQuery)
+
+	result, err = pg.RunAdminQueryWithTimeout(ctx, "address_history", map[string]string{"address": deposit.DestinationAddress.Hex()}, 10, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Rows))
+	_, err = pg.RunAdminQuery(ctx, "address_history", map[string]string{"address": "0x01"}, 10)
+	require.ErrorIs(t, err, gerror.ErrInvalidAdminQuery)
+
+	// The jobs store the results of the queries until they are deleted
+	job := &etherman.QueryJob{
+		ID:        "job",
+		Query:     "address_history",
+		Params:    map[string]string{"address": deposit.DestinationAddress.Hex()},
+		Status:    etherman.QueryJobStatusRunning,
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, pg.AddQueryJob(ctx, job, nil))
+	_, err = pg.GetQueryJobResult(ctx, job.ID, 0, 10, nil)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	require.NoError(t, pg.FinishQueryJob(ctx, job.ID, etherman.QueryJobStatusDone, "", result, nil))
+	stored, err := pg.GetQueryJob(ctx, job.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, etherman.QueryJobStatusDone, stored.Status)
+	require.Equal(t, job.Params, stored.Params)
+	require.Equal(t, uint64(1), stored.Rows)
+	storedResult, err := pg.GetQueryJobResult(ctx, job.ID, 0, 10, nil)
+	require.NoError(t, err)
+	require.Equal(t, result, storedResult)
+	// The rows are paginated
+	storedResult, err = pg.GetQueryJobResult(ctx, job.ID, 1, 10, nil)
+	require.NoError(t, err)
+	require.Equal(t, result.Columns, storedResult.Columns)
+	require.Empty(t, storedResult.Rows)
+	require.NoError(t, pg.DeleteQueryJobs(ctx, time.Now().Add(time.Minute), nil))
+	_, err = pg.GetQueryJob(ctx, job.ID, nil)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+}
@@ -0,0 +1,231 @@
+package etherman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ArchiveConfig represents the configuration of the archive nodes the historical requests are routed to
+type ArchiveConfig struct {
+	// L1URL is the archive node of L1. Empty doesn't route the L1 requests
+	L1URL string `mapstructure:"L1URL"`
+	// L2URLs are the archive nodes of the L2 networks, in the order of the L2URLs of the etherman. An empty URL
+	// doesn't route the requests of its network
+	L2URLs []string `mapstructure:"L2URLs"`
+	// Depth is the number of blocks behind the head of the full node after which a block is historical, and the
+	// requests reading it are sent to the archive node
+	Depth uint64 `mapstructure:"Depth"`
+}
+
+// Validate checks the configuration.
+func (cfg ArchiveConfig) Validate(l2URLs []string) error {
+	if len(cfg.L2URLs) > len(l2URLs) {
+		return fmt.Errorf("there are %d L2 archive nodes for %d L2 networks", len(cfg.L2URLs), len(l2URLs))
+	}
+	if cfg.Depth == 0 && (cfg.L1URL != "" || len(cfg.L2URLs) > 0) {
+		return errors.New("the depth of the archive nodes must be positive")
+	}
+	return nil
+}
+
+// l2URL returns the archive node of the L2 network of the url, empty if it has none.
+func (cfg ArchiveConfig) l2URL(url string, l2URLs []string) string {
+	for i, l2URL := range l2URLs {
+		if l2URL == url && i < len(cfg.L2URLs) {
+			return cfg.L2URLs[i]
+		}
+	}
+	return ""
+}
+
+// rpcCaller sends the calls not supported by the ethclient, e.g. the traces.
+type rpcCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// archiveRouter sends the requests of the historical blocks to the archive node and the others, the latest head
+// included, to the full node. The requests by hash are sent to the full node, and to the archive node when the
+// full node doesn't have the block or the tx anymore. It's the backend of the contract bindings too, so their
+// calls at the historical blocks are routed the same way.
+type archiveRouter struct {
+	ChainBackend
+	archive    ethClienter
+	rpc        rpcCaller
+	archiveRPC rpcCaller
+	archiveURL string
+	depth      uint64
+	// head is the last block number of the full node, read by the requests of the latest header or block
+	head uint64
+}
+
+// newArchiveRouter connects to the archive node of a full node, returning the backend and the rpc client of the
+// requests routed between them. It returns the full node without archive node.
+func newArchiveRouter(full *ethclient.Client, archiveURL string, depth uint64, throttle ThrottleConfig, capture CaptureConfig, retry RetryConfig) (ChainBackend, rpcCaller, error) {
+	if archiveURL == "" {
+		return full, full.Client(), nil
+	}
+	archive, err := dial(archiveURL, throttle, capture, retry)
+	if err != nil {
+		log.Errorf("error connecting to the archive node %s: %+v", archiveURL, err)
+		return nil, nil, err
+	}
+	r := &archiveRouter{ChainBackend: full, archive: archive, rpc: full.Client(), archiveRPC: archive.Client(), archiveURL: archiveURL, depth: depth}
+	return r, r, nil
+}
+
+// historical checks if a block is older than the depth, according to the last head read. The blocks are not
+// historical until a head is read, and the nil and negative numbers are the tags of the latest blocks.
+func (r *archiveRouter) historical(number *big.Int) bool {
+	if number == nil || number.Sign() < 0 {
+		return false
+	}
+	head := atomic.LoadUint64(&r.head)
+	return head > r.depth && number.Uint64() < head-r.depth
+}
+
+func (r *archiveRouter) client(number *big.Int) ethClienter {
+	if r.historical(number) {
+		return r.archive
+	}
+	return r.ChainBackend
+}
+
+func (r *archiveRouter) setHead(number *big.Int) {
+	if number != nil {
+		atomic.StoreUint64(&r.head, number.Uint64())
+	}
+}
+
+// fallback reports if a request by hash failed in the full node is sent to the archive node.
+func (r *archiveRouter) fallback(err error, what string) bool {
+	if !errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	log.Debugf("%s not found in the full node, reading it from the archive node %s", what, r.archiveURL)
+	return true
+}
+
+// HeaderByNumber implements ethereum.ChainReader.
+func (r *archiveRouter) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := r.client(number).HeaderByNumber(ctx, number)
+	if err == nil && number == nil {
+		r.setHead(header.Number)
+	}
+	return header, err
+}
+
+// BlockByNumber implements ethereum.ChainReader.
+func (r *archiveRouter) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	block, err := r.client(number).BlockByNumber(ctx, number)
+	if err == nil && number == nil {
+		r.setHead(block.Number())
+	}
+	return block, err
+}
+
+// HeaderByHash implements ethereum.ChainReader.
+func (r *archiveRouter) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	header, err := r.ChainBackend.HeaderByHash(ctx, hash)
+	if err != nil && r.fallback(err, "header "+hash.String()) {
+		return r.archive.HeaderByHash(ctx, hash)
+	}
+	return header, err
+}
+
+// BlockByHash implements ethereum.ChainReader.
+func (r *archiveRouter) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	block, err := r.ChainBackend.BlockByHash(ctx, hash)
+	if err != nil && r.fallback(err, "block "+hash.String()) {
+		return r.archive.BlockByHash(ctx, hash)
+	}
+	return block, err
+}
+
+// TransactionByHash implements ethereum.TransactionReader.
+func (r *archiveRouter) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	tx, isPending, err := r.ChainBackend.TransactionByHash(ctx, txHash)
+	if err != nil && r.fallback(err, "tx "+txHash.String()) {
+		return r.archive.TransactionByHash(ctx, txHash)
+	}
+	return tx, isPending, err
+}
+
+// TransactionReceipt implements ethereum.TransactionReader.
+func (r *archiveRouter) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := r.ChainBackend.TransactionReceipt(ctx, txHash)
+	if err != nil && r.fallback(err, "receipt "+txHash.String()) {
+		return r.archive.TransactionReceipt(ctx, txHash)
+	}
+	return receipt, err
+}
+
+// FilterLogs implements ethereum.LogFilterer. The logs of a block hash and the ranges starting at a historical
+// block are read from the archive node when the full node doesn't have them.
+func (r *archiveRouter) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if query.BlockHash == nil && r.historical(query.FromBlock) {
+		return r.archive.FilterLogs(ctx, query)
+	}
+	logs, err := r.ChainBackend.FilterLogs(ctx, query)
+	if err != nil && query.BlockHash != nil && r.fallback(err, "logs of the block "+query.BlockHash.String()) {
+		return r.archive.FilterLogs(ctx, query)
+	}
+	return logs, err
+}
+
+// CallContract implements ethereum.ContractCaller.
+func (r *archiveRouter) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return r.client(blockNumber).CallContract(ctx, call, blockNumber)
+}
+
+// BalanceAt implements ethereum.ChainStateReader.
+func (r *archiveRouter) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return r.client(blockNumber).BalanceAt(ctx, account, blockNumber)
+}
+
+// StorageAt implements ethereum.ChainStateReader.
+func (r *archiveRouter) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return r.client(blockNumber).StorageAt(ctx, account, key, blockNumber)
+}
+
+// CodeAt implements ethereum.ChainStateReader.
+func (r *archiveRouter) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return r.client(blockNumber).CodeAt(ctx, account, blockNumber)
+}
+
+// NonceAt implements ethereum.ChainStateReader.
+func (r *archiveRouter) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return r.client(blockNumber).NonceAt(ctx, account, blockNumber)
+}
+
+// ChainID returns the chain id of the full node.
+func (r *archiveRouter) ChainID(ctx context.Context) (*big.Int, error) {
+	reader, ok := r.ChainBackend.(interface {
+		ChainID(ctx context.Context) (*big.Int, error)
+	})
+	if !ok {
+		return nil, errors.New("the full node doesn't provide the chain id")
+	}
+	return reader.ChainID(ctx)
+}
+
+// CallContext implements rpcCaller. The calls are sent to the full node, and to the archive node when the full node
+// returns an error, e.g. the traces of the txs whose state the full node doesn't keep anymore.
+func (r *archiveRouter) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	err := r.rpc.CallContext(ctx, result, method, args...)
+	var rpcErr rpc.Error
+	if err == nil || !errors.As(err, &rpcErr) {
+		return err
+	}
+	log.Debugf("%s failed in the full node, calling the archive node %s: %v", method, r.archiveURL, err)
+	return r.archiveRPC.CallContext(ctx, result, method, args...)
+}
@@ -0,0 +1,191 @@
+package etherman
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmbridge"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// nodeClient is a node with the blocks from its first block to its head, counting the requests it receives.
+type nodeClient struct {
+	ChainBackend
+	first, head uint64
+	requests    int
+}
+
+func (c *nodeClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.requests++
+	if number == nil {
+		number = new(big.Int).SetUint64(c.head)
+	}
+	return &types.Header{Number: number}, nil
+}
+
+func (c *nodeClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.requests++
+	if txHash.Big().Uint64() < c.first {
+		return nil, ethereum.NotFound
+	}
+	return &types.Receipt{BlockNumber: txHash.Big()}, nil
+}
+
+func (c *nodeClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	c.requests++
+	return nil, nil
+}
+
+func (c *nodeClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.requests++
+	return common.BigToHash(new(big.Int).SetUint64(c.head)).Bytes(), nil
+}
+
+// rpcNode is the rpc client of a node, failing the calls it can't answer.
+type rpcNode struct {
+	err   error
+	calls int
+}
+
+func (n *rpcNode) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	n.calls++
+	return n.err
+}
+
+// nodeError is an error returned by a node, e.g. the traces of a pruned state.
+type nodeError string
+
+func (e nodeError) Error() string  { return string(e) }
+func (e nodeError) ErrorCode() int { return -32000 }
+
+func TestArchiveRouter(t *testing.T) {
+	ctx := context.Background()
+	full, archive := &nodeClient{first: 900, head: 1000}, &nodeClient{head: 1000}
+	r := &archiveRouter{ChainBackend: full, archive: archive, archiveURL: "archive", depth: 64}
+
+	// The blocks are not historical until the head is read
+	_, err := r.HeaderByNumber(ctx, big.NewInt(10))
+	require.NoError(t, err)
+	require.Equal(t, 1, full.requests)
+	_, err = r.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), r.head)
+
+	// The blocks older than the depth are read from the archive node
+	for _, number := range []int64{936, 999, -1} {
+		_, err = r.HeaderByNumber(ctx, big.NewInt(number))
+		require.NoError(t, err)
+	}
+	_, err = r.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(950)})
+	require.NoError(t, err)
+	require.Equal(t, 6, full.requests)
+	require.Zero(t, archive.requests)
+	_, err = r.HeaderByNumber(ctx, big.NewInt(935))
+	require.NoError(t, err)
+	_, err = r.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(10), ToBlock: big.NewInt(20)})
+	require.NoError(t, err)
+	require.Equal(t, 2, archive.requests)
+
+	// The receipts pruned by the full node are read from the archive node
+	receipt, err := r.TransactionReceipt(ctx, common.BigToHash(big.NewInt(950)))
+	require.NoError(t, err)
+	require.Equal(t, int64(950), receipt.BlockNumber.Int64())
+	require.Equal(t, 2, archive.requests)
+	receipt, err = r.TransactionReceipt(ctx, common.BigToHash(big.NewInt(5)))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), receipt.BlockNumber.Int64())
+	require.Equal(t, 3, archive.requests)
+}
+
+func TestArchiveRouterBindings(t *testing.T) {
+	ctx := context.Background()
+	full, archive := &nodeClient{head: 1000}, &nodeClient{head: 1}
+	r := &archiveRouter{ChainBackend: full, archive: archive, archiveURL: "archive", depth: 64, head: 1000}
+
+	// The calls of the bindings at the historical blocks are sent to the archive node
+	bridge, err := polygonzkevmbridge.NewPolygonzkevmbridge(common.HexToAddress("0x1"), r)
+	require.NoError(t, err)
+	count, err := bridge.DepositCount(&bind.CallOpts{Context: ctx, BlockNumber: big.NewInt(10)})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), count)
+	require.Equal(t, 1, archive.requests)
+	count, err = bridge.DepositCount(&bind.CallOpts{Context: ctx, BlockNumber: big.NewInt(999)})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000), count)
+	require.Equal(t, 1, full.requests)
+
+	// The rpc calls failed by the full node are sent to the archive node
+	fullRPC, archiveRPC := &rpcNode{err: nodeError("missing trie node")}, &rpcNode{}
+	r.rpc, r.archiveRPC = fullRPC, archiveRPC
+	require.NoError(t, r.CallContext(ctx, nil, "debug_traceTransaction"))
+	require.Equal(t, 1, archiveRPC.calls)
+	// The connection errors are not
+	fullRPC.err = errors.New("connection refused")
+	require.ErrorContains(t, r.CallContext(ctx, nil, "debug_traceTransaction"), "connection refused")
+	require.Equal(t, 1, archiveRPC.calls)
+}
+
+// gasNodeClient is a full node suggesting the fees of the txs.
+type gasNodeClient struct {
+	nodeClient
+}
+
+func (c *gasNodeClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1101), nil
+}
+
+func (c *gasNodeClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(20), nil
+}
+
+func (c *gasNodeClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(2), nil
+}
+
+func TestArchiveRouterGas(t *testing.T) {
+	ctx := context.Background()
+	// The chain id and the fees asserted by the client are read from the full node behind the router
+	client := &Client{EtherClient: &archiveRouter{ChainBackend: &gasNodeClient{}, archive: &nodeClient{}, archiveURL: "archive", depth: 64}}
+	chainID, err := client.ChainID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1101), chainID)
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(20), gasPrice)
+	tip, err := client.SuggestGasTipCap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2), tip)
+
+	client = &Client{EtherClient: struct{ ethClienter }{}}
+	_, err = client.SuggestGasTipCap(ctx)
+	require.ErrorContains(t, err, "gas tip cap")
+}
+
+func TestArchiveConfig(t *testing.T) {
+	l2URLs := []string{"http://l2-a", "http://l2-b"}
+	require.NoError(t, ArchiveConfig{}.Validate(l2URLs))
+	cfg := ArchiveConfig{L1URL: "http://archive-l1", L2URLs: []string{"", "http://archive-l2-b"}, Depth: 128}
+	require.NoError(t, cfg.Validate(l2URLs))
+	require.Equal(t, "", cfg.l2URL("http://l2-a", l2URLs))
+	require.Equal(t, "http://archive-l2-b", cfg.l2URL("http://l2-b", l2URLs))
+	require.Error(t, cfg.Validate(l2URLs[:1]))
+	cfg.Depth = 0
+	require.Error(t, cfg.Validate(l2URLs))
+
+	// Without archive node the requests are sent to the full node
+	rpcClient, err := rpc.DialHTTP("http://localhost:8545")
+	require.NoError(t, err)
+	full := ethclient.NewClient(rpcClient)
+	client, caller, err := newArchiveRouter(full, "", 128, ThrottleConfig{}, CaptureConfig{}, RetryConfig{})
+	require.NoError(t, err)
+	require.Equal(t, full, client)
+	require.Equal(t, rpcClient, caller)
+}
@@ -0,0 +1,49 @@
+package etherman
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrBatchesNotSupported is returned when the client can't read the batches of the network
+var ErrBatchesNotSupported = errors.New("batches not supported by the client")
+
+// BatchNumberByBlockNumber returns the number of the batch of a L2 block, with the zkevm_batchNumberByBlockNumber
+// method of the zkEVM nodes. It returns ethereum.NotFound while the block is not in a batch.
+func (etherMan *Client) BatchNumberByBlockNumber(ctx context.Context, blockNumber uint64) (uint64, error) {
+	if etherMan.rpcClient == nil {
+		return 0, ErrBatchesNotSupported
+	}
+	var batchNum *hexutil.Uint64
+	if err := etherMan.rpcClient.CallContext(ctx, &batchNum, "zkevm_batchNumberByBlockNumber", hexutil.Uint64(blockNumber)); err != nil {
+		return 0, providerError(err)
+	}
+	if batchNum == nil {
+		return 0, ethereum.NotFound
+	}
+	return uint64(*batchNum), nil
+}
+
+// VirtualBatchNumber returns the number of the last batch virtualized, sequenced in L1.
+func (etherMan *Client) VirtualBatchNumber(ctx context.Context) (uint64, error) {
+	return etherMan.batchNumber(ctx, "zkevm_virtualBatchNumber")
+}
+
+// VerifiedBatchNumber returns the number of the last batch verified in L1.
+func (etherMan *Client) VerifiedBatchNumber(ctx context.Context) (uint64, error) {
+	return etherMan.batchNumber(ctx, "zkevm_verifiedBatchNumber")
+}
+
+func (etherMan *Client) batchNumber(ctx context.Context, method string) (uint64, error) {
+	if etherMan.rpcClient == nil {
+		return 0, ErrBatchesNotSupported
+	}
+	var batchNum hexutil.Uint64
+	if err := etherMan.rpcClient.CallContext(ctx, &batchNum, method); err != nil {
+		return 0, providerError(err)
+	}
+	return uint64(batchNum), nil
+}
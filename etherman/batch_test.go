@@ -0,0 +1,55 @@
+package etherman
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// zkevmService serves the batches of a L2 network whose blocks are in the batch of their number divided by 10.
+type zkevmService struct {
+	head uint64
+}
+
+func (s *zkevmService) BatchNumberByBlockNumber(blockNumber hexutil.Uint64) *hexutil.Uint64 {
+	if uint64(blockNumber) > s.head {
+		return nil
+	}
+	batchNum := blockNumber / 10 //nolint:gomnd
+	return &batchNum
+}
+
+func (s *zkevmService) VirtualBatchNumber() hexutil.Uint64 {
+	return 4
+}
+
+func (s *zkevmService) VerifiedBatchNumber() hexutil.Uint64 {
+	return 2
+}
+
+func TestBatchNumbers(t *testing.T) {
+	ctx := context.Background()
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("zkevm", &zkevmService{head: 100}))
+	defer srv.Stop()
+	client := &Client{rpcClient: rpc.DialInProc(srv)}
+
+	batchNum, err := client.BatchNumberByBlockNumber(ctx, 35)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), batchNum)
+	_, err = client.BatchNumberByBlockNumber(ctx, 101)
+	require.ErrorIs(t, err, ethereum.NotFound)
+	virtual, err := client.VirtualBatchNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), virtual)
+	verified, err := client.VerifiedBatchNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), verified)
+
+	_, err = (&Client{}).VerifiedBatchNumber(ctx)
+	require.ErrorIs(t, err, ErrBatchesNotSupported)
+}
@@ -0,0 +1,135 @@
+package etherman
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// callCache keeps the results of the rpc calls that return immutable data, so they are
+// not requested again to the provider. A nil callCache disables the caching.
+type callCache struct {
+	blocksByHash   *lru.Cache[common.Hash, *types.Block]
+	blocksByNumber *lru.Cache[uint64, *types.Block]
+	tokenDecimals  *lru.Cache[common.Address, uint8]
+	contractCode   *lru.Cache[common.Address, bool]
+}
+
+func newCallCache(cfg CacheConfig) (*callCache, error) {
+	if cfg.Size <= 0 {
+		return nil, nil
+	}
+	blocksByHash, err := lru.New[common.Hash, *types.Block](cfg.Size)
+	if err != nil {
+		return nil, err
+	}
+	blocksByNumber, err := lru.New[uint64, *types.Block](cfg.Size)
+	if err != nil {
+		return nil, err
+	}
+	tokenDecimals, err := lru.New[common.Address, uint8](cfg.Size)
+	if err != nil {
+		return nil, err
+	}
+	contractCode, err := lru.New[common.Address, bool](cfg.Size)
+	if err != nil {
+		return nil, err
+	}
+	return &callCache{
+		blocksByHash:   blocksByHash,
+		blocksByNumber: blocksByNumber,
+		tokenDecimals:  tokenDecimals,
+		contractCode:   contractCode,
+	}, nil
+}
+
+func (c *callCache) getBlockByHash(hash common.Hash) (*types.Block, bool) {
+	if c == nil {
+		return nil, false
+	}
+	return c.blocksByHash.Get(hash)
+}
+
+func (c *callCache) addBlockByHash(block *types.Block) {
+	if c == nil {
+		return
+	}
+	c.blocksByHash.Add(block.Hash(), block)
+}
+
+func (c *callCache) getBlockByNumber(number uint64) (*types.Block, bool) {
+	if c == nil {
+		return nil, false
+	}
+	return c.blocksByNumber.Get(number)
+}
+
+func (c *callCache) addBlockByNumber(block *types.Block) {
+	if c == nil {
+		return
+	}
+	c.blocksByNumber.Add(block.NumberU64(), block)
+	c.blocksByHash.Add(block.Hash(), block)
+}
+
+func (c *callCache) getTokenDecimals(token common.Address) (uint8, bool) {
+	if c == nil {
+		return 0, false
+	}
+	return c.tokenDecimals.Get(token)
+}
+
+func (c *callCache) addTokenDecimals(token common.Address, decimals uint8) {
+	if c == nil {
+		return
+	}
+	c.tokenDecimals.Add(token, decimals)
+}
+
+func (c *callCache) hasCode(addr common.Address) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.contractCode.Get(addr)
+	return ok
+}
+
+func (c *callCache) addCode(addr common.Address) {
+	if c == nil {
+		return
+	}
+	c.contractCode.Add(addr, true)
+}
+
+// cacheState is the exported state of the callCache. Only the small entries are exported,
+// the blocks are cheap to get again compared to the size they would take in the state file.
+type cacheState struct {
+	TokenDecimals map[common.Address]uint8 `json:"tokenDecimals"`
+	ContractCode  []common.Address         `json:"contractCode"`
+}
+
+func (c *callCache) export() cacheState {
+	state := cacheState{TokenDecimals: make(map[common.Address]uint8)}
+	if c == nil {
+		return state
+	}
+	for _, token := range c.tokenDecimals.Keys() {
+		if decimals, ok := c.tokenDecimals.Peek(token); ok {
+			state.TokenDecimals[token] = decimals
+		}
+	}
+	state.ContractCode = c.contractCode.Keys()
+	return state
+}
+
+func (c *callCache) restore(state cacheState) {
+	if c == nil {
+		return
+	}
+	for token, decimals := range state.TokenDecimals {
+		c.addTokenDecimals(token, decimals)
+	}
+	for _, addr := range state.ContractCode {
+		c.addCode(addr)
+	}
+}
@@ -0,0 +1,265 @@
+package etherman
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// captureFileExt is the extension of the files of the captured rpc interactions
+const captureFileExt = ".json"
+
+// CaptureConfig represents the configuration of the debug capture of the rpc interactions with the providers
+type CaptureConfig struct {
+	// SampleRate is the fraction of the rpc requests captured, from 0 to 1. 0 disables the capture
+	SampleRate float64 `mapstructure:"SampleRate"`
+	// MethodSampleRates overrides the sample rate of the methods, e.g. { eth_getLogs = 1 }. A batch is captured
+	// with the highest rate of its methods
+	MethodSampleRates map[string]float64 `mapstructure:"MethodSampleRates"`
+	// Dir is the directory the captured interactions are stored in, a file each
+	Dir string `mapstructure:"Dir"`
+	// MaxFiles is the max number of captured interactions kept in the directory, the oldest are deleted. 0 keeps all
+	MaxFiles int `mapstructure:"MaxFiles"`
+	// MaxBodySize is the max size in bytes of the request and the response bodies stored, the larger are truncated
+	MaxBodySize int `mapstructure:"MaxBodySize"`
+}
+
+func (cfg CaptureConfig) enabled() bool {
+	if cfg.SampleRate > 0 {
+		return true
+	}
+	for _, rate := range cfg.MethodSampleRates {
+		if rate > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the configuration.
+func (cfg CaptureConfig) Validate() error {
+	if !cfg.enabled() {
+		return nil
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return fmt.Errorf("the sample rate of the rpc capture must be between 0 and 1")
+	}
+	for method, rate := range cfg.MethodSampleRates {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("the sample rate of the rpc capture of %s must be between 0 and 1", method)
+		}
+	}
+	if cfg.Dir == "" {
+		return fmt.Errorf("the directory of the rpc capture is empty")
+	}
+	return nil
+}
+
+// RPCCapture is a captured rpc interaction with a provider. The url keeps only the scheme and the host, the
+// credentials of the provider are usually in the path, the query or the user info.
+type RPCCapture struct {
+	Time       time.Time       `json:"time"`
+	URL        string          `json:"url"`
+	Methods    []string        `json:"methods"`
+	Duration   string          `json:"duration"`
+	StatusCode int             `json:"status_code,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Request    json.RawMessage `json:"request,omitempty"`
+	Response   json.RawMessage `json:"response,omitempty"`
+	// Truncated marks the bodies larger than the max body size, which are stored as a string
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ReadCaptures reads the rpc interactions captured in the directory, the oldest first.
+func ReadCaptures(dir string) ([]RPCCapture, error) {
+	files, err := captureFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	captures := make([]RPCCapture, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var c RPCCapture
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("error decoding the rpc capture %s: %w", file, err)
+		}
+		captures = append(captures, c)
+	}
+	return captures, nil
+}
+
+// captureFiles returns the files of the captured interactions in the directory, the oldest first.
+func captureFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+captureFileExt))
+	if err != nil {
+		return nil, err
+	}
+	// The names start with the time of the capture
+	sort.Strings(files)
+	return files, nil
+}
+
+// sanitizeURL returns the scheme and the host of the url of a provider, without its credentials.
+func sanitizeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "redacted"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// captureTransport stores a sample of the json rpc requests and their responses, for the offline inspection of the
+// responses a provider decodes differently.
+type captureTransport struct {
+	next   http.RoundTripper
+	url    string
+	cfg    CaptureConfig
+	sample func() float64
+
+	mu  sync.Mutex
+	seq uint64
+}
+
+func newCaptureTransport(next http.RoundTripper, rawURL string, cfg CaptureConfig) (*captureTransport, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error creating the directory of the rpc capture: %w", err)
+	}
+	return &captureTransport{
+		next:   next,
+		url:    sanitizeURL(rawURL),
+		cfg:    cfg,
+		sample: rand.Float64, //nolint:gosec
+	}, nil
+}
+
+// rate returns the sample rate of a request with the methods.
+func (t *captureTransport) rate(methods []string) float64 {
+	var rate float64
+	for _, method := range methods {
+		r, found := t.cfg.MethodSampleRates[method]
+		if !found {
+			r = t.cfg.SampleRate
+		}
+		if r > rate {
+			rate = r
+		}
+	}
+	return rate
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	methods := requestMethods(body)
+	if t.sample() >= t.rate(methods) {
+		return t.next.RoundTrip(req)
+	}
+	c := RPCCapture{
+		Time:    time.Now(),
+		URL:     t.url,
+		Methods: methods,
+	}
+	c.Request = t.body(&c, body)
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		c.Duration, c.Error = time.Since(c.Time).String(), err.Error()
+		t.store(c)
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	c.Duration, c.StatusCode = time.Since(c.Time).String(), resp.StatusCode
+	c.Response = t.body(&c, respBody)
+	if err != nil {
+		// The response is not returned with the error, its body is already closed
+		c.Error = err.Error()
+		t.store(c)
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	t.store(c)
+	return resp, nil
+}
+
+// body returns a body to store, as a string when it's truncated or it's not json.
+func (t *captureTransport) body(c *RPCCapture, body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if t.cfg.MaxBodySize > 0 && len(body) > t.cfg.MaxBodySize {
+		c.Truncated = true
+		body = body[:t.cfg.MaxBodySize]
+	} else if json.Valid(body) {
+		return body
+	}
+	s, _ := json.Marshal(string(body))
+	return s
+}
+
+// store writes the capture to its file, deleting the oldest files beyond the max.
+func (t *captureTransport) store(c RPCCapture) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		log.Warnf("error encoding the rpc capture: %v", err)
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	method := "unknown"
+	if len(c.Methods) > 0 && c.Methods[0] != "" {
+		method = c.Methods[0]
+	}
+	name := fmt.Sprintf("%020d-%06d-%s%s", c.Time.UnixNano(), t.seq%1000000, strings.Map(fileNameRune, method), captureFileExt)
+	if err := os.WriteFile(filepath.Join(t.cfg.Dir, name), data, 0o600); err != nil {
+		log.Warnf("error storing the rpc capture: %v", err)
+		return
+	}
+	if t.cfg.MaxFiles <= 0 {
+		return
+	}
+	files, err := captureFiles(t.cfg.Dir)
+	if err != nil {
+		log.Warnf("error listing the rpc captures: %v", err)
+		return
+	}
+	for i := 0; i < len(files)-t.cfg.MaxFiles; i++ {
+		if err := os.Remove(files[i]); err != nil {
+			log.Warnf("error deleting the rpc capture %s: %v", files[i], err)
+		}
+	}
+}
+
+// fileNameRune keeps the runes of a method safe in a file name.
+func fileNameRune(r rune) rune {
+	if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+		return r
+	}
+	return '-'
+}
@@ -0,0 +1,111 @@
+package etherman
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmbridge"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// Claim interfaces of the releases of the bridge contract
+const (
+	// ClaimVersionV1 is the claim of PolygonZkEVMBridge, with the deposit count and its proof in the local exit tree
+	ClaimVersionV1 = "v1"
+	// ClaimVersionV2 is the claim of PolygonZkEVMBridgeV2, with the global index and the proofs in the local exit
+	// tree and in the rollup exit tree
+	ClaimVersionV2 = "v2"
+)
+
+// claimV2ABI is the claim functions of PolygonZkEVMBridgeV2
+const claimV2ABI = `[
+	{"type": "function", "name": "claimAsset", "stateMutability": "nonpayable", "outputs": [], "inputs": [
+		{"name": "smtProofLocalExitRoot", "type": "bytes32[32]"}, {"name": "smtProofRollupExitRoot", "type": "bytes32[32]"},
+		{"name": "globalIndex", "type": "uint256"}, {"name": "mainnetExitRoot", "type": "bytes32"},
+		{"name": "rollupExitRoot", "type": "bytes32"}, {"name": "originNetwork", "type": "uint32"},
+		{"name": "originTokenAddress", "type": "address"}, {"name": "destinationNetwork", "type": "uint32"},
+		{"name": "destinationAddress", "type": "address"}, {"name": "amount", "type": "uint256"},
+		{"name": "metadata", "type": "bytes"}]},
+	{"type": "function", "name": "claimMessage", "stateMutability": "nonpayable", "outputs": [], "inputs": [
+		{"name": "smtProofLocalExitRoot", "type": "bytes32[32]"}, {"name": "smtProofRollupExitRoot", "type": "bytes32[32]"},
+		{"name": "globalIndex", "type": "uint256"}, {"name": "mainnetExitRoot", "type": "bytes32"},
+		{"name": "rollupExitRoot", "type": "bytes32"}, {"name": "originNetwork", "type": "uint32"},
+		{"name": "originAddress", "type": "address"}, {"name": "destinationNetwork", "type": "uint32"},
+		{"name": "destinationAddress", "type": "address"}, {"name": "amount", "type": "uint256"},
+		{"name": "metadata", "type": "bytes"}]}
+]`
+
+// The ABIs of the claim interfaces, parsed once as they are used to encode and decode every claim
+var bridgeV1ABI, bridgeV2ABI *abi.ABI
+
+func init() {
+	var err error
+	if bridgeV1ABI, err = polygonzkevmbridge.PolygonzkevmbridgeMetaData.GetAbi(); err != nil {
+		panic(fmt.Sprintf("failed to parse the PolygonZkEVMBridge ABI: %v", err))
+	}
+	v2ABI, err := abi.JSON(strings.NewReader(claimV2ABI))
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse the PolygonZkEVMBridgeV2 ABI: %v", err))
+	}
+	bridgeV2ABI = &v2ABI
+}
+
+// IsClaimVersion returns if the claim interface is supported.
+func IsClaimVersion(version string) bool {
+	return version == ClaimVersionV1 || version == ClaimVersionV2
+}
+
+// GlobalIndex returns the global index of a deposit, the argument of the v2 claims: the mainnet flag in the bit 64
+// for the L1 deposits, or the rollup index, the network id - 1, in the bits 32 to 63, and the deposit count.
+func GlobalIndex(networkID, depositCount uint) *big.Int {
+	globalIndex := new(big.Int).SetUint64(uint64(depositCount))
+	if networkID == 0 {
+		return globalIndex.SetBit(globalIndex, 64, 1) //nolint:gomnd
+	}
+	return globalIndex.Or(globalIndex, new(big.Int).Lsh(new(big.Int).SetUint64(uint64(networkID-1)), 32)) //nolint:gomnd
+}
+
+// DecodeGlobalIndex returns the parts of a global index: the mainnet flag, the rollup index and the local index, the
+// deposit count in the exit tree of its network. It fails if a bit out of the parts is set, or if the rollup index
+// of a L1 deposit is not 0.
+func DecodeGlobalIndex(globalIndex *big.Int) (mainnetFlag bool, rollupIndex, localIndex uint32, err error) {
+	if globalIndex.Sign() < 0 || globalIndex.BitLen() > 65 { //nolint:gomnd
+		return false, 0, 0, fmt.Errorf("invalid global index %s, it has more than 65 bits", globalIndex.String())
+	}
+	mainnetFlag = globalIndex.Bit(64) == 1                                           //nolint:gomnd
+	rollupIndex = uint32(new(big.Int).Rsh(globalIndex, 32).Uint64())                 //nolint:gomnd
+	localIndex = uint32(new(big.Int).And(globalIndex, big.NewInt(1<<32-1)).Uint64()) //nolint:gomnd
+	if mainnetFlag && rollupIndex != 0 {
+		return false, 0, 0, fmt.Errorf("invalid global index %s, the rollup index of a mainnet deposit must be 0", globalIndex.String())
+	}
+	return mainnetFlag, rollupIndex, localIndex, nil
+}
+
+// GlobalIndexNetworkID returns the network id of the deposits of the global indexes with the mainnet flag and the
+// rollup index.
+func GlobalIndexNetworkID(mainnetFlag bool, rollupIndex uint32) uint {
+	if mainnetFlag {
+		return 0
+	}
+	return uint(rollupIndex) + 1
+}
+
+// PackClaim encodes the claimAsset or claimMessage call of the deposit with the claim interface of the version.
+// The rollup proof and the global index are only used by the v2 claims.
+func PackClaim(version string, deposit *Deposit, smtProof, rollupProof [32][32]byte, globalExitRoot *GlobalExitRoot) ([]byte, error) {
+	method := "claimAsset"
+	if deposit.LeafType == leafTypeMessage {
+		method = "claimMessage"
+	}
+	switch version {
+	case ClaimVersionV1:
+		return bridgeV1ABI.Pack(method, smtProof, uint32(deposit.DepositCount), globalExitRoot.ExitRoots[0], globalExitRoot.ExitRoots[1],
+			uint32(deposit.OriginalNetwork), deposit.OriginalAddress, uint32(deposit.DestinationNetwork), deposit.DestinationAddress, deposit.Amount, deposit.Metadata)
+	case ClaimVersionV2:
+		return bridgeV2ABI.Pack(method, smtProof, rollupProof, GlobalIndex(deposit.NetworkID, deposit.DepositCount), globalExitRoot.ExitRoots[0], globalExitRoot.ExitRoots[1],
+			uint32(deposit.OriginalNetwork), deposit.OriginalAddress, uint32(deposit.DestinationNetwork), deposit.DestinationAddress, deposit.Amount, deposit.Metadata)
+	default:
+		return nil, fmt.Errorf("unknown claim version %s", version)
+	}
+}
@@ -0,0 +1,65 @@
+package etherman
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalIndex(t *testing.T) {
+	require.Equal(t, "18446744073709551621", GlobalIndex(0, 5).String())
+	require.Equal(t, uint64(5), GlobalIndex(1, 5).Uint64())
+	require.Equal(t, uint64(1<<32|5), GlobalIndex(2, 5).Uint64())
+}
+
+func TestDecodeGlobalIndex(t *testing.T) {
+	for _, c := range []struct {
+		networkID, depositCount uint
+		mainnetFlag             bool
+		rollupIndex             uint32
+	}{
+		{0, 5, true, 0},
+		{1, 5, false, 0},
+		{2, 1<<32 - 1, false, 1},
+	} {
+		mainnetFlag, rollupIndex, localIndex, err := DecodeGlobalIndex(GlobalIndex(c.networkID, c.depositCount))
+		require.NoError(t, err)
+		require.Equal(t, c.mainnetFlag, mainnetFlag)
+		require.Equal(t, c.rollupIndex, rollupIndex)
+		require.Equal(t, uint32(c.depositCount), localIndex)
+		require.Equal(t, c.networkID, GlobalIndexNetworkID(mainnetFlag, rollupIndex))
+	}
+
+	// The bits out of the parts, and a rollup index with the mainnet flag, are rejected
+	_, _, _, err := DecodeGlobalIndex(new(big.Int).Lsh(big.NewInt(1), 65))
+	require.Error(t, err)
+	_, _, _, err = DecodeGlobalIndex(new(big.Int).Or(GlobalIndex(0, 5), big.NewInt(1<<32)))
+	require.Error(t, err)
+	_, _, _, err = DecodeGlobalIndex(big.NewInt(-1))
+	require.Error(t, err)
+}
+
+func TestPackClaim(t *testing.T) {
+	deposit := &Deposit{Amount: big.NewInt(1)}
+	ger := &GlobalExitRoot{ExitRoots: []common.Hash{{}, {}}}
+	for _, c := range []struct {
+		version  string
+		leafType uint8
+		selector string
+	}{
+		{ClaimVersionV1, 0, "0x2cffd02e"},
+		{ClaimVersionV1, leafTypeMessage, "0x2d2c9d94"},
+		{ClaimVersionV2, 0, "0xccaa2d11"},
+		{ClaimVersionV2, leafTypeMessage, "0xf5efcd79"},
+	} {
+		deposit.LeafType = c.leafType
+		data, err := PackClaim(c.version, deposit, [32][32]byte{}, [32][32]byte{}, ger)
+		require.NoError(t, err)
+		require.Equal(t, c.selector, hexutil.Encode(data[:4]))
+	}
+	_, err := PackClaim("v3", deposit, [32][32]byte{}, [32][32]byte{}, ger)
+	require.Error(t, err)
+}
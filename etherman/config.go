@@ -4,4 +4,30 @@ package etherman
 type Config struct {
 	L1URL  string   `mapstructure:"L1URL"`
 	L2URLs []string `mapstructure:"L2URLs"`
+
+	// Cache is the configuration of the cache for the immutable chain data
+	Cache CacheConfig `mapstructure:"Cache"`
+	// L1Throttle is the requests budget of the L1 provider
+	L1Throttle ThrottleConfig `mapstructure:"L1Throttle"`
+	// L2Throttle is the requests budget of each L2 provider
+	L2Throttle ThrottleConfig `mapstructure:"L2Throttle"`
+	// L1Quorum is the quorum of the global exit root updates and the verified batches read from L1
+	L1Quorum QuorumConfig `mapstructure:"L1Quorum"`
+	// L2Quorum is the quorum of the trusted state read from the first L2 provider
+	L2Quorum QuorumConfig `mapstructure:"L2Quorum"`
+	// Capture is the debug capture of a sample of the rpc interactions with the providers
+	Capture CaptureConfig `mapstructure:"Capture"`
+	// Retry is the retry profile of the requests to the providers failed by transient errors
+	Retry RetryConfig `mapstructure:"Retry"`
+	// Archive is the routing of the requests of the historical blocks to archive nodes
+	Archive ArchiveConfig `mapstructure:"Archive"`
+}
+
+// CacheConfig represents the configuration of the etherman call cache
+type CacheConfig struct {
+	// Size is the max number of entries kept for each kind of cached data. 0 disables the cache
+	Size int `mapstructure:"Size"`
+	// FinalizedBlockDepth is the number of confirmations after which a block is considered final
+	// and can be cached by its number
+	FinalizedBlockDepth uint64 `mapstructure:"FinalizedBlockDepth"`
 }
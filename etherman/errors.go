@@ -0,0 +1,41 @@
+package etherman
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// limitExceededCode is the json rpc error code used by the providers when a request exceeds their limits (EIP-1474)
+const limitExceededCode = -32005
+
+// providerError tags the errors of the rpc provider with the domain errors, so the callers can decide on
+// retries without matching the error text.
+func providerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return gerror.Wrap(gerror.ErrProviderLimit, err)
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && (rpcErr.ErrorCode() == limitExceededCode || rpcErr.ErrorCode() == http.StatusTooManyRequests) {
+		return gerror.Wrap(gerror.ErrProviderLimit, err)
+	}
+	return err
+}
+
+// blockNotFoundError returns the error of a block that the provider doesn't have. The block of a log
+// that can't be found by its hash has been reorganized.
+func blockNotFoundError(err error, hash common.Hash) error {
+	if errors.Is(err, ethereum.NotFound) {
+		return gerror.Wrap(gerror.ErrReorgDetected, fmt.Errorf("block %s: %w", hash.String(), err))
+	}
+	return providerError(err)
+}
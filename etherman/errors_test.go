@@ -0,0 +1,38 @@
+package etherman
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonRPCError struct {
+	code int
+}
+
+func (e jsonRPCError) Error() string  { return "limit exceeded" }
+func (e jsonRPCError) ErrorCode() int { return e.code }
+
+func TestProviderError(t *testing.T) {
+	require.NoError(t, providerError(nil))
+
+	err := providerError(rpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"})
+	require.ErrorIs(t, err, gerror.ErrProviderLimit)
+	var httpErr rpc.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+
+	require.ErrorIs(t, providerError(jsonRPCError{code: limitExceededCode}), gerror.ErrProviderLimit)
+	require.False(t, errors.Is(providerError(jsonRPCError{code: -32000}), gerror.ErrProviderLimit))
+	require.False(t, errors.Is(providerError(rpc.HTTPError{StatusCode: http.StatusBadGateway}), gerror.ErrProviderLimit))
+
+	err = blockNotFoundError(ethereum.NotFound, common.HexToHash("0x01"))
+	require.ErrorIs(t, err, gerror.ErrReorgDetected)
+	require.ErrorIs(t, err, ethereum.NotFound)
+	require.ErrorIs(t, ErrNotFound, gerror.ErrNotFound)
+}
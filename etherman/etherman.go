@@ -2,25 +2,35 @@ package etherman
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/receipt"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
 	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmbridge"
 	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmglobalexitroot"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"golang.org/x/crypto/sha3"
 )
 
 var (
 	updateGlobalExitRootSignatureHash = crypto.Keccak256Hash([]byte("UpdateGlobalExitRoot(bytes32,bytes32)"))
+	updateL1InfoTreeSignatureHash     = crypto.Keccak256Hash([]byte("UpdateL1InfoTree(bytes32,bytes32)"))
 	transferOwnershipSignatureHash    = crypto.Keccak256Hash([]byte("OwnershipTransferred(address,address)"))
 
 	// Bridge events
@@ -28,14 +38,21 @@ var (
 	claimEventSignatureHash           = crypto.Keccak256Hash([]byte("ClaimEvent(uint32,uint32,address,address,uint256)"))
 	newWrappedTokenEventSignatureHash = crypto.Keccak256Hash([]byte("NewWrappedToken(uint32,address,address,bytes)"))
 
+	// Rollup manager events
+	verifyBatchesSignatureHash                  = crypto.Keccak256Hash([]byte("VerifyBatches(uint32,uint64,bytes32,bytes32,address)"))
+	verifyBatchesTrustedAggregatorSignatureHash = crypto.Keccak256Hash([]byte("VerifyBatchesTrustedAggregator(uint32,uint64,bytes32,bytes32,address)"))
+
 	// Proxy events
 	initializedSignatureHash    = crypto.Keccak256Hash([]byte("Initialized(uint8)"))
 	adminChangedSignatureHash   = crypto.Keccak256Hash([]byte("AdminChanged(address,address)"))
 	beaconUpgradedSignatureHash = crypto.Keccak256Hash([]byte("BeaconUpgraded(address)"))
 	upgradedSignatureHash       = crypto.Keccak256Hash([]byte("Upgraded(address)"))
 
-	// ErrNotFound is used when the object is not found
-	ErrNotFound = errors.New("Not found")
+	// ERC20 calls
+	decimalsSignature = crypto.Keccak256([]byte("decimals()"))[:4]
+
+	// ErrNotFound is used when the object is not found in the network
+	ErrNotFound = fmt.Errorf("%w in the network", gerror.ErrNotFound)
 )
 
 // EventOrder is the the type used to identify the events order
@@ -50,12 +67,25 @@ const (
 	ClaimsOrder EventOrder = "Claim"
 	// TokensOrder identifies a TokenWrapped event
 	TokensOrder EventOrder = "TokenWrapped"
+	// RollupExitLeavesOrder identifies a VerifyBatches event of the rollup manager
+	RollupExitLeavesOrder EventOrder = "RollupExitLeaf"
+
+	leafTypeMessage = uint8(1)
 )
 
 type ethClienter interface {
 	ethereum.ChainReader
 	ethereum.LogFilterer
 	ethereum.TransactionReader
+	ethereum.ContractCaller
+	ethereum.ChainStateReader
+	ethereum.GasEstimator
+}
+
+// ChainBackend reads a chain and calls the contracts of the bridge in it, e.g. a chain recorded to be replayed.
+type ChainBackend interface {
+	ethClienter
+	bind.ContractBackend
 }
 
 // Client is a simple implementation of EtherMan.
@@ -64,47 +94,108 @@ type Client struct {
 	PolygonBridge              *polygonzkevmbridge.Polygonzkevmbridge
 	PolygonZkEVMGlobalExitRoot *polygonzkevmglobalexitroot.Polygonzkevmglobalexitroot
 	SCAddresses                []common.Address
+
+	bridgeAddr          common.Address
+	rollupManagerAddr   common.Address
+	cache               *callCache
+	finalizedBlockDepth uint64
+	lastBlockNumber     uint64
+	// rpcClient is used for the calls not supported by the ethclient, nil for the simulated backend
+	rpcClient rpcCaller
+	// quorum checks the critical logs with other providers, nil if it's disabled
+	quorum *quorum
 }
 
-// NewClient creates a new etherman.
-func NewClient(cfg Config, polygonBridgeAddr, polygonZkEVMGlobalExitRootAddress common.Address) (*Client, error) {
+// NewClient creates a new etherman. The rollup manager address is zero for the contracts of a single rollup,
+// otherwise its verified batches update the rollup exit tree.
+func NewClient(cfg Config, polygonBridgeAddr, polygonZkEVMGlobalExitRootAddress, polygonRollupManagerAddress common.Address) (*Client, error) {
 	// Connect to ethereum node
-	ethClient, err := ethclient.Dial(cfg.L1URL)
+	ethClient, err := dial(cfg.L1URL, cfg.L1Throttle, cfg.Capture, cfg.Retry)
 	if err != nil {
 		log.Errorf("error connecting to %s: %+v", cfg.L1URL, err)
 		return nil, err
 	}
+	client, rpcClient, err := newArchiveRouter(ethClient, cfg.Archive.L1URL, cfg.Archive.Depth, cfg.L1Throttle, cfg.Capture, cfg.Retry)
+	if err != nil {
+		return nil, err
+	}
 	// Create smc clients
-	polygonBridge, err := polygonzkevmbridge.NewPolygonzkevmbridge(polygonBridgeAddr, ethClient)
+	polygonBridge, err := polygonzkevmbridge.NewPolygonzkevmbridge(polygonBridgeAddr, client)
 	if err != nil {
 		return nil, err
 	}
-	polygonZkEVMGlobalExitRoot, err := polygonzkevmglobalexitroot.NewPolygonzkevmglobalexitroot(polygonZkEVMGlobalExitRootAddress, ethClient)
+	polygonZkEVMGlobalExitRoot, err := polygonzkevmglobalexitroot.NewPolygonzkevmglobalexitroot(polygonZkEVMGlobalExitRootAddress, client)
 	if err != nil {
 		return nil, err
 	}
 	var scAddresses []common.Address
 	scAddresses = append(scAddresses, polygonZkEVMGlobalExitRootAddress, polygonBridgeAddr)
+	if polygonRollupManagerAddress != (common.Address{}) {
+		scAddresses = append(scAddresses, polygonRollupManagerAddress)
+	}
+	cache, err := newCallCache(cfg.Cache)
+	if err != nil {
+		return nil, err
+	}
+	quorum, err := newQuorum(cfg.L1Quorum, cfg.L1Throttle, cfg.Capture, cfg.Retry)
+	if err != nil {
+		return nil, err
+	}
 
-	return &Client{EtherClient: ethClient, PolygonBridge: polygonBridge, PolygonZkEVMGlobalExitRoot: polygonZkEVMGlobalExitRoot, SCAddresses: scAddresses}, nil
+	return &Client{EtherClient: client, PolygonBridge: polygonBridge, PolygonZkEVMGlobalExitRoot: polygonZkEVMGlobalExitRoot, SCAddresses: scAddresses,
+		bridgeAddr: polygonBridgeAddr, rollupManagerAddr: polygonRollupManagerAddress, rpcClient: rpcClient, cache: cache, finalizedBlockDepth: cfg.Cache.FinalizedBlockDepth,
+		quorum: quorum}, nil
 }
 
 // NewL2Client creates a new etherman for L2.
-func NewL2Client(url string, bridgeAddr common.Address) (*Client, error) {
+func NewL2Client(cfg Config, url string, bridgeAddr common.Address) (*Client, error) {
 	// Connect to ethereum node
-	ethClient, err := ethclient.Dial(url)
+	ethClient, err := dial(url, cfg.L2Throttle, cfg.Capture, cfg.Retry)
 	if err != nil {
 		log.Errorf("error connecting to %s: %+v", url, err)
 		return nil, err
 	}
+	client, rpcClient, err := newArchiveRouter(ethClient, cfg.Archive.l2URL(url, cfg.L2URLs), cfg.Archive.Depth, cfg.L2Throttle, cfg.Capture, cfg.Retry)
+	if err != nil {
+		return nil, err
+	}
 	// Create smc clients
-	bridge, err := polygonzkevmbridge.NewPolygonzkevmbridge(bridgeAddr, ethClient)
+	bridge, err := polygonzkevmbridge.NewPolygonzkevmbridge(bridgeAddr, client)
 	if err != nil {
 		return nil, err
 	}
 	scAddresses := []common.Address{bridgeAddr}
+	cache, err := newCallCache(cfg.Cache)
+	if err != nil {
+		return nil, err
+	}
 
-	return &Client{EtherClient: ethClient, PolygonBridge: bridge, SCAddresses: scAddresses}, nil
+	return &Client{EtherClient: client, PolygonBridge: bridge, SCAddresses: scAddresses, bridgeAddr: bridgeAddr, rpcClient: rpcClient, cache: cache, finalizedBlockDepth: cfg.Cache.FinalizedBlockDepth}, nil
+}
+
+// NewClientFromBackend creates an etherman that reads the events of the contracts from the backend instead of a
+// node. The global exit root and the rollup manager addresses are zero when the contracts are not in the network.
+func NewClientFromBackend(cfg Config, backend ChainBackend, bridgeAddr, globalExitRootAddr, rollupManagerAddr common.Address) (*Client, error) {
+	bridge, err := polygonzkevmbridge.NewPolygonzkevmbridge(bridgeAddr, backend)
+	if err != nil {
+		return nil, err
+	}
+	globalExitRoot, err := polygonzkevmglobalexitroot.NewPolygonzkevmglobalexitroot(globalExitRootAddr, backend)
+	if err != nil {
+		return nil, err
+	}
+	scAddresses := []common.Address{bridgeAddr}
+	for _, addr := range []common.Address{globalExitRootAddr, rollupManagerAddr} {
+		if addr != (common.Address{}) {
+			scAddresses = append(scAddresses, addr)
+		}
+	}
+	cache, err := newCallCache(cfg.Cache)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{EtherClient: backend, PolygonBridge: bridge, PolygonZkEVMGlobalExitRoot: globalExitRoot, SCAddresses: scAddresses,
+		bridgeAddr: bridgeAddr, rollupManagerAddr: rollupManagerAddr, cache: cache, finalizedBlockDepth: cfg.Cache.FinalizedBlockDepth}, nil
 }
 
 // GetRollupInfoByBlockRange function retrieves the Rollup information that are included in all this ethereum blocks
@@ -134,8 +225,17 @@ type Order struct {
 func (etherMan *Client) readEvents(ctx context.Context, query ethereum.FilterQuery) ([]Block, map[common.Hash][]Order, error) {
 	logs, err := etherMan.EtherClient.FilterLogs(ctx, query)
 	if err != nil {
+		return nil, nil, providerError(err)
+	}
+	if err = etherMan.quorum.checkLogs(ctx, query, logs); err != nil {
 		return nil, nil, err
 	}
+	return etherMan.ProcessLogs(ctx, logs)
+}
+
+// ProcessLogs decodes the given logs into blocks in the same way than GetRollupInfoByBlockRange does.
+// It allows to re-decode the raw logs stored in the database without fetching them again from the network.
+func (etherMan *Client) ProcessLogs(ctx context.Context, logs []types.Log) ([]Block, map[common.Hash][]Order, error) {
 	var blocks []Block
 	blocksOrder := make(map[common.Hash][]Order)
 	for _, vLog := range logs {
@@ -150,8 +250,10 @@ func (etherMan *Client) readEvents(ctx context.Context, query ethereum.FilterQue
 
 func (etherMan *Client) processEvent(ctx context.Context, vLog types.Log, blocks *[]Block, blocksOrder *map[common.Hash][]Order) error {
 	switch vLog.Topics[0] {
-	case updateGlobalExitRootSignatureHash:
+	case updateGlobalExitRootSignatureHash, updateL1InfoTreeSignatureHash:
 		return etherMan.updateGlobalExitRootEvent(ctx, vLog, blocks, blocksOrder)
+	case verifyBatchesSignatureHash, verifyBatchesTrustedAggregatorSignatureHash:
+		return etherMan.verifyBatchesEvent(ctx, vLog, blocks, blocksOrder)
 	case depositEventSignatureHash:
 		return etherMan.depositEvent(ctx, vLog, blocks, blocksOrder)
 	case claimEventSignatureHash:
@@ -174,17 +276,33 @@ func (etherMan *Client) processEvent(ctx context.Context, vLog types.Log, blocks
 		log.Debug("TransferOwnership event detected")
 		return nil
 	}
+	// The rollup manager emits many events not needed by the bridge
+	if etherMan.rollupManagerAddr != (common.Address{}) && vLog.Address == etherMan.rollupManagerAddr {
+		log.Debugf("Rollup manager event not needed: %s", vLog.Topics[0].String())
+		return nil
+	}
 	log.Warnf("Event not registered: %+v", vLog)
 	return nil
 }
 
 func (etherMan *Client) updateGlobalExitRootEvent(ctx context.Context, vLog types.Log, blocks *[]Block, blocksOrder *map[common.Hash][]Order) error {
 	log.Debug("UpdateGlobalExitRoot event detected")
-	globalExitRoot, err := etherMan.PolygonZkEVMGlobalExitRoot.ParseUpdateGlobalExitRoot(vLog)
-	if err != nil {
-		return err
+	var globalExitRoot polygonzkevmglobalexitroot.PolygonzkevmglobalexitrootUpdateGlobalExitRoot
+	if vLog.Topics[0] == updateL1InfoTreeSignatureHash {
+		// The event of the multi rollup contracts has the same exit roots, and it appends the global exit root to the L1 info tree
+		if len(vLog.Topics) != 3 { //nolint:gomnd
+			return fmt.Errorf("invalid UpdateL1InfoTree event with %d topics", len(vLog.Topics))
+		}
+		globalExitRoot.MainnetExitRoot = vLog.Topics[1]
+		globalExitRoot.RollupExitRoot = vLog.Topics[2]
+	} else {
+		ger, err := etherMan.PolygonZkEVMGlobalExitRoot.ParseUpdateGlobalExitRoot(vLog)
+		if err != nil {
+			return err
+		}
+		globalExitRoot = *ger
 	}
-	fullBlock, err := etherMan.EtherClient.BlockByHash(ctx, vLog.BlockHash)
+	fullBlock, err := etherMan.blockByHash(ctx, vLog.BlockHash)
 	if err != nil {
 		return fmt.Errorf("error getting hashParent. BlockNumber: %d. Error: %w", vLog.BlockNumber, err)
 	}
@@ -229,11 +347,12 @@ func (etherMan *Client) depositEvent(ctx context.Context, vLog types.Log, blocks
 	deposit.OriginalAddress = d.OriginAddress
 	deposit.DepositCount = uint(d.DepositCount)
 	deposit.TxHash = vLog.TxHash
+	deposit.LogIndex = vLog.Index
 	deposit.Metadata = d.Metadata
 	deposit.LeafType = d.LeafType
 
 	if len(*blocks) == 0 || ((*blocks)[len(*blocks)-1].BlockHash != vLog.BlockHash || (*blocks)[len(*blocks)-1].BlockNumber != vLog.BlockNumber) {
-		fullBlock, err := etherMan.EtherClient.BlockByHash(ctx, vLog.BlockHash)
+		fullBlock, err := etherMan.blockByHash(ctx, vLog.BlockHash)
 		if err != nil {
 			return fmt.Errorf("error getting hashParent. BlockNumber: %d. Error: %w", vLog.BlockNumber, err)
 		}
@@ -246,6 +365,7 @@ func (etherMan *Client) depositEvent(ctx context.Context, vLog types.Log, blocks
 		log.Error("Error processing deposit event. BlockHash:", vLog.BlockHash, ". BlockNumber: ", vLog.BlockNumber)
 		return fmt.Errorf("error processing Deposit event")
 	}
+	(*blocks)[len(*blocks)-1].RawLogs = append((*blocks)[len(*blocks)-1].RawLogs, vLog)
 	or := Order{
 		Name: DepositsOrder,
 		Pos:  len((*blocks)[len(*blocks)-1].Deposits) - 1,
@@ -268,9 +388,10 @@ func (etherMan *Client) claimEvent(ctx context.Context, vLog types.Log, blocks *
 	claim.OriginalAddress = c.OriginAddress
 	claim.BlockNumber = vLog.BlockNumber
 	claim.TxHash = vLog.TxHash
+	claim.LogIndex = vLog.Index
 
 	if len(*blocks) == 0 || ((*blocks)[len(*blocks)-1].BlockHash != vLog.BlockHash || (*blocks)[len(*blocks)-1].BlockNumber != vLog.BlockNumber) {
-		fullBlock, err := etherMan.EtherClient.BlockByHash(ctx, vLog.BlockHash)
+		fullBlock, err := etherMan.blockByHash(ctx, vLog.BlockHash)
 		if err != nil {
 			return fmt.Errorf("error getting hashParent. BlockNumber: %d. Error: %w", vLog.BlockNumber, err)
 		}
@@ -283,6 +404,7 @@ func (etherMan *Client) claimEvent(ctx context.Context, vLog types.Log, blocks *
 		log.Error("Error processing claim event. BlockHash:", vLog.BlockHash, ". BlockNumber: ", vLog.BlockNumber)
 		return fmt.Errorf("error processing claim event")
 	}
+	(*blocks)[len(*blocks)-1].RawLogs = append((*blocks)[len(*blocks)-1].RawLogs, vLog)
 	or := Order{
 		Name: ClaimsOrder,
 		Pos:  len((*blocks)[len(*blocks)-1].Claims) - 1,
@@ -304,7 +426,7 @@ func (etherMan *Client) tokenWrappedEvent(ctx context.Context, vLog types.Log, b
 	tokenWrapped.BlockNumber = vLog.BlockNumber
 
 	if len(*blocks) == 0 || ((*blocks)[len(*blocks)-1].BlockHash != vLog.BlockHash || (*blocks)[len(*blocks)-1].BlockNumber != vLog.BlockNumber) {
-		fullBlock, err := etherMan.EtherClient.BlockByHash(ctx, vLog.BlockHash)
+		fullBlock, err := etherMan.blockByHash(ctx, vLog.BlockHash)
 		if err != nil {
 			return fmt.Errorf("error getting hashParent. BlockNumber: %d. Error: %w", vLog.BlockNumber, err)
 		}
@@ -325,6 +447,41 @@ func (etherMan *Client) tokenWrappedEvent(ctx context.Context, vLog types.Log, b
 	return nil
 }
 
+func (etherMan *Client) verifyBatchesEvent(ctx context.Context, vLog types.Log, blocks *[]Block, blocksOrder *map[common.Hash][]Order) error {
+	log.Debug("VerifyBatches event detected")
+	// The rollup id and the aggregator are indexed, the data is the batch number, the state root and the exit root
+	const dataLen = 3 * 32
+	if len(vLog.Topics) != 3 || len(vLog.Data) != dataLen { //nolint:gomnd
+		return fmt.Errorf("invalid VerifyBatches event with %d topics and %d bytes of data", len(vLog.Topics), len(vLog.Data))
+	}
+	leaf := RollupExitLeaf{
+		BlockNumber: vLog.BlockNumber,
+		RollupID:    uint(new(big.Int).SetBytes(vLog.Topics[1][:]).Uint64()),
+		Leaf:        common.BytesToHash(vLog.Data[64:dataLen]),
+	}
+
+	if len(*blocks) == 0 || ((*blocks)[len(*blocks)-1].BlockHash != vLog.BlockHash || (*blocks)[len(*blocks)-1].BlockNumber != vLog.BlockNumber) {
+		fullBlock, err := etherMan.blockByHash(ctx, vLog.BlockHash)
+		if err != nil {
+			return fmt.Errorf("error getting hashParent. BlockNumber: %d. Error: %w", vLog.BlockNumber, err)
+		}
+		block := prepareBlock(vLog, time.Unix(int64(fullBlock.Time()), 0), fullBlock)
+		block.RollupExitLeaves = append(block.RollupExitLeaves, leaf)
+		*blocks = append(*blocks, block)
+	} else if (*blocks)[len(*blocks)-1].BlockHash == vLog.BlockHash && (*blocks)[len(*blocks)-1].BlockNumber == vLog.BlockNumber {
+		(*blocks)[len(*blocks)-1].RollupExitLeaves = append((*blocks)[len(*blocks)-1].RollupExitLeaves, leaf)
+	} else {
+		log.Error("Error processing VerifyBatches event. BlockHash:", vLog.BlockHash, ". BlockNumber: ", vLog.BlockNumber)
+		return fmt.Errorf("error processing VerifyBatches event")
+	}
+	or := Order{
+		Name: RollupExitLeavesOrder,
+		Pos:  len((*blocks)[len(*blocks)-1].RollupExitLeaves) - 1,
+	}
+	(*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash] = append((*blocksOrder)[(*blocks)[len(*blocks)-1].BlockHash], or)
+	return nil
+}
+
 func prepareBlock(vLog types.Log, t time.Time, fullBlock *types.Block) Block {
 	var block Block
 	block.BlockNumber = vLog.BlockNumber
@@ -347,21 +504,99 @@ func hash(data ...[32]byte) [32]byte {
 // HeaderByNumber returns a block header from the current canonical chain. If number is
 // nil, the latest known header is returned.
 func (etherMan *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
-	return etherMan.EtherClient.HeaderByNumber(ctx, number)
+	header, err := etherMan.EtherClient.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	if number == nil {
+		atomic.StoreUint64(&etherMan.lastBlockNumber, header.Number.Uint64())
+	}
+	return header, nil
 }
 
 // EthBlockByNumber function retrieves the ethereum block information by ethereum block number.
 func (etherMan *Client) EthBlockByNumber(ctx context.Context, blockNumber uint64) (*types.Block, error) {
+	if block, ok := etherMan.cache.getBlockByNumber(blockNumber); ok {
+		return block, nil
+	}
 	block, err := etherMan.EtherClient.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
 	if err != nil {
 		if errors.Is(err, ethereum.NotFound) || err.Error() == "block does not exist in blockchain" {
 			return nil, ErrNotFound
 		}
-		return nil, err
+		return nil, providerError(err)
+	}
+	if etherMan.isFinalized(blockNumber) {
+		etherMan.cache.addBlockByNumber(block)
+	}
+	return block, nil
+}
+
+// blockByHash retrieves the block by its hash. The block is immutable for a given hash, so it's always cached.
+func (etherMan *Client) blockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	if block, ok := etherMan.cache.getBlockByHash(hash); ok {
+		return block, nil
+	}
+	block, err := etherMan.EtherClient.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, blockNotFoundError(err, hash)
 	}
+	etherMan.cache.addBlockByHash(block)
 	return block, nil
 }
 
+// isFinalized checks if the block has enough confirmations, according to the last block seen, to not be reorged.
+func (etherMan *Client) isFinalized(blockNumber uint64) bool {
+	lastBlockNumber := atomic.LoadUint64(&etherMan.lastBlockNumber)
+	return lastBlockNumber >= etherMan.finalizedBlockDepth && blockNumber <= lastBlockNumber-etherMan.finalizedBlockDepth
+}
+
+// TokenDecimals returns the decimals of an erc20 token.
+func (etherMan *Client) TokenDecimals(ctx context.Context, token common.Address) (uint8, error) {
+	if decimals, ok := etherMan.cache.getTokenDecimals(token); ok {
+		return decimals, nil
+	}
+	result, err := etherMan.EtherClient.CallContract(ctx, ethereum.CallMsg{To: &token, Data: decimalsSignature}, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(result) != common.HashLength {
+		return 0, fmt.Errorf("invalid decimals response for token %s", token.String())
+	}
+	decimals := new(big.Int).SetBytes(result)
+	if !decimals.IsUint64() || decimals.Uint64() > math.MaxUint8 {
+		return 0, fmt.Errorf("invalid decimals value for token %s: %s", token.String(), decimals.String())
+	}
+	etherMan.cache.addTokenDecimals(token, uint8(decimals.Uint64()))
+	return uint8(decimals.Uint64()), nil
+}
+
+// HasCode checks if there is a smart contract deployed in the address. Only the addresses with code
+// are cached, because an empty address can get a contract deployed later.
+func (etherMan *Client) HasCode(ctx context.Context, addr common.Address) (bool, error) {
+	if etherMan.cache.hasCode(addr) {
+		return true, nil
+	}
+	code, err := etherMan.EtherClient.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return false, err
+	}
+	if len(code) == 0 {
+		return false, nil
+	}
+	etherMan.cache.addCode(addr)
+	return true, nil
+}
+
+// CodeHash returns the keccak256 of the code deployed in the address, zero if there is no code.
+func (etherMan *Client) CodeHash(ctx context.Context, addr common.Address) (common.Hash, error) {
+	code, err := etherMan.EtherClient.CodeAt(ctx, addr, nil)
+	if err != nil || len(code) == 0 {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(code), nil
+}
+
 // GetNetworkID gets the network ID of the dedicated chain.
 func (etherMan *Client) GetNetworkID(ctx context.Context) (uint, error) {
 	networkID, err := etherMan.PolygonBridge.NetworkID(&bind.CallOpts{Pending: false})
@@ -370,3 +605,135 @@ func (etherMan *Client) GetNetworkID(ctx context.Context) (uint, error) {
 	}
 	return uint(networkID), nil
 }
+
+// ChainID returns the chain id of the network.
+func (etherMan *Client) ChainID(ctx context.Context) (uint64, error) {
+	reader, ok := etherMan.EtherClient.(interface {
+		ChainID(ctx context.Context) (*big.Int, error)
+	})
+	if !ok {
+		return 0, fmt.Errorf("the client doesn't provide the chain id")
+	}
+	chainID, err := reader.ChainID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return chainID.Uint64(), nil
+}
+
+// SuggestGasPrice returns the gas price suggested by the node of the network.
+func (etherMan *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	reader, ok := etherMan.EtherClient.(interface {
+		SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("the client doesn't provide the gas price")
+	}
+	return reader.SuggestGasPrice(ctx)
+}
+
+// SuggestGasTipCap returns the max priority fee per gas suggested by the node of the network, for the EIP-1559 txs.
+func (etherMan *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	reader, ok := etherMan.EtherClient.(interface {
+		SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("the client doesn't provide the gas tip cap")
+	}
+	return reader.SuggestGasTipCap(ctx)
+}
+
+// SimulateClaim executes the claim of the deposit with the claim interface of the version in the bridge smart
+// contract without sending any tx. It returns if the claim succeeds, with the decoded revert reason if it fails. The
+// claims reverting without a reason get the one of the call error.
+func (etherMan *Client) SimulateClaim(ctx context.Context, from common.Address, version string, deposit *Deposit, smtProof, rollupProof [32][32]byte, globalExitRoot *GlobalExitRoot) (bool, string, error) {
+	bridgeABI, data, err := packClaim(version, deposit, smtProof, rollupProof, globalExitRoot)
+	if err != nil {
+		return false, "", err
+	}
+	_, err = etherMan.EtherClient.CallContract(ctx, ethereum.CallMsg{From: from, To: &etherMan.bridgeAddr, Data: data}, nil)
+	if err == nil {
+		return true, "", nil
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if reason := decodeRevertReason(bridgeABI, dataErr); reason != "" {
+			return false, reason, nil
+		}
+		return false, err.Error(), nil
+	}
+	if strings.Contains(err.Error(), vm.ErrExecutionReverted.Error()) {
+		return false, err.Error(), nil
+	}
+	return false, "", err
+}
+
+// BuildClaimTx returns the unsigned claim tx of the deposit with the claim interface of the version, with the gas
+// estimated for the sender, so it can be signed and sent by the claimer.
+func (etherMan *Client) BuildClaimTx(ctx context.Context, from common.Address, version string, deposit *Deposit, smtProof, rollupProof [32][32]byte, globalExitRoot *GlobalExitRoot) (*ClaimTx, error) {
+	_, data, err := packClaim(version, deposit, smtProof, rollupProof, globalExitRoot)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := etherMan.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := etherMan.EtherClient.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &etherMan.bridgeAddr, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("error estimating the claim gas: %w", providerError(err))
+	}
+	return &ClaimTx{
+		ChainID: chainID,
+		To:      etherMan.bridgeAddr,
+		Data:    data,
+		Gas:     gas,
+		Value:   big.NewInt(0),
+	}, nil
+}
+
+// packClaim encodes the claimAsset or claimMessage call of the deposit with the claim interface of the version,
+// and returns the ABI of the interface to decode its reverts.
+func packClaim(version string, deposit *Deposit, smtProof, rollupProof [32][32]byte, globalExitRoot *GlobalExitRoot) (*abi.ABI, []byte, error) {
+	data, err := PackClaim(version, deposit, smtProof, rollupProof, globalExitRoot)
+	if version == ClaimVersionV2 {
+		return bridgeV2ABI, data, err
+	}
+	return bridgeV1ABI, data, err
+}
+
+// decodeRevertReason decodes the revert data using the custom errors of the contract or the standard Error(string).
+func decodeRevertReason(contractABI *abi.ABI, dataErr rpc.DataError) string {
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return dataErr.Error()
+	}
+	revertData, err := hexutil.Decode(hexData)
+	if err != nil {
+		return dataErr.Error()
+	}
+	if reason, ok := receipt.DecodeRevert(revertData, contractABI); ok {
+		return reason
+	}
+	return dataErr.Error()
+}
+
+// StateKey identifies the etherman in the state file.
+func (etherMan *Client) StateKey() string {
+	return "etherman-" + etherMan.bridgeAddr.Hex()
+}
+
+// ExportState returns the cached immutable data to be saved in the state file.
+func (etherMan *Client) ExportState() (json.RawMessage, error) {
+	return json.Marshal(etherMan.cache.export())
+}
+
+// ImportState restores the cached immutable data from the state file.
+func (etherMan *Client) ImportState(state json.RawMessage) error {
+	var s cacheState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return err
+	}
+	etherMan.cache.restore(s)
+	return nil
+}
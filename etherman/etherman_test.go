@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,6 +96,13 @@ func TestBridgeEvents(t *testing.T) {
 	assert.Equal(t, uint(destNetwork), block[0].Deposits[0].DestinationNetwork)
 	assert.Equal(t, destinationAddr, block[0].Deposits[0].DestinationAddress)
 	assert.Equal(t, 1, len(block[0].GlobalExitRoots))
+	require.Equal(t, 1, len(block[0].RawLogs))
+
+	// Decode again the raw log
+	decoded, decodedOrder, err := etherman.ProcessLogs(ctx, block[0].RawLogs)
+	require.NoError(t, err)
+	assert.Equal(t, DepositsOrder, decodedOrder[decoded[0].BlockHash][0].Name)
+	assert.Equal(t, block[0].Deposits[0], decoded[0].Deposits[0])
 
 	//Claim funds
 	var (
@@ -106,6 +114,31 @@ func TestBridgeEvents(t *testing.T) {
 	rollupExitRoot := block[0].GlobalExitRoots[0].ExitRoots[1]
 
 	destNetwork = 1
+	claimDeposit := &Deposit{
+		DepositCount:       uint(index),
+		OriginalNetwork:    uint(network),
+		OriginalAddress:    maticAddr,
+		DestinationNetwork: uint(destNetwork),
+		DestinationAddress: auth.From,
+		Amount:             big.NewInt(1000000000000000000),
+		Metadata:           []byte{},
+	}
+	claimGER := &GlobalExitRoot{ExitRoots: []common.Hash{mainnetExitRoot, rollupExitRoot}}
+	success, revertReason, err := etherman.SimulateClaim(ctx, auth.From, ClaimVersionV1, claimDeposit, smtProof, [32][32]byte{}, claimGER)
+	require.NoError(t, err)
+	assert.True(t, success)
+	assert.Equal(t, "", revertReason)
+
+	claimTx, err := etherman.BuildClaimTx(ctx, auth.From, ClaimVersionV1, claimDeposit, smtProof, [32][32]byte{}, claimGER)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1337), claimTx.ChainID)
+	assert.Equal(t, etherman.bridgeAddr, claimTx.To)
+	assert.Equal(t, big.NewInt(0), claimTx.Value)
+	assert.NotZero(t, claimTx.Gas)
+	_, data, err := packClaim(ClaimVersionV1, claimDeposit, smtProof, [32][32]byte{}, claimGER)
+	require.NoError(t, err)
+	assert.Equal(t, data, claimTx.Data)
+
 	_, err = bridge.ClaimAsset(auth, smtProof, index, mainnetExitRoot, rollupExitRoot,
 		network, maticAddr, destNetwork, auth.From, big.NewInt(1000000000000000000), []byte{})
 	require.NoError(t, err)
@@ -113,6 +146,12 @@ func TestBridgeEvents(t *testing.T) {
 	// Mine the tx in a block
 	ethBackend.Commit()
 
+	// The deposit can't be claimed twice
+	success, revertReason, err = etherman.SimulateClaim(ctx, auth.From, ClaimVersionV1, claimDeposit, smtProof, [32][32]byte{}, claimGER)
+	require.NoError(t, err)
+	assert.False(t, success)
+	assert.Equal(t, "AlreadyClaimed", revertReason)
+
 	//Read claim event
 	initBlock, err = etherman.EtherClient.BlockByNumber(ctx, nil)
 	require.NoError(t, err)
@@ -128,3 +167,141 @@ func TestBridgeEvents(t *testing.T) {
 	assert.Equal(t, uint(0), block[0].Claims[0].OriginalNetwork)
 	assert.Equal(t, uint64(3), block[0].Claims[0].BlockNumber)
 }
+
+func TestCallCache(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(1337))
+	require.NoError(t, err)
+	cfg := Config{Cache: CacheConfig{Size: 10, FinalizedBlockDepth: 1}}
+	etherman, ethBackend, maticAddr, _, err := NewSimulatedEtherman(cfg, auth)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	decimals, err := etherman.TokenDecimals(ctx, maticAddr)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(18), decimals)
+	cached, ok := etherman.cache.getTokenDecimals(maticAddr)
+	require.True(t, ok)
+	assert.Equal(t, uint8(18), cached)
+
+	hasCode, err := etherman.HasCode(ctx, maticAddr)
+	require.NoError(t, err)
+	assert.True(t, hasCode)
+	assert.True(t, etherman.cache.hasCode(maticAddr))
+	hasCode, err = etherman.HasCode(ctx, auth.From)
+	require.NoError(t, err)
+	assert.False(t, hasCode)
+	assert.False(t, etherman.cache.hasCode(auth.From))
+
+	// The last block is not final, so it must not be cached by number
+	ethBackend.Commit()
+	header, err := etherman.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	_, err = etherman.EthBlockByNumber(ctx, header.Number.Uint64())
+	require.NoError(t, err)
+	_, ok = etherman.cache.getBlockByNumber(header.Number.Uint64())
+	assert.False(t, ok)
+	block, err := etherman.EthBlockByNumber(ctx, header.Number.Uint64()-1)
+	require.NoError(t, err)
+	cachedBlock, ok := etherman.cache.getBlockByNumber(header.Number.Uint64() - 1)
+	require.True(t, ok)
+	assert.Equal(t, block.Hash(), cachedBlock.Hash())
+}
+
+func TestExportImportState(t *testing.T) {
+	cfg := Config{Cache: CacheConfig{Size: 10}}
+	cache, err := newCallCache(cfg.Cache)
+	require.NoError(t, err)
+	token := common.HexToAddress("0x1")
+	contract := common.HexToAddress("0x2")
+	cache.addTokenDecimals(token, 6)
+	cache.addCode(contract)
+	etherman := &Client{cache: cache}
+	state, err := etherman.ExportState()
+	require.NoError(t, err)
+
+	restoredCache, err := newCallCache(cfg.Cache)
+	require.NoError(t, err)
+	restored := &Client{cache: restoredCache}
+	require.NoError(t, restored.ImportState(state))
+	decimals, ok := restored.cache.getTokenDecimals(token)
+	require.True(t, ok)
+	assert.Equal(t, uint8(6), decimals)
+	assert.True(t, restored.cache.hasCode(contract))
+	assert.False(t, restored.cache.hasCode(token))
+
+	// A disabled cache ignores the state
+	require.NoError(t, (&Client{}).ImportState(state))
+}
+
+func TestMultiRollupEvents(t *testing.T) {
+	etherman, _, _, _, _ := newTestingEnv()
+	ctx := context.Background()
+	block, err := etherman.EtherClient.BlockByNumber(ctx, nil)
+	require.NoError(t, err)
+
+	rollupManager := common.HexToAddress("0x1000")
+	etherman.rollupManagerAddr = rollupManager
+	mainnetExitRoot, rollupExitRoot := common.HexToHash("0x01"), common.HexToHash("0x02")
+	localExitRoot := common.HexToHash("0x03")
+	data := append(common.LeftPadBytes([]byte{7}, 32), append(common.HexToHash("0x04").Bytes(), localExitRoot.Bytes()...)...)
+	logs := []types.Log{
+		{Address: rollupManager, Topics: []common.Hash{verifyBatchesTrustedAggregatorSignatureHash, common.BigToHash(big.NewInt(2)), common.HexToHash("0x05")}, Data: data},
+		// The events of the rollup manager not needed are ignored
+		{Address: rollupManager, Topics: []common.Hash{crypto.Keccak256Hash([]byte("OnSequenceBatches(uint32,uint64)"))}},
+		{Topics: []common.Hash{updateL1InfoTreeSignatureHash, mainnetExitRoot, rollupExitRoot}},
+	}
+	for i := range logs {
+		logs[i].BlockHash = block.Hash()
+		logs[i].BlockNumber = block.NumberU64()
+	}
+
+	blocks, order, err := etherman.ProcessLogs(ctx, logs)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, []Order{{Name: RollupExitLeavesOrder, Pos: 0}, {Name: GlobalExitRootsOrder, Pos: 0}}, order[block.Hash()])
+	assert.Equal(t, RollupExitLeaf{BlockNumber: block.NumberU64(), RollupID: 2, Leaf: localExitRoot}, blocks[0].RollupExitLeaves[0])
+	assert.Equal(t, []common.Hash{mainnetExitRoot, rollupExitRoot}, blocks[0].GlobalExitRoots[0].ExitRoots)
+	assert.Equal(t, common.Hash(hash(mainnetExitRoot, rollupExitRoot)), blocks[0].GlobalExitRoots[0].GlobalExitRoot)
+
+	// A malformed event fails
+	logs[0].Data = data[:64]
+	_, _, err = etherman.ProcessLogs(ctx, logs[:1])
+	require.Error(t, err)
+}
+
+func BenchmarkProcessLogs(b *testing.B) {
+	// The debug logs of every event would be measured
+	log.Init(log.Config{Level: "error", Outputs: []string{"stdout"}})
+	b.Cleanup(func() {
+		log.Init(log.Config{Level: "debug", Outputs: []string{"stdout"}})
+	})
+	etherman, ethBackend, auth, maticAddr, bridge := newTestingEnv()
+	ctx := context.Background()
+	initBlock, err := etherman.EtherClient.BlockByNumber(ctx, nil)
+	require.NoError(b, err)
+
+	const deposits = 20
+	destinationAddr := common.HexToAddress("0x61A1d716a74fb45d29f148C6C20A2eccabaFD753")
+	for i := 0; i < deposits; i++ {
+		_, err = bridge.BridgeAsset(auth, 1, destinationAddr, big.NewInt(1000000000000000000), maticAddr, false, []byte{})
+		require.NoError(b, err)
+	}
+	ethBackend.Commit()
+	blocks, _, err := etherman.GetRollupInfoByBlockRange(ctx, initBlock.NumberU64()+1, nil)
+	require.NoError(b, err)
+	require.Equal(b, deposits, len(blocks[0].RawLogs))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		decoded, _, err := etherman.ProcessLogs(ctx, blocks[0].RawLogs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(decoded[0].Deposits) != deposits {
+			b.Fatalf("unexpected deposits: %d", len(decoded[0].Deposits))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package etherman
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// claimEventV2SignatureHash is the ClaimEvent of PolygonZkEVMBridgeV2, with the global index
+	claimEventV2SignatureHash = crypto.Keccak256Hash([]byte("ClaimEvent(uint256,uint32,address,address,uint256)"))
+	// transferSignatureHash is the Transfer event of the ERC20 tokens
+	transferSignatureHash = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+)
+
+// claimEventDataLen is the length of the data of the ClaimEvents: the index, the origin network, the origin
+// address, the destination address and the amount
+const claimEventDataLen = 5 * common.HashLength
+
+// DecodeClaimOutcome decodes the outcome of a mined claim tx of the bridge from its calldata and the logs of its
+// receipt: the ClaimEvent of the bridge and the transfers of the claimed token to its recipient. The tokens
+// delivered are not known for the ether and the messages, which don't emit transfers.
+func DecodeClaimOutcome(data []byte, receipt *types.Receipt, bridgeAddr common.Address) (*ClaimOutcome, error) {
+	isMessage, err := isClaimMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	outcome := &ClaimOutcome{TxHash: receipt.TxHash, GasUsed: receipt.GasUsed, EffectiveGasPrice: receipt.EffectiveGasPrice}
+	if receipt.BlockNumber != nil {
+		outcome.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	found := false
+	for _, vLog := range receipt.Logs {
+		if vLog.Address != bridgeAddr || len(vLog.Topics) == 0 || (vLog.Topics[0] != claimEventSignatureHash && vLog.Topics[0] != claimEventV2SignatureHash) {
+			continue
+		}
+		if len(vLog.Data) != claimEventDataLen {
+			return nil, fmt.Errorf("invalid ClaimEvent in the tx %s, %d bytes of data", receipt.TxHash.String(), len(vLog.Data))
+		}
+		outcome.OriginalNetwork = uint(new(big.Int).SetBytes(vLog.Data[32:64]).Uint64())
+		outcome.OriginalAddress = common.BytesToAddress(vLog.Data[64:96])
+		outcome.Recipient = common.BytesToAddress(vLog.Data[96:128])
+		outcome.Amount = new(big.Int).SetBytes(vLog.Data[128:160])
+		found = true
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("the tx %s has no ClaimEvent of the bridge %s", receipt.TxHash.String(), bridgeAddr.String())
+	}
+	if isMessage || outcome.OriginalAddress == (common.Address{}) {
+		return outcome, nil
+	}
+	// The bridge transfers the original tokens it holds, or mints the wrapped ones, to the recipient
+	outcome.Delivered = big.NewInt(0)
+	for _, vLog := range receipt.Logs {
+		if len(vLog.Topics) != 3 || vLog.Topics[0] != transferSignatureHash || common.BytesToAddress(vLog.Topics[2].Bytes()) != outcome.Recipient { //nolint:gomnd
+			continue
+		}
+		from := common.BytesToAddress(vLog.Topics[1].Bytes())
+		if outcome.Token == (common.Address{}) {
+			if from != bridgeAddr && from != (common.Address{}) {
+				continue
+			}
+			outcome.Token, outcome.Minted = vLog.Address, from == (common.Address{})
+		}
+		if vLog.Address == outcome.Token {
+			outcome.Delivered.Add(outcome.Delivered, new(big.Int).SetBytes(vLog.Data))
+		}
+	}
+	return outcome, nil
+}
+
+// isClaimMessage checks if the calldata is a claimMessage of the v1 or the v2 bridge, or a claimAsset otherwise.
+func isClaimMessage(data []byte) (bool, error) {
+	if len(data) < 4 { //nolint:gomnd
+		return false, fmt.Errorf("the calldata of the claim has %d bytes", len(data))
+	}
+	for _, bridgeABI := range []*abi.ABI{bridgeV1ABI, bridgeV2ABI} {
+		if bytes.Equal(data[:4], bridgeABI.Methods["claimMessage"].ID) {
+			return true, nil
+		}
+		if bytes.Equal(data[:4], bridgeABI.Methods["claimAsset"].ID) {
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("the calldata 0x%x is not a claim", data[:4])
+}
@@ -0,0 +1,96 @@
+package etherman
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func claimEventLog(bridge common.Address, origAddr, recipient common.Address, amount int64) *types.Log {
+	data := make([]byte, 0, claimEventDataLen)
+	for _, word := range []common.Hash{common.BigToHash(big.NewInt(5)), {}, common.BytesToHash(origAddr.Bytes()), common.BytesToHash(recipient.Bytes()), common.BigToHash(big.NewInt(amount))} {
+		data = append(data, word.Bytes()...)
+	}
+	return &types.Log{Address: bridge, Topics: []common.Hash{claimEventSignatureHash}, Data: data}
+}
+
+func transferLog(token, from, to common.Address, amount int64) *types.Log {
+	return &types.Log{
+		Address: token,
+		Topics:  []common.Hash{transferSignatureHash, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:    common.BigToHash(big.NewInt(amount)).Bytes(),
+	}
+}
+
+func TestDecodeClaimOutcome(t *testing.T) {
+	var (
+		bridge    = common.HexToAddress("0x2a3dd3eb832af982ec71669e178424b10dca2ede")
+		origToken = common.HexToAddress("0xaaaa")
+		wrapped   = common.HexToAddress("0xbbbb")
+		recipient = common.HexToAddress("0xcccc")
+		other     = common.HexToAddress("0xdddd")
+	)
+	pack := func(leafType uint8) []byte {
+		data, err := PackClaim(ClaimVersionV1, &Deposit{LeafType: leafType, Amount: big.NewInt(1)}, [32][32]byte{}, [32][32]byte{}, &GlobalExitRoot{ExitRoots: []common.Hash{{}, {}}})
+		require.NoError(t, err)
+		return data
+	}
+	asset, message := pack(0), pack(leafTypeMessage)
+
+	// A wrapped token minted to the recipient
+	receipt := &types.Receipt{BlockNumber: big.NewInt(10), GasUsed: 90000, EffectiveGasPrice: big.NewInt(1e9), Logs: []*types.Log{
+		transferLog(other, recipient, other, 7),
+		transferLog(wrapped, common.Address{}, recipient, 100),
+		claimEventLog(bridge, origToken, recipient, 100),
+	}}
+	outcome, err := DecodeClaimOutcome(asset, receipt, bridge)
+	require.NoError(t, err)
+	require.Equal(t, recipient, outcome.Recipient)
+	require.Equal(t, origToken, outcome.OriginalAddress)
+	require.Equal(t, wrapped, outcome.Token)
+	require.True(t, outcome.Minted)
+	require.Equal(t, int64(100), outcome.Delivered.Int64())
+	require.Equal(t, uint64(10), outcome.BlockNumber)
+	require.Equal(t, uint64(90000), outcome.GasUsed)
+	require.Equal(t, big.NewInt(1e9), outcome.EffectiveGasPrice)
+	require.False(t, outcome.Discrepancy())
+
+	// A fee-on-transfer token held by the bridge delivers less than the amount claimed
+	receipt.Logs = []*types.Log{
+		transferLog(origToken, bridge, recipient, 98),
+		transferLog(origToken, recipient, other, 2),
+		claimEventLog(bridge, origToken, recipient, 100),
+	}
+	outcome, err = DecodeClaimOutcome(asset, receipt, bridge)
+	require.NoError(t, err)
+	require.Equal(t, origToken, outcome.Token)
+	require.False(t, outcome.Minted)
+	require.Equal(t, int64(98), outcome.Delivered.Int64())
+	require.True(t, outcome.Discrepancy())
+
+	// The ether and the messages don't emit transfers
+	receipt.Logs = []*types.Log{claimEventLog(bridge, common.Address{}, recipient, 100)}
+	outcome, err = DecodeClaimOutcome(asset, receipt, bridge)
+	require.NoError(t, err)
+	require.Nil(t, outcome.Delivered)
+	require.False(t, outcome.Discrepancy())
+	receipt.Logs = []*types.Log{claimEventLog(bridge, origToken, recipient, 100)}
+	outcome, err = DecodeClaimOutcome(message, receipt, bridge)
+	require.NoError(t, err)
+	require.Nil(t, outcome.Delivered)
+
+	// An asset claim without transfer delivered nothing
+	outcome, err = DecodeClaimOutcome(asset, receipt, bridge)
+	require.NoError(t, err)
+	require.Zero(t, outcome.Delivered.Sign())
+	require.True(t, outcome.Discrepancy())
+
+	// The ClaimEvents of another contract are ignored
+	_, err = DecodeClaimOutcome(asset, receipt, other)
+	require.Error(t, err)
+	_, err = DecodeClaimOutcome([]byte{1, 2, 3, 4}, receipt, bridge)
+	require.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package etherman
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// criticalTopics are the events whose values are trusted by the bridge: the global exit roots and the
+// verified batches updating the rollup exit tree.
+var criticalTopics = []common.Hash{
+	updateGlobalExitRootSignatureHash,
+	updateL1InfoTreeSignatureHash,
+	verifyBatchesSignatureHash,
+	verifyBatchesTrustedAggregatorSignatureHash,
+}
+
+// QuorumConfig represents the configuration of the quorum of the critical reads of a network
+type QuorumConfig struct {
+	// URLs are the other providers of the network the critical reads are checked with
+	URLs []string `mapstructure:"URLs"`
+	// Threshold is the number of providers, the main one included, that must return the same value before it's
+	// trusted. 0 and 1 disable the quorum
+	Threshold uint `mapstructure:"Threshold"`
+}
+
+// Validate checks that the quorum can be reached with the configured providers.
+func (cfg QuorumConfig) Validate() error {
+	if cfg.Threshold > uint(len(cfg.URLs))+1 {
+		return fmt.Errorf("the quorum threshold %d is greater than the %d providers", cfg.Threshold, len(cfg.URLs)+1)
+	}
+	return nil
+}
+
+// Enabled returns if the critical reads are checked with other providers.
+func (cfg QuorumConfig) Enabled() bool {
+	return cfg.Threshold > 1
+}
+
+// quorum checks the critical reads of the main provider with other providers, so a single malicious or faulty
+// provider can't make the bridge trust a wrong value. A nil quorum trusts the main provider.
+type quorum struct {
+	clients   []ethClienter
+	urls      []string
+	threshold uint
+}
+
+func newQuorum(cfg QuorumConfig, throttle ThrottleConfig, capture CaptureConfig, retry RetryConfig) (*quorum, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	q := &quorum{urls: cfg.URLs, threshold: cfg.Threshold}
+	for _, url := range cfg.URLs {
+		client, err := dial(url, throttle, capture, retry)
+		if err != nil {
+			log.Errorf("error connecting to %s: %+v", url, err)
+			return nil, err
+		}
+		q.clients = append(q.clients, client)
+	}
+	return q, nil
+}
+
+// checkLogs checks that the critical logs returned by the main provider for the query are the same in the
+// other providers, until the threshold is reached.
+func (q *quorum) checkLogs(ctx context.Context, query ethereum.FilterQuery, logs []types.Log) error {
+	if q == nil {
+		return nil
+	}
+	var critical []types.Log
+	for _, vLog := range logs {
+		if len(vLog.Topics) > 0 && isCriticalTopic(vLog.Topics[0]) {
+			critical = append(critical, vLog)
+		}
+	}
+	query.Topics = [][]common.Hash{criticalTopics}
+	matches := uint(1)
+	for i, client := range q.clients {
+		if matches >= q.threshold {
+			break
+		}
+		other, err := client.FilterLogs(ctx, query)
+		if err != nil {
+			log.Warnf("error getting the critical logs from the quorum provider %s: %v", q.urls[i], err)
+			continue
+		}
+		if !sameLogs(critical, other) {
+			log.Warnf("the quorum provider %s returned %d critical logs from the block %s, the main provider %d", q.urls[i], len(other), query.FromBlock.String(), len(critical))
+			continue
+		}
+		matches++
+	}
+	if matches < q.threshold {
+		return gerror.Wrap(gerror.ErrQuorumNotReached, fmt.Errorf("%d of %d providers returned the critical logs from the block %s, %d required",
+			matches, len(q.clients)+1, query.FromBlock.String(), q.threshold))
+	}
+	return nil
+}
+
+func isCriticalTopic(topic common.Hash) bool {
+	for _, critical := range criticalTopics {
+		if topic == critical {
+			return true
+		}
+	}
+	return false
+}
+
+// sameLogs compares the logs by their content and position in the chain.
+func sameLogs(a, b []types.Log) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Address != b[i].Address || a[i].BlockHash != b[i].BlockHash || a[i].BlockNumber != b[i].BlockNumber ||
+			a[i].TxHash != b[i].TxHash || a[i].Index != b[i].Index || !bytes.Equal(a[i].Data, b[i].Data) || len(a[i].Topics) != len(b[i].Topics) {
+			return false
+		}
+		for j := range a[i].Topics {
+			if a[i].Topics[j] != b[i].Topics[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package etherman
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// logsClient is a provider that only returns logs.
+type logsClient struct {
+	ethClienter
+	logs []types.Log
+	err  error
+}
+
+func (c *logsClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return c.logs, c.err
+}
+
+func TestQuorumConfig(t *testing.T) {
+	require.NoError(t, QuorumConfig{}.Validate())
+	require.False(t, QuorumConfig{Threshold: 1}.Enabled())
+	require.NoError(t, QuorumConfig{URLs: []string{"http://a"}, Threshold: 2}.Validate())
+	require.Error(t, QuorumConfig{URLs: []string{"http://a"}, Threshold: 3}.Validate())
+}
+
+func TestQuorumCheckLogs(t *testing.T) {
+	ger := types.Log{
+		Address:     common.HexToAddress("0x01"),
+		Topics:      []common.Hash{updateGlobalExitRootSignatureHash, common.HexToHash("0x02"), common.HexToHash("0x03")},
+		BlockNumber: 10,
+		BlockHash:   common.HexToHash("0x0a"),
+		TxHash:      common.HexToHash("0x0b"),
+	}
+	deposit := types.Log{Topics: []common.Hash{depositEventSignatureHash}, BlockNumber: 10}
+	forged := ger
+	forged.Topics = []common.Hash{updateGlobalExitRootSignatureHash, common.HexToHash("0x04"), common.HexToHash("0x03")}
+	query := ethereum.FilterQuery{FromBlock: big.NewInt(1)}
+	logs := []types.Log{deposit, ger}
+
+	// A nil quorum trusts the main provider
+	require.NoError(t, (*quorum)(nil).checkLogs(context.Background(), query, logs))
+
+	// The non critical logs are not compared
+	q := &quorum{clients: []ethClienter{&logsClient{logs: []types.Log{ger}}}, urls: []string{"a"}, threshold: 2}
+	require.NoError(t, q.checkLogs(context.Background(), query, logs))
+
+	// A provider with other exit roots, without the log or failing doesn't count
+	for _, other := range []*logsClient{{logs: []types.Log{forged}}, {}, {err: errors.New("unavailable")}} {
+		q = &quorum{clients: []ethClienter{other}, urls: []string{"a"}, threshold: 2}
+		require.ErrorIs(t, q.checkLogs(context.Background(), query, logs), gerror.ErrQuorumNotReached)
+	}
+
+	// 2 of 3 providers are enough
+	q = &quorum{clients: []ethClienter{&logsClient{logs: []types.Log{forged}}, &logsClient{logs: []types.Log{ger}}}, urls: []string{"a", "b"}, threshold: 2}
+	require.NoError(t, q.checkLogs(context.Background(), query, logs))
+	q.threshold = 3
+	require.ErrorIs(t, q.checkLogs(context.Background(), query, logs), gerror.ErrQuorumNotReached)
+}
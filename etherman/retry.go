@@ -0,0 +1,146 @@
+package etherman
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// Retry profiles
+const (
+	// RetryProfileConservative retries the failed requests a few times, for the stable providers of mainnet
+	RetryProfileConservative = "mainnet-conservative"
+	// RetryProfileAggressive retries the failed requests for about a minute, for the unstable providers of the
+	// testnets that drop requests or restart often
+	RetryProfileAggressive = "testnet-aggressive"
+
+	retryMultiplier = 2
+)
+
+// retryProfiles are the attempts and the backoffs of the profiles
+var retryProfiles = map[string]RetryConfig{
+	RetryProfileConservative: {Attempts: 3, InitialBackoff: types.NewDuration(time.Second), MaxBackoff: types.NewDuration(10 * time.Second)},
+	RetryProfileAggressive:   {Attempts: 10, InitialBackoff: types.NewDuration(500 * time.Millisecond), MaxBackoff: types.NewDuration(15 * time.Second)},
+}
+
+// retryStatusCodes are the http statuses of the transient errors of the providers
+var retryStatusCodes = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// RetryConfig represents the configuration of the retries of the requests to the rpc providers and of the syncs
+// failed by them
+type RetryConfig struct {
+	// Profile is mainnet-conservative or testnet-aggressive, it sets the fields that are 0
+	Profile string `mapstructure:"Profile"`
+	// Attempts is the max number of attempts of a request failed by a transient error, 1 doesn't retry
+	Attempts int `mapstructure:"Attempts"`
+	// InitialBackoff is the time waited before the first retry, it's doubled after each retry
+	InitialBackoff types.Duration `mapstructure:"InitialBackoff"`
+	// MaxBackoff is the max time waited between two retries
+	MaxBackoff types.Duration `mapstructure:"MaxBackoff"`
+}
+
+// Validate checks the configuration.
+func (cfg RetryConfig) Validate() error {
+	if _, found := retryProfiles[cfg.Profile]; !found && cfg.Profile != "" {
+		return fmt.Errorf("unknown retry profile %s, it must be %s or %s", cfg.Profile, RetryProfileConservative, RetryProfileAggressive)
+	}
+	if cfg.Attempts < 0 || cfg.InitialBackoff.Duration < 0 || cfg.MaxBackoff.Duration < 0 {
+		return fmt.Errorf("the retry attempts and backoffs can't be negative")
+	}
+	return nil
+}
+
+// Waiter returns the waiter of the retries, with the values of the profile for the fields that are 0. An empty
+// profile is mainnet-conservative.
+func (cfg RetryConfig) Waiter() wait.Waiter {
+	profile, found := retryProfiles[cfg.Profile]
+	if !found {
+		profile = retryProfiles[RetryProfileConservative]
+	}
+	if cfg.Attempts == 0 {
+		cfg.Attempts = profile.Attempts
+	}
+	if cfg.InitialBackoff.Duration == 0 {
+		cfg.InitialBackoff = profile.InitialBackoff
+	}
+	if cfg.MaxBackoff.Duration == 0 {
+		cfg.MaxBackoff = profile.MaxBackoff
+	}
+	return wait.Waiter{
+		Interval:    cfg.InitialBackoff.Duration,
+		Attempts:    cfg.Attempts,
+		Multiplier:  retryMultiplier,
+		MaxInterval: cfg.MaxBackoff.Duration,
+	}
+}
+
+// errRetryStatus is the error of an attempt answered with a transient http status
+var errRetryStatus = errors.New("transient http status")
+
+// retryTransport retries the json rpc requests failed by the connection or a transient http status of the provider,
+// which are safe to send again: the bridge only reads the chains through the etherman.
+type retryTransport struct {
+	next  http.RoundTripper
+	url   string
+	retry wait.Waiter
+}
+
+func newRetryTransport(next http.RoundTripper, url string, cfg RetryConfig) *retryTransport {
+	return &retryTransport{next: next, url: sanitizeURL(url), retry: cfg.Waiter()}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+	attempt := 0
+	var res *http.Response
+	_, err := wait.Retry(req.Context(), t.retry, func(ctx context.Context) (struct{}, error) {
+		attempt++
+		if attempt > 1 {
+			log.Debugf("retrying the request to %s, attempt %d", t.url, attempt)
+		}
+		r := req.Clone(ctx)
+		if body != nil {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		var err error
+		res, err = t.next.RoundTrip(r)
+		if err != nil {
+			return struct{}{}, err
+		}
+		if retryStatusCodes[res.StatusCode] && attempt < t.retry.Attempts {
+			// The response of the transient status is discarded, the one of the last attempt is returned
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			return struct{}{}, fmt.Errorf("%w %d", errRetryStatus, res.StatusCode)
+		}
+		return struct{}{}, nil
+	}, func(err error) bool {
+		return req.Context().Err() == nil
+	})
+	if err != nil {
+		log.Warnf("request to %s failed after %d attempts: %v", t.url, attempt, err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,64 @@
+package etherman
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryConfig(t *testing.T) {
+	require.NoError(t, RetryConfig{}.Validate())
+	require.NoError(t, RetryConfig{Profile: RetryProfileAggressive}.Validate())
+	require.Error(t, RetryConfig{Profile: "testnet"}.Validate())
+	require.Error(t, RetryConfig{Attempts: -1}.Validate())
+
+	// The fields that are set override the profile
+	w := RetryConfig{Profile: RetryProfileAggressive, Attempts: 4}.Waiter()
+	require.Equal(t, 4, w.Attempts)
+	require.Equal(t, 500*time.Millisecond, w.Interval)
+	require.Equal(t, 15*time.Second, w.MaxInterval)
+	require.Equal(t, retryProfiles[RetryProfileConservative].Attempts, RetryConfig{}.Waiter().Attempts)
+}
+
+func TestRetryClient(t *testing.T) {
+	var calls, failures atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer srv.Close()
+	client, err := dial(srv.URL, ThrottleConfig{}, CaptureConfig{}, RetryConfig{Attempts: 3, InitialBackoff: types.NewDuration(time.Millisecond)})
+	require.NoError(t, err)
+
+	// The transient errors are retried with the same request
+	failures.Store(2)
+	number, err := client.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(16), number)
+	require.Equal(t, int32(3), calls.Load())
+
+	// The response of the last attempt is returned
+	calls.Store(0)
+	failures.Store(3)
+	_, err = client.BlockNumber(context.Background())
+	require.Error(t, err)
+	require.Equal(t, int32(3), calls.Load())
+
+	// The retries stop with the context
+	calls.Store(0)
+	failures.Store(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.BlockNumber(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.LessOrEqual(t, calls.Load(), int32(1))
+}
@@ -18,6 +18,16 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// simulatedClient adds the chain id, which the simulated backend doesn't provide, to the simulated blockchain.
+type simulatedClient struct {
+	*backends.SimulatedBackend
+}
+
+// ChainID returns the chain id of the simulated blockchain.
+func (c simulatedClient) ChainID(context.Context) (*big.Int, error) {
+	return c.Blockchain().Config().ChainID, nil
+}
+
 // NewSimulatedEtherman creates an etherman that uses a simulated blockchain. It's important to notice that the ChainID of the auth
 // must be 1337. The address that holds the auth will have an initial balance of 10 ETH
 func NewSimulatedEtherman(cfg Config, auth *bind.TransactOpts) (etherman *Client, ethBackend *backends.SimulatedBackend, maticAddr common.Address, mockBridge *mockbridge.Polygonzkevmbridge, err error) {
@@ -109,5 +119,11 @@ func NewSimulatedEtherman(cfg Config, auth *bind.TransactOpts) (etherman *Client
 	}
 
 	client.Commit()
-	return &Client{EtherClient: client, PolygonBridge: br, PolygonZkEVMGlobalExitRoot: globalExitRoot, SCAddresses: []common.Address{exitManagerAddr, bridgeAddr}}, client, maticAddr, mockbr, nil
+	cache, err := newCallCache(cfg.Cache)
+	if err != nil {
+		return nil, nil, common.Address{}, nil, err
+	}
+
+	return &Client{EtherClient: simulatedClient{client}, PolygonBridge: br, PolygonZkEVMGlobalExitRoot: globalExitRoot, SCAddresses: []common.Address{exitManagerAddr, bridgeAddr},
+		bridgeAddr: bridgeAddr, cache: cache, finalizedBlockDepth: cfg.Cache.FinalizedBlockDepth}, client, maticAddr, mockbr, nil
 }
@@ -0,0 +1,205 @@
+package etherman
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultComputeUnits are the compute units charged by the usual commercial providers for
+// the methods called by the bridge.
+var defaultComputeUnits = map[string]uint{
+	"eth_blockNumber":           10,
+	"eth_chainId":               0,
+	"eth_getBlockByNumber":      16,
+	"eth_getBlockByHash":        16,
+	"eth_getLogs":               75,
+	"eth_call":                  26,
+	"eth_getCode":               26,
+	"eth_getTransactionByHash":  17,
+	"eth_getTransactionReceipt": 15,
+	"eth_getBalance":            19,
+	"eth_getTransactionCount":   26,
+}
+
+// ThrottleConfig represents the configuration of the requests budget of a rpc provider
+type ThrottleConfig struct {
+	// RequestsPerSecond is the max number of requests per second sent to the provider. 0 disables the limit
+	RequestsPerSecond float64 `mapstructure:"RequestsPerSecond"`
+	// ComputeUnitsPerSecond is the max number of compute units per second spent in the provider. 0 disables the limit
+	ComputeUnitsPerSecond float64 `mapstructure:"ComputeUnitsPerSecond"`
+	// BurstSeconds is the number of seconds of budget that can be spent at once after an idle period
+	BurstSeconds float64 `mapstructure:"BurstSeconds"`
+	// DefaultComputeUnits is the cost of the methods without a known cost
+	DefaultComputeUnits uint `mapstructure:"DefaultComputeUnits"`
+	// MethodComputeUnits overrides the cost of the methods, e.g. { eth_getLogs = 75 }
+	MethodComputeUnits map[string]uint `mapstructure:"MethodComputeUnits"`
+}
+
+func (cfg ThrottleConfig) enabled() bool {
+	return cfg.RequestsPerSecond > 0 || cfg.ComputeUnitsPerSecond > 0
+}
+
+// tokenBucket is a token bucket that lets the tokens be borrowed, so the caller waits
+// until the bucket is refilled instead of being rejected. A nil tokenBucket doesn't limit.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burstSeconds float64, now time.Time) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	burst := math.Max(rate*burstSeconds, 1)
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+// take takes n tokens and returns how long the caller has to wait before spending them.
+func (b *tokenBucket) take(n float64, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// throttledTransport delays the json rpc requests to keep them inside the provider budget.
+type throttledTransport struct {
+	next         http.RoundTripper
+	url          string
+	requests     *tokenBucket
+	computeUnits *tokenBucket
+	costs        map[string]uint
+	defaultCost  uint
+}
+
+func newThrottledTransport(url string, cfg ThrottleConfig) *throttledTransport {
+	costs := make(map[string]uint, len(defaultComputeUnits)+len(cfg.MethodComputeUnits))
+	for method, cost := range defaultComputeUnits {
+		costs[method] = cost
+	}
+	for method, cost := range cfg.MethodComputeUnits {
+		costs[method] = cost
+	}
+	now := time.Now()
+	return &throttledTransport{
+		next:         http.DefaultTransport,
+		url:          url,
+		requests:     newTokenBucket(cfg.RequestsPerSecond, cfg.BurstSeconds, now),
+		computeUnits: newTokenBucket(cfg.ComputeUnitsPerSecond, cfg.BurstSeconds, now),
+		costs:        costs,
+		defaultCost:  cfg.DefaultComputeUnits,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if err = t.wait(req.Context(), body); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+func (t *throttledTransport) wait(ctx context.Context, body []byte) error {
+	methods := requestMethods(body)
+	var cost uint
+	for _, method := range methods {
+		cost += t.cost(method)
+	}
+	now := time.Now()
+	delay := t.requests.take(float64(len(methods)), now)
+	if cuDelay := t.computeUnits.take(float64(cost), now); cuDelay > delay {
+		delay = cuDelay
+	}
+	if delay <= 0 {
+		return nil
+	}
+	log.Debugf("throttling %v (%d compute units) to %s for %s", methods, cost, t.url, delay)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *throttledTransport) cost(method string) uint {
+	if cost, found := t.costs[method]; found {
+		return cost
+	}
+	return t.defaultCost
+}
+
+// requestMethods returns the methods of a single or batch json rpc request.
+func requestMethods(body []byte) []string {
+	type call struct {
+		Method string `json:"method"`
+	}
+	var batch []call
+	if err := json.Unmarshal(body, &batch); err != nil {
+		var single call
+		if err := json.Unmarshal(body, &single); err != nil {
+			return []string{""}
+		}
+		batch = []call{single}
+	}
+	methods := make([]string, 0, len(batch))
+	for _, c := range batch {
+		methods = append(methods, c.Method)
+	}
+	return methods
+}
+
+// dial connects to the rpc provider, retrying the requests failed by transient errors, throttling them if the
+// budget is configured and capturing a sample of them if the capture is configured. Only the http providers can be
+// retried, throttled and captured.
+func dial(url string, throttle ThrottleConfig, capture CaptureConfig, retry RetryConfig) (*ethclient.Client, error) {
+	var transport http.RoundTripper = http.DefaultTransport
+	if throttle.enabled() {
+		transport = newThrottledTransport(url, throttle)
+	}
+	if capture.enabled() {
+		captureTransport, err := newCaptureTransport(transport, url, capture)
+		if err != nil {
+			return nil, err
+		}
+		transport = captureTransport
+	}
+	// Each attempt is throttled and captured
+	transport = newRetryTransport(transport, url, retry)
+	rpcClient, err := rpc.DialOptions(context.Background(), url, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(rpcClient), nil
+}
@@ -0,0 +1,56 @@
+package etherman
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	require.Nil(t, newTokenBucket(0, 1, now))
+	require.Equal(t, time.Duration(0), (*tokenBucket)(nil).take(100, now))
+
+	b := newTokenBucket(10, 1, now)
+	// The burst is spent without waiting
+	require.Equal(t, time.Duration(0), b.take(10, now))
+	// Then the caller waits until the bucket is refilled
+	require.Equal(t, 500*time.Millisecond, b.take(5, now))
+	require.Equal(t, time.Second, b.take(5, now))
+	// The tokens are refilled with the time, up to the burst
+	require.Equal(t, time.Duration(0), b.take(10, now.Add(3*time.Second)))
+}
+
+func TestRequestMethods(t *testing.T) {
+	require.Equal(t, []string{"eth_getLogs"}, requestMethods([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[]}`)))
+	require.Equal(t, []string{"eth_blockNumber", "eth_call"}, requestMethods([]byte(`[{"method":"eth_blockNumber"},{"method":"eth_call"}]`)))
+	require.Equal(t, []string{""}, requestMethods([]byte(`invalid`)))
+}
+
+func TestThrottledClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer srv.Close()
+
+	// eth_blockNumber costs 10 compute units, so the second call waits 100ms
+	client, err := dial(srv.URL, ThrottleConfig{ComputeUnitsPerSecond: 100, BurstSeconds: 0.1}, CaptureConfig{}, RetryConfig{Attempts: 1})
+	require.NoError(t, err)
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		number, err := client.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(16), number)
+	}
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	// The wait is cancelled with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.BlockNumber(ctx)
+	require.Error(t, err)
+}
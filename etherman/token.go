@@ -0,0 +1,44 @@
+package etherman
+
+import (
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EtherDecimals are the decimals of the ether, bridged with the zero address.
+const EtherDecimals = 18
+
+// The metadata of the ERC20 deposits is abi.encode(string name, string symbol, uint8 decimals).
+var (
+	stringType, _     = abi.NewType("string", "", nil)
+	uint8Type, _      = abi.NewType("uint8", "", nil)
+	tokenMetadataArgs = abi.Arguments{{Name: "name", Type: stringType}, {Name: "symbol", Type: stringType}, {Name: "decimals", Type: uint8Type}}
+)
+
+// TokenDecimals resolves the decimals of an original token from the metadata of its deposit, or from the wrapped
+// token returned by getTokenWrapped when the metadata is missing or can't be decoded. It's false if the decimals
+// are not known, with the error of getTokenWrapped other than not found.
+func TokenDecimals(originalAddress common.Address, metadata []byte, getTokenWrapped func() (*TokenWrapped, error)) (uint8, bool, error) {
+	if originalAddress == (common.Address{}) {
+		return EtherDecimals, true, nil
+	}
+	if len(metadata) > 0 {
+		if values, err := tokenMetadataArgs.Unpack(metadata); err == nil {
+			return values[2].(uint8), true, nil
+		}
+	}
+	tokenWrapped, err := getTokenWrapped()
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	// The metadata is not known until the first deposit of the token is synced
+	if tokenWrapped.Symbol == "" {
+		return 0, false, nil
+	}
+	return tokenWrapped.Decimals, true, nil
+}
@@ -0,0 +1,45 @@
+package etherman
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenDecimals(t *testing.T) {
+	metadata, err := tokenMetadataArgs.Pack("CoinA", "COA", uint8(12))
+	require.NoError(t, err)
+	token := common.HexToAddress("0x1")
+	notFound := func() (*TokenWrapped, error) { return nil, gerror.ErrStorageNotFound }
+
+	decimals, found, err := TokenDecimals(common.Address{}, nil, notFound)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint8(EtherDecimals), decimals)
+
+	decimals, found, err = TokenDecimals(token, metadata, notFound)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint8(12), decimals)
+
+	// Without metadata the decimals are the wrapped token's, once its metadata is synced
+	wrapped := &TokenWrapped{TokenMetadata: TokenMetadata{Symbol: "COA", Decimals: 6}}
+	decimals, found, err = TokenDecimals(token, []byte{0x1}, func() (*TokenWrapped, error) { return wrapped, nil })
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint8(6), decimals)
+	_, found, err = TokenDecimals(token, nil, func() (*TokenWrapped, error) { return &TokenWrapped{}, nil })
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = TokenDecimals(token, nil, notFound)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	storageErr := errors.New("connection refused")
+	_, found, err = TokenDecimals(token, nil, func() (*TokenWrapped, error) { return nil, storageErr })
+	require.ErrorIs(t, err, storageErr)
+	require.False(t, found)
+}
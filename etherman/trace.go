@@ -0,0 +1,144 @@
+package etherman
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrTracingNotSupported is returned when the client can't trace the txs
+var ErrTracingNotSupported = errors.New("tx tracing not supported by the client")
+
+// onMessageReceivedSelector is the selector of the call of the bridge to the destination of a claimed message
+var onMessageReceivedSelector = crypto.Keccak256([]byte("onMessageReceived(address,uint32,bytes)"))[:4]
+
+// callFrame is a call of the trace returned by the callTracer of debug_traceTransaction.
+type callFrame struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Type  string          `json:"type"`
+	Input hexutil.Bytes   `json:"input"`
+	// Error is the error of a call that reverted
+	Error string      `json:"error"`
+	Calls []callFrame `json:"calls"`
+}
+
+// TraceDeposit traces the internal calls of a deposit tx, returning its originator and the contracts that called
+// the bridge. The node must support the debug_traceTransaction method with the callTracer.
+func (etherMan *Client) TraceDeposit(ctx context.Context, txHash common.Hash) (*DepositTrace, error) {
+	if etherMan.rpcClient == nil {
+		return nil, ErrTracingNotSupported
+	}
+	var frame callFrame
+	err := etherMan.rpcClient.CallContext(ctx, &frame, "debug_traceTransaction", txHash, map[string]interface{}{"tracer": "callTracer"})
+	if err != nil {
+		return nil, providerError(err)
+	}
+	path, _ := bridgeCallPath(frame, etherMan.bridgeAddr)
+	return &DepositTrace{
+		TxHash:     txHash,
+		Originator: frame.From,
+		CallPath:   path,
+	}, nil
+}
+
+// bridgeCallPath returns the contracts called from the top level call until the first call to the bridge.
+func bridgeCallPath(frame callFrame, bridgeAddr common.Address) ([]common.Address, bool) {
+	if frame.To == nil {
+		// Contract creation, the bridge can still be called from the constructor
+		for _, call := range frame.Calls {
+			if path, found := bridgeCallPath(call, bridgeAddr); found {
+				return path, true
+			}
+		}
+		return nil, false
+	}
+	if *frame.To == bridgeAddr {
+		return []common.Address{}, true
+	}
+	for _, call := range frame.Calls {
+		if path, found := bridgeCallPath(call, bridgeAddr); found {
+			return append([]common.Address{*frame.To}, path...), true
+		}
+	}
+	return nil, false
+}
+
+// DepositOrigin returns the origin of a deposit tx known from the tx itself, without tracing it: its sender as the
+// originator, and the call path empty when the tx calls the bridge, otherwise the contract called by the tx, or the
+// zero address for a contract creation.
+func (etherMan *Client) DepositOrigin(ctx context.Context, txHash common.Hash) (*DepositTrace, error) {
+	tx, _, err := etherMan.EtherClient.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+	originator, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, fmt.Errorf("error recovering the sender of the tx %s: %w", txHash.String(), err)
+	}
+	origin := &DepositTrace{TxHash: txHash, Originator: originator}
+	switch {
+	case tx.To() == nil:
+		origin.CallPath = []common.Address{{}}
+	case *tx.To() == etherMan.bridgeAddr:
+		origin.CallPath = []common.Address{}
+	default:
+		origin.CallPath = []common.Address{*tx.To()}
+	}
+	return origin, nil
+}
+
+// TraceMessageCall traces a claim tx, mined in the block number, to find if the call of the bridge to the destination
+// of the claimed message succeeded, empty for the asset claims. The node must support the debug_traceTransaction
+// method with the callTracer.
+func (etherMan *Client) TraceMessageCall(ctx context.Context, txHash common.Hash, destination common.Address, blockNumber uint64) (string, error) {
+	if etherMan.rpcClient == nil {
+		return "", ErrTracingNotSupported
+	}
+	var frame callFrame
+	err := etherMan.rpcClient.CallContext(ctx, &frame, "debug_traceTransaction", txHash, map[string]interface{}{"tracer": "callTracer"})
+	if err != nil {
+		return "", providerError(err)
+	}
+	call, found := messageCall(frame, etherMan.bridgeAddr, destination)
+	if !found {
+		return "", nil
+	}
+	if call.Error != "" {
+		return MessageCallReverted, nil
+	}
+	// The call to an address without code succeeds without running the message. The code is read at the block of
+	// the claim, so a contract deployed or destroyed later doesn't change the result
+	code, err := etherMan.EtherClient.CodeAt(ctx, destination, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return "", providerError(err)
+	}
+	if len(code) == 0 {
+		return MessageCallNoCode, nil
+	}
+	return MessageCallSucceeded, nil
+}
+
+// messageCall returns the first call of the bridge to the onMessageReceived of the destination.
+func messageCall(frame callFrame, bridgeAddr, destination common.Address) (*callFrame, bool) {
+	if frame.From == bridgeAddr && frame.To != nil && *frame.To == destination && bytes.HasPrefix(frame.Input, onMessageReceivedSelector) {
+		return &frame, true
+	}
+	for _, call := range frame.Calls {
+		if found, ok := messageCall(call, bridgeAddr, destination); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
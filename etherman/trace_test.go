@@ -0,0 +1,80 @@
+package etherman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBridgeCallPath(t *testing.T) {
+	bridge := common.HexToAddress("0x2a3DD3EB832aF982ec71669E178424b10Dca2EDe")
+	// The user calls a router that calls a vault, that deposits in the bridge
+	const trace = `{
+		"from": "0x61a1d716a74fb45d29f148c6c20a2eccabafd753", "to": "0x1111111111111111111111111111111111111111", "type": "CALL",
+		"calls": [
+			{"from": "0x1111111111111111111111111111111111111111", "to": "0x3333333333333333333333333333333333333333", "type": "STATICCALL"},
+			{"from": "0x1111111111111111111111111111111111111111", "to": "0x2222222222222222222222222222222222222222", "type": "DELEGATECALL",
+				"calls": [{"from": "0x1111111111111111111111111111111111111111", "to": "0x2a3dd3eb832af982ec71669e178424b10dca2ede", "type": "CALL"}]}
+		]
+	}`
+	var frame callFrame
+	require.NoError(t, json.Unmarshal([]byte(trace), &frame))
+	path, found := bridgeCallPath(frame, bridge)
+	require.True(t, found)
+	require.Equal(t, []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+	}, path)
+
+	// Direct call to the bridge
+	path, found = bridgeCallPath(callFrame{To: &bridge}, bridge)
+	require.True(t, found)
+	require.Empty(t, path)
+
+	other := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	_, found = bridgeCallPath(callFrame{To: &other}, bridge)
+	require.False(t, found)
+}
+
+func TestTraceDepositNotSupported(t *testing.T) {
+	etherman, _, _, _, _ := newTestingEnv()
+	_, err := etherman.TraceDeposit(context.Background(), common.Hash{})
+	require.ErrorIs(t, err, ErrTracingNotSupported)
+}
+
+func TestMessageCall(t *testing.T) {
+	bridge := common.HexToAddress("0x2a3DD3EB832aF982ec71669E178424b10Dca2EDe")
+	receiver := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	// A relayer claims a message whose execution reverts in the receiver, and an asset to the receiver
+	const trace = `{
+		"from": "0x61a1d716a74fb45d29f148c6c20a2eccabafd753", "to": "0x1111111111111111111111111111111111111111", "type": "CALL",
+		"calls": [
+			{"from": "0x1111111111111111111111111111111111111111", "to": "0x2a3dd3eb832af982ec71669e178424b10dca2ede", "type": "CALL",
+				"calls": [{"from": "0x2a3dd3eb832af982ec71669e178424b10dca2ede", "to": "0x4444444444444444444444444444444444444444", "type": "CALL", "input": "0x"}]},
+			{"from": "0x1111111111111111111111111111111111111111", "to": "0x2a3dd3eb832af982ec71669e178424b10dca2ede", "type": "CALL",
+				"calls": [{"from": "0x2a3dd3eb832af982ec71669e178424b10dca2ede", "to": "0x4444444444444444444444444444444444444444", "type": "CALL",
+					"input": "0x` + `%s` + `00", "error": "execution reverted"}]}
+		]
+	}`
+	var frame callFrame
+	require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(trace, common.Bytes2Hex(onMessageReceivedSelector))), &frame))
+	call, found := messageCall(frame, bridge, receiver)
+	require.True(t, found)
+	require.Equal(t, "execution reverted", call.Error)
+
+	// The calls to other contracts, or from other contracts, are not the message
+	_, found = messageCall(frame, bridge, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	require.False(t, found)
+	_, found = messageCall(frame, receiver, receiver)
+	require.False(t, found)
+}
+
+func TestTraceMessageCallNotSupported(t *testing.T) {
+	etherman, _, _, _, _ := newTestingEnv()
+	_, err := etherman.TraceMessageCall(context.Background(), common.Hash{}, common.Address{}, 0)
+	require.ErrorIs(t, err, ErrTracingNotSupported)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // Block struct
@@ -18,7 +19,11 @@ type Block struct {
 	Deposits        []Deposit
 	Claims          []Claim
 	Tokens          []TokenWrapped
-	ReceivedAt      time.Time
+	// RollupExitLeaves are the local exit roots of the rollups verified in the block
+	RollupExitLeaves []RollupExitLeaf
+	ReceivedAt       time.Time
+	// RawLogs are the undecoded deposit and claim logs of the block
+	RawLogs []types.Log
 }
 
 // GlobalExitRoot struct
@@ -29,6 +34,31 @@ type GlobalExitRoot struct {
 	GlobalExitRoot common.Hash
 }
 
+// RollupExitLeaf is the local exit root of a rollup verified in L1. It's the leaf of the rollup in the rollup
+// exit tree, at the position rollupID - 1.
+type RollupExitLeaf struct {
+	BlockID     uint64
+	BlockNumber uint64
+	RollupID    uint
+	Leaf        common.Hash
+	// Root is the rollup exit root after updating the leaf of the rollup
+	Root common.Hash
+}
+
+// L1InfoTreeLeaf is an update of the global exit root in L1, appended to the L1 info tree.
+type L1InfoTreeLeaf struct {
+	Index          uint
+	BlockID        uint64
+	BlockNumber    uint64
+	GlobalExitRoot common.Hash
+	// ParentHash is the hash of the block previous to the update
+	ParentHash common.Hash
+	Timestamp  time.Time
+	Leaf       common.Hash
+	// Root is the L1 info root after appending the leaf
+	Root common.Hash
+}
+
 // Deposit struct
 type Deposit struct {
 	LeafType           uint8
@@ -43,8 +73,312 @@ type Deposit struct {
 	NetworkID          uint
 	TxHash             common.Hash
 	Metadata           []byte
+	// LogIndex is the index of the log of the deposit in its block
+	LogIndex uint
 	// it is only used for the bridge service
 	ReadyForClaim bool
+	// BlockTime is the timestamp of the block and SyncedAt the time the service synced it, zero if it was synced
+	// before the sync times were recorded. They are read by the bridge service and set for the hooks
+	BlockTime time.Time
+	SyncedAt  time.Time
+	// Integration is the integration the deposit comes from, empty if it was not attributed
+	Integration string
+	// Sender is the msg.sender of the bridge call, a router contract for the deposits made on behalf of the users,
+	// and Depositor is the sender of the tx. They are zero if they were not recorded
+	Sender    common.Address
+	Depositor common.Address
+}
+
+// Roles of the address the deposits are queried by
+const (
+	// DepositAddressDestination queries the deposits by their destination address
+	DepositAddressDestination = "destination"
+	// DepositAddressSender queries the deposits by the msg.sender of the bridge call
+	DepositAddressSender = "sender"
+	// DepositAddressDepositor queries the deposits by the sender of their tx
+	DepositAddressDepositor = "depositor"
+)
+
+// IsDepositAddressRole returns if the role of the address the deposits are queried by is supported.
+func IsDepositAddressRole(role string) bool {
+	return role == DepositAddressDestination || role == DepositAddressSender || role == DepositAddressDepositor
+}
+
+// Integrations of the deposits that are not known integrations
+const (
+	// DepositIntegrationDirect is the integration of the deposits whose tx calls the bridge
+	DepositIntegrationDirect = "direct"
+	// DepositIntegrationContract is the integration of the deposits made from unknown contracts
+	DepositIntegrationContract = "contract"
+)
+
+// DepositTrace is the origin of the deposits of a tx, traced from its internal calls.
+type DepositTrace struct {
+	NetworkID uint
+	TxHash    common.Hash
+	BlockID   uint64
+	// Originator is the sender of the top level tx
+	Originator common.Address
+	// CallPath are the contracts called from the originator until the bridge, empty if the bridge is called directly
+	CallPath []common.Address
+}
+
+// Sender returns the msg.sender of the bridge call, the last contract of the call path or the originator if the
+// bridge is called directly.
+func (t *DepositTrace) Sender() common.Address {
+	if len(t.CallPath) == 0 {
+		return t.Originator
+	}
+	return t.CallPath[len(t.CallPath)-1]
+}
+
+// Deposit statuses recorded in the status history
+const (
+	DepositStatusIndexed       = "indexed"
+	DepositStatusReadyForClaim = "ready_for_claim"
+	DepositStatusClaimed       = "claimed"
+)
+
+// DepositStatusChange is a change of status of a deposit, with the block of the event that changed it.
+type DepositStatusChange struct {
+	Status string
+	// BlockID is 0 when the change was not triggered by a synced block, e.g. a trusted global exit root
+	BlockID     uint64
+	NetworkID   uint
+	BlockNumber uint64
+	// Time is the time of the block, or the time the change was observed when there is no block
+	Time time.Time
+}
+
+// StuckDeposit is a deposit that is not claimed, with the time it got its current status.
+type StuckDeposit struct {
+	Deposit
+	// Since is the time the deposit became ready for claim, or the time of its block if it's not ready
+	Since time.Time
+	// ClaimTxStatus is the status of the claim tx of the claim tx manager, empty if there is none
+	ClaimTxStatus string
+}
+
+// TokenFlow is the amount of a token deposited to a network and the amount claimed in it. The messages are grouped by
+// their sender, with the ether sent with them.
+type TokenFlow struct {
+	NetworkID       uint
+	OriginalNetwork uint
+	OriginalAddress common.Address
+	Deposited       *big.Int
+	Claimed         *big.Int
+}
+
+// HistoryPoint is a block of a network in the history of the bridge.
+type HistoryPoint struct {
+	NetworkID   uint
+	BlockNumber uint64
+	Time        time.Time
+}
+
+// Includes checks if the status change happened at or before the point. The changes of the network of
+// the point are compared by block number, and the others by time.
+func (p HistoryPoint) Includes(c DepositStatusChange) bool {
+	if c.BlockID != 0 && c.NetworkID == p.NetworkID {
+		return c.BlockNumber <= p.BlockNumber
+	}
+	return !c.Time.After(p.Time)
+}
+
+// DepositAnnotation is the note of a wallet on a deposit to its address.
+type DepositAnnotation struct {
+	NetworkID  uint
+	DepositCnt uint
+	Address    common.Address
+	Note       string
+	UpdatedAt  time.Time
+}
+
+// DepositRef is the external reference id a destination address set on a deposit, e.g. the id of an order in the
+// ledger of an exchange.
+type DepositRef struct {
+	NetworkID  uint
+	DepositCnt uint
+	Address    common.Address
+	RefID      string
+	UpdatedAt  time.Time
+}
+
+// WalletWebhook is a webhook registered by a wallet, receiving the events of the deposits to its address.
+type WalletWebhook struct {
+	ID      uint64
+	Address common.Address
+	URL     string
+	Secret  string
+	// EventTypes are the types of the events sent to the webhook, empty sends all of them
+	EventTypes []string
+	CreatedAt  time.Time
+}
+
+// WebhookDelivery is a webhook delivery stored while the queue of the dispatcher is full.
+type WebhookDelivery struct {
+	ID uint64
+	// Subscription and URL are the name and the endpoint of the subscription receiving the event
+	Subscription string
+	URL          string
+	EventType    string
+	Body         []byte
+	CreatedAt    time.Time
+}
+
+// PushDevice is a mobile device registered by a wallet, receiving the push notifications of the deposits to its
+// address.
+type PushDevice struct {
+	Address common.Address
+	// Platform is the push service of the device: fcm or apns
+	Platform  string
+	Token     string
+	CreatedAt time.Time
+}
+
+// WatchedAddress is an address watched by a wallet: the responses of its first pages are kept hot in the cache,
+// and the history of its deposits is backfilled on demand.
+type WatchedAddress struct {
+	// Owner is the wallet watching the address
+	Owner     common.Address
+	Address   common.Address
+	CreatedAt time.Time
+	// BackfillRequestedAt is the time of the last backfill requested, nil if it was never requested
+	BackfillRequestedAt *time.Time
+	// BackfilledAt is the time the last requested backfill was done, nil while it's pending
+	BackfilledAt *time.Time
+}
+
+// BusEvent is an event of the synced data published on the event bus, with its payload encoded as JSON.
+type BusEvent struct {
+	ID        uint64
+	Type      string
+	NetworkID uint
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// BusOffset is the position of a consumer of the event bus, the id of the last event it handled.
+type BusOffset struct {
+	Consumer  string
+	EventID   uint64
+	UpdatedAt time.Time
+}
+
+// ProofConsumer is a consumer allowed to get the proofs when they are restricted, authenticated by its key.
+type ProofConsumer struct {
+	Name string
+	// KeyHash is the SHA-256 of the key, the key is only known by the consumer
+	KeyHash   common.Hash
+	CreatedAt time.Time
+}
+
+// ProofAccess is the mode of the proofs set by the operators.
+type ProofAccess struct {
+	Restricted bool
+	Reason     string
+	UpdatedAt  time.Time
+}
+
+// Maintenance is a planned downtime set by the operators. The read APIs stay up with its notice, and the writers
+// are paused until it's disabled or its ETA, which ends it.
+type Maintenance struct {
+	Enabled bool
+	// Message is the notice shown by the frontends
+	Message   string
+	ETA       time.Time
+	UpdatedAt time.Time
+}
+
+// Active returns if the maintenance is enabled and not ended at now.
+func (m *Maintenance) Active(now time.Time) bool {
+	return m.Enabled && now.Before(m.ETA)
+}
+
+// Token lists of the operators
+const (
+	TokenListAllow = "allow"
+	TokenListDeny  = "deny"
+)
+
+// Actions of the changes of the token lists
+const (
+	TokenListActionSet    = "set"
+	TokenListActionDelete = "delete"
+)
+
+// TokenListEntry is a token in the allow or the deny list of the operators, by its original network and address.
+type TokenListEntry struct {
+	OriginalNetwork uint
+	OriginalAddress common.Address
+	// List is allow or deny
+	List   string
+	Reason string
+	// Operator is the operator that added the token to the list
+	Operator  string
+	UpdatedAt time.Time
+}
+
+// TokenListChange is a change of the token lists, in their audit log.
+type TokenListChange struct {
+	ID uint64
+	// Action is set or delete
+	Action          string
+	OriginalNetwork uint
+	OriginalAddress common.Address
+	// List is the list the token was added to or removed from
+	List      string
+	Reason    string
+	Operator  string
+	CreatedAt time.Time
+}
+
+// Statuses of the changes of the runtime settings proposed by the admins
+const (
+	ConfigChangePending = "pending"
+	// ConfigChangeApproved is the status of an approved change while it's applied
+	ConfigChangeApproved = "approved"
+	ConfigChangeApplied  = "applied"
+	ConfigChangeFailed   = "failed"
+	ConfigChangeRejected = "rejected"
+	// ConfigChangeExpired is the status of the pending changes not reviewed before they expire, it's not stored
+	ConfigChangeExpired = "expired"
+)
+
+// ConfigChange is a change of the runtime settings proposed by an admin, applied when another admin approves it.
+type ConfigChange struct {
+	ID uint64
+	// Method is the admin endpoint applying the change
+	Method string
+	// Request is the json request of the method
+	Request   string
+	Reason    string
+	Proposer  string
+	Status    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// Reviewer is the admin that approved or rejected the change, empty while it's pending
+	Reviewer   string
+	ReviewedAt *time.Time
+	// Error is the error of the method if the change failed
+	Error string
+}
+
+// SigningKey is an operator key that signed the responses of the API, from its activation until it's retired.
+type SigningKey struct {
+	Address     common.Address
+	ActivatedAt time.Time
+	// RetiredAt is nil while the key signs the responses
+	RetiredAt *time.Time
+}
+
+// IdempotencyKey is the Idempotency-Key of an admin mutation, with its response once it's done.
+type IdempotencyKey struct {
+	Method      string
+	Key         string
+	RequestHash common.Hash
+	Response    []byte
+	CreatedAt   time.Time
 }
 
 // Claim struct
@@ -58,8 +392,27 @@ type Claim struct {
 	BlockNumber        uint64
 	NetworkID          uint
 	TxHash             common.Hash
+	// LogIndex is the index of the log of the claim in its block
+	LogIndex uint
+	// BlockTime is the timestamp of the block and SyncedAt the time the service synced it, zero if it was synced
+	// before the sync times were recorded. They are read by the bridge service and set for the hooks
+	BlockTime time.Time
+	SyncedAt  time.Time
+	// MessageCall is the result of the call of the bridge to the destination of a claimed message, traced when it's
+	// synced. Empty for the asset claims and the claims not traced
+	MessageCall string
 }
 
+// The results of the call to the destination of a claimed message.
+const (
+	// MessageCallSucceeded is a message run by the destination contract
+	MessageCallSucceeded = "succeeded"
+	// MessageCallReverted is a message whose call reverted, without reverting the claim
+	MessageCallReverted = "reverted"
+	// MessageCallNoCode is a message claimed to an address without code, it succeeds without running
+	MessageCallNoCode = "no_code"
+)
+
 // TokenWrapped struct
 type TokenWrapped struct {
 	TokenMetadata
@@ -71,9 +424,162 @@ type TokenWrapped struct {
 	NetworkID            uint
 }
 
+// TokenWrappedMapping is a mapping between an original token and its wrapped token, valid in a range of blocks.
+type TokenWrappedMapping struct {
+	OriginalNetwork      uint
+	OriginalTokenAddress common.Address
+	WrappedTokenAddress  common.Address
+	NetworkID            uint
+	// ValidFromBlock is the block where the mapping was created
+	ValidFromBlock uint64
+	// ValidToBlock is the block where the mapping was replaced. It's 0 for the current mapping
+	ValidToBlock uint64
+}
+
+// ActivityBucket aggregates the deposits and claims of a token in a time bucket.
+type ActivityBucket struct {
+	Time            time.Time
+	OriginalNetwork uint
+	OriginalAddress common.Address
+	DepositCount    uint64
+	DepositVolume   *big.Int
+	ClaimCount      uint64
+	ClaimVolume     *big.Int
+}
+
+// IntegrationStats aggregates the deposits of an integration.
+type IntegrationStats struct {
+	// Integration is empty for the unattributed deposits
+	Integration   string
+	DepositCount  uint64
+	DepositVolume *big.Int
+}
+
+// NetworkPin is the chain id and the contract addresses of a network the database was built against.
+type NetworkPin struct {
+	NetworkID     uint
+	ChainID       uint64
+	BridgeAddress common.Address
+	// GlobalExitRootAddress is the zero address for the L2 networks
+	GlobalExitRootAddress common.Address
+}
+
+// ReplayStatus is the replay of the WAL of a primary database by a read replica.
+type ReplayStatus struct {
+	// InRecovery is false for a primary, that has no lag
+	InRecovery bool
+	// LagBytes is the size of the WAL until the position of the primary not replayed yet
+	LagBytes uint64
+	// LastReplayAt is the commit time of the last replayed transaction, zero if nothing was replayed
+	LastReplayAt time.Time
+}
+
+// ClaimTx is an unsigned claim tx, to be signed and sent by the claimer.
+type ClaimTx struct {
+	ChainID uint64
+	To      common.Address
+	Data    []byte
+	Gas     uint64
+	Value   *big.Int
+}
+
+// AdminQuery describes a predefined read only query the operators can run.
+type AdminQuery struct {
+	Name        string
+	Description string
+	// Params are the names of the params of the query, with their types, e.g. network_id:uint
+	Params []string
+}
+
+// AdminQueryResult is the result of an admin query, with the values formatted as strings.
+type AdminQueryResult struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Query job statuses
+const (
+	QueryJobStatusRunning = "running"
+	QueryJobStatusDone    = "done"
+	QueryJobStatusFailed  = "failed"
+)
+
+// QueryJob is an admin query run asynchronously. Its result is stored apart, once it's done.
+type QueryJob struct {
+	ID     string
+	Query  string
+	Params map[string]string
+	Status string
+	// Error is the error of a failed job
+	Error string
+	// Rows is the number of rows of the result
+	Rows       uint64
+	CreatedAt  time.Time
+	FinishedAt time.Time
+}
+
 // TokenMetadata is a metadata of ERC20 token.
 type TokenMetadata struct {
 	Name     string
 	Symbol   string
 	Decimals uint8
 }
+
+// ClaimOutcome is the outcome of a mined claim, decoded from the logs of its receipt.
+type ClaimOutcome struct {
+	DepositID       uint
+	TxHash          common.Hash
+	BlockNumber     uint64
+	OriginalNetwork uint
+	OriginalAddress common.Address
+	// Recipient is the destination address of the ClaimEvent, the final recipient of the tokens
+	Recipient common.Address
+	// Amount is the amount of the ClaimEvent, the amount the bridge released
+	Amount *big.Int
+	// Token is the token transferred or minted to the recipient, zero for the ether and the messages
+	Token common.Address
+	// Minted is true when the bridge minted the wrapped token
+	Minted bool
+	// Delivered is the amount of the token the recipient received, nil for the ether and the messages
+	Delivered *big.Int
+	// GasUsed and EffectiveGasPrice are the gas the claim tx spent and its price, from its receipt. The price is nil
+	// when it's unknown
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	DecodedAt         time.Time
+}
+
+// ClaimGas is the gas a mined claim tx of the claim tx manager spent.
+type ClaimGas struct {
+	CreatedAt         time.Time
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+}
+
+// ClaimGasStats aggregates the gas the mined claim txs of the claim tx manager spent.
+type ClaimGasStats struct {
+	Claims  int
+	GasUsed uint64
+	// Fee is the sum of the gas used by each tx times its effective gas price
+	Fee      *big.Int
+	From, To time.Time
+}
+
+// Discrepancy returns if the recipient received another amount than the one released by the bridge, e.g. the
+// fee-on-transfer tokens deliver less than expected.
+func (o *ClaimOutcome) Discrepancy() bool {
+	return o.Delivered != nil && o.Amount != nil && o.Delivered.Cmp(o.Amount) != 0
+}
+
+// DepositActivity is an entry of the activity log of a deposit: a log line, a claim attempt or a change of status.
+type DepositActivity struct {
+	NetworkID  uint
+	DepositCnt uint
+	Time       time.Time
+	// Kind is log, claim_attempt or status
+	Kind string
+	// Level is the level of the log lines and the failed claim attempts: debug, info, warn or error
+	Level   string
+	Message string
+	Fields  map[string]string
+}
@@ -0,0 +1,40 @@
+package eventbus
+
+import (
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the event bus of the synced data
+type Config struct {
+	// Enabled publishes the synced deposits, exit roots, claims and reorgs on the event bus, from which the hooks,
+	// the claim tx managers and the exit root latency stats consume them instead of being called by the synchronizers
+	Enabled bool `mapstructure:"Enabled"`
+	// PollInterval is the max time a consumer waits for new events, the consumers are woken by the synchronizers
+	// once the events are committed
+	PollInterval types.Duration `mapstructure:"PollInterval"`
+	// RetryInterval is the time a consumer waits before handling again an event it failed to handle
+	RetryInterval types.Duration `mapstructure:"RetryInterval"`
+	// BatchSize is the max number of events read at once by a consumer
+	BatchSize uint `mapstructure:"BatchSize"`
+	// Retention is the time the events are kept to be replayed, 0 keeps them forever
+	Retention types.Duration `mapstructure:"Retention"`
+}
+
+// Validate checks the intervals and the batch size of the consumers.
+func (c Config) Validate() error {
+	if c.PollInterval.Duration <= 0 {
+		return errors.New("the poll interval of the event bus must be positive")
+	}
+	if c.RetryInterval.Duration <= 0 {
+		return errors.New("the retry interval of the event bus must be positive")
+	}
+	if c.BatchSize == 0 {
+		return errors.New("the batch size of the event bus must be positive")
+	}
+	if c.Retention.Duration < 0 {
+		return errors.New("the retention of the event bus is negative")
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+// Package eventbus carries the typed events of the synced data from the synchronizers to the subsystems consuming
+// them. The events are stored in the database transaction of the synced data, and each consumer reads them from
+// its own offset, so a consumer restarted or being replayed doesn't lose or block the events of the others.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+)
+
+// Type is the type of an event.
+type Type string
+
+// Event types
+const (
+	// DepositIndexed is published when a deposit has been synced
+	DepositIndexed Type = "deposit_indexed"
+	// GERUpdated is published when a global exit root has been synced from L1 or read from the trusted state of L2
+	GERUpdated Type = "ger_updated"
+	// ClaimConfirmed is published when a claim has been synced, once its tx is mined in the claimed network
+	ClaimConfirmed Type = "claim_confirmed"
+	// Reorg is published when the synced state of a network has been reverted until a block
+	Reorg Type = "reorg"
+)
+
+// pruneInterval is the time between two deletions of the events older than the retention
+const pruneInterval = time.Hour
+
+// Event is an event of the bus. Only the data of its type is set.
+type Event struct {
+	ID        uint64    `json:"-"`
+	Type      Type      `json:"-"`
+	NetworkID uint      `json:"-"`
+	CreatedAt time.Time `json:"-"`
+
+	Deposit        *etherman.Deposit        `json:",omitempty"`
+	GlobalExitRoot *etherman.GlobalExitRoot `json:",omitempty"`
+	Claim          *etherman.Claim          `json:",omitempty"`
+	// BlockTime is the time of the block of the global exit roots synced from L1
+	BlockTime time.Time
+	// BlockNumber is the block until which the state is reverted, for the reorgs
+	BlockNumber uint64 `json:",omitempty"`
+}
+
+// Consumer handles the events of the bus. An event whose handling fails is handled again after the RetryInterval,
+// so the events are handled at least once and in order.
+type Consumer interface {
+	HandleEvent(ctx context.Context, event *Event) error
+}
+
+// ConsumerFunc is a function handling the events of the bus.
+type ConsumerFunc func(ctx context.Context, event *Event) error
+
+// HandleEvent implements Consumer.
+func (f ConsumerFunc) HandleEvent(ctx context.Context, event *Event) error {
+	return f(ctx, event)
+}
+
+type storageInterface interface {
+	AddBusEvent(ctx context.Context, event *etherman.BusEvent, dbTx pgx.Tx) error
+	GetBusEvents(ctx context.Context, afterID uint64, types []string, limit uint, dbTx pgx.Tx) ([]etherman.BusEvent, error)
+	GetLastBusEventID(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	DeleteBusEvents(ctx context.Context, before time.Time, dbTx pgx.Tx) (int64, error)
+	GetBusOffset(ctx context.Context, consumer string, dbTx pgx.Tx) (uint64, error)
+	SetBusOffset(ctx context.Context, consumer string, eventID uint64, dbTx pgx.Tx) error
+	AdvanceBusOffset(ctx context.Context, consumer string, fromEventID, toEventID uint64, dbTx pgx.Tx) error
+}
+
+type subscription struct {
+	name     string
+	types    []string
+	consumer Consumer
+	wake     chan struct{}
+}
+
+// Bus stores the published events and runs the consumers subscribed to them.
+type Bus struct {
+	cfg     Config
+	storage storageInterface
+	now     func() time.Time
+
+	mu            sync.Mutex
+	subscriptions []*subscription
+}
+
+// NewBus creates the event bus.
+func NewBus(cfg Config, storage interface{}) (*Bus, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Bus{
+		cfg:     cfg,
+		storage: storage.(storageInterface),
+		now:     time.Now,
+	}, nil
+}
+
+// Publish stores an event in the db tx of its data, setting its id. The consumers read it once the tx is committed
+// and they are woken by Notify, or after the PollInterval.
+func (b *Bus) Publish(ctx context.Context, event *Event, dbTx pgx.Tx) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding the %s event: %w", event.Type, err)
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = b.now()
+	}
+	stored := &etherman.BusEvent{
+		Type:      string(event.Type),
+		NetworkID: event.NetworkID,
+		Payload:   payload,
+		CreatedAt: event.CreatedAt,
+	}
+	if err := b.storage.AddBusEvent(ctx, stored, dbTx); err != nil {
+		return err
+	}
+	event.ID = stored.ID
+	return nil
+}
+
+// Notify wakes the consumers to read the events committed.
+func (b *Bus) Notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.subscriptions {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Subscribe makes a consumer receive the events of some types. The name identifies the offset of the consumer,
+// a consumer subscribed for the first time starts after the last event published.
+func (b *Bus) Subscribe(name string, consumer Consumer, types ...Type) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.subscriptions {
+		if s.name == name {
+			return fmt.Errorf("the consumer %s is already subscribed to the event bus", name)
+		}
+	}
+	s := &subscription{name: name, consumer: consumer, wake: make(chan struct{}, 1)}
+	for _, t := range types {
+		s.types = append(s.types, string(t))
+	}
+	b.subscriptions = append(b.subscriptions, s)
+	return nil
+}
+
+// SubscribeHooks subscribes each registered hook to the deposit, claim and reorg events as the consumer
+// hook:<name>, so each hook is replayed on its own.
+func (b *Bus) SubscribeHooks() error {
+	for _, name := range hooks.Registered() {
+		h, _ := hooks.Lookup(name)
+		if err := b.Subscribe(HookConsumer(name), hookConsumer{name: name, hook: h}, DepositIndexed, ClaimConfirmed, Reorg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HookConsumer returns the name of the consumer of a registered hook.
+func HookConsumer(name string) string {
+	return "hook:" + name
+}
+
+// Start runs the subscribed consumers, and prunes the events older than the retention, until the context is done.
+func (b *Bus) Start(ctx context.Context) {
+	b.mu.Lock()
+	subscriptions := append([]*subscription(nil), b.subscriptions...)
+	b.mu.Unlock()
+	var wg sync.WaitGroup
+	for _, s := range subscriptions {
+		wg.Add(1)
+		go func(s *subscription) {
+			defer wg.Done()
+			b.consume(ctx, s)
+		}(s)
+	}
+	if b.cfg.Retention.Duration > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.prune(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (b *Bus) consume(ctx context.Context, s *subscription) {
+	for {
+		handled, err := b.consumeBatch(ctx, s)
+		wait, wake := b.cfg.PollInterval.Duration, s.wake
+		if err != nil {
+			// The failed event is not handled again before the RetryInterval, even if new events are published
+			log.Warnf("event bus: consumer %s: %v", s.name, err)
+			wait, wake = b.cfg.RetryInterval.Duration, nil
+		} else if handled == int(b.cfg.BatchSize) {
+			// There may be more events to read
+			wait = 0
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// consumeBatch handles the next batch of events of a consumer, returning the number handled. The offset is read
+// again on each batch, so the offsets set by the replays apply to the next batch.
+func (b *Bus) consumeBatch(ctx context.Context, s *subscription) (int, error) {
+	offset, err := b.storage.GetBusOffset(ctx, s.name, nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		if offset, err = b.storage.GetLastBusEventID(ctx, nil); err != nil {
+			return 0, err
+		}
+		log.Infof("event bus: consumer %s subscribed after the event %d", s.name, offset)
+		err = b.storage.SetBusOffset(ctx, s.name, offset, nil)
+	}
+	if err != nil {
+		return 0, err
+	}
+	stored, err := b.storage.GetBusEvents(ctx, offset, s.types, b.cfg.BatchSize, nil)
+	if err != nil {
+		return 0, err
+	}
+	handled := offset
+	defer func() {
+		if handled != offset {
+			if err := b.storage.AdvanceBusOffset(ctx, s.name, offset, handled, nil); err != nil {
+				log.Errorf("event bus: error storing the offset %d of the consumer %s: %v", handled, s.name, err)
+			}
+		}
+	}()
+	for i := range stored {
+		event, err := decodeEvent(&stored[i])
+		if err != nil {
+			// An event that can't be decoded is never handled, so it doesn't block the consumer
+			log.Errorf("event bus: skipping the event %d for the consumer %s: %v", stored[i].ID, s.name, err)
+		} else if err := s.consumer.HandleEvent(ctx, event); err != nil {
+			return i, fmt.Errorf("error handling the %s event %d: %w", event.Type, event.ID, err)
+		}
+		handled = stored[i].ID
+	}
+	return len(stored), nil
+}
+
+func (b *Bus) prune(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		deleted, err := b.storage.DeleteBusEvents(ctx, b.now().Add(-b.cfg.Retention.Duration), nil)
+		if err != nil {
+			log.Warnf("event bus: error deleting the events older than the retention: %v", err)
+		} else if deleted > 0 {
+			log.Debugf("event bus: %d events older than the retention deleted", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func decodeEvent(stored *etherman.BusEvent) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(stored.Payload, &event); err != nil {
+		return nil, err
+	}
+	event.ID, event.Type, event.NetworkID, event.CreatedAt = stored.ID, Type(stored.Type), stored.NetworkID, stored.CreatedAt
+	switch {
+	case event.Type == DepositIndexed && event.Deposit == nil,
+		event.Type == GERUpdated && event.GlobalExitRoot == nil,
+		event.Type == ClaimConfirmed && event.Claim == nil:
+		return nil, fmt.Errorf("the %s event has no data", event.Type)
+	}
+	return &event, nil
+}
+
+// hookConsumer calls a registered hook with the events of the bus. A panic of the hook is recovered and logged,
+// like the calls of the synchronizers, so the event is not handled again.
+type hookConsumer struct {
+	name string
+	hook hooks.Hook
+}
+
+func (c hookConsumer) HandleEvent(ctx context.Context, event *Event) error {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("hook %s panicked on the %s event %d: %v", c.name, event.Type, event.ID, r)
+		}
+	}()
+	switch event.Type {
+	case DepositIndexed:
+		c.hook.OnDepositIndexed(ctx, event.Deposit)
+	case ClaimConfirmed:
+		c.hook.OnClaimIndexed(ctx, event.Claim)
+	case Reorg:
+		c.hook.OnReorg(ctx, event.NetworkID, event.BlockNumber)
+	}
+	return nil
+}
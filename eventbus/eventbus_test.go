@@ -0,0 +1,210 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStorage struct {
+	mu      sync.Mutex
+	events  []etherman.BusEvent
+	offsets map[string]uint64
+}
+
+func (s *memoryStorage) AddBusEvent(_ context.Context, event *etherman.BusEvent, _ pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event.ID = uint64(len(s.events) + 1)
+	s.events = append(s.events, *event)
+	return nil
+}
+
+func (s *memoryStorage) GetBusEvents(_ context.Context, afterID uint64, types []string, limit uint, _ pgx.Tx) ([]etherman.BusEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []etherman.BusEvent
+	for _, e := range s.events {
+		for _, t := range types {
+			if e.ID > afterID && e.Type == t && uint(len(events)) < limit {
+				events = append(events, e)
+			}
+		}
+	}
+	return events, nil
+}
+
+func (s *memoryStorage) GetLastBusEventID(context.Context, pgx.Tx) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return uint64(len(s.events)), nil
+}
+
+func (s *memoryStorage) DeleteBusEvents(_ context.Context, before time.Time, _ pgx.Tx) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return 0, nil
+}
+
+func (s *memoryStorage) GetBusOffset(_ context.Context, consumer string, _ pgx.Tx) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	offset, found := s.offsets[consumer]
+	if !found {
+		return 0, gerror.ErrStorageNotFound
+	}
+	return offset, nil
+}
+
+func (s *memoryStorage) SetBusOffset(_ context.Context, consumer string, eventID uint64, _ pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offsets[consumer] = eventID
+	return nil
+}
+
+func (s *memoryStorage) AdvanceBusOffset(_ context.Context, consumer string, fromEventID, toEventID uint64, _ pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.offsets[consumer] == fromEventID {
+		s.offsets[consumer] = toEventID
+	}
+	return nil
+}
+
+type recorder struct {
+	hooks.NopHook
+	deposits []uint
+	reorgs   []uint64
+}
+
+func (r *recorder) OnDepositIndexed(_ context.Context, deposit *etherman.Deposit) {
+	r.deposits = append(r.deposits, deposit.DepositCount)
+}
+
+func (r *recorder) OnReorg(_ context.Context, networkID uint, blockNumber uint64) {
+	r.reorgs = append(r.reorgs, blockNumber)
+}
+
+func TestBus(t *testing.T) {
+	ctx := context.Background()
+	storage := &memoryStorage{offsets: make(map[string]uint64)}
+	cfg := Config{PollInterval: types.NewDuration(time.Second), RetryInterval: types.NewDuration(time.Second), BatchSize: 2}
+	bus, err := NewBus(cfg, storage)
+	require.NoError(t, err)
+	deposit := func(cnt uint) *Event {
+		return &Event{Type: DepositIndexed, NetworkID: 1, Deposit: &etherman.Deposit{DepositCount: cnt, Amount: big.NewInt(10)}}
+	}
+	// The events published before the first subscription are not consumed
+	require.NoError(t, bus.Publish(ctx, deposit(0), nil))
+
+	var gers []common.Hash
+	fail := true
+	require.NoError(t, bus.Subscribe("gers", ConsumerFunc(func(_ context.Context, e *Event) error {
+		if fail && len(gers) == 1 {
+			return errors.New("unavailable")
+		}
+		gers = append(gers, e.GlobalExitRoot.GlobalExitRoot)
+		return nil
+	}), GERUpdated))
+	require.Error(t, bus.Subscribe("gers", ConsumerFunc(nil), GERUpdated))
+	r := &recorder{}
+	c := hookConsumer{name: "recorder", hook: r}
+	require.NoError(t, bus.Subscribe(HookConsumer("recorder"), c, DepositIndexed, ClaimConfirmed, Reorg))
+	gersSub, hookSub := bus.subscriptions[0], bus.subscriptions[1]
+	handled, err := bus.consumeBatch(ctx, gersSub)
+	require.NoError(t, err)
+	require.Zero(t, handled)
+	handled, err = bus.consumeBatch(ctx, hookSub)
+	require.NoError(t, err)
+	require.Zero(t, handled)
+
+	require.NoError(t, bus.Publish(ctx, deposit(1), nil))
+	for i := int64(1); i <= 3; i++ {
+		ger := &etherman.GlobalExitRoot{GlobalExitRoot: common.BigToHash(big.NewInt(i)), ExitRoots: []common.Hash{{}, {}}}
+		require.NoError(t, bus.Publish(ctx, &Event{Type: GERUpdated, GlobalExitRoot: ger}, nil))
+	}
+	require.NoError(t, bus.Publish(ctx, &Event{Type: Reorg, NetworkID: 1, BlockNumber: 7}, nil))
+	require.NoError(t, bus.Publish(ctx, deposit(2), nil))
+	require.Equal(t, uint64(7), storage.events[6].ID)
+
+	// The consumers read their own events from their own offset, the failed event is handled again
+	handled, err = bus.consumeBatch(ctx, gersSub)
+	require.Error(t, err)
+	require.Equal(t, 1, handled)
+	require.Equal(t, uint64(3), storage.offsets["gers"])
+	fail = false
+	handled, err = bus.consumeBatch(ctx, gersSub)
+	require.NoError(t, err)
+	require.Equal(t, 2, handled)
+	require.Equal(t, []common.Hash{common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(2)), common.BigToHash(big.NewInt(3))}, gers)
+	require.Equal(t, uint64(5), storage.offsets["gers"])
+	for handled = 1; handled > 0; {
+		handled, err = bus.consumeBatch(ctx, hookSub)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []uint{1, 2}, r.deposits)
+	require.Equal(t, []uint64{7}, r.reorgs)
+
+	// A replay sets the offset of a consumer back
+	require.NoError(t, storage.SetBusOffset(ctx, HookConsumer("recorder"), 0, nil))
+	for handled = 1; handled > 0; {
+		handled, err = bus.consumeBatch(ctx, hookSub)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []uint{1, 2, 0, 1, 2}, r.deposits)
+
+	// The events without their data are skipped
+	storage.events = append(storage.events, etherman.BusEvent{ID: 8, Type: string(DepositIndexed), Payload: []byte("{}")})
+	require.NoError(t, bus.Publish(ctx, deposit(3), nil))
+	handled, err = bus.consumeBatch(ctx, hookSub)
+	require.NoError(t, err)
+	require.Equal(t, 2, handled)
+	require.Equal(t, []uint{1, 2, 0, 1, 2, 3}, r.deposits)
+
+	_, err = NewBus(Config{PollInterval: types.NewDuration(time.Second), RetryInterval: types.NewDuration(time.Second)}, storage)
+	require.Error(t, err)
+}
+
+func TestBusStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	storage := &memoryStorage{offsets: make(map[string]uint64)}
+	cfg := Config{PollInterval: types.NewDuration(time.Hour), RetryInterval: types.NewDuration(time.Hour), BatchSize: 10}
+	bus, err := NewBus(cfg, storage)
+	require.NoError(t, err)
+	// The consumer reads the events from the first one
+	require.NoError(t, storage.SetBusOffset(ctx, "deposits", 0, nil))
+	received := make(chan uint, 1)
+	require.NoError(t, bus.Subscribe("deposits", ConsumerFunc(func(_ context.Context, e *Event) error {
+		received <- e.Deposit.DepositCount
+		return nil
+	}), DepositIndexed))
+	done := make(chan struct{})
+	go func() {
+		bus.Start(ctx)
+		close(done)
+	}()
+
+	// The consumers are woken once the published events are committed
+	require.NoError(t, bus.Publish(ctx, &Event{Type: DepositIndexed, Deposit: &etherman.Deposit{DepositCount: 4}}, nil))
+	bus.Notify()
+	select {
+	case cnt := <-received:
+		require.Equal(t, uint(4), cnt)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event was not consumed")
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,182 @@
+// Package gasreport replays the past claim txs under other batching windows and multicall sizes, estimating the gas
+// they would have spent and the delay added to the claims, so the batching is tuned with the data of the network.
+package gasreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	// TxBaseGas is the intrinsic gas of a tx, paid once by a batch instead of once by each claim
+	TxBaseGas = 21000
+	// DefaultCallOverhead is the gas a multicall spends on each of its calls
+	DefaultCallOverhead = 5000
+	// DefaultBatchOverhead is the gas a multicall spends on top of its calls
+	DefaultBatchOverhead = 10000
+
+	// FormatText renders the report as a table
+	FormatText = "text"
+	// FormatJSON renders the report as JSON
+	FormatJSON = "json"
+)
+
+// Claim is a past claim tx, with the gas used by its receipt and the effective gas price it paid.
+type Claim struct {
+	CreatedAt time.Time
+	GasUsed   uint64
+	GasPrice  *big.Int
+}
+
+// Totals are the claims as they were sent, one by one.
+type Totals struct {
+	Claims int       `json:"claims"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Gas    uint64    `json:"gas"`
+	Fee    *big.Int  `json:"fee"`
+}
+
+// Params are the batching parameters replayed, and the gas costs of the multicall.
+type Params struct {
+	// Windows are the max times a batch waits for more claims since its first claim
+	Windows []time.Duration
+	// Sizes are the max numbers of claims of a multicall
+	Sizes         []int
+	CallOverhead  uint64
+	BatchOverhead uint64
+}
+
+// Scenario is the replay of the claims with a batching window and a multicall size.
+type Scenario struct {
+	Window   time.Duration `json:"window"`
+	Size     int           `json:"size"`
+	Txs      int           `json:"txs"`
+	Gas      uint64        `json:"gas"`
+	SavedGas int64         `json:"saved_gas"`
+	// SavedPercent is the saved gas in percent of the gas of the claims sent one by one
+	SavedPercent float64 `json:"saved_percent"`
+	// Fee is the gas of each batch times the gas price of its last claim, the closest to when it's sent
+	Fee      *big.Int `json:"fee"`
+	SavedFee *big.Int `json:"saved_fee"`
+	// AvgDelay and MaxDelay are the times the claims would have waited for their batch to be sent
+	AvgDelay time.Duration `json:"avg_delay"`
+	MaxDelay time.Duration `json:"max_delay"`
+}
+
+// Report is the gas of the claims sent one by one, as they were, and of each scenario.
+type Report struct {
+	Totals
+	Scenarios []Scenario `json:"scenarios"`
+}
+
+// NewReport replays the claims with each window and size. The totals are aggregated by the storage from the same
+// claims.
+func NewReport(totals Totals, claims []Claim, params Params) *Report {
+	claims = append([]Claim(nil), claims...)
+	sort.SliceStable(claims, func(i, j int) bool { return claims[i].CreatedAt.Before(claims[j].CreatedAt) })
+	r := &Report{Totals: totals}
+	if r.Fee == nil {
+		r.Fee = big.NewInt(0)
+	}
+	for _, window := range params.Windows {
+		for _, size := range params.Sizes {
+			s := replay(claims, window, size, params)
+			s.SavedGas = int64(r.Gas) - int64(s.Gas)
+			if r.Gas > 0 {
+				s.SavedPercent = float64(s.SavedGas) * 100 / float64(r.Gas) //nolint:gomnd
+			}
+			s.SavedFee = new(big.Int).Sub(r.Fee, s.Fee)
+			r.Scenarios = append(r.Scenarios, s)
+		}
+	}
+	return r
+}
+
+// replay batches the sorted claims: a batch is sent when it's full or when the window of its first claim elapses.
+func replay(claims []Claim, window time.Duration, size int, params Params) Scenario {
+	s := Scenario{Window: window, Size: size, Fee: big.NewInt(0)}
+	var totalDelay time.Duration
+	for i := 0; i < len(claims); {
+		j := i + 1
+		for j < len(claims) && j-i < size && !claims[j].CreatedAt.After(claims[i].CreatedAt.Add(window)) {
+			j++
+		}
+		batch := claims[i:j]
+		sentAt := claims[i].CreatedAt.Add(window)
+		if len(batch) == size {
+			sentAt = batch[len(batch)-1].CreatedAt
+		}
+		for _, c := range batch {
+			delay := sentAt.Sub(c.CreatedAt)
+			totalDelay += delay
+			if delay > s.MaxDelay {
+				s.MaxDelay = delay
+			}
+		}
+		gas := batchGas(batch, params)
+		s.Txs++
+		s.Gas += gas
+		if price := batch[len(batch)-1].GasPrice; price != nil {
+			s.Fee.Add(s.Fee, new(big.Int).Mul(new(big.Int).SetUint64(gas), price))
+		}
+		i = j
+	}
+	if len(claims) > 0 {
+		s.AvgDelay = totalDelay / time.Duration(len(claims))
+	}
+	return s
+}
+
+// batchGas is the gas of a multicall of the claims, the gas of the claim when it's alone.
+func batchGas(batch []Claim, params Params) uint64 {
+	if len(batch) == 1 {
+		return batch[0].GasUsed
+	}
+	gas := TxBaseGas + params.BatchOverhead
+	for _, c := range batch {
+		gas += params.CallOverhead
+		if c.GasUsed > TxBaseGas {
+			gas += c.GasUsed - TxBaseGas
+		}
+	}
+	return gas
+}
+
+// IsFormat returns if the format of the report is supported.
+func IsFormat(format string) bool {
+	return format == FormatText || format == FormatJSON
+}
+
+// Write renders the report in the format.
+func Write(w io.Writer, format string, r *Report) error {
+	switch format {
+	case FormatText:
+		return writeText(w, r)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+}
+
+func writeText(w io.Writer, r *Report) error {
+	if _, err := fmt.Fprintf(w, "%d claims from %s to %s, %d gas and %s wei of fees sent one by one\n\n", r.Claims,
+		r.From.Format(time.RFC3339), r.To.Format(time.RFC3339), r.Gas, r.Fee.String()); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(tw, "WINDOW\tSIZE\tTXS\tGAS\tSAVED GAS\tSAVED\tFEE\tSAVED FEE\tAVG DELAY\tMAX DELAY")
+	for _, s := range r.Scenarios {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%.1f%%\t%s\t%s\t%s\t%s\n", s.Window, s.Size, s.Txs, s.Gas, s.SavedGas, s.SavedPercent,
+			s.Fee.String(), s.SavedFee.String(), s.AvgDelay.Round(time.Second), s.MaxDelay.Round(time.Second))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,76 @@
+package gasreport
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReport(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	gwei := func(n int64) *big.Int { return big.NewInt(n * 1e9) }
+	claims := []Claim{
+		{CreatedAt: start.Add(10 * time.Second), GasUsed: 121000, GasPrice: gwei(2)},
+		{CreatedAt: start, GasUsed: 121000, GasPrice: gwei(1)},
+		{CreatedAt: start.Add(20 * time.Second), GasUsed: 121000, GasPrice: gwei(3)},
+		{CreatedAt: start.Add(5 * time.Minute), GasUsed: 121000, GasPrice: gwei(1)},
+	}
+	fee := func(gas uint64, price *big.Int) *big.Int { return new(big.Int).Mul(new(big.Int).SetUint64(gas), price) }
+	totals := Totals{Claims: 4, From: start, To: start.Add(5 * time.Minute), Gas: 4 * 121000, Fee: fee(121000, gwei(7))}
+	r := NewReport(totals, claims, Params{
+		Windows:       []time.Duration{0, time.Minute},
+		Sizes:         []int{1, 2, 10},
+		CallOverhead:  DefaultCallOverhead,
+		BatchOverhead: DefaultBatchOverhead,
+	})
+	require.Equal(t, 4, r.Claims)
+	require.Equal(t, start, r.From)
+	require.Equal(t, start.Add(5*time.Minute), r.To)
+	require.Equal(t, uint64(4*121000), r.Gas)
+	require.Equal(t, 6, len(r.Scenarios))
+
+	// Without a window nor a multicall the claims are sent as they were
+	for _, s := range r.Scenarios[:3] {
+		require.Equal(t, 4, s.Txs)
+		require.Equal(t, int64(0), s.SavedGas)
+		require.Equal(t, r.Fee, s.Fee)
+		require.Zero(t, s.SavedFee.Sign())
+		require.Equal(t, time.Duration(0), s.MaxDelay)
+	}
+	s := r.Scenarios[3]
+	require.Equal(t, 4, s.Txs)
+	require.Equal(t, r.Gas, s.Gas)
+	require.Equal(t, r.Fee, s.Fee)
+	require.Equal(t, time.Duration(0), s.MaxDelay)
+
+	// The full batches are sent at once
+	s = r.Scenarios[4]
+	require.Equal(t, 3, s.Txs)
+	require.Equal(t, uint64(2*121000+TxBaseGas+DefaultBatchOverhead+2*(100000+DefaultCallOverhead)), s.Gas)
+	require.Equal(t, int64(21000-DefaultBatchOverhead-2*DefaultCallOverhead), s.SavedGas)
+	// A batch pays the gas price of its last claim
+	batch := uint64(TxBaseGas + DefaultBatchOverhead + 2*(100000+DefaultCallOverhead))
+	require.Equal(t, new(big.Int).Add(fee(batch, gwei(2)), fee(121000, gwei(3+1))), s.Fee)
+	require.Equal(t, new(big.Int).Sub(r.Fee, s.Fee), s.SavedFee)
+	// The claims alone wait for the whole window
+	require.Equal(t, time.Minute, s.MaxDelay)
+	require.Equal(t, (10*time.Second+2*time.Minute)/4, s.AvgDelay)
+
+	// The others when the window of their first claim elapses
+	s = r.Scenarios[5]
+	require.Equal(t, 2, s.Txs)
+	require.Equal(t, uint64(121000+TxBaseGas+DefaultBatchOverhead+3*(100000+DefaultCallOverhead)), s.Gas)
+	require.Equal(t, 2*21000-DefaultBatchOverhead-3*DefaultCallOverhead, int(s.SavedGas))
+	require.InDelta(t, float64(s.SavedGas)*100/float64(r.Gas), s.SavedPercent, 0.001)
+	require.Equal(t, time.Minute, s.MaxDelay)
+
+	var b bytes.Buffer
+	require.NoError(t, Write(&b, FormatText, r))
+	require.Contains(t, b.String(), "4 claims from 2023-01-01T00:00:00Z to 2023-01-01T00:05:00Z, 484000 gas and 847000000000000 wei of fees sent one by one")
+	require.Contains(t, b.String(), "WINDOW")
+	require.True(t, IsFormat(FormatJSON))
+	require.False(t, IsFormat("csv"))
+}
@@ -0,0 +1,245 @@
+// Package gerlatency measures, for each destination network, the delay between the global exit root
+// updates in L1 and their injection in the L2, which is the time the L1 deposits wait before they can be claimed.
+package gerlatency
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// maxL1Updates is the max number of L1 updates kept waiting for their injection
+	maxL1Updates = 1000
+	// averageWeight is the weight of the last sample in the moving average
+	averageWeight = 0.2
+)
+
+var (
+	latencyDesc = prometheus.NewDesc("bridge_ger_injection_latency_seconds",
+		"Delay between the last injected global exit root update in L1 and its injection in the network", []string{"network_id"}, nil)
+	averageDesc = prometheus.NewDesc("bridge_ger_injection_latency_average_seconds",
+		"Moving average of the global exit root injection delay in the network", []string{"network_id"}, nil)
+	pendingDesc = prometheus.NewDesc("bridge_ger_injection_pending_seconds",
+		"Time the oldest global exit root update in L1 has been waiting to be injected in the network, 0 if all are injected", []string{"network_id"}, nil)
+	injectionsDesc = prometheus.NewDesc("bridge_ger_injections_total",
+		"Number of global exit root injections measured in the network", []string{"network_id"}, nil)
+)
+
+// Stats are the injection delays observed in a destination network.
+type Stats struct {
+	NetworkID uint
+	// Last is the delay of the last injection
+	Last time.Duration
+	// Average is the moving average of the delays
+	Average time.Duration
+	// Samples is the number of injections measured
+	Samples uint64
+	// LastInjection is the time the last injection was observed
+	LastInjection time.Time
+	// Pending is the time the oldest L1 update not injected yet has been waiting
+	Pending time.Duration
+}
+
+type l1Update struct {
+	ger common.Hash
+	at  time.Time
+}
+
+type network struct {
+	stats Stats
+	// injectedAt is the L1 time of the last injected update
+	injectedAt time.Time
+	// syncedAt is the time the synchronizer of the network got synced, zero until then
+	syncedAt time.Time
+}
+
+// Tracker matches the global exit root updates of L1 with their injections in the destination networks. The
+// updates and injections synced while the synchronizers catch up are not measured, their delays are the ones of
+// the sync.
+type Tracker struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	l1Updates []l1Update
+	networks  map[uint]*network
+	// l1SyncedAt is the time the L1 synchronizer got synced, zero until then
+	l1SyncedAt time.Time
+}
+
+// NewTracker creates a tracker using now as the source of time.
+func NewTracker(now func() time.Time) *Tracker {
+	return &Tracker{
+		now:      now,
+		networks: make(map[uint]*network),
+	}
+}
+
+// L1Synced records that the L1 synchronizer is synced, the updates of the blocks before are not measured.
+func (t *Tracker) L1Synced() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.l1SyncedAt.IsZero() {
+		t.l1SyncedAt = t.now()
+	}
+}
+
+// Synced records that the synchronizer of the destination network is synced. The injections of the updates before
+// are observed when the network is synced, not when they are injected, so they are not measured.
+func (t *Tracker) Synced(networkID uint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if n := t.network(networkID); n.syncedAt.IsZero() {
+		n.syncedAt = t.now()
+	}
+}
+
+// L1Updated records that the global exit root was updated in a L1 block with the provided time. The updates of the
+// blocks synced before the L1 synchronizer is synced are ignored, even if they are received later.
+func (t *Tracker) L1Updated(ger common.Hash, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.l1SyncedAt.IsZero() || at.Before(t.l1SyncedAt) {
+		return
+	}
+	t.l1Updates = append(t.l1Updates, l1Update{ger: ger, at: at})
+	if len(t.l1Updates) > maxL1Updates {
+		t.l1Updates = t.l1Updates[len(t.l1Updates)-maxL1Updates:]
+	}
+}
+
+// Injected records that the global exit root is available in the destination network. The injections
+// of the updates that are not known, e.g. synced before the start, are ignored.
+func (t *Tracker) Injected(networkID uint, ger common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var update *l1Update
+	for i := range t.l1Updates {
+		if t.l1Updates[i].ger == ger {
+			update = &t.l1Updates[i]
+			break
+		}
+	}
+	if update == nil {
+		return
+	}
+	n := t.network(networkID)
+	if !update.at.After(n.injectedAt) {
+		return
+	}
+	now := t.now()
+	n.injectedAt = update.at
+	if n.syncedAt.IsZero() || update.at.Before(n.syncedAt) {
+		return
+	}
+	latency := now.Sub(update.at)
+	if latency < 0 {
+		latency = 0
+	}
+	n.stats.Last = latency
+	if n.stats.Samples == 0 {
+		n.stats.Average = latency
+	} else {
+		n.stats.Average = time.Duration(averageWeight*float64(latency) + (1-averageWeight)*float64(n.stats.Average))
+	}
+	n.stats.Samples++
+	n.stats.LastInjection = now
+}
+
+// Track makes the tracker report the networks before their first injection.
+func (t *Tracker) Track(networkIDs ...uint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, networkID := range networkIDs {
+		t.network(networkID)
+	}
+}
+
+func (t *Tracker) network(networkID uint) *network {
+	n, found := t.networks[networkID]
+	if !found {
+		n = &network{stats: Stats{NetworkID: networkID}}
+		t.networks[networkID] = n
+	}
+	return n
+}
+
+// Stats returns the stats of the tracked networks sorted by network id. The pending time is
+// only reported once an injection has been measured, so the initial sync doesn't look like a stall.
+func (t *Tracker) Stats() []Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	stats := make([]Stats, 0, len(t.networks))
+	for _, n := range t.networks {
+		s := n.stats
+		if s.Samples > 0 {
+			for _, update := range t.l1Updates {
+				if update.at.After(n.injectedAt) {
+					s.Pending = now.Sub(update.at)
+					break
+				}
+			}
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].NetworkID < stats[j].NetworkID })
+	return stats
+}
+
+// ExpectedInjection returns when the global exit root updated in L1 at the time is expected to be injected in the
+// network, from the average delay of its injections. It's false until an injection is measured.
+func (t *Tracker) ExpectedInjection(networkID uint, updatedAt time.Time) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n, found := t.networks[networkID]
+	if !found || n.stats.Samples == 0 {
+		return time.Time{}, false
+	}
+	return updatedAt.Add(n.stats.Average), true
+}
+
+// Describe implements prometheus.Collector.
+func (t *Tracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- latencyDesc
+	ch <- averageDesc
+	ch <- pendingDesc
+	ch <- injectionsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (t *Tracker) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range t.Stats() {
+		networkID := strconv.FormatUint(uint64(s.NetworkID), 10) //nolint:gomnd
+		ch <- prometheus.MustNewConstMetric(latencyDesc, prometheus.GaugeValue, s.Last.Seconds(), networkID)
+		ch <- prometheus.MustNewConstMetric(averageDesc, prometheus.GaugeValue, s.Average.Seconds(), networkID)
+		ch <- prometheus.MustNewConstMetric(pendingDesc, prometheus.GaugeValue, s.Pending.Seconds(), networkID)
+		ch <- prometheus.MustNewConstMetric(injectionsDesc, prometheus.CounterValue, float64(s.Samples), networkID)
+	}
+}
+
+// Default is the tracker fed by the synchronizer and the claim tx managers.
+var Default = NewTracker(time.Now)
+
+// L1Updated records a L1 update in the default tracker.
+func L1Updated(ger common.Hash, at time.Time) {
+	Default.L1Updated(ger, at)
+}
+
+// L1Synced records the sync of L1 in the default tracker.
+func L1Synced() {
+	Default.L1Synced()
+}
+
+// Synced records the sync of a destination network in the default tracker.
+func Synced(networkID uint) {
+	Default.Synced(networkID)
+}
+
+// Injected records an injection in the default tracker.
+func Injected(networkID uint, ger common.Hash) {
+	Default.Injected(networkID, ger)
+}
@@ -0,0 +1,109 @@
+package gerlatency
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start
+	tracker := NewTracker(func() time.Time { return now })
+	tracker.Track(1)
+	tracker.L1Synced()
+	tracker.Synced(1)
+	tracker.Synced(2)
+
+	ger1, ger2, ger3 := common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+	tracker.L1Updated(ger1, start)
+	tracker.L1Updated(ger2, start.Add(time.Minute))
+
+	// Before the first injection the pending time is not reported
+	now = start.Add(2 * time.Minute)
+	stats := tracker.Stats()
+	require.Equal(t, []Stats{{NetworkID: 1}, {NetworkID: 2}}, stats)
+
+	// Unknown global exit roots are ignored
+	tracker.Injected(1, ger3)
+	require.Equal(t, uint64(0), tracker.Stats()[0].Samples)
+
+	tracker.Injected(1, ger1)
+	stats = tracker.Stats()
+	require.Equal(t, 2*time.Minute, stats[0].Last)
+	require.Equal(t, 2*time.Minute, stats[0].Average)
+	require.Equal(t, uint64(1), stats[0].Samples)
+	require.Equal(t, now, stats[0].LastInjection)
+	require.Equal(t, time.Minute, stats[0].Pending)
+
+	now = start.Add(3 * time.Minute)
+	tracker.Injected(1, ger2)
+	// An older update injected later doesn't change the stats
+	tracker.Injected(1, ger1)
+	stats = tracker.Stats()
+	require.Equal(t, 2*time.Minute, stats[0].Last)
+	require.Equal(t, uint64(2), stats[0].Samples)
+	require.Equal(t, time.Duration(0), stats[0].Pending)
+
+	// The injection of a network doesn't affect the others
+	tracker.Injected(2, ger2)
+	stats = tracker.Stats()
+	require.Equal(t, 2, len(stats))
+	require.Equal(t, uint(2), stats[1].NetworkID)
+	require.Equal(t, 2*time.Minute, stats[1].Last)
+
+	tracker.L1Updated(ger3, start.Add(4*time.Minute))
+	now = start.Add(10 * time.Minute)
+	require.Equal(t, 6*time.Minute, tracker.Stats()[0].Pending)
+
+	expected := `
+# HELP bridge_ger_injection_pending_seconds Time the oldest global exit root update in L1 has been waiting to be injected in the network, 0 if all are injected
+# TYPE bridge_ger_injection_pending_seconds gauge
+bridge_ger_injection_pending_seconds{network_id="1"} 360
+bridge_ger_injection_pending_seconds{network_id="2"} 360
+`
+	require.NoError(t, testutil.CollectAndCompare(tracker, strings.NewReader(expected), "bridge_ger_injection_pending_seconds"))
+}
+
+func TestTrackerSync(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start
+	tracker := NewTracker(func() time.Time { return now })
+	ger1, ger2, ger3 := common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+
+	// The updates synced while L1 catches up are ignored, even if they are received after it's synced
+	tracker.L1Updated(ger1, start.Add(-time.Hour))
+	tracker.L1Synced()
+	tracker.L1Updated(ger2, start.Add(-time.Minute))
+	tracker.Synced(1)
+	tracker.Injected(1, ger1)
+	tracker.Injected(1, ger2)
+	require.Equal(t, uint64(0), tracker.Stats()[0].Samples)
+
+	// The injections observed when the network got synced are not measured, only the next ones
+	now = start.Add(time.Minute)
+	tracker.L1Updated(ger1, now)
+	tracker.Injected(2, ger1)
+	now = start.Add(2 * time.Minute)
+	tracker.Synced(2)
+	tracker.Injected(2, ger1)
+	tracker.L1Updated(ger3, now)
+	now = start.Add(3 * time.Minute)
+	tracker.Injected(2, ger3)
+	stats := tracker.Stats()
+	require.Equal(t, uint(2), stats[1].NetworkID)
+	require.Equal(t, uint64(1), stats[1].Samples)
+	require.Equal(t, time.Minute, stats[1].Last)
+	require.Equal(t, time.Duration(0), stats[1].Pending)
+
+	// The injections are expected after the average delay of the network
+	_, ok := tracker.ExpectedInjection(1, now)
+	require.False(t, ok)
+	expected, ok := tracker.ExpectedInjection(2, start)
+	require.True(t, ok)
+	require.Equal(t, start.Add(time.Minute), expected)
+}
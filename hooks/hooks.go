@@ -0,0 +1,160 @@
+// Package hooks lets the operators run custom logic on the bridge events without forking the sync loop.
+// A hook is registered from the init function of its package, and the package is linked in the bridge binary
+// with a blank import in the main package:
+//
+//	import _ "github.com/operator/bridgehooks"
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Hook is the interface implemented by the custom operator logic.
+// The methods are called synchronously from the sync and claim loops, so they must return quickly,
+// moving any slow work to their own goroutines. A panic in a hook is recovered and logged.
+type Hook interface {
+	// OnDepositIndexed is called when a deposit has been synced and stored
+	OnDepositIndexed(ctx context.Context, deposit *etherman.Deposit)
+	// OnDepositReady is called when a L1 deposit becomes ready to be claimed in L2
+	OnDepositReady(ctx context.Context, deposit *etherman.Deposit)
+	// OnClaimSent is called when the claim tx manager sends a claim tx to L2
+	OnClaimSent(ctx context.Context, mTx *ctmtypes.MonitoredTx, tx *types.Transaction)
+	// OnClaimFailed is called when the claim tx manager gives up on a claim tx
+	OnClaimFailed(ctx context.Context, mTx *ctmtypes.MonitoredTx)
+	// OnClaimIndexed is called when a claim has been synced and stored
+	OnClaimIndexed(ctx context.Context, claim *etherman.Claim)
+	// OnTokenWrapped is called when a wrapped token has been synced and stored
+	OnTokenWrapped(ctx context.Context, token *etherman.TokenWrapped)
+	// OnL2DepositReady is called when a L2 deposit becomes ready to be claimed in L1
+	OnL2DepositReady(ctx context.Context, deposit *etherman.Deposit)
+	// OnReorg is called when the synced state of a network is reverted until a block
+	OnReorg(ctx context.Context, networkID uint, blockNumber uint64)
+}
+
+// NopHook implements all the Hook methods doing nothing. It can be embedded by the hooks
+// that are only interested in some of the events.
+type NopHook struct{}
+
+// OnDepositIndexed implements Hook.
+func (NopHook) OnDepositIndexed(context.Context, *etherman.Deposit) {}
+
+// OnDepositReady implements Hook.
+func (NopHook) OnDepositReady(context.Context, *etherman.Deposit) {}
+
+// OnClaimSent implements Hook.
+func (NopHook) OnClaimSent(context.Context, *ctmtypes.MonitoredTx, *types.Transaction) {}
+
+// OnClaimFailed implements Hook.
+func (NopHook) OnClaimFailed(context.Context, *ctmtypes.MonitoredTx) {}
+
+// OnClaimIndexed implements Hook.
+func (NopHook) OnClaimIndexed(context.Context, *etherman.Claim) {}
+
+// OnTokenWrapped implements Hook.
+func (NopHook) OnTokenWrapped(context.Context, *etherman.TokenWrapped) {}
+
+// OnL2DepositReady implements Hook.
+func (NopHook) OnL2DepositReady(context.Context, *etherman.Deposit) {}
+
+// OnReorg implements Hook.
+func (NopHook) OnReorg(context.Context, uint, uint64) {}
+
+var (
+	mu    sync.RWMutex
+	names []string
+	hooks = make(map[string]Hook)
+)
+
+// Register makes a hook receive the bridge events. It panics if the hook is nil or
+// the name is already registered.
+func Register(name string, h Hook) {
+	mu.Lock()
+	defer mu.Unlock()
+	if h == nil {
+		panic("hooks: Register hook is nil")
+	}
+	if _, dup := hooks[name]; dup {
+		panic(fmt.Sprintf("hooks: Register called twice for hook %s", name))
+	}
+	hooks[name] = h
+	names = append(names, name)
+}
+
+// Registered returns the names of the registered hooks in registration order.
+func Registered() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]string(nil), names...)
+}
+
+// Lookup returns the hook registered with a name.
+func Lookup(name string) (Hook, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	h, found := hooks[name]
+	return h, found
+}
+
+// DepositIndexed notifies the registered hooks that a deposit has been indexed.
+func DepositIndexed(ctx context.Context, deposit *etherman.Deposit) {
+	dispatch("OnDepositIndexed", func(h Hook) { h.OnDepositIndexed(ctx, deposit) })
+}
+
+// DepositReady notifies the registered hooks that a deposit is ready for claim.
+func DepositReady(ctx context.Context, deposit *etherman.Deposit) {
+	dispatch("OnDepositReady", func(h Hook) { h.OnDepositReady(ctx, deposit) })
+}
+
+// ClaimSent notifies the registered hooks that a claim tx has been sent.
+func ClaimSent(ctx context.Context, mTx *ctmtypes.MonitoredTx, tx *types.Transaction) {
+	dispatch("OnClaimSent", func(h Hook) { h.OnClaimSent(ctx, mTx, tx) })
+}
+
+// ClaimFailed notifies the registered hooks that a claim tx has failed.
+func ClaimFailed(ctx context.Context, mTx *ctmtypes.MonitoredTx) {
+	dispatch("OnClaimFailed", func(h Hook) { h.OnClaimFailed(ctx, mTx) })
+}
+
+// ClaimIndexed notifies the registered hooks that a claim has been indexed.
+func ClaimIndexed(ctx context.Context, claim *etherman.Claim) {
+	dispatch("OnClaimIndexed", func(h Hook) { h.OnClaimIndexed(ctx, claim) })
+}
+
+// TokenWrapped notifies the registered hooks that a wrapped token has been indexed.
+func TokenWrapped(ctx context.Context, token *etherman.TokenWrapped) {
+	dispatch("OnTokenWrapped", func(h Hook) { h.OnTokenWrapped(ctx, token) })
+}
+
+// L2DepositReady notifies the registered hooks that a L2 deposit is ready for claim.
+func L2DepositReady(ctx context.Context, deposit *etherman.Deposit) {
+	dispatch("OnL2DepositReady", func(h Hook) { h.OnL2DepositReady(ctx, deposit) })
+}
+
+// Reorg notifies the registered hooks that the state of a network has been reverted.
+func Reorg(ctx context.Context, networkID uint, blockNumber uint64) {
+	dispatch("OnReorg", func(h Hook) { h.OnReorg(ctx, networkID, blockNumber) })
+}
+
+func dispatch(event string, call func(Hook)) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, name := range names {
+		callHook(name, event, hooks[name], call)
+	}
+}
+
+func callHook(name, event string, h Hook, call func(Hook)) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("hook %s panicked on %s: %v", name, event, r)
+		}
+	}()
+	call(h)
+}
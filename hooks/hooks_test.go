@@ -0,0 +1,62 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	NopHook
+	events []string
+}
+
+func (r *recorder) OnDepositIndexed(_ context.Context, deposit *etherman.Deposit) {
+	r.events = append(r.events, "indexed")
+}
+
+func (r *recorder) OnReorg(_ context.Context, networkID uint, blockNumber uint64) {
+	r.events = append(r.events, "reorg")
+}
+
+func (r *recorder) OnClaimSent(_ context.Context, mTx *ctmtypes.MonitoredTx, tx *types.Transaction) {
+	r.events = append(r.events, "sent")
+}
+
+type panicker struct {
+	NopHook
+}
+
+func (panicker) OnDepositIndexed(context.Context, *etherman.Deposit) {
+	panic("boom")
+}
+
+func TestHooks(t *testing.T) {
+	ctx := context.Background()
+	r := &recorder{}
+	Register("panicker", panicker{})
+	Register("recorder", r)
+	require.Equal(t, []string{"panicker", "recorder"}, Registered())
+	require.Panics(t, func() { Register("recorder", r) })
+	require.Panics(t, func() { Register("nil", nil) })
+	h, found := Lookup("recorder")
+	require.True(t, found)
+	require.Equal(t, r, h)
+	_, found = Lookup("nil")
+	require.False(t, found)
+
+	// The panic of the first hook doesn't prevent the next ones from running
+	DepositIndexed(ctx, &etherman.Deposit{})
+	DepositReady(ctx, &etherman.Deposit{})
+	ClaimSent(ctx, &ctmtypes.MonitoredTx{}, types.NewTx(&types.LegacyTx{}))
+	ClaimFailed(ctx, &ctmtypes.MonitoredTx{})
+	ClaimIndexed(ctx, &etherman.Claim{})
+	TokenWrapped(ctx, &etherman.TokenWrapped{})
+	L2DepositReady(ctx, &etherman.Deposit{})
+	Reorg(ctx, 1, 10)
+	require.Equal(t, []string{"indexed", "sent", "reorg"}, r.events)
+}
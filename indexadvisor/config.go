@@ -0,0 +1,27 @@
+package indexadvisor
+
+import (
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the index advisor
+type Config struct {
+	// Enabled runs the advisor periodically, logging the suggested indexes
+	Enabled bool `mapstructure:"Enabled"`
+	// Interval is the time between two analysis of the queries profile
+	Interval types.Duration `mapstructure:"Interval"`
+	// MinCalls is the min number of calls of a query to be analyzed
+	MinCalls uint64 `mapstructure:"MinCalls"`
+	// CreateIndexes creates the suggested indexes, concurrently so the tables are not locked
+	CreateIndexes bool `mapstructure:"CreateIndexes"`
+}
+
+// Validate checks the interval of the analysis.
+func (c Config) Validate() error {
+	if c.Interval.Duration <= 0 {
+		return errors.New("the interval of the index advisor must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Package indexadvisor suggests the indexes missing for the bridge queries, analyzing the filters
+// of the most expensive statements recorded by the pg_stat_statements extension.
+package indexadvisor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const (
+	// maxSuggestionQueries is the max number of queries kept as the reason of a suggestion
+	maxSuggestionQueries = 3
+	// maxNameLen is the max length of the identifiers of postgres, the longer names are truncated
+	maxNameLen = 63
+	// nameHashLen is the length of the hash that ends the names that would be truncated
+	nameHashLen = 8
+)
+
+var (
+	// tableRegexp matches the bridge tables of the FROM and JOIN clauses with their alias
+	tableRegexp = regexp.MustCompile(`(?i)\b(?:FROM|JOIN|UPDATE)\s+((?:sync|mt)\.\w+)(?:\s+(?:AS\s+)?(\w+))?`)
+	// filterRegexp matches the columns compared with a param, e.g. d.network_id = $1
+	filterRegexp = regexp.MustCompile(`(?i)(?:\b(\w+)\.)?\b(\w+)\s*(=|<=|>=|<|>|\bIN\b|\bLIKE\b|\bILIKE\b)\s*\(?\s*(?:\$\d+|ANY\s*\(\s*\$\d+)`)
+	// keywords are the words that can follow a table instead of an alias
+	keywords = map[string]bool{
+		"where": true, "inner": true, "left": true, "right": true, "full": true, "join": true, "on": true, "order": true,
+		"group": true, "limit": true, "offset": true, "set": true, "union": true, "returning": true, "for": true, "using": true,
+	}
+)
+
+// QueryStat is the execution profile of a normalized statement.
+type QueryStat struct {
+	Query     string
+	Calls     uint64
+	TotalTime time.Duration
+}
+
+// Index is an existing index with its columns in order.
+type Index struct {
+	Table   string
+	Name    string
+	Columns []string
+	// Valid is false for the indexes whose concurrent creation failed, which are not used by the queries
+	Valid bool
+}
+
+// Suggestion is an index missing for the filters of some queries.
+type Suggestion struct {
+	Table   string
+	Columns []string
+	// Calls and TotalTime are the profile of the queries that would use the index
+	Calls     uint64
+	TotalTime time.Duration
+	Queries   []string
+}
+
+// Name returns the name of the suggested index. The names over the max length of postgres are cut and end with a
+// hash of the whole name, so two suggestions don't get the same truncated name.
+func (s Suggestion) Name() string {
+	table := s.Table[strings.LastIndex(s.Table, ".")+1:]
+	name := "advisor_" + table + "_" + strings.Join(s.Columns, "_")
+	if len(name) <= maxNameLen {
+		return name
+	}
+	hash := sha256.Sum256([]byte(name))
+	return name[:maxNameLen-nameHashLen-1] + "_" + hex.EncodeToString(hash[:])[:nameHashLen]
+}
+
+// Statement returns the statement creating the suggested index.
+func (s Suggestion) Statement() string {
+	return fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s (%s)", s.Name(), s.Table, strings.Join(s.Columns, ", "))
+}
+
+type storageInterface interface {
+	GetQueryStats(ctx context.Context, minCalls uint64) ([]QueryStat, error)
+	GetIndexes(ctx context.Context) ([]Index, error)
+	CreateIndex(ctx context.Context, name, table string, columns []string) error
+}
+
+// Suggest returns the indexes missing for the filters of the queries, sorted by the time spent in the queries.
+// The equality filters of a table are suggested first in the index, followed by one of the range filters.
+func Suggest(stats []QueryStat, indexes []Index) []Suggestion {
+	suggestions := make(map[string]*Suggestion)
+	for _, stat := range stats {
+		for table, f := range queryFilters(stat.Query) {
+			if f.coveredBy(table, indexes) {
+				continue
+			}
+			columns := f.columns()
+			key := table + "(" + strings.Join(columns, ",") + ")"
+			s, found := suggestions[key]
+			if !found {
+				s = &Suggestion{Table: table, Columns: columns}
+				suggestions[key] = s
+			}
+			s.Calls += stat.Calls
+			s.TotalTime += stat.TotalTime
+			if len(s.Queries) < maxSuggestionQueries {
+				s.Queries = append(s.Queries, stat.Query)
+			}
+		}
+	}
+	result := make([]Suggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalTime != result[j].TotalTime {
+			return result[i].TotalTime > result[j].TotalTime
+		}
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+// filter are the columns of a table compared with the query params.
+type filter struct {
+	equalities []string
+	// rangeColumn is the first column filtered by a range, if any
+	rangeColumn string
+}
+
+func (f filter) columns() []string {
+	columns := append([]string(nil), f.equalities...)
+	if f.rangeColumn != "" {
+		columns = append(columns, f.rangeColumn)
+	}
+	return columns
+}
+
+// coveredBy checks if a valid index of the table starts with the equality columns, in any order,
+// followed by the range column.
+func (f filter) coveredBy(table string, indexes []Index) bool {
+	for _, index := range indexes {
+		if !index.Valid || index.Table != table || len(index.Columns) < len(f.columns()) {
+			continue
+		}
+		all := true
+		for _, column := range f.equalities {
+			if !contains(index.Columns[:len(f.equalities)], column) {
+				all = false
+				break
+			}
+		}
+		if all && (f.rangeColumn == "" || index.Columns[len(f.equalities)] == f.rangeColumn) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryFilters returns the filters of the query for each table.
+func queryFilters(query string) map[string]filter {
+	aliases := make(map[string]string)
+	var tables []string
+	for _, match := range tableRegexp.FindAllStringSubmatch(query, -1) {
+		table := strings.ToLower(match[1])
+		tables = append(tables, table)
+		aliases[table[strings.Index(table, ".")+1:]] = table
+		if alias := strings.ToLower(match[2]); alias != "" && !keywords[alias] {
+			aliases[alias] = table
+		}
+	}
+	filters := make(map[string]filter)
+	for _, match := range filterRegexp.FindAllStringSubmatch(query, -1) {
+		qualifier, column, operator := strings.ToLower(match[1]), strings.ToLower(match[2]), strings.ToUpper(match[3])
+		if len(tables) == 0 {
+			break
+		}
+		table := tables[0]
+		if qualifier != "" {
+			var found bool
+			if table, found = aliases[qualifier]; !found {
+				continue
+			}
+		} else if len(tables) > 1 {
+			// The column can't be assigned to a table without its qualifier
+			continue
+		}
+		f := filters[table]
+		switch operator {
+		case "=", "IN":
+			if !contains(f.equalities, column) {
+				f.equalities = append(f.equalities, column)
+			}
+		default:
+			if f.rangeColumn == "" {
+				f.rangeColumn = column
+			}
+		}
+		filters[table] = f
+	}
+	for table, f := range filters {
+		if contains(f.equalities, f.rangeColumn) {
+			f.rangeColumn = ""
+			filters[table] = f
+		}
+	}
+	return filters
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Advisor analyzes the queries profile periodically.
+type Advisor struct {
+	cfg     Config
+	storage storageInterface
+}
+
+// NewAdvisor creates a new index advisor.
+func NewAdvisor(cfg Config, storage interface{}) *Advisor {
+	return &Advisor{
+		cfg:     cfg,
+		storage: storage.(storageInterface),
+	}
+}
+
+// Start runs the analysis every interval until the context is done.
+func (a *Advisor) Start(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		if err := a.run(ctx); err != nil {
+			log.Warnf("index advisor error: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *Advisor) run(ctx context.Context) error {
+	suggestions, err := Analyze(ctx, a.storage, a.cfg.MinCalls)
+	if err != nil {
+		return err
+	}
+	for _, s := range suggestions {
+		log.Infof("index advisor: %s would serve %d calls taking %s, e.g. %s", s.Statement(), s.Calls, s.TotalTime, s.Queries[0])
+		if !a.cfg.CreateIndexes {
+			continue
+		}
+		log.Warnf("index advisor: creating the index %s", s.Name())
+		if err := a.storage.CreateIndex(ctx, s.Name(), s.Table, s.Columns); err != nil {
+			return fmt.Errorf("error creating the index %s: %w", s.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Analyze returns the indexes suggested for the queries with at least minCalls calls.
+func Analyze(ctx context.Context, storage interface{}, minCalls uint64) ([]Suggestion, error) {
+	s := storage.(storageInterface)
+	stats, err := s.GetQueryStats(ctx, minCalls)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := s.GetIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Suggest(stats, indexes), nil
+}
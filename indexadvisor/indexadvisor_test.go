@@ -0,0 +1,65 @@
+package indexadvisor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFilters(t *testing.T) {
+	query := `SELECT d.leaf_type, d.orig_net, b.received_at FROM sync.deposit as d INNER JOIN sync.block as b ON d.network_id = b.network_id
+		WHERE d.dest_addr = $1 AND d.network_id = $2 AND b.received_at >= $3 ORDER BY d.deposit_cnt DESC LIMIT $4`
+	filters := queryFilters(query)
+	require.Equal(t, map[string]filter{
+		"sync.deposit": {equalities: []string{"dest_addr", "network_id"}},
+		"sync.block":   {rangeColumn: "received_at"},
+	}, filters)
+
+	// Without alias the columns are assigned to the only table
+	filters = queryFilters("SELECT id FROM sync.claim WHERE index = $1 AND network_id = ANY($2) AND block_id > $3")
+	require.Equal(t, map[string]filter{
+		"sync.claim": {equalities: []string{"index", "network_id"}, rangeColumn: "block_id"},
+	}, filters)
+
+	require.Empty(t, queryFilters("SELECT 1 FROM pg_class WHERE relname = $1"))
+}
+
+func TestSuggest(t *testing.T) {
+	stats := []QueryStat{
+		{Query: "SELECT * FROM sync.deposit WHERE dest_addr = $1 ORDER BY deposit_cnt DESC LIMIT $2", Calls: 100, TotalTime: time.Second},
+		{Query: "SELECT count(*) FROM sync.deposit WHERE dest_addr = $1", Calls: 50, TotalTime: time.Second},
+		{Query: "SELECT * FROM sync.deposit WHERE network_id = $1 AND deposit_cnt = $2", Calls: 1000, TotalTime: time.Minute},
+		{Query: "SELECT * FROM sync.claim WHERE network_id = $1 AND block_id >= $2", Calls: 10, TotalTime: 3 * time.Second},
+	}
+	indexes := []Index{
+		{Table: "sync.deposit", Name: "deposit_pkey", Columns: []string{"deposit_cnt", "network_id"}, Valid: true},
+		{Table: "sync.claim", Name: "claim_network_id_idx", Columns: []string{"network_id"}, Valid: true},
+		// The index of a failed concurrent creation doesn't cover the queries
+		{Table: "sync.deposit", Name: "advisor_deposit_dest_addr", Columns: []string{"dest_addr"}},
+	}
+	suggestions := Suggest(stats, indexes)
+	require.Equal(t, 2, len(suggestions))
+
+	require.Equal(t, "sync.claim", suggestions[0].Table)
+	require.Equal(t, []string{"network_id", "block_id"}, suggestions[0].Columns)
+	require.Equal(t, "CREATE INDEX CONCURRENTLY IF NOT EXISTS advisor_claim_network_id_block_id ON sync.claim (network_id, block_id)", suggestions[0].Statement())
+
+	require.Equal(t, "sync.deposit", suggestions[1].Table)
+	require.Equal(t, []string{"dest_addr"}, suggestions[1].Columns)
+	require.Equal(t, uint64(150), suggestions[1].Calls)
+	require.Equal(t, 2*time.Second, suggestions[1].TotalTime)
+	require.Equal(t, 2, len(suggestions[1].Queries))
+}
+
+func TestSuggestionName(t *testing.T) {
+	s := Suggestion{Table: "sync.deposit", Columns: []string{"network_id", "dest_addr"}}
+	require.Equal(t, "advisor_deposit_network_id_dest_addr", s.Name())
+
+	// The names over the max length of postgres end with a hash instead of being truncated by postgres
+	long := Suggestion{Table: "sync.monitored_txs", Columns: []string{"deposit_id", "from_addr", "status", "created_at", "updated_at"}}
+	other := Suggestion{Table: "sync.monitored_txs", Columns: []string{"deposit_id", "from_addr", "status", "created_at", "block_id"}}
+	require.Len(t, long.Name(), maxNameLen)
+	require.Equal(t, "advisor_monitored_txs_deposit_id_from_addr_status_crea", long.Name()[:maxNameLen-nameHashLen-1])
+	require.NotEqual(t, long.Name(), other.Name())
+}
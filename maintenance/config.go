@@ -0,0 +1,27 @@
+package maintenance
+
+import (
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the maintenance mode
+type Config struct {
+	// RefreshInterval is the time each instance caches the maintenance, so the maintenance set through another
+	// instance applies after it
+	RefreshInterval types.Duration `mapstructure:"RefreshInterval"`
+	// MaxDuration is the max time between the set of a maintenance and its ETA, so a forgotten maintenance ends
+	MaxDuration types.Duration `mapstructure:"MaxDuration"`
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if c.RefreshInterval.Duration <= 0 {
+		return fmt.Errorf("the refresh interval of the maintenance must be positive")
+	}
+	if c.MaxDuration.Duration <= 0 {
+		return fmt.Errorf("the max duration of the maintenance must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+)
+
+type storageInterface interface {
+	GetMaintenance(ctx context.Context, dbTx pgx.Tx) (*etherman.Maintenance, error)
+	SetMaintenance(ctx context.Context, maintenance *etherman.Maintenance, dbTx pgx.Tx) error
+}
+
+// Monitor caches the maintenance set by the operators, reloading it from the storage every refresh interval. It's
+// shared by the api, which serves its notice and rejects the writes, and the writers, which pause during it.
+type Monitor struct {
+	storage         storageInterface
+	refreshInterval time.Duration
+	maxDuration     time.Duration
+	now             func() time.Time
+
+	mu          sync.Mutex
+	loadedAt    time.Time
+	maintenance *etherman.Maintenance
+}
+
+// NewMonitor creates a new monitor of the maintenance.
+func NewMonitor(cfg Config, storage interface{}) *Monitor {
+	return newMonitor(cfg, storage.(storageInterface))
+}
+
+func newMonitor(cfg Config, storage storageInterface) *Monitor {
+	return &Monitor{
+		storage:         storage,
+		refreshInterval: cfg.RefreshInterval.Duration,
+		maxDuration:     cfg.MaxDuration.Duration,
+		now:             time.Now,
+	}
+}
+
+// Get returns the maintenance set by the operators, nil if none was set.
+func (m *Monitor) Get(ctx context.Context) (*etherman.Maintenance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.loadedAt.IsZero() && m.now().Sub(m.loadedAt) < m.refreshInterval {
+		return m.maintenance, nil
+	}
+	maintenance, err := m.storage.GetMaintenance(ctx, nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		maintenance, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.maintenance, m.loadedAt = maintenance, m.now()
+	return m.maintenance, nil
+}
+
+// Active returns the maintenance in progress, nil if there is none. The writers keep running if it can't be
+// loaded, since an error of the storage would fail them anyway.
+func (m *Monitor) Active(ctx context.Context) *etherman.Maintenance {
+	if m == nil {
+		return nil
+	}
+	maintenance, err := m.Get(ctx)
+	if err != nil {
+		log.Errorf("error getting the maintenance: %v", err)
+		return nil
+	}
+	if maintenance == nil || !maintenance.Active(m.now()) {
+		return nil
+	}
+	return maintenance
+}
+
+// Set starts a maintenance until its ETA, or ends it when it's not enabled, in every instance.
+func (m *Monitor) Set(ctx context.Context, enabled bool, message string, eta time.Time) error {
+	now := m.now()
+	if enabled && !eta.After(now) {
+		return fmt.Errorf("the eta %s of the maintenance is not in the future", eta.UTC().Format(time.RFC3339))
+	}
+	if enabled && eta.Sub(now) > m.maxDuration {
+		return fmt.Errorf("the eta %s of the maintenance is more than %s away", eta.UTC().Format(time.RFC3339), m.maxDuration)
+	}
+	maintenance := &etherman.Maintenance{Enabled: enabled, Message: message, ETA: eta, UpdatedAt: now}
+	if !enabled {
+		maintenance.ETA = now
+	}
+	if err := m.storage.SetMaintenance(ctx, maintenance, nil); err != nil {
+		return err
+	}
+	m.Invalidate()
+	return nil
+}
+
+// Invalidate reloads the maintenance in the next check, so the changes of this instance apply at once.
+func (m *Monitor) Invalidate() {
+	m.mu.Lock()
+	m.loadedAt = time.Time{}
+	m.mu.Unlock()
+}
@@ -0,0 +1,98 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type storage struct {
+	maintenance *etherman.Maintenance
+	err         error
+	loads       int
+}
+
+func (s *storage) GetMaintenance(ctx context.Context, dbTx pgx.Tx) (*etherman.Maintenance, error) {
+	s.loads++
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.maintenance == nil {
+		return nil, gerror.ErrStorageNotFound
+	}
+	m := *s.maintenance
+	return &m, nil
+}
+
+func (s *storage) SetMaintenance(ctx context.Context, maintenance *etherman.Maintenance, dbTx pgx.Tx) error {
+	s.maintenance = maintenance
+	return nil
+}
+
+func TestMonitor(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &storage{}
+	m := newMonitor(Config{RefreshInterval: types.NewDuration(time.Minute), MaxDuration: types.NewDuration(time.Hour)}, s)
+	m.now = func() time.Time { return now }
+
+	// No maintenance was set
+	require.Nil(t, m.Active(ctx))
+
+	// The maintenance is cached until the refresh interval or an invalidate
+	s.maintenance = &etherman.Maintenance{Enabled: true, Message: "upgrade", ETA: now.Add(30 * time.Minute)}
+	require.Nil(t, m.Active(ctx))
+	require.Equal(t, 1, s.loads)
+	m.Invalidate()
+	require.Equal(t, "upgrade", m.Active(ctx).Message)
+	now = now.Add(2 * time.Minute)
+	require.NotNil(t, m.Active(ctx))
+	require.Equal(t, 3, s.loads)
+
+	// It ends at its ETA, or when it's disabled
+	now = now.Add(28 * time.Minute)
+	require.Nil(t, m.Active(ctx))
+	s.maintenance.ETA = now.Add(time.Hour)
+	s.maintenance.Enabled = false
+	m.Invalidate()
+	require.Nil(t, m.Active(ctx))
+
+	// The writers keep running if it can't be loaded
+	s.err = errors.New("connection refused")
+	m.Invalidate()
+	require.Nil(t, m.Active(ctx))
+	_, err := m.Get(ctx)
+	require.Error(t, err)
+
+	// Without a monitor there is no maintenance
+	var none *Monitor
+	require.Nil(t, none.Active(ctx))
+}
+
+func TestSet(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &storage{}
+	m := newMonitor(Config{RefreshInterval: types.NewDuration(time.Minute), MaxDuration: types.NewDuration(time.Hour)}, s)
+	m.now = func() time.Time { return now }
+
+	// The eta must be in the future and within the max duration
+	require.Error(t, m.Set(ctx, true, "upgrade", now))
+	require.Error(t, m.Set(ctx, true, "upgrade", now.Add(2*time.Hour)))
+	require.Nil(t, s.maintenance)
+
+	// The maintenance applies at once in the instance that sets it
+	require.Nil(t, m.Active(ctx))
+	require.NoError(t, m.Set(ctx, true, "upgrade", now.Add(time.Hour)))
+	require.Equal(t, now.Add(time.Hour), m.Active(ctx).ETA)
+	require.NoError(t, m.Set(ctx, false, "", time.Time{}))
+	require.Nil(t, m.Active(ctx))
+	require.Equal(t, now, s.maintenance.UpdatedAt)
+}
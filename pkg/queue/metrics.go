@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	depthDesc = prometheus.NewDesc("bridge_queue_depth",
+		"Number of items waiting for a worker in the queue", []string{"queue"}, nil)
+	capacityDesc = prometheus.NewDesc("bridge_queue_capacity",
+		"Max number of items waiting for a worker in the queue", []string{"queue"}, nil)
+	droppedDesc = prometheus.NewDesc("bridge_queue_dropped_total",
+		"Number of items dropped because the queue was full", []string{"queue"}, nil)
+	spilledDesc = prometheus.NewDesc("bridge_queue_spilled_total",
+		"Number of items stored in the database because the queue was full", []string{"queue"}, nil)
+)
+
+// Stats are the depth and the overflows of a queue.
+type Stats struct {
+	Name     string
+	Depth    int
+	Capacity int
+	Dropped  uint64
+	Spilled  uint64
+}
+
+type statser interface {
+	Stats() Stats
+}
+
+// Registry reports the stats of the queues, by name.
+type Registry struct {
+	mu     sync.Mutex
+	queues map[string]statser
+}
+
+// register adds a queue, replacing the previous queue with its name.
+func (r *Registry) register(q statser) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.queues == nil {
+		r.queues = make(map[string]statser)
+	}
+	r.queues[q.Stats().Name] = q
+}
+
+// Stats returns the stats of the queues sorted by name.
+func (r *Registry) Stats() []Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make([]Stats, 0, len(r.queues))
+	for _, q := range r.queues {
+		stats = append(stats, q.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// Describe implements prometheus.Collector.
+func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
+	ch <- depthDesc
+	ch <- capacityDesc
+	ch <- droppedDesc
+	ch <- spilledDesc
+}
+
+// Collect implements prometheus.Collector.
+func (r *Registry) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range r.Stats() {
+		ch <- prometheus.MustNewConstMetric(depthDesc, prometheus.GaugeValue, float64(s.Depth), s.Name)
+		ch <- prometheus.MustNewConstMetric(capacityDesc, prometheus.GaugeValue, float64(s.Capacity), s.Name)
+		ch <- prometheus.MustNewConstMetric(droppedDesc, prometheus.CounterValue, float64(s.Dropped), s.Name)
+		ch <- prometheus.MustNewConstMetric(spilledDesc, prometheus.CounterValue, float64(s.Spilled), s.Name)
+	}
+}
+
+// Default is the registry of the queues of the service.
+var Default = &Registry{}
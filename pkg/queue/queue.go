@@ -0,0 +1,224 @@
+// Package queue provides the bounded queues between the producers and the background workers, with the policy
+// applied when a queue is full, so an overload degrades in a known way instead of growing the memory. The depth of
+// the queues is exported as prometheus metrics.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// Policy is what a queue does with a new item when it's full.
+type Policy string
+
+const (
+	// PolicyBlock blocks the producer until a worker takes an item
+	PolicyBlock Policy = "block"
+	// PolicyDropNewest drops the new item
+	PolicyDropNewest Policy = "drop_newest"
+	// PolicyDropOldest drops the oldest queued item to make room for the new one
+	PolicyDropOldest Policy = "drop_oldest"
+	// PolicySpill stores the new items in the database, they are queued back in order when the workers free room
+	PolicySpill Policy = "spill"
+)
+
+// Config is the configuration of a queue
+type Config struct {
+	// Size is the max number of items waiting for a worker. 0 hands each item to a waiting worker, with the
+	// block and drop_newest policies
+	Size int `mapstructure:"Size"`
+	// Policy is applied when the queue is full: block, drop_newest, drop_oldest or spill
+	Policy Policy `mapstructure:"Policy"`
+	// RefillInterval is the time between the checks for room to queue back the spilled items, with the spill policy
+	RefillInterval types.Duration `mapstructure:"RefillInterval"`
+}
+
+// Validate checks the size and the policy of the queue.
+func (c Config) Validate() error {
+	switch c.Policy {
+	case PolicyBlock, PolicyDropNewest:
+	case PolicyDropOldest, PolicySpill:
+		if c.Size <= 0 {
+			return fmt.Errorf("the %s policy needs a size", c.Policy)
+		}
+	default:
+		return fmt.Errorf("unknown policy %q", c.Policy)
+	}
+	if c.Size < 0 {
+		return errors.New("negative size")
+	}
+	if c.Policy == PolicySpill && c.RefillInterval.Duration <= 0 {
+		return errors.New("the spill policy needs a refill interval")
+	}
+	return nil
+}
+
+// Spiller stores the items of a queue with the spill policy while it's full.
+type Spiller[T any] interface {
+	// Spill stores an item after the stored ones
+	Spill(ctx context.Context, item T) error
+	// Unspill removes the oldest stored items, at most limit, returning the ones to queue back and the number
+	// of items removed
+	Unspill(ctx context.Context, limit int) ([]T, int, error)
+}
+
+// Queue is a bounded queue of items read by the workers from C.
+type Queue[T any] struct {
+	name    string
+	cfg     Config
+	ch      chan T
+	spiller Spiller[T]
+
+	// mu serializes the pushes of the spill policy with the refills, so the items are queued in order.
+	// spilling is set while there may be stored items, so the new items are stored behind them
+	mu       sync.Mutex
+	spilling bool
+
+	dropped atomic.Uint64
+	spilled atomic.Uint64
+}
+
+// New creates a queue, registered in the Default metrics with its name. The spiller is only used, and required,
+// by the spill policy.
+func New[T any](name string, cfg Config, spiller Spiller[T]) (*Queue[T], error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("queue %s: %w", name, err)
+	}
+	if cfg.Policy == PolicySpill && spiller == nil {
+		return nil, fmt.Errorf("queue %s: the items can't be spilled", name)
+	}
+	q := &Queue[T]{
+		name:    name,
+		cfg:     cfg,
+		ch:      make(chan T, cfg.Size),
+		spiller: spiller,
+		// The items stored before the start are queued back first
+		spilling: cfg.Policy == PolicySpill,
+	}
+	Default.register(q)
+	return q, nil
+}
+
+// C returns the channel the workers read the items from.
+func (q *Queue[T]) C() <-chan T {
+	return q.ch
+}
+
+// Push queues an item, applying the policy if the queue is full. It returns false if the item is dropped, or the
+// context is done while it's blocked.
+func (q *Queue[T]) Push(ctx context.Context, item T) bool {
+	if q.cfg.Policy == PolicySpill {
+		return q.pushOrSpill(ctx, item)
+	}
+	select {
+	case q.ch <- item:
+		return true
+	default:
+	}
+	switch q.cfg.Policy {
+	case PolicyBlock:
+		select {
+		case q.ch <- item:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	case PolicyDropOldest:
+		for {
+			select {
+			case <-q.ch:
+				q.dropped.Add(1)
+				log.Warnf("queue %s full, dropping the oldest item", q.name)
+			default:
+			}
+			select {
+			case q.ch <- item:
+				return true
+			default:
+			}
+		}
+	default:
+		q.dropped.Add(1)
+		return false
+	}
+}
+
+func (q *Queue[T]) pushOrSpill(ctx context.Context, item T) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.spilling {
+		select {
+		case q.ch <- item:
+			return true
+		default:
+			q.spilling = true
+		}
+	}
+	if err := q.spiller.Spill(ctx, item); err != nil {
+		q.dropped.Add(1)
+		log.Errorf("queue %s full, error spilling an item, dropping it: %v", q.name, err)
+		return false
+	}
+	q.spilled.Add(1)
+	return true
+}
+
+// Run queues back the spilled items when the workers free room, until the context is done. It returns at once
+// if the policy is not spill.
+func (q *Queue[T]) Run(ctx context.Context) {
+	if q.cfg.Policy != PolicySpill {
+		return
+	}
+	ticker := time.NewTicker(q.cfg.RefillInterval.Duration)
+	defer ticker.Stop()
+	for {
+		if err := q.refill(ctx); err != nil {
+			log.Errorf("queue %s: error queuing back the spilled items: %v", q.name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refill queues back as many spilled items as fit in the queue. The pushes wait for the lock, so the room is only
+// taken by the spilled items and the sends don't block.
+func (q *Queue[T]) refill(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	room := cap(q.ch) - len(q.ch)
+	if !q.spilling || room == 0 {
+		return nil
+	}
+	items, removed, err := q.spiller.Unspill(ctx, room)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		q.ch <- item
+	}
+	if removed < room {
+		q.spilling = false
+	}
+	return nil
+}
+
+// Stats returns the current stats of the queue.
+func (q *Queue[T]) Stats() Stats {
+	return Stats{
+		Name:     q.name,
+		Depth:    len(q.ch),
+		Capacity: cap(q.ch),
+		Dropped:  q.dropped.Load(),
+		Spilled:  q.spilled.Load(),
+	}
+}
@@ -0,0 +1,121 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySpiller keeps the spilled items in memory.
+type memorySpiller struct {
+	items []int
+}
+
+func (s *memorySpiller) Spill(ctx context.Context, item int) error {
+	s.items = append(s.items, item)
+	return nil
+}
+
+func (s *memorySpiller) Unspill(ctx context.Context, limit int) ([]int, int, error) {
+	if limit > len(s.items) {
+		limit = len(s.items)
+	}
+	items := s.items[:limit]
+	s.items = s.items[limit:]
+	return items, len(items), nil
+}
+
+func drain(q *Queue[int]) []int {
+	var items []int
+	for len(q.ch) > 0 {
+		items = append(items, <-q.C())
+	}
+	return items
+}
+
+func TestPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	q, err := New[int]("drop_newest", Config{Size: 2, Policy: PolicyDropNewest}, nil)
+	require.NoError(t, err)
+	require.True(t, q.Push(ctx, 1))
+	require.True(t, q.Push(ctx, 2))
+	require.False(t, q.Push(ctx, 3))
+	require.Equal(t, []int{1, 2}, drain(q))
+	require.Equal(t, uint64(1), q.Stats().Dropped)
+
+	q, err = New[int]("drop_oldest", Config{Size: 2, Policy: PolicyDropOldest}, nil)
+	require.NoError(t, err)
+	for i := 1; i <= 4; i++ {
+		require.True(t, q.Push(ctx, i))
+	}
+	require.Equal(t, Stats{Name: "drop_oldest", Depth: 2, Capacity: 2, Dropped: 2}, q.Stats())
+	require.Equal(t, []int{3, 4}, drain(q))
+
+	// A blocked push returns when a worker takes an item, or when the context is done
+	q, err = New[int]("block", Config{Size: 1, Policy: PolicyBlock}, nil)
+	require.NoError(t, err)
+	require.True(t, q.Push(ctx, 1))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-q.C()
+	}()
+	require.True(t, q.Push(ctx, 2))
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.False(t, q.Push(cancelled, 3))
+	require.Equal(t, []int{2}, drain(q))
+}
+
+func TestSpill(t *testing.T) {
+	ctx := context.Background()
+	spiller := &memorySpiller{items: []int{0}}
+	cfg := Config{Size: 2, Policy: PolicySpill, RefillInterval: types.NewDuration(time.Second)}
+	q, err := New[int]("spill", cfg, spiller)
+	require.NoError(t, err)
+
+	// The items spilled before the start are queued first, and the new ones are spilled behind them
+	for i := 1; i <= 4; i++ {
+		require.True(t, q.Push(ctx, i))
+	}
+	require.Equal(t, []int{0, 1, 2, 3, 4}, spiller.items)
+	require.Equal(t, uint64(4), q.Stats().Spilled)
+	require.NoError(t, q.refill(ctx))
+	require.Equal(t, []int{0, 1}, drain(q))
+	require.NoError(t, q.refill(ctx))
+	require.Equal(t, []int{2, 3}, drain(q))
+	require.NoError(t, q.refill(ctx))
+	require.Equal(t, []int{4}, drain(q))
+
+	// Once the spilled items are queued back, the new items are queued
+	require.True(t, q.Push(ctx, 5))
+	require.Empty(t, spiller.items)
+	require.Equal(t, []int{5}, drain(q))
+}
+
+func TestConfig(t *testing.T) {
+	require.NoError(t, Config{Policy: PolicyBlock}.Validate())
+	require.NoError(t, Config{Policy: PolicyDropNewest}.Validate())
+	require.Error(t, Config{Policy: PolicyDropOldest}.Validate())
+	require.Error(t, Config{Size: 1, Policy: PolicySpill}.Validate())
+	require.Error(t, Config{Size: 1, Policy: "drop"}.Validate())
+	require.Error(t, Config{Size: -1, Policy: PolicyBlock}.Validate())
+
+	_, err := New[int]("spill", Config{Size: 1, Policy: PolicySpill, RefillInterval: types.NewDuration(time.Second)}, nil)
+	require.Error(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	r := &Registry{}
+	q, err := New[int]("b", Config{Size: 3, Policy: PolicyDropNewest}, nil)
+	require.NoError(t, err)
+	r.register(q)
+	q, err = New[int]("a", Config{Size: 1, Policy: PolicyDropNewest}, nil)
+	require.NoError(t, err)
+	r.register(q)
+	require.True(t, q.Push(context.Background(), 1))
+	require.Equal(t, []Stats{{Name: "a", Depth: 1, Capacity: 1}, {Name: "b", Capacity: 3}}, r.Stats())
+}
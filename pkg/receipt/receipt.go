@@ -0,0 +1,172 @@
+// Package receipt waits for the receipts of the txs, shared by the claim monitoring, the canary and the test
+// operations. The receipt is checked on each new head when the node supports subscriptions, or polled otherwise,
+// and the failed txs are replayed with eth_call before their block to return the decoded revert reason.
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultInterval is the time between the receipt checks without new heads subscriptions.
+const DefaultInterval = time.Second
+
+// Client is the node the receipts are read from.
+type Client interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// headSubscriber is implemented by the clients that may support the newHeads subscriptions, like ethclient.
+type headSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// RevertError is returned when the tx is mined but reverted.
+type RevertError struct {
+	Receipt *types.Receipt
+	// Reason is the decoded revert reason, or the error of the eth_call when it can't be decoded
+	Reason string
+}
+
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("tx %s reverted at block %d, reason: %s", e.Receipt.TxHash.String(), e.Receipt.BlockNumber.Uint64(), e.Reason)
+}
+
+// Watcher waits for the receipts of the txs.
+type Watcher struct {
+	client Client
+	// Interval is the time between the checks when the client doesn't support the subscriptions.
+	// With subscriptions it's the max time between checks, in case a head is missed
+	Interval time.Duration
+	// ABIs decode the custom errors of the contracts in the revert reasons
+	ABIs []*abi.ABI
+	// Clock is the source of time of the polls, wait.RealClock by default
+	Clock wait.Clock
+}
+
+// NewWatcher creates a new receipt watcher, decoding the custom errors of the contract ABIs.
+func NewWatcher(client Client, interval time.Duration, abis ...*abi.ABI) *Watcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Watcher{client: client, Interval: interval, ABIs: abis, Clock: wait.RealClock}
+}
+
+// WaitMined waits until the tx is mined and returns its receipt, whatever its status.
+func (w *Watcher) WaitMined(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	var heads chan *types.Header
+	if subscriber, ok := w.client.(headSubscriber); ok {
+		heads = make(chan *types.Header, 1)
+		sub, err := subscriber.SubscribeNewHead(ctx, heads)
+		if err != nil {
+			// The http clients don't support subscriptions
+			if !errors.Is(err, rpc.ErrNotificationsUnsupported) {
+				log.Debugf("error subscribing to the new heads, polling the receipt of the tx %s: %v", txHash.String(), err)
+			}
+			heads = nil
+		} else {
+			defer sub.Unsubscribe()
+		}
+	}
+	for {
+		receipt, err := w.client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			log.Debugf("error getting the receipt of the tx %s: %v", txHash.String(), err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-heads:
+		case <-w.Clock.After(w.Interval):
+		}
+	}
+}
+
+// Wait waits until the tx is mined and returns its receipt. A reverted tx returns a *RevertError with the reason.
+func (w *Watcher) Wait(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := w.WaitMined(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return receipt, nil
+	}
+	reason, err := w.RevertReason(ctx, tx, receipt.BlockNumber)
+	if err != nil {
+		reason = err.Error()
+	}
+	return receipt, &RevertError{Receipt: receipt, Reason: reason}
+}
+
+// RevertReason replays the tx with eth_call on the state before its block and returns the decoded revert reason.
+// The state of the block already includes the tx, so the call is at the parent block, without the txs before it
+// in the block.
+func (w *Watcher) RevertReason(ctx context.Context, tx *types.Transaction, blockNumber *big.Int) (string, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return "", err
+	}
+	msg := ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	callBlock := blockNumber
+	if blockNumber != nil && blockNumber.Sign() > 0 {
+		callBlock = new(big.Int).Sub(blockNumber, big.NewInt(1))
+	}
+	_, err = w.client.CallContract(ctx, msg, callBlock)
+	if err == nil {
+		// The txs before it in the block may have changed the state, so the call may succeed where the tx failed
+		return "unknown, the call before the block succeeds", nil
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(hexData); decodeErr == nil {
+				if reason, ok := DecodeRevert(data, w.ABIs...); ok {
+					return reason, nil
+				}
+			}
+		}
+	}
+	return err.Error(), nil
+}
+
+// DecodeRevert decodes the revert data of a call using the standard Error(string) and the custom errors of the ABIs.
+func DecodeRevert(data []byte, abis ...*abi.ABI) (string, bool) {
+	if len(data) < 4 { //nolint:gomnd
+		return "", false
+	}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason, true
+	}
+	for _, contractABI := range abis {
+		for name, e := range contractABI.Errors {
+			if bytes.Equal(e.ID[:4], data[:4]) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
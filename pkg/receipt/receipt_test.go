@@ -0,0 +1,172 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// client mines the tx after a number of receipt requests.
+type client struct {
+	mu       sync.Mutex
+	receipt  *types.Receipt
+	requests int
+	minedAt  int
+	callErr  error
+	callNum  *big.Int
+}
+
+func (c *client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	if c.requests < c.minedAt {
+		return nil, ethereum.NotFound
+	}
+	return c.receipt, nil
+}
+
+func (c *client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.callNum = blockNumber
+	return nil, c.callErr
+}
+
+// subscriber is a client that notifies the new heads.
+type subscriber struct {
+	*client
+	heads chan<- *types.Header
+	ready chan struct{}
+	err   error
+}
+
+func (s *subscriber) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.heads = ch
+	close(s.ready)
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+type dataError struct {
+	data string
+}
+
+func (e dataError) Error() string          { return "execution reverted" }
+func (e dataError) ErrorData() interface{} { return e.data }
+
+var _ rpc.DataError = dataError{}
+
+func signedTx(t *testing.T) *types.Transaction {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x01")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{To: &to, Gas: 21000, GasPrice: big.NewInt(1)})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestWaitPolling(t *testing.T) {
+	ctx := context.Background()
+	tx := signedTx(t)
+	c := &client{receipt: &types.Receipt{TxHash: tx.Hash(), Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)}, minedAt: 3}
+	w := NewWatcher(c, time.Second)
+	clock := wait.NewFakeClock(time.Unix(0, 0))
+	w.Clock = clock
+
+	receipt, err := w.Wait(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, c.receipt, receipt)
+	require.Equal(t, 3, c.requests)
+	require.Equal(t, 2*time.Second, clock.Slept())
+
+	// The http clients without subscriptions are polled
+	s := &subscriber{client: &client{receipt: c.receipt, minedAt: 2}, err: rpc.ErrNotificationsUnsupported}
+	w = NewWatcher(s, time.Second)
+	w.Clock = wait.NewFakeClock(time.Unix(0, 0))
+	_, err = w.Wait(ctx, tx)
+	require.NoError(t, err)
+
+	// The wait ends with the context
+	c = &client{minedAt: 1 << 30}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = NewWatcher(c, time.Millisecond).WaitMined(ctx, tx.Hash())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitNewHeads(t *testing.T) {
+	ctx := context.Background()
+	tx := signedTx(t)
+	s := &subscriber{client: &client{receipt: &types.Receipt{TxHash: tx.Hash(), Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)}, minedAt: 2}, ready: make(chan struct{})}
+	// The interval is too long to be reached, the receipt is checked on the new head
+	w := NewWatcher(s, time.Hour)
+
+	done := make(chan error)
+	go func() {
+		_, err := w.Wait(ctx, tx)
+		done <- err
+	}()
+	<-s.ready
+	s.heads <- &types.Header{Number: big.NewInt(10)}
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the receipt was not checked on the new head")
+	}
+	require.Equal(t, 2, s.requests)
+}
+
+func TestWaitReverted(t *testing.T) {
+	ctx := context.Background()
+	tx := signedTx(t)
+	receipt := &types.Receipt{TxHash: tx.Hash(), Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(10)}
+
+	// The standard Error(string)
+	errorABI, err := abi.JSON(strings.NewReader(`[{"inputs":[],"name":"AlreadyClaimed","type":"error"}]`))
+	require.NoError(t, err)
+	revert := append(crypto.Keccak256([]byte("Error(string)"))[:4], make([]byte, 96)...)
+	revert[35] = 32
+	revert[67] = 4
+	copy(revert[68:], "nope")
+	c := &client{receipt: receipt, callErr: dataError{data: hexutil.Encode(revert)}}
+	w := NewWatcher(c, time.Millisecond, &errorABI)
+	_, err = w.Wait(ctx, tx)
+	var revertErr *RevertError
+	require.True(t, errors.As(err, &revertErr))
+	require.Equal(t, "nope", revertErr.Reason)
+	// The tx is replayed on the state before its block
+	require.Equal(t, big.NewInt(9), c.callNum)
+
+	// The custom errors of the ABIs
+	errorID := errorABI.Errors["AlreadyClaimed"].ID
+	c.callErr = dataError{data: hexutil.Encode(errorID[:4])}
+	_, err = w.Wait(ctx, tx)
+	require.True(t, errors.As(err, &revertErr))
+	require.Equal(t, "AlreadyClaimed", revertErr.Reason)
+
+	// The errors without data
+	c.callErr = errors.New("out of gas")
+	_, err = w.Wait(ctx, tx)
+	require.True(t, errors.As(err, &revertErr))
+	require.Equal(t, "out of gas", revertErr.Reason)
+}
@@ -0,0 +1,60 @@
+// Package uint256 checks the amounts of the bridge, the uint256 values of the contracts, when they are read from
+// or written to the database and the API, so an amount is never truncated, overflowed or rounded on the way.
+package uint256
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// MaxDigits is the number of decimal digits of the max uint256.
+const MaxDigits = 78
+
+// Max is the max uint256, 2^256 - 1.
+var Max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)) //nolint:gomnd
+
+// ErrInvalid is returned for the amounts that are not uint256 values.
+var ErrInvalid = errors.New("invalid uint256 amount")
+
+// Check checks that the amount is a uint256 value.
+func Check(amount *big.Int) error {
+	if amount == nil {
+		return fmt.Errorf("%w: missing", ErrInvalid)
+	}
+	if amount.Sign() < 0 {
+		return fmt.Errorf("%w: %s is negative", ErrInvalid, amount.String())
+	}
+	if amount.Cmp(Max) > 0 {
+		return fmt.Errorf("%w: %s overflows 256 bits", ErrInvalid, amount.String())
+	}
+	return nil
+}
+
+// Parse parses the decimal string of a uint256 amount. Only the digits are accepted, without sign, spaces,
+// exponent or fraction, so a malformed amount is rejected instead of being read as another value.
+func Parse(s string) (*big.Int, error) {
+	amount, err := ParseSum(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := Check(amount); err != nil {
+		return nil, err
+	}
+	return amount, nil
+}
+
+// ParseSum parses the decimal string of a sum of amounts, e.g. the volume of a token, which can be greater than
+// the max uint256.
+func ParseSum(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalid)
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("%w: %q is not a decimal integer", ErrInvalid, s)
+		}
+	}
+	amount, _ := new(big.Int).SetString(s, 10) //nolint:gomnd
+	return amount, nil
+}
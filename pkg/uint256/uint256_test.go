@@ -0,0 +1,83 @@
+package uint256
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+// value is a random uint256 for the property tests, with the small, the large and the boundary values likely.
+type value struct {
+	*big.Int
+}
+
+func (value) Generate(r *rand.Rand, size int) reflect.Value {
+	var v *big.Int
+	switch r.Intn(4) { //nolint:gomnd
+	case 0:
+		v = new(big.Int).Sub(Max, big.NewInt(r.Int63n(1000))) //nolint:gomnd
+	case 1:
+		v = big.NewInt(r.Int63n(1000)) //nolint:gomnd
+	default:
+		v = new(big.Int).Rand(r, new(big.Int).Add(Max, big.NewInt(1)))
+	}
+	return reflect.ValueOf(value{v})
+}
+
+func TestParse(t *testing.T) {
+	for _, s := range []string{"", "-1", "+1", " 1", "1 ", "0x1", "1e30", "1.5", "1_000", "١"} {
+		_, err := Parse(s)
+		require.ErrorIs(t, err, ErrInvalid, s)
+	}
+	max, err := Parse(Max.String())
+	require.NoError(t, err)
+	require.Equal(t, Max, max)
+	require.Len(t, Max.String(), MaxDigits)
+	_, err = Parse(new(big.Int).Add(Max, big.NewInt(1)).String())
+	require.ErrorIs(t, err, ErrInvalid)
+	amount, err := Parse("000123")
+	require.NoError(t, err)
+	require.Equal(t, int64(123), amount.Int64())
+
+	// The sums can overflow 256 bits
+	sum, err := ParseSum(strings.Repeat("9", 100))
+	require.NoError(t, err)
+	require.Len(t, sum.String(), 100)
+	require.Error(t, Check(sum))
+	require.Error(t, Check(nil))
+	require.Error(t, Check(big.NewInt(-1)))
+}
+
+func TestParseProperties(t *testing.T) {
+	// Every uint256 is read back as itself from its decimal string
+	roundTrip := func(v value) bool {
+		parsed, err := Parse(v.String())
+		return err == nil && parsed.Cmp(v.Int) == 0 && Check(v.Int) == nil
+	}
+	require.NoError(t, quick.Check(roundTrip, nil))
+
+	// Adding Max to an amount that is not 0 always overflows
+	overflow := func(v value) bool {
+		sum := new(big.Int).Add(v.Int, Max)
+		_, err := Parse(sum.String())
+		return v.Sign() == 0 || err != nil
+	}
+	require.NoError(t, quick.Check(overflow, nil))
+
+	// A string with any char that is not a digit is never read as an amount
+	invalid := func(v value, c rune, pos uint8) bool {
+		if c >= '0' && c <= '9' {
+			return true
+		}
+		s := v.String()
+		i := int(pos) % (len(s) + 1)
+		_, err := Parse(s[:i] + string(c) + s[i:])
+		return err != nil
+	}
+	require.NoError(t, quick.Check(invalid, nil))
+}
@@ -0,0 +1,166 @@
+// Package wait provides the polling and retrying helpers used to wait for conditions,
+// with context support and pluggable clocks so the waits can be tested deterministically.
+package wait
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is returned when the condition is not met before the timeout or the max attempts.
+var ErrTimeout = errors.New("condition not met before the timeout")
+
+// Clock is the source of time of the waits.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RealClock is the clock of the system.
+var RealClock Clock = realClock{}
+
+// FakeClock is a clock for the tests that doesn't sleep: each wait fires at once
+// and moves the clock forward by the waited duration.
+type FakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept time.Duration
+}
+
+// NewFakeClock creates a fake clock starting at the provided time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now implements Clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After implements Clock.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.slept += d
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// Slept returns the total time waited on the clock.
+func (c *FakeClock) Slept() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.slept
+}
+
+// Waiter defines how long a condition is waited for.
+type Waiter struct {
+	// Interval is the time between two attempts
+	Interval time.Duration
+	// Timeout is the max time waiting. 0 waits without timeout
+	Timeout time.Duration
+	// Attempts is the max number of attempts. 0 doesn't limit the attempts
+	Attempts int
+	// Multiplier multiplies the interval after each attempt, for an exponential backoff. 0 or 1 keeps the interval
+	Multiplier float64
+	// MaxInterval is the max time between two attempts with a multiplier. 0 doesn't limit it
+	MaxInterval time.Duration
+	// Clock is the source of time. nil uses the system clock
+	Clock Clock
+}
+
+func (w Waiter) clock() Clock {
+	if w.Clock == nil {
+		return RealClock
+	}
+	return w.Clock
+}
+
+// Backoff returns the time waited after the attempt, starting at 1.
+func (w Waiter) Backoff(attempt int) time.Duration {
+	interval := w.Interval
+	for i := 1; i < attempt && w.Multiplier > 1; i++ {
+		interval = time.Duration(float64(interval) * w.Multiplier)
+		if w.MaxInterval > 0 && interval >= w.MaxInterval {
+			return w.MaxInterval
+		}
+	}
+	return interval
+}
+
+// Condition checks if the wait is done, returning the value waited for.
+type Condition[T any] func(ctx context.Context) (value T, done bool, err error)
+
+// Until checks the condition until it's done and returns its value. It stops with the error
+// of the condition, ErrTimeout when the timeout or the attempts are exhausted, or the error of the context.
+func Until[T any](ctx context.Context, w Waiter, condition Condition[T]) (T, error) {
+	clock := w.clock()
+	deadline := clock.Now().Add(w.Timeout)
+	for attempt := 1; ; attempt++ {
+		value, done, err := condition(ctx)
+		if err != nil || done {
+			return value, err
+		}
+		interval := w.Backoff(attempt)
+		if w.Attempts > 0 && attempt >= w.Attempts || w.Timeout > 0 && !clock.Now().Add(interval).Before(deadline) {
+			return value, ErrTimeout
+		}
+		if err := Sleep(ctx, clock, interval); err != nil {
+			return value, err
+		}
+	}
+}
+
+// Poll checks the condition every interval until it's done, it fails or the timeout expires.
+func Poll(ctx context.Context, interval, timeout time.Duration, condition func() (bool, error)) error {
+	_, err := Until(ctx, Waiter{Interval: interval, Timeout: timeout}, func(context.Context) (struct{}, bool, error) {
+		done, err := condition()
+		return struct{}{}, done, err
+	})
+	return err
+}
+
+// Retry calls f until it succeeds, the attempts or the timeout of the waiter are exhausted, or the error
+// is not retryable. A nil retryable retries all the errors. It returns the last result and error of f.
+func Retry[T any](ctx context.Context, w Waiter, f func(ctx context.Context) (T, error), retryable func(error) bool) (T, error) {
+	var lastErr error
+	value, err := Until(ctx, w, func(ctx context.Context) (T, bool, error) {
+		value, err := f(ctx)
+		lastErr = err
+		if err == nil {
+			return value, true, nil
+		}
+		if retryable != nil && !retryable(err) {
+			return value, false, err
+		}
+		return value, false, nil
+	})
+	if errors.Is(err, ErrTimeout) {
+		return value, lastErr
+	}
+	return value, err
+}
+
+// Sleep waits for the duration on the clock, returning early with the error of the context if it's done.
+func Sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	if clock == nil {
+		clock = RealClock
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(d):
+		return nil
+	}
+}
@@ -0,0 +1,112 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUntil(t *testing.T) {
+	ctx := context.Background()
+	clock := NewFakeClock(time.Unix(0, 0))
+	w := Waiter{Interval: time.Second, Timeout: 10 * time.Second, Clock: clock}
+
+	// The value of the condition is returned once it's done
+	calls := 0
+	value, err := Until(ctx, w, func(context.Context) (int, bool, error) {
+		calls++
+		return calls, calls == 3, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, value)
+	require.Equal(t, 2*time.Second, clock.Slept())
+
+	// The timeout stops the wait
+	_, err = Until(ctx, w, func(context.Context) (int, bool, error) { return 0, false, nil })
+	require.ErrorIs(t, err, ErrTimeout)
+	require.Equal(t, 11*time.Second, clock.Slept())
+
+	// The attempts stop the wait
+	calls = 0
+	_, err = Until(ctx, Waiter{Interval: time.Second, Attempts: 4, Clock: clock}, func(context.Context) (int, bool, error) {
+		calls++
+		return 0, false, nil
+	})
+	require.ErrorIs(t, err, ErrTimeout)
+	require.Equal(t, 4, calls)
+
+	// The error of the condition stops the wait
+	errCondition := errors.New("condition error")
+	_, err = Until(ctx, w, func(context.Context) (int, bool, error) { return 0, false, errCondition })
+	require.ErrorIs(t, err, errCondition)
+
+	// The context stops the wait
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = Until(ctx, Waiter{Interval: time.Hour}, func(context.Context) (int, bool, error) { return 0, false, nil })
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	w := Waiter{Interval: time.Second, Attempts: 3, Clock: NewFakeClock(time.Unix(0, 0))}
+	errRetryable := errors.New("retryable")
+	errFinal := errors.New("final")
+
+	calls := 0
+	value, err := Retry(ctx, w, func(context.Context) (string, error) {
+		calls++
+		if calls < 2 {
+			return "", errRetryable
+		}
+		return "ok", nil
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "ok", value)
+
+	// The last error is returned when the attempts are exhausted
+	calls = 0
+	_, err = Retry(ctx, w, func(context.Context) (string, error) {
+		calls++
+		return "", errRetryable
+	}, nil)
+	require.ErrorIs(t, err, errRetryable)
+	require.Equal(t, 3, calls)
+
+	// The errors not retryable are returned at once
+	calls = 0
+	_, err = Retry(ctx, w, func(context.Context) (string, error) {
+		calls++
+		return "", errFinal
+	}, func(err error) bool { return !errors.Is(err, errFinal) })
+	require.ErrorIs(t, err, errFinal)
+	require.Equal(t, 1, calls)
+}
+
+func TestPoll(t *testing.T) {
+	calls := 0
+	require.NoError(t, Poll(context.Background(), time.Millisecond, time.Second, func() (bool, error) {
+		calls++
+		return calls == 2, nil
+	}))
+	require.ErrorIs(t, Poll(context.Background(), time.Millisecond, 5*time.Millisecond, func() (bool, error) { return false, nil }), ErrTimeout)
+}
+
+func TestBackoff(t *testing.T) {
+	w := Waiter{Interval: time.Second, Multiplier: 2, MaxInterval: 5 * time.Second}
+	require.Equal(t, time.Second, w.Backoff(1))
+	require.Equal(t, 2*time.Second, w.Backoff(2))
+	require.Equal(t, 4*time.Second, w.Backoff(3))
+	require.Equal(t, 5*time.Second, w.Backoff(4))
+	require.Equal(t, time.Second, Waiter{Interval: time.Second}.Backoff(10))
+
+	// The attempts wait with the backoff
+	clock := NewFakeClock(time.Unix(0, 0))
+	w.Attempts, w.Clock = 4, clock
+	_, err := Until(context.Background(), w, func(context.Context) (int, bool, error) { return 0, false, nil })
+	require.ErrorIs(t, err, ErrTimeout)
+	require.Equal(t, 7*time.Second, clock.Slept())
+}
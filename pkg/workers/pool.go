@@ -0,0 +1,127 @@
+// Package workers runs the pools of workers of the background queues, scaled between a min and a max number of
+// workers by the depth of their queue, so a burst or a slow destination is absorbed without keeping the workers of
+// the peak.
+package workers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config is the size of a pool of workers.
+type Config struct {
+	// Min is the number of workers started, never stopped
+	Min int
+	// Max is the max number of workers, added while items wait in the queue. Less than Min is Min
+	Max int
+	// ScaleInterval is the time between two checks of the queue to add the workers or stop an idle one
+	ScaleInterval time.Duration
+}
+
+// Pool runs the workers handling the items of a queue.
+type Pool[T any] struct {
+	cfg    Config
+	items  <-chan T
+	depth  func() int
+	handle func(ctx context.Context, item T)
+
+	// stop is received by an idle worker to stop it
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	running atomic.Int64
+	busy    atomic.Int64
+}
+
+// New creates a pool handling the items of the channel, scaled with the depth of the queue.
+func New[T any](cfg Config, items <-chan T, depth func() int, handle func(ctx context.Context, item T)) *Pool[T] {
+	if cfg.Min <= 0 {
+		cfg.Min = 1
+	}
+	if cfg.Max < cfg.Min {
+		cfg.Max = cfg.Min
+	}
+	return &Pool[T]{
+		cfg:    cfg,
+		items:  items,
+		depth:  depth,
+		handle: handle,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Run runs the workers until the context is done, waiting for the items being handled.
+func (p *Pool[T]) Run(ctx context.Context) {
+	for i := 0; i < p.cfg.Min; i++ {
+		p.add(ctx)
+	}
+	if p.cfg.Max > p.cfg.Min && p.cfg.ScaleInterval > 0 {
+		ticker := time.NewTicker(p.cfg.ScaleInterval)
+		defer ticker.Stop()
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case <-ticker.C:
+				p.scale(ctx)
+			}
+		}
+	}
+	p.wg.Wait()
+}
+
+// Workers returns the number of running workers.
+func (p *Pool[T]) Workers() int {
+	return int(p.running.Load())
+}
+
+// Busy returns the number of workers handling an item.
+func (p *Pool[T]) Busy() int {
+	return int(p.busy.Load())
+}
+
+// scale adds a worker for each item waiting, up to the max, or stops an idle worker when none is waiting.
+func (p *Pool[T]) scale(ctx context.Context) {
+	running, waiting := p.Workers(), p.depth()
+	switch {
+	case waiting > 0 && running < p.cfg.Max:
+		n := waiting
+		if n > p.cfg.Max-running {
+			n = p.cfg.Max - running
+		}
+		for i := 0; i < n; i++ {
+			p.add(ctx)
+		}
+	case waiting == 0 && running > p.cfg.Min && p.Busy() < running:
+		// Only an idle worker receives it, the busy ones aren't waiting
+		select {
+		case p.stop <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *Pool[T]) add(ctx context.Context) {
+	p.running.Add(1)
+	p.wg.Add(1)
+	go p.work(ctx)
+}
+
+func (p *Pool[T]) work(ctx context.Context) {
+	defer p.wg.Done()
+	defer p.running.Add(-1)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.stop:
+			return
+		case item := <-p.items:
+			p.busy.Add(1)
+			p.handle(ctx, item)
+			p.busy.Add(-1)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make(chan int, 10)
+	release := make(chan struct{})
+	var (
+		mu      sync.Mutex
+		handled []int
+	)
+	p := New(Config{Min: 1, Max: 3, ScaleInterval: time.Millisecond}, items, func() int { return len(items) }, func(ctx context.Context, item int) {
+		<-release
+		mu.Lock()
+		handled = append(handled, item)
+		mu.Unlock()
+	})
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return p.Workers() == 1 }, time.Second, time.Millisecond)
+
+	// The items waiting behind the blocked worker add workers, up to the max
+	for i := 0; i < 5; i++ {
+		items <- i
+	}
+	require.Eventually(t, func() bool { return p.Workers() == 3 && p.Busy() == 3 }, time.Second, time.Millisecond)
+
+	// The idle workers are stopped once the queue is empty, down to the min
+	close(release)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handled) == 5
+	}, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return p.Workers() == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+	require.Equal(t, 0, p.Workers())
+}
+
+func TestPoolFixed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make(chan int)
+	// Without scale interval nor max, the pool keeps the min
+	p := New(Config{Min: 2}, items, func() int { return 0 }, func(ctx context.Context, item int) {})
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return p.Workers() == 2 }, time.Second, time.Millisecond)
+	items <- 1
+	cancel()
+	<-done
+}
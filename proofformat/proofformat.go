@@ -0,0 +1,142 @@
+// Package proofformat encodes the inputs of the claim of a claim witness for the external tooling: the proofs as
+// flat hex, the arguments of the claim as a JSON array, as a solidity tuple, or as a foundry cast command, so the
+// scripts calling claimAsset or claimMessage don't reformat them.
+package proofformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+)
+
+// Formats of the claim inputs
+const (
+	// FormatHex is the proofs as flat hex, the siblings of each proof concatenated, one proof per line
+	FormatHex = "hex"
+	// FormatJSON is the arguments of the claim as a JSON array, the proofs as arrays of hex strings
+	FormatJSON = "json"
+	// FormatTuple is the arguments of the claim as a solidity tuple literal
+	FormatTuple = "tuple"
+	// FormatCast is the cast send command of the claim, with the bridge address in $BRIDGE
+	FormatCast = "cast"
+)
+
+// leafTypeMessage is the leaf type of the bridge messages, claimed with claimMessage
+const leafTypeMessage = 1
+
+// IsFormat returns if the format of the claim inputs is supported.
+func IsFormat(format string) bool {
+	return format == FormatHex || format == FormatJSON || format == FormatTuple || format == FormatCast
+}
+
+// arg is an argument of the claim: a value, or the siblings of a proof.
+type arg struct {
+	value  string
+	proof  []string
+	number bool
+}
+
+// Encode returns the inputs of the claim of the witness in the format.
+func Encode(format string, witness *pb.ClaimWitness) (string, error) {
+	signature, args, err := claimArgs(witness)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case FormatHex:
+		var lines []string
+		for _, a := range args {
+			if a.proof == nil {
+				continue
+			}
+			var line strings.Builder
+			line.WriteString("0x")
+			for _, sibling := range a.proof {
+				line.WriteString(strings.TrimPrefix(sibling, "0x"))
+			}
+			lines = append(lines, line.String())
+		}
+		return strings.Join(lines, "\n"), nil
+	case FormatJSON:
+		values := make([]interface{}, 0, len(args))
+		for _, a := range args {
+			switch {
+			case a.proof != nil:
+				values = append(values, a.proof)
+			case a.number:
+				values = append(values, json.Number(a.value))
+			default:
+				values = append(values, a.value)
+			}
+		}
+		b, err := json.Marshal(values)
+		return string(b), err
+	case FormatTuple:
+		values := make([]string, 0, len(args))
+		for _, a := range args {
+			values = append(values, a.literal())
+		}
+		return "(" + strings.Join(values, ",") + ")", nil
+	case FormatCast:
+		values := []string{"cast", "send", "$BRIDGE", strconv.Quote(signature)}
+		for _, a := range args {
+			if a.proof != nil {
+				values = append(values, "'"+a.literal()+"'")
+			} else {
+				values = append(values, a.literal())
+			}
+		}
+		return strings.Join(values, " "), nil
+	default:
+		return "", fmt.Errorf("unknown format %s, it must be %s, %s, %s or %s", format, FormatHex, FormatJSON, FormatTuple, FormatCast)
+	}
+}
+
+// literal returns the argument as a solidity literal, the proofs as arrays.
+func (a arg) literal() string {
+	if a.proof != nil {
+		return "[" + strings.Join(a.proof, ",") + "]"
+	}
+	return a.value
+}
+
+// claimArgs returns the signature and the arguments of the claim of the witness, in the order of the claim
+// interface of its version.
+func claimArgs(witness *pb.ClaimWitness) (string, []arg, error) {
+	method := "claimAsset"
+	if witness.LeafType == leafTypeMessage {
+		method = "claimMessage"
+	}
+	leaf := []arg{
+		{value: witness.MainnetExitRoot},
+		{value: witness.RollupExitRoot},
+		{value: strconv.FormatUint(uint64(witness.OrigNet), 10), number: true},
+		{value: witness.OrigAddr},
+		{value: strconv.FormatUint(uint64(witness.DestNet), 10), number: true},
+		{value: witness.DestAddr},
+		{value: witness.Amount, number: true},
+		{value: witness.Metadata},
+	}
+	const leafTypes = "bytes32,bytes32,uint32,address,uint32,address,uint256,bytes"
+	switch witness.Version {
+	case etherman.ClaimVersionV1:
+		args := append([]arg{
+			{proof: witness.LocalExitRootProof},
+			{value: strconv.FormatUint(witness.Index, 10), number: true},
+		}, leaf...)
+		return method + "(bytes32[32],uint32," + leafTypes + ")", args, nil
+	case etherman.ClaimVersionV2:
+		args := append([]arg{
+			{proof: witness.LocalExitRootProof},
+			{proof: witness.RollupExitRootProof},
+			{value: witness.GlobalIndex, number: true},
+		}, leaf...)
+		return method + "(bytes32[32],bytes32[32],uint256," + leafTypes + ")", args, nil
+	default:
+		return "", nil, fmt.Errorf("unknown claim version %s", witness.Version)
+	}
+}
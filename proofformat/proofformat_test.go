@@ -0,0 +1,96 @@
+package proofformat
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func newWitness(version string) *pb.ClaimWitness {
+	proof := make([]string, 32)
+	for i := range proof {
+		proof[i] = common.BigToHash(common.Big1).Hex()
+	}
+	w := &pb.ClaimWitness{
+		Version:            version,
+		OrigNet:            0,
+		OrigAddr:           common.HexToAddress("0x1").Hex(),
+		Amount:             "1000000000000000000",
+		DestNet:            1,
+		DestAddr:           common.HexToAddress("0x2").Hex(),
+		Metadata:           "0x",
+		Index:              5,
+		LocalExitRootProof: proof,
+		MainnetExitRoot:    common.HexToHash("0x3").Hex(),
+		RollupExitRoot:     common.HexToHash("0x4").Hex(),
+	}
+	if version == etherman.ClaimVersionV2 {
+		w.GlobalIndex = etherman.GlobalIndex(0, 5).String()
+		w.RollupExitRootProof = proof
+	}
+	return w
+}
+
+func TestClaimArgs(t *testing.T) {
+	// The signatures are the ones of the claims packed by the etherman
+	for _, c := range []struct {
+		version  string
+		leafType uint32
+		selector string
+	}{
+		{etherman.ClaimVersionV1, 0, "0x2cffd02e"},
+		{etherman.ClaimVersionV1, leafTypeMessage, "0x2d2c9d94"},
+		{etherman.ClaimVersionV2, 0, "0xccaa2d11"},
+		{etherman.ClaimVersionV2, leafTypeMessage, "0xf5efcd79"},
+	} {
+		w := newWitness(c.version)
+		w.LeafType = c.leafType
+		signature, args, err := claimArgs(w)
+		require.NoError(t, err)
+		require.Equal(t, c.selector, hexutil.Encode(crypto.Keccak256([]byte(signature))[:4]))
+		require.Len(t, args, strings.Count(signature, ",")+1)
+	}
+	_, _, err := claimArgs(newWitness("v3"))
+	require.Error(t, err)
+}
+
+func TestEncode(t *testing.T) {
+	w := newWitness(etherman.ClaimVersionV2)
+	sibling := strings.TrimPrefix(w.LocalExitRootProof[0], "0x")
+
+	encoded, err := Encode(FormatHex, w)
+	require.NoError(t, err)
+	lines := strings.Split(encoded, "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, "0x"+strings.Repeat(sibling, 32), lines[0])
+
+	encoded, err = Encode(FormatJSON, w)
+	require.NoError(t, err)
+	var args []interface{}
+	require.NoError(t, json.Unmarshal([]byte(encoded), &args))
+	require.Len(t, args, 11)
+	require.Len(t, args[0], 32)
+	require.Equal(t, float64(1), args[7])
+	require.Contains(t, encoded, `,18446744073709551621,`)
+
+	encoded, err = Encode(FormatTuple, w)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "([0x"+sibling+","))
+	require.True(t, strings.HasSuffix(encoded, ","+w.DestAddr+",1000000000000000000,0x)"))
+
+	encoded, err = Encode(FormatCast, newWitness(etherman.ClaimVersionV1))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, `cast send $BRIDGE "claimAsset(bytes32[32],uint32,bytes32,bytes32,uint32,address,uint32,address,uint256,bytes)" '[0x`))
+	require.True(t, strings.HasSuffix(encoded, "]' 5 "+w.MainnetExitRoot+" "+w.RollupExitRoot+" 0 "+w.OrigAddr+" 1 "+w.DestAddr+" 1000000000000000000 0x"))
+
+	_, err = Encode("bytes", w)
+	require.Error(t, err)
+	require.False(t, IsFormat("bytes"))
+}
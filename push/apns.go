@@ -0,0 +1,142 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	apnsHost        = "https://api.push.apple.com"
+	apnsSandboxHost = "https://api.sandbox.push.apple.com"
+	// apnsTokenRefresh renews the provider tokens, apple rejects the tokens older than an hour and the tokens
+	// renewed more than once every 20 minutes
+	apnsTokenRefresh = 30 * time.Minute
+	// es256KeySize is the size of each of the r and s integers of the ES256 signatures
+	es256KeySize = 32
+)
+
+// APNsSender sends the notifications through the Apple Push Notification service, authenticated with the
+// provider tokens of a signing key. The service requires HTTP/2, negotiated by the default transport.
+type APNsSender struct {
+	client *http.Client
+	cfg    APNsConfig
+	key    *ecdsa.PrivateKey
+	host   string
+	now    func() time.Time
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsSender creates the sender of the .p8 signing key.
+func NewAPNsSender(cfg APNsConfig, p8 []byte, client *http.Client) (*APNsSender, error) {
+	if cfg.KeyID == "" || cfg.TeamID == "" || cfg.Topic == "" {
+		return nil, errors.New("invalid apns config: missing KeyID, TeamID or Topic")
+	}
+	der, err := decodePEM(p8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apns key: %w", err)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apns key: %w", err)
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid apns key: the key is not an ecdsa key")
+	}
+	host := apnsHost
+	if cfg.Sandbox {
+		host = apnsSandboxHost
+	}
+	return &APNsSender{client: client, cfg: cfg, key: ecKey, host: host, now: time.Now}, nil
+}
+
+// Send implements Sender.
+func (s *APNsSender) Send(ctx context.Context, token string, n Notification) error {
+	providerToken, err := s.getProviderToken()
+	if err != nil {
+		return err
+	}
+	// The data is sent in the custom keys of the payload, next to the aps dictionary
+	payload := make(map[string]interface{}, len(n.Data)+1)
+	for k, v := range n.Data {
+		payload[k] = v
+	}
+	payload["aps"] = map[string]interface{}{"alert": map[string]string{"title": n.Title, "body": n.Body}}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.host+"/3/device/"+url.PathEscape(token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", s.cfg.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	var reply struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(res.Body).Decode(&reply)
+	// The tokens of the uninstalled apps are gone, and the tokens of the other environment are bad
+	if res.StatusCode == http.StatusGone || reply.Reason == "BadDeviceToken" || reply.Reason == "Unregistered" {
+		return ErrUnregistered
+	}
+	if reply.Reason == "ExpiredProviderToken" {
+		s.mu.Lock()
+		s.token = ""
+		s.mu.Unlock()
+	}
+	return fmt.Errorf("unexpected apns status %s: %s", res.Status, reply.Reason)
+}
+
+// getProviderToken returns the provider token, signing a new one every apnsTokenRefresh.
+func (s *APNsSender) getProviderToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.token != "" && now.Sub(s.issuedAt) < apnsTokenRefresh {
+		return s.token, nil
+	}
+	token, err := signJWT(map[string]string{"alg": "ES256", "kid": s.cfg.KeyID}, map[string]interface{}{
+		"iss": s.cfg.TeamID,
+		"iat": now.Unix(),
+	}, func(signingInput []byte) ([]byte, error) {
+		digest := sha256.Sum256(signingInput)
+		r, ss, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		// The JWS signatures are the fixed size r and s, not the der encoding
+		signature := make([]byte, 2*es256KeySize) //nolint:gomnd
+		r.FillBytes(signature[:es256KeySize])
+		ss.FillBytes(signature[es256KeySize:])
+		return signature, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	s.token, s.issuedAt = token, now
+	return token, nil
+}
@@ -0,0 +1,66 @@
+package push
+
+import (
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/workers"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the push notifications
+type Config struct {
+	// Enabled sends the push notifications to the devices registered by the wallets
+	Enabled bool `mapstructure:"Enabled"`
+	// Workers is the number of notifications sent concurrently, 0 for the number of the Tuning config for the CPUs
+	Workers int `mapstructure:"Workers"`
+	// MaxWorkers is the max number of workers, added while the notifications wait in the queue. 0 is Workers
+	MaxWorkers int `mapstructure:"MaxWorkers"`
+	// ScaleInterval is the time between the checks of the queue to add the workers or stop the idle ones
+	ScaleInterval types.Duration `mapstructure:"ScaleInterval"`
+	// QueueSize is the max number of notifications waiting for a worker
+	QueueSize int `mapstructure:"QueueSize"`
+	// QueuePolicy is applied to the new notifications when the queue is full: drop_newest, drop_oldest or block,
+	// which blocks the sync and claim loops. Empty is drop_newest
+	QueuePolicy queue.Policy `mapstructure:"QueuePolicy"`
+	// Timeout is the max time waiting for the response of the push services
+	Timeout types.Duration `mapstructure:"Timeout"`
+	// FCM is the configuration of Firebase Cloud Messaging, for the fcm devices
+	FCM FCMConfig `mapstructure:"FCM"`
+	// APNs is the configuration of the Apple Push Notification service, for the apns devices
+	APNs APNsConfig `mapstructure:"APNs"`
+}
+
+// QueueConfig returns the config of the queue of the notifications.
+func (c Config) QueueConfig() queue.Config {
+	policy := c.QueuePolicy
+	if policy == "" {
+		policy = queue.PolicyDropNewest
+	}
+	return queue.Config{Size: c.QueueSize, Policy: policy}
+}
+
+// PoolConfig returns the config of the pool of the workers.
+func (c Config) PoolConfig() workers.Config {
+	return workers.Config{Min: c.Workers, Max: c.MaxWorkers, ScaleInterval: c.ScaleInterval.Duration}
+}
+
+// FCMConfig is the configuration of Firebase Cloud Messaging
+type FCMConfig struct {
+	// CredentialsFile is the json key of the service account of the firebase project, empty doesn't send the
+	// notifications of the fcm devices
+	CredentialsFile string `mapstructure:"CredentialsFile"`
+}
+
+// APNsConfig is the configuration of the Apple Push Notification service
+type APNsConfig struct {
+	// KeyFile is the .p8 signing key of the apple developer account, empty doesn't send the notifications of the
+	// apns devices
+	KeyFile string `mapstructure:"KeyFile"`
+	// KeyID is the id of the signing key
+	KeyID string `mapstructure:"KeyID"`
+	// TeamID is the id of the apple developer team
+	TeamID string `mapstructure:"TeamID"`
+	// Topic is the bundle id of the app
+	Topic string `mapstructure:"Topic"`
+	// Sandbox sends the notifications to the development environment, for the debug builds of the app
+	Sandbox bool `mapstructure:"Sandbox"`
+}
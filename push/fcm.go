@@ -0,0 +1,177 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fcmEndpoint = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	fcmScope    = "https://www.googleapis.com/auth/firebase.messaging"
+	// fcmTokenLifetime is the lifetime requested for the access tokens, the max allowed by google
+	fcmTokenLifetime = time.Hour
+	// fcmTokenMargin renews the access tokens before they expire
+	fcmTokenMargin = time.Minute
+)
+
+// FCMSender sends the notifications through the HTTP v1 API of Firebase Cloud Messaging, authenticated with the
+// OAuth2 access tokens of a service account.
+type FCMSender struct {
+	client   *http.Client
+	email    string
+	key      *rsa.PrivateKey
+	tokenURI string
+	endpoint string
+	now      func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+// NewFCMSender creates the sender of the json key of a service account.
+func NewFCMSender(credentials []byte, client *http.Client) (*FCMSender, error) {
+	var c struct {
+		ProjectID   string `json:"project_id"`
+		PrivateKey  string `json:"private_key"`
+		ClientEmail string `json:"client_email"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(credentials, &c); err != nil {
+		return nil, fmt.Errorf("invalid fcm credentials: %w", err)
+	}
+	if c.ProjectID == "" || c.ClientEmail == "" || c.TokenURI == "" {
+		return nil, errors.New("invalid fcm credentials: missing project_id, client_email or token_uri")
+	}
+	der, err := decodePEM([]byte(c.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid fcm credentials: %w", err)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fcm credentials: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid fcm credentials: the private key is not a rsa key")
+	}
+	return &FCMSender{
+		client:   client,
+		email:    c.ClientEmail,
+		key:      rsaKey,
+		tokenURI: c.TokenURI,
+		endpoint: fmt.Sprintf(fcmEndpoint, url.PathEscape(c.ProjectID)),
+		now:      time.Now,
+	}, nil
+}
+
+// Send implements Sender.
+func (s *FCMSender) Send(ctx context.Context, token string, n Notification) error {
+	accessToken, err := s.getAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+	var msg struct {
+		Message struct {
+			Token        string            `json:"token"`
+			Notification map[string]string `json:"notification"`
+			Data         map[string]string `json:"data,omitempty"`
+		} `json:"message"`
+	}
+	msg.Message.Token = token
+	msg.Message.Notification = map[string]string{"title": n.Title, "body": n.Body}
+	msg.Message.Data = n.Data
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	resBody, _ := io.ReadAll(io.LimitReader(res.Body, 4096)) //nolint:gomnd
+	// The tokens of the uninstalled apps are not found
+	if res.StatusCode == http.StatusNotFound || bytes.Contains(resBody, []byte("UNREGISTERED")) {
+		return ErrUnregistered
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		s.mu.Lock()
+		s.accessToken = ""
+		s.mu.Unlock()
+	}
+	return fmt.Errorf("unexpected fcm status %s: %s", res.Status, strings.TrimSpace(string(resBody)))
+}
+
+// getAccessToken returns the cached access token, exchanging a new assertion of the service account when it
+// expires.
+func (s *FCMSender) getAccessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.accessToken != "" && now.Before(s.expiry) {
+		return s.accessToken, nil
+	}
+	assertion, err := signJWT(map[string]string{"alg": "RS256", "typ": "JWT"}, map[string]interface{}{
+		"iss":   s.email,
+		"scope": fcmScope,
+		"aud":   s.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(fcmTokenLifetime).Unix(),
+	}, func(signingInput []byte) ([]byte, error) {
+		digest := sha256.Sum256(signingInput)
+		return rsa.SignPKCS1v15(nil, s.key, crypto.SHA256, digest[:])
+	})
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected fcm token status %s", res.Status)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid fcm token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("invalid fcm token response: missing access_token")
+	}
+	s.accessToken = token.AccessToken
+	s.expiry = now.Add(time.Duration(token.ExpiresIn)*time.Second - fcmTokenMargin)
+	return s.accessToken, nil
+}
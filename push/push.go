@@ -0,0 +1,241 @@
+// Package push sends mobile push notifications to the devices registered by the wallets, when the deposits to
+// their address become claimable and when they are claimed. The notifications are sent through Firebase Cloud
+// Messaging for the android devices and the Apple Push Notification service for the ios devices.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/workers"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// PlatformFCM is the platform of the devices receiving the notifications through Firebase Cloud Messaging
+	PlatformFCM = "fcm"
+	// PlatformAPNs is the platform of the devices receiving the notifications through the Apple Push
+	// Notification service
+	PlatformAPNs = "apns"
+
+	// EventClaimable is the notification of a deposit ready to be claimed
+	EventClaimable = "claimable"
+	// EventCompleted is the notification of a claimed deposit
+	EventCompleted = "completed"
+
+	// publishedSize is the number of notifications remembered as published, so the events synced again by a
+	// resync are not notified twice
+	publishedSize = 100000
+)
+
+// ErrUnregistered is returned by the senders when the device token is no longer valid, so the device is deleted.
+var ErrUnregistered = errors.New("device token unregistered")
+
+// Notification is a push notification, with the text shown to the user and the data read by the app.
+type Notification struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender sends the notifications of a platform.
+type Sender interface {
+	Send(ctx context.Context, token string, n Notification) error
+}
+
+type storageInterface interface {
+	GetPushDevices(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.PushDevice, error)
+	DeletePushDevice(ctx context.Context, address common.Address, platform, token string, dbTx pgx.Tx) error
+}
+
+type message struct {
+	address      common.Address
+	notification Notification
+}
+
+// Notifier sends the notifications of the bridge events to the devices of the destination addresses.
+// It implements hooks.Hook, so the events are received from the sync and claim loops.
+type Notifier struct {
+	hooks.NopHook
+	cfg     Config
+	storage storageInterface
+	senders map[string]Sender
+	queue   *queue.Queue[message]
+	// published are the ids of the last notifications queued
+	published *lru.Cache[string, struct{}]
+}
+
+// NewNotifier creates the notifier with the senders of the configured push services.
+func NewNotifier(cfg Config, storage interface{}) (*Notifier, error) {
+	client := &http.Client{Timeout: cfg.Timeout.Duration}
+	senders := make(map[string]Sender)
+	if cfg.FCM.CredentialsFile != "" {
+		credentials, err := os.ReadFile(cfg.FCM.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the fcm credentials: %w", err)
+		}
+		if senders[PlatformFCM], err = NewFCMSender(credentials, client); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.APNs.KeyFile != "" {
+		key, err := os.ReadFile(cfg.APNs.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the apns key: %w", err)
+		}
+		if senders[PlatformAPNs], err = NewAPNsSender(cfg.APNs, key, client); err != nil {
+			return nil, err
+		}
+	}
+	if len(senders) == 0 {
+		return nil, errors.New("push notifications enabled without fcm credentials nor apns key")
+	}
+	return newNotifier(cfg, storage.(storageInterface), senders)
+}
+
+func newNotifier(cfg Config, storage storageInterface, senders map[string]Sender) (*Notifier, error) {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	// The notifications are not stored, they are only useful while the deposit is fresh
+	q, err := queue.New[message]("push", cfg.QueueConfig(), nil)
+	if err != nil {
+		return nil, err
+	}
+	published, err := lru.New[string, struct{}](publishedSize)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{
+		cfg:       cfg,
+		storage:   storage,
+		senders:   senders,
+		queue:     q,
+		published: published,
+	}, nil
+}
+
+// Platforms returns the platforms with a configured push service.
+func (n *Notifier) Platforms() []string {
+	var platforms []string
+	for _, platform := range []string{PlatformFCM, PlatformAPNs} {
+		if _, ok := n.senders[platform]; ok {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms
+}
+
+// Start runs the workers sending the notifications until the context is done, scaled up to MaxWorkers while the
+// notifications wait in the queue.
+func (n *Notifier) Start(ctx context.Context) {
+	workers.New(n.cfg.PoolConfig(), n.queue.C(), func() int { return n.queue.Stats().Depth }, n.send).Run(ctx)
+}
+
+// OnDepositReady implements hooks.Hook.
+func (n *Notifier) OnDepositReady(ctx context.Context, deposit *etherman.Deposit) {
+	n.publish(ctx, deposit.DestinationAddress, claimableID(deposit), claimableNotification(deposit))
+}
+
+// OnL2DepositReady implements hooks.Hook.
+func (n *Notifier) OnL2DepositReady(ctx context.Context, deposit *etherman.Deposit) {
+	n.publish(ctx, deposit.DestinationAddress, claimableID(deposit), claimableNotification(deposit))
+}
+
+// OnClaimIndexed implements hooks.Hook.
+func (n *Notifier) OnClaimIndexed(ctx context.Context, claim *etherman.Claim) {
+	n.publish(ctx, claim.DestinationAddress, completedID(claim), completedNotification(claim))
+}
+
+// claimableID is the id of the claimable notification of a deposit, the same when the deposit is synced again.
+func claimableID(deposit *etherman.Deposit) string {
+	return fmt.Sprintf("%s:%d:%d", EventClaimable, deposit.NetworkID, deposit.DepositCount)
+}
+
+// completedID is the id of the completed notification of a claim, the same when the claim is synced again.
+func completedID(claim *etherman.Claim) string {
+	return fmt.Sprintf("%s:%d:%d", EventCompleted, claim.NetworkID, claim.Index)
+}
+
+func claimableNotification(deposit *etherman.Deposit) Notification {
+	return Notification{
+		Title: "Transfer ready to claim",
+		Body:  fmt.Sprintf("Your transfer from network %d to network %d is ready to be claimed", deposit.NetworkID, deposit.DestinationNetwork),
+		Data: map[string]string{
+			"event":       EventClaimable,
+			"network_id":  fmt.Sprint(deposit.NetworkID),
+			"deposit_cnt": fmt.Sprint(deposit.DepositCount),
+			"dest_net":    fmt.Sprint(deposit.DestinationNetwork),
+			"dest_addr":   deposit.DestinationAddress.Hex(),
+			"orig_net":    fmt.Sprint(deposit.OriginalNetwork),
+			"orig_addr":   deposit.OriginalAddress.Hex(),
+			"amount":      deposit.Amount.String(),
+			"tx_hash":     deposit.TxHash.Hex(),
+		},
+	}
+}
+
+func completedNotification(claim *etherman.Claim) Notification {
+	return Notification{
+		Title: "Transfer completed",
+		Body:  fmt.Sprintf("Your transfer to network %d has been claimed", claim.NetworkID),
+		Data: map[string]string{
+			"event":      EventCompleted,
+			"network_id": fmt.Sprint(claim.NetworkID),
+			"index":      fmt.Sprint(claim.Index),
+			"dest_addr":  claim.DestinationAddress.Hex(),
+			"orig_net":   fmt.Sprint(claim.OriginalNetwork),
+			"orig_addr":  claim.OriginalAddress.Hex(),
+			"amount":     claim.Amount.String(),
+			"tx_hash":    claim.TxHash.Hex(),
+		},
+	}
+}
+
+// publish queues the notification of the address, unless the notification with the same id was already queued,
+// e.g. by the sync of the same blocks before a resync. The hooks must not block the sync and claim loops, so unless
+// the policy is block the notifications are dropped when the queue is full.
+func (n *Notifier) publish(ctx context.Context, address common.Address, id string, notification Notification) {
+	if found, _ := n.published.ContainsOrAdd(id, struct{}{}); found {
+		log.Debugf("push: the %s notification of %s was already published", id, address.Hex())
+		return
+	}
+	if !n.queue.Push(ctx, message{address: address, notification: notification}) {
+		n.published.Remove(id)
+		log.Warnf("push: queue full, dropping the %s notification of %s", notification.Data["event"], address.Hex())
+	}
+}
+
+// send sends the notification to the devices of the address. The push services retry the delivery to the
+// devices themselves, so the failed requests are not retried.
+func (n *Notifier) send(ctx context.Context, m message) {
+	devices, err := n.storage.GetPushDevices(ctx, m.address, nil)
+	if err != nil {
+		log.Errorf("push: error getting the devices of %s: %v", m.address.Hex(), err)
+		return
+	}
+	for _, device := range devices {
+		sender, ok := n.senders[device.Platform]
+		if !ok {
+			continue
+		}
+		err := sender.Send(ctx, device.Token, m.notification)
+		if errors.Is(err, ErrUnregistered) {
+			log.Debugf("push: deleting the unregistered %s device of %s", device.Platform, device.Address.Hex())
+			if err := n.storage.DeletePushDevice(ctx, device.Address, device.Platform, device.Token, nil); err != nil {
+				log.Errorf("push: error deleting the %s device of %s: %v", device.Platform, device.Address.Hex(), err)
+			}
+		} else if err != nil {
+			log.Warnf("push: error sending the %s notification to a %s device of %s: %v", m.notification.Data["event"], device.Platform, device.Address.Hex(), err)
+		}
+	}
+}
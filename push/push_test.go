@@ -0,0 +1,290 @@
+package push
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type storage struct {
+	mu      sync.Mutex
+	devices []etherman.PushDevice
+}
+
+func (s *storage) GetPushDevices(_ context.Context, address common.Address, _ pgx.Tx) ([]etherman.PushDevice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var devices []etherman.PushDevice
+	for _, d := range s.devices {
+		if d.Address == address {
+			devices = append(devices, d)
+		}
+	}
+	return devices, nil
+}
+
+func (s *storage) DeletePushDevice(_ context.Context, address common.Address, platform, token string, _ pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, d := range s.devices {
+		if d.Address == address && d.Platform == platform && d.Token == token {
+			s.devices = append(s.devices[:i], s.devices[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *storage) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.devices)
+}
+
+type sent struct {
+	token        string
+	notification Notification
+}
+
+type sender struct {
+	mu           sync.Mutex
+	sent         []sent
+	unregistered map[string]bool
+}
+
+func (s *sender) Send(_ context.Context, token string, n Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.unregistered[token] {
+		return ErrUnregistered
+	}
+	s.sent = append(s.sent, sent{token: token, notification: n})
+	return nil
+}
+
+func (s *sender) getSent() []sent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sent(nil), s.sent...)
+}
+
+func TestNotifier(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	alice, bob := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	s := &storage{devices: []etherman.PushDevice{
+		{Address: alice, Platform: PlatformFCM, Token: "alice-android"},
+		{Address: alice, Platform: PlatformAPNs, Token: "alice-iphone"},
+		{Address: bob, Platform: PlatformFCM, Token: "bob-old-android"},
+	}}
+	fcm := &sender{unregistered: map[string]bool{"bob-old-android": true}}
+	apns := &sender{}
+	n, err := newNotifier(Config{Workers: 1, QueueSize: 10}, s, map[string]Sender{PlatformFCM: fcm, PlatformAPNs: apns})
+	require.NoError(t, err)
+	go n.Start(ctx)
+
+	require.Equal(t, []string{PlatformFCM, PlatformAPNs}, n.Platforms())
+	n.OnL2DepositReady(ctx, &etherman.Deposit{NetworkID: 1, DepositCount: 7, DestinationNetwork: 0, DestinationAddress: alice, Amount: big.NewInt(100)})
+	require.Eventually(t, func() bool { return len(fcm.getSent()) == 1 && len(apns.getSent()) == 1 }, 5*time.Second, time.Millisecond)
+	claimable := fcm.getSent()[0]
+	require.Equal(t, "alice-android", claimable.token)
+	require.Equal(t, EventClaimable, claimable.notification.Data["event"])
+	require.Equal(t, "7", claimable.notification.Data["deposit_cnt"])
+	require.Equal(t, "100", claimable.notification.Data["amount"])
+	require.Equal(t, "alice-iphone", apns.getSent()[0].token)
+
+	n.OnClaimIndexed(ctx, &etherman.Claim{NetworkID: 0, Index: 7, DestinationAddress: alice, Amount: big.NewInt(100)})
+	require.Eventually(t, func() bool { return len(fcm.getSent()) == 2 }, 5*time.Second, time.Millisecond)
+	require.Equal(t, EventCompleted, fcm.getSent()[1].notification.Data["event"])
+
+	// The events synced again by a resync are not notified twice, checked once the next notification is sent
+	n.OnL2DepositReady(ctx, &etherman.Deposit{NetworkID: 1, DepositCount: 7, DestinationNetwork: 0, DestinationAddress: alice, Amount: big.NewInt(100)})
+	n.OnClaimIndexed(ctx, &etherman.Claim{NetworkID: 0, Index: 7, DestinationAddress: alice, Amount: big.NewInt(100)})
+
+	// The unregistered devices are deleted
+	n.OnDepositReady(ctx, &etherman.Deposit{DestinationNetwork: 1, DestinationAddress: bob, Amount: big.NewInt(1)})
+	require.Eventually(t, func() bool { return s.count() == 2 }, 5*time.Second, time.Millisecond)
+	require.Len(t, fcm.getSent(), 2)
+	require.Len(t, apns.getSent(), 2)
+
+	// The notifications are dropped when the queue is full
+	full, err := newNotifier(Config{QueueSize: 1}, s, nil)
+	require.NoError(t, err)
+	full.OnDepositReady(ctx, &etherman.Deposit{DepositCount: 1, DestinationAddress: alice, Amount: big.NewInt(1)})
+	full.OnDepositReady(ctx, &etherman.Deposit{DepositCount: 2, DestinationAddress: alice, Amount: big.NewInt(1)})
+	require.Equal(t, 1, full.queue.Stats().Depth)
+	require.Equal(t, uint64(1), full.queue.Stats().Dropped)
+	// The dropped notifications are published again by the next sync
+	require.False(t, full.published.Contains(claimableID(&etherman.Deposit{DepositCount: 2})))
+
+	// The notifications are not stored
+	_, err = newNotifier(Config{QueueSize: 1, QueuePolicy: queue.PolicySpill}, s, nil)
+	require.Error(t, err)
+}
+
+// jwtParts returns the decoded claims of the JWT and its signed input and signature.
+func jwtParts(t *testing.T, jwt string) (map[string]interface{}, []byte, []byte) {
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+	c, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]interface{}
+	require.NoError(t, json.Unmarshal(c, &claims))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	return claims, []byte(parts[0] + "." + parts[1]), signature
+}
+
+func TestFCMSender(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	var tokenRequests int
+	var tokenURI string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.Form.Get("grant_type"))
+		claims, signed, signature := jwtParts(t, r.Form.Get("assertion"))
+		digest := sha256.Sum256(signed)
+		require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+		require.Equal(t, "bridge@project.iam.gserviceaccount.com", claims["iss"])
+		require.Equal(t, tokenURI, claims["aud"])
+		require.Equal(t, fcmScope, claims["scope"])
+		_, _ = w.Write([]byte(`{"access_token":"access","expires_in":3600,"token_type":"Bearer"}`))
+	})
+	mux.HandleFunc("/v1/projects/project/messages:send", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+		var msg struct {
+			Message struct {
+				Token        string            `json:"token"`
+				Notification map[string]string `json:"notification"`
+				Data         map[string]string `json:"data"`
+			} `json:"message"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		require.Equal(t, "Transfer completed", msg.Message.Notification["title"])
+		require.Equal(t, EventCompleted, msg.Message.Data["event"])
+		if msg.Message.Token == "gone" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"projects/project/messages/1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	tokenURI = server.URL + "/token"
+
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "project",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email": "bridge@project.iam.gserviceaccount.com",
+		"token_uri":    tokenURI,
+	})
+	require.NoError(t, err)
+	s, err := NewFCMSender(credentials, server.Client())
+	require.NoError(t, err)
+	require.Equal(t, "https://fcm.googleapis.com/v1/projects/project/messages:send", s.endpoint)
+	s.endpoint = server.URL + "/v1/projects/project/messages:send"
+
+	ctx := context.Background()
+	n := Notification{Title: "Transfer completed", Data: map[string]string{"event": EventCompleted}}
+	require.NoError(t, s.Send(ctx, "device", n))
+	require.ErrorIs(t, s.Send(ctx, "gone", n), ErrUnregistered)
+	// The access token is cached until it expires
+	require.Equal(t, 1, tokenRequests)
+	s.now = func() time.Time { return time.Now().Add(time.Hour) }
+	require.NoError(t, s.Send(ctx, "device", n))
+	require.Equal(t, 2, tokenRequests)
+
+	_, err = NewFCMSender([]byte(`{"project_id":"project"}`), server.Client())
+	require.Error(t, err)
+}
+
+func TestAPNsSender(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	p8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	var tokens []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "com.example.wallet", r.Header.Get("apns-topic"))
+		require.Equal(t, "alert", r.Header.Get("apns-push-type"))
+		token := strings.TrimPrefix(r.Header.Get("authorization"), "bearer ")
+		claims, signed, signature := jwtParts(t, token)
+		require.Equal(t, "TEAM", claims["iss"])
+		require.Len(t, signature, 2*es256KeySize)
+		digest := sha256.Sum256(signed)
+		r1, s1 := new(big.Int).SetBytes(signature[:es256KeySize]), new(big.Int).SetBytes(signature[es256KeySize:])
+		require.True(t, ecdsa.Verify(&key.PublicKey, digest[:], r1, s1))
+		tokens = append(tokens, token)
+
+		var payload map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		require.Equal(t, EventClaimable, payload["event"])
+		require.Equal(t, map[string]interface{}{"alert": map[string]interface{}{"title": "Transfer ready to claim", "body": "ready"}}, payload["aps"])
+		switch r.URL.Path {
+		case "/3/device/gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+		case "/3/device/bad":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+		case "/3/device/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"reason":"TooManyRequests"}`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := APNsConfig{KeyID: "KEY", TeamID: "TEAM", Topic: "com.example.wallet", Sandbox: true}
+	s, err := NewAPNsSender(cfg, p8, server.Client())
+	require.NoError(t, err)
+	require.Equal(t, apnsSandboxHost, s.host)
+	s.host = server.URL
+
+	ctx := context.Background()
+	n := Notification{Title: "Transfer ready to claim", Body: "ready", Data: map[string]string{"event": EventClaimable}}
+	require.NoError(t, s.Send(ctx, "device", n))
+	require.ErrorIs(t, s.Send(ctx, "gone", n), ErrUnregistered)
+	require.ErrorIs(t, s.Send(ctx, "bad", n), ErrUnregistered)
+	err = s.Send(ctx, "busy", n)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrUnregistered)
+	// The provider token is reused until it's renewed
+	require.Equal(t, tokens[0], tokens[3])
+	s.now = func() time.Time { return time.Now().Add(apnsTokenRefresh) }
+	require.NoError(t, s.Send(ctx, "device", n))
+	require.NotEqual(t, tokens[0], tokens[4])
+
+	_, err = NewAPNsSender(APNsConfig{KeyID: "KEY"}, p8, server.Client())
+	require.Error(t, err)
+}
@@ -0,0 +1,8 @@
+//go:build !readonly
+// +build !readonly
+
+package zkevmbridgeservice
+
+// ReadOnly is true in the read-only builds, without the claim tx manager, the canary deposits and the reserve
+// attestations, so the binary never sends txs nor loads keys.
+const ReadOnly = false
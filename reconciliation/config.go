@@ -0,0 +1,33 @@
+package reconciliation
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the reconciliation of the claimed amounts with the deposited amounts
+type Config struct {
+	// Enabled runs the reconciliation periodically, alerting on the tokens claimed beyond their deposits
+	Enabled bool `mapstructure:"Enabled"`
+	// Interval is the time between two reconciliations
+	Interval types.Duration `mapstructure:"Interval"`
+	// AlertURL receives the violations as a JSON POST, empty to only log them
+	AlertURL string `mapstructure:"AlertURL"`
+	// Timeout is the max time of a POST of the violations
+	Timeout types.Duration `mapstructure:"Timeout"`
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if c.Interval.Duration <= 0 {
+		return fmt.Errorf("the interval of the reconciliation must be positive")
+	}
+	if c.AlertURL != "" {
+		if _, err := url.ParseRequestURI(c.AlertURL); err != nil {
+			return fmt.Errorf("invalid alert url of the reconciliation: %w", err)
+		}
+	}
+	return nil
+}
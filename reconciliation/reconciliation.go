@@ -0,0 +1,214 @@
+// Package reconciliation checks the invariant of the bridge that the amount of a token claimed in a network never
+// exceeds the amount deposited to it, and alerts on the violations, which are caused by a bug of the service or of the
+// contracts, or by an exploit.
+package reconciliation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	violationsDesc = prometheus.NewDesc("bridge_reconciliation_violations",
+		"Number of tokens claimed in the network beyond the amount deposited to it", []string{"network_id"}, nil)
+	checkedDesc = prometheus.NewDesc("bridge_reconciliation_checked_timestamp_seconds",
+		"Unix time of the last reconciliation", nil, nil)
+)
+
+type storageInterface interface {
+	GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error)
+	GetTokenFlows(ctx context.Context, claimedUntil time.Time, dbTx pgx.Tx) ([]*etherman.TokenFlow, error)
+}
+
+// Violation is a token claimed in a network beyond the amount deposited to it.
+type Violation struct {
+	NetworkID       uint           `json:"network_id"`
+	OriginalNetwork uint           `json:"orig_net"`
+	OriginalAddress common.Address `json:"orig_addr"`
+	Deposited       string         `json:"deposited"`
+	Claimed         string         `json:"claimed"`
+	// Excess is the amount claimed beyond the deposited amount
+	Excess string `json:"excess"`
+	// Since is the time of the first reconciliation that found the violation
+	Since time.Time `json:"since"`
+}
+
+type tokenKey struct {
+	networkID uint
+	origNet   uint
+	origAddr  common.Address
+}
+
+// Reconciler compares the claimed amounts with the deposited amounts periodically. It implements
+// prometheus.Collector.
+type Reconciler struct {
+	cfg      Config
+	storage  storageInterface
+	networks []uint
+	client   *http.Client
+	now      func() time.Time
+
+	mu         sync.Mutex
+	violations []Violation
+	since      map[tokenKey]time.Time
+	checkedAt  time.Time
+}
+
+// NewReconciler creates a new reconciler of the amounts of the synced networks.
+func NewReconciler(cfg Config, storage interface{}, networks []uint) (*Reconciler, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Reconciler{
+		cfg:      cfg,
+		storage:  storage.(storageInterface),
+		networks: networks,
+		client:   &http.Client{Timeout: cfg.Timeout.Duration},
+		now:      time.Now,
+		since:    make(map[tokenKey]time.Time),
+	}, nil
+}
+
+// Start reconciles the amounts every interval until the context is done.
+func (r *Reconciler) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		if err := r.run(ctx); err != nil {
+			log.Warnf("reconciliation error: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Reconciler) run(ctx context.Context) error {
+	violations, err := r.Check(ctx)
+	if err != nil {
+		return err
+	}
+	for _, v := range violations {
+		log.Errorw("claimed amount exceeds the deposited amount", "networkID", v.NetworkID, "origNet", v.OriginalNetwork,
+			"origAddr", v.OriginalAddress.Hex(), "deposited", v.Deposited, "claimed", v.Claimed, "excess", v.Excess, "since", v.Since)
+	}
+	if r.cfg.AlertURL == "" || len(violations) == 0 {
+		return nil
+	}
+	return r.post(ctx, violations)
+}
+
+// Check returns the tokens claimed beyond their deposits, the metrics are updated with them. The claims are only
+// counted until the last synced block of the network behind the others, since the deposits of the networks not
+// synced yet until the time of a claim are not known.
+func (r *Reconciler) Check(ctx context.Context) ([]Violation, error) {
+	var claimedUntil time.Time
+	for _, networkID := range r.networks {
+		block, err := r.storage.GetLastBlock(ctx, networkID, nil)
+		if errors.Is(err, gerror.ErrStorageNotFound) {
+			// Nothing is checked until every network is synced
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("error getting the last block of the network %d: %w", networkID, err)
+		}
+		if claimedUntil.IsZero() || block.ReceivedAt.Before(claimedUntil) {
+			claimedUntil = block.ReceivedAt
+		}
+	}
+	flows, err := r.storage.GetTokenFlows(ctx, claimedUntil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the amounts of the tokens: %w", err)
+	}
+	now := r.now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var violations []Violation
+	since := make(map[tokenKey]time.Time)
+	for _, flow := range flows {
+		if flow.Claimed.Cmp(flow.Deposited) <= 0 {
+			continue
+		}
+		key := tokenKey{networkID: flow.NetworkID, origNet: flow.OriginalNetwork, origAddr: flow.OriginalAddress}
+		first, found := r.since[key]
+		if !found {
+			first = now
+		}
+		since[key] = first
+		violations = append(violations, Violation{
+			NetworkID:       flow.NetworkID,
+			OriginalNetwork: flow.OriginalNetwork,
+			OriginalAddress: flow.OriginalAddress,
+			Deposited:       flow.Deposited.String(),
+			Claimed:         flow.Claimed.String(),
+			Excess:          new(big.Int).Sub(flow.Claimed, flow.Deposited).String(),
+			Since:           first,
+		})
+	}
+	r.violations, r.since, r.checkedAt = violations, since, now
+	return violations, nil
+}
+
+func (r *Reconciler) post(ctx context.Context, violations []Violation) error {
+	body, err := json.Marshal(struct {
+		Violations []Violation `json:"violations"`
+	}{Violations: violations})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.AlertURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting the violations: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("error posting the violations: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (r *Reconciler) Describe(ch chan<- *prometheus.Desc) {
+	ch <- violationsDesc
+	ch <- checkedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (r *Reconciler) Collect(ch chan<- prometheus.Metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[uint]int, len(r.networks))
+	for _, networkID := range r.networks {
+		counts[networkID] = 0
+	}
+	for _, v := range r.violations {
+		counts[v.NetworkID]++
+	}
+	for networkID, count := range counts {
+		ch <- prometheus.MustNewConstMetric(violationsDesc, prometheus.GaugeValue, float64(count), strconv.FormatUint(uint64(networkID), 10))
+	}
+	if !r.checkedAt.IsZero() {
+		ch <- prometheus.MustNewConstMetric(checkedDesc, prometheus.GaugeValue, float64(r.checkedAt.Unix()))
+	}
+}
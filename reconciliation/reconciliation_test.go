@@ -0,0 +1,113 @@
+package reconciliation
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// flowStorage returns the flows of the tokens, recording the time the claims are counted until.
+type flowStorage struct {
+	lastBlocks   map[uint]time.Time
+	flows        []*etherman.TokenFlow
+	claimedUntil time.Time
+}
+
+func (s *flowStorage) GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
+	receivedAt, found := s.lastBlocks[networkID]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &etherman.Block{NetworkID: networkID, ReceivedAt: receivedAt}, nil
+}
+
+func (s *flowStorage) GetTokenFlows(ctx context.Context, claimedUntil time.Time, dbTx pgx.Tx) ([]*etherman.TokenFlow, error) {
+	s.claimedUntil = claimedUntil
+	return s.flows, nil
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	dai := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	storage := &flowStorage{lastBlocks: map[uint]time.Time{0: now.Add(-time.Minute)}}
+	r, err := NewReconciler(Config{Interval: types.NewDuration(time.Minute)}, storage, []uint{0, 1})
+	require.NoError(t, err)
+	r.now = func() time.Time { return now }
+
+	// Nothing is checked until every network is synced
+	violations, err := r.Check(ctx)
+	require.NoError(t, err)
+	require.Empty(t, violations)
+	require.True(t, storage.claimedUntil.IsZero())
+
+	// The claims are counted until the network behind the others
+	storage.lastBlocks[1] = now.Add(-time.Hour)
+	storage.flows = []*etherman.TokenFlow{
+		{NetworkID: 1, OriginalNetwork: 0, OriginalAddress: dai, Deposited: big.NewInt(100), Claimed: big.NewInt(100)},
+		{NetworkID: 0, OriginalNetwork: 0, OriginalAddress: dai, Deposited: big.NewInt(50), Claimed: big.NewInt(80)},
+	}
+	violations, err = r.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-time.Hour), storage.claimedUntil)
+	require.Equal(t, []Violation{{NetworkID: 0, OriginalNetwork: 0, OriginalAddress: dai, Deposited: "50", Claimed: "80", Excess: "30", Since: now}}, violations)
+	require.Equal(t, 2, testutil.CollectAndCount(r, "bridge_reconciliation_violations"))
+
+	// A violation keeps the time it was found first, until it's resolved
+	r.now = func() time.Time { return now.Add(time.Minute) }
+	violations, err = r.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, now, violations[0].Since)
+	storage.flows[1].Deposited = big.NewInt(80)
+	violations, err = r.Check(ctx)
+	require.NoError(t, err)
+	require.Empty(t, violations)
+	storage.flows[1].Deposited = big.NewInt(50)
+	violations, err = r.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute), violations[0].Since)
+}
+
+func TestAlert(t *testing.T) {
+	var posted []Violation
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Violations []Violation `json:"violations"`
+		}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		posted = append(posted, body.Violations...)
+	}))
+	defer srv.Close()
+	storage := &flowStorage{
+		lastBlocks: map[uint]time.Time{0: time.Now()},
+		flows:      []*etherman.TokenFlow{{NetworkID: 0, Deposited: big.NewInt(0), Claimed: big.NewInt(1)}},
+	}
+	r, err := NewReconciler(Config{Interval: types.NewDuration(time.Minute), AlertURL: srv.URL, Timeout: types.NewDuration(time.Second)}, storage, []uint{0})
+	require.NoError(t, err)
+	require.NoError(t, r.run(context.Background()))
+	require.Len(t, posted, 1)
+	require.Equal(t, "1", posted[0].Excess)
+
+	// Nothing is posted without violations
+	storage.flows[0].Deposited = big.NewInt(1)
+	require.NoError(t, r.run(context.Background()))
+	require.Len(t, posted, 1)
+}
+
+func TestConfig(t *testing.T) {
+	require.Error(t, Config{}.Validate())
+	require.Error(t, Config{Interval: types.NewDuration(time.Minute), AlertURL: "alerts"}.Validate())
+	require.NoError(t, Config{Interval: types.NewDuration(time.Minute), AlertURL: "https://alerts.example.com"}.Validate())
+}
@@ -0,0 +1,49 @@
+package replication
+
+import (
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the health checks of the read replicas of the synchronizer database
+type Config struct {
+	// Enabled checks the replicas periodically and serves their health in /replication
+	Enabled bool `mapstructure:"Enabled"`
+	// Interval is the time between two checks of the replicas
+	Interval types.Duration `mapstructure:"Interval"`
+	// MaxLagBytes is the max size of the WAL of the primary not replayed yet by a healthy replica
+	MaxLagBytes uint64 `mapstructure:"MaxLagBytes"`
+	// MaxReplayDelay is the max time since the last replayed transaction of a healthy replica that is behind
+	MaxReplayDelay types.Duration `mapstructure:"MaxReplayDelay"`
+	// Replicas are the read replicas checked, besides the database of the API when it's not the synchronizer one
+	Replicas []ReplicaConfig `mapstructure:"Replicas"`
+}
+
+// ReplicaConfig is a read replica of the synchronizer database.
+type ReplicaConfig struct {
+	// Name identifies the replica in the API and the metrics
+	Name string `mapstructure:"Name"`
+	// Region is the location of the replica, e.g. the datacenter serving its traffic
+	Region string    `mapstructure:"Region"`
+	DB     db.Config `mapstructure:"DB"`
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if c.Interval.Duration <= 0 {
+		return fmt.Errorf("the interval of the replication checks must be positive")
+	}
+	names := make(map[string]bool)
+	for i, replica := range c.Replicas {
+		if replica.Name == "" {
+			return fmt.Errorf("replica %d has no name", i)
+		}
+		if names[replica.Name] || replica.Name == APIReplica {
+			return fmt.Errorf("duplicated replica %s", replica.Name)
+		}
+		names[replica.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// Package replication checks the read replicas of the synchronizer database: the WAL of the primary they haven't
+// replayed yet, and the synced blocks they have with a different hash than the primary, e.g. a replica left attached
+// to an old primary after a failover. The load balancers route the traffic away from the regions of the unhealthy
+// replicas with their status in the API.
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// APIReplica is the name of the database of the API, checked when it's not the synchronizer one
+const APIReplica = "api"
+
+var (
+	lagDesc = prometheus.NewDesc("bridge_replica_lag_bytes",
+		"Size of the WAL of the primary not replayed yet by the replica", []string{"replica", "region"}, nil)
+	delayDesc = prometheus.NewDesc("bridge_replica_replay_delay_seconds",
+		"Time since the last transaction replayed by the replica, 0 when it's up to date", []string{"replica", "region"}, nil)
+	healthyDesc = prometheus.NewDesc("bridge_replica_healthy",
+		"1 if the replica is not diverged and its lag is under the max", []string{"replica", "region"}, nil)
+)
+
+type primaryStorage interface {
+	GetCurrentLSN(ctx context.Context, dbTx pgx.Tx) (string, error)
+	GetBlockUntil(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.Block, error)
+}
+
+type replicaStorage interface {
+	GetReplayStatus(ctx context.Context, lsn string, dbTx pgx.Tx) (*etherman.ReplayStatus, error)
+	GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error)
+}
+
+// Replica is a read replica to check, with its storage.
+type Replica struct {
+	Name    string
+	Region  string
+	Storage interface{}
+}
+
+// Status is the health of a replica at its last check.
+type Status struct {
+	Name      string
+	Region    string
+	CheckedAt time.Time
+	LagBytes  uint64
+	// ReplayDelay is the time since the last replayed transaction, 0 when the replica is up to date
+	ReplayDelay time.Duration
+	// Divergence describes the synced block the replica has with a different hash than the primary, empty if the
+	// replica isn't diverged
+	Divergence string
+	Healthy    bool
+	Error      string
+}
+
+type replica struct {
+	name    string
+	region  string
+	storage replicaStorage
+}
+
+// Monitor checks the replicas periodically. It implements prometheus.Collector.
+type Monitor struct {
+	cfg      Config
+	primary  primaryStorage
+	replicas []replica
+	networks []uint
+
+	mu       sync.Mutex
+	statuses map[string]Status
+	// suspects are the divergences found in the last check of each replica. A replica behind the primary may not
+	// have replayed a reorg yet, so a divergence is only reported if it's found again in the next check
+	suspects map[string]string
+}
+
+// NewMonitor creates the monitor of the replicas of the primary, comparing the synced blocks of the networks.
+func NewMonitor(cfg Config, primary interface{}, replicas []Replica, networks []uint) *Monitor {
+	m := &Monitor{
+		cfg:      cfg,
+		primary:  primary.(primaryStorage),
+		networks: networks,
+		statuses: make(map[string]Status),
+		suspects: make(map[string]string),
+	}
+	for _, r := range replicas {
+		m.replicas = append(m.replicas, replica{name: r.Name, region: r.Region, storage: r.Storage.(replicaStorage)})
+		m.statuses[r.Name] = Status{Name: r.Name, Region: r.Region, Error: "not checked yet"}
+	}
+	return m
+}
+
+// Start checks the replicas until the context is done.
+func (m *Monitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		m.Check(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check checks all the replicas against the current position of the primary.
+func (m *Monitor) Check(ctx context.Context, now time.Time) {
+	lsn, err := m.primary.GetCurrentLSN(ctx, nil)
+	if err != nil {
+		log.Errorf("error getting the WAL position of the primary database: %v", err)
+		return
+	}
+	for _, r := range m.replicas {
+		status := m.check(ctx, r, lsn, now)
+		if !status.Healthy {
+			log.Warnf("replica %s is unhealthy: lag %d bytes, replay delay %s, divergence %q, error %q",
+				r.name, status.LagBytes, status.ReplayDelay, status.Divergence, status.Error)
+		}
+		m.mu.Lock()
+		m.statuses[r.name] = status
+		m.mu.Unlock()
+	}
+}
+
+func (m *Monitor) check(ctx context.Context, r replica, lsn string, now time.Time) Status {
+	status := Status{Name: r.name, Region: r.region, CheckedAt: now}
+	replay, err := r.storage.GetReplayStatus(ctx, lsn, nil)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.LagBytes = replay.LagBytes
+	if replay.LagBytes > 0 && !replay.LastReplayAt.IsZero() && now.After(replay.LastReplayAt) {
+		status.ReplayDelay = now.Sub(replay.LastReplayAt)
+	}
+	divergence, err := m.divergence(ctx, r)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	m.mu.Lock()
+	if divergence != "" && m.suspects[r.name] == divergence {
+		status.Divergence = divergence
+	}
+	m.suspects[r.name] = divergence
+	m.mu.Unlock()
+	status.Healthy = status.Divergence == "" && status.LagBytes <= m.cfg.MaxLagBytes &&
+		(m.cfg.MaxReplayDelay.Duration <= 0 || status.ReplayDelay <= m.cfg.MaxReplayDelay.Duration)
+	return status
+}
+
+// divergence compares the last synced block of each network in the replica with the block of the primary at its
+// height. The primary is read after the replica, so a replica behind finds its last block unless it was reorged.
+func (m *Monitor) divergence(ctx context.Context, r replica) (string, error) {
+	for _, networkID := range m.networks {
+		block, err := r.storage.GetLastBlock(ctx, networkID, nil)
+		if errors.Is(err, gerror.ErrStorageNotFound) {
+			continue
+		} else if err != nil {
+			return "", fmt.Errorf("error getting the last block of the network %d in the replica: %w", networkID, err)
+		}
+		primaryBlock, err := m.primary.GetBlockUntil(ctx, networkID, block.BlockNumber, nil)
+		if errors.Is(err, gerror.ErrStorageNotFound) {
+			return fmt.Sprintf("network %d: the primary has no block until %d", networkID, block.BlockNumber), nil
+		} else if err != nil {
+			return "", fmt.Errorf("error getting the block %d of the network %d in the primary: %w", block.BlockNumber, networkID, err)
+		}
+		if primaryBlock.BlockNumber != block.BlockNumber || primaryBlock.BlockHash != block.BlockHash {
+			return fmt.Sprintf("network %d: block %d %s is not in the primary", networkID, block.BlockNumber, block.BlockHash.String()), nil
+		}
+	}
+	return "", nil
+}
+
+// Statuses returns the status of the replicas sorted by name. It returns nil if the monitor is nil.
+func (m *Monitor) Statuses() []Status {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	statuses := make([]Status, 0, len(m.statuses))
+	for _, status := range m.statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Describe implements prometheus.Collector.
+func (m *Monitor) Describe(ch chan<- *prometheus.Desc) {
+	ch <- lagDesc
+	ch <- delayDesc
+	ch <- healthyDesc
+}
+
+// Collect implements prometheus.Collector.
+func (m *Monitor) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range m.Statuses() {
+		healthy := 0.0
+		if s.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(lagDesc, prometheus.GaugeValue, float64(s.LagBytes), s.Name, s.Region)
+		ch <- prometheus.MustNewConstMetric(delayDesc, prometheus.GaugeValue, s.ReplayDelay.Seconds(), s.Name, s.Region)
+		ch <- prometheus.MustNewConstMetric(healthyDesc, prometheus.GaugeValue, healthy, s.Name, s.Region)
+	}
+}
@@ -0,0 +1,108 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// blockStorage has the synced blocks of the network 0, by number.
+type blockStorage struct {
+	blocks map[uint64]common.Hash
+	replay etherman.ReplayStatus
+	err    error
+}
+
+func (s *blockStorage) GetCurrentLSN(ctx context.Context, dbTx pgx.Tx) (string, error) {
+	return "0/1000", nil
+}
+
+func (s *blockStorage) GetBlockUntil(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.Block, error) {
+	for n := blockNum; ; n-- {
+		if hash, found := s.blocks[n]; found {
+			return &etherman.Block{BlockNumber: n, BlockHash: hash}, nil
+		}
+		if n == 0 {
+			return nil, gerror.ErrStorageNotFound
+		}
+	}
+}
+
+func (s *blockStorage) GetReplayStatus(ctx context.Context, lsn string, dbTx pgx.Tx) (*etherman.ReplayStatus, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &s.replay, nil
+}
+
+func (s *blockStorage) GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
+	var last uint64
+	for n := range s.blocks {
+		if n > last {
+			last = n
+		}
+	}
+	return &etherman.Block{BlockNumber: last, BlockHash: s.blocks[last]}, nil
+}
+
+func TestMonitor(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	primary := &blockStorage{blocks: map[uint64]common.Hash{1: common.HexToHash("0x1"), 2: common.HexToHash("0x2"), 3: common.HexToHash("0x3")}}
+	behind := &blockStorage{
+		blocks: map[uint64]common.Hash{1: common.HexToHash("0x1"), 2: common.HexToHash("0x2")},
+		replay: etherman.ReplayStatus{InRecovery: true, LagBytes: 100, LastReplayAt: now.Add(-time.Minute)},
+	}
+	forked := &blockStorage{
+		blocks: map[uint64]common.Hash{1: common.HexToHash("0x1"), 2: common.HexToHash("0xf")},
+		replay: etherman.ReplayStatus{InRecovery: true},
+	}
+	down := &blockStorage{err: errors.New("connection refused")}
+	cfg := Config{Interval: types.NewDuration(time.Second), MaxLagBytes: 1000, MaxReplayDelay: types.NewDuration(time.Hour)}
+	m := NewMonitor(cfg, primary, []Replica{
+		{Name: "eu", Region: "eu-west", Storage: behind},
+		{Name: "us", Region: "us-east", Storage: forked},
+		{Name: "asia", Storage: down},
+	}, []uint{0})
+
+	// The replicas are unhealthy until they are checked
+	for _, status := range m.Statuses() {
+		require.False(t, status.Healthy)
+	}
+
+	// A divergence is only reported if it's found in two checks, the replica may not have replayed a reorg yet
+	m.Check(ctx, now)
+	statuses := m.Statuses()
+	require.Equal(t, []string{"asia", "eu", "us"}, []string{statuses[0].Name, statuses[1].Name, statuses[2].Name})
+	require.Equal(t, "connection refused", statuses[0].Error)
+	require.False(t, statuses[0].Healthy)
+	require.Equal(t, Status{Name: "eu", Region: "eu-west", CheckedAt: now, LagBytes: 100, ReplayDelay: time.Minute, Healthy: true}, statuses[1])
+	require.True(t, statuses[2].Healthy)
+
+	m.Check(ctx, now)
+	statuses = m.Statuses()
+	require.True(t, statuses[1].Healthy)
+	require.False(t, statuses[2].Healthy)
+	require.Contains(t, statuses[2].Divergence, "block 2")
+
+	// A replica far behind the primary is unhealthy
+	behind.replay.LagBytes = 2000
+	m.Check(ctx, now)
+	require.False(t, m.Statuses()[1].Healthy)
+	behind.replay.LagBytes = 100
+	behind.replay.LastReplayAt = now.Add(-2 * time.Hour)
+	m.Check(ctx, now)
+	require.False(t, m.Statuses()[1].Healthy)
+
+	// The nil monitor has no replicas
+	var nilMonitor *Monitor
+	require.Nil(t, nilMonitor.Statuses())
+}
@@ -0,0 +1,11 @@
+package reserve
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+// Config is the configuration of the proof of reserve attestations
+type Config struct {
+	// Enabled serves the signed attestations in the admin endpoints
+	Enabled bool `mapstructure:"Enabled"`
+	// PrivateKey is the keystore of the operator key signing the attestations
+	PrivateKey types.KeystoreFileConfig `mapstructure:"PrivateKey"`
+}
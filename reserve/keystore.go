@@ -0,0 +1,24 @@
+//go:build !readonly
+// +build !readonly
+
+package reserve
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// NewAttester creates a new attester signing with the key of the keystore.
+func NewAttester(cfg Config, storage interface{}) (*Attester, error) {
+	keystoreEncrypted, err := os.ReadFile(filepath.Clean(cfg.PrivateKey.Path))
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keystoreEncrypted, cfg.PrivateKey.Password)
+	if err != nil {
+		return nil, err
+	}
+	return newAttester(storage.(storageInterface), key.PrivateKey), nil
+}
@@ -0,0 +1,332 @@
+// Package reserve builds the proof of reserve attestations of the bridge: for each token, the amount locked in
+// the bridge of its origin network against the amount minted as wrapped tokens in the other network, at a block
+// of each network. The attestation carries the deposits and claims the amounts are calculated from and is signed
+// with the operator key, so third parties can verify the solvency of the bridge from the data of the service.
+package reserve
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/uint256"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// mainnetID is the network of the ether bridged with the messages
+	mainnetID       = 0
+	leafTypeMessage = 1
+)
+
+type storageInterface interface {
+	GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error)
+	GetBlockUntil(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.Block, error)
+	GetNetworkDepositsUntilBlock(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	GetNetworkClaimsUntilBlock(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) ([]*etherman.Claim, error)
+}
+
+// Block is the block of a network the amounts are calculated at.
+type Block struct {
+	NetworkID uint        `json:"network_id"`
+	Number    uint64      `json:"number"`
+	Hash      common.Hash `json:"hash"`
+}
+
+// Deposit is a deposit of the report. The messages lock the ether sent with them.
+type Deposit struct {
+	LeafType   uint8          `json:"leaf_type"`
+	NetworkID  uint           `json:"network_id"`
+	DepositCnt uint           `json:"deposit_cnt"`
+	OrigNet    uint           `json:"orig_net"`
+	OrigAddr   common.Address `json:"orig_addr"`
+	Amount     string         `json:"amount"`
+	DestNet    uint           `json:"dest_net"`
+	BlockNum   uint64         `json:"block_num"`
+	TxHash     common.Hash    `json:"tx_hash"`
+}
+
+// Claim is a claim of the report. Index is the deposit count of the claimed deposit in the other network.
+type Claim struct {
+	NetworkID uint           `json:"network_id"`
+	Index     uint           `json:"index"`
+	OrigNet   uint           `json:"orig_net"`
+	OrigAddr  common.Address `json:"orig_addr"`
+	Amount    string         `json:"amount"`
+	BlockNum  uint64         `json:"block_num"`
+	TxHash    common.Hash    `json:"tx_hash"`
+}
+
+// TokenReserve are the amounts of a token. Pending is Locked - Minted, the amount of the deposits not claimed
+// yet, and it's negative if more tokens were minted than locked.
+type TokenReserve struct {
+	OrigNet  uint           `json:"orig_net"`
+	OrigAddr common.Address `json:"orig_addr"`
+	Locked   string         `json:"locked"`
+	Minted   string         `json:"minted"`
+	Pending  string         `json:"pending"`
+}
+
+// Report are the amounts of each token between two networks, with the deposits and claims until their blocks.
+type Report struct {
+	Blocks    [2]Block       `json:"blocks"`
+	Tokens    []TokenReserve `json:"tokens"`
+	Deposits  []Deposit      `json:"deposits"`
+	Claims    []Claim        `json:"claims"`
+	CreatedAt int64          `json:"created_at"`
+}
+
+// Attestation is the report signed by the operator. The signature is the EIP-191 personal signature of the
+// report bytes, as signed with personal_sign.
+type Attestation struct {
+	Report    json.RawMessage `json:"report"`
+	Signer    common.Address  `json:"signer"`
+	Signature hexutil.Bytes   `json:"signature"`
+}
+
+// Attester builds and signs the attestations.
+type Attester struct {
+	storage storageInterface
+	key     *ecdsa.PrivateKey
+	now     func() time.Time
+}
+
+func newAttester(storage storageInterface, key *ecdsa.PrivateKey) *Attester {
+	return &Attester{storage: storage, key: key, now: time.Now}
+}
+
+// Signer returns the address of the operator key.
+func (a *Attester) Signer() common.Address {
+	return crypto.PubkeyToAddress(a.key.PublicKey)
+}
+
+// Attest returns the signed report of the tokens bridged between L1 and the L2 network, at the last synced blocks
+// until the block numbers. A block number 0 uses the last synced block.
+func (a *Attester) Attest(ctx context.Context, l2NetworkID uint, l1BlockNum, l2BlockNum uint64) (*Attestation, error) {
+	report := &Report{CreatedAt: a.now().Unix()}
+	for i, point := range []struct {
+		networkID uint
+		blockNum  uint64
+		destNet   uint
+	}{{mainnetID, l1BlockNum, l2NetworkID}, {l2NetworkID, l2BlockNum, mainnetID}} {
+		block, err := a.getBlock(ctx, point.networkID, point.blockNum)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the block %d of the network %d: %w", point.blockNum, point.networkID, err)
+		}
+		report.Blocks[i] = Block{NetworkID: point.networkID, Number: block.BlockNumber, Hash: block.BlockHash}
+		deposits, err := a.storage.GetNetworkDepositsUntilBlock(ctx, point.networkID, block.BlockNumber, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range deposits {
+			// The L1 deposits to other rollups are not locked for this network
+			if d.DestinationNetwork != point.destNet {
+				continue
+			}
+			report.Deposits = append(report.Deposits, Deposit{
+				LeafType:   d.LeafType,
+				NetworkID:  d.NetworkID,
+				DepositCnt: d.DepositCount,
+				OrigNet:    d.OriginalNetwork,
+				OrigAddr:   d.OriginalAddress,
+				Amount:     d.Amount.String(),
+				DestNet:    d.DestinationNetwork,
+				BlockNum:   d.BlockNumber,
+				TxHash:     d.TxHash,
+			})
+		}
+		claims, err := a.storage.GetNetworkClaimsUntilBlock(ctx, point.networkID, block.BlockNumber, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range claims {
+			report.Claims = append(report.Claims, Claim{
+				NetworkID: c.NetworkID,
+				Index:     c.Index,
+				OrigNet:   c.OriginalNetwork,
+				OrigAddr:  c.OriginalAddress,
+				Amount:    c.Amount.String(),
+				BlockNum:  c.BlockNumber,
+				TxHash:    c.TxHash,
+			})
+		}
+	}
+	var err error
+	if report.Tokens, err = Aggregate(report.Blocks[0].NetworkID, report.Blocks[1].NetworkID, report.Deposits, report.Claims); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := a.sign(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Attestation{Report: data, Signer: a.Signer(), Signature: signature}, nil
+}
+
+// sign returns the personal signature of the data, with the recovery id 27 or 28 as personal_sign.
+func (a *Attester) sign(data []byte) ([]byte, error) {
+	signature, err := crypto.Sign(accounts.TextHash(data), a.key)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+func (a *Attester) getBlock(ctx context.Context, networkID uint, blockNum uint64) (*etherman.Block, error) {
+	if blockNum == 0 {
+		return a.storage.GetLastBlock(ctx, networkID, nil)
+	}
+	return a.storage.GetBlockUntil(ctx, networkID, blockNum, nil)
+}
+
+type token struct {
+	net  uint
+	addr common.Address
+}
+
+// Aggregate calculates the amounts of each token bridged between the networks from the deposits and claims. The
+// deposits in the origin network of the token lock it and the claims release it, while in the other network the
+// claims mint the wrapped token and the deposits burn it. The tokens originated in a third network are not included.
+func Aggregate(networkID1, networkID2 uint, deposits []Deposit, claims []Claim) ([]TokenReserve, error) {
+	type amounts struct {
+		locked, minted *big.Int
+	}
+	tokens := make(map[token]*amounts)
+	// add adds the amount to the locked amount of the token in its origin network, or to the minted one otherwise
+	add := func(t token, networkID uint, amount string, negative bool) error {
+		value, err := uint256.Parse(amount)
+		if err != nil {
+			return err
+		}
+		if t.net != networkID1 && t.net != networkID2 {
+			return nil
+		}
+		a, found := tokens[t]
+		if !found {
+			a = &amounts{locked: big.NewInt(0), minted: big.NewInt(0)}
+			tokens[t] = a
+		}
+		if negative {
+			value.Neg(value)
+		}
+		if networkID == t.net {
+			a.locked.Add(a.locked, value)
+		} else {
+			a.minted.Add(a.minted, value)
+		}
+		return nil
+	}
+	other := func(networkID uint) (uint, error) {
+		switch networkID {
+		case networkID1:
+			return networkID2, nil
+		case networkID2:
+			return networkID1, nil
+		}
+		return 0, fmt.Errorf("network %d not in the report", networkID)
+	}
+
+	depositsByCnt := make(map[[2]uint]Deposit, len(deposits))
+	for _, d := range deposits {
+		if _, err := other(d.NetworkID); err != nil {
+			return nil, err
+		}
+		depositsByCnt[[2]uint{d.NetworkID, d.DepositCnt}] = d
+		t := depositToken(d.LeafType, d.OrigNet, d.OrigAddr)
+		// Locked in the origin network, burnt in the other one
+		if err := add(t, d.NetworkID, d.Amount, d.NetworkID != t.net); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range claims {
+		sourceNet, err := other(c.NetworkID)
+		if err != nil {
+			return nil, err
+		}
+		// The claimed deposit tells if it's a message, whose origin is the sender instead of the token
+		t := token{net: c.OrigNet, addr: c.OrigAddr}
+		if d, found := depositsByCnt[[2]uint{sourceNet, c.Index}]; found {
+			t = depositToken(d.LeafType, c.OrigNet, c.OrigAddr)
+		}
+		// Released in the origin network, minted in the other one
+		if err := add(t, c.NetworkID, c.Amount, c.NetworkID == t.net); err != nil {
+			return nil, err
+		}
+	}
+
+	reserves := make([]TokenReserve, 0, len(tokens))
+	for t, a := range tokens {
+		reserves = append(reserves, TokenReserve{
+			OrigNet:  t.net,
+			OrigAddr: t.addr,
+			Locked:   a.locked.String(),
+			Minted:   a.minted.String(),
+			Pending:  new(big.Int).Sub(a.locked, a.minted).String(),
+		})
+	}
+	sort.Slice(reserves, func(i, j int) bool {
+		if reserves[i].OrigNet != reserves[j].OrigNet {
+			return reserves[i].OrigNet < reserves[j].OrigNet
+		}
+		return reserves[i].OrigAddr.Hex() < reserves[j].OrigAddr.Hex()
+	})
+	return reserves, nil
+}
+
+func depositToken(leafType uint8, origNet uint, origAddr common.Address) token {
+	if leafType == leafTypeMessage {
+		return token{net: mainnetID}
+	}
+	return token{net: origNet, addr: origAddr}
+}
+
+// Verify checks the signature of the attestation and that its amounts match its deposits and claims, and returns
+// its report.
+func Verify(attestation *Attestation) (*Report, error) {
+	signature := make([]byte, len(attestation.Signature))
+	copy(signature, attestation.Signature)
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	if signature[crypto.RecoveryIDOffset] >= 27 { //nolint:gomnd
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(attestation.Report), signature)
+	if err != nil {
+		return nil, err
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != attestation.Signer {
+		return nil, fmt.Errorf("report signed by %s instead of %s", signer.Hex(), attestation.Signer.Hex())
+	}
+	var report Report
+	if err := json.Unmarshal(attestation.Report, &report); err != nil {
+		return nil, err
+	}
+	tokens, err := Aggregate(report.Blocks[0].NetworkID, report.Blocks[1].NetworkID, report.Deposits, report.Claims)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) != len(report.Tokens) {
+		return nil, errors.New("the tokens of the report don't match its deposits and claims")
+	}
+	for i := range tokens {
+		if tokens[i] != report.Tokens[i] {
+			return nil, fmt.Errorf("the amounts of the token %s of the network %d don't match its deposits and claims", tokens[i].OrigAddr.Hex(), tokens[i].OrigNet)
+		}
+	}
+	return &report, nil
+}
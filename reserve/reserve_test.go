@@ -0,0 +1,148 @@
+package reserve
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type storage struct {
+	blocks   map[uint][]*etherman.Block
+	deposits []*etherman.Deposit
+	claims   []*etherman.Claim
+}
+
+func (s *storage) GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
+	return s.GetBlockUntil(ctx, networkID, 1<<62, dbTx)
+}
+
+func (s *storage) GetBlockUntil(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.Block, error) {
+	var last *etherman.Block
+	for _, b := range s.blocks[networkID] {
+		if b.BlockNumber <= blockNum {
+			last = b
+		}
+	}
+	if last == nil {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return last, nil
+}
+
+func (s *storage) GetNetworkDepositsUntilBlock(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	var deposits []*etherman.Deposit
+	for _, d := range s.deposits {
+		if d.NetworkID == networkID && d.BlockNumber <= blockNum {
+			deposits = append(deposits, d)
+		}
+	}
+	return deposits, nil
+}
+
+func (s *storage) GetNetworkClaimsUntilBlock(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) ([]*etherman.Claim, error) {
+	var claims []*etherman.Claim
+	for _, c := range s.claims {
+		if c.NetworkID == networkID && c.BlockNumber <= blockNum {
+			claims = append(claims, c)
+		}
+	}
+	return claims, nil
+}
+
+func TestAttestation(t *testing.T) {
+	ctx := context.Background()
+	token := common.HexToAddress("0x1000")
+	l2Token := common.HexToAddress("0x2000")
+	sender := common.HexToAddress("0x3000")
+	s := &storage{
+		blocks: map[uint][]*etherman.Block{
+			0: {{BlockNumber: 10, BlockHash: common.HexToHash("0x0a")}, {BlockNumber: 20, BlockHash: common.HexToHash("0x14")}},
+			1: {{BlockNumber: 5, BlockHash: common.HexToHash("0x05")}, {BlockNumber: 15, BlockHash: common.HexToHash("0x0f")}},
+		},
+		deposits: []*etherman.Deposit{
+			// L1 ether and token deposits to the L2
+			{NetworkID: 0, DepositCount: 0, OriginalNetwork: 0, Amount: big.NewInt(100), DestinationNetwork: 1, BlockNumber: 10},
+			{NetworkID: 0, DepositCount: 1, OriginalNetwork: 0, OriginalAddress: token, Amount: big.NewInt(50), DestinationNetwork: 1, BlockNumber: 10},
+			// A deposit to another rollup is not locked for this one
+			{NetworkID: 0, DepositCount: 2, OriginalNetwork: 0, Amount: big.NewInt(1000), DestinationNetwork: 2, BlockNumber: 10},
+			// A message with ether, after the first L1 block
+			{LeafType: leafTypeMessage, NetworkID: 0, DepositCount: 3, OriginalNetwork: 0, OriginalAddress: sender, Amount: big.NewInt(7), DestinationNetwork: 1, BlockNumber: 20},
+			// L2 deposits: the wrapped token is burnt, the L2 token is locked
+			{NetworkID: 1, DepositCount: 0, OriginalNetwork: 0, OriginalAddress: token, Amount: big.NewInt(20), DestinationNetwork: 0, BlockNumber: 5},
+			{NetworkID: 1, DepositCount: 1, OriginalNetwork: 1, OriginalAddress: l2Token, Amount: big.NewInt(30), DestinationNetwork: 0, BlockNumber: 15},
+		},
+		claims: []*etherman.Claim{
+			// L2 claims mint the wrapped tokens
+			{NetworkID: 1, Index: 0, OriginalNetwork: 0, Amount: big.NewInt(100), BlockNumber: 5},
+			{NetworkID: 1, Index: 1, OriginalNetwork: 0, OriginalAddress: token, Amount: big.NewInt(50), BlockNumber: 5},
+			{NetworkID: 1, Index: 3, OriginalNetwork: 0, OriginalAddress: sender, Amount: big.NewInt(7), BlockNumber: 15},
+			// L1 claims release the L1 token and mint the L2 token
+			{NetworkID: 0, Index: 0, OriginalNetwork: 0, OriginalAddress: token, Amount: big.NewInt(20), BlockNumber: 20},
+			{NetworkID: 0, Index: 1, OriginalNetwork: 1, OriginalAddress: l2Token, Amount: big.NewInt(30), BlockNumber: 20},
+		},
+	}
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	attester := newAttester(s, key)
+	attester.now = func() time.Time { return time.Unix(1000, 0) }
+
+	// The last synced blocks
+	attestation, err := attester.Attest(ctx, 1, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), attestation.Signer)
+	report, err := Verify(attestation)
+	require.NoError(t, err)
+	require.Equal(t, [2]Block{{0, 20, common.HexToHash("0x14")}, {1, 15, common.HexToHash("0x0f")}}, report.Blocks)
+	require.Equal(t, int64(1000), report.CreatedAt)
+	require.Len(t, report.Deposits, 5)
+	require.Len(t, report.Claims, 5)
+	require.Equal(t, []TokenReserve{
+		{OrigNet: 0, OrigAddr: common.Address{}, Locked: "107", Minted: "107", Pending: "0"},
+		{OrigNet: 0, OrigAddr: token, Locked: "30", Minted: "30", Pending: "0"},
+		{OrigNet: 1, OrigAddr: l2Token, Locked: "30", Minted: "30", Pending: "0"},
+	}, report.Tokens)
+
+	// The blocks before the message and the L1 claims
+	attestation, err = attester.Attest(ctx, 1, 15, 10)
+	require.NoError(t, err)
+	report, err = Verify(attestation)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), report.Blocks[0].Number)
+	require.Equal(t, uint64(5), report.Blocks[1].Number)
+	require.Equal(t, []TokenReserve{
+		{OrigNet: 0, OrigAddr: common.Address{}, Locked: "100", Minted: "100", Pending: "0"},
+		{OrigNet: 0, OrigAddr: token, Locked: "50", Minted: "30", Pending: "20"},
+	}, report.Tokens)
+
+	_, err = attester.Attest(ctx, 1, 0, 1)
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+
+	// The tampered amounts
+	report.Tokens[1].Locked = "70"
+	report.Tokens[1].Pending = "40"
+	tampered, err := json.Marshal(report)
+	require.NoError(t, err)
+	resigned, err := newAttester(s, key).sign(tampered)
+	require.NoError(t, err)
+	_, err = Verify(&Attestation{Report: tampered, Signer: attestation.Signer, Signature: resigned})
+	require.Error(t, err)
+
+	// The tampered report
+	_, err = Verify(&Attestation{Report: tampered, Signer: attestation.Signer, Signature: attestation.Signature})
+	require.Error(t, err)
+
+	// Another signer
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = Verify(&Attestation{Report: attestation.Report, Signer: crypto.PubkeyToAddress(otherKey.PublicKey), Signature: attestation.Signature})
+	require.Error(t, err)
+}
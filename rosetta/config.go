@@ -0,0 +1,28 @@
+package rosetta
+
+import (
+	"fmt"
+)
+
+// Config is the configuration of the Rosetta API
+type Config struct {
+	// Enabled serves the Rosetta Data API in the port
+	Enabled bool `mapstructure:"Enabled"`
+	// Port is the HTTP port of the Rosetta API
+	Port string `mapstructure:"Port"`
+	// Blockchain and Network are the network identifier of the bridge, the networks of the bridge are its sub
+	// networks
+	Blockchain string `mapstructure:"Blockchain"`
+	Network    string `mapstructure:"Network"`
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if c.Port == "" {
+		return fmt.Errorf("the port of the rosetta api is required")
+	}
+	if c.Blockchain == "" || c.Network == "" {
+		return fmt.Errorf("the blockchain and the network of the rosetta api are required")
+	}
+	return nil
+}
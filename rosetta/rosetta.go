@@ -0,0 +1,553 @@
+// Package rosetta serves the Rosetta Data API of the bridge, so the integrations standardized on Rosetta track the
+// deposits and the claims without custom code. Each network of the bridge is a sub network, whose blocks are its
+// synced blocks, with the deposits and the claims of their txs as operations.
+package rosetta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	rosettaVersion = "1.4.13"
+
+	// OperationDeposit is a deposit, its amount leaves the account of the depositor
+	OperationDeposit = "BRIDGE_DEPOSIT"
+	// OperationClaim is a claim, its amount is received by the account of its destination
+	OperationClaim = "BRIDGE_CLAIM"
+	// StatusSuccess is the status of the synced operations, whose logs are only emitted by the successful txs
+	StatusSuccess = "SUCCESS"
+
+	ethDecimals = 18
+	// leafTypeMessage is the leaf type of the messages, whose amount is the ether sent with them
+	leafTypeMessage = 1
+	maxRequestSize  = 1 << 16
+	// readHeaderTimeout is the max time to read the headers of a request
+	readHeaderTimeout = 5 * time.Second
+)
+
+var (
+	errNetworkNotFound     = &Error{Code: 1, Message: "network not found"}
+	errBlockNotFound       = &Error{Code: 2, Message: "block not found or not synced yet", Retriable: true}
+	errTransactionNotFound = &Error{Code: 3, Message: "transaction not found"}
+	errInvalidRequest      = &Error{Code: 4, Message: "invalid request"}
+	errNotSupported        = &Error{Code: 5, Message: "not supported by the bridge"}
+	errInternal            = &Error{Code: 6, Message: "internal error", Retriable: true}
+
+	allErrors = []*Error{errNetworkNotFound, errBlockNotFound, errTransactionNotFound, errInvalidRequest, errNotSupported, errInternal}
+)
+
+var (
+	stringType, _     = abi.NewType("string", "", nil)
+	uint8Type, _      = abi.NewType("uint8", "", nil)
+	tokenMetadataArgs = abi.Arguments{{Name: "name", Type: stringType}, {Name: "symbol", Type: stringType}, {Name: "decimals", Type: uint8Type}}
+)
+
+type storageInterface interface {
+	BeginSnapshotTransaction(ctx context.Context) (pgx.Tx, error)
+	GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error)
+	GetFirstBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error)
+	GetBlockUntil(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.Block, error)
+	GetBlockByHash(ctx context.Context, networkID uint, blockHash common.Hash, dbTx pgx.Tx) (*etherman.Block, error)
+	GetBlockDeposits(ctx context.Context, blockID uint64, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	GetBlockClaims(ctx context.Context, blockID uint64, dbTx pgx.Tx) ([]*etherman.Claim, error)
+	GetClaimedDeposit(ctx context.Context, depositCnt, destNetworkID, origNet uint, origAddr common.Address, dbTx pgx.Tx) (*etherman.Deposit, error)
+	GetTokenWrapped(ctx context.Context, originalNetwork uint, originalTokenAddress common.Address, dbTx pgx.Tx) (*etherman.TokenWrapped, error)
+}
+
+type tokenKey struct {
+	origNet  uint
+	origAddr common.Address
+}
+
+// Server serves the Rosetta Data API of the synced networks.
+type Server struct {
+	cfg      Config
+	storage  storageInterface
+	networks []uint
+	version  string
+
+	mu sync.Mutex
+	// currencies are the currencies of the tokens whose metadata is known
+	currencies map[tokenKey]Currency
+}
+
+// NewServer creates the Rosetta API of the networks, with the version of the service.
+func NewServer(cfg Config, storage interface{}, networks []uint, version string) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Server{
+		cfg:        cfg,
+		storage:    storage.(storageInterface),
+		networks:   networks,
+		version:    version,
+		currencies: make(map[tokenKey]Currency),
+	}, nil
+}
+
+// Start serves the API until the context is done.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{Addr: ":" + s.cfg.Port, Handler: s.Handler(), ReadHeaderTimeout: readHeaderTimeout}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+	log.Info("Rosetta API is serving at ", s.cfg.Port)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Handler returns the handler of the endpoints of the API. The account and construction endpoints are not
+// supported: the balances are the ones of the chains, and the bridge txs are built by the wallets.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/network/list", handle(s.networkList))
+	mux.HandleFunc("/network/status", handle(s.networkStatus))
+	mux.HandleFunc("/network/options", handle(s.networkOptions))
+	mux.HandleFunc("/block", handle(s.block))
+	mux.HandleFunc("/block/transaction", handle(s.blockTransaction))
+	mux.HandleFunc("/mempool", handle(s.mempool))
+	mux.HandleFunc("/mempool/transaction", handle(func(ctx context.Context, req *NetworkRequest) (*struct{}, *Error) {
+		return nil, errTransactionNotFound
+	}))
+	notSupported := handle(func(ctx context.Context, req *NetworkRequest) (*struct{}, *Error) {
+		return nil, errNotSupported
+	})
+	for _, path := range []string{"/account/", "/construction/", "/call", "/events/", "/search/"} {
+		mux.HandleFunc(path, notSupported)
+	}
+	return mux
+}
+
+// handle decodes the request of an endpoint and encodes its response, or its error with the status 500 of the
+// specification.
+func handle[Req, Res any](endpoint func(ctx context.Context, req *Req) (*Res, *Error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var req Req
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusInternalServerError, withDetails(errInvalidRequest, err))
+			return
+		}
+		res, rErr := endpoint(r.Context(), &req)
+		if rErr != nil {
+			writeJSON(w, http.StatusInternalServerError, rErr)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debugf("error writing the rosetta response: %v", err)
+	}
+}
+
+func withDetails(e *Error, err error) *Error {
+	res := *e
+	res.Details = map[string]interface{}{"error": err.Error()}
+	return &res
+}
+
+// storageError returns the error of a failed read, a block not found being one not synced yet. The other errors
+// are logged, their details are not returned to the clients.
+func storageError(err error) *Error {
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return errBlockNotFound
+	}
+	log.Errorf("rosetta: error reading the synced blocks: %v", err)
+	return errInternal
+}
+
+// readSnapshot runs the reads of a request in a snapshot of the database, so they don't mix the states before and
+// after a reorg.
+func (s *Server) readSnapshot(ctx context.Context, read func(dbTx pgx.Tx) error) error {
+	dbTx, err := s.storage.BeginSnapshotTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	// The transaction only reads, there is nothing to commit
+	defer dbTx.Rollback(context.Background()) //nolint:errcheck
+	return read(dbTx)
+}
+
+func (s *Server) networkIdentifier(networkID uint) NetworkIdentifier {
+	return NetworkIdentifier{
+		Blockchain:           s.cfg.Blockchain,
+		Network:              s.cfg.Network,
+		SubNetworkIdentifier: &SubNetworkIdentifier{Network: strconv.FormatUint(uint64(networkID), 10)},
+	}
+}
+
+// networkID returns the network of the bridge of the identifier.
+func (s *Server) networkID(id NetworkIdentifier) (uint, *Error) {
+	if id.Blockchain != s.cfg.Blockchain || id.Network != s.cfg.Network || id.SubNetworkIdentifier == nil {
+		return 0, errNetworkNotFound
+	}
+	for _, networkID := range s.networks {
+		if strconv.FormatUint(uint64(networkID), 10) == id.SubNetworkIdentifier.Network {
+			return networkID, nil
+		}
+	}
+	return 0, errNetworkNotFound
+}
+
+func blockIdentifier(block *etherman.Block) BlockIdentifier {
+	return BlockIdentifier{Index: int64(block.BlockNumber), Hash: block.BlockHash.Hex()}
+}
+
+func (s *Server) networkList(ctx context.Context, req *struct{}) (*NetworkListResponse, *Error) {
+	res := NetworkListResponse{NetworkIdentifiers: make([]NetworkIdentifier, 0, len(s.networks))}
+	for _, networkID := range s.networks {
+		res.NetworkIdentifiers = append(res.NetworkIdentifiers, s.networkIdentifier(networkID))
+	}
+	return &res, nil
+}
+
+// networkStatus returns the last synced block of the network, and its first synced block as the genesis.
+func (s *Server) networkStatus(ctx context.Context, req *NetworkRequest) (*NetworkStatusResponse, *Error) {
+	networkID, rErr := s.networkID(req.NetworkIdentifier)
+	if rErr != nil {
+		return nil, rErr
+	}
+	var res NetworkStatusResponse
+	err := s.readSnapshot(ctx, func(dbTx pgx.Tx) error {
+		last, err := s.storage.GetLastBlock(ctx, networkID, dbTx)
+		if err != nil {
+			return err
+		}
+		first, err := s.storage.GetFirstBlock(ctx, networkID, dbTx)
+		if err != nil {
+			return err
+		}
+		res = NetworkStatusResponse{
+			CurrentBlockIdentifier: blockIdentifier(last),
+			CurrentBlockTimestamp:  last.ReceivedAt.UnixMilli(),
+			GenesisBlockIdentifier: blockIdentifier(first),
+			Peers:                  []interface{}{},
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+	return &res, nil
+}
+
+func (s *Server) networkOptions(ctx context.Context, req *NetworkRequest) (*NetworkOptionsResponse, *Error) {
+	if _, rErr := s.networkID(req.NetworkIdentifier); rErr != nil {
+		return nil, rErr
+	}
+	return &NetworkOptionsResponse{
+		Version: Version{RosettaVersion: rosettaVersion, NodeVersion: s.version},
+		Allow: Allow{
+			OperationStatuses: []OperationStatus{{Status: StatusSuccess, Successful: true}},
+			OperationTypes:    []string{OperationDeposit, OperationClaim},
+			Errors:            allErrors,
+			CallMethods:       []string{},
+			BalanceExemptions: []interface{}{},
+		},
+	}, nil
+}
+
+func (s *Server) mempool(ctx context.Context, req *NetworkRequest) (*MempoolResponse, *Error) {
+	if _, rErr := s.networkID(req.NetworkIdentifier); rErr != nil {
+		return nil, rErr
+	}
+	// Only the mined deposits and claims are synced
+	return &MempoolResponse{TransactionIdentifiers: []TransactionIdentifier{}}, nil
+}
+
+// block returns a synced block. The blocks without bridge events aren't synced, they are the omitted blocks of the
+// specification, returned without block, and the parent of a block is the previous synced one.
+func (s *Server) block(ctx context.Context, req *BlockRequest) (*BlockResponse, *Error) {
+	networkID, rErr := s.networkID(req.NetworkIdentifier)
+	if rErr != nil {
+		return nil, rErr
+	}
+	if req.BlockIdentifier.Index != nil && *req.BlockIdentifier.Index < 0 {
+		return nil, errInvalidRequest
+	}
+	var res BlockResponse
+	err := s.readSnapshot(ctx, func(dbTx pgx.Tx) error {
+		block, err := s.findBlock(ctx, networkID, req.BlockIdentifier, dbTx)
+		if err != nil || block == nil {
+			return err
+		}
+		parent := blockIdentifier(block)
+		if block.BlockNumber > 0 {
+			// The first synced block is its own parent, as a genesis block
+			previous, err := s.storage.GetBlockUntil(ctx, networkID, block.BlockNumber-1, dbTx)
+			if err == nil {
+				parent = blockIdentifier(previous)
+			} else if !errors.Is(err, gerror.ErrStorageNotFound) {
+				return err
+			}
+		}
+		txs, err := s.transactions(ctx, block, dbTx)
+		if err != nil {
+			return err
+		}
+		res.Block = &Block{
+			BlockIdentifier:       blockIdentifier(block),
+			ParentBlockIdentifier: parent,
+			Timestamp:             block.ReceivedAt.UnixMilli(),
+			Transactions:          txs,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+	return &res, nil
+}
+
+// findBlock returns the synced block of the identifier, the last one without index nor hash. It's nil for an
+// omitted block, and not found for a block not synced yet.
+func (s *Server) findBlock(ctx context.Context, networkID uint, id PartialBlockIdentifier, dbTx pgx.Tx) (*etherman.Block, error) {
+	if id.Hash != nil {
+		block, err := s.storage.GetBlockByHash(ctx, networkID, common.HexToHash(*id.Hash), dbTx)
+		if err != nil {
+			return nil, err
+		}
+		if id.Index != nil && uint64(*id.Index) != block.BlockNumber {
+			return nil, gerror.ErrStorageNotFound
+		}
+		return block, nil
+	}
+	last, err := s.storage.GetLastBlock(ctx, networkID, dbTx)
+	if err != nil || id.Index == nil {
+		return last, err
+	}
+	index := uint64(*id.Index)
+	if index > last.BlockNumber {
+		return nil, gerror.ErrStorageNotFound
+	}
+	block, err := s.storage.GetBlockUntil(ctx, networkID, index, dbTx)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if block.BlockNumber != index {
+		return nil, nil
+	}
+	return block, nil
+}
+
+func (s *Server) blockTransaction(ctx context.Context, req *BlockTransactionRequest) (*BlockTransactionResponse, *Error) {
+	networkID, rErr := s.networkID(req.NetworkIdentifier)
+	if rErr != nil {
+		return nil, rErr
+	}
+	var res *BlockTransactionResponse
+	err := s.readSnapshot(ctx, func(dbTx pgx.Tx) error {
+		block, err := s.storage.GetBlockByHash(ctx, networkID, common.HexToHash(req.BlockIdentifier.Hash), dbTx)
+		if err != nil {
+			return err
+		}
+		if int64(block.BlockNumber) != req.BlockIdentifier.Index {
+			return gerror.ErrStorageNotFound
+		}
+		txs, err := s.transactions(ctx, block, dbTx)
+		if err != nil {
+			return err
+		}
+		txHash := common.HexToHash(req.TransactionIdentifier.Hash).Hex()
+		for _, tx := range txs {
+			if tx.TransactionIdentifier.Hash == txHash {
+				res = &BlockTransactionResponse{Transaction: tx}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+	if res == nil {
+		return nil, errTransactionNotFound
+	}
+	return res, nil
+}
+
+// event is a deposit or a claim as an operation of its tx. The operation is nil for the deposits skipped.
+type event struct {
+	txHash    common.Hash
+	logIndex  uint
+	operation *Operation
+}
+
+// transactions returns the txs of the block with their deposits and claims, in the order of their logs.
+func (s *Server) transactions(ctx context.Context, block *etherman.Block, dbTx pgx.Tx) ([]Transaction, error) {
+	deposits, err := s.storage.GetBlockDeposits(ctx, block.ID, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := s.storage.GetBlockClaims(ctx, block.ID, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]event, 0, len(deposits)+len(claims))
+	for _, deposit := range deposits {
+		op, ok := s.depositOperation(ctx, deposit, dbTx)
+		if !ok {
+			log.Warnf("rosetta: skipping the operation of the deposit %d of network %d, its depositor isn't recorded, enable Synchronizer.RecordDepositSenders",
+				deposit.DepositCount, deposit.NetworkID)
+			events = append(events, event{txHash: deposit.TxHash, logIndex: deposit.LogIndex})
+			continue
+		}
+		events = append(events, event{txHash: deposit.TxHash, logIndex: deposit.LogIndex, operation: &op})
+	}
+	for _, claim := range claims {
+		op, err := s.claimOperation(ctx, claim, dbTx)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event{txHash: claim.TxHash, logIndex: claim.LogIndex, operation: &op})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].logIndex < events[j].logIndex })
+
+	txs := make([]Transaction, 0)
+	positions := make(map[common.Hash]int)
+	for _, e := range events {
+		i, found := positions[e.txHash]
+		if !found {
+			i = len(txs)
+			positions[e.txHash] = i
+			txs = append(txs, Transaction{TransactionIdentifier: TransactionIdentifier{Hash: e.txHash.Hex()}, Operations: []Operation{}})
+		}
+		if e.operation == nil {
+			continue
+		}
+		e.operation.OperationIdentifier = OperationIdentifier{Index: int64(len(txs[i].Operations))}
+		txs[i].Operations = append(txs[i].Operations, *e.operation)
+	}
+	return txs, nil
+}
+
+// depositOperation returns the operation of a deposit, from the account of the depositor. It's false for the
+// deposits synced without their senders, an operation without account isn't valid for the Rosetta clients.
+func (s *Server) depositOperation(ctx context.Context, deposit *etherman.Deposit, dbTx pgx.Tx) (Operation, bool) {
+	var account *AccountIdentifier
+	switch {
+	case deposit.Depositor != (common.Address{}):
+		account = &AccountIdentifier{Address: deposit.Depositor.Hex()}
+	case deposit.Sender != (common.Address{}):
+		account = &AccountIdentifier{Address: deposit.Sender.Hex()}
+	default:
+		return Operation{}, false
+	}
+	status := StatusSuccess
+	op := Operation{
+		Type:    OperationDeposit,
+		Status:  &status,
+		Account: account,
+		Amount:  s.amount(ctx, new(big.Int).Neg(deposit.Amount), deposit.LeafType, deposit.OriginalNetwork, deposit.OriginalAddress, deposit.Metadata, dbTx),
+		Metadata: map[string]interface{}{
+			"leaf_type":   deposit.LeafType,
+			"deposit_cnt": deposit.DepositCount,
+			"dest_net":    deposit.DestinationNetwork,
+			"dest_addr":   deposit.DestinationAddress.Hex(),
+		},
+	}
+	return op, true
+}
+
+// claimOperation returns the operation of a claim, to the account of its destination. The leaf type of the claimed
+// deposit tells if it's a message, the claims of the deposits not synced are assets.
+func (s *Server) claimOperation(ctx context.Context, claim *etherman.Claim, dbTx pgx.Tx) (Operation, error) {
+	var leafType uint8
+	deposit, err := s.storage.GetClaimedDeposit(ctx, claim.Index, claim.NetworkID, claim.OriginalNetwork, claim.OriginalAddress, dbTx)
+	if err == nil {
+		leafType = deposit.LeafType
+	} else if !errors.Is(err, gerror.ErrStorageNotFound) {
+		return Operation{}, err
+	}
+	status := StatusSuccess
+	op := Operation{
+		Type:     OperationClaim,
+		Status:   &status,
+		Account:  &AccountIdentifier{Address: claim.DestinationAddress.Hex()},
+		Amount:   s.amount(ctx, claim.Amount, leafType, claim.OriginalNetwork, claim.OriginalAddress, nil, dbTx),
+		Metadata: map[string]interface{}{"index": claim.Index},
+	}
+	if claim.MessageCall != "" {
+		op.Metadata["message_call"] = claim.MessageCall
+	}
+	return op, nil
+}
+
+// amount returns the amount of a deposit or a claim in the currency of its token. The amount of a message is the
+// ether sent with it, whose origin is the sender of the message instead of a token, and a message without ether
+// has no amount.
+func (s *Server) amount(ctx context.Context, value *big.Int, leafType uint8, origNet uint, origAddr common.Address, metadata []byte, dbTx pgx.Tx) *Amount {
+	if leafType == leafTypeMessage {
+		if value.Sign() == 0 {
+			return nil
+		}
+		return &Amount{Value: value.String(), Currency: s.currency(ctx, 0, common.Address{}, nil, dbTx)}
+	}
+	return &Amount{Value: value.String(), Currency: s.currency(ctx, origNet, origAddr, metadata, dbTx)}
+}
+
+// currency returns the currency of a token, from the metadata of its deposit or of its wrapped token. A token whose
+// metadata isn't known yet has its address as symbol and no decimals.
+func (s *Server) currency(ctx context.Context, origNet uint, origAddr common.Address, metadata []byte, dbTx pgx.Tx) Currency {
+	key := tokenKey{origNet: origNet, origAddr: origAddr}
+	s.mu.Lock()
+	currency, found := s.currencies[key]
+	s.mu.Unlock()
+	if found {
+		return currency
+	}
+	currency = Currency{
+		Symbol:   origAddr.Hex(),
+		Metadata: map[string]interface{}{"orig_net": origNet, "orig_addr": origAddr.Hex()},
+	}
+	known := false
+	if origAddr == (common.Address{}) {
+		currency.Symbol, currency.Decimals, known = "ETH", ethDecimals, true
+	} else if len(metadata) > 0 {
+		if values, err := tokenMetadataArgs.Unpack(metadata); err == nil {
+			currency.Symbol, currency.Decimals, known = values[1].(string), int32(values[2].(uint8)), true
+		}
+	}
+	if !known {
+		token, err := s.storage.GetTokenWrapped(ctx, origNet, origAddr, dbTx)
+		if err == nil && token.Symbol != "" {
+			currency.Symbol, currency.Decimals, known = token.Symbol, int32(token.Decimals), true
+		} else if err != nil && !errors.Is(err, gerror.ErrStorageNotFound) {
+			log.Warnf("error getting the wrapped token of %d-%s: %v", origNet, origAddr.Hex(), err)
+		}
+	}
+	if known {
+		s.mu.Lock()
+		s.currencies[key] = currency
+		s.mu.Unlock()
+	}
+	return currency
+}
@@ -0,0 +1,243 @@
+package rosetta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// coinMetadata is the metadata of the token CoinA, COA with 12 decimals.
+var coinMetadata = common.FromHex("0x000000000000000000000000000000000000000000000000000000000000006000000000000000000000000000000000000000000000000000000000000000a0000000000000000000000000000000000000000000000000000000000000000c0000000000000000000000000000000000000000000000000000000000000005436f696e410000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000003434f410000000000000000000000000000000000000000000000000000000000")
+
+type fakeTx struct {
+	pgx.Tx
+}
+
+func (tx *fakeTx) Rollback(ctx context.Context) error {
+	return nil
+}
+
+// blockStorage serves the synced blocks of the network 0 from memory, in order.
+type blockStorage struct {
+	blocks   []*etherman.Block
+	deposits map[uint64][]*etherman.Deposit
+	claims   map[uint64][]*etherman.Claim
+	tokens   map[common.Address]*etherman.TokenWrapped
+	// claimed are the deposits of the claims by their index
+	claimed map[uint]*etherman.Deposit
+	err     error
+}
+
+func (s *blockStorage) BeginSnapshotTransaction(ctx context.Context) (pgx.Tx, error) {
+	return &fakeTx{}, nil
+}
+
+func (s *blockStorage) GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
+	if networkID != 0 || len(s.blocks) == 0 {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return s.blocks[len(s.blocks)-1], nil
+}
+
+func (s *blockStorage) GetFirstBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
+	if networkID != 0 || len(s.blocks) == 0 {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return s.blocks[0], nil
+}
+
+func (s *blockStorage) GetBlockUntil(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.Block, error) {
+	for i := len(s.blocks) - 1; i >= 0 && networkID == 0; i-- {
+		if s.blocks[i].BlockNumber <= blockNum {
+			return s.blocks[i], nil
+		}
+	}
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (s *blockStorage) GetBlockByHash(ctx context.Context, networkID uint, blockHash common.Hash, dbTx pgx.Tx) (*etherman.Block, error) {
+	for _, block := range s.blocks {
+		if networkID == 0 && block.BlockHash == blockHash {
+			return block, nil
+		}
+	}
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (s *blockStorage) GetBlockDeposits(ctx context.Context, blockID uint64, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	return s.deposits[blockID], nil
+}
+
+func (s *blockStorage) GetBlockClaims(ctx context.Context, blockID uint64, dbTx pgx.Tx) ([]*etherman.Claim, error) {
+	return s.claims[blockID], s.err
+}
+
+func (s *blockStorage) GetClaimedDeposit(ctx context.Context, depositCnt, destNetworkID, origNet uint, origAddr common.Address, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	deposit, found := s.claimed[depositCnt]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return deposit, nil
+}
+
+func (s *blockStorage) GetTokenWrapped(ctx context.Context, originalNetwork uint, originalTokenAddress common.Address, dbTx pgx.Tx) (*etherman.TokenWrapped, error) {
+	token, found := s.tokens[originalTokenAddress]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return token, nil
+}
+
+// post calls an endpoint, decoding its response or its error.
+func post(t *testing.T, url string, req interface{}, res interface{}) *Error {
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body)) //nolint:gosec
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		var rErr Error
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&rErr))
+		return &rErr
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	return nil
+}
+
+func TestBlocks(t *testing.T) {
+	user := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	coin := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	wrapped := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	sender := common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	depositTx, claimTx, messageTx := common.HexToHash("0xd1"), common.HexToHash("0xc1"), common.HexToHash("0xe1")
+	storage := &blockStorage{
+		blocks: []*etherman.Block{
+			{ID: 1, BlockNumber: 5, BlockHash: common.HexToHash("0xb5"), ReceivedAt: time.Unix(1700000000, 0)},
+			{ID: 2, BlockNumber: 9, BlockHash: common.HexToHash("0xb9"), ReceivedAt: time.Unix(1700000060, 0)},
+		},
+		deposits: map[uint64][]*etherman.Deposit{2: {
+			{OriginalAddress: coin, Amount: big.NewInt(1000), DestinationNetwork: 1, DestinationAddress: user, DepositCount: 7, TxHash: depositTx, LogIndex: 3, Metadata: coinMetadata, Depositor: user},
+			{Amount: big.NewInt(5), DestinationNetwork: 1, DestinationAddress: user, DepositCount: 6, TxHash: depositTx, LogIndex: 1},
+			{LeafType: leafTypeMessage, OriginalAddress: sender, Amount: big.NewInt(0), DestinationNetwork: 1, DestinationAddress: user, DepositCount: 8, TxHash: messageTx, LogIndex: 4, Sender: sender},
+		}},
+		claims: map[uint64][]*etherman.Claim{2: {
+			{Index: 4, OriginalNetwork: 1, OriginalAddress: wrapped, Amount: big.NewInt(20), DestinationAddress: user, TxHash: claimTx, LogIndex: 2},
+			{Index: 5, OriginalNetwork: 1, OriginalAddress: sender, Amount: big.NewInt(30), DestinationAddress: user, TxHash: messageTx, LogIndex: 5},
+		}},
+		tokens:  map[common.Address]*etherman.TokenWrapped{wrapped: {TokenMetadata: etherman.TokenMetadata{Symbol: "WRP", Decimals: 6}}},
+		claimed: map[uint]*etherman.Deposit{5: {LeafType: leafTypeMessage, DepositCount: 5, NetworkID: 1, OriginalNetwork: 1, OriginalAddress: sender}},
+	}
+	s, err := NewServer(Config{Port: "8090", Blockchain: "zkevm-bridge", Network: "mainnet"}, storage, []uint{0, 1}, "v1.0.0")
+	require.NoError(t, err)
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	var networks NetworkListResponse
+	require.Nil(t, post(t, srv.URL+"/network/list", struct{}{}, &networks))
+	require.Len(t, networks.NetworkIdentifiers, 2)
+	network := networks.NetworkIdentifiers[0]
+	require.Equal(t, "0", network.SubNetworkIdentifier.Network)
+
+	var status NetworkStatusResponse
+	require.Nil(t, post(t, srv.URL+"/network/status", NetworkRequest{NetworkIdentifier: network}, &status))
+	require.Equal(t, int64(9), status.CurrentBlockIdentifier.Index)
+	require.Equal(t, int64(5), status.GenesisBlockIdentifier.Index)
+	require.Equal(t, int64(1700000060000), status.CurrentBlockTimestamp)
+	// The network 1 has no synced blocks yet
+	rErr := post(t, srv.URL+"/network/status", NetworkRequest{NetworkIdentifier: networks.NetworkIdentifiers[1]}, &status)
+	require.Equal(t, errBlockNotFound.Code, rErr.Code)
+	require.True(t, rErr.Retriable)
+	rErr = post(t, srv.URL+"/network/status", NetworkRequest{NetworkIdentifier: NetworkIdentifier{Blockchain: "zkevm-bridge", Network: "testnet"}}, &status)
+	require.Equal(t, errNetworkNotFound.Code, rErr.Code)
+
+	block := func(index int64) *BlockResponse {
+		var res BlockResponse
+		require.Nil(t, post(t, srv.URL+"/block", BlockRequest{NetworkIdentifier: network, BlockIdentifier: PartialBlockIdentifier{Index: &index}}, &res))
+		return &res
+	}
+	// The first block is its own parent
+	res := block(5)
+	require.Equal(t, res.Block.BlockIdentifier, res.Block.ParentBlockIdentifier)
+	require.Empty(t, res.Block.Transactions)
+	// The blocks without events are omitted, the parent of a block is the previous synced one
+	require.Nil(t, block(7).Block)
+	require.Nil(t, block(2).Block)
+	res = block(9)
+	require.Equal(t, int64(5), res.Block.ParentBlockIdentifier.Index)
+
+	// The operations are in the order of their logs, grouped by tx. The deposits synced without their senders have
+	// no account, their operations are skipped
+	txs := res.Block.Transactions
+	require.Len(t, txs, 3)
+	require.Equal(t, depositTx.Hex(), txs[0].TransactionIdentifier.Hash)
+	require.Len(t, txs[0].Operations, 1)
+	token := txs[0].Operations[0]
+	require.Equal(t, OperationDeposit, token.Type)
+	require.Equal(t, int64(0), token.OperationIdentifier.Index)
+	require.Equal(t, user.Hex(), token.Account.Address)
+	require.Equal(t, "-1000", token.Amount.Value)
+	require.Equal(t, "COA", token.Amount.Currency.Symbol)
+	require.Equal(t, int32(12), token.Amount.Currency.Decimals)
+	require.Equal(t, coin.Hex(), token.Amount.Currency.Metadata["orig_addr"])
+	claim := txs[1].Operations[0]
+	require.Equal(t, OperationClaim, claim.Type)
+	require.Equal(t, "20", claim.Amount.Value)
+	require.Equal(t, "WRP", claim.Amount.Currency.Symbol)
+
+	// The messages are in ether, those without it have no amount
+	require.Equal(t, messageTx.Hex(), txs[2].TransactionIdentifier.Hash)
+	require.Len(t, txs[2].Operations, 2)
+	require.Nil(t, txs[2].Operations[0].Amount)
+	require.Equal(t, sender.Hex(), txs[2].Operations[0].Account.Address)
+	messageClaim := txs[2].Operations[1]
+	require.Equal(t, "30", messageClaim.Amount.Value)
+	require.Equal(t, "ETH", messageClaim.Amount.Currency.Symbol)
+	require.Equal(t, int32(18), messageClaim.Amount.Currency.Decimals)
+
+	// The blocks not synced yet are retried
+	index := int64(12)
+	rErr = post(t, srv.URL+"/block", BlockRequest{NetworkIdentifier: network, BlockIdentifier: PartialBlockIdentifier{Index: &index}}, &res)
+	require.Equal(t, errBlockNotFound.Code, rErr.Code)
+	// The last block without identifier, or by its hash
+	require.Nil(t, post(t, srv.URL+"/block", BlockRequest{NetworkIdentifier: network}, &res))
+	require.Equal(t, int64(9), res.Block.BlockIdentifier.Index)
+	hash := common.HexToHash("0xb5").Hex()
+	require.Nil(t, post(t, srv.URL+"/block", BlockRequest{NetworkIdentifier: network, BlockIdentifier: PartialBlockIdentifier{Hash: &hash}}, &res))
+	require.Equal(t, int64(5), res.Block.BlockIdentifier.Index)
+
+	var txRes BlockTransactionResponse
+	require.Nil(t, post(t, srv.URL+"/block/transaction", BlockTransactionRequest{
+		NetworkIdentifier:     network,
+		BlockIdentifier:       BlockIdentifier{Index: 9, Hash: common.HexToHash("0xb9").Hex()},
+		TransactionIdentifier: TransactionIdentifier{Hash: claimTx.Hex()},
+	}, &txRes))
+	require.Equal(t, OperationClaim, txRes.Transaction.Operations[0].Type)
+	rErr = post(t, srv.URL+"/block/transaction", BlockTransactionRequest{
+		NetworkIdentifier:     network,
+		BlockIdentifier:       BlockIdentifier{Index: 9, Hash: common.HexToHash("0xb9").Hex()},
+		TransactionIdentifier: TransactionIdentifier{Hash: common.HexToHash("0x01").Hex()},
+	}, &txRes)
+	require.Equal(t, errTransactionNotFound.Code, rErr.Code)
+
+	rErr = post(t, srv.URL+"/account/balance", NetworkRequest{NetworkIdentifier: network}, &struct{}{})
+	require.Equal(t, errNotSupported.Code, rErr.Code)
+
+	// The storage errors are not returned to the clients
+	storage.err = errors.New("connection refused to 10.0.0.5:5432")
+	rErr = post(t, srv.URL+"/block", BlockRequest{NetworkIdentifier: network}, &res)
+	require.Equal(t, errInternal.Code, rErr.Code)
+	require.Empty(t, rErr.Details)
+}
@@ -0,0 +1,163 @@
+package rosetta
+
+// The types of the Rosetta Data API used by the bridge, as defined by its specification.
+
+// NetworkIdentifier identifies a network of the bridge.
+type NetworkIdentifier struct {
+	Blockchain           string                `json:"blockchain"`
+	Network              string                `json:"network"`
+	SubNetworkIdentifier *SubNetworkIdentifier `json:"sub_network_identifier,omitempty"`
+}
+
+// SubNetworkIdentifier is the network id of a network of the bridge.
+type SubNetworkIdentifier struct {
+	Network string `json:"network"`
+}
+
+// BlockIdentifier identifies a block.
+type BlockIdentifier struct {
+	Index int64  `json:"index"`
+	Hash  string `json:"hash"`
+}
+
+// PartialBlockIdentifier identifies a block by its index, its hash or both. The last block without any.
+type PartialBlockIdentifier struct {
+	Index *int64  `json:"index,omitempty"`
+	Hash  *string `json:"hash,omitempty"`
+}
+
+// TransactionIdentifier identifies a tx.
+type TransactionIdentifier struct {
+	Hash string `json:"hash"`
+}
+
+// OperationIdentifier is the index of an operation in its tx.
+type OperationIdentifier struct {
+	Index int64 `json:"index"`
+}
+
+// AccountIdentifier is an address.
+type AccountIdentifier struct {
+	Address string `json:"address"`
+}
+
+// Currency is a token, identified by its original network and address in its metadata.
+type Currency struct {
+	Symbol   string                 `json:"symbol"`
+	Decimals int32                  `json:"decimals"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Amount is a signed amount of a currency, in its smallest unit.
+type Amount struct {
+	Value    string   `json:"value"`
+	Currency Currency `json:"currency"`
+}
+
+// Operation is a deposit or a claim.
+type Operation struct {
+	OperationIdentifier OperationIdentifier    `json:"operation_identifier"`
+	Type                string                 `json:"type"`
+	Status              *string                `json:"status,omitempty"`
+	Account             *AccountIdentifier     `json:"account,omitempty"`
+	Amount              *Amount                `json:"amount,omitempty"`
+	Metadata            map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Transaction is a tx with the deposits and the claims of its logs.
+type Transaction struct {
+	TransactionIdentifier TransactionIdentifier `json:"transaction_identifier"`
+	Operations            []Operation           `json:"operations"`
+}
+
+// Block is a synced block of a network.
+type Block struct {
+	BlockIdentifier       BlockIdentifier `json:"block_identifier"`
+	ParentBlockIdentifier BlockIdentifier `json:"parent_block_identifier"`
+	// Timestamp is the time of the block in milliseconds
+	Timestamp    int64         `json:"timestamp"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Error is the error of a request.
+type Error struct {
+	Code      int32                  `json:"code"`
+	Message   string                 `json:"message"`
+	Retriable bool                   `json:"retriable"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// OperationStatus is a status of the operations.
+type OperationStatus struct {
+	Status     string `json:"status"`
+	Successful bool   `json:"successful"`
+}
+
+// Version is the version of the API and of the service.
+type Version struct {
+	RosettaVersion string `json:"rosetta_version"`
+	NodeVersion    string `json:"node_version"`
+}
+
+// Allow are the statuses, operations and errors of the API.
+type Allow struct {
+	OperationStatuses       []OperationStatus `json:"operation_statuses"`
+	OperationTypes          []string          `json:"operation_types"`
+	Errors                  []*Error          `json:"errors"`
+	HistoricalBalanceLookup bool              `json:"historical_balance_lookup"`
+	CallMethods             []string          `json:"call_methods"`
+	BalanceExemptions       []interface{}     `json:"balance_exemptions"`
+	MempoolCoins            bool              `json:"mempool_coins"`
+}
+
+// NetworkRequest is the request of the network endpoints.
+type NetworkRequest struct {
+	NetworkIdentifier NetworkIdentifier `json:"network_identifier"`
+}
+
+// NetworkListResponse is the response of /network/list.
+type NetworkListResponse struct {
+	NetworkIdentifiers []NetworkIdentifier `json:"network_identifiers"`
+}
+
+// NetworkStatusResponse is the response of /network/status.
+type NetworkStatusResponse struct {
+	CurrentBlockIdentifier BlockIdentifier `json:"current_block_identifier"`
+	CurrentBlockTimestamp  int64           `json:"current_block_timestamp"`
+	GenesisBlockIdentifier BlockIdentifier `json:"genesis_block_identifier"`
+	Peers                  []interface{}   `json:"peers"`
+}
+
+// NetworkOptionsResponse is the response of /network/options.
+type NetworkOptionsResponse struct {
+	Version Version `json:"version"`
+	Allow   Allow   `json:"allow"`
+}
+
+// BlockRequest is the request of /block.
+type BlockRequest struct {
+	NetworkIdentifier NetworkIdentifier      `json:"network_identifier"`
+	BlockIdentifier   PartialBlockIdentifier `json:"block_identifier"`
+}
+
+// BlockResponse is the response of /block, without block for the omitted blocks.
+type BlockResponse struct {
+	Block *Block `json:"block,omitempty"`
+}
+
+// BlockTransactionRequest is the request of /block/transaction.
+type BlockTransactionRequest struct {
+	NetworkIdentifier     NetworkIdentifier     `json:"network_identifier"`
+	BlockIdentifier       BlockIdentifier       `json:"block_identifier"`
+	TransactionIdentifier TransactionIdentifier `json:"transaction_identifier"`
+}
+
+// BlockTransactionResponse is the response of /block/transaction.
+type BlockTransactionResponse struct {
+	Transaction Transaction `json:"transaction"`
+}
+
+// MempoolResponse is the response of /mempool.
+type MempoolResponse struct {
+	TransactionIdentifiers []TransactionIdentifier `json:"transaction_identifiers"`
+}
@@ -0,0 +1,251 @@
+// Package schemadoc renders the schema of the bridge database, read from a migrated database, as documented SQL
+// and as entity relationship diagrams, so the docs of the tables are always the ones of the code.
+package schemadoc
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	// FormatSQL renders the tables as documented CREATE statements
+	FormatSQL = "sql"
+	// FormatMermaid renders the tables as a mermaid entity relationship diagram
+	FormatMermaid = "mermaid"
+	// FormatDot renders the tables as a graphviz graph
+	FormatDot = "dot"
+
+	constraintPrimaryKey = "p"
+	constraintForeignKey = "f"
+)
+
+// mermaidInvalidType matches the chars not allowed in the types of the attributes of a mermaid diagram
+var mermaidInvalidType = regexp.MustCompile(`[^A-Za-z0-9_\[\]()]`)
+
+// Schema is the schema of the bridge tables.
+type Schema struct {
+	Tables []Table
+}
+
+// Table is a table with its documentation, from the comments of the database.
+type Table struct {
+	Schema      string
+	Name        string
+	Comment     string
+	Columns     []Column
+	Constraints []Constraint
+	// Indexes are the definitions of the indexes that are not of a constraint
+	Indexes []string
+	// Triggers are the definitions of the triggers, with if they are enabled
+	Triggers []Trigger
+}
+
+// FullName returns the name of the table with its schema.
+func (t *Table) FullName() string {
+	return t.Schema + "." + t.Name
+}
+
+// Column is a column of a table.
+type Column struct {
+	Name    string
+	Type    string
+	NotNull bool
+	Default string
+	Comment string
+}
+
+// Constraint is a primary key, foreign key or unique constraint of a table.
+type Constraint struct {
+	Name string
+	// Type is the postgres type of the constraint: p, f or u
+	Type       string
+	Definition string
+	Columns    []string
+	// RefTable and RefColumns are the referenced ones, only for the foreign keys
+	RefTable   string
+	RefColumns []string
+}
+
+// Trigger is a trigger of a table.
+type Trigger struct {
+	Definition string
+	Enabled    bool
+}
+
+// IsFormat returns if the format is supported.
+func IsFormat(format string) bool {
+	return format == FormatSQL || format == FormatMermaid || format == FormatDot
+}
+
+// Write renders the schema in the format.
+func Write(w io.Writer, format string, schema *Schema) error {
+	var b strings.Builder
+	switch format {
+	case FormatSQL:
+		writeSQL(&b, schema)
+	case FormatMermaid:
+		writeMermaid(&b, schema)
+	case FormatDot:
+		writeDot(&b, schema)
+	default:
+		return fmt.Errorf("unknown schema format %s", format)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeSQL(b *strings.Builder, schema *Schema) {
+	b.WriteString("-- Schema of the bridge database, generated from the migrated database. Do not edit.\n")
+	for _, table := range schema.Tables {
+		b.WriteString("\n")
+		writeSQLComment(b, "", table.Comment)
+		fmt.Fprintf(b, "CREATE TABLE %s\n(\n", table.FullName())
+		lines := make([]string, 0, len(table.Columns)+len(table.Constraints))
+		for _, column := range table.Columns {
+			var line strings.Builder
+			writeSQLComment(&line, "    ", column.Comment)
+			fmt.Fprintf(&line, "    %s %s", column.Name, column.Type)
+			if column.NotNull {
+				line.WriteString(" NOT NULL")
+			}
+			if column.Default != "" {
+				line.WriteString(" DEFAULT " + column.Default)
+			}
+			lines = append(lines, line.String())
+		}
+		for _, constraint := range table.Constraints {
+			lines = append(lines, fmt.Sprintf("    CONSTRAINT %s %s", constraint.Name, constraint.Definition))
+		}
+		b.WriteString(strings.Join(lines, ",\n"))
+		b.WriteString("\n);\n")
+		for _, index := range table.Indexes {
+			b.WriteString(index + ";\n")
+		}
+		for _, trigger := range table.Triggers {
+			if !trigger.Enabled {
+				b.WriteString("-- Disabled in the database\n")
+			}
+			b.WriteString(trigger.Definition + ";\n")
+		}
+	}
+}
+
+func writeSQLComment(b *strings.Builder, indent, comment string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		b.WriteString(strings.TrimRight(indent+"-- "+line, " ") + "\n")
+	}
+}
+
+// mermaidName returns the name of the entity of a table, mermaid doesn't allow dots in the names.
+func mermaidName(table string) string {
+	return strings.ReplaceAll(table, ".", "_")
+}
+
+func writeMermaid(b *strings.Builder, schema *Schema) {
+	b.WriteString("erDiagram\n")
+	for _, table := range schema.Tables {
+		keys := columnKeys(table)
+		fmt.Fprintf(b, "    %s {\n", mermaidName(table.FullName()))
+		for _, column := range table.Columns {
+			fmt.Fprintf(b, "        %s %s", mermaidInvalidType.ReplaceAllString(column.Type, "_"), column.Name)
+			if key := keys[column.Name]; key != "" {
+				b.WriteString(" " + key)
+			}
+			if column.Comment != "" {
+				fmt.Fprintf(b, " %q", strings.ReplaceAll(firstLine(column.Comment), `"`, "'"))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, table := range schema.Tables {
+		for _, fk := range foreignKeys(table) {
+			// The child has one parent if the columns are not null, at most one otherwise
+			parent := "|o"
+			if allNotNull(table, fk.Columns) {
+				parent = "||"
+			}
+			fmt.Fprintf(b, "    %s %s--o{ %s : %q\n", mermaidName(fk.RefTable), parent, mermaidName(table.FullName()), strings.Join(fk.Columns, ", "))
+		}
+	}
+}
+
+func writeDot(b *strings.Builder, schema *Schema) {
+	b.WriteString("digraph schema {\n    rankdir=LR;\n    node [shape=plaintext];\n")
+	for _, table := range schema.Tables {
+		keys := columnKeys(table)
+		fmt.Fprintf(b, "    %q [label=<<TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\">", table.FullName())
+		fmt.Fprintf(b, "<TR><TD BGCOLOR=\"lightgrey\"><B>%s</B></TD></TR>", html.EscapeString(table.FullName()))
+		for _, column := range table.Columns {
+			label := html.EscapeString(column.Name + " " + column.Type)
+			if key := keys[column.Name]; key != "" {
+				label += " <I>" + key + "</I>"
+			}
+			fmt.Fprintf(b, "<TR><TD PORT=%q ALIGN=\"LEFT\">%s</TD></TR>", column.Name, label)
+		}
+		b.WriteString("</TABLE>>];\n")
+	}
+	for _, table := range schema.Tables {
+		for _, fk := range foreignKeys(table) {
+			fmt.Fprintf(b, "    %q:%q -> %q:%q;\n", table.FullName(), fk.Columns[0], fk.RefTable, fk.RefColumns[0])
+		}
+	}
+	b.WriteString("}\n")
+}
+
+// columnKeys returns if the columns are primary keys, foreign keys or both.
+func columnKeys(table Table) map[string]string {
+	keys := make(map[string]string)
+	for _, constraint := range table.Constraints {
+		var key string
+		switch constraint.Type {
+		case constraintPrimaryKey:
+			key = "PK"
+		case constraintForeignKey:
+			key = "FK"
+		default:
+			continue
+		}
+		for _, column := range constraint.Columns {
+			if keys[column] != "" && keys[column] != key {
+				keys[column] = "PK, FK"
+			} else {
+				keys[column] = key
+			}
+		}
+	}
+	return keys
+}
+
+// foreignKeys returns the foreign keys of the table to the tables of the schema.
+func foreignKeys(table Table) []Constraint {
+	var fks []Constraint
+	for _, constraint := range table.Constraints {
+		if constraint.Type == constraintForeignKey && len(constraint.Columns) > 0 && len(constraint.RefColumns) > 0 {
+			fks = append(fks, constraint)
+		}
+	}
+	return fks
+}
+
+func allNotNull(table Table, columns []string) bool {
+	for _, name := range columns {
+		for _, column := range table.Columns {
+			if column.Name == name && !column.NotNull {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,70 @@
+package schemadoc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSchema() *Schema {
+	return &Schema{Tables: []Table{
+		{
+			Schema:  "sync",
+			Name:    "block",
+			Comment: "The synced blocks",
+			Columns: []Column{{Name: "id", Type: "integer", NotNull: true, Default: "nextval('sync.block_id_seq'::regclass)"}},
+			Constraints: []Constraint{
+				{Name: "block_pkey", Type: constraintPrimaryKey, Definition: "PRIMARY KEY (id)", Columns: []string{"id"}},
+			},
+		},
+		{
+			Schema: "sync",
+			Name:   "deposit",
+			Columns: []Column{
+				{Name: "block_id", Type: "bigint", NotNull: true, Comment: "Block of the \"deposit\""},
+				{Name: "created_at", Type: "timestamp with time zone"},
+			},
+			Constraints: []Constraint{{
+				Name: "deposit_block_id_fkey", Type: constraintForeignKey, Definition: "FOREIGN KEY (block_id) REFERENCES sync.block(id) ON DELETE CASCADE",
+				Columns: []string{"block_id"}, RefTable: "sync.block", RefColumns: []string{"id"},
+			}},
+			Indexes:  []string{"CREATE INDEX deposit_block_id ON sync.deposit USING btree (block_id)"},
+			Triggers: []Trigger{{Definition: "CREATE TRIGGER deposit_outbox AFTER INSERT ON sync.deposit FOR EACH ROW EXECUTE FUNCTION sync.capture_row_change()"}},
+		},
+	}}
+}
+
+func TestWriteSQL(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, Write(&b, FormatSQL, testSchema()))
+	require.Contains(t, b.String(), `-- The synced blocks
+CREATE TABLE sync.block
+(
+    id integer NOT NULL DEFAULT nextval('sync.block_id_seq'::regclass),
+    CONSTRAINT block_pkey PRIMARY KEY (id)
+);`)
+	require.Contains(t, b.String(), `    -- Block of the "deposit"
+    block_id bigint NOT NULL,
+    created_at timestamp with time zone,`)
+	require.Contains(t, b.String(), "CREATE INDEX deposit_block_id ON sync.deposit USING btree (block_id);\n-- Disabled in the database\nCREATE TRIGGER deposit_outbox")
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, Write(&b, FormatMermaid, testSchema()))
+	require.Contains(t, b.String(), "    sync_block {\n        integer id PK\n    }\n")
+	require.Contains(t, b.String(), `        bigint block_id FK "Block of the 'deposit'"`)
+	require.Contains(t, b.String(), "        timestamp_with_time_zone created_at\n")
+	require.Contains(t, b.String(), `    sync_block ||--o{ sync_deposit : "block_id"`)
+}
+
+func TestWriteDot(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, Write(&b, FormatDot, testSchema()))
+	require.Contains(t, b.String(), `<TD PORT="id" ALIGN="LEFT">id integer <I>PK</I></TD>`)
+	require.Contains(t, b.String(), `    "sync.deposit":"block_id" -> "sync.block":"id";`)
+	require.True(t, strings.HasSuffix(b.String(), "}\n"))
+
+	require.Error(t, Write(&b, "png", testSchema()))
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/activity"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+)
+
+// GetDepositActivity returns the activity log of a deposit for the support tools: its changes of status, the attempts
+// of its claim tx and the log lines about it, merged in order.
+// Bridge rest API endpoint
+func (s *bridgeService) GetDepositActivity(ctx context.Context, req *pb.GetDepositActivityRequest) (*pb.GetDepositActivityResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	history, err := s.storage.GetDepositStatusHistory(ctx, uint(req.DepositCnt), uint(req.NetId), nil)
+	if err != nil {
+		return nil, err
+	}
+	// One more entry than the limit tells if the log is truncated, the last entries are the ones kept
+	entries, err := s.storage.GetDepositActivities(ctx, uint(req.NetId), uint(req.DepositCnt), req.Q, uint(s.adminMaxPageLimit)+1, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.GetDepositActivityResponse{}
+	if len(entries) > int(s.adminMaxPageLimit) {
+		entries, res.Truncated = entries[len(entries)-int(s.adminMaxPageLimit):], true
+	}
+	// The recorded entries are filtered by the storage, the changes of status here
+	q := strings.ToLower(req.Q)
+	for _, c := range history {
+		entry := etherman.DepositActivity{Kind: activity.KindStatus, Message: c.Status, Time: c.Time}
+		if c.BlockID != 0 {
+			entry.Fields = map[string]string{
+				"network_id": strconv.FormatUint(uint64(c.NetworkID), 10),
+				"block_num":  strconv.FormatUint(c.BlockNumber, 10),
+			}
+		}
+		if q == "" || matchActivity(entry, q) {
+			entries = append(entries, entry)
+		}
+	}
+	// The recorded entries are in order, the changes of status are placed between them by time
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	for _, e := range entries {
+		res.Activity = append(res.Activity, &pb.DepositActivity{
+			Kind:    e.Kind,
+			Level:   e.Level,
+			Message: e.Message,
+			Fields:  e.Fields,
+			Time:    unixTime(e.Time),
+		})
+	}
+	return res, nil
+}
+
+// matchActivity checks if the message or a field of the entry contains the lower case text.
+func matchActivity(e etherman.DepositActivity, q string) bool {
+	if strings.Contains(strings.ToLower(e.Message), q) {
+		return true
+	}
+	for k, v := range e.Fields {
+		if strings.Contains(strings.ToLower(k+"="+v), q) {
+			return true
+		}
+	}
+	return false
+}
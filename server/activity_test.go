@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/activity"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestGetDepositActivity(t *testing.T) {
+	bench, _ := newBenchStorage(1, 0)
+	start := time.Unix(1700000000, 0)
+	storage := newMemStorage(bench)
+	storage.history = []etherman.DepositStatusChange{
+		{Status: etherman.DepositStatusIndexed, BlockID: 1, NetworkID: 0, BlockNumber: 10, Time: start},
+		{Status: etherman.DepositStatusReadyForClaim, Time: start.Add(5 * time.Minute)},
+	}
+	storage.activities = []etherman.DepositActivity{
+		{Kind: activity.KindLog, Level: activity.LevelInfo, Message: "create the claim tx for the deposit 0", Time: start.Add(6 * time.Minute)},
+		{Kind: activity.KindClaimAttempt, Level: activity.LevelInfo, Message: "claim tx sent", Fields: map[string]string{"tx_hash": "0xABC"}, Time: start.Add(7 * time.Minute)},
+		{Kind: activity.KindLog, Level: activity.LevelError, Message: "failed to check if tx 0xabc was mined", Time: start.Add(8 * time.Minute)},
+	}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, AdminMaxPageLimit: 10, AdminToken: "admin"}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, "admin"))
+
+	_, err := s.GetDepositActivity(context.Background(), &pb.GetDepositActivityRequest{DepositCnt: 0})
+	require.Error(t, err)
+
+	// The changes of status are merged with the recorded entries by time
+	res, err := s.GetDepositActivity(ctx, &pb.GetDepositActivityRequest{DepositCnt: 0})
+	require.NoError(t, err)
+	require.False(t, res.Truncated)
+	require.Len(t, res.Activity, 5)
+	require.Equal(t, activity.KindStatus, res.Activity[0].Kind)
+	require.Equal(t, etherman.DepositStatusIndexed, res.Activity[0].Message)
+	require.Equal(t, "10", res.Activity[0].Fields["block_num"])
+	require.Empty(t, res.Activity[1].Fields)
+	require.Equal(t, activity.KindClaimAttempt, res.Activity[3].Kind)
+	require.Equal(t, uint64(start.Add(8*time.Minute).Unix()), res.Activity[4].Time)
+
+	// The text filter matches the messages and the fields, ignoring the case
+	res, err = s.GetDepositActivity(ctx, &pb.GetDepositActivityRequest{DepositCnt: 0, Q: "0xabc"})
+	require.NoError(t, err)
+	require.Len(t, res.Activity, 2)
+	require.Equal(t, "claim tx sent", res.Activity[0].Message)
+
+	// The changes of status are filtered too
+	res, err = s.GetDepositActivity(ctx, &pb.GetDepositActivityRequest{DepositCnt: 0, Q: "block_num=10"})
+	require.NoError(t, err)
+	require.Len(t, res.Activity, 1)
+	require.Equal(t, etherman.DepositStatusIndexed, res.Activity[0].Message)
+
+	// The recorded entries over the admin page limit are truncated, keeping the last ones
+	s.adminMaxPageLimit = 2
+	res, err = s.GetDepositActivity(ctx, &pb.GetDepositActivityRequest{DepositCnt: 0})
+	require.NoError(t, err)
+	require.True(t, res.Truncated)
+	require.Len(t, res.Activity, 4)
+	require.Equal(t, "claim tx sent", res.Activity[2].Message)
+	require.Equal(t, "failed to check if tx 0xabc was mined", res.Activity[3].Message)
+}
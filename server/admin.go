@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// adminTokenHeader is the header with the token to call the admin endpoints.
+// The http gateway forwards it as grpc metadata.
+const adminTokenHeader = "x-admin-token"
+
+// checkAdmin checks that the request carries the configured admin token, or the token of a named admin.
+func (s *bridgeService) checkAdmin(ctx context.Context) error {
+	if s.adminToken == "" {
+		return status.Error(codes.Unimplemented, "admin endpoints are disabled")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, token := range md.Get(adminTokenHeader) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1 {
+			return nil
+		}
+	}
+	if _, err := s.adminName(ctx); err == nil {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "invalid admin token")
+}
+
+// adminName returns the name of the admin of the token of the request, failing if it's not the token of a named
+// admin.
+func (s *bridgeService) adminName(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, token := range md.Get(adminTokenHeader) {
+		for _, admin := range s.configApprovals.Admins {
+			if admin.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(admin.Token)) == 1 {
+				return admin.Name, nil
+			}
+		}
+	}
+	return "", status.Error(codes.PermissionDenied, "the token of a named admin is required")
+}
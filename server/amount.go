@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// leafTypeMessage is the leaf type of the message deposits, whose amount is the ether sent with the message.
+const leafTypeMessage = 1
+
+// formatAmount formats the raw amount as a decimal number with the token decimals, without trailing zeros.
+func formatAmount(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return ""
+	}
+	raw := new(big.Int).Abs(amount).String()
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals == 0 {
+		return sign + raw
+	}
+	if len(raw) <= int(decimals) {
+		raw = strings.Repeat("0", int(decimals)-len(raw)+1) + raw
+	}
+	integer, fraction := raw[:len(raw)-int(decimals)], strings.TrimRight(raw[len(raw)-int(decimals):], "0")
+	if fraction == "" {
+		return sign + integer
+	}
+	return sign + integer + "." + fraction
+}
+
+// formatDepositAmount returns the decimals and the formatted amount of the deposit. The amount of the messages is
+// ether, their original address is the sender of the message instead of a token.
+func (s *bridgeService) formatDepositAmount(ctx context.Context, deposit *etherman.Deposit) (uint32, string) {
+	if deposit.LeafType == leafTypeMessage {
+		return etherman.EtherDecimals, formatAmount(deposit.Amount, etherman.EtherDecimals)
+	}
+	return s.formatTokenAmount(ctx, deposit.OriginalNetwork, deposit.OriginalAddress, deposit.Metadata, deposit.Amount)
+}
+
+// formatTokenAmount returns the decimals of the token and the formatted amount, both empty if the decimals are unknown.
+func (s *bridgeService) formatTokenAmount(ctx context.Context, originalNetwork uint, originalAddress common.Address, metadata []byte, amount *big.Int) (uint32, string) {
+	decimals, found := s.tokenDecimals(ctx, originalNetwork, originalAddress, metadata)
+	if !found {
+		return 0, ""
+	}
+	return uint32(decimals), formatAmount(amount, decimals)
+}
+
+// tokenDecimals resolves the decimals of a token from the deposit metadata or from the wrapped token.
+func (s *bridgeService) tokenDecimals(ctx context.Context, originalNetwork uint, originalAddress common.Address, metadata []byte) (uint8, bool) {
+	key := fmt.Sprintf("%d-%s", originalNetwork, originalAddress.Hex())
+	if decimals, found := s.decimalsCache.Get(key); found {
+		return decimals, true
+	}
+	decimals, found, err := etherman.TokenDecimals(originalAddress, metadata, func() (*etherman.TokenWrapped, error) {
+		return s.storage.GetTokenWrapped(ctx, originalNetwork, originalAddress, nil)
+	})
+	if err != nil {
+		log.Warnf("error getting the wrapped token of %s. Error: %v", key, err)
+	}
+	if found {
+		s.decimalsCache.Add(key, decimals)
+	}
+	return decimals, found
+}
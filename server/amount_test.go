@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/uint256"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// The metadata of the ERC20 deposits is abi.encode(string name, string symbol, uint8 decimals).
+var (
+	stringType, _     = abi.NewType("string", "", nil)
+	uint8Type, _      = abi.NewType("uint8", "", nil)
+	tokenMetadataArgs = abi.Arguments{{Name: "name", Type: stringType}, {Name: "symbol", Type: stringType}, {Name: "decimals", Type: uint8Type}}
+)
+
+func TestFormatAmount(t *testing.T) {
+	amount, _ := new(big.Int).SetString("1500000000000000000", 10)
+	assert.Equal(t, "1.5", formatAmount(amount, 18))
+	assert.Equal(t, "1500000000000", formatAmount(amount, 6))
+	assert.Equal(t, "1500000000000000000", formatAmount(amount, 0))
+	assert.Equal(t, "0.000001", formatAmount(big.NewInt(1), 6))
+	assert.Equal(t, "0", formatAmount(big.NewInt(0), 18))
+	assert.Equal(t, "-0.5", formatAmount(big.NewInt(-5), 1))
+	assert.Equal(t, "", formatAmount(nil, 18))
+}
+
+func TestFormatAmountProperties(t *testing.T) {
+	// The formatted amount is exactly the raw amount divided by 10^decimals for every uint256, it's never rounded
+	exact := func(words [4]uint64, decimals uint8) bool {
+		amount := new(big.Int)
+		for _, w := range words {
+			amount.Lsh(amount, 64).Or(amount, new(big.Int).SetUint64(w))
+		}
+		formatted := formatAmount(amount, decimals)
+		parsed, ok := new(big.Rat).SetString(formatted)
+		if !ok || strings.HasSuffix(formatted, ".") || (strings.Contains(formatted, ".") && strings.HasSuffix(formatted, "0")) {
+			return false
+		}
+		expected := new(big.Rat).SetFrac(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+		return uint256.Check(amount) == nil && parsed.Cmp(expected) == 0
+	}
+	assert.NoError(t, quick.Check(exact, nil))
+	assert.Equal(t, "115792089237316195423570985008.687907853269984665640564039457584007913129639935", formatAmount(uint256.Max, 48))
+}
+
+func TestFormatDepositAmount(t *testing.T) {
+	decimalsCache, err := lru.New[string, uint8](10)
+	assert.NoError(t, err)
+	s := &bridgeService{decimalsCache: decimalsCache}
+	metadata, err := tokenMetadataArgs.Pack("CoinA", "COA", uint8(6))
+	assert.NoError(t, err)
+	amount, _ := new(big.Int).SetString("1500000000000000000", 10)
+	deposit := &etherman.Deposit{OriginalAddress: common.HexToAddress("0x1"), Metadata: metadata, Amount: amount}
+	decimals, formatted := s.formatDepositAmount(context.Background(), deposit)
+	assert.Equal(t, uint32(6), decimals)
+	assert.Equal(t, "1500000000000", formatted)
+
+	// The amount of a message is ether, its metadata is not a token's
+	message := &etherman.Deposit{LeafType: leafTypeMessage, OriginalAddress: common.HexToAddress("0x2"), Metadata: metadata, Amount: amount}
+	decimals, formatted = s.formatDepositAmount(context.Background(), message)
+	assert.Equal(t, uint32(etherman.EtherDecimals), decimals)
+	assert.Equal(t, "1.5", formatted)
+	assert.Equal(t, 1, decimalsCache.Len())
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/cache"
+	"google.golang.org/protobuf/proto"
+)
+
+// SetCache caches the token metadata, the proof nodes and the account queries in the two tier cache.
+func (s *bridgeService) SetCache(c *cache.Cache) {
+	s.responses = c
+}
+
+// loadCached fills the response with the one cached in the key, calling load on a miss. The load errors are not
+// cached.
+func (s *bridgeService) loadCached(ctx context.Context, key string, tags []string, res proto.Message, load func() (proto.Message, error)) error {
+	if s.responses == nil {
+		msg, err := load()
+		if err != nil {
+			return err
+		}
+		proto.Merge(res, msg)
+		return nil
+	}
+	data, err := s.responses.Load(ctx, key, tags, func() ([]byte, error) {
+		msg, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(msg)
+	})
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, res)
+}
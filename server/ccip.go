@@ -0,0 +1,56 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The on-chain consumers revert with OffchainLookup using as callData
+// abi.encodeWithSignature("getClaimProof(uint32,uint32)", networkID, depositCount).
+// The response is abi.encode(bytes32[32] smtProof, bytes32 mainnetExitRoot, bytes32 rollupExitRoot),
+// which are the same params expected by the claimAsset and claimMessage functions of the bridge.
+var (
+	ccipClaimProofSelector = crypto.Keccak256([]byte("getClaimProof(uint32,uint32)"))[:4]
+
+	uint32Type, _        = abi.NewType("uint32", "", nil)
+	bytes32Type, _       = abi.NewType("bytes32", "", nil)
+	bytes32ArrayType, _  = abi.NewType("bytes32[32]", "", nil)
+	ccipClaimProofArgs   = abi.Arguments{{Name: "networkID", Type: uint32Type}, {Name: "depositCount", Type: uint32Type}}
+	ccipClaimProofResult = abi.Arguments{{Name: "smtProof", Type: bytes32ArrayType}, {Name: "mainnetExitRoot", Type: bytes32Type}, {Name: "rollupExitRoot", Type: bytes32Type}}
+)
+
+// decodeCCIPCallData decodes the callData of the offchain lookup. The data can have the
+// .json suffix, as it's common to use {data}.json in the gateway url template.
+func decodeCCIPCallData(data string) (networkID, depositCount uint, err error) {
+	callData, err := hexutil.Decode(strings.TrimSuffix(data, ".json"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid ccip callData: %w", err)
+	}
+	if len(callData) < len(ccipClaimProofSelector) || !bytes.Equal(callData[:len(ccipClaimProofSelector)], ccipClaimProofSelector) {
+		return 0, 0, fmt.Errorf("unsupported ccip callData selector")
+	}
+	values, err := ccipClaimProofArgs.Unpack(callData[len(ccipClaimProofSelector):])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid ccip callData arguments: %w", err)
+	}
+	return uint(values[0].(uint32)), uint(values[1].(uint32)), nil
+}
+
+// encodeCCIPClaimProof encodes the claim proof to be returned to the callback of the offchain lookup.
+func encodeCCIPClaimProof(globalExitRoot *etherman.GlobalExitRoot, merkleProof [][bridgectrl.KeyLen]byte) ([]byte, error) {
+	var smtProof [32][32]byte
+	if len(merkleProof) != len(smtProof) {
+		return nil, fmt.Errorf("invalid merkle proof length: %d", len(merkleProof))
+	}
+	for i := range merkleProof {
+		smtProof[i] = merkleProof[i]
+	}
+	return ccipClaimProofResult.Pack(smtProof, [32]byte(globalExitRoot.ExitRoots[0]), [32]byte(globalExitRoot.ExitRoots[1]))
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCCIPCallData(t *testing.T) {
+	args, err := ccipClaimProofArgs.Pack(uint32(1), uint32(25))
+	require.NoError(t, err)
+	data := hexutil.Encode(append(ccipClaimProofSelector, args...))
+
+	networkID, depositCnt, err := decodeCCIPCallData(data)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), networkID)
+	require.Equal(t, uint(25), depositCnt)
+
+	networkID, depositCnt, err = decodeCCIPCallData(data + ".json")
+	require.NoError(t, err)
+	require.Equal(t, uint(1), networkID)
+	require.Equal(t, uint(25), depositCnt)
+
+	_, _, err = decodeCCIPCallData("0x12345678" + data[10:])
+	require.Error(t, err)
+	_, _, err = decodeCCIPCallData(data[:20])
+	require.Error(t, err)
+	_, _, err = decodeCCIPCallData("invalid")
+	require.Error(t, err)
+}
+
+func TestEncodeCCIPClaimProof(t *testing.T) {
+	ger := &etherman.GlobalExitRoot{
+		ExitRoots: []common.Hash{common.HexToHash("0x1"), common.HexToHash("0x2")},
+	}
+	proof := make([][bridgectrl.KeyLen]byte, 32)
+	proof[3] = common.HexToHash("0x3")
+
+	data, err := encodeCCIPClaimProof(ger, proof)
+	require.NoError(t, err)
+	values, err := ccipClaimProofResult.Unpack(data)
+	require.NoError(t, err)
+	smtProof := values[0].([32][32]byte)
+	require.Equal(t, [32]byte(common.HexToHash("0x3")), smtProof[3])
+	require.Equal(t, [32]byte(ger.ExitRoots[0]), values[1].([32]byte))
+	require.Equal(t, [32]byte(ger.ExitRoots[1]), values[2].([32]byte))
+
+	_, err = encodeCCIPClaimProof(ger, proof[:31])
+	require.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/changefeed"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+)
+
+// maxDepositWait is the max time a request waits for the change of a deposit
+const maxDepositWait = 60 * time.Second
+
+// SetChangeFeed wakes the requests waiting for the changes of the deposits with the changes of the feed.
+func (s *bridgeService) SetChangeFeed(feed *changefeed.Feed) {
+	s.changes = feed
+}
+
+// waitDepositChange waits until the deposit, or its claim, changes or the wait elapses. The claimed deposits don't
+// change anymore, so they are not waited for.
+func (s *bridgeService) waitDepositChange(ctx context.Context, networkID, depositCnt uint, wait time.Duration) error {
+	if s.changes == nil || wait <= 0 {
+		return nil
+	}
+	if wait > maxDepositWait {
+		wait = maxDepositWait
+	}
+	// The subscription starts before the deposit is read, so the changes meanwhile are not missed
+	sub := s.changes.Subscribe()
+	defer sub.Close()
+	deposit, err := s.storage.GetDeposit(ctx, depositCnt, networkID, nil)
+	if err == nil {
+		claimTxHash, err := s.GetDepositStatus(ctx, depositCnt, deposit.DestinationNetwork)
+		if err != nil || claimTxHash != "" {
+			return err
+		}
+	} else if !errors.Is(err, gerror.ErrStorageNotFound) {
+		return err
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case change := <-sub.Changes():
+			if isDepositChange(change, networkID, depositCnt, deposit) {
+				return nil
+			}
+		}
+	}
+}
+
+// isDepositChange returns if the change is of the deposit or of its claim. The deposit is nil while it's unknown.
+func isDepositChange(change changefeed.Change, networkID, depositCnt uint, deposit *etherman.Deposit) bool {
+	switch change.Table {
+	case changefeed.TableDeposit:
+		return change.NetworkID == networkID && change.DepositCount == depositCnt
+	case changefeed.TableClaim:
+		return deposit != nil && change.NetworkID == deposit.DestinationNetwork && change.DepositCount == depositCnt
+	case changefeed.TableTokenWrapped:
+		return false
+	default:
+		// The changes may have been lost
+		return true
+	}
+}
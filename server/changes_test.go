@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/changefeed"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// feedStorage notifies the payloads sent by the test, and serves the deposits of the test.
+type feedStorage struct {
+	*benchStorage
+	payloads chan string
+	known    map[uint]*etherman.Deposit
+}
+
+func (s *feedStorage) ListenChanges(ctx context.Context, handle func(payload string)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case payload := <-s.payloads:
+			handle(payload)
+		}
+	}
+}
+
+func (s *feedStorage) GetCurrentLSN(ctx context.Context, dbTx pgx.Tx) (string, error) {
+	return "0/1", nil
+}
+
+func (s *feedStorage) IsLSNReplayed(ctx context.Context, lsn string, dbTx pgx.Tx) (bool, error) {
+	return true, nil
+}
+
+func (s *feedStorage) GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	deposit, found := s.known[depositCnt]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return deposit, nil
+}
+
+func TestWaitDepositChange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bench, _ := newBenchStorage(10, 5)
+	// The odd deposits are not claimed in the bench storage
+	storage := &feedStorage{benchStorage: bench, payloads: make(chan string), known: map[uint]*etherman.Deposit{
+		1: {NetworkID: 0, DepositCount: 1, DestinationNetwork: 1},
+		2: {NetworkID: 0, DepositCount: 2, DestinationNetwork: 1},
+	}}
+	s := newBenchService(bench)
+	s.storage = storage
+
+	// Without the feed the requests don't wait
+	require.NoError(t, s.waitDepositChange(ctx, 0, 1, time.Minute))
+
+	feed := changefeed.NewFeed(changefeed.Config{ReplicaWait: types.NewDuration(time.Second), SubscriberBuffer: 10}, storage, storage)
+	go feed.Start(ctx)
+	s.SetChangeFeed(feed)
+
+	// The claimed deposits don't change anymore
+	require.NoError(t, s.waitDepositChange(ctx, 0, 2, time.Minute))
+
+	// The unclaimed deposit waits for its claim
+	done := make(chan error)
+	go func() {
+		done <- s.waitDepositChange(ctx, 0, 1, time.Minute)
+	}()
+	storage.payloads <- `{"table": "claim", "op": "INSERT", "network_id": 1, "deposit_cnt": 3}`
+	storage.payloads <- `{"table": "deposit", "op": "INSERT", "network_id": 1, "deposit_cnt": 1}`
+	select {
+	case <-done:
+		t.Fatal("the changes of other deposits finished the wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.NoError(t, notifyUntilDone(storage, `{"table": "claim", "op": "INSERT", "network_id": 1, "deposit_cnt": 1}`, done))
+
+	// The unknown deposit waits until it's indexed or the wait elapses
+	require.NoError(t, s.waitDepositChange(ctx, 0, 3, 50*time.Millisecond))
+	go func() {
+		done <- s.waitDepositChange(ctx, 0, 3, time.Minute)
+	}()
+	require.NoError(t, notifyUntilDone(storage, `{"table": "deposit", "op": "INSERT", "network_id": 0, "deposit_cnt": 3}`, done))
+}
+
+// notifyUntilDone notifies the payload until the wait is done, as the waiting request could subscribe after the
+// first notification.
+func notifyUntilDone(storage *feedStorage, payload string, done chan error) error {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case storage.payloads <- payload:
+		case err := <-done:
+			return err
+		case <-timeout:
+			return errors.New("the change didn't finish the wait")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
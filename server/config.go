@@ -1,6 +1,10 @@
 package server
 
-import "github.com/0xPolygonHermez/zkevm-bridge-service/db"
+import (
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/statusrules"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
 
 // Config struct
 type Config struct {
@@ -10,12 +14,208 @@ type Config struct {
 	HTTPPort string `mapstructure:"HTTPPort"`
 	// CacheSize is the buffer size of the lru-cache
 	CacheSize int `mapstructure:"CacheSize"`
+	// TopLevels is the number of top levels of the exit trees kept in memory, so the proofs only read the nodes of
+	// the bottom levels from the lru-cache and the storage. 0 disables it
+	TopLevels uint8 `mapstructure:"TopLevels"`
+	// TopLevelsSize is the max number of nodes of the top levels kept in memory
+	TopLevelsSize int `mapstructure:"TopLevelsSize"`
+	// StatusRules are the display statuses of the deposits defined by the operator, the first rule a deposit matches
+	// sets its display status
+	StatusRules []statusrules.Rule `mapstructure:"StatusRules"`
 	// DefaultPageLimit is the default page limit for pagination
 	DefaultPageLimit uint32 `mapstructure:"DefaultPageLimit"`
-	// MaxPageLimit is the maximum page limit for pagination
+	// MaxPageLimit is the maximum page limit for pagination. Larger limits are rejected
 	MaxPageLimit uint32 `mapstructure:"MaxPageLimit"`
+	// MaxPageResults is the max offset plus limit of the paginated requests, rejecting the deep pages that scan
+	// most of the results. 0 doesn't limit them
+	MaxPageResults uint64 `mapstructure:"MaxPageResults"`
+	// AdminMaxPageLimit is the max page limit of the requests with the admin token, without max results, for the
+	// bulk consumers. It's only used when larger than MaxPageLimit
+	AdminMaxPageLimit uint32 `mapstructure:"AdminMaxPageLimit"`
+	// MaxSimulatedClaims is the max number of claims simulated by a request, each one sending two calls to the node
+	// of the destination network, also for the admin requests. 0 only limits them with the page limits
+	MaxSimulatedClaims uint32 `mapstructure:"MaxSimulatedClaims"`
 	// Version is the version of the bridge service
 	BridgeVersion string `mapstructure:"BridgeVersion"`
+	// MaxSyncLag is the max number of blocks that the synced data can be behind the network head
+	// to consider the service ready
+	MaxSyncLag uint64 `mapstructure:"MaxSyncLag"`
+	// AdminToken is the token required in the X-Admin-Token header to call the admin endpoints.
+	// Empty disables the admin endpoints
+	AdminToken string `mapstructure:"AdminToken"`
+	// ConfigApprovals is the two-person rule of the changes of the runtime settings made with the admin endpoints
+	ConfigApprovals ConfigApprovalsConfig `mapstructure:"ConfigApprovals"`
+	// InProcessGateway makes the REST gateway call the service directly instead of going through the gRPC server.
+	// Off by default: the direct calls skip the message size and the stream limits of the gRPC server
+	InProcessGateway bool `mapstructure:"InProcessGateway"`
+	// GRPC is the tuning of the gRPC server connections
+	GRPC GRPCConfig `mapstructure:"GRPC"`
+	// Gateway is the resolution and the load balancing of the connection of the REST gateway to the gRPC server
+	Gateway GatewayConfig `mapstructure:"Gateway"`
+	// Session is the configuration of the wallet sessions
+	Session SessionConfig `mapstructure:"Session"`
+	// Watch is the configuration of the addresses watched by the wallets
+	Watch WatchConfig `mapstructure:"Watch"`
+	// MaxEventSubscribers is the max number of streams, of bridge events and of deposit statuses, served at the
+	// same time by each instance. 0 disables the streams
+	MaxEventSubscribers int `mapstructure:"MaxEventSubscribers"`
+	// BatchPollInterval is the time between the reads of the batch of a L2 deposit whose status is streamed, until
+	// the batch is verified. 0 doesn't stream the states of the batches
+	BatchPollInterval types.Duration `mapstructure:"BatchPollInterval"`
+	// Jobs is the configuration of the admin queries run asynchronously
+	Jobs JobsConfig `mapstructure:"Jobs"`
+	// ProofAccess is the configuration of the proofs restricted to the allowed consumers
+	ProofAccess ProofAccessConfig `mapstructure:"ProofAccess"`
+	// TokenList is the configuration of the token lists managed with the admin endpoints
+	TokenList TokenListConfig `mapstructure:"TokenList"`
+	// ClaimVersion is the claim interface of the bridge contract the claim witnesses are formatted for by default:
+	// v1 for PolygonZkEVMBridge, v2 for PolygonZkEVMBridgeV2
+	ClaimVersion string `mapstructure:"ClaimVersion"`
+	// Contracts is the configuration of the address book of the contracts the service interacts with
+	Contracts ContractsConfig `mapstructure:"Contracts"`
+	// IdempotencyKeyTTL is the time the responses of the admin mutations sent with an Idempotency-Key header are
+	// kept, so their retries return them instead of running again
+	IdempotencyKeyTTL types.Duration `mapstructure:"IdempotencyKeyTTL"`
 	// DB is the database config
 	DB db.Config `mapstructure:"DB"`
 }
+
+// GRPCConfig is the configuration of the gRPC server connections. The zero values keep the gRPC defaults
+type GRPCConfig struct {
+	// MaxRecvMsgSize is the max size in bytes of the messages received by the server
+	MaxRecvMsgSize int `mapstructure:"MaxRecvMsgSize"`
+	// MaxSendMsgSize is the max size in bytes of the messages sent by the server
+	MaxSendMsgSize int `mapstructure:"MaxSendMsgSize"`
+	// MaxConcurrentStreams is the max number of concurrent streams of each connection
+	MaxConcurrentStreams uint32 `mapstructure:"MaxConcurrentStreams"`
+	// MaxConnectionIdle is the time after which an idle connection is closed
+	MaxConnectionIdle types.Duration `mapstructure:"MaxConnectionIdle"`
+	// MaxConnectionAge is the max time a connection may exist before it's gracefully closed
+	MaxConnectionAge types.Duration `mapstructure:"MaxConnectionAge"`
+	// MaxConnectionAgeGrace is the time given to the pending rpcs to finish after MaxConnectionAge
+	MaxConnectionAgeGrace types.Duration `mapstructure:"MaxConnectionAgeGrace"`
+	// KeepaliveTime is the time without activity after which the server pings the client
+	KeepaliveTime types.Duration `mapstructure:"KeepaliveTime"`
+	// KeepaliveTimeout is the time the server waits for the ping ack before closing the connection
+	KeepaliveTimeout types.Duration `mapstructure:"KeepaliveTimeout"`
+	// KeepaliveMinTime is the min time between the client pings, the clients pinging more often are disconnected
+	KeepaliveMinTime types.Duration `mapstructure:"KeepaliveMinTime"`
+	// KeepalivePermitWithoutStream allows the client pings when there are no active streams
+	KeepalivePermitWithoutStream bool `mapstructure:"KeepalivePermitWithoutStream"`
+	// XDS serves the gRPC server configured by the xDS control plane of the bootstrap file in GRPC_XDS_BOOTSTRAP.
+	// It requires a binary built with the xds tag
+	XDS bool `mapstructure:"XDS"`
+}
+
+// GatewayConfig is the resolution and the load balancing of the connection of the REST gateway to the gRPC server,
+// e.g. to reach the gRPC servers through a service mesh. The zero values dial the local gRPC server
+type GatewayConfig struct {
+	// Target is the gRPC target dialed, with the scheme of its resolver: dns:///bridge:9090, xds:///bridge, or
+	// static:///bridge for the Addresses. Empty dials localhost:GRPCPort
+	Target string `mapstructure:"Target"`
+	// Addresses are the addresses the static:/// target resolves to
+	Addresses []string `mapstructure:"Addresses"`
+	// ServiceConfig is the json gRPC service config of the connection, with its load balancing policy, e.g.
+	// {"loadBalancingConfig": [{"round_robin": {}}]}. Empty uses the one of the resolver or pick_first
+	ServiceConfig string `mapstructure:"ServiceConfig"`
+	// Authority is the :authority header of the requests, for the meshes routing by it. Empty uses the target
+	Authority string `mapstructure:"Authority"`
+}
+
+// SessionConfig is the configuration of the sessions of the wallets, created signing an EIP-712 challenge
+// to access the endpoints with the private data of their address
+type SessionConfig struct {
+	// Secret authenticates the challenges and the session tokens. Empty disables the wallet endpoints
+	Secret string `mapstructure:"Secret"`
+	// ChainID is the chain id of the EIP-712 domain of the challenges
+	ChainID uint64 `mapstructure:"ChainID"`
+	// ChallengeTTL is the time to sign a challenge before it expires
+	ChallengeTTL types.Duration `mapstructure:"ChallengeTTL"`
+	// SessionTTL is the time a session is valid
+	SessionTTL types.Duration `mapstructure:"SessionTTL"`
+	// MaxWebhooks is the max number of webhooks of a wallet
+	MaxWebhooks int `mapstructure:"MaxWebhooks"`
+	// MaxDevices is the max number of push notification devices of a wallet
+	MaxDevices int `mapstructure:"MaxDevices"`
+}
+
+// WatchConfig is the configuration of the addresses watched by the wallets with a session: the first pages of their
+// bridges and claims are kept hot in the response cache, the statuses of their deposits are refreshed faster, and
+// the history of their deposits is backfilled on demand
+type WatchConfig struct {
+	// MaxAddresses is the max number of addresses watched by a wallet
+	MaxAddresses int `mapstructure:"MaxAddresses"`
+	// WarmInterval is the time between two loads of the first pages of the watched addresses in the response cache,
+	// so they are loaded again soon after their invalidation. 0 disables it
+	WarmInterval types.Duration `mapstructure:"WarmInterval"`
+	// BatchPollInterval is the time between the reads of the batch of a streamed L2 deposit to a watched address,
+	// instead of the BatchPollInterval of the server when it's shorter. 0 polls them as the other deposits
+	BatchPollInterval types.Duration `mapstructure:"BatchPollInterval"`
+	// BackfillInterval is the time between two checks of the backfills requested by the wallets, run by the instance
+	// syncing the networks. 0 disables the backfills
+	BackfillInterval types.Duration `mapstructure:"BackfillInterval"`
+	// BackfillBatch is the number of deposits read at once by the backfills
+	BackfillBatch uint `mapstructure:"BackfillBatch"`
+}
+
+// JobsConfig is the configuration of the query jobs, the admin queries run asynchronously for the exports and the
+// scans too long for a request. The jobs and their results are stored in the database, so any instance of the
+// service serves them
+type JobsConfig struct {
+	// MaxConcurrent is the max number of jobs running at the same time in each instance. 0 disables the jobs
+	MaxConcurrent int `mapstructure:"MaxConcurrent"`
+	// MaxRows is the max number of rows of the result of a job
+	MaxRows uint `mapstructure:"MaxRows"`
+	// Timeout is the statement timeout of the query of a job
+	Timeout types.Duration `mapstructure:"Timeout"`
+	// TTL is the time the jobs and their results are kept after their submission
+	TTL types.Duration `mapstructure:"TTL"`
+}
+
+// ContractsConfig is the configuration of the address book of the contracts, with their code hash and the official
+// deployments they match
+type ContractsConfig struct {
+	// CheckInterval is the time the code hashes of the contracts are served before they are read again
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
+	// CheckTimeout is the max time the codes of the contracts are read, the check doesn't depend on the request that
+	// triggers it
+	CheckTimeout types.Duration `mapstructure:"CheckTimeout"`
+	// Deployments are the official deployments added to the ones of the known networks, e.g. of a private network
+	Deployments []Deployment `mapstructure:"Deployments"`
+}
+
+// TokenListConfig is the configuration of the allow and deny lists of tokens. The lists are changed with the admin
+// endpoints and stored in the database, so they apply to every instance of the service without a restart
+type TokenListConfig struct {
+	// RefreshInterval is the time each instance caches the lists
+	RefreshInterval types.Duration `mapstructure:"RefreshInterval"`
+}
+
+// ConfigApprovalsConfig is the configuration of the two-person rule of the runtime settings: an admin proposes a
+// change of the proof access or the token lists, and another admin approves it before it's applied
+type ConfigApprovalsConfig struct {
+	// Enabled rejects the changes of the runtime settings not approved by a second admin
+	Enabled bool `mapstructure:"Enabled"`
+	// Admins are the admins proposing and approving the changes, identified by their token
+	Admins []AdminConfig `mapstructure:"Admins"`
+	// TTL is the time a proposed change can be approved before it expires
+	TTL types.Duration `mapstructure:"TTL"`
+}
+
+// AdminConfig is an admin identified by its token in the X-Admin-Token header
+type AdminConfig struct {
+	// Name identifies the admin in the proposed changes
+	Name string `mapstructure:"Name"`
+	// Token is the token of the admin, it also calls the rest of the admin endpoints
+	Token string `mapstructure:"Token"`
+}
+
+// ProofAccessConfig is the configuration of the mode where the proofs are only served to the allowed consumers, e.g.
+// the relayers of the operator during an incident. The mode and the consumers are changed with the admin endpoints
+// and stored in the database, so they apply to every instance of the service without a restart
+type ProofAccessConfig struct {
+	// Restricted restricts the proofs until an operator changes the mode with the admin endpoint
+	Restricted bool `mapstructure:"Restricted"`
+	// RefreshInterval is the time each instance caches the mode and the consumers
+	RefreshInterval types.Duration `mapstructure:"RefreshInterval"`
+}
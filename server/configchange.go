@@ -0,0 +1,303 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxConfigChangeReasonLength is the max length in bytes of the reason of a proposed change
+	maxConfigChangeReasonLength = 256
+	// maxAdminNameLength is the max length in bytes of the name of an admin
+	maxAdminNameLength = 64
+)
+
+// approvedChangeKey is the context key of the method of the change approved by a second admin being applied.
+type approvedChangeKey struct{}
+
+// configChangeMethod is an admin endpoint changing the runtime settings, with the decoding of its json request.
+type configChangeMethod struct {
+	decode func(request string) (proto.Message, error)
+	apply  func(s *bridgeService, ctx context.Context, req proto.Message) (proto.Message, error)
+}
+
+// approvable returns the configChangeMethod of an admin endpoint.
+func approvable[Req, Res proto.Message](method func(*bridgeService, context.Context, Req) (Res, error)) configChangeMethod {
+	return configChangeMethod{
+		decode: func(request string) (proto.Message, error) {
+			var zero Req
+			req := zero.ProtoReflect().New().Interface()
+			if err := protojson.Unmarshal([]byte(request), req); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+			}
+			return req, nil
+		},
+		apply: func(s *bridgeService, ctx context.Context, req proto.Message) (proto.Message, error) {
+			res, err := method(s, ctx, req.(Req))
+			if err != nil {
+				return nil, err
+			}
+			return res, nil
+		},
+	}
+}
+
+// configChangeMethods are the admin endpoints changing the runtime settings, applied with the two-person rule.
+var configChangeMethods = map[string]configChangeMethod{
+	"SetProofAccess":       approvable((*bridgeService).SetProofAccess),
+	"AddProofConsumer":     approvable((*bridgeService).AddProofConsumer),
+	"DeleteProofConsumer":  approvable((*bridgeService).DeleteProofConsumer),
+	"SetTokenListEntry":    approvable((*bridgeService).SetTokenListEntry),
+	"DeleteTokenListEntry": approvable((*bridgeService).DeleteTokenListEntry),
+	"RotateSigningKey":     approvable((*bridgeService).RotateSigningKey),
+	"SetMaintenance":       approvable((*bridgeService).SetMaintenance),
+}
+
+// Validate checks the admins and the TTL of the changes.
+func (c ConfigApprovalsConfig) Validate() error {
+	if len(c.Admins) < 2 {
+		return errors.New("the two-person rule needs at least 2 admins")
+	}
+	names := make(map[string]bool, len(c.Admins))
+	tokens := make(map[string]bool, len(c.Admins))
+	for _, admin := range c.Admins {
+		if admin.Name == "" || len(admin.Name) > maxAdminNameLength {
+			return fmt.Errorf("the name of an admin must have 1 to %d bytes", maxAdminNameLength)
+		}
+		if names[admin.Name] {
+			return fmt.Errorf("duplicated admin %s", admin.Name)
+		}
+		if admin.Token == "" || tokens[admin.Token] {
+			return fmt.Errorf("the admin %s needs its own token", admin.Name)
+		}
+		names[admin.Name], tokens[admin.Token] = true, true
+	}
+	if c.TTL.Duration <= 0 {
+		return errors.New("the changes need a TTL")
+	}
+	return nil
+}
+
+// checkConfigApproval checks that a change of the runtime settings was approved by a second admin, if the
+// two-person rule is enabled.
+func (s *bridgeService) checkConfigApproval(ctx context.Context, method string) error {
+	if !s.configApprovals.Enabled {
+		return nil
+	}
+	if approved, _ := ctx.Value(approvedChangeKey{}).(string); approved == method {
+		return nil
+	}
+	return status.Errorf(codes.FailedPrecondition, "%s needs the approval of a second admin, propose the change with POST /admin/config-changes", method)
+}
+
+// configChangeStatus returns the status of a change, expired if it's still pending after its TTL.
+func configChangeStatus(c *etherman.ConfigChange, now time.Time) string {
+	if c.Status == etherman.ConfigChangePending && !now.Before(c.ExpiresAt) {
+		return etherman.ConfigChangeExpired
+	}
+	return c.Status
+}
+
+func pbConfigChange(c *etherman.ConfigChange, now time.Time) *pb.ConfigChange {
+	change := &pb.ConfigChange{
+		Id:        c.ID,
+		Method:    c.Method,
+		Request:   c.Request,
+		Reason:    c.Reason,
+		Proposer:  c.Proposer,
+		Status:    configChangeStatus(c, now),
+		CreatedAt: unixTime(c.CreatedAt),
+		ExpiresAt: unixTime(c.ExpiresAt),
+		Reviewer:  c.Reviewer,
+		Error:     c.Error,
+	}
+	if c.ReviewedAt != nil {
+		change.ReviewedAt = unixTime(*c.ReviewedAt)
+	}
+	return change
+}
+
+// pendingConfigChange returns a change that can be reviewed.
+func (s *bridgeService) pendingConfigChange(ctx context.Context, id uint64, now time.Time) (*etherman.ConfigChange, error) {
+	change, err := s.storage.GetConfigChange(ctx, id, nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return nil, status.Errorf(codes.NotFound, "unknown config change %d", id)
+	} else if err != nil {
+		return nil, err
+	}
+	if st := configChangeStatus(change, now); st != etherman.ConfigChangePending {
+		return nil, status.Errorf(codes.FailedPrecondition, "the config change %d is %s", id, st)
+	}
+	return change, nil
+}
+
+// reviewConfigChange stores the review of a pending change, failing if it was reviewed at the same time.
+func (s *bridgeService) reviewConfigChange(ctx context.Context, change *etherman.ConfigChange, st, reviewer string, now time.Time) error {
+	err := s.storage.ReviewConfigChange(ctx, change.ID, st, reviewer, now, nil)
+	if errors.Is(err, gerror.ErrAlreadyProcessed) {
+		return status.Errorf(codes.FailedPrecondition, "the config change %d is not pending anymore", change.ID)
+	} else if err != nil {
+		return err
+	}
+	change.Status, change.Reviewer, change.ReviewedAt = st, reviewer, &now
+	return nil
+}
+
+// applyConfigChange calls the method of an approved change.
+func (s *bridgeService) applyConfigChange(ctx context.Context, method configChangeMethod, change *etherman.ConfigChange) (proto.Message, error) {
+	req, err := method.decode(change.Request)
+	if err != nil {
+		return nil, err
+	}
+	return method.apply(s, context.WithValue(ctx, approvedChangeKey{}, change.Method), req)
+}
+
+// ProposeConfigChange proposes a change of the runtime settings, applied when another admin approves it. Bridge
+// rest API admin endpoint
+func (s *bridgeService) ProposeConfigChange(ctx context.Context, req *pb.ProposeConfigChangeRequest) (*pb.ProposeConfigChangeResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	proposer, err := s.adminName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	method, found := configChangeMethods[req.Method]
+	if !found {
+		return nil, status.Errorf(codes.InvalidArgument, "the method %q doesn't change the runtime settings", req.Method)
+	}
+	if len(req.Reason) > maxConfigChangeReasonLength {
+		return nil, status.Errorf(codes.InvalidArgument, "the reason must have at most %d bytes", maxConfigChangeReasonLength)
+	}
+	// The request is stored in its canonical form
+	decoded, err := method.decode(req.Request)
+	if err != nil {
+		return nil, err
+	}
+	request, err := protojson.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+	return idempotent(ctx, s, "ProposeConfigChange", req, func() (*pb.ProposeConfigChangeResponse, error) {
+		now := time.Now()
+		change := &etherman.ConfigChange{
+			Method:    req.Method,
+			Request:   string(request),
+			Reason:    req.Reason,
+			Proposer:  proposer,
+			Status:    etherman.ConfigChangePending,
+			CreatedAt: now,
+			ExpiresAt: now.Add(s.configApprovals.TTL.Duration),
+		}
+		if err := s.storage.AddConfigChange(ctx, change, nil); err != nil {
+			return nil, err
+		}
+		log.Warnf("config change %d proposed by %s: %s %s, reason: %s", change.ID, proposer, change.Method, change.Request, change.Reason)
+		return &pb.ProposeConfigChangeResponse{Change: pbConfigChange(change, now)}, nil
+	})
+}
+
+// ApproveConfigChange approves a change proposed by another admin, applying it. The response of the method is only
+// returned to the approver. Bridge rest API admin endpoint
+func (s *bridgeService) ApproveConfigChange(ctx context.Context, req *pb.ApproveConfigChangeRequest) (*pb.ApproveConfigChangeResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	approver, err := s.adminName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	change, err := s.pendingConfigChange(ctx, req.Id, now)
+	if err != nil {
+		return nil, err
+	}
+	if change.Proposer == approver {
+		return nil, status.Errorf(codes.PermissionDenied, "the config change %d must be approved by another admin than its proposer", change.ID)
+	}
+	method, found := configChangeMethods[change.Method]
+	if !found {
+		return nil, status.Errorf(codes.FailedPrecondition, "the method %s of the config change %d is unknown", change.Method, change.ID)
+	}
+	if err := s.reviewConfigChange(ctx, change, etherman.ConfigChangeApproved, approver, now); err != nil {
+		return nil, err
+	}
+	res, applyErr := s.applyConfigChange(ctx, method, change)
+	change.Status = etherman.ConfigChangeApplied
+	if applyErr != nil {
+		change.Status, change.Error = etherman.ConfigChangeFailed, applyErr.Error()
+	}
+	if err := s.storage.SetConfigChangeResult(ctx, change.ID, change.Status, change.Error, nil); err != nil {
+		log.Errorf("error storing the result of the config change %d %s: %v", change.ID, change.Status, err)
+	}
+	if applyErr != nil {
+		log.Warnf("config change %d proposed by %s and approved by %s failed: %v", change.ID, change.Proposer, approver, applyErr)
+		return nil, applyErr
+	}
+	log.Warnf("config change %d proposed by %s applied, approved by %s", change.ID, change.Proposer, approver)
+	result, err := protojson.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ApproveConfigChangeResponse{Change: pbConfigChange(change, now), Result: string(result)}, nil
+}
+
+// RejectConfigChange rejects a proposed change, or withdraws it by its proposer. Bridge rest API admin endpoint
+func (s *bridgeService) RejectConfigChange(ctx context.Context, req *pb.RejectConfigChangeRequest) (*pb.RejectConfigChangeResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	reviewer, err := s.adminName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	change, err := s.pendingConfigChange(ctx, req.Id, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reviewConfigChange(ctx, change, etherman.ConfigChangeRejected, reviewer, now); err != nil {
+		return nil, err
+	}
+	log.Warnf("config change %d proposed by %s rejected by %s", change.ID, change.Proposer, reviewer)
+	return &pb.RejectConfigChangeResponse{Change: pbConfigChange(change, now)}, nil
+}
+
+// GetConfigChanges returns the proposed changes of the runtime settings, the last first. Bridge rest API admin
+// endpoint
+func (s *bridgeService) GetConfigChanges(ctx context.Context, req *pb.GetConfigChangesRequest) (*pb.GetConfigChangesResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	switch req.Status {
+	case "", etherman.ConfigChangePending, etherman.ConfigChangeApproved, etherman.ConfigChangeApplied,
+		etherman.ConfigChangeFailed, etherman.ConfigChangeRejected, etherman.ConfigChangeExpired:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown config change status %q", req.Status)
+	}
+	limit, err := s.pageLimit(ctx, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	changes, err := s.storage.GetConfigChanges(ctx, req.Status, now, uint(limit), uint(req.Offset), nil)
+	if err != nil {
+		return nil, err
+	}
+	pbChanges := make([]*pb.ConfigChange, 0, len(changes))
+	for i := range changes {
+		pbChanges = append(pbChanges, pbConfigChange(&changes[i], now))
+	}
+	return &pb.GetConfigChangesResponse{Changes: pbChanges}, nil
+}
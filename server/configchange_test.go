@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestConfigChanges(t *testing.T) {
+	bench, _ := newBenchStorage(4, 0)
+	storage := newMemStorage(bench)
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, AdminToken: "admin",
+		TokenList: TokenListConfig{RefreshInterval: types.NewDuration(time.Minute)},
+		ConfigApprovals: ConfigApprovalsConfig{
+			Enabled: true,
+			Admins:  []AdminConfig{{Name: "alice", Token: "alice-token"}, {Name: "bob", Token: "bob-token"}},
+			TTL:     types.NewDuration(time.Hour),
+		}}
+	require.NoError(t, cfg.ConfigApprovals.Validate())
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	adminCtx := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, token))
+	}
+	admin, alice, bob := adminCtx("admin"), adminCtx("alice-token"), adminCtx("bob-token")
+	dai := "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+
+	// The settings are not changed directly with the two-person rule, and the tokens of the admins call the rest
+	// of the admin endpoints
+	_, err := s.SetTokenListEntry(alice, &pb.SetTokenListEntryRequest{OrigAddr: dai, List: "deny", Operator: "alice"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = s.GetTokenList(bob, &pb.GetTokenListRequest{})
+	require.NoError(t, err)
+
+	// The changes are proposed by a named admin, with a method changing the settings and a valid request
+	request := `{"origAddr": "` + dai + `", "list": "deny", "operator": "alice"}`
+	_, err = s.ProposeConfigChange(admin, &pb.ProposeConfigChangeRequest{Method: "SetTokenListEntry", Request: request})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.ProposeConfigChange(alice, &pb.ProposeConfigChangeRequest{Method: "RunAdminQuery", Request: "{}"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.ProposeConfigChange(alice, &pb.ProposeConfigChangeRequest{Method: "SetTokenListEntry", Request: `{"token": 1}`})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	proposed, err := s.ProposeConfigChange(alice, &pb.ProposeConfigChangeRequest{Method: "SetTokenListEntry", Request: request, Reason: "exploit"})
+	require.NoError(t, err)
+	require.Equal(t, "pending", proposed.Change.Status)
+	require.Equal(t, "alice", proposed.Change.Proposer)
+
+	// The proposer can't approve its own change, another admin applies it
+	_, err = s.ApproveConfigChange(alice, &pb.ApproveConfigChangeRequest{Id: proposed.Change.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Empty(t, storage.tokenList)
+	approved, err := s.ApproveConfigChange(bob, &pb.ApproveConfigChangeRequest{Id: proposed.Change.Id})
+	require.NoError(t, err)
+	require.Equal(t, "applied", approved.Change.Status)
+	require.Equal(t, "bob", approved.Change.Reviewer)
+	require.Equal(t, "{}", approved.Result)
+	require.Len(t, storage.tokenList, 1)
+	require.Equal(t, etherman.TokenListDeny, storage.tokenList[0].List)
+	_, err = s.ApproveConfigChange(bob, &pb.ApproveConfigChangeRequest{Id: proposed.Change.Id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = s.ApproveConfigChange(bob, &pb.ApproveConfigChangeRequest{Id: 42})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// The failures of the method are stored in the change
+	proposed, err = s.ProposeConfigChange(bob, &pb.ProposeConfigChangeRequest{Method: "DeleteTokenListEntry", Request: `{"origNet": 1, "origAddr": "` + dai + `", "operator": "bob"}`})
+	require.NoError(t, err)
+	_, err = s.ApproveConfigChange(alice, &pb.ApproveConfigChangeRequest{Id: proposed.Change.Id})
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	failed, err := s.GetConfigChanges(admin, &pb.GetConfigChangesRequest{Status: "failed"})
+	require.NoError(t, err)
+	require.Len(t, failed.Changes, 1)
+	require.Contains(t, failed.Changes[0].Error, "not found")
+
+	// The proposer withdraws its change, and the changes not reviewed in time expire
+	proposed, err = s.ProposeConfigChange(alice, &pb.ProposeConfigChangeRequest{Method: "SetProofAccess", Request: `{"restricted": true}`})
+	require.NoError(t, err)
+	rejected, err := s.RejectConfigChange(alice, &pb.RejectConfigChangeRequest{Id: proposed.Change.Id})
+	require.NoError(t, err)
+	require.Equal(t, "rejected", rejected.Change.Status)
+	proposed, err = s.ProposeConfigChange(alice, &pb.ProposeConfigChangeRequest{Method: "SetProofAccess", Request: `{"restricted": true}`})
+	require.NoError(t, err)
+	storage.configChanges[proposed.Change.Id-1].ExpiresAt = time.Now()
+	_, err = s.ApproveConfigChange(bob, &pb.ApproveConfigChangeRequest{Id: proposed.Change.Id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	changes, err := s.GetConfigChanges(admin, &pb.GetConfigChangesRequest{})
+	require.NoError(t, err)
+	require.Len(t, changes.Changes, 4)
+	require.Equal(t, []string{"expired", "rejected", "failed", "applied"}, []string{changes.Changes[0].Status,
+		changes.Changes[1].Status, changes.Changes[2].Status, changes.Changes[3].Status})
+	_, err = s.GetConfigChanges(admin, &pb.GetConfigChangesRequest{Status: "done"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The two-person rule needs two admins with their own tokens
+	require.Error(t, ConfigApprovalsConfig{Admins: cfg.ConfigApprovals.Admins[:1], TTL: cfg.ConfigApprovals.TTL}.Validate())
+	require.Error(t, ConfigApprovalsConfig{Admins: []AdminConfig{{Name: "alice", Token: "t"}, {Name: "bob", Token: "t"}}, TTL: cfg.ConfigApprovals.TTL}.Validate())
+	require.Error(t, ConfigApprovalsConfig{Admins: cfg.ConfigApprovals.Admins}.Validate())
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Names of the contracts the service interacts with
+const (
+	ContractBridge                = "bridge"
+	ContractGlobalExitRootManager = "global_exit_root_manager"
+	ContractRollupManager         = "rollup_manager"
+)
+
+// Contract is a contract the service interacts with in a network.
+type Contract struct {
+	NetworkID uint
+	// Name is the role of the contract: bridge, global_exit_root_manager or rollup_manager
+	Name    string
+	Address common.Address
+}
+
+// Deployment is an official deployment of a contract.
+type Deployment struct {
+	// Name identifies the deployment, e.g. the network it's part of
+	Name string `mapstructure:"Name"`
+	// Contract is the role of the contract: bridge, global_exit_root_manager or rollup_manager
+	Contract string         `mapstructure:"Contract"`
+	Address  common.Address `mapstructure:"Address"`
+	// CodeHash is the keccak256 of the code of the deployment. Zero only matches the address
+	CodeHash common.Hash `mapstructure:"CodeHash"`
+}
+
+// CodeReader reads the code of the contracts of a network.
+type CodeReader interface {
+	CodeHash(ctx context.Context, addr common.Address) (common.Hash, error)
+}
+
+// contractCheck is the last check of the code of a contract.
+type contractCheck struct {
+	codeHash  common.Hash
+	checkedAt time.Time
+	err       error
+}
+
+// contractBook is the address book of the contracts, with the last check of their code. The codes are read again
+// on the requests after the check interval, so the address book doesn't call the networks on its own.
+type contractBook struct {
+	cfg         ContractsConfig
+	contracts   []Contract
+	deployments []Deployment
+	readers     map[uint]CodeReader
+
+	mu       sync.Mutex
+	checking bool
+	checks   []contractCheck
+	checked  time.Time
+}
+
+// SetContracts sets the contracts of the address book, compared with the official deployments of the known
+// networks and the configured ones. The code of the contracts of each network is read with its reader.
+func (s *bridgeService) SetContracts(contracts []Contract, deployments []Deployment, readers map[uint]CodeReader) {
+	cfg := s.contracts.cfg
+	s.contracts = &contractBook{
+		cfg:         cfg,
+		contracts:   contracts,
+		deployments: append(deployments, cfg.Deployments...),
+		readers:     readers,
+		checks:      make([]contractCheck, len(contracts)),
+	}
+}
+
+// GetContracts returns the contracts the service interacts with in each network, the code hash at their last check
+// and the official deployment they match. Bridge rest API endpoint
+func (s *bridgeService) GetContracts(ctx context.Context, req *pb.GetContractsRequest) (*pb.GetContractsResponse, error) {
+	res := &pb.GetContractsResponse{Contracts: []*pb.Contract{}}
+	checks := s.contracts.check(time.Now())
+	for i, contract := range s.contracts.contracts {
+		res.Contracts = append(res.Contracts, s.contracts.pbContract(contract, checks[i]))
+	}
+	return res, nil
+}
+
+// check returns the last checks of the contracts, reading their code again if the interval has passed. The contracts
+// that fail to be read keep the code hash of their previous check. The codes are read without holding the lock, the
+// other requests get the previous checks meanwhile. They are read with a context of their own, so the cancellation
+// of the request reading them isn't stored as the error of the check served to the others.
+func (b *contractBook) check(now time.Time) []contractCheck {
+	b.mu.Lock()
+	if b.checking || (!b.checked.IsZero() && now.Sub(b.checked) < b.cfg.CheckInterval.Duration) {
+		defer b.mu.Unlock()
+		return b.checks
+	}
+	b.checking = true
+	previous := b.checks
+	b.mu.Unlock()
+
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if b.cfg.CheckTimeout.Duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, b.cfg.CheckTimeout.Duration)
+	}
+	defer cancel()
+	checks := make([]contractCheck, len(b.contracts))
+	for i, contract := range b.contracts {
+		checks[i] = previous[i]
+		reader, found := b.readers[contract.NetworkID]
+		if !found {
+			continue
+		}
+		codeHash, err := reader.CodeHash(ctx, contract.Address)
+		if err != nil {
+			checks[i].err = err
+			continue
+		}
+		checks[i] = contractCheck{codeHash: codeHash, checkedAt: now}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.checks, b.checked, b.checking = checks, now, false
+	return checks
+}
+
+// pbContract returns the contract with its check and the official deployment at its address.
+func (b *contractBook) pbContract(contract Contract, check contractCheck) *pb.Contract {
+	c := &pb.Contract{
+		NetworkId: uint32(contract.NetworkID),
+		Name:      contract.Name,
+		Address:   contract.Address.Hex(),
+	}
+	if check.codeHash != (common.Hash{}) {
+		c.CodeHash = check.codeHash.Hex()
+	}
+	if !check.checkedAt.IsZero() {
+		c.CheckedAt = uint64(check.checkedAt.Unix())
+	}
+	if check.err != nil {
+		c.Error = check.err.Error()
+	}
+	for _, d := range b.deployments {
+		if d.Contract != contract.Name || d.Address != contract.Address {
+			continue
+		}
+		c.Deployment = d.Name
+		// The contract must have code, the one of the deployment when it's known
+		c.Official = check.codeHash != (common.Hash{}) && (d.CodeHash == (common.Hash{}) || d.CodeHash == check.codeHash)
+		if c.Official {
+			break
+		}
+	}
+	return c
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// codeReader returns the code hashes of the contracts by address, counting the reads.
+type codeReader struct {
+	hashes map[common.Address]common.Hash
+	err    error
+	reads  int
+}
+
+func (r *codeReader) CodeHash(ctx context.Context, addr common.Address) (common.Hash, error) {
+	r.reads++
+	if r.err != nil {
+		return common.Hash{}, r.err
+	}
+	if err := ctx.Err(); err != nil {
+		return common.Hash{}, err
+	}
+	return r.hashes[addr], nil
+}
+
+func TestGetContracts(t *testing.T) {
+	ctx := context.Background()
+	bridge := common.HexToAddress("0x1")
+	ger := common.HexToAddress("0x2")
+	l2Bridge := common.HexToAddress("0x3")
+	l1 := &codeReader{hashes: map[common.Address]common.Hash{bridge: common.HexToHash("0xb"), ger: common.HexToHash("0xc")}}
+	l2 := &codeReader{hashes: map[common.Address]common.Hash{}}
+
+	cfg := Config{CacheSize: 100, Contracts: ContractsConfig{
+		CheckInterval: types.NewDuration(time.Hour),
+		CheckTimeout:  types.NewDuration(time.Second),
+		Deployments:   []Deployment{{Name: "private", Contract: ContractBridge, Address: l2Bridge}},
+	}}
+	bench, _ := newBenchStorage(1, 0)
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, bench, nil)
+	s.SetContracts([]Contract{
+		{NetworkID: 0, Name: ContractBridge, Address: bridge},
+		{NetworkID: 0, Name: ContractGlobalExitRootManager, Address: ger},
+		{NetworkID: 1, Name: ContractBridge, Address: l2Bridge},
+	}, []Deployment{
+		{Name: "mainnet", Contract: ContractBridge, Address: bridge, CodeHash: common.HexToHash("0xb")},
+		{Name: "mainnet", Contract: ContractGlobalExitRootManager, Address: ger, CodeHash: common.HexToHash("0xd")},
+	}, map[uint]CodeReader{0: l1, 1: l2})
+
+	// The bridge matches its deployment, the code of the manager doesn't and the L2 bridge has no code
+	resp, err := s.GetContracts(ctx, &pb.GetContractsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Contracts, 3)
+	require.Equal(t, "mainnet", resp.Contracts[0].Deployment)
+	require.True(t, resp.Contracts[0].Official)
+	require.Equal(t, common.HexToHash("0xb").Hex(), resp.Contracts[0].CodeHash)
+	require.NotZero(t, resp.Contracts[0].CheckedAt)
+	require.Equal(t, "mainnet", resp.Contracts[1].Deployment)
+	require.False(t, resp.Contracts[1].Official)
+	require.Equal(t, uint32(1), resp.Contracts[2].NetworkId)
+	require.Equal(t, "private", resp.Contracts[2].Deployment)
+	require.Empty(t, resp.Contracts[2].CodeHash)
+	require.False(t, resp.Contracts[2].Official)
+
+	// The checks are served until the interval passes
+	_, err = s.GetContracts(ctx, &pb.GetContractsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 2, l1.reads)
+
+	// The codes aren't read with the context of the request, its cancellation isn't the error of the check
+	s.contracts.checked = time.Now().Add(-2 * time.Hour)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	resp, err = s.GetContracts(cancelled, &pb.GetContractsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 4, l1.reads)
+	require.Empty(t, resp.Contracts[0].Error)
+	require.True(t, resp.Contracts[0].Official)
+
+	// A failed check keeps the previous code hash
+	l1.err = errors.New("unreachable")
+	checks := s.contracts.check(time.Now().Add(2 * time.Hour))
+	require.Equal(t, 6, l1.reads)
+	c := s.contracts.pbContract(s.contracts.contracts[0], checks[0])
+	require.Equal(t, "unreachable", c.Error)
+	require.Equal(t, common.HexToHash("0xb").Hex(), c.CodeHash)
+	require.True(t, c.Official)
+}
+
+// blockingReader signals the reads of the codes and blocks them until it's released.
+type blockingReader struct {
+	read, release chan struct{}
+}
+
+func (r *blockingReader) CodeHash(ctx context.Context, addr common.Address) (common.Hash, error) {
+	r.read <- struct{}{}
+	<-r.release
+	return common.HexToHash("0xb"), nil
+}
+
+func TestContractsCheckWithoutLock(t *testing.T) {
+	reader := &blockingReader{read: make(chan struct{}), release: make(chan struct{})}
+	cfg := Config{CacheSize: 100, Contracts: ContractsConfig{CheckInterval: types.NewDuration(time.Hour)}}
+	bench, _ := newBenchStorage(1, 0)
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, bench, nil)
+	s.SetContracts([]Contract{{NetworkID: 0, Name: ContractBridge, Address: common.HexToAddress("0x1")}}, nil, map[uint]CodeReader{0: reader})
+
+	checked := make(chan []contractCheck)
+	go func() {
+		checked <- s.contracts.check(time.Now())
+	}()
+	<-reader.read
+	// The other requests get the previous checks while the codes are read
+	checks := s.contracts.check(time.Now())
+	require.Zero(t, checks[0].codeHash)
+	close(reader.release)
+	require.Equal(t, common.HexToHash("0xb"), (<-checked)[0].codeHash)
+	require.Equal(t, common.HexToHash("0xb"), s.contracts.check(time.Now())[0].codeHash)
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/statusrules"
+)
+
+// SetStatusRules sets the compiled status rules of the operator, the display statuses of the deposits.
+func (s *bridgeService) SetStatusRules(rules *statusrules.Rules) {
+	s.statusRules = rules
+}
+
+// fillDisplayStatuses sets the display statuses and the ready ETAs of the deposits. They depend on the age of the
+// deposits and on the measured injection delays, so they are evaluated on every response instead of being cached
+// with the deposits.
+func (s *bridgeService) fillDisplayStatuses(deposits []*pb.Deposit) {
+	now := time.Now()
+	for _, deposit := range deposits {
+		deposit.ReadyEta = s.readyETA(deposit)
+		if s.statusRules != nil {
+			deposit.DisplayStatus = s.statusRules.Status(ruleState(deposit, now))
+		}
+	}
+}
+
+// readyETA returns when the L1 deposit is expected to be ready for claim, 0 if it's unknown. The global exit root
+// is usually updated by the deposit in its block, so it's ready after the injection delay of the destination network.
+func (s *bridgeService) readyETA(deposit *pb.Deposit) uint64 {
+	if deposit.NetworkId != 0 || deposit.ReadyForClaim || deposit.BlockTime == 0 || s.gerLatency == nil {
+		return 0
+	}
+	eta, ok := s.gerLatency.ExpectedInjection(uint(deposit.DestNet), time.Unix(int64(deposit.BlockTime), 0))
+	if !ok {
+		return 0
+	}
+	return uint64(eta.Unix())
+}
+
+// ruleState returns the state of a deposit the status rules are evaluated on.
+func ruleState(deposit *pb.Deposit, now time.Time) *statusrules.State {
+	state := &statusrules.State{
+		Status:             etherman.DepositStatusIndexed,
+		LeafType:           uint(deposit.LeafType),
+		NetworkID:          uint(deposit.NetworkId),
+		DepositCount:       uint(deposit.DepositCnt),
+		OriginalNetwork:    uint(deposit.OrigNet),
+		OriginalAddress:    deposit.OrigAddr,
+		DestinationNetwork: uint(deposit.DestNet),
+		DestinationAddress: deposit.DestAddr,
+		Integration:        deposit.Integration,
+	}
+	if deposit.ClaimTxHash != "" {
+		state.Status = etherman.DepositStatusClaimed
+	} else if deposit.ReadyForClaim {
+		state.Status = etherman.DepositStatusReadyForClaim
+	}
+	state.Amount, _ = new(big.Int).SetString(deposit.Amount, 10) //nolint:gomnd
+	if deposit.BlockTime != 0 {
+		state.Age = now.Sub(time.Unix(int64(deposit.BlockTime), 0))
+	}
+	return state
+}
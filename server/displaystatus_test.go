@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/gerlatency"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/statusrules"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayStatus(t *testing.T) {
+	ctx := context.Background()
+	bench, _ := newBenchStorage(4, 0)
+	storage := newMemStorage(bench)
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	addr := "0xc949254d682d8c9ad5682521675b8f43b102aec4"
+
+	// Without rules there is no display status
+	resp, err := s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr})
+	require.NoError(t, err)
+	for _, deposit := range resp.Deposits {
+		require.Empty(t, deposit.DisplayStatus)
+	}
+
+	rules, err := statusrules.Compile([]statusrules.Rule{
+		{Status: "Delivered", When: `status == "claimed"`},
+		{Status: "Ready to claim", When: `status == "ready_for_claim" && dest_addr == "` + addr + `"`},
+	})
+	require.NoError(t, err)
+	s.SetStatusRules(rules)
+
+	// The even deposits of the bench storage are claimed
+	resp, err = s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr})
+	require.NoError(t, err)
+	require.Len(t, resp.Deposits, 4)
+	for _, deposit := range resp.Deposits {
+		if deposit.DepositCnt%2 == 0 {
+			require.Equal(t, "Delivered", deposit.DisplayStatus)
+		} else {
+			require.Equal(t, "Ready to claim", deposit.DisplayStatus)
+		}
+	}
+	bridge, err := s.GetBridge(ctx, &pb.GetBridgeRequest{NetId: 0, DepositCnt: 1})
+	require.NoError(t, err)
+	require.Equal(t, "Ready to claim", bridge.Deposit.DisplayStatus)
+
+	// The age is the time since the block of the deposit
+	now := time.Unix(1700000000, 0)
+	state := ruleState(&pb.Deposit{Amount: "10", BlockTime: uint64(now.Add(-time.Hour).Unix())}, now)
+	require.Equal(t, "indexed", state.Status)
+	require.Equal(t, time.Hour, state.Age)
+	require.Equal(t, int64(10), state.Amount.Int64())
+}
+
+func TestReadyETA(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start.Add(-time.Hour)
+	tracker := gerlatency.NewTracker(func() time.Time { return now })
+	s := &bridgeService{gerLatency: tracker}
+	deposit := &pb.Deposit{DestNet: 1, BlockTime: uint64(start.Unix())}
+
+	// There is no ETA until an injection is measured in the destination network
+	require.Zero(t, s.readyETA(deposit))
+
+	tracker.L1Synced()
+	tracker.Synced(1)
+	tracker.L1Updated(common.HexToHash("0x01"), now)
+	now = now.Add(90 * time.Second)
+	tracker.Injected(1, common.HexToHash("0x01"))
+	require.Equal(t, uint64(start.Add(90*time.Second).Unix()), s.readyETA(deposit))
+
+	// The ready deposits and the L2 ones have no ETA
+	require.Zero(t, s.readyETA(&pb.Deposit{DestNet: 1, BlockTime: uint64(start.Unix()), ReadyForClaim: true}))
+	require.Zero(t, s.readyETA(&pb.Deposit{NetworkId: 1, BlockTime: uint64(start.Unix())}))
+}
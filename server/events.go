@@ -0,0 +1,357 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/changefeed"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Types of the bridge events
+const (
+	bridgeEventDeposit       = "deposit"
+	bridgeEventReadyForClaim = "ready_for_claim"
+	bridgeEventClaim         = "claim"
+	bridgeEventResync        = "resync"
+)
+
+// States of the batch of a L2 deposit, streamed before the deposit is ready for claim
+const (
+	depositStatusVirtualized = "virtualized"
+	depositStatusVerified    = "verified"
+)
+
+// SubscribeBridgeEvents streams the new deposits, the deposits ready for claim and the claims of the address, as the
+// change feed notifies them, until the client cancels the stream. When events may have been lost, after a reorg,
+// a reconnection of the feed or with the buffer of the subscription full, a resync event is sent instead.
+// Bridge rest API endpoint
+func (s *bridgeService) SubscribeBridgeEvents(req *pb.SubscribeBridgeEventsRequest, stream pb.BridgeService_SubscribeBridgeEventsServer) error {
+	if !common.IsHexAddress(req.Address) {
+		return status.Errorf(codes.InvalidArgument, "invalid address %s", req.Address)
+	}
+	if err := s.acquireStream(); err != nil {
+		return err
+	}
+	defer func() { <-s.eventSlots }()
+
+	ctx := stream.Context()
+	addr := common.HexToAddress(req.Address)
+	sub := s.changes.Subscribe()
+	defer sub.Close()
+	var dropped uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change := <-sub.Changes():
+			events := s.bridgeEvents(ctx, addr, change)
+			// The changes dropped since the last one may have had events of the address
+			if d := sub.Dropped(); d != dropped {
+				dropped = d
+				events = []*pb.BridgeEvent{{Type: bridgeEventResync}}
+			}
+			for _, event := range events {
+				if err := stream.Send(event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// SetBatchReaders sets the readers of the batches of the L2 networks, indexed by networkID, streaming the states of
+// the batches of their deposits.
+func (s *bridgeService) SetBatchReaders(readers map[uint]BatchReader) {
+	s.batchReaders = readers
+}
+
+// acquireStream takes a slot of the streams, released by the caller when the stream ends.
+func (s *bridgeService) acquireStream() error {
+	if s.changes == nil || cap(s.eventSlots) == 0 {
+		return status.Error(codes.Unimplemented, "the streams are not enabled")
+	}
+	select {
+	case s.eventSlots <- struct{}{}:
+		return nil
+	default:
+		return status.Errorf(codes.ResourceExhausted, "%d streams already served", cap(s.eventSlots))
+	}
+}
+
+// StreamDepositStatus streams the changes of status of the deposit: the changes of its status history first, and
+// then the next ones as the change feed notifies them, until it's claimed or the client cancels the stream. The
+// batch of a L2 deposit is read from the node of its network every BatchPollInterval, or the faster interval of the
+// watched addresses for the deposits to them, streaming when it's virtualized and verified. The stream of an unknown
+// deposit waits for its indexing. If a reorg removes changes already streamed, the stream fails with Aborted, and
+// the client streams the deposit again.
+// Bridge rest API endpoint
+func (s *bridgeService) StreamDepositStatus(req *pb.StreamDepositStatusRequest, stream pb.BridgeService_StreamDepositStatusServer) error {
+	if err := s.acquireStream(); err != nil {
+		return err
+	}
+	defer func() { <-s.eventSlots }()
+
+	ctx := stream.Context()
+	networkID, depositCnt := uint(req.NetId), uint(req.DepositCnt)
+	// The subscription starts before the history is read, so the changes meanwhile are not missed
+	sub := s.changes.Subscribe()
+	defer sub.Close()
+	var (
+		sent    []etherman.DepositStatusChange
+		deposit *etherman.Deposit
+		dropped uint64
+		batch   *batchProgress
+		poll    <-chan time.Time
+	)
+	var ticker *time.Ticker
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
+	for {
+		if deposit == nil {
+			d, err := s.storage.GetDeposit(ctx, depositCnt, networkID, nil)
+			if err == nil {
+				deposit = d
+				if batch = s.newBatchProgress(deposit); batch != nil {
+					ticker = time.NewTicker(s.batchPollInterval(ctx, deposit))
+					poll = ticker.C
+				}
+			} else if !errors.Is(err, gerror.ErrStorageNotFound) {
+				return err
+			}
+		}
+		history, err := s.storage.GetDepositStatusHistory(ctx, depositCnt, networkID, nil)
+		if err != nil {
+			return err
+		}
+		if !isStatusPrefix(sent, history) {
+			return status.Errorf(codes.Aborted, "the status history of the deposit %d of the network %d changed with a reorg", depositCnt, networkID)
+		}
+		for _, c := range history[len(sent):] {
+			// The batch of a L2 deposit is verified before the deposit is ready for claim
+			if batch != nil && c.Status != etherman.DepositStatusIndexed {
+				if err := batch.send(ctx, stream, true); err != nil {
+					return err
+				}
+			}
+			if err := stream.Send(statusChangeToPb(c)); err != nil {
+				return err
+			}
+			sent = append(sent, c)
+			// The claimed deposits don't change anymore
+			if c.Status == etherman.DepositStatusClaimed {
+				return nil
+			}
+		}
+		if batch != nil && len(sent) > 0 {
+			if err := batch.send(ctx, stream, false); err != nil {
+				return err
+			}
+			if batch.done() {
+				poll = nil
+			}
+		}
+		// The history is read again when the deposit or its claim change, or when the changes may have been lost,
+		// and the batch on each poll
+		for changed := false; !changed; {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-poll:
+				changed = true
+			case change := <-sub.Changes():
+				d := sub.Dropped()
+				changed = d != dropped || isDepositChange(change, networkID, depositCnt, deposit)
+				dropped = d
+			}
+		}
+	}
+}
+
+// batchProgress is the batch of a L2 deposit whose status is streamed, with the states already sent.
+type batchProgress struct {
+	reader   BatchReader
+	blockNum uint64
+	batchNum uint64
+	found    bool
+	// sent is the number of states sent, 1 when virtualized and 2 when verified
+	sent int
+}
+
+// newBatchProgress returns the batch of a L2 deposit, nil for the L1 deposits and without node of the network.
+func (s *bridgeService) newBatchProgress(deposit *etherman.Deposit) *batchProgress {
+	reader, found := s.batchReaders[deposit.NetworkID]
+	if deposit.NetworkID == 0 || !found || s.batchPoll <= 0 {
+		return nil
+	}
+	return &batchProgress{reader: reader, blockNum: deposit.BlockNumber}
+}
+
+// batchPollInterval returns the time between the reads of the batch of a L2 deposit, the faster interval of the
+// watched addresses if the deposit is to one of them. The deposit is polled as the others if it can't be checked.
+func (s *bridgeService) batchPollInterval(ctx context.Context, deposit *etherman.Deposit) time.Duration {
+	watchPoll := s.watch.BatchPollInterval.Duration
+	if watchPoll <= 0 || watchPoll >= s.batchPoll {
+		return s.batchPoll
+	}
+	watched, err := s.storage.IsWatchedAddress(ctx, deposit.DestinationAddress, nil)
+	if err != nil {
+		log.Warnf("deposit status: error checking if the address %s is watched: %v", deposit.DestinationAddress.Hex(), err)
+		return s.batchPoll
+	}
+	if watched {
+		return watchPoll
+	}
+	return s.batchPoll
+}
+
+// done returns if the batch is verified.
+func (b *batchProgress) done() bool {
+	return b.sent == len(batchStates)
+}
+
+// batchStates are the states of a batch, in order
+var batchStates = []string{depositStatusVirtualized, depositStatusVerified}
+
+// send streams the states of the batch reached since the last call. The states are read from the node of the
+// network, unless the batch is known to be verified. The states that can't be read are read again on the next poll,
+// and the batch number is 0 if it's still unknown when the batch is verified.
+func (b *batchProgress) send(ctx context.Context, stream pb.BridgeService_StreamDepositStatusServer, verified bool) error {
+	if b.done() {
+		return nil
+	}
+	if !b.found {
+		batchNum, err := b.reader.BatchNumberByBlockNumber(ctx, b.blockNum)
+		if err == nil {
+			b.batchNum, b.found = batchNum, true
+		} else if !errors.Is(err, ethereum.NotFound) {
+			log.Warnf("deposit status: error getting the batch of the L2 block %d: %v", b.blockNum, err)
+		}
+	}
+	reached := len(batchStates)
+	if !verified {
+		if !b.found {
+			return nil
+		}
+		var err error
+		if reached, err = b.reached(ctx); err != nil {
+			log.Warnf("deposit status: error getting the state of the batch %d: %v", b.batchNum, err)
+			return nil
+		}
+	}
+	for ; b.sent < reached; b.sent++ {
+		change := &pb.DepositStatusChange{Status: batchStates[b.sent], BatchNum: b.batchNum, Time: uint64(time.Now().Unix())}
+		if err := stream.Send(change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reached returns the number of states reached by the batch.
+func (b *batchProgress) reached(ctx context.Context) (int, error) {
+	verified, err := b.reader.VerifiedBatchNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if verified >= b.batchNum {
+		return len(batchStates), nil
+	}
+	virtual, err := b.reader.VirtualBatchNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if virtual >= b.batchNum {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// isStatusPrefix returns if the changes sent are the first changes of the history.
+func isStatusPrefix(sent, history []etherman.DepositStatusChange) bool {
+	if len(sent) > len(history) {
+		return false
+	}
+	for i, c := range sent {
+		h := history[i]
+		if c.Status != h.Status || c.BlockID != h.BlockID || c.NetworkID != h.NetworkID || c.BlockNumber != h.BlockNumber || !c.Time.Equal(h.Time) {
+			return false
+		}
+	}
+	return true
+}
+
+func statusChangeToPb(c etherman.DepositStatusChange) *pb.DepositStatusChange {
+	return &pb.DepositStatusChange{
+		Status:    c.Status,
+		NetworkId: uint32(c.NetworkID),
+		BlockNum:  c.BlockNumber,
+		Time:      uint64(c.Time.Unix()),
+	}
+}
+
+// bridgeEvents returns the events of the address for the change. The deposits and claims are read from the
+// storage, the changes that can't be read are skipped.
+func (s *bridgeService) bridgeEvents(ctx context.Context, addr common.Address, change changefeed.Change) []*pb.BridgeEvent {
+	switch change.Table {
+	case changefeed.TableDeposit:
+		if change.DestinationAddress != addr && change.Sender != addr && change.Depositor != addr {
+			return nil
+		}
+		if change.Operation == "DELETE" {
+			return []*pb.BridgeEvent{{Type: bridgeEventResync}}
+		}
+		res, err := s.GetBridge(ctx, &pb.GetBridgeRequest{NetId: uint32(change.NetworkID), DepositCnt: uint64(change.DepositCount)})
+		if err != nil {
+			logEventError(err, "deposit %d of the network %d", change.DepositCount, change.NetworkID)
+			return nil
+		}
+		var events []*pb.BridgeEvent
+		if change.Operation == "INSERT" {
+			events = append(events, &pb.BridgeEvent{Type: bridgeEventDeposit, Deposit: res.Deposit})
+		}
+		// The other updates of a deposit ready for claim don't make it ready again
+		if change.Ready {
+			events = append(events, &pb.BridgeEvent{Type: bridgeEventReadyForClaim, Deposit: res.Deposit})
+		}
+		return events
+	case changefeed.TableClaim:
+		if change.DestinationAddress != addr {
+			return nil
+		}
+		if change.Operation == "DELETE" {
+			return []*pb.BridgeEvent{{Type: bridgeEventResync}}
+		}
+		claim, err := s.storage.GetClaim(ctx, change.DepositCount, change.NetworkID, nil)
+		if err != nil {
+			logEventError(err, "claim %d of the network %d", change.DepositCount, change.NetworkID)
+			return nil
+		}
+		return []*pb.BridgeEvent{{Type: bridgeEventClaim, Claim: s.claimToPb(ctx, claim)}}
+	case changefeed.TableTokenWrapped:
+		return nil
+	default:
+		// The changes may have been lost
+		return []*pb.BridgeEvent{{Type: bridgeEventResync}}
+	}
+}
+
+func logEventError(err error, format string, args ...interface{}) {
+	// The row of the change is not found when it has been reorged meanwhile
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		log.Debugf("bridge events: "+format+" not found: %v", append(args, err)...)
+		return
+	}
+	log.Warnf("bridge events: error reading the "+format+", skipping it: %v", append(args, err)...)
+}
@@ -0,0 +1,313 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/changefeed"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// eventStorage serves the claims of the test, with their amounts, and the status history set by the test.
+type eventStorage struct {
+	*feedStorage
+	mu      sync.Mutex
+	history []etherman.DepositStatusChange
+}
+
+func (s *eventStorage) setHistory(statuses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = nil
+	for i, status := range statuses {
+		s.history = append(s.history, etherman.DepositStatusChange{Status: status, BlockID: uint64(i + 1), BlockNumber: uint64(i + 1), Time: time.Unix(int64(i), 0)})
+	}
+}
+
+func (s *eventStorage) GetDepositStatusHistory(ctx context.Context, depositCnt, networkID uint, dbTx pgx.Tx) ([]etherman.DepositStatusChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]etherman.DepositStatusChange(nil), s.history...), nil
+}
+
+func (s *eventStorage) GetClaim(ctx context.Context, index uint, networkID uint, dbTx pgx.Tx) (*etherman.Claim, error) {
+	return &etherman.Claim{Index: index, NetworkID: networkID, Amount: big.NewInt(1)}, nil
+}
+
+// eventStream receives the events sent to the client.
+type eventStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *pb.BridgeEvent
+}
+
+func (s *eventStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *eventStream) Send(event *pb.BridgeEvent) error {
+	s.events <- event
+	return nil
+}
+
+func TestSubscribeBridgeEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	addr := common.HexToAddress("0xc949254d682d8c9ad5682521675b8f43b102aec4")
+	bench, _ := newBenchStorage(10, 5)
+	storage := &eventStorage{feedStorage: &feedStorage{benchStorage: bench, payloads: make(chan string), known: map[uint]*etherman.Deposit{
+		1: {NetworkID: 0, DepositCount: 1, DestinationNetwork: 1, DestinationAddress: addr, Amount: big.NewInt(1)},
+	}}}
+	s := NewBridgeService(Config{CacheSize: 100, MaxEventSubscribers: 1}, benchHeight, []uint{0, 1}, storage, nil)
+	newStream := func(ctx context.Context) *eventStream {
+		return &eventStream{ctx: ctx, events: make(chan *pb.BridgeEvent, 10)}
+	}
+
+	// Without the feed the events are not streamed
+	err := s.SubscribeBridgeEvents(&pb.SubscribeBridgeEventsRequest{Address: addr.Hex()}, newStream(ctx))
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+
+	feed := changefeed.NewFeed(changefeed.Config{ReplicaWait: types.NewDuration(time.Second), SubscriberBuffer: 10}, storage, storage)
+	go feed.Start(ctx)
+	s.SetChangeFeed(feed)
+
+	err = s.SubscribeBridgeEvents(&pb.SubscribeBridgeEventsRequest{Address: "0x1"}, newStream(ctx))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	streamCtx, stop := context.WithCancel(ctx)
+	stream := newStream(streamCtx)
+	done := make(chan error)
+	go func() {
+		done <- s.SubscribeBridgeEvents(&pb.SubscribeBridgeEventsRequest{Address: addr.Hex()}, stream)
+	}()
+	// The lost changes are sent as resync events, the stream is subscribed once it receives one
+	timeout := time.After(5 * time.Second)
+subscribed:
+	for {
+		select {
+		case storage.payloads <- `{}`:
+		case event := <-stream.events:
+			require.Equal(t, bridgeEventResync, event.Type)
+			break subscribed
+		case <-timeout:
+			t.Fatal("the stream didn't subscribe")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	next := func() *pb.BridgeEvent {
+		for {
+			select {
+			case event := <-stream.events:
+				if event.Type != bridgeEventResync {
+					return event
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no event streamed")
+			}
+		}
+	}
+
+	// The instance serves at most MaxEventSubscribers streams
+	err = s.SubscribeBridgeEvents(&pb.SubscribeBridgeEventsRequest{Address: addr.Hex()}, newStream(ctx))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	storage.payloads <- `{"table": "deposit", "op": "INSERT", "network_id": 0, "deposit_cnt": 1, "dest_addr": "0xc949254d682d8c9ad5682521675b8f43b102aec4"}`
+	event := next()
+	require.Equal(t, bridgeEventDeposit, event.Type)
+	require.Equal(t, uint64(1), event.Deposit.DepositCnt)
+	require.False(t, event.Deposit.ReadyForClaim)
+
+	// The changes of other addresses are skipped
+	storage.payloads <- `{"table": "deposit", "op": "INSERT", "network_id": 0, "deposit_cnt": 2, "dest_addr": "0x0000000000000000000000000000000000000002"}`
+	storage.known[1].ReadyForClaim = true
+	storage.payloads <- `{"table": "deposit", "op": "UPDATE", "network_id": 0, "deposit_cnt": 1, "dest_addr": "0xc949254d682d8c9ad5682521675b8f43b102aec4", "ready": true}`
+	event = next()
+	require.Equal(t, bridgeEventReadyForClaim, event.Type)
+	require.True(t, event.Deposit.ReadyForClaim)
+
+	// The other updates of the deposit ready for claim are skipped
+	storage.payloads <- `{"table": "deposit", "op": "UPDATE", "network_id": 0, "deposit_cnt": 1, "dest_addr": "0xc949254d682d8c9ad5682521675b8f43b102aec4"}`
+
+	storage.payloads <- `{"table": "claim", "op": "INSERT", "network_id": 1, "deposit_cnt": 1, "dest_addr": "0xc949254d682d8c9ad5682521675b8f43b102aec4"}`
+	event = next()
+	require.Equal(t, bridgeEventClaim, event.Type)
+	require.Equal(t, uint64(1), event.Claim.Index)
+	require.Equal(t, uint32(1), event.Claim.NetworkId)
+
+	// The stream ends with the client
+	stop()
+	require.NoError(t, <-done)
+}
+
+// statusStream receives the changes of status sent to the client.
+type statusStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	changes chan *pb.DepositStatusChange
+}
+
+func (s *statusStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *statusStream) Send(change *pb.DepositStatusChange) error {
+	s.changes <- change
+	return nil
+}
+
+func TestStreamDepositStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bench, _ := newBenchStorage(10, 5)
+	storage := &eventStorage{feedStorage: &feedStorage{benchStorage: bench, payloads: make(chan string), known: map[uint]*etherman.Deposit{
+		1: {NetworkID: 0, DepositCount: 1, DestinationNetwork: 1, Amount: big.NewInt(1)},
+	}}}
+	s := NewBridgeService(Config{CacheSize: 100, MaxEventSubscribers: 1}, benchHeight, []uint{0, 1}, storage, nil)
+	feed := changefeed.NewFeed(changefeed.Config{ReplicaWait: types.NewDuration(time.Second), SubscriberBuffer: 10}, storage, storage)
+	go feed.Start(ctx)
+	s.SetChangeFeed(feed)
+
+	start := func() (*statusStream, chan error) {
+		stream := &statusStream{ctx: ctx, changes: make(chan *pb.DepositStatusChange, 10)}
+		done := make(chan error, 1)
+		go func() {
+			done <- s.StreamDepositStatus(&pb.StreamDepositStatusRequest{NetId: 0, DepositCnt: 1}, stream)
+		}()
+		return stream, done
+	}
+	next := func(stream *statusStream) *pb.DepositStatusChange {
+		select {
+		case change := <-stream.changes:
+			return change
+		case <-time.After(5 * time.Second):
+			t.Fatal("no change streamed")
+			return nil
+		}
+	}
+
+	// The past changes are streamed first, the stream is subscribed once it sends them
+	storage.setHistory(etherman.DepositStatusIndexed)
+	stream, done := start()
+	require.Equal(t, etherman.DepositStatusIndexed, next(stream).Status)
+
+	storage.setHistory(etherman.DepositStatusIndexed, etherman.DepositStatusReadyForClaim)
+	storage.payloads <- `{"table": "deposit", "op": "UPDATE", "network_id": 0, "deposit_cnt": 1}`
+	change := next(stream)
+	require.Equal(t, etherman.DepositStatusReadyForClaim, change.Status)
+	require.Equal(t, uint64(2), change.BlockNum)
+
+	// The claim of the deposit, in its destination network, ends the stream
+	storage.setHistory(etherman.DepositStatusIndexed, etherman.DepositStatusReadyForClaim, etherman.DepositStatusClaimed)
+	storage.payloads <- `{"table": "claim", "op": "INSERT", "network_id": 1, "deposit_cnt": 1}`
+	require.Equal(t, etherman.DepositStatusClaimed, next(stream).Status)
+	require.NoError(t, <-done)
+
+	// A reorg of the changes streamed aborts the stream
+	storage.setHistory(etherman.DepositStatusIndexed, etherman.DepositStatusReadyForClaim)
+	stream, done = start()
+	require.Equal(t, etherman.DepositStatusIndexed, next(stream).Status)
+	require.Equal(t, etherman.DepositStatusReadyForClaim, next(stream).Status)
+	storage.setHistory(etherman.DepositStatusIndexed)
+	storage.payloads <- `{"table": "deposit", "op": "DELETE", "network_id": 0, "deposit_cnt": 1}`
+	require.Equal(t, codes.Aborted, status.Code(<-done))
+}
+
+// batchNode is the node of a L2 network whose deposits are in the batch 3.
+type batchNode struct {
+	mu                sync.Mutex
+	virtual, verified uint64
+}
+
+func (n *batchNode) set(virtual, verified uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.virtual, n.verified = virtual, verified
+}
+
+func (n *batchNode) BatchNumberByBlockNumber(ctx context.Context, blockNumber uint64) (uint64, error) {
+	return 3, nil
+}
+
+func (n *batchNode) VirtualBatchNumber(ctx context.Context) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.virtual, nil
+}
+
+func (n *batchNode) VerifiedBatchNumber(ctx context.Context) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.verified, nil
+}
+
+func TestStreamDepositStatusBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bench, _ := newBenchStorage(10, 5)
+	storage := &eventStorage{feedStorage: &feedStorage{benchStorage: bench, payloads: make(chan string), known: map[uint]*etherman.Deposit{
+		1: {NetworkID: 1, DepositCount: 1, DestinationNetwork: 0, BlockNumber: 25, Amount: big.NewInt(1)},
+	}}}
+	cfg := Config{CacheSize: 100, MaxEventSubscribers: 1, BatchPollInterval: types.NewDuration(10 * time.Millisecond)}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	feed := changefeed.NewFeed(changefeed.Config{ReplicaWait: types.NewDuration(time.Second), SubscriberBuffer: 10}, storage, storage)
+	go feed.Start(ctx)
+	s.SetChangeFeed(feed)
+	node := &batchNode{}
+	s.SetBatchReaders(map[uint]BatchReader{1: node})
+
+	start := func() (*statusStream, chan error) {
+		stream := &statusStream{ctx: ctx, changes: make(chan *pb.DepositStatusChange, 10)}
+		done := make(chan error, 1)
+		go func() {
+			done <- s.StreamDepositStatus(&pb.StreamDepositStatusRequest{NetId: 1, DepositCnt: 1}, stream)
+		}()
+		return stream, done
+	}
+	next := func(stream *statusStream) *pb.DepositStatusChange {
+		select {
+		case change := <-stream.changes:
+			return change
+		case <-time.After(5 * time.Second):
+			t.Fatal("no change streamed")
+			return nil
+		}
+	}
+
+	// The states of the batch of the L2 deposit are polled from the node of its network
+	storage.setHistory(etherman.DepositStatusIndexed)
+	stream, done := start()
+	require.Equal(t, etherman.DepositStatusIndexed, next(stream).Status)
+	node.set(3, 2)
+	change := next(stream)
+	require.Equal(t, depositStatusVirtualized, change.Status)
+	require.Equal(t, uint64(3), change.BatchNum)
+	node.set(4, 3)
+	change = next(stream)
+	require.Equal(t, depositStatusVerified, change.Status)
+	require.Equal(t, uint64(3), change.BatchNum)
+
+	storage.setHistory(etherman.DepositStatusIndexed, etherman.DepositStatusReadyForClaim, etherman.DepositStatusClaimed)
+	storage.payloads <- `{"table": "deposit", "op": "UPDATE", "network_id": 1, "deposit_cnt": 1}`
+	require.Equal(t, etherman.DepositStatusReadyForClaim, next(stream).Status)
+	require.Equal(t, etherman.DepositStatusClaimed, next(stream).Status)
+	require.NoError(t, <-done)
+
+	// The batch of a deposit ready for claim is verified, even if the node is behind
+	node.set(0, 0)
+	stream, done = start()
+	for _, status := range []string{etherman.DepositStatusIndexed, depositStatusVirtualized, depositStatusVerified, etherman.DepositStatusReadyForClaim, etherman.DepositStatusClaimed} {
+		require.Equal(t, status, next(stream).Status)
+	}
+	require.NoError(t, <-done)
+}
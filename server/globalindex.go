@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pbGlobalIndex returns the global index of a deposit with its parts, so the clients don't pack it themselves.
+func pbGlobalIndex(networkID, depositCount uint) *pb.GlobalIndex {
+	globalIndex := &pb.GlobalIndex{
+		GlobalIndex: etherman.GlobalIndex(networkID, depositCount).String(),
+		MainnetFlag: networkID == 0,
+		LocalIndex:  uint32(depositCount),
+	}
+	if networkID != 0 {
+		globalIndex.RollupIndex = uint32(networkID - 1)
+	}
+	return globalIndex
+}
+
+// DecodeGlobalIndex returns the parts of a global index and the deposit it identifies. Bridge rest API endpoint
+func (s *bridgeService) DecodeGlobalIndex(ctx context.Context, req *pb.DecodeGlobalIndexRequest) (*pb.DecodeGlobalIndexResponse, error) {
+	globalIndex, ok := new(big.Int).SetString(req.GlobalIndex, 0)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid global index %s", req.GlobalIndex)
+	}
+	mainnetFlag, rollupIndex, localIndex, err := etherman.DecodeGlobalIndex(globalIndex)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &pb.DecodeGlobalIndexResponse{
+		GlobalIndex: &pb.GlobalIndex{
+			GlobalIndex: globalIndex.String(),
+			MainnetFlag: mainnetFlag,
+			RollupIndex: rollupIndex,
+			LocalIndex:  localIndex,
+		},
+		NetworkId:  uint32(etherman.GlobalIndexNetworkID(mainnetFlag, rollupIndex)),
+		DepositCnt: uint64(localIndex),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDecodeGlobalIndex(t *testing.T) {
+	ctx := context.Background()
+	bench, _ := newBenchStorage(1, 0)
+	s := newBenchService(bench)
+
+	// The global indexes of the deposits are decoded back to them
+	for _, deposit := range []struct {
+		networkID, depositCount uint
+	}{{0, 7}, {1, 7}, {3, 1<<32 - 1}} {
+		globalIndex := pbGlobalIndex(deposit.networkID, deposit.depositCount)
+		res, err := s.DecodeGlobalIndex(ctx, &pb.DecodeGlobalIndexRequest{GlobalIndex: globalIndex.GlobalIndex})
+		require.NoError(t, err)
+		require.Equal(t, globalIndex, res.GlobalIndex)
+		require.Equal(t, uint32(deposit.networkID), res.NetworkId)
+		require.Equal(t, uint64(deposit.depositCount), res.DepositCnt)
+	}
+	require.Equal(t, &pb.GlobalIndex{GlobalIndex: "18446744073709551623", MainnetFlag: true, LocalIndex: 7}, pbGlobalIndex(0, 7))
+
+	res, err := s.DecodeGlobalIndex(ctx, &pb.DecodeGlobalIndexRequest{GlobalIndex: "0x10000000000000007"})
+	require.NoError(t, err)
+	require.True(t, res.GlobalIndex.MainnetFlag)
+	require.Equal(t, uint64(7), res.DepositCnt)
+
+	for _, invalid := range []string{"", "index", "-1", "0x20000000000000000", "0x10000000100000007"} {
+		_, err = s.DecodeGlobalIndex(ctx, &pb.DecodeGlobalIndexRequest{GlobalIndex: invalid})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), invalid)
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// idempotencyKeyHeader is the header with the key that deduplicates the retries of an admin mutation.
+// The http gateway forwards it as grpc metadata.
+const idempotencyKeyHeader = "idempotency-key"
+
+// maxIdempotencyKeyLength is the max length in bytes of an idempotency key
+const maxIdempotencyKeyLength = 255
+
+// idempotencyKey returns the idempotency key of the request, empty if it has none.
+func idempotencyKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get(idempotencyKeyHeader); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// idempotent runs the mutation of the method once for its idempotency key. The retries with the same key and request
+// get the stored response without running it again, until the key expires. A failed mutation doesn't keep its key,
+// so it can be retried with it.
+func idempotent[T proto.Message](ctx context.Context, s *bridgeService, method string, req proto.Message, run func() (T, error)) (T, error) {
+	var res T
+	key := idempotencyKey(ctx)
+	if key == "" {
+		return run()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return res, status.Errorf(codes.InvalidArgument, "the idempotency key must have at most %d bytes", maxIdempotencyKeyLength)
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return res, err
+	}
+	requestHash := sha256.Sum256(append([]byte(method+"\x00"), data...))
+	now := time.Now()
+	if err := s.storage.DeleteIdempotencyKeys(ctx, now.Add(-s.idempotencyTTL), nil); err != nil {
+		return res, err
+	}
+	err = s.storage.AddIdempotencyKey(ctx, &etherman.IdempotencyKey{
+		Method:      method,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+	}, nil)
+	if errors.Is(err, gerror.ErrAlreadyProcessed) {
+		stored, err := s.storage.GetIdempotencyKey(ctx, method, key, nil)
+		if errors.Is(err, gerror.ErrStorageNotFound) {
+			return res, status.Error(codes.Aborted, "the request with the idempotency key failed, retry it")
+		}
+		if err != nil {
+			return res, err
+		}
+		if stored.RequestHash != requestHash {
+			return res, status.Errorf(codes.InvalidArgument, "the idempotency key %s was used by another request", key)
+		}
+		if stored.Response == nil {
+			return res, status.Errorf(codes.Aborted, "the request with the idempotency key %s is in progress", key)
+		}
+		res = res.ProtoReflect().New().Interface().(T)
+		if err := proto.Unmarshal(stored.Response, res); err != nil {
+			return res, err
+		}
+		log.Infof("%s with the idempotency key %s already done, returning its response", method, key)
+		return res, nil
+	}
+	if err != nil {
+		return res, err
+	}
+	res, err = run()
+	if err != nil {
+		if delErr := s.storage.DeleteIdempotencyKey(ctx, method, key, nil); delErr != nil {
+			log.Errorf("error deleting the idempotency key %s of %s: %v", key, method, delErr)
+		}
+		return res, err
+	}
+	response, err := proto.Marshal(res)
+	if err != nil {
+		return res, err
+	}
+	if response == nil {
+		// The empty responses are stored as empty, not null, to be told apart from the requests in progress
+		response = []byte{}
+	}
+	if err := s.storage.SetIdempotencyResponse(ctx, method, key, response, nil); err != nil {
+		log.Errorf("error storing the response of the idempotency key %s of %s: %v", key, method, err)
+	}
+	return res, nil
+}
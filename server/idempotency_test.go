@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// idempotencyStorage stores the idempotency keys in memory and counts the claim txs approved.
+type idempotencyStorage struct {
+	*benchStorage
+	keys       map[string]*etherman.IdempotencyKey
+	approved   []uint
+	approveErr error
+}
+
+func (s *idempotencyStorage) AddIdempotencyKey(ctx context.Context, key *etherman.IdempotencyKey, dbTx pgx.Tx) error {
+	if _, found := s.keys[key.Method+"/"+key.Key]; found {
+		return gerror.ErrAlreadyProcessed
+	}
+	k := *key
+	s.keys[key.Method+"/"+key.Key] = &k
+	return nil
+}
+
+func (s *idempotencyStorage) GetIdempotencyKey(ctx context.Context, method, key string, dbTx pgx.Tx) (*etherman.IdempotencyKey, error) {
+	k, found := s.keys[method+"/"+key]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return k, nil
+}
+
+func (s *idempotencyStorage) SetIdempotencyResponse(ctx context.Context, method, key string, response []byte, dbTx pgx.Tx) error {
+	s.keys[method+"/"+key].Response = response
+	return nil
+}
+
+func (s *idempotencyStorage) DeleteIdempotencyKey(ctx context.Context, method, key string, dbTx pgx.Tx) error {
+	delete(s.keys, method+"/"+key)
+	return nil
+}
+
+func (s *idempotencyStorage) DeleteIdempotencyKeys(ctx context.Context, before time.Time, dbTx pgx.Tx) error {
+	for id, k := range s.keys {
+		if k.CreatedAt.Before(before) {
+			delete(s.keys, id)
+		}
+	}
+	return nil
+}
+
+func (s *idempotencyStorage) ApproveClaimTx(ctx context.Context, depositID uint, dbTx pgx.Tx) error {
+	if s.approveErr != nil {
+		return s.approveErr
+	}
+	s.approved = append(s.approved, depositID)
+	return nil
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	bench, _ := newBenchStorage(10, 5)
+	storage := &idempotencyStorage{benchStorage: bench, keys: make(map[string]*etherman.IdempotencyKey)}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, AdminToken: "admin",
+		IdempotencyKeyTTL: types.NewDuration(time.Hour)}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	withKey := func(key string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, "admin", idempotencyKeyHeader, key))
+	}
+
+	// The retries with the same key run the mutation once
+	for i := 0; i < 3; i++ {
+		_, err := s.ApproveClaim(withKey("retry"), &pb.ApproveClaimRequest{DepositCnt: 1})
+		require.NoError(t, err)
+	}
+	require.Equal(t, []uint{1}, storage.approved)
+
+	// The key of another request is rejected
+	_, err := s.ApproveClaim(withKey("retry"), &pb.ApproveClaimRequest{DepositCnt: 2})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// A request still running is not run again
+	storage.keys["ApproveClaim/retry"].Response = nil
+	_, err = s.ApproveClaim(withKey("retry"), &pb.ApproveClaimRequest{DepositCnt: 1})
+	require.Equal(t, codes.Aborted, status.Code(err))
+
+	// The requests without key always run
+	_, err = s.ApproveClaim(metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, "admin")), &pb.ApproveClaimRequest{DepositCnt: 1})
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 1}, storage.approved)
+
+	// A failed request releases its key to be retried
+	storage.approveErr = errors.New("database down")
+	_, err = s.ApproveClaim(withKey("failed"), &pb.ApproveClaimRequest{DepositCnt: 3})
+	require.Error(t, err)
+	storage.approveErr = nil
+	_, err = s.ApproveClaim(withKey("failed"), &pb.ApproveClaimRequest{DepositCnt: 3})
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 1, 3}, storage.approved)
+
+	// The expired keys run again
+	storage.keys["ApproveClaim/failed"].CreatedAt = time.Now().Add(-2 * time.Hour)
+	_, err = s.ApproveClaim(withKey("failed"), &pb.ApproveClaimRequest{DepositCnt: 3})
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 1, 3, 3}, storage.approved)
+
+	// The admin token is checked before the key is stored
+	_, err = s.ApproveClaim(metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "other")), &pb.ApproveClaimRequest{DepositCnt: 4})
+	require.Error(t, err)
+	require.Nil(t, storage.keys["ApproveClaim/other"])
+}
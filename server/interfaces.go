@@ -2,22 +2,133 @@ package server
 
 import (
 	"context"
+	"math/big"
+	"time"
 
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/indexadvisor"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/reserve"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/signing"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jackc/pgx/v4"
 )
 
 type bridgeServiceStorage interface {
+	BeginDBTransaction(ctx context.Context) (pgx.Tx, error)
+	BeginSnapshotTransaction(ctx context.Context) (pgx.Tx, error)
 	Get(ctx context.Context, key []byte, dbTx pgx.Tx) ([][]byte, error)
 	GetRoot(ctx context.Context, depositCnt uint, network uint, dbTx pgx.Tx) ([]byte, error)
 	GetDepositCountByRoot(ctx context.Context, root []byte, network uint8, dbTx pgx.Tx) (uint, error)
 	GetLatestExitRoot(ctx context.Context, isRollup bool, dbTx pgx.Tx) (*etherman.GlobalExitRoot, error)
+	GetRollupExitLeavesByRoot(ctx context.Context, root common.Hash, dbTx pgx.Tx) ([]etherman.RollupExitLeaf, error)
+	GetL1InfoTreeLeaf(ctx context.Context, ger common.Hash, dbTx pgx.Tx) (*etherman.L1InfoTreeLeaf, error)
+	GetLatestL1InfoTreeLeaf(ctx context.Context, dbTx pgx.Tx) (*etherman.L1InfoTreeLeaf, error)
 	GetClaim(ctx context.Context, index uint, networkID uint, dbTx pgx.Tx) (*etherman.Claim, error)
 	GetClaims(ctx context.Context, destAddr string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Claim, error)
 	GetClaimCount(ctx context.Context, destAddr string, dbTx pgx.Tx) (uint64, error)
 	GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error)
-	GetDeposits(ctx context.Context, destAddr string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error)
-	GetDepositCount(ctx context.Context, destAddr string, dbTx pgx.Tx) (uint64, error)
+	GetDeposits(ctx context.Context, addr string, role string, integration string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	GetDepositsByTxHash(ctx context.Context, txHash common.Hash, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	SearchDeposits(ctx context.Context, prefix string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	SearchClaims(ctx context.Context, prefix string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Claim, error)
+	GetDepositTrace(ctx context.Context, networkID uint, txHash common.Hash, dbTx pgx.Tx) (*etherman.DepositTrace, error)
+	GetDepositStatusHistory(ctx context.Context, depositCnt, networkID uint, dbTx pgx.Tx) ([]etherman.DepositStatusChange, error)
+	GetDepositActivities(ctx context.Context, networkID, depositCnt uint, q string, limit uint, dbTx pgx.Tx) ([]etherman.DepositActivity, error)
+	SetDepositAnnotation(ctx context.Context, annotation *etherman.DepositAnnotation, dbTx pgx.Tx) error
+	GetDepositAnnotations(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.DepositAnnotation, error)
+	SetDepositRef(ctx context.Context, ref *etherman.DepositRef, dbTx pgx.Tx) error
+	GetDepositRef(ctx context.Context, networkID, depositCnt uint, dbTx pgx.Tx) (*etherman.DepositRef, error)
+	GetDepositRefs(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.DepositRef, error)
+	GetClaimableDeposits(ctx context.Context, destNet uint, destAddr string, origNet uint, origAddr string, limit, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error)
+	AddWalletWebhook(ctx context.Context, webhook *etherman.WalletWebhook, dbTx pgx.Tx) error
+	GetWalletWebhooks(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.WalletWebhook, error)
+	DeleteWalletWebhook(ctx context.Context, id uint64, address common.Address, dbTx pgx.Tx) error
+	AddPushDevice(ctx context.Context, device *etherman.PushDevice, dbTx pgx.Tx) error
+	GetPushDevices(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.PushDevice, error)
+	DeletePushDevice(ctx context.Context, address common.Address, platform, token string, dbTx pgx.Tx) error
+	AddWatchedAddress(ctx context.Context, watch *etherman.WatchedAddress, dbTx pgx.Tx) error
+	GetWatchedAddresses(ctx context.Context, owner common.Address, dbTx pgx.Tx) ([]etherman.WatchedAddress, error)
+	GetAllWatchedAddresses(ctx context.Context, dbTx pgx.Tx) ([]common.Address, error)
+	IsWatchedAddress(ctx context.Context, address common.Address, dbTx pgx.Tx) (bool, error)
+	DeleteWatchedAddress(ctx context.Context, owner, address common.Address, dbTx pgx.Tx) error
+	RequestWatchBackfill(ctx context.Context, owner, address common.Address, requestedAt time.Time, dbTx pgx.Tx) error
+	AddProofConsumer(ctx context.Context, consumer *etherman.ProofConsumer, dbTx pgx.Tx) error
+	GetProofConsumers(ctx context.Context, dbTx pgx.Tx) ([]etherman.ProofConsumer, error)
+	DeleteProofConsumer(ctx context.Context, name string, dbTx pgx.Tx) error
+	GetProofAccess(ctx context.Context, dbTx pgx.Tx) (*etherman.ProofAccess, error)
+	SetProofAccess(ctx context.Context, access *etherman.ProofAccess, dbTx pgx.Tx) error
+	GetTokenList(ctx context.Context, dbTx pgx.Tx) ([]etherman.TokenListEntry, error)
+	SetTokenListEntry(ctx context.Context, entry *etherman.TokenListEntry, dbTx pgx.Tx) error
+	DeleteTokenListEntry(ctx context.Context, change *etherman.TokenListChange, dbTx pgx.Tx) error
+	GetTokenListChanges(ctx context.Context, limit, offset uint, dbTx pgx.Tx) ([]etherman.TokenListChange, error)
+	AddConfigChange(ctx context.Context, change *etherman.ConfigChange, dbTx pgx.Tx) error
+	GetConfigChange(ctx context.Context, id uint64, dbTx pgx.Tx) (*etherman.ConfigChange, error)
+	GetConfigChanges(ctx context.Context, status string, now time.Time, limit, offset uint, dbTx pgx.Tx) ([]etherman.ConfigChange, error)
+	ReviewConfigChange(ctx context.Context, id uint64, status, reviewer string, reviewedAt time.Time, dbTx pgx.Tx) error
+	SetConfigChangeResult(ctx context.Context, id uint64, status, errMsg string, dbTx pgx.Tx) error
+	AddIdempotencyKey(ctx context.Context, key *etherman.IdempotencyKey, dbTx pgx.Tx) error
+	GetIdempotencyKey(ctx context.Context, method, key string, dbTx pgx.Tx) (*etherman.IdempotencyKey, error)
+	SetIdempotencyResponse(ctx context.Context, method, key string, response []byte, dbTx pgx.Tx) error
+	DeleteIdempotencyKey(ctx context.Context, method, key string, dbTx pgx.Tx) error
+	DeleteIdempotencyKeys(ctx context.Context, before time.Time, dbTx pgx.Tx) error
+	GetHistoryPoint(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.HistoryPoint, error)
+	GetLocalExitRootAt(ctx context.Context, networkID uint, point etherman.HistoryPoint, dbTx pgx.Tx) (common.Hash, error)
+	GetDepositCount(ctx context.Context, addr string, role string, integration string, dbTx pgx.Tx) (uint64, error)
 	GetTokenWrapped(ctx context.Context, originalNetwork uint, originalTokenAddress common.Address, dbTx pgx.Tx) (*etherman.TokenWrapped, error)
+	GetTokenWrappedHistory(ctx context.Context, originalNetwork uint, originalTokenAddress common.Address, dbTx pgx.Tx) ([]*etherman.TokenWrappedMapping, error)
+	GetActivity(ctx context.Context, networkID uint, originalNetwork uint, originalTokenAddress *common.Address, interval string, from, to time.Time, limit uint, dbTx pgx.Tx) ([]*etherman.ActivityBucket, error)
+	GetIntegrationStats(ctx context.Context, networkID uint, originalNetwork uint, originalTokenAddress *common.Address, from, to time.Time, dbTx pgx.Tx) ([]*etherman.IntegrationStats, error)
+	GetClaimTxsByStatus(ctx context.Context, statuses []ctmtypes.MonitoredTxStatus, dbTx pgx.Tx) ([]ctmtypes.MonitoredTx, error)
+	GetClaimOutcome(ctx context.Context, depositID uint, dbTx pgx.Tx) (*etherman.ClaimOutcome, error)
+	ApproveClaimTx(ctx context.Context, depositID uint, dbTx pgx.Tx) error
+	AddSignedClaimTx(ctx context.Context, depositID uint, signedTx []byte, dbTx pgx.Tx) error
+	GetAdminQueries() []etherman.AdminQuery
+	RunAdminQuery(ctx context.Context, name string, params map[string]string, limit uint) (*etherman.AdminQueryResult, error)
+	RunAdminQueryWithTimeout(ctx context.Context, name string, params map[string]string, limit uint, timeout time.Duration) (*etherman.AdminQueryResult, error)
+	AddQueryJob(ctx context.Context, job *etherman.QueryJob, dbTx pgx.Tx) error
+	FinishQueryJob(ctx context.Context, id string, status string, jobErr string, result *etherman.AdminQueryResult, dbTx pgx.Tx) error
+	GetQueryJob(ctx context.Context, id string, dbTx pgx.Tx) (*etherman.QueryJob, error)
+	GetQueryJobResult(ctx context.Context, id string, offset, limit uint, dbTx pgx.Tx) (*etherman.AdminQueryResult, error)
+	DeleteQueryJobs(ctx context.Context, before time.Time, dbTx pgx.Tx) error
+	GetQueryStats(ctx context.Context, minCalls uint64) ([]indexadvisor.QueryStat, error)
+	GetIndexes(ctx context.Context) ([]indexadvisor.Index, error)
+}
+
+// ReserveAttester signs the proof of reserve reports of the tokens bridged between L1 and a L2 network.
+type ReserveAttester interface {
+	Attest(ctx context.Context, l2NetworkID uint, l1BlockNum, l2BlockNum uint64) (*reserve.Attestation, error)
+}
+
+// ResponseSigner signs the responses of the REST API with the operator keys.
+type ResponseSigner interface {
+	Sign(ctx context.Context, requestURI string, body []byte) (*signing.Signature, error)
+	Keys(ctx context.Context) ([]signing.Key, error)
+	Rotate(ctx context.Context, signer common.Address) (*signing.Key, error)
+}
+
+// ClaimSimulator simulates claims in a network without sending any tx.
+type ClaimSimulator interface {
+	SimulateClaim(ctx context.Context, from common.Address, version string, deposit *etherman.Deposit, smtProof, rollupProof [32][32]byte, globalExitRoot *etherman.GlobalExitRoot) (bool, string, error)
+	BuildClaimTx(ctx context.Context, from common.Address, version string, deposit *etherman.Deposit, smtProof, rollupProof [32][32]byte, globalExitRoot *etherman.GlobalExitRoot) (*etherman.ClaimTx, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	ChainID(ctx context.Context) (uint64, error)
+}
+
+// L1InfoTreeProver calculates the merkle proofs of the L1 info tree kept in memory by the synchronizer.
+type L1InfoTreeProver interface {
+	GetL1InfoTreeProof(index, count uint) (*bridgectrl.L1InfoTreeProof, error)
+}
+
+// BatchReader reads the batches of a L2 network from its node.
+type BatchReader interface {
+	BatchNumberByBlockNumber(ctx context.Context, blockNumber uint64) (uint64, error)
+	VirtualBatchNumber(ctx context.Context) (uint64, error)
+	VerifiedBatchNumber(ctx context.Context) (uint64, error)
+}
+
+// ClaimBreaker is the circuit breaker of the claim txs of a network.
+type ClaimBreaker interface {
+	BreakerState() ctmtypes.BreakerState
 }
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	jobIDLength = 16
+	// jobInterruptedMargin is the time after the timeout of a job to consider it interrupted while still running,
+	// when the instance running it stopped
+	jobInterruptedMargin = time.Minute
+)
+
+// SubmitQueryJob submits a predefined read only query to run asynchronously. The job runs in this instance, at most
+// MaxConcurrent jobs at the same time.
+// Bridge rest API admin endpoint
+func (s *bridgeService) SubmitQueryJob(ctx context.Context, req *pb.SubmitQueryJobRequest) (*pb.SubmitQueryJobResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return idempotent(ctx, s, "SubmitQueryJob", req, func() (*pb.SubmitQueryJobResponse, error) {
+		if s.jobs.MaxConcurrent <= 0 {
+			return nil, status.Error(codes.Unimplemented, "query jobs are disabled")
+		}
+		if !s.isAdminQuery(req.Name) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown query %s", req.Name)
+		}
+		select {
+		case s.jobSlots <- struct{}{}:
+		default:
+			return nil, status.Errorf(codes.ResourceExhausted, "%d query jobs already running", s.jobs.MaxConcurrent)
+		}
+		job, err := s.addQueryJob(ctx, req)
+		if err != nil {
+			<-s.jobSlots
+			return nil, err
+		}
+		go s.runQueryJob(job)
+		log.Infof("query job %s of the admin query %s submitted with params %v", job.ID, job.Query, job.Params)
+		return &pb.SubmitQueryJobResponse{
+			Job: queryJobToPb(job),
+		}, nil
+	})
+}
+
+func (s *bridgeService) isAdminQuery(name string) bool {
+	for _, query := range s.storage.GetAdminQueries() {
+		if query.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *bridgeService) addQueryJob(ctx context.Context, req *pb.SubmitQueryJobRequest) (*etherman.QueryJob, error) {
+	id := make([]byte, jobIDLength)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	job := &etherman.QueryJob{
+		ID:        hex.EncodeToString(id),
+		Query:     req.Name,
+		Params:    req.Params,
+		Status:    etherman.QueryJobStatusRunning,
+		CreatedAt: time.Now(),
+	}
+	if job.Params == nil {
+		job.Params = map[string]string{}
+	}
+	// The expired jobs are deleted with the submissions, so they don't need a cleanup loop in every instance
+	if err := s.storage.DeleteQueryJobs(ctx, job.CreatedAt.Add(-s.jobs.TTL.Duration), nil); err != nil {
+		return nil, err
+	}
+	if err := s.storage.AddQueryJob(ctx, job, nil); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// runQueryJob runs the query of the job and stores its result, releasing its slot once it's finished.
+func (s *bridgeService) runQueryJob(job *etherman.QueryJob) {
+	defer func() { <-s.jobSlots }()
+	ctx := context.Background()
+	start := time.Now()
+	result, err := s.storage.RunAdminQueryWithTimeout(ctx, job.Query, job.Params, s.jobs.MaxRows, s.jobs.Timeout.Duration)
+	jobStatus, jobErr := etherman.QueryJobStatusDone, ""
+	if err != nil {
+		log.Warnf("query job %s failed: %v", job.ID, err)
+		jobStatus, jobErr, result = etherman.QueryJobStatusFailed, err.Error(), nil
+	} else {
+		log.Infof("query job %s done in %s, %d rows", job.ID, time.Since(start), len(result.Rows))
+	}
+	if err := s.storage.FinishQueryJob(ctx, job.ID, jobStatus, jobErr, result, nil); err != nil {
+		log.Errorf("error storing the result of the query job %s: %v", job.ID, err)
+	}
+}
+
+// GetQueryJob returns the status of a query job.
+// Bridge rest API admin endpoint
+func (s *bridgeService) GetQueryJob(ctx context.Context, req *pb.GetQueryJobRequest) (*pb.GetQueryJobResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	job, err := s.getQueryJob(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetQueryJobResponse{
+		Job: queryJobToPb(job),
+	}, nil
+}
+
+func (s *bridgeService) getQueryJob(ctx context.Context, id string) (*etherman.QueryJob, error) {
+	job, err := s.storage.GetQueryJob(ctx, id, nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return nil, status.Errorf(codes.NotFound, "query job %s not found", id)
+	} else if err != nil {
+		return nil, err
+	}
+	// The statement timeout bounds the running time, so a job still running after the timeout was interrupted
+	if job.Status == etherman.QueryJobStatusRunning && time.Since(job.CreatedAt) > s.jobs.Timeout.Duration+jobInterruptedMargin {
+		job.Status = etherman.QueryJobStatusFailed
+		job.Error = "interrupted, the instance running the job stopped"
+	}
+	return job, nil
+}
+
+// GetQueryJobResult returns a page of the result of a done query job, or exports it as csv. The pages have at most
+// the max page limit rows, so the large results don't exceed the max size of the messages.
+// Bridge rest API admin endpoint
+func (s *bridgeService) GetQueryJobResult(ctx context.Context, req *pb.GetQueryJobResultRequest) (*pb.GetQueryJobResultResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.Format != "" && req.Format != adminQueryFormatCSV {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported format %s", req.Format)
+	}
+	var offset uint64
+	if req.PageToken != "" {
+		var err error
+		if offset, err = strconv.ParseUint(req.PageToken, 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %s", req.PageToken)
+		}
+	}
+	pageSize, err := s.pageLimit(ctx, req.PageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.getQueryJob(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != etherman.QueryJobStatusDone {
+		return nil, status.Errorf(codes.FailedPrecondition, "query job %s is %s", job.ID, job.Status)
+	}
+	result, err := s.storage.GetQueryJobResult(ctx, job.ID, uint(offset), uint(pageSize), nil)
+	if err != nil {
+		return nil, err
+	}
+	columns, rows, csv, err := formatAdminQueryResult(result, req.Format)
+	if err != nil {
+		return nil, err
+	}
+	var nextPageToken string
+	if next := offset + uint64(pageSize); next < job.Rows {
+		nextPageToken = strconv.FormatUint(next, 10)
+	}
+	return &pb.GetQueryJobResultResponse{
+		Columns:       columns,
+		Rows:          rows,
+		Csv:           csv,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func queryJobToPb(job *etherman.QueryJob) *pb.QueryJob {
+	return &pb.QueryJob{
+		Id:         job.ID,
+		Name:       job.Query,
+		Params:     job.Params,
+		Status:     job.Status,
+		Error:      job.Error,
+		Rows:       job.Rows,
+		CreatedAt:  unixTime(job.CreatedAt),
+		FinishedAt: unixTime(job.FinishedAt),
+	}
+}
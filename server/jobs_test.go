@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestQueryJobs(t *testing.T) {
+	bench, _ := newBenchStorage(1, 0)
+	storage := newMemStorage(bench)
+	storage.release = make(chan struct{})
+	cfg := Config{
+		CacheSize:        100,
+		DefaultPageLimit: 25,
+		MaxPageLimit:     100,
+		AdminToken:       "admin",
+		Jobs:             JobsConfig{MaxConcurrent: 1, MaxRows: 100, Timeout: types.NewDuration(time.Minute), TTL: types.NewDuration(time.Hour)},
+	}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, "admin"))
+
+	_, err := s.SubmitQueryJob(context.Background(), &pb.SubmitQueryJobRequest{Name: "address_history"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.SubmitQueryJob(ctx, &pb.SubmitQueryJobRequest{Name: "unknown"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	params := map[string]string{"address": "0xc949254d682d8c9ad5682521675b8f43b102aec4"}
+	submitted, err := s.SubmitQueryJob(ctx, &pb.SubmitQueryJobRequest{Name: "address_history", Params: params})
+	require.NoError(t, err)
+	require.Equal(t, etherman.QueryJobStatusRunning, submitted.Job.Status)
+	require.Len(t, submitted.Job.Id, 2*jobIDLength)
+	// The instance runs at most MaxConcurrent jobs
+	_, err = s.SubmitQueryJob(ctx, &pb.SubmitQueryJobRequest{Name: "address_history", Params: params})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// The result is only served once the job is done
+	_, err = s.GetQueryJobResult(ctx, &pb.GetQueryJobResultRequest{Id: submitted.Job.Id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	storage.release <- struct{}{}
+	require.Eventually(t, func() bool {
+		res, err := s.GetQueryJob(ctx, &pb.GetQueryJobRequest{Id: submitted.Job.Id})
+		return err == nil && res.Job.Status == etherman.QueryJobStatusDone
+	}, time.Second, 10*time.Millisecond)
+	res, err := s.GetQueryJob(ctx, &pb.GetQueryJobRequest{Id: submitted.Job.Id})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.Job.Rows)
+	require.NotZero(t, res.Job.FinishedAt)
+	result, err := s.GetQueryJobResult(ctx, &pb.GetQueryJobResultRequest{Id: submitted.Job.Id})
+	require.NoError(t, err)
+	require.Equal(t, []string{"address", "limit"}, result.Columns)
+	require.Equal(t, params["address"], result.Rows[0].Values[0])
+	result, err = s.GetQueryJobResult(ctx, &pb.GetQueryJobResultRequest{Id: submitted.Job.Id, Format: adminQueryFormatCSV})
+	require.NoError(t, err)
+	require.Equal(t, "address,limit\n"+params["address"]+",10\n", result.Csv)
+	_, err = s.GetQueryJobResult(ctx, &pb.GetQueryJobResultRequest{Id: submitted.Job.Id, Format: "xml"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The result is paginated with the page tokens
+	storage.mu.Lock()
+	storage.jobs["paged"] = &etherman.QueryJob{ID: "paged", Status: etherman.QueryJobStatusDone, Rows: 3, CreatedAt: time.Now()}
+	storage.results["paged"] = &etherman.AdminQueryResult{Columns: []string{"n"}, Rows: [][]string{{"1"}, {"2"}, {"3"}}}
+	storage.mu.Unlock()
+	var pages [][]string
+	pageToken := ""
+	for {
+		page, err := s.GetQueryJobResult(ctx, &pb.GetQueryJobResultRequest{Id: "paged", PageSize: 2, PageToken: pageToken})
+		require.NoError(t, err)
+		require.Equal(t, []string{"n"}, page.Columns)
+		for _, row := range page.Rows {
+			pages = append(pages, row.Values)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	require.Equal(t, [][]string{{"1"}, {"2"}, {"3"}}, pages)
+	_, err = s.GetQueryJobResult(ctx, &pb.GetQueryJobResultRequest{Id: "paged", PageToken: "invalid"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The slot is released once the job is finished, and the failed jobs keep their error
+	require.Eventually(t, func() bool { return len(s.jobSlots) == 0 }, time.Second, 10*time.Millisecond)
+	failing, err := s.SubmitQueryJob(ctx, &pb.SubmitQueryJobRequest{Name: "failing"})
+	require.NoError(t, err)
+	storage.release <- struct{}{}
+	require.Eventually(t, func() bool {
+		res, err := s.GetQueryJob(ctx, &pb.GetQueryJobRequest{Id: failing.Job.Id})
+		return err == nil && res.Job.Status == etherman.QueryJobStatusFailed && res.Job.Error != ""
+	}, time.Second, 10*time.Millisecond)
+
+	// The jobs running after their timeout were interrupted
+	storage.jobs["interrupted"] = &etherman.QueryJob{ID: "interrupted", Status: etherman.QueryJobStatusRunning, CreatedAt: time.Now().Add(-time.Hour)}
+	res, err = s.GetQueryJob(ctx, &pb.GetQueryJobRequest{Id: "interrupted"})
+	require.NoError(t, err)
+	require.Equal(t, etherman.QueryJobStatusFailed, res.Job.Status)
+	_, err = s.GetQueryJob(ctx, &pb.GetQueryJobRequest{Id: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
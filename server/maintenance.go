@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/maintenance"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaintenanceHeader is the header of the REST responses with the notice of the maintenance in progress, for the
+// banners of the frontends.
+const MaintenanceHeader = "X-Maintenance"
+
+// maxMaintenanceMessageLength is the max length in bytes of the notice of a maintenance
+const maxMaintenanceMessageLength = 256
+
+// maintenanceNotice is the json of the maintenance header.
+type maintenanceNotice struct {
+	Message string `json:"message"`
+	// ETA is the expected end of the maintenance in unix seconds
+	ETA uint64 `json:"eta"`
+}
+
+// maintenanceService is the service whose REST responses carry the notice of the maintenance.
+type maintenanceService interface {
+	maintenanceMonitor() *maintenance.Monitor
+}
+
+// SetMaintenanceMonitor sets the monitor of the maintenance shared with the writers, nil if it's not available.
+func (s *bridgeService) SetMaintenanceMonitor(monitor *maintenance.Monitor) {
+	s.maintenance = monitor
+}
+
+func (s *bridgeService) maintenanceMonitor() *maintenance.Monitor {
+	return s.maintenance
+}
+
+// checkMaintenance rejects the writes of the wallets during a maintenance, with its notice.
+func (s *bridgeService) checkMaintenance(ctx context.Context) error {
+	if m := s.maintenance.Active(ctx); m != nil {
+		return status.Errorf(codes.Unavailable, "maintenance until %s: %s", m.ETA.UTC().Format(time.RFC3339), m.Message)
+	}
+	return nil
+}
+
+// GetMaintenance returns the maintenance in progress, with the notice for the frontends.
+// Bridge rest API endpoint
+func (s *bridgeService) GetMaintenance(ctx context.Context, req *pb.GetMaintenanceRequest) (*pb.GetMaintenanceResponse, error) {
+	m := s.maintenance.Active(ctx)
+	if m == nil {
+		return &pb.GetMaintenanceResponse{}, nil
+	}
+	return &pb.GetMaintenanceResponse{
+		Active:    true,
+		Message:   m.Message,
+		Eta:       unixTime(m.ETA),
+		UpdatedAt: unixTime(m.UpdatedAt),
+	}, nil
+}
+
+// SetMaintenance starts a maintenance until its eta in every instance, pausing the synchronizers and the claim tx
+// managers and rejecting the writes of the wallets, or ends it. Bridge rest API admin endpoint
+func (s *bridgeService) SetMaintenance(ctx context.Context, req *pb.SetMaintenanceRequest) (*pb.SetMaintenanceResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkConfigApproval(ctx, "SetMaintenance"); err != nil {
+		return nil, err
+	}
+	if s.maintenance == nil {
+		return nil, status.Error(codes.Unimplemented, "the maintenance mode is not available")
+	}
+	if len(req.Message) > maxMaintenanceMessageLength {
+		return nil, status.Errorf(codes.InvalidArgument, "message longer than %d bytes", maxMaintenanceMessageLength)
+	}
+	return idempotent(ctx, s, "SetMaintenance", req, func() (*pb.SetMaintenanceResponse, error) {
+		eta := time.Unix(int64(req.Eta), 0)
+		if err := s.maintenance.Set(ctx, req.Enabled, req.Message, eta); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if req.Enabled {
+			log.Warnf("maintenance started until %s: %s", eta.UTC().Format(time.RFC3339), req.Message)
+		} else {
+			log.Warn("maintenance ended")
+		}
+		return &pb.SetMaintenanceResponse{}, nil
+	})
+}
+
+// maintenanceNotices adds the notice of the maintenance in progress to the responses, so the frontends show it
+// without polling the maintenance endpoint.
+func maintenanceNotices(h http.Handler, monitor *maintenance.Monitor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m := monitor.Active(r.Context()); m != nil {
+			w.Header().Set(MaintenanceHeader, maintenanceHeader(m))
+			w.Header().Add("Access-Control-Expose-Headers", MaintenanceHeader)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// maintenanceHeader returns the json of the notice of a maintenance.
+func maintenanceHeader(m *etherman.Maintenance) string {
+	notice, _ := json.Marshal(maintenanceNotice{Message: m.Message, ETA: unixTime(m.ETA)})
+	return string(notice)
+}
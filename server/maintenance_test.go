@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/maintenance"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMaintenance(t *testing.T) {
+	ctx := context.Background()
+	key := common.FromHex("0x4eba8ff6000ded1e4f8a4e9bba0b3eb3f1e9a1d9156a16e45bb4a3c2a8b00f9b")
+	privateKey, err := crypto.ToECDSA(key)
+	require.NoError(t, err)
+	wallet := crypto.PubkeyToAddress(privateKey.PublicKey)
+	bench, _ := newBenchStorage(4, 0)
+	storage := newMemStorage(bench)
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, AdminToken: "admin",
+		Session: SessionConfig{Secret: "secret", ChainID: 1, ChallengeTTL: types.NewDuration(time.Minute), SessionTTL: types.NewDuration(time.Hour)},
+		Watch:   WatchConfig{MaxAddresses: 1},
+	}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	admin := metadata.NewIncomingContext(ctx, metadata.Pairs(adminTokenHeader, "admin"))
+	challenge, err := s.GetSessionChallenge(ctx, &pb.GetSessionChallengeRequest{Address: wallet.Hex()})
+	require.NoError(t, err)
+	session, err := s.CreateSession(ctx, &pb.CreateSessionRequest{Address: wallet.Hex(), Nonce: challenge.Nonce, ExpiresAt: challenge.ExpiresAt,
+		Signature: signChallenge(t, challenge.TypedData, key)})
+	require.NoError(t, err)
+	sessionCtx := withSession(ctx, session.Token)
+
+	// Without the monitor there is no maintenance
+	res, err := s.GetMaintenance(ctx, &pb.GetMaintenanceRequest{})
+	require.NoError(t, err)
+	require.False(t, res.Active)
+	_, err = s.SetMaintenance(admin, &pb.SetMaintenanceRequest{Enabled: true})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+
+	monitor := maintenance.NewMonitor(maintenance.Config{RefreshInterval: types.NewDuration(time.Minute), MaxDuration: types.NewDuration(time.Hour)}, storage)
+	s.SetMaintenanceMonitor(monitor)
+	eta := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	_, err = s.SetMaintenance(ctx, &pb.SetMaintenanceRequest{Enabled: true, Message: "upgrade", Eta: uint64(eta.Unix())})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.SetMaintenance(admin, &pb.SetMaintenanceRequest{Enabled: true, Message: "upgrade", Eta: uint64(eta.Add(time.Hour).Unix())})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.SetMaintenance(admin, &pb.SetMaintenanceRequest{Enabled: true, Message: "upgrade", Eta: uint64(eta.Unix())})
+	require.NoError(t, err)
+
+	// The reads are served with the notice, the writes of the wallets are rejected
+	res, err = s.GetMaintenance(ctx, &pb.GetMaintenanceRequest{})
+	require.NoError(t, err)
+	require.True(t, res.Active)
+	require.Equal(t, "upgrade", res.Message)
+	require.Equal(t, uint64(eta.Unix()), res.Eta)
+	_, err = s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: bench.deposits[0].DestinationAddress.Hex()})
+	require.NoError(t, err)
+	_, err = s.AddWatchedAddress(sessionCtx, &pb.AddWatchedAddressRequest{Address: bench.deposits[0].DestinationAddress.Hex()})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	handler := maintenanceNotices(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), monitor)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bridges/0x1", nil))
+	var notice maintenanceNotice
+	require.NoError(t, json.Unmarshal([]byte(w.Header().Get(MaintenanceHeader)), &notice))
+	require.Equal(t, maintenanceNotice{Message: "upgrade", ETA: uint64(eta.Unix())}, notice)
+	require.Equal(t, MaintenanceHeader, w.Header().Get("Access-Control-Expose-Headers"))
+
+	// The writes are accepted again once it ends
+	_, err = s.SetMaintenance(admin, &pb.SetMaintenanceRequest{})
+	require.NoError(t, err)
+	res, err = s.GetMaintenance(ctx, &pb.GetMaintenanceRequest{})
+	require.NoError(t, err)
+	require.False(t, res.Active)
+	_, err = s.AddWatchedAddress(sessionCtx, &pb.AddWatchedAddressRequest{Address: bench.deposits[0].DestinationAddress.Hex()})
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bridges/0x1", nil))
+	require.Empty(t, w.Header().Get(MaintenanceHeader))
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const maxPooledBufferSize = 1 << 20
+
+// jsonMarshaler is the gateway JSON marshaler. It encodes the messages in pooled buffers instead of
+// growing a new buffer and copying it for each response, and reuses the encoding of the constant responses.
+type jsonMarshaler struct {
+	*runtime.JSONPb
+	buffers sync.Pool
+	// apiResponses keeps the encoded CheckAPI responses by version
+	apiResponses sync.Map
+}
+
+func newJSONMarshaler() *jsonMarshaler {
+	return &jsonMarshaler{
+		JSONPb: &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		},
+		buffers: sync.Pool{New: func() interface{} { return new([]byte) }},
+	}
+}
+
+// Marshal implements runtime.Marshaler.
+func (m *jsonMarshaler) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return m.JSONPb.Marshal(v)
+	}
+	if api, ok := msg.(*pb.CheckAPIResponse); ok {
+		if b, found := m.apiResponses.Load(api.Api); found {
+			return b.([]byte), nil
+		}
+		b, err := m.marshal(msg)
+		if err == nil {
+			m.apiResponses.Store(api.Api, b)
+		}
+		return b, err
+	}
+	return m.marshal(msg)
+}
+
+func (m *jsonMarshaler) marshal(msg proto.Message) ([]byte, error) {
+	buf := m.buffers.Get().(*[]byte)
+	b, err := m.MarshalOptions.MarshalAppend((*buf)[:0], msg)
+	if err != nil {
+		m.buffers.Put(buf)
+		return nil, err
+	}
+	// The gateway keeps the returned slice, so the pooled buffer can't be returned
+	out := make([]byte, len(b))
+	copy(out, b)
+	if cap(b) <= maxPooledBufferSize {
+		*buf = b
+		m.buffers.Put(buf)
+	}
+	return out, nil
+}
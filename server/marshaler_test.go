@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type benchService struct {
+	pb.UnimplementedBridgeServiceServer
+	resp *pb.GetBridgesResponse
+}
+
+func (s *benchService) GetBridges(context.Context, *pb.GetBridgesRequest) (*pb.GetBridgesResponse, error) {
+	return s.resp, nil
+}
+
+func bridgesResponse(n int) *pb.GetBridgesResponse {
+	resp := &pb.GetBridgesResponse{TotalCnt: uint64(n)}
+	for i := 0; i < n; i++ {
+		resp.Deposits = append(resp.Deposits, &pb.Deposit{
+			OrigAddr:        "0x0000000000000000000000000000000000000000",
+			Amount:          "1000000000000000000",
+			DestNet:         1,
+			DestAddr:        "0xc949254d682d8c9ad5682521675b8f43b102aec4",
+			BlockNum:        uint64(i),
+			DepositCnt:      uint64(i),
+			TxHash:          "0x9d6a2f6d4a0b6c5b6ba3c9f3e8efd3608f8a7d5f1a5e4f3f5b0d8e8c3b8a1d2c",
+			Metadata:        "0x",
+			ReadyForClaim:   true,
+			Decimals:        18,
+			FormattedAmount: "1",
+		})
+	}
+	return resp
+}
+
+func TestJSONMarshaler(t *testing.T) {
+	m := newJSONMarshaler()
+	reference := &runtime.JSONPb{MarshalOptions: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}}
+	for i := 0; i < 3; i++ {
+		resp := bridgesResponse(i * 10)
+		expected, err := reference.Marshal(resp)
+		require.NoError(t, err)
+		actual, err := m.Marshal(resp)
+		require.NoError(t, err)
+		require.JSONEq(t, string(expected), string(actual))
+	}
+	// The returned slices are not reused by the next responses
+	first, err := m.Marshal(&pb.CheckAPIResponse{Api: "v1"})
+	require.NoError(t, err)
+	_, err = m.Marshal(bridgesResponse(5))
+	require.NoError(t, err)
+	require.Equal(t, `{"api":"v1"}`, string(first))
+	cached, err := m.Marshal(&pb.CheckAPIResponse{Api: "v1"})
+	require.NoError(t, err)
+	require.Equal(t, first, cached)
+	// Non message values are encoded by the gateway marshaler
+	b, err := m.Marshal("value")
+	require.NoError(t, err)
+	require.Equal(t, `"value"`, string(b))
+}
+
+func BenchmarkMarshaler(b *testing.B) {
+	resp := bridgesResponse(25)
+	marshalers := map[string]runtime.Marshaler{
+		"gateway": &runtime.JSONPb{MarshalOptions: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}},
+		"pooled":  newJSONMarshaler(),
+	}
+	for name, m := range marshalers {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := m.Marshal(resp); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkGateway compares serving the REST requests through the gRPC server and calling the service directly.
+func BenchmarkGateway(b *testing.B) {
+	ctx := context.Background()
+	service := &benchService{resp: bridgesResponse(25)}
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(b, err)
+	grpcServer := grpc.NewServer()
+	pb.RegisterBridgeServiceServer(grpcServer, service)
+	go func() { _ = grpcServer.Serve(lis) }()
+	defer grpcServer.Stop()
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(b, err)
+	defer conn.Close() //nolint:errcheck
+
+	grpcMux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, newJSONMarshaler()))
+	require.NoError(b, pb.RegisterBridgeServiceHandler(ctx, grpcMux, conn))
+	inProcessMux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, newJSONMarshaler()))
+	require.NoError(b, pb.RegisterBridgeServiceHandlerServer(ctx, inProcessMux, service))
+
+	for name, mux := range map[string]http.Handler{"grpc": grpcMux, "in-process": inProcessMux} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				req := httptest.NewRequest(http.MethodGet, "/bridges/0xc949254d682d8c9ad5682521675b8f43b102aec4", nil)
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, req)
+				if w.Code != http.StatusOK {
+					b.Fatalf("unexpected status %d", w.Code)
+				}
+			}
+		})
+	}
+}
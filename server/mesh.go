@@ -0,0 +1,105 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// staticScheme is the scheme of the gateway targets resolved to the configured addresses.
+const staticScheme = "static"
+
+// grpcServer is the gRPC server of the service, the plain one or the one configured through xDS.
+type grpcServer interface {
+	grpc.ServiceRegistrar
+	Serve(lis net.Listener) error
+	GracefulStop()
+}
+
+// newGRPCServer creates the gRPC server, configured through xDS when it's enabled.
+func newGRPCServer(cfg GRPCConfig) (grpcServer, error) {
+	opts := grpcServerOptions(cfg)
+	if cfg.XDS {
+		return newXDSServer(opts)
+	}
+	return grpc.NewServer(opts...), nil
+}
+
+// Validate checks the xDS server is supported by the binary.
+func (c GRPCConfig) Validate() error {
+	if c.XDS && !xdsSupported {
+		return errors.New("the xDS server requires a binary built with the xds tag")
+	}
+	return nil
+}
+
+// Validate checks the target of the gateway is resolved by a registered resolver, and the service config.
+func (c GatewayConfig) Validate() error {
+	if c.ServiceConfig != "" && !json.Valid([]byte(c.ServiceConfig)) {
+		return errors.New("the service config is not valid json")
+	}
+	if c.Target == "" {
+		if len(c.Addresses) > 0 {
+			return fmt.Errorf("the addresses require a %s:/// target", staticScheme)
+		}
+		return nil
+	}
+	// The targets without a scheme are dialed as they are
+	if !strings.Contains(c.Target, "://") {
+		return nil
+	}
+	u, err := url.Parse(c.Target)
+	if err != nil {
+		return fmt.Errorf("invalid target %s: %w", c.Target, err)
+	}
+	if u.Scheme == staticScheme {
+		if len(c.Addresses) == 0 {
+			return fmt.Errorf("the target %s has no addresses", c.Target)
+		}
+		return nil
+	}
+	if resolver.Get(u.Scheme) == nil {
+		if u.Scheme == "xds" {
+			return errors.New("the xds targets require a binary built with the xds tag")
+		}
+		return fmt.Errorf("no resolver registered for the scheme %s of the target %s", u.Scheme, c.Target)
+	}
+	return nil
+}
+
+// gatewayTarget returns the target the gateway dials, the local gRPC server by default.
+func gatewayTarget(cfg Config) string {
+	if cfg.Gateway.Target != "" {
+		return cfg.Gateway.Target
+	}
+	return "localhost:" + cfg.GRPCPort
+}
+
+// gatewayDialOptions builds the dial options of the resolution and the load balancing of the gateway connection.
+func gatewayDialOptions(cfg GatewayConfig) []grpc.DialOption {
+	var opts []grpc.DialOption
+	if len(cfg.Addresses) > 0 {
+		// The resolver is only used by this connection, it's not registered globally
+		r := manual.NewBuilderWithScheme(staticScheme)
+		addresses := make([]resolver.Address, 0, len(cfg.Addresses))
+		for _, addr := range cfg.Addresses {
+			addresses = append(addresses, resolver.Address{Addr: addr})
+		}
+		r.InitialState(resolver.State{Addresses: addresses})
+		opts = append(opts, grpc.WithResolvers(r))
+	}
+	if cfg.ServiceConfig != "" {
+		opts = append(opts, grpc.WithDefaultServiceConfig(cfg.ServiceConfig))
+	}
+	if cfg.Authority != "" {
+		opts = append(opts, grpc.WithAuthority(cfg.Authority))
+	}
+	return opts
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// countingHealth counts the health checks served by a server.
+type countingHealth struct {
+	grpc_health_v1.UnimplementedHealthServer
+	checks atomic.Int32
+}
+
+func (h *countingHealth) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	h.checks.Add(1)
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func TestGatewayConfig(t *testing.T) {
+	require.NoError(t, GatewayConfig{}.Validate())
+	require.NoError(t, GatewayConfig{Target: "bridge:9090"}.Validate())
+	require.NoError(t, GatewayConfig{Target: "dns:///bridge:9090", ServiceConfig: `{"loadBalancingConfig": [{"round_robin": {}}]}`}.Validate())
+	require.NoError(t, GatewayConfig{Target: "static:///bridge", Addresses: []string{"10.0.0.1:9090"}}.Validate())
+	require.Error(t, GatewayConfig{Target: "static:///bridge"}.Validate())
+	require.Error(t, GatewayConfig{Addresses: []string{"10.0.0.1:9090"}}.Validate())
+	require.Error(t, GatewayConfig{Target: "consul:///bridge"}.Validate())
+	require.Error(t, GatewayConfig{ServiceConfig: "round_robin"}.Validate())
+	if !xdsSupported {
+		require.Error(t, GatewayConfig{Target: "xds:///bridge"}.Validate())
+		require.Error(t, GRPCConfig{XDS: true}.Validate())
+	}
+	require.Equal(t, "localhost:9090", gatewayTarget(Config{GRPCPort: "9090"}))
+}
+
+func TestGatewayStaticTarget(t *testing.T) {
+	var (
+		addresses []string
+		healths   []*countingHealth
+	)
+	for i := 0; i < 2; i++ {
+		listen, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		server, err := newGRPCServer(GRPCConfig{})
+		require.NoError(t, err)
+		health := &countingHealth{}
+		grpc_health_v1.RegisterHealthServer(server, health)
+		go server.Serve(listen) //nolint:errcheck
+		defer server.GracefulStop()
+		addresses = append(addresses, listen.Addr().String())
+		healths = append(healths, health)
+	}
+
+	// The gateway balances the requests between the addresses of the static target
+	cfg := GatewayConfig{
+		Target:        "static:///bridge",
+		Addresses:     addresses,
+		ServiceConfig: `{"loadBalancingConfig": [{"round_robin": {}}]}`,
+		Authority:     "bridge.mesh",
+	}
+	require.NoError(t, cfg.Validate())
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, gatewayDialOptions(cfg)...)
+	conn, err := grpc.Dial(gatewayTarget(Config{Gateway: cfg}), opts...)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+	for i := 0; i < 10; i++ {
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{}, grpc.WaitForReady(true))
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(10), healths[0].checks.Load()+healths[1].checks.Load())
+	require.NotZero(t, healths[0].checks.Load())
+	require.NotZero(t, healths[1].checks.Load())
+}
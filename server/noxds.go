@@ -0,0 +1,17 @@
+//go:build !xds
+// +build !xds
+
+package server
+
+import (
+	"errors"
+
+	"google.golang.org/grpc"
+)
+
+// xdsSupported is whether the binary is built with the xDS support.
+const xdsSupported = false
+
+func newXDSServer(opts []grpc.ServerOption) (grpcServer, error) {
+	return nil, errors.New("the binary is built without the xds tag")
+}
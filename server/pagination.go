@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+	"math"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPageEnd is the max result a page can end at, even for the admin requests, so the offsets and the limits are
+// converted to int on every platform without overflowing.
+const maxPageEnd = math.MaxInt32
+
+// pageLimit returns the limit of a paginated request, the default page limit when it's not set. It fails when
+// the limit is over the max page limit or the page ends after the max results. The requests with the admin
+// token have the admin max page limit and no max results, for the bulk consumers.
+func (s *bridgeService) pageLimit(ctx context.Context, limit uint32, offset uint64) (uint32, error) {
+	if limit == 0 {
+		limit = s.defaultPageLimit
+	}
+	maxLimit, maxResults := s.maxPageLimit, s.maxPageResults
+	if s.adminMaxPageLimit > maxLimit && s.checkAdmin(ctx) == nil {
+		maxLimit, maxResults = s.adminMaxPageLimit, 0
+	}
+	if limit > maxLimit {
+		return 0, status.Errorf(codes.InvalidArgument, "limit %d exceeds the max page limit %d", limit, maxLimit)
+	}
+	if offset > maxPageEnd || uint64(limit) > maxPageEnd-offset {
+		return 0, status.Errorf(codes.InvalidArgument, "the page of offset %d and limit %d ends over the result %d", offset, limit, uint64(maxPageEnd))
+	}
+	if maxResults > 0 && offset+uint64(limit) > maxResults {
+		return 0, status.Errorf(codes.InvalidArgument, "the page ends at the result %d, over the max of %d results that can be paginated", offset+uint64(limit), maxResults)
+	}
+	return limit, nil
+}
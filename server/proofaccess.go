@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// relayerKeyHeader is the header with the key of a consumer of the restricted proofs.
+// The http gateway forwards it as grpc metadata.
+const relayerKeyHeader = "x-relayer-key"
+
+const (
+	// proofConsumerKeyLength is the length in bytes of the keys of the consumers
+	proofConsumerKeyLength = 32
+	// maxProofConsumerNameLength is the max length in bytes of the name of a consumer
+	maxProofConsumerNameLength = 64
+)
+
+// proofAccess caches the mode of the proofs and the keys of their consumers, reloading them from the storage every
+// refresh interval so the changes made through another instance are applied.
+type proofAccess struct {
+	storage           bridgeServiceStorage
+	defaultRestricted bool
+	refreshInterval   time.Duration
+	now               func() time.Time
+
+	mu         sync.Mutex
+	loadedAt   time.Time
+	access     etherman.ProofAccess
+	consumers  []etherman.ProofConsumer
+	keyHashes  map[common.Hash]string
+	configured bool
+}
+
+func newProofAccess(cfg ProofAccessConfig, storage bridgeServiceStorage) *proofAccess {
+	return &proofAccess{
+		storage:           storage,
+		defaultRestricted: cfg.Restricted,
+		refreshInterval:   cfg.RefreshInterval.Duration,
+		now:               time.Now,
+	}
+}
+
+// hashProofConsumerKey returns the hash of the key of a consumer, as stored.
+func hashProofConsumerKey(key string) common.Hash {
+	return sha256.Sum256([]byte(key))
+}
+
+// load returns the mode of the proofs, if it was set by an operator, and the consumers, reloaded when they are
+// older than the refresh interval.
+func (a *proofAccess) load(ctx context.Context) (etherman.ProofAccess, bool, []etherman.ProofConsumer, map[common.Hash]string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.loadedAt.IsZero() && a.now().Sub(a.loadedAt) < a.refreshInterval {
+		return a.access, a.configured, a.consumers, a.keyHashes, nil
+	}
+	access, err := a.storage.GetProofAccess(ctx, nil)
+	configured := err == nil
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		access, err = &etherman.ProofAccess{Restricted: a.defaultRestricted}, nil
+	}
+	if err != nil {
+		return etherman.ProofAccess{}, false, nil, nil, err
+	}
+	consumers, err := a.storage.GetProofConsumers(ctx, nil)
+	if err != nil {
+		return etherman.ProofAccess{}, false, nil, nil, err
+	}
+	keyHashes := make(map[common.Hash]string, len(consumers))
+	for _, c := range consumers {
+		keyHashes[c.KeyHash] = c.Name
+	}
+	a.access, a.configured, a.consumers, a.keyHashes, a.loadedAt = *access, configured, consumers, keyHashes, a.now()
+	return a.access, a.configured, a.consumers, a.keyHashes, nil
+}
+
+// invalidate reloads the mode and the consumers in the next request, so the changes of this instance apply at once.
+func (a *proofAccess) invalidate() {
+	a.mu.Lock()
+	a.loadedAt = time.Time{}
+	a.mu.Unlock()
+}
+
+// checkProofConsumer checks that the request can get the proofs: they are public, or the request carries the key of
+// an allowed consumer or the admin token.
+func (s *bridgeService) checkProofConsumer(ctx context.Context) error {
+	access, _, _, keyHashes, err := s.proofAccess.load(ctx)
+	if err != nil {
+		return err
+	}
+	if !access.Restricted || s.checkAdmin(ctx) == nil {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range md.Get(relayerKeyHeader) {
+		if _, found := keyHashes[hashProofConsumerKey(key)]; found {
+			return nil
+		}
+	}
+	return status.Error(codes.PermissionDenied, "the proofs are restricted to the allowed consumers")
+}
+
+// GetProofAccess returns if the proofs are restricted and the allowed consumers. Bridge rest API admin endpoint
+func (s *bridgeService) GetProofAccess(ctx context.Context, req *pb.GetProofAccessRequest) (*pb.GetProofAccessResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	access, configured, consumers, _, err := s.proofAccess.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pbConsumers := make([]*pb.ProofConsumer, 0, len(consumers))
+	for _, c := range consumers {
+		pbConsumers = append(pbConsumers, &pb.ProofConsumer{
+			Name:      c.Name,
+			CreatedAt: uint64(c.CreatedAt.Unix()),
+		})
+	}
+	res := &pb.GetProofAccessResponse{
+		Restricted: access.Restricted,
+		Reason:     access.Reason,
+		Consumers:  pbConsumers,
+	}
+	if configured {
+		res.UpdatedAt = unixTime(access.UpdatedAt)
+	}
+	return res, nil
+}
+
+// SetProofAccess restricts the proofs to the allowed consumers, or makes them public again, in every instance.
+// Bridge rest API admin endpoint
+func (s *bridgeService) SetProofAccess(ctx context.Context, req *pb.SetProofAccessRequest) (*pb.SetProofAccessResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkConfigApproval(ctx, "SetProofAccess"); err != nil {
+		return nil, err
+	}
+	return idempotent(ctx, s, "SetProofAccess", req, func() (*pb.SetProofAccessResponse, error) {
+		access := &etherman.ProofAccess{
+			Restricted: req.Restricted,
+			Reason:     req.Reason,
+			UpdatedAt:  time.Now(),
+		}
+		if err := s.storage.SetProofAccess(ctx, access, nil); err != nil {
+			return nil, err
+		}
+		s.proofAccess.invalidate()
+		log.Warnf("proofs restricted to the allowed consumers: %t, reason: %s", req.Restricted, req.Reason)
+		return &pb.SetProofAccessResponse{}, nil
+	})
+}
+
+// AddProofConsumer allows a consumer to get the restricted proofs, returning its new key. The key of an existing
+// consumer is rotated. Bridge rest API admin endpoint
+func (s *bridgeService) AddProofConsumer(ctx context.Context, req *pb.AddProofConsumerRequest) (*pb.AddProofConsumerResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkConfigApproval(ctx, "AddProofConsumer"); err != nil {
+		return nil, err
+	}
+	if req.Name == "" || len(req.Name) > maxProofConsumerNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "the name of the consumer must have 1 to %d bytes", maxProofConsumerNameLength)
+	}
+	b := make([]byte, proofConsumerKeyLength)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	key := hex.EncodeToString(b)
+	consumer := &etherman.ProofConsumer{
+		Name:      req.Name,
+		KeyHash:   hashProofConsumerKey(key),
+		CreatedAt: time.Now(),
+	}
+	if err := s.storage.AddProofConsumer(ctx, consumer, nil); err != nil {
+		return nil, err
+	}
+	s.proofAccess.invalidate()
+	log.Infof("proof consumer %s added", req.Name)
+	return &pb.AddProofConsumerResponse{
+		Consumer: &pb.ProofConsumer{
+			Name:      consumer.Name,
+			CreatedAt: uint64(consumer.CreatedAt.Unix()),
+		},
+		Key: key,
+	}, nil
+}
+
+// DeleteProofConsumer removes a consumer of the restricted proofs. Bridge rest API admin endpoint
+func (s *bridgeService) DeleteProofConsumer(ctx context.Context, req *pb.DeleteProofConsumerRequest) (*pb.DeleteProofConsumerResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkConfigApproval(ctx, "DeleteProofConsumer"); err != nil {
+		return nil, err
+	}
+	return idempotent(ctx, s, "DeleteProofConsumer", req, func() (*pb.DeleteProofConsumerResponse, error) {
+		if err := s.storage.DeleteProofConsumer(ctx, req.Name, nil); err != nil {
+			return nil, err
+		}
+		s.proofAccess.invalidate()
+		log.Infof("proof consumer %s deleted", req.Name)
+		return &pb.DeleteProofConsumerResponse{}, nil
+	})
+}
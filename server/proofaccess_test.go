@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// accessStorage stores the mode and the consumers of the proofs in memory, shared by the instances of the tests.
+type accessStorage struct {
+	*benchStorage
+	access    *etherman.ProofAccess
+	consumers []etherman.ProofConsumer
+}
+
+func (s *accessStorage) GetProofAccess(ctx context.Context, dbTx pgx.Tx) (*etherman.ProofAccess, error) {
+	if s.access == nil {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return s.access, nil
+}
+
+func (s *accessStorage) SetProofAccess(ctx context.Context, access *etherman.ProofAccess, dbTx pgx.Tx) error {
+	s.access = access
+	return nil
+}
+
+func (s *accessStorage) GetProofConsumers(ctx context.Context, dbTx pgx.Tx) ([]etherman.ProofConsumer, error) {
+	return s.consumers, nil
+}
+
+func (s *accessStorage) AddProofConsumer(ctx context.Context, consumer *etherman.ProofConsumer, dbTx pgx.Tx) error {
+	for i := range s.consumers {
+		if s.consumers[i].Name == consumer.Name {
+			s.consumers[i] = *consumer
+			return nil
+		}
+	}
+	s.consumers = append(s.consumers, *consumer)
+	return nil
+}
+
+func (s *accessStorage) DeleteProofConsumer(ctx context.Context, name string, dbTx pgx.Tx) error {
+	for i := range s.consumers {
+		if s.consumers[i].Name == name {
+			s.consumers = append(s.consumers[:i], s.consumers[i+1:]...)
+			return nil
+		}
+	}
+	return gerror.ErrStorageNotFound
+}
+
+func TestProofAccess(t *testing.T) {
+	bench, _ := newBenchStorage(10, 5)
+	storage := &accessStorage{benchStorage: bench}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, AdminToken: "admin",
+		ProofAccess: ProofAccessConfig{RefreshInterval: types.NewDuration(time.Minute)}}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	other := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	now := time.Now()
+	other.proofAccess.now = func() time.Time { return now }
+	admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, "admin"))
+	public := context.Background()
+
+	// The proofs are public by default
+	require.NoError(t, s.checkProofConsumer(public))
+	require.NoError(t, other.checkProofConsumer(public))
+
+	_, err := s.SetProofAccess(public, &pb.SetProofAccessRequest{Restricted: true})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.SetProofAccess(admin, &pb.SetProofAccessRequest{Restricted: true, Reason: "incident"})
+	require.NoError(t, err)
+	res, err := s.AddProofConsumer(admin, &pb.AddProofConsumerRequest{Name: "relayer"})
+	require.NoError(t, err)
+	require.Len(t, res.Key, 2*proofConsumerKeyLength)
+
+	// Only the consumers and the admin get the proofs
+	require.Equal(t, codes.PermissionDenied, status.Code(s.checkProofConsumer(public)))
+	relayer := metadata.NewIncomingContext(context.Background(), metadata.Pairs(relayerKeyHeader, res.Key))
+	require.NoError(t, s.checkProofConsumer(relayer))
+	require.NoError(t, s.checkProofConsumer(admin))
+	forged := metadata.NewIncomingContext(context.Background(), metadata.Pairs(relayerKeyHeader, "relayer"))
+	require.Equal(t, codes.PermissionDenied, status.Code(s.checkProofConsumer(forged)))
+	_, err = s.GetProof(public, &pb.GetProofRequest{NetId: 0, DepositCnt: 5})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// The other instance applies the change after its refresh interval
+	require.NoError(t, other.checkProofConsumer(public))
+	now = now.Add(time.Minute)
+	require.Equal(t, codes.PermissionDenied, status.Code(other.checkProofConsumer(public)))
+	require.NoError(t, other.checkProofConsumer(relayer))
+
+	access, err := s.GetProofAccess(admin, &pb.GetProofAccessRequest{})
+	require.NoError(t, err)
+	require.True(t, access.Restricted)
+	require.Equal(t, "incident", access.Reason)
+	require.NotZero(t, access.UpdatedAt)
+	require.Len(t, access.Consumers, 1)
+	require.Equal(t, "relayer", access.Consumers[0].Name)
+
+	// A rotated key replaces the previous one
+	rotated, err := s.AddProofConsumer(admin, &pb.AddProofConsumerRequest{Name: "relayer"})
+	require.NoError(t, err)
+	require.Equal(t, codes.PermissionDenied, status.Code(s.checkProofConsumer(relayer)))
+	relayer = metadata.NewIncomingContext(context.Background(), metadata.Pairs(relayerKeyHeader, rotated.Key))
+	require.NoError(t, s.checkProofConsumer(relayer))
+
+	_, err = s.DeleteProofConsumer(admin, &pb.DeleteProofConsumerRequest{Name: "relayer"})
+	require.NoError(t, err)
+	require.Equal(t, codes.PermissionDenied, status.Code(s.checkProofConsumer(relayer)))
+	_, err = s.AddProofConsumer(admin, &pb.AddProofConsumerRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The proofs are public again
+	_, err = s.SetProofAccess(admin, &pb.SetProofAccessRequest{Restricted: false})
+	require.NoError(t, err)
+	require.NoError(t, s.checkProofConsumer(public))
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Sources of the tree nodes read to build a proof
+const (
+	nodeSourceMemory = "memory"
+	nodeSourceCache  = "cache"
+	nodeSourceDB     = "db"
+)
+
+// proofTrace records the queries and the tree nodes read to build a proof. The methods of a nil trace do nothing,
+// so the proofs are built in the same way with and without a trace.
+type proofTrace struct {
+	start time.Time
+	steps []*pb.ProofTraceStep
+	nodes []*pb.ProofTraceNode
+}
+
+func newProofTrace() *proofTrace {
+	return &proofTrace{start: time.Now()}
+}
+
+// step records a query that started at start and has just finished.
+func (t *proofTrace) step(name string, start time.Time) {
+	if t == nil {
+		return
+	}
+	t.steps = append(t.steps, &pb.ProofTraceStep{
+		Name:   name,
+		TimeUs: uint64(time.Since(start).Microseconds()),
+	})
+}
+
+// node records a node of the tree read at start, with its children and the one added to the proof.
+func (t *proofTrace) node(height uint, key, left, right, sibling [bridgectrl.KeyLen]byte, source string, start time.Time) {
+	if t == nil {
+		return
+	}
+	t.nodes = append(t.nodes, &pb.ProofTraceNode{
+		Height:  uint32(height),
+		Key:     common.Hash(key).Hex(),
+		Left:    common.Hash(left).Hex(),
+		Right:   common.Hash(right).Hex(),
+		Sibling: common.Hash(sibling).Hex(),
+		Source:  source,
+		TimeUs:  uint64(time.Since(start).Microseconds()),
+	})
+}
+
+func (t *proofTrace) toPb() *pb.ProofTrace {
+	if t == nil {
+		return nil
+	}
+	return &pb.ProofTrace{
+		Steps:       t.steps,
+		Nodes:       t.nodes,
+		TotalTimeUs: uint64(time.Since(t.start).Microseconds()),
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const (
+	// readinessService is the service name used in the gRPC health requests to check the readiness
+	readinessService = "readiness"
+	// readinessDBTimeout is the max time to wait for the db ping
+	readinessDBTimeout = 2 * time.Second
+)
+
+// SyncStatusReporter reports the synchronization status of a network.
+type SyncStatusReporter interface {
+	NetworkID() uint
+	// SyncStatus returns if the initial synchronization is done and the number of blocks
+	// that the synced data is behind the network head
+	SyncStatus() (bool, uint64)
+}
+
+type readinessStorage interface {
+	Ping(ctx context.Context) error
+}
+
+// ReadinessChecker checks if the service is able to serve up to date data. Unlike the
+// liveness, it depends on the db connectivity and on the synchronization lag.
+type ReadinessChecker struct {
+	storage       readinessStorage
+	synchronizers []SyncStatusReporter
+	maxSyncLag    uint64
+}
+
+// NewReadinessChecker creates a new readiness checker.
+func NewReadinessChecker(cfg Config, storage interface{}, synchronizers []SyncStatusReporter) *ReadinessChecker {
+	return &ReadinessChecker{
+		storage:       storage.(readinessStorage),
+		synchronizers: synchronizers,
+		maxSyncLag:    cfg.MaxSyncLag,
+	}
+}
+
+// Check returns an error if the service is not ready to serve traffic.
+func (r *ReadinessChecker) Check(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessDBTimeout)
+	defer cancel()
+	if err := r.storage.Ping(ctx); err != nil {
+		return fmt.Errorf("database is not reachable: %w", err)
+	}
+	for _, s := range r.synchronizers {
+		synced, lag := s.SyncStatus()
+		if !synced {
+			return fmt.Errorf("networkID %d is not synced yet", s.NetworkID())
+		}
+		if lag > r.maxSyncLag {
+			return fmt.Errorf("networkID %d is %d blocks behind the head, max allowed: %d", s.NetworkID(), lag, r.maxSyncLag)
+		}
+	}
+	return nil
+}
+
+// readyzHandler serves the readiness check for the http probes.
+func (r *ReadinessChecker) readyzHandler(w http.ResponseWriter, req *http.Request, _ map[string]string) {
+	if err := r.Check(req.Context()); err != nil {
+		log.Warn("service not ready: ", err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
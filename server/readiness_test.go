@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pingStorage is a db whose ping fails with err.
+type pingStorage struct {
+	err error
+}
+
+func (s *pingStorage) Ping(ctx context.Context) error {
+	return s.err
+}
+
+// syncReporter is a network with a fixed sync status.
+type syncReporter struct {
+	networkID uint
+	synced    bool
+	lag       uint64
+}
+
+func (s *syncReporter) NetworkID() uint {
+	return s.networkID
+}
+
+func (s *syncReporter) SyncStatus() (bool, uint64) {
+	return s.synced, s.lag
+}
+
+func TestReadinessChecker(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pingErr error
+		l2      syncReporter
+		err     string
+	}{
+		{name: "ready", l2: syncReporter{networkID: 1, synced: true, lag: 10}},
+		{name: "db not reachable", pingErr: errors.New("connection refused"), l2: syncReporter{networkID: 1, synced: true}, err: "database is not reachable: connection refused"},
+		{name: "not synced", l2: syncReporter{networkID: 1}, err: "networkID 1 is not synced yet"},
+		{name: "behind the head", l2: syncReporter{networkID: 1, synced: true, lag: 11}, err: "networkID 1 is 11 blocks behind the head, max allowed: 10"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l1, l2 := &syncReporter{synced: true}, tc.l2
+			r := NewReadinessChecker(Config{MaxSyncLag: 10}, &pingStorage{err: tc.pingErr}, []SyncStatusReporter{l1, &l2})
+			err := r.Check(context.Background())
+			rec := httptest.NewRecorder()
+			r.readyzHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil), nil)
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, rec.Code)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+			require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		})
+	}
+
+	// The service is ready without a checker
+	var r *ReadinessChecker
+	require.NoError(t, r.Check(context.Background()))
+}
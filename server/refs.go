@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxRefIDLength is the max length in bytes of the external reference id of a deposit
+const maxRefIDLength = 128
+
+// hasRefs returns if the deposits to the address can have reference ids: the zero address has no session, so any
+// reference id resolved for it would be meaningless.
+func hasRefs(addr common.Address) bool {
+	return addr != (common.Address{})
+}
+
+// canReadRefs returns if the request can read the reference ids of the deposits to the address: the ids of the
+// ledger of an integrator are only returned to the session of the address and to the admin.
+func (s *bridgeService) canReadRefs(ctx context.Context, addr common.Address) bool {
+	if s.checkAdmin(ctx) == nil {
+		return true
+	}
+	sessionAddr, err := s.checkSession(ctx)
+	return err == nil && sessionAddr == addr
+}
+
+// fillRefIDs sets the reference ids of the deposits to the address, if it has them and the request can read them. The deposits
+// are cached without them, since they are shared by all the requests.
+func (s *bridgeService) fillRefIDs(ctx context.Context, addr common.Address, deposits []*pb.Deposit) error {
+	if len(deposits) == 0 || !hasRefs(addr) || !s.canReadRefs(ctx, addr) {
+		return nil
+	}
+	refs, err := s.storage.GetDepositRefs(ctx, addr, nil)
+	if err != nil {
+		return err
+	}
+	refIDs := make(map[[2]uint]string, len(refs))
+	for _, ref := range refs {
+		refIDs[[2]uint{ref.NetworkID, ref.DepositCnt}] = ref.RefID
+	}
+	for _, deposit := range deposits {
+		deposit.RefId = refIDs[[2]uint{uint(deposit.NetworkId), uint(deposit.DepositCnt)}]
+	}
+	return nil
+}
+
+// getRefID returns the reference id of the deposit, empty if the request can't read it or there is none.
+func (s *bridgeService) getRefID(ctx context.Context, deposit *etherman.Deposit) (string, error) {
+	if !hasRefs(deposit.DestinationAddress) || !s.canReadRefs(ctx, deposit.DestinationAddress) {
+		return "", nil
+	}
+	ref, err := s.storage.GetDepositRef(ctx, deposit.NetworkID, deposit.DepositCount, nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return ref.RefID, nil
+}
+
+// SetDepositRef sets the external reference id of a deposit to the address of the session, e.g. the id of the
+// order in the ledger of an exchange, returned with the deposit and in its webhook events. An empty id removes it.
+// Bridge rest API endpoint
+func (s *bridgeService) SetDepositRef(ctx context.Context, req *pb.SetDepositRefRequest) (*pb.SetDepositRefResponse, error) {
+	addr, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if len(req.RefId) > maxRefIDLength {
+		return nil, status.Errorf(codes.InvalidArgument, "reference id longer than %d bytes", maxRefIDLength)
+	}
+	deposit, err := s.storage.GetDeposit(ctx, uint(req.DepositCnt), uint(req.NetId), nil)
+	if err != nil {
+		return nil, err
+	}
+	if deposit.DestinationAddress != addr {
+		return nil, status.Error(codes.PermissionDenied, "the deposit is not to the address of the session")
+	}
+	err = s.storage.SetDepositRef(ctx, &etherman.DepositRef{
+		NetworkID:  deposit.NetworkID,
+		DepositCnt: deposit.DepositCount,
+		Address:    addr,
+		RefID:      req.RefId,
+		UpdatedAt:  time.Now(),
+	}, nil)
+	if errors.Is(err, gerror.ErrAlreadyProcessed) {
+		return nil, status.Errorf(codes.AlreadyExists, "the reference id %s is set on another deposit", req.RefId)
+	} else if err != nil {
+		return nil, err
+	}
+	return &pb.SetDepositRefResponse{}, nil
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDepositRef(t *testing.T) {
+	ctx := context.Background()
+	key := common.FromHex("0x4eba8ff6000ded1e4f8a4e9bba0b3eb3f1e9a1d9156a16e45bb4a3c2a8b00f9b")
+	privateKey, err := crypto.ToECDSA(key)
+	require.NoError(t, err)
+	wallet := crypto.PubkeyToAddress(privateKey.PublicKey)
+	other := common.HexToAddress("0x01")
+
+	bench, _ := newBenchStorage(4, 0)
+	for _, deposit := range bench.deposits {
+		deposit.DestinationAddress = wallet
+	}
+	bench.deposits[3].DestinationAddress = other
+	storage := newMemStorage(bench)
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, Session: SessionConfig{
+		Secret:       "secret",
+		ChainID:      1,
+		ChallengeTTL: types.NewDuration(time.Minute),
+		SessionTTL:   types.NewDuration(time.Hour),
+	}}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	challenge, err := s.GetSessionChallenge(ctx, &pb.GetSessionChallengeRequest{Address: wallet.Hex()})
+	require.NoError(t, err)
+	session, err := s.CreateSession(ctx, &pb.CreateSessionRequest{Address: wallet.Hex(), Nonce: challenge.Nonce, ExpiresAt: challenge.ExpiresAt, Signature: signChallenge(t, challenge.TypedData, key)})
+	require.NoError(t, err)
+	sessionCtx := withSession(ctx, session.Token)
+
+	// The ref needs a session on a deposit to its address
+	_, err = s.SetDepositRef(ctx, &pb.SetDepositRefRequest{NetId: 0, DepositCnt: 1, RefId: "order-1"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.SetDepositRef(sessionCtx, &pb.SetDepositRefRequest{NetId: 0, DepositCnt: 3, RefId: "order-1"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.SetDepositRef(sessionCtx, &pb.SetDepositRefRequest{NetId: 0, DepositCnt: 1, RefId: string(make([]byte, maxRefIDLength+1))})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.SetDepositRef(sessionCtx, &pb.SetDepositRefRequest{NetId: 0, DepositCnt: 1, RefId: "order-1"})
+	require.NoError(t, err)
+
+	// The ref is unique per address
+	_, err = s.SetDepositRef(sessionCtx, &pb.SetDepositRefRequest{NetId: 0, DepositCnt: 2, RefId: "order-1"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	// The ref is only returned to the session of the address, even once the deposits are cached
+	bridges, err := s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: wallet.Hex()})
+	require.NoError(t, err)
+	for _, deposit := range bridges.Deposits {
+		require.Empty(t, deposit.RefId)
+	}
+	history, err := s.GetWalletHistory(sessionCtx, &pb.GetWalletHistoryRequest{})
+	require.NoError(t, err)
+	for _, deposit := range history.Deposits {
+		if deposit.Deposit.DepositCnt == 1 {
+			require.Equal(t, "order-1", deposit.Deposit.RefId)
+		} else {
+			require.Empty(t, deposit.Deposit.RefId)
+		}
+	}
+	bridge, err := s.GetBridge(sessionCtx, &pb.GetBridgeRequest{NetId: 0, DepositCnt: 1})
+	require.NoError(t, err)
+	require.Equal(t, "order-1", bridge.Deposit.RefId)
+	bridge, err = s.GetBridge(ctx, &pb.GetBridgeRequest{NetId: 0, DepositCnt: 1})
+	require.NoError(t, err)
+	require.Empty(t, bridge.Deposit.RefId)
+
+	// The refs are only resolved for the non-zero hex addresses, the other deposits are returned without them
+	for _, addr := range []string{"", "0xinvalid", wallet.Hex()[:20], common.Address{}.Hex()} {
+		bridges, err = s.GetBridges(sessionCtx, &pb.GetBridgesRequest{DestAddr: addr})
+		require.NoError(t, err, addr)
+		for _, deposit := range bridges.Deposits {
+			require.Empty(t, deposit.RefId, addr)
+		}
+	}
+	bench.deposits[1].DestinationAddress = common.Address{}
+	bridge, err = s.GetBridge(sessionCtx, &pb.GetBridgeRequest{NetId: 0, DepositCnt: 1})
+	require.NoError(t, err)
+	require.Empty(t, bridge.Deposit.RefId)
+	bench.deposits[1].DestinationAddress = wallet
+
+	// An empty ref removes it, freeing the id
+	_, err = s.SetDepositRef(sessionCtx, &pb.SetDepositRefRequest{NetId: 0, DepositCnt: 1})
+	require.NoError(t, err)
+	_, err = s.SetDepositRef(sessionCtx, &pb.SetDepositRefRequest{NetId: 0, DepositCnt: 2, RefId: "order-1"})
+	require.NoError(t, err)
+	require.Len(t, storage.refs, 1)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/replication"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetReplication sets the monitor of the read replicas of the database, nil if they are not checked.
+func (s *bridgeService) SetReplication(monitor *replication.Monitor) {
+	s.replication = monitor
+}
+
+// GetReplication returns the lag and the divergence of the read replicas at their last check. The request of a
+// replica fails as unavailable if it's unhealthy, for the health checks of the load balancers.
+// Bridge rest API endpoint
+func (s *bridgeService) GetReplication(ctx context.Context, req *pb.GetReplicationRequest) (*pb.GetReplicationResponse, error) {
+	res := &pb.GetReplicationResponse{Replicas: []*pb.Replica{}}
+	for _, replica := range s.replication.Statuses() {
+		if req.Replica != "" && req.Replica != replica.Name {
+			continue
+		}
+		if req.Replica != "" && !replica.Healthy {
+			return nil, status.Errorf(codes.Unavailable, "replica %s is unhealthy: lag %d bytes, divergence %q, error %q",
+				replica.Name, replica.LagBytes, replica.Divergence, replica.Error)
+		}
+		pbReplica := &pb.Replica{
+			Name:          replica.Name,
+			Region:        replica.Region,
+			LagBytes:      replica.LagBytes,
+			ReplayDelayMs: uint64(replica.ReplayDelay.Milliseconds()),
+			Diverged:      replica.Divergence != "",
+			Divergence:    replica.Divergence,
+			Healthy:       replica.Healthy,
+			Error:         replica.Error,
+		}
+		if !replica.CheckedAt.IsZero() {
+			pbReplica.CheckedAt = uint64(replica.CheckedAt.Unix())
+		}
+		res.Replicas = append(res.Replicas, pbReplica)
+	}
+	if req.Replica != "" && len(res.Replicas) == 0 {
+		return nil, status.Errorf(codes.NotFound, "replica %s not found", req.Replica)
+	}
+	return res, nil
+}
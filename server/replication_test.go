@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/replication"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// replicaStorage is a replica replaying the primary, without synced blocks.
+type replicaStorage struct{}
+
+func (replicaStorage) GetCurrentLSN(ctx context.Context, dbTx pgx.Tx) (string, error) {
+	return "0/0", nil
+}
+
+func (replicaStorage) GetBlockUntil(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.Block, error) {
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (replicaStorage) GetReplayStatus(ctx context.Context, lsn string, dbTx pgx.Tx) (*etherman.ReplayStatus, error) {
+	return &etherman.ReplayStatus{InRecovery: true}, nil
+}
+
+func (replicaStorage) GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
+	return nil, gerror.ErrStorageNotFound
+}
+
+func TestGetReplication(t *testing.T) {
+	ctx := context.Background()
+	bench, _ := newBenchStorage(1, 0)
+	s := NewBridgeService(Config{CacheSize: 100}, benchHeight, []uint{0, 1}, bench, nil)
+
+	// Without the monitor there are no replicas
+	resp, err := s.GetReplication(ctx, &pb.GetReplicationRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Replicas)
+
+	// The replicas not checked yet are unavailable
+	monitor := replication.NewMonitor(replication.Config{}, replicaStorage{}, []replication.Replica{{Name: "eu", Storage: replicaStorage{}}}, []uint{0})
+	s.SetReplication(monitor)
+	resp, err = s.GetReplication(ctx, &pb.GetReplicationRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Replicas, 1)
+	require.False(t, resp.Replicas[0].Healthy)
+	_, err = s.GetReplication(ctx, &pb.GetReplicationRequest{Replica: "eu"})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = s.GetReplication(ctx, &pb.GetReplicationRequest{Replica: "us"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	monitor.Check(ctx, time.Now())
+	resp, err = s.GetReplication(ctx, &pb.GetReplicationRequest{Replica: "eu"})
+	require.NoError(t, err)
+	require.True(t, resp.Replicas[0].Healthy)
+	require.NotZero(t, resp.Replicas[0].CheckedAt)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// searchMinPrefixLen is the min number of hex chars of a search, the shorter prefixes match too many events
+	searchMinPrefixLen = 6
+	// searchMaxPrefixLen is the number of hex chars of a tx hash, the longest searched value
+	searchMaxPrefixLen = 64
+)
+
+var searchQueryRegexp = regexp.MustCompile(`^(0[xX])?[0-9a-fA-F]*$`)
+
+// parseSearchQuery returns the hex prefix of a search query, without 0x. The truncated addresses and hashes, as
+// 0x1234…abcd, are searched by the part before the ellipsis.
+func parseSearchQuery(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if i := strings.Index(query, "…"); i >= 0 {
+		query = query[:i]
+	}
+	if i := strings.Index(query, "..."); i >= 0 {
+		query = query[:i]
+	}
+	if !searchQueryRegexp.MatchString(query) {
+		return "", status.Errorf(codes.InvalidArgument, "invalid search %s, must be the hex prefix of an address or a tx hash", query)
+	}
+	prefix := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(query, "0x"), "0X"))
+	if len(prefix) < searchMinPrefixLen || len(prefix) > searchMaxPrefixLen {
+		return "", status.Errorf(codes.InvalidArgument, "invalid search %s, must have between %d and %d hex chars", query, searchMinPrefixLen, searchMaxPrefixLen)
+	}
+	return prefix, nil
+}
+
+// Search returns the deposits and the claims whose destination address or tx hash start with the hex prefix of
+// the query, from the last ones.
+// Bridge rest API endpoint
+func (s *bridgeService) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	prefix, err := parseSearchQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := s.pageLimit(ctx, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	deposits, err := s.storage.SearchDeposits(ctx, prefix, uint(limit), uint(req.Offset), nil)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := s.storage.SearchClaims(ctx, prefix, uint(limit), uint(req.Offset), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res pb.SearchResponse
+	for _, deposit := range deposits {
+		claimTxHash, err := s.GetDepositStatus(ctx, deposit.DepositCount, deposit.DestinationNetwork)
+		if err != nil {
+			return nil, err
+		}
+		decimals, formattedAmount := s.formatDepositAmount(ctx, deposit)
+		res.Deposits = append(res.Deposits, &pb.Deposit{
+			LeafType:        uint32(deposit.LeafType),
+			OrigNet:         uint32(deposit.OriginalNetwork),
+			OrigAddr:        deposit.OriginalAddress.Hex(),
+			Amount:          deposit.Amount.String(),
+			DestNet:         uint32(deposit.DestinationNetwork),
+			DestAddr:        deposit.DestinationAddress.Hex(),
+			BlockNum:        deposit.BlockNumber,
+			DepositCnt:      uint64(deposit.DepositCount),
+			NetworkId:       uint32(deposit.NetworkID),
+			TxHash:          deposit.TxHash.String(),
+			ClaimTxHash:     claimTxHash,
+			Metadata:        "0x" + hex.EncodeToString(deposit.Metadata),
+			ReadyForClaim:   deposit.ReadyForClaim,
+			Decimals:        decimals,
+			FormattedAmount: formattedAmount,
+			LogIndex:        uint32(deposit.LogIndex),
+			BlockTime:       unixTime(deposit.BlockTime),
+			SyncedAt:        unixTime(deposit.SyncedAt),
+			Integration:     deposit.Integration,
+			Sender:          optionalAddress(deposit.Sender),
+			Depositor:       optionalAddress(deposit.Depositor),
+			GlobalIndex:     pbGlobalIndex(deposit.NetworkID, deposit.DepositCount),
+		})
+	}
+	for _, claim := range claims {
+		decimals, formattedAmount := s.formatTokenAmount(ctx, claim.OriginalNetwork, claim.OriginalAddress, nil, claim.Amount)
+		res.Claims = append(res.Claims, &pb.Claim{
+			Index:           uint64(claim.Index),
+			OrigNet:         uint32(claim.OriginalNetwork),
+			OrigAddr:        claim.OriginalAddress.Hex(),
+			Amount:          claim.Amount.String(),
+			NetworkId:       uint32(claim.NetworkID),
+			DestAddr:        claim.DestinationAddress.Hex(),
+			BlockNum:        claim.BlockNumber,
+			TxHash:          claim.TxHash.String(),
+			Decimals:        decimals,
+			FormattedAmount: formattedAmount,
+			LogIndex:        uint32(claim.LogIndex),
+			BlockTime:       unixTime(claim.BlockTime),
+			SyncedAt:        unixTime(claim.SyncedAt),
+			MessageCall:     claim.MessageCall,
+		})
+	}
+	s.fillDisplayStatuses(res.Deposits)
+	s.fillTokenLists(ctx, res.Deposits)
+	return &res, nil
+}
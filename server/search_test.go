@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// searchStorage matches the prefixes with the destination addresses and the tx hashes of the bench deposits.
+type searchStorage struct {
+	*benchStorage
+}
+
+func (s *searchStorage) SearchDeposits(ctx context.Context, prefix string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	var deposits []*etherman.Deposit
+	for _, deposit := range s.deposits {
+		if strings.HasPrefix(strings.ToLower(deposit.DestinationAddress.Hex()[2:]), prefix) || strings.HasPrefix(deposit.TxHash.Hex()[2:], prefix) {
+			deposits = append(deposits, deposit)
+		}
+	}
+	return deposits, nil
+}
+
+func (s *searchStorage) SearchClaims(ctx context.Context, prefix string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Claim, error) {
+	return nil, nil
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	for query, prefix := range map[string]string{
+		"0xAbCdEf12":                   "abcdef12",
+		" abcdef ":                     "abcdef",
+		"0x1234ab…cdef":                "1234ab",
+		"0x1234abc...def":              "1234abc",
+		"0X" + strings.Repeat("a", 64): strings.Repeat("a", 64),
+	} {
+		p, err := parseSearchQuery(query)
+		require.NoError(t, err, query)
+		require.Equal(t, prefix, p, query)
+	}
+	for _, query := range []string{"", "0x12345", "0x1234…", "0x12345g", "0x" + strings.Repeat("a", 65)} {
+		_, err := parseSearchQuery(query)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), query)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	bench, _ := newBenchStorage(4, 0)
+	s := newBenchService(bench)
+	s.storage = &searchStorage{benchStorage: bench}
+
+	resp, err := s.Search(context.Background(), &pb.SearchRequest{Query: "0xC949254d…2aec4"})
+	require.NoError(t, err)
+	require.Len(t, resp.Deposits, 4)
+	require.Equal(t, bench.deposits[0].DestinationAddress.Hex(), resp.Deposits[0].DestAddr)
+
+	// The tx hashes of the bench deposits are their deposit count
+	resp, err = s.Search(context.Background(), &pb.SearchRequest{Query: "0x" + bench.deposits[3].TxHash.Hex()[2:60]})
+	require.NoError(t, err)
+	require.Len(t, resp.Deposits, 4)
+	resp, err = s.Search(context.Background(), &pb.SearchRequest{Query: bench.deposits[3].TxHash.Hex()})
+	require.NoError(t, err)
+	require.Len(t, resp.Deposits, 1)
+	require.Equal(t, uint64(3), resp.Deposits[0].DepositCnt)
+}
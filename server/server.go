@@ -13,14 +13,15 @@ import (
 	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health/grpc_health_v1"
-	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/grpc/keepalive"
 )
 
 // RunServer runs gRPC server and HTTP gateway
-func RunServer(cfg Config, bridgeService pb.BridgeServiceServer) error {
+func RunServer(cfg Config, bridgeService pb.BridgeServiceServer, readiness *ReadinessChecker) error {
 	ctx := context.Background()
 
 	if len(cfg.GRPCPort) == 0 {
@@ -31,54 +32,104 @@ func RunServer(cfg Config, bridgeService pb.BridgeServiceServer) error {
 		return fmt.Errorf("invalid TCP port for HTTP gateway: '%s'", cfg.HTTPPort)
 	}
 
+	if err := cfg.GRPC.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Gateway.Validate(); err != nil {
+		return fmt.Errorf("invalid gateway config: %w", err)
+	}
+
 	go func() {
-		_ = runRestServer(ctx, cfg.GRPCPort, cfg.HTTPPort)
+		_ = runRestServer(ctx, cfg, bridgeService, readiness)
 	}()
 
 	go func() {
-		_ = runGRPCServer(ctx, bridgeService, cfg.GRPCPort)
+		_ = runGRPCServer(ctx, bridgeService, cfg.GRPCPort, cfg.GRPC, readiness)
 	}()
 
 	return nil
 }
 
 // HealthChecker will provide an implementation of the HealthCheck interface.
-type healthChecker struct{}
+type healthChecker struct {
+	readiness *ReadinessChecker
+}
 
 // NewHealthChecker returns a health checker according to standard package
 // grpc.health.v1.
-func newHealthChecker() *healthChecker {
-	return &healthChecker{}
+func newHealthChecker(readiness *ReadinessChecker) *healthChecker {
+	return &healthChecker{readiness: readiness}
 }
 
 // HealthCheck interface implementation.
 
-// Check returns the current status of the server for unary gRPC health requests,
-// for now if the server is up and able to respond we will always return SERVING.
+// Check returns the current status of the server for unary gRPC health requests.
+// The liveness (empty service name) is SERVING if the server is up and able to respond,
+// the readiness service also depends on the db connectivity and the sync lag.
 func (s *healthChecker) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
 	return &grpc_health_v1.HealthCheckResponse{
-		Status: grpc_health_v1.HealthCheckResponse_SERVING,
+		Status: s.status(ctx, req.Service),
 	}, nil
 }
 
-// Watch returns the current status of the server for stream gRPC health requests,
-// for now if the server is up and able to respond we will always return SERVING.
+// Watch returns the current status of the server for stream gRPC health requests.
 func (s *healthChecker) Watch(req *grpc_health_v1.HealthCheckRequest, server grpc_health_v1.Health_WatchServer) error {
 	return server.Send(&grpc_health_v1.HealthCheckResponse{
-		Status: grpc_health_v1.HealthCheckResponse_SERVING,
+		Status: s.status(server.Context(), req.Service),
 	})
 }
 
-func runGRPCServer(ctx context.Context, bridgeServer pb.BridgeServiceServer, port string) error {
+func (s *healthChecker) status(ctx context.Context, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if service == readinessService {
+		if err := s.readiness.Check(ctx); err != nil {
+			log.Warn("service not ready: ", err)
+			return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING
+}
+
+// grpcServerOptions builds the server options of the configured connection tuning.
+func grpcServerOptions(cfg GRPCConfig) []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if cfg.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize))
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(cfg.MaxSendMsgSize))
+	}
+	if cfg.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(cfg.MaxConcurrentStreams))
+	}
+	opts = append(opts,
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     cfg.MaxConnectionIdle.Duration,
+			MaxConnectionAge:      cfg.MaxConnectionAge.Duration,
+			MaxConnectionAgeGrace: cfg.MaxConnectionAgeGrace.Duration,
+			Time:                  cfg.KeepaliveTime.Duration,
+			Timeout:               cfg.KeepaliveTimeout.Duration,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.KeepaliveMinTime.Duration,
+			PermitWithoutStream: cfg.KeepalivePermitWithoutStream,
+		}),
+	)
+	return opts
+}
+
+func runGRPCServer(ctx context.Context, bridgeServer pb.BridgeServiceServer, port string, cfg GRPCConfig, readiness *ReadinessChecker) error {
 	listen, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		return err
 	}
 
-	server := grpc.NewServer()
+	server, err := newGRPCServer(cfg)
+	if err != nil {
+		return err
+	}
 	pb.RegisterBridgeServiceServer(server, bridgeServer)
 
-	healthService := newHealthChecker()
+	healthService := newHealthChecker(readiness)
 	grpc_health_v1.RegisterHealthServer(server, healthService)
 
 	c := make(chan os.Signal, 1)
@@ -94,8 +145,22 @@ func runGRPCServer(ctx context.Context, bridgeServer pb.BridgeServiceServer, por
 	return server.Serve(listen)
 }
 
+// streamPatterns are the paths of the streaming endpoints of the REST server.
+var streamPatterns = []string{"/bridge-events/{address}", "/deposit-status"}
+
+// isStreamPath returns if the path is of a streaming endpoint.
+func isStreamPath(path string) bool {
+	for _, pattern := range streamPatterns {
+		prefix, _, _ := strings.Cut(pattern, "{")
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func preflightHandler(w http.ResponseWriter, r *http.Request) {
-	headers := []string{"Content-Type", "Accept"}
+	headers := []string{"Content-Type", "Accept", "X-Admin-Token"}
 	w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ","))
 	methods := []string{"GET", "HEAD", "POST", "PUT", "DELETE"}
 	w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ","))
@@ -116,37 +181,90 @@ func allowCORS(h http.Handler) http.Handler {
 	})
 }
 
-func runRestServer(ctx context.Context, grpcPort, httpPort string) error {
+func runRestServer(ctx context.Context, cfg Config, bridgeService pb.BridgeServiceServer, readiness *ReadinessChecker) error {
+	grpcCfg := cfg.GRPC
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-	endpoint := "localhost:" + grpcPort
-	conn, err := grpc.Dial(endpoint, opts...)
+	// The gateway must accept the same message sizes than the gRPC server
+	var callOpts []grpc.CallOption
+	if grpcCfg.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(grpcCfg.MaxSendMsgSize))
+	}
+	if grpcCfg.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(grpcCfg.MaxRecvMsgSize))
+	}
+	if len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+	opts = append(opts, gatewayDialOptions(cfg.Gateway)...)
+	conn, err := grpc.Dial(gatewayTarget(cfg), opts...)
 	if err != nil {
 		return err
 	}
 
 	muxHealthOpt := runtime.WithHealthzEndpoint(grpc_health_v1.NewHealthClient(conn))
-	muxJSONOpt := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-		MarshalOptions: protojson.MarshalOptions{
-			UseProtoNames:   true,
-			EmitUnpopulated: true,
-		},
-		UnmarshalOptions: protojson.UnmarshalOptions{
-			DiscardUnknown: true,
-		},
+	muxJSONOpt := runtime.WithMarshalerOption(runtime.MIMEWildcard, newJSONMarshaler())
+	muxHeaderOpt := runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+		switch strings.ToLower(key) {
+		case adminTokenHeader:
+			return adminTokenHeader, true
+		case sessionTokenHeader:
+			return sessionTokenHeader, true
+		case relayerKeyHeader:
+			return relayerKeyHeader, true
+		case idempotencyKeyHeader:
+			return idempotencyKeyHeader, true
+		}
+		return runtime.DefaultHeaderMatcher(key)
 	})
-	mux := runtime.NewServeMux(muxJSONOpt, muxHealthOpt)
+	mux := runtime.NewServeMux(muxJSONOpt, muxHealthOpt, muxHeaderOpt)
 
-	if err := pb.RegisterBridgeServiceHandler(ctx, mux, conn); err != nil {
+	if cfg.InProcessGateway {
+		// The requests call the service directly, without encoding and decoding the messages through the gRPC server
+		err = pb.RegisterBridgeServiceHandlerServer(ctx, mux, bridgeService)
+	} else {
+		err = pb.RegisterBridgeServiceHandler(ctx, mux, conn)
+	}
+	if err != nil {
+		return err
+	}
+	if cfg.InProcessGateway {
+		// The in-process transport doesn't support the streams, they go through the gRPC server
+		streams := runtime.NewServeMux(muxJSONOpt, muxHeaderOpt)
+		if err := pb.RegisterBridgeServiceHandler(ctx, streams, conn); err != nil {
+			return err
+		}
+		for _, pattern := range streamPatterns {
+			if err := mux.HandlePath(http.MethodGet, pattern, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				streams.ServeHTTP(w, r)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := mux.HandlePath(http.MethodGet, "/readyz", readiness.readyzHandler); err != nil {
+		return err
+	}
+	metricsHandler := promhttp.Handler()
+	if err := mux.HandlePath(http.MethodGet, "/metrics", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		metricsHandler.ServeHTTP(w, r)
+	}); err != nil {
 		return err
 	}
 
+	var handler http.Handler = mux
+	if service, ok := bridgeService.(signedService); ok && service.responseSigner() != nil {
+		handler = signResponses(handler, service.responseSigner())
+	}
+	if service, ok := bridgeService.(maintenanceService); ok && service.maintenanceMonitor() != nil {
+		handler = maintenanceNotices(handler, service.maintenanceMonitor())
+	}
 	srv := &http.Server{
 		ReadTimeout: 1 * time.Second, //nolint:gomnd
-		Addr:        ":" + httpPort,
-		Handler:     allowCORS(mux),
+		Addr:        ":" + cfg.HTTPPort,
+		Handler:     allowCORS(handler),
 	}
 
 	c := make(chan os.Signal, 1)
@@ -163,6 +281,6 @@ func runRestServer(ctx context.Context, grpcPort, httpPort string) error {
 		_ = srv.Shutdown(ctx)
 	}()
 
-	log.Info("Restful Server is serving at ", httpPort)
+	log.Info("Restful Server is serving at ", cfg.HTTPPort)
 	return srv.ListenAndServe()
 }
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// watchingHealth serves the health checks, and the watches until the server closes them.
+type watchingHealth struct {
+	countingHealth
+}
+
+func (h *watchingHealth) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}); err != nil {
+		return err
+	}
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func TestGRPCServerOptions(t *testing.T) {
+	// The keepalive options are always set, their zero values keep the gRPC defaults
+	require.Len(t, grpcServerOptions(GRPCConfig{}), 2)
+	require.Len(t, grpcServerOptions(GRPCConfig{
+		MaxRecvMsgSize:       16 << 20,
+		MaxSendMsgSize:       16 << 20,
+		MaxConcurrentStreams: 100,
+	}), 5)
+
+	listen, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server, err := newGRPCServer(GRPCConfig{
+		MaxRecvMsgSize:        1024,
+		MaxConnectionAge:      types.NewDuration(200 * time.Millisecond),
+		MaxConnectionAgeGrace: types.NewDuration(200 * time.Millisecond),
+	})
+	require.NoError(t, err)
+	health := &watchingHealth{}
+	grpc_health_v1.RegisterHealthServer(server, health)
+	go server.Serve(listen) //nolint:errcheck
+	defer server.GracefulStop()
+
+	conn, err := grpc.Dial(listen.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The requests up to the max message size are served, the bigger ones are rejected before reaching the service
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: strings.Repeat("a", 512)})
+	require.NoError(t, err)
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: strings.Repeat("a", 2048)})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, int32(1), health.checks.Load())
+
+	// The streams are closed once the connection reaches its max age and the grace period elapses
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	start := time.Now()
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Less(t, time.Since(start), 2*time.Second)
+}
@@ -1,32 +1,102 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/cache"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/changefeed"
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/gerlatency"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/indexadvisor"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/maintenance"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/uint256"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/push"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/replication"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/statusrules"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/webhook"
+	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/jackc/pgx/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	activityIntervalHour = "hour"
+	activityIntervalDay  = "day"
+
+	// integrationStatsDefaultRange is the time range of the integration stats if the from time is not set
+	integrationStatsDefaultRange = 30 * 24 * time.Hour
+
+	adminQueryFormatCSV = "csv"
+
+	// maxAnnotationLength is the max length in bytes of the note of a wallet on a deposit
+	maxAnnotationLength = 1024
+	// maxPushTokenLength is the max length in bytes of the token of a push notification device
+	maxPushTokenLength = 4096
 )
 
 type bridgeService struct {
-	storage          bridgeServiceStorage
-	networkIDs       map[uint]uint8
-	height           uint8
-	defaultPageLimit uint32
-	maxPageLimit     uint32
-	version          string
-	cache            *lru.Cache[string, [][]byte]
+	storage           bridgeServiceStorage
+	networkIDs        map[uint]uint8
+	height            uint8
+	defaultPageLimit  uint32
+	maxPageLimit      uint32
+	maxPageResults    uint64
+	adminMaxPageLimit uint32
+	maxSimClaims      uint32
+	version           string
+	cache             *lru.Cache[string, [][]byte]
+	topLevels         *topLevelCache
+	statusRules       *statusrules.Rules
+	decimalsCache     *lru.Cache[string, uint8]
+	claimSimulators   map[uint]ClaimSimulator
+	adminToken        string
+	configApprovals   ConfigApprovalsConfig
+	sessions          *sessionAuth
+	maxWebhooks       int
+	maxDevices        int
+	watch             WatchConfig
+	jobs              JobsConfig
+	jobSlots          chan struct{}
+	eventSlots        chan struct{}
+	gerLatency        *gerlatency.Tracker
+	reserve           ReserveAttester
+	signer            ResponseSigner
+	claimBreakers     []ClaimBreaker
+	responses         *cache.Cache
+	proofAccess       *proofAccess
+	tokenLists        *tokenLists
+	changes           *changefeed.Feed
+	claimVersion      string
+	idempotencyTTL    time.Duration
+	batchReaders      map[uint]BatchReader
+	batchPoll         time.Duration
+	contracts         *contractBook
+	replication       *replication.Monitor
+	maintenance       *maintenance.Monitor
+	l1InfoTree        L1InfoTreeProver
 	pb.UnimplementedBridgeServiceServer
 }
 
-// NewBridgeService creates new bridge service.
-func NewBridgeService(cfg Config, height uint8, networks []uint, storage interface{}) *bridgeService {
+// NewBridgeService creates new bridge service. The claimSimulators are indexed by networkID.
+func NewBridgeService(cfg Config, height uint8, networks []uint, storage interface{}, claimSimulators map[uint]ClaimSimulator) *bridgeService {
 	var networkIDs = make(map[uint]uint8)
 	for i, network := range networks {
 		networkIDs[network] = uint8(i)
@@ -35,14 +105,41 @@ func NewBridgeService(cfg Config, height uint8, networks []uint, storage interfa
 	if err != nil {
 		panic(err)
 	}
+	decimalsCache, err := lru.New[string, uint8](cfg.CacheSize)
+	if err != nil {
+		panic(err)
+	}
+	bridgeStorage := storage.(bridgeServiceStorage)
 	return &bridgeService{
-		storage:          storage.(bridgeServiceStorage),
-		height:           height,
-		networkIDs:       networkIDs,
-		defaultPageLimit: cfg.DefaultPageLimit,
-		maxPageLimit:     cfg.MaxPageLimit,
-		version:          cfg.BridgeVersion,
-		cache:            cache,
+		storage:           bridgeStorage,
+		height:            height,
+		networkIDs:        networkIDs,
+		defaultPageLimit:  cfg.DefaultPageLimit,
+		maxPageLimit:      cfg.MaxPageLimit,
+		maxPageResults:    cfg.MaxPageResults,
+		adminMaxPageLimit: cfg.AdminMaxPageLimit,
+		maxSimClaims:      cfg.MaxSimulatedClaims,
+		version:           cfg.BridgeVersion,
+		cache:             cache,
+		topLevels:         newTopLevelCache(height, cfg.TopLevels, cfg.TopLevelsSize),
+		decimalsCache:     decimalsCache,
+		gerLatency:        gerlatency.Default,
+		claimSimulators:   claimSimulators,
+		adminToken:        cfg.AdminToken,
+		configApprovals:   cfg.ConfigApprovals,
+		sessions:          newSessionAuth(cfg.Session),
+		maxWebhooks:       cfg.Session.MaxWebhooks,
+		maxDevices:        cfg.Session.MaxDevices,
+		watch:             cfg.Watch,
+		jobs:              cfg.Jobs,
+		jobSlots:          make(chan struct{}, cfg.Jobs.MaxConcurrent),
+		eventSlots:        make(chan struct{}, cfg.MaxEventSubscribers),
+		proofAccess:       newProofAccess(cfg.ProofAccess, bridgeStorage),
+		tokenLists:        newTokenLists(cfg.TokenList, bridgeStorage),
+		claimVersion:      cfg.ClaimVersion,
+		idempotencyTTL:    cfg.IdempotencyKeyTTL.Duration,
+		batchPoll:         cfg.BatchPollInterval.Duration,
+		contracts:         &contractBook{cfg: cfg.Contracts},
 	}
 }
 
@@ -54,34 +151,69 @@ func (s *bridgeService) getNetworkID(networkID uint) (uint8, error) {
 	return tID, nil
 }
 
-// getNode returns the children hash pairs for a given parent hash.
-func (s *bridgeService) getNode(ctx context.Context, parentHash [bridgectrl.KeyLen]byte, dbTx pgx.Tx) (left, right [bridgectrl.KeyLen]byte, err error) {
+// getNode returns the children hash pairs for a given parent hash, with the source they were read from. The nodes of
+// the top levels are read from the top level cache, and are not added to the lru-cache when they are not there.
+func (s *bridgeService) getNode(ctx context.Context, parentHash [bridgectrl.KeyLen]byte, topLevel bool, dbTx pgx.Tx) (left, right [bridgectrl.KeyLen]byte, source string, err error) {
+	if topLevel {
+		if left, right, found := s.topLevels.get(parentHash); found {
+			return left, right, nodeSourceTopLevels, nil
+		}
+	}
+	source = nodeSourceMemory
 	value, ok := s.cache.Get(string(parentHash[:]))
 	if !ok {
-		var err error
-		value, err = s.storage.Get(ctx, parentHash[:], dbTx)
+		source = nodeSourceCache
+		// The nodes are indexed by their hash, so they never change in the shared cache
+		data, err := s.responses.Load(ctx, "node:"+hex.EncodeToString(parentHash[:]), nil, func() ([]byte, error) {
+			source = nodeSourceDB
+			value, err := s.storage.Get(ctx, parentHash[:], dbTx)
+			if err != nil {
+				return nil, err
+			}
+			return bytes.Join(value, nil), nil
+		})
 		if err != nil {
-			return left, right, fmt.Errorf("parentHash: %v, error: %w", parentHash, err)
+			return left, right, source, fmt.Errorf("parentHash: %v, error: %w", parentHash, err)
+		}
+		if len(data) != 2*bridgectrl.KeyLen {
+			return left, right, source, fmt.Errorf("parentHash: %v, invalid node length %d", parentHash, len(data))
+		}
+		value = [][]byte{data[:bridgectrl.KeyLen], data[bridgectrl.KeyLen:]}
+		if !topLevel {
+			s.cache.Add(string(parentHash[:]), value)
 		}
-		s.cache.Add(string(parentHash[:]), value)
 	}
 	copy(left[:], value[0])
 	copy(right[:], value[1])
-	return left, right, nil
+	return left, right, source, nil
 }
 
 // getProof returns the merkle proof for a given index and root.
 func (s *bridgeService) getProof(index uint, root [bridgectrl.KeyLen]byte, dbTx pgx.Tx) ([][bridgectrl.KeyLen]byte, error) {
-	var siblings [][bridgectrl.KeyLen]byte
+	return s.traceProof(index, root, nil, dbTx)
+}
+
+// traceProof returns the merkle proof for a given index and root, recording the nodes read in the trace if it's
+// not nil.
+func (s *bridgeService) traceProof(index uint, root [bridgectrl.KeyLen]byte, trace *proofTrace, dbTx pgx.Tx) ([][bridgectrl.KeyLen]byte, error) {
+	var (
+		siblings  [][bridgectrl.KeyLen]byte
+		topMisses []topLevelNode
+	)
 
 	cur := root
 	ctx := context.Background()
 	// It starts in height-1 because 0 is the level of the leafs
 	for h := int(s.height - 1); h >= 0; h-- {
-		left, right, err := s.getNode(ctx, cur, dbTx)
+		start := time.Now()
+		topLevel := s.topLevels.isTopLevel(s.height, h)
+		left, right, source, err := s.getNode(ctx, cur, topLevel, dbTx)
 		if err != nil {
 			return nil, fmt.Errorf("height: %d, cur: %v, error: %w", h, cur, err)
 		}
+		if topLevel && source != nodeSourceTopLevels {
+			topMisses = append(topMisses, topLevelNode{key: cur, left: left, right: right})
+		}
 		/*
 					*        Root                (level h=3 => height=4)
 					*      /     \
@@ -104,6 +236,7 @@ func (s *bridgeService) getProof(index uint, root [bridgectrl.KeyLen]byte, dbTx
 					* Now, let's do AND operation => 100&100=100 which is higher than 0 so we need the left sibling (O5)
 		*/
 
+		key := cur
 		if index&(1<<h) > 0 {
 			siblings = append(siblings, left)
 			cur = right
@@ -111,8 +244,12 @@ func (s *bridgeService) getProof(index uint, root [bridgectrl.KeyLen]byte, dbTx
 			siblings = append(siblings, right)
 			cur = left
 		}
+		trace.node(uint(h), key, left, right, siblings[len(siblings)-1], source, start)
 	}
 
+	// The nodes of the path of a new root are kept in a single update
+	s.topLevels.add(topMisses)
+
 	// We need to invert the siblings to go from leafs to the top
 	for st, en := 0, len(siblings)-1; st < en; st, en = st+1, en-1 {
 		siblings[st], siblings[en] = siblings[en], siblings[st]
@@ -123,46 +260,138 @@ func (s *bridgeService) getProof(index uint, root [bridgectrl.KeyLen]byte, dbTx
 
 // GetClaimProof returns the merkle proof to claim the given deposit.
 func (s *bridgeService) GetClaimProof(depositCnt, networkID uint, dbTx pgx.Tx) (*etherman.GlobalExitRoot, [][bridgectrl.KeyLen]byte, error) {
-	ctx := context.Background()
+	globalExitRoot, merkleProof, _, _, err := s.getClaimProofs(depositCnt, networkID, dbTx)
+	return globalExitRoot, merkleProof, err
+}
+
+// getClaimProofs returns the merkle proofs to claim the given deposit: the proof of the deposit in the local exit
+// tree of its network, and for the rollup deposits the proof of the local exit root in the rollup exit tree. The
+// rollup proof is empty with the contracts of a single rollup, whose rollup exit root is its local exit root.
+func (s *bridgeService) getClaimProofs(depositCnt, networkID uint, dbTx pgx.Tx) (*etherman.GlobalExitRoot, [][bridgectrl.KeyLen]byte, [][bridgectrl.KeyLen]byte, common.Hash, error) {
+	return s.traceClaimProofs(depositCnt, networkID, nil, dbTx)
+}
 
-	if dbTx == nil { // if the call comes from the rest API
-		deposit, err := s.storage.GetDeposit(ctx, depositCnt, networkID, nil)
+// traceClaimProofs returns the merkle proofs to claim the given deposit, recording the queries made in the trace
+// if it's not nil.
+func (s *bridgeService) traceClaimProofs(depositCnt, networkID uint, trace *proofTrace, dbTx pgx.Tx) (*etherman.GlobalExitRoot, [][bridgectrl.KeyLen]byte, [][bridgectrl.KeyLen]byte, common.Hash, error) {
+	if dbTx != nil {
+		return s.buildClaimProofs(depositCnt, networkID, trace, dbTx)
+	}
+	// The call comes from the rest API. The deposit, the exit roots and the tree are read in the same snapshot, so
+	// the proof is built against the exit roots returned even if the synchronizer updates them meanwhile
+	var (
+		globalExitRoot           *etherman.GlobalExitRoot
+		merkleProof, rollupProof [][bridgectrl.KeyLen]byte
+		localExitRoot            common.Hash
+	)
+	ctx := context.Background()
+	err := s.readSnapshot(ctx, func(dbTx pgx.Tx) error {
+		start := time.Now()
+		deposit, err := s.storage.GetDeposit(ctx, depositCnt, networkID, dbTx)
+		trace.step("get_deposit", start)
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
-
 		if !deposit.ReadyForClaim {
-			return nil, nil, gerror.ErrDepositNotSynced
+			return gerror.ErrDepositNotSynced
 		}
+		globalExitRoot, merkleProof, rollupProof, localExitRoot, err = s.buildClaimProofs(depositCnt, networkID, trace, dbTx)
+		return err
+	})
+	if err != nil {
+		return nil, nil, nil, common.Hash{}, err
 	}
+	return globalExitRoot, merkleProof, rollupProof, localExitRoot, nil
+}
+
+// buildClaimProofs builds the merkle proofs to claim the given deposit from the latest exit roots read in the
+// transaction.
+func (s *bridgeService) buildClaimProofs(depositCnt, networkID uint, trace *proofTrace, dbTx pgx.Tx) (*etherman.GlobalExitRoot, [][bridgectrl.KeyLen]byte, [][bridgectrl.KeyLen]byte, common.Hash, error) {
+	ctx := context.Background()
 
 	tID, err := s.getNetworkID(networkID)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, common.Hash{}, err
 	}
 
+	start := time.Now()
 	globalExitRoot, err := s.storage.GetLatestExitRoot(ctx, tID != 0, dbTx)
+	trace.step("get_latest_exit_root", start)
+	if err != nil {
+		return nil, nil, nil, common.Hash{}, err
+	}
+
+	localExitRoot := globalExitRoot.ExitRoots[0]
+	var rollupProof [][bridgectrl.KeyLen]byte
+	if tID != 0 {
+		start = time.Now()
+		localExitRoot, rollupProof, err = s.getRollupExitProof(ctx, networkID, globalExitRoot.ExitRoots[1], dbTx)
+		trace.step("get_rollup_exit_proof", start)
+		if err != nil {
+			return nil, nil, nil, common.Hash{}, err
+		}
+	}
+
+	start = time.Now()
+	merkleProof, err := s.traceProof(depositCnt, localExitRoot, trace, dbTx)
+	trace.step("get_proof", start)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, common.Hash{}, fmt.Errorf("getting the proof failed, error: %v, network: %d", err, networkID)
 	}
 
-	merkleProof, err := s.getProof(depositCnt, globalExitRoot.ExitRoots[tID], dbTx)
+	return globalExitRoot, merkleProof, rollupProof, localExitRoot, nil
+}
+
+// getRollupExitProof returns the local exit root of the rollup included in the rollup exit root, and its proof in
+// the rollup exit tree. The rollup id of a rollup is its network id.
+func (s *bridgeService) getRollupExitProof(ctx context.Context, networkID uint, rollupExitRoot common.Hash, dbTx pgx.Tx) (common.Hash, [][bridgectrl.KeyLen]byte, error) {
+	rollupExitLeaves, err := s.storage.GetRollupExitLeavesByRoot(ctx, rollupExitRoot, dbTx)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		// The rollup exit tree is not used by the contracts of a single rollup
+		return rollupExitRoot, nil, nil
+	} else if err != nil {
+		return common.Hash{}, nil, err
+	}
+	leaves, err := bridgectrl.RollupExitLeaves(rollupExitLeaves)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	if networkID == 0 || networkID > uint(len(leaves)) {
+		return common.Hash{}, nil, fmt.Errorf("rollup %d not included in the rollup exit root %s: %w", networkID, rollupExitRoot.String(), gerror.ErrDepositNotSynced)
+	}
+	proof, err := bridgectrl.ComputeSiblings(networkID-1, leaves, s.height)
 	if err != nil {
-		return nil, nil, fmt.Errorf("getting the proof failed, error: %v, network: %d", err, networkID)
+		return common.Hash{}, nil, err
 	}
+	return leaves[networkID-1], proof, nil
+}
 
-	return globalExitRoot, merkleProof, nil
+// readSnapshot runs read in a read only transaction with a consistent snapshot of the database, so the reads of a
+// request don't mix the states before and after an update of the synchronizer committed meanwhile.
+func (s *bridgeService) readSnapshot(ctx context.Context, read func(dbTx pgx.Tx) error) error {
+	dbTx, err := s.storage.BeginSnapshotTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	// The transaction only reads, there is nothing to commit
+	defer dbTx.Rollback(context.Background()) //nolint:errcheck
+	return read(dbTx)
 }
 
 // GetDepositStatus returns deposit with ready_for_claim status.
 func (s *bridgeService) GetDepositStatus(ctx context.Context, depositCount uint, destNetworkID uint) (string, error) {
+	return s.getClaimTxHash(ctx, depositCount, destNetworkID, nil)
+}
+
+// getClaimTxHash returns the hash of the claim tx of the deposit, empty if it's not claimed.
+func (s *bridgeService) getClaimTxHash(ctx context.Context, depositCount uint, destNetworkID uint, dbTx pgx.Tx) (string, error) {
 	var (
 		claimTxHash string
 	)
 	// Get the claim tx hash
-	claim, err := s.storage.GetClaim(ctx, depositCount, destNetworkID, nil)
+	claim, err := s.storage.GetClaim(ctx, depositCount, destNetworkID, dbTx)
 	if err != nil {
-		if err != gerror.ErrStorageNotFound {
+		if !errors.Is(err, gerror.ErrStorageNotFound) {
 			return "", err
 		}
 	} else {
@@ -179,46 +408,97 @@ func (s *bridgeService) CheckAPI(ctx context.Context, req *pb.CheckAPIRequest) (
 	}, nil
 }
 
-// GetBridges returns bridges for the destination address both in L1 and L2.
+// GetBridges returns bridges for the address both in L1 and L2, as the destination address by default or as the
+// msg.sender or the sender of the tx of the deposits, with their reference ids for the session of the destination
+// address and the admin.
 // Bridge rest API endpoint
 func (s *bridgeService) GetBridges(ctx context.Context, req *pb.GetBridgesRequest) (*pb.GetBridgesResponse, error) {
-	limit := req.Limit
-	if limit == 0 {
-		limit = s.defaultPageLimit
+	limit, err := s.pageLimit(ctx, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
 	}
-	if limit > s.maxPageLimit {
-		limit = s.maxPageLimit
+	if req.AddressRole != "" && !etherman.IsDepositAddressRole(req.AddressRole) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address role %q", req.AddressRole)
 	}
-	totalCount, err := s.storage.GetDepositCount(ctx, req.DestAddr, nil)
+	// The reference ids are set by the destination addresses
+	withRefs := (req.AddressRole == "" || req.AddressRole == etherman.DepositAddressDestination) && common.IsHexAddress(req.DestAddr)
+	addr := common.HexToAddress(req.DestAddr)
+	var res pb.GetBridgesResponse
+	err = s.loadCached(ctx, fmt.Sprintf("bridges:%s:%s:%s:%d:%d", addr.Hex(), req.AddressRole, req.Integration, req.Offset, limit), []string{cache.AccountTag(addr)}, &res, func() (proto.Message, error) {
+		return s.getBridges(ctx, req.DestAddr, req.AddressRole, req.Integration, req.Offset, limit)
+	})
 	if err != nil {
 		return nil, err
 	}
-	deposits, err := s.storage.GetDeposits(ctx, req.DestAddr, uint(limit), uint(req.Offset), nil)
+	if withRefs {
+		if err := s.fillRefIDs(ctx, addr, res.Deposits); err != nil {
+			return nil, err
+		}
+	}
+	s.fillDisplayStatuses(res.Deposits)
+	s.fillTokenLists(ctx, res.Deposits)
+	return &res, nil
+}
+
+func (s *bridgeService) getBridges(ctx context.Context, addr, role, integration string, offset uint64, limit uint32) (*pb.GetBridgesResponse, error) {
+	var (
+		totalCount    uint64
+		deposits      []*etherman.Deposit
+		claimTxHashes []string
+	)
+	// The count, the page and the claims are read in the same snapshot, so the deposits ready for claim are the ones
+	// whose proofs are built against the same exit roots
+	err := s.readSnapshot(ctx, func(dbTx pgx.Tx) error {
+		var err error
+		totalCount, err = s.storage.GetDepositCount(ctx, addr, role, integration, dbTx)
+		if err != nil {
+			return err
+		}
+		deposits, err = s.storage.GetDeposits(ctx, addr, role, integration, uint(limit), uint(offset), dbTx)
+		if err != nil {
+			return err
+		}
+		claimTxHashes = make([]string, len(deposits))
+		for i, deposit := range deposits {
+			claimTxHashes[i], err = s.getClaimTxHash(ctx, deposit.DepositCount, deposit.DestinationNetwork, dbTx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	var pbDeposits []*pb.Deposit
-	for _, deposit := range deposits {
-		claimTxHash, err := s.GetDepositStatus(ctx, deposit.DepositCount, deposit.DestinationNetwork)
-		if err != nil {
-			return nil, err
-		}
+	for i, deposit := range deposits {
+		claimTxHash := claimTxHashes[i]
+		decimals, formattedAmount := s.formatDepositAmount(ctx, deposit)
 		pbDeposits = append(
 			pbDeposits, &pb.Deposit{
-				LeafType:      uint32(deposit.LeafType),
-				OrigNet:       uint32(deposit.OriginalNetwork),
-				OrigAddr:      deposit.OriginalAddress.Hex(),
-				Amount:        deposit.Amount.String(),
-				DestNet:       uint32(deposit.DestinationNetwork),
-				DestAddr:      deposit.DestinationAddress.Hex(),
-				BlockNum:      deposit.BlockNumber,
-				DepositCnt:    uint64(deposit.DepositCount),
-				NetworkId:     uint32(deposit.NetworkID),
-				TxHash:        deposit.TxHash.String(),
-				ClaimTxHash:   claimTxHash,
-				Metadata:      "0x" + hex.EncodeToString(deposit.Metadata),
-				ReadyForClaim: deposit.ReadyForClaim,
+				LeafType:        uint32(deposit.LeafType),
+				OrigNet:         uint32(deposit.OriginalNetwork),
+				OrigAddr:        deposit.OriginalAddress.Hex(),
+				Amount:          deposit.Amount.String(),
+				DestNet:         uint32(deposit.DestinationNetwork),
+				DestAddr:        deposit.DestinationAddress.Hex(),
+				BlockNum:        deposit.BlockNumber,
+				DepositCnt:      uint64(deposit.DepositCount),
+				NetworkId:       uint32(deposit.NetworkID),
+				TxHash:          deposit.TxHash.String(),
+				ClaimTxHash:     claimTxHash,
+				Metadata:        "0x" + hex.EncodeToString(deposit.Metadata),
+				ReadyForClaim:   deposit.ReadyForClaim,
+				Decimals:        decimals,
+				FormattedAmount: formattedAmount,
+				LogIndex:        uint32(deposit.LogIndex),
+				BlockTime:       unixTime(deposit.BlockTime),
+				SyncedAt:        unixTime(deposit.SyncedAt),
+				Integration:     deposit.Integration,
+				Sender:          optionalAddress(deposit.Sender),
+				Depositor:       optionalAddress(deposit.Depositor),
+				GlobalIndex:     pbGlobalIndex(deposit.NetworkID, deposit.DepositCount),
 			},
 		)
 	}
@@ -232,34 +512,34 @@ func (s *bridgeService) GetBridges(ctx context.Context, req *pb.GetBridgesReques
 // GetClaims returns claims for the specific smart contract address both in L1 and L2.
 // Bridge rest API endpoint
 func (s *bridgeService) GetClaims(ctx context.Context, req *pb.GetClaimsRequest) (*pb.GetClaimsResponse, error) {
-	limit := req.Limit
-	if limit == 0 {
-		limit = s.defaultPageLimit
+	limit, err := s.pageLimit(ctx, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
 	}
-	if limit > s.maxPageLimit {
-		limit = s.maxPageLimit
+	addr := common.HexToAddress(req.DestAddr)
+	var res pb.GetClaimsResponse
+	err = s.loadCached(ctx, fmt.Sprintf("claims:%s:%d:%d", addr.Hex(), req.Offset, limit), []string{cache.AccountTag(addr)}, &res, func() (proto.Message, error) {
+		return s.getClaims(ctx, req.DestAddr, req.Offset, limit)
+	})
+	if err != nil {
+		return nil, err
 	}
-	totalCount, err := s.storage.GetClaimCount(ctx, req.DestAddr, nil)
+	return &res, nil
+}
+
+func (s *bridgeService) getClaims(ctx context.Context, destAddr string, offset uint64, limit uint32) (*pb.GetClaimsResponse, error) {
+	totalCount, err := s.storage.GetClaimCount(ctx, destAddr, nil)
 	if err != nil {
 		return nil, err
 	}
-	claims, err := s.storage.GetClaims(ctx, req.DestAddr, uint(limit), uint(req.Offset), nil) //nolint:gomnd
+	claims, err := s.storage.GetClaims(ctx, destAddr, uint(limit), uint(offset), nil) //nolint:gomnd
 	if err != nil {
 		return nil, err
 	}
 
 	var pbClaims []*pb.Claim
 	for _, claim := range claims {
-		pbClaims = append(pbClaims, &pb.Claim{
-			Index:     uint64(claim.Index),
-			OrigNet:   uint32(claim.OriginalNetwork),
-			OrigAddr:  claim.OriginalAddress.Hex(),
-			Amount:    claim.Amount.String(),
-			NetworkId: uint32(claim.NetworkID),
-			DestAddr:  claim.DestinationAddress.Hex(),
-			BlockNum:  claim.BlockNumber,
-			TxHash:    claim.TxHash.String(),
-		})
+		pbClaims = append(pbClaims, s.claimToPb(ctx, claim))
 	}
 
 	return &pb.GetClaimsResponse{
@@ -268,10 +548,41 @@ func (s *bridgeService) GetClaims(ctx context.Context, req *pb.GetClaimsRequest)
 	}, nil
 }
 
-// GetProof returns the merkle proof for the given deposit.
+func (s *bridgeService) claimToPb(ctx context.Context, claim *etherman.Claim) *pb.Claim {
+	decimals, formattedAmount := s.formatTokenAmount(ctx, claim.OriginalNetwork, claim.OriginalAddress, nil, claim.Amount)
+	return &pb.Claim{
+		Index:           uint64(claim.Index),
+		OrigNet:         uint32(claim.OriginalNetwork),
+		OrigAddr:        claim.OriginalAddress.Hex(),
+		Amount:          claim.Amount.String(),
+		NetworkId:       uint32(claim.NetworkID),
+		DestAddr:        claim.DestinationAddress.Hex(),
+		BlockNum:        claim.BlockNumber,
+		TxHash:          claim.TxHash.String(),
+		Decimals:        decimals,
+		FormattedAmount: formattedAmount,
+		LogIndex:        uint32(claim.LogIndex),
+		BlockTime:       unixTime(claim.BlockTime),
+		SyncedAt:        unixTime(claim.SyncedAt),
+		MessageCall:     claim.MessageCall,
+	}
+}
+
+// GetProof returns the merkle proof for the given deposit. With debug, it returns the trace of the construction
+// of the proof, and it requires the admin token.
 // Bridge rest API endpoint
 func (s *bridgeService) GetProof(ctx context.Context, req *pb.GetProofRequest) (*pb.GetProofResponse, error) {
-	globalExitRoot, merkleProof, err := s.GetClaimProof(uint(req.DepositCnt), uint(req.NetId), nil)
+	if err := s.checkProofConsumer(ctx); err != nil {
+		return nil, err
+	}
+	var trace *proofTrace
+	if req.Debug {
+		if err := s.checkAdmin(ctx); err != nil {
+			return nil, err
+		}
+		trace = newProofTrace()
+	}
+	globalExitRoot, merkleProof, rollupMerkleProof, localExitRoot, err := s.traceClaimProofs(uint(req.DepositCnt), uint(req.NetId), trace, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -279,19 +590,35 @@ func (s *bridgeService) GetProof(ctx context.Context, req *pb.GetProofRequest) (
 	for i := 0; i < len(merkleProof); i++ {
 		proof = append(proof, "0x"+hex.EncodeToString(merkleProof[i][:]))
 	}
+	rollupProof := make([]string, 0, len(rollupMerkleProof))
+	for i := 0; i < len(rollupMerkleProof); i++ {
+		rollupProof = append(rollupProof, "0x"+hex.EncodeToString(rollupMerkleProof[i][:]))
+	}
 
 	return &pb.GetProofResponse{
 		Proof: &pb.Proof{
-			MerkleProof:    proof,
-			MainExitRoot:   globalExitRoot.ExitRoots[0].Hex(),
-			RollupExitRoot: globalExitRoot.ExitRoots[1].Hex(),
+			MerkleProof:       proof,
+			MainExitRoot:      globalExitRoot.ExitRoots[0].Hex(),
+			RollupExitRoot:    globalExitRoot.ExitRoots[1].Hex(),
+			RollupMerkleProof: rollupProof,
+			LocalExitRoot:     localExitRoot.Hex(),
+			GlobalIndex:       pbGlobalIndex(uint(req.NetId), uint(req.DepositCnt)),
 		},
+		Trace: trace.toPb(),
 	}, nil
 }
 
 // GetBridge returns the bridge  with status whether it is able to send a claim transaction or not.
+// With as_of_block, it returns the status the deposit had at that block. With wait_seconds, it waits for the
+// change of an unclaimed deposit first. The reference id is only returned to the session of the destination
+// address and to the admin.
 // Bridge rest API endpoint
 func (s *bridgeService) GetBridge(ctx context.Context, req *pb.GetBridgeRequest) (*pb.GetBridgeResponse, error) {
+	if req.AsOfBlock == 0 {
+		if err := s.waitDepositChange(ctx, uint(req.NetId), uint(req.DepositCnt), time.Duration(req.WaitSeconds)*time.Second); err != nil {
+			return nil, err
+		}
+	}
 	deposit, err := s.storage.GetDeposit(ctx, uint(req.DepositCnt), uint(req.NetId), nil)
 	if err != nil {
 		return nil, err
@@ -302,41 +629,1228 @@ func (s *bridgeService) GetBridge(ctx context.Context, req *pb.GetBridgeRequest)
 		return nil, err
 	}
 
-	return &pb.GetBridgeResponse{
-		Deposit: &pb.Deposit{
-			LeafType:      uint32(deposit.LeafType),
-			OrigNet:       uint32(deposit.OriginalNetwork),
-			OrigAddr:      deposit.OriginalAddress.Hex(),
-			Amount:        deposit.Amount.String(),
-			DestNet:       uint32(deposit.DestinationNetwork),
-			DestAddr:      deposit.DestinationAddress.Hex(),
-			BlockNum:      deposit.BlockNumber,
-			DepositCnt:    uint64(deposit.DepositCount),
-			NetworkId:     uint32(deposit.NetworkID),
-			TxHash:        deposit.TxHash.String(),
-			ClaimTxHash:   claimTxHash,
-			Metadata:      "0x" + hex.EncodeToString(deposit.Metadata),
-			ReadyForClaim: deposit.ReadyForClaim,
-		},
-	}, nil
+	res := &pb.GetBridgeResponse{}
+	if req.AsOfBlock != 0 {
+		state, err := s.getDepositStateAt(ctx, deposit, uint(req.AsOfNet), req.AsOfBlock)
+		if err != nil {
+			return nil, err
+		}
+		deposit.ReadyForClaim = state.readyForClaim
+		if !state.claimed {
+			claimTxHash = ""
+		}
+		res.LocalExitRoot = state.localExitRoot.String()
+		for _, c := range state.history {
+			res.History = append(res.History, statusChangeToPb(c))
+		}
+	}
+
+	refID, err := s.getRefID(ctx, deposit)
+	if err != nil {
+		return nil, err
+	}
+	decimals, formattedAmount := s.formatDepositAmount(ctx, deposit)
+	res.Deposit = &pb.Deposit{
+		LeafType:        uint32(deposit.LeafType),
+		OrigNet:         uint32(deposit.OriginalNetwork),
+		OrigAddr:        deposit.OriginalAddress.Hex(),
+		Amount:          deposit.Amount.String(),
+		DestNet:         uint32(deposit.DestinationNetwork),
+		DestAddr:        deposit.DestinationAddress.Hex(),
+		BlockNum:        deposit.BlockNumber,
+		DepositCnt:      uint64(deposit.DepositCount),
+		NetworkId:       uint32(deposit.NetworkID),
+		TxHash:          deposit.TxHash.String(),
+		ClaimTxHash:     claimTxHash,
+		Metadata:        "0x" + hex.EncodeToString(deposit.Metadata),
+		ReadyForClaim:   deposit.ReadyForClaim,
+		Decimals:        decimals,
+		FormattedAmount: formattedAmount,
+		LogIndex:        uint32(deposit.LogIndex),
+		BlockTime:       unixTime(deposit.BlockTime),
+		SyncedAt:        unixTime(deposit.SyncedAt),
+		Integration:     deposit.Integration,
+		Sender:          optionalAddress(deposit.Sender),
+		Depositor:       optionalAddress(deposit.Depositor),
+		GlobalIndex:     pbGlobalIndex(deposit.NetworkID, deposit.DepositCount),
+		RefId:           refID,
+	}
+	s.fillDisplayStatuses([]*pb.Deposit{res.Deposit})
+	s.fillTokenLists(ctx, []*pb.Deposit{res.Deposit})
+	return res, nil
+}
+
+// depositState is the state of a deposit at a point of the history.
+type depositState struct {
+	readyForClaim bool
+	claimed       bool
+	localExitRoot common.Hash
+	history       []etherman.DepositStatusChange
+}
+
+// getDepositStateAt returns the state of the deposit at the block of the network, from its status history.
+func (s *bridgeService) getDepositStateAt(ctx context.Context, deposit *etherman.Deposit, networkID uint, blockNum uint64) (*depositState, error) {
+	point, err := s.storage.GetHistoryPoint(ctx, networkID, blockNum, nil)
+	if err != nil {
+		return nil, fmt.Errorf("no block of the network %d synced until the block %d: %w", networkID, blockNum, err)
+	}
+	history, err := s.storage.GetDepositStatusHistory(ctx, deposit.DepositCount, deposit.NetworkID, nil)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		state   depositState
+		indexed bool
+	)
+	for _, c := range history {
+		if !point.Includes(c) {
+			continue
+		}
+		state.history = append(state.history, c)
+		switch c.Status {
+		case etherman.DepositStatusIndexed:
+			indexed = true
+		case etherman.DepositStatusReadyForClaim:
+			state.readyForClaim = true
+		case etherman.DepositStatusClaimed:
+			state.claimed = true
+		}
+	}
+	if !indexed {
+		return nil, fmt.Errorf("deposit not indexed at the block %d of the network %d: %w", blockNum, networkID, gerror.ErrStorageNotFound)
+	}
+	state.localExitRoot, err = s.storage.GetLocalExitRootAt(ctx, deposit.NetworkID, *point, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
 }
 
 // GetTokenWrapped returns the token wrapped created for a specific network
 // Bridge rest API endpoint
 func (s *bridgeService) GetTokenWrapped(ctx context.Context, req *pb.GetTokenWrappedRequest) (*pb.GetTokenWrappedResponse, error) {
-	tokenWrapped, err := s.storage.GetTokenWrapped(ctx, uint(req.OrigNet), common.HexToAddress(req.OrigTokenAddr), nil)
+	origAddr := common.HexToAddress(req.OrigTokenAddr)
+	var res pb.GetTokenWrappedResponse
+	err := s.loadCached(ctx, fmt.Sprintf("tokenwrapped:%d:%s", req.OrigNet, origAddr.Hex()), []string{cache.TokenTag(uint(req.OrigNet), origAddr)}, &res, func() (proto.Message, error) {
+		tokenWrapped, err := s.storage.GetTokenWrapped(ctx, uint(req.OrigNet), origAddr, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.GetTokenWrappedResponse{
+			Tokenwrapped: &pb.TokenWrapped{
+				OrigNet:           uint32(tokenWrapped.OriginalNetwork),
+				OriginalTokenAddr: tokenWrapped.OriginalTokenAddress.Hex(),
+				WrappedTokenAddr:  tokenWrapped.WrappedTokenAddress.Hex(),
+				NetworkId:         uint32(tokenWrapped.NetworkID),
+				Name:              tokenWrapped.Name,
+				Symbol:            tokenWrapped.Symbol,
+				Decimals:          uint32(tokenWrapped.Decimals),
+			},
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetCCIPProof returns the claim proof requested by an EIP-3668 (CCIP-Read) offchain lookup.
+// Bridge rest API endpoint
+func (s *bridgeService) GetCCIPProof(ctx context.Context, req *pb.GetCCIPProofRequest) (*pb.GetCCIPProofResponse, error) {
+	if err := s.checkProofConsumer(ctx); err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(req.Sender) {
+		return nil, fmt.Errorf("invalid ccip sender: %s", req.Sender)
+	}
+	networkID, depositCnt, err := decodeCCIPCallData(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	globalExitRoot, merkleProof, err := s.GetClaimProof(depositCnt, networkID, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := encodeCCIPClaimProof(globalExitRoot, merkleProof)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetCCIPProofResponse{
+		Data: hexutil.Encode(data),
+	}, nil
+}
+
+// GetTokenWrappedHistory returns all the mappings of the wrapped tokens of an original token, so the
+// transfers made under a previous mapping can be labeled correctly.
+// Bridge rest API endpoint
+func (s *bridgeService) GetTokenWrappedHistory(ctx context.Context, req *pb.GetTokenWrappedHistoryRequest) (*pb.GetTokenWrappedHistoryResponse, error) {
+	mappings, err := s.storage.GetTokenWrappedHistory(ctx, uint(req.OrigNet), common.HexToAddress(req.OrigTokenAddr), nil)
+	if err != nil {
+		return nil, err
+	}
+	var pbMappings []*pb.TokenWrappedMapping
+	for _, mapping := range mappings {
+		pbMappings = append(pbMappings, &pb.TokenWrappedMapping{
+			OrigNet:           uint32(mapping.OriginalNetwork),
+			OriginalTokenAddr: mapping.OriginalTokenAddress.Hex(),
+			WrappedTokenAddr:  mapping.WrappedTokenAddress.Hex(),
+			NetworkId:         uint32(mapping.NetworkID),
+			ValidFromBlock:    mapping.ValidFromBlock,
+			ValidToBlock:      mapping.ValidToBlock,
+		})
+	}
+	return &pb.GetTokenWrappedHistoryResponse{
+		Mappings: pbMappings,
+	}, nil
+}
+
+// ValidateClaim simulates the claim in the destination network and returns the revert reason if it fails.
+// If the proof is not provided, the one calculated by the service is used.
+// Bridge rest API endpoint
+func (s *bridgeService) ValidateClaim(ctx context.Context, req *pb.ValidateClaimRequest) (*pb.ValidateClaimResponse, error) {
+	simulator, found := s.claimSimulators[uint(req.DestNet)]
+	if !found {
+		return nil, gerror.ErrNetworkNotRegister
+	}
+	for _, addr := range []string{req.OrigAddr, req.DestAddr, req.From} {
+		if addr != "" && !common.IsHexAddress(addr) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", addr)
+		}
+	}
+	amount, err := uint256.Parse(req.Amount)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	deposit := &etherman.Deposit{
+		LeafType:           uint8(req.LeafType),
+		OriginalNetwork:    uint(req.OrigNet),
+		OriginalAddress:    common.HexToAddress(req.OrigAddr),
+		Amount:             amount,
+		DestinationNetwork: uint(req.DestNet),
+		DestinationAddress: common.HexToAddress(req.DestAddr),
+		DepositCount:       uint(req.DepositCnt),
+		NetworkID:          uint(req.NetworkId),
+		Metadata:           common.FromHex(req.Metadata),
+	}
+
+	var (
+		version               string
+		smtProof, rollupProof [bridgectrl.KeyLen][bridgectrl.KeyLen]byte
+		globalExitRoot        *etherman.GlobalExitRoot
+	)
+	if req.Proof == nil {
+		version, globalExitRoot, smtProof, rollupProof, err = s.getClaimInputs(deposit)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if len(req.Proof.MerkleProof) != len(smtProof) || (len(req.Proof.RollupMerkleProof) != 0 && len(req.Proof.RollupMerkleProof) != len(rollupProof)) {
+			return nil, fmt.Errorf("invalid merkle proof length: %d, rollup merkle proof length: %d", len(req.Proof.MerkleProof), len(req.Proof.RollupMerkleProof))
+		}
+		for i := range req.Proof.MerkleProof {
+			smtProof[i] = common.HexToHash(req.Proof.MerkleProof[i])
+		}
+		for i := range req.Proof.RollupMerkleProof {
+			rollupProof[i] = common.HexToHash(req.Proof.RollupMerkleProof[i])
+		}
+		version = s.depositClaimVersion(deposit, len(req.Proof.RollupMerkleProof))
+		globalExitRoot = &etherman.GlobalExitRoot{
+			ExitRoots: []common.Hash{common.HexToHash(req.Proof.MainExitRoot), common.HexToHash(req.Proof.RollupExitRoot)},
+		}
+	}
+
+	success, revertReason, err := simulator.SimulateClaim(ctx, common.HexToAddress(req.From), version, deposit, smtProof, rollupProof, globalExitRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ValidateClaimResponse{
+		Success:      success,
+		RevertReason: revertReason,
+	}, nil
+}
+
+// GetClaimTx returns the unsigned claim tx of a deposit ready for claim, so the integrators
+// can sign it with their own keys and send it to the destination network.
+// Bridge rest API endpoint
+func (s *bridgeService) GetClaimTx(ctx context.Context, req *pb.GetClaimTxRequest) (*pb.GetClaimTxResponse, error) {
+	if err := s.checkProofConsumer(ctx); err != nil {
+		return nil, err
+	}
+	if req.From != "" && !common.IsHexAddress(req.From) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", req.From)
+	}
+	deposit, err := s.storage.GetDeposit(ctx, uint(req.DepositCnt), uint(req.NetId), nil)
+	if err != nil {
+		return nil, err
+	}
+	if !deposit.ReadyForClaim {
+		return nil, fmt.Errorf("deposit %d of network %d is not ready for claim", req.DepositCnt, req.NetId)
+	}
+	simulator, found := s.claimSimulators[deposit.DestinationNetwork]
+	if !found {
+		return nil, gerror.ErrNetworkNotRegister
+	}
+	version, ger, smtProof, rollupProof, err := s.getClaimInputs(deposit)
+	if err != nil {
+		return nil, err
+	}
+	from := deposit.DestinationAddress
+	if req.From != "" {
+		from = common.HexToAddress(req.From)
+	}
+	tx, err := simulator.BuildClaimTx(ctx, from, version, deposit, smtProof, rollupProof, ger)
 	if err != nil {
 		return nil, err
 	}
-	return &pb.GetTokenWrappedResponse{
-		Tokenwrapped: &pb.TokenWrapped{
-			OrigNet:           uint32(tokenWrapped.OriginalNetwork),
-			OriginalTokenAddr: tokenWrapped.OriginalTokenAddress.Hex(),
-			WrappedTokenAddr:  tokenWrapped.WrappedTokenAddress.Hex(),
-			NetworkId:         uint32(tokenWrapped.NetworkID),
-			Name:              tokenWrapped.Name,
-			Symbol:            tokenWrapped.Symbol,
-			Decimals:          uint32(tokenWrapped.Decimals),
+	return &pb.GetClaimTxResponse{
+		Tx: &pb.ClaimTx{
+			ChainId: tx.ChainID,
+			To:      tx.To.Hex(),
+			Data:    hexutil.Encode(tx.Data),
+			Gas:     tx.Gas,
+			Value:   tx.Value.String(),
 		},
 	}, nil
 }
+
+// GetClaimOutcome returns the outcome of the mined claim of a deposit sent by the claim tx manager, with the
+// recipient, the token and the amount it received.
+// Bridge rest API endpoint
+func (s *bridgeService) GetClaimOutcome(ctx context.Context, req *pb.GetClaimOutcomeRequest) (*pb.GetClaimOutcomeResponse, error) {
+	outcome, err := s.storage.GetClaimOutcome(ctx, uint(req.DepositCnt), nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return nil, status.Errorf(codes.NotFound, "no outcome of the claim of the deposit %d", req.DepositCnt)
+	} else if err != nil {
+		return nil, err
+	}
+	pbOutcome := &pb.ClaimOutcome{
+		DepositCnt:  uint64(outcome.DepositID),
+		TxHash:      outcome.TxHash.String(),
+		BlockNum:    outcome.BlockNumber,
+		OrigNet:     uint32(outcome.OriginalNetwork),
+		OrigAddr:    outcome.OriginalAddress.String(),
+		Recipient:   outcome.Recipient.String(),
+		Amount:      outcome.Amount.String(),
+		Minted:      outcome.Minted,
+		Discrepancy: outcome.Discrepancy(),
+		DecodedAt:   unixTime(outcome.DecodedAt),
+	}
+	if outcome.Delivered != nil {
+		pbOutcome.Token, pbOutcome.Delivered = outcome.Token.String(), outcome.Delivered.String()
+	}
+	return &pb.GetClaimOutcomeResponse{Outcome: pbOutcome}, nil
+}
+
+// GetActivity returns the deposits and claims of a network aggregated by token and by hour or day, optionally
+// filtered by token. The range is [from_time, to_time) in unix seconds, by default the last day or month of activity.
+// Bridge rest API endpoint
+func (s *bridgeService) GetActivity(ctx context.Context, req *pb.GetActivityRequest) (*pb.GetActivityResponse, error) {
+	if _, err := s.getNetworkID(uint(req.NetworkId)); err != nil {
+		return nil, err
+	}
+	var bucketDuration, defaultRange time.Duration
+	switch req.Interval {
+	case "", activityIntervalHour:
+		req.Interval = activityIntervalHour
+		bucketDuration, defaultRange = time.Hour, 24*time.Hour //nolint:gomnd
+	case activityIntervalDay:
+		bucketDuration, defaultRange = 24*time.Hour, 30*24*time.Hour //nolint:gomnd
+	default:
+		return nil, fmt.Errorf("invalid interval: %s", req.Interval)
+	}
+	to := time.Now()
+	if req.ToTime != 0 {
+		to = time.Unix(int64(req.ToTime), 0)
+	}
+	from := to.Add(-defaultRange)
+	if req.FromTime != 0 {
+		from = time.Unix(int64(req.FromTime), 0)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid time range: from %d to %d", from.Unix(), to.Unix())
+	}
+	if to.Sub(from) > time.Duration(s.maxPageLimit)*bucketDuration {
+		return nil, fmt.Errorf("too many buckets, the max is %d", s.maxPageLimit)
+	}
+	var tokenAddr *common.Address
+	if req.OrigTokenAddr != "" {
+		addr := common.HexToAddress(req.OrigTokenAddr)
+		tokenAddr = &addr
+	}
+	// A bucket of the range has a row per token, one more row than the max tells the range is too wide.
+	buckets, err := s.storage.GetActivity(ctx, uint(req.NetworkId), uint(req.OrigNet), tokenAddr, req.Interval, from, to, uint(s.maxPageLimit)+1, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(buckets) > int(s.maxPageLimit) {
+		return nil, status.Errorf(codes.InvalidArgument, "too many buckets of tokens, the max is %d, narrow the range or filter by token", s.maxPageLimit)
+	}
+	var pbBuckets []*pb.ActivityBucket
+	for _, bucket := range buckets {
+		pbBuckets = append(pbBuckets, &pb.ActivityBucket{
+			Time:          uint64(bucket.Time.Unix()),
+			DepositCnt:    bucket.DepositCount,
+			DepositVolume: bucket.DepositVolume.String(),
+			ClaimCnt:      bucket.ClaimCount,
+			ClaimVolume:   bucket.ClaimVolume.String(),
+			OrigNet:       uint32(bucket.OriginalNetwork),
+			OrigAddr:      bucket.OriginalAddress.Hex(),
+		})
+	}
+	return &pb.GetActivityResponse{
+		Buckets: pbBuckets,
+	}, nil
+}
+
+// GetIntegrationStats returns the deposits of a network aggregated by the integration they come from, optionally
+// filtered by token.
+// Bridge rest API endpoint
+func (s *bridgeService) GetIntegrationStats(ctx context.Context, req *pb.GetIntegrationStatsRequest) (*pb.GetIntegrationStatsResponse, error) {
+	if _, err := s.getNetworkID(uint(req.NetworkId)); err != nil {
+		return nil, err
+	}
+	to := time.Now()
+	if req.ToTime != 0 {
+		to = time.Unix(int64(req.ToTime), 0)
+	}
+	from := to.Add(-integrationStatsDefaultRange)
+	if req.FromTime != 0 {
+		from = time.Unix(int64(req.FromTime), 0)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid time range: from %d to %d", from.Unix(), to.Unix())
+	}
+	var tokenAddr *common.Address
+	if req.OrigTokenAddr != "" {
+		addr := common.HexToAddress(req.OrigTokenAddr)
+		tokenAddr = &addr
+	}
+	stats, err := s.storage.GetIntegrationStats(ctx, uint(req.NetworkId), uint(req.OrigNet), tokenAddr, from, to, nil)
+	if err != nil {
+		return nil, err
+	}
+	var pbStats []*pb.IntegrationStats
+	for _, integration := range stats {
+		pbStats = append(pbStats, &pb.IntegrationStats{
+			Integration:   integration.Integration,
+			DepositCnt:    integration.DepositCount,
+			DepositVolume: integration.DepositVolume.String(),
+		})
+	}
+	return &pb.GetIntegrationStatsResponse{
+		Integrations: pbStats,
+	}, nil
+}
+
+// GetGERInjectionLatency returns the observed delays between the L1 global exit root updates
+// and their injection in the destination networks.
+// Bridge rest API endpoint
+func (s *bridgeService) GetGERInjectionLatency(ctx context.Context, req *pb.GetGERInjectionLatencyRequest) (*pb.GetGERInjectionLatencyResponse, error) {
+	var pbNetworks []*pb.GERInjectionLatency
+	for _, stats := range s.gerLatency.Stats() {
+		if req.NetId != 0 && uint(req.NetId) != stats.NetworkID {
+			continue
+		}
+		var lastInjection uint64
+		if !stats.LastInjection.IsZero() {
+			lastInjection = uint64(stats.LastInjection.Unix())
+		}
+		pbNetworks = append(pbNetworks, &pb.GERInjectionLatency{
+			NetworkId:         uint32(stats.NetworkID),
+			LastLatencyMs:     uint64(stats.Last.Milliseconds()),
+			AverageLatencyMs:  uint64(stats.Average.Milliseconds()),
+			Samples:           stats.Samples,
+			LastInjectionTime: lastInjection,
+			PendingMs:         uint64(stats.Pending.Milliseconds()),
+		})
+	}
+	return &pb.GetGERInjectionLatencyResponse{
+		Networks: pbNetworks,
+	}, nil
+}
+
+// GetBridgesByTx returns the deposits of a tx. If the deposits are traced, it also returns the originator of
+// the tx and the contracts called until the bridge.
+// Bridge rest API endpoint
+func (s *bridgeService) GetBridgesByTx(ctx context.Context, req *pb.GetBridgesByTxRequest) (*pb.GetBridgesByTxResponse, error) {
+	deposits, err := s.storage.GetDepositsByTxHash(ctx, common.HexToHash(req.TxHash), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbDeposits []*pb.Deposit
+	for _, deposit := range deposits {
+		claimTxHash, err := s.GetDepositStatus(ctx, deposit.DepositCount, deposit.DestinationNetwork)
+		if err != nil {
+			return nil, err
+		}
+		decimals, formattedAmount := s.formatDepositAmount(ctx, deposit)
+		pbDeposits = append(
+			pbDeposits, &pb.Deposit{
+				LeafType:        uint32(deposit.LeafType),
+				OrigNet:         uint32(deposit.OriginalNetwork),
+				OrigAddr:        deposit.OriginalAddress.Hex(),
+				Amount:          deposit.Amount.String(),
+				DestNet:         uint32(deposit.DestinationNetwork),
+				DestAddr:        deposit.DestinationAddress.Hex(),
+				BlockNum:        deposit.BlockNumber,
+				DepositCnt:      uint64(deposit.DepositCount),
+				NetworkId:       uint32(deposit.NetworkID),
+				TxHash:          deposit.TxHash.String(),
+				ClaimTxHash:     claimTxHash,
+				Metadata:        "0x" + hex.EncodeToString(deposit.Metadata),
+				ReadyForClaim:   deposit.ReadyForClaim,
+				Decimals:        decimals,
+				FormattedAmount: formattedAmount,
+				LogIndex:        uint32(deposit.LogIndex),
+				BlockTime:       unixTime(deposit.BlockTime),
+				SyncedAt:        unixTime(deposit.SyncedAt),
+				Integration:     deposit.Integration,
+				Sender:          optionalAddress(deposit.Sender),
+				Depositor:       optionalAddress(deposit.Depositor),
+				GlobalIndex:     pbGlobalIndex(deposit.NetworkID, deposit.DepositCount),
+			},
+		)
+	}
+
+	s.fillDisplayStatuses(pbDeposits)
+	s.fillTokenLists(ctx, pbDeposits)
+	res := &pb.GetBridgesByTxResponse{
+		Deposits: pbDeposits,
+	}
+	trace, err := s.storage.GetDepositTrace(ctx, deposits[0].NetworkID, deposits[0].TxHash, nil)
+	if err != nil {
+		if !errors.Is(err, gerror.ErrStorageNotFound) {
+			return nil, err
+		}
+		return res, nil
+	}
+	res.Originator = trace.Originator.Hex()
+	for _, addr := range trace.CallPath {
+		res.CallPath = append(res.CallPath, addr.Hex())
+	}
+	return res, nil
+}
+
+// SetL1InfoTree sets the L1 info tree of the synchronizer, to calculate the proofs of its leaves.
+func (s *bridgeService) SetL1InfoTree(tree L1InfoTreeProver) {
+	s.l1InfoTree = tree
+}
+
+// GetL1InfoTreeProof returns the merkle proof of a global exit root in the L1 info tree.
+// Bridge rest API endpoint
+func (s *bridgeService) GetL1InfoTreeProof(ctx context.Context, req *pb.GetL1InfoTreeProofRequest) (*pb.GetL1InfoTreeProofResponse, error) {
+	if err := s.checkProofConsumer(ctx); err != nil {
+		return nil, err
+	}
+	if s.l1InfoTree == nil {
+		return nil, status.Errorf(codes.Unavailable, "the L1 info tree is not synced by this service")
+	}
+	ger := common.HexToHash(req.GlobalExitRoot)
+	leaf, err := s.storage.GetL1InfoTreeLeaf(ctx, ger, nil)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return nil, fmt.Errorf("global exit root %s not found in the L1 info tree: %w", ger.String(), err)
+	} else if err != nil {
+		return nil, err
+	}
+	last, err := s.storage.GetLatestL1InfoTreeLeaf(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The proof is calculated in the tree of the stored leaves, which is behind the synchronizer while it
+	// reloads the tree
+	proof, err := s.l1InfoTree.GetL1InfoTreeProof(leaf.Index, last.Index+1)
+	if err != nil || proof.Leaf != leaf.Leaf || proof.Root != last.Root {
+		return nil, status.Errorf(codes.Unavailable, "the L1 info tree is being synced, try again later")
+	}
+	return &pb.GetL1InfoTreeProofResponse{
+		Leaf:  l1InfoTreeLeafToPb(*leaf),
+		Proof: hexProof(proof.Siblings),
+		Root:  last.Root.String(),
+	}, nil
+}
+
+// GetPendingClaimApprovals returns the deposits whose claim tx is parked until an admin approves it, above the
+// approval threshold, uneconomical or of a reverting message. Bridge rest API admin endpoint
+func (s *bridgeService) GetPendingClaimApprovals(ctx context.Context, req *pb.GetPendingClaimApprovalsRequest) (*pb.GetPendingClaimApprovalsResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	parked := []ctmtypes.MonitoredTxStatus{ctmtypes.MonitoredTxStatusPendingApproval, ctmtypes.MonitoredTxStatusUneconomical, ctmtypes.MonitoredTxStatusMessageReverted}
+	mTxs, err := s.storage.GetClaimTxsByStatus(ctx, parked, nil)
+	if err != nil {
+		return nil, err
+	}
+	var pbDeposits []*pb.Deposit
+	for _, mTx := range mTxs {
+		// The claim txs are only sent for the L1 deposits
+		deposit, err := s.storage.GetDeposit(ctx, mTx.DepositID, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+		pbDeposits = append(pbDeposits, &pb.Deposit{
+			LeafType:      uint32(deposit.LeafType),
+			OrigNet:       uint32(deposit.OriginalNetwork),
+			OrigAddr:      deposit.OriginalAddress.Hex(),
+			Amount:        deposit.Amount.String(),
+			DestNet:       uint32(deposit.DestinationNetwork),
+			DestAddr:      deposit.DestinationAddress.Hex(),
+			BlockNum:      deposit.BlockNumber,
+			DepositCnt:    uint64(deposit.DepositCount),
+			NetworkId:     uint32(deposit.NetworkID),
+			TxHash:        deposit.TxHash.String(),
+			Metadata:      "0x" + hex.EncodeToString(deposit.Metadata),
+			ReadyForClaim: deposit.ReadyForClaim,
+			LogIndex:      uint32(deposit.LogIndex),
+			BlockTime:     unixTime(deposit.BlockTime),
+			SyncedAt:      unixTime(deposit.SyncedAt),
+			Integration:   deposit.Integration,
+			Sender:        optionalAddress(deposit.Sender),
+			Depositor:     optionalAddress(deposit.Depositor),
+			GlobalIndex:   pbGlobalIndex(deposit.NetworkID, deposit.DepositCount),
+		})
+	}
+	s.fillTokenLists(ctx, pbDeposits)
+	return &pb.GetPendingClaimApprovalsResponse{
+		Deposits: pbDeposits,
+	}, nil
+}
+
+// ApproveClaim approves a parked claim tx, so the claim tx manager sends it.
+// Bridge rest API admin endpoint
+func (s *bridgeService) ApproveClaim(ctx context.Context, req *pb.ApproveClaimRequest) (*pb.ApproveClaimResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return idempotent(ctx, s, "ApproveClaim", req, func() (*pb.ApproveClaimResponse, error) {
+		if err := s.storage.ApproveClaimTx(ctx, uint(req.DepositCnt), nil); err != nil {
+			return nil, err
+		}
+		log.Infof("claim tx of the deposit %d approved", req.DepositCnt)
+		return &pb.ApproveClaimResponse{}, nil
+	})
+}
+
+// GetAdminQueries returns the predefined read only queries the operators can run.
+// Bridge rest API admin endpoint
+func (s *bridgeService) GetAdminQueries(ctx context.Context, req *pb.GetAdminQueriesRequest) (*pb.GetAdminQueriesResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	var pbQueries []*pb.AdminQuery
+	for _, query := range s.storage.GetAdminQueries() {
+		pbQueries = append(pbQueries, &pb.AdminQuery{
+			Name:        query.Name,
+			Description: query.Description,
+			Params:      query.Params,
+		})
+	}
+	return &pb.GetAdminQueriesResponse{
+		Queries: pbQueries,
+	}, nil
+}
+
+// RunAdminQuery runs a predefined read only query, returning the rows or exporting them as csv.
+// Bridge rest API admin endpoint
+func (s *bridgeService) RunAdminQuery(ctx context.Context, req *pb.RunAdminQueryRequest) (*pb.RunAdminQueryResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.Format != "" && req.Format != adminQueryFormatCSV {
+		return nil, fmt.Errorf("unsupported format %s", req.Format)
+	}
+	limit, err := s.pageLimit(ctx, req.Limit, 0)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.storage.RunAdminQuery(ctx, req.Name, req.Params, uint(limit))
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("admin query %s run with params %v, %d rows", req.Name, req.Params, len(result.Rows))
+	columns, rows, csv, err := formatAdminQueryResult(result, req.Format)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RunAdminQueryResponse{
+		Columns: columns,
+		Rows:    rows,
+		Csv:     csv,
+	}, nil
+}
+
+// formatAdminQueryResult returns the columns and rows of the result, or only the csv export with the csv format.
+func formatAdminQueryResult(result *etherman.AdminQueryResult, format string) ([]string, []*pb.AdminQueryRow, string, error) {
+	if format == adminQueryFormatCSV {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write(result.Columns)
+		_ = w.WriteAll(result.Rows)
+		if err := w.Error(); err != nil {
+			return nil, nil, "", err
+		}
+		return nil, nil, buf.String(), nil
+	}
+	pbRows := make([]*pb.AdminQueryRow, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		pbRows = append(pbRows, &pb.AdminQueryRow{Values: row})
+	}
+	return result.Columns, pbRows, "", nil
+}
+
+// SubmitSignedClaims receives the claim txs signed offline, so the claim tx managers send them. The txs
+// must be built from claim txs pending of signature, for the chain of their network. The txs are all checked
+// before storing them in a single db tx, so a batch is stored entirely or not at all. Bridge rest API admin endpoint
+func (s *bridgeService) SubmitSignedClaims(ctx context.Context, req *pb.SubmitSignedClaimsRequest) (*pb.SubmitSignedClaimsResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return idempotent(ctx, s, "SubmitSignedClaims", req, func() (*pb.SubmitSignedClaimsResponse, error) {
+		mTxs, err := s.storage.GetClaimTxsByStatus(ctx, []ctmtypes.MonitoredTxStatus{ctmtypes.MonitoredTxStatusPendingSignature}, nil)
+		if err != nil {
+			return nil, err
+		}
+		type signedClaim struct {
+			mTx  *ctmtypes.MonitoredTx
+			tx   *types.Transaction
+			data []byte
+		}
+		signed := make([]signedClaim, 0, len(req.SignedTxs))
+		chainIDs := make(map[uint]*big.Int)
+		for i, rawTx := range req.SignedTxs {
+			data, err := hexutil.Decode(rawTx)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "signed tx %d: %v", i, err)
+			}
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(data); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "signed tx %d: %v", i, err)
+			}
+			var mTx *ctmtypes.MonitoredTx
+			for j := range mTxs {
+				if mTxs[j].Nonce == tx.Nonce() && mTxs[j].To != nil && tx.To() != nil && *mTxs[j].To == *tx.To() && bytes.Equal(mTxs[j].Data, tx.Data()) {
+					mTx = &mTxs[j]
+					break
+				}
+			}
+			if mTx == nil {
+				return nil, status.Errorf(codes.NotFound, "signed tx %d: no claim tx pending of signature", i)
+			}
+			chainID, err := s.claimChainID(ctx, mTx, chainIDs)
+			if err != nil {
+				return nil, err
+			}
+			if err := mTx.CheckSignedTx(tx, chainID); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "signed tx %d of the deposit %d: %v", i, mTx.DepositID, err)
+			}
+			signed = append(signed, signedClaim{mTx: mTx, tx: tx, data: data})
+		}
+
+		dbTx, err := s.storage.BeginDBTransaction(ctx)
+		if err != nil {
+			return nil, err
+		}
+		depositCnts := make([]uint64, 0, len(signed))
+		for _, c := range signed {
+			if err := s.storage.AddSignedClaimTx(ctx, c.mTx.DepositID, c.data, dbTx); err != nil {
+				if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+					log.Errorf("error rolling back the signed claim txs: %v", rollbackErr)
+				}
+				if errors.Is(err, gerror.ErrStorageNotFound) {
+					return nil, status.Errorf(codes.FailedPrecondition, "the claim tx of the deposit %d is not pending of signature", c.mTx.DepositID)
+				}
+				return nil, err
+			}
+			depositCnts = append(depositCnts, uint64(c.mTx.DepositID))
+		}
+		if err := dbTx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		for _, c := range signed {
+			log.Infof("claim tx %s of the deposit %d signed offline", c.tx.Hash().String(), c.mTx.DepositID)
+		}
+		return &pb.SubmitSignedClaimsResponse{
+			DepositCnts: depositCnts,
+		}, nil
+	})
+}
+
+// claimChainID returns the chain id of the network the monitored tx claims the L1 deposit in, cached in chainIDs
+// for the next claim txs of the same network.
+func (s *bridgeService) claimChainID(ctx context.Context, mTx *ctmtypes.MonitoredTx, chainIDs map[uint]*big.Int) (*big.Int, error) {
+	// The claim txs are only sent for the L1 deposits
+	deposit, err := s.storage.GetDeposit(ctx, mTx.DepositID, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	if chainID, found := chainIDs[deposit.DestinationNetwork]; found {
+		return chainID, nil
+	}
+	simulator, found := s.claimSimulators[deposit.DestinationNetwork]
+	if !found {
+		return nil, status.Errorf(codes.FailedPrecondition, "the network %d of the deposit %d is not synced", deposit.DestinationNetwork, mTx.DepositID)
+	}
+	id, err := simulator.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chainID := new(big.Int).SetUint64(id)
+	chainIDs[deposit.DestinationNetwork] = chainID
+	return chainID, nil
+}
+
+// SetReserveAttester enables the proof of reserve attestations, signed by the attester.
+func (s *bridgeService) SetReserveAttester(attester ReserveAttester) {
+	s.reserve = attester
+}
+
+// GetReserveAttestation returns the signed report of the locked and minted amounts of each token bridged between
+// L1 and the L2 network, at the requested blocks or the last synced ones. Bridge rest API admin endpoint
+func (s *bridgeService) GetReserveAttestation(ctx context.Context, req *pb.GetReserveAttestationRequest) (*pb.GetReserveAttestationResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if s.reserve == nil {
+		return nil, status.Error(codes.Unimplemented, "the proof of reserve attestations are disabled")
+	}
+	if _, found := s.networkIDs[uint(req.NetId)]; !found || req.NetId == 0 {
+		return nil, gerror.ErrNetworkNotRegister
+	}
+	attestation, err := s.reserve.Attest(ctx, uint(req.NetId), req.L1Block, req.L2Block)
+	if errors.Is(err, gerror.ErrStorageNotFound) {
+		return nil, status.Error(codes.NotFound, "no synced block at the requested height")
+	} else if err != nil {
+		return nil, err
+	}
+	return &pb.GetReserveAttestationResponse{
+		Report:    string(attestation.Report),
+		Signer:    attestation.Signer.String(),
+		Signature: attestation.Signature.String(),
+	}, nil
+}
+
+// SetClaimBreakers exposes the circuit breakers of the claim tx managers of the networks.
+func (s *bridgeService) SetClaimBreakers(breakers []ClaimBreaker) {
+	s.claimBreakers = breakers
+}
+
+// GetClaimBreakers returns the state of the circuit breakers of the claim txs of the networks, empty when the
+// claim tx managers are disabled. Bridge rest API admin endpoint
+func (s *bridgeService) GetClaimBreakers(ctx context.Context, req *pb.GetClaimBreakersRequest) (*pb.GetClaimBreakersResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	var pbBreakers []*pb.ClaimBreaker
+	for _, breaker := range s.claimBreakers {
+		state := breaker.BreakerState()
+		if req.NetId != 0 && uint(req.NetId) != state.NetworkID {
+			continue
+		}
+		pbBreakers = append(pbBreakers, &pb.ClaimBreaker{
+			NetworkId: uint32(state.NetworkID),
+			State:     state.State,
+			Failures:  uint64(state.Failures),
+			LastError: state.LastError,
+			OpenedAt:  unixTime(state.OpenedAt),
+			RetryAt:   unixTime(state.RetryAt),
+		})
+	}
+	return &pb.GetClaimBreakersResponse{
+		Breakers: pbBreakers,
+	}, nil
+}
+
+// GetIndexSuggestions returns the indexes missing for the filters of the bridge queries, from the
+// pg_stat_statements profile. Bridge rest API admin endpoint
+func (s *bridgeService) GetIndexSuggestions(ctx context.Context, req *pb.GetIndexSuggestionsRequest) (*pb.GetIndexSuggestionsResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	suggestions, err := indexadvisor.Analyze(ctx, s.storage, req.MinCalls)
+	if err != nil {
+		return nil, err
+	}
+	pbSuggestions := make([]*pb.IndexSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		pbSuggestions = append(pbSuggestions, &pb.IndexSuggestion{
+			Table:       suggestion.Table,
+			Columns:     suggestion.Columns,
+			Statement:   suggestion.Statement(),
+			Calls:       suggestion.Calls,
+			TotalTimeMs: uint64(suggestion.TotalTime.Milliseconds()),
+			Queries:     suggestion.Queries,
+		})
+	}
+	return &pb.GetIndexSuggestionsResponse{
+		Suggestions: pbSuggestions,
+	}, nil
+}
+
+// GetSessionChallenge returns the EIP-712 typed data to be signed by a wallet to create a session.
+// Bridge rest API endpoint
+func (s *bridgeService) GetSessionChallenge(ctx context.Context, req *pb.GetSessionChallengeRequest) (*pb.GetSessionChallengeResponse, error) {
+	if s.sessions == nil {
+		return nil, status.Error(codes.Unimplemented, "wallet sessions are disabled")
+	}
+	if !common.IsHexAddress(req.Address) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", req.Address)
+	}
+	typedData, nonce, expiresAt := s.sessions.challenge(common.HexToAddress(req.Address))
+	data, err := encodeTypedData(typedData)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetSessionChallengeResponse{
+		TypedData: data,
+		Nonce:     nonce,
+		ExpiresAt: uint64(expiresAt),
+	}, nil
+}
+
+// CreateSession checks the signature of a challenge and returns the token of the session of the wallet.
+// Bridge rest API endpoint
+func (s *bridgeService) CreateSession(ctx context.Context, req *pb.CreateSessionRequest) (*pb.CreateSessionResponse, error) {
+	if s.sessions == nil {
+		return nil, status.Error(codes.Unimplemented, "wallet sessions are disabled")
+	}
+	if !common.IsHexAddress(req.Address) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", req.Address)
+	}
+	signature, err := decodeSignature(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	token, expiresAt, err := s.sessions.createSession(common.HexToAddress(req.Address), req.Nonce, int64(req.ExpiresAt), signature)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateSessionResponse{
+		Token:     token,
+		ExpiresAt: uint64(expiresAt),
+	}, nil
+}
+
+// GetWalletHistory returns the deposits to the address of the session, with their claims, status history
+// and the annotations of the wallet.
+// Bridge rest API session endpoint
+func (s *bridgeService) GetWalletHistory(ctx context.Context, req *pb.GetWalletHistoryRequest) (*pb.GetWalletHistoryResponse, error) {
+	addr, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bridges, err := s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr.Hex(), Offset: req.Offset, Limit: req.Limit})
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := s.storage.GetDepositAnnotations(ctx, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	notes := make(map[[2]uint]string, len(annotations))
+	for _, a := range annotations {
+		notes[[2]uint{a.NetworkID, a.DepositCnt}] = a.Note
+	}
+	res := &pb.GetWalletHistoryResponse{TotalCnt: bridges.TotalCnt}
+	for _, deposit := range bridges.Deposits {
+		walletDeposit := &pb.WalletDeposit{
+			Deposit:    deposit,
+			Annotation: notes[[2]uint{uint(deposit.NetworkId), uint(deposit.DepositCnt)}],
+		}
+		claim, err := s.storage.GetClaim(ctx, uint(deposit.DepositCnt), uint(deposit.DestNet), nil)
+		if err != nil && !errors.Is(err, gerror.ErrStorageNotFound) {
+			return nil, err
+		}
+		if claim != nil {
+			walletDeposit.Claim = &pb.Claim{
+				Index:       uint64(claim.Index),
+				OrigNet:     uint32(claim.OriginalNetwork),
+				OrigAddr:    claim.OriginalAddress.Hex(),
+				Amount:      claim.Amount.String(),
+				NetworkId:   uint32(claim.NetworkID),
+				DestAddr:    claim.DestinationAddress.Hex(),
+				BlockNum:    claim.BlockNumber,
+				TxHash:      claim.TxHash.String(),
+				LogIndex:    uint32(claim.LogIndex),
+				BlockTime:   unixTime(claim.BlockTime),
+				SyncedAt:    unixTime(claim.SyncedAt),
+				MessageCall: claim.MessageCall,
+			}
+		}
+		history, err := s.storage.GetDepositStatusHistory(ctx, uint(deposit.DepositCnt), uint(deposit.NetworkId), nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range history {
+			walletDeposit.History = append(walletDeposit.History, &pb.DepositStatusChange{
+				Status:    c.Status,
+				NetworkId: uint32(c.NetworkID),
+				BlockNum:  c.BlockNumber,
+				Time:      uint64(c.Time.Unix()),
+			})
+		}
+		res.Deposits = append(res.Deposits, walletDeposit)
+	}
+	return res, nil
+}
+
+// AnnotateDeposit sets the note of the wallet of the session on a deposit to its address.
+// Bridge rest API session endpoint
+func (s *bridgeService) AnnotateDeposit(ctx context.Context, req *pb.AnnotateDepositRequest) (*pb.AnnotateDepositResponse, error) {
+	addr, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if len(req.Note) > maxAnnotationLength {
+		return nil, status.Errorf(codes.InvalidArgument, "note longer than %d bytes", maxAnnotationLength)
+	}
+	deposit, err := s.storage.GetDeposit(ctx, uint(req.DepositCnt), uint(req.NetId), nil)
+	if err != nil {
+		return nil, err
+	}
+	if deposit.DestinationAddress != addr {
+		return nil, status.Error(codes.PermissionDenied, "the deposit is not to the address of the session")
+	}
+	err = s.storage.SetDepositAnnotation(ctx, &etherman.DepositAnnotation{
+		NetworkID:  deposit.NetworkID,
+		DepositCnt: deposit.DepositCount,
+		Address:    addr,
+		Note:       req.Note,
+		UpdatedAt:  time.Now(),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AnnotateDepositResponse{}, nil
+}
+
+// GetWalletWebhooks returns the webhooks of the wallet of the session.
+// Bridge rest API session endpoint
+func (s *bridgeService) GetWalletWebhooks(ctx context.Context, req *pb.GetWalletWebhooksRequest) (*pb.GetWalletWebhooksResponse, error) {
+	addr, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhooks, err := s.storage.GetWalletWebhooks(ctx, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.GetWalletWebhooksResponse{}
+	for i := range webhooks {
+		res.Webhooks = append(res.Webhooks, walletWebhookToPb(&webhooks[i]))
+	}
+	return res, nil
+}
+
+// AddWalletWebhook adds a webhook receiving the events of the deposits to the address of the session.
+// Bridge rest API session endpoint
+func (s *bridgeService) AddWalletWebhook(ctx context.Context, req *pb.AddWalletWebhookRequest) (*pb.AddWalletWebhookResponse, error) {
+	addr, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if err := webhook.ValidateWalletURL(req.Url); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid webhook url %s: %v", req.Url, err)
+	}
+	if err := (webhook.Filter{EventTypes: req.EventTypes}).Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	webhooks, err := s.storage.GetWalletWebhooks(ctx, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) >= s.maxWebhooks {
+		return nil, status.Errorf(codes.ResourceExhausted, "the wallet already has %d webhooks", len(webhooks))
+	}
+	w := &etherman.WalletWebhook{
+		Address:    addr,
+		URL:        req.Url,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.storage.AddWalletWebhook(ctx, w, nil); err != nil {
+		return nil, err
+	}
+	log.Infof("webhook %d of the wallet %s added", w.ID, addr.Hex())
+	return &pb.AddWalletWebhookResponse{
+		Webhook: walletWebhookToPb(w),
+	}, nil
+}
+
+// DeleteWalletWebhook deletes a webhook of the wallet of the session.
+// Bridge rest API session endpoint
+func (s *bridgeService) DeleteWalletWebhook(ctx context.Context, req *pb.DeleteWalletWebhookRequest) (*pb.DeleteWalletWebhookResponse, error) {
+	addr, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.storage.DeleteWalletWebhook(ctx, req.Id, addr, nil); err != nil {
+		return nil, err
+	}
+	log.Infof("webhook %d of the wallet %s deleted", req.Id, addr.Hex())
+	return &pb.DeleteWalletWebhookResponse{}, nil
+}
+
+// GetPushDevices returns the push notification devices of the wallet of the session.
+// Bridge rest API session endpoint
+func (s *bridgeService) GetPushDevices(ctx context.Context, req *pb.GetPushDevicesRequest) (*pb.GetPushDevicesResponse, error) {
+	addr, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := s.storage.GetPushDevices(ctx, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.GetPushDevicesResponse{}
+	for i := range devices {
+		res.Devices = append(res.Devices, pushDeviceToPb(&devices[i]))
+	}
+	return res, nil
+}
+
+// AddPushDevice registers a device receiving the push notifications of the deposits to the address of the
+// session. A device already registered by another wallet moves to this one.
+// Bridge rest API session endpoint
+func (s *bridgeService) AddPushDevice(ctx context.Context, req *pb.AddPushDeviceRequest) (*pb.AddPushDeviceResponse, error) {
+	addr, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if req.Platform != push.PlatformFCM && req.Platform != push.PlatformAPNs {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid push platform %s, must be %s or %s", req.Platform, push.PlatformFCM, push.PlatformAPNs)
+	}
+	if req.Token == "" || len(req.Token) > maxPushTokenLength {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid push token, must have between 1 and %d characters", maxPushTokenLength)
+	}
+	devices, err := s.storage.GetPushDevices(ctx, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range devices {
+		if devices[i].Platform == req.Platform && devices[i].Token == req.Token {
+			return &pb.AddPushDeviceResponse{Device: pushDeviceToPb(&devices[i])}, nil
+		}
+	}
+	if len(devices) >= s.maxDevices {
+		return nil, status.Errorf(codes.ResourceExhausted, "the wallet already has %d devices", len(devices))
+	}
+	d := &etherman.PushDevice{
+		Address:   addr,
+		Platform:  req.Platform,
+		Token:     req.Token,
+		CreatedAt: time.Now(),
+	}
+	if err := s.storage.AddPushDevice(ctx, d, nil); err != nil {
+		return nil, err
+	}
+	log.Infof("%s push device of the wallet %s added", d.Platform, addr.Hex())
+	return &pb.AddPushDeviceResponse{
+		Device: pushDeviceToPb(d),
+	}, nil
+}
+
+// DeletePushDevice deletes a push notification device of the wallet of the session.
+// Bridge rest API session endpoint
+func (s *bridgeService) DeletePushDevice(ctx context.Context, req *pb.DeletePushDeviceRequest) (*pb.DeletePushDeviceResponse, error) {
+	addr, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.storage.DeletePushDevice(ctx, addr, req.Platform, req.Token, nil); err != nil {
+		return nil, err
+	}
+	log.Infof("%s push device of the wallet %s deleted", req.Platform, addr.Hex())
+	return &pb.DeletePushDeviceResponse{}, nil
+}
+
+func pushDeviceToPb(d *etherman.PushDevice) *pb.PushDevice {
+	return &pb.PushDevice{
+		Platform:  d.Platform,
+		Token:     d.Token,
+		CreatedAt: uint64(d.CreatedAt.Unix()),
+	}
+}
+
+func walletWebhookToPb(w *etherman.WalletWebhook) *pb.WalletWebhook {
+	return &pb.WalletWebhook{
+		Id:         w.ID,
+		Url:        w.URL,
+		EventTypes: w.EventTypes,
+		CreatedAt:  uint64(w.CreatedAt.Unix()),
+	}
+}
+
+type nodeCacheEntry struct {
+	Key   hexutil.Bytes   `json:"key"`
+	Value []hexutil.Bytes `json:"value"`
+}
+
+// StateKey identifies the bridge service in the state file.
+func (s *bridgeService) StateKey() string {
+	return "bridgeservice"
+}
+
+// ExportState returns the cached merkle tree nodes, from the oldest to the newest.
+func (s *bridgeService) ExportState() (json.RawMessage, error) {
+	entries := make([]nodeCacheEntry, 0, s.cache.Len())
+	for _, key := range s.cache.Keys() {
+		value, ok := s.cache.Peek(key)
+		if !ok {
+			continue
+		}
+		entry := nodeCacheEntry{Key: []byte(key)}
+		for _, v := range value {
+			entry.Value = append(entry.Value, v)
+		}
+		entries = append(entries, entry)
+	}
+	return json.Marshal(entries)
+}
+
+// ImportState restores the cached merkle tree nodes. The nodes are indexed by their hash, so they never get outdated.
+func (s *bridgeService) ImportState(state json.RawMessage) error {
+	var entries []nodeCacheEntry
+	if err := json.Unmarshal(state, &entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		value := make([][]byte, 0, len(entry.Value))
+		for _, v := range entry.Value {
+			value = append(value, v)
+		}
+		s.cache.Add(string(entry.Key), value)
+	}
+	return nil
+}
+
+// unixTime returns the unix time of t, 0 if it's unknown.
+func unixTime(t time.Time) uint64 {
+	if t.Unix() <= 0 {
+		return 0
+	}
+	return uint64(t.Unix())
+}
+
+// optionalAddress returns the hex of an address that is not always set, empty when it's zero.
+func optionalAddress(addr common.Address) string {
+	if addr == (common.Address{}) {
+		return ""
+	}
+	return addr.Hex()
+}
@@ -0,0 +1,623 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const benchHeight = 32
+
+// benchStorage serves the fixture data of the benchmarks from memory, so they measure the service and not the database.
+type benchStorage struct {
+	bridgeServiceStorage
+	deposits []*etherman.Deposit
+	nodes    map[string][][]byte
+}
+
+// benchTx is the snapshot transaction of the bench storage, whose reads are served from memory.
+type benchTx struct {
+	pgx.Tx
+	rolledBack bool
+}
+
+func (tx *benchTx) Rollback(ctx context.Context) error {
+	tx.rolledBack = true
+	return nil
+}
+
+func (s *benchStorage) BeginSnapshotTransaction(ctx context.Context) (pgx.Tx, error) {
+	return &benchTx{}, nil
+}
+
+func (s *benchStorage) Get(ctx context.Context, key []byte, dbTx pgx.Tx) ([][]byte, error) {
+	value, found := s.nodes[string(key)]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return value, nil
+}
+
+// The proofs of the bench storage are public
+func (s *benchStorage) GetProofAccess(ctx context.Context, dbTx pgx.Tx) (*etherman.ProofAccess, error) {
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (s *benchStorage) GetProofConsumers(ctx context.Context, dbTx pgx.Tx) ([]etherman.ProofConsumer, error) {
+	return nil, nil
+}
+
+func (s *benchStorage) GetTokenList(ctx context.Context, dbTx pgx.Tx) ([]etherman.TokenListEntry, error) {
+	return nil, nil
+}
+
+func (s *benchStorage) GetDepositCount(ctx context.Context, addr string, role string, integration string, dbTx pgx.Tx) (uint64, error) {
+	return uint64(len(s.deposits)), nil
+}
+
+func (s *benchStorage) GetDeposits(ctx context.Context, addr string, role string, integration string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	if offset >= uint(len(s.deposits)) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > uint(len(s.deposits)) {
+		end = uint(len(s.deposits))
+	}
+	return s.deposits[offset:end], nil
+}
+
+func (s *benchStorage) GetClaim(ctx context.Context, index uint, networkID uint, dbTx pgx.Tx) (*etherman.Claim, error) {
+	// Half of the deposits are claimed
+	if index%2 == 1 {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &etherman.Claim{Index: index, NetworkID: networkID, TxHash: common.BigToHash(big.NewInt(int64(index)))}, nil
+}
+
+// The deposits of the bench storage have no reference ids
+func (s *benchStorage) GetDepositRef(ctx context.Context, networkID, depositCnt uint, dbTx pgx.Tx) (*etherman.DepositRef, error) {
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (s *benchStorage) GetDepositRefs(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.DepositRef, error) {
+	return nil, nil
+}
+
+func (s *benchStorage) GetTokenWrapped(ctx context.Context, originalNetwork uint, originalTokenAddress common.Address, dbTx pgx.Tx) (*etherman.TokenWrapped, error) {
+	return &etherman.TokenWrapped{TokenMetadata: etherman.TokenMetadata{Name: "CoinA", Symbol: "COA", Decimals: 12}}, nil
+}
+
+// newBenchStorage returns the storage with n deposits and the path of the tree to the leaf of the deposit index.
+func newBenchStorage(n int, index uint) (*benchStorage, [bridgectrl.KeyLen]byte) {
+	s := &benchStorage{nodes: make(map[string][][]byte)}
+	token := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	for i := 0; i < n; i++ {
+		deposit := &etherman.Deposit{
+			OriginalAddress:    token,
+			Amount:             big.NewInt(1000000000000000000),
+			DestinationNetwork: 1,
+			DestinationAddress: common.HexToAddress("0xc949254d682d8c9ad5682521675b8f43b102aec4"),
+			DepositCount:       uint(i),
+			BlockNumber:        uint64(i),
+			TxHash:             common.BigToHash(big.NewInt(int64(i))),
+			ReadyForClaim:      true,
+		}
+		// The metadata of the token is known for half of the deposits
+		if i%2 == 0 {
+			deposit.Metadata, _ = tokenMetadataArgs.Pack("CoinA", "COA", uint8(12))
+		} else {
+			deposit.OriginalAddress = common.Address{}
+		}
+		s.deposits = append(s.deposits, deposit)
+	}
+
+	cur := bridgectrl.HashDeposit(s.deposits[index%uint(n)])
+	for h := 0; h < benchHeight; h++ {
+		var sibling [bridgectrl.KeyLen]byte
+		sibling[0] = byte(h + 1)
+		left, right := cur, sibling
+		if index&(1<<h) > 0 {
+			left, right = sibling, cur
+		}
+		cur = bridgectrl.Hash(left, right)
+		s.nodes[string(cur[:])] = [][]byte{left[:], right[:]}
+	}
+	return s, cur
+}
+
+func newBenchService(storage *benchStorage) *bridgeService {
+	cfg := Config{CacheSize: 100000, DefaultPageLimit: 25, MaxPageLimit: 100}
+	return NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+}
+
+func TestGetProof(t *testing.T) {
+	const index = 5
+	storage, root := newBenchStorage(10, index)
+	s := newBenchService(storage)
+	siblings, err := s.getProof(index, root, nil)
+	require.NoError(t, err)
+	require.Equal(t, benchHeight, len(siblings))
+
+	// The proof rebuilds the root from the leaf
+	cur := bridgectrl.HashDeposit(storage.deposits[index])
+	for h, sibling := range siblings {
+		require.Equal(t, byte(h+1), sibling[0])
+		if index&(1<<h) > 0 {
+			cur = bridgectrl.Hash(sibling, cur)
+		} else {
+			cur = bridgectrl.Hash(cur, sibling)
+		}
+	}
+	require.Equal(t, root, cur)
+}
+
+func TestTraceProof(t *testing.T) {
+	const index = 5
+	storage, root := newBenchStorage(10, index)
+	s := newBenchService(storage)
+
+	trace := newProofTrace()
+	siblings, err := s.traceProof(index, root, trace, nil)
+	require.NoError(t, err)
+	res := trace.toPb()
+	require.Len(t, res.Nodes, benchHeight)
+	// The nodes are read from the root, and the siblings of the proof go from the leaf
+	require.Equal(t, common.Hash(root).Hex(), res.Nodes[0].Key)
+	for i, node := range res.Nodes {
+		require.Equal(t, uint32(benchHeight-1-i), node.Height)
+		require.Equal(t, common.Hash(siblings[benchHeight-1-i]).Hex(), node.Sibling)
+		require.Equal(t, nodeSourceDB, node.Source)
+	}
+
+	// The nodes are read from memory once loaded
+	trace = newProofTrace()
+	_, err = s.traceProof(index, root, trace, nil)
+	require.NoError(t, err)
+	for _, node := range trace.toPb().Nodes {
+		require.Equal(t, nodeSourceMemory, node.Source)
+	}
+
+	// A nil trace records nothing
+	var nilTrace *proofTrace
+	_, err = s.traceProof(index, root, nilTrace, nil)
+	require.NoError(t, err)
+	require.Nil(t, nilTrace.toPb())
+
+	// The traces require the admin token
+	s.adminToken = "admin"
+	_, err = s.GetProof(context.Background(), &pb.GetProofRequest{NetId: 0, DepositCnt: index, Debug: true})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func BenchmarkGetProof(b *testing.B) {
+	const index = 5
+	storage, root := newBenchStorage(10, index)
+	s := newBenchService(storage)
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.getProof(index, root, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s.cache.Purge()
+			if _, err := s.getProof(index, root, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetBridges(b *testing.B) {
+	ctx := context.Background()
+	storage, _ := newBenchStorage(100, 0)
+	s := newBenchService(storage)
+	req := &pb.GetBridgesRequest{DestAddr: "0xc949254d682d8c9ad5682521675b8f43b102aec4", Limit: 25}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, err := s.GetBridges(ctx, req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(resp.Deposits) != 25 {
+			b.Fatalf("unexpected deposits: %d", len(resp.Deposits))
+		}
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newBenchStorage(100, 0)
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 50, MaxPageResults: 80, AdminMaxPageLimit: 200, AdminToken: "admin"}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	addr := "0xc949254d682d8c9ad5682521675b8f43b102aec4"
+
+	resp, err := s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr})
+	require.NoError(t, err)
+	require.Len(t, resp.Deposits, 25)
+	resp, err = s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr, Offset: 30, Limit: 50})
+	require.NoError(t, err)
+	require.Len(t, resp.Deposits, 50)
+
+	// The limits over the max page limit and the pages after the max results are rejected
+	_, err = s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr, Limit: 51})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr, Offset: 60, Limit: 25})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr, AddressRole: "origin"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr, AddressRole: etherman.DepositAddressDepositor})
+	require.NoError(t, err)
+
+	// The admin requests have the admin max page limit and no max results
+	adminCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(adminTokenHeader, "admin"))
+	resp, err = s.GetBridges(adminCtx, &pb.GetBridgesRequest{DestAddr: addr, Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, resp.Deposits, 100)
+	_, err = s.GetBridges(adminCtx, &pb.GetBridgesRequest{DestAddr: addr, Offset: 60, Limit: 40})
+	require.NoError(t, err)
+	_, err = s.GetBridges(adminCtx, &pb.GetBridgesRequest{DestAddr: addr, Limit: 201})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The offsets that overflow once converted are rejected, even for the admin
+	_, err = s.GetBridges(adminCtx, &pb.GetBridgesRequest{DestAddr: addr, Offset: math.MaxUint64, Limit: 10})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.GetBridges(adminCtx, &pb.GetBridgesRequest{DestAddr: addr, Offset: math.MaxInt32 - 5, Limit: 10})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// activityStorage serves the activity buckets of a token per hour.
+type activityStorage struct {
+	*benchStorage
+	tokens int
+}
+
+func (s *activityStorage) GetActivity(ctx context.Context, networkID uint, originalNetwork uint, originalTokenAddress *common.Address, interval string, from, to time.Time, limit uint, dbTx pgx.Tx) ([]*etherman.ActivityBucket, error) {
+	var buckets []*etherman.ActivityBucket
+	for t := from.Truncate(time.Hour); t.Before(to) && uint(len(buckets)) < limit; t = t.Add(time.Hour) {
+		for i := 0; i < s.tokens && uint(len(buckets)) < limit; i++ {
+			buckets = append(buckets, &etherman.ActivityBucket{Time: t, OriginalAddress: common.BigToAddress(big.NewInt(int64(i))), DepositCount: 1, DepositVolume: big.NewInt(1), ClaimVolume: big.NewInt(0)})
+		}
+	}
+	return buckets, nil
+}
+
+func TestGetActivityLimit(t *testing.T) {
+	ctx := context.Background()
+	bench, _ := newBenchStorage(1, 0)
+	storage := &activityStorage{benchStorage: bench, tokens: 2}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 50}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	to := time.Unix(1700000000, 0).Truncate(time.Hour)
+
+	// The limit applies to the buckets of each token, not to the buckets of the range
+	res, err := s.GetActivity(ctx, &pb.GetActivityRequest{FromTime: uint64(to.Add(-25 * time.Hour).Unix()), ToTime: uint64(to.Unix())})
+	require.NoError(t, err)
+	require.Len(t, res.Buckets, 50)
+	_, err = s.GetActivity(ctx, &pb.GetActivityRequest{FromTime: uint64(to.Add(-26 * time.Hour).Unix()), ToTime: uint64(to.Unix())})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The ranges of more buckets than the max are rejected before reading them
+	storage.tokens = 1
+	_, err = s.GetActivity(ctx, &pb.GetActivityRequest{FromTime: uint64(to.Add(-50 * time.Hour).Unix()), ToTime: uint64(to.Unix())})
+	require.NoError(t, err)
+	_, err = s.GetActivity(ctx, &pb.GetActivityRequest{FromTime: uint64(to.Add(-51 * time.Hour).Unix()), ToTime: uint64(to.Unix())})
+	require.Error(t, err)
+}
+
+func TestGetBridgeAsOf(t *testing.T) {
+	bench, _ := newBenchStorage(2, 0)
+	start := time.Unix(1700000000, 0)
+	storage := newMemStorage(bench)
+	storage.history = []etherman.DepositStatusChange{
+		{Status: etherman.DepositStatusIndexed, BlockID: 1, NetworkID: 0, BlockNumber: 10, Time: start},
+		{Status: etherman.DepositStatusReadyForClaim, Time: start.Add(5 * time.Minute)},
+		{Status: etherman.DepositStatusClaimed, BlockID: 2, NetworkID: 1, BlockNumber: 100, Time: start.Add(10 * time.Minute)},
+	}
+	storage.blockTimes = map[uint]map[uint64]time.Time{
+		0: {5: start.Add(-time.Minute), 10: start, 20: start.Add(7 * time.Minute)},
+		1: {100: start.Add(10 * time.Minute)},
+	}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	ctx := context.Background()
+
+	// The current state
+	res, err := s.GetBridge(ctx, &pb.GetBridgeRequest{DepositCnt: 0})
+	require.NoError(t, err)
+	require.True(t, res.Deposit.ReadyForClaim)
+	require.NotEmpty(t, res.Deposit.ClaimTxHash)
+	require.Empty(t, res.History)
+
+	tcs := []struct {
+		name    string
+		net     uint32
+		block   uint64
+		ready   bool
+		claimed bool
+		changes int
+	}{
+		{"indexed", 0, 10, false, false, 1},
+		{"ready", 0, 25, true, false, 2},
+		{"claimed in the destination network", 1, 100, true, true, 3},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := s.GetBridge(ctx, &pb.GetBridgeRequest{DepositCnt: 0, AsOfNet: tc.net, AsOfBlock: tc.block})
+			require.NoError(t, err)
+			require.Equal(t, tc.ready, res.Deposit.ReadyForClaim)
+			require.Equal(t, tc.claimed, res.Deposit.ClaimTxHash != "")
+			require.Equal(t, tc.changes, len(res.History))
+			require.NotEmpty(t, res.LocalExitRoot)
+		})
+	}
+
+	// Before the deposit is indexed, or before any synced block
+	_, err = s.GetBridge(ctx, &pb.GetBridgeRequest{DepositCnt: 0, AsOfNet: 0, AsOfBlock: 9})
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	_, err = s.GetBridge(ctx, &pb.GetBridgeRequest{DepositCnt: 0, AsOfNet: 1, AsOfBlock: 99})
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+}
+
+// rollupStorage serves the L2 deposits of the bench storage, with the rollup exit tree of the multi rollup contracts.
+type rollupStorage struct {
+	*benchStorage
+	ger    *etherman.GlobalExitRoot
+	leaves []etherman.RollupExitLeaf
+}
+
+func (s *rollupStorage) GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	return s.deposits[depositCnt], nil
+}
+
+func (s *rollupStorage) GetLatestExitRoot(ctx context.Context, isRollup bool, dbTx pgx.Tx) (*etherman.GlobalExitRoot, error) {
+	return s.ger, nil
+}
+
+func (s *rollupStorage) GetRollupExitLeavesByRoot(ctx context.Context, root common.Hash, dbTx pgx.Tx) ([]etherman.RollupExitLeaf, error) {
+	if len(s.leaves) == 0 || s.leaves[len(s.leaves)-1].Root != root {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return s.leaves, nil
+}
+
+func TestGetClaimProofRollupExitTree(t *testing.T) {
+	const index = 5
+	ctx := context.Background()
+	bench, localExitRoot := newBenchStorage(10, index)
+	storage := &rollupStorage{benchStorage: bench}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1, 2, 3}, storage, nil)
+
+	// With a single rollup, the rollup exit root is its local exit root
+	storage.ger = &etherman.GlobalExitRoot{ExitRoots: []common.Hash{{}, localExitRoot}}
+	res, err := s.GetProof(ctx, &pb.GetProofRequest{NetId: 1, DepositCnt: index})
+	require.NoError(t, err)
+	require.Len(t, res.Proof.MerkleProof, benchHeight)
+	require.Empty(t, res.Proof.RollupMerkleProof)
+	require.Equal(t, common.Hash(localExitRoot).Hex(), res.Proof.LocalExitRoot)
+
+	// With the multi rollup contracts, the local exit root is the leaf of the rollup in the rollup exit tree
+	otherRollup := common.HexToHash("0x01")
+	leaves := [][bridgectrl.KeyLen]byte{otherRollup, localExitRoot}
+	rollupExitRoot := common.Hash(bridgectrl.ComputeRoot(leaves, benchHeight))
+	storage.leaves = []etherman.RollupExitLeaf{
+		{RollupID: 1, Leaf: otherRollup, Root: common.Hash(bridgectrl.ComputeRoot(leaves[:1], benchHeight))},
+		{RollupID: 2, Leaf: localExitRoot, Root: rollupExitRoot},
+	}
+	storage.ger = &etherman.GlobalExitRoot{ExitRoots: []common.Hash{{}, rollupExitRoot}}
+	res, err = s.GetProof(ctx, &pb.GetProofRequest{NetId: 2, DepositCnt: index})
+	require.NoError(t, err)
+	require.Len(t, res.Proof.MerkleProof, benchHeight)
+	require.Len(t, res.Proof.RollupMerkleProof, benchHeight)
+	require.Equal(t, common.Hash(localExitRoot).Hex(), res.Proof.LocalExitRoot)
+	require.Equal(t, &pb.GlobalIndex{GlobalIndex: "4294967301", RollupIndex: 1, LocalIndex: index}, res.Proof.GlobalIndex)
+	var rollupProof [][bridgectrl.KeyLen]byte
+	for _, sibling := range res.Proof.RollupMerkleProof {
+		rollupProof = append(rollupProof, common.HexToHash(sibling))
+	}
+	require.True(t, bridgectrl.CheckProof(localExitRoot, 1, rollupProof, rollupExitRoot))
+
+	// A rollup without a leaf can't be claimed yet
+	_, _, _, _, err = s.getClaimProofs(index, 3, nil)
+	require.ErrorIs(t, err, gerror.ErrDepositNotSynced)
+}
+
+// snapshotStorage fails the reads made outside the snapshot transaction it started.
+type snapshotStorage struct {
+	*rollupStorage
+	tx *benchTx
+}
+
+func (s *snapshotStorage) BeginSnapshotTransaction(ctx context.Context) (pgx.Tx, error) {
+	s.tx = &benchTx{}
+	return s.tx, nil
+}
+
+func (s *snapshotStorage) checkTx(dbTx pgx.Tx) error {
+	if s.tx == nil || dbTx != s.tx || s.tx.rolledBack {
+		return errors.New("read outside the snapshot")
+	}
+	return nil
+}
+
+func (s *snapshotStorage) Get(ctx context.Context, key []byte, dbTx pgx.Tx) ([][]byte, error) {
+	if err := s.checkTx(dbTx); err != nil {
+		return nil, err
+	}
+	return s.rollupStorage.Get(ctx, key, dbTx)
+}
+
+func (s *snapshotStorage) GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	if err := s.checkTx(dbTx); err != nil {
+		return nil, err
+	}
+	return s.rollupStorage.GetDeposit(ctx, depositCnt, networkID, dbTx)
+}
+
+func (s *snapshotStorage) GetLatestExitRoot(ctx context.Context, isRollup bool, dbTx pgx.Tx) (*etherman.GlobalExitRoot, error) {
+	if err := s.checkTx(dbTx); err != nil {
+		return nil, err
+	}
+	return s.rollupStorage.GetLatestExitRoot(ctx, isRollup, dbTx)
+}
+
+func (s *snapshotStorage) GetDepositCount(ctx context.Context, addr string, role string, integration string, dbTx pgx.Tx) (uint64, error) {
+	if err := s.checkTx(dbTx); err != nil {
+		return 0, err
+	}
+	return s.rollupStorage.GetDepositCount(ctx, addr, role, integration, dbTx)
+}
+
+func (s *snapshotStorage) GetDeposits(ctx context.Context, addr string, role string, integration string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	if err := s.checkTx(dbTx); err != nil {
+		return nil, err
+	}
+	return s.rollupStorage.GetDeposits(ctx, addr, role, integration, limit, offset, dbTx)
+}
+
+func (s *snapshotStorage) GetClaim(ctx context.Context, index uint, networkID uint, dbTx pgx.Tx) (*etherman.Claim, error) {
+	if err := s.checkTx(dbTx); err != nil {
+		return nil, err
+	}
+	return s.rollupStorage.GetClaim(ctx, index, networkID, dbTx)
+}
+
+func TestSnapshotReads(t *testing.T) {
+	const index = 5
+	ctx := context.Background()
+	bench, localExitRoot := newBenchStorage(10, index)
+	storage := &snapshotStorage{rollupStorage: &rollupStorage{
+		benchStorage: bench,
+		ger:          &etherman.GlobalExitRoot{ExitRoots: []common.Hash{localExitRoot, {}}},
+	}}
+	s := newBenchService(bench)
+	s.storage = storage
+
+	// The deposit, the exit roots and the tree of the proof are read in the same snapshot
+	res, err := s.GetProof(ctx, &pb.GetProofRequest{NetId: 0, DepositCnt: index})
+	require.NoError(t, err)
+	require.Len(t, res.Proof.MerkleProof, benchHeight)
+	require.True(t, storage.tx.rolledBack)
+
+	// The count, the deposits and their claims too
+	storage.tx = nil
+	bridges, err := s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: "0xc949254d682d8c9ad5682521675b8f43b102aec4"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), bridges.TotalCnt)
+	require.NotEmpty(t, bridges.Deposits[0].ClaimTxHash)
+	require.True(t, storage.tx.rolledBack)
+}
+
+// signedClaimStorage serves the claim txs pending of signature of L1 deposits to the network 1, and stores their
+// signed txs in the transaction committed.
+type signedClaimStorage struct {
+	*benchStorage
+	mTxs   []ctmtypes.MonitoredTx
+	signed map[uint][]byte
+	tx     *signedClaimTx
+}
+
+// signedClaimTx is the db tx of the signed claim txs, only stored on commit.
+type signedClaimTx struct {
+	pgx.Tx
+	signed     map[uint][]byte
+	rolledBack bool
+	storage    *signedClaimStorage
+}
+
+func (tx *signedClaimTx) Commit(ctx context.Context) error {
+	for depositID, data := range tx.signed {
+		tx.storage.signed[depositID] = data
+	}
+	return nil
+}
+
+func (tx *signedClaimTx) Rollback(ctx context.Context) error {
+	tx.rolledBack = true
+	return nil
+}
+
+func (s *signedClaimStorage) BeginDBTransaction(ctx context.Context) (pgx.Tx, error) {
+	s.tx = &signedClaimTx{signed: make(map[uint][]byte), storage: s}
+	return s.tx, nil
+}
+
+func (s *signedClaimStorage) GetClaimTxsByStatus(ctx context.Context, statuses []ctmtypes.MonitoredTxStatus, dbTx pgx.Tx) ([]ctmtypes.MonitoredTx, error) {
+	return s.mTxs, nil
+}
+
+func (s *signedClaimStorage) GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	return &etherman.Deposit{DepositCount: depositCnt, DestinationNetwork: 1}, nil
+}
+
+func (s *signedClaimStorage) AddSignedClaimTx(ctx context.Context, depositID uint, signedTx []byte, dbTx pgx.Tx) error {
+	if _, found := s.signed[depositID]; found {
+		return gerror.ErrStorageNotFound
+	}
+	dbTx.(*signedClaimTx).signed[depositID] = signedTx
+	return nil
+}
+
+func TestSubmitSignedClaims(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x2a3DD3EB832aF982ec71669E178424b10Dca2EDe")
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	bench, _ := newBenchStorage(4, 0)
+	storage := &signedClaimStorage{benchStorage: bench, signed: make(map[uint][]byte), mTxs: []ctmtypes.MonitoredTx{
+		{DepositID: 1, From: from, To: &to, Nonce: 1, Value: big.NewInt(0), Data: []byte{1}, Gas: 100000},
+		{DepositID: 2, From: from, To: &to, Nonce: 2, Value: big.NewInt(0), Data: []byte{2}, Gas: 100000},
+	}}
+	sign := func(mTx ctmtypes.MonitoredTx, chainID int64) string {
+		mTx.GasPrice = big.NewInt(1000000000)
+		tx, err := types.SignTx(mTx.Tx(), types.NewEIP155Signer(big.NewInt(chainID)), key)
+		require.NoError(t, err)
+		data, err := tx.MarshalBinary()
+		require.NoError(t, err)
+		return hexutil.Encode(data)
+	}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, AdminToken: "admin"}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, map[uint]ClaimSimulator{1: &amountSimulator{}})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, "admin"))
+
+	// A tx signed for another chain rejects the whole batch before storing any tx
+	_, err = s.SubmitSignedClaims(ctx, &pb.SubmitSignedClaimsRequest{SignedTxs: []string{sign(storage.mTxs[0], 1001), sign(storage.mTxs[1], 1)}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Nil(t, storage.tx)
+	require.Empty(t, storage.signed)
+
+	res, err := s.SubmitSignedClaims(ctx, &pb.SubmitSignedClaimsRequest{SignedTxs: []string{sign(storage.mTxs[0], 1001)}})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1}, res.DepositCnts)
+	require.Len(t, storage.signed, 1)
+
+	// A tx that can't be stored rolls back the others of the batch
+	_, err = s.SubmitSignedClaims(ctx, &pb.SubmitSignedClaimsRequest{SignedTxs: []string{sign(storage.mTxs[1], 1001), sign(storage.mTxs[0], 1001)}})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.True(t, storage.tx.rolledBack)
+	require.Len(t, storage.signed, 1)
+}
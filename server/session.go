@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// sessionTokenHeader is the header with the session token to call the wallet endpoints.
+// The http gateway forwards it as grpc metadata.
+const sessionTokenHeader = "x-session-token"
+
+const (
+	sessionDomainName    = "zkEVM Bridge Service"
+	sessionDomainVersion = "1"
+	sessionPrimaryType   = "Session"
+	sessionStatement     = "Sign in to the bridge service to access the private data of your address"
+)
+
+// sessionAuth issues the sessions of the wallets proving the ownership of their address with an EIP-712 signature.
+// The challenges and the tokens are authenticated with the secret, so they are not stored and any replica of the
+// service accepts them.
+type sessionAuth struct {
+	secret       []byte
+	chainID      int64
+	challengeTTL time.Duration
+	sessionTTL   time.Duration
+	now          func() time.Time
+}
+
+func newSessionAuth(cfg SessionConfig) *sessionAuth {
+	if cfg.Secret == "" {
+		return nil
+	}
+	return &sessionAuth{
+		secret:       []byte(cfg.Secret),
+		chainID:      int64(cfg.ChainID),
+		challengeTTL: cfg.ChallengeTTL.Duration,
+		sessionTTL:   cfg.SessionTTL.Duration,
+		now:          time.Now,
+	}
+}
+
+func (a *sessionAuth) mac(kind string, addr common.Address, expiresAt int64) string {
+	mac := hmac.New(sha256.New, a.secret)
+	fmt.Fprintf(mac, "%s:%s:%d", kind, addr.Hex(), expiresAt)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// challenge returns the typed data signed by the wallet to create a session, and its nonce and expiration.
+func (a *sessionAuth) challenge(addr common.Address) (apitypes.TypedData, string, int64) {
+	expiresAt := a.now().Add(a.challengeTTL).Unix()
+	nonce := a.mac("challenge", addr, expiresAt)
+	return a.typedData(addr, nonce, expiresAt), nonce, expiresAt
+}
+
+func (a *sessionAuth) typedData(addr common.Address, nonce string, expiresAt int64) apitypes.TypedData {
+	chainID := math.HexOrDecimal256(*big.NewInt(a.chainID))
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			sessionPrimaryType: {
+				{Name: "wallet", Type: "address"},
+				{Name: "statement", Type: "string"},
+				{Name: "nonce", Type: "string"},
+				{Name: "expiresAt", Type: "uint256"},
+			},
+		},
+		PrimaryType: sessionPrimaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:    sessionDomainName,
+			Version: sessionDomainVersion,
+			ChainId: &chainID,
+		},
+		Message: apitypes.TypedDataMessage{
+			"wallet":    addr.Hex(),
+			"statement": sessionStatement,
+			"nonce":     nonce,
+			"expiresAt": strconv.FormatInt(expiresAt, 10),
+		},
+	}
+}
+
+// createSession checks the signature of the challenge and returns the session token and its expiration.
+func (a *sessionAuth) createSession(addr common.Address, nonce string, expiresAt int64, signature []byte) (string, int64, error) {
+	if a.now().Unix() > expiresAt {
+		return "", 0, status.Error(codes.Unauthenticated, "expired challenge")
+	}
+	if !hmac.Equal([]byte(nonce), []byte(a.mac("challenge", addr, expiresAt))) {
+		return "", 0, status.Error(codes.Unauthenticated, "invalid challenge")
+	}
+	hash, _, err := apitypes.TypedDataAndHash(a.typedData(addr, nonce, expiresAt))
+	if err != nil {
+		return "", 0, err
+	}
+	signer, err := recoverSigner(hash, signature)
+	if err != nil {
+		return "", 0, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if signer != addr {
+		return "", 0, status.Errorf(codes.Unauthenticated, "challenge signed by %s instead of %s", signer.Hex(), addr.Hex())
+	}
+	sessionExpiresAt := a.now().Add(a.sessionTTL).Unix()
+	token := fmt.Sprintf("%s.%d.%s", addr.Hex(), sessionExpiresAt, a.mac("session", addr, sessionExpiresAt))
+	return token, sessionExpiresAt, nil
+}
+
+// recoverSigner returns the address that signed the hash. The recovery id can be 0/1 or 27/28, as sent by the wallets.
+func recoverSigner(hash []byte, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 { //nolint:gomnd
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// address returns the address of a valid session token.
+func (a *sessionAuth) address(token string) (common.Address, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || !common.IsHexAddress(parts[0]) { //nolint:gomnd
+		return common.Address{}, status.Error(codes.Unauthenticated, "invalid session token")
+	}
+	addr := common.HexToAddress(parts[0])
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || !hmac.Equal([]byte(parts[2]), []byte(a.mac("session", addr, expiresAt))) {
+		return common.Address{}, status.Error(codes.Unauthenticated, "invalid session token")
+	}
+	if a.now().Unix() > expiresAt {
+		return common.Address{}, status.Error(codes.Unauthenticated, "expired session")
+	}
+	return addr, nil
+}
+
+// checkSession returns the address of the wallet of the session token of the request.
+func (s *bridgeService) checkSession(ctx context.Context) (common.Address, error) {
+	if s.sessions == nil {
+		return common.Address{}, status.Error(codes.Unimplemented, "wallet sessions are disabled")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(sessionTokenHeader)
+	if len(tokens) == 0 {
+		return common.Address{}, status.Error(codes.Unauthenticated, "missing session token")
+	}
+	return s.sessions.address(tokens[0])
+}
+
+// encodeTypedData returns the JSON of the typed data, as passed to eth_signTypedData_v4.
+func encodeTypedData(typedData apitypes.TypedData) (string, error) {
+	data, err := json.Marshal(typedData)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// decodeSignature decodes the hex of a signature.
+func decodeSignature(signature string) ([]byte, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signature: %v", err)
+	}
+	return sig, nil
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// signChallenge signs the typed data of the challenge as a wallet does with eth_signTypedData_v4.
+func signChallenge(t *testing.T, typedData string, key []byte) string {
+	var data apitypes.TypedData
+	require.NoError(t, json.Unmarshal([]byte(typedData), &data))
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	require.NoError(t, err)
+	privateKey, err := crypto.ToECDSA(key)
+	require.NoError(t, err)
+	sig, err := crypto.Sign(hash, privateKey)
+	require.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig)
+}
+
+func withSession(ctx context.Context, token string) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(sessionTokenHeader, token))
+}
+
+func TestWalletSession(t *testing.T) {
+	ctx := context.Background()
+	key := common.FromHex("0x4eba8ff6000ded1e4f8a4e9bba0b3eb3f1e9a1d9156a16e45bb4a3c2a8b00f9b")
+	privateKey, err := crypto.ToECDSA(key)
+	require.NoError(t, err)
+	wallet := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	bench, _ := newBenchStorage(4, 0)
+	for _, deposit := range bench.deposits {
+		deposit.DestinationAddress = wallet
+	}
+	storage := newMemStorage(bench)
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, Session: SessionConfig{
+		Secret:       "secret",
+		ChainID:      1,
+		ChallengeTTL: types.NewDuration(time.Minute),
+		SessionTTL:   types.NewDuration(time.Hour),
+		MaxWebhooks:  1,
+		MaxDevices:   1,
+	}}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+
+	// The wallet signs the challenge to create the session
+	challenge, err := s.GetSessionChallenge(ctx, &pb.GetSessionChallengeRequest{Address: wallet.Hex()})
+	require.NoError(t, err)
+	signature := signChallenge(t, challenge.TypedData, key)
+	session, err := s.CreateSession(ctx, &pb.CreateSessionRequest{Address: wallet.Hex(), Nonce: challenge.Nonce, ExpiresAt: challenge.ExpiresAt, Signature: signature})
+	require.NoError(t, err)
+
+	// The signature of another address or of a modified challenge is rejected
+	other := common.HexToAddress("0x01")
+	_, err = s.CreateSession(ctx, &pb.CreateSessionRequest{Address: other.Hex(), Nonce: challenge.Nonce, ExpiresAt: challenge.ExpiresAt, Signature: signature})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.CreateSession(ctx, &pb.CreateSessionRequest{Address: wallet.Hex(), Nonce: challenge.Nonce, ExpiresAt: challenge.ExpiresAt + 1, Signature: signature})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	otherChallenge, err := s.GetSessionChallenge(ctx, &pb.GetSessionChallengeRequest{Address: other.Hex()})
+	require.NoError(t, err)
+	_, err = s.CreateSession(ctx, &pb.CreateSessionRequest{Address: other.Hex(), Nonce: otherChallenge.Nonce, ExpiresAt: otherChallenge.ExpiresAt, Signature: signChallenge(t, otherChallenge.TypedData, key)})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// The session gives access to the annotated history of the wallet
+	sessionCtx := withSession(ctx, session.Token)
+	_, err = s.AnnotateDeposit(sessionCtx, &pb.AnnotateDepositRequest{NetId: 0, DepositCnt: 2, Note: "rent"})
+	require.NoError(t, err)
+	history, err := s.GetWalletHistory(sessionCtx, &pb.GetWalletHistoryRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), history.TotalCnt)
+	require.Len(t, history.Deposits, 4)
+	require.Equal(t, "rent", history.Deposits[2].Annotation)
+	require.Empty(t, history.Deposits[1].Annotation)
+	require.NotNil(t, history.Deposits[0].Claim)
+	require.Nil(t, history.Deposits[1].Claim)
+	require.Len(t, history.Deposits[0].History, 1)
+
+	// The wallet only annotates its deposits
+	bench.deposits[3].DestinationAddress = other
+	_, err = s.AnnotateDeposit(sessionCtx, &pb.AnnotateDepositRequest{NetId: 0, DepositCnt: 3, Note: "not mine"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// The webhooks are validated and limited
+	_, err = s.AddWalletWebhook(sessionCtx, &pb.AddWalletWebhookRequest{Url: "ftp://example.com"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.AddWalletWebhook(sessionCtx, &pb.AddWalletWebhookRequest{Url: "http://169.254.169.254/latest/meta-data"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.AddWalletWebhook(sessionCtx, &pb.AddWalletWebhookRequest{Url: "https://example.com", EventTypes: []string{"unknown"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	res, err := s.AddWalletWebhook(sessionCtx, &pb.AddWalletWebhookRequest{Url: "https://example.com", EventTypes: []string{"claim_sent"}})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.Webhook.Id)
+	require.Equal(t, wallet, storage.webhooks[0].Address)
+	_, err = s.AddWalletWebhook(sessionCtx, &pb.AddWalletWebhookRequest{Url: "https://example.com"})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// The push devices are validated and limited, registering a device again doesn't add it
+	_, err = s.AddPushDevice(sessionCtx, &pb.AddPushDeviceRequest{Platform: "sms", Token: "token"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.AddPushDevice(sessionCtx, &pb.AddPushDeviceRequest{Platform: "fcm"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	device, err := s.AddPushDevice(sessionCtx, &pb.AddPushDeviceRequest{Platform: "fcm", Token: "token"})
+	require.NoError(t, err)
+	require.Equal(t, "token", device.Device.Token)
+	require.Equal(t, wallet, storage.devices[0].Address)
+	_, err = s.AddPushDevice(sessionCtx, &pb.AddPushDeviceRequest{Platform: "fcm", Token: "token"})
+	require.NoError(t, err)
+	_, err = s.AddPushDevice(sessionCtx, &pb.AddPushDeviceRequest{Platform: "apns", Token: "token"})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	devices, err := s.GetPushDevices(sessionCtx, &pb.GetPushDevicesRequest{})
+	require.NoError(t, err)
+	require.Len(t, devices.Devices, 1)
+
+	// The requests without a valid session are rejected
+	_, err = s.GetWalletHistory(ctx, &pb.GetWalletHistoryRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.GetWalletHistory(withSession(ctx, other.Hex()+session.Token[len(wallet.Hex()):]), &pb.GetWalletHistoryRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	s.sessions.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = s.GetWalletHistory(sessionCtx, &pb.GetWalletHistoryRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.CreateSession(ctx, &pb.CreateSessionRequest{Address: wallet.Hex(), Nonce: challenge.Nonce, ExpiresAt: challenge.ExpiresAt, Signature: signature})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Without a secret the wallet endpoints are disabled
+	s = newBenchService(bench)
+	_, err = s.GetSessionChallenge(ctx, &pb.GetSessionChallengeRequest{Address: wallet.Hex()})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+	_, err = s.GetWalletHistory(sessionCtx, &pb.GetWalletHistoryRequest{})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/signing"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unsignedPaths are the paths of the REST server whose responses are not signed.
+var unsignedPaths = map[string]bool{"/metrics": true}
+
+// signedService is the service whose responses are signed by the REST server.
+type signedService interface {
+	responseSigner() ResponseSigner
+}
+
+// SetResponseSigner signs the responses of the REST API with the signer.
+func (s *bridgeService) SetResponseSigner(signer ResponseSigner) {
+	s.signer = signer
+}
+
+func (s *bridgeService) responseSigner() ResponseSigner {
+	return s.signer
+}
+
+// signedResponseWriter keeps the response of the REST API until it's signed.
+type signedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *signedResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *signedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// signResponses adds the signature headers to the responses. The responses are served without them if they can't
+// be signed, so the consumers must reject the unsigned responses.
+func signResponses(h http.Handler, signer ResponseSigner) http.Handler {
+	exposed := strings.Join([]string{signing.DigestHeader, signing.SignerHeader, signing.SignedAtHeader, signing.SignatureHeader}, ",")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The streams are written as their messages are sent, they can't be signed
+		if unsignedPaths[r.URL.Path] || isStreamPath(r.URL.Path) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		signed := &signedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(signed, r)
+		signature, err := signer.Sign(r.Context(), r.URL.RequestURI(), signed.body.Bytes())
+		if err != nil {
+			log.Errorf("error signing the response of %s: %v", r.URL.Path, err)
+		} else {
+			signature.SetHeader(w.Header())
+			w.Header().Add("Access-Control-Expose-Headers", exposed)
+		}
+		w.WriteHeader(signed.status)
+		_, _ = w.Write(signed.body.Bytes())
+	})
+}
+
+func pbSigningKey(key *signing.Key) *pb.SigningKey {
+	res := &pb.SigningKey{
+		Signer: key.Signer.Hex(),
+		Status: key.Status,
+	}
+	if key.ActivatedAt != nil {
+		res.ActivatedAt = unixTime(*key.ActivatedAt)
+	}
+	if key.RetiredAt != nil {
+		res.RetiredAt = unixTime(*key.RetiredAt)
+	}
+	return res
+}
+
+// GetSigningKeys returns the operator keys signing the responses, with the times they signed them. Bridge rest API
+// endpoint
+func (s *bridgeService) GetSigningKeys(ctx context.Context, req *pb.GetSigningKeysRequest) (*pb.GetSigningKeysResponse, error) {
+	if s.signer == nil {
+		return nil, status.Error(codes.Unimplemented, "the responses are not signed")
+	}
+	keys, err := s.signer.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pbKeys := make([]*pb.SigningKey, 0, len(keys))
+	for i := range keys {
+		pbKeys = append(pbKeys, pbSigningKey(&keys[i]))
+	}
+	return &pb.GetSigningKeysResponse{Keys: pbKeys}, nil
+}
+
+// RotateSigningKey retires the key signing the responses and activates another configured key in every instance.
+// Bridge rest API admin endpoint
+func (s *bridgeService) RotateSigningKey(ctx context.Context, req *pb.RotateSigningKeyRequest) (*pb.RotateSigningKeyResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkConfigApproval(ctx, "RotateSigningKey"); err != nil {
+		return nil, err
+	}
+	if s.signer == nil {
+		return nil, status.Error(codes.Unimplemented, "the responses are not signed")
+	}
+	var signer common.Address
+	if req.Signer != "" {
+		if !common.IsHexAddress(req.Signer) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid signer %s", req.Signer)
+		}
+		signer = common.HexToAddress(req.Signer)
+	}
+	return idempotent(ctx, s, "RotateSigningKey", req, func() (*pb.RotateSigningKeyResponse, error) {
+		key, err := s.signer.Rotate(ctx, signer)
+		if errors.Is(err, signing.ErrUnknownKey) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		} else if errors.Is(err, signing.ErrActiveKey) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		} else if err != nil {
+			return nil, err
+		}
+		log.Warnf("the responses are signed by %s since %s", key.Signer.Hex(), key.ActivatedAt.Format(time.RFC3339))
+		return &pb.RotateSigningKeyResponse{Key: pbSigningKey(key)}, nil
+	})
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/signing"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSigner signs the responses with a constant signature of the request uri, and rotates between two keys.
+type fakeSigner struct {
+	active common.Address
+	keys   []signing.Key
+	signed []string
+}
+
+func (s *fakeSigner) Sign(ctx context.Context, requestURI string, body []byte) (*signing.Signature, error) {
+	s.signed = append(s.signed, requestURI+" "+string(body))
+	return &signing.Signature{Signer: s.active, SignedAt: 1700000000, Signature: []byte{1}}, nil
+}
+
+func (s *fakeSigner) Keys(ctx context.Context) ([]signing.Key, error) {
+	return s.keys, nil
+}
+
+func (s *fakeSigner) Rotate(ctx context.Context, signer common.Address) (*signing.Key, error) {
+	if signer == s.active {
+		return nil, signing.ErrActiveKey
+	}
+	now := time.Unix(1700000000, 0)
+	s.active = signer
+	return &signing.Key{Signer: signer, Status: signing.KeyActive, ActivatedAt: &now}, nil
+}
+
+func TestSignResponses(t *testing.T) {
+	signer := &fakeSigner{active: common.HexToAddress("0x1")}
+	handler := signResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":5}`))
+	}), signer)
+
+	// The responses are signed with their status and headers
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bridge?net_id=0&deposit_cnt=1", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, `{"code":5}`, w.Body.String())
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	require.Equal(t, common.HexToAddress("0x1").Hex(), w.Header().Get(signing.SignerHeader))
+	require.Equal(t, "1700000000", w.Header().Get(signing.SignedAtHeader))
+	require.Equal(t, []string{`/bridge?net_id=0&deposit_cnt=1 {"code":5}`}, signer.signed)
+
+	// The metrics are not signed
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Empty(t, w.Header().Get(signing.SignatureHeader))
+	require.Len(t, signer.signed, 1)
+}
+
+func TestSigningKeys(t *testing.T) {
+	bench, _ := newBenchStorage(4, 0)
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, AdminToken: "admin"}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, bench, nil)
+	admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, "admin"))
+	public := context.Background()
+
+	_, err := s.GetSigningKeys(public, &pb.GetSigningKeysRequest{})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+
+	activatedAt := time.Unix(1600000000, 0)
+	signer := &fakeSigner{
+		active: common.HexToAddress("0x1"),
+		keys: []signing.Key{
+			{Signer: common.HexToAddress("0x1"), Status: signing.KeyActive, ActivatedAt: &activatedAt},
+			{Signer: common.HexToAddress("0x2"), Status: signing.KeyStandby},
+		},
+	}
+	s.SetResponseSigner(signer)
+	keys, err := s.GetSigningKeys(public, &pb.GetSigningKeysRequest{})
+	require.NoError(t, err)
+	require.Len(t, keys.Keys, 2)
+	require.Equal(t, uint64(1600000000), keys.Keys[0].ActivatedAt)
+	require.Equal(t, "standby", keys.Keys[1].Status)
+	require.Zero(t, keys.Keys[1].ActivatedAt)
+
+	// The rotation is an admin endpoint
+	_, err = s.RotateSigningKey(public, &pb.RotateSigningKeyRequest{Signer: common.HexToAddress("0x2").Hex()})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.RotateSigningKey(admin, &pb.RotateSigningKeyRequest{Signer: common.HexToAddress("0x1").Hex()})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = s.RotateSigningKey(admin, &pb.RotateSigningKeyRequest{Signer: "key"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	rotated, err := s.RotateSigningKey(admin, &pb.RotateSigningKeyRequest{Signer: common.HexToAddress("0x2").Hex()})
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x2").Hex(), rotated.Key.Signer)
+	require.Equal(t, common.HexToAddress("0x2"), signer.active)
+}
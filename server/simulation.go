@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SimulateClaims simulates the claims of a page of the deposits to a network that are ready for claim and not
+// claimed, filtered by the destination address and the token, so the relayers can plan and price their claiming
+// runs. The claims that succeed are estimated with the gas price of the destination network. The page is limited to
+// the max simulated claims, since every claim sends calls to the node. Bridge rest API endpoint
+func (s *bridgeService) SimulateClaims(ctx context.Context, req *pb.SimulateClaimsRequest) (*pb.SimulateClaimsResponse, error) {
+	if err := s.checkProofConsumer(ctx); err != nil {
+		return nil, err
+	}
+	simulator, found := s.claimSimulators[uint(req.DestNet)]
+	if !found {
+		return nil, gerror.ErrNetworkNotRegister
+	}
+	for _, addr := range []string{req.DestAddr, req.OrigAddr, req.From} {
+		if addr != "" && !common.IsHexAddress(addr) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", addr)
+		}
+	}
+	if s.maxSimClaims > 0 && req.Limit > s.maxSimClaims {
+		return nil, status.Errorf(codes.InvalidArgument, "limit %d exceeds the max of %d simulated claims", req.Limit, s.maxSimClaims)
+	}
+	limit, err := s.pageLimit(ctx, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	// The default page limit can be over the max simulated claims
+	if s.maxSimClaims > 0 && limit > s.maxSimClaims {
+		limit = s.maxSimClaims
+	}
+	deposits, err := s.storage.GetClaimableDeposits(ctx, uint(req.DestNet), req.DestAddr, uint(req.OrigNet), req.OrigAddr, uint(limit), uint(req.Offset), nil)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := simulator.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.SimulateClaimsResponse{GasPrice: gasPrice.String()}
+	totalCost := new(big.Int)
+	for _, deposit := range deposits {
+		version, ger, smtProof, rollupProof, err := s.getClaimInputs(deposit)
+		if err != nil {
+			return nil, err
+		}
+		from := deposit.DestinationAddress
+		if req.From != "" {
+			from = common.HexToAddress(req.From)
+		}
+		claim := &pb.ClaimSimulation{
+			NetId:      uint32(deposit.NetworkID),
+			DepositCnt: uint64(deposit.DepositCount),
+			DestAddr:   deposit.DestinationAddress.Hex(),
+			OrigNet:    uint32(deposit.OriginalNetwork),
+			OrigAddr:   deposit.OriginalAddress.Hex(),
+			Amount:     deposit.Amount.String(),
+		}
+		var success bool
+		success, claim.RevertReason, err = simulator.SimulateClaim(ctx, from, version, deposit, smtProof, rollupProof, ger)
+		if err != nil {
+			return nil, err
+		}
+		if success {
+			tx, err := simulator.BuildClaimTx(ctx, from, version, deposit, smtProof, rollupProof, ger)
+			if err != nil {
+				return nil, err
+			}
+			cost := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas), gasPrice)
+			claim.Success, claim.Gas, claim.Cost = true, tx.Gas, cost.String()
+			res.TotalGas += tx.Gas
+			totalCost.Add(totalCost, cost)
+		} else {
+			res.FailedCnt++
+		}
+		res.Claims = append(res.Claims, claim)
+	}
+	res.TotalCost = totalCost.String()
+	return res, nil
+}
+
+// getClaimInputs returns the claim version of the deposit with the exit roots and the proofs to claim it. The formats
+// are picked per deposit from the proofs, like the ones returned by GetProof: the rollup deposits are claimed with v2
+// once their local exit root is in a rollup exit tree, and with v1 before. The L1 deposits have no rollup proof in
+// any version, they are claimed with the configured one.
+func (s *bridgeService) getClaimInputs(deposit *etherman.Deposit) (string, *etherman.GlobalExitRoot, [bridgectrl.KeyLen][bridgectrl.KeyLen]byte, [bridgectrl.KeyLen][bridgectrl.KeyLen]byte, error) {
+	var smtProof, rollupProof [bridgectrl.KeyLen][bridgectrl.KeyLen]byte
+	ger, merkleProof, rollupMerkleProof, _, err := s.getClaimProofs(deposit.DepositCount, deposit.NetworkID, nil)
+	if err != nil {
+		return "", nil, smtProof, rollupProof, err
+	}
+	if len(merkleProof) != len(smtProof) || (len(rollupMerkleProof) != 0 && len(rollupMerkleProof) != len(rollupProof)) {
+		return "", nil, smtProof, rollupProof, fmt.Errorf("invalid merkle proof length: %d, rollup merkle proof length: %d", len(merkleProof), len(rollupMerkleProof))
+	}
+	copy(smtProof[:], merkleProof)
+	copy(rollupProof[:], rollupMerkleProof)
+	return s.depositClaimVersion(deposit, len(rollupMerkleProof)), ger, smtProof, rollupProof, nil
+}
+
+// depositClaimVersion returns the claim version of the deposit with a rollup proof of the length.
+func (s *bridgeService) depositClaimVersion(deposit *etherman.Deposit, rollupProofLen int) string {
+	switch {
+	case rollupProofLen > 0:
+		return etherman.ClaimVersionV2
+	case deposit.NetworkID != 0 || s.claimVersion == "":
+		return etherman.ClaimVersionV1
+	default:
+		return s.claimVersion
+	}
+}
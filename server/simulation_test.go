@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// claimableStorage returns the same claimable deposits for any filter, up to the limit.
+type claimableStorage struct {
+	*rollupStorage
+	claimable []*etherman.Deposit
+}
+
+func (s *claimableStorage) GetClaimableDeposits(ctx context.Context, destNet uint, destAddr string, origNet uint, origAddr string, limit, offset uint, dbTx pgx.Tx) ([]*etherman.Deposit, error) {
+	if int(limit) < len(s.claimable) {
+		return s.claimable[:limit], nil
+	}
+	return s.claimable, nil
+}
+
+// amountSimulator reverts the claims of the deposits over the max amount, and of the empty ones without a reason, and
+// estimates the gas of the others.
+type amountSimulator struct {
+	maxAmount   *big.Int
+	from        common.Address
+	version     string
+	rollupProof [32][32]byte
+}
+
+func (s *amountSimulator) SimulateClaim(ctx context.Context, from common.Address, version string, deposit *etherman.Deposit, smtProof, rollupProof [32][32]byte, globalExitRoot *etherman.GlobalExitRoot) (bool, string, error) {
+	s.from, s.version, s.rollupProof = from, version, rollupProof
+	if deposit.Amount.Cmp(s.maxAmount) > 0 {
+		return false, "InvalidSmtProof", nil
+	}
+	if deposit.Amount.Sign() == 0 {
+		return false, "", nil
+	}
+	return true, "", nil
+}
+
+func (s *amountSimulator) BuildClaimTx(ctx context.Context, from common.Address, version string, deposit *etherman.Deposit, smtProof, rollupProof [32][32]byte, globalExitRoot *etherman.GlobalExitRoot) (*etherman.ClaimTx, error) {
+	return &etherman.ClaimTx{Gas: 100000 + uint64(deposit.DepositCount), Value: big.NewInt(0)}, nil
+}
+
+func (s *amountSimulator) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(10), nil
+}
+
+func (s *amountSimulator) ChainID(ctx context.Context) (uint64, error) {
+	return 1001, nil //nolint:gomnd
+}
+
+func TestSimulateClaims(t *testing.T) {
+	const index = 3
+	ctx := context.Background()
+	bench, localExitRoot := newBenchStorage(8, index)
+	over := *bench.deposits[index]
+	over.Amount = big.NewInt(2000000000000000000)
+	storage := &claimableStorage{
+		rollupStorage: &rollupStorage{benchStorage: bench, ger: &etherman.GlobalExitRoot{ExitRoots: []common.Hash{localExitRoot, common.HexToHash("0x02")}}},
+		claimable:     []*etherman.Deposit{bench.deposits[index], &over, bench.deposits[index]},
+	}
+	simulator := &amountSimulator{maxAmount: big.NewInt(1000000000000000000)}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, MaxSimulatedClaims: 3}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, map[uint]ClaimSimulator{1: simulator})
+
+	res, err := s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1})
+	require.NoError(t, err)
+	require.Len(t, res.Claims, 3)
+	require.True(t, res.Claims[0].Success)
+	require.Equal(t, uint64(100003), res.Claims[0].Gas)
+	require.Equal(t, "1000030", res.Claims[0].Cost)
+	require.False(t, res.Claims[1].Success)
+	require.Equal(t, "InvalidSmtProof", res.Claims[1].RevertReason)
+	require.Zero(t, res.Claims[1].Gas)
+	require.Equal(t, "10", res.GasPrice)
+	require.Equal(t, uint64(200006), res.TotalGas)
+	require.Equal(t, "2000060", res.TotalCost)
+	require.Equal(t, uint32(1), res.FailedCnt)
+	require.Equal(t, bench.deposits[index].DestinationAddress, simulator.from)
+	require.Equal(t, etherman.ClaimVersionV1, simulator.version)
+
+	// The claims are simulated for the relayer
+	relayer := common.HexToAddress("0x01")
+	_, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1, From: relayer.Hex()})
+	require.NoError(t, err)
+	require.Equal(t, relayer, simulator.from)
+
+	_, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1, OrigAddr: "dai"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1, Limit: 101})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The default page limit is capped by the max simulated claims, larger limits are rejected
+	storage.claimable = append(storage.claimable, bench.deposits[index])
+	res, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1})
+	require.NoError(t, err)
+	require.Len(t, res.Claims, 3)
+	res, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, res.Claims, 2)
+	_, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1, Limit: 4})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 0})
+	require.Error(t, err)
+
+	// The claims reverting without a reason fail
+	empty := *bench.deposits[index]
+	empty.Amount = big.NewInt(0)
+	storage.claimable = []*etherman.Deposit{&empty}
+	res, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1})
+	require.NoError(t, err)
+	require.False(t, res.Claims[0].Success)
+	require.Zero(t, res.TotalGas)
+	require.Equal(t, uint32(1), res.FailedCnt)
+
+	// The rollup deposits whose local exit root is in a rollup exit tree are simulated with the v2 proofs
+	rollup := *bench.deposits[index]
+	rollup.NetworkID = 1
+	rollupExitRoot := common.Hash(bridgectrl.ComputeRoot([][bridgectrl.KeyLen]byte{localExitRoot}, benchHeight))
+	storage.leaves = []etherman.RollupExitLeaf{{RollupID: 1, Leaf: localExitRoot, Root: rollupExitRoot}}
+	storage.ger = &etherman.GlobalExitRoot{ExitRoots: []common.Hash{{}, rollupExitRoot}}
+	storage.claimable = []*etherman.Deposit{&rollup}
+	res, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1})
+	require.NoError(t, err)
+	require.True(t, res.Claims[0].Success)
+	require.Equal(t, etherman.ClaimVersionV2, simulator.version)
+	require.NotEqual(t, [32][32]byte{}, simulator.rollupProof)
+
+	// The rollup deposits before the rollup exit tree are simulated with the v1 proofs
+	storage.leaves = nil
+	storage.ger = &etherman.GlobalExitRoot{ExitRoots: []common.Hash{localExitRoot, localExitRoot}}
+	_, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1})
+	require.NoError(t, err)
+	require.Equal(t, etherman.ClaimVersionV1, simulator.version)
+	require.Equal(t, [32][32]byte{}, simulator.rollupProof)
+
+	// The L1 deposits are simulated with the configured version
+	cfg.ClaimVersion = etherman.ClaimVersionV2
+	s = NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, map[uint]ClaimSimulator{1: simulator})
+	storage.claimable = []*etherman.Deposit{bench.deposits[index]}
+	_, err = s.SimulateClaims(ctx, &pb.SimulateClaimsRequest{DestNet: 1})
+	require.NoError(t, err)
+	require.Equal(t, etherman.ClaimVersionV2, simulator.version)
+}
+
+func TestClaimTxAddresses(t *testing.T) {
+	ctx := context.Background()
+	bench, _ := newBenchStorage(1, 0)
+	simulator := &amountSimulator{maxAmount: big.NewInt(1000000000000000000)}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, bench, map[uint]ClaimSimulator{1: simulator})
+
+	// The addresses that aren't hex are rejected instead of being read as other addresses
+	_, err := s.GetClaimTx(ctx, &pb.GetClaimTxRequest{DepositCnt: 0, From: "0xrelayer"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.ValidateClaim(ctx, &pb.ValidateClaimRequest{DestNet: 1, Amount: "1", From: "relayer"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.ValidateClaim(ctx, &pb.ValidateClaimRequest{DestNet: 1, Amount: "1", DestAddr: "0x1234"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
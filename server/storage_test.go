@@ -0,0 +1,397 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// memStorage is the storage of the server tests: it serves the deposits of the bench storage, and keeps in memory
+// the data the tests store.
+type memStorage struct {
+	*benchStorage
+	// history is the status history of the deposits, an indexed status at the block of the deposit count if it's nil
+	history []etherman.DepositStatusChange
+	// blockTimes are the times of the synced blocks of each network
+	blockTimes  map[uint]map[uint64]time.Time
+	activities  []etherman.DepositActivity
+	annotations []etherman.DepositAnnotation
+	webhooks    []etherman.WalletWebhook
+	devices     []etherman.PushDevice
+	refs        []etherman.DepositRef
+	// tokenList and its audit log, from the latest change
+	tokenList        []etherman.TokenListEntry
+	tokenListChanges []etherman.TokenListChange
+	configChanges    []etherman.ConfigChange
+	watches          []etherman.WatchedAddress
+	// bridgeReads counts the reads of the bridges of the addresses
+	bridgeReads int
+	maintenance *etherman.Maintenance
+	// release releases the admin queries, which run in the background with the jobs guarded by mu
+	release chan struct{}
+	mu      sync.Mutex
+	jobs    map[string]*etherman.QueryJob
+	results map[string]*etherman.AdminQueryResult
+}
+
+func newMemStorage(bench *benchStorage) *memStorage {
+	return &memStorage{
+		benchStorage: bench,
+		jobs:         make(map[string]*etherman.QueryJob),
+		results:      make(map[string]*etherman.AdminQueryResult),
+	}
+}
+
+func (s *memStorage) GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	if depositCnt >= uint(len(s.deposits)) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	deposit := *s.deposits[depositCnt]
+	return &deposit, nil
+}
+
+func (s *memStorage) GetDepositStatusHistory(ctx context.Context, depositCnt, networkID uint, dbTx pgx.Tx) ([]etherman.DepositStatusChange, error) {
+	if s.history == nil {
+		return []etherman.DepositStatusChange{{Status: etherman.DepositStatusIndexed, BlockNumber: uint64(depositCnt), Time: time.Unix(1700000000, 0)}}, nil
+	}
+	return s.history, nil
+}
+
+func (s *memStorage) GetHistoryPoint(ctx context.Context, networkID uint, blockNum uint64, dbTx pgx.Tx) (*etherman.HistoryPoint, error) {
+	point := &etherman.HistoryPoint{NetworkID: networkID, BlockNumber: blockNum}
+	var last uint64
+	for num, t := range s.blockTimes[networkID] {
+		if num <= blockNum && num >= last {
+			last, point.Time = num, t
+		}
+	}
+	if point.Time.IsZero() {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return point, nil
+}
+
+func (s *memStorage) GetLocalExitRootAt(ctx context.Context, networkID uint, point etherman.HistoryPoint, dbTx pgx.Tx) (common.Hash, error) {
+	return common.BigToHash(big.NewInt(int64(point.Time.Unix()))), nil
+}
+
+func (s *memStorage) GetDepositActivities(ctx context.Context, networkID, depositCnt uint, q string, limit uint, dbTx pgx.Tx) ([]etherman.DepositActivity, error) {
+	var entries []etherman.DepositActivity
+	for _, e := range s.activities {
+		if q == "" || matchActivity(e, strings.ToLower(q)) {
+			entries = append(entries, e)
+		}
+	}
+	if int(limit) < len(entries) {
+		return entries[len(entries)-int(limit):], nil
+	}
+	return entries, nil
+}
+
+// The wallet sessions
+func (s *memStorage) SetDepositAnnotation(ctx context.Context, annotation *etherman.DepositAnnotation, dbTx pgx.Tx) error {
+	s.annotations = append(s.annotations, *annotation)
+	return nil
+}
+
+func (s *memStorage) GetDepositAnnotations(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.DepositAnnotation, error) {
+	return s.annotations, nil
+}
+
+func (s *memStorage) GetWalletWebhooks(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.WalletWebhook, error) {
+	return s.webhooks, nil
+}
+
+func (s *memStorage) AddWalletWebhook(ctx context.Context, webhook *etherman.WalletWebhook, dbTx pgx.Tx) error {
+	webhook.ID = uint64(len(s.webhooks) + 1)
+	s.webhooks = append(s.webhooks, *webhook)
+	return nil
+}
+
+func (s *memStorage) GetPushDevices(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.PushDevice, error) {
+	return s.devices, nil
+}
+
+func (s *memStorage) AddPushDevice(ctx context.Context, device *etherman.PushDevice, dbTx pgx.Tx) error {
+	s.devices = append(s.devices, *device)
+	return nil
+}
+
+// The reference ids are unique per address, as in the table
+func (s *memStorage) SetDepositRef(ctx context.Context, ref *etherman.DepositRef, dbTx pgx.Tx) error {
+	refs := s.refs[:0]
+	for _, r := range s.refs {
+		if r.NetworkID == ref.NetworkID && r.DepositCnt == ref.DepositCnt {
+			continue
+		}
+		if ref.RefID != "" && r.Address == ref.Address && r.RefID == ref.RefID {
+			return gerror.ErrAlreadyProcessed
+		}
+		refs = append(refs, r)
+	}
+	if ref.RefID != "" {
+		refs = append(refs, *ref)
+	}
+	s.refs = refs
+	return nil
+}
+
+func (s *memStorage) GetDepositRef(ctx context.Context, networkID, depositCnt uint, dbTx pgx.Tx) (*etherman.DepositRef, error) {
+	for _, r := range s.refs {
+		if r.NetworkID == networkID && r.DepositCnt == depositCnt {
+			return &r, nil
+		}
+	}
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (s *memStorage) GetDepositRefs(ctx context.Context, address common.Address, dbTx pgx.Tx) ([]etherman.DepositRef, error) {
+	var refs []etherman.DepositRef
+	for _, r := range s.refs {
+		if r.Address == address {
+			refs = append(refs, r)
+		}
+	}
+	return refs, nil
+}
+
+// The admin queries run once they are released
+func (s *memStorage) GetAdminQueries() []etherman.AdminQuery {
+	return []etherman.AdminQuery{{Name: "address_history"}, {Name: "failing"}}
+}
+
+func (s *memStorage) RunAdminQueryWithTimeout(ctx context.Context, name string, params map[string]string, limit uint, timeout time.Duration) (*etherman.AdminQueryResult, error) {
+	<-s.release
+	if name == "failing" {
+		return nil, errors.New("canceling statement due to statement timeout")
+	}
+	return &etherman.AdminQueryResult{Columns: []string{"address", "limit"}, Rows: [][]string{{params["address"], "10"}}}, nil
+}
+
+func (s *memStorage) AddQueryJob(ctx context.Context, job *etherman.QueryJob, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *job
+	s.jobs[job.ID] = &stored
+	return nil
+}
+
+func (s *memStorage) FinishQueryJob(ctx context.Context, id string, status string, jobErr string, result *etherman.AdminQueryResult, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.jobs[id]
+	job.Status, job.Error, job.FinishedAt = status, jobErr, time.Now()
+	if result != nil {
+		job.Rows = uint64(len(result.Rows))
+		s.results[id] = result
+	}
+	return nil
+}
+
+func (s *memStorage) GetQueryJob(ctx context.Context, id string, dbTx pgx.Tx) (*etherman.QueryJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, found := s.jobs[id]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	stored := *job
+	return &stored, nil
+}
+
+func (s *memStorage) GetQueryJobResult(ctx context.Context, id string, offset, limit uint, dbTx pgx.Tx) (*etherman.AdminQueryResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := s.results[id]
+	rows := result.Rows
+	if offset > uint(len(rows)) {
+		offset = uint(len(rows))
+	}
+	rows = rows[offset:]
+	if limit < uint(len(rows)) {
+		rows = rows[:limit]
+	}
+	return &etherman.AdminQueryResult{Columns: result.Columns, Rows: rows}, nil
+}
+
+func (s *memStorage) DeleteQueryJobs(ctx context.Context, before time.Time, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, job := range s.jobs {
+		if job.CreatedAt.Before(before) {
+			delete(s.jobs, id)
+		}
+	}
+	return nil
+}
+
+func (s *memStorage) GetTokenList(ctx context.Context, dbTx pgx.Tx) ([]etherman.TokenListEntry, error) {
+	return append([]etherman.TokenListEntry(nil), s.tokenList...), nil
+}
+
+func (s *memStorage) SetTokenListEntry(ctx context.Context, entry *etherman.TokenListEntry, dbTx pgx.Tx) error {
+	s.tokenListChanges = append([]etherman.TokenListChange{{
+		ID: uint64(len(s.tokenListChanges) + 1), Action: etherman.TokenListActionSet, OriginalNetwork: entry.OriginalNetwork,
+		OriginalAddress: entry.OriginalAddress, List: entry.List, Reason: entry.Reason, Operator: entry.Operator,
+		CreatedAt: entry.UpdatedAt,
+	}}, s.tokenListChanges...)
+	for i := range s.tokenList {
+		if s.tokenList[i].OriginalNetwork == entry.OriginalNetwork && s.tokenList[i].OriginalAddress == entry.OriginalAddress {
+			s.tokenList[i] = *entry
+			return nil
+		}
+	}
+	s.tokenList = append(s.tokenList, *entry)
+	return nil
+}
+
+func (s *memStorage) DeleteTokenListEntry(ctx context.Context, change *etherman.TokenListChange, dbTx pgx.Tx) error {
+	for i, e := range s.tokenList {
+		if e.OriginalNetwork == change.OriginalNetwork && e.OriginalAddress == change.OriginalAddress {
+			s.tokenList = append(s.tokenList[:i], s.tokenList[i+1:]...)
+			c := *change
+			c.ID, c.Action, c.List = uint64(len(s.tokenListChanges)+1), etherman.TokenListActionDelete, e.List
+			s.tokenListChanges = append([]etherman.TokenListChange{c}, s.tokenListChanges...)
+			return nil
+		}
+	}
+	return gerror.ErrStorageNotFound
+}
+
+func (s *memStorage) GetTokenListChanges(ctx context.Context, limit, offset uint, dbTx pgx.Tx) ([]etherman.TokenListChange, error) {
+	if offset > uint(len(s.tokenListChanges)) {
+		return nil, nil
+	}
+	changes := s.tokenListChanges[offset:]
+	if limit < uint(len(changes)) {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
+
+func (s *memStorage) AddConfigChange(ctx context.Context, change *etherman.ConfigChange, dbTx pgx.Tx) error {
+	change.ID = uint64(len(s.configChanges) + 1)
+	s.configChanges = append(s.configChanges, *change)
+	return nil
+}
+
+func (s *memStorage) GetConfigChange(ctx context.Context, id uint64, dbTx pgx.Tx) (*etherman.ConfigChange, error) {
+	if id == 0 || id > uint64(len(s.configChanges)) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	change := s.configChanges[id-1]
+	return &change, nil
+}
+
+func (s *memStorage) GetConfigChanges(ctx context.Context, st string, now time.Time, limit, offset uint, dbTx pgx.Tx) ([]etherman.ConfigChange, error) {
+	var changes []etherman.ConfigChange
+	for i := len(s.configChanges) - 1; i >= 0; i-- {
+		change := s.configChanges[i]
+		change.Status = configChangeStatus(&change, now)
+		if st == "" || change.Status == st {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (s *memStorage) ReviewConfigChange(ctx context.Context, id uint64, st, reviewer string, reviewedAt time.Time, dbTx pgx.Tx) error {
+	change := &s.configChanges[id-1]
+	if change.Status != etherman.ConfigChangePending || !reviewedAt.Before(change.ExpiresAt) ||
+		(st != etherman.ConfigChangeRejected && change.Proposer == reviewer) {
+		return gerror.ErrAlreadyProcessed
+	}
+	change.Status, change.Reviewer, change.ReviewedAt = st, reviewer, &reviewedAt
+	return nil
+}
+
+func (s *memStorage) SetConfigChangeResult(ctx context.Context, id uint64, st, errMsg string, dbTx pgx.Tx) error {
+	s.configChanges[id-1].Status, s.configChanges[id-1].Error = st, errMsg
+	return nil
+}
+
+// The reads of the bridges are counted, and the addresses have no claims
+func (s *memStorage) GetDepositCount(ctx context.Context, addr string, role string, integration string, dbTx pgx.Tx) (uint64, error) {
+	s.bridgeReads++
+	return s.benchStorage.GetDepositCount(ctx, addr, role, integration, dbTx)
+}
+
+func (s *memStorage) GetClaimCount(ctx context.Context, destAddr string, dbTx pgx.Tx) (uint64, error) {
+	return 0, nil
+}
+
+func (s *memStorage) GetClaims(ctx context.Context, destAddr string, limit uint, offset uint, dbTx pgx.Tx) ([]*etherman.Claim, error) {
+	return nil, nil
+}
+
+func (s *memStorage) AddWatchedAddress(ctx context.Context, watch *etherman.WatchedAddress, dbTx pgx.Tx) error {
+	s.watches = append(s.watches, *watch)
+	return nil
+}
+
+func (s *memStorage) GetWatchedAddresses(ctx context.Context, owner common.Address, dbTx pgx.Tx) ([]etherman.WatchedAddress, error) {
+	var watches []etherman.WatchedAddress
+	for _, w := range s.watches {
+		if w.Owner == owner {
+			watches = append(watches, w)
+		}
+	}
+	return watches, nil
+}
+
+func (s *memStorage) GetAllWatchedAddresses(ctx context.Context, dbTx pgx.Tx) ([]common.Address, error) {
+	var addresses []common.Address
+	for _, w := range s.watches {
+		addresses = append(addresses, w.Address)
+	}
+	return addresses, nil
+}
+
+func (s *memStorage) IsWatchedAddress(ctx context.Context, address common.Address, dbTx pgx.Tx) (bool, error) {
+	for _, w := range s.watches {
+		if w.Address == address {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memStorage) DeleteWatchedAddress(ctx context.Context, owner, address common.Address, dbTx pgx.Tx) error {
+	for i, w := range s.watches {
+		if w.Owner == owner && w.Address == address {
+			s.watches = append(s.watches[:i], s.watches[i+1:]...)
+			return nil
+		}
+	}
+	return gerror.ErrStorageNotFound
+}
+
+func (s *memStorage) RequestWatchBackfill(ctx context.Context, owner, address common.Address, requestedAt time.Time, dbTx pgx.Tx) error {
+	for i, w := range s.watches {
+		if w.Owner == owner && w.Address == address {
+			s.watches[i].BackfillRequestedAt, s.watches[i].BackfilledAt = &requestedAt, nil
+			return nil
+		}
+	}
+	return gerror.ErrStorageNotFound
+}
+
+func (s *memStorage) GetMaintenance(ctx context.Context, dbTx pgx.Tx) (*etherman.Maintenance, error) {
+	if s.maintenance == nil {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return s.maintenance, nil
+}
+
+func (s *memStorage) SetMaintenance(ctx context.Context, maintenance *etherman.Maintenance, dbTx pgx.Tx) error {
+	s.maintenance = maintenance
+	return nil
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxTokenListReasonLength is the max length in bytes of the reason of a change of the token lists
+	maxTokenListReasonLength = 256
+	// maxTokenListOperatorLength is the max length in bytes of the operator of a change of the token lists
+	maxTokenListOperatorLength = 64
+)
+
+// tokenKey identifies a token by its original network and address.
+type tokenKey struct {
+	network uint
+	address common.Address
+}
+
+// tokenLists caches the token lists, reloading them from the storage every refresh interval so the changes made
+// through another instance are applied.
+type tokenLists struct {
+	storage         bridgeServiceStorage
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu       sync.Mutex
+	loadedAt time.Time
+	entries  []etherman.TokenListEntry
+	lists    map[tokenKey]string
+}
+
+func newTokenLists(cfg TokenListConfig, storage bridgeServiceStorage) *tokenLists {
+	return &tokenLists{
+		storage:         storage,
+		refreshInterval: cfg.RefreshInterval.Duration,
+		now:             time.Now,
+	}
+}
+
+// load returns the tokens in the lists and their lists by token, reloaded when they are older than the refresh
+// interval.
+func (l *tokenLists) load(ctx context.Context) ([]etherman.TokenListEntry, map[tokenKey]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.loadedAt.IsZero() && l.now().Sub(l.loadedAt) < l.refreshInterval {
+		return l.entries, l.lists, nil
+	}
+	entries, err := l.storage.GetTokenList(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	lists := make(map[tokenKey]string, len(entries))
+	for _, e := range entries {
+		lists[tokenKey{e.OriginalNetwork, e.OriginalAddress}] = e.List
+	}
+	l.entries, l.lists, l.loadedAt = entries, lists, l.now()
+	return l.entries, l.lists, nil
+}
+
+// invalidate reloads the lists in the next request, so the changes of this instance apply at once.
+func (l *tokenLists) invalidate() {
+	l.mu.Lock()
+	l.loadedAt = time.Time{}
+	l.mu.Unlock()
+}
+
+// fillTokenLists sets the list of the original token of the deposits. The deposits are served without it if the
+// lists can't be loaded.
+func (s *bridgeService) fillTokenLists(ctx context.Context, deposits []*pb.Deposit) {
+	_, lists, err := s.tokenLists.load(ctx)
+	if err != nil {
+		log.Warnf("error loading the token lists: %v", err)
+		return
+	}
+	if len(lists) == 0 {
+		return
+	}
+	for _, deposit := range deposits {
+		deposit.TokenList = lists[tokenKey{uint(deposit.OrigNet), common.HexToAddress(deposit.OrigAddr)}]
+	}
+}
+
+// checkTokenListChange checks the token, the reason and the operator of a change of the token lists.
+func checkTokenListChange(origAddr, reason, operator string) error {
+	if !common.IsHexAddress(origAddr) {
+		return status.Errorf(codes.InvalidArgument, "invalid token address %s", origAddr)
+	}
+	if len(reason) > maxTokenListReasonLength {
+		return status.Errorf(codes.InvalidArgument, "the reason must have at most %d bytes", maxTokenListReasonLength)
+	}
+	if operator == "" || len(operator) > maxTokenListOperatorLength {
+		return status.Errorf(codes.InvalidArgument, "the operator must have 1 to %d bytes", maxTokenListOperatorLength)
+	}
+	return nil
+}
+
+// GetTokenList returns the tokens in the allow and deny lists. Bridge rest API admin endpoint
+func (s *bridgeService) GetTokenList(ctx context.Context, req *pb.GetTokenListRequest) (*pb.GetTokenListResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	entries, _, err := s.tokenLists.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pbEntries := make([]*pb.TokenListEntry, 0, len(entries))
+	for _, e := range entries {
+		pbEntries = append(pbEntries, &pb.TokenListEntry{
+			OrigNet:   uint32(e.OriginalNetwork),
+			OrigAddr:  e.OriginalAddress.Hex(),
+			List:      e.List,
+			Reason:    e.Reason,
+			Operator:  e.Operator,
+			UpdatedAt: unixTime(e.UpdatedAt),
+		})
+	}
+	return &pb.GetTokenListResponse{Entries: pbEntries}, nil
+}
+
+// SetTokenListEntry adds a token to the allow or the deny list in every instance, logging the change in the
+// audit log. Bridge rest API admin endpoint
+func (s *bridgeService) SetTokenListEntry(ctx context.Context, req *pb.SetTokenListEntryRequest) (*pb.SetTokenListEntryResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkConfigApproval(ctx, "SetTokenListEntry"); err != nil {
+		return nil, err
+	}
+	if req.List != etherman.TokenListAllow && req.List != etherman.TokenListDeny {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown token list %q, it must be allow or deny", req.List)
+	}
+	if err := checkTokenListChange(req.OrigAddr, req.Reason, req.Operator); err != nil {
+		return nil, err
+	}
+	return idempotent(ctx, s, "SetTokenListEntry", req, func() (*pb.SetTokenListEntryResponse, error) {
+		entry := &etherman.TokenListEntry{
+			OriginalNetwork: uint(req.OrigNet),
+			OriginalAddress: common.HexToAddress(req.OrigAddr),
+			List:            req.List,
+			Reason:          req.Reason,
+			Operator:        req.Operator,
+			UpdatedAt:       time.Now(),
+		}
+		if err := s.storage.SetTokenListEntry(ctx, entry, nil); err != nil {
+			return nil, err
+		}
+		s.tokenLists.invalidate()
+		log.Warnf("token %d:%s added to the %s list by %s, reason: %s", req.OrigNet, entry.OriginalAddress.Hex(), req.List, req.Operator, req.Reason)
+		return &pb.SetTokenListEntryResponse{}, nil
+	})
+}
+
+// DeleteTokenListEntry removes a token from its list in every instance, logging the change in the audit log.
+// Bridge rest API admin endpoint
+func (s *bridgeService) DeleteTokenListEntry(ctx context.Context, req *pb.DeleteTokenListEntryRequest) (*pb.DeleteTokenListEntryResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkConfigApproval(ctx, "DeleteTokenListEntry"); err != nil {
+		return nil, err
+	}
+	if err := checkTokenListChange(req.OrigAddr, req.Reason, req.Operator); err != nil {
+		return nil, err
+	}
+	return idempotent(ctx, s, "DeleteTokenListEntry", req, func() (*pb.DeleteTokenListEntryResponse, error) {
+		change := &etherman.TokenListChange{
+			OriginalNetwork: uint(req.OrigNet),
+			OriginalAddress: common.HexToAddress(req.OrigAddr),
+			Reason:          req.Reason,
+			Operator:        req.Operator,
+			CreatedAt:       time.Now(),
+		}
+		if err := s.storage.DeleteTokenListEntry(ctx, change, nil); err != nil {
+			return nil, err
+		}
+		s.tokenLists.invalidate()
+		log.Warnf("token %d:%s removed from its list by %s, reason: %s", req.OrigNet, change.OriginalAddress.Hex(), req.Operator, req.Reason)
+		return &pb.DeleteTokenListEntryResponse{}, nil
+	})
+}
+
+// GetTokenListAudit returns the changes of the token lists, the last first. Bridge rest API admin endpoint
+func (s *bridgeService) GetTokenListAudit(ctx context.Context, req *pb.GetTokenListAuditRequest) (*pb.GetTokenListAuditResponse, error) {
+	if err := s.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	limit, err := s.pageLimit(ctx, req.Limit, uint64(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.storage.GetTokenListChanges(ctx, uint(limit), uint(req.Offset), nil)
+	if err != nil {
+		return nil, err
+	}
+	pbChanges := make([]*pb.TokenListChange, 0, len(changes))
+	for _, c := range changes {
+		pbChanges = append(pbChanges, &pb.TokenListChange{
+			Id:        c.ID,
+			Action:    c.Action,
+			OrigNet:   uint32(c.OriginalNetwork),
+			OrigAddr:  c.OriginalAddress.Hex(),
+			List:      c.List,
+			Reason:    c.Reason,
+			Operator:  c.Operator,
+			CreatedAt: unixTime(c.CreatedAt),
+		})
+	}
+	return &pb.GetTokenListAuditResponse{Changes: pbChanges}, nil
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTokenLists(t *testing.T) {
+	bench, _ := newBenchStorage(4, 0)
+	storage := newMemStorage(bench)
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, AdminToken: "admin",
+		TokenList: TokenListConfig{RefreshInterval: types.NewDuration(time.Minute)}}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	other := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	now := time.Now()
+	other.tokenLists.now = func() time.Time { return now }
+	admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, "admin"))
+	public := context.Background()
+	dai := "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+	addr := "0xc949254d682d8c9ad5682521675b8f43b102aec4"
+
+	// The token lists are admin endpoints
+	_, err := s.GetTokenList(public, &pb.GetTokenListRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.SetTokenListEntry(public, &pb.SetTokenListEntryRequest{OrigAddr: dai, List: "deny", Operator: "ops"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// The list, the token and the operator are checked
+	for _, req := range []*pb.SetTokenListEntryRequest{
+		{OrigAddr: dai, List: "block", Operator: "ops"},
+		{OrigAddr: "0x1234", List: "deny", Operator: "ops"},
+		{OrigAddr: dai, List: "deny"},
+	} {
+		_, err = s.SetTokenListEntry(admin, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	// The other instance loads the empty lists before the change
+	bridges, err := other.GetBridges(public, &pb.GetBridgesRequest{DestAddr: addr})
+	require.NoError(t, err)
+	for _, deposit := range bridges.Deposits {
+		require.Empty(t, deposit.TokenList)
+	}
+
+	_, err = s.SetTokenListEntry(admin, &pb.SetTokenListEntryRequest{OrigAddr: dai, List: "deny", Reason: "exploit", Operator: "ops"})
+	require.NoError(t, err)
+	_, err = s.SetTokenListEntry(admin, &pb.SetTokenListEntryRequest{OrigAddr: "0x0000000000000000000000000000000000000000", List: "allow", Operator: "ops"})
+	require.NoError(t, err)
+	list, err := s.GetTokenList(admin, &pb.GetTokenListRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Entries, 2)
+	require.Equal(t, "deny", list.Entries[0].List)
+	require.Equal(t, "exploit", list.Entries[0].Reason)
+
+	// The deposits show the list of their token, the even deposits of the bench storage are of DAI
+	bridges, err = s.GetBridges(public, &pb.GetBridgesRequest{DestAddr: addr})
+	require.NoError(t, err)
+	require.Len(t, bridges.Deposits, 4)
+	for _, deposit := range bridges.Deposits {
+		if deposit.DepositCnt%2 == 0 {
+			require.Equal(t, "deny", deposit.TokenList)
+		} else {
+			require.Equal(t, "allow", deposit.TokenList)
+		}
+	}
+
+	// The other instance applies the change after the refresh interval
+	bridges, err = other.GetBridges(public, &pb.GetBridgesRequest{DestAddr: addr})
+	require.NoError(t, err)
+	require.Empty(t, bridges.Deposits[0].TokenList)
+	now = now.Add(time.Minute)
+	bridges, err = other.GetBridges(public, &pb.GetBridgesRequest{DestAddr: addr})
+	require.NoError(t, err)
+	require.NotEmpty(t, bridges.Deposits[0].TokenList)
+
+	// The removals need a listed token, and are logged with the list the token was in
+	_, err = s.DeleteTokenListEntry(admin, &pb.DeleteTokenListEntryRequest{OrigNet: 1, OrigAddr: dai, Operator: "ops"})
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+	_, err = s.DeleteTokenListEntry(admin, &pb.DeleteTokenListEntryRequest{OrigAddr: dai, Reason: "fixed", Operator: "ops"})
+	require.NoError(t, err)
+	audit, err := s.GetTokenListAudit(admin, &pb.GetTokenListAuditRequest{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, audit.Changes, 2)
+	require.Equal(t, "delete", audit.Changes[0].Action)
+	require.Equal(t, "deny", audit.Changes[0].List)
+	require.Equal(t, "fixed", audit.Changes[0].Reason)
+	require.Equal(t, "set", audit.Changes[1].Action)
+	require.Equal(t, "allow", audit.Changes[1].List)
+	bridge, err := s.GetBridge(public, &pb.GetBridgeRequest{NetId: 0, DepositCnt: 0})
+	require.NoError(t, err)
+	require.Empty(t, bridge.Deposit.TokenList)
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+)
+
+// nodeSourceTopLevels is the source of the nodes read from the top levels kept in memory
+const nodeSourceTopLevels = "top_levels"
+
+// topLevelNode is a node of the top levels of an exit tree with its children.
+type topLevelNode struct {
+	key, left, right [bridgectrl.KeyLen]byte
+}
+
+// topLevelCache keeps the nodes of the top levels of the exit trees in memory. They are read by every proof and
+// only the path of the new deposits changes, so the proofs only read the bottom levels from the storage. The nodes
+// are added to the current generation, and when it's full it becomes the previous one, dropping the nodes of the
+// oldest roots. The methods of a nil cache do nothing, so the proofs are built in the same way without it.
+type topLevelCache struct {
+	levels         uint8
+	generationSize int
+
+	mu                sync.RWMutex
+	current, previous map[[bridgectrl.KeyLen]byte][2][bridgectrl.KeyLen]byte
+}
+
+// newTopLevelCache returns a cache of the levels top levels of the trees of the height, with at most size nodes.
+// It returns nil if no levels are kept.
+func newTopLevelCache(height, levels uint8, size int) *topLevelCache {
+	if levels == 0 || size <= 0 {
+		return nil
+	}
+	if levels > height {
+		levels = height
+	}
+	return &topLevelCache{
+		levels:         levels,
+		generationSize: (size + 1) / 2, //nolint:gomnd
+		current:        make(map[[bridgectrl.KeyLen]byte][2][bridgectrl.KeyLen]byte),
+	}
+}
+
+// isTopLevel returns if the nodes of the level h of a tree of the height are kept in memory.
+func (c *topLevelCache) isTopLevel(height uint8, h int) bool {
+	return c != nil && h >= int(height)-int(c.levels)
+}
+
+// get returns the children of a node of the top levels, false if it's not kept.
+func (c *topLevelCache) get(key [bridgectrl.KeyLen]byte) (left, right [bridgectrl.KeyLen]byte, found bool) {
+	if c == nil {
+		return left, right, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	children, found := c.current[key]
+	if !found {
+		children, found = c.previous[key]
+	}
+	return children[0], children[1], found
+}
+
+// add keeps the nodes read from the storage, all of them in the same generation.
+func (c *topLevelCache) add(nodes []topLevelNode) {
+	if c == nil || len(nodes) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.current)+len(nodes) > c.generationSize {
+		c.previous = c.current
+		c.current = make(map[[bridgectrl.KeyLen]byte][2][bridgectrl.KeyLen]byte, len(nodes))
+	}
+	for _, n := range nodes {
+		c.current[n.key] = [2][bridgectrl.KeyLen]byte{n.left, n.right}
+	}
+}
+
+// len returns the number of nodes kept.
+func (c *topLevelCache) len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.current) + len(c.previous)
+}
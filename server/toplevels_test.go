@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// countingStorage counts the nodes read from the storage.
+type countingStorage struct {
+	*benchStorage
+	reads int
+}
+
+func (s *countingStorage) Get(ctx context.Context, key []byte, dbTx pgx.Tx) ([][]byte, error) {
+	s.reads++
+	return s.benchStorage.Get(ctx, key, dbTx)
+}
+
+func TestTopLevelCache(t *testing.T) {
+	const index = 5
+	bench, root := newBenchStorage(10, index)
+	storage := &countingStorage{benchStorage: bench}
+	cfg := Config{CacheSize: 1, DefaultPageLimit: 25, MaxPageLimit: 100, TopLevels: 20, TopLevelsSize: 100}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+
+	proof, err := s.getProof(index, root, nil)
+	require.NoError(t, err)
+	require.Equal(t, benchHeight, storage.reads)
+	require.Equal(t, 20, s.topLevels.len())
+
+	// The second proof only reads the bottom levels
+	storage.reads = 0
+	trace := newProofTrace()
+	cached, err := s.traceProof(index, root, trace, nil)
+	require.NoError(t, err)
+	require.Equal(t, proof, cached)
+	require.LessOrEqual(t, storage.reads, benchHeight-20)
+	require.Equal(t, nodeSourceTopLevels, trace.nodes[0].Source)
+	require.Equal(t, nodeSourceTopLevels, trace.nodes[19].Source)
+	require.NotEqual(t, nodeSourceTopLevels, trace.nodes[20].Source)
+
+	// The levels are limited by the height, and no levels disable the cache
+	require.Equal(t, uint8(benchHeight), newTopLevelCache(benchHeight, 64, 100).levels)
+	require.Nil(t, newTopLevelCache(benchHeight, 0, 100))
+	require.False(t, newTopLevelCache(benchHeight, 0, 100).isTopLevel(benchHeight, benchHeight-1))
+}
+
+func TestTopLevelCacheGenerations(t *testing.T) {
+	node := func(b byte) topLevelNode {
+		return topLevelNode{key: [bridgectrl.KeyLen]byte{b}, left: [bridgectrl.KeyLen]byte{b, 1}, right: [bridgectrl.KeyLen]byte{b, 2}}
+	}
+	c := newTopLevelCache(benchHeight, 4, 4)
+	c.add([]topLevelNode{node(1), node(2)})
+
+	// A full generation becomes the previous one
+	c.add([]topLevelNode{node(3)})
+	require.Len(t, c.previous, 2)
+	require.Len(t, c.current, 1)
+	require.Equal(t, 3, c.len())
+	left, right, found := c.get(node(1).key)
+	require.True(t, found)
+	require.Equal(t, node(1).left, left)
+	require.Equal(t, node(1).right, right)
+
+	// The nodes of the oldest generation are dropped
+	c.add([]topLevelNode{node(4), node(5)})
+	_, _, found = c.get(node(1).key)
+	require.False(t, found)
+	_, _, found = c.get(node(3).key)
+	require.True(t, found)
+	require.Equal(t, 3, c.len())
+}
+
+func TestTopLevelCacheConcurrent(t *testing.T) {
+	c := newTopLevelCache(benchHeight, 4, 64)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(b byte) {
+			defer wg.Done()
+			for j := byte(0); j < 32; j++ {
+				key := [bridgectrl.KeyLen]byte{b, j}
+				c.add([]topLevelNode{{key: key}})
+				c.get(key)
+			}
+		}(byte(i))
+	}
+	wg.Wait()
+	require.LessOrEqual(t, c.len(), 64)
+}
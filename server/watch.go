@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Validate checks the limits and the intervals of the watched addresses.
+func (c WatchConfig) Validate() error {
+	if c.MaxAddresses < 0 {
+		return errors.New("the max number of watched addresses is negative")
+	}
+	if c.WarmInterval.Duration < 0 || c.BatchPollInterval.Duration < 0 || c.BackfillInterval.Duration < 0 {
+		return errors.New("the intervals of the watched addresses are negative")
+	}
+	if c.BackfillInterval.Duration > 0 && c.BackfillBatch == 0 {
+		return errors.New("the backfills of the watched addresses require a BackfillBatch")
+	}
+	return nil
+}
+
+// GetWatchedAddresses returns the addresses watched by the wallet of the session.
+// Bridge rest API session endpoint
+func (s *bridgeService) GetWatchedAddresses(ctx context.Context, req *pb.GetWatchedAddressesRequest) (*pb.GetWatchedAddressesResponse, error) {
+	owner, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	watches, err := s.storage.GetWatchedAddresses(ctx, owner, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.GetWatchedAddressesResponse{}
+	for i := range watches {
+		res.Addresses = append(res.Addresses, watchedAddressToPb(&watches[i]))
+	}
+	return res, nil
+}
+
+// AddWatchedAddress watches an address for the wallet of the session, keeping the first pages of its bridges and
+// claims hot in the response cache. Watching an address already watched returns it.
+// Bridge rest API session endpoint
+func (s *bridgeService) AddWatchedAddress(ctx context.Context, req *pb.AddWatchedAddressRequest) (*pb.AddWatchedAddressResponse, error) {
+	owner, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(req.Address) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", req.Address)
+	}
+	addr := common.HexToAddress(req.Address)
+	watches, err := s.storage.GetWatchedAddresses(ctx, owner, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range watches {
+		if watches[i].Address == addr {
+			return &pb.AddWatchedAddressResponse{Address: watchedAddressToPb(&watches[i])}, nil
+		}
+	}
+	if len(watches) >= s.watch.MaxAddresses {
+		return nil, status.Errorf(codes.ResourceExhausted, "the wallet already watches %d addresses", len(watches))
+	}
+	w := &etherman.WatchedAddress{
+		Owner:     owner,
+		Address:   addr,
+		CreatedAt: time.Now(),
+	}
+	if err := s.storage.AddWatchedAddress(ctx, w, nil); err != nil {
+		return nil, err
+	}
+	log.Infof("address %s watched by the wallet %s", addr.Hex(), owner.Hex())
+	return &pb.AddWatchedAddressResponse{
+		Address: watchedAddressToPb(w),
+	}, nil
+}
+
+// DeleteWatchedAddress stops watching an address for the wallet of the session.
+// Bridge rest API session endpoint
+func (s *bridgeService) DeleteWatchedAddress(ctx context.Context, req *pb.DeleteWatchedAddressRequest) (*pb.DeleteWatchedAddressResponse, error) {
+	owner, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(req.Address) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", req.Address)
+	}
+	addr := common.HexToAddress(req.Address)
+	if err := s.storage.DeleteWatchedAddress(ctx, owner, addr, nil); err != nil {
+		return nil, err
+	}
+	log.Infof("address %s no longer watched by the wallet %s", addr.Hex(), owner.Hex())
+	return &pb.DeleteWatchedAddressResponse{}, nil
+}
+
+// BackfillWatchedAddress requests the backfill of the history of an address watched by the wallet of the session.
+// The backfill is run by the instance syncing the networks, ahead of the backfill of every deposit.
+// Bridge rest API session endpoint
+func (s *bridgeService) BackfillWatchedAddress(ctx context.Context, req *pb.BackfillWatchedAddressRequest) (*pb.BackfillWatchedAddressResponse, error) {
+	owner, err := s.checkSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+	if s.watch.BackfillInterval.Duration <= 0 {
+		return nil, status.Error(codes.Unimplemented, "the backfills of the watched addresses are disabled")
+	}
+	if !common.IsHexAddress(req.Address) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", req.Address)
+	}
+	addr := common.HexToAddress(req.Address)
+	if err := s.storage.RequestWatchBackfill(ctx, owner, addr, time.Now(), nil); err != nil {
+		return nil, err
+	}
+	watches, err := s.storage.GetWatchedAddresses(ctx, owner, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.BackfillWatchedAddressResponse{}
+	for i := range watches {
+		if watches[i].Address == addr {
+			res.Address = watchedAddressToPb(&watches[i])
+		}
+	}
+	log.Infof("backfill of the address %s requested by the wallet %s", addr.Hex(), owner.Hex())
+	return res, nil
+}
+
+// StartWatchWarmer loads the first pages of the bridges and the claims of the watched addresses in the response cache
+// every WarmInterval until the context is done. The pages already cached are not read again, so each warm-up only
+// reads the pages invalidated by the syncs since the previous one.
+func (s *bridgeService) StartWatchWarmer(ctx context.Context) {
+	ticker := time.NewTicker(s.watch.WarmInterval.Duration)
+	defer ticker.Stop()
+	for {
+		if err := s.warmWatchedAddresses(ctx); err != nil {
+			log.Warnf("error warming up the watched addresses: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *bridgeService) warmWatchedAddresses(ctx context.Context) error {
+	if s.responses == nil {
+		return nil
+	}
+	addresses, err := s.storage.GetAllWatchedAddresses(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addresses {
+		if _, err := s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: addr.Hex()}); err != nil {
+			return err
+		}
+		if _, err := s.GetClaims(ctx, &pb.GetClaimsRequest{DestAddr: addr.Hex()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func watchedAddressToPb(w *etherman.WatchedAddress) *pb.WatchedAddress {
+	res := &pb.WatchedAddress{
+		Address:   w.Address.Hex(),
+		CreatedAt: uint64(w.CreatedAt.Unix()),
+	}
+	if w.BackfillRequestedAt != nil {
+		res.BackfillRequestedAt = uint64(w.BackfillRequestedAt.Unix())
+	}
+	if w.BackfilledAt != nil {
+		res.BackfilledAt = uint64(w.BackfilledAt.Unix())
+	}
+	return res
+}
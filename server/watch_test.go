@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/cache"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWatchedAddresses(t *testing.T) {
+	ctx := context.Background()
+	key := common.FromHex("0x4eba8ff6000ded1e4f8a4e9bba0b3eb3f1e9a1d9156a16e45bb4a3c2a8b00f9b")
+	privateKey, err := crypto.ToECDSA(key)
+	require.NoError(t, err)
+	wallet := crypto.PubkeyToAddress(privateKey.PublicKey)
+	bench, _ := newBenchStorage(4, 0)
+	storage := newMemStorage(bench)
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100,
+		Session: SessionConfig{Secret: "secret", ChainID: 1, ChallengeTTL: types.NewDuration(time.Minute), SessionTTL: types.NewDuration(time.Hour)},
+		Watch:   WatchConfig{MaxAddresses: 1, WarmInterval: types.NewDuration(time.Minute)},
+	}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	challenge, err := s.GetSessionChallenge(ctx, &pb.GetSessionChallengeRequest{Address: wallet.Hex()})
+	require.NoError(t, err)
+	session, err := s.CreateSession(ctx, &pb.CreateSessionRequest{Address: wallet.Hex(), Nonce: challenge.Nonce, ExpiresAt: challenge.ExpiresAt,
+		Signature: signChallenge(t, challenge.TypedData, key)})
+	require.NoError(t, err)
+	sessionCtx := withSession(ctx, session.Token)
+
+	// The wallet watches up to MaxAddresses addresses, watching one again returns it
+	watched := bench.deposits[0].DestinationAddress
+	_, err = s.AddWatchedAddress(ctx, &pb.AddWatchedAddressRequest{Address: watched.Hex()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.AddWatchedAddress(sessionCtx, &pb.AddWatchedAddressRequest{Address: "0x1"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	added, err := s.AddWatchedAddress(sessionCtx, &pb.AddWatchedAddressRequest{Address: watched.Hex()})
+	require.NoError(t, err)
+	require.Equal(t, watched.Hex(), added.Address.Address)
+	_, err = s.AddWatchedAddress(sessionCtx, &pb.AddWatchedAddressRequest{Address: watched.Hex()})
+	require.NoError(t, err)
+	_, err = s.AddWatchedAddress(sessionCtx, &pb.AddWatchedAddressRequest{Address: common.HexToAddress("0x2").Hex()})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	watches, err := s.GetWatchedAddresses(sessionCtx, &pb.GetWatchedAddressesRequest{})
+	require.NoError(t, err)
+	require.Len(t, watches.Addresses, 1)
+
+	// The backfills are disabled without a BackfillInterval
+	_, err = s.BackfillWatchedAddress(sessionCtx, &pb.BackfillWatchedAddressRequest{Address: watched.Hex()})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+	s.watch.BackfillInterval = types.NewDuration(time.Minute)
+	backfill, err := s.BackfillWatchedAddress(sessionCtx, &pb.BackfillWatchedAddressRequest{Address: watched.Hex()})
+	require.NoError(t, err)
+	require.NotZero(t, backfill.Address.BackfillRequestedAt)
+	require.Zero(t, backfill.Address.BackfilledAt)
+	_, err = s.BackfillWatchedAddress(sessionCtx, &pb.BackfillWatchedAddressRequest{Address: common.HexToAddress("0x2").Hex()})
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+
+	// The warm-up loads the bridges of the watched addresses in the cache, once until they are invalidated
+	require.NoError(t, s.warmWatchedAddresses(ctx))
+	require.Zero(t, storage.bridgeReads)
+	responses, err := cache.New(cache.Config{Enabled: true, LocalSize: 100, TTL: types.NewDuration(time.Minute)})
+	require.NoError(t, err)
+	s.SetCache(responses)
+	require.NoError(t, s.warmWatchedAddresses(ctx))
+	require.Equal(t, 1, storage.bridgeReads)
+	require.NoError(t, s.warmWatchedAddresses(ctx))
+	_, err = s.GetBridges(ctx, &pb.GetBridgesRequest{DestAddr: watched.Hex()})
+	require.NoError(t, err)
+	require.Equal(t, 1, storage.bridgeReads)
+	require.NoError(t, responses.Invalidate(ctx, cache.AccountTag(watched)))
+	require.NoError(t, s.warmWatchedAddresses(ctx))
+	require.Equal(t, 2, storage.bridgeReads)
+
+	// The batches of the deposits to the watched addresses are polled faster
+	s.batchPoll = 10 * time.Second
+	require.Equal(t, 10*time.Second, s.batchPollInterval(ctx, bench.deposits[0]))
+	s.watch.BatchPollInterval = types.NewDuration(time.Second)
+	require.Equal(t, time.Second, s.batchPollInterval(ctx, bench.deposits[0]))
+	require.Equal(t, 10*time.Second, s.batchPollInterval(ctx, &etherman.Deposit{DestinationAddress: common.HexToAddress("0x2")}))
+
+	_, err = s.DeleteWatchedAddress(sessionCtx, &pb.DeleteWatchedAddressRequest{Address: watched.Hex()})
+	require.NoError(t, err)
+	_, err = s.DeleteWatchedAddress(sessionCtx, &pb.DeleteWatchedAddressRequest{Address: watched.Hex()})
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+
+	require.NoError(t, cfg.Watch.Validate())
+	require.Error(t, WatchConfig{BackfillInterval: types.NewDuration(time.Minute)}.Validate())
+	require.Error(t, WatchConfig{MaxAddresses: -1}.Validate())
+	require.Error(t, WatchConfig{BatchPollInterval: types.NewDuration(-time.Second)}.Validate())
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/proofformat"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetClaimWitness returns the inputs of the claim of a deposit: the leaf, its indexes, the proofs in the local and
+// the rollup exit trees, the exit roots and the index of the global exit root in the L1 info tree, with the call
+// of the claim interface of the version. With a format the inputs are also encoded for the external tooling.
+// Bridge rest API endpoint
+func (s *bridgeService) GetClaimWitness(ctx context.Context, req *pb.GetClaimWitnessRequest) (*pb.GetClaimWitnessResponse, error) {
+	if err := s.checkProofConsumer(ctx); err != nil {
+		return nil, err
+	}
+	version := req.Version
+	if version == "" {
+		version = s.claimVersion
+	}
+	if !etherman.IsClaimVersion(version) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid version %s, it must be %s or %s", version, etherman.ClaimVersionV1, etherman.ClaimVersionV2)
+	}
+	if req.Format != "" && !proofformat.IsFormat(req.Format) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid format %s, it must be %s, %s, %s or %s", req.Format,
+			proofformat.FormatHex, proofformat.FormatJSON, proofformat.FormatTuple, proofformat.FormatCast)
+	}
+	deposit, err := s.storage.GetDeposit(ctx, uint(req.DepositCnt), uint(req.NetId), nil)
+	if err != nil {
+		return nil, err
+	}
+	globalExitRoot, merkleProof, rollupMerkleProof, localExitRoot, err := s.getClaimProofs(deposit.DepositCount, deposit.NetworkID, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The v1 claims only verify the local exit root against the rollup exit root of a single rollup, and the v2
+	// claims of the rollup deposits verify it in the rollup exit tree
+	if version == etherman.ClaimVersionV1 && len(rollupMerkleProof) > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "the rollup exit root %s is a rollup exit tree, the deposit is claimed with %s", globalExitRoot.ExitRoots[1].String(), etherman.ClaimVersionV2)
+	}
+	if version == etherman.ClaimVersionV2 && deposit.NetworkID != 0 && len(rollupMerkleProof) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "the rollup exit root %s is not a rollup exit tree, the deposit is claimed with %s", globalExitRoot.ExitRoots[1].String(), etherman.ClaimVersionV1)
+	}
+	var smtProof, rollupProof [bridgectrl.KeyLen][bridgectrl.KeyLen]byte
+	if len(merkleProof) != len(smtProof) || (len(rollupMerkleProof) != 0 && len(rollupMerkleProof) != len(rollupProof)) {
+		return nil, fmt.Errorf("invalid merkle proof length: %d, rollup merkle proof length: %d", len(merkleProof), len(rollupMerkleProof))
+	}
+	copy(smtProof[:], merkleProof)
+	copy(rollupProof[:], rollupMerkleProof)
+	calldata, err := etherman.PackClaim(version, deposit, smtProof, rollupProof, globalExitRoot)
+	if err != nil {
+		return nil, err
+	}
+	ger := common.Hash(bridgectrl.Hash(globalExitRoot.ExitRoots[0], globalExitRoot.ExitRoots[1]))
+	// The leaf is empty if the global exit root is not in the L1 info tree
+	l1InfoLeaf, err := s.storage.GetL1InfoTreeLeaf(ctx, ger, nil)
+	if err != nil && !errors.Is(err, gerror.ErrStorageNotFound) {
+		return nil, err
+	}
+	witness := &pb.ClaimWitness{
+		Version:            version,
+		LeafType:           uint32(deposit.LeafType),
+		OrigNet:            uint32(deposit.OriginalNetwork),
+		OrigAddr:           deposit.OriginalAddress.Hex(),
+		Amount:             deposit.Amount.String(),
+		DestNet:            uint32(deposit.DestinationNetwork),
+		DestAddr:           deposit.DestinationAddress.Hex(),
+		Metadata:           hexutil.Encode(deposit.Metadata),
+		Leaf:               common.Hash(bridgectrl.HashDeposit(deposit)).Hex(),
+		Index:              uint64(deposit.DepositCount),
+		LocalExitRoot:      localExitRoot.Hex(),
+		LocalExitRootProof: hexProof(smtProof[:]),
+		MainnetExitRoot:    globalExitRoot.ExitRoots[0].Hex(),
+		RollupExitRoot:     globalExitRoot.ExitRoots[1].Hex(),
+		GlobalExitRoot:     ger.Hex(),
+		Calldata:           hexutil.Encode(calldata),
+	}
+	if version == etherman.ClaimVersionV2 {
+		witness.GlobalIndex = etherman.GlobalIndex(deposit.NetworkID, deposit.DepositCount).String()
+		witness.RollupExitRootProof = hexProof(rollupProof[:])
+	}
+	if l1InfoLeaf != nil {
+		witness.L1InfoTreeLeaf = l1InfoTreeLeafToPb(*l1InfoLeaf)
+	}
+	res := &pb.GetClaimWitnessResponse{Witness: witness}
+	if req.Format != "" {
+		if res.Encoded, err = proofformat.Encode(req.Format, witness); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func l1InfoTreeLeafToPb(leaf etherman.L1InfoTreeLeaf) *pb.L1InfoTreeLeaf {
+	return &pb.L1InfoTreeLeaf{
+		Index:          uint64(leaf.Index),
+		Leaf:           leaf.Leaf.String(),
+		GlobalExitRoot: leaf.GlobalExitRoot.String(),
+		ParentHash:     leaf.ParentHash.String(),
+		Timestamp:      uint64(leaf.Timestamp.Unix()),
+		BlockNum:       leaf.BlockNumber,
+	}
+}
+
+func hexProof(proof [][bridgectrl.KeyLen]byte) []string {
+	res := make([]string, 0, len(proof))
+	for _, sibling := range proof {
+		res = append(res, "0x"+hex.EncodeToString(sibling[:]))
+	}
+	return res
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/proofformat"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// witnessStorage is the rollup storage with the global exit roots of the L1 info tree.
+type witnessStorage struct {
+	*rollupStorage
+	l1InfoLeaves []etherman.L1InfoTreeLeaf
+}
+
+func (s *witnessStorage) GetL1InfoTreeLeaf(ctx context.Context, ger common.Hash, dbTx pgx.Tx) (*etherman.L1InfoTreeLeaf, error) {
+	for i := range s.l1InfoLeaves {
+		if s.l1InfoLeaves[i].GlobalExitRoot == ger {
+			return &s.l1InfoLeaves[i], nil
+		}
+	}
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (s *witnessStorage) GetLatestL1InfoTreeLeaf(ctx context.Context, dbTx pgx.Tx) (*etherman.L1InfoTreeLeaf, error) {
+	if len(s.l1InfoLeaves) == 0 {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &s.l1InfoLeaves[len(s.l1InfoLeaves)-1], nil
+}
+
+// l1InfoTreeProver calculates the proofs of the first leaves of the tree of the synchronizer.
+type l1InfoTreeProver struct {
+	leaves [][bridgectrl.KeyLen]byte
+}
+
+func (p *l1InfoTreeProver) GetL1InfoTreeProof(index, count uint) (*bridgectrl.L1InfoTreeProof, error) {
+	siblings, err := bridgectrl.ComputeSiblings(index, p.leaves[:count], benchHeight)
+	if err != nil {
+		return nil, err
+	}
+	return &bridgectrl.L1InfoTreeProof{
+		Leaf:     p.leaves[index],
+		Siblings: siblings,
+		Root:     bridgectrl.ComputeRoot(p.leaves[:count], benchHeight),
+	}, nil
+}
+
+func TestGetClaimWitness(t *testing.T) {
+	const index = 5
+	ctx := context.Background()
+	bench, localExitRoot := newBenchStorage(10, index)
+	storage := &witnessStorage{rollupStorage: &rollupStorage{benchStorage: bench}}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100, ClaimVersion: etherman.ClaimVersionV1}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1, 2}, storage, nil)
+	deposit := bench.deposits[index]
+	leaf := common.Hash(bridgectrl.HashDeposit(deposit)).Hex()
+
+	// A deposit of a single rollup, whose rollup exit root is its local exit root
+	deposit.NetworkID = 1
+	storage.ger = &etherman.GlobalExitRoot{ExitRoots: []common.Hash{common.HexToHash("0x02"), localExitRoot}}
+	ger := common.Hash(bridgectrl.Hash(storage.ger.ExitRoots[0], storage.ger.ExitRoots[1]))
+	storage.l1InfoLeaves = []etherman.L1InfoTreeLeaf{{Index: 0, GlobalExitRoot: common.HexToHash("0x03")}, {Index: 1, GlobalExitRoot: ger}}
+	res, err := s.GetClaimWitness(ctx, &pb.GetClaimWitnessRequest{NetId: 1, DepositCnt: index})
+	require.NoError(t, err)
+	w := res.Witness
+	require.Equal(t, etherman.ClaimVersionV1, w.Version)
+	require.Equal(t, leaf, w.Leaf)
+	require.Equal(t, uint64(index), w.Index)
+	require.Empty(t, w.GlobalIndex)
+	require.Len(t, w.LocalExitRootProof, benchHeight)
+	require.Empty(t, w.RollupExitRootProof)
+	require.Equal(t, common.Hash(localExitRoot).Hex(), w.LocalExitRoot)
+	require.Equal(t, ger.Hex(), w.GlobalExitRoot)
+	require.Equal(t, uint64(1), w.L1InfoTreeLeaf.Index)
+	require.Equal(t, deposit.Amount.String(), w.Amount)
+	// claimAsset of PolygonZkEVMBridge
+	require.Equal(t, "0x2cffd02e", w.Calldata[:10])
+	_, err = s.GetClaimWitness(ctx, &pb.GetClaimWitnessRequest{NetId: 1, DepositCnt: index, Version: etherman.ClaimVersionV2})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// A deposit of a rollup of the rollup exit tree
+	deposit.NetworkID = 2
+	leaves := [][bridgectrl.KeyLen]byte{common.HexToHash("0x01"), localExitRoot}
+	rollupExitRoot := common.Hash(bridgectrl.ComputeRoot(leaves, benchHeight))
+	storage.leaves = []etherman.RollupExitLeaf{
+		{RollupID: 1, Leaf: leaves[0], Root: common.Hash(bridgectrl.ComputeRoot(leaves[:1], benchHeight))},
+		{RollupID: 2, Leaf: localExitRoot, Root: rollupExitRoot},
+	}
+	storage.ger = &etherman.GlobalExitRoot{ExitRoots: []common.Hash{common.HexToHash("0x02"), rollupExitRoot}}
+	res, err = s.GetClaimWitness(ctx, &pb.GetClaimWitnessRequest{NetId: 2, DepositCnt: index, Version: etherman.ClaimVersionV2})
+	require.NoError(t, err)
+	w = res.Witness
+	require.Equal(t, new(big.Int).SetUint64(1<<32|index).String(), w.GlobalIndex)
+	require.Len(t, w.RollupExitRootProof, benchHeight)
+	var rollupProof [][bridgectrl.KeyLen]byte
+	for _, sibling := range w.RollupExitRootProof {
+		rollupProof = append(rollupProof, common.HexToHash(sibling))
+	}
+	require.True(t, bridgectrl.CheckProof(localExitRoot, 1, rollupProof, rollupExitRoot))
+	require.Nil(t, w.L1InfoTreeLeaf)
+	// claimAsset of PolygonZkEVMBridgeV2
+	require.Equal(t, "0xccaa2d11", w.Calldata[:10])
+	_, err = s.GetClaimWitness(ctx, &pb.GetClaimWitnessRequest{NetId: 2, DepositCnt: index})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// A L1 deposit, whose rollup proof is empty
+	deposit.NetworkID = 0
+	storage.ger = &etherman.GlobalExitRoot{ExitRoots: []common.Hash{localExitRoot, rollupExitRoot}}
+	res, err = s.GetClaimWitness(ctx, &pb.GetClaimWitnessRequest{NetId: 0, DepositCnt: index, Version: etherman.ClaimVersionV2})
+	require.NoError(t, err)
+	w = res.Witness
+	require.Equal(t, new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(index)).String(), w.GlobalIndex)
+	require.Len(t, w.RollupExitRootProof, benchHeight)
+	require.Equal(t, common.Hash{}.Hex(), w.RollupExitRootProof[0])
+
+	_, err = s.GetClaimWitness(ctx, &pb.GetClaimWitnessRequest{NetId: 0, DepositCnt: index, Version: "v3"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The inputs are encoded with a format
+	require.Empty(t, res.Encoded)
+	res, err = s.GetClaimWitness(ctx, &pb.GetClaimWitnessRequest{NetId: 0, DepositCnt: index, Version: etherman.ClaimVersionV2, Format: proofformat.FormatTuple})
+	require.NoError(t, err)
+	encoded, err := proofformat.Encode(proofformat.FormatTuple, res.Witness)
+	require.NoError(t, err)
+	require.Equal(t, encoded, res.Encoded)
+	_, err = s.GetClaimWitness(ctx, &pb.GetClaimWitnessRequest{NetId: 0, DepositCnt: index, Version: etherman.ClaimVersionV2, Format: "bytes"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetL1InfoTreeProof(t *testing.T) {
+	ctx := context.Background()
+	storage := &witnessStorage{rollupStorage: &rollupStorage{benchStorage: &benchStorage{}}}
+	cfg := Config{CacheSize: 100, DefaultPageLimit: 25, MaxPageLimit: 100}
+	s := NewBridgeService(cfg, benchHeight, []uint{0, 1}, storage, nil)
+	prover := &l1InfoTreeProver{}
+	for i := 0; i < 3; i++ {
+		leaf := etherman.L1InfoTreeLeaf{Index: uint(i), GlobalExitRoot: common.BigToHash(big.NewInt(int64(i + 1)))}
+		leaf.Leaf = common.Hash(bridgectrl.HashL1InfoLeaf(leaf.GlobalExitRoot, leaf.ParentHash, 0))
+		prover.leaves = append(prover.leaves, leaf.Leaf)
+		leaf.Root = common.Hash(bridgectrl.ComputeRoot(prover.leaves, benchHeight))
+		storage.l1InfoLeaves = append(storage.l1InfoLeaves, leaf)
+	}
+	req := &pb.GetL1InfoTreeProofRequest{GlobalExitRoot: storage.l1InfoLeaves[1].GlobalExitRoot.String()}
+
+	// The proofs need the tree of the synchronizer
+	_, err := s.GetL1InfoTreeProof(ctx, req)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	// The proof is of the leaf in the tree of the stored leaves, without the ones of the synchronizer not
+	// committed yet
+	s.SetL1InfoTree(prover)
+	prover.leaves = append(prover.leaves, common.HexToHash("0x0a"))
+	res, err := s.GetL1InfoTreeProof(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.Leaf.Index)
+	require.Equal(t, storage.l1InfoLeaves[2].Root.String(), res.Root)
+	var proof [][bridgectrl.KeyLen]byte
+	for _, sibling := range res.Proof {
+		proof = append(proof, common.HexToHash(sibling))
+	}
+	require.True(t, bridgectrl.CheckProof(storage.l1InfoLeaves[1].Leaf, 1, proof, storage.l1InfoLeaves[2].Root))
+
+	// The tree reloaded by the synchronizer doesn't match the stored leaves
+	prover.leaves[1] = common.HexToHash("0x0b")
+	_, err = s.GetL1InfoTreeProof(ctx, req)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = s.GetL1InfoTreeProof(ctx, &pb.GetL1InfoTreeProofRequest{GlobalExitRoot: common.HexToHash("0x0c").String()})
+	require.ErrorIs(t, err, gerror.ErrStorageNotFound)
+}
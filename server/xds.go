@@ -0,0 +1,23 @@
+//go:build xds
+// +build xds
+
+package server
+
+import (
+	"google.golang.org/grpc"
+	// The xds package also registers the xds resolver and balancers used by the xds:/// targets of the gateway
+	"google.golang.org/grpc/xds"
+)
+
+// xdsSupported is whether the binary is built with the xDS support.
+const xdsSupported = true
+
+// newXDSServer creates a gRPC server whose listeners, routes and security are configured by the xDS control plane
+// of the bootstrap file in GRPC_XDS_BOOTSTRAP.
+func newXDSServer(opts []grpc.ServerOption) (grpcServer, error) {
+	server, err := xds.NewGRPCServer(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return server, nil
+}
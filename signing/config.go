@@ -0,0 +1,29 @@
+package signing
+
+import (
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the signatures of the responses of the API
+type Config struct {
+	// Enabled signs the responses of the REST API
+	Enabled bool `mapstructure:"Enabled"`
+	// Keys are the keystores of the operator keys. The first one signs the responses until another one is activated
+	// with the rotation endpoint, the others are published so the consumers trust them before they are activated
+	Keys []types.KeystoreFileConfig `mapstructure:"Keys"`
+	// RefreshInterval is the time each instance caches the active key
+	RefreshInterval types.Duration `mapstructure:"RefreshInterval"`
+}
+
+// Validate checks the keys and the refresh interval.
+func (c Config) Validate() error {
+	if len(c.Keys) == 0 {
+		return errors.New("no keys to sign the responses")
+	}
+	if c.RefreshInterval.Duration <= 0 {
+		return errors.New("the refresh interval must be positive")
+	}
+	return nil
+}
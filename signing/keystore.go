@@ -0,0 +1,29 @@
+//go:build !readonly
+// +build !readonly
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// NewSigner creates a new signer of the responses with the keys of the keystores.
+func NewSigner(cfg Config, storage interface{}) (*Signer, error) {
+	keys := make([]*ecdsa.PrivateKey, 0, len(cfg.Keys))
+	for _, k := range cfg.Keys {
+		keystoreEncrypted, err := os.ReadFile(filepath.Clean(k.Path))
+		if err != nil {
+			return nil, err
+		}
+		key, err := keystore.DecryptKey(keystoreEncrypted, k.Password)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key.PrivateKey)
+	}
+	return newSigner(cfg, storage.(storageInterface), keys), nil
+}
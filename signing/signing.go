@@ -0,0 +1,316 @@
+// Package signing signs the responses of the API with the operator keys, so the consumers caching or relaying the
+// data of the bridge can prove it was served by the official service. The key signing the responses is rotated at
+// runtime through the admin API, and the keys are published with the times they signed the responses, so the
+// responses signed by a retired key are still verified.
+package signing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v4"
+)
+
+// Headers of the signature of a response
+const (
+	// DigestHeader is the SHA-256 of the body, as defined by RFC 3230: SHA-256=<base64>
+	DigestHeader = "Digest"
+	// SignerHeader is the address of the key signing the response
+	SignerHeader = "X-Bridge-Signer"
+	// SignedAtHeader is the unix time of the signature
+	SignedAtHeader = "X-Bridge-Signed-At"
+	// SignatureHeader is the hex EIP-191 personal signature of the Message of the response
+	SignatureHeader = "X-Bridge-Signature"
+)
+
+// Statuses of the keys
+const (
+	// KeyActive is the status of the key signing the responses
+	KeyActive = "active"
+	// KeyRetired is the status of a key that signed the responses until it was rotated
+	KeyRetired = "retired"
+	// KeyStandby is the status of a configured key that can be activated
+	KeyStandby = "standby"
+)
+
+var (
+	// ErrUnknownKey is used when the rotation activates a key that is not configured
+	ErrUnknownKey = errors.New("unknown signing key")
+	// ErrActiveKey is used when the rotation activates the active key
+	ErrActiveKey = errors.New("the key already signs the responses")
+)
+
+type storageInterface interface {
+	GetSigningKeys(ctx context.Context, dbTx pgx.Tx) ([]etherman.SigningKey, error)
+	RetireSigningKey(ctx context.Context, retiredAt time.Time, dbTx pgx.Tx) error
+	AddSigningKey(ctx context.Context, address common.Address, activatedAt time.Time, dbTx pgx.Tx) error
+	BeginDBTransaction(ctx context.Context) (pgx.Tx, error)
+	Commit(ctx context.Context, dbTx pgx.Tx) error
+	Rollback(ctx context.Context, dbTx pgx.Tx) error
+}
+
+// Signature is the signature of a response.
+type Signature struct {
+	Signer    common.Address
+	SignedAt  int64
+	Digest    common.Hash
+	Signature hexutil.Bytes
+}
+
+// SetHeader sets the headers of the signature.
+func (s *Signature) SetHeader(h http.Header) {
+	h.Set(DigestHeader, digestHeader(s.Digest))
+	h.Set(SignerHeader, s.Signer.Hex())
+	h.Set(SignedAtHeader, strconv.FormatInt(s.SignedAt, 10))
+	h.Set(SignatureHeader, s.Signature.String())
+}
+
+// Key is an operator key with the period it signed the responses. A key activated again after it was retired has
+// a Key for each period.
+type Key struct {
+	Signer common.Address
+	Status string
+	// ActivatedAt is nil for the standby keys
+	ActivatedAt *time.Time
+	// RetiredAt is nil for the active and the standby keys
+	RetiredAt *time.Time
+}
+
+// Signer signs the responses with the active key, the activations of the keys are stored in the database so every
+// instance signs with the same key.
+type Signer struct {
+	storage         storageInterface
+	keys            map[common.Address]*ecdsa.PrivateKey
+	order           []common.Address
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu       sync.Mutex
+	loadedAt time.Time
+	history  []etherman.SigningKey
+}
+
+func newSigner(cfg Config, storage storageInterface, keys []*ecdsa.PrivateKey) *Signer {
+	s := &Signer{
+		storage:         storage,
+		keys:            make(map[common.Address]*ecdsa.PrivateKey, len(keys)),
+		refreshInterval: cfg.RefreshInterval.Duration,
+		now:             time.Now,
+	}
+	for _, key := range keys {
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		if _, found := s.keys[address]; !found {
+			s.order = append(s.order, address)
+		}
+		s.keys[address] = key
+	}
+	return s
+}
+
+// load returns the activations of the keys, reloaded when they are older than the refresh interval. The first
+// configured key is activated if no key signed the responses yet.
+func (s *Signer) load(ctx context.Context) ([]etherman.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loadedAt.IsZero() && s.now().Sub(s.loadedAt) < s.refreshInterval {
+		return s.history, nil
+	}
+	history, err := s.storage.GetSigningKeys(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 && len(s.order) > 0 {
+		// Another instance may activate it at the same time, the activation stored first is used
+		if err := s.storage.AddSigningKey(ctx, s.order[0], s.now(), nil); err != nil {
+			log.Warnf("error activating the first signing key %s: %v", s.order[0].Hex(), err)
+		}
+		if history, err = s.storage.GetSigningKeys(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	s.history, s.loadedAt = history, s.now()
+	return s.history, nil
+}
+
+// invalidate reloads the activations in the next signature, so a rotation of this instance applies at once.
+func (s *Signer) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func activeKey(history []etherman.SigningKey) *etherman.SigningKey {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].RetiredAt == nil {
+			return &history[i]
+		}
+	}
+	return nil
+}
+
+// Message returns the message signed for a response: the request uri, the unix time of the signature and the hex
+// SHA-256 of the body, separated by new lines.
+func Message(requestURI string, signedAt int64, digest common.Hash) []byte {
+	return []byte(fmt.Sprintf("%s\n%d\n%s", requestURI, signedAt, hex.EncodeToString(digest[:])))
+}
+
+func digestHeader(digest common.Hash) string {
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// Sign signs the body of the response of a request with the active key.
+func (s *Signer) Sign(ctx context.Context, requestURI string, body []byte) (*Signature, error) {
+	history, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active := activeKey(history)
+	if active == nil {
+		return nil, errors.New("no active signing key")
+	}
+	key, found := s.keys[active.Address]
+	if !found {
+		return nil, fmt.Errorf("the active signing key %s is not configured in this instance", active.Address.Hex())
+	}
+	digest := common.Hash(sha256.Sum256(body))
+	signedAt := s.now().Unix()
+	signature, err := crypto.Sign(accounts.TextHash(Message(requestURI, signedAt, digest)), key)
+	if err != nil {
+		return nil, err
+	}
+	// The recovery id is 27 or 28 as personal_sign
+	signature[crypto.RecoveryIDOffset] += 27
+	return &Signature{Signer: active.Address, SignedAt: signedAt, Digest: digest, Signature: signature}, nil
+}
+
+// Keys returns the keys that signed the responses, in the order they were activated, and the standby keys.
+func (s *Signer) Keys(ctx context.Context) ([]Key, error) {
+	history, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]Key, 0, len(history)+len(s.order))
+	activated := make(map[common.Address]bool, len(history))
+	for i := range history {
+		key := Key{Signer: history[i].Address, Status: KeyActive, ActivatedAt: &history[i].ActivatedAt, RetiredAt: history[i].RetiredAt}
+		if key.RetiredAt != nil {
+			key.Status = KeyRetired
+		}
+		keys = append(keys, key)
+		activated[key.Signer] = true
+	}
+	for _, address := range s.order {
+		if !activated[address] {
+			keys = append(keys, Key{Signer: address, Status: KeyStandby})
+		}
+	}
+	return keys, nil
+}
+
+// Rotate retires the active key and activates a configured key, the next configured key after the active one if
+// the signer is the zero address. The retired key is trusted for the refresh interval after the rotation, until
+// every instance signs with the new key.
+func (s *Signer) Rotate(ctx context.Context, signer common.Address) (*Key, error) {
+	s.invalidate()
+	history, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active := activeKey(history)
+	if signer == (common.Address{}) {
+		signer = s.order[0]
+		for i, address := range s.order {
+			if active != nil && address == active.Address {
+				signer = s.order[(i+1)%len(s.order)]
+			}
+		}
+	}
+	if _, found := s.keys[signer]; !found {
+		return nil, fmt.Errorf("%w %s", ErrUnknownKey, signer.Hex())
+	}
+	if active != nil && active.Address == signer {
+		return nil, fmt.Errorf("%w: %s", ErrActiveKey, signer.Hex())
+	}
+	now := s.now()
+	dbTx, err := s.storage.BeginDBTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The other instances sign with the retired key until they refresh the active key
+	err = s.storage.RetireSigningKey(ctx, now.Add(s.refreshInterval), dbTx)
+	if err == nil {
+		err = s.storage.AddSigningKey(ctx, signer, now, dbTx)
+	}
+	if err == nil {
+		err = s.storage.Commit(ctx, dbTx)
+	}
+	if err != nil {
+		if rollbackErr := s.storage.Rollback(ctx, dbTx); rollbackErr != nil {
+			log.Errorf("error rolling back the rotation of the signing key: %v", rollbackErr)
+		}
+		return nil, err
+	}
+	s.invalidate()
+	return &Key{Signer: signer, Status: KeyActive, ActivatedAt: &now}, nil
+}
+
+// Verify checks the signature of the response of a request with its headers, returning the signer and the time of
+// the signature. The signer must be checked against the published keys with Trusted.
+func Verify(requestURI string, header http.Header, body []byte) (common.Address, time.Time, error) {
+	digest := common.Hash(sha256.Sum256(body))
+	if header.Get(DigestHeader) != digestHeader(digest) {
+		return common.Address{}, time.Time{}, errors.New("the digest doesn't match the body")
+	}
+	signedAt, err := strconv.ParseInt(header.Get(SignedAtHeader), 10, 64)
+	if err != nil {
+		return common.Address{}, time.Time{}, fmt.Errorf("invalid signature time: %w", err)
+	}
+	signature, err := hexutil.Decode(header.Get(SignatureHeader))
+	if err != nil {
+		return common.Address{}, time.Time{}, fmt.Errorf("invalid signature: %w", err)
+	}
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, time.Time{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	if signature[crypto.RecoveryIDOffset] >= 27 { //nolint:gomnd
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(Message(requestURI, signedAt, digest)), signature)
+	if err != nil {
+		return common.Address{}, time.Time{}, err
+	}
+	signer := crypto.PubkeyToAddress(*pub)
+	if claimed := common.HexToAddress(header.Get(SignerHeader)); claimed != signer {
+		return common.Address{}, time.Time{}, fmt.Errorf("response signed by %s instead of %s", signer.Hex(), claimed.Hex())
+	}
+	return signer, time.Unix(signedAt, 0), nil
+}
+
+// Trusted returns if the signer was signing the responses at a time, according to the published keys.
+func Trusted(keys []Key, signer common.Address, at time.Time) bool {
+	for _, key := range keys {
+		if key.Signer != signer || key.ActivatedAt == nil {
+			continue
+		}
+		// The times of the signatures are truncated to the second
+		if !at.Before(key.ActivatedAt.Truncate(time.Second)) && (key.RetiredAt == nil || at.Before(*key.RetiredAt)) {
+			return true
+		}
+	}
+	return false
+}
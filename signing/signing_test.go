@@ -0,0 +1,156 @@
+package signing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStorage stores the activations of the keys in memory, the transactions are ignored.
+type memoryStorage struct {
+	history []etherman.SigningKey
+}
+
+func (s *memoryStorage) GetSigningKeys(ctx context.Context, dbTx pgx.Tx) ([]etherman.SigningKey, error) {
+	return append([]etherman.SigningKey(nil), s.history...), nil
+}
+
+func (s *memoryStorage) RetireSigningKey(ctx context.Context, retiredAt time.Time, dbTx pgx.Tx) error {
+	for i := range s.history {
+		if s.history[i].RetiredAt == nil {
+			s.history[i].RetiredAt = &retiredAt
+		}
+	}
+	return nil
+}
+
+func (s *memoryStorage) AddSigningKey(ctx context.Context, address common.Address, activatedAt time.Time, dbTx pgx.Tx) error {
+	s.history = append(s.history, etherman.SigningKey{Address: address, ActivatedAt: activatedAt})
+	return nil
+}
+
+func (s *memoryStorage) BeginDBTransaction(ctx context.Context) (pgx.Tx, error) {
+	return nil, nil
+}
+
+func (s *memoryStorage) Commit(ctx context.Context, dbTx pgx.Tx) error {
+	return nil
+}
+
+func (s *memoryStorage) Rollback(ctx context.Context, dbTx pgx.Tx) error {
+	return nil
+}
+
+func newKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		keys[i] = key
+	}
+	return keys
+}
+
+func TestSignAndVerify(t *testing.T) {
+	ctx := context.Background()
+	keys := newKeys(t, 2)
+	first, second := crypto.PubkeyToAddress(keys[0].PublicKey), crypto.PubkeyToAddress(keys[1].PublicKey)
+	s := newSigner(Config{RefreshInterval: types.NewDuration(time.Minute)}, &memoryStorage{}, keys)
+	now := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return now }
+
+	// The first configured key signs until a rotation
+	body := []byte(`{"deposits":[]}`)
+	signature, err := s.Sign(ctx, "/bridges/0x1?limit=10", body)
+	require.NoError(t, err)
+	require.Equal(t, first, signature.Signer)
+	header := http.Header{}
+	signature.SetHeader(header)
+	signer, signedAt, err := Verify("/bridges/0x1?limit=10", header, body)
+	require.NoError(t, err)
+	require.Equal(t, first, signer)
+	require.Equal(t, now, signedAt)
+
+	// The signature is bound to the body and the request
+	_, _, err = Verify("/bridges/0x1?limit=10", header, []byte(`{"deposits":[{}]}`))
+	require.Error(t, err)
+	_, _, err = Verify("/bridges/0x2?limit=10", header, body)
+	require.Error(t, err)
+
+	keyList, err := s.Keys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keyList, 2)
+	require.Equal(t, KeyActive, keyList[0].Status)
+	require.Equal(t, KeyStandby, keyList[1].Status)
+	require.True(t, Trusted(keyList, first, signedAt))
+	require.False(t, Trusted(keyList, second, signedAt))
+	require.False(t, Trusted(keyList, first, signedAt.Add(-time.Second)))
+}
+
+func TestRotate(t *testing.T) {
+	ctx := context.Background()
+	keys := newKeys(t, 2)
+	first, second := crypto.PubkeyToAddress(keys[0].PublicKey), crypto.PubkeyToAddress(keys[1].PublicKey)
+	storage := &memoryStorage{}
+	cfg := Config{RefreshInterval: types.NewDuration(time.Minute)}
+	s := newSigner(cfg, storage, keys)
+	other := newSigner(cfg, storage, keys)
+	now := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return now }
+	other.now = func() time.Time { return now }
+	old, err := other.Sign(ctx, "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, first, old.Signer)
+
+	// Only the other configured keys are activated
+	_, err = s.Rotate(ctx, first)
+	require.ErrorIs(t, err, ErrActiveKey)
+	_, err = s.Rotate(ctx, common.HexToAddress("0x1"))
+	require.ErrorIs(t, err, ErrUnknownKey)
+	now = now.Add(time.Second)
+	key, err := s.Rotate(ctx, common.Address{})
+	require.NoError(t, err)
+	require.Equal(t, second, key.Signer)
+	signature, err := s.Sign(ctx, "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, second, signature.Signer)
+
+	// The other instance signs with the retired key until it refreshes, its signatures are still trusted
+	signature, err = other.Sign(ctx, "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, first, signature.Signer)
+	keyList, err := s.Keys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{KeyRetired, KeyActive}, []string{keyList[0].Status, keyList[1].Status})
+	require.True(t, Trusted(keyList, first, time.Unix(old.SignedAt, 0)))
+	require.True(t, Trusted(keyList, first, time.Unix(signature.SignedAt, 0)))
+	require.False(t, Trusted(keyList, first, now.Add(time.Minute)))
+	now = now.Add(time.Minute)
+	signature, err = other.Sign(ctx, "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, second, signature.Signer)
+
+	// A retired key is activated again with a new period
+	_, err = s.Rotate(ctx, first)
+	require.NoError(t, err)
+	keyList, err = s.Keys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keyList, 3)
+	require.Equal(t, []string{KeyRetired, KeyRetired, KeyActive}, []string{keyList[0].Status, keyList[1].Status, keyList[2].Status})
+}
+
+func TestConfig(t *testing.T) {
+	require.Error(t, Config{RefreshInterval: types.NewDuration(time.Second)}.Validate())
+	keys := []types.KeystoreFileConfig{{Path: "key.keystore", Password: "testonly"}}
+	require.Error(t, Config{Keys: keys}.Validate())
+	require.NoError(t, Config{Keys: keys, RefreshInterval: types.NewDuration(time.Second)}.Validate())
+}
@@ -0,0 +1,269 @@
+package statusrules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// valueType is the type of the values of the expressions.
+type valueType string
+
+const (
+	typeBool   valueType = "bool"
+	typeNumber valueType = "number"
+	typeString valueType = "string"
+)
+
+// evalFunc evaluates a compiled expression. The type of the value is checked when it's compiled.
+type evalFunc func(s *State) interface{}
+
+// token is a token of an expression: an operator, a parenthesis, a literal or a variable.
+type token struct {
+	kind  string
+	text  string
+	value interface{}
+	pos   int
+}
+
+const (
+	tokenOp     = "operator"
+	tokenLit    = "literal"
+	tokenIdent  = "variable"
+	tokenLParen = "("
+	tokenRParen = ")"
+	tokenEnd    = "end"
+)
+
+// operators are the operators of the language, the longest first so they are matched before their prefixes.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!"}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{kind: string(c), text: string(c), pos: i})
+			i++
+		case c == '"':
+			end := strings.IndexByte(expr[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			text := expr[i+1 : i+1+end]
+			tokens = append(tokens, token{kind: tokenLit, text: text, value: text, pos: i})
+			i += end + 2 //nolint:gomnd
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(expr) && (unicode.IsDigit(rune(expr[i])) || expr[i] == '.' || expr[i] == 'e' || expr[i] == 'E') {
+				i++
+			}
+			n, err := strconv.ParseFloat(expr[start:i], 64) //nolint:gomnd
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %s at %d", expr[start:i], start)
+			}
+			tokens = append(tokens, token{kind: tokenLit, text: expr[start:i], value: n, pos: start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(expr) && (unicode.IsLetter(rune(expr[i])) || unicode.IsDigit(rune(expr[i])) || expr[i] == '_') {
+				i++
+			}
+			switch word := expr[start:i]; word {
+			case "true", "false":
+				tokens = append(tokens, token{kind: tokenLit, text: word, value: word == "true", pos: start})
+			default:
+				tokens = append(tokens, token{kind: tokenIdent, text: word, pos: start})
+			}
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(expr[i:], op) {
+					tokens = append(tokens, token{kind: tokenOp, text: op, pos: i})
+					i += len(op)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, text: "end of the expression", pos: len(expr)}), nil
+}
+
+// parser compiles the tokens of an expression with the precedence, from the lowest: ||, &&, !, the comparisons.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// compile compiles a boolean expression on the variables of the state.
+func compile(expr string) (evalFunc, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	typ, eval, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %s at %d", t.text, t.pos)
+	}
+	if typ != typeBool {
+		return nil, fmt.Errorf("the expression is a %s, it must be a bool", typ)
+	}
+	return eval, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOp {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) or() (valueType, evalFunc, error) {
+	return p.logical("||", p.and)
+}
+
+func (p *parser) and() (valueType, evalFunc, error) {
+	return p.logical("&&", p.not)
+}
+
+// logical compiles the operands of a logical operator, evaluated from the left until the result is known.
+func (p *parser) logical(op string, operand func() (valueType, evalFunc, error)) (valueType, evalFunc, error) {
+	typ, left, err := operand()
+	if err != nil {
+		return "", nil, err
+	}
+	for p.isOp(op) {
+		t := p.next()
+		rightType, right, err := operand()
+		if err != nil {
+			return "", nil, err
+		}
+		if typ != typeBool || rightType != typeBool {
+			return "", nil, fmt.Errorf("%s at %d needs bools, not a %s and a %s", op, t.pos, typ, rightType)
+		}
+		l := left
+		if op == "&&" {
+			left = func(s *State) interface{} { return l(s).(bool) && right(s).(bool) }
+		} else {
+			left = func(s *State) interface{} { return l(s).(bool) || right(s).(bool) }
+		}
+	}
+	return typ, left, nil
+}
+
+func (p *parser) not() (valueType, evalFunc, error) {
+	if !p.isOp("!") {
+		return p.comparison()
+	}
+	t := p.next()
+	typ, operand, err := p.not()
+	if err != nil {
+		return "", nil, err
+	}
+	if typ != typeBool {
+		return "", nil, fmt.Errorf("! at %d needs a bool, not a %s", t.pos, typ)
+	}
+	return typeBool, func(s *State) interface{} { return !operand(s).(bool) }, nil
+}
+
+func (p *parser) comparison() (valueType, evalFunc, error) {
+	typ, left, err := p.primary()
+	if err != nil {
+		return "", nil, err
+	}
+	if !p.isOp("==", "!=", "<", "<=", ">", ">=") {
+		return typ, left, nil
+	}
+	t := p.next()
+	rightType, right, err := p.primary()
+	if err != nil {
+		return "", nil, err
+	}
+	if typ != rightType {
+		return "", nil, fmt.Errorf("%s at %d compares a %s with a %s", t.text, t.pos, typ, rightType)
+	}
+	switch t.text {
+	case "==":
+		return typeBool, func(s *State) interface{} { return left(s) == right(s) }, nil
+	case "!=":
+		return typeBool, func(s *State) interface{} { return left(s) != right(s) }, nil
+	}
+	if typ != typeNumber {
+		return "", nil, fmt.Errorf("%s at %d needs numbers, not a %s", t.text, t.pos, typ)
+	}
+	var cmp func(l, r float64) bool
+	switch t.text {
+	case "<":
+		cmp = func(l, r float64) bool { return l < r }
+	case "<=":
+		cmp = func(l, r float64) bool { return l <= r }
+	case ">":
+		cmp = func(l, r float64) bool { return l > r }
+	default:
+		cmp = func(l, r float64) bool { return l >= r }
+	}
+	return typeBool, func(s *State) interface{} { return cmp(left(s).(float64), right(s).(float64)) }, nil
+}
+
+func (p *parser) primary() (valueType, evalFunc, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLit:
+		value := t.value
+		eval := func(*State) interface{} { return value }
+		switch value.(type) {
+		case bool:
+			return typeBool, eval, nil
+		case float64:
+			return typeNumber, eval, nil
+		default:
+			return typeString, eval, nil
+		}
+	case tokenIdent:
+		v, found := variables[t.text]
+		if !found {
+			return "", nil, fmt.Errorf("unknown variable %s at %d", t.text, t.pos)
+		}
+		return v.typ, v.eval, nil
+	case tokenLParen:
+		typ, eval, err := p.or()
+		if err != nil {
+			return "", nil, err
+		}
+		if r := p.next(); r.kind != tokenRParen {
+			return "", nil, fmt.Errorf("expected ) at %d, found %s", r.pos, r.text)
+		}
+		return typ, eval, nil
+	default:
+		return "", nil, fmt.Errorf("unexpected %s at %d", t.text, t.pos)
+	}
+}
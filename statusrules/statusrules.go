@@ -0,0 +1,107 @@
+// Package statusrules maps the state of the deposits in the bridge to the display statuses defined by the operator,
+// so the frontends show the states of their product without forking the service. Each rule is a boolean expression
+// on the variables of the state of a deposit, and the status of the first rule that matches is displayed.
+package statusrules
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Rule is a display status with the expression a deposit must match to have it.
+type Rule struct {
+	// Status is the display status of the deposits matching the rule
+	Status string `mapstructure:"Status"`
+	// When is the boolean expression on the variables of the deposit, e.g. status == "ready_for_claim" && age > 3600
+	When string `mapstructure:"When"`
+}
+
+// State is the state of a deposit in the bridge the rules are evaluated on.
+type State struct {
+	// Status is the status of the deposit in the bridge: indexed, ready_for_claim or claimed
+	Status             string
+	LeafType           uint
+	NetworkID          uint
+	DepositCount       uint
+	OriginalNetwork    uint
+	OriginalAddress    string
+	DestinationNetwork uint
+	DestinationAddress string
+	Amount             *big.Int
+	Integration        string
+	// Age is the time since the block of the deposit, 0 if it's unknown
+	Age time.Duration
+}
+
+// variable is a variable of the expressions, read from the state.
+type variable struct {
+	typ  valueType
+	eval evalFunc
+}
+
+// variables are the variables of the expressions. The addresses are lower case hex.
+var variables = map[string]variable{
+	"status":      {typeString, func(s *State) interface{} { return s.Status }},
+	"leaf_type":   {typeNumber, func(s *State) interface{} { return float64(s.LeafType) }},
+	"network_id":  {typeNumber, func(s *State) interface{} { return float64(s.NetworkID) }},
+	"deposit_cnt": {typeNumber, func(s *State) interface{} { return float64(s.DepositCount) }},
+	"orig_net":    {typeNumber, func(s *State) interface{} { return float64(s.OriginalNetwork) }},
+	"orig_addr":   {typeString, func(s *State) interface{} { return strings.ToLower(s.OriginalAddress) }},
+	"dest_net":    {typeNumber, func(s *State) interface{} { return float64(s.DestinationNetwork) }},
+	"dest_addr":   {typeString, func(s *State) interface{} { return strings.ToLower(s.DestinationAddress) }},
+	"amount": {typeNumber, func(s *State) interface{} {
+		if s.Amount == nil {
+			return float64(0)
+		}
+		amount, _ := new(big.Float).SetInt(s.Amount).Float64()
+		return amount
+	}},
+	"integration": {typeString, func(s *State) interface{} { return s.Integration }},
+	"age":         {typeNumber, func(s *State) interface{} { return s.Age.Seconds() }},
+}
+
+// compiledRule is a rule with its compiled expression.
+type compiledRule struct {
+	status string
+	when   evalFunc
+}
+
+// Rules are the compiled rules, in the configured order.
+type Rules struct {
+	rules []compiledRule
+}
+
+// Compile compiles the rules, failing with the first invalid expression. It returns nil if there are no rules.
+func Compile(rules []Rule) (*Rules, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	compiled := &Rules{rules: make([]compiledRule, 0, len(rules))}
+	for i, rule := range rules {
+		if rule.Status == "" {
+			return nil, fmt.Errorf("rule %d has no status", i)
+		}
+		when, err := compile(rule.When)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d of the status %s: %w", i, rule.Status, err)
+		}
+		compiled.rules = append(compiled.rules, compiledRule{status: rule.Status, when: when})
+	}
+	return compiled, nil
+}
+
+// Status returns the display status of the first rule the state matches, empty if it matches none or there are
+// no rules.
+func (r *Rules) Status(s *State) string {
+	if r == nil {
+		return ""
+	}
+	for _, rule := range r.rules {
+		if rule.when(s).(bool) {
+			return rule.status
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package statusrules
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules(t *testing.T) {
+	rules, err := Compile([]Rule{
+		{Status: "Delayed", When: `status == "indexed" && network_id == 0 && age > 1800`},
+		{Status: "Whale", When: `amount >= 1e21 && !(status == "claimed")`},
+		{Status: "Partner", When: `integration == "partner" || dest_addr == "0x00000000000000000000000000000000000000aa"`},
+		{Status: "Claimable", When: `status == "ready_for_claim"`},
+	})
+	require.NoError(t, err)
+
+	state := &State{Status: "indexed", NetworkID: 0, Age: time.Hour, Amount: big.NewInt(1)}
+	require.Equal(t, "Delayed", rules.Status(state))
+	state.Age = time.Minute
+	require.Empty(t, rules.Status(state))
+	state.Amount, _ = new(big.Int).SetString("5000000000000000000000", 10)
+	require.Equal(t, "Whale", rules.Status(state))
+	state.Status = "claimed"
+	require.Empty(t, rules.Status(state))
+	// The addresses are compared in lower case
+	state.DestinationAddress = "0x00000000000000000000000000000000000000AA"
+	require.Equal(t, "Partner", rules.Status(state))
+	state.DestinationAddress = ""
+	state.Status, state.Amount = "ready_for_claim", big.NewInt(1)
+	require.Equal(t, "Claimable", rules.Status(state))
+
+	// Without rules there is no display status
+	none, err := Compile(nil)
+	require.NoError(t, err)
+	require.Empty(t, none.Status(state))
+}
+
+func TestCompile(t *testing.T) {
+	for _, when := range []string{
+		`status == "claimed" && (age > 10 || amount < 5)`,
+		`!(leaf_type == 1) && deposit_cnt != 3`,
+		`true`,
+	} {
+		_, err := compile(when)
+		require.NoError(t, err, when)
+	}
+	for _, when := range []string{
+		``,
+		`status`,
+		`age`,
+		`status > "a"`,
+		`status == 1`,
+		`age && true`,
+		`!age`,
+		`unknown == 1`,
+		`(age > 1`,
+		`age > 1)`,
+		`status == "claimed`,
+		`age > 1.2.3`,
+		`age # 1`,
+	} {
+		_, err := compile(when)
+		require.Error(t, err, when)
+	}
+	_, err := Compile([]Rule{{When: "true"}})
+	require.Error(t, err)
+}
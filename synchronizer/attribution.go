@@ -0,0 +1,40 @@
+package synchronizer
+
+import (
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// integrationsByAddress indexes the integrations by the addresses of their contracts.
+func integrationsByAddress(integrations []Integration) (map[common.Address]string, error) {
+	res := make(map[common.Address]string)
+	for _, integration := range integrations {
+		switch integration.Name {
+		case "", etherman.DepositIntegrationDirect, etherman.DepositIntegrationContract:
+			return nil, fmt.Errorf("invalid integration name %q", integration.Name)
+		}
+		for _, addr := range integration.Addresses {
+			if name, found := res[addr]; found && name != integration.Name {
+				return nil, fmt.Errorf("the address %s is of the integrations %s and %s", addr.String(), name, integration.Name)
+			}
+			res[addr] = integration.Name
+		}
+	}
+	return res, nil
+}
+
+// attribute returns the integration of the first known contract of the call path of a deposit. It's direct when
+// the tx calls the bridge, and contract when the bridge is only called by unknown contracts.
+func attribute(callPath []common.Address, integrations map[common.Address]string) string {
+	if len(callPath) == 0 {
+		return etherman.DepositIntegrationDirect
+	}
+	for _, addr := range callPath {
+		if name, found := integrations[addr]; found {
+			return name
+		}
+	}
+	return etherman.DepositIntegrationContract
+}
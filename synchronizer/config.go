@@ -1,7 +1,11 @@
 package synchronizer
 
 import (
+	"github.com/0xPolygonHermez/zkevm-bridge-service/blocktime"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // Config represents the configuration of the synchronizer
@@ -9,6 +13,61 @@ type Config struct {
 	// SyncInterval is the delay interval between reading new rollup information
 	SyncInterval types.Duration `mapstructure:"SyncInterval"`
 
+	// AdaptiveInterval makes the delay between the syncs of a synced network follow its observed block time,
+	// instead of the SyncInterval
+	AdaptiveInterval blocktime.Config `mapstructure:"AdaptiveInterval"`
+
+	// Retry is the retry profile of the syncs failed by the providers. An empty Profile uses the one of the
+	// Etherman
+	Retry etherman.RetryConfig `mapstructure:"Retry"`
+
 	// SyncChunkSize is the number of blocks to sync on each chunk
 	SyncChunkSize uint64 `mapstructure:"SyncChunkSize"`
+
+	// L1Confirmations is the number of confirmations of the L1 blocks before they are synced, so the reorgs
+	// shallower than them are never synced. 0 syncs up to the latest block
+	L1Confirmations uint64 `mapstructure:"L1Confirmations"`
+
+	// L2Confirmations are the confirmations of the blocks of each L2 network, in the order of the L2URLs. The
+	// networks without an entry sync up to their latest block
+	L2Confirmations []uint64 `mapstructure:"L2Confirmations"`
+
+	// PersistRawLogs enables storing the raw deposit and claim logs, so they can be decoded again later
+	PersistRawLogs bool `mapstructure:"PersistRawLogs"`
+
+	// TraceDeposits enables tracing the deposit txs to store their originator and the contracts that called the
+	// bridge, for the deposits made through internal calls. The node must support debug_traceTransaction
+	TraceDeposits bool `mapstructure:"TraceDeposits"`
+
+	// AttributeDeposits attributes the deposits to the integration they come from: a known integration, direct or
+	// an unknown contract. The call path of the trace is used with TraceDeposits, otherwise the destination of the tx
+	AttributeDeposits bool `mapstructure:"AttributeDeposits"`
+
+	// RecordDepositSenders records the msg.sender of the bridge call and the sender of the tx of each deposit, so
+	// the deposits made by contracts on behalf of the users can be queried by either. The msg.sender is exact with
+	// TraceDeposits, otherwise it's the contract called by the tx
+	RecordDepositSenders bool `mapstructure:"RecordDepositSenders"`
+
+	// TraceMessageClaims traces the claim txs of the messages to store if the call to their destination succeeded,
+	// reverted or ran no code, since the claim alone doesn't tell. The node must support debug_traceTransaction
+	TraceMessageClaims bool `mapstructure:"TraceMessageClaims"`
+
+	// RepairDepositGaps resyncs the blocks since the last deposit before a deposit count gap, which means the
+	// provider missed deposit events, instead of retrying the blocks after it
+	RepairDepositGaps bool `mapstructure:"RepairDepositGaps"`
+
+	// Integrations are the known integrations calling the bridge through their contracts
+	Integrations []Integration `mapstructure:"Integrations"`
+
+	// ExitRootQueue is the queue of the global exit root updates applied by the claim tx managers to the status
+	// of the deposits
+	ExitRootQueue queue.Config `mapstructure:"ExitRootQueue"`
+}
+
+// Integration is a known integration calling the bridge through its contracts, e.g. an aggregator or a portal
+type Integration struct {
+	// Name identifies the integration in the deposits and the stats
+	Name string `mapstructure:"Name"`
+	// Addresses are the contracts of the integration calling the bridge, e.g. its routers
+	Addresses []common.Address `mapstructure:"Addresses"`
 }
@@ -0,0 +1,82 @@
+package synchronizer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Kinds of the deposit continuity errors
+const (
+	// continuityGap is a deposit count that skips values
+	continuityGap = "gap"
+	// continuityOutOfOrder is the next deposit count in a log before the log of the previous one
+	continuityOutOfOrder = "out_of_order"
+)
+
+// DepositContinuityErrors counts the deposits of each network that break the continuity of the deposit counts or
+// of their logs, which means the provider missed or reordered deposit events.
+var DepositContinuityErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "bridge_deposit_continuity_errors_total",
+	Help: "Number of synced deposits breaking the continuity of the deposit counts (gap) or of their logs (out_of_order)",
+}, []string{"network_id", "kind"})
+
+// depositPosition is the position of a synced deposit in the chain and in the exit tree.
+type depositPosition struct {
+	blockNumber  uint64
+	logIndex     uint
+	depositCount uint
+}
+
+// after returns if the log of the deposit is after the position.
+func (p depositPosition) after(deposit etherman.Deposit) bool {
+	return deposit.BlockNumber > p.blockNumber || deposit.BlockNumber == p.blockNumber && deposit.LogIndex > p.logIndex
+}
+
+// checkDepositOrder checks that the log of the next deposit count is after the log of the previous one. A deposit
+// out of order is only reported, since its count is still the next one.
+func (s *ClientSynchronizer) checkDepositOrder(deposit etherman.Deposit) {
+	last := s.lastDeposit
+	if last == nil || deposit.DepositCount != last.depositCount+1 || last.after(deposit) {
+		return
+	}
+	DepositContinuityErrors.WithLabelValues(strconv.FormatUint(uint64(s.networkID), 10), continuityOutOfOrder).Inc()
+	log.Errorf("networkID: %d, deposit %d in the log %d of the block %d is not after the deposit %d in the log %d of the block %d",
+		s.networkID, deposit.DepositCount, deposit.LogIndex, deposit.BlockNumber, last.depositCount, last.logIndex, last.blockNumber)
+}
+
+// repairDepositGap handles a deposit whose count skips values of the exit tree. The events of the deposits in
+// between were missed, so the blocks since the last deposit before the gap are synced again if the repair is
+// enabled. It returns the error of the gap.
+func (s *ClientSynchronizer) repairDepositGap(deposit etherman.Deposit, gapErr error) error {
+	DepositContinuityErrors.WithLabelValues(strconv.FormatUint(uint64(s.networkID), 10), continuityGap).Inc()
+	// The committed state has the deposits before the gap
+	expected, err := s.storage.GetNumberDeposits(s.ctx, s.networkID, deposit.BlockNumber, nil)
+	if err != nil {
+		return fmt.Errorf("error getting the deposit count expected before the deposit %d: %w", deposit.DepositCount, err)
+	}
+	log.Errorf("networkID: %d, deposit count gap: deposit %d in the log %d of the block %d, expected %d, the deposits %d to %d were missed",
+		s.networkID, deposit.DepositCount, deposit.LogIndex, deposit.BlockNumber, expected, expected, deposit.DepositCount-1)
+	if !s.cfg.RepairDepositGaps {
+		return gapErr
+	}
+	resetBlock := s.genBlockNumber
+	if expected > 0 {
+		last, err := s.storage.GetDeposit(s.ctx, uint(expected-1), s.networkID, nil)
+		if err != nil {
+			return fmt.Errorf("error getting the deposit %d before the gap: %w", expected-1, err)
+		}
+		// The block of the last deposit is synced again too, the missed events can be after it in the block
+		if last.BlockNumber > resetBlock {
+			resetBlock = last.BlockNumber - 1
+		}
+	}
+	if err := s.resetState(resetBlock); err != nil {
+		return fmt.Errorf("error resetting the state to repair the deposit count gap: %w", err)
+	}
+	log.Warnf("networkID: %d, deposit count gap repaired, syncing again from the block %d", s.networkID, resetBlock+1)
+	return gapErr
+}
@@ -0,0 +1,68 @@
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositGap(t *testing.T) {
+	m := mocks{
+		BridgeCtrl: newBridgectrlMock(t),
+		Storage:    newStorageMock(t),
+		DbTx:       newDbTxMock(t),
+	}
+	ctx := context.Background()
+	s := &ClientSynchronizer{
+		bridgeCtrl: m.BridgeCtrl,
+		storage:    m.Storage,
+		ctx:        ctx,
+		cfg:        Config{RepairDepositGaps: true},
+		networkID:  1,
+	}
+	first := etherman.Deposit{DepositCount: 0, BlockNumber: 10, LogIndex: 3}
+	second := etherman.Deposit{DepositCount: 1, BlockNumber: 10, LogIndex: 2}
+	gap := etherman.Deposit{DepositCount: 5, BlockNumber: 20, LogIndex: 0}
+	m.Storage.On("AddDeposit", ctx, mock.Anything, m.DbTx).Return(uint64(1), nil)
+	m.BridgeCtrl.On("AddDeposit", mock.MatchedBy(func(d *etherman.Deposit) bool { return d.DepositCount < 2 }), uint64(1), m.DbTx).Return(nil).Twice()
+	outOfOrder := testutil.ToFloat64(DepositContinuityErrors.WithLabelValues("1", continuityOutOfOrder))
+	gaps := testutil.ToFloat64(DepositContinuityErrors.WithLabelValues("1", continuityGap))
+
+	// The next deposit count in a previous log is only reported
+	require.NoError(t, s.processDeposit(first, 1, m.DbTx))
+	require.NoError(t, s.processDeposit(second, 1, m.DbTx))
+	require.Equal(t, outOfOrder+1, testutil.ToFloat64(DepositContinuityErrors.WithLabelValues("1", continuityOutOfOrder)))
+	require.Equal(t, &depositPosition{blockNumber: 10, logIndex: 2, depositCount: 1}, s.lastDeposit)
+
+	// A gap resyncs the blocks since the block before the last deposit before it
+	m.BridgeCtrl.On("AddDeposit", mock.MatchedBy(func(d *etherman.Deposit) bool { return d.DepositCount == 5 }), uint64(1), m.DbTx).
+		Return(gerror.Wrap(gerror.ErrDepositGap, fmt.Errorf("mismatched deposit count: 5, expected: 2"))).Once()
+	m.Storage.On("Rollback", ctx, m.DbTx).Return(nil).Once()
+	m.Storage.On("GetNumberDeposits", ctx, uint(1), uint64(20), nil).Return(uint64(2), nil).Once()
+	m.Storage.On("GetDeposit", ctx, uint(1), uint(1), nil).Return(&second, nil).Once()
+	m.Storage.On("BeginDBTransaction", ctx).Return(m.DbTx, nil).Once()
+	m.Storage.On("Reset", ctx, uint64(9), uint(1), m.DbTx).Return(nil).Once()
+	m.Storage.On("GetNumberDeposits", ctx, uint(1), uint64(9), m.DbTx).Return(uint64(0), nil).Once()
+	m.BridgeCtrl.On("ReorgMT", uint(0), uint(1), m.DbTx).Return(nil).Once()
+	m.Storage.On("Commit", ctx, m.DbTx).Return(nil).Once()
+	err := s.processDeposit(gap, 1, m.DbTx)
+	require.ErrorIs(t, err, gerror.ErrDepositGap)
+	require.Equal(t, gaps+1, testutil.ToFloat64(DepositContinuityErrors.WithLabelValues("1", continuityGap)))
+	require.Nil(t, s.lastDeposit)
+
+	// Without the repair the gap is only reported
+	s.cfg.RepairDepositGaps = false
+	m.BridgeCtrl.On("AddDeposit", mock.MatchedBy(func(d *etherman.Deposit) bool { return d.DepositCount == 5 }), uint64(1), m.DbTx).
+		Return(gerror.Wrap(gerror.ErrDepositGap, fmt.Errorf("mismatched deposit count: 5, expected: 2"))).Once()
+	m.Storage.On("Rollback", ctx, m.DbTx).Return(nil).Once()
+	m.Storage.On("GetNumberDeposits", ctx, uint(1), uint64(20), nil).Return(uint64(2), nil).Once()
+	err = s.processDeposit(gap, 1, m.DbTx)
+	require.ErrorIs(t, err, gerror.ErrDepositGap)
+	require.Equal(t, gaps+2, testutil.ToFloat64(DepositContinuityErrors.WithLabelValues("1", continuityGap)))
+}
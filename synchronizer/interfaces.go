@@ -5,6 +5,7 @@ import (
 	"math/big"
 
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/eventbus"
 	rpcTypes "github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -17,6 +18,9 @@ type ethermanInterface interface {
 	GetRollupInfoByBlockRange(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]etherman.Block, map[common.Hash][]etherman.Order, error)
 	EthBlockByNumber(ctx context.Context, blockNumber uint64) (*types.Block, error)
 	GetNetworkID(ctx context.Context) (uint, error)
+	TraceDeposit(ctx context.Context, txHash common.Hash) (*etherman.DepositTrace, error)
+	DepositOrigin(ctx context.Context, txHash common.Hash) (*etherman.DepositTrace, error)
+	TraceMessageCall(ctx context.Context, txHash common.Hash, destination common.Address, blockNumber uint64) (string, error)
 }
 
 type storageInterface interface {
@@ -29,9 +33,13 @@ type storageInterface interface {
 	AddDeposit(ctx context.Context, deposit *etherman.Deposit, dbTx pgx.Tx) (uint64, error)
 	AddClaim(ctx context.Context, claim *etherman.Claim, dbTx pgx.Tx) error
 	AddTokenWrapped(ctx context.Context, tokenWrapped *etherman.TokenWrapped, dbTx pgx.Tx) error
+	AddRawLog(ctx context.Context, vLog *types.Log, blockID uint64, networkID uint, dbTx pgx.Tx) error
+	AddDepositTrace(ctx context.Context, trace *etherman.DepositTrace, dbTx pgx.Tx) error
 	Reset(ctx context.Context, blockNumber uint64, networkID uint, dbTx pgx.Tx) error
 	GetPreviousBlock(ctx context.Context, networkID uint, offset uint64, dbTx pgx.Tx) (*etherman.Block, error)
 	GetNumberDeposits(ctx context.Context, origNetworkID uint, blockNumber uint64, dbTx pgx.Tx) (uint64, error)
+	GetDeposit(ctx context.Context, depositCounterUser uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error)
+	GetClaimedDeposit(ctx context.Context, depositCnt, destNetworkID, origNet uint, origAddr common.Address, dbTx pgx.Tx) (*etherman.Deposit, error)
 	AddTrustedGlobalExitRoot(ctx context.Context, trustedExitRoot *etherman.GlobalExitRoot, dbTx pgx.Tx) (bool, error)
 	GetLatestL1SyncedExitRoot(ctx context.Context, dbTx pgx.Tx) (*etherman.GlobalExitRoot, error)
 }
@@ -39,9 +47,18 @@ type storageInterface interface {
 type bridgectrlInterface interface {
 	AddDeposit(deposit *etherman.Deposit, depositID uint64, dbTx pgx.Tx) error
 	ReorgMT(depositCount, networkID uint, dbTx pgx.Tx) error
+	AddRollupExitLeaf(ctx context.Context, leaf *etherman.RollupExitLeaf, dbTx pgx.Tx) error
+	AddL1InfoTreeLeaf(ctx context.Context, leaf *etherman.L1InfoTreeLeaf, dbTx pgx.Tx) error
+	ReorgL1InfoTree(ctx context.Context, dbTx pgx.Tx) error
 }
 
 type zkEVMClientInterface interface {
 	BatchNumber(ctx context.Context) (uint64, error)
 	BatchByNumber(ctx context.Context, number *big.Int) (*rpcTypes.Batch, error)
 }
+
+// EventPublisher publishes the synced data on the event bus, in the db tx storing it.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *eventbus.Event, dbTx pgx.Tx) error
+	Notify()
+}
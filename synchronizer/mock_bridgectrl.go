@@ -3,6 +3,8 @@
 package synchronizer
 
 import (
+	context "context"
+
 	etherman "github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
 	mock "github.com/stretchr/testify/mock"
 
@@ -28,6 +30,48 @@ func (_m *bridgectrlMock) AddDeposit(deposit *etherman.Deposit, depositID uint64
 	return r0
 }
 
+// AddL1InfoTreeLeaf provides a mock function with given fields: ctx, leaf, dbTx
+func (_m *bridgectrlMock) AddL1InfoTreeLeaf(ctx context.Context, leaf *etherman.L1InfoTreeLeaf, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, leaf, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *etherman.L1InfoTreeLeaf, pgx.Tx) error); ok {
+		r0 = rf(ctx, leaf, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddRollupExitLeaf provides a mock function with given fields: ctx, leaf, dbTx
+func (_m *bridgectrlMock) AddRollupExitLeaf(ctx context.Context, leaf *etherman.RollupExitLeaf, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, leaf, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *etherman.RollupExitLeaf, pgx.Tx) error); ok {
+		r0 = rf(ctx, leaf, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReorgL1InfoTree provides a mock function with given fields: ctx, dbTx
+func (_m *bridgectrlMock) ReorgL1InfoTree(ctx context.Context, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) error); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ReorgMT provides a mock function with given fields: depositCount, networkID, dbTx
 func (_m *bridgectrlMock) ReorgMT(depositCount uint, networkID uint, dbTx pgx.Tx) error {
 	ret := _m.Called(depositCount, networkID, dbTx)
@@ -20,6 +20,32 @@ type ethermanMock struct {
 	mock.Mock
 }
 
+// DepositOrigin provides a mock function with given fields: ctx, txHash
+func (_m *ethermanMock) DepositOrigin(ctx context.Context, txHash common.Hash) (*etherman.DepositTrace, error) {
+	ret := _m.Called(ctx, txHash)
+
+	var r0 *etherman.DepositTrace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash) (*etherman.DepositTrace, error)); ok {
+		return rf(ctx, txHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash) *etherman.DepositTrace); ok {
+		r0 = rf(ctx, txHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*etherman.DepositTrace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash) error); ok {
+		r1 = rf(ctx, txHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EthBlockByNumber provides a mock function with given fields: ctx, blockNumber
 func (_m *ethermanMock) EthBlockByNumber(ctx context.Context, blockNumber uint64) (*types.Block, error) {
 	ret := _m.Called(ctx, blockNumber)
@@ -131,6 +157,56 @@ func (_m *ethermanMock) HeaderByNumber(ctx context.Context, number *big.Int) (*t
 	return r0, r1
 }
 
+// TraceDeposit provides a mock function with given fields: ctx, txHash
+func (_m *ethermanMock) TraceDeposit(ctx context.Context, txHash common.Hash) (*etherman.DepositTrace, error) {
+	ret := _m.Called(ctx, txHash)
+
+	var r0 *etherman.DepositTrace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash) (*etherman.DepositTrace, error)); ok {
+		return rf(ctx, txHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash) *etherman.DepositTrace); ok {
+		r0 = rf(ctx, txHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*etherman.DepositTrace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash) error); ok {
+		r1 = rf(ctx, txHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TraceMessageCall provides a mock function with given fields: ctx, txHash, destination, blockNumber
+func (_m *ethermanMock) TraceMessageCall(ctx context.Context, txHash common.Hash, destination common.Address, blockNumber uint64) (string, error) {
+	ret := _m.Called(ctx, txHash, destination, blockNumber)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, common.Address, uint64) (string, error)); ok {
+		return rf(ctx, txHash, destination, blockNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, common.Address, uint64) string); ok {
+		r0 = rf(ctx, txHash, destination, blockNumber)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash, common.Address, uint64) error); ok {
+		r1 = rf(ctx, txHash, destination, blockNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTnewEthermanMock interface {
 	mock.TestingT
 	Cleanup(func())
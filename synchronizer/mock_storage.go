@@ -5,10 +5,15 @@ package synchronizer
 import (
 	context "context"
 
+	common "github.com/ethereum/go-ethereum/common"
+
 	etherman "github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+
 	mock "github.com/stretchr/testify/mock"
 
 	pgx "github.com/jackc/pgx/v4"
+
+	types "github.com/ethereum/go-ethereum/core/types"
 )
 
 // storageMock is an autogenerated mock type for the storageInterface type
@@ -78,6 +83,20 @@ func (_m *storageMock) AddDeposit(ctx context.Context, deposit *etherman.Deposit
 	return r0, r1
 }
 
+// AddDepositTrace provides a mock function with given fields: ctx, trace, dbTx
+func (_m *storageMock) AddDepositTrace(ctx context.Context, trace *etherman.DepositTrace, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, trace, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *etherman.DepositTrace, pgx.Tx) error); ok {
+		r0 = rf(ctx, trace, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddGlobalExitRoot provides a mock function with given fields: ctx, exitRoot, dbTx
 func (_m *storageMock) AddGlobalExitRoot(ctx context.Context, exitRoot *etherman.GlobalExitRoot, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, exitRoot, dbTx)
@@ -92,6 +111,20 @@ func (_m *storageMock) AddGlobalExitRoot(ctx context.Context, exitRoot *etherman
 	return r0
 }
 
+// AddRawLog provides a mock function with given fields: ctx, vLog, blockID, networkID, dbTx
+func (_m *storageMock) AddRawLog(ctx context.Context, vLog *types.Log, blockID uint64, networkID uint, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, vLog, blockID, networkID, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *types.Log, uint64, uint, pgx.Tx) error); ok {
+		r0 = rf(ctx, vLog, blockID, networkID, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddTokenWrapped provides a mock function with given fields: ctx, tokenWrapped, dbTx
 func (_m *storageMock) AddTokenWrapped(ctx context.Context, tokenWrapped *etherman.TokenWrapped, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, tokenWrapped, dbTx)
@@ -170,6 +203,58 @@ func (_m *storageMock) Commit(ctx context.Context, dbTx pgx.Tx) error {
 	return r0
 }
 
+// GetClaimedDeposit provides a mock function with given fields: ctx, depositCnt, destNetworkID, origNet, origAddr, dbTx
+func (_m *storageMock) GetClaimedDeposit(ctx context.Context, depositCnt uint, destNetworkID uint, origNet uint, origAddr common.Address, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	ret := _m.Called(ctx, depositCnt, destNetworkID, origNet, origAddr, dbTx)
+
+	var r0 *etherman.Deposit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, uint, common.Address, pgx.Tx) (*etherman.Deposit, error)); ok {
+		return rf(ctx, depositCnt, destNetworkID, origNet, origAddr, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, uint, common.Address, pgx.Tx) *etherman.Deposit); ok {
+		r0 = rf(ctx, depositCnt, destNetworkID, origNet, origAddr, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*etherman.Deposit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint, uint, common.Address, pgx.Tx) error); ok {
+		r1 = rf(ctx, depositCnt, destNetworkID, origNet, origAddr, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeposit provides a mock function with given fields: ctx, depositCounterUser, networkID, dbTx
+func (_m *storageMock) GetDeposit(ctx context.Context, depositCounterUser uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	ret := _m.Called(ctx, depositCounterUser, networkID, dbTx)
+
+	var r0 *etherman.Deposit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, pgx.Tx) (*etherman.Deposit, error)); ok {
+		return rf(ctx, depositCounterUser, networkID, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, pgx.Tx) *etherman.Deposit); ok {
+		r0 = rf(ctx, depositCounterUser, networkID, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*etherman.Deposit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint, pgx.Tx) error); ok {
+		r1 = rf(ctx, depositCounterUser, networkID, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastBlock provides a mock function with given fields: ctx, networkID, dbTx
 func (_m *storageMock) GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
 	ret := _m.Called(ctx, networkID, dbTx)
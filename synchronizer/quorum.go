@@ -0,0 +1,85 @@
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	rpcTypes "github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// QuorumZkEVMClient reads the trusted state from the main provider and only trusts the batches whose exit
+// roots are the same in the threshold of providers, the main one included.
+type QuorumZkEVMClient struct {
+	main      zkEVMClientInterface
+	others    []zkEVMClientInterface
+	urls      []string
+	threshold uint
+}
+
+// NewQuorumZkEVMClient creates the client of the trusted state of the url, checked with the providers of the
+// quorum if it's enabled.
+func NewQuorumZkEVMClient(url string, cfg etherman.QuorumConfig) (*QuorumZkEVMClient, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	c := &QuorumZkEVMClient{main: client.NewClient(url), threshold: cfg.Threshold}
+	if cfg.Enabled() {
+		for _, url := range cfg.URLs {
+			c.others = append(c.others, client.NewClient(url))
+		}
+		c.urls = cfg.URLs
+	}
+	return c, nil
+}
+
+// BatchNumber returns the last batch number of the main provider. The providers behind it fail the quorum of
+// the batch until they get it.
+func (c *QuorumZkEVMClient) BatchNumber(ctx context.Context) (uint64, error) {
+	return c.main.BatchNumber(ctx)
+}
+
+// BatchByNumber returns the batch of the main provider if the quorum has the same exit roots.
+func (c *QuorumZkEVMClient) BatchByNumber(ctx context.Context, number *big.Int) (*rpcTypes.Batch, error) {
+	batch, err := c.main.BatchByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, gerror.Wrap(gerror.ErrNotFound, fmt.Errorf("the main provider has no batch %s", number.String()))
+	}
+	if len(c.others) == 0 {
+		return batch, nil
+	}
+	matches := uint(1)
+	for i, other := range c.others {
+		if matches >= c.threshold {
+			break
+		}
+		otherBatch, err := other.BatchByNumber(ctx, number)
+		if err != nil {
+			log.Warnf("error getting the batch %s from the quorum provider %s: %v", number.String(), c.urls[i], err)
+			continue
+		}
+		// The providers behind the main one have no batch yet
+		if otherBatch == nil {
+			log.Warnf("the quorum provider %s has no batch %s", c.urls[i], number.String())
+			continue
+		}
+		if otherBatch.GlobalExitRoot != batch.GlobalExitRoot || otherBatch.MainnetExitRoot != batch.MainnetExitRoot || otherBatch.RollupExitRoot != batch.RollupExitRoot {
+			log.Warnf("the quorum provider %s has the global exit root %s in the batch %s, the main provider %s",
+				c.urls[i], otherBatch.GlobalExitRoot.String(), number.String(), batch.GlobalExitRoot.String())
+			continue
+		}
+		matches++
+	}
+	if matches < c.threshold {
+		return nil, gerror.Wrap(gerror.ErrQuorumNotReached, fmt.Errorf("%d of %d providers have the global exit root %s of the batch %s, %d required",
+			matches, len(c.others)+1, batch.GlobalExitRoot.String(), number.String(), c.threshold))
+	}
+	return batch, nil
+}
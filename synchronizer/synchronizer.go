@@ -5,19 +5,34 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync/atomic"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-bridge-service/activity"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/blocktime"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/eventbus"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/gerlatency"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/maintenance"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 )
 
+// leafTypeMessage is the leaf type of the message deposits
+const leafTypeMessage = uint8(1)
+
 // Synchronizer connects L1 and L2
 type Synchronizer interface {
 	Sync() error
 	Stop()
+	NetworkID() uint
+	SyncStatus() (bool, uint64)
 }
 
 // ClientSynchronizer connects L1 and L2
@@ -30,11 +45,29 @@ type ClientSynchronizer struct {
 	genBlockNumber   uint64
 	cfg              Config
 	networkID        uint
-	chExitRootEvent  chan *etherman.GlobalExitRoot
+	integrations     map[common.Address]string
+	exitRootEvents   *queue.Queue[*etherman.GlobalExitRoot]
 	chSynced         chan uint
 	zkEVMClient      zkEVMClientInterface
+	clock            wait.Clock
 	synced           bool
 	l1RollupExitRoot common.Hash
+	// syncFailures is the number of consecutive syncs of the blocks failed by an unexpected error, for the backoff
+	syncFailures int
+	// lastDeposit is the position of the last deposit synced, nil until a deposit is synced after the start or a reset
+	lastDeposit *depositPosition
+	// bus publishes the synced data when it's set, instead of notifying the exit root events and the hooks
+	bus EventPublisher
+	// maintenance pauses the sync during the maintenances, nil when it's not available
+	maintenance *maintenance.Monitor
+	// confirmations is the number of blocks after the latest one before a block is synced
+	confirmations uint64
+	// syncStatus is read from other goroutines to report the readiness
+	syncStatus struct {
+		synced         atomic.Bool
+		lastKnownBlock atomic.Uint64
+		lastSynced     atomic.Uint64
+	}
 }
 
 // NewSynchronizer creates and initializes an instance of Synchronizer
@@ -44,7 +77,7 @@ func NewSynchronizer(
 	ethMan ethermanInterface,
 	zkEVMClient zkEVMClientInterface,
 	genBlockNumber uint64,
-	chExitRootEvent chan *etherman.GlobalExitRoot,
+	exitRootEvents *queue.Queue[*etherman.GlobalExitRoot],
 	chSynced chan uint,
 	cfg Config) (Synchronizer, error) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -52,9 +85,22 @@ func NewSynchronizer(
 	if err != nil {
 		log.Fatal("error getting networkID. Error: ", err)
 	}
+	integrations, err := integrationsByAddress(cfg.Integrations)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := cfg.AdaptiveInterval.Validate(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid adaptive sync interval: %w", err)
+	}
+	if err := cfg.Retry.Validate(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid sync retry profile: %w", err)
+	}
 	ger, err := storage.(storageInterface).GetLatestL1SyncedExitRoot(context.Background(), nil)
 	if err != nil {
-		if err == gerror.ErrStorageNotFound {
+		if errors.Is(err, gerror.ErrStorageNotFound) {
 			ger.ExitRoots = []common.Hash{{}, {}}
 		} else {
 			log.Fatal("error getting last L1 synced exitroot. Error: ", err)
@@ -71,9 +117,11 @@ func NewSynchronizer(
 			genBlockNumber:   genBlockNumber,
 			cfg:              cfg,
 			networkID:        networkID,
-			chExitRootEvent:  chExitRootEvent,
+			integrations:     integrations,
+			exitRootEvents:   exitRootEvents,
 			chSynced:         chSynced,
 			zkEVMClient:      zkEVMClient,
+			clock:            wait.RealClock,
 			l1RollupExitRoot: ger.ExitRoots[1],
 		}, nil
 	}
@@ -87,10 +135,28 @@ func NewSynchronizer(
 		cfg:            cfg,
 		chSynced:       chSynced,
 		networkID:      networkID,
+		integrations:   integrations,
+		clock:          wait.RealClock,
 	}, nil
 }
 
-var waitDuration = time.Duration(0)
+// SetEventBus publishes the synced deposits, exit roots, claims and reorgs on the event bus, in the db tx of the
+// synced data, instead of notifying the claim tx managers, the exit root latency stats and the hooks directly.
+func (s *ClientSynchronizer) SetEventBus(bus EventPublisher) {
+	s.bus = bus
+}
+
+// SetMaintenanceMonitor pauses the sync of the blocks during the maintenances, resuming it from the last synced
+// block when they end.
+func (s *ClientSynchronizer) SetMaintenanceMonitor(monitor *maintenance.Monitor) {
+	s.maintenance = monitor
+}
+
+// SetConfirmations syncs the blocks of the network once they have the confirmations, instead of up to the latest
+// block, so the reorgs shallower than them are never synced.
+func (s *ClientSynchronizer) SetConfirmations(confirmations uint64) {
+	s.confirmations = confirmations
+}
 
 // Sync function will read the last state synced and will continue from that point.
 // Sync() will read blockchain events to detect rollup updates
@@ -100,7 +166,7 @@ func (s *ClientSynchronizer) Sync() error {
 	log.Infof("NetworkID: %d, Synchronization started", s.networkID)
 	lastBlockSynced, err := s.storage.GetLastBlock(s.ctx, s.networkID, nil)
 	if err != nil {
-		if err == gerror.ErrStorageNotFound {
+		if errors.Is(err, gerror.ErrStorageNotFound) {
 			log.Warnf("networkID: %d, error getting the latest ethereum block. No data stored. Setting genesis block. Error: %w", s.networkID, err)
 			lastBlockSynced = &etherman.Block{
 				BlockNumber: s.genBlockNumber,
@@ -118,11 +184,40 @@ func (s *ClientSynchronizer) Sync() error {
 		case <-s.ctx.Done():
 			log.Debugf("NetworkID: %d, synchronizer ctx done", s.networkID)
 			return nil
-		case <-time.After(waitDuration):
+		case <-s.clock.After(s.syncInterval()):
+			if m := s.maintenance.Active(s.ctx); m != nil {
+				log.Debugf("NetworkID: %d, maintenance in progress until %s, skipping the sync", s.networkID, m.ETA.String())
+				continue
+			}
 			log.Debugf("NetworkID: %d, syncing...", s.networkID)
 			//Sync L1Blocks
 			if lastBlockSynced, err = s.syncBlocks(lastBlockSynced); err != nil {
-				log.Warnf("networkID: %d, error syncing blocks: ", s.networkID, err)
+				switch {
+				case errors.Is(err, gerror.ErrReorgDetected):
+					log.Infof("networkID: %d, reorg detected while reading the blocks, resuming from the last synced block: %v", s.networkID, err)
+				case errors.Is(err, gerror.ErrProviderLimit):
+					// Back off instead of retrying at once, which would keep the provider over its limit
+					log.Warnf("networkID: %d, rpc provider limit exceeded, retrying in %s: %v", s.networkID, s.cfg.SyncInterval.Duration, err)
+					if err := wait.Sleep(s.ctx, s.clock, s.cfg.SyncInterval.Duration); err != nil {
+						continue
+					}
+				case errors.Is(err, gerror.ErrDepositGap):
+					log.Errorf("networkID: %d, deposit count gap, resuming from the last synced block: %v", s.networkID, err)
+				case errors.Is(err, gerror.ErrQuorumNotReached):
+					// The providers may disagree until the ones behind the others get the same blocks
+					log.Warnf("networkID: %d, rpc providers quorum not reached, retrying in %s: %v", s.networkID, s.cfg.SyncInterval.Duration, err)
+					if err := wait.Sleep(s.ctx, s.clock, s.cfg.SyncInterval.Duration); err != nil {
+						continue
+					}
+				default:
+					// Back off while the provider keeps failing, instead of retrying at once until it recovers
+					s.syncFailures++
+					backoff := s.cfg.Retry.Waiter().Backoff(s.syncFailures)
+					log.Warnf("networkID: %d, error syncing blocks, retrying in %s: %v", s.networkID, backoff, err)
+					if err := wait.Sleep(s.ctx, s.clock, backoff); err != nil {
+						continue
+					}
+				}
 				lastBlockSynced, err = s.storage.GetLastBlock(s.ctx, s.networkID, nil)
 				if err != nil {
 					log.Fatalf("networkID: %d, error getting lastBlockSynced to resume the synchronization... Error: ", s.networkID, err)
@@ -130,6 +225,8 @@ func (s *ClientSynchronizer) Sync() error {
 				if s.ctx.Err() != nil {
 					continue
 				}
+			} else {
+				s.syncFailures = 0
 			}
 			if !s.synced {
 				// Check latest Block
@@ -139,10 +236,20 @@ func (s *ClientSynchronizer) Sync() error {
 					continue
 				}
 				lastKnownBlock := header.Number.Uint64()
-				if lastBlockSynced.BlockNumber == lastKnownBlock && !s.synced {
+				if lastBlockSynced.BlockNumber > lastKnownBlock {
+					// The provider may answer from a node behind the one that returned the synced blocks
+					if lastKnownBlock, err = s.waitLatestBlock(lastBlockSynced.BlockNumber); err != nil && !errors.Is(err, wait.ErrTimeout) {
+						log.Warnf("networkID: %d, error getting latest block from. Error: %s", s.networkID, err.Error())
+						continue
+					}
+				}
+				// The blocks synced beyond the confirmed ones, before the confirmations were set, are kept
+				confirmedBlock := s.confirmedBlock(lastKnownBlock)
+				if lastBlockSynced.BlockNumber >= confirmedBlock && lastBlockSynced.BlockNumber <= lastKnownBlock && !s.synced {
 					log.Infof("NetworkID %d Synced!", s.networkID)
-					waitDuration = s.cfg.SyncInterval.Duration
 					s.synced = true
+					s.notifySynced()
+					s.updateSyncStatus(confirmedBlock, confirmedBlock)
 					s.chSynced <- s.networkID
 				}
 				if lastBlockSynced.BlockNumber > lastKnownBlock {
@@ -171,11 +278,65 @@ func (s *ClientSynchronizer) Sync() error {
 	}
 }
 
+// waitLatestBlock returns the latest block of the network, checked again with the backoff of the retry profile
+// while it's behind the block number, so a provider answering from a node behind the others isn't taken for a
+// reorg of the network. The last block is returned with wait.ErrTimeout if it's still behind.
+func (s *ClientSynchronizer) waitLatestBlock(blockNumber uint64) (uint64, error) {
+	w := s.cfg.Retry.Waiter()
+	w.Clock = s.clock
+	return wait.Until(s.ctx, w, func(ctx context.Context) (uint64, bool, error) {
+		header, err := s.etherMan.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return 0, false, err
+		}
+		return header.Number.Uint64(), header.Number.Uint64() >= blockNumber, nil
+	})
+}
+
+// confirmedBlock returns the last block of the network with the confirmations.
+func (s *ClientSynchronizer) confirmedBlock(latestBlock uint64) uint64 {
+	if latestBlock < s.confirmations {
+		return 0
+	}
+	return latestBlock - s.confirmations
+}
+
+// syncInterval returns the delay before the next sync: none until the network is synced, then the SyncInterval
+// or the observed block time of the network if the adaptive interval is enabled.
+func (s *ClientSynchronizer) syncInterval() time.Duration {
+	if !s.synced {
+		return 0
+	}
+	return blocktime.Interval(s.cfg.AdaptiveInterval, s.networkID, s.cfg.SyncInterval.Duration)
+}
+
 // Stop function stops the synchronizer
 func (s *ClientSynchronizer) Stop() {
 	s.cancelCtx()
 }
 
+// NetworkID returns the networkID that is being synced
+func (s *ClientSynchronizer) NetworkID() uint {
+	return s.networkID
+}
+
+// SyncStatus returns if the initial synchronization is finished and the number of blocks
+// that the synced data is behind the latest known block of the network
+func (s *ClientSynchronizer) SyncStatus() (bool, uint64) {
+	lastKnownBlock := s.syncStatus.lastKnownBlock.Load()
+	lastSynced := s.syncStatus.lastSynced.Load()
+	if lastSynced >= lastKnownBlock {
+		return s.syncStatus.synced.Load(), 0
+	}
+	return s.syncStatus.synced.Load(), lastKnownBlock - lastSynced
+}
+
+func (s *ClientSynchronizer) updateSyncStatus(lastKnownBlock, lastSynced uint64) {
+	s.syncStatus.lastKnownBlock.Store(lastKnownBlock)
+	s.syncStatus.lastSynced.Store(lastSynced)
+	s.syncStatus.synced.Store(s.synced)
+}
+
 func (s *ClientSynchronizer) syncTrustedState() error {
 	lastBatchNumber, err := s.zkEVMClient.BatchNumber(s.ctx)
 	if err != nil {
@@ -194,24 +355,61 @@ func (s *ClientSynchronizer) syncTrustedState() error {
 			lastBatch.RollupExitRoot,
 		},
 	}
-	isUpdated, err := s.storage.AddTrustedGlobalExitRoot(s.ctx, ger, nil)
+	if s.bus == nil {
+		isUpdated, err := s.storage.AddTrustedGlobalExitRoot(s.ctx, ger, nil)
+		if err != nil {
+			log.Error("networkID: %d, error storing latest trusted globalExitRoot. Error: %w", s.networkID, err)
+			return err
+		}
+		if isUpdated {
+			s.exitRootEvents.Push(s.ctx, ger)
+		}
+		return nil
+	}
+	// The event is published in the db tx of the exit root, so it's never read if the exit root is rolled back
+	dbTx, err := s.storage.BeginDBTransaction(s.ctx)
 	if err != nil {
-		log.Error("networkID: %d, error storing latest trusted globalExitRoot. Error: %w", s.networkID, err)
+		log.Errorf("networkID: %d, error creating db transaction to store the trusted globalExitRoot. Error: %v", s.networkID, err)
+		return err
+	}
+	isUpdated, err := s.storage.AddTrustedGlobalExitRoot(s.ctx, ger, dbTx)
+	if err != nil {
+		log.Errorf("networkID: %d, error storing latest trusted globalExitRoot. Error: %v", s.networkID, err)
+		s.rollbackTrustedState(dbTx)
+		return err
+	}
+	if isUpdated {
+		err = s.bus.Publish(s.ctx, &eventbus.Event{Type: eventbus.GERUpdated, NetworkID: s.networkID, GlobalExitRoot: ger}, dbTx)
+		if err != nil {
+			log.Errorf("networkID: %d, error publishing the trusted globalExitRoot. Error: %v", s.networkID, err)
+			s.rollbackTrustedState(dbTx)
+			return err
+		}
+	}
+	if err := s.storage.Commit(s.ctx, dbTx); err != nil {
+		log.Errorf("networkID: %d, error committing the trusted globalExitRoot. Error: %v", s.networkID, err)
+		s.rollbackTrustedState(dbTx)
 		return err
 	}
 	if isUpdated {
-		s.chExitRootEvent <- ger
+		s.bus.Notify()
 	}
 	return nil
 }
 
+func (s *ClientSynchronizer) rollbackTrustedState(dbTx pgx.Tx) {
+	if err := s.storage.Rollback(s.ctx, dbTx); err != nil {
+		log.Errorf("networkID: %d, error rolling back the trusted globalExitRoot. Error: %v", s.networkID, err)
+	}
+}
+
 // This function syncs the node from a specific block to the latest
 func (s *ClientSynchronizer) syncBlocks(lastBlockSynced *etherman.Block) (*etherman.Block, error) {
 	// This function will read events fromBlockNum to latestEthBlock. Check reorg to be sure that everything is ok.
 	block, err := s.checkReorg(lastBlockSynced)
 	if err != nil {
 		log.Errorf("networkID: %d, error checking reorgs. Retrying... Err: %s", s.networkID, err.Error())
-		return lastBlockSynced, fmt.Errorf("networkID: %d, error checking reorgs", s.networkID)
+		return lastBlockSynced, fmt.Errorf("networkID: %d, error checking reorgs: %w", s.networkID, err)
 	}
 	if block != nil {
 		err = s.resetState(block.BlockNumber)
@@ -227,15 +425,24 @@ func (s *ClientSynchronizer) syncBlocks(lastBlockSynced *etherman.Block) (*ether
 	if err != nil {
 		return lastBlockSynced, err
 	}
-	lastKnownBlock := header.Number
+	blocktime.Observe(s.networkID, header.Number.Uint64(), header.Time)
+	lastKnownBlock := new(big.Int).SetUint64(s.confirmedBlock(header.Number.Uint64()))
 
 	var fromBlock uint64
 	if lastBlockSynced.BlockNumber > 0 {
 		fromBlock = lastBlockSynced.BlockNumber + 1
 	}
+	if s.confirmations > 0 && fromBlock > lastKnownBlock.Uint64() {
+		// The next block doesn't have the confirmations yet
+		return lastBlockSynced, nil
+	}
 
 	for {
 		toBlock := fromBlock + s.cfg.SyncChunkSize
+		if s.confirmations > 0 && toBlock > lastKnownBlock.Uint64() {
+			// The blocks without the confirmations are not read
+			toBlock = lastKnownBlock.Uint64()
+		}
 
 		log.Debugf("NetworkID: %d, Getting bridge info from block %d to block %d", s.networkID, fromBlock, toBlock)
 		// This function returns the rollup information contained in the ethereum blocks and an extra param called order.
@@ -261,10 +468,11 @@ func (s *ClientSynchronizer) syncBlocks(lastBlockSynced *etherman.Block) (*ether
 		if lastKnownBlock.Cmp(new(big.Int).SetUint64(toBlock)) < 1 {
 			if !s.synced {
 				log.Infof("NetworkID %d Synced!", s.networkID)
-				waitDuration = s.cfg.SyncInterval.Duration
 				s.synced = true
+				s.notifySynced()
 				s.chSynced <- s.networkID
 			}
+			s.updateSyncStatus(lastKnownBlock.Uint64(), lastKnownBlock.Uint64())
 			break
 		}
 		if len(blocks) == 0 { // If there is no events in the checked blocks range and lastKnownBlock > fromBlock.
@@ -288,6 +496,8 @@ func (s *ClientSynchronizer) syncBlocks(lastBlockSynced *etherman.Block) (*ether
 			log.Debugf("NetworkID: %d, Storing empty block. BlockNumber: %d. BlockHash: %s",
 				s.networkID, b.BlockNumber, b.BlockHash.String())
 		}
+		// The range is synced once its blocks are committed
+		s.updateSyncStatus(lastKnownBlock.Uint64(), toBlock)
 	}
 
 	return lastBlockSynced, nil
@@ -320,7 +530,12 @@ func (s *ClientSynchronizer) processBlockRange(blocks []etherman.Block, order ma
 		for _, element := range order[blocks[i].BlockHash] {
 			switch element.Name {
 			case etherman.GlobalExitRootsOrder:
-				err = s.processGlobalExitRoot(blocks[i].GlobalExitRoots[element.Pos], blockID, dbTx)
+				err = s.processGlobalExitRoot(blocks[i].GlobalExitRoots[element.Pos], &blocks[i], blockID, dbTx)
+				if err != nil {
+					return err
+				}
+			case etherman.RollupExitLeavesOrder:
+				err = s.processRollupExitLeaf(blocks[i].RollupExitLeaves[element.Pos], blockID, dbTx)
 				if err != nil {
 					return err
 				}
@@ -341,6 +556,29 @@ func (s *ClientSynchronizer) processBlockRange(blocks []etherman.Block, order ma
 				}
 			}
 		}
+		if s.cfg.PersistRawLogs {
+			for j := range blocks[i].RawLogs {
+				err = s.processRawLog(&blocks[i].RawLogs[j], blockID, dbTx)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		syncedAt := s.clock.Now()
+		if s.bus != nil {
+			err = s.publishBlock(&blocks[i], syncedAt, dbTx)
+			if err != nil {
+				log.Errorf("networkID: %d, error publishing the events of the block. BlockNumber: %d, err: %v",
+					s.networkID, blocks[i].BlockNumber, err)
+				rollbackErr := s.storage.Rollback(s.ctx, dbTx)
+				if rollbackErr != nil {
+					log.Errorf("networkID: %d, error rolling back state. BlockNumber: %d, rollbackErr: %v, err: %s",
+						s.networkID, blocks[i].BlockNumber, rollbackErr, err.Error())
+					return rollbackErr
+				}
+				return err
+			}
+		}
 		err = s.storage.Commit(s.ctx, dbTx)
 		if err != nil {
 			log.Errorf("networkID: %d, error committing state to store block. BlockNumber: %d, err: %v",
@@ -353,6 +591,16 @@ func (s *ClientSynchronizer) processBlockRange(blocks []etherman.Block, order ma
 			}
 			return err
 		}
+		if s.bus != nil {
+			s.bus.Notify()
+		} else {
+			s.notifyBlock(&blocks[i], syncedAt)
+		}
+		for j := range blocks[i].Tokens {
+			tokenWrapped := blocks[i].Tokens[j]
+			tokenWrapped.NetworkID = s.networkID
+			hooks.TokenWrapped(s.ctx, &tokenWrapped)
+		}
 	}
 	return nil
 }
@@ -399,6 +647,32 @@ func (s *ClientSynchronizer) resetState(blockNumber uint64) error {
 		}
 		return err
 	}
+	if s.networkID == 0 {
+		err = s.bridgeCtrl.ReorgL1InfoTree(s.ctx, dbTx)
+		if err != nil {
+			log.Errorf("networkID: %d, error resetting the L1 info tree. Error: %v", s.networkID, err)
+			rollbackErr := s.storage.Rollback(s.ctx, dbTx)
+			if rollbackErr != nil {
+				log.Errorf("networkID: %d, error rolling back state to store block. BlockNumber: %d, rollbackErr: %v, error : %s",
+					s.networkID, blockNumber, rollbackErr, err.Error())
+				return rollbackErr
+			}
+			return err
+		}
+	}
+	if s.bus != nil {
+		err = s.bus.Publish(s.ctx, &eventbus.Event{Type: eventbus.Reorg, NetworkID: s.networkID, BlockNumber: blockNumber}, dbTx)
+		if err != nil {
+			log.Errorf("networkID: %d, error publishing the reorg. Error: %v", s.networkID, err)
+			rollbackErr := s.storage.Rollback(s.ctx, dbTx)
+			if rollbackErr != nil {
+				log.Errorf("networkID: %d, error rolling back state to store block. BlockNumber: %d, rollbackErr: %v, error : %s",
+					s.networkID, blockNumber, rollbackErr, err.Error())
+				return rollbackErr
+			}
+			return err
+		}
+	}
 	err = s.storage.Commit(s.ctx, dbTx)
 	if err != nil {
 		log.Errorf("networkID: %d, error committing the resetted state. Error: %v", s.networkID, err)
@@ -410,10 +684,70 @@ func (s *ClientSynchronizer) resetState(blockNumber uint64) error {
 		}
 		return err
 	}
+	s.lastDeposit = nil
+	if s.bus != nil {
+		s.bus.Notify()
+	} else {
+		hooks.Reorg(s.ctx, s.networkID, blockNumber)
+	}
+	return nil
+}
 
+// publishBlock publishes the deposits and the claims of a synced block on the event bus.
+func (s *ClientSynchronizer) publishBlock(block *etherman.Block, syncedAt time.Time, dbTx pgx.Tx) error {
+	events := make([]*eventbus.Event, 0, len(block.Deposits)+len(block.Claims))
+	for j := range block.Deposits {
+		deposit := block.Deposits[j]
+		deposit.NetworkID = s.networkID
+		deposit.BlockTime, deposit.SyncedAt = block.ReceivedAt, syncedAt
+		events = append(events, &eventbus.Event{Type: eventbus.DepositIndexed, Deposit: &deposit})
+	}
+	for j := range block.Claims {
+		claim := block.Claims[j]
+		claim.NetworkID = s.networkID
+		claim.BlockTime, claim.SyncedAt = block.ReceivedAt, syncedAt
+		events = append(events, &eventbus.Event{Type: eventbus.ClaimConfirmed, Claim: &claim})
+	}
+	for _, event := range events {
+		event.NetworkID = s.networkID
+		if err := s.bus.Publish(s.ctx, event, dbTx); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// notifySynced makes the exit root latency stats measure the updates and the injections of the network from now on.
+func (s *ClientSynchronizer) notifySynced() {
+	if s.networkID == 0 {
+		gerlatency.L1Synced()
+	} else {
+		gerlatency.Synced(s.networkID)
+	}
+}
+
+// notifyBlock notifies the exit root latency stats and the hooks of the exit roots, the deposits and the claims of
+// a synced block, when there is no event bus.
+func (s *ClientSynchronizer) notifyBlock(block *etherman.Block, syncedAt time.Time) {
+	if s.networkID == 0 {
+		for _, ger := range block.GlobalExitRoots {
+			gerlatency.L1Updated(ger.GlobalExitRoot, block.ReceivedAt)
+		}
+	}
+	for j := range block.Deposits {
+		deposit := block.Deposits[j]
+		deposit.NetworkID = s.networkID
+		deposit.BlockTime, deposit.SyncedAt = block.ReceivedAt, syncedAt
+		hooks.DepositIndexed(s.ctx, &deposit)
+	}
+	for j := range block.Claims {
+		claim := block.Claims[j]
+		claim.NetworkID = s.networkID
+		claim.BlockTime, claim.SyncedAt = block.ReceivedAt, syncedAt
+		hooks.ClaimIndexed(s.ctx, &claim)
+	}
+}
+
 /*
 This function will check if there is a reorg.
 As input param needs the last ethereum block synced. Retrieve the block info from the blockchain
@@ -486,7 +820,7 @@ func (s *ClientSynchronizer) checkReorg(latestBlock *etherman.Block) (*etherman.
 	return nil, nil
 }
 
-func (s *ClientSynchronizer) processGlobalExitRoot(globalExitRoot etherman.GlobalExitRoot, blockID uint64, dbTx pgx.Tx) error {
+func (s *ClientSynchronizer) processGlobalExitRoot(globalExitRoot etherman.GlobalExitRoot, block *etherman.Block, blockID uint64, dbTx pgx.Tx) error {
 	// Store GlobalExitRoot
 	globalExitRoot.BlockID = blockID
 	err := s.storage.AddGlobalExitRoot(s.ctx, &globalExitRoot, dbTx)
@@ -500,9 +834,56 @@ func (s *ClientSynchronizer) processGlobalExitRoot(globalExitRoot etherman.Globa
 		}
 		return err
 	}
-	if s.l1RollupExitRoot != globalExitRoot.ExitRoots[1] {
+	// Every global exit root update in L1 is appended to the L1 info tree
+	err = s.bridgeCtrl.AddL1InfoTreeLeaf(s.ctx, &etherman.L1InfoTreeLeaf{
+		BlockID:        blockID,
+		BlockNumber:    block.BlockNumber,
+		GlobalExitRoot: globalExitRoot.GlobalExitRoot,
+		ParentHash:     block.ParentHash,
+		Timestamp:      block.ReceivedAt,
+	}, dbTx)
+	if err != nil {
+		log.Errorf("networkID: %d, error adding the GlobalExitRoot to the L1 info tree. BlockNumber: %d. Error: %v", s.networkID, globalExitRoot.BlockNumber, err)
+		rollbackErr := s.storage.Rollback(s.ctx, dbTx)
+		if rollbackErr != nil {
+			log.Errorf("networkID: %d, error rolling back state. BlockNumber: %d, rollbackErr: %v, error : %s",
+				s.networkID, globalExitRoot.BlockNumber, rollbackErr, err.Error())
+			return rollbackErr
+		}
+		return err
+	}
+	if s.bus != nil {
+		// The claim tx managers skip the exit roots that don't update the rollup exit root
+		err = s.bus.Publish(s.ctx, &eventbus.Event{Type: eventbus.GERUpdated, NetworkID: s.networkID, GlobalExitRoot: &globalExitRoot, BlockTime: block.ReceivedAt}, dbTx)
+		if err != nil {
+			log.Errorf("networkID: %d, error publishing the GlobalExitRoot. BlockNumber: %d. Error: %v", s.networkID, globalExitRoot.BlockNumber, err)
+			rollbackErr := s.storage.Rollback(s.ctx, dbTx)
+			if rollbackErr != nil {
+				log.Errorf("networkID: %d, error rolling back state. BlockNumber: %d, rollbackErr: %v, error : %s",
+					s.networkID, globalExitRoot.BlockNumber, rollbackErr, err.Error())
+				return rollbackErr
+			}
+			return err
+		}
+	} else if s.l1RollupExitRoot != globalExitRoot.ExitRoots[1] {
 		s.l1RollupExitRoot = globalExitRoot.ExitRoots[1]
-		s.chExitRootEvent <- &globalExitRoot
+		s.exitRootEvents.Push(s.ctx, &globalExitRoot)
+	}
+	return nil
+}
+
+func (s *ClientSynchronizer) processRollupExitLeaf(leaf etherman.RollupExitLeaf, blockID uint64, dbTx pgx.Tx) error {
+	leaf.BlockID = blockID
+	err := s.bridgeCtrl.AddRollupExitLeaf(s.ctx, &leaf, dbTx)
+	if err != nil {
+		log.Errorf("networkID: %d, error updating the leaf of the rollup %d in the rollup exit tree. BlockNumber: %d. Error: %v", s.networkID, leaf.RollupID, leaf.BlockNumber, err)
+		rollbackErr := s.storage.Rollback(s.ctx, dbTx)
+		if rollbackErr != nil {
+			log.Errorf("networkID: %d, error rolling back state. BlockNumber: %d, rollbackErr: %v, error : %s",
+				s.networkID, leaf.BlockNumber, rollbackErr, err.Error())
+			return rollbackErr
+		}
+		return err
 	}
 	return nil
 }
@@ -510,9 +891,25 @@ func (s *ClientSynchronizer) processGlobalExitRoot(globalExitRoot etherman.Globa
 func (s *ClientSynchronizer) processDeposit(deposit etherman.Deposit, blockID uint64, dbTx pgx.Tx) error {
 	deposit.BlockID = blockID
 	deposit.NetworkID = s.networkID
+	var trace *etherman.DepositTrace
+	if s.cfg.TraceDeposits {
+		trace = s.traceDeposit(deposit)
+	}
+	if s.cfg.AttributeDeposits || s.cfg.RecordDepositSenders {
+		origin := trace
+		if origin == nil {
+			origin = s.depositOrigin(deposit)
+		}
+		if origin != nil && s.cfg.AttributeDeposits {
+			deposit.Integration = attribute(origin.CallPath, s.integrations)
+		}
+		if origin != nil && s.cfg.RecordDepositSenders {
+			deposit.Sender, deposit.Depositor = origin.Sender(), origin.Originator
+		}
+	}
 	depositID, err := s.storage.AddDeposit(s.ctx, &deposit, dbTx)
 	if err != nil {
-		log.Errorf("networkID: %d, failed to store new deposit locally, BlockNumber: %d, Deposit: %+v err: %v", s.networkID, deposit.BlockNumber, deposit, err)
+		activity.For(s.networkID, deposit.DepositCount).Errorf("networkID: %d, failed to store new deposit locally, BlockNumber: %d, Deposit: %+v err: %v", s.networkID, deposit.BlockNumber, deposit, err)
 		rollbackErr := s.storage.Rollback(s.ctx, dbTx)
 		if rollbackErr != nil {
 			log.Errorf("networkID: %d, error rolling back state to store block. BlockNumber: %v, rollbackErr: %v, err: %s",
@@ -524,21 +921,75 @@ func (s *ClientSynchronizer) processDeposit(deposit etherman.Deposit, blockID ui
 
 	err = s.bridgeCtrl.AddDeposit(&deposit, depositID, dbTx)
 	if err != nil {
-		log.Errorf("networkID: %d, failed to store new deposit in the bridge tree, BlockNumber: %d, Deposit: %+v err: %v", s.networkID, deposit.BlockNumber, deposit, err)
+		activity.For(s.networkID, deposit.DepositCount).Errorf("networkID: %d, failed to store new deposit in the bridge tree, BlockNumber: %d, Deposit: %+v err: %v", s.networkID, deposit.BlockNumber, deposit, err)
 		rollbackErr := s.storage.Rollback(s.ctx, dbTx)
 		if rollbackErr != nil {
 			log.Errorf("networkID: %d, error rolling back state to store block. BlockNumber: %v, rollbackErr: %v, err: %s",
 				s.networkID, deposit.BlockNumber, rollbackErr, err.Error())
 			return rollbackErr
 		}
+		if errors.Is(err, gerror.ErrDepositGap) {
+			return s.repairDepositGap(deposit, err)
+		}
 		return err
 	}
+	s.checkDepositOrder(deposit)
+	s.lastDeposit = &depositPosition{blockNumber: deposit.BlockNumber, logIndex: deposit.LogIndex, depositCount: deposit.DepositCount}
+	if trace != nil {
+		return s.addDepositTrace(deposit, trace, dbTx)
+	}
 	return nil
 }
 
+// traceDeposit traces the originator and the call path of the deposit tx. The deposit is synced even if the
+// tx can't be traced, since the trace only enriches it, so it returns nil in that case.
+func (s *ClientSynchronizer) traceDeposit(deposit etherman.Deposit) *etherman.DepositTrace {
+	trace, err := s.etherMan.TraceDeposit(s.ctx, deposit.TxHash)
+	if err != nil {
+		activity.For(s.networkID, deposit.DepositCount).Warnf("networkID: %d, error tracing the deposit tx %s, it's synced without its trace: %v", s.networkID, deposit.TxHash.String(), err)
+		return nil
+	}
+	trace.NetworkID = s.networkID
+	trace.BlockID = deposit.BlockID
+	return trace
+}
+
+// addDepositTrace stores the trace of the deposit tx.
+func (s *ClientSynchronizer) addDepositTrace(deposit etherman.Deposit, trace *etherman.DepositTrace, dbTx pgx.Tx) error {
+	err := s.storage.AddDepositTrace(s.ctx, trace, dbTx)
+	if err != nil {
+		activity.For(s.networkID, deposit.DepositCount).Errorf("networkID: %d, failed to store the trace of the deposit tx %s, err: %v", s.networkID, deposit.TxHash.String(), err)
+		rollbackErr := s.storage.Rollback(s.ctx, dbTx)
+		if rollbackErr != nil {
+			log.Errorf("networkID: %d, error rolling back state to store block. BlockNumber: %v, rollbackErr: %v, err: %s",
+				s.networkID, deposit.BlockNumber, rollbackErr, err.Error())
+			return rollbackErr
+		}
+		return err
+	}
+	return nil
+}
+
+// depositOrigin returns the origin of the deposit tx known from the tx, without tracing it, to attribute the deposit
+// and record its senders, set like the trace of the deposit. The deposit is synced unattributed and without senders
+// if the tx can't be read.
+func (s *ClientSynchronizer) depositOrigin(deposit etherman.Deposit) *etherman.DepositTrace {
+	origin, err := s.etherMan.DepositOrigin(s.ctx, deposit.TxHash)
+	if err != nil {
+		activity.For(s.networkID, deposit.DepositCount).Warnf("networkID: %d, error reading the deposit tx %s, it's synced unattributed and without senders: %v", s.networkID, deposit.TxHash.String(), err)
+		return nil
+	}
+	origin.NetworkID = s.networkID
+	origin.BlockID = deposit.BlockID
+	return origin
+}
+
 func (s *ClientSynchronizer) processClaim(claim etherman.Claim, blockID uint64, dbTx pgx.Tx) error {
 	claim.BlockID = blockID
 	claim.NetworkID = s.networkID
+	if s.cfg.TraceMessageClaims && s.isMessageClaim(claim, dbTx) {
+		claim.MessageCall = s.traceMessageCall(claim)
+	}
 	err := s.storage.AddClaim(s.ctx, &claim, dbTx)
 	if err != nil {
 		log.Errorf("networkID: %d, error storing new Claim in Block:  %d, Claim: %+v, err: %v", s.networkID, claim.BlockNumber, claim, err)
@@ -553,6 +1004,33 @@ func (s *ClientSynchronizer) processClaim(claim etherman.Claim, blockID uint64,
 	return nil
 }
 
+// isMessageClaim checks if the claimed deposit is a message, so only the claims of the messages are traced. The claims
+// whose deposit isn't synced are traced too, the trace of an asset claim has no message call.
+func (s *ClientSynchronizer) isMessageClaim(claim etherman.Claim, dbTx pgx.Tx) bool {
+	deposit, err := s.storage.GetClaimedDeposit(s.ctx, claim.Index, s.networkID, claim.OriginalNetwork, claim.OriginalAddress, dbTx)
+	if err != nil {
+		if !errors.Is(err, gerror.ErrStorageNotFound) {
+			log.Warnf("networkID: %d, error getting the deposit of the claim %d, it's traced: %v", s.networkID, claim.Index, err)
+		}
+		return true
+	}
+	return deposit.LeafType == leafTypeMessage
+}
+
+// traceMessageCall returns the result of the call to the destination of a claimed message, empty for the asset
+// claims and when the claim tx can't be traced, so the claim is synced without it.
+func (s *ClientSynchronizer) traceMessageCall(claim etherman.Claim) string {
+	messageCall, err := s.etherMan.TraceMessageCall(s.ctx, claim.TxHash, claim.DestinationAddress, claim.BlockNumber)
+	if err != nil {
+		log.Warnf("networkID: %d, error tracing the claim tx %s, it's synced without its message call: %v", s.networkID, claim.TxHash.String(), err)
+		return ""
+	}
+	if messageCall == etherman.MessageCallReverted {
+		log.Warnf("networkID: %d, the message of the claim %d was claimed in the tx %s but its call reverted", s.networkID, claim.Index, claim.TxHash.String())
+	}
+	return messageCall
+}
+
 func (s *ClientSynchronizer) processTokenWrapped(tokenWrapped etherman.TokenWrapped, blockID uint64, dbTx pgx.Tx) error {
 	tokenWrapped.BlockID = blockID
 	tokenWrapped.NetworkID = s.networkID
@@ -569,3 +1047,18 @@ func (s *ClientSynchronizer) processTokenWrapped(tokenWrapped etherman.TokenWrap
 	}
 	return nil
 }
+
+func (s *ClientSynchronizer) processRawLog(vLog *types.Log, blockID uint64, dbTx pgx.Tx) error {
+	err := s.storage.AddRawLog(s.ctx, vLog, blockID, s.networkID, dbTx)
+	if err != nil {
+		log.Errorf("networkID: %d, error storing raw log in Block: %d, TxHash: %s, LogIndex: %d, err: %v", s.networkID, vLog.BlockNumber, vLog.TxHash.String(), vLog.Index, err)
+		rollbackErr := s.storage.Rollback(s.ctx, dbTx)
+		if rollbackErr != nil {
+			log.Errorf("networkID: %d, error rolling back state to store block. BlockNumber: %d, rollbackErr: %v, err: %s",
+				s.networkID, vLog.BlockNumber, rollbackErr, err.Error())
+			return rollbackErr
+		}
+		return err
+	}
+	return nil
+}
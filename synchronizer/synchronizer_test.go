@@ -2,16 +2,22 @@ package synchronizer
 
 import (
 	context "context"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-bridge-service/blocktime"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/eventbus"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
 	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
 	rpcTypes "github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -34,7 +40,8 @@ func TestSyncGer(t *testing.T) {
 		ctx := mock.MatchedBy(func(ctx context.Context) bool { return ctx != nil })
 		m.Etherman.On("GetNetworkID", ctx).Return(uint(0), nil)
 		m.Storage.On("GetLatestL1SyncedExitRoot", context.Background(), nil).Return(&etherman.GlobalExitRoot{}, gerror.ErrStorageNotFound)
-		chEvent := make(chan *etherman.GlobalExitRoot)
+		chEvent, err := queue.New[*etherman.GlobalExitRoot]("exit_roots", queue.Config{Policy: queue.PolicyBlock}, nil)
+		require.NoError(t, err)
 		chSynced := make(chan uint)
 		sync, err := NewSynchronizer(m.Storage, m.BridgeCtrl, m.Etherman, m.ZkEVMClient, genBlockNumber, chEvent, chSynced, cfg)
 		require.NoError(t, err)
@@ -42,7 +49,7 @@ func TestSyncGer(t *testing.T) {
 		go func() {
 			for {
 				select {
-				case <-chEvent:
+				case <-chEvent.C():
 					t.Log("New GER received")
 				case netID := <-chSynced:
 					t.Log("Synced networkID: ", netID)
@@ -121,6 +128,11 @@ func TestSyncGer(t *testing.T) {
 			Return(nil).
 			Once()
 
+		m.BridgeCtrl.
+			On("AddL1InfoTreeLeaf", ctx, &etherman.L1InfoTreeLeaf{BlockID: 1, GlobalExitRoot: globalExitRoot.GlobalExitRoot}, m.DbTx).
+			Return(nil).
+			Once()
+
 		m.Storage.
 			On("Commit", ctx, m.DbTx).
 			Run(func(args mock.Arguments) { sync.Stop() }).
@@ -171,5 +183,396 @@ func TestSyncGer(t *testing.T) {
 		sync := setupMocks(&m)
 		err := sync.Sync()
 		require.NoError(t, err)
+		synced, lag := sync.SyncStatus()
+		require.True(t, synced)
+		require.Equal(t, uint64(0), lag)
+	})
+}
+
+func TestAttribute(t *testing.T) {
+	router := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	portal := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	unknown := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	integrations, err := integrationsByAddress([]Integration{
+		{Name: "aggregator", Addresses: []common.Address{router}},
+		{Name: "portal", Addresses: []common.Address{portal}},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, etherman.DepositIntegrationDirect, attribute(nil, integrations))
+	require.Equal(t, "aggregator", attribute([]common.Address{router}, integrations))
+	// The first known contract of the path, the outermost one, is attributed
+	require.Equal(t, "portal", attribute([]common.Address{unknown, portal, router}, integrations))
+	require.Equal(t, etherman.DepositIntegrationContract, attribute([]common.Address{unknown}, integrations))
+	// A contract creation calling the bridge has the zero address as the destination of the tx
+	require.Equal(t, etherman.DepositIntegrationContract, attribute([]common.Address{{}}, integrations))
+
+	_, err = integrationsByAddress([]Integration{{Name: etherman.DepositIntegrationDirect, Addresses: []common.Address{router}}})
+	require.Error(t, err)
+	_, err = integrationsByAddress([]Integration{{Addresses: []common.Address{router}}})
+	require.Error(t, err)
+	_, err = integrationsByAddress([]Integration{
+		{Name: "aggregator", Addresses: []common.Address{router}},
+		{Name: "portal", Addresses: []common.Address{router}},
 	})
+	require.Error(t, err)
+}
+
+func TestRecordDepositSenders(t *testing.T) {
+	m := mocks{
+		Etherman:   newEthermanMock(t),
+		BridgeCtrl: newBridgectrlMock(t),
+		Storage:    newStorageMock(t),
+		DbTx:       newDbTxMock(t),
+	}
+	user := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	router := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	s := &ClientSynchronizer{etherMan: m.Etherman, storage: m.Storage, bridgeCtrl: m.BridgeCtrl, ctx: context.Background(), cfg: Config{RecordDepositSenders: true}}
+	deposit := etherman.Deposit{DepositCount: 5, Amount: big.NewInt(1), TxHash: common.Hash{1}}
+
+	// The deposit made by a router contract on behalf of the user
+	origin := &etherman.DepositTrace{Originator: user, CallPath: []common.Address{router}}
+	m.Etherman.On("DepositOrigin", mock.Anything, deposit.TxHash).Return(origin, nil).Once()
+	m.Storage.On("AddDeposit", mock.Anything, mock.MatchedBy(func(d *etherman.Deposit) bool {
+		return d.Sender == router && d.Depositor == user && d.Integration == ""
+	}), m.DbTx).Return(uint64(1), nil).Once()
+	m.BridgeCtrl.On("AddDeposit", mock.Anything, uint64(1), m.DbTx).Return(nil).Once()
+	require.NoError(t, s.processDeposit(deposit, 1, m.DbTx))
+	// The origin read from the tx is set like the trace of the deposit
+	require.Equal(t, uint64(1), origin.BlockID)
+
+	// The tx calling the bridge is the msg.sender, and the deposit is synced without senders if the tx can't be read
+	m.Etherman.On("DepositOrigin", mock.Anything, deposit.TxHash).Return(&etherman.DepositTrace{Originator: user, CallPath: []common.Address{}}, nil).Once()
+	m.Storage.On("AddDeposit", mock.Anything, mock.MatchedBy(func(d *etherman.Deposit) bool {
+		return d.Sender == user && d.Depositor == user
+	}), m.DbTx).Return(uint64(2), nil).Once()
+	m.BridgeCtrl.On("AddDeposit", mock.Anything, uint64(2), m.DbTx).Return(nil).Once()
+	require.NoError(t, s.processDeposit(deposit, 1, m.DbTx))
+	m.Etherman.On("DepositOrigin", mock.Anything, deposit.TxHash).Return(nil, errors.New("not found")).Once()
+	m.Storage.On("AddDeposit", mock.Anything, mock.MatchedBy(func(d *etherman.Deposit) bool {
+		return d.Sender == (common.Address{}) && d.Depositor == (common.Address{})
+	}), m.DbTx).Return(uint64(3), nil).Once()
+	m.BridgeCtrl.On("AddDeposit", mock.Anything, uint64(3), m.DbTx).Return(nil).Once()
+	require.NoError(t, s.processDeposit(deposit, 1, m.DbTx))
+}
+
+func TestTraceMessageClaims(t *testing.T) {
+	m := mocks{
+		Etherman: newEthermanMock(t),
+		Storage:  newStorageMock(t),
+		DbTx:     newDbTxMock(t),
+	}
+	receiver := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	s := &ClientSynchronizer{etherMan: m.Etherman, storage: m.Storage, ctx: context.Background(), networkID: 1, cfg: Config{TraceMessageClaims: true}}
+	sender := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	claim := etherman.Claim{Index: 5, OriginalAddress: sender, Amount: big.NewInt(0), DestinationAddress: receiver, TxHash: common.Hash{1}, BlockNumber: 9}
+	m.Storage.On("GetClaimedDeposit", mock.Anything, uint(5), uint(1), uint(0), sender, m.DbTx).Return(&etherman.Deposit{LeafType: leafTypeMessage}, nil).Twice()
+
+	// The claim of a message whose call reverted, traced at its block, and a claim synced without its message call if
+	// it can't be traced
+	m.Etherman.On("TraceMessageCall", mock.Anything, claim.TxHash, receiver, uint64(9)).Return(etherman.MessageCallReverted, nil).Once()
+	m.Storage.On("AddClaim", mock.Anything, mock.MatchedBy(func(c *etherman.Claim) bool {
+		return c.MessageCall == etherman.MessageCallReverted && c.NetworkID == 1
+	}), m.DbTx).Return(nil).Once()
+	require.NoError(t, s.processClaim(claim, 1, m.DbTx))
+	m.Etherman.On("TraceMessageCall", mock.Anything, claim.TxHash, receiver, uint64(9)).Return("", errors.New("method not found")).Once()
+	m.Storage.On("AddClaim", mock.Anything, mock.MatchedBy(func(c *etherman.Claim) bool {
+		return c.MessageCall == ""
+	}), m.DbTx).Return(nil).Twice()
+	require.NoError(t, s.processClaim(claim, 1, m.DbTx))
+
+	// The claims of the assets aren't traced
+	asset := claim
+	asset.Index = 6
+	m.Storage.On("GetClaimedDeposit", mock.Anything, uint(6), uint(1), uint(0), sender, m.DbTx).Return(&etherman.Deposit{}, nil).Once()
+	require.NoError(t, s.processClaim(asset, 1, m.DbTx))
+
+	// The claims whose deposit isn't synced are traced
+	unknown := claim
+	unknown.Index = 7
+	m.Storage.On("GetClaimedDeposit", mock.Anything, uint(7), uint(1), uint(0), sender, m.DbTx).Return(nil, gerror.ErrStorageNotFound).Once()
+	m.Etherman.On("TraceMessageCall", mock.Anything, claim.TxHash, receiver, uint64(9)).Return(etherman.MessageCallSucceeded, nil).Once()
+	m.Storage.On("AddClaim", mock.Anything, mock.MatchedBy(func(c *etherman.Claim) bool {
+		return c.MessageCall == etherman.MessageCallSucceeded
+	}), m.DbTx).Return(nil).Once()
+	require.NoError(t, s.processClaim(unknown, 1, m.DbTx))
+}
+
+func TestQuorumZkEVMClient(t *testing.T) {
+	ctx := context.Background()
+	number := big.NewInt(7)
+	batch := &rpcTypes.Batch{
+		GlobalExitRoot:  common.HexToHash("0x01"),
+		MainnetExitRoot: common.HexToHash("0x02"),
+		RollupExitRoot:  common.HexToHash("0x03"),
+	}
+	forged := *batch
+	forged.GlobalExitRoot = common.HexToHash("0x04")
+	newClient := func(threshold uint, others ...*rpcTypes.Batch) *QuorumZkEVMClient {
+		main := newZkEVMClientMock(t)
+		main.On("BatchByNumber", ctx, number).Return(batch, nil)
+		c := &QuorumZkEVMClient{main: main, threshold: threshold}
+		for _, other := range others {
+			client := newZkEVMClientMock(t)
+			if other != nil {
+				client.On("BatchByNumber", ctx, number).Return(other, nil).Maybe()
+			} else {
+				client.On("BatchByNumber", ctx, number).Return(nil, gerror.ErrNotFound).Maybe()
+			}
+			c.others = append(c.others, client)
+			c.urls = append(c.urls, "http://provider")
+		}
+		return c
+	}
+
+	res, err := newClient(0).BatchByNumber(ctx, number)
+	require.NoError(t, err)
+	require.Equal(t, batch, res)
+
+	res, err = newClient(2, &forged, batch).BatchByNumber(ctx, number)
+	require.NoError(t, err)
+	require.Equal(t, batch, res)
+
+	// Behind the main provider or with other exit roots
+	_, err = newClient(2, nil, &forged).BatchByNumber(ctx, number)
+	require.ErrorIs(t, err, gerror.ErrQuorumNotReached)
+
+	// A lagging provider returns no batch and no error
+	lagging := newZkEVMClientMock(t)
+	lagging.On("BatchByNumber", ctx, number).Return(nil, nil)
+	c := newClient(2, batch)
+	c.others = append([]zkEVMClientInterface{lagging}, c.others...)
+	c.urls = append([]string{"http://lagging"}, c.urls...)
+	res, err = c.BatchByNumber(ctx, number)
+	require.NoError(t, err)
+	require.Equal(t, batch, res)
+	c.others = c.others[:1]
+	_, err = c.BatchByNumber(ctx, number)
+	require.ErrorIs(t, err, gerror.ErrQuorumNotReached)
+
+	// The main provider returns no batch and no error
+	main := newZkEVMClientMock(t)
+	main.On("BatchByNumber", ctx, number).Return(nil, nil)
+	_, err = (&QuorumZkEVMClient{main: main, threshold: 1}).BatchByNumber(ctx, number)
+	require.ErrorIs(t, err, gerror.ErrNotFound)
+}
+
+func TestSyncInterval(t *testing.T) {
+	const networkID = 1000
+	s := &ClientSynchronizer{
+		networkID: networkID,
+		cfg: Config{
+			SyncInterval:     cfgTypes.NewDuration(5 * time.Second),
+			AdaptiveInterval: blocktime.Config{Enabled: true, MinInterval: cfgTypes.NewDuration(time.Second), MaxInterval: cfgTypes.NewDuration(15 * time.Second)},
+		},
+	}
+	// The initial sync doesn't wait between the syncs
+	require.Equal(t, time.Duration(0), s.syncInterval())
+	// The SyncInterval is used until the block time is measured
+	s.synced = true
+	require.Equal(t, 5*time.Second, s.syncInterval())
+	blocktime.Observe(networkID, 10, 1700000000)
+	blocktime.Observe(networkID, 15, 1700000010)
+	require.Equal(t, 2*time.Second, s.syncInterval())
+	s.cfg.AdaptiveInterval.Enabled = false
+	require.Equal(t, 5*time.Second, s.syncInterval())
+}
+
+func TestWaitLatestBlock(t *testing.T) {
+	ctx := context.Background()
+	m := &mocks{Etherman: newEthermanMock(t)}
+	clock := wait.NewFakeClock(time.Unix(0, 0))
+	s := &ClientSynchronizer{
+		etherMan: m.Etherman,
+		ctx:      ctx,
+		clock:    clock,
+		cfg:      Config{Retry: etherman.RetryConfig{Profile: etherman.RetryProfileAggressive, Attempts: 3}},
+	}
+	header := func(number int64) *types.Header { return &types.Header{Number: big.NewInt(number)} }
+
+	// The provider catches up with the synced block
+	m.Etherman.On("HeaderByNumber", ctx, mock.Anything).Return(header(9), nil).Once()
+	m.Etherman.On("HeaderByNumber", ctx, mock.Anything).Return(header(11), nil).Once()
+	latest, err := s.waitLatestBlock(10)
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), latest)
+	require.Equal(t, 500*time.Millisecond, clock.Slept())
+
+	// The provider is still behind after the attempts of the profile
+	m.Etherman.On("HeaderByNumber", ctx, mock.Anything).Return(header(9), nil).Times(3)
+	latest, err = s.waitLatestBlock(10)
+	require.ErrorIs(t, err, wait.ErrTimeout)
+	require.Equal(t, uint64(9), latest)
+	require.Equal(t, 2*time.Second, clock.Slept())
+}
+
+func TestSyncConfirmations(t *testing.T) {
+	ctx := context.Background()
+	m := &mocks{Etherman: newEthermanMock(t)}
+	s := &ClientSynchronizer{
+		etherMan:      m.Etherman,
+		ctx:           ctx,
+		cfg:           Config{SyncChunkSize: 100},
+		chSynced:      make(chan uint, 1),
+		confirmations: 5,
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(100)})
+	lastBlockSynced := &etherman.Block{BlockNumber: 100, BlockHash: block.Hash(), ParentHash: block.ParentHash()}
+	m.Etherman.On("EthBlockByNumber", ctx, uint64(100)).Return(block, nil)
+
+	// The blocks are read up to the last one with the confirmations
+	m.Etherman.On("HeaderByNumber", ctx, mock.Anything).Return(&types.Header{Number: big.NewInt(110)}, nil).Once()
+	toBlock := uint64(105)
+	m.Etherman.On("GetRollupInfoByBlockRange", ctx, uint64(101), &toBlock).Return(nil, nil, nil).Once()
+	synced, err := s.syncBlocks(lastBlockSynced)
+	require.NoError(t, err)
+	require.Equal(t, lastBlockSynced, synced)
+	require.True(t, s.synced)
+	isSynced, behind := s.SyncStatus()
+	require.True(t, isSynced)
+	require.Equal(t, uint64(0), behind)
+
+	// The blocks without the confirmations are not read
+	m.Etherman.On("HeaderByNumber", ctx, mock.Anything).Return(&types.Header{Number: big.NewInt(103)}, nil).Once()
+	synced, err = s.syncBlocks(lastBlockSynced)
+	require.NoError(t, err)
+	require.Equal(t, lastBlockSynced, synced)
+
+	require.Equal(t, uint64(0), s.confirmedBlock(3))
+}
+
+func TestSyncStatusAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	m := &mocks{Etherman: newEthermanMock(t), Storage: newStorageMock(t), DbTx: newDbTxMock(t)}
+	s := &ClientSynchronizer{
+		etherMan: m.Etherman,
+		storage:  m.Storage,
+		ctx:      ctx,
+		clock:    wait.NewFakeClock(time.Unix(1700000000, 0)),
+		cfg:      Config{SyncChunkSize: 100},
+		chSynced: make(chan uint, 1),
+	}
+	s.updateSyncStatus(300, 100)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(100)})
+	lastBlockSynced := &etherman.Block{BlockNumber: 100, BlockHash: block.Hash(), ParentHash: block.ParentHash()}
+	m.Etherman.On("EthBlockByNumber", ctx, uint64(100)).Return(block, nil).Once()
+	m.Etherman.On("HeaderByNumber", ctx, mock.Anything).Return(&types.Header{Number: big.NewInt(300)}, nil).Once()
+	toBlock := uint64(201)
+	m.Etherman.On("GetRollupInfoByBlockRange", ctx, uint64(101), &toBlock).Return(nil, nil, nil).Once()
+	m.Etherman.On("EthBlockByNumber", ctx, uint64(201)).Return(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(201)}), nil).Once()
+
+	// The empty block of the range fails to be committed, so the status keeps the last committed block
+	m.Storage.On("BeginDBTransaction", ctx).Return(m.DbTx, nil).Once()
+	m.Storage.On("AddBlock", ctx, mock.Anything, m.DbTx).Return(uint64(1), nil).Once()
+	m.Storage.On("Commit", ctx, m.DbTx).Return(errors.New("commit failed")).Once()
+	m.Storage.On("Rollback", ctx, m.DbTx).Return(nil).Once()
+	synced, err := s.syncBlocks(lastBlockSynced)
+	require.Error(t, err)
+	require.Equal(t, lastBlockSynced, synced)
+	isSynced, behind := s.SyncStatus()
+	require.False(t, isSynced)
+	require.Equal(t, uint64(200), behind)
+}
+
+// busRecorder records the events published on the event bus and the notifications.
+type busRecorder struct {
+	events   []*eventbus.Event
+	txs      []pgx.Tx
+	notified int
+}
+
+func (b *busRecorder) Publish(_ context.Context, event *eventbus.Event, dbTx pgx.Tx) error {
+	b.events = append(b.events, event)
+	b.txs = append(b.txs, dbTx)
+	return nil
+}
+
+func (b *busRecorder) Notify() {
+	b.notified++
+}
+
+func TestPublishBlock(t *testing.T) {
+	m := mocks{
+		BridgeCtrl: newBridgectrlMock(t),
+		Storage:    newStorageMock(t),
+		DbTx:       newDbTxMock(t),
+	}
+	bus := &busRecorder{}
+	receivedAt := time.Unix(1700000000, 0)
+	clock := wait.NewFakeClock(receivedAt.Add(time.Minute))
+	s := &ClientSynchronizer{storage: m.Storage, bridgeCtrl: m.BridgeCtrl, ctx: context.Background(), networkID: 0, clock: clock}
+	s.SetEventBus(bus)
+	ger := etherman.GlobalExitRoot{ExitRoots: []common.Hash{{1}, {2}}, GlobalExitRoot: common.Hash{3}}
+	block := etherman.Block{
+		BlockHash:       common.Hash{4},
+		BlockNumber:     10,
+		ReceivedAt:      receivedAt,
+		GlobalExitRoots: []etherman.GlobalExitRoot{ger},
+		Deposits:        []etherman.Deposit{{DepositCount: 5, Amount: big.NewInt(1)}},
+		Claims:          []etherman.Claim{{Index: 2, Amount: big.NewInt(1)}},
+	}
+	order := map[common.Hash][]etherman.Order{block.BlockHash: {
+		{Name: etherman.GlobalExitRootsOrder, Pos: 0},
+		{Name: etherman.DepositsOrder, Pos: 0},
+		{Name: etherman.ClaimsOrder, Pos: 0},
+	}}
+	m.Storage.On("BeginDBTransaction", mock.Anything).Return(m.DbTx, nil).Once()
+	m.Storage.On("AddBlock", mock.Anything, mock.Anything, m.DbTx).Return(uint64(1), nil).Once()
+	m.Storage.On("AddGlobalExitRoot", mock.Anything, mock.Anything, m.DbTx).Return(nil).Once()
+	m.BridgeCtrl.On("AddL1InfoTreeLeaf", mock.Anything, mock.Anything, m.DbTx).Return(nil).Once()
+	m.Storage.On("AddDeposit", mock.Anything, mock.Anything, m.DbTx).Return(uint64(1), nil).Once()
+	m.BridgeCtrl.On("AddDeposit", mock.Anything, uint64(1), m.DbTx).Return(nil).Once()
+	m.Storage.On("AddClaim", mock.Anything, mock.Anything, m.DbTx).Return(nil).Once()
+	m.Storage.On("Commit", mock.Anything, m.DbTx).Run(func(mock.Arguments) {
+		// The events are published in the db tx of the block and notified once it's committed
+		require.Len(t, bus.events, 3)
+		require.Zero(t, bus.notified)
+	}).Return(nil).Once()
+
+	require.NoError(t, s.processBlockRange([]etherman.Block{block}, order))
+	require.Equal(t, 1, bus.notified)
+	require.Equal(t, []pgx.Tx{m.DbTx, m.DbTx, m.DbTx}, bus.txs)
+	require.Equal(t, eventbus.GERUpdated, bus.events[0].Type)
+	require.Equal(t, uint64(1), bus.events[0].GlobalExitRoot.BlockID)
+	require.Equal(t, receivedAt, bus.events[0].BlockTime)
+	require.Equal(t, eventbus.DepositIndexed, bus.events[1].Type)
+	require.Equal(t, uint(5), bus.events[1].Deposit.DepositCount)
+	require.Equal(t, receivedAt, bus.events[1].Deposit.BlockTime)
+	require.Equal(t, clock.Now(), bus.events[1].Deposit.SyncedAt)
+	require.Equal(t, eventbus.ClaimConfirmed, bus.events[2].Type)
+	require.Equal(t, uint(2), bus.events[2].Claim.Index)
+}
+
+func TestPublishTrustedState(t *testing.T) {
+	m := mocks{
+		Storage:     newStorageMock(t),
+		DbTx:        newDbTxMock(t),
+		ZkEVMClient: newZkEVMClientMock(t),
+	}
+	bus := &busRecorder{}
+	s := &ClientSynchronizer{storage: m.Storage, zkEVMClient: m.ZkEVMClient, ctx: context.Background()}
+	s.SetEventBus(bus)
+	batch := &rpcTypes.Batch{GlobalExitRoot: common.Hash{1}, MainnetExitRoot: common.Hash{2}, RollupExitRoot: common.Hash{3}}
+	m.ZkEVMClient.On("BatchNumber", mock.Anything).Return(uint64(1), nil)
+	m.ZkEVMClient.On("BatchByNumber", mock.Anything, big.NewInt(1)).Return(batch, nil)
+	m.Storage.On("BeginDBTransaction", mock.Anything).Return(m.DbTx, nil)
+
+	// The trusted exit root is published in its db tx, and notified once it's committed
+	m.Storage.On("AddTrustedGlobalExitRoot", mock.Anything, mock.Anything, m.DbTx).Return(true, nil).Once()
+	m.Storage.On("Commit", mock.Anything, m.DbTx).Run(func(mock.Arguments) {
+		require.Len(t, bus.events, 1)
+		require.Zero(t, bus.notified)
+	}).Return(nil).Once()
+	require.NoError(t, s.syncTrustedState())
+	require.Equal(t, 1, bus.notified)
+	require.Equal(t, []pgx.Tx{m.DbTx}, bus.txs)
+	require.Equal(t, eventbus.GERUpdated, bus.events[0].Type)
+	require.Equal(t, common.Hash{1}, bus.events[0].GlobalExitRoot.GlobalExitRoot)
+
+	// The exit root failing to be stored is rolled back without an event
+	m.Storage.On("AddTrustedGlobalExitRoot", mock.Anything, mock.Anything, m.DbTx).Return(false, errors.New("conn closed")).Once()
+	m.Storage.On("Rollback", mock.Anything, m.DbTx).Return(nil).Once()
+	require.Error(t, s.syncTrustedState())
+	require.Len(t, bus.events, 1)
+	require.Equal(t, 1, bus.notified)
 }
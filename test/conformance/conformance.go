@@ -0,0 +1,217 @@
+// Package conformance runs a battery of behavioral tests against any bridge API endpoint,
+// so forks and alternative implementations can verify they are compatible with the bridge service.
+package conformance
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/test/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	defaultPageSize  = 2
+	maxDepositsCheck = 100
+)
+
+// Config is the configuration of the conformance suite
+type Config struct {
+	// BridgeURL is the url of the bridge API under test
+	BridgeURL string
+	// Addresses are destination addresses with deposits and claims in the bridge under test
+	Addresses []string
+	// PageSize is the page size used to check the pagination. Defaults to 2
+	PageSize uint
+	// ObservationTime is the time between the two snapshots used to check the status transitions.
+	// 0 skips the status transitions checks
+	ObservationTime time.Duration
+}
+
+// Run runs all the conformance tests against the configured bridge API.
+func Run(t *testing.T, cfg Config) {
+	if cfg.PageSize == 0 {
+		cfg.PageSize = defaultPageSize
+	}
+	c := client.NewRestClient(cfg.BridgeURL)
+	t.Run("API version", func(t *testing.T) {
+		version, err := c.GetVersion()
+		require.NoError(t, err)
+		require.NotEmpty(t, version)
+	})
+	t.Run("Deposits pagination", func(t *testing.T) {
+		for _, addr := range cfg.Addresses {
+			checkDepositsPagination(t, c, addr, cfg.PageSize)
+		}
+	})
+	t.Run("Claims pagination", func(t *testing.T) {
+		for _, addr := range cfg.Addresses {
+			checkClaimsPagination(t, c, addr, cfg.PageSize)
+		}
+	})
+	t.Run("Deposit consistency", func(t *testing.T) {
+		for _, addr := range cfg.Addresses {
+			for _, deposit := range getDeposits(t, c, addr) {
+				checkDepositConsistency(t, c, deposit)
+			}
+		}
+	})
+	t.Run("Proof correctness", func(t *testing.T) {
+		for _, addr := range cfg.Addresses {
+			for _, deposit := range getDeposits(t, c, addr) {
+				if deposit.ReadyForClaim {
+					checkProof(t, c, deposit)
+				}
+			}
+		}
+	})
+	t.Run("Status transitions", func(t *testing.T) {
+		if cfg.ObservationTime == 0 {
+			t.Skip("ObservationTime not configured")
+		}
+		before := make(map[string][]*pb.Deposit)
+		for _, addr := range cfg.Addresses {
+			before[addr] = getDeposits(t, c, addr)
+		}
+		time.Sleep(cfg.ObservationTime)
+		for _, addr := range cfg.Addresses {
+			checkStatusTransitions(t, before[addr], getDeposits(t, c, addr))
+		}
+	})
+}
+
+func getDeposits(t *testing.T, c *client.RestClient, addr string) []*pb.Deposit {
+	deposits, _, err := c.GetBridges(addr, 0, maxDepositsCheck)
+	require.NoError(t, err)
+	return deposits
+}
+
+// checkDepositsPagination checks that walking the pages returns every deposit once
+// and the total count is stable between pages.
+func checkDepositsPagination(t *testing.T, c *client.RestClient, addr string, pageSize uint) {
+	all, totalCnt, err := c.GetBridges(addr, 0, maxDepositsCheck)
+	require.NoError(t, err)
+	require.LessOrEqual(t, uint64(len(all)), totalCnt, "address %s: more deposits than the total count", addr)
+
+	seen := make(map[string]bool)
+	var paged []*pb.Deposit
+	for offset := uint(0); offset < uint(len(all)); offset += pageSize {
+		page, pageTotalCnt, err := c.GetBridges(addr, offset, pageSize)
+		require.NoError(t, err)
+		require.Equal(t, totalCnt, pageTotalCnt, "address %s: total count changed between pages", addr)
+		require.LessOrEqual(t, uint(len(page)), pageSize, "address %s: page bigger than the limit", addr)
+		for _, deposit := range page {
+			key := depositKey(deposit)
+			require.False(t, seen[key], "address %s: deposit %s returned twice", addr, key)
+			seen[key] = true
+		}
+		paged = append(paged, page...)
+	}
+	require.Equal(t, len(all), len(paged), "address %s: pages don't return all the deposits", addr)
+	for i := range all {
+		require.Equal(t, depositKey(all[i]), depositKey(paged[i]), "address %s: unstable deposits order", addr)
+	}
+}
+
+// checkClaimsPagination checks that walking the pages returns every claim once
+// and the total count is stable between pages.
+func checkClaimsPagination(t *testing.T, c *client.RestClient, addr string, pageSize uint) {
+	all, totalCnt, err := c.GetClaims(addr, 0, maxDepositsCheck)
+	require.NoError(t, err)
+	require.LessOrEqual(t, uint64(len(all)), totalCnt, "address %s: more claims than the total count", addr)
+
+	var paged []*pb.Claim
+	for offset := uint(0); offset < uint(len(all)); offset += pageSize {
+		page, pageTotalCnt, err := c.GetClaims(addr, offset, pageSize)
+		require.NoError(t, err)
+		require.Equal(t, totalCnt, pageTotalCnt, "address %s: total count changed between pages", addr)
+		require.LessOrEqual(t, uint(len(page)), pageSize, "address %s: page bigger than the limit", addr)
+		paged = append(paged, page...)
+	}
+	require.Equal(t, len(all), len(paged), "address %s: pages don't return all the claims", addr)
+	for i := range all {
+		require.Equal(t, all[i].Index, paged[i].Index, "address %s: unstable claims order", addr)
+		require.Equal(t, all[i].NetworkId, paged[i].NetworkId, "address %s: unstable claims order", addr)
+	}
+}
+
+// checkDepositConsistency checks that the deposit returned by the list is the same than the single deposit endpoint.
+func checkDepositConsistency(t *testing.T, c *client.RestClient, deposit *pb.Deposit) {
+	single, err := c.GetBridge(deposit.NetworkId, deposit.DepositCnt)
+	require.NoError(t, err)
+	require.Equal(t, deposit.TxHash, single.TxHash, "deposit %s", depositKey(deposit))
+	require.Equal(t, deposit.Amount, single.Amount, "deposit %s", depositKey(deposit))
+	require.Equal(t, deposit.DestAddr, single.DestAddr, "deposit %s", depositKey(deposit))
+	require.Equal(t, deposit.Metadata, single.Metadata, "deposit %s", depositKey(deposit))
+	if deposit.ClaimTxHash != "" {
+		require.True(t, deposit.ReadyForClaim, "deposit %s is claimed but not ready for claim", depositKey(deposit))
+	}
+}
+
+// checkProof checks that the merkle proof of the deposit leads to the exit root of its network.
+func checkProof(t *testing.T, c *client.RestClient, deposit *pb.Deposit) {
+	proof, err := c.GetMerkleProof(deposit.NetworkId, deposit.DepositCnt)
+	require.NoError(t, err)
+	require.Equal(t, int(bridgectrl.KeyLen), len(proof.MerkleProof), "deposit %s: invalid proof length", depositKey(deposit))
+
+	amount, ok := new(big.Int).SetString(deposit.Amount, 10) //nolint:gomnd
+	require.True(t, ok, "deposit %s: invalid amount %s", depositKey(deposit), deposit.Amount)
+	leaf := bridgectrl.HashDeposit(&etherman.Deposit{
+		LeafType:           uint8(deposit.LeafType),
+		OriginalNetwork:    uint(deposit.OrigNet),
+		OriginalAddress:    common.HexToAddress(deposit.OrigAddr),
+		Amount:             amount,
+		DestinationNetwork: uint(deposit.DestNet),
+		DestinationAddress: common.HexToAddress(deposit.DestAddr),
+		Metadata:           common.FromHex(deposit.Metadata),
+	})
+	exitRoot := proof.MainExitRoot
+	if deposit.NetworkId != 0 {
+		exitRoot = proof.RollupExitRoot
+	}
+	root := calculateRoot(leaf, proof.MerkleProof, uint32(deposit.DepositCnt))
+	require.Equal(t, common.HexToHash(exitRoot), common.Hash(root), "deposit %s: the proof doesn't match the exit root", depositKey(deposit))
+}
+
+// checkStatusTransitions checks that a deposit never goes back to a previous status.
+func checkStatusTransitions(t *testing.T, before, after []*pb.Deposit) {
+	current := make(map[string]*pb.Deposit, len(after))
+	for _, deposit := range after {
+		current[depositKey(deposit)] = deposit
+	}
+	for _, prev := range before {
+		deposit, found := current[depositKey(prev)]
+		if !found {
+			continue
+		}
+		if prev.ReadyForClaim {
+			require.True(t, deposit.ReadyForClaim, "deposit %s is not ready for claim anymore", depositKey(prev))
+		}
+		if prev.ClaimTxHash != "" {
+			require.Equal(t, prev.ClaimTxHash, deposit.ClaimTxHash, "deposit %s claim tx changed", depositKey(prev))
+		}
+	}
+}
+
+func calculateRoot(leaf [bridgectrl.KeyLen]byte, proof []string, index uint32) [bridgectrl.KeyLen]byte {
+	node := leaf
+	for height, sibling := range proof {
+		s := common.HexToHash(sibling)
+		if index&(1<<height) != 0 {
+			node = bridgectrl.Hash(s, node)
+		} else {
+			node = bridgectrl.Hash(node, s)
+		}
+	}
+	return node
+}
+
+func depositKey(deposit *pb.Deposit) string {
+	return fmt.Sprintf("%d-%d", deposit.NetworkId, deposit.DepositCnt)
+}
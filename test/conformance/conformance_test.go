@@ -0,0 +1,45 @@
+package conformance
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConformance runs the conformance suite against the bridge API set in the
+// BRIDGE_CONFORMANCE_URL env var, with the comma separated addresses of BRIDGE_CONFORMANCE_ADDRESSES.
+func TestConformance(t *testing.T) {
+	url := os.Getenv("BRIDGE_CONFORMANCE_URL")
+	if url == "" {
+		t.Skip("BRIDGE_CONFORMANCE_URL not set")
+	}
+	var addrs []string
+	if v := os.Getenv("BRIDGE_CONFORMANCE_ADDRESSES"); v != "" {
+		addrs = strings.Split(v, ",")
+	}
+	Run(t, Config{
+		BridgeURL:       url,
+		Addresses:       addrs,
+		ObservationTime: 10 * time.Second, //nolint:gomnd
+	})
+}
+
+func TestCalculateRoot(t *testing.T) {
+	// The proof of the first leaf of an empty tree is made of the zero hashes
+	var (
+		leaf  [bridgectrl.KeyLen]byte
+		node  [bridgectrl.KeyLen]byte
+		proof []string
+	)
+	for height := 0; height < int(bridgectrl.KeyLen); height++ {
+		proof = append(proof, common.Hash(node).String())
+		node = bridgectrl.Hash(node, node)
+	}
+	root := calculateRoot(leaf, proof, 0)
+	require.Equal(t, common.HexToHash("0x27ae5ba08d7291c96c8cbddcc148bf48a6d68c7974b94356f53754ef6171d757"), common.Hash(root))
+}
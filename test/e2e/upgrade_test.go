@@ -0,0 +1,146 @@
+//go:build upgrade
+// +build upgrade
+
+package e2e
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/server"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/test/operations"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpgrade upgrades the bridge contract of L1 in the middle of the bridge flows, checking that the deposits
+// and claims are indexed in the same way before and after the upgrade.
+func TestUpgrade(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	opsCfg := &operations.Config{
+		Storage: db.Config{
+			Database: "postgres",
+			Name:     "test_db",
+			User:     "test_user",
+			Password: "test_password",
+			Host:     "localhost",
+			Port:     "5435",
+			MaxConns: 10,
+		},
+		BT: bridgectrl.Config{
+			Store:  "postgres",
+			Height: uint8(32),
+		},
+		BS: server.Config{
+			GRPCPort:         "9090",
+			HTTPPort:         "8080",
+			CacheSize:        100000,
+			DefaultPageLimit: 25,
+			MaxPageLimit:     100,
+			BridgeVersion:    "v1",
+			DB: db.Config{
+				Database: "postgres",
+				Name:     "test_db",
+				User:     "test_user",
+				Password: "test_password",
+				Host:     "localhost",
+				Port:     "5435",
+				MaxConns: 10,
+			},
+		},
+	}
+	opsman, err := operations.NewManager(ctx, opsCfg)
+	require.NoError(t, err)
+
+	tokenAddr := common.Address{} // This means is eth
+	amount := new(big.Int).SetUint64(1000000000000000000)
+
+	t.Run("L1 deposits across the upgrade", func(t *testing.T) {
+		destAddr := common.HexToAddress("0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65")
+		var destNetwork uint32 = 1
+		// Deposit with the current implementation
+		err := opsman.SendL1Deposit(ctx, tokenAddr, amount, destNetwork, &destAddr)
+		require.NoError(t, err)
+		deposits, err := opsman.GetBridgeInfoByDestAddr(ctx, &destAddr)
+		require.NoError(t, err)
+		require.Len(t, deposits, 1)
+		before := deposits[0]
+		err = opsman.CheckL2Claim(ctx, uint(before.DestNet), uint(before.DepositCnt))
+		require.NoError(t, err)
+
+		// Upgrade the bridge and wait until the service has synced the upgrade block
+		oldImplementation, err := opsman.GetBridgeImplementation(ctx, operations.L1)
+		require.NoError(t, err)
+		upgrade, err := opsman.UpgradeBridge(ctx, operations.L1)
+		require.NoError(t, err)
+		upgradeBlock := upgrade.Block
+		// The new implementation has the same code, so it's told apart by its address and the block it's set at
+		require.NotEqual(t, oldImplementation, upgrade.Implementation)
+		require.Equal(t, oldImplementation, upgrade.Previous)
+		require.False(t, upgrade.CodeChanged)
+		previous, err := opsman.GetBridgeImplementationAt(ctx, operations.L1, upgradeBlock-1)
+		require.NoError(t, err)
+		require.Equal(t, oldImplementation, previous)
+		t.Logf("L1 bridge redeployed from %s to %s at block %d", oldImplementation, upgrade.Implementation, upgradeBlock)
+		err = opsman.WaitBlockToBeSynced(ctx, 0, upgradeBlock)
+		require.NoError(t, err)
+
+		// Deposit with the new implementation. The deposit count continues from the one before the upgrade
+		err = opsman.SendL1Deposit(ctx, tokenAddr, amount, destNetwork, &destAddr)
+		require.NoError(t, err)
+		deposits, err = opsman.GetBridgeInfoByDestAddr(ctx, &destAddr)
+		require.NoError(t, err)
+		require.Len(t, deposits, 2)
+		after := deposits[0]
+		require.Equal(t, before.DepositCnt, deposits[1].DepositCnt)
+		require.Greater(t, after.DepositCnt, before.DepositCnt)
+		require.Greater(t, after.BlockNum, upgradeBlock)
+		require.Equal(t, before.NetworkId, after.NetworkId)
+		require.Equal(t, amount.String(), after.Amount)
+
+		// The proof of the new deposit is built from the tree of the deposits of both implementations
+		err = opsman.CheckL2Claim(ctx, uint(after.DestNet), uint(after.DepositCnt))
+		require.NoError(t, err)
+		balance, err := opsman.CheckAccountBalance(ctx, operations.L2, &destAddr)
+		require.NoError(t, err)
+		require.Equal(t, new(big.Int).Mul(amount, big.NewInt(2)), balance)
+	})
+
+	t.Run("L2 deposit claimed in the upgraded bridge", func(t *testing.T) {
+		destAddr := common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+		var destNetwork uint32 = 0
+		implementation, err := opsman.GetBridgeImplementation(ctx, operations.L1)
+		require.NoError(t, err)
+
+		initialBalance, err := opsman.CheckAccountBalance(ctx, operations.L1, &destAddr)
+		require.NoError(t, err)
+		err = opsman.SendL2Deposit(ctx, tokenAddr, amount, destNetwork, &destAddr)
+		require.NoError(t, err)
+		deposits, err := opsman.GetBridgeInfoByDestAddr(ctx, &destAddr)
+		require.NoError(t, err)
+		deposit := deposits[0]
+		require.Equal(t, uint32(1), deposit.NetworkId)
+
+		// Claim in L1 with the proof of the service, and check the claim event of the new implementation is indexed
+		smtProof, globalExitRoot, err := opsman.GetClaimData(ctx, uint(deposit.NetworkId), uint(deposit.DepositCnt))
+		require.NoError(t, err)
+		err = opsman.SendL1Claim(ctx, deposit, smtProof, globalExitRoot)
+		require.NoError(t, err)
+		err = opsman.CheckL2Claim(ctx, uint(deposit.DestNet), uint(deposit.DepositCnt))
+		require.NoError(t, err)
+		balance, err := opsman.CheckAccountBalance(ctx, operations.L1, &destAddr)
+		require.NoError(t, err)
+		require.True(t, balance.Cmp(initialBalance) > 0)
+
+		current, err := opsman.GetBridgeImplementation(ctx, operations.L1)
+		require.NoError(t, err)
+		require.Equal(t, implementation, current)
+	})
+}
@@ -0,0 +1,282 @@
+// Package mockaggregator implements a failure-injection proxy placed between the zkevm node and the L1 network.
+// It forwards every JSON-RPC call to L1 but can be told to delay or skip the batch verification txs sent by
+// the aggregator, so the tests can deterministically exercise the deposits waiting for verification.
+package mockaggregator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevm"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Mode defines what the proxy does with the verification txs
+type Mode string
+
+const (
+	// ModePass forwards the verification txs to L1
+	ModePass Mode = "pass"
+	// ModeDelay holds the verification txs during the configured delay before forwarding them to L1
+	ModeDelay Mode = "delay"
+	// ModeSkip drops the verification txs, answering the tx hash as if they were accepted
+	ModeSkip Mode = "skip"
+
+	// ControlPath is the path of the endpoint used to get and set the proxy mode
+	ControlPath = "/control"
+
+	sendRawTxMethod = "eth_sendRawTransaction"
+	selectorLen     = 4
+)
+
+var verificationMethods = []string{"verifyBatches", "verifyBatchesTrustedAggregator"}
+
+// Control is the body of the control endpoint
+type Control struct {
+	Mode  Mode   `json:"mode"`
+	Delay string `json:"delay,omitempty"`
+}
+
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+}
+
+// Proxy is the JSON-RPC proxy in front of L1
+type Proxy struct {
+	upstream   string
+	client     *http.Client
+	selectors  map[string]bool
+	mu         sync.RWMutex
+	mode       Mode
+	delay      time.Duration
+	intercepts uint64
+}
+
+// NewProxy creates a proxy forwarding the calls to the upstream url in pass mode.
+func NewProxy(upstream string) (*Proxy, error) {
+	a, err := polygonzkevm.PolygonzkevmMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	selectors := make(map[string]bool, len(verificationMethods))
+	for _, name := range verificationMethods {
+		method, ok := a.Methods[name]
+		if !ok {
+			return nil, fmt.Errorf("method %s not found in the polygonzkevm abi", name)
+		}
+		selectors[string(method.ID)] = true
+	}
+	return &Proxy{
+		upstream:  upstream,
+		client:    &http.Client{},
+		selectors: selectors,
+		mode:      ModePass,
+	}, nil
+}
+
+// SetMode changes what the proxy does with the verification txs. The delay is only used in delay mode.
+func (p *Proxy) SetMode(mode Mode, delay time.Duration) error {
+	switch mode {
+	case ModePass, ModeSkip:
+	case ModeDelay:
+		if delay <= 0 {
+			return fmt.Errorf("delay mode requires a positive delay")
+		}
+	default:
+		return fmt.Errorf("unknown mode %s", mode)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mode = mode
+	p.delay = delay
+	log.Infof("mock aggregator mode set to %s, delay %s", mode, delay)
+	return nil
+}
+
+// Mode returns the current mode and delay.
+func (p *Proxy) Mode() (Mode, time.Duration) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.mode, p.delay
+}
+
+// Intercepts returns the number of verification txs delayed or skipped.
+func (p *Proxy) Intercepts() uint64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.intercepts
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == ControlPath {
+		p.serveControl(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req rpcRequest
+	// Batch requests and unparsable bodies are forwarded as they are
+	if json.Unmarshal(body, &req) == nil && req.Method == sendRawTxMethod && len(req.Params) > 0 {
+		if tx := p.verificationTx(req.Params[0]); tx != nil {
+			mode, delay := p.Mode()
+			if mode != ModePass {
+				p.intercept(w, req, tx, body, mode, delay)
+				return
+			}
+		}
+	}
+	p.forward(w, r, body)
+}
+
+// verificationTx returns the decoded tx if the raw tx param is a batch verification.
+func (p *Proxy) verificationTx(param json.RawMessage) *types.Transaction {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(param, &raw); err != nil {
+		return nil
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil
+	}
+	if len(tx.Data()) < selectorLen || !p.selectors[string(tx.Data()[:selectorLen])] {
+		return nil
+	}
+	return tx
+}
+
+func (p *Proxy) intercept(w http.ResponseWriter, req rpcRequest, tx *types.Transaction, body []byte, mode Mode, delay time.Duration) {
+	p.mu.Lock()
+	p.intercepts++
+	p.mu.Unlock()
+	if mode == ModeDelay {
+		log.Infof("mock aggregator: delaying verification tx %s for %s", tx.Hash().String(), delay)
+		go func() {
+			time.Sleep(delay)
+			if err := p.send(context.Background(), body); err != nil {
+				log.Errorf("mock aggregator: error forwarding the delayed verification tx %s: %v", tx.Hash().String(), err)
+			}
+		}()
+	} else {
+		log.Infof("mock aggregator: skipping verification tx %s", tx.Hash().String())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(rpcResponse{JSONRPC: req.JSONRPC, ID: req.ID, Result: tx.Hash()})
+	if err != nil {
+		log.Error("mock aggregator: error writing the response: ", err)
+	}
+}
+
+func (p *Proxy) forward(w http.ResponseWriter, r *http.Request, body []byte) {
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, p.upstream, bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	req.Header = r.Header.Clone()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		log.Error("mock aggregator: error copying the upstream response: ", err)
+	}
+}
+
+func (p *Proxy) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.upstream, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (p *Proxy) serveControl(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var c Control
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var delay time.Duration
+		if c.Delay != "" {
+			var err error
+			if delay, err = time.ParseDuration(c.Delay); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if err := p.SetMode(c.Mode, delay); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	mode, delay := p.Mode()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Control{Mode: mode, Delay: delay.String()}); err != nil {
+		log.Error("mock aggregator: error writing the response: ", err)
+	}
+}
+
+// SetMode calls the control endpoint of the proxy running at url to change its mode.
+func SetMode(ctx context.Context, url string, mode Mode, delay time.Duration) error {
+	c := Control{Mode: mode}
+	if delay > 0 {
+		c.Delay = delay.String()
+	}
+	body, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+ControlPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("error setting the mock aggregator mode: %s", string(bytes.TrimSpace(msg)))
+	}
+	return nil
+}
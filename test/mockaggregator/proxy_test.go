@@ -0,0 +1,101 @@
+package mockaggregator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeL1 struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+func (f *fakeL1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	f.mu.Lock()
+	f.methods = append(f.methods, req.Method)
+	f.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(rpcResponse{JSONRPC: req.JSONRPC, ID: req.ID, Result: "upstream"})
+}
+
+func (f *fakeL1) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.methods)
+}
+
+func rawTx(t *testing.T, data []byte) (string, common.Hash) {
+	tx := types.NewTransaction(0, common.HexToAddress("0x610178dA211FEF7D417bC0e6FeD39F05609AD788"), big.NewInt(0), 21000, big.NewInt(1), data)
+	b, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return hexutil.Encode(b), tx.Hash()
+}
+
+func call(t *testing.T, url, method, param string) string {
+	body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":["` + param + `"]}`
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var res struct {
+		Result string `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(b, &res))
+	return res.Result
+}
+
+func TestProxy(t *testing.T) {
+	l1 := &fakeL1{}
+	upstream := httptest.NewServer(l1)
+	defer upstream.Close()
+	proxy, err := NewProxy(upstream.URL)
+	require.NoError(t, err)
+	srv := httptest.NewServer(proxy)
+	defer srv.Close()
+
+	a, err := polygonzkevm.PolygonzkevmMetaData.GetAbi()
+	require.NoError(t, err)
+	verifyTx, verifyHash := rawTx(t, append(a.Methods["verifyBatchesTrustedAggregator"].ID, make([]byte, 32)...))
+	otherTx, _ := rawTx(t, a.Methods["sequenceBatches"].ID)
+
+	// Pass mode forwards everything
+	require.Equal(t, "upstream", call(t, srv.URL, sendRawTxMethod, verifyTx))
+	require.Equal(t, 1, l1.calls())
+
+	// Skip mode answers the hash without forwarding the verification
+	require.NoError(t, SetMode(context.Background(), srv.URL, ModeSkip, 0))
+	require.Equal(t, verifyHash.String(), call(t, srv.URL, sendRawTxMethod, verifyTx))
+	require.Equal(t, 1, l1.calls())
+	require.Equal(t, "upstream", call(t, srv.URL, sendRawTxMethod, otherTx))
+	require.Equal(t, "upstream", call(t, srv.URL, "eth_blockNumber", ""))
+	require.Equal(t, 3, l1.calls())
+	require.Equal(t, uint64(1), proxy.Intercepts())
+
+	// Delay mode answers the hash and forwards the verification later
+	require.NoError(t, SetMode(context.Background(), srv.URL, ModeDelay, 100*time.Millisecond))
+	require.Equal(t, verifyHash.String(), call(t, srv.URL, sendRawTxMethod, verifyTx))
+	require.Equal(t, 3, l1.calls())
+	require.Eventually(t, func() bool { return l1.calls() == 4 }, time.Second, 10*time.Millisecond)
+
+	require.Error(t, SetMode(context.Background(), srv.URL, ModeDelay, 0))
+	require.Error(t, SetMode(context.Background(), srv.URL, "unknown", 0))
+	mode, delay := proxy.Mode()
+	require.Equal(t, ModeDelay, mode)
+	require.Equal(t, 100*time.Millisecond, delay)
+}
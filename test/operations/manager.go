@@ -2,6 +2,7 @@ package operations
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"os"
 	"os/exec"
@@ -12,7 +13,9 @@ import (
 	"github.com/0xPolygonHermez/zkevm-bridge-service/db"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/db/pgstorage"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/server"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/test/mockaggregator"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/utils"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
@@ -42,6 +45,8 @@ const (
 	l1NetworkURL = "http://localhost:8545"
 	l2NetworkURL = "http://localhost:8123"
 
+	mockAggregatorURL = "http://localhost:8546"
+
 	// MaticTokenAddress token address
 	MaticTokenAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3" //nolint:gosec
 	l1BridgeAddr      = "0xff0EE8ea08cEf5cb4322777F5CC3E8A584B8A4A0"
@@ -106,7 +111,7 @@ func NewManager(ctx context.Context, cfg *Config) (*Manager, error) {
 	if err != nil {
 		return nil, err
 	}
-	bt, err := bridgectrl.NewBridgeController(cfg.BT, []uint{0, 1}, pgst)
+	bt, err := bridgectrl.NewBridgeController(ctx, cfg.BT, []uint{0, 1}, pgst)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +123,7 @@ func NewManager(ctx context.Context, cfg *Config) (*Manager, error) {
 	if err != nil {
 		return nil, err
 	}
-	bService := server.NewBridgeService(cfg.BS, cfg.BT.Height, []uint{0, 1}, pgst)
+	bService := server.NewBridgeService(cfg.BS, cfg.BT.Height, []uint{0, 1}, pgst, nil)
 	opsman.storage = st.(StorageInterface)
 	opsman.bridgetree = bt
 	opsman.bridgeService = bService
@@ -130,10 +135,10 @@ func NewManager(ctx context.Context, cfg *Config) (*Manager, error) {
 
 // CheckL2Claim checks if the claim is already in the L2 network.
 func (m *Manager) CheckL2Claim(ctx context.Context, networkID, depositCnt uint) error {
-	return operations.Poll(defaultInterval, defaultDeadline, func() (bool, error) {
+	return wait.Poll(ctx, defaultInterval, defaultDeadline, func() (bool, error) {
 		_, err := m.storage.GetClaim(ctx, depositCnt, networkID, nil)
 		if err != nil {
-			if err == gerror.ErrStorageNotFound {
+			if errors.Is(err, gerror.ErrStorageNotFound) {
 				return false, nil
 			}
 			return false, err
@@ -153,7 +158,7 @@ func (m *Manager) SendL1Deposit(ctx context.Context, tokenAddr common.Address, a
 	}
 
 	orgExitRoot, err := m.storage.GetLatestExitRoot(ctx, false, nil)
-	if err != nil && err != gerror.ErrStorageNotFound {
+	if err != nil && !errors.Is(err, gerror.ErrStorageNotFound) {
 		return err
 	}
 
@@ -177,7 +182,7 @@ func (m *Manager) SendL2Deposit(ctx context.Context, tokenAddr common.Address, a
 	}
 
 	orgExitRoot, err := m.storage.GetLatestExitRoot(ctx, true, nil)
-	if err != nil && err != gerror.ErrStorageNotFound {
+	if err != nil && !errors.Is(err, gerror.ErrStorageNotFound) {
 		return err
 	}
 
@@ -205,7 +210,7 @@ func (m *Manager) SendL1BridgeMessage(ctx context.Context, destAddr common.Addre
 	}
 
 	orgExitRoot, err := m.storage.GetLatestExitRoot(ctx, true, nil)
-	if err != nil && err != gerror.ErrStorageNotFound {
+	if err != nil && !errors.Is(err, gerror.ErrStorageNotFound) {
 		return err
 	}
 
@@ -228,7 +233,7 @@ func (m *Manager) SendL2BridgeMessage(ctx context.Context, destAddr common.Addre
 	}
 
 	orgExitRoot, err := m.storage.GetLatestExitRoot(ctx, true, nil)
-	if err != nil && err != gerror.ErrStorageNotFound {
+	if err != nil && !errors.Is(err, gerror.ErrStorageNotFound) {
 		return err
 	}
 
@@ -254,6 +259,13 @@ func (m *Manager) Setup() error {
 	const t time.Duration = 3
 	time.Sleep(t * time.Second)
 
+	// Run the mock aggregator between the node and the network
+	err = m.startMockAggregator()
+	if err != nil {
+		log.Error("mock aggregator start failed")
+		return err
+	}
+
 	// Start prover container
 	err = m.startProver()
 	if err != nil {
@@ -331,7 +343,7 @@ func (m *Manager) AddFunds(ctx context.Context) error {
 	// Wait eth transfer to be mined
 	log.Infof("Waiting tx to be mined")
 	const txETHTransferTimeout = 5 * time.Second
-	err = WaitTxToBeMined(ctx, client.Client, signedTx, txETHTransferTimeout)
+	err = client.WaitTxToBeMined(ctx, signedTx, txETHTransferTimeout)
 	if err != nil {
 		return err
 	}
@@ -355,7 +367,7 @@ func (m *Manager) AddFunds(ctx context.Context) error {
 	// wait matic transfer to be mined
 	log.Infof("Waiting tx to be mined")
 	const txMaticTransferTimeout = 5 * time.Second
-	return WaitTxToBeMined(ctx, client.Client, tx, txMaticTransferTimeout)
+	return client.WaitTxToBeMined(ctx, tx, txMaticTransferTimeout)
 }
 
 // Teardown stops all the components.
@@ -401,6 +413,30 @@ func stopNetwork() error {
 	return runCmd(cmd)
 }
 
+func (m *Manager) startMockAggregator() error {
+	if err := stopMockAggregator(); err != nil {
+		return err
+	}
+	cmd := exec.Command(makeCmd, "run-mock-aggregator")
+	err := runCmd(cmd)
+	if err != nil {
+		return err
+	}
+	// Wait mock aggregator to be ready
+	return poll(defaultInterval, defaultDeadline, mockAggregatorUpCondition)
+}
+
+func stopMockAggregator() error {
+	cmd := exec.Command(makeCmd, "stop-mock-aggregator")
+	return runCmd(cmd)
+}
+
+// SetVerificationMode makes the mock aggregator pass, delay or skip the batch verifications sent to L1,
+// so the deposits can be kept waiting for verification.
+func (m *Manager) SetVerificationMode(ctx context.Context, mode mockaggregator.Mode, delay time.Duration) error {
+	return mockaggregator.SetMode(ctx, mockAggregatorURL, mode, delay)
+}
+
 func (m *Manager) startZKEVMNode() error {
 	if err := stopZKEVMNode(); err != nil {
 		return err
@@ -664,7 +700,7 @@ func (m *Manager) ApproveERC20(ctx context.Context, erc20Addr, bridgeAddr common
 // GetTokenWrapped get token wrapped info
 func (m *Manager) GetTokenWrapped(ctx context.Context, originNetwork uint, originalTokenAddr common.Address, isCreated bool) (*etherman.TokenWrapped, error) {
 	if isCreated {
-		if err := operations.Poll(defaultInterval, defaultDeadline, func() (bool, error) {
+		if err := wait.Poll(ctx, defaultInterval, defaultDeadline, func() (bool, error) {
 			wrappedToken, err := m.storage.GetTokenWrapped(ctx, originNetwork, originalTokenAddr, nil)
 			if err != nil {
 				return false, err
@@ -690,10 +726,10 @@ func (m *Manager) WaitExitRootToBeSynced(ctx context.Context, orgExitRoot *ether
 			ExitRoots: []common.Hash{{}, {}},
 		}
 	}
-	return operations.Poll(defaultInterval, waitRootSyncDeadline, func() (bool, error) {
+	return wait.Poll(ctx, defaultInterval, waitRootSyncDeadline, func() (bool, error) {
 		exitRoot, err := m.storage.GetLatestExitRoot(ctx, isRollup, nil)
 		if err != nil {
-			if err == gerror.ErrStorageNotFound {
+			if errors.Is(err, gerror.ErrStorageNotFound) {
 				return false, nil
 			}
 			return false, err
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
@@ -30,7 +31,7 @@ func RunMockServer(dbType string, height uint8, networks []uint) (*bridgectrl.Br
 		Store:  "postgres",
 	}
 
-	bt, err := bridgectrl.NewBridgeController(btCfg, networks, store)
+	bt, err := bridgectrl.NewBridgeController(context.Background(), btCfg, networks, store)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -43,6 +44,7 @@ func RunMockServer(dbType string, height uint8, networks []uint) (*bridgectrl.Br
 		MaxPageLimit:     100,    //nolint:gomnd
 		BridgeVersion:    "v1",
 	}
-	bridgeService := server.NewBridgeService(cfg, btCfg.Height, networks, store)
-	return bt, store, server.RunServer(cfg, bridgeService)
+	bridgeService := server.NewBridgeService(cfg, btCfg.Height, networks, store, nil)
+	readiness := server.NewReadinessChecker(cfg, store, nil)
+	return bt, store, server.RunServer(cfg, bridgeService, readiness)
 }
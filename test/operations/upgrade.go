@@ -0,0 +1,238 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmbridge"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// proxyAdminABI is the part of the ProxyAdmin contract used to upgrade the bridge
+	proxyAdminABI = `[
+		{"inputs":[],"name":"owner","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"},
+		{"inputs":[{"name":"proxy","type":"address"},{"name":"implementation","type":"address"}],"name":"upgrade","outputs":[],"stateMutability":"nonpayable","type":"function"}
+	]`
+	// timelockABI is the part of the TimelockController contract used when it owns the ProxyAdmin
+	timelockABI = `[
+		{"inputs":[],"name":"getMinDelay","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+		{"inputs":[{"name":"target","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"predecessor","type":"bytes32"},{"name":"salt","type":"bytes32"},{"name":"delay","type":"uint256"}],"name":"schedule","outputs":[],"stateMutability":"nonpayable","type":"function"},
+		{"inputs":[{"name":"target","type":"address"},{"name":"value","type":"uint256"},{"name":"payload","type":"bytes"},{"name":"predecessor","type":"bytes32"},{"name":"salt","type":"bytes32"}],"name":"execute","outputs":[],"stateMutability":"payable","type":"function"}
+	]`
+
+	// deployerHexPrivateKey is the key of the devnet deployer, the owner of the proxy admin or its timelock
+	deployerHexPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80" //nolint:gosec
+
+	upgradeTxTimeout = 60 * time.Second
+)
+
+var (
+	// EIP-1967 storage slots of the transparent proxies
+	implementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	adminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+)
+
+// BridgeUpgrade is an upgrade of the bridge proxy of a network.
+type BridgeUpgrade struct {
+	Previous       common.Address
+	Implementation common.Address
+	Block          uint64
+	// CodeChanged is false when the new implementation redeploys the bytecode of the previous one
+	CodeChanged bool
+}
+
+// GetBridgeImplementation returns the address of the implementation behind the bridge proxy of the network.
+func (m *Manager) GetBridgeImplementation(ctx context.Context, network NetworkSID) (common.Address, error) {
+	return m.proxySlot(ctx, network, implementationSlot, nil)
+}
+
+// GetBridgeImplementationAt returns the address of the implementation behind the bridge proxy of the network at the
+// block. The implementations are compared by address, since a redeployed implementation can have the same code.
+func (m *Manager) GetBridgeImplementationAt(ctx context.Context, network NetworkSID, blockNum uint64) (common.Address, error) {
+	return m.proxySlot(ctx, network, implementationSlot, new(big.Int).SetUint64(blockNum))
+}
+
+// UpgradeBridge deploys a new implementation of the bridge contract and upgrades the bridge proxy of the network
+// to it, through its proxy admin. The implementations are told apart by address, and their code hashes tell
+// whether the upgrade changed the code or redeployed the same one.
+func (m *Manager) UpgradeBridge(ctx context.Context, network NetworkSID) (*BridgeUpgrade, error) {
+	client := m.clients[network]
+	auth, err := client.GetSigner(ctx, deployerHexPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	implementation, tx, _, err := polygonzkevmbridge.DeployPolygonzkevmbridge(auth, client.Client)
+	if err != nil {
+		return nil, fmt.Errorf("error deploying the bridge implementation: %w", err)
+	}
+	if err := client.WaitTxToBeMined(ctx, tx, upgradeTxTimeout); err != nil {
+		return nil, err
+	}
+	log.Debugf("new bridge implementation deployed at %s", implementation.Hex())
+
+	proxyAdmin, err := m.proxySlot(ctx, network, adminSlot, nil)
+	if err != nil {
+		return nil, err
+	}
+	parsedProxyAdmin, err := abi.JSON(strings.NewReader(proxyAdminABI))
+	if err != nil {
+		return nil, err
+	}
+	proxyAdminContract := bind.NewBoundContract(proxyAdmin, parsedProxyAdmin, client.Client, client.Client, client.Client)
+	var out []interface{}
+	if err := proxyAdminContract.Call(&bind.CallOpts{Context: ctx}, &out, "owner"); err != nil {
+		return nil, fmt.Errorf("error getting the owner of the proxy admin %s: %w", proxyAdmin.Hex(), err)
+	}
+	owner := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	bridgeProxy := bridgeProxyAddress(network)
+	code, err := client.CodeAt(ctx, owner, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		if owner != auth.From {
+			return nil, fmt.Errorf("the proxy admin is owned by %s, not by the deployer %s", owner.Hex(), auth.From.Hex())
+		}
+		tx, err = proxyAdminContract.Transact(auth, "upgrade", bridgeProxy, implementation)
+	} else {
+		var data []byte
+		data, err = parsedProxyAdmin.Pack("upgrade", bridgeProxy, implementation)
+		if err != nil {
+			return nil, err
+		}
+		tx, err = m.executeTimelocked(ctx, network, owner, proxyAdmin, data, auth)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error upgrading the bridge proxy: %w", err)
+	}
+	if err := client.WaitTxToBeMined(ctx, tx, upgradeTxTimeout); err != nil {
+		return nil, err
+	}
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	// The proxy must point to the new deployment from the block of the upgrade, and not before it, whatever its code
+	upgradeBlock := receipt.BlockNumber.Uint64()
+	current, err := m.GetBridgeImplementationAt(ctx, network, upgradeBlock)
+	if err != nil {
+		return nil, err
+	}
+	if current != implementation {
+		return nil, fmt.Errorf("the bridge proxy points to %s after the upgrade to %s", current.Hex(), implementation.Hex())
+	}
+	previous, err := m.GetBridgeImplementationAt(ctx, network, upgradeBlock-1)
+	if err != nil {
+		return nil, err
+	}
+	if previous == implementation {
+		return nil, fmt.Errorf("the bridge proxy already pointed to %s before the upgrade", implementation.Hex())
+	}
+	previousCode, err := client.CodeAt(ctx, previous, receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	currentCode, err := client.CodeAt(ctx, current, receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	upgrade := &BridgeUpgrade{
+		Previous:       previous,
+		Implementation: implementation,
+		Block:          upgradeBlock,
+		CodeChanged:    crypto.Keccak256Hash(previousCode) != crypto.Keccak256Hash(currentCode),
+	}
+	if !upgrade.CodeChanged {
+		log.Debugf("bridge implementation %s redeploys the code of %s", implementation.Hex(), previous.Hex())
+	}
+	return upgrade, nil
+}
+
+// executeTimelocked schedules the call of the target through the timelock, waits for its delay and executes it.
+func (m *Manager) executeTimelocked(ctx context.Context, network NetworkSID, timelock, target common.Address, data []byte, auth *bind.TransactOpts) (*types.Transaction, error) {
+	client := m.clients[network]
+	parsed, err := abi.JSON(strings.NewReader(timelockABI))
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(timelock, parsed, client.Client, client.Client, client.Client)
+	var out []interface{}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, "getMinDelay"); err != nil {
+		return nil, fmt.Errorf("error getting the delay of the timelock %s: %w", timelock.Hex(), err)
+	}
+	delay := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	if delay.Cmp(big.NewInt(int64(waitRootSyncDeadline/time.Second))) > 0 {
+		return nil, fmt.Errorf("the timelock %s delay of %s seconds is too long for a test", timelock.Hex(), delay)
+	}
+	salt := common.BigToHash(big.NewInt(time.Now().UnixNano()))
+	tx, err := contract.Transact(auth, "schedule", target, big.NewInt(0), data, common.Hash{}, salt, delay)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.WaitTxToBeMined(ctx, tx, upgradeTxTimeout); err != nil {
+		return nil, err
+	}
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	header, err := client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	ready := header.Time + delay.Uint64()
+	// The operation is executable once a block is mined after its delay
+	if err := wait.Poll(ctx, defaultInterval, waitRootSyncDeadline, func() (bool, error) {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return false, err
+		}
+		return header.Time >= ready, nil
+	}); err != nil {
+		return nil, err
+	}
+	return contract.Transact(auth, "execute", target, big.NewInt(0), data, common.Hash{}, salt)
+}
+
+// WaitBlockToBeSynced waits until the service has synced the block of the network.
+func (m *Manager) WaitBlockToBeSynced(ctx context.Context, networkID uint, blockNum uint64) error {
+	return wait.Poll(ctx, defaultInterval, waitRootSyncDeadline, func() (bool, error) {
+		block, err := m.storage.GetLastBlock(ctx, networkID, nil)
+		if err != nil {
+			return false, err
+		}
+		return block.BlockNumber >= blockNum, nil
+	})
+}
+
+func (m *Manager) proxySlot(ctx context.Context, network NetworkSID, slot common.Hash, blockNum *big.Int) (common.Address, error) {
+	value, err := m.clients[network].StorageAt(ctx, bridgeProxyAddress(network), slot, blockNum)
+	if err != nil {
+		return common.Address{}, err
+	}
+	addr := common.BytesToAddress(value)
+	if addr == (common.Address{}) {
+		return common.Address{}, errors.New("the bridge is not behind a transparent proxy")
+	}
+	return addr, nil
+}
+
+func bridgeProxyAddress(network NetworkSID) common.Address {
+	if network == L2 {
+		return common.HexToAddress(l2BridgeAddr)
+	}
+	return common.HexToAddress(l1BridgeAddr)
+}
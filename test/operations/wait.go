@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils"
 	ops "github.com/0xPolygonHermez/zkevm-node/test/operations"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -17,8 +19,8 @@ const (
 	defaultDeadline = 60 * time.Second
 )
 
-func poll(interval, deadline time.Duration, condition ops.ConditionFunc) error {
-	return ops.Poll(interval, deadline, condition)
+func poll(interval, deadline time.Duration, condition func() (bool, error)) error {
+	return wait.Poll(context.Background(), interval, deadline, condition)
 }
 
 // WaitRestHealthy waits for a rest endpoint to be ready
@@ -44,6 +46,10 @@ func networkUpCondition() (bool, error) {
 	return ops.NodeUpCondition(l1NetworkURL)
 }
 
+func mockAggregatorUpCondition() (bool, error) {
+	return ops.NodeUpCondition(mockAggregatorURL)
+}
+
 func proverUpCondition() (bool, error) {
 	return true, nil
 	// return ops.ProverUpCondition()
@@ -79,7 +85,7 @@ func bridgeUpCondition() (done bool, err error) {
 	return done, nil
 }
 
-// WaitTxToBeMined waits until a tx is mined or forged.
+// WaitTxToBeMined waits until a tx is mined or forged, returning a *receipt.RevertError with the revert reason if it fails.
 func WaitTxToBeMined(ctx context.Context, client *ethclient.Client, tx *types.Transaction, timeout time.Duration) error {
-	return ops.WaitTxToBeMined(ctx, client, tx, timeout)
+	return utils.WaitTxToBeMined(ctx, client, tx, timeout)
 }
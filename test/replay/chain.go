@@ -0,0 +1,194 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// errNotRecorded is returned for the calls whose result is not recorded in the fixtures, the synchronizer doesn't
+// need them.
+var errNotRecorded = errors.New("not recorded in the fixture")
+
+var _ etherman.ChainBackend = (*chain)(nil)
+
+// chain is the backend of the etherman replaying a fixture. It serves the recorded headers and logs, and the
+// window looks like the whole chain, its last block being the latest one.
+type chain struct {
+	fixture  *Fixture
+	byNumber map[uint64]*types.Header
+	byHash   map[common.Hash]*types.Header
+}
+
+func newChain(f *Fixture) *chain {
+	c := &chain{
+		fixture:  f,
+		byNumber: make(map[uint64]*types.Header, len(f.Headers)),
+		byHash:   make(map[common.Hash]*types.Header, len(f.Headers)),
+	}
+	for _, header := range f.Headers {
+		c.byNumber[header.Number.Uint64()] = header
+		c.byHash[header.Hash()] = header
+	}
+	return c
+}
+
+// HeaderByNumber returns the recorded header of the block, the last block of the window when number is nil.
+func (c *chain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	n := c.fixture.ToBlock
+	if number != nil {
+		n = number.Uint64()
+	}
+	header, found := c.byNumber[n]
+	if !found {
+		return nil, fmt.Errorf("header of the block %d: %w", n, ethereum.NotFound)
+	}
+	return header, nil
+}
+
+// HeaderByHash returns the recorded header of the block.
+func (c *chain) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	header, found := c.byHash[hash]
+	if !found {
+		return nil, fmt.Errorf("header of the block %s: %w", hash.String(), ethereum.NotFound)
+	}
+	return header, nil
+}
+
+// BlockByNumber returns the recorded block without its txs.
+func (c *chain) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	header, err := c.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewBlockWithHeader(header), nil
+}
+
+// BlockByHash returns the recorded block without its txs.
+func (c *chain) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	header, err := c.HeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewBlockWithHeader(header), nil
+}
+
+// FilterLogs returns the recorded logs of the range of blocks and the addresses of the query.
+func (c *chain) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if q.BlockHash != nil {
+		return nil, fmt.Errorf("logs by block hash: %w", errNotRecorded)
+	}
+	var from, to uint64 = 0, c.fixture.ToBlock
+	if q.FromBlock != nil {
+		from = q.FromBlock.Uint64()
+	}
+	if q.ToBlock != nil && q.ToBlock.Uint64() < to {
+		to = q.ToBlock.Uint64()
+	}
+	addresses := make(map[common.Address]bool, len(q.Addresses))
+	for _, addr := range q.Addresses {
+		addresses[addr] = true
+	}
+	var logs []types.Log
+	for _, vLog := range c.fixture.Logs {
+		if vLog.BlockNumber < from || vLog.BlockNumber > to {
+			continue
+		}
+		if len(addresses) > 0 && !addresses[vLog.Address] {
+			continue
+		}
+		logs = append(logs, vLog)
+	}
+	return logs, nil
+}
+
+// TransactionCount is not recorded.
+func (c *chain) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return 0, errNotRecorded
+}
+
+// TransactionInBlock is not recorded.
+func (c *chain) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	return nil, errNotRecorded
+}
+
+// TransactionByHash is not recorded.
+func (c *chain) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	return nil, false, errNotRecorded
+}
+
+// TransactionReceipt is not recorded.
+func (c *chain) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return nil, errNotRecorded
+}
+
+// SubscribeNewHead is not supported, the recorded chain doesn't grow.
+func (c *chain) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, errNotRecorded
+}
+
+// SubscribeFilterLogs is not supported, the recorded chain doesn't grow.
+func (c *chain) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errNotRecorded
+}
+
+// CallContract is not recorded, the state of the contracts is only recorded at the last block of the window.
+func (c *chain) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, errNotRecorded
+}
+
+// CodeAt is not recorded.
+func (c *chain) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, errNotRecorded
+}
+
+// BalanceAt is not recorded.
+func (c *chain) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return nil, errNotRecorded
+}
+
+// StorageAt is not recorded.
+func (c *chain) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return nil, errNotRecorded
+}
+
+// NonceAt is not recorded.
+func (c *chain) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return 0, errNotRecorded
+}
+
+// PendingCodeAt is not recorded.
+func (c *chain) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return nil, errNotRecorded
+}
+
+// PendingNonceAt is not recorded.
+func (c *chain) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 0, errNotRecorded
+}
+
+// SuggestGasPrice is not supported, no tx is sent to the recorded chain.
+func (c *chain) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return nil, errNotRecorded
+}
+
+// SuggestGasTipCap is not supported, no tx is sent to the recorded chain.
+func (c *chain) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return nil, errNotRecorded
+}
+
+// EstimateGas is not supported, no tx is sent to the recorded chain.
+func (c *chain) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 0, errNotRecorded
+}
+
+// SendTransaction is not supported, no tx is sent to the recorded chain.
+func (c *chain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return errNotRecorded
+}
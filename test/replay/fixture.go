@@ -0,0 +1,67 @@
+// Package replay records the bridge events of a window of blocks of a real network into a fixture, and replays it
+// through the synchronizer against an in memory storage, checking that the trees built from the events have the
+// roots recorded from the contracts. The fixtures are regression tests of the decoding of the events and of the
+// tree logic.
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Fixture is a window of blocks of a network with the logs of the bridge contracts, and the state of the
+// contracts at the last block of the window.
+type Fixture struct {
+	NetworkID     uint           `json:"networkId"`
+	BridgeAddress common.Address `json:"bridgeAddress"`
+	// GlobalExitRootAddress is zero for the L2 networks
+	GlobalExitRootAddress common.Address `json:"globalExitRootAddress"`
+	// RollupManagerAddress is zero for the contracts of a single rollup
+	RollupManagerAddress common.Address `json:"rollupManagerAddress"`
+	// FromBlock is the first block of the window. No deposit was made before it, so the exit tree is built from
+	// its first leaf
+	FromBlock uint64 `json:"fromBlock"`
+	ToBlock   uint64 `json:"toBlock"`
+	// Headers are the headers of the blocks with logs, of the block before the window and of its last block
+	Headers []*types.Header `json:"headers"`
+	Logs    []types.Log     `json:"logs"`
+	// Roots is the state of the contracts at the last block of the window
+	Roots Roots `json:"roots"`
+}
+
+// Roots is the state of the bridge contracts at a block.
+type Roots struct {
+	DepositCount  uint        `json:"depositCount"`
+	LocalExitRoot common.Hash `json:"localExitRoot"`
+	// MainnetExitRoot and RollupExitRoot are the last exit roots of the global exit root manager, zero for the
+	// L2 networks
+	MainnetExitRoot common.Hash `json:"mainnetExitRoot"`
+	RollupExitRoot  common.Hash `json:"rollupExitRoot"`
+}
+
+// Load reads a fixture from a json file.
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("error decoding the fixture %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to a json file.
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(path), data, 0600) //nolint:gomnd
+}
@@ -0,0 +1,167 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmbridge"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmglobalexitroot"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Client is the node the fixtures are recorded from. The contracts are called at the last block of the window,
+// so the node must serve the state of that block, e.g. an archive node for the old blocks.
+type Client interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	ethereum.LogFilterer
+	bind.ContractCaller
+}
+
+// RecordConfig is the network, the contracts and the window of blocks to record.
+type RecordConfig struct {
+	NetworkID     uint
+	BridgeAddress common.Address
+	// GlobalExitRootAddress is zero for the L2 networks
+	GlobalExitRootAddress common.Address
+	// RollupManagerAddress is zero for the contracts of a single rollup
+	RollupManagerAddress common.Address
+	// FromBlock must be before the first deposit of the bridge, since the exit tree is built from the deposits
+	FromBlock uint64
+	ToBlock   uint64
+	// ChunkSize is the number of blocks whose logs are requested at once, 0 requests the whole window
+	ChunkSize uint64
+}
+
+// Record records the logs of the contracts in the window of blocks, with the state of the contracts at its last block.
+func Record(ctx context.Context, client Client, cfg RecordConfig) (*Fixture, error) {
+	if cfg.FromBlock == 0 || cfg.ToBlock < cfg.FromBlock {
+		return nil, fmt.Errorf("invalid window of blocks %d-%d, the first block must be after the genesis", cfg.FromBlock, cfg.ToBlock)
+	}
+	f := &Fixture{
+		NetworkID:             cfg.NetworkID,
+		BridgeAddress:         cfg.BridgeAddress,
+		GlobalExitRootAddress: cfg.GlobalExitRootAddress,
+		RollupManagerAddress:  cfg.RollupManagerAddress,
+		FromBlock:             cfg.FromBlock,
+		ToBlock:               cfg.ToBlock,
+	}
+	addresses := []common.Address{cfg.BridgeAddress}
+	for _, addr := range []common.Address{cfg.GlobalExitRootAddress, cfg.RollupManagerAddress} {
+		if addr != (common.Address{}) {
+			addresses = append(addresses, addr)
+		}
+	}
+	chunkSize := cfg.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = cfg.ToBlock - cfg.FromBlock + 1
+	}
+	for from := cfg.FromBlock; from <= cfg.ToBlock; from += chunkSize {
+		to := from + chunkSize - 1
+		if to > cfg.ToBlock {
+			to = cfg.ToBlock
+		}
+		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: addresses,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error getting the logs of the blocks %d-%d: %w", from, to, err)
+		}
+		f.Logs = append(f.Logs, logs...)
+	}
+	if err := checkFirstDeposit(f); err != nil {
+		return nil, err
+	}
+	if err := recordHeaders(ctx, client, f); err != nil {
+		return nil, err
+	}
+	if err := recordRoots(ctx, client, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// checkFirstDeposit checks that the window starts before the first deposit of the bridge.
+func checkFirstDeposit(f *Fixture) error {
+	bridge, err := polygonzkevmbridge.NewPolygonzkevmbridgeFilterer(f.BridgeAddress, nil)
+	if err != nil {
+		return err
+	}
+	for _, vLog := range f.Logs {
+		if vLog.Address != f.BridgeAddress {
+			continue
+		}
+		deposit, err := bridge.ParseBridgeEvent(vLog)
+		if err != nil {
+			// Not a deposit
+			continue
+		}
+		if deposit.DepositCount != 0 {
+			return fmt.Errorf("the first deposit of the window has the deposit count %d, the window must start before the first deposit of the bridge", deposit.DepositCount)
+		}
+		return nil
+	}
+	return nil
+}
+
+// recordHeaders records the headers of the blocks with logs, of the block before the window and of its last block,
+// checking that the logs are of the recorded blocks.
+func recordHeaders(ctx context.Context, client Client, f *Fixture) error {
+	numbers := map[uint64]bool{f.FromBlock - 1: true, f.ToBlock: true}
+	for _, vLog := range f.Logs {
+		numbers[vLog.BlockNumber] = true
+	}
+	headers := make(map[uint64]*types.Header, len(numbers))
+	for n := range numbers {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return fmt.Errorf("error getting the header of the block %d: %w", n, err)
+		}
+		headers[n] = header
+		f.Headers = append(f.Headers, header)
+	}
+	sort.Slice(f.Headers, func(i, j int) bool { return f.Headers[i].Number.Cmp(f.Headers[j].Number) < 0 })
+	for _, vLog := range f.Logs {
+		if vLog.Removed || headers[vLog.BlockNumber].Hash() != vLog.BlockHash {
+			return fmt.Errorf("the block %d of the log %d was reorged while recording, retry once it's final", vLog.BlockNumber, vLog.Index)
+		}
+	}
+	return nil
+}
+
+// recordRoots records the state of the contracts at the last block of the window.
+func recordRoots(ctx context.Context, client Client, f *Fixture) error {
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(f.ToBlock)}
+	bridge, err := polygonzkevmbridge.NewPolygonzkevmbridgeCaller(f.BridgeAddress, client)
+	if err != nil {
+		return err
+	}
+	depositCount, err := bridge.DepositCount(opts)
+	if err != nil {
+		return fmt.Errorf("error getting the deposit count: %w", err)
+	}
+	f.Roots.DepositCount = uint(depositCount.Uint64())
+	if f.Roots.LocalExitRoot, err = bridge.GetDepositRoot(opts); err != nil {
+		return fmt.Errorf("error getting the deposit root: %w", err)
+	}
+	if f.GlobalExitRootAddress == (common.Address{}) {
+		return nil
+	}
+	globalExitRoot, err := polygonzkevmglobalexitroot.NewPolygonzkevmglobalexitrootCaller(f.GlobalExitRootAddress, client)
+	if err != nil {
+		return err
+	}
+	if f.Roots.MainnetExitRoot, err = globalExitRoot.LastMainnetExitRoot(opts); err != nil {
+		return fmt.Errorf("error getting the last mainnet exit root: %w", err)
+	}
+	if f.Roots.RollupExitRoot, err = globalExitRoot.LastRollupExitRoot(opts); err != nil {
+		return fmt.Errorf("error getting the last rollup exit root: %w", err)
+	}
+	return nil
+}
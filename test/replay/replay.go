@@ -0,0 +1,225 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/synchronizer"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	rpcTypes "github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	treeHeight   = 32
+	syncInterval = 10 * time.Millisecond
+)
+
+// Result is the state synced from the replay of a fixture.
+type Result struct {
+	Deposits []etherman.Deposit
+	Claims   []etherman.Claim
+	Tokens   []etherman.TokenWrapped
+	// GlobalExitRoots are the global exit roots synced from the events of the global exit root manager
+	GlobalExitRoots  []etherman.GlobalExitRoot
+	RollupExitLeaves []etherman.RollupExitLeaf
+	// LocalExitRoots are the roots of the exit tree by number of deposits, the first one is the root of the empty tree
+	LocalExitRoots []common.Hash
+}
+
+// LocalExitRoot returns the root of the exit tree after the last deposit.
+func (r *Result) LocalExitRoot() common.Hash {
+	return r.LocalExitRoots[len(r.LocalExitRoots)-1]
+}
+
+// Replay syncs the fixture with the synchronizer, decoding its logs with the etherman and building the trees
+// with the bridge controller, as the service syncs a network.
+func Replay(ctx context.Context, f *Fixture) (*Result, error) {
+	if f.FromBlock == 0 || f.ToBlock < f.FromBlock {
+		return nil, fmt.Errorf("invalid window of blocks %d-%d", f.FromBlock, f.ToBlock)
+	}
+	client, err := etherman.NewClientFromBackend(etherman.Config{}, newChain(f), f.BridgeAddress, f.GlobalExitRootAddress, f.RollupManagerAddress)
+	if err != nil {
+		return nil, err
+	}
+	store := newStorage()
+	bridgeCtrl, err := bridgectrl.NewBridgeController(ctx, bridgectrl.Config{Height: treeHeight}, []uint{f.NetworkID}, store)
+	if err != nil {
+		return nil, err
+	}
+	emptyRoot, err := bridgeCtrl.GetExitRoot(0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	exitRootEvents, err := queue.New[*etherman.GlobalExitRoot]("exit_roots", queue.Config{Policy: queue.PolicyBlock}, nil)
+	if err != nil {
+		return nil, err
+	}
+	chSynced := make(chan uint)
+	cfg := synchronizer.Config{
+		SyncInterval: types.NewDuration(syncInterval),
+		// The window is synced in a single chunk, so no block without events is requested
+		SyncChunkSize: f.ToBlock - f.FromBlock + 1,
+	}
+	sync, err := synchronizer.NewSynchronizer(store, &bridge{BridgeController: bridgeCtrl, store: store},
+		&ethermanReplay{Client: client, networkID: f.NetworkID}, zkEVMClient{}, f.FromBlock-1, exitRootEvents, chSynced, cfg)
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := sync.Sync(); err != nil {
+			store.fail(err)
+		}
+	}()
+	go func() {
+		for {
+			select {
+			case <-exitRootEvents.C():
+			case <-done:
+				return
+			}
+		}
+	}()
+	select {
+	case <-chSynced:
+	case <-store.failed:
+	case <-ctx.Done():
+	}
+	sync.Stop()
+	<-done
+	if store.err != nil {
+		return nil, store.err
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("the window was not synced: %w", ctx.Err())
+	}
+	return store.result(common.BytesToHash(emptyRoot)), nil
+}
+
+func (s *storage) result(emptyRoot common.Hash) *Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := &Result{
+		RollupExitLeaves: s.rollupLeaves,
+		LocalExitRoots:   []common.Hash{emptyRoot},
+	}
+	for _, deposit := range s.deposits {
+		r.Deposits = append(r.Deposits, *deposit)
+	}
+	for _, claim := range s.claims {
+		r.Claims = append(r.Claims, *claim)
+	}
+	for _, token := range s.tokens {
+		r.Tokens = append(r.Tokens, *token)
+	}
+	for _, exitRoot := range s.exitRoots {
+		r.GlobalExitRoots = append(r.GlobalExitRoots, *exitRoot)
+	}
+	for _, roots := range s.roots {
+		for depositCount := uint(0); depositCount < uint(len(roots)); depositCount++ {
+			r.LocalExitRoots = append(r.LocalExitRoots, common.BytesToHash(roots[depositCount]))
+		}
+	}
+	return r
+}
+
+// Check compares the result with the state of the contracts recorded in the fixture:
+//   - the deposits are the deposit count of the bridge, and the exit tree has its root
+//   - the last global exit root synced has the last exit roots of the global exit root manager
+//   - the mainnet exit roots of the global exit roots are roots of the exit tree, for the L1 bridges
+func (r *Result) Check(f *Fixture) error {
+	var mismatches []string
+	if uint(len(r.Deposits)) != f.Roots.DepositCount {
+		mismatches = append(mismatches, fmt.Sprintf("%d deposits synced, the deposit count is %d", len(r.Deposits), f.Roots.DepositCount))
+	}
+	if r.LocalExitRoot() != f.Roots.LocalExitRoot {
+		mismatches = append(mismatches, fmt.Sprintf("local exit root %s, the root of the bridge is %s", r.LocalExitRoot().String(), f.Roots.LocalExitRoot.String()))
+	}
+	if f.GlobalExitRootAddress != (common.Address{}) && len(r.GlobalExitRoots) > 0 {
+		last := r.GlobalExitRoots[len(r.GlobalExitRoots)-1]
+		if last.ExitRoots[0] != f.Roots.MainnetExitRoot || last.ExitRoots[1] != f.Roots.RollupExitRoot {
+			mismatches = append(mismatches, fmt.Sprintf("last exit roots %s and %s, the global exit root manager has %s and %s",
+				last.ExitRoots[0].String(), last.ExitRoots[1].String(), f.Roots.MainnetExitRoot.String(), f.Roots.RollupExitRoot.String()))
+		}
+	}
+	if f.NetworkID == 0 {
+		roots := make(map[common.Hash]bool, len(r.LocalExitRoots))
+		for _, root := range r.LocalExitRoots {
+			roots[root] = true
+		}
+		for _, exitRoot := range r.GlobalExitRoots {
+			if !roots[exitRoot.ExitRoots[0]] {
+				mismatches = append(mismatches, fmt.Sprintf("mainnet exit root %s of the block %d is not a root of the exit tree",
+					exitRoot.ExitRoots[0].String(), exitRoot.BlockNumber))
+			}
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%d mismatches with the recorded state: %s", len(mismatches), strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// bridge records the errors of the trees as the failure of the replay, since the synchronizer only logs them.
+type bridge struct {
+	*bridgectrl.BridgeController
+	store *storage
+}
+
+func (b *bridge) AddDeposit(deposit *etherman.Deposit, depositID uint64, dbTx pgx.Tx) error {
+	err := b.BridgeController.AddDeposit(deposit, depositID, dbTx)
+	if err != nil {
+		b.store.fail(fmt.Errorf("error adding the deposit %d of the block %d: %w", deposit.DepositCount, deposit.BlockNumber, err))
+	}
+	return err
+}
+
+func (b *bridge) AddRollupExitLeaf(ctx context.Context, leaf *etherman.RollupExitLeaf, dbTx pgx.Tx) error {
+	err := b.BridgeController.AddRollupExitLeaf(ctx, leaf, dbTx)
+	if err != nil {
+		b.store.fail(fmt.Errorf("error adding the rollup exit leaf of the block %d: %w", leaf.BlockNumber, err))
+	}
+	return err
+}
+
+func (b *bridge) AddL1InfoTreeLeaf(ctx context.Context, leaf *etherman.L1InfoTreeLeaf, dbTx pgx.Tx) error {
+	err := b.BridgeController.AddL1InfoTreeLeaf(ctx, leaf, dbTx)
+	if err != nil {
+		b.store.fail(fmt.Errorf("error adding the L1 info tree leaf of the block %d: %w", leaf.BlockNumber, err))
+	}
+	return err
+}
+
+// ethermanReplay returns the network of the fixture, the contracts are not called in the replay.
+type ethermanReplay struct {
+	*etherman.Client
+	networkID uint
+}
+
+func (e *ethermanReplay) GetNetworkID(ctx context.Context) (uint, error) {
+	return e.networkID, nil
+}
+
+var errNoTrustedState = errors.New("the trusted state is not replayed")
+
+// zkEVMClient has no trusted state, only the events of the fixture are synced.
+type zkEVMClient struct{}
+
+func (zkEVMClient) BatchNumber(ctx context.Context) (uint64, error) {
+	return 0, errNoTrustedState
+}
+
+func (zkEVMClient) BatchByNumber(ctx context.Context, number *big.Int) (*rpcTypes.Batch, error) {
+	return nil, errNoTrustedState
+}
@@ -0,0 +1,167 @@
+package replay
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmbridge"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmglobalexitroot"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const replayTimeout = 10 * time.Second
+
+// newSimulatedBridge deploys the bridge and the global exit root manager of the L1 in a simulated chain. The
+// simulated etherman deploys a mock bridge without exit tree, so the contracts are deployed apart.
+func newSimulatedBridge(t *testing.T) (*backends.SimulatedBackend, *bind.TransactOpts, *polygonzkevmbridge.Polygonzkevmbridge, common.Address, common.Address) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(1337))
+	require.NoError(t, err)
+	balance, _ := new(big.Int).SetString("10000000000000000000000000", 10)
+	client := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: balance}}, 999999999999999999)
+	nonce, err := client.PendingNonceAt(context.Background(), auth.From)
+	require.NoError(t, err)
+	calculatedBridgeAddr := crypto.CreateAddress(auth.From, nonce+1)
+	// The sender acts as the rollup, it's not called in the replay
+	globalExitRootAddr, _, _, err := polygonzkevmglobalexitroot.DeployPolygonzkevmglobalexitroot(auth, client, auth.From, calculatedBridgeAddr)
+	require.NoError(t, err)
+	bridgeAddr, _, bridge, err := polygonzkevmbridge.DeployPolygonzkevmbridge(auth, client)
+	require.NoError(t, err)
+	require.Equal(t, calculatedBridgeAddr, bridgeAddr)
+	_, err = bridge.Initialize(auth, 0, globalExitRootAddr, auth.From)
+	require.NoError(t, err)
+	client.Commit()
+	return client, auth, bridge, bridgeAddr, globalExitRootAddr
+}
+
+func TestReplaySimulated(t *testing.T) {
+	ethBackend, auth, bridge, bridgeAddr, globalExitRootAddr := newSimulatedBridge(t)
+	ctx := context.Background()
+	deployed, err := ethBackend.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+
+	// Some deposits updating the global exit root and some not, in a few blocks
+	amount := big.NewInt(1000000000000000)
+	for i := 0; i < 5; i++ {
+		auth.Value = amount
+		_, err = bridge.BridgeAsset(auth, 1, auth.From, amount, common.Address{}, i%2 == 0, []byte{})
+		require.NoError(t, err)
+		if i != 1 {
+			ethBackend.Commit()
+		}
+	}
+	latest, err := ethBackend.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+
+	f, err := Record(ctx, ethBackend, RecordConfig{
+		BridgeAddress:         bridgeAddr,
+		GlobalExitRootAddress: globalExitRootAddr,
+		FromBlock:             deployed.Number.Uint64() + 1,
+		ToBlock:               latest.Number.Uint64(),
+		ChunkSize:             2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint(5), f.Roots.DepositCount)
+
+	// The fixture replays the same after saving it
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, f.Save(path))
+	f, err = Load(path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(ctx, replayTimeout)
+	defer cancel()
+	result, err := Replay(ctx, f)
+	require.NoError(t, err)
+	require.Len(t, result.Deposits, 5)
+	require.NotEmpty(t, result.GlobalExitRoots)
+	require.NoError(t, result.Check(f))
+
+	// A fixture with other roots fails the check
+	f.Roots.LocalExitRoot = common.HexToHash("0x01")
+	require.Error(t, result.Check(f))
+
+	// The window must start before the first deposit
+	_, err = Record(ctx, ethBackend, RecordConfig{
+		BridgeAddress: bridgeAddr,
+		FromBlock:     deployed.Number.Uint64() + 2,
+		ToBlock:       latest.Number.Uint64(),
+	})
+	require.Error(t, err)
+}
+
+// TestReplayDecoding checks the values decoded from the events of the committed simulated fixture, recorded from
+// the deposits of ether of TestReplaySimulated to the sender in the network 1.
+func TestReplayDecoding(t *testing.T) {
+	f, err := Load(filepath.Join(defaultFixtures, "simulated.json"))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+	defer cancel()
+	result, err := Replay(ctx, f)
+	require.NoError(t, err)
+
+	destination := common.HexToAddress("0x0f38B8C5C6C1a4EE4654885772d16039aA293ce3")
+	blocks := []uint64{2, 2, 3, 4, 4, 5, 6, 6}
+	require.Len(t, result.Deposits, len(blocks))
+	for i, deposit := range result.Deposits {
+		require.Equal(t, uint(i), deposit.DepositCount)
+		require.Equal(t, blocks[i], deposit.BlockNumber)
+		require.Equal(t, uint(0), deposit.NetworkID)
+		require.Equal(t, uint8(0), deposit.LeafType)
+		require.Equal(t, uint(0), deposit.OriginalNetwork)
+		require.Equal(t, common.Address{}, deposit.OriginalAddress)
+		require.Equal(t, "1000000000000000", deposit.Amount.String())
+		require.Equal(t, uint(1), deposit.DestinationNetwork)
+		require.Equal(t, destination, deposit.DestinationAddress)
+		require.Empty(t, deposit.Metadata)
+	}
+	require.Equal(t, common.HexToHash("0x7a1ce91cbb6877abddc4bd9ca4f69b8cbfae7cc5cf90f85222d7f7586bcfdfad"), result.Deposits[0].TxHash)
+
+	// A global exit root is updated once by block, with the mainnet exit root after the last deposit that updates it
+	updates := map[uint64]uint{2: 1, 3: 3, 4: 4, 5: 6, 6: 7}
+	require.Len(t, result.GlobalExitRoots, len(updates))
+	for _, ger := range result.GlobalExitRoots {
+		require.Equal(t, result.LocalExitRoots[updates[ger.BlockNumber]], ger.ExitRoots[0], "block %d", ger.BlockNumber)
+		require.Equal(t, common.Hash{}, ger.ExitRoots[1])
+		require.Equal(t, crypto.Keccak256Hash(ger.ExitRoots[0].Bytes(), ger.ExitRoots[1].Bytes()), ger.GlobalExitRoot)
+	}
+	require.Empty(t, result.Claims)
+	require.Empty(t, result.Tokens)
+}
+
+// defaultFixtures is the directory of the committed fixtures, replayed when BRIDGE_REPLAY_FIXTURES is not set.
+const defaultFixtures = "../vectors/src/replay"
+
+// TestReplayFixtures replays the fixtures of the directory set in the BRIDGE_REPLAY_FIXTURES env var, the committed
+// ones by default.
+func TestReplayFixtures(t *testing.T) {
+	dir := os.Getenv("BRIDGE_REPLAY_FIXTURES")
+	if dir == "" {
+		dir = defaultFixtures
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			f, err := Load(path)
+			require.NoError(t, err)
+			ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+			defer cancel()
+			result, err := Replay(ctx, f)
+			require.NoError(t, err)
+			require.NoError(t, result.Check(f))
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// errRolledBack is the failure of a replay whose block was rolled back without an error of the trees.
+var errRolledBack = errors.New("the synchronizer rolled back a block, see its logs")
+
+// storage is the in memory storage of the synchronizer and the trees of a replay. The blocks are synced without
+// transactions: the replay fails at the first rollback, so the storage is never retried from a partial block.
+type storage struct {
+	mu sync.Mutex
+	// failed is closed at the first failure of the replay, recorded in err
+	failed chan struct{}
+	err    error
+
+	blocks    []*etherman.Block
+	deposits  []*etherman.Deposit
+	claims    []*etherman.Claim
+	tokens    []*etherman.TokenWrapped
+	exitRoots []*etherman.GlobalExitRoot
+	// roots are the roots of the exit trees by network and deposit count
+	roots        map[uint]map[uint][]byte
+	nodes        map[string][][]byte
+	rollupLeaves []etherman.RollupExitLeaf
+	l1InfoLeaves []etherman.L1InfoTreeLeaf
+}
+
+func newStorage() *storage {
+	return &storage{
+		failed: make(chan struct{}),
+		roots:  make(map[uint]map[uint][]byte),
+		nodes:  make(map[string][][]byte),
+	}
+}
+
+// fail records the first failure of the replay.
+func (s *storage) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	s.err = err
+	close(s.failed)
+}
+
+func (s *storage) BeginDBTransaction(ctx context.Context) (pgx.Tx, error) {
+	return nil, nil
+}
+
+func (s *storage) Commit(ctx context.Context, dbTx pgx.Tx) error {
+	return nil
+}
+
+func (s *storage) Rollback(ctx context.Context, dbTx pgx.Tx) error {
+	s.fail(errRolledBack)
+	return nil
+}
+
+func (s *storage) GetLastBlock(ctx context.Context, networkID uint, dbTx pgx.Tx) (*etherman.Block, error) {
+	return s.GetPreviousBlock(ctx, networkID, 0, dbTx)
+}
+
+func (s *storage) GetPreviousBlock(ctx context.Context, networkID uint, offset uint64, dbTx pgx.Tx) (*etherman.Block, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if offset >= uint64(len(s.blocks)) {
+		return nil, gerror.ErrStorageNotFound
+	}
+	block := *s.blocks[uint64(len(s.blocks))-1-offset]
+	return &block, nil
+}
+
+func (s *storage) AddBlock(ctx context.Context, block *etherman.Block, dbTx pgx.Tx) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *block
+	stored.ID = uint64(len(s.blocks)) + 1
+	s.blocks = append(s.blocks, &stored)
+	return stored.ID, nil
+}
+
+func (s *storage) AddGlobalExitRoot(ctx context.Context, exitRoot *etherman.GlobalExitRoot, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *exitRoot
+	s.exitRoots = append(s.exitRoots, &stored)
+	return nil
+}
+
+func (s *storage) AddTrustedGlobalExitRoot(ctx context.Context, trustedExitRoot *etherman.GlobalExitRoot, dbTx pgx.Tx) (bool, error) {
+	return false, nil
+}
+
+func (s *storage) GetLatestL1SyncedExitRoot(ctx context.Context, dbTx pgx.Tx) (*etherman.GlobalExitRoot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.exitRoots) == 0 {
+		return &etherman.GlobalExitRoot{}, gerror.ErrStorageNotFound
+	}
+	exitRoot := *s.exitRoots[len(s.exitRoots)-1]
+	return &exitRoot, nil
+}
+
+func (s *storage) AddDeposit(ctx context.Context, deposit *etherman.Deposit, dbTx pgx.Tx) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *deposit
+	s.deposits = append(s.deposits, &stored)
+	return uint64(len(s.deposits)), nil
+}
+
+func (s *storage) AddClaim(ctx context.Context, claim *etherman.Claim, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *claim
+	s.claims = append(s.claims, &stored)
+	return nil
+}
+
+func (s *storage) AddTokenWrapped(ctx context.Context, tokenWrapped *etherman.TokenWrapped, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *tokenWrapped
+	s.tokens = append(s.tokens, &stored)
+	return nil
+}
+
+func (s *storage) AddRawLog(ctx context.Context, vLog *types.Log, blockID uint64, networkID uint, dbTx pgx.Tx) error {
+	return nil
+}
+
+func (s *storage) AddDepositTrace(ctx context.Context, trace *etherman.DepositTrace, dbTx pgx.Tx) error {
+	return nil
+}
+
+// Reset removes the blocks after blockNumber. The replayed chain has no reorgs, it's only called when the
+// synchronizer is ahead of the window.
+func (s *storage) Reset(ctx context.Context, blockNumber uint64, networkID uint, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.blocks) > 0 && s.blocks[len(s.blocks)-1].BlockNumber > blockNumber {
+		s.blocks = s.blocks[:len(s.blocks)-1]
+	}
+	for len(s.deposits) > 0 && s.deposits[len(s.deposits)-1].BlockNumber > blockNumber {
+		deposit := s.deposits[len(s.deposits)-1]
+		delete(s.roots[deposit.NetworkID], deposit.DepositCount)
+		s.deposits = s.deposits[:len(s.deposits)-1]
+	}
+	return nil
+}
+
+func (s *storage) GetNumberDeposits(ctx context.Context, origNetworkID uint, blockNumber uint64, dbTx pgx.Tx) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var count uint64
+	for _, deposit := range s.deposits {
+		if deposit.NetworkID == origNetworkID && deposit.BlockNumber <= blockNumber && uint64(deposit.DepositCount) >= count {
+			count = uint64(deposit.DepositCount) + 1
+		}
+	}
+	return count, nil
+}
+
+func (s *storage) GetDeposit(ctx context.Context, depositCounterUser uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, deposit := range s.deposits {
+		if deposit.NetworkID == networkID && deposit.DepositCount == depositCounterUser {
+			stored := *deposit
+			return &stored, nil
+		}
+	}
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (s *storage) GetClaimedDeposit(ctx context.Context, depositCnt, destNetworkID, origNet uint, origAddr common.Address, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, deposit := range s.deposits {
+		if deposit.DepositCount == depositCnt && deposit.DestinationNetwork == destNetworkID && deposit.OriginalNetwork == origNet && deposit.OriginalAddress == origAddr {
+			stored := *deposit
+			return &stored, nil
+		}
+	}
+	return nil, gerror.ErrStorageNotFound
+}
+
+func (s *storage) Get(ctx context.Context, key []byte, dbTx pgx.Tx) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, found := s.nodes[string(key)]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return value, nil
+}
+
+func (s *storage) BulkSet(ctx context.Context, rows [][]interface{}, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, row := range rows {
+		key, ok := row[0].([]byte)
+		if !ok {
+			return fmt.Errorf("invalid key %v of the merkle tree node", row[0])
+		}
+		value, ok := row[1].([][]byte)
+		if !ok {
+			return fmt.Errorf("invalid value %v of the merkle tree node", row[1])
+		}
+		s.nodes[string(key)] = value
+	}
+	return nil
+}
+
+func (s *storage) GetRoot(ctx context.Context, depositCount uint, network uint, dbTx pgx.Tx) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	root, found := s.roots[network][depositCount]
+	if !found {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return root, nil
+}
+
+func (s *storage) SetRoot(ctx context.Context, root []byte, depositID uint64, network uint, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if depositID == 0 || depositID > uint64(len(s.deposits)) {
+		return fmt.Errorf("unknown deposit id %d of the root", depositID)
+	}
+	if s.roots[network] == nil {
+		s.roots[network] = make(map[uint][]byte)
+	}
+	s.roots[network][s.deposits[depositID-1].DepositCount] = root
+	return nil
+}
+
+func (s *storage) GetLastDepositCount(ctx context.Context, network uint, dbTx pgx.Tx) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.roots[network]) == 0 {
+		return 0, gerror.ErrStorageNotFound
+	}
+	var last uint
+	for depositCount := range s.roots[network] {
+		if depositCount > last {
+			last = depositCount
+		}
+	}
+	return last, nil
+}
+
+func (s *storage) AddRollupExitLeaf(ctx context.Context, leaf *etherman.RollupExitLeaf, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rollupLeaves = append(s.rollupLeaves, *leaf)
+	return nil
+}
+
+func (s *storage) GetLatestRollupExitLeaves(ctx context.Context, dbTx pgx.Tx) ([]etherman.RollupExitLeaf, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	latest := make(map[uint]etherman.RollupExitLeaf)
+	for _, leaf := range s.rollupLeaves {
+		latest[leaf.RollupID] = leaf
+	}
+	leaves := make([]etherman.RollupExitLeaf, 0, len(latest))
+	for _, leaf := range latest {
+		leaves = append(leaves, leaf)
+	}
+	sort.Slice(leaves, func(i, j int) bool { return leaves[i].RollupID < leaves[j].RollupID })
+	return leaves, nil
+}
+
+func (s *storage) AddL1InfoTreeLeaf(ctx context.Context, leaf *etherman.L1InfoTreeLeaf, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.l1InfoLeaves = append(s.l1InfoLeaves, *leaf)
+	return nil
+}
+
+func (s *storage) GetL1InfoTreeLeaves(ctx context.Context, dbTx pgx.Tx) ([]etherman.L1InfoTreeLeaf, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]etherman.L1InfoTreeLeaf{}, s.l1InfoLeaves...), nil
+}
+
+func (s *storage) GetL1InfoTreeLeafCount(ctx context.Context, dbTx pgx.Tx) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return uint(len(s.l1InfoLeaves)), nil
+}
+
+// GetL1GlobalExitRootUpdates returns the leaves of the L1 info tree, since every global exit root synced is
+// appended to the tree with it.
+func (s *storage) GetL1GlobalExitRootUpdates(ctx context.Context, dbTx pgx.Tx) ([]etherman.L1InfoTreeLeaf, error) {
+	return s.GetL1InfoTreeLeaves(ctx, dbTx)
+}
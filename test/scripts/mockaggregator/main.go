@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/test/mockaggregator"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+func main() {
+	l1URL := flag.String("l1", "http://localhost:8545", "url of the L1 network the calls are forwarded to")
+	addr := flag.String("addr", ":8546", "address the proxy listens on")
+	mode := flag.String("mode", string(mockaggregator.ModePass), "initial mode: pass, delay or skip")
+	delay := flag.Duration("delay", 0, "delay of the verification txs in delay mode")
+	flag.Parse()
+
+	proxy, err := mockaggregator.NewProxy(*l1URL)
+	if err != nil {
+		log.Fatal("Error: ", err)
+	}
+	if err = proxy.SetMode(mockaggregator.Mode(*mode), *delay); err != nil {
+		log.Fatal("Error: ", err)
+	}
+	log.Infof("Mock aggregator listening on %s, forwarding to %s", *addr, *l1URL)
+	srv := &http.Server{Addr: *addr, Handler: proxy, ReadHeaderTimeout: readHeaderTimeout}
+	if err = srv.ListenAndServe(); err != nil {
+		log.Fatal("Error: ", err)
+	}
+}
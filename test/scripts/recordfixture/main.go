@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/test/replay"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:8545", "url of the node the events are recorded from, an archive node for the old blocks")
+	networkID := flag.Uint("network", 0, "network id of the bridge")
+	bridgeAddr := flag.String("bridge", "", "address of the bridge")
+	globalExitRootAddr := flag.String("ger", "", "address of the global exit root manager, empty for the L2 networks")
+	rollupManagerAddr := flag.String("rollupmanager", "", "address of the rollup manager, empty for the contracts of a single rollup")
+	from := flag.Uint64("from", 0, "first block of the window, before the first deposit of the bridge")
+	to := flag.Uint64("to", 0, "last block of the window")
+	chunkSize := flag.Uint64("chunk", 10000, "number of blocks whose logs are requested at once") //nolint:gomnd
+	out := flag.String("out", "fixture.json", "file the fixture is written to")
+	flag.Parse()
+
+	client, err := ethclient.Dial(*url)
+	if err != nil {
+		log.Fatal("Error: ", err)
+	}
+	f, err := replay.Record(context.Background(), client, replay.RecordConfig{
+		NetworkID:             *networkID,
+		BridgeAddress:         common.HexToAddress(*bridgeAddr),
+		GlobalExitRootAddress: common.HexToAddress(*globalExitRootAddr),
+		RollupManagerAddress:  common.HexToAddress(*rollupManagerAddr),
+		FromBlock:             *from,
+		ToBlock:               *to,
+		ChunkSize:             *chunkSize,
+	})
+	if err != nil {
+		log.Fatal("Error: ", err)
+	}
+	if err = f.Save(*out); err != nil {
+		log.Fatal("Error: ", err)
+	}
+	log.Infof("%d logs of the blocks %d-%d recorded in %s, deposit count %d", len(f.Logs), f.FromBlock, f.ToBlock, *out, f.Roots.DepositCount)
+}
@@ -0,0 +1,21 @@
+package tuning
+
+// Config is the configuration of the tuning of the runtime, the database pools and the workers for the resources
+// of the container
+type Config struct {
+	// GOMAXPROCS is the max number of CPUs running Go code at the same time, 0 to use the CPU limit of the
+	// container, or the CPUs of the host without a limit. The GOMAXPROCS env var takes precedence
+	GOMAXPROCS int `mapstructure:"GOMAXPROCS"`
+	// MemoryLimitRatio is the part of the memory limit of the container used as the soft memory limit of the Go
+	// runtime, so the GC runs more often before the container runs out of memory. 0 doesn't set it, the GOMEMLIMIT
+	// env var takes precedence
+	MemoryLimitRatio float64 `mapstructure:"MemoryLimitRatio"`
+	// DBConnsPerCPU is the number of connections per CPU of the pools of the databases with MaxConns 0
+	DBConnsPerCPU int `mapstructure:"DBConnsPerCPU"`
+	// DBMinConns is the min number of connections of the pools sized by CPU
+	DBMinConns int `mapstructure:"DBMinConns"`
+	// DBMaxConns is the max number of connections of the pools sized by CPU
+	DBMaxConns int `mapstructure:"DBMaxConns"`
+	// WorkersPerCPU is the number of workers per CPU of the webhooks and the push notifications with Workers 0
+	WorkersPerCPU int `mapstructure:"WorkersPerCPU"`
+}
@@ -0,0 +1,171 @@
+// Package tuning sizes the Go runtime, the database pools and the workers for the CPU and memory limits of the
+// container, read from its cgroup, so the same config runs from a pod of a CPU to a host of many cores.
+package tuning
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const (
+	// cgroupRoot is the mount point of the cgroup of the container
+	cgroupRoot = "/sys/fs/cgroup"
+	// unlimitedMemory is the lower bound of the memory limits of the cgroup v1 that mean no limit
+	unlimitedMemory = int64(1) << 62
+)
+
+// Resources are the CPU and memory limits of the container, 0 without a limit.
+type Resources struct {
+	CPUs        float64
+	MemoryLimit int64
+}
+
+// Detect reads the limits of the cgroup v2 or v1 of the process.
+func Detect() Resources {
+	return detect(cgroupRoot)
+}
+
+func detect(root string) Resources {
+	var res Resources
+	// cgroup v2
+	if fields := readFields(filepath.Join(root, "cpu.max")); len(fields) == 2 { //nolint:gomnd
+		res.CPUs = quotaCPUs(fields[0], fields[1])
+	} else if quota := readFields(filepath.Join(root, "cpu", "cpu.cfs_quota_us")); len(quota) == 1 {
+		// cgroup v1
+		if period := readFields(filepath.Join(root, "cpu", "cpu.cfs_period_us")); len(period) == 1 {
+			res.CPUs = quotaCPUs(quota[0], period[0])
+		}
+	}
+	for _, path := range []string{filepath.Join(root, "memory.max"), filepath.Join(root, "memory", "memory.limit_in_bytes")} {
+		if fields := readFields(path); len(fields) == 1 {
+			if limit, err := strconv.ParseInt(fields[0], 10, 64); err == nil && limit > 0 && limit < unlimitedMemory {
+				res.MemoryLimit = limit
+			}
+			break
+		}
+	}
+	return res
+}
+
+// quotaCPUs returns the CPUs of a quota of CPU time per period, 0 without a quota.
+func quotaCPUs(quota, period string) float64 {
+	if quota == "max" || quota == "-1" {
+		return 0
+	}
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+func readFields(path string) []string {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(data))
+}
+
+// Tuner sizes the runtime, the pools and the workers for the resources of the container.
+type Tuner struct {
+	cfg       Config
+	resources Resources
+	hostCPUs  int
+	procs     int
+}
+
+// NewTuner creates a new tuner for the resources of the container.
+func NewTuner(cfg Config) *Tuner {
+	return newTuner(cfg, Detect(), runtime.NumCPU())
+}
+
+func newTuner(cfg Config, resources Resources, hostCPUs int) *Tuner {
+	t := &Tuner{cfg: cfg, resources: resources, hostCPUs: hostCPUs}
+	t.procs = t.maxProcs()
+	return t
+}
+
+// maxProcs returns the GOMAXPROCS of the config, or the CPU limit rounded down, so the process isn't throttled,
+// bounded by the CPUs of the host.
+func (t *Tuner) maxProcs() int {
+	if t.cfg.GOMAXPROCS > 0 {
+		return t.cfg.GOMAXPROCS
+	}
+	procs := t.hostCPUs
+	if t.resources.CPUs > 0 {
+		procs = int(math.Floor(t.resources.CPUs))
+	}
+	if procs < 1 {
+		procs = 1
+	}
+	if procs > t.hostCPUs {
+		procs = t.hostCPUs
+	}
+	return procs
+}
+
+// memoryLimit returns the soft memory limit of the runtime, 0 to not set it.
+func (t *Tuner) memoryLimit() int64 {
+	if t.cfg.MemoryLimitRatio <= 0 || t.resources.MemoryLimit == 0 {
+		return 0
+	}
+	return int64(float64(t.resources.MemoryLimit) * math.Min(t.cfg.MemoryLimitRatio, 1))
+}
+
+// Apply sets the GOMAXPROCS and the soft memory limit of the runtime, unless they are set by their env vars.
+func (t *Tuner) Apply() {
+	if _, set := os.LookupEnv("GOMAXPROCS"); set {
+		t.procs = runtime.GOMAXPROCS(0)
+	} else {
+		runtime.GOMAXPROCS(t.procs)
+	}
+	memoryLimit := int64(0)
+	if _, set := os.LookupEnv("GOMEMLIMIT"); !set {
+		if memoryLimit = t.memoryLimit(); memoryLimit > 0 {
+			debug.SetMemoryLimit(memoryLimit)
+		}
+	}
+	log.Infof("runtime tuned for %.2f CPUs and %d bytes of memory limit of the container: GOMAXPROCS %d, memory limit %d",
+		t.resources.CPUs, t.resources.MemoryLimit, t.procs, memoryLimit)
+}
+
+// DBConns returns the configured size of a database pool, or the size for the CPUs if it's 0.
+func (t *Tuner) DBConns(configured int) int {
+	if configured > 0 {
+		return configured
+	}
+	conns := t.cfg.DBConnsPerCPU * t.procs
+	if conns < t.cfg.DBMinConns {
+		conns = t.cfg.DBMinConns
+	}
+	if t.cfg.DBMaxConns > 0 && conns > t.cfg.DBMaxConns {
+		conns = t.cfg.DBMaxConns
+	}
+	if conns < 1 {
+		conns = 1
+	}
+	return conns
+}
+
+// Workers returns the configured number of workers, or the number for the CPUs if it's 0.
+func (t *Tuner) Workers(configured int) int {
+	if configured > 0 {
+		return configured
+	}
+	if workers := t.cfg.WorkersPerCPU * t.procs; workers > 0 {
+		return workers
+	}
+	return 1
+}
@@ -0,0 +1,66 @@
+package tuning
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func TestDetect(t *testing.T) {
+	// cgroup v2
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "cpu.max"), "150000 100000\n")
+	writeFile(t, filepath.Join(root, "memory.max"), "536870912\n")
+	require.Equal(t, Resources{CPUs: 1.5, MemoryLimit: 536870912}, detect(root))
+	writeFile(t, filepath.Join(root, "cpu.max"), "max 100000\n")
+	writeFile(t, filepath.Join(root, "memory.max"), "max\n")
+	require.Equal(t, Resources{}, detect(root))
+
+	// cgroup v1
+	root = t.TempDir()
+	writeFile(t, filepath.Join(root, "cpu", "cpu.cfs_quota_us"), "400000\n")
+	writeFile(t, filepath.Join(root, "cpu", "cpu.cfs_period_us"), "100000\n")
+	writeFile(t, filepath.Join(root, "memory", "memory.limit_in_bytes"), "1073741824\n")
+	require.Equal(t, Resources{CPUs: 4, MemoryLimit: 1073741824}, detect(root))
+	writeFile(t, filepath.Join(root, "cpu", "cpu.cfs_quota_us"), "-1\n")
+	writeFile(t, filepath.Join(root, "memory", "memory.limit_in_bytes"), "9223372036854771712\n")
+	require.Equal(t, Resources{}, detect(root))
+
+	// Without cgroup
+	require.Equal(t, Resources{}, detect(t.TempDir()))
+}
+
+func TestTuner(t *testing.T) {
+	cfg := Config{MemoryLimitRatio: 0.9, DBConnsPerCPU: 4, DBMinConns: 4, DBMaxConns: 64, WorkersPerCPU: 2}
+
+	// A pod of half a CPU
+	tuner := newTuner(cfg, Resources{CPUs: 0.5, MemoryLimit: 1000}, 32)
+	require.Equal(t, 1, tuner.procs)
+	require.Equal(t, int64(900), tuner.memoryLimit())
+	require.Equal(t, 4, tuner.DBConns(0))
+	require.Equal(t, 20, tuner.DBConns(20))
+	require.Equal(t, 2, tuner.Workers(0))
+	require.Equal(t, 3, tuner.Workers(3))
+
+	// A host of 32 CPUs without limits
+	tuner = newTuner(cfg, Resources{}, 32)
+	require.Equal(t, 32, tuner.procs)
+	require.Equal(t, int64(0), tuner.memoryLimit())
+	require.Equal(t, 64, tuner.DBConns(0))
+	require.Equal(t, 64, tuner.Workers(0))
+
+	// A limit above the CPUs of the host, and the GOMAXPROCS of the config
+	tuner = newTuner(cfg, Resources{CPUs: 8}, 4)
+	require.Equal(t, 4, tuner.procs)
+	cfg.GOMAXPROCS = 6
+	tuner = newTuner(cfg, Resources{CPUs: 8}, 4)
+	require.Equal(t, 6, tuner.procs)
+	require.Equal(t, 24, tuner.DBConns(0))
+}
@@ -8,17 +8,19 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-bridge-service/bridgectrl/pb"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/receipt"
 	"github.com/0xPolygonHermez/zkevm-bridge-service/test/mocksmartcontracts/BridgeMessageReceiver"
 	zkevmtypes "github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmbridge"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmglobalexitroot"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/test/contracts/bin/ERC20"
-	ops "github.com/0xPolygonHermez/zkevm-node/test/operations"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -43,6 +45,11 @@ type Client struct {
 	// Client ethclient
 	*ethclient.Client
 	bridge *polygonzkevmbridge.Polygonzkevmbridge
+	// gerManager is the global exit root manager of the bridge, loaded on first use
+	gerManager     *polygonzkevmglobalexitroot.Polygonzkevmglobalexitroot
+	gerManagerLock sync.Mutex
+	// Receipts waits for the txs sent by the client, decoding the errors of the bridge in the revert reasons
+	Receipts *receipt.Watcher
 }
 
 // NewClient creates client.
@@ -55,10 +62,18 @@ func NewClient(ctx context.Context, nodeURL string, bridgeSCAddr common.Address)
 	if len(bridgeSCAddr) != 0 {
 		br, err = polygonzkevmbridge.NewPolygonzkevmbridge(bridgeSCAddr, client)
 	}
+	if err != nil {
+		return nil, err
+	}
+	bridgeABI, err := polygonzkevmbridge.PolygonzkevmbridgeMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
 	return &Client{
-		Client: client,
-		bridge: br,
-	}, err
+		Client:   client,
+		bridge:   br,
+		Receipts: receipt.NewWatcher(client, receipt.DefaultInterval, bridgeABI),
+	}, nil
 }
 
 // GetSigner returns a transaction signer.
@@ -103,6 +118,47 @@ func (c *Client) CheckTxWasMined(ctx context.Context, txHash common.Hash) (bool,
 	return true, receipt, nil
 }
 
+// RevertReason returns the decoded revert reason of a failed tx, replaying it at its block.
+func (c *Client) RevertReason(ctx context.Context, tx *types.Transaction, blockNumber *big.Int) (string, error) {
+	return c.Receipts.RevertReason(ctx, tx, blockNumber)
+}
+
+// IsClaimed checks the claim bitmap of the bridge for the deposit, so the deposits claimed by other txs are not
+// claimed again.
+func (c *Client) IsClaimed(ctx context.Context, depositCount uint) (bool, error) {
+	return c.bridge.IsClaimed(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(uint64(depositCount)))
+}
+
+// ClaimedBitMap returns the word of the claim bitmap of the bridge at the position, with the claim bits of the
+// 256 deposits from wordPos*256.
+func (c *Client) ClaimedBitMap(ctx context.Context, wordPos uint) (*big.Int, error) {
+	return c.bridge.ClaimedBitMap(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(uint64(wordPos)))
+}
+
+// IsGlobalExitRootAvailable checks if the global exit root is already set in the global exit root
+// manager used by the bridge, so the claims against it don't revert.
+func (c *Client) IsGlobalExitRootAvailable(ctx context.Context, globalExitRoot common.Hash) (bool, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	c.gerManagerLock.Lock()
+	if c.gerManager == nil {
+		gerManagerAddr, err := c.bridge.GlobalExitRootManager(opts)
+		if err == nil {
+			c.gerManager, err = polygonzkevmglobalexitroot.NewPolygonzkevmglobalexitroot(gerManagerAddr, c.Client)
+		}
+		if err != nil {
+			c.gerManagerLock.Unlock()
+			return false, err
+		}
+	}
+	gerManager := c.gerManager
+	c.gerManagerLock.Unlock()
+	timestamp, err := gerManager.GlobalExitRootMap(opts, globalExitRoot)
+	if err != nil {
+		return false, err
+	}
+	return timestamp.Sign() != 0, nil
+}
+
 // DeployERC20 deploys erc20 smc.
 func (c *Client) DeployERC20(ctx context.Context, name, symbol string, auth *bind.TransactOpts) (common.Address, *ERC20.ERC20, error) {
 	const txMinedTimeoutLimit = 60 * time.Second
@@ -110,7 +166,7 @@ func (c *Client) DeployERC20(ctx context.Context, name, symbol string, auth *bin
 	if err != nil {
 		return common.Address{}, nil, err
 	}
-	err = WaitTxToBeMined(ctx, c.Client, tx, txMinedTimeoutLimit)
+	err = c.WaitTxToBeMined(ctx, tx, txMinedTimeoutLimit)
 
 	return addr, instance, err
 }
@@ -122,7 +178,7 @@ func (c *Client) DeployBridgeMessageReceiver(ctx context.Context, auth *bind.Tra
 	if err != nil {
 		return common.Address{}, err
 	}
-	err = WaitTxToBeMined(ctx, c.Client, tx, txMinedTimeoutLimit)
+	err = c.WaitTxToBeMined(ctx, tx, txMinedTimeoutLimit)
 
 	return addr, err
 }
@@ -138,7 +194,7 @@ func (c *Client) ApproveERC20(ctx context.Context, erc20Addr, spender common.Add
 		return err
 	}
 	const txMinedTimeoutLimit = 60 * time.Second
-	return WaitTxToBeMined(ctx, c.Client, tx, txMinedTimeoutLimit)
+	return c.WaitTxToBeMined(ctx, tx, txMinedTimeoutLimit)
 }
 
 // MintERC20 mint erc20 tokens.
@@ -152,13 +208,21 @@ func (c *Client) MintERC20(ctx context.Context, erc20Addr common.Address, amount
 		return err
 	}
 	const txMinedTimeoutLimit = 60 * time.Second
-	return WaitTxToBeMined(ctx, c.Client, tx, txMinedTimeoutLimit)
+	return c.WaitTxToBeMined(ctx, tx, txMinedTimeoutLimit)
 }
 
 // SendBridgeAsset sends a bridge asset transaction.
 func (c *Client) SendBridgeAsset(ctx context.Context, tokenAddr common.Address, amount *big.Int, destNetwork uint32,
 	destAddr *common.Address, metadata []byte, auth *bind.TransactOpts,
 ) error {
+	_, err := c.BridgeAsset(ctx, tokenAddr, amount, destNetwork, destAddr, metadata, auth)
+	return err
+}
+
+// BridgeAsset sends a bridge asset transaction and returns it once it's mined.
+func (c *Client) BridgeAsset(ctx context.Context, tokenAddr common.Address, amount *big.Int, destNetwork uint32,
+	destAddr *common.Address, metadata []byte, auth *bind.TransactOpts,
+) (*types.Transaction, error) {
 	emptyAddr := common.Address{}
 	if tokenAddr == emptyAddr {
 		auth.Value = amount
@@ -169,11 +233,11 @@ func (c *Client) SendBridgeAsset(ctx context.Context, tokenAddr common.Address,
 	tx, err := c.bridge.BridgeAsset(auth, destNetwork, *destAddr, amount, tokenAddr, true, metadata)
 	if err != nil {
 		log.Error("Error: ", err)
-		return err
+		return nil, err
 	}
 	// wait transfer to be included in a batch
 	const txTimeout = 60 * time.Second
-	return WaitTxToBeMined(ctx, c.Client, tx, txTimeout)
+	return tx, c.WaitTxToBeMined(ctx, tx, txTimeout)
 }
 
 // SendBridgeMessage sends a bridge message transaction.
@@ -187,7 +251,7 @@ func (c *Client) SendBridgeMessage(ctx context.Context, destNetwork uint32, dest
 	}
 	// wait transfer to be included in a batch
 	const txTimeout = 60 * time.Second
-	return WaitTxToBeMined(ctx, c.Client, tx, txTimeout)
+	return c.WaitTxToBeMined(ctx, tx, txTimeout)
 }
 
 // BuildSendClaim builds a tx data to be sent to the bridge method SendClaim.
@@ -243,10 +307,25 @@ func (c *Client) SendClaim(ctx context.Context, deposit *pb.Deposit, smtProof [m
 
 	// wait transfer to be mined
 	const txTimeout = 60 * time.Second
-	return WaitTxToBeMined(ctx, c.Client, tx, txTimeout)
+	return c.WaitTxToBeMined(ctx, tx, txTimeout)
+}
+
+// WaitTxToBeMined waits until a tx sent by the client is mined, returning a *receipt.RevertError if it fails.
+func (c *Client) WaitTxToBeMined(ctx context.Context, tx *types.Transaction, timeout time.Duration) error {
+	return waitTxToBeMined(ctx, c.Receipts, tx, timeout)
 }
 
-// WaitTxToBeMined waits until a tx is mined or forged.
+// WaitTxToBeMined waits until a tx is mined or forged, returning a *receipt.RevertError if it fails.
 func WaitTxToBeMined(ctx context.Context, client *ethclient.Client, tx *types.Transaction, timeout time.Duration) error {
-	return ops.WaitTxToBeMined(ctx, client, tx, timeout)
+	return waitTxToBeMined(ctx, receipt.NewWatcher(client, receipt.DefaultInterval), tx, timeout)
+}
+
+func waitTxToBeMined(ctx context.Context, watcher *receipt.Watcher, tx *types.Transaction, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if _, err := watcher.Wait(ctx, tx); err != nil {
+		return fmt.Errorf("error waiting the tx %s to be mined: %w", tx.Hash().String(), err)
+	}
+	log.Debug("Transaction successfully mined: ", tx.Hash())
+	return nil
 }
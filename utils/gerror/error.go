@@ -1,10 +1,26 @@
 package gerror
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	// ErrStorageNotFound is used when the object is not found in the storage
-	ErrStorageNotFound = errors.New("not found in the Storage")
+	// ErrNotFound is used when the object is not found, in the storage or in the network
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyProcessed is used when the object was already stored or processed
+	ErrAlreadyProcessed = errors.New("already processed")
+	// ErrReorgDetected is used when the data being processed belongs to a reorganized block
+	ErrReorgDetected = errors.New("reorg detected")
+	// ErrProviderLimit is used when the rpc provider rejects a request because of its rate or usage limits
+	ErrProviderLimit = errors.New("rpc provider limit exceeded")
+	// ErrQuorumNotReached is used when not enough rpc providers return the same value of a critical read
+	ErrQuorumNotReached = errors.New("rpc providers quorum not reached")
+	// ErrDepositGap is used when a deposit count skips values, so the events of the deposits in between were missed
+	ErrDepositGap = errors.New("deposit count gap")
+
+	// ErrStorageNotFound is used when the object is not found in the Storage
+	ErrStorageNotFound = fmt.Errorf("%w in the Storage", ErrNotFound)
 	// ErrStorageNotRegister is used when the object is not found in the synchronizer
 	ErrStorageNotRegister = errors.New("not registered storage")
 	// ErrNilDBTransaction indicates the db transaction has not been properly initialized
@@ -15,4 +31,33 @@ var (
 	ErrDepositNotSynced = errors.New("not synchronized deposit")
 	// ErrNetworkNotRegister is used when the networkID is not registered in the bridge
 	ErrNetworkNotRegister = errors.New("not registered network")
+	// ErrInvalidAdminQuery is used when the admin query is unknown or its params are invalid
+	ErrInvalidAdminQuery = errors.New("invalid admin query")
 )
+
+// domainError tags an error with one of the sentinel errors, keeping the original error in the chain.
+type domainError struct {
+	kind error
+	err  error
+}
+
+func (e *domainError) Error() string {
+	return e.kind.Error() + ": " + e.err.Error()
+}
+
+func (e *domainError) Is(target error) bool {
+	return errors.Is(e.kind, target)
+}
+
+func (e *domainError) Unwrap() error {
+	return e.err
+}
+
+// Wrap tags err with the sentinel error kind, so the callers can check it with errors.Is
+// while errors.As still finds the original error. It returns nil if err is nil.
+func Wrap(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &domainError{kind: kind, err: err}
+}
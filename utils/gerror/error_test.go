@@ -0,0 +1,28 @@
+package gerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type causeError struct{}
+
+func (causeError) Error() string { return "cause" }
+
+func TestWrap(t *testing.T) {
+	require.NoError(t, Wrap(ErrProviderLimit, nil))
+
+	err := fmt.Errorf("getting logs: %w", Wrap(ErrProviderLimit, causeError{}))
+	require.ErrorIs(t, err, ErrProviderLimit)
+	require.False(t, errors.Is(err, ErrReorgDetected))
+	var cause causeError
+	require.True(t, errors.As(err, &cause))
+	require.Equal(t, "getting logs: rpc provider limit exceeded: cause", err.Error())
+
+	require.ErrorIs(t, ErrStorageNotFound, ErrNotFound)
+	require.ErrorIs(t, Wrap(ErrStorageNotFound, causeError{}), ErrNotFound)
+	require.Equal(t, "not found in the Storage", ErrStorageNotFound.Error())
+}
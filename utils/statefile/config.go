@@ -0,0 +1,12 @@
+package statefile
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+// Config is the configuration of the in-memory state file
+type Config struct {
+	// Path is the file where the in-memory state is dumped on shutdown and restored at startup.
+	// Empty value disables the state file
+	Path string `mapstructure:"Path"`
+	// MaxAge is the max age of the state file to be restored. Older states are ignored
+	MaxAge types.Duration `mapstructure:"MaxAge"`
+}
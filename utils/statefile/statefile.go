@@ -0,0 +1,102 @@
+package statefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const stateFileVersion = 1
+
+// Exporter is implemented by the modules that keep in-memory state that is worth
+// keeping between restarts.
+type Exporter interface {
+	// StateKey identifies the module in the state file
+	StateKey() string
+	// ExportState returns the in-memory state of the module
+	ExportState() (json.RawMessage, error)
+	// ImportState restores the in-memory state of the module
+	ImportState(state json.RawMessage) error
+}
+
+type stateFile struct {
+	Version int                        `json:"version"`
+	SavedAt time.Time                  `json:"savedAt"`
+	Modules map[string]json.RawMessage `json:"modules"`
+}
+
+// Save dumps the state of the modules to the state file. The file is written in a temporary
+// file first and renamed, so a crash while saving doesn't leave a corrupted state file.
+func Save(cfg Config, modules []Exporter) error {
+	if cfg.Path == "" {
+		return nil
+	}
+	state := stateFile{
+		Version: stateFileVersion,
+		SavedAt: time.Now(),
+		Modules: make(map[string]json.RawMessage, len(modules)),
+	}
+	for _, m := range modules {
+		data, err := m.ExportState()
+		if err != nil {
+			return fmt.Errorf("error exporting the state of %s: %w", m.StateKey(), err)
+		}
+		state.Modules[m.StateKey()] = data
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmpFile := cfg.Path + ".tmp"
+	if err := os.WriteFile(filepath.Clean(tmpFile), data, 0600); err != nil { //nolint:gomnd
+		return err
+	}
+	if err := os.Rename(tmpFile, cfg.Path); err != nil {
+		return err
+	}
+	log.Infof("in-memory state of %d modules saved in %s", len(modules), cfg.Path)
+	return nil
+}
+
+// Load restores the state of the modules from the state file. A missing, outdated or
+// unknown version state file is ignored, because the state is only a warm-up optimization.
+func Load(cfg Config, modules []Exporter) error {
+	if cfg.Path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Clean(cfg.Path))
+	if errors.Is(err, os.ErrNotExist) {
+		log.Infof("state file %s not found, starting with empty in-memory state", cfg.Path)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var state stateFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("error decoding the state file %s: %w", cfg.Path, err)
+	}
+	if state.Version != stateFileVersion {
+		log.Warnf("ignoring state file %s with version %d, expected version %d", cfg.Path, state.Version, stateFileVersion)
+		return nil
+	}
+	if cfg.MaxAge.Duration > 0 && time.Since(state.SavedAt) > cfg.MaxAge.Duration {
+		log.Warnf("ignoring state file %s saved at %s, it's older than %s", cfg.Path, state.SavedAt, cfg.MaxAge.Duration)
+		return nil
+	}
+	for _, m := range modules {
+		moduleState, found := state.Modules[m.StateKey()]
+		if !found {
+			continue
+		}
+		if err := m.ImportState(moduleState); err != nil {
+			return fmt.Errorf("error importing the state of %s: %w", m.StateKey(), err)
+		}
+	}
+	log.Infof("in-memory state restored from %s, saved at %s", cfg.Path, state.SavedAt)
+	return nil
+}
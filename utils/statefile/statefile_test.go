@@ -0,0 +1,60 @@
+package statefile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testModule struct {
+	key   string
+	Value map[string]uint64
+}
+
+func (m *testModule) StateKey() string {
+	return m.key
+}
+
+func (m *testModule) ExportState() (json.RawMessage, error) {
+	return json.Marshal(m.Value)
+}
+
+func (m *testModule) ImportState(state json.RawMessage) error {
+	return json.Unmarshal(state, &m.Value)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	cfg := Config{Path: filepath.Join(t.TempDir(), "state.json"), MaxAge: types.Duration{Duration: time.Hour}}
+
+	// Missing file is not an error
+	require.NoError(t, Load(cfg, nil))
+
+	saved := &testModule{key: "a", Value: map[string]uint64{"0x1": 5}}
+	other := &testModule{key: "b", Value: map[string]uint64{"0x2": 7}}
+	require.NoError(t, Save(cfg, []Exporter{saved, other}))
+
+	restored := &testModule{key: "a"}
+	unknown := &testModule{key: "c"}
+	require.NoError(t, Load(cfg, []Exporter{restored, unknown}))
+	require.Equal(t, saved.Value, restored.Value)
+	require.Nil(t, unknown.Value)
+
+	// Outdated state is ignored
+	cfg.MaxAge = types.Duration{Duration: time.Nanosecond}
+	restored = &testModule{key: "a"}
+	require.NoError(t, Load(cfg, []Exporter{restored}))
+	require.Nil(t, restored.Value)
+
+	// Corrupted state file
+	require.NoError(t, os.WriteFile(cfg.Path, []byte("{"), 0600))
+	require.Error(t, Load(cfg, []Exporter{restored}))
+
+	// Disabled
+	require.NoError(t, Save(Config{}, []Exporter{saved}))
+	require.NoError(t, Load(Config{}, []Exporter{saved}))
+}
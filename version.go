@@ -22,4 +22,5 @@ func PrintVersion(w io.Writer) {
 	fmt.Fprintf(w, "Go version:   %s\n", runtime.Version())
 	fmt.Fprintf(w, "Built:        %s\n", BuildDate)
 	fmt.Fprintf(w, "OS/Arch:      %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(w, "Read-only:    %t\n", ReadOnly)
 }
@@ -0,0 +1,61 @@
+package watchdog
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
+// Config is the configuration of the stuck deposits watchdog
+type Config struct {
+	// Enabled runs the watchdog periodically, alerting on the deposits stuck in a status
+	Enabled bool `mapstructure:"Enabled"`
+	// Interval is the time between two checks of the deposits
+	Interval types.Duration `mapstructure:"Interval"`
+	// L1IndexedSLA is the max time a L1 deposit waits for its global exit root in the L2, 0 to not check it
+	L1IndexedSLA types.Duration `mapstructure:"L1IndexedSLA"`
+	// L2IndexedSLA is the max time a L2 deposit waits for its batch to be verified, 0 to not check it
+	L2IndexedSLA types.Duration `mapstructure:"L2IndexedSLA"`
+	// ReadyForClaimSLA is the max time a deposit ready for claim waits for its claim, 0 to not check it
+	ReadyForClaimSLA types.Duration `mapstructure:"ReadyForClaimSLA"`
+	// MaxDeposits is the max number of deposits of each status listed in the alerts
+	MaxDeposits uint `mapstructure:"MaxDeposits"`
+	// AlertURL receives the alerts as a JSON POST, empty to only log them
+	AlertURL string `mapstructure:"AlertURL"`
+	// Timeout is the max time of a POST of the alerts
+	Timeout types.Duration `mapstructure:"Timeout"`
+	// Stages are the SLAs of the stages of the deposits, checked when each deposit reaches them
+	Stages StagesConfig `mapstructure:"Stages"`
+}
+
+// StagesConfig are the max times the deposits take to reach each stage, 0 to not check a stage.
+type StagesConfig struct {
+	// Indexed is the max time between the block of a deposit and its sync
+	Indexed types.Duration `mapstructure:"Indexed"`
+	// Ready is the max time between the block of a deposit and its change to ready for claim
+	Ready types.Duration `mapstructure:"Ready"`
+	// Claimed is the max time between the change of a deposit to ready for claim and the sync of its claim
+	Claimed types.Duration `mapstructure:"Claimed"`
+}
+
+// Enabled returns if any stage is checked.
+func (c StagesConfig) Enabled() bool {
+	return c.Indexed.Duration > 0 || c.Ready.Duration > 0 || c.Claimed.Duration > 0
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if c.Interval.Duration <= 0 {
+		return fmt.Errorf("the interval of the watchdog must be positive")
+	}
+	if c.MaxDeposits == 0 {
+		return fmt.Errorf("the max deposits of the watchdog must be positive")
+	}
+	if c.AlertURL != "" {
+		if _, err := url.ParseRequestURI(c.AlertURL); err != nil {
+			return fmt.Errorf("invalid alert url of the watchdog: %w", err)
+		}
+	}
+	return nil
+}
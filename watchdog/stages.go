@@ -0,0 +1,226 @@
+package watchdog
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Stages of the deposits
+const (
+	// StageIndexed is the sync of the deposit
+	StageIndexed = "indexed"
+	// StageReady is the change of the deposit to ready for claim
+	StageReady = "ready_for_claim"
+	// StageClaimed is the sync of the claim of the deposit
+	StageClaimed = "claimed"
+)
+
+// maxTrackedDeposits is the max number of deposits whose times are kept until their next stage
+const maxTrackedDeposits = 100000
+
+// StageViolations counts the deposits that reached a stage beyond its SLA.
+var StageViolations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "bridge_sla_violations_total",
+	Help: "Number of deposits that reached a stage later than its SLA",
+}, []string{"stage", "network_id"})
+
+// Violation is a deposit that reached a stage beyond its SLA.
+type Violation struct {
+	Stage              string      `json:"stage"`
+	NetworkID          uint        `json:"network_id"`
+	DepositCount       uint        `json:"deposit_cnt"`
+	DestinationNetwork uint        `json:"dest_net"`
+	TxHash             common.Hash `json:"tx_hash"`
+	SLA                string      `json:"sla"`
+	// Elapsed is the time the deposit took to reach the stage
+	Elapsed string `json:"elapsed"`
+	// ReachedAt is the time the deposit reached the stage
+	ReachedAt time.Time `json:"reached_at"`
+}
+
+// trackedDeposit are the times of a deposit kept until its next stage.
+type trackedDeposit struct {
+	networkID    uint
+	depositCount uint
+	txHash       common.Hash
+	blockTime    time.Time
+	readyAt      time.Time
+}
+
+// depositKey is the key of a tracked deposit, its destination network, its deposit count and its origin network,
+// as the claims reference them.
+type depositKey [3]uint
+
+func keyOfDeposit(deposit *etherman.Deposit) depositKey {
+	return depositKey{deposit.DestinationNetwork, deposit.DepositCount, deposit.OriginalNetwork}
+}
+
+// pendingReady is a deposit ready for claim whose block time is read from the storage by the next check.
+type pendingReady struct {
+	deposit *etherman.Deposit
+	readyAt time.Time
+}
+
+// OnDepositIndexed implements hooks.Hook.
+func (w *Watchdog) OnDepositIndexed(_ context.Context, deposit *etherman.Deposit) {
+	if deposit.BlockTime.IsZero() {
+		return
+	}
+	syncedAt := deposit.SyncedAt
+	if syncedAt.IsZero() {
+		syncedAt = w.now()
+	}
+	w.checkStage(StageIndexed, w.cfg.Stages.Indexed.Duration, deposit, syncedAt.Sub(deposit.BlockTime), syncedAt)
+	w.track(deposit, func(d *trackedDeposit) { d.blockTime = deposit.BlockTime })
+}
+
+// OnDepositReady implements hooks.Hook.
+func (w *Watchdog) OnDepositReady(_ context.Context, deposit *etherman.Deposit) {
+	w.depositReady(deposit)
+}
+
+// OnL2DepositReady implements hooks.Hook.
+func (w *Watchdog) OnL2DepositReady(_ context.Context, deposit *etherman.Deposit) {
+	w.depositReady(deposit)
+}
+
+func (w *Watchdog) depositReady(deposit *etherman.Deposit) {
+	now := w.now()
+	var blockTime time.Time
+	w.mu.Lock()
+	tracked, found := w.tracked[keyOfDeposit(deposit)]
+	if found && tracked.networkID == deposit.NetworkID {
+		blockTime = tracked.blockTime
+	}
+	if blockTime.IsZero() && w.cfg.Stages.Ready.Duration > 0 && len(w.pendingReady) < maxTrackedDeposits {
+		// The deposit was indexed before the start, its block time is read by the next check instead of in the hook
+		w.pendingReady = append(w.pendingReady, pendingReady{deposit: deposit, readyAt: now})
+	}
+	w.mu.Unlock()
+	if !blockTime.IsZero() {
+		w.checkStage(StageReady, w.cfg.Stages.Ready.Duration, deposit, now.Sub(blockTime), now)
+	}
+	w.track(deposit, func(d *trackedDeposit) { d.readyAt = now })
+}
+
+// checkPendingReady checks the ready stage of the deposits indexed before the start, with their block time read
+// from the storage.
+func (w *Watchdog) checkPendingReady(ctx context.Context) {
+	w.mu.Lock()
+	pending := w.pendingReady
+	w.pendingReady = nil
+	w.mu.Unlock()
+	for _, p := range pending {
+		stored, err := w.storage.GetDeposit(ctx, p.deposit.DepositCount, p.deposit.NetworkID, nil)
+		if err != nil {
+			log.Warnf("watchdog: error getting the deposit %d of the network %d: %v", p.deposit.DepositCount, p.deposit.NetworkID, err)
+			continue
+		}
+		if !stored.BlockTime.IsZero() {
+			w.checkStage(StageReady, w.cfg.Stages.Ready.Duration, p.deposit, p.readyAt.Sub(stored.BlockTime), p.readyAt)
+		}
+	}
+}
+
+// OnClaimIndexed implements hooks.Hook. The claims of the deposits ready before the start are not checked, nor the
+// claims of the L2 deposits, which are claimed by their users instead of the claim tx manager.
+func (w *Watchdog) OnClaimIndexed(_ context.Context, claim *etherman.Claim) {
+	key := depositKey{claim.NetworkID, claim.Index, claim.OriginalNetwork}
+	w.mu.Lock()
+	tracked, found := w.tracked[key]
+	delete(w.tracked, key)
+	w.mu.Unlock()
+	// The tracked deposit is not updated once it is removed
+	if !found || tracked.readyAt.IsZero() || tracked.networkID != 0 {
+		return
+	}
+	claimedAt := claim.SyncedAt
+	if claimedAt.IsZero() {
+		claimedAt = w.now()
+	}
+	deposit := &etherman.Deposit{
+		NetworkID:          tracked.networkID,
+		DepositCount:       tracked.depositCount,
+		DestinationNetwork: claim.NetworkID,
+		TxHash:             tracked.txHash,
+	}
+	w.checkStage(StageClaimed, w.cfg.Stages.Claimed.Duration, deposit, claimedAt.Sub(tracked.readyAt), claimedAt)
+}
+
+// track updates the times of a deposit, evicting the oldest deposit when there are too many.
+func (w *Watchdog) track(deposit *etherman.Deposit, update func(d *trackedDeposit)) {
+	if !w.cfg.Stages.Enabled() {
+		return
+	}
+	key := keyOfDeposit(deposit)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	tracked, found := w.tracked[key]
+	if !found || tracked.networkID != deposit.NetworkID {
+		tracked = &trackedDeposit{networkID: deposit.NetworkID, depositCount: deposit.DepositCount, txHash: deposit.TxHash}
+		w.tracked[key] = tracked
+		w.trackedOrder = append(w.trackedOrder, key)
+		for len(w.tracked) > maxTrackedDeposits && len(w.trackedOrder) > 0 {
+			delete(w.tracked, w.trackedOrder[0])
+			w.trackedOrder = w.trackedOrder[1:]
+		}
+	}
+	update(tracked)
+	// The order keeps the keys of the claimed deposits until they are the oldest
+	if len(w.trackedOrder) > 2*maxTrackedDeposits {
+		order := make([]depositKey, 0, len(w.tracked))
+		for _, k := range w.trackedOrder {
+			if _, found := w.tracked[k]; found {
+				order = append(order, k)
+			}
+		}
+		w.trackedOrder = order
+	}
+}
+
+// checkStage records a violation if the deposit reached the stage beyond its SLA.
+func (w *Watchdog) checkStage(stage string, sla time.Duration, deposit *etherman.Deposit, elapsed time.Duration, at time.Time) {
+	if sla <= 0 || elapsed <= sla {
+		return
+	}
+	StageViolations.WithLabelValues(stage, strconv.FormatUint(uint64(deposit.NetworkID), 10)).Inc()
+	log.Warnw("deposit stage beyond its sla", "stage", stage, "sla", sla, "elapsed", elapsed,
+		"deposit", strconv.FormatUint(uint64(deposit.NetworkID), 10)+"/"+strconv.FormatUint(uint64(deposit.DepositCount), 10))
+	if w.cfg.AlertURL == "" {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if uint(len(w.violations)) >= w.cfg.MaxDeposits {
+		// The receiver gets the count from the metric, the list is only a sample
+		return
+	}
+	w.violations = append(w.violations, Violation{
+		Stage:              stage,
+		NetworkID:          deposit.NetworkID,
+		DepositCount:       deposit.DepositCount,
+		DestinationNetwork: deposit.DestinationNetwork,
+		TxHash:             deposit.TxHash,
+		SLA:                sla.String(),
+		Elapsed:            elapsed.Round(time.Second).String(),
+		ReachedAt:          at,
+	})
+}
+
+// takeViolations returns the violations recorded since the last post and clears them.
+func (w *Watchdog) takeViolations() []Violation {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	violations := w.violations
+	w.violations = nil
+	return violations
+}
+
+var _ hooks.Hook = (*Watchdog)(nil)
@@ -0,0 +1,269 @@
+// Package watchdog finds the deposits stuck in a status beyond its SLA and alerts on them, grouped by the likely
+// cause, so the operators know if the verification, the global exit root injection or the claims are stalled.
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Likely causes of the stuck deposits
+const (
+	// CauseGERNotInjected is a L1 deposit whose global exit root is not in the L2 yet
+	CauseGERNotInjected = "ger_not_injected"
+	// CauseVerificationStalled is a L2 deposit whose batch is not verified in L1 yet
+	CauseVerificationStalled = "verification_stalled"
+	// CauseClaimReverting is a deposit ready for claim whose claim tx failed, or whose message reverts
+	CauseClaimReverting = "claim_reverting"
+	// CauseClaimAwaitingOperator is a deposit ready for claim whose claim tx waits for an approval or a signature
+	CauseClaimAwaitingOperator = "claim_awaiting_operator"
+	// CauseClaimUneconomical is a deposit ready for claim worth less than the gas of its claim tx, parked until an
+	// operator approves it
+	CauseClaimUneconomical = "claim_uneconomical"
+	// CauseClaimPending is a deposit ready for claim whose claim tx is not mined yet
+	CauseClaimPending = "claim_pending"
+	// CauseNotClaimed is a deposit ready for claim without a claim tx of the claim tx manager
+	CauseNotClaimed = "not_claimed"
+)
+
+var stuckDepositsDesc = prometheus.NewDesc("bridge_stuck_deposits",
+	"Number of deposits stuck in a status beyond its SLA, at most the max deposits of the watchdog", []string{"status", "cause"}, nil)
+
+type storageInterface interface {
+	GetStuckDeposits(ctx context.Context, readyForClaim bool, l1Before, l2Before time.Time, limit uint, dbTx pgx.Tx) ([]*etherman.StuckDeposit, error)
+	GetDeposit(ctx context.Context, depositCounterUser uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error)
+}
+
+// Alert is a group of deposits stuck in the same status with the same likely cause.
+type Alert struct {
+	Status string `json:"status"`
+	Cause  string `json:"cause"`
+	SLA    string `json:"sla"`
+	Count  int    `json:"count"`
+	// OldestSince is the time the oldest deposit of the alert got its status
+	OldestSince time.Time      `json:"oldest_since"`
+	Deposits    []AlertDeposit `json:"deposits"`
+}
+
+// AlertDeposit is a stuck deposit of an alert.
+type AlertDeposit struct {
+	NetworkID          uint        `json:"network_id"`
+	DepositCount       uint        `json:"deposit_cnt"`
+	DestinationNetwork uint        `json:"dest_net"`
+	TxHash             common.Hash `json:"tx_hash"`
+	Since              time.Time   `json:"since"`
+	ClaimTxStatus      string      `json:"claim_tx_status,omitempty"`
+}
+
+// Classify returns the likely cause of a stuck deposit.
+func Classify(deposit *etherman.StuckDeposit) string {
+	if !deposit.ReadyForClaim {
+		if deposit.NetworkID == 0 {
+			return CauseGERNotInjected
+		}
+		return CauseVerificationStalled
+	}
+	switch ctmtypes.MonitoredTxStatus(deposit.ClaimTxStatus) {
+	case "":
+		return CauseNotClaimed
+	case ctmtypes.MonitoredTxStatusFailed, ctmtypes.MonitoredTxStatusMessageReverted:
+		return CauseClaimReverting
+	case ctmtypes.MonitoredTxStatusPendingApproval, ctmtypes.MonitoredTxStatusPendingSignature:
+		return CauseClaimAwaitingOperator
+	case ctmtypes.MonitoredTxStatusUneconomical:
+		return CauseClaimUneconomical
+	default:
+		return CauseClaimPending
+	}
+}
+
+// Watchdog checks the stuck deposits periodically. It implements hooks.Hook, so the stages of the deposits are
+// checked when they are reached.
+type Watchdog struct {
+	hooks.NopHook
+	cfg     Config
+	storage storageInterface
+	client  *http.Client
+	now     func() time.Time
+
+	mu           sync.Mutex
+	alerts       []Alert
+	violations   []Violation
+	tracked      map[depositKey]*trackedDeposit
+	trackedOrder []depositKey
+	pendingReady []pendingReady
+}
+
+// NewWatchdog creates a new stuck deposits watchdog.
+func NewWatchdog(cfg Config, storage interface{}) (*Watchdog, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Watchdog{
+		cfg:     cfg,
+		storage: storage.(storageInterface),
+		client:  &http.Client{Timeout: cfg.Timeout.Duration},
+		now:     time.Now,
+		tracked: make(map[depositKey]*trackedDeposit),
+	}, nil
+}
+
+// Start checks the deposits every interval until the context is done.
+func (w *Watchdog) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		if err := w.run(ctx); err != nil {
+			log.Warnf("watchdog error: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watchdog) run(ctx context.Context) error {
+	w.checkPendingReady(ctx)
+	alerts, err := w.Check(ctx)
+	if err != nil {
+		return err
+	}
+	for _, a := range alerts {
+		deposits := make([]string, 0, len(a.Deposits))
+		for _, d := range a.Deposits {
+			deposits = append(deposits, fmt.Sprintf("%d/%d", d.NetworkID, d.DepositCount))
+		}
+		log.Warnw("stuck deposits", "status", a.Status, "cause", a.Cause, "sla", a.SLA, "count", a.Count,
+			"oldestSince", a.OldestSince, "deposits", deposits)
+	}
+	violations := w.takeViolations()
+	if w.cfg.AlertURL == "" || (len(alerts) == 0 && len(violations) == 0) {
+		return nil
+	}
+	return w.post(ctx, alerts, violations)
+}
+
+// Check returns the alerts of the deposits stuck beyond the SLAs, the metrics are updated with them.
+func (w *Watchdog) Check(ctx context.Context) ([]Alert, error) {
+	now := w.now()
+	var deposits []*etherman.StuckDeposit
+	if w.cfg.L1IndexedSLA.Duration > 0 || w.cfg.L2IndexedSLA.Duration > 0 {
+		indexed, err := w.storage.GetStuckDeposits(ctx, false, before(now, w.cfg.L1IndexedSLA.Duration), before(now, w.cfg.L2IndexedSLA.Duration), w.cfg.MaxDeposits, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the stuck indexed deposits: %w", err)
+		}
+		deposits = append(deposits, indexed...)
+	}
+	if w.cfg.ReadyForClaimSLA.Duration > 0 {
+		ready, err := w.storage.GetStuckDeposits(ctx, true, before(now, w.cfg.ReadyForClaimSLA.Duration), before(now, w.cfg.ReadyForClaimSLA.Duration), w.cfg.MaxDeposits, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the stuck deposits ready for claim: %w", err)
+		}
+		deposits = append(deposits, ready...)
+	}
+	alerts := w.group(deposits)
+	w.mu.Lock()
+	w.alerts = alerts
+	w.mu.Unlock()
+	return alerts, nil
+}
+
+// group groups the deposits, the oldest first, by status and cause.
+func (w *Watchdog) group(deposits []*etherman.StuckDeposit) []Alert {
+	var alerts []Alert
+	index := make(map[string]int)
+	for _, d := range deposits {
+		status, cause := etherman.DepositStatusIndexed, Classify(d)
+		if d.ReadyForClaim {
+			status = etherman.DepositStatusReadyForClaim
+		}
+		i, found := index[status+"/"+cause]
+		if !found {
+			i = len(alerts)
+			index[status+"/"+cause] = i
+			alerts = append(alerts, Alert{Status: status, Cause: cause, SLA: w.sla(cause).String(), OldestSince: d.Since})
+		}
+		alerts[i].Count++
+		alerts[i].Deposits = append(alerts[i].Deposits, AlertDeposit{
+			NetworkID:          d.NetworkID,
+			DepositCount:       d.DepositCount,
+			DestinationNetwork: d.DestinationNetwork,
+			TxHash:             d.TxHash,
+			Since:              d.Since,
+			ClaimTxStatus:      d.ClaimTxStatus,
+		})
+	}
+	return alerts
+}
+
+func (w *Watchdog) sla(cause string) time.Duration {
+	switch cause {
+	case CauseGERNotInjected:
+		return w.cfg.L1IndexedSLA.Duration
+	case CauseVerificationStalled:
+		return w.cfg.L2IndexedSLA.Duration
+	default:
+		return w.cfg.ReadyForClaimSLA.Duration
+	}
+}
+
+// before returns the time the deposits are stuck before for the sla, the zero time if it's not checked.
+func before(now time.Time, sla time.Duration) time.Time {
+	if sla <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-sla)
+}
+
+func (w *Watchdog) post(ctx context.Context, alerts []Alert, violations []Violation) error {
+	body, err := json.Marshal(struct {
+		Alerts     []Alert     `json:"alerts"`
+		Violations []Violation `json:"violations,omitempty"`
+	}{Alerts: alerts, Violations: violations})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.AlertURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting the alerts: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("error posting the alerts: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (w *Watchdog) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stuckDepositsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (w *Watchdog) Collect(ch chan<- prometheus.Metric) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, a := range w.alerts {
+		ch <- prometheus.MustNewConstMetric(stuckDepositsDesc, prometheus.GaugeValue, float64(a.Count), a.Status, a.Cause)
+	}
+}
@@ -0,0 +1,191 @@
+package watchdog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// stuckStorage returns the deposits with the status stuck before the time of their network, only the L1 ones when
+// they are ready for claim, without the denied ones.
+type stuckStorage struct {
+	deposits []*etherman.StuckDeposit
+}
+
+func (s *stuckStorage) GetStuckDeposits(ctx context.Context, readyForClaim bool, l1Before, l2Before time.Time, limit uint, dbTx pgx.Tx) ([]*etherman.StuckDeposit, error) {
+	var deposits []*etherman.StuckDeposit
+	for _, d := range s.deposits {
+		before := l1Before
+		if d.NetworkID != 0 {
+			before = l2Before
+		}
+		denied := d.ClaimTxStatus == "denied"
+		if d.ReadyForClaim == readyForClaim && (!d.ReadyForClaim || d.NetworkID == 0) && !denied && d.Since.Before(before) && uint(len(deposits)) < limit {
+			deposits = append(deposits, d)
+		}
+	}
+	return deposits, nil
+}
+
+func (s *stuckStorage) GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	for _, d := range s.deposits {
+		if d.NetworkID == networkID && d.DepositCount == depositCnt {
+			return &d.Deposit, nil
+		}
+	}
+	return nil, gerror.ErrStorageNotFound
+}
+
+func stuckDeposit(networkID, depositCount uint, ready bool, since time.Time, claimTxStatus string) *etherman.StuckDeposit {
+	return &etherman.StuckDeposit{
+		Deposit:       etherman.Deposit{NetworkID: networkID, DepositCount: depositCount, ReadyForClaim: ready},
+		Since:         since,
+		ClaimTxStatus: claimTxStatus,
+	}
+}
+
+func TestClassify(t *testing.T) {
+	now := time.Now()
+	require.Equal(t, CauseGERNotInjected, Classify(stuckDeposit(0, 1, false, now, "")))
+	require.Equal(t, CauseVerificationStalled, Classify(stuckDeposit(1, 1, false, now, "")))
+	require.Equal(t, CauseNotClaimed, Classify(stuckDeposit(1, 1, true, now, "")))
+	require.Equal(t, CauseClaimReverting, Classify(stuckDeposit(0, 1, true, now, "failed")))
+	require.Equal(t, CauseClaimAwaitingOperator, Classify(stuckDeposit(0, 1, true, now, "pending_approval")))
+	require.Equal(t, CauseClaimUneconomical, Classify(stuckDeposit(0, 1, true, now, "uneconomical")))
+	require.Equal(t, CauseClaimPending, Classify(stuckDeposit(0, 1, true, now, "created")))
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	storage := &stuckStorage{deposits: []*etherman.StuckDeposit{
+		stuckDeposit(0, 1, false, now.Add(-2*time.Hour), ""),
+		stuckDeposit(0, 2, false, now.Add(-90*time.Minute), ""),
+		// Within the SLA
+		stuckDeposit(0, 3, false, now.Add(-time.Minute), ""),
+		// The L2 indexed deposits are not checked
+		stuckDeposit(1, 1, false, now.Add(-24*time.Hour), ""),
+		stuckDeposit(0, 4, true, now.Add(-time.Hour), "failed"),
+		stuckDeposit(0, 5, true, now.Add(-time.Hour), ""),
+		// The denied deposits are never claimed
+		stuckDeposit(0, 6, true, now.Add(-time.Hour), "denied"),
+		// The L2 deposits ready for claim are claimed by their users
+		stuckDeposit(1, 2, true, now.Add(-time.Hour), ""),
+	}}
+	cfg := Config{
+		Interval:         types.NewDuration(time.Minute),
+		L1IndexedSLA:     types.NewDuration(time.Hour),
+		ReadyForClaimSLA: types.NewDuration(30 * time.Minute),
+		MaxDeposits:      10,
+	}
+	w, err := NewWatchdog(cfg, storage)
+	require.NoError(t, err)
+	w.now = func() time.Time { return now }
+
+	alerts, err := w.Check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, len(alerts))
+	require.Equal(t, etherman.DepositStatusIndexed, alerts[0].Status)
+	require.Equal(t, CauseGERNotInjected, alerts[0].Cause)
+	require.Equal(t, "1h0m0s", alerts[0].SLA)
+	require.Equal(t, 2, alerts[0].Count)
+	require.Equal(t, now.Add(-2*time.Hour), alerts[0].OldestSince)
+	require.Equal(t, uint(2), alerts[0].Deposits[1].DepositCount)
+	require.Equal(t, CauseClaimReverting, alerts[1].Cause)
+	require.Equal(t, "failed", alerts[1].Deposits[0].ClaimTxStatus)
+	require.Equal(t, etherman.DepositStatusReadyForClaim, alerts[2].Status)
+	require.Equal(t, CauseNotClaimed, alerts[2].Cause)
+
+	// The alerts are posted to the url
+	var received struct {
+		Alerts []Alert `json:"alerts"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer srv.Close()
+	w.cfg.AlertURL = srv.URL
+	require.NoError(t, w.run(context.Background()))
+	require.Equal(t, 3, len(received.Alerts))
+	require.Equal(t, CauseGERNotInjected, received.Alerts[0].Cause)
+
+	require.Error(t, Config{Interval: types.NewDuration(time.Minute)}.Validate())
+	require.Error(t, Config{Interval: types.NewDuration(time.Minute), MaxDeposits: 1, AlertURL: "alerts"}.Validate())
+}
+
+func TestStages(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	storage := &stuckStorage{deposits: []*etherman.StuckDeposit{
+		{Deposit: etherman.Deposit{NetworkID: 0, DepositCount: 9, DestinationNetwork: 1, BlockTime: now.Add(-3 * time.Hour)}},
+	}}
+	cfg := Config{
+		Interval:    types.NewDuration(time.Minute),
+		MaxDeposits: 10,
+		AlertURL:    "http://localhost",
+		Stages: StagesConfig{
+			Indexed: types.NewDuration(time.Minute),
+			Ready:   types.NewDuration(time.Hour),
+			Claimed: types.NewDuration(30 * time.Minute),
+		},
+	}
+	w, err := NewWatchdog(cfg, storage)
+	require.NoError(t, err)
+	w.now = func() time.Time { return now }
+	violations := func(stage string) float64 {
+		return testutil.ToFloat64(StageViolations.WithLabelValues(stage, "0"))
+	}
+	indexed, ready, claimed := violations(StageIndexed), violations(StageReady), violations(StageClaimed)
+
+	// Indexed within the SLA, ready and claimed beyond it
+	deposit := &etherman.Deposit{NetworkID: 0, DepositCount: 1, DestinationNetwork: 1, BlockTime: now.Add(-2 * time.Hour), SyncedAt: now.Add(-2*time.Hour + 30*time.Second)}
+	w.OnDepositIndexed(ctx, deposit)
+	require.Equal(t, indexed, violations(StageIndexed))
+	w.OnDepositReady(ctx, &etherman.Deposit{NetworkID: 0, DepositCount: 1, DestinationNetwork: 1})
+	require.Equal(t, ready+1, violations(StageReady))
+	w.now = func() time.Time { return now.Add(time.Hour) }
+	w.OnClaimIndexed(ctx, &etherman.Claim{NetworkID: 1, Index: 1, SyncedAt: now.Add(time.Hour)})
+	require.Equal(t, claimed+1, violations(StageClaimed))
+	// The claim of an untracked deposit is not checked, nor the claim of a deposit with the same count from another
+	// origin network
+	w.OnClaimIndexed(ctx, &etherman.Claim{NetworkID: 1, Index: 1, SyncedAt: now.Add(time.Hour)})
+	require.Equal(t, claimed+1, violations(StageClaimed))
+	w.OnDepositReady(ctx, &etherman.Deposit{NetworkID: 0, DepositCount: 4, DestinationNetwork: 1, BlockTime: now})
+	w.OnClaimIndexed(ctx, &etherman.Claim{NetworkID: 1, Index: 4, OriginalNetwork: 1, SyncedAt: now.Add(2 * time.Hour)})
+	require.Equal(t, claimed+1, violations(StageClaimed))
+	// The claims of the L2 deposits are not checked, their users claim them
+	w.OnDepositReady(ctx, &etherman.Deposit{NetworkID: 1, DepositCount: 3, DestinationNetwork: 0, BlockTime: now})
+	w.OnClaimIndexed(ctx, &etherman.Claim{NetworkID: 0, Index: 3, SyncedAt: now.Add(2 * time.Hour)})
+
+	// Indexed beyond the SLA
+	w.OnDepositIndexed(ctx, &etherman.Deposit{NetworkID: 0, DepositCount: 2, DestinationNetwork: 1, BlockTime: now, SyncedAt: now.Add(2 * time.Minute)})
+	require.Equal(t, indexed+1, violations(StageIndexed))
+
+	// The block time of a deposit indexed before the start is read from the storage by the next check
+	w.OnDepositReady(ctx, &etherman.Deposit{NetworkID: 0, DepositCount: 9, DestinationNetwork: 1})
+	require.Equal(t, ready+1, violations(StageReady))
+	w.checkPendingReady(ctx)
+	require.Equal(t, ready+2, violations(StageReady))
+	w.checkPendingReady(ctx)
+	require.Equal(t, ready+2, violations(StageReady))
+
+	v := w.takeViolations()
+	require.Equal(t, 4, len(v))
+	require.Equal(t, StageReady, v[0].Stage)
+	require.Equal(t, "1h0m0s", v[0].SLA)
+	require.Equal(t, "2h0m0s", v[0].Elapsed)
+	require.Equal(t, StageClaimed, v[1].Stage)
+	require.Equal(t, uint(1), v[1].DepositCount)
+	require.Equal(t, StageIndexed, v[2].Stage)
+	require.Equal(t, uint(9), v[3].DepositCount)
+	require.Empty(t, w.takeViolations())
+}
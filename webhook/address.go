@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ValidateWalletURL checks the url of a webhook registered by a wallet: an http or https url whose host is not a
+// loopback, private, link-local or unspecified address, e.g. the metadata endpoint of the cloud providers.
+func ValidateWalletURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("the webhook url must be an http or https url")
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("the webhook host %s is a private address", u.Hostname())
+	}
+	if ip := net.ParseIP(host); ip != nil && privateIP(ip) {
+		return fmt.Errorf("the webhook host %s is a private address", u.Hostname())
+	}
+	return nil
+}
+
+// privateIP checks if the ip isn't reachable from the internet, so the wallets can't have it called.
+func privateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
+
+// publicDialControl refuses the connections to the private addresses. It runs once the host is resolved, so the
+// names that resolve to a private address, or are changed to one after their registration, are refused too.
+func publicDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || privateIP(ip) {
+		return fmt.Errorf("the webhook address %s is a private address", host)
+	}
+	return nil
+}
+
+// newWalletClient returns the client of the webhooks of the wallets, which only connects to the public addresses.
+// It doesn't use the proxy of the environment, whose address would be checked instead of the webhook's.
+func newWalletClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: publicDialControl}).DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
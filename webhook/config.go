@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Config is the configuration of the webhook dispatcher
+type Config struct {
+	// Enabled sends the bridge events to the subscriptions
+	Enabled bool `mapstructure:"Enabled"`
+	// Workers is the number of deliveries sent concurrently, 0 for the number of the Tuning config for the CPUs
+	Workers int `mapstructure:"Workers"`
+	// MaxWorkers is the max number of workers, added while the deliveries wait in the queue. 0 is Workers
+	MaxWorkers int `mapstructure:"MaxWorkers"`
+	// ScaleInterval is the time between the checks of the queue to add the workers or stop the idle ones
+	ScaleInterval types.Duration `mapstructure:"ScaleInterval"`
+	// MaxEndpointConcurrency is the max number of deliveries sent concurrently to an endpoint, 0 for no limit.
+	// The others wait in the backlog of the endpoint, without holding a worker
+	MaxEndpointConcurrency int `mapstructure:"MaxEndpointConcurrency"`
+	// EndpointBacklog is the max number of deliveries waiting for an endpoint, the next ones are dropped
+	EndpointBacklog int `mapstructure:"EndpointBacklog"`
+	// SlowThreshold is the average latency of an endpoint above which it's quarantined, 0 never quarantines them
+	SlowThreshold types.Duration `mapstructure:"SlowThreshold"`
+	// QuarantineDuration is the time a slow endpoint is sent one delivery at a time before being checked again
+	QuarantineDuration types.Duration `mapstructure:"QuarantineDuration"`
+	// QueueSize is the max number of deliveries waiting for a worker
+	QueueSize int `mapstructure:"QueueSize"`
+	// QueuePolicy is applied to the new deliveries when the queue is full: drop_newest, drop_oldest, block, which
+	// blocks the sync and claim loops, or spill, which stores them in the database. Empty is drop_newest
+	QueuePolicy queue.Policy `mapstructure:"QueuePolicy"`
+	// RefillInterval is the time between the checks for room to queue back the stored deliveries, with the spill
+	// policy
+	RefillInterval types.Duration `mapstructure:"RefillInterval"`
+	// Timeout is the max time waiting for the response of a subscription
+	Timeout types.Duration `mapstructure:"Timeout"`
+	// RetryInterval is time between each attempt of a failed delivery
+	RetryInterval types.Duration `mapstructure:"RetryInterval"`
+	// RetryNumber is the number of attempts before dropping a delivery
+	RetryNumber int `mapstructure:"RetryNumber"`
+	// Subscriptions are the endpoints receiving the events
+	Subscriptions []Subscription `mapstructure:"Subscriptions"`
+	// WalletRefreshInterval is the time between each load of the webhooks registered by the wallets through
+	// their sessions, 0 doesn't send the events to them
+	WalletRefreshInterval types.Duration `mapstructure:"WalletRefreshInterval"`
+}
+
+// QueueConfig returns the config of the queue of the deliveries.
+func (c Config) QueueConfig() queue.Config {
+	policy := c.QueuePolicy
+	if policy == "" {
+		policy = queue.PolicyDropNewest
+	}
+	return queue.Config{Size: c.QueueSize, Policy: policy, RefillInterval: c.RefillInterval}
+}
+
+// Subscription is an endpoint receiving the events that match its filter
+type Subscription struct {
+	// Name identifies the subscription in the logs
+	Name string `mapstructure:"Name"`
+	// URL is the endpoint the events are posted to
+	URL string `mapstructure:"URL"`
+	// Secret signs the body of the deliveries with HMAC-SHA256 in the X-Bridge-Signature header.
+	// Empty doesn't sign them
+	Secret string `mapstructure:"Secret"`
+	// Filter selects the events sent to the subscription
+	Filter Filter `mapstructure:"Filter"`
+
+	// wallet is set for the webhooks of the wallets, only sent to the public addresses
+	wallet bool
+}
+
+// Filter selects the events of a subscription. An empty field matches all the events,
+// and an event must match all the fields to be sent
+type Filter struct {
+	// EventTypes are the types of the events: deposit_indexed, deposit_ready, claim_sent and claim_failed
+	EventTypes []string `mapstructure:"EventTypes"`
+	// Networks are the ids of the networks, matching the origin or the destination network of the deposit
+	Networks []uint `mapstructure:"Networks"`
+	// Addresses are the destination addresses of the deposits
+	Addresses []common.Address `mapstructure:"Addresses"`
+	// Tokens are the original addresses of the tokens, the zero address for ether
+	Tokens []common.Address `mapstructure:"Tokens"`
+	// MinAmount is the min amount of the deposits, in the smallest unit of the token
+	MinAmount *big.Int `mapstructure:"MinAmount"`
+}
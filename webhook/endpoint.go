@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// endpoint is the state of the deliveries to the url of a subscription, so a slow subscriber only holds the workers
+// of its own deliveries: the deliveries over its concurrency wait in its backlog instead of in a worker, and while
+// it's quarantined it's sent one delivery at a time.
+type endpoint struct {
+	mu       sync.Mutex
+	inFlight int
+	backlog  []delivery
+	// latency is the moving average of the latency of the attempts
+	latency          time.Duration
+	quarantinedUntil time.Time
+}
+
+// getEndpoint returns the endpoint of the url, created on its first delivery.
+func (d *Dispatcher) getEndpoint(url string) *endpoint {
+	d.endpointsMu.Lock()
+	defer d.endpointsMu.Unlock()
+	e, found := d.endpoints[url]
+	if !found {
+		e = &endpoint{}
+		d.endpoints[url] = e
+	}
+	return e
+}
+
+// pruneEndpoints removes the idle endpoints whose url is not used by any subscription anymore, e.g. the ones of the
+// deleted webhooks of the wallets. The endpoints with deliveries are removed once they are idle.
+func (d *Dispatcher) pruneEndpoints(subscriptions []Subscription) {
+	urls := make(map[string]struct{}, len(subscriptions))
+	for _, s := range subscriptions {
+		urls[s.URL] = struct{}{}
+	}
+	d.endpointsMu.Lock()
+	defer d.endpointsMu.Unlock()
+	for url, e := range d.endpoints {
+		if _, found := urls[url]; found {
+			continue
+		}
+		e.mu.Lock()
+		if e.inFlight == 0 && len(e.backlog) == 0 {
+			delete(d.endpoints, url)
+		}
+		e.mu.Unlock()
+	}
+}
+
+// limit returns the max number of deliveries sent concurrently to the endpoint, 0 for no limit. The lock of the
+// endpoint must be held.
+func (d *Dispatcher) limit(e *endpoint) int {
+	if d.now().Before(e.quarantinedUntil) {
+		return 1
+	}
+	return d.cfg.MaxEndpointConcurrency
+}
+
+// deliver sends the delivery if the endpoint is below its concurrency, and then the deliveries of its backlog while
+// no other worker is needed for them. Otherwise the delivery waits in the backlog. When it's full the delivery is
+// stored with the spill policy, and dropped with the others.
+func (d *Dispatcher) deliver(ctx context.Context, dl delivery) {
+	e := d.getEndpoint(dl.subscription.URL)
+	e.mu.Lock()
+	if limit := d.limit(e); limit > 0 && e.inFlight >= limit {
+		if len(e.backlog) >= d.cfg.EndpointBacklog {
+			e.mu.Unlock()
+			if d.cfg.QueueConfig().Policy == queue.PolicySpill {
+				err := spiller{d: d}.Spill(ctx, dl)
+				if err == nil {
+					return
+				}
+				log.Errorf("webhook: error storing the %s event of the full backlog of %s: %v", dl.eventType, dl.subscription.Name, err)
+			}
+			d.backlogDropped.Add(1)
+			log.Warnf("webhook: backlog of %s full, dropping the %s event", dl.subscription.Name, dl.eventType)
+			return
+		}
+		e.backlog = append(e.backlog, dl)
+		e.mu.Unlock()
+		return
+	}
+	e.inFlight++
+	e.mu.Unlock()
+
+	for {
+		d.attempt(ctx, e, dl)
+		var next bool
+		e.mu.Lock()
+		// The worker only takes the next delivery of the backlog if the endpoint is still below its concurrency,
+		// it's over it after entering the quarantine until the other deliveries in flight end
+		if limit := d.limit(e); len(e.backlog) > 0 && (limit == 0 || e.inFlight <= limit) {
+			dl, e.backlog = e.backlog[0], e.backlog[1:]
+			next = true
+		} else {
+			e.inFlight--
+		}
+		e.mu.Unlock()
+		if !next {
+			return
+		}
+	}
+}
+
+// attempt sends the delivery with its retries, observing the latency of each attempt.
+func (d *Dispatcher) attempt(ctx context.Context, e *endpoint, dl delivery) {
+	_, err := wait.Retry(ctx, d.retry, func(ctx context.Context) (struct{}, error) {
+		start := d.now()
+		err := d.send(ctx, dl)
+		d.observe(e, dl.subscription.Name, d.now().Sub(start))
+		return struct{}{}, err
+	}, func(err error) bool {
+		_, permanent := err.(permanentError)
+		return !permanent
+	})
+	if err != nil {
+		log.Warnf("webhook: error sending the %s event to %s, dropping it: %v", dl.eventType, dl.subscription.Name, err)
+	}
+}
+
+// observe adds the latency of an attempt to the average of the endpoint, quarantining it when the average is over
+// the slow threshold. The attempts sent during the quarantine are averaged too, so the endpoint is quarantined again
+// on its first attempt after it unless its latency recovered.
+func (d *Dispatcher) observe(e *endpoint, name string, latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = (e.latency*4 + latency) / 5 //nolint:gomnd
+	}
+	now := d.now()
+	if d.cfg.SlowThreshold.Duration <= 0 || e.latency <= d.cfg.SlowThreshold.Duration || now.Before(e.quarantinedUntil) {
+		return
+	}
+	e.quarantinedUntil = now.Add(d.cfg.QuarantineDuration.Duration)
+	log.Warnf("webhook: average latency of %s is %s, quarantining it until %s", name, e.latency, e.quarantinedUntil.Format(time.RFC3339))
+}
+
+// quarantined returns the number of endpoints in quarantine.
+func (d *Dispatcher) quarantined() int {
+	d.endpointsMu.Lock()
+	defer d.endpointsMu.Unlock()
+	now := d.now()
+	n := 0
+	for _, e := range d.endpoints {
+		e.mu.Lock()
+		if now.Before(e.quarantinedUntil) {
+			n++
+		}
+		e.mu.Unlock()
+	}
+	return n
+}
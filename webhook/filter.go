@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Event types
+const (
+	EventDepositIndexed = "deposit_indexed"
+	EventDepositReady   = "deposit_ready"
+	EventClaimSent      = "claim_sent"
+	EventClaimFailed    = "claim_failed"
+)
+
+var eventTypes = map[string]bool{
+	EventDepositIndexed: true,
+	EventDepositReady:   true,
+	EventClaimSent:      true,
+	EventClaimFailed:    true,
+}
+
+// Event is the body posted to the subscriptions. The claim events carry the deposit being claimed.
+type Event struct {
+	Type        string         `json:"type"`
+	NetworkID   uint           `json:"network_id"`
+	DepositCnt  uint           `json:"deposit_cnt"`
+	OrigNet     uint           `json:"orig_net"`
+	OrigAddr    common.Address `json:"orig_addr"`
+	DestNet     uint           `json:"dest_net"`
+	DestAddr    common.Address `json:"dest_addr"`
+	Amount      string         `json:"amount"`
+	BlockNum    uint64         `json:"block_num"`
+	TxHash      common.Hash    `json:"tx_hash"`
+	ClaimTxHash string         `json:"claim_tx_hash,omitempty"`
+	// RefID is the external reference id set by the destination address on the deposit
+	RefID string `json:"ref_id,omitempty"`
+
+	amount *big.Int
+}
+
+func newEvent(eventType string, deposit *etherman.Deposit) Event {
+	amount := deposit.Amount
+	if amount == nil {
+		amount = big.NewInt(0)
+	}
+	return Event{
+		Type:       eventType,
+		NetworkID:  deposit.NetworkID,
+		DepositCnt: deposit.DepositCount,
+		OrigNet:    deposit.OriginalNetwork,
+		OrigAddr:   deposit.OriginalAddress,
+		DestNet:    deposit.DestinationNetwork,
+		DestAddr:   deposit.DestinationAddress,
+		Amount:     amount.String(),
+		BlockNum:   deposit.BlockNumber,
+		TxHash:     deposit.TxHash,
+		amount:     amount,
+	}
+}
+
+// Validate checks that the event types of the filter are known.
+func (f Filter) Validate() error {
+	for _, eventType := range f.EventTypes {
+		if !eventTypes[eventType] {
+			return fmt.Errorf("unknown event type %s", eventType)
+		}
+	}
+	return nil
+}
+
+// Match checks if the event is selected by the filter.
+func (f Filter) Match(e Event) bool {
+	if len(f.EventTypes) > 0 && !containsString(f.EventTypes, e.Type) {
+		return false
+	}
+	if len(f.Networks) > 0 && !containsNetwork(f.Networks, e.NetworkID) && !containsNetwork(f.Networks, e.DestNet) {
+		return false
+	}
+	if len(f.Addresses) > 0 && !containsAddress(f.Addresses, e.DestAddr) {
+		return false
+	}
+	if len(f.Tokens) > 0 && !containsAddress(f.Tokens, e.OrigAddr) {
+		return false
+	}
+	if f.MinAmount != nil && e.amount != nil && e.amount.Cmp(f.MinAmount) < 0 {
+		return false
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsNetwork(networks []uint, networkID uint) bool {
+	for _, n := range networks {
+		if n == networkID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAddress(addresses []common.Address, addr common.Address) bool {
+	for _, a := range addresses {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
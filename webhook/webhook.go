@@ -0,0 +1,387 @@
+// Package webhook posts the bridge events to the endpoints of the integrators. Each subscription has a filter
+// evaluated before the delivery, so an integrator only receives the events of its addresses, tokens and networks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/hooks"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/wait"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/workers"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// SignatureHeader is the header with the HMAC-SHA256 of the body, when the subscription has a secret
+	SignatureHeader = "X-Bridge-Signature"
+	// EventHeader is the header with the type of the event
+	EventHeader = "X-Bridge-Event"
+)
+
+var (
+	workersDesc = prometheus.NewDesc("bridge_webhook_workers",
+		"Number of running workers of the webhook dispatcher", nil, nil)
+	busyWorkersDesc = prometheus.NewDesc("bridge_webhook_busy_workers",
+		"Number of workers of the webhook dispatcher sending a delivery", nil, nil)
+	quarantinedDesc = prometheus.NewDesc("bridge_webhook_quarantined_endpoints",
+		"Number of webhook endpoints in quarantine for their latency", nil, nil)
+	backlogDroppedDesc = prometheus.NewDesc("bridge_webhook_backlog_dropped_total",
+		"Number of webhook deliveries dropped because the backlog of their endpoint was full", nil, nil)
+)
+
+type storageInterface interface {
+	GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error)
+	GetAllWalletWebhooks(ctx context.Context, dbTx pgx.Tx) ([]etherman.WalletWebhook, error)
+	GetDepositRef(ctx context.Context, networkID, depositCnt uint, dbTx pgx.Tx) (*etherman.DepositRef, error)
+	SpillWebhookDelivery(ctx context.Context, delivery *etherman.WebhookDelivery, dbTx pgx.Tx) error
+	UnspillWebhookDeliveries(ctx context.Context, limit uint, dbTx pgx.Tx) ([]etherman.WebhookDelivery, error)
+}
+
+type delivery struct {
+	subscription *Subscription
+	eventType    string
+	body         []byte
+}
+
+// permanentError is a delivery error that is not retried
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Dispatcher sends the bridge events to the subscriptions whose filter matches them.
+// It implements hooks.Hook, so the events are received from the sync and claim loops, and prometheus.Collector.
+type Dispatcher struct {
+	hooks.NopHook
+	cfg     Config
+	storage storageInterface
+	client  *http.Client
+	// walletClient sends the deliveries of the webhooks of the wallets, only to the public addresses
+	walletClient *http.Client
+	retry        wait.Waiter
+	queue        *queue.Queue[delivery]
+	pool         *workers.Pool[delivery]
+	now          func() time.Time
+
+	endpointsMu    sync.Mutex
+	endpoints      map[string]*endpoint
+	backlogDropped atomic.Uint64
+
+	// subscriptions are the configured subscriptions and the webhooks of the wallets. The slice is replaced
+	// on each load of the wallet webhooks, never modified, so the queued deliveries keep pointing to it
+	mu            sync.RWMutex
+	subscriptions []Subscription
+}
+
+// NewDispatcher creates a new webhook dispatcher.
+func NewDispatcher(cfg Config, storage interface{}) (*Dispatcher, error) {
+	for i, s := range cfg.Subscriptions {
+		if s.URL == "" {
+			return nil, fmt.Errorf("webhook subscription %d (%s) without url", i, s.Name)
+		}
+		if err := s.Filter.Validate(); err != nil {
+			return nil, fmt.Errorf("webhook subscription %d (%s): %w", i, s.Name, err)
+		}
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.EndpointBacklog <= 0 {
+		cfg.EndpointBacklog = cfg.QueueSize
+	}
+	if cfg.SlowThreshold.Duration > 0 && cfg.QuarantineDuration.Duration <= 0 {
+		return nil, fmt.Errorf("webhook slow threshold without quarantine duration")
+	}
+	attempts := cfg.RetryNumber
+	if attempts <= 0 {
+		attempts = 1
+	}
+	d := &Dispatcher{
+		cfg:          cfg,
+		storage:      storage.(storageInterface),
+		client:       &http.Client{Timeout: cfg.Timeout.Duration},
+		walletClient: newWalletClient(cfg.Timeout.Duration),
+		retry:        wait.Waiter{Interval: cfg.RetryInterval.Duration, Attempts: attempts},
+		now:          time.Now,
+
+		endpoints:     make(map[string]*endpoint),
+		subscriptions: cfg.Subscriptions,
+	}
+	var err error
+	d.queue, err = queue.New[delivery]("webhook", cfg.QueueConfig(), spiller{d: d})
+	if err != nil {
+		return nil, err
+	}
+	d.pool = workers.New(workers.Config{Min: cfg.Workers, Max: cfg.MaxWorkers, ScaleInterval: cfg.ScaleInterval.Duration},
+		d.queue.C(), func() int { return d.queue.Stats().Depth }, d.deliver)
+	return d, nil
+}
+
+// Start runs the workers sending the deliveries until the context is done, scaled up to MaxWorkers while the
+// deliveries wait in the queue.
+func (d *Dispatcher) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.queue.Run(ctx)
+	}()
+	if d.cfg.WalletRefreshInterval.Duration > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.refreshWallets(ctx)
+		}()
+	}
+	d.pool.Run(ctx)
+	wg.Wait()
+}
+
+// OnDepositIndexed implements hooks.Hook.
+func (d *Dispatcher) OnDepositIndexed(ctx context.Context, deposit *etherman.Deposit) {
+	d.publish(ctx, newEvent(EventDepositIndexed, deposit))
+}
+
+// OnDepositReady implements hooks.Hook.
+func (d *Dispatcher) OnDepositReady(ctx context.Context, deposit *etherman.Deposit) {
+	d.publish(ctx, newEvent(EventDepositReady, deposit))
+}
+
+// OnClaimSent implements hooks.Hook.
+func (d *Dispatcher) OnClaimSent(ctx context.Context, mTx *ctmtypes.MonitoredTx, tx *types.Transaction) {
+	d.publishClaim(ctx, EventClaimSent, mTx, tx.Hash().String())
+}
+
+// OnClaimFailed implements hooks.Hook.
+func (d *Dispatcher) OnClaimFailed(ctx context.Context, mTx *ctmtypes.MonitoredTx) {
+	d.publishClaim(ctx, EventClaimFailed, mTx, "")
+}
+
+func (d *Dispatcher) publishClaim(ctx context.Context, eventType string, mTx *ctmtypes.MonitoredTx, claimTxHash string) {
+	if !d.subscribed(eventType) {
+		return
+	}
+	// The claim txs are only sent for the L1 deposits
+	deposit, err := d.storage.GetDeposit(ctx, mTx.DepositID, 0, nil)
+	if err != nil {
+		log.Errorf("webhook: error getting the deposit %d of the %s event: %v", mTx.DepositID, eventType, err)
+		return
+	}
+	event := newEvent(eventType, deposit)
+	event.ClaimTxHash = claimTxHash
+	d.publish(ctx, event)
+}
+
+// refreshWallets loads the webhooks of the wallets every WalletRefreshInterval until the context is done.
+func (d *Dispatcher) refreshWallets(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.WalletRefreshInterval.Duration)
+	defer ticker.Stop()
+	for {
+		if err := d.loadWalletWebhooks(ctx); err != nil {
+			log.Errorf("webhook: error loading the webhooks of the wallets: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// loadWalletWebhooks replaces the subscriptions of the wallets with their stored webhooks. Each webhook only
+// receives the events of the deposits to the address of its wallet.
+func (d *Dispatcher) loadWalletWebhooks(ctx context.Context) error {
+	webhooks, err := d.storage.GetAllWalletWebhooks(ctx, nil)
+	if err != nil {
+		return err
+	}
+	subscriptions := append(make([]Subscription, 0, len(d.cfg.Subscriptions)+len(webhooks)), d.cfg.Subscriptions...)
+	for _, w := range webhooks {
+		s := Subscription{
+			Name:   fmt.Sprintf("wallet %s webhook %d", w.Address.Hex(), w.ID),
+			URL:    w.URL,
+			Secret: w.Secret,
+			Filter: Filter{EventTypes: w.EventTypes, Addresses: []common.Address{w.Address}},
+			wallet: true,
+		}
+		if err := s.Filter.Validate(); err != nil {
+			log.Warnf("webhook: skipping the %s: %v", s.Name, err)
+			continue
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	d.mu.Lock()
+	d.subscriptions = subscriptions
+	d.mu.Unlock()
+	d.pruneEndpoints(subscriptions)
+	return nil
+}
+
+func (d *Dispatcher) getSubscriptions() []Subscription {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.subscriptions
+}
+
+// subscribed checks if any subscription receives the event type.
+func (d *Dispatcher) subscribed(eventType string) bool {
+	for _, s := range d.getSubscriptions() {
+		if len(s.Filter.EventTypes) == 0 || containsString(s.Filter.EventTypes, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// publish queues the event for the subscriptions that match it. The hooks must not block the
+// sync and claim loops, so unless the policy is block the deliveries are dropped or spilled when the queue is full.
+func (d *Dispatcher) publish(ctx context.Context, event Event) {
+	var body []byte
+	subscriptions := d.getSubscriptions()
+	for i := range subscriptions {
+		s := &subscriptions[i]
+		if !s.Filter.Match(event) {
+			continue
+		}
+		if body == nil {
+			// The reference id is only read for the events delivered
+			event.RefID = d.refID(ctx, event)
+			var err error
+			if body, err = json.Marshal(event); err != nil {
+				log.Errorf("webhook: error encoding the %s event: %v", event.Type, err)
+				return
+			}
+		}
+		if !d.queue.Push(ctx, delivery{subscription: s, eventType: event.Type, body: body}) {
+			log.Warnf("webhook: queue full, dropping the %s event of the deposit %d for %s", event.Type, event.DepositCnt, s.Name)
+		}
+	}
+}
+
+// refID returns the external reference id of the deposit of the event, empty if it has none or it can't be read.
+func (d *Dispatcher) refID(ctx context.Context, event Event) string {
+	ref, err := d.storage.GetDepositRef(ctx, event.NetworkID, event.DepositCnt, nil)
+	if err != nil {
+		if !errors.Is(err, gerror.ErrStorageNotFound) {
+			log.Warnf("webhook: error getting the reference id of the deposit %d of the %s event: %v", event.DepositCnt, event.Type, err)
+		}
+		return ""
+	}
+	return ref.RefID
+}
+
+func (d *Dispatcher) send(ctx context.Context, dl delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dl.subscription.URL, bytes.NewReader(dl.body))
+	if err != nil {
+		return permanentError{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, dl.eventType)
+	if dl.subscription.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(dl.subscription.Secret, dl.body))
+	}
+	client := d.client
+	if dl.subscription.wallet {
+		client = d.walletClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	err = fmt.Errorf("unexpected status %s", res.Status)
+	// The client errors are not fixed retrying, except the rate limits
+	if res.StatusCode < http.StatusInternalServerError && res.StatusCode != http.StatusTooManyRequests {
+		return permanentError{err: err}
+	}
+	return err
+}
+
+// Describe implements prometheus.Collector.
+func (d *Dispatcher) Describe(ch chan<- *prometheus.Desc) {
+	ch <- workersDesc
+	ch <- busyWorkersDesc
+	ch <- quarantinedDesc
+	ch <- backlogDroppedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (d *Dispatcher) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(workersDesc, prometheus.GaugeValue, float64(d.pool.Workers()))
+	ch <- prometheus.MustNewConstMetric(busyWorkersDesc, prometheus.GaugeValue, float64(d.pool.Busy()))
+	ch <- prometheus.MustNewConstMetric(quarantinedDesc, prometheus.GaugeValue, float64(d.quarantined()))
+	ch <- prometheus.MustNewConstMetric(backlogDroppedDesc, prometheus.CounterValue, float64(d.backlogDropped.Load()))
+}
+
+// spiller stores the deliveries in the database while the queue of the dispatcher is full.
+type spiller struct {
+	d *Dispatcher
+}
+
+// Spill implements queue.Spiller.
+func (s spiller) Spill(ctx context.Context, dl delivery) error {
+	return s.d.storage.SpillWebhookDelivery(ctx, &etherman.WebhookDelivery{
+		Subscription: dl.subscription.Name,
+		URL:          dl.subscription.URL,
+		EventType:    dl.eventType,
+		Body:         dl.body,
+		CreatedAt:    time.Now(),
+	}, nil)
+}
+
+// Unspill implements queue.Spiller. The deliveries of the subscriptions removed since they were stored are dropped.
+func (s spiller) Unspill(ctx context.Context, limit int) ([]delivery, int, error) {
+	stored, err := s.d.storage.UnspillWebhookDeliveries(ctx, uint(limit), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	subscriptions := s.d.getSubscriptions()
+	deliveries := make([]delivery, 0, len(stored))
+	for _, w := range stored {
+		var subscription *Subscription
+		for i := range subscriptions {
+			if subscriptions[i].Name == w.Subscription && subscriptions[i].URL == w.URL {
+				subscription = &subscriptions[i]
+				break
+			}
+		}
+		if subscription == nil {
+			log.Warnf("webhook: the subscription %s of a stored %s event was removed, dropping it", w.Subscription, w.EventType)
+			continue
+		}
+		deliveries = append(deliveries, delivery{subscription: subscription, eventType: w.EventType, body: w.Body})
+	}
+	return deliveries, len(stored), nil
+}
+
+// Sign returns the signature of the body sent in the SignatureHeader, so the subscriptions can verify it.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) //nolint:errcheck
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,472 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	ctmtypes "github.com/0xPolygonHermez/zkevm-bridge-service/claimtxman/types"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/etherman"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/pkg/queue"
+	"github.com/0xPolygonHermez/zkevm-bridge-service/utils/gerror"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	wallet = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	token  = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+func testDeposit() *etherman.Deposit {
+	return &etherman.Deposit{
+		OriginalNetwork:    0,
+		OriginalAddress:    token,
+		Amount:             big.NewInt(1000),
+		DestinationNetwork: 1,
+		DestinationAddress: wallet,
+		DepositCount:       5,
+		NetworkID:          0,
+		TxHash:             common.HexToHash("0x03"),
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	event := newEvent(EventDepositIndexed, testDeposit())
+	tcs := []struct {
+		name   string
+		filter Filter
+		match  bool
+	}{
+		{"empty", Filter{}, true},
+		{"event type", Filter{EventTypes: []string{EventDepositReady, EventDepositIndexed}}, true},
+		{"other event type", Filter{EventTypes: []string{EventClaimSent}}, false},
+		{"origin network", Filter{Networks: []uint{0}}, true},
+		{"destination network", Filter{Networks: []uint{1}}, true},
+		{"other network", Filter{Networks: []uint{2}}, false},
+		{"address", Filter{Addresses: []common.Address{wallet}}, true},
+		{"other address", Filter{Addresses: []common.Address{token}}, false},
+		{"token", Filter{Tokens: []common.Address{token}}, true},
+		{"ether", Filter{Tokens: []common.Address{{}}}, false},
+		{"min amount", Filter{MinAmount: big.NewInt(1000)}, true},
+		{"min amount above", Filter{MinAmount: big.NewInt(1001)}, false},
+		{"all fields", Filter{EventTypes: []string{EventDepositIndexed}, Networks: []uint{1}, Addresses: []common.Address{wallet}, Tokens: []common.Address{token}, MinAmount: big.NewInt(1)}, true},
+		{"one field not matching", Filter{Addresses: []common.Address{wallet}, MinAmount: big.NewInt(2000)}, false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.match, tc.filter.Match(event))
+		})
+	}
+	require.NoError(t, Filter{EventTypes: []string{EventClaimFailed}}.Validate())
+	require.Error(t, Filter{EventTypes: []string{"deposit"}}.Validate())
+}
+
+type depositStorage struct {
+	deposit  *etherman.Deposit
+	webhooks []etherman.WalletWebhook
+	refID    string
+}
+
+func (s depositStorage) GetDeposit(ctx context.Context, depositCnt uint, networkID uint, dbTx pgx.Tx) (*etherman.Deposit, error) {
+	return s.deposit, nil
+}
+
+func (s depositStorage) GetDepositRef(ctx context.Context, networkID, depositCnt uint, dbTx pgx.Tx) (*etherman.DepositRef, error) {
+	if s.refID == "" {
+		return nil, gerror.ErrStorageNotFound
+	}
+	return &etherman.DepositRef{NetworkID: networkID, DepositCnt: depositCnt, RefID: s.refID}, nil
+}
+
+func (s depositStorage) GetAllWalletWebhooks(ctx context.Context, dbTx pgx.Tx) ([]etherman.WalletWebhook, error) {
+	return s.webhooks, nil
+}
+
+func (s depositStorage) SpillWebhookDelivery(ctx context.Context, delivery *etherman.WebhookDelivery, dbTx pgx.Tx) error {
+	return nil
+}
+
+func (s depositStorage) UnspillWebhookDeliveries(ctx context.Context, limit uint, dbTx pgx.Tx) ([]etherman.WebhookDelivery, error) {
+	return nil, nil
+}
+
+// spillStorage keeps the spilled deliveries in memory.
+type spillStorage struct {
+	depositStorage
+	mu      sync.Mutex
+	spilled []etherman.WebhookDelivery
+}
+
+func (s *spillStorage) SpillWebhookDelivery(ctx context.Context, delivery *etherman.WebhookDelivery, dbTx pgx.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spilled = append(s.spilled, *delivery)
+	return nil
+}
+
+func (s *spillStorage) UnspillWebhookDeliveries(ctx context.Context, limit uint, dbTx pgx.Tx) ([]etherman.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if int(limit) > len(s.spilled) {
+		limit = uint(len(s.spilled))
+	}
+	deliveries := s.spilled[:limit]
+	s.spilled = s.spilled[limit:]
+	return deliveries, nil
+}
+
+type received struct {
+	path      string
+	event     Event
+	signature string
+	body      []byte
+}
+
+func TestDispatcher(t *testing.T) {
+	ch := make(chan received, 10)
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// The first delivery to the retried subscription fails
+		if r.URL.Path == "/retried" {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		var event Event
+		if !assert.NoError(t, json.Unmarshal(body, &event)) {
+			return
+		}
+		assert.Equal(t, event.Type, r.Header.Get(EventHeader))
+		ch <- received{path: r.URL.Path, event: event, signature: r.Header.Get(SignatureHeader), body: body}
+	}))
+	defer srv.Close()
+
+	cfg := Config{
+		Workers:       1,
+		QueueSize:     10,
+		Timeout:       types.NewDuration(time.Second),
+		RetryInterval: types.NewDuration(time.Millisecond),
+		RetryNumber:   2,
+		Subscriptions: []Subscription{
+			{Name: "wallet", URL: srv.URL + "/wallet", Secret: "secret", Filter: Filter{Addresses: []common.Address{wallet}, EventTypes: []string{EventClaimSent}}},
+			{Name: "whales", URL: srv.URL + "/whales", Filter: Filter{MinAmount: big.NewInt(1000000)}},
+			{Name: "retried", URL: srv.URL + "/retried", Filter: Filter{EventTypes: []string{EventClaimFailed}}},
+		},
+	}
+	_, err := NewDispatcher(Config{Subscriptions: []Subscription{{Name: "invalid", URL: srv.URL, Filter: Filter{EventTypes: []string{"unknown"}}}}}, depositStorage{})
+	require.Error(t, err)
+	d, err := NewDispatcher(cfg, depositStorage{deposit: testDeposit()})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Start(ctx)
+
+	// Only the wallet subscription receives the claim sent, and nobody the deposit below the min amount
+	d.OnDepositIndexed(ctx, testDeposit())
+	mTx := &ctmtypes.MonitoredTx{DepositID: 5}
+	d.OnClaimSent(ctx, mTx, ctmtypes.MonitoredTx{}.Tx())
+	r := <-ch
+	require.Equal(t, "/wallet", r.path)
+	require.Equal(t, EventClaimSent, r.event.Type)
+	require.Equal(t, uint(5), r.event.DepositCnt)
+	require.Equal(t, "1000", r.event.Amount)
+	require.Equal(t, ctmtypes.MonitoredTx{}.Tx().Hash().String(), r.event.ClaimTxHash)
+	require.Empty(t, r.event.RefID)
+	require.Equal(t, Sign("secret", r.body), r.signature)
+
+	d.OnClaimFailed(ctx, mTx)
+	r = <-ch
+	require.Equal(t, "/retried", r.path)
+	require.Equal(t, EventClaimFailed, r.event.Type)
+	require.Equal(t, "", r.signature)
+	require.Equal(t, 2, attempts)
+	require.Empty(t, ch)
+}
+
+func TestDispatcherWalletWebhooks(t *testing.T) {
+	ch := make(chan received, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		var event Event
+		if !assert.NoError(t, json.Unmarshal(body, &event)) {
+			return
+		}
+		ch <- received{path: r.URL.Path, event: event, signature: r.Header.Get(SignatureHeader), body: body}
+	}))
+	defer srv.Close()
+
+	storage := depositStorage{deposit: testDeposit(), refID: "order-1", webhooks: []etherman.WalletWebhook{
+		{ID: 1, Address: wallet, URL: srv.URL + "/wallet", Secret: "secret", EventTypes: []string{EventDepositIndexed}},
+		{ID: 2, Address: common.HexToAddress("0x02"), URL: srv.URL + "/other"},
+		{ID: 3, Address: wallet, URL: srv.URL + "/invalid", EventTypes: []string{"unknown"}},
+	}}
+	cfg := Config{Workers: 1, QueueSize: 10, Timeout: types.NewDuration(time.Second), RetryNumber: 1}
+	d, err := NewDispatcher(cfg, storage)
+	require.NoError(t, err)
+	// The test server listens on a loopback address, refused for the wallets
+	walletClient := d.walletClient
+	d.walletClient = srv.Client()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Start(ctx)
+
+	// The webhooks of the wallets are not used until they are loaded
+	require.False(t, d.subscribed(EventDepositIndexed))
+	require.NoError(t, d.loadWalletWebhooks(ctx))
+	require.Len(t, d.getSubscriptions(), 2)
+
+	// Only the webhook of the destination address of the deposit receives it
+	d.OnDepositIndexed(ctx, testDeposit())
+	r := <-ch
+	require.Equal(t, "/wallet", r.path)
+	require.Equal(t, EventDepositIndexed, r.event.Type)
+	require.Equal(t, "order-1", r.event.RefID)
+	require.Equal(t, Sign("secret", r.body), r.signature)
+	d.OnDepositReady(ctx, testDeposit())
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, ch)
+
+	// The client of the wallets doesn't connect to the private addresses
+	d.walletClient = walletClient
+	subscriptions := d.getSubscriptions()
+	err = d.send(ctx, delivery{subscription: &subscriptions[1], eventType: EventDepositIndexed, body: []byte("{}")})
+	require.ErrorContains(t, err, "private address")
+	require.Empty(t, ch)
+}
+
+func TestValidateWalletURL(t *testing.T) {
+	require.NoError(t, ValidateWalletURL("https://example.com/webhook"))
+	require.NoError(t, ValidateWalletURL("http://8.8.8.8:8080"))
+	for _, u := range []string{
+		"ftp://example.com",
+		"https://",
+		"http://localhost:8080",
+		"http://api.localhost",
+		"http://127.0.0.1",
+		"http://10.0.0.1",
+		"http://192.168.1.1",
+		"http://172.16.0.1",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]:8080",
+		"http://[fd00::1]",
+		"http://0.0.0.0",
+	} {
+		require.Error(t, ValidateWalletURL(u), u)
+	}
+}
+
+func TestDispatcherSpill(t *testing.T) {
+	ch := make(chan received, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		var event Event
+		if !assert.NoError(t, json.Unmarshal(body, &event)) {
+			return
+		}
+		ch <- received{path: r.URL.Path, event: event, body: body}
+	}))
+	defer srv.Close()
+
+	storage := &spillStorage{depositStorage: depositStorage{deposit: testDeposit()}}
+	cfg := Config{
+		Workers:        1,
+		QueueSize:      1,
+		QueuePolicy:    queue.PolicySpill,
+		RefillInterval: types.NewDuration(10 * time.Millisecond),
+		Timeout:        types.NewDuration(time.Second),
+		RetryNumber:    1,
+		Subscriptions:  []Subscription{{Name: "exchange", URL: srv.URL + "/exchange"}},
+	}
+	d, err := NewDispatcher(cfg, storage)
+	require.NoError(t, err)
+
+	// The deliveries over the size of the queue are stored until the workers take the queued ones
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i := uint(1); i <= 3; i++ {
+		deposit := testDeposit()
+		deposit.DepositCount = i
+		d.OnDepositIndexed(ctx, deposit)
+	}
+	require.Len(t, storage.spilled, 3)
+	require.Equal(t, "exchange", storage.spilled[0].Subscription)
+	require.Equal(t, EventDepositIndexed, storage.spilled[0].EventType)
+
+	go d.Start(ctx)
+	for i := uint(1); i <= 3; i++ {
+		r := <-ch
+		require.Equal(t, "/exchange", r.path)
+		require.Equal(t, i, r.event.DepositCnt)
+	}
+
+	// The stored deliveries of a removed subscription are dropped
+	removedStorage := &spillStorage{spilled: []etherman.WebhookDelivery{{Subscription: "removed", URL: srv.URL, EventType: EventDepositIndexed}}}
+	d, err = NewDispatcher(cfg, removedStorage)
+	require.NoError(t, err)
+	deliveries, removed, err := spiller{d: d}.Unspill(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+	require.Equal(t, 1, removed)
+
+	cfg.QueueSize = 0
+	_, err = NewDispatcher(cfg, storage)
+	require.Error(t, err)
+}
+
+func TestDispatcherSlowEndpoint(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		now      = time.Unix(1700000000, 0)
+		inFlight int
+		maxIn    int
+		sent     int
+	)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	unblock := make(chan struct{})
+	ch := make(chan received, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fast" {
+			ch <- received{path: r.URL.Path}
+			return
+		}
+		// Each delivery to the slow endpoint takes 10s of the clock, and waits to be unblocked
+		mu.Lock()
+		inFlight++
+		if inFlight > maxIn {
+			maxIn = inFlight
+		}
+		now = now.Add(10 * time.Second)
+		mu.Unlock()
+		<-unblock
+		mu.Lock()
+		inFlight--
+		sent++
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	cfg := Config{
+		Workers:                3,
+		QueueSize:              10,
+		MaxEndpointConcurrency: 2,
+		EndpointBacklog:        1,
+		SlowThreshold:          types.NewDuration(5 * time.Second),
+		QuarantineDuration:     types.NewDuration(time.Minute),
+		Timeout:                types.NewDuration(10 * time.Second),
+		RetryNumber:            1,
+		Subscriptions: []Subscription{
+			{Name: "slow", URL: srv.URL + "/slow", Filter: Filter{EventTypes: []string{EventDepositIndexed}}},
+			{Name: "fast", URL: srv.URL + "/fast", Filter: Filter{EventTypes: []string{EventDepositReady}}},
+		},
+	}
+	_, err := NewDispatcher(Config{QueueSize: 10, SlowThreshold: types.NewDuration(time.Second)}, depositStorage{})
+	require.Error(t, err)
+	d, err := NewDispatcher(cfg, depositStorage{deposit: testDeposit()})
+	require.NoError(t, err)
+	d.now = clock
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Start(ctx)
+
+	// The first delivery is over the slow threshold, so the endpoint is quarantined
+	d.OnDepositIndexed(ctx, testDeposit())
+	require.Eventually(t, func() bool { mu.Lock(); defer mu.Unlock(); return inFlight == 1 }, time.Second, time.Millisecond)
+	unblock <- struct{}{}
+	require.Eventually(t, func() bool { return d.quarantined() == 1 }, time.Second, time.Millisecond)
+
+	// While quarantined it's sent one delivery at a time, the next one waits in its backlog and the one over the
+	// backlog is dropped, without delaying the other endpoints
+	mu.Lock()
+	maxIn = 0
+	mu.Unlock()
+	for i := 0; i < 3; i++ {
+		d.OnDepositIndexed(ctx, testDeposit())
+	}
+	require.Eventually(t, func() bool { return d.backlogDropped.Load() == 1 }, time.Second, time.Millisecond)
+	d.OnDepositReady(ctx, testDeposit())
+	select {
+	case r := <-ch:
+		require.Equal(t, "/fast", r.path)
+	case <-time.After(time.Second):
+		t.Fatal("the fast endpoint is delayed by the slow one")
+	}
+	unblock <- struct{}{}
+	unblock <- struct{}{}
+	require.Eventually(t, func() bool { mu.Lock(); defer mu.Unlock(); return sent == 3 }, time.Second, time.Millisecond)
+	mu.Lock()
+	require.Equal(t, 1, maxIn)
+	now = now.Add(time.Minute)
+	mu.Unlock()
+	require.Equal(t, 0, d.quarantined())
+}
+
+func TestDispatcherBacklogSpill(t *testing.T) {
+	storage := &spillStorage{depositStorage: depositStorage{deposit: testDeposit()}}
+	cfg := Config{
+		QueueSize:              10,
+		QueuePolicy:            queue.PolicySpill,
+		RefillInterval:         types.NewDuration(time.Second),
+		MaxEndpointConcurrency: 1,
+		EndpointBacklog:        1,
+		Subscriptions:          []Subscription{{Name: "exchange", URL: "http://exchange"}},
+	}
+	d, err := NewDispatcher(cfg, storage)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// The delivery over the full backlog is stored instead of dropped
+	e := d.getEndpoint("http://exchange")
+	e.inFlight, e.backlog = 1, []delivery{{subscription: &cfg.Subscriptions[0], eventType: EventDepositIndexed}}
+	d.deliver(ctx, delivery{subscription: &cfg.Subscriptions[0], eventType: EventDepositReady, body: []byte("{}")})
+	require.Len(t, storage.spilled, 1)
+	require.Equal(t, "exchange", storage.spilled[0].Subscription)
+	require.Equal(t, EventDepositReady, storage.spilled[0].EventType)
+	require.Zero(t, d.backlogDropped.Load())
+	require.Len(t, e.backlog, 1)
+}
+
+func TestDispatcherPruneEndpoints(t *testing.T) {
+	storage := depositStorage{webhooks: []etherman.WalletWebhook{{ID: 1, Address: wallet, URL: "http://wallet"}}}
+	d, err := NewDispatcher(Config{QueueSize: 10, Subscriptions: []Subscription{{Name: "exchange", URL: "http://exchange"}}}, storage)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, d.loadWalletWebhooks(ctx))
+	d.getEndpoint("http://exchange")
+	busy := d.getEndpoint("http://wallet")
+	busy.inFlight = 1
+
+	// The endpoints of the deleted webhooks are removed once they are idle
+	d.storage = depositStorage{}
+	require.NoError(t, d.loadWalletWebhooks(ctx))
+	require.Len(t, d.endpoints, 2)
+	busy.inFlight = 0
+	require.NoError(t, d.loadWalletWebhooks(ctx))
+	require.Len(t, d.endpoints, 1)
+	require.Contains(t, d.endpoints, "http://exchange")
+}